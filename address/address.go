@@ -5,7 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/url"
 	"strings"
+	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcec/v2/schnorr"
@@ -47,13 +49,19 @@ var (
 		"address: collectible asset amount not one",
 	)
 
-	// ErrInvalidAmountNormal is an error returned when we attempt to
-	// create a Taproot Asset address for a Normal asset with an amount of
-	// zero.
+	// ErrInvalidAmountNormal is an error returned when we attempt to send
+	// an amount of zero to a Taproot Asset address for a Normal asset.
 	ErrInvalidAmountNormal = errors.New(
 		"address: normal asset amount of zero",
 	)
 
+	// ErrFixedAmount is an error returned when we attempt to choose the
+	// amount to send to a Taproot Asset address that already specifies
+	// the amount to receive.
+	ErrFixedAmount = errors.New(
+		"address: address already specifies an amount",
+	)
+
 	// ErrUnsupportedAssetType is an error returned when we attempt to
 	// create a Taproot Asset address for a non-standard asset type.
 	ErrUnsupportedAssetType = errors.New(
@@ -65,6 +73,36 @@ var (
 		"address: no address found",
 	)
 
+	// ErrStaticTapscriptSibling is an error returned when we attempt to
+	// create a static Taproot Asset address with a tapscript sibling.
+	ErrStaticTapscriptSibling = errors.New(
+		"address: static address can't have a tapscript sibling",
+	)
+
+	// ErrMissingGroupKey is an error returned when we attempt to create
+	// or decode an address for any asset of a group without a group key.
+	ErrMissingGroupKey = errors.New(
+		"address: group address requires a group key",
+	)
+
+	// ErrNotGroupAddr is returned when we attempt to choose the asset to
+	// send to an address that already specifies an asset ID.
+	ErrNotGroupAddr = errors.New(
+		"address: address doesn't accept any asset of a group",
+	)
+
+	// ErrStaticSingleUse is an error returned when we attempt to create a
+	// static Taproot Asset address that can only be used once.
+	ErrStaticSingleUse = errors.New(
+		"address: static address can't be single-use",
+	)
+
+	// ErrAddrExpired is returned when we attempt to use an address after
+	// its expiry.
+	ErrAddrExpired = errors.New(
+		"address: address is expired",
+	)
+
 	// ErrScriptKeyNotFound is returned when a script key is not found in
 	// the local database.
 	ErrScriptKeyNotFound = errors.New(
@@ -76,6 +114,15 @@ const (
 	// TapScriptVersion is the highest version of Taproot Assets script
 	// supported.
 	TapScriptVersion uint8 = 0
+
+	// HashmailCourierType is the URL scheme of a proof courier that uses
+	// the hashmail protocol to deliver proofs.
+	HashmailCourierType = "hashmail"
+
+	// UniverseRpcCourierType is the URL scheme of a proof courier that
+	// deposits proofs in and retrieves them from a universe server using
+	// its RPC interface.
+	UniverseRpcCourierType = "universerpc"
 )
 
 // Tap represents a Taproot Asset address. Taproot Asset addresses specify an
@@ -113,23 +160,113 @@ type Tap struct {
 	TapscriptSibling *commitment.TapscriptPreimage
 
 	// Amount is the number of asset units being requested by the receiver.
+	// An amount of zero means the address doesn't specify an amount and the
+	// sender chooses how many units to send.
 	Amount uint64
 
+	// ProofCourierAddr is an optional hint of the proof courier service
+	// the sender should use to deliver the transfer proof to the receiver.
+	// If this is nil, the sender falls back to its default courier.
+	ProofCourierAddr *url.URL
+
+	// Static indicates that the address can be used to receive any number
+	// of transfers. The sender of each transfer derives a unique script
+	// key from the script key of the address and a random nonce, which is
+	// committed to in the tapscript sibling of the anchor output.
+	Static bool
+
+	// AnyAssetInGroup indicates that the address accepts any asset of the
+	// group identified by the group key, which must be set. The asset ID
+	// isn't encoded in such an address, so the sender chooses which
+	// tranche of the group to send. The receiver only keeps the asset ID
+	// the address was created for as a template.
+	AnyAssetInGroup bool
+
+	// Expiry is the optional time after which the address no longer
+	// accepts transfers. A zero time means the address doesn't expire.
+	Expiry time.Time
+
+	// SingleUse indicates that the address only accepts a single transfer.
+	// Any transfer to the address after the first one is ignored by the
+	// receiver.
+	SingleUse bool
+
 	// assetGen is the receiving asset's genesis metadata which directly
 	// maps to its unique ID within the Taproot Asset protocol.
 	assetGen asset.Genesis
 }
 
+// NewAddrOpt is a functional option that modifies a newly created address.
+type NewAddrOpt func(*Tap)
+
+// WithProofCourierAddr sets the proof courier address hint of a new address.
+func WithProofCourierAddr(addr *url.URL) NewAddrOpt {
+	return func(a *Tap) {
+		a.ProofCourierAddr = addr
+	}
+}
+
+// ValidateProofCourierAddr makes sure the given proof courier address uses a
+// known courier type and specifies both a host and a port.
+func ValidateProofCourierAddr(addr *url.URL) error {
+	switch addr.Scheme {
+	case HashmailCourierType, UniverseRpcCourierType:
+
+	default:
+		return fmt.Errorf("unknown proof courier protocol: %v",
+			addr.Scheme)
+	}
+
+	if addr.Hostname() == "" || addr.Port() == "" {
+		return fmt.Errorf("proof courier address %v must specify "+
+			"host and port", addr)
+	}
+
+	return nil
+}
+
+// WithStatic marks a new address as static, allowing it to be used for any
+// number of independent transfers.
+func WithStatic() NewAddrOpt {
+	return func(a *Tap) {
+		a.Static = true
+	}
+}
+
+// WithAnyAssetInGroup marks a new address as accepting any asset of the group
+// of the given asset.
+func WithAnyAssetInGroup() NewAddrOpt {
+	return func(a *Tap) {
+		a.AnyAssetInGroup = true
+	}
+}
+
+// WithExpiry sets the time after which a new address no longer accepts
+// transfers. The expiry is encoded with a precision of one second.
+func WithExpiry(expiry time.Time) NewAddrOpt {
+	return func(a *Tap) {
+		a.Expiry = time.Unix(expiry.Unix(), 0)
+	}
+}
+
+// WithSingleUse marks a new address as only accepting a single transfer.
+func WithSingleUse() NewAddrOpt {
+	return func(a *Tap) {
+		a.SingleUse = true
+	}
+}
+
 // New creates an address for receiving a Taproot asset.
 func New(genesis asset.Genesis, groupKey *btcec.PublicKey,
 	groupSig *schnorr.Signature, scriptKey btcec.PublicKey,
 	internalKey btcec.PublicKey, amt uint64,
 	tapscriptSibling *commitment.TapscriptPreimage,
-	net *ChainParams) (*Tap, error) {
+	net *ChainParams, opts ...NewAddrOpt) (*Tap, error) {
 
 	// Check for invalid combinations of asset type and amount.
-	// Collectible assets must have an amount of 1, and Normal assets must
-	// have a non-zero amount. We also reject invalid asset types.
+	// Collectible assets must have an amount of 1, while Normal assets can
+	// have any amount, with zero meaning the address doesn't specify one.
+	// We also reject invalid asset types.
 	switch genesis.Type {
 	case asset.Collectible:
 		if amt != 1 {
@@ -137,9 +274,6 @@ func New(genesis asset.Genesis, groupKey *btcec.PublicKey,
 		}
 
 	case asset.Normal:
-		if amt == 0 {
-			return nil, ErrInvalidAmountNormal
-		}
 
 	default:
 		return nil, ErrUnsupportedAssetType
@@ -174,6 +308,26 @@ func New(genesis asset.Genesis, groupKey *btcec.PublicKey,
 		Amount:           amt,
 		assetGen:         genesis,
 	}
+	for _, opt := range opts {
+		opt(&payload)
+	}
+
+	// The tapscript sibling of a static address is chosen by the sender
+	// of each transfer, so it can't be set on the address itself.
+	if payload.Static && tapscriptSibling != nil {
+		return nil, ErrStaticTapscriptSibling
+	}
+
+	if payload.AnyAssetInGroup && groupKey == nil {
+		return nil, ErrMissingGroupKey
+	}
+
+	// Every receive through a static address is a separate transfer, so
+	// limiting it to a single one defeats its purpose.
+	if payload.Static && payload.SingleUse {
+		return nil, ErrStaticSingleUse
+	}
+
 	return &payload, nil
 }
 
@@ -189,10 +343,60 @@ func (a *Tap) Copy() *Tap {
 		groupSig := *a.groupSig
 		addressCopy.groupSig = &groupSig
 	}
+	if a.ProofCourierAddr != nil {
+		courierAddr := *a.ProofCourierAddr
+		addressCopy.ProofCourierAddr = &courierAddr
+	}
 
 	return &addressCopy
 }
 
+// HasAmount returns true if the address specifies the amount to receive. If
+// it doesn't, the sender chooses the amount.
+func (a *Tap) HasAmount() bool {
+	return a.Amount != 0
+}
+
+// ForAmount returns a copy of an address that doesn't specify an amount, with
+// the amount set to the one the sender chose to send.
+func (a *Tap) ForAmount(amt uint64) (*Tap, error) {
+	if a.HasAmount() {
+		return nil, ErrFixedAmount
+	}
+	if amt == 0 {
+		return nil, ErrInvalidAmountNormal
+	}
+
+	addrCopy := a.Copy()
+	addrCopy.Amount = amt
+
+	return addrCopy, nil
+}
+
+// ForAssetID returns a copy of an address that accepts any asset of a group,
+// with the asset ID set to the one of the tranche the sender chose to send.
+func (a *Tap) ForAssetID(id asset.ID) (*Tap, error) {
+	if !a.AnyAssetInGroup {
+		return nil, ErrNotGroupAddr
+	}
+
+	addrCopy := a.Copy()
+	addrCopy.AssetID = id
+	addrCopy.AnyAssetInGroup = false
+
+	return addrCopy, nil
+}
+
+// HasExpiry returns true if the address expires at some point.
+func (a *Tap) HasExpiry() bool {
+	return !a.Expiry.IsZero()
+}
+
+// IsExpired returns true if the address expired at the given time.
+func (a *Tap) IsExpired(now time.Time) bool {
+	return a.HasExpiry() && !now.Before(a.Expiry)
+}
+
 // Net returns the ChainParams struct matching the Taproot Asset address
 // network.
 func (a *Tap) Net() (*ChainParams, error) {
@@ -295,9 +499,11 @@ func (a *Tap) TaprootOutputKey() (*btcec.PublicKey, error) {
 // EncodeRecords determines the non-nil records to include when encoding an
 // address at runtime.
 func (a *Tap) EncodeRecords() []tlv.Record {
-	records := make([]tlv.Record, 0, 6)
+	records := make([]tlv.Record, 0, 7)
 	records = append(records, newAddressVersionRecord(&a.Version))
-	records = append(records, newAddressAssetID(&a.AssetID))
+	if !a.AnyAssetInGroup {
+		records = append(records, newAddressAssetID(&a.AssetID))
+	}
 
 	if a.GroupKey != nil {
 		records = append(records, newAddressGroupKeyRecord(&a.GroupKey))
@@ -310,7 +516,35 @@ func (a *Tap) EncodeRecords() []tlv.Record {
 			&a.TapscriptSibling,
 		))
 	}
-	records = append(records, newAddressAmountRecord(&a.Amount))
+	if a.HasAmount() {
+		records = append(records, newAddressAmountRecord(&a.Amount))
+	}
+
+	if a.ProofCourierAddr != nil {
+		records = append(records, newProofCourierAddrRecord(
+			&a.ProofCourierAddr,
+		))
+	}
+
+	if a.Static {
+		records = append(records, newAddressStaticRecord(&a.Static))
+	}
+
+	if a.HasExpiry() {
+		records = append(records, newAddressExpiryRecord(&a.Expiry))
+	}
+
+	if a.AnyAssetInGroup {
+		records = append(records, newAddressAnyAssetInGroupRecord(
+			&a.AnyAssetInGroup,
+		))
+	}
+
+	if a.SingleUse {
+		records = append(records, newAddressSingleUseRecord(
+			&a.SingleUse,
+		))
+	}
 
 	return records
 }
@@ -326,6 +560,11 @@ func (a *Tap) DecodeRecords() []tlv.Record {
 		newAddressInternalKeyRecord(&a.InternalKey),
 		newAddressTapscriptSiblingRecord(&a.TapscriptSibling),
 		newAddressAmountRecord(&a.Amount),
+		newProofCourierAddrRecord(&a.ProofCourierAddr),
+		newAddressStaticRecord(&a.Static),
+		newAddressExpiryRecord(&a.Expiry),
+		newAddressAnyAssetInGroupRecord(&a.AnyAssetInGroup),
+		newAddressSingleUseRecord(&a.SingleUse),
 	}
 }
 
@@ -427,6 +666,10 @@ func DecodeAddress(addr string, net *ChainParams) (*Tap, error) {
 		return nil, err
 	}
 
+	if a.AnyAssetInGroup && a.GroupKey == nil {
+		return nil, ErrMissingGroupKey
+	}
+
 	a.ChainParams = net
 
 	return &a, nil
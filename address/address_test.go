@@ -1,9 +1,13 @@
 package address
 
 import (
+	"bytes"
 	"encoding/hex"
 	"math/rand"
+	"net/url"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcec/v2/schnorr"
@@ -11,6 +15,8 @@ import (
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/lightninglabs/taproot-assets/asset"
 	"github.com/lightninglabs/taproot-assets/commitment"
+	"github.com/lightninglabs/taproot-assets/internal/test"
+	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/stretchr/testify/require"
 )
 
@@ -91,6 +97,11 @@ func assertAddressEqual(t *testing.T, a, b *Tap) {
 	require.Equal(t, a.ScriptKey, b.ScriptKey)
 	require.Equal(t, a.InternalKey, b.InternalKey)
 	require.Equal(t, a.Amount, b.Amount)
+	require.Equal(t, a.ProofCourierAddr, b.ProofCourierAddr)
+	require.Equal(t, a.Static, b.Static)
+	require.Equal(t, a.AnyAssetInGroup, b.AnyAssetInGroup)
+	require.Equal(t, a.Expiry, b.Expiry)
+	require.Equal(t, a.SingleUse, b.SingleUse)
 }
 
 // TestNewAddress tests edge cases around creating a new address.
@@ -133,7 +144,7 @@ func TestNewAddress(t *testing.T) {
 			err: nil,
 		},
 		{
-			name: "invalid normal asset value",
+			name: "normal address without amount",
 			f: func() (*Tap, error) {
 				zeroAmt := uint64(0)
 				return randAddress(
@@ -141,7 +152,7 @@ func TestNewAddress(t *testing.T) {
 					&zeroAmt, asset.Normal,
 				)
 			},
-			err: ErrInvalidAmountNormal,
+			err: nil,
 		},
 		{
 			name: "invalid collectible asset value",
@@ -164,6 +175,43 @@ func TestNewAddress(t *testing.T) {
 			},
 			err: ErrUnsupportedHRP,
 		},
+		{
+			name: "static address with sibling",
+			f: func() (*Tap, error) {
+				sibling := commitment.NewPreimageFromLeaf(
+					txscript.NewBaseTapLeaf([]byte("foo")),
+				)
+				return New(
+					asset.RandGenesis(t, asset.Normal), nil,
+					nil, *pubKey, *pubKey, 1, sibling,
+					&TestNet3Tap, WithStatic(),
+				)
+			},
+			err: ErrStaticTapscriptSibling,
+		},
+		{
+			name: "group address without group key",
+			f: func() (*Tap, error) {
+				return New(
+					asset.RandGenesis(t, asset.Normal), nil,
+					nil, *pubKey, *pubKey, 1, nil,
+					&TestNet3Tap, WithAnyAssetInGroup(),
+				)
+			},
+			err: ErrMissingGroupKey,
+		},
+		{
+			name: "single-use static address",
+			f: func() (*Tap, error) {
+				return New(
+					asset.RandGenesis(t, asset.Normal), nil,
+					nil, *pubKey, *pubKey, 1, nil,
+					&TestNet3Tap, WithStatic(),
+					WithSingleUse(),
+				)
+			},
+			err: ErrStaticSingleUse,
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -198,6 +246,13 @@ func TestAddressEncoding(t *testing.T) {
 		require.NoError(t, err)
 		b, err := DecodeAddress(addr, net)
 		require.NoError(t, err)
+
+		// The asset ID of an address that accepts any asset of a
+		// group isn't encoded.
+		if a.AnyAssetInGroup {
+			require.Equal(t, asset.ID{}, b.AssetID)
+			b.AssetID = a.AssetID
+		}
 		assertAddressEqual(t, a, b)
 	}
 
@@ -246,6 +301,90 @@ func TestAddressEncoding(t *testing.T) {
 			},
 			err: nil,
 		},
+		{
+			name: "proof courier address hint",
+			f: func() (*Tap, string, error) {
+				newAddr, _, err := randEncodedAddress(
+					t, &RegressionNetTap, false, false,
+					asset.Normal,
+				)
+				require.NoError(t, err)
+
+				courierAddr, err := url.ParseRequestURI(
+					"hashmail://localhost:10029",
+				)
+				require.NoError(t, err)
+				newAddr.ProofCourierAddr = courierAddr
+
+				encodedAddr, err := newAddr.EncodeAddress()
+				return newAddr, encodedAddr, err
+			},
+			err: nil,
+		},
+		{
+			name: "static address",
+			f: func() (*Tap, string, error) {
+				newAddr, _, err := randEncodedAddress(
+					t, &RegressionNetTap, false, false,
+					asset.Normal,
+				)
+				require.NoError(t, err)
+
+				newAddr.Static = true
+
+				encodedAddr, err := newAddr.EncodeAddress()
+				return newAddr, encodedAddr, err
+			},
+			err: nil,
+		},
+		{
+			name: "address without amount",
+			f: func() (*Tap, string, error) {
+				zeroAmt := uint64(0)
+				newAddr, err := randAddress(
+					t, &RegressionNetTap, false, false,
+					&zeroAmt, asset.Normal,
+				)
+				require.NoError(t, err)
+
+				encodedAddr, err := newAddr.EncodeAddress()
+				return newAddr, encodedAddr, err
+			},
+			err: nil,
+		},
+		{
+			name: "any asset in group",
+			f: func() (*Tap, string, error) {
+				newAddr, _, err := randEncodedAddress(
+					t, &RegressionNetTap, true, false,
+					asset.Normal,
+				)
+				require.NoError(t, err)
+
+				newAddr.AnyAssetInGroup = true
+
+				encodedAddr, err := newAddr.EncodeAddress()
+				return newAddr, encodedAddr, err
+			},
+			err: nil,
+		},
+		{
+			name: "single-use address with expiry",
+			f: func() (*Tap, string, error) {
+				newAddr, _, err := randEncodedAddress(
+					t, &RegressionNetTap, false, false,
+					asset.Normal,
+				)
+				require.NoError(t, err)
+
+				newAddr.Expiry = time.Unix(1700000000, 0)
+				newAddr.SingleUse = true
+
+				encodedAddr, err := newAddr.EncodeAddress()
+				return newAddr, encodedAddr, err
+			},
+			err: nil,
+		},
 		{
 			name: "unsupported hrp",
 			f: func() (*Tap, string, error) {
@@ -302,3 +441,315 @@ func TestAddressEncoding(t *testing.T) {
 		}
 	}
 }
+
+// TestStaticReceive tests that sender and receiver of a transfer to a static
+// address derive the same unique receive from the tapscript sibling.
+func TestStaticReceive(t *testing.T) {
+	t.Parallel()
+
+	addr, err := randAddress(
+		t, &TestNet3Tap, false, false, nil, asset.Normal,
+	)
+	require.NoError(t, err)
+
+	nonce, err := RandStaticNonce()
+	require.NoError(t, err)
+
+	// Only static addresses can be used to derive a receive.
+	_, err = addr.StaticReceive(nonce)
+	require.ErrorIs(t, err, ErrNotStatic)
+
+	addr.Static = true
+	receiveAddr, err := addr.StaticReceive(nonce)
+	require.NoError(t, err)
+
+	require.False(t, receiveAddr.Static)
+	require.True(t, addr.Static)
+	require.Equal(t, addr.InternalKey, receiveAddr.InternalKey)
+	require.Equal(t, addr.Amount, receiveAddr.Amount)
+	require.NotEqual(t, addr.ScriptKey, receiveAddr.ScriptKey)
+	require.Equal(
+		t, StaticReceiveScriptKey(&addr.ScriptKey, nonce),
+		&receiveAddr.ScriptKey,
+	)
+
+	// The receiver must be able to find the script key of the address and
+	// the nonce in the tapscript sibling of the anchor output.
+	baseKey, parsedNonce, err := ParseStaticReceiveSibling(
+		receiveAddr.TapscriptSibling,
+	)
+	require.NoError(t, err)
+	require.Equal(
+		t, schnorr.SerializePubKey(&addr.ScriptKey),
+		schnorr.SerializePubKey(baseKey),
+	)
+	require.Equal(t, nonce, parsedNonce)
+
+	// The receiver spends the asset with the raw script key, tweaked with
+	// the nonce.
+	keyInfo := &AddrWithKeyInfo{
+		Tap: addr,
+		ScriptKeyTweak: asset.TweakedScriptKey{
+			RawKey: keychain.KeyDescriptor{
+				PubKey: &addr.ScriptKey,
+			},
+		},
+	}
+	receiveKeyInfo, err := keyInfo.StaticReceiveFromSibling(
+		receiveAddr.TapscriptSibling,
+	)
+	require.NoError(t, err)
+	require.Equal(t, receiveAddr.ScriptKey, receiveKeyInfo.ScriptKey)
+	require.Equal(t, nonce[:], receiveKeyInfo.ScriptKeyTweak.Tweak)
+
+	// A sibling of a receive through another static address is rejected.
+	otherKeyInfo := &AddrWithKeyInfo{
+		Tap: receiveAddr.Copy(),
+	}
+	otherKeyInfo.Static = true
+	otherKeyInfo.ScriptKey = *test.RandPubKey(t)
+	_, err = otherKeyInfo.StaticReceiveFromSibling(
+		receiveAddr.TapscriptSibling,
+	)
+	require.Error(t, err)
+
+	// Any other sibling isn't a static receive.
+	_, _, err = ParseStaticReceiveSibling(commitment.NewPreimageFromLeaf(
+		txscript.NewBaseTapLeaf([]byte("not a valid script")),
+	))
+	require.ErrorIs(t, err, ErrNoStaticReceive)
+	_, _, err = ParseStaticReceiveSibling(nil)
+	require.ErrorIs(t, err, ErrNoStaticReceive)
+}
+
+// TestAddressForAmount tests that the sender can only choose the amount to
+// send to an address that doesn't specify one.
+func TestAddressForAmount(t *testing.T) {
+	t.Parallel()
+
+	zeroAmt := uint64(0)
+	addr, err := randAddress(
+		t, &TestNet3Tap, false, false, &zeroAmt, asset.Normal,
+	)
+	require.NoError(t, err)
+	require.False(t, addr.HasAmount())
+
+	// The amount must not be encoded at all.
+	var buf bytes.Buffer
+	require.NoError(t, addr.Encode(&buf))
+	var fixedBuf bytes.Buffer
+	fixedAddr := addr.Copy()
+	fixedAddr.Amount = 1
+	require.NoError(t, fixedAddr.Encode(&fixedBuf))
+	require.Less(t, buf.Len(), fixedBuf.Len())
+
+	_, err = addr.ForAmount(0)
+	require.ErrorIs(t, err, ErrInvalidAmountNormal)
+
+	sendAddr, err := addr.ForAmount(123)
+	require.NoError(t, err)
+	require.True(t, sendAddr.HasAmount())
+	require.EqualValues(t, 123, sendAddr.Amount)
+	require.False(t, addr.HasAmount())
+
+	// Once the amount is set, it can't be changed anymore.
+	_, err = sendAddr.ForAmount(456)
+	require.ErrorIs(t, err, ErrFixedAmount)
+}
+
+// TestAddressForAssetID tests that the sender can only choose the asset ID to
+// send to an address that accepts any asset of a group.
+func TestAddressForAssetID(t *testing.T) {
+	t.Parallel()
+
+	addr, err := randAddress(
+		t, &TestNet3Tap, true, false, nil, asset.Normal,
+	)
+	require.NoError(t, err)
+
+	otherID := asset.RandID(t)
+	_, err = addr.ForAssetID(otherID)
+	require.ErrorIs(t, err, ErrNotGroupAddr)
+
+	addr.AnyAssetInGroup = true
+	sendAddr, err := addr.ForAssetID(otherID)
+	require.NoError(t, err)
+	require.Equal(t, otherID, sendAddr.AssetID)
+	require.False(t, sendAddr.AnyAssetInGroup)
+	require.True(t, addr.AnyAssetInGroup)
+	require.NotEqual(t, otherID, addr.AssetID)
+
+	// An address that doesn't contain a group key can't be decoded as an
+	// address that accepts any asset of a group.
+	addr.GroupKey = nil
+	encoded, err := addr.EncodeAddress()
+	require.NoError(t, err)
+	_, err = DecodeAddress(encoded, &TestNet3Tap)
+	require.ErrorIs(t, err, ErrMissingGroupKey)
+}
+
+// TestAddressExpiry tests that an address only expires once its expiry is
+// reached and that addresses without an expiry never expire.
+func TestAddressExpiry(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	addr, err := New(
+		asset.RandGenesis(t, asset.Normal), nil, nil, *pubKey, *pubKey,
+		1, nil, &TestNet3Tap, WithExpiry(now.Add(time.Hour)),
+	)
+	require.NoError(t, err)
+	require.True(t, addr.HasExpiry())
+
+	// The expiry is only stored with second precision.
+	require.Equal(t, int64(0), int64(addr.Expiry.Nanosecond()))
+
+	require.False(t, addr.IsExpired(now))
+	require.True(t, addr.IsExpired(addr.Expiry))
+	require.True(t, addr.IsExpired(now.Add(2*time.Hour)))
+
+	addr.Expiry = time.Time{}
+	require.False(t, addr.HasExpiry())
+	require.False(t, addr.IsExpired(now.Add(100*365*24*time.Hour)))
+}
+
+// TestPaymentRequestURI tests that payment request URIs survive an encode and
+// decode round trip and that invalid URIs are rejected.
+func TestPaymentRequestURI(t *testing.T) {
+	t.Parallel()
+
+	addr, encodedAddr, err := randEncodedAddress(
+		t, &TestNet3Tap, false, false, asset.Normal,
+	)
+	require.NoError(t, err)
+
+	courierAddr, err := url.ParseRequestURI("universerpc://localhost:10029")
+	require.NoError(t, err)
+	mailboxAddr, err := url.ParseRequestURI("hashmail://localhost:443")
+	require.NoError(t, err)
+
+	req := &PaymentRequest{
+		Addr:         addr,
+		Label:        "Coffee & Co",
+		Message:      "order #42 = 100%",
+		CourierHints: []*url.URL{courierAddr, mailboxAddr},
+		Expiry:       time.Unix(1700000000, 0),
+	}
+	uri, err := req.EncodeURI()
+	require.NoError(t, err)
+	require.Equal(
+		t, "taprootassets:"+encodedAddr+"?label=Coffee%20%26%20Co&"+
+			"message=order%20%2342%20%3D%20100%25&courier="+
+			"universerpc%3A%2F%2Flocalhost%3A10029&courier="+
+			"hashmail%3A%2F%2Flocalhost%3A443&"+
+			"expiry=1700000000",
+		uri,
+	)
+
+	decoded, err := DecodePaymentRequestURI(uri, &TestNet3Tap)
+	require.NoError(t, err)
+	assertAddressEqual(t, addr, decoded.Addr)
+	require.Equal(t, req.Label, decoded.Label)
+	require.Equal(t, req.Message, decoded.Message)
+	require.Equal(t, req.CourierHints, decoded.CourierHints)
+	require.Equal(t, req.Expiry, decoded.Expiry)
+	require.True(t, decoded.IsExpired(time.Unix(1700000000, 0)))
+
+	// A URI without any parameters only carries the address. Upper case
+	// URIs as used in QR codes must be accepted as well.
+	decoded, err = DecodePaymentRequestURI(
+		strings.ToUpper("taprootassets:"+encodedAddr), &TestNet3Tap,
+	)
+	require.NoError(t, err)
+	assertAddressEqual(t, addr, decoded.Addr)
+	require.Empty(t, decoded.Label)
+	require.False(t, decoded.HasExpiry())
+
+	// Unknown parameters are ignored, unless they are required.
+	_, err = DecodePaymentRequestURI(
+		"taprootassets:"+encodedAddr+"?foo=bar", &TestNet3Tap,
+	)
+	require.NoError(t, err)
+
+	_, err = DecodePaymentRequestURI(
+		"taprootassets:"+encodedAddr+"?req-foo=bar", &TestNet3Tap,
+	)
+	require.ErrorIs(t, err, ErrUnknownRequiredURIParam)
+
+	_, err = DecodePaymentRequestURI(
+		"taprootassets:"+encodedAddr+"?label=a&label=b", &TestNet3Tap,
+	)
+	require.ErrorIs(t, err, ErrDuplicateURIParam)
+
+	_, err = DecodePaymentRequestURI("bitcoin:"+encodedAddr, &TestNet3Tap)
+	require.ErrorIs(t, err, ErrInvalidURIScheme)
+
+	_, err = DecodePaymentRequestURI(
+		"taprootassets:"+encodedAddr, &MainNetTap,
+	)
+	require.ErrorIs(t, err, ErrMismatchedHRP)
+
+	// Courier hints must be valid proof courier addresses.
+	_, err = DecodePaymentRequestURI(
+		"taprootassets:"+encodedAddr+"?courier=https://localhost:443",
+		&TestNet3Tap,
+	)
+	require.ErrorContains(t, err, "unknown proof courier protocol")
+
+	_, err = DecodePaymentRequestURI(
+		"taprootassets:"+encodedAddr+"?courier=hashmail://localhost",
+		&TestNet3Tap,
+	)
+	require.ErrorContains(t, err, "must specify host and port")
+
+	// A courier hint can't be repeated, neither by another hint nor by
+	// the courier of the address itself.
+	_, err = DecodePaymentRequestURI(
+		"taprootassets:"+encodedAddr+"?courier="+mailboxAddr.String()+
+			"&courier="+mailboxAddr.String(), &TestNet3Tap,
+	)
+	require.ErrorIs(t, err, ErrDuplicateURIParam)
+
+	courierReq := &PaymentRequest{
+		Addr:         addr.Copy(),
+		CourierHints: []*url.URL{courierAddr},
+	}
+	courierReq.Addr.ProofCourierAddr = courierAddr
+	_, err = courierReq.EncodeURI()
+	require.ErrorIs(t, err, ErrDuplicateURIParam)
+
+	courierEncodedAddr, err := courierReq.Addr.EncodeAddress()
+	require.NoError(t, err)
+	_, err = DecodePaymentRequestURI(
+		"taprootassets:"+courierEncodedAddr+"?courier="+
+			courierAddr.String(), &TestNet3Tap,
+	)
+	require.ErrorIs(t, err, ErrDuplicateURIParam)
+
+	// The payment request can't expire after the address it wraps, but
+	// it can expire earlier.
+	expiryReq := &PaymentRequest{
+		Addr:   addr.Copy(),
+		Expiry: time.Unix(1700000001, 0),
+	}
+	expiryReq.Addr.Expiry = time.Unix(1700000000, 0)
+	_, err = expiryReq.EncodeURI()
+	require.ErrorIs(t, err, ErrConflictingURIParam)
+
+	expiryEncodedAddr, err := expiryReq.Addr.EncodeAddress()
+	require.NoError(t, err)
+	_, err = DecodePaymentRequestURI(
+		"taprootassets:"+expiryEncodedAddr+"?expiry=1700000001",
+		&TestNet3Tap,
+	)
+	require.ErrorIs(t, err, ErrConflictingURIParam)
+
+	expiryReq.Expiry = time.Unix(1699999999, 0)
+	uri, err = expiryReq.EncodeURI()
+	require.NoError(t, err)
+
+	decoded, err = DecodePaymentRequestURI(uri, &TestNet3Tap)
+	require.NoError(t, err)
+	require.Equal(t, expiryReq.Expiry, decoded.Expiry)
+	require.Equal(t, expiryReq.Addr.Expiry, decoded.Addr.Expiry)
+}
@@ -48,6 +48,42 @@ type AddrWithKeyInfo struct {
 	ManagedAfter time.Time
 }
 
+// ForAmount returns a copy of an address that doesn't specify an amount, with
+// the amount set to the one that was received through it. The Taproot output
+// key of the returned address is still the one of the original address, as
+// that is what identifies the address in the address book.
+func (a *AddrWithKeyInfo) ForAmount(amt uint64) (*AddrWithKeyInfo, error) {
+	receiveAddr, err := a.Tap.ForAmount(amt)
+	if err != nil {
+		return nil, err
+	}
+
+	addrCopy := *a
+	addrCopy.Tap = receiveAddr
+
+	return &addrCopy, nil
+}
+
+// ForGroupAsset returns a copy of an address that accepts any asset of a
+// group, with the asset ID, genesis and group signature set to the ones of the
+// tranche that was received through it. Like with ForAmount, the Taproot
+// output key of the returned address is still the one of the original address.
+func (a *AddrWithKeyInfo) ForGroupAsset(gen asset.Genesis,
+	groupSig schnorr.Signature) (*AddrWithKeyInfo, error) {
+
+	receiveAddr, err := a.Tap.ForAssetID(gen.ID())
+	if err != nil {
+		return nil, err
+	}
+	receiveAddr.AttachGenesis(gen)
+	receiveAddr.AttachGroupSig(groupSig)
+
+	addrCopy := *a
+	addrCopy.Tap = receiveAddr
+
+	return &addrCopy, nil
+}
+
 // QueryParams holds the set of query params for the address book.
 type QueryParams struct {
 	// CreatedAfter if set, only addresses created after the time will be
@@ -68,6 +104,18 @@ type QueryParams struct {
 	// UnmanagedOnly is a boolean pointer indicating whether only addresses
 	// should be returned that are not yet managed by the wallet.
 	UnmanagedOnly bool
+
+	// StaticOnly indicates whether only static addresses should be
+	// returned.
+	StaticOnly bool
+
+	// AmountlessOnly indicates whether only addresses that don't specify
+	// an amount should be returned.
+	AmountlessOnly bool
+
+	// GroupOnly indicates whether only addresses that accept any asset of
+	// a group should be returned.
+	GroupOnly bool
 }
 
 // Storage is the main storage interface for the address book.
@@ -106,6 +154,12 @@ type Storage interface {
 	// database, so it can be recognized as belonging to the wallet when a
 	// transfer comes in later on.
 	InsertScriptKey(ctx context.Context, scriptKey asset.ScriptKey) error
+
+	// PruneAddrs deletes all addresses that can no longer receive any
+	// transfers at the given time, together with their events, and
+	// returns them. Addresses with pending events are kept.
+	PruneAddrs(ctx context.Context, now time.Time) ([]AddrWithKeyInfo,
+		error)
 }
 
 // KeyRing is used to create script and internal keys for Taproot Asset
@@ -173,8 +227,8 @@ func NewBook(cfg BookConfig) *Book {
 
 // NewAddress creates a new Taproot Asset address based on the input parameters.
 func (b *Book) NewAddress(ctx context.Context, assetID asset.ID, amount uint64,
-	tapscriptSibling *commitment.TapscriptPreimage) (*AddrWithKeyInfo,
-	error) {
+	tapscriptSibling *commitment.TapscriptPreimage,
+	opts ...NewAddrOpt) (*AddrWithKeyInfo, error) {
 
 	// Before we proceed and make new keys, make sure that we actually know
 	// of this asset ID already.
@@ -200,7 +254,41 @@ func (b *Book) NewAddress(ctx context.Context, assetID asset.ID, amount uint64,
 
 	return b.NewAddressWithKeys(
 		ctx, assetID, amount, scriptKey, internalKeyDesc,
-		tapscriptSibling,
+		tapscriptSibling, opts...,
+	)
+}
+
+// NewStaticAddress creates a new static Taproot Asset address that can be used
+// to receive any number of transfers. The script key of a static address is an
+// untweaked wallet key, as the sender of each transfer tweaks it with a nonce
+// of its own to derive the unique script key of the receive.
+func (b *Book) NewStaticAddress(ctx context.Context, assetID asset.ID,
+	amount uint64, opts ...NewAddrOpt) (*AddrWithKeyInfo, error) {
+
+	if _, err := b.cfg.Store.QueryAssetGroup(ctx, assetID); err != nil {
+		return nil, fmt.Errorf("unable to make address for unknown "+
+			"asset %x: %w", assetID[:], err)
+	}
+
+	rawScriptKeyDesc, err := b.cfg.KeyRing.DeriveNextTaprootAssetKey(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to gen key: %w", err)
+	}
+	scriptKey := asset.ScriptKey{
+		PubKey: rawScriptKeyDesc.PubKey,
+		TweakedScriptKey: &asset.TweakedScriptKey{
+			RawKey: rawScriptKeyDesc,
+		},
+	}
+
+	internalKeyDesc, err := b.cfg.KeyRing.DeriveNextTaprootAssetKey(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to gen key: %w", err)
+	}
+
+	return b.NewAddressWithKeys(
+		ctx, assetID, amount, scriptKey, internalKeyDesc, nil,
+		append(opts, WithStatic())...,
 	)
 }
 
@@ -209,8 +297,8 @@ func (b *Book) NewAddress(ctx context.Context, assetID asset.ID, amount uint64,
 func (b *Book) NewAddressWithKeys(ctx context.Context, assetID asset.ID,
 	amount uint64, scriptKey asset.ScriptKey,
 	internalKeyDesc keychain.KeyDescriptor,
-	tapscriptSibling *commitment.TapscriptPreimage) (*AddrWithKeyInfo,
-	error) {
+	tapscriptSibling *commitment.TapscriptPreimage,
+	opts ...NewAddrOpt) (*AddrWithKeyInfo, error) {
 
 	// Before we proceed, we'll make sure that the asset group is known to
 	// the local store. Otherwise, we can't make an address as we haven't
@@ -233,11 +321,22 @@ func (b *Book) NewAddressWithKeys(ctx context.Context, assetID asset.ID,
 	baseAddr, err := New(
 		*assetGroup.Genesis, groupKey, groupSig, *scriptKey.PubKey,
 		*internalKeyDesc.PubKey, amount, tapscriptSibling, &b.cfg.Chain,
+		opts...,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("unable to make new addr: %w", err)
 	}
 
+	// The receives through a static address can only be spent if the
+	// nonce of the sender is the only tweak of the raw script key.
+	if baseAddr.Static && (scriptKey.TweakedScriptKey == nil ||
+		len(scriptKey.Tweak) > 0 ||
+		!scriptKey.PubKey.IsEqual(scriptKey.RawKey.PubKey)) {
+
+		return nil, fmt.Errorf("static address requires an untweaked " +
+			"script key")
+	}
+
 	taprootOutputKey, err := baseAddr.TaprootOutputKey()
 	if err != nil {
 		return nil, fmt.Errorf("unable to derive Taproot output key:"+
@@ -323,6 +422,14 @@ func (b *Book) NextScriptKey(ctx context.Context,
 	return scriptKey, nil
 }
 
+// InsertScriptKey inserts a script key into the database, so it can be
+// recognized as belonging to the wallet when a transfer comes in later on.
+func (b *Book) InsertScriptKey(ctx context.Context,
+	scriptKey asset.ScriptKey) error {
+
+	return b.cfg.Store.InsertScriptKey(ctx, scriptKey)
+}
+
 // ListAddrs lists a set of addresses based on the expressed query params.
 func (b *Book) ListAddrs(ctx context.Context,
 	params QueryParams) ([]AddrWithKeyInfo, error) {
@@ -338,6 +445,13 @@ func (b *Book) AddrByTaprootOutput(ctx context.Context,
 	return b.cfg.Store.AddrByTaprootOutput(ctx, key)
 }
 
+// PruneAddrs removes all addresses from the address book that expired or that
+// are single-use and already received their transfer. Addresses with pending
+// events are only pruned once all their receives completed.
+func (b *Book) PruneAddrs(ctx context.Context) ([]AddrWithKeyInfo, error) {
+	return b.cfg.Store.PruneAddrs(ctx, time.Now())
+}
+
 // SetAddrManaged sets an address as being managed by the internal
 // wallet.
 func (b *Book) SetAddrManaged(ctx context.Context, addr *AddrWithKeyInfo,
@@ -1,7 +1,10 @@
 package address
 
 import (
+	"fmt"
 	"io"
+	"net/url"
+	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/lightningnetwork/lnd/tlv"
@@ -40,3 +43,56 @@ func compressedPubKeyDecoder(r io.Reader, val any, buf *[8]byte, l uint64) error
 		val, "*btcec.PublicKey", l, btcec.PubKeyBytesLenCompressed,
 	)
 }
+
+// UrlEncoder encodes a url.URL as a variable length byte slice.
+func UrlEncoder(w io.Writer, val any, buf *[8]byte) error {
+	if t, ok := val.(**url.URL); ok {
+		addrBytes := []byte((*t).String())
+		return tlv.EVarBytes(w, &addrBytes, buf)
+	}
+	return tlv.NewTypeForEncodingErr(val, "**url.URL")
+}
+
+// UrlDecoder decodes a variable length byte slice as an url.URL.
+func UrlDecoder(r io.Reader, val any, buf *[8]byte, l uint64) error {
+	if typ, ok := val.(**url.URL); ok {
+		var addrBytes []byte
+		err := tlv.DVarBytes(r, &addrBytes, buf, l)
+		if err != nil {
+			return err
+		}
+
+		addr, err := url.ParseRequestURI(string(addrBytes))
+		if err != nil {
+			return fmt.Errorf("unable to parse proof courier "+
+				"address: %w", err)
+		}
+
+		*typ = addr
+		return nil
+	}
+	return tlv.NewTypeForDecodingErr(val, "**url.URL", l, l)
+}
+
+// unixTimeEncoder encodes a time.Time as its Unix timestamp in seconds.
+func unixTimeEncoder(w io.Writer, val any, buf *[8]byte) error {
+	if t, ok := val.(*time.Time); ok {
+		unixSeconds := uint64(t.Unix())
+		return tlv.EUint64(w, &unixSeconds, buf)
+	}
+	return tlv.NewTypeForEncodingErr(val, "*time.Time")
+}
+
+// unixTimeDecoder decodes a Unix timestamp in seconds as a time.Time.
+func unixTimeDecoder(r io.Reader, val any, buf *[8]byte, l uint64) error {
+	if typ, ok := val.(*time.Time); ok && l == 8 {
+		var unixSeconds uint64
+		if err := tlv.DUint64(r, &unixSeconds, buf, l); err != nil {
+			return err
+		}
+
+		*typ = time.Unix(int64(unixSeconds), 0)
+		return nil
+	}
+	return tlv.NewTypeForDecodingErr(val, "*time.Time", l, 8)
+}
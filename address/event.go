@@ -93,6 +93,20 @@ type Event struct {
 	// don't keep a reference to it in memory as the proof itself can be
 	// large. The proof can be fetched by the script key of the address.
 	HasProof bool
+
+	// StatusChanges is the list of status changes the event went through,
+	// ordered by status.
+	StatusChanges []StatusChange
+}
+
+// StatusChange records the time at which an address event first reached a
+// certain status.
+type StatusChange struct {
+	// Status is the status the event changed to.
+	Status Status
+
+	// Time is the time at which the event first reached the status.
+	Time time.Time
 }
 
 // EventStorage is the interface that a component storing address events should
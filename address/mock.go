@@ -2,6 +2,7 @@ package address
 
 import (
 	"math/rand"
+	"net/url"
 	"testing"
 	"time"
 
@@ -54,9 +55,18 @@ func RandAddr(t testing.TB, params *ChainParams) (*AddrWithKeyInfo,
 		},
 	})
 
+	var courierAddr *url.URL
+	if rand.Int31()%2 == 0 {
+		courierAddr, err = url.ParseRequestURI(
+			"hashmail://localhost:10029",
+		)
+		require.NoError(t, err)
+	}
+
 	tapAddr, err := New(
 		genesis, groupPubKey, groupSig, *scriptKey.PubKey,
 		*internalKey.PubKey(), amount, tapscriptSibling, params,
+		WithProofCourierAddr(courierAddr),
 	)
 	require.NoError(t, err)
 
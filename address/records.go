@@ -1,6 +1,9 @@
 package address
 
 import (
+	"net/url"
+	"time"
+
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/lightninglabs/taproot-assets/asset"
 	"github.com/lightninglabs/taproot-assets/commitment"
@@ -32,6 +35,29 @@ const (
 
 	// addrAmountType is the TLV type of the amount of the asset.
 	addrAmountType addressTLVType = 8
+
+	// addrProofCourierAddrType is the TLV type of the proof courier address
+	// hint. This is an odd type, so older decoders can safely ignore it.
+	addrProofCourierAddrType addressTLVType = 9
+
+	// addrStaticType is the TLV type of the flag that marks an address as
+	// static. This is an even type, so older decoders that don't know how
+	// to derive a unique script key for each receive refuse the address.
+	addrStaticType addressTLVType = 10
+
+	// addrExpiryType is the TLV type of the optional expiry of the address.
+	// This is an odd type, so older decoders can safely ignore it.
+	addrExpiryType addressTLVType = 11
+
+	// addrAnyAssetInGroupType is the TLV type of the flag that marks an
+	// address as accepting any asset of its group. Such an address doesn't
+	// contain an asset ID, so older decoders must refuse it.
+	addrAnyAssetInGroupType addressTLVType = 12
+
+	// addrSingleUseType is the TLV type of the flag that marks an address
+	// as accepting a single transfer only. This is an odd type, as it only
+	// matters to the receiver.
+	addrSingleUseType addressTLVType = 13
 )
 
 func newAddressVersionRecord(version *asset.Version) tlv.Record {
@@ -91,3 +117,39 @@ func newAddressAmountRecord(amount *uint64) tlv.Record {
 		asset.VarIntEncoder, asset.VarIntDecoder,
 	)
 }
+
+func newProofCourierAddrRecord(addr **url.URL) tlv.Record {
+	sizeFunc := func() uint64 {
+		return uint64(len((*addr).String()))
+	}
+	return tlv.MakeDynamicRecord(
+		addrProofCourierAddrType, addr, sizeFunc, UrlEncoder,
+		UrlDecoder,
+	)
+}
+
+func newAddressStaticRecord(static *bool) tlv.Record {
+	return tlv.MakeStaticRecord(
+		addrStaticType, static, 1, asset.BoolEncoder, asset.BoolDecoder,
+	)
+}
+
+func newAddressAnyAssetInGroupRecord(anyAsset *bool) tlv.Record {
+	return tlv.MakeStaticRecord(
+		addrAnyAssetInGroupType, anyAsset, 1, asset.BoolEncoder,
+		asset.BoolDecoder,
+	)
+}
+
+func newAddressExpiryRecord(expiry *time.Time) tlv.Record {
+	return tlv.MakeStaticRecord(
+		addrExpiryType, expiry, 8, unixTimeEncoder, unixTimeDecoder,
+	)
+}
+
+func newAddressSingleUseRecord(singleUse *bool) tlv.Record {
+	return tlv.MakeStaticRecord(
+		addrSingleUseType, singleUse, 1, asset.BoolEncoder,
+		asset.BoolDecoder,
+	)
+}
@@ -0,0 +1,213 @@
+package address
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightninglabs/taproot-assets/commitment"
+)
+
+const (
+	// StaticNonceSize is the size of the nonce the sender of a transfer to
+	// a static address picks to derive the unique script key of the
+	// receive.
+	StaticNonceSize = 32
+)
+
+var (
+	// ErrNotStatic is returned when a receive is derived from an address
+	// that isn't static.
+	ErrNotStatic = errors.New("address: address is not static")
+
+	// ErrNoStaticReceive is returned when a tapscript sibling doesn't
+	// commit to a receive through a static address.
+	ErrNoStaticReceive = errors.New(
+		"address: tapscript sibling is not a static receive leaf",
+	)
+)
+
+// StaticNonce is the nonce the sender of a transfer to a static address picks
+// to derive the unique script key of the receive.
+type StaticNonce [StaticNonceSize]byte
+
+// RandStaticNonce returns a new random nonce for a receive through a static
+// address.
+func RandStaticNonce() (StaticNonce, error) {
+	var nonce StaticNonce
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return nonce, fmt.Errorf("unable to read random nonce: %w", err)
+	}
+
+	return nonce, nil
+}
+
+// StaticReceiveScriptKey derives the unique script key of a receive through a
+// static address from the script key of the address and the nonce picked by
+// the sender. The nonce is applied as a taproot tweak, so the receiver can
+// spend the asset with a key spend of the raw script key and the nonce as the
+// tweak.
+func StaticReceiveScriptKey(baseKey *btcec.PublicKey,
+	nonce StaticNonce) *btcec.PublicKey {
+
+	scriptKey := txscript.ComputeTaprootOutputKey(baseKey, nonce[:])
+
+	// Make sure we always return the parity stripped key, so sender and
+	// receiver arrive at the exact same key.
+	scriptKey, _ = schnorr.ParsePubKey(schnorr.SerializePubKey(scriptKey))
+
+	return scriptKey
+}
+
+// StaticReceiveLeaf returns the tapscript leaf that is committed to as the
+// tapscript sibling of the anchor output of a receive through a static
+// address. The leaf carries the script key of the address and the nonce, so
+// both sender and receiver can find out which key the receive was derived
+// from. The OP_RETURN makes sure the leaf can never be used to spend the
+// anchor output.
+func StaticReceiveLeaf(baseKey *btcec.PublicKey,
+	nonce StaticNonce) (txscript.TapLeaf, error) {
+
+	script, err := txscript.NewScriptBuilder().
+		AddOp(txscript.OP_RETURN).
+		AddData(schnorr.SerializePubKey(baseKey)).
+		AddData(nonce[:]).
+		Script()
+	if err != nil {
+		return txscript.TapLeaf{}, err
+	}
+
+	return txscript.NewBaseTapLeaf(script), nil
+}
+
+// ParseStaticReceiveSibling extracts the script key of the static address and
+// the nonce of a receive from the tapscript sibling of its anchor output. If
+// the sibling isn't a static receive leaf, ErrNoStaticReceive is returned.
+func ParseStaticReceiveSibling(
+	sibling *commitment.TapscriptPreimage) (*btcec.PublicKey, StaticNonce,
+	error) {
+
+	var nonce StaticNonce
+	if sibling == nil || sibling.SiblingType != commitment.LeafPreimage {
+		return nil, nonce, ErrNoStaticReceive
+	}
+
+	// The leaf pre-image is the leaf version followed by the var bytes
+	// encoded script.
+	preimage := bytes.NewReader(sibling.SiblingPreimage)
+	leafVersion, err := preimage.ReadByte()
+	if err != nil {
+		return nil, nonce, ErrNoStaticReceive
+	}
+	script, err := wire.ReadVarBytes(
+		preimage, 0, uint32(len(sibling.SiblingPreimage)), "script",
+	)
+	if err != nil || preimage.Len() != 0 {
+		return nil, nonce, ErrNoStaticReceive
+	}
+
+	if txscript.TapscriptLeafVersion(leafVersion) !=
+		txscript.BaseLeafVersion {
+
+		return nil, nonce, ErrNoStaticReceive
+	}
+
+	// The script must be exactly OP_RETURN <32 byte key> <32 byte nonce>.
+	const (
+		keyStart   = 2
+		nonceStart = keyStart + schnorr.PubKeyBytesLen + 1
+		scriptLen  = nonceStart + StaticNonceSize
+	)
+	if len(script) != scriptLen || script[0] != txscript.OP_RETURN ||
+		script[keyStart-1] != txscript.OP_DATA_32 ||
+		script[nonceStart-1] != txscript.OP_DATA_32 {
+
+		return nil, nonce, ErrNoStaticReceive
+	}
+
+	baseKey, err := schnorr.ParsePubKey(script[keyStart : nonceStart-1])
+	if err != nil {
+		return nil, nonce, ErrNoStaticReceive
+	}
+	copy(nonce[:], script[nonceStart:])
+
+	return baseKey, nonce, nil
+}
+
+// StaticReceive returns the address of a single receive through the static
+// address, identified by the given nonce. The returned address is a plain,
+// non-static address with the unique script key of the receive and the static
+// receive leaf as its tapscript sibling.
+func (a *Tap) StaticReceive(nonce StaticNonce) (*Tap, error) {
+	if !a.Static {
+		return nil, ErrNotStatic
+	}
+
+	leaf, err := StaticReceiveLeaf(&a.ScriptKey, nonce)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create static receive leaf: "+
+			"%w", err)
+	}
+
+	receiveAddr := a.Copy()
+	receiveAddr.Static = false
+	receiveAddr.ScriptKey = *StaticReceiveScriptKey(&a.ScriptKey, nonce)
+	receiveAddr.TapscriptSibling = commitment.NewPreimageFromLeaf(leaf)
+
+	return receiveAddr, nil
+}
+
+// StaticReceive returns the address of a single receive through the static
+// address, identified by the given nonce, along with the key information the
+// wallet needs to spend the received asset. The Taproot output key of the
+// returned address is still the one of the static address, as that is what
+// identifies the static address in the address book.
+func (a *AddrWithKeyInfo) StaticReceive(
+	nonce StaticNonce) (*AddrWithKeyInfo, error) {
+
+	receiveAddr, err := a.Tap.StaticReceive(nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AddrWithKeyInfo{
+		Tap: receiveAddr,
+		ScriptKeyTweak: asset.TweakedScriptKey{
+			RawKey: a.ScriptKeyTweak.RawKey,
+			Tweak:  append([]byte(nil), nonce[:]...),
+		},
+		InternalKeyDesc:  a.InternalKeyDesc,
+		TaprootOutputKey: a.TaprootOutputKey,
+		CreationTime:     a.CreationTime,
+		ManagedAfter:     a.ManagedAfter,
+	}, nil
+}
+
+// StaticReceiveFromSibling returns the address of the receive through the
+// static address that is identified by the given tapscript sibling of the
+// anchor output. An error is returned if the sibling doesn't commit to a
+// receive through this address.
+func (a *AddrWithKeyInfo) StaticReceiveFromSibling(
+	sibling *commitment.TapscriptPreimage) (*AddrWithKeyInfo, error) {
+
+	baseKey, nonce, err := ParseStaticReceiveSibling(sibling)
+	if err != nil {
+		return nil, err
+	}
+
+	baseKeyBytes := schnorr.SerializePubKey(baseKey)
+	addrKeyBytes := schnorr.SerializePubKey(&a.ScriptKey)
+	if !bytes.Equal(baseKeyBytes, addrKeyBytes) {
+		return nil, fmt.Errorf("static receive was derived from "+
+			"script key %x, not from address script key %x",
+			baseKeyBytes, addrKeyBytes)
+	}
+
+	return a.StaticReceive(nonce)
+}
@@ -0,0 +1,272 @@
+package address
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// URIScheme is the scheme of a payment request URI that wraps a Taproot
+	// Asset address, following the format of BIP-21:
+	//
+	//	taprootassets:<address>[?<param>=<value>[&<param>=<value>...]]
+	URIScheme = "taprootassets"
+
+	// uriParamLabel is the query parameter of the label of the receiver,
+	// for example the name of the shop.
+	uriParamLabel = "label"
+
+	// uriParamMessage is the query parameter of the message describing
+	// the payment, for example an order number.
+	uriParamMessage = "message"
+
+	// uriParamCourier is the query parameter of a proof courier the sender
+	// can use in addition to the one of the address. It can be specified
+	// multiple times, but must not repeat the courier of the address.
+	uriParamCourier = "courier"
+
+	// uriParamExpiry is the query parameter of the expiry of the payment
+	// request in unix timestamp seconds. It must not be later than the
+	// expiry of the address.
+	uriParamExpiry = "expiry"
+
+	// uriRequiredParamPrefix is the prefix of query parameters that must
+	// be understood by the decoder. As in BIP-21, a payment request with
+	// an unknown parameter with this prefix is considered invalid, while
+	// other unknown parameters are ignored.
+	uriRequiredParamPrefix = "req-"
+)
+
+var (
+	// ErrInvalidURIScheme is returned when a payment request URI doesn't
+	// start with the Taproot Asset URI scheme.
+	ErrInvalidURIScheme = errors.New(
+		"address: invalid payment request URI scheme",
+	)
+
+	// ErrUnknownRequiredURIParam is returned when a payment request URI
+	// contains a required parameter we don't understand.
+	ErrUnknownRequiredURIParam = errors.New(
+		"address: unknown required payment request URI parameter",
+	)
+
+	// ErrDuplicateURIParam is returned when a payment request URI contains
+	// a parameter more than once that can only be specified once.
+	ErrDuplicateURIParam = errors.New(
+		"address: duplicate payment request URI parameter",
+	)
+
+	// ErrConflictingURIParam is returned when a parameter of a payment
+	// request URI conflicts with the address it wraps.
+	ErrConflictingURIParam = errors.New(
+		"address: payment request URI parameter conflicts with address",
+	)
+)
+
+// PaymentRequest is a request to pay to a Taproot Asset address, together
+// with optional metadata about the payment. It can be exchanged between
+// wallets and point-of-sale software as a BIP-21 style URI.
+type PaymentRequest struct {
+	// Addr is the address to pay to.
+	Addr *Tap
+
+	// Label is an optional label of the receiver.
+	Label string
+
+	// Message is an optional message that describes the payment.
+	Message string
+
+	// CourierHints is an optional list of proof courier addresses the
+	// sender can use to deliver the proof, in addition to the one
+	// specified in the address itself. Each hint must be unique and differ
+	// from the courier of the address.
+	CourierHints []*url.URL
+
+	// Expiry is the optional time after which the payment request should
+	// no longer be paid. The zero value means the request itself doesn't
+	// expire. If the address expires, the request must not expire later.
+	// The expiry is encoded with a precision of seconds.
+	Expiry time.Time
+}
+
+// validate makes sure the courier hints are valid proof courier addresses and
+// that neither they nor the expiry of the payment request conflict with the
+// address it wraps.
+func (p *PaymentRequest) validate() error {
+	seenCouriers := make(map[string]struct{}, len(p.CourierHints)+1)
+	if p.Addr.ProofCourierAddr != nil {
+		seenCouriers[p.Addr.ProofCourierAddr.String()] = struct{}{}
+	}
+
+	for _, courierAddr := range p.CourierHints {
+		err := ValidateProofCourierAddr(courierAddr)
+		if err != nil {
+			return fmt.Errorf("invalid courier hint: %w", err)
+		}
+
+		if _, ok := seenCouriers[courierAddr.String()]; ok {
+			return fmt.Errorf("%w: courier %v",
+				ErrDuplicateURIParam, courierAddr)
+		}
+		seenCouriers[courierAddr.String()] = struct{}{}
+	}
+
+	if p.HasExpiry() && p.Addr.HasExpiry() &&
+		p.Expiry.Unix() > p.Addr.Expiry.Unix() {
+
+		return fmt.Errorf("%w: expiry %d is after address expiry %d",
+			ErrConflictingURIParam, p.Expiry.Unix(),
+			p.Addr.Expiry.Unix())
+	}
+
+	return nil
+}
+
+// HasExpiry returns true if the payment request itself expires.
+func (p *PaymentRequest) HasExpiry() bool {
+	return !p.Expiry.IsZero()
+}
+
+// IsExpired returns true if either the payment request or the address it
+// wraps expired at the given time.
+func (p *PaymentRequest) IsExpired(now time.Time) bool {
+	if p.HasExpiry() && !now.Before(p.Expiry) {
+		return true
+	}
+
+	return p.Addr != nil && p.Addr.IsExpired(now)
+}
+
+// EncodeURI encodes the payment request as a BIP-21 style URI.
+func (p *PaymentRequest) EncodeURI() (string, error) {
+	if p.Addr == nil {
+		return "", fmt.Errorf("payment request has no address")
+	}
+
+	if err := p.validate(); err != nil {
+		return "", err
+	}
+
+	addr, err := p.Addr.EncodeAddress()
+	if err != nil {
+		return "", err
+	}
+
+	// We don't use url.Values here, as it sorts the parameters and encodes
+	// spaces as '+', while BIP-21 requires them to be percent-encoded.
+	var params []string
+	addParam := func(key, value string) {
+		value = strings.ReplaceAll(url.QueryEscape(value), "+", "%20")
+		params = append(params, key+"="+value)
+	}
+
+	if p.Label != "" {
+		addParam(uriParamLabel, p.Label)
+	}
+	if p.Message != "" {
+		addParam(uriParamMessage, p.Message)
+	}
+	for _, courierAddr := range p.CourierHints {
+		addParam(uriParamCourier, courierAddr.String())
+	}
+	if p.HasExpiry() {
+		addParam(
+			uriParamExpiry, strconv.FormatInt(p.Expiry.Unix(), 10),
+		)
+	}
+
+	uri := URIScheme + ":" + addr
+	if len(params) > 0 {
+		uri += "?" + strings.Join(params, "&")
+	}
+
+	return uri, nil
+}
+
+// DecodePaymentRequestURI decodes a BIP-21 style payment request URI. The
+// address it wraps must be for the given network.
+func DecodePaymentRequestURI(uri string,
+	net *ChainParams) (*PaymentRequest, error) {
+
+	// The scheme is case-insensitive, so URIs can also be encoded in
+	// upper case, which allows for more compact QR codes.
+	scheme, rest, found := strings.Cut(uri, ":")
+	if !found || !strings.EqualFold(scheme, URIScheme) {
+		return nil, ErrInvalidURIScheme
+	}
+
+	addrStr, query, _ := strings.Cut(rest, "?")
+
+	// A bech32m string must either be all lower or all upper case, so
+	// we'll only convert an address that is entirely in upper case.
+	if addrStr == strings.ToUpper(addrStr) {
+		addrStr = strings.ToLower(addrStr)
+	}
+
+	addr, err := DecodeAddress(addrStr, net)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode addr: %w", err)
+	}
+
+	params, err := url.ParseQuery(query)
+	if err != nil {
+		return nil, fmt.Errorf("invalid payment request URI "+
+			"parameters: %w", err)
+	}
+
+	req := &PaymentRequest{
+		Addr: addr,
+	}
+	for key, values := range params {
+		if key != uriParamCourier && len(values) > 1 {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateURIParam,
+				key)
+		}
+
+		switch key {
+		case uriParamLabel:
+			req.Label = values[0]
+
+		case uriParamMessage:
+			req.Message = values[0]
+
+		case uriParamCourier:
+			for _, value := range values {
+				courierAddr, err := url.ParseRequestURI(value)
+				if err != nil {
+					return nil, fmt.Errorf("invalid "+
+						"courier hint: %w", err)
+				}
+
+				req.CourierHints = append(
+					req.CourierHints, courierAddr,
+				)
+			}
+
+		case uriParamExpiry:
+			expiry, err := strconv.ParseInt(values[0], 10, 64)
+			if err != nil || expiry <= 0 {
+				return nil, fmt.Errorf("invalid expiry: %v",
+					values[0])
+			}
+
+			req.Expiry = time.Unix(expiry, 0)
+
+		default:
+			if strings.HasPrefix(key, uriRequiredParamPrefix) {
+				return nil, fmt.Errorf("%w: %s",
+					ErrUnknownRequiredURIParam, key)
+			}
+		}
+	}
+
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
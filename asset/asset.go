@@ -373,6 +373,29 @@ func (w *Witness) Decode(r io.Reader) error {
 	return stream.Decode(r)
 }
 
+// decodeSplitRoot decodes a witness of the root asset of a split commitment.
+// These witnesses can't have a split commitment, which bounds the nesting
+// depth of assets we decode.
+func (w *Witness) decodeSplitRoot(r io.Reader) error {
+	records := w.DecodeRecords()
+	for idx := range records {
+		if records[idx].Type() != WitnessSplitCommitment {
+			continue
+		}
+
+		records[idx] = tlv.MakeDynamicRecord(
+			WitnessSplitCommitment, &w.SplitCommitment, nil,
+			SplitCommitmentEncoder, nestedSplitCommitmentDecoder,
+		)
+	}
+
+	stream, err := tlv.NewStream(records...)
+	if err != nil {
+		return err
+	}
+	return stream.Decode(r)
+}
+
 // DeepEqual returns true if this witness is equal with the given witness.
 func (w *Witness) DeepEqual(o *Witness) bool {
 	if w == nil || o == nil {
@@ -390,6 +413,39 @@ func (w *Witness) DeepEqual(o *Witness) bool {
 	return w.SplitCommitment.DeepEqual(o.SplitCommitment)
 }
 
+// SigHashType returns the sighash type of the signature found in the first
+// element of the witness, which is where the signature of a key path spend or
+// a single signature leaf script is located. SIGHASH_DEFAULT is returned if
+// the first element isn't a signature with an explicit sighash type.
+func (w *Witness) SigHashType() txscript.SigHashType {
+	if len(w.TxWitness) == 0 ||
+		len(w.TxWitness[0]) != schnorr.SignatureSize+1 {
+
+		return txscript.SigHashDefault
+	}
+
+	return txscript.SigHashType(w.TxWitness[0][schnorr.SignatureSize])
+}
+
+// IsSupportedSigHashType returns true if signatures with the given sighash
+// type can be used to spend an asset. The virtual transaction of a state
+// transition only has a single output that commits to all asset outputs, so
+// SIGHASH_SINGLE commits to the same outputs as SIGHASH_ALL. SIGHASH_NONE is
+// not supported, as it would allow anyone to redirect the spent assets.
+func IsSupportedSigHashType(sigHashType txscript.SigHashType) bool {
+	switch sigHashType {
+	case txscript.SigHashDefault, txscript.SigHashAll,
+		txscript.SigHashSingle,
+		txscript.SigHashAll | txscript.SigHashAnyOneCanPay,
+		txscript.SigHashSingle | txscript.SigHashAnyOneCanPay:
+
+		return true
+
+	default:
+		return false
+	}
+}
+
 // ScriptVersion denotes the asset script versioning scheme.
 type ScriptVersion uint16
 
@@ -425,6 +481,18 @@ type GroupKey struct {
 
 	// Sig is a signature over an asset's ID by `Key`.
 	Sig schnorr.Signature
+
+	// TapscriptRoot is the root of the tapscript tree the group key commits
+	// to, if any. This is only known to the issuer and is therefore not
+	// part of the encoded asset.
+	TapscriptRoot []byte
+
+	// Witness is the witness that authorizes the issuance of an asset with
+	// a group key that commits to a tapscript tree. The witness is created
+	// for the group virtual transaction and either contains a key spend
+	// signature or satisfies one of the scripts in the tree. If this is
+	// set, the Sig field is not used.
+	Witness wire.TxWitness
 }
 
 // IsEqual returns true if this group key and signature are exactly equivalent
@@ -443,6 +511,10 @@ func (g *GroupKey) IsEqual(otherGroupKey *GroupKey) bool {
 		return false
 	}
 
+	if !reflect.DeepEqual(g.Witness, otherGroupKey.Witness) {
+		return false
+	}
+
 	return g.Sig.IsEqual(&otherGroupKey.Sig)
 }
 
@@ -500,6 +572,12 @@ type TweakedScriptKey struct {
 	// Tweak is the tweak that is applied on the raw script key to get the
 	// public key. If this is nil, then a BIP-0086 tweak is assumed.
 	Tweak []byte
+
+	// Leaves is the list of tapscript leaves the tweak commits to, in the
+	// order they were assembled into the tapscript tree. This is only set
+	// if the script key was created from known leaves and is needed to
+	// spend an asset through the script path.
+	Leaves []txscript.TapLeaf
 }
 
 // ScriptKey represents a tweaked Taproot output key encumbering the different
@@ -522,6 +600,40 @@ func (s ScriptKey) IsUnSpendable() (bool, error) {
 	return NUMSPubKey.IsEqual(s.PubKey), nil
 }
 
+// GenChallengeNUMS returns the script key an ownership proof spends the owned
+// asset to. Without a challenge, this is the NUMS script key. Otherwise, the
+// NUMS key is tweaked with the challenge (NUMS + challenge*G), which binds the
+// ownership proof to the challenge of a verifier and prevents it from being
+// replayed. The resulting key is still provably un-spendable to anyone that
+// knows the challenge.
+func GenChallengeNUMS(challenge *[32]byte) ScriptKey {
+	if challenge == nil {
+		return NUMSScriptKey
+	}
+
+	var (
+		challengeScalar btcec.ModNScalar
+		challengePoint  btcec.JacobianPoint
+		numsPoint       btcec.JacobianPoint
+		result          btcec.JacobianPoint
+	)
+	challengeScalar.SetBytes(challenge)
+	btcec.ScalarBaseMultNonConst(&challengeScalar, &challengePoint)
+	NUMSPubKey.AsJacobian(&numsPoint)
+	btcec.AddNonConst(&numsPoint, &challengePoint, &result)
+	result.ToAffine()
+
+	pubKey := btcec.NewPublicKey(&result.X, &result.Y)
+	return ScriptKey{
+		PubKey: pubKey,
+		TweakedScriptKey: &TweakedScriptKey{
+			RawKey: keychain.KeyDescriptor{
+				PubKey: pubKey,
+			},
+		},
+	}
+}
+
 // NewScriptKey constructs a ScriptKey with only the publicly available
 // information. This resulting key may or may not have a tweak applied to it.
 func NewScriptKey(key *btcec.PublicKey) ScriptKey {
@@ -572,6 +684,14 @@ type GenesisSigner interface {
 	// The final tweaked public key and the signature are returned.
 	SignGenesis(keychain.KeyDescriptor, Genesis,
 		*Genesis) (*btcec.PublicKey, *schnorr.Signature, error)
+
+	// SignGroupVirtualTx signs the passed group virtual transaction with
+	// the key identified by the passed key descriptor. If no tap leaf is
+	// passed, a key spend signature for the group key that commits to the
+	// passed tapscript root is created. Otherwise, a script spend signature
+	// for the passed tap leaf is created.
+	SignGroupVirtualTx(keychain.KeyDescriptor, []byte, *txscript.TapLeaf,
+		*wire.MsgTx, *wire.TxOut) (*schnorr.Signature, error)
 }
 
 // RawKeyGenesisSigner implements the GenesisSigner interface using a raw
@@ -631,6 +751,24 @@ func (r *RawKeyGenesisSigner) SignGenesis(keyDesc keychain.KeyDescriptor,
 	return tweakedPrivKey.PubKey(), sig, nil
 }
 
+// SignGroupVirtualTx signs the passed group virtual transaction with the key
+// identified by the passed key descriptor. If no tap leaf is passed, a key
+// spend signature for the group key that commits to the passed tapscript root
+// is created. Otherwise, a script spend signature for the passed tap leaf is
+// created.
+func (r *RawKeyGenesisSigner) SignGroupVirtualTx(keyDesc keychain.KeyDescriptor,
+	tapscriptRoot []byte, leaf *txscript.TapLeaf, virtualTx *wire.MsgTx,
+	prevOut *wire.TxOut) (*schnorr.Signature, error) {
+
+	if !keyDesc.PubKey.IsEqual(r.privKey.PubKey()) {
+		return nil, fmt.Errorf("cannot sign with key")
+	}
+
+	return signGroupVirtualTx(
+		r.privKey, tapscriptRoot, leaf, virtualTx, prevOut,
+	)
+}
+
 // A compile-time assertion to ensure RawKeyGenesisSigner meets the
 // GenesisSigner interface.
 var _ GenesisSigner = (*RawKeyGenesisSigner)(nil)
@@ -697,6 +835,20 @@ type Asset struct {
 	// together across distinct asset IDs, allowing further issuance of the
 	// asset to be made possible.
 	GroupKey *GroupKey
+
+	// NonDivisible, if true, marks a normal asset as non-divisible. Such an
+	// asset can be transferred as a whole, but it can never be split into
+	// multiple non-zero outputs. This flag is set at genesis and must be
+	// carried along unchanged by every state transition.
+	NonDivisible bool
+
+	// Edition is the serial number of a collectible that was minted as
+	// one of several editions sharing the same genesis. Editions are
+	// numbered starting from 1, a value of 0 means the asset isn't an
+	// edition. The serial is committed to in the asset leaf, set at
+	// genesis and must be carried along unchanged by every state
+	// transition.
+	Edition uint64
 }
 
 // New instantiates a new asset with a genesis asset witness.
@@ -865,6 +1017,13 @@ func (a *Asset) Copy() *Asset {
 		assetCopy.ScriptKey.RawKey = a.ScriptKey.RawKey
 		assetCopy.ScriptKey.Tweak = make([]byte, len(a.ScriptKey.Tweak))
 		copy(assetCopy.ScriptKey.Tweak, a.ScriptKey.Tweak)
+
+		if len(a.ScriptKey.Leaves) > 0 {
+			assetCopy.ScriptKey.Leaves = make(
+				[]txscript.TapLeaf, len(a.ScriptKey.Leaves),
+			)
+			copy(assetCopy.ScriptKey.Leaves, a.ScriptKey.Leaves)
+		}
 	}
 
 	if a.GroupKey != nil {
@@ -873,11 +1032,58 @@ func (a *Asset) Copy() *Asset {
 			GroupPubKey: a.GroupKey.GroupPubKey,
 			Sig:         a.GroupKey.Sig,
 		}
+
+		if len(a.GroupKey.TapscriptRoot) > 0 {
+			assetCopy.GroupKey.TapscriptRoot = make(
+				[]byte, len(a.GroupKey.TapscriptRoot),
+			)
+			copy(
+				assetCopy.GroupKey.TapscriptRoot,
+				a.GroupKey.TapscriptRoot,
+			)
+		}
+
+		if len(a.GroupKey.Witness) > 0 {
+			assetCopy.GroupKey.Witness = make(
+				wire.TxWitness, len(a.GroupKey.Witness),
+			)
+			for i, witnessItem := range a.GroupKey.Witness {
+				assetCopy.GroupKey.Witness[i] = make(
+					[]byte, len(witnessItem),
+				)
+				copy(assetCopy.GroupKey.Witness[i], witnessItem)
+			}
+		}
 	}
 
 	return &assetCopy
 }
 
+// CopyWithoutSplitCommitment returns a deep copy of the asset with the split
+// commitment proofs removed from its witnesses. This is the form in which an
+// asset created by a split is committed to in its anchor output, as the split
+// commitment proof is only delivered to the receiver within the proof file.
+func (a *Asset) CopyWithoutSplitCommitment() *Asset {
+	assetCopy := a.Copy()
+	for idx := range assetCopy.PrevWitnesses {
+		assetCopy.PrevWitnesses[idx].SplitCommitment = nil
+	}
+
+	return assetCopy
+}
+
+// ReplaceWitnesses strips all witnesses of the asset, including any split
+// commitment witness, and replaces them with a single unsigned witness that
+// spends the given previous input. As the asset is spent in full, the split
+// commitment root is removed as well. The asset needs to be signed again
+// before it can be committed to a new anchor output.
+func (a *Asset) ReplaceWitnesses(prevID PrevID) {
+	a.PrevWitnesses = []Witness{{
+		PrevID: &prevID,
+	}}
+	a.SplitCommitmentRoot = nil
+}
+
 // DeepEqual returns true if this asset is equal with the given asset.
 func (a *Asset) DeepEqual(o *Asset) bool {
 	if a.Version != o.Version {
@@ -914,6 +1120,14 @@ func (a *Asset) DeepEqual(o *Asset) bool {
 		return false
 	}
 
+	if a.NonDivisible != o.NonDivisible {
+		return false
+	}
+
+	if a.Edition != o.Edition {
+		return false
+	}
+
 	if len(a.PrevWitnesses) != len(o.PrevWitnesses) {
 		return false
 	}
@@ -930,7 +1144,7 @@ func (a *Asset) DeepEqual(o *Asset) bool {
 // EncodeRecords determines the non-nil records to include when encoding an
 // asset at runtime.
 func (a *Asset) EncodeRecords() []tlv.Record {
-	records := make([]tlv.Record, 0, 11)
+	records := make([]tlv.Record, 0, 13)
 	records = append(records, NewLeafVersionRecord(&a.Version))
 	records = append(records, NewLeafGenesisRecord(&a.Genesis))
 	records = append(records, NewLeafTypeRecord(&a.Type))
@@ -958,6 +1172,14 @@ func (a *Asset) EncodeRecords() []tlv.Record {
 	if a.GroupKey != nil {
 		records = append(records, NewLeafGroupKeyRecord(&a.GroupKey))
 	}
+	if a.NonDivisible {
+		records = append(records, NewLeafNonDivisibleRecord(
+			&a.NonDivisible,
+		))
+	}
+	if a.Edition > 0 {
+		records = append(records, NewLeafEditionRecord(&a.Edition))
+	}
 	return records
 }
 
@@ -976,6 +1198,8 @@ func (a *Asset) DecodeRecords() []tlv.Record {
 		NewLeafScriptVersionRecord(&a.ScriptVersion),
 		NewLeafScriptKeyRecord(&a.ScriptKey.PubKey),
 		NewLeafGroupKeyRecord(&a.GroupKey),
+		NewLeafNonDivisibleRecord(&a.NonDivisible),
+		NewLeafEditionRecord(&a.Edition),
 	}
 }
 
@@ -997,6 +1221,28 @@ func (a *Asset) Decode(r io.Reader) error {
 	return stream.Decode(r)
 }
 
+// decodeSplitRoot decodes the root asset of a split commitment. The witnesses
+// of a split root can't have split commitments themselves.
+func (a *Asset) decodeSplitRoot(r io.Reader) error {
+	records := a.DecodeRecords()
+	for idx := range records {
+		if records[idx].Type() != LeafPrevWitness {
+			continue
+		}
+
+		records[idx] = tlv.MakeDynamicRecord(
+			LeafPrevWitness, &a.PrevWitnesses, nil, WitnessEncoder,
+			splitRootWitnessDecoder,
+		)
+	}
+
+	stream, err := tlv.NewStream(records...)
+	if err != nil {
+		return err
+	}
+	return stream.Decode(r)
+}
+
 // Leaf returns the asset encoded as a MS-SMT leaf node.
 func (a *Asset) Leaf() (*mssmt.LeafNode, error) {
 	var buf bytes.Buffer
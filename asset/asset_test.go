@@ -4,6 +4,9 @@ import (
 	"bytes"
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"testing"
@@ -12,8 +15,10 @@ import (
 	"github.com/btcsuite/btcd/btcec/v2/schnorr"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
+	"github.com/lightninglabs/taproot-assets/internal/test"
 	"github.com/lightninglabs/taproot-assets/mssmt"
 	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/tlv"
 	"github.com/stretchr/testify/require"
 )
 
@@ -283,6 +288,8 @@ func TestAssetEncoding(t *testing.T) {
 		ScriptVersion:       2,
 		ScriptKey:           NewScriptKey(pubKey),
 		GroupKey:            nil,
+		NonDivisible:        true,
+		Edition:             1337,
 	})
 }
 
@@ -408,13 +415,65 @@ func TestAssetGroupKey(t *testing.T) {
 	)
 }
 
+// FuzzAssetDecode tests that decoding arbitrary asset leaves and encoding the
+// decoded assets again doesn't panic.
 func FuzzAssetDecode(f *testing.F) {
+	fileContent, err := os.ReadFile(filepath.Join("testdata", "asset.hex"))
+	require.NoError(f, err)
+	rawBytes, err := hex.DecodeString(string(fileContent))
+	require.NoError(f, err)
+
+	f.Add(rawBytes)
+	f.Add(encodeAsset(f, RandAsset(f, Normal)))
+	f.Add(encodeAsset(f, RandAsset(f, Collectible)))
+	f.Add(encodeAsset(f, splitAsset(f, false)))
+
 	f.Fuzz(func(t *testing.T, data []byte) {
-		r := bytes.NewReader(data)
-		a := &Asset{}
-		if err := a.Decode(r); err != nil {
+		var a Asset
+		if err := a.Decode(bytes.NewReader(data)); err != nil {
 			return
 		}
+
+		var b bytes.Buffer
+		_ = a.Encode(&b)
+	})
+}
+
+// FuzzWitnessDecode tests that decoding arbitrary asset witnesses doesn't
+// panic.
+func FuzzWitnessDecode(f *testing.F) {
+	for _, a := range []*Asset{
+		RandAsset(f, Normal), splitAsset(f, false),
+	} {
+		var b bytes.Buffer
+		err := WitnessEncoder(&b, &a.PrevWitnesses, &[8]byte{})
+		require.NoError(f, err)
+
+		f.Add(b.Bytes())
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var witnesses []Witness
+		_ = WitnessDecoder(
+			bytes.NewReader(data), &witnesses, &[8]byte{},
+			uint64(len(data)),
+		)
+	})
+}
+
+// FuzzGenesisDecode tests that decoding arbitrary asset geneses doesn't panic.
+func FuzzGenesisDecode(f *testing.F) {
+	genesis := RandGenesis(f, Normal)
+	var b bytes.Buffer
+	require.NoError(f, GenesisEncoder(&b, &genesis, &[8]byte{}))
+	f.Add(b.Bytes())
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var genesis Genesis
+		_ = GenesisDecoder(
+			bytes.NewReader(data), &genesis, &[8]byte{},
+			uint64(len(data)),
+		)
 	})
 }
 
@@ -433,3 +492,573 @@ func TestDecodeHex(t *testing.T) {
 	err = a.Decode(bytes.NewReader(rawBytes))
 	require.NoError(t, err)
 }
+
+// TestBurnKey tests that burn keys are derived deterministically from the
+// first input of a transfer and are correctly recognized.
+func TestBurnKey(t *testing.T) {
+	t.Parallel()
+
+	prevID := PrevID{
+		OutPoint: wire.OutPoint{
+			Hash:  hashBytes1,
+			Index: 1,
+		},
+		ID:        hashBytes2,
+		ScriptKey: ToSerialized(pubKey),
+	}
+	burnKey := DeriveBurnKey(prevID)
+	require.True(t, burnKey.IsEqual(DeriveBurnKey(prevID)))
+	require.False(t, burnKey.IsEqual(NUMSPubKey))
+
+	burnScriptKey := NewBurnScriptKey(prevID)
+	require.True(t, burnScriptKey.PubKey.IsEqual(burnKey))
+
+	// The burn key must be recognized for the direct witness.
+	witness := Witness{
+		PrevID: &prevID,
+	}
+	require.True(t, IsBurnKey(burnKey, witness))
+	require.False(t, IsBurnKey(pubKey, witness))
+
+	// A different prev ID results in a different burn key.
+	otherPrevID := prevID
+	otherPrevID.OutPoint.Index = 2
+	require.False(t, IsBurnKey(burnKey, Witness{PrevID: &otherPrevID}))
+
+	// For split assets, the prev ID of the root asset is used.
+	splitWitness := Witness{
+		PrevID: &ZeroPrevID,
+		SplitCommitment: &SplitCommitment{
+			RootAsset: Asset{
+				PrevWitnesses: []Witness{witness},
+			},
+		},
+	}
+	require.True(t, IsBurnKey(burnKey, splitWitness))
+
+	// Genesis assets can never be burns.
+	require.False(t, IsBurnKey(
+		DeriveBurnKey(ZeroPrevID), Witness{PrevID: &ZeroPrevID},
+	))
+
+	burnAsset := &Asset{
+		ScriptKey:     burnScriptKey,
+		PrevWitnesses: []Witness{witness},
+	}
+	require.True(t, burnAsset.IsBurn())
+	require.False(t, burnAsset.IsUnSpendable())
+
+	burnAsset.ScriptKey = NewScriptKey(pubKey)
+	require.False(t, burnAsset.IsBurn())
+}
+
+// TestTapscriptGroupKey tests that group keys that commit to a tapscript tree
+// can authorize issuance with both a key spend and a script spend witness.
+func TestTapscriptGroupKey(t *testing.T) {
+	t.Parallel()
+
+	internalPriv, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	internalDesc := keychain.KeyDescriptor{
+		PubKey: internalPriv.PubKey(),
+	}
+
+	leafPriv, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	leafDesc := keychain.KeyDescriptor{
+		PubKey: leafPriv.PubKey(),
+	}
+
+	leafScript, err := txscript.NewScriptBuilder().
+		AddData(schnorr.SerializePubKey(leafDesc.PubKey)).
+		AddOp(txscript.OP_CHECKSIG).
+		Script()
+	require.NoError(t, err)
+
+	leaf := txscript.NewBaseTapLeaf(leafScript)
+	tree := txscript.AssembleTaprootScriptTree(leaf)
+	rootHash := tree.RootNode.TapHash()
+	root := rootHash[:]
+
+	gen := Genesis{
+		FirstPrevOut: wire.OutPoint{
+			Hash:  hashBytes1,
+			Index: 1,
+		},
+		Tag:         "tapscript group",
+		MetaHash:    [MetaHashLen]byte{1, 2, 3},
+		OutputIndex: 1,
+		Type:        Normal,
+	}
+	otherGen := gen
+	otherGen.Tag = "other asset"
+
+	// A key spend witness created by the holder of the internal key must
+	// authorize the issuance of this genesis only.
+	keySpendKey, err := DeriveTapscriptGroupKey(
+		NewRawKeyGenesisSigner(internalPriv), internalDesc, root, gen,
+	)
+	require.NoError(t, err)
+	require.True(t, keySpendKey.GroupPubKey.IsEqual(
+		TapscriptGroupPubKey(internalDesc.PubKey, root),
+	))
+	require.True(t, gen.VerifyGroupWitness(keySpendKey))
+	require.False(t, otherGen.VerifyGroupWitness(keySpendKey))
+
+	// A script spend witness for the leaf must also be accepted.
+	groupPubKey := TapscriptGroupPubKey(internalDesc.PubKey, root)
+	virtualTx, prevOut, err := GroupVirtualTx(gen, groupPubKey)
+	require.NoError(t, err)
+
+	leafSig, err := NewRawKeyGenesisSigner(leafPriv).SignGroupVirtualTx(
+		leafDesc, root, &leaf, virtualTx, prevOut,
+	)
+	require.NoError(t, err)
+
+	ctrlBlock := tree.LeafMerkleProofs[0].ToControlBlock(
+		internalDesc.PubKey,
+	)
+	ctrlBlockBytes, err := ctrlBlock.ToBytes()
+	require.NoError(t, err)
+
+	witness := wire.TxWitness{
+		leafSig.Serialize(), leafScript, ctrlBlockBytes,
+	}
+	scriptSpendKey, err := NewTapscriptGroupKey(
+		internalDesc, root, gen, witness,
+	)
+	require.NoError(t, err)
+	require.True(t, scriptSpendKey.IsEqual(&GroupKey{
+		RawKey:      internalDesc,
+		GroupPubKey: *groupPubKey,
+		Witness:     witness,
+	}))
+
+	// The same witness is not valid for a different genesis, and an empty
+	// witness is never valid.
+	_, err = NewTapscriptGroupKey(internalDesc, root, otherGen, witness)
+	require.ErrorIs(t, err, ErrInvalidGroupWitness)
+	_, err = NewTapscriptGroupKey(internalDesc, root, gen, nil)
+	require.ErrorIs(t, err, ErrInvalidGroupWitness)
+
+	// Finally, the group witness must survive an encoding round trip.
+	a := &Asset{
+		Version:   1,
+		Genesis:   gen,
+		Amount:    100,
+		ScriptKey: NewScriptKey(pubKey),
+		GroupKey:  scriptSpendKey,
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, a.Encode(&buf))
+
+	var b Asset
+	require.NoError(t, b.Decode(&buf))
+	require.Equal(t, witness, b.GroupKey.Witness)
+	require.True(t, gen.VerifyGroupWitness(b.GroupKey))
+}
+
+// multiKeyGenSigner is a GenesisSigner that signs with the private key that
+// matches the key descriptor of the request.
+type multiKeyGenSigner struct {
+	keys map[SerializedKey]*btcec.PrivateKey
+}
+
+func (m *multiKeyGenSigner) SignGenesis(keychain.KeyDescriptor, Genesis,
+	*Genesis) (*btcec.PublicKey, *schnorr.Signature, error) {
+
+	return nil, nil, fmt.Errorf("not implemented")
+}
+
+func (m *multiKeyGenSigner) SignGroupVirtualTx(desc keychain.KeyDescriptor,
+	tapscriptRoot []byte, leaf *txscript.TapLeaf, virtualTx *wire.MsgTx,
+	prevOut *wire.TxOut) (*schnorr.Signature, error) {
+
+	privKey, ok := m.keys[ToSerialized(desc.PubKey)]
+	if !ok {
+		return nil, fmt.Errorf("unknown key")
+	}
+
+	return NewRawKeyGenesisSigner(privKey).SignGroupVirtualTx(
+		desc, tapscriptRoot, leaf, virtualTx, prevOut,
+	)
+}
+
+// TestScriptSpendGroupKey tests that a new tranche can be authorized through a
+// 2-of-3 multisig leaf of the group's tapscript tree.
+func TestScriptSpendGroupKey(t *testing.T) {
+	t.Parallel()
+
+	internalDesc := keychain.KeyDescriptor{
+		PubKey: test.RandPubKey(t),
+	}
+
+	signer := &multiKeyGenSigner{
+		keys: make(map[SerializedKey]*btcec.PrivateKey),
+	}
+	issuers := make([]*keychain.KeyDescriptor, 3)
+	for idx := range issuers {
+		privKey := test.RandPrivKey(t)
+		signer.keys[ToSerialized(privKey.PubKey())] = privKey
+		issuers[idx] = &keychain.KeyDescriptor{
+			PubKey: privKey.PubKey(),
+			KeyLocator: keychain.KeyLocator{
+				Family: TaprootAssetsKeyFamily,
+				Index:  uint32(idx),
+			},
+		}
+	}
+
+	multiSigScript, err := txscript.NewScriptBuilder().
+		AddData(schnorr.SerializePubKey(issuers[0].PubKey)).
+		AddOp(txscript.OP_CHECKSIG).
+		AddData(schnorr.SerializePubKey(issuers[1].PubKey)).
+		AddOp(txscript.OP_CHECKSIGADD).
+		AddData(schnorr.SerializePubKey(issuers[2].PubKey)).
+		AddOp(txscript.OP_CHECKSIGADD).
+		AddInt64(2).
+		AddOp(txscript.OP_NUMEQUAL).
+		Script()
+	require.NoError(t, err)
+
+	multiSigLeaf := txscript.NewBaseTapLeaf(multiSigScript)
+	otherLeaf := txscript.NewBaseTapLeaf([]byte{txscript.OP_RETURN})
+	tree := txscript.AssembleTaprootScriptTree(multiSigLeaf, otherLeaf)
+	rootHash := tree.RootNode.TapHash()
+	root := rootHash[:]
+
+	gen := RandGenesis(t, Normal)
+
+	// The signatures are consumed from the top of the stack, so the
+	// signature of the first issuer must be placed last. The second
+	// issuer doesn't sign.
+	spend := &GroupScriptSpend{
+		Leaf:           multiSigLeaf,
+		InclusionProof: tree.LeafMerkleProofs[0].InclusionProof,
+		Signers: []*keychain.KeyDescriptor{
+			issuers[2], nil, issuers[0],
+		},
+	}
+	groupKey, err := DeriveScriptSpendGroupKey(
+		signer, internalDesc, root, spend, gen,
+	)
+	require.NoError(t, err)
+	require.True(t, groupKey.GroupPubKey.IsEqual(
+		TapscriptGroupPubKey(internalDesc.PubKey, root),
+	))
+	require.True(t, gen.VerifyGroupWitness(groupKey))
+
+	// A single signature doesn't satisfy the leaf.
+	spend.Signers = []*keychain.KeyDescriptor{nil, nil, issuers[0]}
+	_, err = DeriveScriptSpendGroupKey(
+		signer, internalDesc, root, spend, gen,
+	)
+	require.ErrorIs(t, err, ErrInvalidGroupWitness)
+
+	// A leaf that isn't part of the tree is rejected.
+	wrongSpend := &GroupScriptSpend{
+		Leaf: multiSigLeaf,
+		Signers: []*keychain.KeyDescriptor{
+			issuers[2], nil, issuers[0],
+		},
+	}
+	_, err = DeriveScriptSpendGroupKey(
+		signer, internalDesc, root, wrongSpend, gen,
+	)
+	require.ErrorIs(t, err, ErrInvalidGroupWitness)
+
+	// Finally, the script spend must survive an encoding round trip.
+	spend.Signers = []*keychain.KeyDescriptor{
+		issuers[2], nil, issuers[0],
+	}
+	var buf bytes.Buffer
+	require.NoError(t, spend.Encode(&buf))
+
+	var decoded GroupScriptSpend
+	require.NoError(t, decoded.Decode(&buf))
+	require.Equal(t, spend, &decoded)
+}
+
+// TestGenChallengeNUMS tests that the challenge NUMS key is only equal to the
+// NUMS key without a challenge, and is bound to the challenge otherwise.
+func TestGenChallengeNUMS(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, NUMSScriptKey, GenChallengeNUMS(nil))
+
+	challenge1 := sha256.Sum256([]byte("challenge 1"))
+	challenge2 := sha256.Sum256([]byte("challenge 2"))
+	key1 := GenChallengeNUMS(&challenge1)
+	key2 := GenChallengeNUMS(&challenge2)
+
+	require.False(t, key1.PubKey.IsEqual(NUMSPubKey))
+	require.False(t, key1.PubKey.IsEqual(key2.PubKey))
+	require.True(
+		t, key1.PubKey.IsEqual(GenChallengeNUMS(&challenge1).PubKey),
+	)
+}
+
+// TestReplaceWitnesses tests that split commitments and witnesses are stripped
+// from an asset without modifying the original asset.
+func TestReplaceWitnesses(t *testing.T) {
+	t.Parallel()
+
+	split := splitAsset(t, false)
+	require.True(t, split.HasSplitCommitmentWitness())
+
+	committed := split.CopyWithoutSplitCommitment()
+	require.False(t, committed.HasSplitCommitmentWitness())
+	require.Equal(t, &ZeroPrevID, committed.PrevWitnesses[0].PrevID)
+	require.True(t, split.HasSplitCommitmentWitness())
+
+	prevID := PrevID{
+		OutPoint:  wire.OutPoint{Index: 1},
+		ID:        split.ID(),
+		ScriptKey: ToSerialized(split.ScriptKey.PubKey),
+	}
+	split.SplitCommitmentRoot = mssmt.NewComputedNode(
+		sha256.Sum256([]byte("root")), split.Amount,
+	)
+	split.ReplaceWitnesses(prevID)
+
+	require.Nil(t, split.SplitCommitmentRoot)
+	require.Equal(t, []Witness{{PrevID: &prevID}}, split.PrevWitnesses)
+	require.False(t, split.HasSplitCommitmentWitness())
+	require.False(t, split.HasGenesisWitness())
+}
+
+// emptySplitProof returns a valid split commitment proof for an empty tree.
+func emptySplitProof() mssmt.Proof {
+	nodes := make([]mssmt.Node, mssmt.MaxTreeLevels)
+	for idx := range nodes {
+		nodes[idx] = mssmt.EmptyTree[mssmt.MaxTreeLevels-idx]
+	}
+
+	return *mssmt.NewProof(nodes)
+}
+
+// splitAsset returns an asset with a split commitment witness. If nested is
+// true, the root asset of the split commitment has a split commitment witness
+// itself.
+func splitAsset(t testing.TB, nested bool) *Asset {
+	rootAsset := RandAsset(t, Normal)
+	if nested {
+		rootAsset = splitAsset(t, false)
+	}
+
+	splitAsset := RandAsset(t, Normal)
+	splitAsset.PrevWitnesses = []Witness{{
+		PrevID: &ZeroPrevID,
+		SplitCommitment: &SplitCommitment{
+			Proof:     emptySplitProof(),
+			RootAsset: *rootAsset,
+		},
+	}}
+
+	return splitAsset
+}
+
+// encodeAsset returns the TLV encoding of the given asset.
+func encodeAsset(t testing.TB, a *Asset) []byte {
+	var b bytes.Buffer
+	require.NoError(t, a.Encode(&b))
+
+	return b.Bytes()
+}
+
+// TestDecodeLimits tests that decoding malicious asset encodings fails before
+// allocating large amounts of memory or recursing without bound.
+func TestDecodeLimits(t *testing.T) {
+	t.Parallel()
+
+	// A split asset decodes fine, but not if the root asset of its split
+	// commitment has a split commitment as well.
+	var a Asset
+	err := a.Decode(bytes.NewReader(encodeAsset(t, splitAsset(t, false))))
+	require.NoError(t, err)
+
+	nested := encodeAsset(t, splitAsset(t, true))
+	err = a.Decode(bytes.NewReader(nested))
+	require.ErrorIs(t, err, ErrNestedSplitCommitment)
+
+	// A leaf that claims to be larger than the limit is rejected before
+	// we attempt to read it.
+	err = LeafDecoder(
+		bytes.NewReader(nil), &a, &[8]byte{}, MaxVarBytesLen+1,
+	)
+	require.ErrorIs(t, err, ErrByteSliceTooLarge)
+
+	// A leaf that claims to be large, but isn't, fails once the input runs
+	// out.
+	err = LeafDecoder(
+		bytes.NewReader([]byte{1, 2, 3}), &a, &[8]byte{},
+		MaxVarBytesLen,
+	)
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	// The same goes for byte slices with a length prefix.
+	var b bytes.Buffer
+	require.NoError(t, tlv.WriteVarInt(&b, MaxVarBytesLen+1, &[8]byte{}))
+	var varBytes []byte
+	err = VarBytesDecoder(&b, &varBytes, &[8]byte{}, 0)
+	require.ErrorIs(t, err, ErrByteSliceTooLarge)
+
+	// And for witness stacks with too many elements.
+	b.Reset()
+	require.NoError(t, tlv.WriteVarInt(
+		&b, MaxWitnessElements+1, &[8]byte{},
+	))
+	var witnesses []Witness
+	err = WitnessDecoder(
+		bytes.NewReader(b.Bytes()), &witnesses, &[8]byte{}, 0,
+	)
+	require.ErrorIs(t, err, ErrTooManyInputs)
+	var txWitness wire.TxWitness
+	err = TxWitnessDecoder(
+		bytes.NewReader(b.Bytes()), &txWitness, &[8]byte{}, 0,
+	)
+	require.ErrorIs(t, err, ErrTooManyInputs)
+}
+
+// generatedTestVectorName is the name of the test vector file that is
+// generated by TestAssetTestVectors.
+const generatedTestVectorName = "asset_tlv_encoding_generated.json"
+
+// validAssetTestCase is a test vector of an asset and its expected TLV
+// encoding.
+type validAssetTestCase struct {
+	Asset    *Asset `json:"asset"`
+	Expected string `json:"expected"`
+	Comment  string `json:"comment"`
+}
+
+// errorAssetTestCase is a test vector of a TLV encoded asset that must be
+// rejected when decoding.
+type errorAssetTestCase struct {
+	Encoded string `json:"encoded"`
+	Error   string `json:"error"`
+	Comment string `json:"comment"`
+}
+
+// assetTestVectors are the test vectors for the TLV encoding of assets.
+type assetTestVectors = test.TestVectors[
+	validAssetTestCase, errorAssetTestCase,
+]
+
+// TestAssetTestVectors generates the asset encoding test vectors and then
+// asserts that all test vector files in the testdata directory are valid.
+func TestAssetTestVectors(t *testing.T) {
+	t.Parallel()
+
+	genesis := Genesis{
+		FirstPrevOut: wire.OutPoint{
+			Hash:  hashBytes2,
+			Index: 3,
+		},
+		Tag:         "vector",
+		MetaHash:    [MetaHashLen]byte{4, 5, 6},
+		OutputIndex: 2,
+		Type:        Normal,
+	}
+	genesisAsset, err := New(genesis, 5000, 0, 0, NewScriptKey(pubKey), nil)
+	require.NoError(t, err)
+
+	split := genesisAsset.Copy()
+	split.Amount = 1000
+	split.PrevWitnesses = []Witness{{
+		PrevID: &ZeroPrevID,
+		SplitCommitment: &SplitCommitment{
+			Proof:     emptySplitProof(),
+			RootAsset: *jsonTestAsset(),
+		},
+	}}
+
+	edition := jsonTestAsset()
+	edition.NonDivisible = true
+	edition.Edition = 3
+	edition.GroupKey.Witness = wire.TxWitness{hashBytes1[:]}
+
+	nested := split.Copy()
+	nested.PrevWitnesses[0].SplitCommitment.RootAsset = *split.Copy()
+
+	vectors := &assetTestVectors{
+		ValidTestCases: []*validAssetTestCase{{
+			Asset:   genesisAsset,
+			Comment: "genesis asset",
+		}, {
+			Asset:   jsonTestAsset(),
+			Comment: "transfer with group key and split root",
+		}, {
+			Asset:   split,
+			Comment: "split asset",
+		}, {
+			Asset:   edition,
+			Comment: "collectible edition with group witness",
+		}},
+	}
+	for _, tc := range vectors.ValidTestCases {
+		tc.Expected = hex.EncodeToString(encodeAsset(t, tc.Asset))
+	}
+
+	encodedGenesis := vectors.ValidTestCases[0].Expected
+	vectors.ErrorTestCases = []*errorAssetTestCase{{
+		Encoded: hex.EncodeToString(encodeAsset(t, nested)),
+		Error:   ErrNestedSplitCommitment.Error(),
+		Comment: "nested split commitment",
+	}, {
+		Encoded: encodedGenesis[:len(encodedGenesis)-2],
+		Error:   io.ErrUnexpectedEOF.Error(),
+		Comment: "truncated asset",
+	}}
+
+	test.WriteTestVectors(
+		t, filepath.Join("testdata", generatedTestVectorName),
+		vectors,
+	)
+
+	vectorFiles := test.TestVectorFiles(
+		t, "testdata", "asset_tlv_encoding_",
+	)
+	for _, fileName := range vectorFiles {
+		var fileVectors assetTestVectors
+		test.ParseTestVectors(t, fileName, &fileVectors)
+
+		t.Run(fileName, func(t *testing.T) {
+			runAssetTestVectors(t, &fileVectors)
+		})
+	}
+}
+
+// runAssetTestVectors asserts that the given asset test vectors are valid.
+func runAssetTestVectors(t *testing.T, vectors *assetTestVectors) {
+	for _, tc := range vectors.ValidTestCases {
+		encoded := hex.EncodeToString(encodeAsset(t, tc.Asset))
+		require.Equal(t, tc.Expected, encoded, tc.Comment)
+
+		encodedBytes, err := hex.DecodeString(tc.Expected)
+		require.NoError(t, err, tc.Comment)
+
+		var decoded Asset
+		err = decoded.Decode(bytes.NewReader(encodedBytes))
+		require.NoError(t, err, tc.Comment)
+
+		expectedJSON, err := json.Marshal(tc.Asset)
+		require.NoError(t, err, tc.Comment)
+		decodedJSON, err := json.Marshal(&decoded)
+		require.NoError(t, err, tc.Comment)
+		require.JSONEq(
+			t, string(expectedJSON), string(decodedJSON),
+			tc.Comment,
+		)
+	}
+
+	for _, tc := range vectors.ErrorTestCases {
+		encodedBytes, err := hex.DecodeString(tc.Encoded)
+		require.NoError(t, err, tc.Comment)
+
+		var decoded Asset
+		err = decoded.Decode(bytes.NewReader(encodedBytes))
+		require.ErrorContains(t, err, tc.Error, tc.Comment)
+	}
+}
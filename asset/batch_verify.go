@@ -0,0 +1,272 @@
+package asset
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"math/bits"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+)
+
+var (
+	// ErrInvalidGroupSig is returned when a group key genesis signature in
+	// a batch doesn't verify.
+	ErrInvalidGroupSig = fmt.Errorf("invalid group key genesis signature")
+)
+
+// GroupSigBatch collects the group key genesis signatures (or group
+// witnesses) of a set of assets so they can be verified at once. Plain
+// Schnorr signatures are verified using BIP-0340 batch verification, which is
+// considerably faster than verifying each signature on its own when ingesting
+// many grouped assets. The zero value is an empty batch ready for use.
+type GroupSigBatch struct {
+	gens      []Genesis
+	groupKeys []*GroupKey
+}
+
+// Add adds the group key of the asset with the given genesis to the batch.
+func (b *GroupSigBatch) Add(gen Genesis, groupKey *GroupKey) {
+	b.gens = append(b.gens, gen)
+	b.groupKeys = append(b.groupKeys, groupKey)
+}
+
+// Len returns the number of group keys in the batch.
+func (b *GroupSigBatch) Len() int {
+	return len(b.gens)
+}
+
+// Verify verifies all group key genesis signatures and group witnesses in the
+// batch. If the batch is invalid, the returned error identifies the first
+// asset ID with an invalid signature or witness.
+func (b *GroupSigBatch) Verify() error {
+	var (
+		digests [][sha256.Size]byte
+		sigs    []*schnorr.Signature
+		pubKeys []*btcec.PublicKey
+		sigIdx  []int
+	)
+	for idx, gen := range b.gens {
+		groupKey := b.groupKeys[idx]
+
+		// Group witnesses need to be executed by the script engine, so
+		// they can't be part of the signature batch.
+		if len(groupKey.Witness) != 0 {
+			if !gen.VerifyGroupWitness(groupKey) {
+				return fmt.Errorf("%w: asset_id=%v",
+					ErrInvalidGroupWitness, gen.ID())
+			}
+
+			continue
+		}
+
+		id := gen.ID()
+		digests = append(digests, sha256.Sum256(id[:]))
+		sigs = append(sigs, &groupKey.Sig)
+		pubKeys = append(pubKeys, &groupKey.GroupPubKey)
+		sigIdx = append(sigIdx, idx)
+	}
+
+	if batchVerifySchnorr(digests, sigs, pubKeys) {
+		return nil
+	}
+
+	// The batch failed, so we verify each signature on its own to find
+	// the culprit.
+	for i, idx := range sigIdx {
+		if !sigs[i].Verify(digests[i][:], pubKeys[i]) {
+			return fmt.Errorf("%w: asset_id=%v", ErrInvalidGroupSig,
+				b.gens[idx].ID())
+		}
+	}
+
+	// This should never happen, as batch verification is only expected
+	// to fail if at least one of the signatures is invalid.
+	return ErrInvalidGroupSig
+}
+
+// batchVerifySchnorr verifies the given BIP-0340 signatures over the given
+// message digests at once. For random scalars a_i (with a_1 = 1) the batch is
+// valid if:
+//
+//	(sum a_i*s_i)*G == sum a_i*R_i + sum (a_i*e_i)*P_i
+//
+// The right hand side is computed with a single multi-scalar multiplication
+// that also includes the negated left hand side, so the batch is valid if the
+// result is the point at infinity.
+func batchVerifySchnorr(digests [][sha256.Size]byte, sigs []*schnorr.Signature,
+	pubKeys []*btcec.PublicKey) bool {
+
+	switch len(sigs) {
+	case 0:
+		return true
+
+	// There's nothing to gain from batching a single signature.
+	case 1:
+		return sigs[0].Verify(digests[0][:], pubKeys[0])
+	}
+
+	var (
+		numTerms = 2*len(sigs) + 1
+		scalars  = make([]btcec.ModNScalar, numTerms)
+		points   = make([]btcec.JacobianPoint, numTerms)
+		sumS     btcec.ModNScalar
+	)
+	for i, sig := range sigs {
+		sigBytes := sig.Serialize()
+		rBytes, sBytes := sigBytes[:32], sigBytes[32:]
+
+		// Both the nonce point R and the public key P are lifted to the
+		// point with an even y coordinate, as mandated by BIP-0340.
+		r, err := schnorr.ParsePubKey(rBytes)
+		if err != nil {
+			return false
+		}
+		pubKeyBytes := schnorr.SerializePubKey(pubKeys[i])
+		pubKey, err := schnorr.ParsePubKey(pubKeyBytes)
+		if err != nil {
+			return false
+		}
+
+		var s btcec.ModNScalar
+		if overflow := s.SetByteSlice(sBytes); overflow {
+			return false
+		}
+
+		// e = int(hash_BIP0340/challenge(r || P || m)) mod n.
+		challenge := chainhash.TaggedHash(
+			chainhash.TagBIP0340Challenge, rBytes, pubKeyBytes,
+			digests[i][:],
+		)
+		var e btcec.ModNScalar
+		e.SetByteSlice(challenge[:])
+
+		// The first signature doesn't need to be randomized, all
+		// others are multiplied with a random scalar so an attacker
+		// can't create invalid signatures that cancel each other out.
+		var a btcec.ModNScalar
+		a.SetInt(1)
+		if i > 0 {
+			var randBytes [32]byte
+			if _, err := rand.Read(randBytes[:]); err != nil {
+				return false
+			}
+			a.SetBytes(&randBytes)
+		}
+
+		sumS.Add(s.Mul(&a))
+
+		scalars[2*i+1] = a
+		r.AsJacobian(&points[2*i+1])
+
+		scalars[2*i+2] = *e.Mul(&a)
+		pubKey.AsJacobian(&points[2*i+2])
+	}
+
+	scalars[0] = *sumS.Negate()
+	btcec.GeneratorJacobian(&points[0])
+
+	var result btcec.JacobianPoint
+	multiScalarMult(scalars, points, &result)
+
+	return isInfinity(&result)
+}
+
+// multiScalarMult computes sum k_i*P_i for the given scalars and points using
+// Pippenger's bucket method, which requires far fewer point additions than
+// computing each product on its own once more than a handful of points are
+// involved.
+func multiScalarMult(scalars []btcec.ModNScalar, points []btcec.JacobianPoint,
+	result *btcec.JacobianPoint) {
+
+	// The window size grows logarithmically with the number of points, as
+	// the cost of summing up the buckets grows exponentially with it.
+	window := bits.Len(uint(len(points))) - 2
+	switch {
+	case window < 2:
+		window = 2
+
+	case window > 16:
+		window = 16
+	}
+
+	scalarBytes := make([][32]byte, len(scalars))
+	for i := range scalars {
+		scalarBytes[i] = scalars[i].Bytes()
+	}
+
+	var (
+		numWindows = (256 + window - 1) / window
+		buckets    = make([]btcec.JacobianPoint, 1<<window-1)
+		tmp        btcec.JacobianPoint
+	)
+
+	*result = btcec.JacobianPoint{}
+	for w := numWindows - 1; w >= 0; w-- {
+		// Make room for the next window by shifting the accumulated
+		// result.
+		for i := 0; i < window; i++ {
+			btcec.DoubleNonConst(result, &tmp)
+			result.Set(&tmp)
+		}
+
+		for i := range buckets {
+			buckets[i] = btcec.JacobianPoint{}
+		}
+
+		// Sort each point into the bucket of its scalar's digit for
+		// the current window.
+		for i := range points {
+			digit := scalarWindow(&scalarBytes[i], w*window, window)
+			if digit == 0 {
+				continue
+			}
+
+			btcec.AddNonConst(&buckets[digit-1], &points[i], &tmp)
+			buckets[digit-1].Set(&tmp)
+		}
+
+		// Sum up the buckets weighted by their digit by keeping a
+		// running sum from the highest bucket downwards.
+		var running, windowSum btcec.JacobianPoint
+		for i := len(buckets) - 1; i >= 0; i-- {
+			btcec.AddNonConst(&running, &buckets[i], &tmp)
+			running.Set(&tmp)
+
+			btcec.AddNonConst(&windowSum, &running, &tmp)
+			windowSum.Set(&tmp)
+		}
+
+		btcec.AddNonConst(result, &windowSum, &tmp)
+		result.Set(&tmp)
+	}
+}
+
+// scalarWindow returns the value of the given number of bits of the
+// big-endian scalar, starting at the given bit offset counted from the least
+// significant bit.
+func scalarWindow(scalar *[32]byte, offset, numBits int) int {
+	var digit int
+	for i := numBits - 1; i >= 0; i-- {
+		bit := offset + i
+		if bit >= 256 {
+			continue
+		}
+
+		byteIdx := 31 - bit/8
+		digit <<= 1
+		digit |= int(scalar[byteIdx]>>(bit%8)) & 1
+	}
+
+	return digit
+}
+
+// isInfinity returns true if the given point is the point at infinity.
+func isInfinity(p *btcec.JacobianPoint) bool {
+	z := p.Z
+	z.Normalize()
+
+	return z.IsZero()
+}
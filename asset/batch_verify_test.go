@@ -0,0 +1,168 @@
+package asset
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/lightninglabs/taproot-assets/internal/test"
+	"github.com/stretchr/testify/require"
+)
+
+// randGroupSigBatch creates a batch of the given size with valid group keys.
+// Every other genesis is re-issued into the group of the previous one, so the
+// batch contains both distinct and repeated group keys.
+func randGroupSigBatch(t testing.TB, size int) *GroupSigBatch {
+	var (
+		batch      GroupSigBatch
+		initialGen Genesis
+		groupPriv  *btcec.PrivateKey
+	)
+	for i := 0; i < size; i++ {
+		gen := RandGenesis(t, Normal)
+
+		var currentGen *Genesis
+		if i%2 == 0 {
+			initialGen = gen
+			groupPriv = test.RandPrivKey(t)
+		} else {
+			currentGen = &gen
+		}
+
+		groupKey, err := DeriveGroupKey(
+			NewRawKeyGenesisSigner(groupPriv),
+			test.PubToKeyDesc(groupPriv.PubKey()), initialGen,
+			currentGen,
+		)
+		require.NoError(t, err)
+
+		batch.Add(gen, groupKey)
+	}
+
+	return &batch
+}
+
+// TestGroupSigBatch tests that valid group key genesis signatures are
+// accepted by the batch verifier and that any invalid signature or witness is
+// detected and reported.
+func TestGroupSigBatch(t *testing.T) {
+	t.Parallel()
+
+	for _, size := range []int{0, 1, 2, 3, 17, 100} {
+		batch := randGroupSigBatch(t, size)
+		require.Equal(t, size, batch.Len())
+		require.NoError(t, batch.Verify(), "batch size %d", size)
+	}
+
+	// Swapping the signatures of two group keys must invalidate the batch
+	// and the first of the two assets must be reported.
+	batch := randGroupSigBatch(t, 20)
+	badKey5 := *batch.groupKeys[5]
+	badKey12 := *batch.groupKeys[12]
+	badKey5.Sig, badKey12.Sig = badKey12.Sig, badKey5.Sig
+	batch.groupKeys[5], batch.groupKeys[12] = &badKey5, &badKey12
+
+	err := batch.Verify()
+	require.ErrorIs(t, err, ErrInvalidGroupSig)
+	require.ErrorContains(t, err, batch.gens[5].ID().String())
+
+	// A signature that belongs to a different asset ID must also be
+	// detected, even if the group key is correct.
+	batch = randGroupSigBatch(t, 20)
+	batch.gens[7] = RandGenesis(t, Normal)
+	require.ErrorIs(t, batch.Verify(), ErrInvalidGroupSig)
+
+	// Group keys with a group witness are verified along with the
+	// signatures in the batch.
+	internalPriv := test.RandPrivKey(t)
+	root := sha256.Sum256([]byte("tapscript root"))
+	gen := RandGenesis(t, Normal)
+	witnessKey, err := DeriveTapscriptGroupKey(
+		NewRawKeyGenesisSigner(internalPriv),
+		test.PubToKeyDesc(internalPriv.PubKey()), root[:], gen,
+	)
+	require.NoError(t, err)
+
+	batch = randGroupSigBatch(t, 10)
+	batch.Add(gen, witnessKey)
+	require.NoError(t, batch.Verify())
+
+	batch.Add(RandGenesis(t, Normal), witnessKey)
+	require.ErrorIs(t, batch.Verify(), ErrInvalidGroupWitness)
+}
+
+// TestBatchVerifySchnorr tests the BIP-0340 batch verification against the
+// result of verifying each signature on its own.
+func TestBatchVerifySchnorr(t *testing.T) {
+	t.Parallel()
+
+	const numSigs = 50
+
+	var (
+		digests = make([][sha256.Size]byte, numSigs)
+		sigs    = make([]*schnorr.Signature, numSigs)
+		pubKeys = make([]*btcec.PublicKey, numSigs)
+	)
+	for i := 0; i < numSigs; i++ {
+		privKey := test.RandPrivKey(t)
+		digests[i] = sha256.Sum256(test.RandBytes(32))
+
+		sig, err := schnorr.Sign(privKey, digests[i][:])
+		require.NoError(t, err)
+
+		sigs[i] = sig
+		pubKeys[i] = privKey.PubKey()
+	}
+
+	require.True(t, batchVerifySchnorr(digests, sigs, pubKeys))
+	require.True(t, batchVerifySchnorr(digests[:1], sigs[:1], pubKeys[:1]))
+	require.True(t, batchVerifySchnorr(nil, nil, nil))
+
+	// Negating the public keys must not make a difference, as only the
+	// x coordinate is committed to.
+	negated := make([]*btcec.PublicKey, numSigs)
+	for i, pubKey := range pubKeys {
+		var p btcec.JacobianPoint
+		pubKey.AsJacobian(&p)
+		p.Y.Negate(1).Normalize()
+		negated[i] = btcec.NewPublicKey(&p.X, &p.Y)
+	}
+	require.True(t, batchVerifySchnorr(digests, sigs, negated))
+
+	// A single wrong message must be detected.
+	badDigests := make([][sha256.Size]byte, numSigs)
+	copy(badDigests, digests)
+	badDigests[numSigs-1][0] ^= 1
+	require.False(t, batchVerifySchnorr(badDigests, sigs, pubKeys))
+
+	// So must a single wrong public key.
+	badPubKeys := make([]*btcec.PublicKey, numSigs)
+	copy(badPubKeys, pubKeys)
+	badPubKeys[0] = test.RandPubKey(t)
+	require.False(t, batchVerifySchnorr(digests, sigs, badPubKeys))
+}
+
+// BenchmarkGroupSigVerification compares verifying a large number of group
+// key genesis signatures in a batch with verifying them one by one.
+func BenchmarkGroupSigVerification(b *testing.B) {
+	batch := randGroupSigBatch(b, 1000)
+
+	b.Run("batch", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			require.NoError(b, batch.Verify())
+		}
+	})
+
+	b.Run("individual", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for idx, gen := range batch.gens {
+				require.True(
+					b, gen.VerifyGroupWitness(
+						batch.groupKeys[idx],
+					),
+				)
+			}
+		}
+	})
+}
@@ -0,0 +1,96 @@
+package asset
+
+import (
+	"bytes"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// DeriveBurnKey derives a provably un-spendable but unique key by tweaking the
+// public NUMS key with a tap tweak:
+//
+//	burnTweak = h_tapTweak(NUMSKey || outPoint || assetID || scriptKey)
+//	burnKey = NUMSKey + burnTweak*G
+//
+// The firstPrevID must be the PrevID from the first input that is being spent
+// by the virtual transaction that contains the burn.
+func DeriveBurnKey(firstPrevID PrevID) *btcec.PublicKey {
+	var b bytes.Buffer
+
+	// The data we use in the tap tweak of the NUMS point is the serialized
+	// PrevID, which consists of an outpoint, the asset ID and the script
+	// key. Because these three values combined are larger than 32 bytes, we
+	// explicitly make sure the virtual transaction uses the "tapscript
+	// tree" variant of the tap tweak.
+	_ = wire.WriteOutPoint(&b, 0, 0, &firstPrevID.OutPoint)
+	_, _ = b.Write(firstPrevID.ID[:])
+	_, _ = b.Write(firstPrevID.ScriptKey.SchnorrSerialized())
+
+	// Since we'll never query lnd for a burn key, it doesn't matter if we
+	// lose the parity information here. And this will only ever be
+	// serialized on chain in a 32-bit representation as well.
+	key := txscript.ComputeTaprootOutputKey(NUMSPubKey, b.Bytes())
+	key, _ = schnorr.ParsePubKey(schnorr.SerializePubKey(key))
+
+	return key
+}
+
+// NewBurnScriptKey returns the canonical script key that burns an asset that
+// is being spent by a virtual transaction with the given first input.
+func NewBurnScriptKey(firstPrevID PrevID) ScriptKey {
+	return NewScriptKey(DeriveBurnKey(firstPrevID))
+}
+
+// IsBurnKey returns true if the given script key is a valid burn key for the
+// given witness. For split assets, the burn key is derived from the first
+// input of the root asset the split was created from.
+func IsBurnKey(scriptKey *btcec.PublicKey, witness Witness) bool {
+	if scriptKey == nil {
+		return false
+	}
+
+	// A genesis asset can't be burned, as the zero prev ID doesn't
+	// reference any asset that could be destroyed.
+	prevID, ok := SpentPrevID(witness)
+	if !ok || prevID == ZeroPrevID {
+		return false
+	}
+
+	return scriptKey.IsEqual(DeriveBurnKey(prevID))
+}
+
+// IsBurn returns true if an asset uses a burn script key that was derived
+// from its first previous input, which makes it provably un-spendable.
+func (a *Asset) IsBurn() bool {
+	// If the script key is nil, then we can't say if this is a burn or
+	// not.
+	if a.ScriptKey.PubKey == nil || len(a.PrevWitnesses) == 0 {
+		return false
+	}
+
+	return IsBurnKey(a.ScriptKey.PubKey, a.PrevWitnesses[0])
+}
+
+// SpentPrevID returns the previous input the given witness spends. For split
+// assets, this is the first input of the root asset the split was created
+// from. False is returned if the witness doesn't reference any input.
+func SpentPrevID(witness Witness) (PrevID, bool) {
+	switch {
+	case witness.SplitCommitment != nil:
+		rootWitnesses := witness.SplitCommitment.RootAsset.PrevWitnesses
+		if len(rootWitnesses) == 0 || rootWitnesses[0].PrevID == nil {
+			return PrevID{}, false
+		}
+
+		return *rootWitnesses[0].PrevID, true
+
+	case witness.PrevID != nil:
+		return *witness.PrevID, true
+
+	default:
+		return PrevID{}, false
+	}
+}
@@ -24,8 +24,51 @@ var (
 	// ErrByteSliceTooLarge is returned when an encoded byte slice is too
 	// large.
 	ErrByteSliceTooLarge = errors.New("bytes: too large")
+
+	// ErrMissingKey is returned when a public key that should be encoded
+	// is missing.
+	ErrMissingKey = errors.New("pubkey: missing key")
+
+	// ErrNestedSplitCommitment is returned when the root asset of a split
+	// commitment itself has a split commitment witness. A split root is
+	// always the result of a regular state transition, so this is never
+	// valid and would otherwise allow unbounded nesting when decoding.
+	ErrNestedSplitCommitment = errors.New("split commitment: nested " +
+		"split commitment")
+)
+
+const (
+	// MaxVarBytesLen is the maximum length of any variable length byte
+	// slice we decode, including the encoding of an asset leaf.
+	MaxVarBytesLen = (2 << 24) - 1
+
+	// MaxWitnessElements is the maximum number of elements we accept in a
+	// decoded witness stack and the maximum number of witnesses we accept
+	// for an asset. We're being generous here, as for the bitcoin VM the
+	// true stack limit is much smaller.
+	MaxWitnessElements = math.MaxUint16
 )
 
+// readVarBytes reads exactly l bytes from the reader, failing if l exceeds
+// MaxVarBytesLen. The returned slice is grown as the bytes are read, so a
+// malicious length prefix can't make us allocate more memory than the reader
+// actually holds.
+func readVarBytes(r io.Reader, l uint64) ([]byte, error) {
+	if l > MaxVarBytesLen {
+		return nil, fmt.Errorf("%w: %v", ErrByteSliceTooLarge, l)
+	}
+
+	var b bytes.Buffer
+	if _, err := io.CopyN(&b, r, int64(l)); err != nil {
+		if err == io.EOF {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
 func VarIntEncoder(w io.Writer, val any, buf *[8]byte) error {
 	if t, ok := val.(*uint64); ok {
 		return tlv.WriteVarInt(w, *t, buf)
@@ -64,19 +107,29 @@ func VarBytesDecoder(r io.Reader, val any, buf *[8]byte, _ uint64) error {
 
 		// We'll limit all decoded byte slices to prevent memory blow
 		// ups or panics.
-		if bytesLen > (2<<24)-1 {
-			return fmt.Errorf("%w: %v", ErrByteSliceTooLarge,
-				bytesLen)
+		bytes, err := readVarBytes(r, bytesLen)
+		if err != nil {
+			return err
 		}
+		*typ = bytes
+		return nil
+	}
+	return tlv.NewTypeForEncodingErr(val, "[]byte")
+}
 
-		var bytes []byte
-		if err := tlv.DVarBytes(r, &bytes, buf, bytesLen); err != nil {
+// InlineVarBytesDecoder decodes a byte slice that takes up the full length of
+// a TLV record, like tlv.DVarBytes. Unlike tlv.DVarBytes, the length is capped
+// at MaxVarBytesLen and the slice is only grown as the bytes are read.
+func InlineVarBytesDecoder(r io.Reader, val any, _ *[8]byte, l uint64) error {
+	if typ, ok := val.(*[]byte); ok {
+		bytes, err := readVarBytes(r, l)
+		if err != nil {
 			return err
 		}
 		*typ = bytes
 		return nil
 	}
-	return tlv.NewTypeForEncodingErr(val, "[]byte")
+	return tlv.NewTypeForDecodingErr(val, "[]byte", l, l)
 }
 
 func OutPointEncoder(w io.Writer, val any, buf *[8]byte) error {
@@ -108,6 +161,12 @@ func OutPointDecoder(r io.Reader, val any, buf *[8]byte, _ uint64) error {
 
 func CompressedPubKeyEncoder(w io.Writer, val any, buf *[8]byte) error {
 	if t, ok := val.(**btcec.PublicKey); ok {
+		// A decoded asset might be missing its keys, which we don't
+		// want to panic on when encoding it again.
+		if *t == nil {
+			return ErrMissingKey
+		}
+
 		var keyBytes [btcec.PubKeyBytesLenCompressed]byte
 		copy(keyBytes[:], (*t).SerializeCompressed())
 		return tlv.EBytes33(w, &keyBytes, buf)
@@ -188,6 +247,38 @@ func VersionDecoder(r io.Reader, val any, buf *[8]byte, l uint64) error {
 	return tlv.NewTypeForDecodingErr(val, "Version", l, 1)
 }
 
+func BoolEncoder(w io.Writer, val any, buf *[8]byte) error {
+	if t, ok := val.(*bool); ok {
+		var b uint8
+		if *t {
+			b = 1
+		}
+		return tlv.EUint8T(w, b, buf)
+	}
+	return tlv.NewTypeForEncodingErr(val, "bool")
+}
+
+func BoolDecoder(r io.Reader, val any, buf *[8]byte, l uint64) error {
+	if typ, ok := val.(*bool); ok {
+		var t uint8
+		if err := tlv.DUint8(r, &t, buf, l); err != nil {
+			return err
+		}
+
+		// We only allow the canonical encoding of a boolean value.
+		switch t {
+		case 0:
+			*typ = false
+		case 1:
+			*typ = true
+		default:
+			return fmt.Errorf("invalid bool value: %v", t)
+		}
+		return nil
+	}
+	return tlv.NewTypeForDecodingErr(val, "bool", l, 1)
+}
+
 func IDEncoder(w io.Writer, val any, buf *[8]byte) error {
 	if t, ok := val.(*ID); ok {
 		id := [sha256.Size]byte(*t)
@@ -370,13 +461,14 @@ func TxWitnessDecoder(r io.Reader, val any, buf *[8]byte, _ uint64) error {
 		}
 
 		// We won't accept anything beyond the set of max witness
-		// elements. We're being generous here, as for the bitcoin VM
-		// the true stack limit is much smaller.
-		if numItems > math.MaxUint16 {
+		// elements.
+		if numItems > MaxWitnessElements {
 			return ErrTooManyInputs
 		}
 
-		witness := make(wire.TxWitness, 0, numItems)
+		// We don't pre-allocate the witness stack, as every element
+		// needs at least one byte of input anyway.
+		var witness wire.TxWitness
 		for i := uint64(0); i < numItems; i++ {
 			var item []byte
 			if err := VarBytesDecoder(r, &item, buf, 0); err != nil {
@@ -413,36 +505,57 @@ func WitnessEncoder(w io.Writer, val any, buf *[8]byte) error {
 
 func WitnessDecoder(r io.Reader, val any, buf *[8]byte, _ uint64) error {
 	if typ, ok := val.(*[]Witness); ok {
-		numItems, err := tlv.ReadVarInt(r, buf)
+		return decodeWitnesses(r, typ, buf, (*Witness).Decode)
+	}
+	return tlv.NewTypeForEncodingErr(val, "[]Witness")
+}
+
+// splitRootWitnessDecoder decodes the witnesses of the root asset of a split
+// commitment, which can't carry split commitments themselves.
+func splitRootWitnessDecoder(r io.Reader, val any, buf *[8]byte,
+	_ uint64) error {
+
+	if typ, ok := val.(*[]Witness); ok {
+		return decodeWitnesses(r, typ, buf, (*Witness).decodeSplitRoot)
+	}
+	return tlv.NewTypeForEncodingErr(val, "[]Witness")
+}
+
+// decodeWitnesses decodes a list of asset witnesses, using the given function
+// to decode the TLV stream of each witness.
+func decodeWitnesses(r io.Reader, witnesses *[]Witness, buf *[8]byte,
+	decode func(*Witness, io.Reader) error) error {
+
+	numItems, err := tlv.ReadVarInt(r, buf)
+	if err != nil {
+		return err
+	}
+
+	// We use a varint, but will practically limit the number of
+	// witnesses to a sane number.
+	//
+	// TODO(roasbeef): just use a uint8 here?
+	if numItems > MaxWitnessElements {
+		return fmt.Errorf("%w: %v", ErrTooManyInputs, numItems)
+	}
+
+	// As with the witness stack, we don't pre-allocate the list based on
+	// the untrusted number of items.
+	*witnesses = nil
+	for i := uint64(0); i < numItems; i++ {
+		var streamBytes []byte
+		err := VarBytesDecoder(r, &streamBytes, buf, 0)
 		if err != nil {
 			return err
 		}
-
-		// We use a varint, but will practically limit the number of
-		// witnesses to a sane number.
-		//
-		// TODO(roasbeef): just use a uint8 here?
-		if numItems > math.MaxUint16 {
-			return fmt.Errorf("%w: %v", ErrTooManyInputs, numItems)
-		}
-
-		*typ = make([]Witness, 0, numItems)
-		for i := uint64(0); i < numItems; i++ {
-			var streamBytes []byte
-			err := VarBytesDecoder(r, &streamBytes, buf, 0)
-			if err != nil {
-				return err
-			}
-			var assetWitness Witness
-			err = assetWitness.Decode(bytes.NewReader(streamBytes))
-			if err != nil {
-				return err
-			}
-			*typ = append(*typ, assetWitness)
+		var assetWitness Witness
+		err = decode(&assetWitness, bytes.NewReader(streamBytes))
+		if err != nil {
+			return err
 		}
-		return nil
+		*witnesses = append(*witnesses, assetWitness)
 	}
-	return tlv.NewTypeForEncodingErr(val, "[]Witness")
+	return nil
 }
 
 func SplitCommitmentEncoder(w io.Writer, val any, buf *[8]byte) error {
@@ -474,7 +587,7 @@ func SplitCommitmentDecoder(r io.Reader, val any, buf *[8]byte, l uint64) error
 		}
 
 		var proof mssmt.CompressedProof
-		if err := proof.Decode(bytes.NewReader(proofBytes)); err != nil {
+		if err := proof.DecodeBytes(proofBytes); err != nil {
 			return err
 		}
 
@@ -485,7 +598,7 @@ func SplitCommitmentDecoder(r io.Reader, val any, buf *[8]byte, l uint64) error
 		}
 
 		var rootAsset Asset
-		err = rootAsset.Decode(bytes.NewReader(rootAssetBytes))
+		err = rootAsset.decodeSplitRoot(bytes.NewReader(rootAssetBytes))
 		if err != nil {
 			return err
 		}
@@ -505,6 +618,15 @@ func SplitCommitmentDecoder(r io.Reader, val any, buf *[8]byte, l uint64) error
 	return tlv.NewTypeForDecodingErr(val, "*SplitCommitment", l, 40)
 }
 
+// nestedSplitCommitmentDecoder is used in place of the SplitCommitmentDecoder
+// for the witnesses of a split root asset, which can't have a split
+// commitment.
+func nestedSplitCommitmentDecoder(_ io.Reader, _ any, _ *[8]byte,
+	_ uint64) error {
+
+	return ErrNestedSplitCommitment
+}
+
 func SplitCommitmentRootEncoder(w io.Writer, val any, buf *[8]byte) error {
 	if t, ok := val.(*mssmt.Node); ok {
 		key := [32]byte((*t).NodeHash())
@@ -558,12 +680,23 @@ func GroupKeyEncoder(w io.Writer, val any, buf *[8]byte) error {
 			return err
 		}
 		sig := (*t).Sig
-		return SchnorrSignatureEncoder(w, &sig, buf)
+		if err := SchnorrSignatureEncoder(w, &sig, buf); err != nil {
+			return err
+		}
+
+		// The witness is only present for group keys that commit to a
+		// tapscript tree. We omit it otherwise to stay compatible with
+		// the original fixed size encoding.
+		witness := (*t).Witness
+		if len(witness) == 0 {
+			return nil
+		}
+		return TxWitnessEncoder(w, &witness, buf)
 	}
 	return tlv.NewTypeForEncodingErr(val, "*GroupKey")
 }
 
-func GroupKeyDecoder(r io.Reader, val any, buf *[8]byte, _ uint64) error {
+func GroupKeyDecoder(r io.Reader, val any, buf *[8]byte, l uint64) error {
 	if typ, ok := val.(**GroupKey); ok {
 		var (
 			groupKey    GroupKey
@@ -582,6 +715,19 @@ func GroupKeyDecoder(r io.Reader, val any, buf *[8]byte, _ uint64) error {
 			return err
 		}
 		groupKey.GroupPubKey = *groupPubKey
+
+		// Any remaining bytes belong to the group witness.
+		const baseLen = btcec.PubKeyBytesLenCompressed +
+			schnorr.SignatureSize
+		if l > baseLen {
+			err = TxWitnessDecoder(
+				r, &groupKey.Witness, buf, l-baseLen,
+			)
+			if err != nil {
+				return err
+			}
+		}
+
 		*typ = &groupKey
 		return nil
 	}
@@ -597,8 +743,8 @@ func LeafEncoder(w io.Writer, val any, buf *[8]byte) error {
 
 func LeafDecoder(r io.Reader, val any, buf *[8]byte, l uint64) error {
 	if typ, ok := val.(*Asset); ok {
-		var assetBytes []byte
-		if err := tlv.DVarBytes(r, &assetBytes, buf, l); err != nil {
+		assetBytes, err := readVarBytes(r, l)
+		if err != nil {
 			return err
 		}
 		var asset Asset
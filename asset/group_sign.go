@@ -0,0 +1,478 @@
+package asset
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+// GroupSignMethod denotes which of the GenesisSigner methods a group sign
+// request is for.
+type GroupSignMethod uint8
+
+const (
+	// GroupSignGenesis is a request to tweak a group internal key with a
+	// genesis and sign an asset ID with the tweaked key.
+	GroupSignGenesis GroupSignMethod = 0
+
+	// GroupSignVirtualTx is a request to sign a group virtual transaction
+	// with either a key spend or a script spend.
+	GroupSignVirtualTx GroupSignMethod = 1
+)
+
+// String returns a human-readable description of the sign method.
+func (m GroupSignMethod) String() string {
+	switch m {
+	case GroupSignGenesis:
+		return "genesis"
+
+	case GroupSignVirtualTx:
+		return "virtual_tx"
+
+	default:
+		return fmt.Sprintf("<unknown(%d)>", uint8(m))
+	}
+}
+
+// GroupSignTlvType represents the different TLV types for group sign request
+// and response records.
+type GroupSignTlvType = tlv.Type
+
+const (
+	GroupSignReqMethod        GroupSignTlvType = 0
+	GroupSignReqRawKey        GroupSignTlvType = 2
+	GroupSignReqKeyFamily     GroupSignTlvType = 4
+	GroupSignReqKeyIndex      GroupSignTlvType = 6
+	GroupSignReqInitialGen    GroupSignTlvType = 8
+	GroupSignReqCurrentGen    GroupSignTlvType = 10
+	GroupSignReqTapscriptRoot GroupSignTlvType = 12
+	GroupSignReqLeafVersion   GroupSignTlvType = 14
+	GroupSignReqLeafScript    GroupSignTlvType = 16
+	GroupSignReqVirtualTx     GroupSignTlvType = 18
+	GroupSignReqPrevOutValue  GroupSignTlvType = 20
+	GroupSignReqPrevOutScript GroupSignTlvType = 22
+
+	GroupSignRespGroupKey GroupSignTlvType = 0
+	GroupSignRespSig      GroupSignTlvType = 2
+)
+
+// GroupSignRequest is a self-contained description of a single call to a
+// GenesisSigner. It can be serialized and handed to a signer that doesn't
+// share any state with tapd, such as a remote signing service or an
+// air-gapped machine that holds the group internal keys.
+type GroupSignRequest struct {
+	// Method is the signing operation that is requested.
+	Method GroupSignMethod
+
+	// KeyDesc identifies the group internal key that should be used for
+	// signing.
+	KeyDesc keychain.KeyDescriptor
+
+	// InitialGen is the genesis of the first asset in the group, which
+	// the group key is tweaked with. Only used by GroupSignGenesis.
+	InitialGen Genesis
+
+	// CurrentGen is the genesis of the asset being issued into an existing
+	// group. Only used by GroupSignGenesis and nil when minting the first
+	// asset of a group.
+	CurrentGen *Genesis
+
+	// TapscriptRoot is the tapscript root the group key commits to. Only
+	// used by GroupSignVirtualTx for key spends.
+	TapscriptRoot []byte
+
+	// TapLeaf is the leaf to create a script spend signature for. Only
+	// used by GroupSignVirtualTx and nil for key spends.
+	TapLeaf *txscript.TapLeaf
+
+	// VirtualTx is the group virtual transaction to sign. Only used by
+	// GroupSignVirtualTx.
+	VirtualTx *wire.MsgTx
+
+	// PrevOut is the output spent by the group virtual transaction. Only
+	// used by GroupSignVirtualTx.
+	PrevOut *wire.TxOut
+}
+
+// newGroupSignGenesisRecord returns a record that encodes a genesis with the
+// given TLV type.
+func newGroupSignGenesisRecord(typ tlv.Type, genesis *Genesis) tlv.Record {
+	recordSize := func() uint64 {
+		var (
+			b   bytes.Buffer
+			buf [8]byte
+		)
+		if err := GenesisEncoder(&b, genesis, &buf); err != nil {
+			panic(err)
+		}
+		return uint64(len(b.Bytes()))
+	}
+	return tlv.MakeDynamicRecord(
+		typ, genesis, recordSize, GenesisEncoder, GenesisDecoder,
+	)
+}
+
+// Encode encodes a group sign request into a TLV stream.
+func (r *GroupSignRequest) Encode(w io.Writer) error {
+	if r.KeyDesc.PubKey == nil {
+		return ErrMissingKey
+	}
+
+	var (
+		method    = uint8(r.Method)
+		rawKey    [btcec.PubKeyBytesLenCompressed]byte
+		keyFamily = uint32(r.KeyDesc.Family)
+		keyIndex  = r.KeyDesc.Index
+	)
+	copy(rawKey[:], r.KeyDesc.PubKey.SerializeCompressed())
+
+	records := []tlv.Record{
+		tlv.MakePrimitiveRecord(GroupSignReqMethod, &method),
+		tlv.MakePrimitiveRecord(GroupSignReqRawKey, &rawKey),
+		tlv.MakePrimitiveRecord(GroupSignReqKeyFamily, &keyFamily),
+		tlv.MakePrimitiveRecord(GroupSignReqKeyIndex, &keyIndex),
+		newGroupSignGenesisRecord(
+			GroupSignReqInitialGen, &r.InitialGen,
+		),
+	}
+	if r.CurrentGen != nil {
+		records = append(records, newGroupSignGenesisRecord(
+			GroupSignReqCurrentGen, r.CurrentGen,
+		))
+	}
+	if len(r.TapscriptRoot) > 0 {
+		records = append(records, tlv.MakePrimitiveRecord(
+			GroupSignReqTapscriptRoot, &r.TapscriptRoot,
+		))
+	}
+	if r.TapLeaf != nil {
+		leafVersion := uint8(r.TapLeaf.LeafVersion)
+		records = append(
+			records, tlv.MakePrimitiveRecord(
+				GroupSignReqLeafVersion, &leafVersion,
+			), tlv.MakePrimitiveRecord(
+				GroupSignReqLeafScript, &r.TapLeaf.Script,
+			),
+		)
+	}
+	if r.VirtualTx != nil {
+		var txBuf bytes.Buffer
+		if err := r.VirtualTx.Serialize(&txBuf); err != nil {
+			return err
+		}
+		txBytes := txBuf.Bytes()
+		records = append(records, tlv.MakePrimitiveRecord(
+			GroupSignReqVirtualTx, &txBytes,
+		))
+	}
+	if r.PrevOut != nil {
+		value := uint64(r.PrevOut.Value)
+		records = append(
+			records, tlv.MakePrimitiveRecord(
+				GroupSignReqPrevOutValue, &value,
+			), tlv.MakePrimitiveRecord(
+				GroupSignReqPrevOutScript, &r.PrevOut.PkScript,
+			),
+		)
+	}
+
+	stream, err := tlv.NewStream(records...)
+	if err != nil {
+		return err
+	}
+	return stream.Encode(w)
+}
+
+// Decode decodes a group sign request from a TLV stream.
+func (r *GroupSignRequest) Decode(rd io.Reader) error {
+	var (
+		method        uint8
+		rawKey        [btcec.PubKeyBytesLenCompressed]byte
+		keyFamily     uint32
+		keyIndex      uint32
+		initialGen    Genesis
+		currentGen    Genesis
+		tapscriptRoot []byte
+		leafVersion   uint8
+		leafScript    []byte
+		txBytes       []byte
+		prevOutValue  uint64
+		prevOutScript []byte
+	)
+	stream, err := tlv.NewStream(
+		tlv.MakePrimitiveRecord(GroupSignReqMethod, &method),
+		tlv.MakePrimitiveRecord(GroupSignReqRawKey, &rawKey),
+		tlv.MakePrimitiveRecord(GroupSignReqKeyFamily, &keyFamily),
+		tlv.MakePrimitiveRecord(GroupSignReqKeyIndex, &keyIndex),
+		newGroupSignGenesisRecord(GroupSignReqInitialGen, &initialGen),
+		newGroupSignGenesisRecord(GroupSignReqCurrentGen, &currentGen),
+		tlv.MakePrimitiveRecord(
+			GroupSignReqTapscriptRoot, &tapscriptRoot,
+		),
+		tlv.MakePrimitiveRecord(GroupSignReqLeafVersion, &leafVersion),
+		tlv.MakePrimitiveRecord(GroupSignReqLeafScript, &leafScript),
+		tlv.MakePrimitiveRecord(GroupSignReqVirtualTx, &txBytes),
+		tlv.MakePrimitiveRecord(
+			GroupSignReqPrevOutValue, &prevOutValue,
+		),
+		tlv.MakePrimitiveRecord(
+			GroupSignReqPrevOutScript, &prevOutScript,
+		),
+	)
+	if err != nil {
+		return err
+	}
+
+	parsedTypes, err := stream.DecodeWithParsedTypes(rd)
+	if err != nil {
+		return err
+	}
+
+	pubKey, err := btcec.ParsePubKey(rawKey[:])
+	if err != nil {
+		return fmt.Errorf("unable to parse raw key: %w", err)
+	}
+
+	*r = GroupSignRequest{
+		Method: GroupSignMethod(method),
+		KeyDesc: keychain.KeyDescriptor{
+			PubKey: pubKey,
+			KeyLocator: keychain.KeyLocator{
+				Family: keychain.KeyFamily(keyFamily),
+				Index:  keyIndex,
+			},
+		},
+		InitialGen:    initialGen,
+		TapscriptRoot: tapscriptRoot,
+	}
+
+	if _, ok := parsedTypes[GroupSignReqCurrentGen]; ok {
+		r.CurrentGen = &currentGen
+	}
+	if _, ok := parsedTypes[GroupSignReqLeafScript]; ok {
+		r.TapLeaf = &txscript.TapLeaf{
+			LeafVersion: txscript.TapscriptLeafVersion(leafVersion),
+			Script:      leafScript,
+		}
+	}
+	if _, ok := parsedTypes[GroupSignReqVirtualTx]; ok {
+		r.VirtualTx = &wire.MsgTx{}
+		err := r.VirtualTx.Deserialize(bytes.NewReader(txBytes))
+		if err != nil {
+			return fmt.Errorf("unable to parse virtual tx: %w", err)
+		}
+	}
+	if _, ok := parsedTypes[GroupSignReqPrevOutScript]; ok {
+		r.PrevOut = wire.NewTxOut(int64(prevOutValue), prevOutScript)
+	}
+
+	return nil
+}
+
+// GroupSignResponse is the answer of a signer to a GroupSignRequest.
+type GroupSignResponse struct {
+	// GroupPubKey is the tweaked group key. Only set in response to a
+	// GroupSignGenesis request.
+	GroupPubKey *btcec.PublicKey
+
+	// Sig is the signature produced by the signer.
+	Sig schnorr.Signature
+}
+
+// Encode encodes a group sign response into a TLV stream.
+func (r *GroupSignResponse) Encode(w io.Writer) error {
+	var records []tlv.Record
+	if r.GroupPubKey != nil {
+		records = append(records, tlv.MakeStaticRecord(
+			GroupSignRespGroupKey, &r.GroupPubKey,
+			btcec.PubKeyBytesLenCompressed,
+			CompressedPubKeyEncoder, CompressedPubKeyDecoder,
+		))
+	}
+	records = append(records, tlv.MakeStaticRecord(
+		GroupSignRespSig, &r.Sig, schnorr.SignatureSize,
+		SchnorrSignatureEncoder, SchnorrSignatureDecoder,
+	))
+
+	stream, err := tlv.NewStream(records...)
+	if err != nil {
+		return err
+	}
+	return stream.Encode(w)
+}
+
+// Decode decodes a group sign response from a TLV stream.
+func (r *GroupSignResponse) Decode(rd io.Reader) error {
+	stream, err := tlv.NewStream(
+		tlv.MakeStaticRecord(
+			GroupSignRespGroupKey, &r.GroupPubKey,
+			btcec.PubKeyBytesLenCompressed,
+			CompressedPubKeyEncoder, CompressedPubKeyDecoder,
+		),
+		tlv.MakeStaticRecord(
+			GroupSignRespSig, &r.Sig, schnorr.SignatureSize,
+			SchnorrSignatureEncoder, SchnorrSignatureDecoder,
+		),
+	)
+	if err != nil {
+		return err
+	}
+	return stream.Decode(rd)
+}
+
+// HandleGroupSignRequest serves a group sign request with the passed signer.
+// This is meant to be used on the signing side, for example on an air-gapped
+// machine that holds the group internal keys.
+func HandleGroupSignRequest(signer GenesisSigner,
+	req *GroupSignRequest) (*GroupSignResponse, error) {
+
+	switch req.Method {
+	case GroupSignGenesis:
+		groupPubKey, sig, err := signer.SignGenesis(
+			req.KeyDesc, req.InitialGen, req.CurrentGen,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		return &GroupSignResponse{
+			GroupPubKey: groupPubKey,
+			Sig:         *sig,
+		}, nil
+
+	case GroupSignVirtualTx:
+		if req.VirtualTx == nil || req.PrevOut == nil {
+			return nil, fmt.Errorf("virtual tx sign request " +
+				"missing tx or previous output")
+		}
+
+		sig, err := signer.SignGroupVirtualTx(
+			req.KeyDesc, req.TapscriptRoot, req.TapLeaf,
+			req.VirtualTx, req.PrevOut,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		return &GroupSignResponse{
+			Sig: *sig,
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown group sign method: %v",
+			req.Method)
+	}
+}
+
+// GroupSignRequester forwards group sign requests to an external signer and
+// returns its response. Implementations may transport the request in any way,
+// for example over RPC or by exchanging serialized requests with an
+// air-gapped machine.
+type GroupSignRequester interface {
+	// RequestGroupSig sends the request to the external signer and blocks
+	// until it responded.
+	RequestGroupSig(*GroupSignRequest) (*GroupSignResponse, error)
+}
+
+// ExternalGenesisSigner implements the GenesisSigner interface by forwarding
+// all signing requests to an external signer through a GroupSignRequester.
+type ExternalGenesisSigner struct {
+	requester GroupSignRequester
+}
+
+// NewExternalGenesisSigner creates a new ExternalGenesisSigner that uses the
+// passed requester to reach the external signer.
+func NewExternalGenesisSigner(
+	requester GroupSignRequester) *ExternalGenesisSigner {
+
+	return &ExternalGenesisSigner{
+		requester: requester,
+	}
+}
+
+// SignGenesis tweaks the public key identified by the passed key
+// descriptor with the the first passed Genesis description, and signs
+// the second passed Genesis description with the tweaked public key.
+// For minting the first asset in a group, only one Genesis object is
+// needed, since we tweak with and sign over the same Genesis object.
+// The final tweaked public key and the signature are returned.
+func (e *ExternalGenesisSigner) SignGenesis(keyDesc keychain.KeyDescriptor,
+	initialGen Genesis, currentGen *Genesis) (*btcec.PublicKey,
+	*schnorr.Signature, error) {
+
+	id := initialGen.ID()
+	if currentGen != nil {
+		if initialGen.Type != currentGen.Type {
+			return nil, nil, fmt.Errorf("asset group type mismatch")
+		}
+
+		id = currentGen.ID()
+	}
+
+	resp, err := e.requester.RequestGroupSig(&GroupSignRequest{
+		Method:     GroupSignGenesis,
+		KeyDesc:    keyDesc,
+		InitialGen: initialGen,
+		CurrentGen: currentGen,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// We don't blindly trust the external signer, so we make sure it
+	// tweaked the expected key and signed the expected asset ID.
+	tweakedPubKey := txscript.ComputeTaprootOutputKey(
+		keyDesc.PubKey, initialGen.GroupKeyTweak(),
+	)
+	if resp.GroupPubKey == nil ||
+		!resp.GroupPubKey.IsEqual(tweakedPubKey) {
+
+		return nil, nil, fmt.Errorf("external signer returned "+
+			"unexpected group key: %w", ErrInvalidGroupWitness)
+	}
+
+	idHash := sha256.Sum256(id[:])
+	if !resp.Sig.Verify(idHash[:], tweakedPubKey) {
+		return nil, nil, fmt.Errorf("external signer returned "+
+			"invalid signature: %w", ErrInvalidGroupWitness)
+	}
+
+	return tweakedPubKey, &resp.Sig, nil
+}
+
+// SignGroupVirtualTx signs the passed group virtual transaction with the key
+// identified by the passed key descriptor. If no tap leaf is passed, a key
+// spend signature for the group key that commits to the passed tapscript root
+// is created. Otherwise, a script spend signature for the passed tap leaf is
+// created.
+func (e *ExternalGenesisSigner) SignGroupVirtualTx(
+	keyDesc keychain.KeyDescriptor, tapscriptRoot []byte,
+	leaf *txscript.TapLeaf, virtualTx *wire.MsgTx,
+	prevOut *wire.TxOut) (*schnorr.Signature, error) {
+
+	resp, err := e.requester.RequestGroupSig(&GroupSignRequest{
+		Method:        GroupSignVirtualTx,
+		KeyDesc:       keyDesc,
+		TapscriptRoot: tapscriptRoot,
+		TapLeaf:       leaf,
+		VirtualTx:     virtualTx,
+		PrevOut:       prevOut,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp.Sig, nil
+}
+
+// A compile-time assertion to ensure ExternalGenesisSigner meets the
+// GenesisSigner interface.
+var _ GenesisSigner = (*ExternalGenesisSigner)(nil)
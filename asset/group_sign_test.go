@@ -0,0 +1,188 @@
+package asset
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/stretchr/testify/require"
+)
+
+// airGappedRequester is a GroupSignRequester that serializes every request
+// and response, as if they were carried to and from an air-gapped signer.
+type airGappedRequester struct {
+	signer GenesisSigner
+
+	// tamper, if set, is applied to every response before it is returned.
+	tamper func(*GroupSignResponse)
+}
+
+func (a *airGappedRequester) RequestGroupSig(
+	req *GroupSignRequest) (*GroupSignResponse, error) {
+
+	var reqBuf bytes.Buffer
+	if err := req.Encode(&reqBuf); err != nil {
+		return nil, err
+	}
+
+	var signerReq GroupSignRequest
+	if err := signerReq.Decode(&reqBuf); err != nil {
+		return nil, err
+	}
+
+	signerResp, err := HandleGroupSignRequest(a.signer, &signerReq)
+	if err != nil {
+		return nil, err
+	}
+	if a.tamper != nil {
+		a.tamper(signerResp)
+	}
+
+	var respBuf bytes.Buffer
+	if err := signerResp.Encode(&respBuf); err != nil {
+		return nil, err
+	}
+
+	var resp GroupSignResponse
+	if err := resp.Decode(&respBuf); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// TestGroupSignRequestEncoding tests that group sign requests survive an
+// encoding round trip.
+func TestGroupSignRequestEncoding(t *testing.T) {
+	t.Parallel()
+
+	keyDesc := keychain.KeyDescriptor{
+		PubKey: pubKey,
+		KeyLocator: keychain.KeyLocator{
+			Family: TaprootAssetsKeyFamily,
+			Index:  7,
+		},
+	}
+	gen := RandGenesis(t, Normal)
+	otherGen := RandGenesis(t, Normal)
+	groupPubKey := TapscriptGroupPubKey(pubKey, hashBytes1[:])
+	virtualTx, prevOut, err := GroupVirtualTx(gen, groupPubKey)
+	require.NoError(t, err)
+	leaf := txscript.NewBaseTapLeaf([]byte{txscript.OP_TRUE})
+
+	testCases := []*GroupSignRequest{{
+		Method:     GroupSignGenesis,
+		KeyDesc:    keyDesc,
+		InitialGen: gen,
+	}, {
+		Method:     GroupSignGenesis,
+		KeyDesc:    keyDesc,
+		InitialGen: gen,
+		CurrentGen: &otherGen,
+	}, {
+		Method:        GroupSignVirtualTx,
+		KeyDesc:       keyDesc,
+		TapscriptRoot: hashBytes1[:],
+		VirtualTx:     virtualTx,
+		PrevOut:       prevOut,
+	}, {
+		Method:        GroupSignVirtualTx,
+		KeyDesc:       keyDesc,
+		TapscriptRoot: hashBytes1[:],
+		TapLeaf:       &leaf,
+		VirtualTx:     virtualTx,
+		PrevOut:       prevOut,
+	}}
+
+	for idx, req := range testCases {
+		req := req
+		t.Run(fmt.Sprintf("%v_%d", req.Method, idx), func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, req.Encode(&buf))
+
+			var decoded GroupSignRequest
+			require.NoError(t, decoded.Decode(&buf))
+
+			// Deserializing a transaction yields empty instead of
+			// nil signature scripts, so we compare it separately.
+			if req.VirtualTx != nil {
+				require.Equal(
+					t, req.VirtualTx.TxHash(),
+					decoded.VirtualTx.TxHash(),
+				)
+				decoded.VirtualTx = req.VirtualTx
+			}
+			require.Equal(t, req, &decoded)
+		})
+	}
+
+	// A request without a signing key can't be encoded.
+	var buf bytes.Buffer
+	err = (&GroupSignRequest{InitialGen: gen}).Encode(&buf)
+	require.ErrorIs(t, err, ErrMissingKey)
+}
+
+// TestExternalGenesisSigner tests that an external genesis signer produces
+// the same group keys and witnesses as a local signer, and that it rejects
+// responses that don't match the request.
+func TestExternalGenesisSigner(t *testing.T) {
+	t.Parallel()
+
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	keyDesc := keychain.KeyDescriptor{
+		PubKey: privKey.PubKey(),
+	}
+
+	requester := &airGappedRequester{
+		signer: NewRawKeyGenesisSigner(privKey),
+	}
+	signer := NewExternalGenesisSigner(requester)
+
+	gen := RandGenesis(t, Normal)
+	otherGen := RandGenesis(t, Normal)
+
+	// Group keys for both the first asset and a reissuance must be valid.
+	groupKey, err := DeriveGroupKey(signer, keyDesc, gen, nil)
+	require.NoError(t, err)
+	require.True(t, gen.VerifyGroupWitness(groupKey))
+
+	groupKey, err = DeriveGroupKey(signer, keyDesc, gen, &otherGen)
+	require.NoError(t, err)
+	require.True(t, otherGen.VerifyGroupWitness(groupKey))
+
+	// Collectibles can't be issued into a group of normal assets.
+	collectibleGen := RandGenesis(t, Collectible)
+	_, err = DeriveGroupKey(signer, keyDesc, gen, &collectibleGen)
+	require.ErrorContains(t, err, "asset group type mismatch")
+
+	// A tapscript group witness must also be valid.
+	tapscriptKey, err := DeriveTapscriptGroupKey(
+		signer, keyDesc, hashBytes2[:], gen,
+	)
+	require.NoError(t, err)
+	require.True(t, gen.VerifyGroupWitness(tapscriptKey))
+
+	// A signer that tweaks the wrong key or signs the wrong asset ID must
+	// be detected.
+	otherPriv, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	requester.tamper = func(resp *GroupSignResponse) {
+		resp.GroupPubKey = otherPriv.PubKey()
+	}
+	_, err = DeriveGroupKey(signer, keyDesc, gen, nil)
+	require.ErrorIs(t, err, ErrInvalidGroupWitness)
+
+	requester.tamper = func(resp *GroupSignResponse) {
+		otherID := otherGen.ID()
+		sig, err := schnorr.Sign(otherPriv, otherID[:])
+		require.NoError(t, err)
+		resp.Sig = *sig
+	}
+	_, err = DeriveGroupKey(signer, keyDesc, gen, nil)
+	require.ErrorIs(t, err, ErrInvalidGroupWitness)
+}
@@ -0,0 +1,446 @@
+package asset
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+var (
+	// ErrInvalidGroupWitness is returned when a group witness doesn't
+	// satisfy the group key it is meant to authorize an issuance for.
+	ErrInvalidGroupWitness = fmt.Errorf("invalid group witness")
+)
+
+const (
+	// The TLV types of the fields of an encoded group script spend.
+	groupSpendLeafVersionType    tlv.Type = 0
+	groupSpendLeafScriptType     tlv.Type = 2
+	groupSpendInclusionProofType tlv.Type = 4
+	groupSpendSignersType        tlv.Type = 6
+
+	// signerSlotSize is the encoded size of a signer slot that holds a
+	// key: a presence flag, the key locator and the compressed key.
+	signerSlotSize = 1 + 4 + 4 + btcec.PubKeyBytesLenCompressed
+)
+
+// TapscriptGroupPubKey computes the group key of an asset group that commits
+// to a tapscript tree. The group key is a normal taproot output key:
+//
+//	groupKey = internalKey + h_tapTweak(internalKey || tapscriptRoot)*G
+//
+// This allows new tranches to be issued either with a key spend by the holder
+// of the internal key, or by satisfying one of the scripts in the tree (for
+// example a 2-of-3 multisig of issuers).
+func TapscriptGroupPubKey(internalKey *btcec.PublicKey,
+	tapscriptRoot []byte) *btcec.PublicKey {
+
+	return txscript.ComputeTaprootOutputKey(internalKey, tapscriptRoot)
+}
+
+// GroupVirtualTx returns the virtual transaction and the previous output it
+// spends that are used to create and verify the witness of an asset group key
+// that commits to a tapscript tree. The single input of the virtual
+// transaction spends an outpoint derived from the ID of the given genesis,
+// which means that a group witness is only valid for a single asset ID, just
+// like a group signature.
+func GroupVirtualTx(gen Genesis, groupPubKey *btcec.PublicKey) (*wire.MsgTx,
+	*wire.TxOut, error) {
+
+	pkScript, err := txscript.PayToTaprootScript(groupPubKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The virtual output is locked to the group key and carries no value,
+	// as the group witness only authorizes the creation of the asset ID.
+	prevOut := wire.NewTxOut(0, pkScript)
+
+	assetID := gen.ID()
+	virtualTx := wire.NewMsgTx(2)
+	virtualTx.AddTxIn(wire.NewTxIn(
+		wire.NewOutPoint((*chainhash.Hash)(&assetID), 0), nil, nil,
+	))
+	virtualTx.AddTxOut(wire.NewTxOut(0, pkScript))
+
+	return virtualTx, prevOut, nil
+}
+
+// VerifyGroupWitness verifies that the given group key authorizes the
+// issuance of the asset with this genesis. Group keys that commit to a
+// tapscript tree carry a full witness that is validated against the group
+// virtual transaction, while all other group keys carry a signature over the
+// asset ID.
+func (g Genesis) VerifyGroupWitness(groupKey *GroupKey) bool {
+	if len(groupKey.Witness) == 0 {
+		return g.VerifySignature(&groupKey.Sig, &groupKey.GroupPubKey)
+	}
+
+	virtualTx, prevOut, err := GroupVirtualTx(g, &groupKey.GroupPubKey)
+	if err != nil {
+		return false
+	}
+	virtualTx.TxIn[0].Witness = groupKey.Witness
+
+	prevOutFetcher := txscript.NewCannedPrevOutputFetcher(
+		prevOut.PkScript, prevOut.Value,
+	)
+	sigHashes := txscript.NewTxSigHashes(virtualTx, prevOutFetcher)
+
+	engine, err := txscript.NewEngine(
+		prevOut.PkScript, virtualTx, 0, txscript.StandardVerifyFlags,
+		nil, sigHashes, prevOut.Value, prevOutFetcher,
+	)
+	if err != nil {
+		return false
+	}
+
+	return engine.Execute() == nil
+}
+
+// DeriveTapscriptGroupKey derives the group key of an asset group that
+// commits to the given tapscript root and creates a key spend witness for the
+// given genesis using the internal key.
+func DeriveTapscriptGroupKey(genSigner GenesisSigner,
+	rawKey keychain.KeyDescriptor, tapscriptRoot []byte,
+	gen Genesis) (*GroupKey, error) {
+
+	groupPubKey := TapscriptGroupPubKey(rawKey.PubKey, tapscriptRoot)
+	virtualTx, prevOut, err := GroupVirtualTx(gen, groupPubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := genSigner.SignGroupVirtualTx(
+		rawKey, tapscriptRoot, nil, virtualTx, prevOut,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GroupKey{
+		RawKey:        rawKey,
+		GroupPubKey:   *groupPubKey,
+		TapscriptRoot: tapscriptRoot,
+		Witness:       wire.TxWitness{sig.Serialize()},
+	}, nil
+}
+
+// NewTapscriptGroupKey creates the group key of an asset group that commits
+// to the given tapscript root, using a witness that was created for the group
+// virtual transaction of the given genesis. The witness is usually a script
+// spend witness that was assembled from the signatures of multiple issuers.
+// An error is returned if the witness doesn't authorize the issuance.
+func NewTapscriptGroupKey(rawKey keychain.KeyDescriptor, tapscriptRoot []byte,
+	gen Genesis, witness wire.TxWitness) (*GroupKey, error) {
+
+	if len(witness) == 0 {
+		return nil, fmt.Errorf("%w: empty witness",
+			ErrInvalidGroupWitness)
+	}
+
+	groupKey := &GroupKey{
+		RawKey: rawKey,
+		GroupPubKey: *TapscriptGroupPubKey(
+			rawKey.PubKey, tapscriptRoot,
+		),
+		TapscriptRoot: tapscriptRoot,
+		Witness:       witness,
+	}
+	if !gen.VerifyGroupWitness(groupKey) {
+		return nil, ErrInvalidGroupWitness
+	}
+
+	return groupKey, nil
+}
+
+// GroupScriptSpend describes how the issuance of a new tranche into an asset
+// group that commits to a tapscript tree is authorized through one of the
+// scripts in the tree, instead of a key spend with the internal key of the
+// group. All keys that sign for the leaf must be available to the
+// GenesisSigner of the minting node.
+type GroupScriptSpend struct {
+	// Leaf is the leaf of the group's tapscript tree that is satisfied.
+	Leaf txscript.TapLeaf
+
+	// InclusionProof is the concatenation of the sibling hashes that prove
+	// the inclusion of the leaf in the tapscript tree, as found in a
+	// control block.
+	InclusionProof []byte
+
+	// Signers are the keys that sign for the leaf, in the order their
+	// signatures are placed on the witness stack. A nil entry results in
+	// an empty witness element, for example for the key of an issuer that
+	// doesn't sign for a 2-of-3 multisig leaf.
+	Signers []*keychain.KeyDescriptor
+}
+
+// ControlBlock returns the serialized control block that reveals the leaf of
+// the script spend for the group key with the given internal key. An error is
+// returned if the leaf isn't part of the tree with the given tapscript root.
+func (s *GroupScriptSpend) ControlBlock(internalKey *btcec.PublicKey,
+	tapscriptRoot []byte) ([]byte, error) {
+
+	groupPubKey := TapscriptGroupPubKey(internalKey, tapscriptRoot)
+	ctrlBlock := txscript.ControlBlock{
+		InternalKey:     internalKey,
+		OutputKeyYIsOdd: groupPubKey.SerializeCompressed()[0] == 0x03,
+		LeafVersion:     s.Leaf.LeafVersion,
+		InclusionProof:  s.InclusionProof,
+	}
+
+	rootHash := ctrlBlock.RootHash(s.Leaf.Script)
+	if !bytes.Equal(rootHash, tapscriptRoot) {
+		return nil, fmt.Errorf("%w: leaf not in tapscript tree",
+			ErrInvalidGroupWitness)
+	}
+
+	return ctrlBlock.ToBytes()
+}
+
+// Encode encodes the script spend as a TLV stream.
+func (s *GroupScriptSpend) Encode(w io.Writer) error {
+	leafVersion := uint8(s.Leaf.LeafVersion)
+	stream, err := tlv.NewStream(
+		tlv.MakePrimitiveRecord(
+			groupSpendLeafVersionType, &leafVersion,
+		),
+		tlv.MakePrimitiveRecord(
+			groupSpendLeafScriptType, &s.Leaf.Script,
+		),
+		tlv.MakePrimitiveRecord(
+			groupSpendInclusionProofType, &s.InclusionProof,
+		),
+		tlv.MakeDynamicRecord(
+			groupSpendSignersType, &s.Signers,
+			func() uint64 {
+				return signerSlotsSize(s.Signers)
+			}, signerSlotsEncoder, signerSlotsDecoder,
+		),
+	)
+	if err != nil {
+		return err
+	}
+
+	return stream.Encode(w)
+}
+
+// Decode decodes a script spend from a TLV stream.
+func (s *GroupScriptSpend) Decode(r io.Reader) error {
+	var leafVersion uint8
+	stream, err := tlv.NewStream(
+		tlv.MakePrimitiveRecord(
+			groupSpendLeafVersionType, &leafVersion,
+		),
+		tlv.MakePrimitiveRecord(
+			groupSpendLeafScriptType, &s.Leaf.Script,
+		),
+		tlv.MakePrimitiveRecord(
+			groupSpendInclusionProofType, &s.InclusionProof,
+		),
+		tlv.MakeDynamicRecord(
+			groupSpendSignersType, &s.Signers, nil,
+			signerSlotsEncoder, signerSlotsDecoder,
+		),
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := stream.Decode(r); err != nil {
+		return err
+	}
+	s.Leaf.LeafVersion = txscript.TapscriptLeafVersion(leafVersion)
+
+	return nil
+}
+
+// signerSlotsSize returns the encoded size of the given signer slots.
+func signerSlotsSize(signers []*keychain.KeyDescriptor) uint64 {
+	size := uint64(tlv.VarIntSize(uint64(len(signers))))
+	for _, signer := range signers {
+		if signer == nil {
+			size++
+			continue
+		}
+
+		size += signerSlotSize
+	}
+
+	return size
+}
+
+func signerSlotsEncoder(w io.Writer, val any, buf *[8]byte) error {
+	if t, ok := val.(*[]*keychain.KeyDescriptor); ok {
+		err := tlv.WriteVarInt(w, uint64(len(*t)), buf)
+		if err != nil {
+			return err
+		}
+
+		for _, signer := range *t {
+			present := signer != nil
+			if err := BoolEncoder(w, &present, buf); err != nil {
+				return err
+			}
+			if !present {
+				continue
+			}
+
+			family := uint32(signer.Family)
+			if err := tlv.EUint32(w, &family, buf); err != nil {
+				return err
+			}
+			err := tlv.EUint32(w, &signer.Index, buf)
+			if err != nil {
+				return err
+			}
+			err = CompressedPubKeyEncoder(w, &signer.PubKey, buf)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+	return tlv.NewTypeForEncodingErr(val, "[]*keychain.KeyDescriptor")
+}
+
+func signerSlotsDecoder(r io.Reader, val any, buf *[8]byte, l uint64) error {
+	if typ, ok := val.(*[]*keychain.KeyDescriptor); ok {
+		numSigners, err := tlv.ReadVarInt(r, buf)
+		if err != nil {
+			return err
+		}
+
+		// Each slot takes at least one byte, which bounds the number
+		// of slots we need to allocate.
+		if numSigners > l {
+			return tlv.ErrRecordTooLarge
+		}
+
+		signers := make([]*keychain.KeyDescriptor, numSigners)
+		for idx := range signers {
+			var present bool
+			if err := BoolDecoder(r, &present, buf, 1); err != nil {
+				return err
+			}
+			if !present {
+				continue
+			}
+
+			var (
+				signer keychain.KeyDescriptor
+				family uint32
+			)
+			if err := tlv.DUint32(r, &family, buf, 4); err != nil {
+				return err
+			}
+			signer.Family = keychain.KeyFamily(family)
+			err := tlv.DUint32(r, &signer.Index, buf, 4)
+			if err != nil {
+				return err
+			}
+			err = CompressedPubKeyDecoder(
+				r, &signer.PubKey, buf,
+				btcec.PubKeyBytesLenCompressed,
+			)
+			if err != nil {
+				return err
+			}
+
+			signers[idx] = &signer
+		}
+
+		*typ = signers
+		return nil
+	}
+	return tlv.NewTypeForDecodingErr(val, "[]*keychain.KeyDescriptor", l, l)
+}
+
+// DeriveScriptSpendGroupKey derives the group key of an asset group that
+// commits to the given tapscript root and creates a script spend witness for
+// the given genesis. Each signer of the script spend signs the group virtual
+// transaction through the passed GenesisSigner, and the signatures are
+// assembled into the witness together with the leaf and its control block.
+func DeriveScriptSpendGroupKey(genSigner GenesisSigner,
+	rawKey keychain.KeyDescriptor, tapscriptRoot []byte,
+	spend *GroupScriptSpend, gen Genesis) (*GroupKey, error) {
+
+	ctrlBlock, err := spend.ControlBlock(rawKey.PubKey, tapscriptRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	groupPubKey := TapscriptGroupPubKey(rawKey.PubKey, tapscriptRoot)
+	virtualTx, prevOut, err := GroupVirtualTx(gen, groupPubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	witness := make(wire.TxWitness, 0, len(spend.Signers)+2)
+	for _, signer := range spend.Signers {
+		if signer == nil {
+			witness = append(witness, nil)
+			continue
+		}
+
+		sig, err := genSigner.SignGroupVirtualTx(
+			*signer, tapscriptRoot, &spend.Leaf, virtualTx, prevOut,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		witness = append(witness, sig.Serialize())
+	}
+	witness = append(witness, spend.Leaf.Script, ctrlBlock)
+
+	return NewTapscriptGroupKey(rawKey, tapscriptRoot, gen, witness)
+}
+
+// signGroupVirtualTx creates a key spend or script spend signature for the
+// given group virtual transaction with the passed private key.
+func signGroupVirtualTx(privKey *btcec.PrivateKey, tapscriptRoot []byte,
+	leaf *txscript.TapLeaf, virtualTx *wire.MsgTx,
+	prevOut *wire.TxOut) (*schnorr.Signature, error) {
+
+	prevOutFetcher := txscript.NewCannedPrevOutputFetcher(
+		prevOut.PkScript, prevOut.Value,
+	)
+	sigHashes := txscript.NewTxSigHashes(virtualTx, prevOutFetcher)
+
+	var (
+		rawSig []byte
+		err    error
+	)
+	switch {
+	// Without a leaf we're doing a key spend, which means the private key
+	// needs to be tweaked with the tapscript root first.
+	case leaf == nil:
+		rawSig, err = txscript.RawTxInTaprootSignature(
+			virtualTx, sigHashes, 0, prevOut.Value,
+			prevOut.PkScript, tapscriptRoot,
+			txscript.SigHashDefault, privKey,
+		)
+
+	default:
+		rawSig, err = txscript.RawTxInTapscriptSignature(
+			virtualTx, sigHashes, 0, prevOut.Value,
+			prevOut.PkScript, *leaf, txscript.SigHashDefault,
+			privKey,
+		)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return schnorr.ParseSignature(rawSig)
+}
@@ -0,0 +1,509 @@
+package asset
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightninglabs/taproot-assets/mssmt"
+)
+
+// The JSON encoding of assets is a stable, human-readable representation of
+// the same data that is committed to in the TLV encoding of an asset. Data that
+// is only known locally and not part of the TLV encoding (such as the key
+// locators of script and group keys or the tapscript root of a group key) is
+// not included. The schema is as follows:
+//
+//	Asset {
+//	  "version": number,
+//	  "genesis": Genesis,
+//	  "amount": decimal string,
+//	  "lock_time": decimal string,
+//	  "relative_lock_time": decimal string,
+//	  "prev_witnesses": [Witness],
+//	  "split_commitment_root": Node or null,
+//	  "script_version": number,
+//	  "script_key": hex compressed public key,
+//	  "group_key": GroupKey or null,
+//	  "non_divisible": bool,
+//	  "edition": decimal string
+//	}
+//
+//	Genesis {
+//	  "first_prev_out": "txid:index",
+//	  "tag": string,
+//	  "meta_hash": hex,
+//	  "output_index": number,
+//	  "type": number,
+//	  "asset_id": hex
+//	}
+//
+//	GroupKey {
+//	  "group_pub_key": hex compressed public key,
+//	  "sig": hex schnorr signature,
+//	  "witness": [hex], omitted if empty
+//	}
+//
+//	Witness {
+//	  "prev_id": PrevID or null,
+//	  "tx_witness": [hex] or null,
+//	  "split_commitment": SplitCommitment or null
+//	}
+//
+//	PrevID {
+//	  "out_point": "txid:index",
+//	  "asset_id": hex,
+//	  "script_key": hex serialized key
+//	}
+//
+//	SplitCommitment {
+//	  "proof": hex compressed MS-SMT proof,
+//	  "root_asset": Asset
+//	}
+//
+//	Node {
+//	  "hash": hex,
+//	  "sum": decimal string
+//	}
+//
+// Amounts and other 64-bit integers are encoded as decimal strings, so they can
+// be consumed without loss of precision by any JSON parser. The asset ID of a
+// genesis is derived from the other fields and only included for convenience.
+// It is validated but otherwise ignored when decoding.
+
+// jsonGenesis is the JSON representation of a Genesis.
+type jsonGenesis struct {
+	FirstPrevOut string `json:"first_prev_out"`
+	Tag          string `json:"tag"`
+	MetaHash     string `json:"meta_hash"`
+	OutputIndex  uint32 `json:"output_index"`
+	Type         Type   `json:"type"`
+	AssetID      string `json:"asset_id,omitempty"`
+}
+
+// jsonGroupKey is the JSON representation of a GroupKey.
+type jsonGroupKey struct {
+	GroupPubKey string   `json:"group_pub_key"`
+	Sig         string   `json:"sig"`
+	Witness     []string `json:"witness,omitempty"`
+}
+
+// jsonPrevID is the JSON representation of a PrevID.
+type jsonPrevID struct {
+	OutPoint  string `json:"out_point"`
+	AssetID   string `json:"asset_id"`
+	ScriptKey string `json:"script_key"`
+}
+
+// jsonSplitCommitment is the JSON representation of a SplitCommitment.
+type jsonSplitCommitment struct {
+	Proof     string `json:"proof"`
+	RootAsset *Asset `json:"root_asset"`
+}
+
+// jsonWitness is the JSON representation of a Witness.
+type jsonWitness struct {
+	PrevID          *jsonPrevID          `json:"prev_id"`
+	TxWitness       []string             `json:"tx_witness"`
+	SplitCommitment *jsonSplitCommitment `json:"split_commitment"`
+}
+
+// jsonNode is the JSON representation of an MS-SMT node.
+type jsonNode struct {
+	Hash string `json:"hash"`
+	Sum  uint64 `json:"sum,string"`
+}
+
+// jsonAsset is the JSON representation of an Asset.
+type jsonAsset struct {
+	Version             Version       `json:"version"`
+	Genesis             Genesis       `json:"genesis"`
+	Amount              uint64        `json:"amount,string"`
+	LockTime            uint64        `json:"lock_time,string"`
+	RelativeLockTime    uint64        `json:"relative_lock_time,string"`
+	PrevWitnesses       []jsonWitness `json:"prev_witnesses"`
+	SplitCommitmentRoot *jsonNode     `json:"split_commitment_root"`
+	ScriptVersion       ScriptVersion `json:"script_version"`
+	ScriptKey           string        `json:"script_key"`
+	GroupKey            *GroupKey     `json:"group_key"`
+	NonDivisible        bool          `json:"non_divisible"`
+	Edition             uint64        `json:"edition,string"`
+}
+
+// MarshalJSON encodes the genesis as JSON.
+func (g Genesis) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&jsonGenesis{
+		FirstPrevOut: g.FirstPrevOut.String(),
+		Tag:          g.Tag,
+		MetaHash:     hex.EncodeToString(g.MetaHash[:]),
+		OutputIndex:  g.OutputIndex,
+		Type:         g.Type,
+		AssetID:      g.ID().String(),
+	})
+}
+
+// UnmarshalJSON decodes the genesis from JSON.
+func (g *Genesis) UnmarshalJSON(data []byte) error {
+	var j jsonGenesis
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	firstPrevOut, err := parseOutPoint(j.FirstPrevOut)
+	if err != nil {
+		return fmt.Errorf("invalid first_prev_out: %w", err)
+	}
+
+	var metaHash [MetaHashLen]byte
+	if err := decodeHexArray(j.MetaHash, metaHash[:]); err != nil {
+		return fmt.Errorf("invalid meta_hash: %w", err)
+	}
+
+	genesis := Genesis{
+		FirstPrevOut: firstPrevOut,
+		Tag:          j.Tag,
+		MetaHash:     metaHash,
+		OutputIndex:  j.OutputIndex,
+		Type:         j.Type,
+	}
+
+	// The asset ID is optional, but if it is present, it must match the
+	// ID derived from the genesis.
+	if j.AssetID != "" && j.AssetID != genesis.ID().String() {
+		return fmt.Errorf("asset_id %v doesn't match genesis asset "+
+			"ID %v", j.AssetID, genesis.ID())
+	}
+
+	*g = genesis
+
+	return nil
+}
+
+// MarshalJSON encodes the group key as JSON.
+func (g GroupKey) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&jsonGroupKey{
+		GroupPubKey: hex.EncodeToString(
+			g.GroupPubKey.SerializeCompressed(),
+		),
+		Sig:     hex.EncodeToString(g.Sig.Serialize()),
+		Witness: encodeHexSlice(g.Witness),
+	})
+}
+
+// UnmarshalJSON decodes the group key from JSON.
+func (g *GroupKey) UnmarshalJSON(data []byte) error {
+	var j jsonGroupKey
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	groupPubKey, err := parsePubKey(j.GroupPubKey)
+	if err != nil {
+		return fmt.Errorf("invalid group_pub_key: %w", err)
+	}
+
+	sigBytes, err := hex.DecodeString(j.Sig)
+	if err != nil {
+		return fmt.Errorf("invalid sig: %w", err)
+	}
+	sig, err := schnorr.ParseSignature(sigBytes)
+	if err != nil {
+		return fmt.Errorf("invalid sig: %w", err)
+	}
+
+	witness, err := decodeHexSlice(j.Witness)
+	if err != nil {
+		return fmt.Errorf("invalid witness: %w", err)
+	}
+
+	*g = GroupKey{
+		GroupPubKey: *groupPubKey,
+		Sig:         *sig,
+		Witness:     witness,
+	}
+
+	return nil
+}
+
+// MarshalJSON encodes the asset as JSON.
+//
+// NOTE: This uses a value receiver, as encoding an asset value would otherwise
+// use the MarshalJSON method of the embedded Genesis.
+func (a Asset) MarshalJSON() ([]byte, error) {
+	if a.ScriptKey.PubKey == nil {
+		return nil, ErrMissingKey
+	}
+
+	j := jsonAsset{
+		Version:          a.Version,
+		Genesis:          a.Genesis,
+		Amount:           a.Amount,
+		LockTime:         a.LockTime,
+		RelativeLockTime: a.RelativeLockTime,
+		PrevWitnesses:    make([]jsonWitness, len(a.PrevWitnesses)),
+		ScriptVersion:    a.ScriptVersion,
+		ScriptKey: hex.EncodeToString(
+			a.ScriptKey.PubKey.SerializeCompressed(),
+		),
+		GroupKey:     a.GroupKey,
+		NonDivisible: a.NonDivisible,
+		Edition:      a.Edition,
+	}
+
+	for idx := range a.PrevWitnesses {
+		witness, err := newJSONWitness(&a.PrevWitnesses[idx])
+		if err != nil {
+			return nil, err
+		}
+		j.PrevWitnesses[idx] = *witness
+	}
+
+	if a.SplitCommitmentRoot != nil {
+		nodeHash := a.SplitCommitmentRoot.NodeHash()
+		j.SplitCommitmentRoot = &jsonNode{
+			Hash: hex.EncodeToString(nodeHash[:]),
+			Sum:  a.SplitCommitmentRoot.NodeSum(),
+		}
+	}
+
+	return json.Marshal(&j)
+}
+
+// UnmarshalJSON decodes the asset from JSON.
+func (a *Asset) UnmarshalJSON(data []byte) error {
+	var j jsonAsset
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	scriptKey, err := parsePubKey(j.ScriptKey)
+	if err != nil {
+		return fmt.Errorf("invalid script_key: %w", err)
+	}
+
+	newAsset := Asset{
+		Version:          j.Version,
+		Genesis:          j.Genesis,
+		Amount:           j.Amount,
+		LockTime:         j.LockTime,
+		RelativeLockTime: j.RelativeLockTime,
+		ScriptVersion:    j.ScriptVersion,
+		ScriptKey:        ScriptKey{PubKey: scriptKey},
+		GroupKey:         j.GroupKey,
+		NonDivisible:     j.NonDivisible,
+		Edition:          j.Edition,
+	}
+
+	if len(j.PrevWitnesses) > 0 {
+		newAsset.PrevWitnesses = make([]Witness, len(j.PrevWitnesses))
+	}
+	for idx := range j.PrevWitnesses {
+		witness, err := j.PrevWitnesses[idx].witness()
+		if err != nil {
+			return fmt.Errorf("invalid prev_witnesses[%d]: %w",
+				idx, err)
+		}
+		newAsset.PrevWitnesses[idx] = *witness
+	}
+
+	if j.SplitCommitmentRoot != nil {
+		var nodeHash mssmt.NodeHash
+		err := decodeHexArray(j.SplitCommitmentRoot.Hash, nodeHash[:])
+		if err != nil {
+			return fmt.Errorf("invalid split_commitment_root: %w",
+				err)
+		}
+		newAsset.SplitCommitmentRoot = mssmt.NewComputedNode(
+			nodeHash, j.SplitCommitmentRoot.Sum,
+		)
+	}
+
+	*a = newAsset
+
+	return nil
+}
+
+// newJSONWitness creates the JSON representation of the given witness.
+func newJSONWitness(w *Witness) (*jsonWitness, error) {
+	j := &jsonWitness{
+		TxWitness: encodeHexSlice(w.TxWitness),
+	}
+
+	if w.PrevID != nil {
+		j.PrevID = &jsonPrevID{
+			OutPoint: w.PrevID.OutPoint.String(),
+			AssetID:  w.PrevID.ID.String(),
+			ScriptKey: hex.EncodeToString(
+				w.PrevID.ScriptKey[:],
+			),
+		}
+	}
+
+	if w.SplitCommitment != nil {
+		var proof bytes.Buffer
+		err := w.SplitCommitment.Proof.Compress().Encode(&proof)
+		if err != nil {
+			return nil, err
+		}
+
+		j.SplitCommitment = &jsonSplitCommitment{
+			Proof:     hex.EncodeToString(proof.Bytes()),
+			RootAsset: &w.SplitCommitment.RootAsset,
+		}
+	}
+
+	return j, nil
+}
+
+// witness converts the JSON representation of a witness back into a witness.
+func (j *jsonWitness) witness() (*Witness, error) {
+	txWitness, err := decodeHexSlice(j.TxWitness)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tx_witness: %w", err)
+	}
+
+	w := &Witness{
+		TxWitness: txWitness,
+	}
+
+	if j.PrevID != nil {
+		outPoint, err := parseOutPoint(j.PrevID.OutPoint)
+		if err != nil {
+			return nil, fmt.Errorf("invalid out_point: %w", err)
+		}
+
+		var prevID PrevID
+		prevID.OutPoint = outPoint
+		err = decodeHexArray(j.PrevID.AssetID, prevID.ID[:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid asset_id: %w", err)
+		}
+
+		// The script key of a previous ID is not validated, as it is
+		// all zeroes for genesis assets and split leaves.
+		err = decodeHexArray(j.PrevID.ScriptKey, prevID.ScriptKey[:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid script_key: %w", err)
+		}
+
+		w.PrevID = &prevID
+	}
+
+	if j.SplitCommitment != nil {
+		if j.SplitCommitment.RootAsset == nil {
+			return nil, fmt.Errorf("split commitment is missing " +
+				"root_asset")
+		}
+
+		proofBytes, err := hex.DecodeString(j.SplitCommitment.Proof)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proof: %w", err)
+		}
+
+		var compressedProof mssmt.CompressedProof
+		err = compressedProof.DecodeBytes(proofBytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proof: %w", err)
+		}
+		proof, err := compressedProof.Decompress()
+		if err != nil {
+			return nil, fmt.Errorf("invalid proof: %w", err)
+		}
+
+		w.SplitCommitment = &SplitCommitment{
+			Proof:     *proof,
+			RootAsset: *j.SplitCommitment.RootAsset,
+		}
+	}
+
+	return w, nil
+}
+
+// parseOutPoint parses an outpoint in the "txid:index" format.
+func parseOutPoint(s string) (wire.OutPoint, error) {
+	txid, index, ok := strings.Cut(s, ":")
+	if !ok {
+		return wire.OutPoint{}, fmt.Errorf("outpoint %q should be of "+
+			"the form txid:index", s)
+	}
+
+	hash, err := chainhash.NewHashFromStr(txid)
+	if err != nil {
+		return wire.OutPoint{}, err
+	}
+
+	outputIndex, err := strconv.ParseUint(index, 10, 32)
+	if err != nil {
+		return wire.OutPoint{}, err
+	}
+
+	return wire.OutPoint{
+		Hash:  *hash,
+		Index: uint32(outputIndex),
+	}, nil
+}
+
+// parsePubKey parses a hex encoded, compressed public key.
+func parsePubKey(s string) (*btcec.PublicKey, error) {
+	keyBytes, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return btcec.ParsePubKey(keyBytes)
+}
+
+// decodeHexArray decodes the hex string into the given fixed size target.
+func decodeHexArray(s string, target []byte) error {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	if len(b) != len(target) {
+		return fmt.Errorf("expected %d bytes, got %d", len(target),
+			len(b))
+	}
+
+	copy(target, b)
+
+	return nil
+}
+
+// encodeHexSlice hex encodes each of the given byte slices.
+func encodeHexSlice(items [][]byte) []string {
+	if len(items) == 0 {
+		return nil
+	}
+
+	encoded := make([]string, len(items))
+	for idx := range items {
+		encoded[idx] = hex.EncodeToString(items[idx])
+	}
+
+	return encoded
+}
+
+// decodeHexSlice decodes each of the given hex strings.
+func decodeHexSlice(items []string) ([][]byte, error) {
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	decoded := make([][]byte, len(items))
+	for idx := range items {
+		item, err := hex.DecodeString(items[idx])
+		if err != nil {
+			return nil, err
+		}
+		decoded[idx] = item
+	}
+
+	return decoded, nil
+}
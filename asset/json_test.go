@@ -0,0 +1,208 @@
+package asset
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightninglabs/taproot-assets/mssmt"
+	"github.com/stretchr/testify/require"
+)
+
+// expectedAssetJSON is the JSON encoding of the asset created by
+// jsonTestAsset. It must not change, as the JSON schema is meant to be stable.
+const expectedAssetJSON = `{
+  "version": 1,
+  "genesis": {
+    "first_prev_out": "0101010101010101010101010101010101010101010101010101010101010101:1",
+    "tag": "asset",
+    "meta_hash": "0102030000000000000000000000000000000000000000000000000000000000",
+    "output_index": 1,
+    "type": 1,
+    "asset_id": "09e6bed29918d63881148922c4487eabba7a133b5cb408b806da38d03f7bc50f"
+  },
+  "amount": "1",
+  "lock_time": "1337",
+  "relative_lock_time": "6",
+  "prev_witnesses": [
+    {
+      "prev_id": {
+        "out_point": "0202020202020202020202020202020202020202020202020202020202020202:2",
+        "asset_id": "0202020202020202020202020202020202020202020202020202020202020202",
+        "script_key": "03a0afeb165f0ec36880b68e0baabd9ad9c62fd1a69aa998bc30e9a346202e078f"
+      },
+      "tx_witness": [
+        "02",
+        "02"
+      ],
+      "split_commitment": null
+    }
+  ],
+  "split_commitment_root": {
+    "hash": "0101010101010101010101010101010101010101010101010101010101010101",
+    "sum": "1337"
+  },
+  "script_version": 1,
+  "script_key": "02a0afeb165f0ec36880b68e0baabd9ad9c62fd1a69aa998bc30e9a346202e078f",
+  "group_key": {
+    "group_pub_key": "03a0afeb165f0ec36880b68e0baabd9ad9c62fd1a69aa998bc30e9a346202e078f",
+    "sig": "e907831f80848d1069a5371b402410364bdf1c5f8307b0084c55f1ce2dca821525f66a4a85ea8b71e482a74f382d2ce5ebeee8fdb2172f477df4900d310536c0"
+  },
+  "non_divisible": false,
+  "edition": "0"
+}`
+
+// jsonTestAsset returns a deterministic asset to test the JSON encoding with.
+func jsonTestAsset() *Asset {
+	return &Asset{
+		Version: 1,
+		Genesis: Genesis{
+			FirstPrevOut: wire.OutPoint{
+				Hash:  hashBytes1,
+				Index: 1,
+			},
+			Tag:         "asset",
+			MetaHash:    [MetaHashLen]byte{1, 2, 3},
+			OutputIndex: 1,
+			Type:        Collectible,
+		},
+		Amount:           1,
+		LockTime:         1337,
+		RelativeLockTime: 6,
+		PrevWitnesses: []Witness{{
+			PrevID: &PrevID{
+				OutPoint: wire.OutPoint{
+					Hash:  hashBytes2,
+					Index: 2,
+				},
+				ID:        hashBytes2,
+				ScriptKey: ToSerialized(pubKey),
+			},
+			TxWitness: wire.TxWitness{{2}, {2}},
+		}},
+		SplitCommitmentRoot: mssmt.NewComputedNode(hashBytes1, 1337),
+		ScriptVersion:       1,
+		ScriptKey:           NewScriptKey(pubKey),
+		GroupKey: &GroupKey{
+			GroupPubKey: *pubKey,
+			Sig:         *sig,
+		},
+	}
+}
+
+// assertJSONRoundTrip asserts that the given asset survives a JSON round trip
+// and is encoded to the same TLV bytes afterwards.
+func assertJSONRoundTrip(t *testing.T, a *Asset) {
+	t.Helper()
+
+	jsonBytes, err := json.Marshal(a)
+	require.NoError(t, err)
+
+	var decoded Asset
+	require.NoError(t, json.Unmarshal(jsonBytes, &decoded))
+
+	var expected, actual bytes.Buffer
+	require.NoError(t, a.Encode(&expected))
+	require.NoError(t, decoded.Encode(&actual))
+	require.Equal(t, expected.Bytes(), actual.Bytes())
+
+	// Encoding the decoded asset again results in the same JSON.
+	reEncoded, err := json.Marshal(&decoded)
+	require.NoError(t, err)
+	require.JSONEq(t, string(jsonBytes), string(reEncoded))
+}
+
+// TestAssetJSON tests the JSON encoding of assets against a fixed vector and
+// asserts that assets survive a JSON round trip.
+func TestAssetJSON(t *testing.T) {
+	t.Parallel()
+
+	testAsset := jsonTestAsset()
+	jsonBytes, err := json.MarshalIndent(testAsset, "", "  ")
+	require.NoError(t, err)
+	require.Equal(t, expectedAssetJSON, string(jsonBytes))
+
+	// Encoding an asset value must result in the same JSON as encoding a
+	// pointer to it.
+	valueBytes, err := json.MarshalIndent(*testAsset, "", "  ")
+	require.NoError(t, err)
+	require.Equal(t, expectedAssetJSON, string(valueBytes))
+
+	assertJSONRoundTrip(t, testAsset)
+	assertJSONRoundTrip(t, RandAsset(t, Normal))
+	assertJSONRoundTrip(t, RandAsset(t, Collectible))
+	assertJSONRoundTrip(t, splitAsset(t, false))
+
+	witnessAsset := jsonTestAsset()
+	witnessAsset.GroupKey.Witness = wire.TxWitness{{1, 2, 3}}
+	witnessAsset.NonDivisible = true
+	witnessAsset.Edition = 7
+	assertJSONRoundTrip(t, witnessAsset)
+}
+
+// TestAssetJSONInvalid tests that invalid JSON encodings of assets are
+// rejected.
+func TestAssetJSONInvalid(t *testing.T) {
+	t.Parallel()
+
+	replace := func(old, new string) string {
+		require.Contains(t, expectedAssetJSON, old)
+		return string(bytes.Replace(
+			[]byte(expectedAssetJSON), []byte(old), []byte(new), 1,
+		))
+	}
+
+	testCases := []struct {
+		name        string
+		json        string
+		errContains string
+	}{{
+		name:        "asset ID mismatch",
+		json:        replace(`"tag": "asset"`, `"tag": "other"`),
+		errContains: "doesn't match genesis asset ID",
+	}, {
+		name:        "invalid outpoint",
+		json:        replace(`01:1"`, `01"`),
+		errContains: "invalid first_prev_out",
+	}, {
+		name:        "short meta hash",
+		json:        replace(`"0102030000`, `"01020300`),
+		errContains: "invalid meta_hash",
+	}, {
+		name: "invalid script key",
+		json: replace(
+			`"script_key": "02a0`, `"script_key": "05a0`,
+		),
+		errContains: "invalid script_key",
+	}, {
+		name:        "amount as number",
+		json:        replace(`"amount": "1"`, `"amount": 1`),
+		errContains: "cannot unmarshal",
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var a Asset
+			err := json.Unmarshal([]byte(tc.json), &a)
+			require.ErrorContains(t, err, tc.errContains)
+		})
+	}
+
+	// The genesis asset ID is optional when decoding.
+	var g Genesis
+	genesisJSON := `{"first_prev_out": "` + wire.OutPoint{}.String() +
+		`", "tag": "asset", "meta_hash": "` + hashBytes1String() + `"}`
+	require.NoError(t, json.Unmarshal([]byte(genesisJSON), &g))
+	require.Equal(t, "asset", g.Tag)
+	require.Equal(t, [MetaHashLen]byte(hashBytes1), g.MetaHash)
+}
+
+// hashBytes1String returns the hex encoding of hashBytes1.
+func hashBytes1String() string {
+	return ID(hashBytes1).String()
+}
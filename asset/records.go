@@ -31,6 +31,8 @@ const (
 	LeafScriptVersion       LeafTlvType = 8
 	LeafScriptKey           LeafTlvType = 9
 	LeafGroupKey            LeafTlvType = 10
+	LeafNonDivisible        LeafTlvType = 11
+	LeafEdition             LeafTlvType = 12
 )
 
 // WitnessTlvType represents the different TLV types for Asset Witness TLV
@@ -144,8 +146,25 @@ func NewLeafScriptKeyRecord(scriptKey **btcec.PublicKey) tlv.Record {
 }
 
 func NewLeafGroupKeyRecord(groupKey **GroupKey) tlv.Record {
-	const recordSize = btcec.PubKeyBytesLenCompressed + schnorr.SignatureSize
-	return tlv.MakeStaticRecord(
+	recordSize := func() uint64 {
+		size := uint64(
+			btcec.PubKeyBytesLenCompressed + schnorr.SignatureSize,
+		)
+		if len((*groupKey).Witness) > 0 {
+			var (
+				b   bytes.Buffer
+				buf [8]byte
+			)
+			witness := (*groupKey).Witness
+			err := TxWitnessEncoder(&b, &witness, &buf)
+			if err != nil {
+				panic(err)
+			}
+			size += uint64(b.Len())
+		}
+		return size
+	}
+	return tlv.MakeDynamicRecord(
 		LeafGroupKey, groupKey, recordSize, GroupKeyEncoder,
 		GroupKeyDecoder,
 	)
@@ -158,6 +177,21 @@ func NewLeafGroupKeyOnlyRecord(groupKey **btcec.PublicKey) tlv.Record {
 	)
 }
 
+func NewLeafNonDivisibleRecord(nonDivisible *bool) tlv.Record {
+	return tlv.MakeStaticRecord(
+		LeafNonDivisible, nonDivisible, 1, BoolEncoder, BoolDecoder,
+	)
+}
+
+func NewLeafEditionRecord(edition *uint64) tlv.Record {
+	recordSize := func() uint64 {
+		return tlv.VarIntSize(*edition)
+	}
+	return tlv.MakeDynamicRecord(
+		LeafEdition, edition, recordSize, VarIntEncoder, VarIntDecoder,
+	)
+}
+
 func NewWitnessPrevIDRecord(prevID **PrevID) tlv.Record {
 	const recordSize = 36 + sha256.Size + btcec.PubKeyBytesLenCompressed
 	return tlv.MakeStaticRecord(
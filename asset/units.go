@@ -0,0 +1,104 @@
+package asset
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MaxDecimalDisplay is the maximum number of decimal places an asset can be
+// displayed with. With 18 decimal places, at least one full display unit still
+// fits into the uint64 amount of an asset.
+const MaxDecimalDisplay = 18
+
+var (
+	// ErrInvalidDecimalDisplay is returned if the decimal display of an
+	// asset is larger than MaxDecimalDisplay.
+	ErrInvalidDecimalDisplay = fmt.Errorf("decimal display must be at "+
+		"most %d", MaxDecimalDisplay)
+
+	// ErrInvalidDisplayAmount is returned if a display amount can't be
+	// converted into base units of an asset.
+	ErrInvalidDisplayAmount = errors.New("invalid display amount")
+)
+
+// FormatUnits formats the given amount of base units of an asset for display,
+// using the decimal display of the asset. The formatted amount always has
+// exactly decimalDisplay fractional digits, so an amount of 150 units with a
+// decimal display of 2 is formatted as "1.50".
+func FormatUnits(units uint64, decimalDisplay uint32) (string, error) {
+	if decimalDisplay > MaxDecimalDisplay {
+		return "", ErrInvalidDecimalDisplay
+	}
+
+	digits := strconv.FormatUint(units, 10)
+	if decimalDisplay == 0 {
+		return digits, nil
+	}
+
+	// We pad the amount with leading zeroes, so there's at least one digit
+	// in front of the decimal point.
+	numDecimals := int(decimalDisplay)
+	if len(digits) <= numDecimals {
+		digits = strings.Repeat("0", numDecimals-len(digits)+1) +
+			digits
+	}
+
+	whole, fraction := digits[:len(digits)-numDecimals],
+		digits[len(digits)-numDecimals:]
+
+	return whole + "." + fraction, nil
+}
+
+// ParseUnits parses the given display amount of an asset into base units,
+// using the decimal display of the asset. The display amount may have at most
+// decimalDisplay fractional digits, so "1.5" is parsed into 150 units with a
+// decimal display of 2, while "1.505" is rejected.
+func ParseUnits(displayAmt string, decimalDisplay uint32) (uint64, error) {
+	if decimalDisplay > MaxDecimalDisplay {
+		return 0, ErrInvalidDecimalDisplay
+	}
+
+	whole, fraction, hasPoint := strings.Cut(displayAmt, ".")
+	switch {
+	case !isDigits(whole):
+		return 0, fmt.Errorf("%w: %q", ErrInvalidDisplayAmount,
+			displayAmt)
+
+	case hasPoint && !isDigits(fraction):
+		return 0, fmt.Errorf("%w: %q", ErrInvalidDisplayAmount,
+			displayAmt)
+
+	case len(fraction) > int(decimalDisplay):
+		return 0, fmt.Errorf("%w: %q has more than %d decimal places",
+			ErrInvalidDisplayAmount, displayAmt, decimalDisplay)
+	}
+
+	// With the fraction padded to the full number of decimal places, the
+	// concatenated digits are the amount in base units.
+	fraction += strings.Repeat("0", int(decimalDisplay)-len(fraction))
+	units, err := strconv.ParseUint(whole+fraction, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q is out of range",
+			ErrInvalidDisplayAmount, displayAmt)
+	}
+
+	return units, nil
+}
+
+// isDigits returns true if the given string is non-empty and only consists of
+// decimal digits.
+func isDigits(s string) bool {
+	if len(s) == 0 {
+		return false
+	}
+
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+
+	return true
+}
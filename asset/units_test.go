@@ -0,0 +1,135 @@
+package asset
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestFormatParseUnits tests the conversion between base units and display
+// units of an asset.
+func TestFormatParseUnits(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		units          uint64
+		decimalDisplay uint32
+		display        string
+	}{{
+		name:    "no decimals",
+		units:   1234,
+		display: "1234",
+	}, {
+		name:           "whole and fraction",
+		units:          150,
+		decimalDisplay: 2,
+		display:        "1.50",
+	}, {
+		name:           "fraction only",
+		units:          5,
+		decimalDisplay: 3,
+		display:        "0.005",
+	}, {
+		name:           "zero",
+		decimalDisplay: 6,
+		display:        "0.000000",
+	}, {
+		name:           "max amount",
+		units:          math.MaxUint64,
+		decimalDisplay: MaxDecimalDisplay,
+		display:        "18.446744073709551615",
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			display, err := FormatUnits(tc.units, tc.decimalDisplay)
+			require.NoError(t, err)
+			require.Equal(t, tc.display, display)
+
+			units, err := ParseUnits(display, tc.decimalDisplay)
+			require.NoError(t, err)
+			require.Equal(t, tc.units, units)
+		})
+	}
+}
+
+// TestParseUnits tests that display amounts are parsed into base units and
+// invalid display amounts are rejected.
+func TestParseUnits(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		display        string
+		decimalDisplay uint32
+		units          uint64
+		err            error
+	}{{
+		name:           "fewer decimals than display",
+		display:        "1.5",
+		decimalDisplay: 2,
+		units:          150,
+	}, {
+		name:           "whole amount",
+		display:        "42",
+		decimalDisplay: 3,
+		units:          42_000,
+	}, {
+		name:           "too many decimals",
+		display:        "1.505",
+		decimalDisplay: 2,
+		err:            ErrInvalidDisplayAmount,
+	}, {
+		name:    "decimals without decimal display",
+		display: "1.5",
+		err:     ErrInvalidDisplayAmount,
+	}, {
+		name:           "missing whole part",
+		display:        ".5",
+		decimalDisplay: 2,
+		err:            ErrInvalidDisplayAmount,
+	}, {
+		name:           "missing fraction",
+		display:        "1.",
+		decimalDisplay: 2,
+		err:            ErrInvalidDisplayAmount,
+	}, {
+		name:           "negative amount",
+		display:        "-1.5",
+		decimalDisplay: 2,
+		err:            ErrInvalidDisplayAmount,
+	}, {
+		name:           "overflow",
+		display:        "18446744073709551.616",
+		decimalDisplay: 3,
+		err:            ErrInvalidDisplayAmount,
+	}, {
+		name:           "invalid decimal display",
+		display:        "1",
+		decimalDisplay: MaxDecimalDisplay + 1,
+		err:            ErrInvalidDecimalDisplay,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			units, err := ParseUnits(tc.display, tc.decimalDisplay)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.units, units)
+		})
+	}
+}
@@ -55,6 +55,23 @@ func (l *LndRpcChainBridge) RegisterConfirmationsNtfn(ctx context.Context,
 	}, errChan, nil
 }
 
+// RegisterBlockEpochNtfn registers an intent to be notified of each new block
+// connected to the tip of the main chain. The height of the current tip is
+// sent immediately.
+func (l *LndRpcChainBridge) RegisterBlockEpochNtfn(
+	ctx context.Context) (chan int32, chan error, error) {
+
+	blockChan, errChan, err := l.lnd.ChainNotifier.RegisterBlockEpochNtfn(
+		ctx,
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to register for block "+
+			"epochs: %w", err)
+	}
+
+	return blockChan, errChan, nil
+}
+
 // GetBlock returns a chain block given its hash.
 func (l *LndRpcChainBridge) GetBlock(ctx context.Context,
 	hash chainhash.Hash) (*wire.MsgBlock, error) {
@@ -67,6 +84,20 @@ func (l *LndRpcChainBridge) GetBlock(ctx context.Context,
 	return block, nil
 }
 
+// GetBlockHash returns the hash of the block in the best chain at the given
+// height.
+func (l *LndRpcChainBridge) GetBlockHash(ctx context.Context,
+	blockHeight int64) (chainhash.Hash, error) {
+
+	blockHash, err := l.lnd.ChainKit.GetBlockHash(ctx, blockHeight)
+	if err != nil {
+		return chainhash.Hash{}, fmt.Errorf("unable to retrieve block "+
+			"hash: %w", err)
+	}
+
+	return blockHash, nil
+}
+
 // CurrentHeight return the current height of the main chain.
 func (l *LndRpcChainBridge) CurrentHeight(ctx context.Context) (uint32, error) {
 	info, err := l.lnd.Client.GetInfo(ctx)
@@ -3,6 +3,7 @@ package chanutils
 import (
 	"context"
 	"runtime"
+	"sync"
 
 	"golang.org/x/sync/errgroup"
 )
@@ -32,3 +33,170 @@ func ParSlice[V any](ctx context.Context, s []V, f ErrFunc[V]) error {
 
 	return errGroup.Wait()
 }
+
+// ResultFunc is a type def for a function that takes a context (to allow early
+// cancellation) and returns a typed result or an error. This is typically used
+// as a task submitted to an ErrGroupPool.
+type ResultFunc[R any] func(context.Context) (R, error)
+
+// poolTask is a single task queued in an ErrGroupPool along with the index its
+// result is stored at.
+type poolTask[R any] struct {
+	idx int
+	f   ResultFunc[R]
+}
+
+// ErrGroupPool is a pool of a bounded number of workers that execute the
+// tasks submitted to it concurrently and collect their typed results. Tasks
+// are queued in a bounded queue, so submitting a task blocks once the queue is
+// full. The context passed to the tasks is canceled the first time a task
+// returns a non-nil error, after which all remaining queued tasks are skipped.
+type ErrGroupPool[R any] struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	tasks chan poolTask[R]
+	wg    sync.WaitGroup
+
+	mu       sync.Mutex
+	numTasks int
+	results  []R
+	err      error
+}
+
+// NewErrGroupPool creates a new pool with the given number of workers and the
+// given size of the queue of pending tasks. A non-positive number of workers
+// results in one worker per CPU. The workers are stopped once Wait is called,
+// which must always happen eventually.
+func NewErrGroupPool[R any](ctx context.Context, numWorkers,
+	queueSize int) *ErrGroupPool[R] {
+
+	if numWorkers <= 0 {
+		numWorkers = runtime.NumCPU()
+	}
+	if queueSize < 0 {
+		queueSize = 0
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	p := &ErrGroupPool[R]{
+		ctx:    ctx,
+		cancel: cancel,
+		tasks:  make(chan poolTask[R], queueSize),
+	}
+
+	p.wg.Add(numWorkers)
+	for i := 0; i < numWorkers; i++ {
+		go p.worker()
+	}
+
+	return p
+}
+
+// worker executes the queued tasks until the queue is closed.
+func (p *ErrGroupPool[R]) worker() {
+	defer p.wg.Done()
+
+	for task := range p.tasks {
+		// Once the context is canceled, either by a failed task or by
+		// the caller, we only drain the queue.
+		if err := p.ctx.Err(); err != nil {
+			p.setErr(err)
+			continue
+		}
+
+		result, err := task.f(p.ctx)
+		if err != nil {
+			p.setErr(err)
+			continue
+		}
+
+		p.mu.Lock()
+		p.results[task.idx] = result
+		p.mu.Unlock()
+	}
+}
+
+// setErr records the given error if it's the first one and cancels the
+// context passed to all tasks.
+func (p *ErrGroupPool[R]) setErr(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.err == nil {
+		p.err = err
+		p.cancel()
+	}
+}
+
+// Go submits a new task to the pool, blocking while the queue is full. An
+// error is returned if the context of the pool is canceled before the task
+// could be queued, in which case Wait returns the cause of the cancellation.
+//
+// NOTE: Go must not be called after Wait.
+func (p *ErrGroupPool[R]) Go(f ResultFunc[R]) error {
+	// We check the context first, as otherwise the select below picks a
+	// random case if a worker is ready to receive as well.
+	if err := p.ctx.Err(); err != nil {
+		p.setErr(err)
+		return err
+	}
+
+	p.mu.Lock()
+	idx := p.numTasks
+	p.numTasks++
+
+	var emptyResult R
+	p.results = append(p.results, emptyResult)
+	p.mu.Unlock()
+
+	select {
+	case p.tasks <- poolTask[R]{idx: idx, f: f}:
+		return nil
+
+	case <-p.ctx.Done():
+		p.setErr(p.ctx.Err())
+		return p.ctx.Err()
+	}
+}
+
+// Wait waits for all submitted tasks to be executed and stops the workers of
+// the pool. The results are returned in the order the tasks were submitted
+// in. If any task failed or the context of the pool was canceled before all
+// tasks were executed, the first error encountered is returned instead.
+func (p *ErrGroupPool[R]) Wait() ([]R, error) {
+	close(p.tasks)
+	p.wg.Wait()
+	p.cancel()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	return p.results, nil
+}
+
+// ParSliceResults can be used to execute a function on each element of a
+// slice in parallel and collect the typed results, which are returned in the
+// order of the slice elements. Active goroutines are limited to the number of
+// CPUs. Context will be passed in executable func and canceled the first time
+// a function passed returns a non-nil error, which is then returned.
+func ParSliceResults[V, R any](ctx context.Context, s []V,
+	f func(context.Context, V) (R, error)) ([]R, error) {
+
+	pool := NewErrGroupPool[R](ctx, runtime.NumCPU(), 0)
+	for _, v := range s {
+		v := v
+		err := pool.Go(func(ctx context.Context) (R, error) {
+			return f(ctx, v)
+		})
+		if err != nil {
+			break
+		}
+	}
+
+	return pool.Wait()
+}
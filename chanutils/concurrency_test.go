@@ -3,7 +3,11 @@ package chanutils
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -57,3 +61,136 @@ func TestParSlice(t *testing.T) {
 		})
 	}
 }
+
+// TestErrGroupPool tests that the worker pool executes all tasks with a
+// bounded number of workers and returns their results in order.
+func TestErrGroupPool(t *testing.T) {
+	t.Parallel()
+
+	const (
+		numWorkers = 3
+		numTasks   = 50
+	)
+
+	pool := NewErrGroupPool[int](context.Background(), numWorkers, 1)
+
+	var (
+		numActive atomic.Int32
+		maxActive int32
+		maxMtx    sync.Mutex
+	)
+	for i := 0; i < numTasks; i++ {
+		i := i
+		err := pool.Go(func(ctx context.Context) (int, error) {
+			active := numActive.Add(1)
+			defer numActive.Add(-1)
+
+			maxMtx.Lock()
+			if active > maxActive {
+				maxActive = active
+			}
+			maxMtx.Unlock()
+
+			time.Sleep(time.Millisecond)
+
+			return i * 2, nil
+		})
+		require.NoError(t, err)
+	}
+
+	results, err := pool.Wait()
+	require.NoError(t, err)
+	require.Len(t, results, numTasks)
+	for i, result := range results {
+		require.Equal(t, i*2, result)
+	}
+
+	require.LessOrEqual(t, maxActive, int32(numWorkers))
+}
+
+// TestErrGroupPoolCancel tests that the first error of a task or the
+// cancellation of the context stops the worker pool.
+func TestErrGroupPoolCancel(t *testing.T) {
+	t.Parallel()
+
+	// The first failed task cancels the context of all other tasks, and
+	// its error is returned.
+	errTask := errors.New("task failed")
+	pool := NewErrGroupPool[int](context.Background(), 2, 10)
+
+	var numExecuted atomic.Int32
+	require.NoError(t, pool.Go(func(ctx context.Context) (int, error) {
+		return 0, errTask
+	}))
+	for i := 0; i < 5; i++ {
+		err := pool.Go(func(ctx context.Context) (int, error) {
+			numExecuted.Add(1)
+			<-ctx.Done()
+			return 0, ctx.Err()
+		})
+		require.NoError(t, err)
+	}
+
+	results, err := pool.Wait()
+	require.ErrorIs(t, err, errTask)
+	require.Nil(t, results)
+
+	// Canceling the context of the pool skips all tasks that haven't been
+	// executed yet, and submitting new tasks fails.
+	ctx, cancel := context.WithCancel(context.Background())
+	pool = NewErrGroupPool[int](ctx, 1, 0)
+
+	started := make(chan struct{})
+	require.NoError(t, pool.Go(func(ctx context.Context) (int, error) {
+		close(started)
+		<-ctx.Done()
+		return 0, nil
+	}))
+	<-started
+	cancel()
+
+	err = pool.Go(func(ctx context.Context) (int, error) {
+		t.Fatalf("task must not be executed")
+		return 0, nil
+	})
+	require.ErrorIs(t, err, context.Canceled)
+
+	_, err = pool.Wait()
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+// TestParSliceResults tests that the results of a function executed on each
+// element of a slice in parallel are returned in order.
+func TestParSliceResults(t *testing.T) {
+	t.Parallel()
+
+	values := make([]int, 100)
+	for i := range values {
+		values[i] = i
+	}
+
+	results, err := ParSliceResults(
+		context.Background(), values,
+		func(_ context.Context, v int) (string, error) {
+			return fmt.Sprintf("%d", v), nil
+		},
+	)
+	require.NoError(t, err)
+	require.Len(t, results, len(values))
+	for i, result := range results {
+		require.Equal(t, fmt.Sprintf("%d", i), result)
+	}
+
+	errValue := errors.New("invalid value")
+	_, err = ParSliceResults(
+		context.Background(), values,
+		func(_ context.Context, v int) (string, error) {
+			if v == 42 {
+				return "", errValue
+			}
+
+			return "", nil
+		},
+	)
+	require.ErrorIs(t, err, errValue)
+}
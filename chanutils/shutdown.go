@@ -0,0 +1,192 @@
+package chanutils
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrStopTimeout is returned if a subsystem didn't stop within its deadline.
+var ErrStopTimeout = errors.New("subsystem didn't stop in time")
+
+// StopFunc is a function that stops a subsystem.
+type StopFunc func() error
+
+// shutdownTarget is a single subsystem registered with a shutdown
+// coordinator.
+type shutdownTarget struct {
+	name      string
+	stop      StopFunc
+	timeout   time.Duration
+	dependsOn []string
+}
+
+// ShutdownError is returned by the shutdown coordinator if one or more
+// subsystems failed to stop cleanly or within their deadline.
+type ShutdownError struct {
+	// Errs maps the name of each subsystem that failed to stop to the
+	// reason it failed.
+	Errs map[string]error
+}
+
+// Error returns the error string of the shutdown error, listing all failed
+// subsystems in alphabetical order.
+func (s *ShutdownError) Error() string {
+	names := make([]string, 0, len(s.Errs))
+	for name := range s.Errs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	reasons := make([]string, 0, len(names))
+	for _, name := range names {
+		reasons = append(
+			reasons, fmt.Sprintf("%v: %v", name, s.Errs[name]),
+		)
+	}
+
+	return fmt.Sprintf("unable to stop subsystems: %v",
+		strings.Join(reasons, ", "))
+}
+
+// ShutdownCoordinator stops a set of subsystems in dependency order. A
+// subsystem is only stopped once all subsystems that depend on it have
+// stopped, so a subsystem never observes one of its dependencies going away
+// underneath it. Subsystems that don't depend on each other are stopped
+// concurrently. Each subsystem is given a deadline to stop within, so a single
+// wedged subsystem can't block the shutdown of the whole daemon.
+type ShutdownCoordinator struct {
+	defaultTimeout time.Duration
+
+	mu      sync.Mutex
+	targets []*shutdownTarget
+	names   map[string]struct{}
+}
+
+// NewShutdownCoordinator creates a new shutdown coordinator that gives each
+// subsystem the given default timeout to stop within.
+func NewShutdownCoordinator(
+	defaultTimeout time.Duration) *ShutdownCoordinator {
+
+	return &ShutdownCoordinator{
+		defaultTimeout: defaultTimeout,
+		names:          make(map[string]struct{}),
+	}
+}
+
+// Register adds a subsystem with the given name and stop function to the
+// coordinator. The subsystem is stopped before all the subsystems it depends
+// on, which must already be registered. A timeout of zero means the default
+// timeout of the coordinator is used.
+func (s *ShutdownCoordinator) Register(name string, stop StopFunc,
+	timeout time.Duration, dependsOn ...string) error {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.names[name]; ok {
+		return fmt.Errorf("subsystem %v already registered", name)
+	}
+	for _, dep := range dependsOn {
+		if _, ok := s.names[dep]; !ok {
+			return fmt.Errorf("subsystem %v depends on unknown "+
+				"subsystem %v", name, dep)
+		}
+	}
+
+	if timeout == 0 {
+		timeout = s.defaultTimeout
+	}
+
+	s.names[name] = struct{}{}
+	s.targets = append(s.targets, &shutdownTarget{
+		name:      name,
+		stop:      stop,
+		timeout:   timeout,
+		dependsOn: dependsOn,
+	})
+
+	return nil
+}
+
+// Stop stops all registered subsystems in dependency order and blocks until
+// each of them either stopped or exceeded its deadline. A subsystem that
+// exceeds its deadline is treated as stopped, so its dependencies are still
+// stopped afterwards. If any subsystem failed to stop, a *ShutdownError is
+// returned.
+func (s *ShutdownCoordinator) Stop() error {
+	s.mu.Lock()
+	targets := make([]*shutdownTarget, len(s.targets))
+	copy(targets, s.targets)
+	s.mu.Unlock()
+
+	// Each subsystem waits for all subsystems that depend on it, which
+	// we track by the channel that is closed once a subsystem is done.
+	done := make(map[string]chan struct{}, len(targets))
+	dependents := make(map[string][]string, len(targets))
+	for _, target := range targets {
+		done[target.name] = make(chan struct{})
+		for _, dep := range target.dependsOn {
+			dependents[dep] = append(dependents[dep], target.name)
+		}
+	}
+
+	var (
+		wg     sync.WaitGroup
+		errMtx sync.Mutex
+		errs   = make(map[string]error)
+	)
+	for _, target := range targets {
+		target := target
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer close(done[target.name])
+
+			for _, dependent := range dependents[target.name] {
+				<-done[dependent]
+			}
+
+			if err := stopWithTimeout(target); err != nil {
+				errMtx.Lock()
+				errs[target.name] = err
+				errMtx.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return &ShutdownError{
+			Errs: errs,
+		}
+	}
+
+	return nil
+}
+
+// stopWithTimeout calls the stop function of the given subsystem and waits
+// for it to return for at most the subsystem's timeout.
+func stopWithTimeout(target *shutdownTarget) error {
+	// The channel is buffered, so the goroutine can exit even if we
+	// already gave up waiting for it.
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- target.stop()
+	}()
+
+	timer := time.NewTimer(target.timeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-errChan:
+		return err
+
+	case <-timer.C:
+		return fmt.Errorf("%w after %v", ErrStopTimeout, target.timeout)
+	}
+}
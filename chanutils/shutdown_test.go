@@ -0,0 +1,90 @@
+package chanutils
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestShutdownCoordinatorOrder tests that subsystems are only stopped after
+// all subsystems that depend on them.
+func TestShutdownCoordinatorOrder(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu      sync.Mutex
+		stopped []string
+	)
+	stopFunc := func(name string) StopFunc {
+		return func() error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			stopped = append(stopped, name)
+			return nil
+		}
+	}
+
+	c := NewShutdownCoordinator(time.Second)
+	require.NoError(t, c.Register("db", stopFunc("db"), 0))
+	require.NoError(t, c.Register("chain", stopFunc("chain"), 0))
+	require.NoError(t, c.Register(
+		"minter", stopFunc("minter"), 0, "db", "chain",
+	))
+	require.NoError(t, c.Register("porter", stopFunc("porter"), 0, "db"))
+	require.NoError(t, c.Register(
+		"rpc", stopFunc("rpc"), 0, "minter", "porter",
+	))
+
+	// Registering a subsystem twice or before its dependencies fails.
+	require.Error(t, c.Register("db", stopFunc("db"), 0))
+	require.Error(t, c.Register("sync", stopFunc("sync"), 0, "unknown"))
+
+	require.NoError(t, c.Stop())
+	require.Len(t, stopped, 5)
+
+	index := make(map[string]int, len(stopped))
+	for i, name := range stopped {
+		index[name] = i
+	}
+	require.Equal(t, 0, index["rpc"])
+	require.Less(t, index["minter"], index["db"])
+	require.Less(t, index["minter"], index["chain"])
+	require.Less(t, index["porter"], index["db"])
+}
+
+// TestShutdownCoordinatorErrors tests that failing and wedged subsystems are
+// reported, without preventing the remaining subsystems from being stopped.
+func TestShutdownCoordinatorErrors(t *testing.T) {
+	t.Parallel()
+
+	errFail := errors.New("fail")
+	wedged := make(chan struct{})
+	defer close(wedged)
+
+	var dbStopped bool
+	c := NewShutdownCoordinator(time.Second)
+	require.NoError(t, c.Register("db", func() error {
+		dbStopped = true
+		return nil
+	}, 0))
+	require.NoError(t, c.Register("minter", func() error {
+		return errFail
+	}, 0, "db"))
+	require.NoError(t, c.Register("porter", func() error {
+		<-wedged
+		return nil
+	}, 10*time.Millisecond, "db"))
+
+	err := c.Stop()
+	require.True(t, dbStopped)
+
+	var shutdownErr *ShutdownError
+	require.ErrorAs(t, err, &shutdownErr)
+	require.Len(t, shutdownErr.Errs, 2)
+	require.ErrorIs(t, shutdownErr.Errs["minter"], errFail)
+	require.ErrorIs(t, shutdownErr.Errs["porter"], ErrStopTimeout)
+}
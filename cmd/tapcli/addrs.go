@@ -1,11 +1,14 @@
 package main
 
 import (
+	"context"
 	"encoding/hex"
 	"fmt"
 	"math"
 	"time"
 
+	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightninglabs/taproot-assets/proof"
 	"github.com/lightninglabs/taproot-assets/taprpc"
 	"github.com/urfave/cli"
 )
@@ -21,6 +24,7 @@ var addrCommands = []cli.Command{
 			queryAddrsCommand,
 			decodeAddrCommand,
 			receivesAddrCommand,
+			pruneAddrsCommand,
 		},
 	},
 }
@@ -29,6 +33,18 @@ const (
 	groupKeyName = "group_key"
 
 	amtName = "amt"
+
+	displayAmtName = "display_amt"
+
+	proofCourierAddrName = "proof_courier_addr"
+
+	staticName = "static"
+
+	anyAssetInGroupName = "any_asset_in_group"
+
+	expiryName = "expiry"
+
+	singleUseName = "single_use"
 )
 
 var newAddrCommand = cli.Command{
@@ -43,13 +59,77 @@ var newAddrCommand = cli.Command{
 			Usage: "the asset genesis ID of the asset to receive",
 		},
 		cli.Uint64Flag{
-			Name:  amtName,
-			Usage: "the amt of the asset to receive",
+			Name: amtName,
+			Usage: "the amt of the asset to receive; if unset, " +
+				"the address doesn't specify an amount and " +
+				"the sender chooses how much to send",
+		},
+		cli.StringFlag{
+			Name: displayAmtName,
+			Usage: "the amt of the asset to receive in display " +
+				"units (e.g. 1.5), using the decimal display " +
+				"of the asset; mutually exclusive with --amt",
+		},
+		cli.StringFlag{
+			Name: proofCourierAddrName,
+			Usage: "the optional proof courier address (e.g. " +
+				"hashmail://host:port) the sender should use " +
+				"to deliver the proof; if unset, the " +
+				"daemon's default courier is used",
+		},
+		cli.BoolFlag{
+			Name: staticName,
+			Usage: "create a static address that can be used to " +
+				"receive any number of transfers",
+		},
+		cli.BoolFlag{
+			Name: anyAssetInGroupName,
+			Usage: "create an address that accepts any asset of " +
+				"the group of the given asset",
+		},
+		cli.DurationFlag{
+			Name: expiryName,
+			Usage: "the duration after which the address no " +
+				"longer accepts any transfers (e.g. 24h); if " +
+				"unset, the address never expires",
+		},
+		cli.BoolFlag{
+			Name: singleUseName,
+			Usage: "create an address that only accepts a single " +
+				"transfer; cannot be combined with --static",
 		},
 	},
 	Action: newAddr,
 }
 
+// parseDisplayAmt converts the given display amount of an asset into base
+// units, using the decimal display of the asset's meta data.
+func parseDisplayAmt(ctxc context.Context, client taprpc.TaprootAssetsClient,
+	assetID []byte, displayAmt string) (uint64, error) {
+
+	rpcMeta, err := client.FetchAssetMeta(
+		ctxc, &taprpc.FetchAssetMetaRequest{
+			Asset: &taprpc.FetchAssetMetaRequest_AssetId{
+				AssetId: assetID,
+			},
+		},
+	)
+	if err != nil {
+		return 0, fmt.Errorf("unable to fetch asset meta: %w", err)
+	}
+
+	meta := &proof.MetaReveal{
+		Type: proof.MetaType(rpcMeta.Type),
+		Data: rpcMeta.Data,
+	}
+	decimalDisplay, err := meta.DecimalDisplay()
+	if err != nil {
+		return 0, err
+	}
+
+	return asset.ParseUnits(displayAmt, decimalDisplay)
+}
+
 func newAddr(ctx *cli.Context) error {
 	switch {
 	case ctx.String(assetIDName) == "":
@@ -65,9 +145,38 @@ func newAddr(ctx *cli.Context) error {
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
+	amt := ctx.Uint64(amtName)
+	if ctx.IsSet(displayAmtName) {
+		if ctx.IsSet(amtName) {
+			return fmt.Errorf("only one of --%s and --%s can be set",
+				amtName, displayAmtName)
+		}
+
+		amt, err = parseDisplayAmt(
+			ctxc, client, assetID, ctx.String(displayAmtName),
+		)
+		if err != nil {
+			return fmt.Errorf("invalid display amt: %w", err)
+		}
+	}
+
+	var expiry uint64
+	if ctx.IsSet(expiryName) {
+		if ctx.Duration(expiryName) <= 0 {
+			return fmt.Errorf("--%s must be positive", expiryName)
+		}
+
+		expiry = uint64(time.Now().Add(ctx.Duration(expiryName)).Unix())
+	}
+
 	addr, err := client.NewAddr(ctxc, &taprpc.NewAddrRequest{
-		AssetId: assetID,
-		Amt:     ctx.Uint64(amtName),
+		AssetId:           assetID,
+		Amt:               amt,
+		ProofCourierAddr:  ctx.String(proofCourierAddrName),
+		Static:            ctx.Bool(staticName),
+		AnyAssetInGroup:   ctx.Bool(anyAssetInGroupName),
+		ExpiryUnixSeconds: expiry,
+		SingleUse:         ctx.Bool(singleUseName),
 	})
 	if err != nil {
 		return fmt.Errorf("unable to make addr: %w", err)
@@ -235,3 +344,28 @@ func addrReceives(ctx *cli.Context) error {
 	printRespJSON(resp)
 	return nil
 }
+
+var pruneAddrsCommand = cli.Command{
+	Name:  "prune",
+	Usage: "Remove all addresses that can no longer receive transfers",
+	Description: `
+	Remove all addresses that either expired or are single-use and already
+	received their transfer, together with their receive events. Addresses
+	that still have a pending receive are kept.
+	`,
+	Action: pruneAddrs,
+}
+
+func pruneAddrs(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.PruneAddrs(ctxc, &taprpc.PruneAddrsRequest{})
+	if err != nil {
+		return fmt.Errorf("unable to prune addrs: %w", err)
+	}
+
+	printRespJSON(resp)
+	return nil
+}
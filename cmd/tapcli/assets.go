@@ -1,10 +1,14 @@
 package main
 
 import (
+	"encoding/base64"
 	"encoding/hex"
 	"fmt"
 	"io/ioutil"
+	"math"
+	"strings"
 
+	tap "github.com/lightninglabs/taproot-assets"
 	"github.com/lightninglabs/taproot-assets/tapcfg"
 	"github.com/lightninglabs/taproot-assets/taprpc"
 	"github.com/lightninglabs/taproot-assets/taprpc/mintrpc"
@@ -24,8 +28,10 @@ var assetsCommands = []cli.Command{
 			listGroupsCommand,
 			listAssetBalancesCommand,
 			sendAssetsCommand,
+			burnAssetsCommand,
 			listTransfersCommand,
 			fetchMetaCommand,
+			fetchGroupMetaHistoryCommand,
 		},
 	},
 }
@@ -38,6 +44,8 @@ var (
 	assetMetaFilePathName = "meta_file_path"
 	assetMetaTypeName     = "meta_type"
 	assetEmissionName     = "enable_emission"
+	decimalDisplayName    = "decimal_display"
+	assetEditionsName     = "editions"
 	assetShowWitnessName  = "show_witness"
 	assetShowSpentName    = "show_spent"
 	assetGroupKeyName     = "group_key"
@@ -45,8 +53,64 @@ var (
 	batchKeyName          = "batch_key"
 	groupByGroupName      = "by_group"
 	assetIDName           = "asset_id"
+	batchFeeRateName      = "sat_per_vbyte"
+	batchTapSiblingName   = "tapscript_sibling"
+	batchOutpointName     = "outpoint"
+	batchAccountName      = "account"
+	burnAmountName        = "amount"
+	burnForceName         = "force"
+	coinSelectName        = "coin_select_strategy"
+	sendFeeRateName       = "sat_per_vbyte"
+	sendConfTargetName    = "conf_target"
+	sendMaxFeeName        = "max_fee"
+	sendForceStrandName   = "force_strand_passive_assets"
+	sendEditionName       = "edition"
+	scriptKeyTypeName     = "script_key_type"
+	minAmountName         = "min_amount"
+	anchorOutpointName    = "anchor_outpoint"
+	assetSpentOnlyName    = "spent_only"
+	sortByAmountName      = "sort_by_amount"
+	sortDescendingName    = "descending"
+	pageCursorName        = "cursor"
 )
 
+// pageFlags are the flags of listing commands that return their results in
+// pages.
+var pageFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  sortDescendingName,
+		Usage: "sort the results in descending order",
+	},
+	cli.Uint64Flag{
+		Name: limitName,
+		Usage: "the maximum number of results to return; if not set, " +
+			"all results are returned",
+	},
+	cli.StringFlag{
+		Name: pageCursorName,
+		Usage: "the next_cursor returned with the previous page, to " +
+			"fetch the next page",
+	},
+}
+
+// parsePageFlags parses the page flags of a listing command.
+func parsePageFlags(ctx *cli.Context) (uint32, []byte, error) {
+	limit := ctx.Uint64(limitName)
+	if limit > math.MaxUint32 {
+		return 0, nil, fmt.Errorf("limit must not exceed %d",
+			uint32(math.MaxUint32))
+	}
+
+	cursor, err := base64.StdEncoding.DecodeString(
+		ctx.String(pageCursorName),
+	)
+	if err != nil {
+		return 0, nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	return uint32(limit), cursor, nil
+}
+
 var mintAssetCommand = cli.Command{
 	Name:        "mint",
 	ShortName:   "m",
@@ -83,6 +147,18 @@ var mintAssetCommand = cli.Command{
 			Usage: "if true, then the asset supports on going " +
 				"emission",
 		},
+		cli.UintFlag{
+			Name: decimalDisplayName,
+			Usage: "the number of decimal places amounts of a " +
+				"normal asset are displayed with, which is " +
+				"added to the asset's JSON meta data",
+		},
+		cli.BoolFlag{
+			Name: assetEditionsName,
+			Usage: "if true, the collectible is minted as a " +
+				"series of numbered editions, with the " +
+				"supply being the number of editions",
+		},
 		cli.StringFlag{
 			Name:  assetGroupKeyName,
 			Usage: "the specific group key to use to mint the asset",
@@ -91,6 +167,12 @@ var mintAssetCommand = cli.Command{
 			Name:  assetGroupAnchorName,
 			Usage: "the other asset in this batch that the new asset be grouped with",
 		},
+		cli.StringFlag{
+			Name: batchKeyName,
+			Usage: "if set, the key of the pending batch to add " +
+				"the asset to, which is created with the key " +
+				"as its internal key if it doesn't exist yet",
+		},
 	},
 	Action: mintAsset,
 	Subcommands: []cli.Command{
@@ -119,8 +201,10 @@ func mintAsset(ctx *cli.Context) error {
 
 	var (
 		groupKey    []byte
+		batchKey    []byte
 		err         error
 		groupKeyStr = ctx.String(assetGroupKeyName)
+		batchKeyStr = ctx.String(batchKeyName)
 	)
 
 	if len(groupKeyStr) != 0 {
@@ -130,6 +214,13 @@ func mintAsset(ctx *cli.Context) error {
 		}
 	}
 
+	if len(batchKeyStr) != 0 {
+		batchKey, err = hex.DecodeString(batchKeyStr)
+		if err != nil {
+			return fmt.Errorf("invalid batch key")
+		}
+	}
+
 	// Both the meta bytes and the meta path can be set.
 	var assetMeta *taprpc.AssetMeta
 	switch {
@@ -171,8 +262,13 @@ func mintAsset(ctx *cli.Context) error {
 			Amount:      ctx.Uint64(assetSupplyName),
 			GroupKey:    groupKey,
 			GroupAnchor: ctx.String(assetGroupAnchorName),
+			DecimalDisplay: uint32(
+				ctx.Uint(decimalDisplayName),
+			),
+			Editions: ctx.Bool(assetEditionsName),
 		},
 		EnableEmission: ctx.Bool(assetEmissionName),
+		BatchKey:       batchKey,
 	})
 	if err != nil {
 		return fmt.Errorf("unable to mint asset: %w", err)
@@ -187,7 +283,35 @@ var finalizeBatchCommand = cli.Command{
 	ShortName:   "f",
 	Usage:       "finalize a batch",
 	Description: "Attempt to finalize a pending batch.",
-	Action:      finalizeBatch,
+	Flags: []cli.Flag{
+		cli.Uint64Flag{
+			Name: batchFeeRateName,
+			Usage: "if set, the fee rate in sat/vB to use " +
+				"for the minting transaction instead of " +
+				"an estimate",
+		},
+		cli.StringFlag{
+			Name: batchTapSiblingName,
+			Usage: "if set, the hex encoded tapscript sibling " +
+				"preimage to commit to alongside the " +
+				"Taproot Asset commitment in the minting " +
+				"output",
+		},
+		cli.StringSliceFlag{
+			Name: batchOutpointName,
+			Usage: "if set, a wallet UTXO (txid:vout) to fund " +
+				"the minting transaction with; can be " +
+				"specified multiple times, and no other " +
+				"UTXOs are spent",
+		},
+		cli.StringFlag{
+			Name: batchAccountName,
+			Usage: "if set, the name of the wallet account that " +
+				"funds the minting transaction and receives " +
+				"its change",
+		},
+	},
+	Action: finalizeBatch,
 }
 
 func finalizeBatch(ctx *cli.Context) error {
@@ -195,7 +319,24 @@ func finalizeBatch(ctx *cli.Context) error {
 	client, cleanUp := getMintClient(ctx)
 	defer cleanUp()
 
-	resp, err := client.FinalizeBatch(ctxc, &mintrpc.FinalizeBatchRequest{})
+	var (
+		tapSibling    []byte
+		tapSiblingStr = ctx.String(batchTapSiblingName)
+	)
+	if len(tapSiblingStr) != 0 {
+		var err error
+		tapSibling, err = hex.DecodeString(tapSiblingStr)
+		if err != nil {
+			return fmt.Errorf("invalid tapscript sibling")
+		}
+	}
+
+	resp, err := client.FinalizeBatch(ctxc, &mintrpc.FinalizeBatchRequest{
+		SatPerVbyte:      ctx.Uint64(batchFeeRateName),
+		TapscriptSibling: tapSibling,
+		Outpoints:        ctx.StringSlice(batchOutpointName),
+		Account:          ctx.String(batchAccountName),
+	})
 	if err != nil {
 		return fmt.Errorf("unable to finalize batch: %w", err)
 	}
@@ -273,7 +414,7 @@ var listAssetsCommand = cli.Command{
 	ShortName:   "l",
 	Usage:       "list all assets",
 	Description: "list all pending and mined assets",
-	Flags: []cli.Flag{
+	Flags: append([]cli.Flag{
 		cli.BoolFlag{
 			Name:  assetShowWitnessName,
 			Usage: "include the asset's witness data",
@@ -282,7 +423,44 @@ var listAssetsCommand = cli.Command{
 			Name:  assetShowSpentName,
 			Usage: "include fully spent assets in the list",
 		},
-	},
+		cli.BoolFlag{
+			Name:  assetSpentOnlyName,
+			Usage: "only list fully spent assets",
+		},
+		cli.StringFlag{
+			Name:  assetIDName,
+			Usage: "only list the assets with this asset ID",
+		},
+		cli.StringFlag{
+			Name: assetGroupKeyName,
+			Usage: "only list the assets of the group with this " +
+				"tweaked group key",
+		},
+		cli.StringFlag{
+			Name:  scriptKeyName,
+			Usage: "only list the assets with this script key",
+		},
+		cli.StringFlag{
+			Name: scriptKeyTypeName,
+			Usage: "only list the assets with this type of " +
+				"script key, must either be: bip86, or " +
+				"script_path",
+		},
+		cli.Uint64Flag{
+			Name:  minAmountName,
+			Usage: "only list the assets with at least this amount",
+		},
+		cli.StringFlag{
+			Name: anchorOutpointName,
+			Usage: "only list the assets anchored at this " +
+				"outpoint (txid:vout)",
+		},
+		cli.BoolFlag{
+			Name: sortByAmountName,
+			Usage: "sort the assets by their amount instead of " +
+				"the order they were created in",
+		},
+	}, pageFlags...),
 	Action: listAssets,
 }
 
@@ -293,9 +471,54 @@ func listAssets(ctx *cli.Context) error {
 
 	// TODO(roasbeef): need to reverse txid
 
+	assetID, err := hex.DecodeString(ctx.String(assetIDName))
+	if err != nil {
+		return fmt.Errorf("invalid asset ID: %w", err)
+	}
+	groupKey, err := hex.DecodeString(ctx.String(assetGroupKeyName))
+	if err != nil {
+		return fmt.Errorf("invalid group key: %w", err)
+	}
+	scriptKey, err := hex.DecodeString(ctx.String(scriptKeyName))
+	if err != nil {
+		return fmt.Errorf("invalid script key: %w", err)
+	}
+
+	var scriptKeyType taprpc.ScriptKeyType
+	switch ctx.String(scriptKeyTypeName) {
+	case "":
+		scriptKeyType = taprpc.ScriptKeyType_SCRIPT_KEY_TYPE_ANY
+
+	case "bip86":
+		scriptKeyType = taprpc.ScriptKeyType_SCRIPT_KEY_TYPE_BIP86
+
+	case "script_path":
+		scriptKeyType = taprpc.ScriptKeyType_SCRIPT_KEY_TYPE_SCRIPT_PATH
+
+	default:
+		return fmt.Errorf("unknown script key type: %v",
+			ctx.String(scriptKeyTypeName))
+	}
+
+	limit, cursor, err := parsePageFlags(ctx)
+	if err != nil {
+		return err
+	}
+
 	resp, err := client.ListAssets(ctxc, &taprpc.ListAssetRequest{
-		WithWitness:  ctx.Bool(assetShowWitnessName),
-		IncludeSpent: ctx.Bool(assetShowSpentName),
+		WithWitness:    ctx.Bool(assetShowWitnessName),
+		IncludeSpent:   ctx.Bool(assetShowSpentName),
+		AssetId:        assetID,
+		GroupKey:       groupKey,
+		ScriptKey:      scriptKey,
+		ScriptKeyType:  scriptKeyType,
+		MinAmount:      ctx.Uint64(minAmountName),
+		AnchorOutpoint: ctx.String(anchorOutpointName),
+		SpentOnly:      ctx.Bool(assetSpentOnlyName),
+		SortByAmount:   ctx.Bool(sortByAmountName),
+		SortDescending: ctx.Bool(sortDescendingName),
+		Limit:          limit,
+		Cursor:         cursor,
 	})
 	if err != nil {
 		return fmt.Errorf("unable to list assets: %w", err)
@@ -428,6 +651,48 @@ var sendAssetsCommand = cli.Command{
 			Usage: "addr to send to; can be specified multiple " +
 				"times to send to multiple addresses at once",
 		},
+		cli.StringFlag{
+			Name: coinSelectName,
+			Usage: "the strategy used to select the assets to " +
+				"spend, must either be: max-amount, " +
+				"min-amount, single-coin or random; if " +
+				"not set, the daemon's default strategy is " +
+				"used",
+		},
+		cli.Uint64Flag{
+			Name: sendFeeRateName,
+			Usage: "if set, the fee rate in sat/vB to use for " +
+				"the anchor transaction instead of an estimate",
+		},
+		cli.Uint64Flag{
+			Name: sendConfTargetName,
+			Usage: "if set, the confirmation target to use for " +
+				"estimating the fee rate of the anchor " +
+				"transaction",
+		},
+		cli.Uint64Flag{
+			Name: sendMaxFeeName,
+			Usage: "if set, the maximum amount of chain fees in " +
+				"sats the anchor transaction is allowed to pay",
+		},
+		cli.BoolFlag{
+			Name: sendForceStrandName,
+			Usage: "if set, the anchor transaction is broadcast " +
+				"even if it would strand passive assets that " +
+				"aren't correctly re-anchored; those assets " +
+				"are lost",
+		},
+		cli.Uint64Flag{
+			Name: amtName,
+			Usage: "the amt of the asset to send to each of the " +
+				"addresses that don't specify an amount",
+		},
+		cli.Uint64Flag{
+			Name: sendEditionName,
+			Usage: "if set, the edition of a collectible minted " +
+				"as numbered editions to send to the single " +
+				"address",
+		},
 		// TODO(roasbeef): add arg for file name to write sender proof
 		// blob
 	},
@@ -440,12 +705,28 @@ func sendAssets(ctx *cli.Context) error {
 		return cli.ShowSubcommandHelp(ctx)
 	}
 
+	coinSelectStrategy, err := parseCoinSelectStrategy(
+		ctx.String(coinSelectName),
+	)
+	if err != nil {
+		return err
+	}
+
 	ctxc := getContext()
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
 	resp, err := client.SendAsset(ctxc, &taprpc.SendAssetRequest{
-		TapAddrs: addrs,
+		TapAddrs:           addrs,
+		CoinSelectStrategy: coinSelectStrategy,
+		SatPerVbyte:        ctx.Uint64(sendFeeRateName),
+		ConfTarget:         uint32(ctx.Uint64(sendConfTargetName)),
+		MaxFeeSats:         ctx.Uint64(sendMaxFeeName),
+		ForceStrandPassiveAssets: ctx.Bool(
+			sendForceStrandName,
+		),
+		Amount:  ctx.Uint64(amtName),
+		Edition: ctx.Uint64(sendEditionName),
 	})
 	if err != nil {
 		return fmt.Errorf("unable to send assets: %w", err)
@@ -455,6 +736,91 @@ func sendAssets(ctx *cli.Context) error {
 	return nil
 }
 
+// parseCoinSelectStrategy parses the name of a coin selection strategy into
+// its RPC counterpart. An empty name selects the default strategy of the
+// daemon.
+func parseCoinSelectStrategy(name string) (taprpc.CoinSelectStrategy, error) {
+	if name == "" {
+		name = "default"
+	}
+
+	rpcName := "COIN_SELECT_STRATEGY_" + strings.ToUpper(
+		strings.ReplaceAll(name, "-", "_"),
+	)
+	strategy, ok := taprpc.CoinSelectStrategy_value[rpcName]
+	if !ok {
+		return 0, fmt.Errorf("unknown coin selection strategy: %v",
+			name)
+	}
+
+	return taprpc.CoinSelectStrategy(strategy), nil
+}
+
+var burnAssetsCommand = cli.Command{
+	Name:  "burn",
+	Usage: "burn units of an asset",
+	Description: "Burn (destroy) units of an asset by sending them to a " +
+		"provably un-spendable script key. This operation cannot be " +
+		"undone and permanently reduces the supply of the asset",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  assetIDName,
+			Usage: "the asset ID of the asset to burn units of",
+		},
+		cli.Uint64Flag{
+			Name:  burnAmountName,
+			Usage: "the number of asset units to burn",
+		},
+		cli.BoolFlag{
+			Name: burnForceName,
+			Usage: "skip the interactive confirmation of the " +
+				"irreversible burn",
+		},
+	},
+	Action: burnAssets,
+}
+
+func burnAssets(ctx *cli.Context) error {
+	if ctx.NArg() != 0 || !ctx.IsSet(assetIDName) ||
+		ctx.Uint64(burnAmountName) == 0 {
+
+		return cli.ShowSubcommandHelp(ctx)
+	}
+
+	assetID, err := hex.DecodeString(ctx.String(assetIDName))
+	if err != nil {
+		return fmt.Errorf("invalid asset ID: %w", err)
+	}
+	burnAmount := ctx.Uint64(burnAmountName)
+
+	if !ctx.Bool(burnForceName) {
+		fmt.Printf("Burning %d units of asset %x permanently destroys "+
+			"them. Type 'yes' to continue: ", burnAmount, assetID)
+
+		var answer string
+		_, _ = fmt.Scanln(&answer)
+		if answer != "yes" {
+			return fmt.Errorf("burn aborted")
+		}
+	}
+
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.BurnAsset(ctxc, &taprpc.BurnAssetRequest{
+		AssetId:          assetID,
+		AmountToBurn:     burnAmount,
+		ConfirmationText: tap.AssetBurnConfirmationText,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to burn assets: %w", err)
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
 var listTransfersCommand = cli.Command{
 	Name:      "transfers",
 	ShortName: "t",
@@ -462,13 +828,21 @@ var listTransfersCommand = cli.Command{
 	Description: "list outgoing transfers of all assets or a selected " +
 		"asset",
 	Action: listTransfers,
-	Flags: []cli.Flag{
+	Flags: append([]cli.Flag{
 		cli.StringFlag{
 			Name: assetIDName,
 			Usage: "A specific asset ID to list outgoing " +
 				"transfers for",
 		},
-	},
+		cli.StringFlag{
+			Name:  "anchor_txid",
+			Usage: "only list the transfer with this anchor txid",
+		},
+		cli.BoolFlag{
+			Name:  "pending_only",
+			Usage: "only list transfers that aren't confirmed yet",
+		},
+	}, pageFlags...),
 }
 
 func listTransfers(ctx *cli.Context) error {
@@ -476,7 +850,24 @@ func listTransfers(ctx *cli.Context) error {
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
-	req := &taprpc.ListTransfersRequest{}
+	assetID, err := hex.DecodeString(ctx.String(assetIDName))
+	if err != nil {
+		return fmt.Errorf("invalid asset ID: %w", err)
+	}
+
+	limit, cursor, err := parsePageFlags(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := &taprpc.ListTransfersRequest{
+		AssetId:        assetID,
+		AnchorTxid:     ctx.String("anchor_txid"),
+		PendingOnly:    ctx.Bool("pending_only"),
+		SortDescending: ctx.Bool(sortDescendingName),
+		Limit:          limit,
+		Cursor:         cursor,
+	}
 	resp, err := client.ListTransfers(ctxc, req)
 	if err != nil {
 		return fmt.Errorf("unable to list asset transfers: %w", err)
@@ -550,3 +941,46 @@ func fetchMeta(ctx *cli.Context) error {
 	printRespJSON(resp)
 	return nil
 }
+
+var fetchGroupMetaHistoryCommand = cli.Command{
+	Name:  "metahistory",
+	Usage: "fetch the meta history of an asset group",
+	Description: "fetch the verified history of the metadata updates " +
+		"of an asset group, along with the metadata that should " +
+		"be displayed for the group",
+	Action: fetchGroupMetaHistory,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  groupKeyName,
+			Usage: "the group key of the asset group",
+		},
+	},
+}
+
+func fetchGroupMetaHistory(ctx *cli.Context) error {
+	if !ctx.IsSet(groupKeyName) {
+		return cli.ShowSubcommandHelp(ctx)
+	}
+
+	groupKey, err := hex.DecodeString(ctx.String(groupKeyName))
+	if err != nil {
+		return fmt.Errorf("invalid group key")
+	}
+
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.FetchGroupMetaHistory(
+		ctxc, &taprpc.FetchGroupMetaHistoryRequest{
+			GroupKey: groupKey,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("unable to fetch group meta history: %w",
+			err)
+	}
+
+	printRespJSON(resp)
+	return nil
+}
@@ -117,6 +117,76 @@ func stopDaemon(ctx *cli.Context) error {
 	return nil
 }
 
+var backupCommand = cli.Command{
+	Name:      "backup",
+	Usage:     "Back up the daemon's database.",
+	ArgsUsage: "[backup_path]",
+	Description: `
+	Write a consistent snapshot of the daemon's database to a file, while
+	the daemon keeps running. The path refers to the file system of the
+	daemon and must not exist yet. If no path is given, the backup is
+	written to the daemon's backup directory.
+
+	To restore a backup, start the daemon with the databaserestorefile
+	option pointing to the backup file. The daemon restores each backup
+	only once, so the option can't accidentally roll back the database on
+	a later restart.`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "backup_path",
+			Usage: "the path of the file the backup is written to",
+		},
+	},
+	Action: backupDatabase,
+}
+
+func backupDatabase(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	backupPath := ctx.String("backup_path")
+	if backupPath == "" && ctx.NArg() > 0 {
+		backupPath = ctx.Args().First()
+	}
+
+	resp, err := client.BackupDatabase(ctxc, &taprpc.BackupDatabaseRequest{
+		BackupPath: backupPath,
+	})
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var databaseStatsCommand = cli.Command{
+	Name:  "dbstats",
+	Usage: "Show statistics about the daemon's database.",
+	Description: `
+	Show the number of rows of each table of the daemon's database, the
+	size of the proof archive, the number of MS-SMT nodes and the version
+	of the last applied database migration. This can be used to monitor
+	the growth of the database.`,
+	Action: databaseStats,
+}
+
+func databaseStats(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &taprpc.DatabaseStatsRequest{}
+	resp, err := client.DatabaseStats(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
 var getInfoCommand = cli.Command{
 	Name:        "getinfo",
 	Usage:       "Get daemon info.",
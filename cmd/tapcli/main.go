@@ -17,6 +17,7 @@ import (
 	"github.com/btcsuite/btcd/btcutil"
 	tap "github.com/lightninglabs/taproot-assets"
 	"github.com/lightninglabs/taproot-assets/taprpc"
+	"github.com/lightninglabs/taproot-assets/taprpc/assetwalletrpc"
 	"github.com/lightninglabs/taproot-assets/taprpc/mintrpc"
 	"github.com/lightningnetwork/lnd/lncfg"
 	"github.com/lightningnetwork/lnd/lnrpc"
@@ -78,6 +79,18 @@ func getMintClient(ctx *cli.Context) (mintrpc.MintClient, func()) {
 	return mintrpc.NewMintClient(conn), cleanUp
 }
 
+func getWalletClient(ctx *cli.Context) (assetwalletrpc.AssetWalletClient,
+	func()) {
+
+	conn := getClientConn(ctx, false)
+
+	cleanUp := func() {
+		conn.Close()
+	}
+
+	return assetwalletrpc.NewAssetWalletClient(conn), cleanUp
+}
+
 func getClientConn(ctx *cli.Context, skipMacaroons bool) *grpc.ClientConn {
 	// First, we'll get the selected stored profile or an ephemeral one
 	// created from the global options in the CLI context.
@@ -318,6 +331,8 @@ func main() {
 	app.Commands = []cli.Command{
 		stopCommand,
 		debugLevelCommand,
+		backupCommand,
+		databaseStatsCommand,
 		profileSubCommand,
 		getInfoCommand,
 	}
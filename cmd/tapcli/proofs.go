@@ -8,6 +8,7 @@ import (
 	"path"
 
 	"github.com/lightninglabs/taproot-assets/taprpc"
+	"github.com/lightninglabs/taproot-assets/taprpc/assetwalletrpc"
 	"github.com/lightningnetwork/lnd/lncfg"
 	"github.com/urfave/cli"
 )
@@ -22,6 +23,10 @@ var proofCommands = []cli.Command{
 			verifyProofCommand,
 			exportProofCommand,
 			importProofCommand,
+			exportProofBundleCommand,
+			importProofBundleCommand,
+			proveReservesCommand,
+			verifyReservesCommand,
 		},
 	},
 }
@@ -181,6 +186,245 @@ func importProof(ctx *cli.Context) error {
 	return nil
 }
 
+const (
+	bundlePathName = "bundle_file"
+)
+
+var exportProofBundleCommand = cli.Command{
+	Name:      "exportbundle",
+	ShortName: "eb",
+	Description: "export the proofs of all owned assets, or only of " +
+		"those with the given asset IDs, into a single compressed " +
+		"proof bundle, for example to back them up or to migrate " +
+		"them to another node",
+	Flags: []cli.Flag{
+		cli.StringSliceFlag{
+			Name: assetIDName,
+			Usage: "(optional) the asset ID of the assets to " +
+				"export the proofs of; can be specified " +
+				"multiple times to export the proofs of " +
+				"multiple assets",
+		},
+		cli.StringFlag{
+			Name: bundlePathName,
+			Usage: "the file to write the proof bundle to; use " +
+				"the dash character (-) to write the raw " +
+				"binary bundle to stdout instead",
+		},
+	},
+	Action: exportProofBundle,
+}
+
+func exportProofBundle(ctx *cli.Context) error {
+	switch {
+	case ctx.String(bundlePathName) == "":
+		return cli.ShowSubcommandHelp(ctx)
+	}
+
+	var assetIDs [][]byte
+	for _, assetIDHex := range ctx.StringSlice(assetIDName) {
+		assetID, err := hex.DecodeString(assetIDHex)
+		if err != nil {
+			return fmt.Errorf("unable to decode asset ID: %v", err)
+		}
+
+		assetIDs = append(assetIDs, assetID)
+	}
+
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ExportProofBundle(
+		ctxc, &taprpc.ExportProofBundleRequest{
+			AssetIds: assetIDs,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("unable to export proof bundle: %w", err)
+	}
+
+	filePath := lncfg.CleanAndExpandPath(ctx.String(bundlePathName))
+	if err := writeToFile(filePath, resp.RawBundle); err != nil {
+		return err
+	}
+
+	// When writing to stdout, we don't want to mix the binary bundle with
+	// the manifest.
+	if filePath == "-" {
+		return nil
+	}
+
+	// The raw bundle was written to the file already, so we only print the
+	// manifest.
+	resp.RawBundle = nil
+	printRespJSON(resp)
+	return nil
+}
+
+var importProofBundleCommand = cli.Command{
+	Name:      "importbundle",
+	ShortName: "ib",
+	Description: "verify and import all proofs of a proof bundle, " +
+		"resulting in a spendable asset for each of them",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: bundlePathName,
+			Usage: "the path to the proof bundle on disk; use " +
+				"the dash character (-) to read from stdin " +
+				"instead",
+		},
+	},
+	Action: importProofBundle,
+}
+
+func importProofBundle(ctx *cli.Context) error {
+	switch {
+	case ctx.String(bundlePathName) == "":
+		return cli.ShowSubcommandHelp(ctx)
+	}
+
+	filePath := lncfg.CleanAndExpandPath(ctx.String(bundlePathName))
+	rawBundle, err := readFile(filePath)
+	if err != nil {
+		return fmt.Errorf("unable to read proof bundle: %w", err)
+	}
+
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ImportProofBundle(
+		ctxc, &taprpc.ImportProofBundleRequest{
+			RawBundle: rawBundle,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("unable to import proof bundle: %w", err)
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+const (
+	reservesPathName = "reserves_file"
+
+	challengeName = "challenge"
+)
+
+var proveReservesCommand = cli.Command{
+	Name:      "provereserves",
+	ShortName: "pr",
+	Description: "create a reserves report over all owned assets that " +
+		"proves ownership of them to a verifier, bound to the " +
+		"challenge chosen by the verifier",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: challengeName,
+			Usage: "the hex encoded 32-byte challenge chosen by " +
+				"the verifier",
+		},
+		cli.StringFlag{
+			Name: reservesPathName,
+			Usage: "the file to write the reserves report to; " +
+				"use the dash character (-) to write the " +
+				"raw binary report to stdout instead",
+		},
+	},
+	Action: proveReserves,
+}
+
+func proveReserves(ctx *cli.Context) error {
+	switch {
+	case ctx.String(challengeName) == "",
+		ctx.String(reservesPathName) == "":
+
+		return cli.ShowSubcommandHelp(ctx)
+	}
+
+	challenge, err := hex.DecodeString(ctx.String(challengeName))
+	if err != nil {
+		return fmt.Errorf("unable to decode challenge: %w", err)
+	}
+
+	ctxc := getContext()
+	client, cleanUp := getWalletClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ProveReserves(
+		ctxc, &assetwalletrpc.ProveReservesRequest{
+			Challenge: challenge,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("unable to prove reserves: %w", err)
+	}
+
+	filePath := lncfg.CleanAndExpandPath(ctx.String(reservesPathName))
+	return writeToFile(filePath, resp.ReservesReport)
+}
+
+var verifyReservesCommand = cli.Command{
+	Name:      "verifyreserves",
+	ShortName: "vr",
+	Description: "verify a reserves report against the challenge it " +
+		"was created for and show the total reserves it proves; " +
+		"the reserves are only held as long as all returned " +
+		"anchor outpoints are unspent",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: challengeName,
+			Usage: "the hex encoded 32-byte challenge the report " +
+				"must be bound to",
+		},
+		cli.StringFlag{
+			Name: reservesPathName,
+			Usage: "the path to the reserves report on disk; use " +
+				"the dash character (-) to read from stdin " +
+				"instead",
+		},
+	},
+	Action: verifyReserves,
+}
+
+func verifyReserves(ctx *cli.Context) error {
+	switch {
+	case ctx.String(challengeName) == "",
+		ctx.String(reservesPathName) == "":
+
+		return cli.ShowSubcommandHelp(ctx)
+	}
+
+	challenge, err := hex.DecodeString(ctx.String(challengeName))
+	if err != nil {
+		return fmt.Errorf("unable to decode challenge: %w", err)
+	}
+
+	filePath := lncfg.CleanAndExpandPath(ctx.String(reservesPathName))
+	rawReport, err := readFile(filePath)
+	if err != nil {
+		return fmt.Errorf("unable to read reserves report: %w", err)
+	}
+
+	ctxc := getContext()
+	client, cleanUp := getWalletClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.VerifyReserves(
+		ctxc, &assetwalletrpc.VerifyReservesRequest{
+			ReservesReport: rawReport,
+			Challenge:      challenge,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("unable to verify reserves: %w", err)
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
 // readFile attempts to read a file from disk. If the passed fileName is equal
 // to the dash character, then this function reads from stdin instead.
 func readFile(fileName string) ([]byte, error) {
@@ -694,7 +694,8 @@ var universeAssetStatsCommand = cli.Command{
 		cli.StringFlag{
 			Name: sortByName,
 			Usage: "the name of the field to sort by, " +
-				"[--sort_by=asset_name|asset_type|asset_id]",
+				"[--sort_by=asset_name|asset_type|asset_id|" +
+				"total_queries]",
 		},
 		cli.Int64Flag{
 			Name:  limitName,
@@ -718,6 +719,7 @@ func universeStatsQueryCommand(ctx *cli.Context) error {
 	case "asset_name":
 	case "asset_type":
 	case "asset_id":
+	case "total_queries":
 	default:
 		return fmt.Errorf("invalid sort_by value: %v",
 			ctx.String(sortByName))
@@ -760,6 +762,9 @@ func universeStatsQueryCommand(ctx *cli.Context) error {
 			case ctx.String(sortByName) == "asset_type":
 				return unirpc.AssetQuerySort_SORT_BY_ASSET_TYPE
 
+			case ctx.String(sortByName) == "total_queries":
+				return unirpc.AssetQuerySort_SORT_BY_TOTAL_QUERIES
+
 			default:
 				return unirpc.AssetQuerySort_SORT_BY_NONE
 			}
@@ -0,0 +1,484 @@
+package commitment
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightninglabs/taproot-assets/chanutils"
+	"github.com/lightninglabs/taproot-assets/mssmt"
+	"github.com/lightningnetwork/lnd/tlv"
+	"golang.org/x/exp/maps"
+)
+
+const (
+	// altLeavesTag is the preimage to the AltLeavesTapCommitmentKey.
+	altLeavesTag = "taproot-assets:alt-leaves"
+)
+
+var (
+	// AltLeavesTapCommitmentKey is the key at which the commitment to all
+	// auxiliary (alt) leaves is stored in the TapCommitment MS-SMT. As it
+	// is the hash of a static tag, it can't collide with an asset ID or
+	// the hash of a group key.
+	AltLeavesTapCommitmentKey = sha256.Sum256([]byte(altLeavesTag))
+
+	// ErrMissingAltLeaf is returned when an alt leaf is required but
+	// missing.
+	ErrMissingAltLeaf = errors.New("missing alt leaf")
+
+	// ErrInvalidAltLeafProof is returned when a proof doesn't prove the
+	// inclusion or exclusion of an alt leaf.
+	ErrInvalidAltLeafProof = errors.New("proof is not an alt leaf proof")
+
+	// ErrDuplicateAltLeaf is returned when a set of alt leaves contains
+	// more than one leaf with the same key.
+	ErrDuplicateAltLeaf = errors.New("duplicate alt leaf key")
+)
+
+// AltLeaf is an application-defined auxiliary leaf that is committed to next
+// to the asset commitments of a TapCommitment. Alt leaves carry no asset value
+// and are never interpreted by the Taproot Assets protocol itself, which allows
+// protocols built on top of it to commit to extra data in the same output.
+type AltLeaf struct {
+	// Key uniquely identifies the alt leaf within the TapCommitment. How
+	// the key is derived is up to the application creating the leaf.
+	Key [32]byte
+
+	// Data is the opaque data committed to by the alt leaf.
+	Data []byte
+}
+
+// Leaf returns the MS-SMT leaf node of the alt leaf. Alt leaves don't carry
+// any value, so the sum of the leaf is always zero.
+func (l *AltLeaf) Leaf() *mssmt.LeafNode {
+	return mssmt.NewLeafNode(l.Data, 0)
+}
+
+// Copy returns a deep copy of the alt leaf.
+func (l *AltLeaf) Copy() *AltLeaf {
+	return &AltLeaf{
+		Key:  l.Key,
+		Data: append([]byte(nil), l.Data...),
+	}
+}
+
+// EncodeRecords returns the TLV encode records for the alt leaf.
+func (l *AltLeaf) EncodeRecords() []tlv.Record {
+	return []tlv.Record{
+		AltLeafKeyRecord(&l.Key),
+		AltLeafDataRecord(&l.Data),
+	}
+}
+
+// DecodeRecords returns the TLV decode records for the alt leaf.
+func (l *AltLeaf) DecodeRecords() []tlv.Record {
+	return []tlv.Record{
+		AltLeafKeyRecord(&l.Key),
+		AltLeafDataRecord(&l.Data),
+	}
+}
+
+// Encode encodes the alt leaf to the given writer.
+func (l *AltLeaf) Encode(w io.Writer) error {
+	stream, err := tlv.NewStream(l.EncodeRecords()...)
+	if err != nil {
+		return err
+	}
+	return stream.Encode(w)
+}
+
+// Decode decodes the alt leaf from the given reader.
+func (l *AltLeaf) Decode(r io.Reader) error {
+	stream, err := tlv.NewStream(l.DecodeRecords()...)
+	if err != nil {
+		return err
+	}
+	return stream.Decode(r)
+}
+
+// altLeafCommitment is the inner MS-SMT committing to all alt leaves of a
+// TapCommitment. It is inserted into the TapCommitment MS-SMT in exactly the
+// same way as an AssetCommitment keyed by AltLeavesTapCommitmentKey, which
+// allows the same proof structure to be used for alt leaves and assets.
+type altLeafCommitment struct {
+	// treeRoot is the root node of the MS-SMT containing all alt leaves.
+	treeRoot *mssmt.BranchNode
+
+	// tree is the MS-SMT containing all alt leaves.
+	tree mssmt.Tree
+
+	// leaves is the set of alt leaves found within the tree above.
+	leaves map[[32]byte]*AltLeaf
+}
+
+// newAltLeafCommitment creates a new commitment to the given alt leaves.
+func newAltLeafCommitment(leaves ...*AltLeaf) (*altLeafCommitment, error) {
+	c := &altLeafCommitment{
+		tree:   mssmt.NewCompactedTree(mssmt.NewDefaultStore()),
+		leaves: make(map[[32]byte]*AltLeaf, len(leaves)),
+	}
+	if err := c.upsert(leaves...); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// copy returns a deep copy of the alt leaf commitment. If possible, the copy
+// shares all nodes of the tree instead of re-creating it.
+func (c *altLeafCommitment) copy() (*altLeafCommitment, error) {
+	leaves := chanutils.Map(maps.Values(c.leaves), (*AltLeaf).Copy)
+
+	treeCopy, ok := copyTree(c.tree)
+	if !ok {
+		return newAltLeafCommitment(leaves...)
+	}
+
+	leavesCopy := make(map[[32]byte]*AltLeaf, len(leaves))
+	for _, leaf := range leaves {
+		leavesCopy[leaf.Key] = leaf
+	}
+
+	return &altLeafCommitment{
+		treeRoot: c.treeRoot,
+		tree:     treeCopy,
+		leaves:   leavesCopy,
+	}, nil
+}
+
+// upsert inserts or updates the given alt leaves.
+func (c *altLeafCommitment) upsert(leaves ...*AltLeaf) error {
+	ctx := context.TODO()
+
+	treeLeaves := make(map[[32]byte]*mssmt.LeafNode, len(leaves))
+	for _, leaf := range leaves {
+		if leaf == nil {
+			return ErrMissingAltLeaf
+		}
+
+		treeLeaves[leaf.Key] = leaf.Leaf()
+	}
+
+	if _, err := c.tree.InsertMany(ctx, treeLeaves); err != nil {
+		return err
+	}
+
+	for _, leaf := range leaves {
+		c.leaves[leaf.Key] = leaf
+	}
+
+	var err error
+	c.treeRoot, err = c.tree.Root(ctx)
+	return err
+}
+
+// delete removes the alt leaves with the given keys.
+func (c *altLeafCommitment) delete(keys ...[32]byte) error {
+	ctx := context.TODO()
+
+	if _, err := c.tree.DeleteMany(ctx, keys); err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		delete(c.leaves, key)
+	}
+
+	var err error
+	c.treeRoot, err = c.tree.Root(ctx)
+	return err
+}
+
+// assetCommitment returns the alt leaf commitment in the form of an asset
+// commitment, which determines how it is committed to in the TapCommitment.
+func (c *altLeafCommitment) assetCommitment() *AssetCommitment {
+	return &AssetCommitment{
+		Version:  asset.V0,
+		AssetID:  AltLeavesTapCommitmentKey,
+		TreeRoot: c.treeRoot,
+	}
+}
+
+// sortedLeaves returns all alt leaves sorted by their key.
+func (c *altLeafCommitment) sortedLeaves() []*AltLeaf {
+	leaves := maps.Values(c.leaves)
+	sort.Slice(leaves, func(i, j int) bool {
+		return string(leaves[i].Key[:]) < string(leaves[j].Key[:])
+	})
+
+	return leaves
+}
+
+// UpsertAltLeaves inserts or updates the given alt leaves in the
+// TapCommitment.
+func (c *TapCommitment) UpsertAltLeaves(leaves ...*AltLeaf) error {
+	if c.tree == nil {
+		return fmt.Errorf("cannot add alt leaves to commitment " +
+			"without tree")
+	}
+
+	if c.altLeaves == nil {
+		altLeaves, err := newAltLeafCommitment()
+		if err != nil {
+			return err
+		}
+		c.altLeaves = altLeaves
+	}
+
+	if err := c.altLeaves.upsert(leaves...); err != nil {
+		return err
+	}
+
+	return c.updateAltLeaves()
+}
+
+// DeleteAltLeaves removes the alt leaves with the given keys from the
+// TapCommitment.
+func (c *TapCommitment) DeleteAltLeaves(keys ...[32]byte) error {
+	if c.altLeaves == nil {
+		return nil
+	}
+
+	if err := c.altLeaves.delete(keys...); err != nil {
+		return err
+	}
+
+	return c.updateAltLeaves()
+}
+
+// updateAltLeaves re-inserts the alt leaf commitment into the TapCommitment
+// MS-SMT after it was modified. Just like asset commitments, an empty alt
+// leaf commitment is removed from the tree entirely.
+func (c *TapCommitment) updateAltLeaves() error {
+	ctx := context.TODO()
+
+	var err error
+	if len(c.altLeaves.leaves) == 0 {
+		c.altLeaves = nil
+		_, err = c.tree.Delete(ctx, AltLeavesTapCommitmentKey)
+	} else {
+		_, err = c.tree.Insert(
+			ctx, AltLeavesTapCommitmentKey,
+			c.altLeaves.assetCommitment().TapCommitmentLeaf(),
+		)
+	}
+	if err != nil {
+		return err
+	}
+
+	c.TreeRoot, err = c.tree.Root(ctx)
+	return err
+}
+
+// AltLeaves returns the alt leaves committed to in the TapCommitment, sorted
+// by their key.
+func (c *TapCommitment) AltLeaves() []*AltLeaf {
+	if c.altLeaves == nil {
+		return nil
+	}
+
+	return c.altLeaves.sortedLeaves()
+}
+
+// AltLeafProof computes the full TapCommitment merkle proof for the alt leaf
+// with the given key. If the alt leaf isn't committed to, the returned leaf is
+// nil and the proof is an exclusion proof.
+func (c *TapCommitment) AltLeafProof(key [32]byte) (*AltLeaf, *Proof,
+	error) {
+
+	if c.tree == nil {
+		return nil, nil, fmt.Errorf("missing tree to compute proofs")
+	}
+
+	ctx := context.TODO()
+
+	merkleProof, err := c.tree.MerkleProof(ctx, AltLeavesTapCommitmentKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	proof := &Proof{
+		TaprootAssetProof: TaprootAssetProof{
+			Proof:   *merkleProof,
+			Version: c.Version,
+		},
+	}
+
+	// Without any alt leaves, proving that the alt leaf commitment isn't
+	// part of the tree is enough.
+	if c.altLeaves == nil {
+		return nil, proof, nil
+	}
+
+	leafProof, err := c.altLeaves.tree.MerkleProof(ctx, key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	proof.AssetProof = &AssetProof{
+		Proof:   *leafProof,
+		Version: asset.V0,
+		AssetID: AltLeavesTapCommitmentKey,
+	}
+
+	return c.altLeaves.leaves[key], proof, nil
+}
+
+// DeriveByAltLeafInclusion derives the Taproot Asset commitment containing
+// the provided alt leaf.
+func (p Proof) DeriveByAltLeafInclusion(leaf *AltLeaf) (*TapCommitment,
+	error) {
+
+	switch {
+	case leaf == nil:
+		return nil, ErrMissingAltLeaf
+
+	case p.AssetProof == nil:
+		return nil, ErrMissingAssetProof
+
+	case p.AssetProof.AssetID != AltLeavesTapCommitmentKey:
+		return nil, ErrInvalidAltLeafProof
+	}
+
+	altCommitment := &AssetCommitment{
+		Version:  p.AssetProof.Version,
+		AssetID:  AltLeavesTapCommitmentKey,
+		TreeRoot: p.AssetProof.Root(leaf.Key, leaf.Leaf()),
+	}
+	tapProofRoot := p.TaprootAssetProof.Root(
+		AltLeavesTapCommitmentKey, altCommitment.TapCommitmentLeaf(),
+	)
+
+	return NewTapCommitmentWithRoot(
+		p.TaprootAssetProof.Version, tapProofRoot,
+	), nil
+}
+
+// DeriveByAltLeafExclusion derives the Taproot Asset commitment excluding the
+// alt leaf with the given key. This is either proven by excluding the leaf
+// from the alt leaf commitment, or by excluding the alt leaf commitment as a
+// whole if the TapCommitment doesn't commit to any alt leaves.
+func (p Proof) DeriveByAltLeafExclusion(key [32]byte) (*TapCommitment,
+	error) {
+
+	if p.AssetProof == nil {
+		return p.DeriveByAssetCommitmentExclusion(
+			AltLeavesTapCommitmentKey,
+		)
+	}
+
+	if p.AssetProof.AssetID != AltLeavesTapCommitmentKey {
+		return nil, ErrInvalidAltLeafProof
+	}
+
+	return p.DeriveByAssetExclusion(key)
+}
+
+// AltLeavesProof proves the full set of alt leaves committed to in a
+// TapCommitment. It allows the owner of an output to re-create the complete
+// TapCommitment of the output, which is required to spend it, even if the
+// alt leaves were added by someone else.
+type AltLeavesProof struct {
+	// Leaves is the full set of alt leaves committed to in the
+	// TapCommitment, sorted by their key.
+	Leaves []*AltLeaf
+
+	// TaprootAssetProof is the proof of the alt leaf commitment within the
+	// TapCommitment.
+	TaprootAssetProof TaprootAssetProof
+}
+
+// EncodeRecords returns the TLV encode records for the alt leaves proof.
+func (p *AltLeavesProof) EncodeRecords() []tlv.Record {
+	return []tlv.Record{
+		AltLeavesProofLeavesRecord(&p.Leaves),
+		AltLeavesProofTaprootAssetProofRecord(&p.TaprootAssetProof),
+	}
+}
+
+// DecodeRecords returns the TLV decode records for the alt leaves proof.
+func (p *AltLeavesProof) DecodeRecords() []tlv.Record {
+	return []tlv.Record{
+		AltLeavesProofLeavesRecord(&p.Leaves),
+		AltLeavesProofTaprootAssetProofRecord(&p.TaprootAssetProof),
+	}
+}
+
+// Encode encodes the alt leaves proof to the given writer.
+func (p *AltLeavesProof) Encode(w io.Writer) error {
+	stream, err := tlv.NewStream(p.EncodeRecords()...)
+	if err != nil {
+		return err
+	}
+	return stream.Encode(w)
+}
+
+// Decode decodes the alt leaves proof from the given reader.
+func (p *AltLeavesProof) Decode(r io.Reader) error {
+	stream, err := tlv.NewStream(p.DecodeRecords()...)
+	if err != nil {
+		return err
+	}
+	return stream.Decode(r)
+}
+
+// DeriveTapCommitment derives the Taproot Asset commitment committing to
+// exactly the set of alt leaves of the proof.
+func (p *AltLeavesProof) DeriveTapCommitment() (*TapCommitment, error) {
+	if len(p.Leaves) == 0 {
+		return nil, ErrMissingAltLeaf
+	}
+
+	altCommitment, err := newAltLeafCommitment(p.Leaves...)
+	if err != nil {
+		return nil, err
+	}
+
+	// The proof must reveal each alt leaf exactly once, otherwise the
+	// same commitment could be proven with different sets of leaves.
+	if len(altCommitment.leaves) != len(p.Leaves) {
+		return nil, ErrDuplicateAltLeaf
+	}
+
+	tapProofRoot := p.TaprootAssetProof.Root(
+		AltLeavesTapCommitmentKey,
+		altCommitment.assetCommitment().TapCommitmentLeaf(),
+	)
+
+	return NewTapCommitmentWithRoot(
+		p.TaprootAssetProof.Version, tapProofRoot,
+	), nil
+}
+
+// AltLeavesProof creates a proof of the full set of alt leaves committed to in
+// the TapCommitment. If the TapCommitment doesn't commit to any alt leaves, nil
+// is returned.
+func (c *TapCommitment) AltLeavesProof() (*AltLeavesProof, error) {
+	if c.altLeaves == nil {
+		return nil, nil
+	}
+
+	if c.tree == nil {
+		return nil, fmt.Errorf("missing tree to compute proofs")
+	}
+
+	ctx := context.TODO()
+
+	merkleProof, err := c.tree.MerkleProof(ctx, AltLeavesTapCommitmentKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AltLeavesProof{
+		Leaves: chanutils.Map(c.AltLeaves(), (*AltLeaf).Copy),
+		TaprootAssetProof: TaprootAssetProof{
+			Proof:   *merkleProof,
+			Version: c.Version,
+		},
+	}, nil
+}
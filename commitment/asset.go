@@ -102,6 +102,10 @@ func parseCommon(assets ...*asset.Asset) (*AssetCommitment, error) {
 	assetGenesis := assets[0].Genesis.ID()
 	assetGroupKey := assets[0].GroupKey
 	assetsMap := make(CommittedAssets, len(assets))
+
+	// The group key signatures are collected along the way so they can be
+	// verified in a single batch.
+	var groupSigs asset.GroupSigBatch
 	for _, asset := range assets {
 		switch {
 		case !assetGroupKey.IsEqualGroup(asset.GroupKey):
@@ -114,13 +118,8 @@ func parseCommon(assets ...*asset.Asset) (*AssetCommitment, error) {
 
 		case assetGroupKey != nil:
 			// There should be a valid Schnorr sig over the asset ID
-			// in the group key struct.
-			validSig := asset.Genesis.VerifySignature(
-				&asset.GroupKey.Sig, &assetGroupKey.GroupPubKey,
-			)
-			if !validSig {
-				return nil, ErrAssetGenesisInvalidSig
-			}
+			// or a valid group witness in the group key struct.
+			groupSigs.Add(asset.Genesis, asset.GroupKey)
 		}
 
 		key := asset.AssetCommitmentKey()
@@ -134,6 +133,10 @@ func parseCommon(assets ...*asset.Asset) (*AssetCommitment, error) {
 		assetsMap[key] = asset
 	}
 
+	if err := groupSigs.Verify(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrAssetGenesisInvalidSig, err)
+	}
+
 	// The assetID here is what will be used to place this asset commitment
 	// into the top-level Taproot Asset commitment. For assets without a
 	// group key, then this will be the normal asset ID. Otherwise, this'll
@@ -163,7 +166,9 @@ func NewAssetCommitment(assets ...*asset.Asset) (*AssetCommitment, error) {
 		return nil, err
 	}
 
-	tree := mssmt.NewCompactedTree(mssmt.NewDefaultStore())
+	// We insert all assets in a single batch, so each branch of the tree is
+	// only computed once.
+	leaves := make(map[[32]byte]*mssmt.LeafNode, len(assets))
 	for _, asset := range assets {
 		key := asset.AssetCommitmentKey()
 		leaf, err := asset.Leaf()
@@ -171,11 +176,14 @@ func NewAssetCommitment(assets ...*asset.Asset) (*AssetCommitment, error) {
 			return nil, err
 		}
 
-		// TODO(bhandras): thread the context through.
-		_, err = tree.Insert(context.TODO(), key, leaf)
-		if err != nil {
-			return nil, err
-		}
+		leaves[key] = leaf
+	}
+
+	// TODO(bhandras): thread the context through.
+	tree := mssmt.NewCompactedTree(mssmt.NewDefaultStore())
+	_, err = tree.InsertMany(context.TODO(), leaves)
+	if err != nil {
+		return nil, err
 	}
 
 	commitment.TreeRoot, err = tree.Root(context.TODO())
@@ -207,19 +215,14 @@ func (c *AssetCommitment) Upsert(asset *asset.Asset) error {
 	// The given Asset must have an ID that matches the AssetCommitment ID.
 	// The AssetCommitment ID is either a hash of the groupKey, or the ID
 	// of all the assets in the AssetCommitment.
-	if asset.TapCommitmentKey() != c.AssetID {
-		if asset.GroupKey != nil {
-			return ErrAssetGroupKeyMismatch
-		}
-		return ErrAssetGenesisMismatch
+	if err := c.checkAssetID(asset); err != nil {
+		return err
 	}
 
 	// There should be a valid Schnorr sig over the asset ID
-	// in the group key struct.
+	// or a valid group witness in the group key struct.
 	if asset.GroupKey != nil {
-		validSig := asset.Genesis.VerifySignature(
-			&asset.GroupKey.Sig, &asset.GroupKey.GroupPubKey,
-		)
+		validSig := asset.Genesis.VerifyGroupWitness(asset.GroupKey)
 		if !validSig {
 			return ErrAssetGenesisInvalidSig
 		}
@@ -259,11 +262,8 @@ func (c *AssetCommitment) Delete(asset *asset.Asset) error {
 	// The given Asset must have an ID that matches the AssetCommitment ID.
 	// The AssetCommitment ID is either a hash of the groupKey, or the ID
 	// of all the assets in the AssetCommitment.
-	if asset.TapCommitmentKey() != c.AssetID {
-		if asset.GroupKey != nil {
-			return ErrAssetGroupKeyMismatch
-		}
-		return ErrAssetGenesisMismatch
+	if err := c.checkAssetID(asset); err != nil {
+		return err
 	}
 
 	key := asset.AssetCommitmentKey()
@@ -285,6 +285,128 @@ func (c *AssetCommitment) Delete(asset *asset.Asset) error {
 	return nil
 }
 
+// checkAssetID returns an error if the given asset can't be committed to by
+// this asset commitment because its ID (or group key) doesn't match.
+func (c *AssetCommitment) checkAssetID(a *asset.Asset) error {
+	if a.TapCommitmentKey() == c.AssetID {
+		return nil
+	}
+
+	if a.GroupKey != nil {
+		return ErrAssetGroupKeyMismatch
+	}
+	return ErrAssetGenesisMismatch
+}
+
+// validateBatch checks that all given assets can be upserted into or deleted
+// from this asset commitment, without modifying it. The group key signatures
+// of the upserted assets are verified in a single batch.
+func (c *AssetCommitment) validateBatch(upserts, deletes []*asset.Asset) error {
+	// Since we don't store the asset type in the AssetCommitment, we fetch
+	// the type of a random, already included and validated asset.
+	var (
+		assetType  asset.Type
+		checkType  bool
+		groupSigs  asset.GroupSigBatch
+		upsertKeys = make(map[[32]byte]struct{}, len(upserts))
+	)
+	for _, randAsset := range c.assets {
+		assetType, checkType = randAsset.Type, true
+		break
+	}
+
+	for _, a := range upserts {
+		if a == nil {
+			return ErrNoAssets
+		}
+		if checkType && a.Type != assetType {
+			return ErrAssetTypeMismatch
+		}
+		if err := c.checkAssetID(a); err != nil {
+			return err
+		}
+		if a.GroupKey != nil {
+			groupSigs.Add(a.Genesis, a.GroupKey)
+		}
+
+		key := a.AssetCommitmentKey()
+		if _, ok := upsertKeys[key]; ok {
+			return fmt.Errorf("%w: %x", ErrAssetDuplicateScriptKey,
+				key[:])
+		}
+		upsertKeys[key] = struct{}{}
+	}
+
+	for _, a := range deletes {
+		if a == nil {
+			return ErrNoAssets
+		}
+		if err := c.checkAssetID(a); err != nil {
+			return err
+		}
+
+		key := a.AssetCommitmentKey()
+		if _, ok := upsertKeys[key]; ok {
+			return fmt.Errorf("%w: %x", ErrConflictingDelta, key[:])
+		}
+	}
+
+	if err := groupSigs.Verify(); err != nil {
+		return fmt.Errorf("%w: %v", ErrAssetGenesisInvalidSig, err)
+	}
+
+	return nil
+}
+
+// applyBatch deletes and then upserts the given assets in the inner MS-SMT and
+// the internal asset map, recomputing the affected branches of the tree only
+// once. The batch must have been checked with validateBatch before.
+func (c *AssetCommitment) applyBatch(upserts, deletes []*asset.Asset) error {
+	// TODO(bhandras): thread the context through.
+	ctx := context.TODO()
+
+	if len(deletes) > 0 {
+		keys := make([][32]byte, 0, len(deletes))
+		for _, a := range deletes {
+			keys = append(keys, a.AssetCommitmentKey())
+		}
+
+		_, err := c.tree.DeleteMany(ctx, keys)
+		if err != nil {
+			return err
+		}
+
+		for _, key := range keys {
+			delete(c.assets, key)
+		}
+	}
+
+	if len(upserts) > 0 {
+		leaves := make(map[[32]byte]*mssmt.LeafNode, len(upserts))
+		for _, a := range upserts {
+			leaf, err := a.Leaf()
+			if err != nil {
+				return err
+			}
+
+			leaves[a.AssetCommitmentKey()] = leaf
+		}
+
+		_, err := c.tree.InsertMany(ctx, leaves)
+		if err != nil {
+			return err
+		}
+
+		for _, a := range upserts {
+			c.assets[a.AssetCommitmentKey()] = a
+		}
+	}
+
+	var err error
+	c.TreeRoot, err = c.tree.Root(ctx)
+	return err
+}
+
 // Root computes the root identifier required to commit to this specific asset
 // commitment within the outer commitment, also known as the Taproot Asset
 // commitment.
@@ -337,6 +459,16 @@ func (c *AssetCommitment) AssetProof(key [32]byte) (
 	return c.assets[key], proof, nil
 }
 
+// TreeStats returns statistics about the nodes of the MS-SMT backing the
+// AssetCommitment.
+func (c *AssetCommitment) TreeStats() (*mssmt.TreeStats, error) {
+	if c.tree == nil {
+		return nil, fmt.Errorf("missing tree to compute stats")
+	}
+
+	return c.tree.Stats(context.TODO())
+}
+
 // Assets returns the set of assets committed to in the asset commitment.
 func (c *AssetCommitment) Assets() CommittedAssets {
 	assets := make(CommittedAssets, len(c.assets))
@@ -370,9 +502,49 @@ func (c *AssetCommitment) Copy() (*AssetCommitment, error) {
 	// commitment is committing to.
 	newAssets := chanutils.CopyAll(maps.Values(c.Assets()))
 
-	// Now that we have a deep copy of all the assets, we can just create a
-	// brand-new commitment from the set of assets.
-	return NewAssetCommitment(newAssets...)
+	// If possible, the copy shares all nodes of our tree instead of
+	// re-creating the tree, which is much cheaper for large commitments.
+	// The nodes themselves are never modified, so we can share the root
+	// node as well.
+	treeCopy, ok := copyTree(c.tree)
+	if !ok {
+		// Otherwise, we can just create a brand-new commitment from
+		// the set of assets.
+		return NewAssetCommitment(newAssets...)
+	}
+
+	maxVersion := asset.V0
+	assetsCopy := make(CommittedAssets, len(newAssets))
+	for _, a := range newAssets {
+		if a.Version > maxVersion {
+			maxVersion = a.Version
+		}
+		assetsCopy[a.AssetCommitmentKey()] = a
+	}
+
+	return &AssetCommitment{
+		Version:  maxVersion,
+		AssetID:  c.AssetID,
+		TreeRoot: c.TreeRoot,
+		tree:     treeCopy,
+		assets:   assetsCopy,
+	}, nil
+}
+
+// copyTree returns a copy of the given tree that shares all nodes with the
+// original tree. False is returned if the tree doesn't support this.
+func copyTree(tree mssmt.Tree) (mssmt.Tree, bool) {
+	compactedTree, ok := tree.(*mssmt.CompactedTree)
+	if !ok {
+		return nil, false
+	}
+
+	treeCopy, err := compactedTree.Copy()
+	if err != nil {
+		return nil, false
+	}
+
+	return treeCopy, true
 }
 
 // Merge merges the other commitment into this commitment. If the other
@@ -390,13 +562,14 @@ func (c *AssetCommitment) Merge(other *AssetCommitment) error {
 		return nil
 	}
 
-	// Otherwise, we'll need to merge the other asset commitments into
-	// this commitment.
-	for _, otherCommitment := range other.assets {
-		if err := c.Upsert(otherCommitment.Copy()); err != nil {
-			return fmt.Errorf("error upserting other commitment: "+
-				"%w", err)
-		}
+	// Otherwise, we'll need to merge the other assets into this
+	// commitment, which we do in a single batch.
+	otherAssets := chanutils.CopyAll(maps.Values(other.assets))
+	if err := c.validateBatch(otherAssets, nil); err != nil {
+		return fmt.Errorf("error upserting other commitment: %w", err)
+	}
+	if err := c.applyBatch(otherAssets, nil); err != nil {
+		return fmt.Errorf("error upserting other commitment: %w", err)
 	}
 
 	return nil
@@ -1,9 +1,11 @@
 package commitment
 
 import (
+	"bytes"
 	"context"
 	"encoding/hex"
 	"math/rand"
+	"path/filepath"
 	"testing"
 	"testing/quick"
 
@@ -310,6 +312,32 @@ func TestMintTapCommitment(t *testing.T) {
 			},
 			valid: true,
 		},
+		{
+			name: "collectible edition",
+			g:    genesisCollectible,
+			f: func() *AssetDetails {
+				return &AssetDetails{
+					Type:      asset.Collectible,
+					ScriptKey: pubKey,
+					Edition:   7,
+				}
+			},
+			valid: true,
+		},
+		{
+			name: "normal edition",
+			g:    genesisNormal,
+			f: func() *AssetDetails {
+				amount := uint64(10)
+				return &AssetDetails{
+					Type:      asset.Normal,
+					ScriptKey: pubKey,
+					Amount:    &amount,
+					Edition:   7,
+				}
+			},
+			valid: false,
+		},
 		{
 			name: "invalid asset type",
 			g:    asset.RandGenesis(t, asset.Type(255)),
@@ -682,6 +710,60 @@ func TestSplitCommitment(t *testing.T) {
 			},
 			err: nil,
 		},
+		{
+			name: "non-divisible asset split",
+			f: func() (*asset.Asset, *SplitLocator, []*SplitLocator) {
+				input := randAsset(
+					t, genesisNormal, groupKeyNormal,
+				)
+				input.Amount = 3
+				input.NonDivisible = true
+
+				root := &SplitLocator{
+					OutputIndex: 0,
+					AssetID:     genesisNormal.ID(),
+					ScriptKey: asset.ToSerialized(
+						input.ScriptKey.PubKey,
+					),
+					Amount: 1,
+				}
+				external := []*SplitLocator{{
+					OutputIndex: 1,
+					AssetID:     genesisNormal.ID(),
+					ScriptKey:   asset.RandSerializedKey(t),
+					Amount:      2,
+				}}
+
+				return input, root, external
+			},
+			err: ErrNonDivisibleSplit,
+		},
+		{
+			name: "non-divisible full value split commitment",
+			f: func() (*asset.Asset, *SplitLocator, []*SplitLocator) {
+				input := randAsset(
+					t, genesisNormal, groupKeyNormal,
+				)
+				input.Amount = 3
+				input.NonDivisible = true
+
+				root := &SplitLocator{
+					OutputIndex: 0,
+					AssetID:     genesisNormal.ID(),
+					ScriptKey:   asset.NUMSCompressedKey,
+					Amount:      0,
+				}
+				external := []*SplitLocator{{
+					OutputIndex: 1,
+					AssetID:     genesisNormal.ID(),
+					ScriptKey:   asset.RandSerializedKey(t),
+					Amount:      3,
+				}}
+
+				return input, root, external
+			},
+			err: nil,
+		},
 		{
 			name: "split commitment remainder underflow",
 			// This test case attempts to underflow the remainder
@@ -1103,6 +1185,238 @@ func TestUpdateTapCommitment(t *testing.T) {
 	)
 }
 
+// TestTapCommitmentApplyDelta tests that applying a batch of changes to a
+// Taproot Asset commitment results in the same commitment as creating it from
+// the resulting assets directly.
+func TestTapCommitmentApplyDelta(t *testing.T) {
+	t.Parallel()
+
+	genesis1 := asset.RandGenesis(t, asset.Normal)
+	genesis2 := asset.RandGenesis(t, asset.Normal)
+	genesis3 := asset.RandGenesis(t, asset.Normal)
+	genesis4 := asset.RandGenesis(t, asset.Normal)
+	groupKey1 := asset.RandGroupKey(t, genesis1)
+
+	grouped1 := randAsset(t, genesis1, groupKey1)
+	grouped2 := randAsset(t, genesis1, groupKey1)
+	grouped3 := randAsset(t, genesis1, groupKey1)
+	ungrouped := randAsset(t, genesis2, nil)
+	removed := randAsset(t, genesis3, nil)
+	added := randAsset(t, genesis4, nil)
+
+	// The random amounts could overflow the sum of the tree.
+	allAssets := []*asset.Asset{
+		grouped1, grouped2, grouped3, ungrouped, removed, added,
+	}
+	for idx, a := range allAssets {
+		a.Amount = uint64(idx + 1)
+	}
+
+	updated := ungrouped.Copy()
+	updated.Amount++
+
+	commitment, err := FromAssets(grouped1, grouped2, ungrouped, removed)
+	require.NoError(t, err)
+	oldRoot := commitment.TapscriptRoot(nil)
+
+	// A delta that both upserts and deletes the same asset is rejected
+	// without modifying the commitment.
+	err = commitment.ApplyDelta(TapCommitmentDelta{
+		Upserts: []*asset.Asset{grouped3, grouped1},
+		Deletes: []*asset.Asset{grouped1},
+	})
+	require.ErrorIs(t, err, ErrConflictingDelta)
+	require.Equal(t, oldRoot, commitment.TapscriptRoot(nil))
+
+	err = commitment.ApplyDelta(TapCommitmentDelta{
+		Upserts: []*asset.Asset{added, added.Copy()},
+	})
+	require.ErrorIs(t, err, ErrAssetDuplicateScriptKey)
+	require.Equal(t, oldRoot, commitment.TapscriptRoot(nil))
+
+	// A valid delta adds, updates and removes assets across all asset
+	// commitments, pruning the asset commitment that ends up empty.
+	err = commitment.ApplyDelta(TapCommitmentDelta{
+		Upserts: []*asset.Asset{grouped3, updated, added},
+		Deletes: []*asset.Asset{grouped1, removed},
+	})
+	require.NoError(t, err)
+
+	expected, err := FromAssets(grouped2, grouped3, updated, added)
+	require.NoError(t, err)
+	require.Equal(
+		t, expected.TapscriptRoot(nil), commitment.TapscriptRoot(nil),
+	)
+	require.Len(t, commitment.Commitments(), 3)
+	_, ok := commitment.Commitment(removed)
+	require.False(t, ok)
+
+	// The commitment can still create proofs for all committed assets.
+	for _, a := range expected.CommittedAssets() {
+		proofAsset, _, err := commitment.Proof(
+			a.TapCommitmentKey(), a.AssetCommitmentKey(),
+		)
+		require.NoError(t, err)
+		require.True(t, proofAsset.DeepEqual(a))
+	}
+
+	// A commitment that only knows its root can't be modified.
+	rootOnly := NewTapCommitmentWithRoot(
+		TapCommitmentV0, commitment.TreeRoot,
+	)
+	err = rootOnly.ApplyDelta(TapCommitmentDelta{
+		Upserts: []*asset.Asset{removed},
+	})
+	require.Error(t, err)
+}
+
+// TestTapCommitmentExclusionProof tests that exclusion proofs can only be
+// created for assets that aren't committed to and that they only verify for the
+// excluded asset and the commitment they were created for.
+func TestTapCommitmentExclusionProof(t *testing.T) {
+	t.Parallel()
+
+	genesis1 := asset.RandGenesis(t, asset.Normal)
+	genesis2 := asset.RandGenesis(t, asset.Normal)
+	groupKey1 := asset.RandGroupKey(t, genesis1)
+
+	committed := randAsset(t, genesis1, groupKey1)
+	sameGroup := randAsset(t, genesis1, groupKey1)
+	otherAsset := randAsset(t, genesis2, nil)
+	committed.Amount, sameGroup.Amount, otherAsset.Amount = 1, 2, 3
+
+	tapCommitment, err := FromAssets(committed)
+	require.NoError(t, err)
+	rootOnly := NewTapCommitmentWithRoot(
+		tapCommitment.Version, tapCommitment.TreeRoot,
+	)
+
+	keys := func(a *asset.Asset) ([32]byte, [32]byte) {
+		return a.TapCommitmentKey(), a.AssetCommitmentKey()
+	}
+
+	// We can't prove that a committed asset is excluded.
+	_, err = tapCommitment.ExclusionProof(keys(committed))
+	require.ErrorIs(t, err, ErrAssetNotExcluded)
+
+	// An asset of the same group is excluded from the existing asset
+	// commitment, an asset of another group by the missing one.
+	for _, excluded := range []*asset.Asset{sameGroup, otherAsset} {
+		exclusionProof, err := tapCommitment.ExclusionProof(
+			keys(excluded),
+		)
+		require.NoError(t, err)
+		hasAssetProof := exclusionProof.AssetProof != nil
+		require.Equal(t, excluded == sameGroup, hasAssetProof)
+
+		// The proof survives an encoding round trip.
+		var buf bytes.Buffer
+		require.NoError(t, exclusionProof.Encode(&buf))
+		var decoded Proof
+		require.NoError(t, decoded.Decode(&buf))
+
+		tapKey, assetKey := keys(excluded)
+		require.NoError(t, decoded.VerifyExclusion(
+			tapKey, assetKey, rootOnly,
+		))
+
+		// It doesn't prove the exclusion of the committed asset.
+		tapKey, assetKey = keys(committed)
+		require.Error(t, decoded.VerifyExclusion(
+			tapKey, assetKey, rootOnly,
+		))
+	}
+
+	// The proof doesn't verify against a different commitment.
+	exclusionProof, err := tapCommitment.ExclusionProof(keys(sameGroup))
+	require.NoError(t, err)
+	otherCommitment, err := FromAssets(committed, otherAsset)
+	require.NoError(t, err)
+
+	tapKey, assetKey := keys(sameGroup)
+	err = exclusionProof.VerifyExclusion(tapKey, assetKey, otherCommitment)
+	require.ErrorIs(t, err, ErrInvalidExclusionProof)
+
+	// And an asset proof of another asset commitment can't be used to
+	// exclude an asset.
+	tapKey, assetKey = keys(otherAsset)
+	err = exclusionProof.VerifyExclusion(tapKey, assetKey, rootOnly)
+	require.ErrorIs(t, err, ErrInvalidExclusionProof)
+}
+
+// TestTapCommitmentVersion tests that the version of a Taproot Asset
+// commitment is committed to, carried by proofs and can only be changed to
+// versions that support all of its asset commitments.
+func TestTapCommitmentVersion(t *testing.T) {
+	t.Parallel()
+
+	genesis := asset.RandGenesis(t, asset.Normal)
+	a := randAsset(t, genesis, nil)
+
+	tapCommitment, err := FromAssets(a)
+	require.NoError(t, err)
+	require.Equal(t, TapCommitmentV0, tapCommitment.Version)
+
+	// The leaf of the commitment commits to its version.
+	leaf := tapCommitment.TapLeaf()
+	require.Equal(
+		t, TapCommitmentLeaf(TapCommitmentV0, tapCommitment.TreeRoot),
+		leaf,
+	)
+	require.Equal(t, byte(TapCommitmentV0), leaf.Script[0])
+	require.True(t, IsTaprootAssetCommitmentScript(leaf.Script))
+
+	// The version survives copies and is carried by proofs.
+	commitmentCopy, err := tapCommitment.Copy()
+	require.NoError(t, err)
+	require.Equal(t, tapCommitment.Version, commitmentCopy.Version)
+
+	_, proof, err := tapCommitment.Proof(
+		a.TapCommitmentKey(), a.AssetCommitmentKey(),
+	)
+	require.NoError(t, err)
+	require.Equal(t, tapCommitment.Version, proof.TaprootAssetProof.Version)
+
+	// Proofs of an unknown commitment version can't be decoded.
+	proof.TaprootAssetProof.Version = LatestTapCommitmentVersion + 1
+	var buf bytes.Buffer
+	require.NoError(t, proof.Encode(&buf))
+	var decoded Proof
+	err = decoded.Decode(&buf)
+	require.ErrorIs(t, err, ErrUnknownTapCommitmentVersion)
+
+	// A commitment can't be changed to an unknown version, or upgraded to
+	// an older and downgraded to a newer one.
+	unknownVersion := LatestTapCommitmentVersion + 1
+	require.False(t, unknownVersion.IsKnown())
+	err = tapCommitment.Upgrade(unknownVersion)
+	require.ErrorIs(t, err, ErrUnknownTapCommitmentVersion)
+	require.Error(t, tapCommitment.Downgrade(unknownVersion))
+	require.NoError(t, tapCommitment.Upgrade(TapCommitmentV0))
+	require.NoError(t, tapCommitment.Downgrade(TapCommitmentV0))
+	require.Equal(t, TapCommitmentV0, tapCommitment.Version)
+
+	// Asset commitments of versions the commitment version doesn't
+	// support are rejected.
+	newAsset := randAsset(t, asset.RandGenesis(t, asset.Normal), nil)
+	newAsset.Version = asset.V0 + 1
+	_, err = FromAssets(newAsset)
+	require.ErrorIs(t, err, ErrUnsupportedAssetVersion)
+
+	assetCommitment, err := NewAssetCommitment(newAsset)
+	require.NoError(t, err)
+	err = tapCommitment.Upsert(assetCommitment)
+	require.ErrorIs(t, err, ErrUnsupportedAssetVersion)
+
+	err = tapCommitment.ApplyDelta(TapCommitmentDelta{
+		Upserts: []*asset.Asset{newAsset},
+	})
+	require.ErrorIs(t, err, ErrUnsupportedAssetVersion)
+
+	// The rejected changes left the commitment untouched.
+	require.Equal(t, commitmentCopy.TreeRoot, tapCommitment.TreeRoot)
+}
+
 // TestAssetCommitmentDeepCopy tests that we're able to properly perform a deep
 // copy of a given asset commitment.
 func TestAssetCommitmentDeepCopy(t *testing.T) {
@@ -1167,6 +1481,249 @@ func TestTapCommitmentDeepCopy(t *testing.T) {
 	require.True(t, mssmt.IsEqualNode(
 		tapCommitment.TreeRoot, newCommitment.TreeRoot),
 	)
+
+	// The copy shares the nodes of the original commitment, but modifying
+	// either of them must not affect the other one.
+	asset3 := randAsset(t, genesis1, groupKey1)
+	asset1.Amount, asset2.Amount, asset3.Amount = 1, 2, 3
+	tapCommitment, err = FromAssets(asset1, asset2)
+	require.NoError(t, err)
+	require.NoError(t, tapCommitment.UpsertAltLeaves(&AltLeaf{
+		Key:  [32]byte{1},
+		Data: []byte("alt"),
+	}))
+	originalRoot := tapCommitment.TapscriptRoot(nil)
+
+	newCommitment, err = tapCommitment.Copy()
+	require.NoError(t, err)
+	require.Equal(t, originalRoot, newCommitment.TapscriptRoot(nil))
+	require.Len(t, newCommitment.AltLeaves(), 1)
+
+	require.NoError(t, newCommitment.ApplyDelta(TapCommitmentDelta{
+		Upserts: []*asset.Asset{asset3},
+		Deletes: []*asset.Asset{asset2},
+	}))
+	require.NoError(t, newCommitment.DeleteAltLeaves([32]byte{1}))
+	require.Equal(t, originalRoot, tapCommitment.TapscriptRoot(nil))
+	require.Len(t, tapCommitment.AltLeaves(), 1)
+
+	expected, err := FromAssets(asset1, asset3)
+	require.NoError(t, err)
+	require.Equal(
+		t, expected.TapscriptRoot(nil),
+		newCommitment.TapscriptRoot(nil),
+	)
+
+	// The original commitment can still be modified and create proofs
+	// for its assets as well.
+	assetCommitment, ok := tapCommitment.Commitment(asset1)
+	require.True(t, ok)
+	require.NoError(t, assetCommitment.Upsert(asset3))
+	require.NoError(t, tapCommitment.Upsert(assetCommitment))
+
+	expected, err = FromAssets(asset1, asset2, asset3)
+	require.NoError(t, err)
+	require.NoError(t, expected.UpsertAltLeaves(&AltLeaf{
+		Key:  [32]byte{1},
+		Data: []byte("alt"),
+	}))
+	require.Equal(
+		t, expected.TapscriptRoot(nil),
+		tapCommitment.TapscriptRoot(nil),
+	)
+	for _, a := range []*asset.Asset{asset1, asset2, asset3} {
+		proofAsset, _, err := tapCommitment.Proof(
+			a.TapCommitmentKey(), a.AssetCommitmentKey(),
+		)
+		require.NoError(t, err)
+		require.True(t, proofAsset.DeepEqual(a))
+	}
+}
+
+// TestTapCommitmentAltLeaves tests that alt leaves can be added to and removed
+// from a Taproot Asset commitment and that their inclusion and exclusion can
+// be proven without affecting the asset commitments.
+func TestTapCommitmentAltLeaves(t *testing.T) {
+	t.Parallel()
+
+	genesis := asset.RandGenesis(t, asset.Normal)
+	asset1 := randAsset(t, genesis, nil)
+	commitment, err := FromAssets(asset1)
+	require.NoError(t, err)
+	assetOnlyRoot := commitment.TapscriptRoot(nil)
+
+	// Without any alt leaves, the alt leaf commitment as a whole is proven
+	// to be excluded.
+	leaf1 := &AltLeaf{Key: test.RandHash(), Data: test.RandBytes(64)}
+	leaf2 := &AltLeaf{Key: test.RandHash(), Data: test.RandBytes(10)}
+	leaf, proof, err := commitment.AltLeafProof(leaf1.Key)
+	require.NoError(t, err)
+	require.Nil(t, leaf)
+	require.Nil(t, proof.AssetProof)
+	derived, err := proof.DeriveByAltLeafExclusion(leaf1.Key)
+	require.NoError(t, err)
+	require.Equal(t, assetOnlyRoot, derived.TapscriptRoot(nil))
+
+	// Adding alt leaves changes the commitment, but the asset can still
+	// be proven.
+	require.NoError(t, commitment.UpsertAltLeaves(leaf1, leaf2))
+	require.NotEqual(t, assetOnlyRoot, commitment.TapscriptRoot(nil))
+	require.Len(t, commitment.AltLeaves(), 2)
+	require.Len(t, commitment.CommittedAssets(), 1)
+
+	assetProof, tapProof, err := commitment.Proof(
+		asset1.TapCommitmentKey(), asset1.AssetCommitmentKey(),
+	)
+	require.NoError(t, err)
+	require.True(t, assetProof.DeepEqual(asset1))
+	derived, err = tapProof.DeriveByAssetInclusion(asset1)
+	require.NoError(t, err)
+	require.Equal(
+		t, commitment.TapscriptRoot(nil), derived.TapscriptRoot(nil),
+	)
+
+	// Both alt leaves can be proven to be included, while an unknown key
+	// is proven to be excluded.
+	for _, altLeaf := range []*AltLeaf{leaf1, leaf2} {
+		leaf, proof, err := commitment.AltLeafProof(altLeaf.Key)
+		require.NoError(t, err)
+		require.Equal(t, altLeaf, leaf)
+
+		derived, err := proof.DeriveByAltLeafInclusion(leaf)
+		require.NoError(t, err)
+		require.Equal(
+			t, commitment.TapscriptRoot(nil),
+			derived.TapscriptRoot(nil),
+		)
+
+		// Different data for the same key must not verify.
+		forged := leaf.Copy()
+		forged.Data = append(forged.Data, 0x01)
+		derived, err = proof.DeriveByAltLeafInclusion(forged)
+		require.NoError(t, err)
+		require.NotEqual(
+			t, commitment.TapscriptRoot(nil),
+			derived.TapscriptRoot(nil),
+		)
+	}
+
+	unknownKey := test.RandHash()
+	leaf, proof, err = commitment.AltLeafProof(unknownKey)
+	require.NoError(t, err)
+	require.Nil(t, leaf)
+	derived, err = proof.DeriveByAltLeafExclusion(unknownKey)
+	require.NoError(t, err)
+	require.Equal(
+		t, commitment.TapscriptRoot(nil), derived.TapscriptRoot(nil),
+	)
+
+	// An asset proof can't be used as an alt leaf proof.
+	_, err = tapProof.DeriveByAltLeafInclusion(leaf1)
+	require.ErrorIs(t, err, ErrInvalidAltLeafProof)
+
+	// Alt leaves survive an encoding round trip.
+	var b bytes.Buffer
+	require.NoError(t, leaf1.Encode(&b))
+	var decodedLeaf AltLeaf
+	require.NoError(t, decodedLeaf.Decode(&b))
+	require.Equal(t, leaf1, &decodedLeaf)
+
+	// Copies and merged commitments carry the alt leaves.
+	commitmentCopy, err := commitment.Copy()
+	require.NoError(t, err)
+	require.Equal(t, commitment.AltLeaves(), commitmentCopy.AltLeaves())
+	require.Equal(
+		t, commitment.TapscriptRoot(nil),
+		commitmentCopy.TapscriptRoot(nil),
+	)
+
+	merged, err := FromAssets(asset1)
+	require.NoError(t, err)
+	require.NoError(t, merged.Merge(commitment))
+	require.Equal(
+		t, commitment.TapscriptRoot(nil), merged.TapscriptRoot(nil),
+	)
+
+	// Removing all alt leaves results in the original commitment again.
+	require.NoError(t, commitment.DeleteAltLeaves(leaf1.Key, leaf2.Key))
+	require.Nil(t, commitment.AltLeaves())
+	require.Equal(t, assetOnlyRoot, commitment.TapscriptRoot(nil))
+}
+
+// TestAltLeavesProof tests that the full set of alt leaves of a Taproot Asset
+// commitment can be proven and that the proof only verifies for exactly that
+// set of leaves.
+func TestAltLeavesProof(t *testing.T) {
+	t.Parallel()
+
+	genesis := asset.RandGenesis(t, asset.Normal)
+	asset1 := randAsset(t, genesis, nil)
+	commitment, err := FromAssets(asset1)
+	require.NoError(t, err)
+
+	// Without any alt leaves, there is nothing to prove.
+	altLeavesProof, err := commitment.AltLeavesProof()
+	require.NoError(t, err)
+	require.Nil(t, altLeavesProof)
+
+	leaf1 := &AltLeaf{Key: test.RandHash(), Data: test.RandBytes(64)}
+	leaf2 := &AltLeaf{Key: test.RandHash(), Data: test.RandBytes(10)}
+	require.NoError(t, commitment.UpsertAltLeaves(leaf1, leaf2))
+
+	altLeavesProof, err = commitment.AltLeavesProof()
+	require.NoError(t, err)
+	require.Equal(t, commitment.AltLeaves(), altLeavesProof.Leaves)
+
+	derived, err := altLeavesProof.DeriveTapCommitment()
+	require.NoError(t, err)
+	require.Equal(
+		t, commitment.TapscriptRoot(nil), derived.TapscriptRoot(nil),
+	)
+
+	// The proof survives an encoding round trip.
+	var b bytes.Buffer
+	require.NoError(t, altLeavesProof.Encode(&b))
+	var decodedProof AltLeavesProof
+	require.NoError(t, decodedProof.Decode(&b))
+	require.Equal(t, altLeavesProof.Leaves, decodedProof.Leaves)
+
+	derived, err = decodedProof.DeriveTapCommitment()
+	require.NoError(t, err)
+	require.Equal(
+		t, commitment.TapscriptRoot(nil), derived.TapscriptRoot(nil),
+	)
+
+	// Leaving out a leaf or changing its data results in a different
+	// commitment.
+	partialProof := *altLeavesProof
+	partialProof.Leaves = altLeavesProof.Leaves[:1]
+	derived, err = partialProof.DeriveTapCommitment()
+	require.NoError(t, err)
+	require.NotEqual(
+		t, commitment.TapscriptRoot(nil), derived.TapscriptRoot(nil),
+	)
+
+	forged := leaf1.Copy()
+	forged.Data = append(forged.Data, 0x01)
+	forgedProof := *altLeavesProof
+	forgedProof.Leaves = []*AltLeaf{forged, leaf2}
+	derived, err = forgedProof.DeriveTapCommitment()
+	require.NoError(t, err)
+	require.NotEqual(
+		t, commitment.TapscriptRoot(nil), derived.TapscriptRoot(nil),
+	)
+
+	// Each leaf can only be revealed once, and at least one leaf must be
+	// revealed.
+	duplicateProof := *altLeavesProof
+	duplicateProof.Leaves = []*AltLeaf{leaf1, leaf2, leaf1}
+	_, err = duplicateProof.DeriveTapCommitment()
+	require.ErrorIs(t, err, ErrDuplicateAltLeaf)
+
+	emptyProof := *altLeavesProof
+	emptyProof.Leaves = nil
+	_, err = emptyProof.DeriveTapCommitment()
+	require.ErrorIs(t, err, ErrMissingAltLeaf)
 }
 
 // TestTaprootAssetCommitmentScript tests that we're able to properly verify if
@@ -1176,4 +1733,192 @@ func TestIsTaprootAssetCommitmentScript(t *testing.T) {
 
 	require.True(t, IsTaprootAssetCommitmentScript(testTapCommitmentScript))
 	require.False(t, IsTaprootAssetCommitmentScript(TaprootAssetsMarker[:]))
+
+	// A script of an unknown commitment version isn't recognized.
+	unknownVersion := append([]byte{}, testTapCommitmentScript...)
+	unknownVersion[0] = byte(LatestTapCommitmentVersion + 1)
+	require.False(t, IsTaprootAssetCommitmentScript(unknownVersion))
+}
+
+// generatedTestVectorName is the name of the test vector file that is
+// generated by TestTapCommitmentTestVectors.
+const generatedTestVectorName = "tap_commitment_generated.json"
+
+// validCommitmentTestCase is a test vector of a set of assets and the expected
+// Taproot Asset commitment. The assets are grouped into asset commitments by
+// their Taproot Asset commitment key in the order they appear in, each group is
+// committed to with NewAssetCommitment and the resulting asset commitments are
+// combined with NewTapCommitment.
+type validCommitmentTestCase struct {
+	Assets        []*asset.Asset `json:"assets"`
+	RootHash      string         `json:"root_hash"`
+	RootSum       uint64         `json:"root_sum,string"`
+	TapLeafScript string         `json:"tap_leaf_script"`
+	TapscriptRoot string         `json:"tapscript_root"`
+	Comment       string         `json:"comment"`
+}
+
+// errorCommitmentTestCase is a test vector of a set of assets that can't be
+// committed to.
+type errorCommitmentTestCase struct {
+	Assets  []*asset.Asset `json:"assets"`
+	Error   string         `json:"error"`
+	Comment string         `json:"comment"`
+}
+
+// commitmentTestVectors are the test vectors for Taproot Asset commitments.
+type commitmentTestVectors = test.TestVectors[
+	validCommitmentTestCase, errorCommitmentTestCase,
+]
+
+// vectorCommitment creates the Taproot Asset commitment for the assets of a
+// test vector.
+func vectorCommitment(assets []*asset.Asset) (*TapCommitment, error) {
+	var (
+		keys   [][32]byte
+		groups = make(map[[32]byte][]*asset.Asset)
+	)
+	for _, a := range assets {
+		key := a.TapCommitmentKey()
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], a)
+	}
+
+	assetCommitments := make([]*AssetCommitment, 0, len(keys))
+	for _, key := range keys {
+		assetCommitment, err := NewAssetCommitment(groups[key]...)
+		if err != nil {
+			return nil, err
+		}
+		assetCommitments = append(assetCommitments, assetCommitment)
+	}
+
+	return NewTapCommitment(assetCommitments...)
+}
+
+// vectorAsset creates a deterministic asset for the commitment test vectors.
+func vectorAsset(t *testing.T, tag string, amount uint64,
+	scriptKeySeed byte) *asset.Asset {
+
+	var keyBytes [32]byte
+	keyBytes[31] = scriptKeySeed
+	_, scriptKey := btcec.PrivKeyFromBytes(keyBytes[:])
+
+	genesis := asset.Genesis{
+		FirstPrevOut: wire.OutPoint{
+			Hash:  [32]byte{1, 2, 3},
+			Index: 1,
+		},
+		Tag:         tag,
+		OutputIndex: 1,
+		Type:        asset.Normal,
+	}
+	a, err := asset.New(
+		genesis, amount, 0, 0, asset.NewScriptKey(scriptKey), nil,
+	)
+	require.NoError(t, err)
+
+	return a
+}
+
+// TestTapCommitmentTestVectors generates the Taproot Asset commitment test
+// vectors and then asserts that all test vector files in the testdata
+// directory are valid.
+func TestTapCommitmentTestVectors(t *testing.T) {
+	t.Parallel()
+
+	asset1 := vectorAsset(t, "asset 1", 1000, 1)
+	asset1Other := vectorAsset(t, "asset 1", 500, 2)
+	asset2 := vectorAsset(t, "asset 2", 7, 1)
+
+	// A group key with an invalid signature can't be committed to.
+	grouped := vectorAsset(t, "grouped", 1, 3)
+	grouped.GroupKey = &asset.GroupKey{
+		GroupPubKey: *grouped.ScriptKey.PubKey,
+	}
+
+	vectors := &commitmentTestVectors{
+		ValidTestCases: []*validCommitmentTestCase{{
+			Assets:  []*asset.Asset{asset1},
+			Comment: "single asset",
+		}, {
+			Assets:  []*asset.Asset{asset1, asset1Other},
+			Comment: "two assets with the same ID",
+		}, {
+			Assets:  []*asset.Asset{asset1, asset1Other, asset2},
+			Comment: "assets with different IDs",
+		}},
+		ErrorTestCases: []*errorCommitmentTestCase{{
+			Assets:  []*asset.Asset{asset1, asset1.Copy()},
+			Error:   ErrAssetDuplicateScriptKey.Error(),
+			Comment: "duplicate script key",
+		}, {
+			Assets:  []*asset.Asset{grouped},
+			Error:   ErrAssetGenesisInvalidSig.Error(),
+			Comment: "invalid group key signature",
+		}},
+	}
+	for _, tc := range vectors.ValidTestCases {
+		tapCommitment, err := vectorCommitment(tc.Assets)
+		require.NoError(t, err)
+
+		rootHash := tapCommitment.TreeRoot.NodeHash()
+		tapscriptRoot := tapCommitment.TapscriptRoot(nil)
+		tc.RootHash = hex.EncodeToString(rootHash[:])
+		tc.RootSum = tapCommitment.TreeRoot.NodeSum()
+		tc.TapLeafScript = hex.EncodeToString(
+			tapCommitment.TapLeaf().Script,
+		)
+		tc.TapscriptRoot = hex.EncodeToString(tapscriptRoot[:])
+	}
+
+	test.WriteTestVectors(
+		t, filepath.Join("testdata", generatedTestVectorName), vectors,
+	)
+
+	vectorFiles := test.TestVectorFiles(t, "testdata", "tap_commitment_")
+	for _, fileName := range vectorFiles {
+		var fileVectors commitmentTestVectors
+		test.ParseTestVectors(t, fileName, &fileVectors)
+
+		t.Run(fileName, func(t *testing.T) {
+			runCommitmentTestVectors(t, &fileVectors)
+		})
+	}
+}
+
+// runCommitmentTestVectors asserts that the given commitment test vectors are
+// valid.
+func runCommitmentTestVectors(t *testing.T, vectors *commitmentTestVectors) {
+	for _, tc := range vectors.ValidTestCases {
+		tapCommitment, err := vectorCommitment(tc.Assets)
+		require.NoError(t, err, tc.Comment)
+
+		rootHash := tapCommitment.TreeRoot.NodeHash()
+		tapscriptRoot := tapCommitment.TapscriptRoot(nil)
+		require.Equal(
+			t, tc.RootHash, hex.EncodeToString(rootHash[:]),
+			tc.Comment,
+		)
+		require.Equal(
+			t, tc.RootSum, tapCommitment.TreeRoot.NodeSum(),
+			tc.Comment,
+		)
+		require.Equal(
+			t, tc.TapLeafScript,
+			hex.EncodeToString(tapCommitment.TapLeaf().Script),
+			tc.Comment,
+		)
+		require.Equal(
+			t, tc.TapscriptRoot,
+			hex.EncodeToString(tapscriptRoot[:]), tc.Comment,
+		)
+	}
+
+	for _, tc := range vectors.ErrorTestCases {
+		_, err := vectorCommitment(tc.Assets)
+		require.ErrorContains(t, err, tc.Error, tc.Comment)
+	}
 }
@@ -2,8 +2,10 @@ package commitment
 
 import (
 	"bytes"
+	"fmt"
 	"io"
 
+	"github.com/lightninglabs/taproot-assets/asset"
 	"github.com/lightninglabs/taproot-assets/mssmt"
 	"github.com/lightningnetwork/lnd/tlv"
 )
@@ -18,7 +20,8 @@ func ProofEncoder(w io.Writer, val any, buf *[8]byte) error {
 func ProofDecoder(r io.Reader, val any, buf *[8]byte, l uint64) error {
 	if typ, ok := val.(*Proof); ok {
 		var proofBytes []byte
-		if err := tlv.DVarBytes(r, &proofBytes, buf, l); err != nil {
+		err := asset.InlineVarBytesDecoder(r, &proofBytes, buf, l)
+		if err != nil {
 			return err
 		}
 		var proof Proof
@@ -50,7 +53,8 @@ func AssetProofEncoder(w io.Writer, val any, buf *[8]byte) error {
 func AssetProofDecoder(r io.Reader, val any, buf *[8]byte, l uint64) error {
 	if typ, ok := val.(**AssetProof); ok {
 		var streamBytes []byte
-		if err := tlv.DVarBytes(r, &streamBytes, buf, l); err != nil {
+		err := asset.InlineVarBytesDecoder(r, &streamBytes, buf, l)
+		if err != nil {
 			return err
 		}
 		var proof AssetProof
@@ -92,7 +96,8 @@ func TaprootAssetProofDecoder(r io.Reader, val any, buf *[8]byte,
 
 	if typ, ok := val.(*TaprootAssetProof); ok {
 		var streamBytes []byte
-		if err := tlv.DVarBytes(r, &streamBytes, buf, l); err != nil {
+		err := asset.InlineVarBytesDecoder(r, &streamBytes, buf, l)
+		if err != nil {
 			return err
 		}
 		var proof TaprootAssetProof
@@ -113,6 +118,34 @@ func TaprootAssetProofDecoder(r io.Reader, val any, buf *[8]byte,
 	return tlv.NewTypeForEncodingErr(val, "commitment.TaprootAssetProof")
 }
 
+func TapCommitmentVersionEncoder(w io.Writer, val any, buf *[8]byte) error {
+	if t, ok := val.(*TapCommitmentVersion); ok {
+		return tlv.EUint8T(w, uint8(*t), buf)
+	}
+	return tlv.NewTypeForEncodingErr(val, "TapCommitmentVersion")
+}
+
+func TapCommitmentVersionDecoder(r io.Reader, val any, buf *[8]byte,
+	l uint64) error {
+
+	if typ, ok := val.(*TapCommitmentVersion); ok {
+		var t uint8
+		if err := tlv.DUint8(r, &t, buf, l); err != nil {
+			return err
+		}
+
+		version := TapCommitmentVersion(t)
+		if !version.IsKnown() {
+			return fmt.Errorf("%w: %d",
+				ErrUnknownTapCommitmentVersion, version)
+		}
+
+		*typ = version
+		return nil
+	}
+	return tlv.NewTypeForDecodingErr(val, "TapCommitmentVersion", l, 1)
+}
+
 func TreeProofEncoder(w io.Writer, val any, buf *[8]byte) error {
 	if t, ok := val.(*mssmt.Proof); ok {
 		return t.Compress().Encode(w)
@@ -122,12 +155,18 @@ func TreeProofEncoder(w io.Writer, val any, buf *[8]byte) error {
 
 func TreeProofDecoder(r io.Reader, val any, buf *[8]byte, l uint64) error {
 	if typ, ok := val.(*mssmt.Proof); ok {
+		// We refuse to allocate more than the maximum proof size.
+		if l > mssmt.MaxCompressedProofSize {
+			return mssmt.ErrProofTooLarge
+		}
+
 		var proofBytes []byte
-		if err := tlv.DVarBytes(r, &proofBytes, buf, l); err != nil {
+		err := asset.InlineVarBytesDecoder(r, &proofBytes, buf, l)
+		if err != nil {
 			return err
 		}
 		var proof mssmt.CompressedProof
-		if err := proof.Decode(bytes.NewReader(proofBytes)); err != nil {
+		if err := proof.DecodeBytes(proofBytes); err != nil {
 			return err
 		}
 
@@ -173,7 +212,9 @@ func TapscriptPreimageDecoder(r io.Reader, val any, buf *[8]byte,
 		preimage.SiblingType = TapscriptPreimageType(siblingType)
 
 		// Now we'll read out the pre-image itself.
-		err = tlv.DVarBytes(r, &preimage.SiblingPreimage, buf, l-1)
+		err = asset.InlineVarBytesDecoder(
+			r, &preimage.SiblingPreimage, buf, l-1,
+		)
 		if err != nil {
 			return err
 		}
@@ -184,3 +225,95 @@ func TapscriptPreimageDecoder(r io.Reader, val any, buf *[8]byte,
 
 	return tlv.NewTypeForDecodingErr(val, "*TapscriptPreimage", l, l)
 }
+
+func AltLeavesEncoder(w io.Writer, val any, buf *[8]byte) error {
+	if t, ok := val.(*[]*AltLeaf); ok {
+		numLeaves := uint64(len(*t))
+		if err := tlv.WriteVarInt(w, numLeaves, buf); err != nil {
+			return err
+		}
+		var leafBuf bytes.Buffer
+		for _, leaf := range *t {
+			if err := leaf.Encode(&leafBuf); err != nil {
+				return err
+			}
+			leafBytes := leafBuf.Bytes()
+			err := asset.VarBytesEncoder(w, &leafBytes, buf)
+			if err != nil {
+				return err
+			}
+			leafBuf.Reset()
+		}
+		return nil
+	}
+	return tlv.NewTypeForEncodingErr(val, "[]*AltLeaf")
+}
+
+func AltLeavesDecoder(r io.Reader, val any, buf *[8]byte, l uint64) error {
+	if typ, ok := val.(*[]*AltLeaf); ok {
+		var leavesBytes []byte
+		err := asset.InlineVarBytesDecoder(r, &leavesBytes, buf, l)
+		if err != nil {
+			return err
+		}
+
+		leavesReader := bytes.NewReader(leavesBytes)
+		numLeaves, err := tlv.ReadVarInt(leavesReader, buf)
+		if err != nil {
+			return err
+		}
+
+		// Each leaf takes up at least one byte, so we can't have more
+		// leaves than bytes left to read.
+		if numLeaves > uint64(leavesReader.Len()) {
+			return fmt.Errorf("too many alt leaves: %d", numLeaves)
+		}
+
+		leaves := make([]*AltLeaf, 0, numLeaves)
+		for i := uint64(0); i < numLeaves; i++ {
+			var leafBytes []byte
+			err := asset.VarBytesDecoder(
+				leavesReader, &leafBytes, buf, 0,
+			)
+			if err != nil {
+				return err
+			}
+			var leaf AltLeaf
+			err = leaf.Decode(bytes.NewReader(leafBytes))
+			if err != nil {
+				return err
+			}
+			leaves = append(leaves, &leaf)
+		}
+		*typ = leaves
+		return nil
+	}
+	return tlv.NewTypeForEncodingErr(val, "[]*AltLeaf")
+}
+
+func AltLeavesProofEncoder(w io.Writer, val any, buf *[8]byte) error {
+	if t, ok := val.(**AltLeavesProof); ok {
+		return (*t).Encode(w)
+	}
+	return tlv.NewTypeForEncodingErr(val, "*AltLeavesProof")
+}
+
+func AltLeavesProofDecoder(r io.Reader, val any, buf *[8]byte,
+	l uint64) error {
+
+	if typ, ok := val.(**AltLeavesProof); ok {
+		var proofBytes []byte
+		err := asset.InlineVarBytesDecoder(r, &proofBytes, buf, l)
+		if err != nil {
+			return err
+		}
+		var proof AltLeavesProof
+		err = proof.Decode(bytes.NewReader(proofBytes))
+		if err != nil {
+			return err
+		}
+		*typ = &proof
+		return nil
+	}
+	return tlv.NewTypeForEncodingErr(val, "*AltLeavesProof")
+}
@@ -29,6 +29,15 @@ type AssetDetails struct {
 	// confirmation height in the blockchain at which the asset(s) to mint
 	// can be spent from `ScriptKey`.
 	RelativeLockTime uint64
+
+	// NonDivisible marks the minted normal asset(s) as non-divisible,
+	// meaning they can never be split into multiple non-zero outputs.
+	NonDivisible bool
+
+	// Edition is the serial number of the minted collectible if it's one
+	// of several editions of the same genesis. Editions are numbered
+	// starting from 1, a value of 0 mints a plain collectible.
+	Edition uint64
 }
 
 // mintAssets mints a series of assets based on the same asset ID and group key.
@@ -36,6 +45,7 @@ func mintAssets(genesis asset.Genesis, groupKey *asset.GroupKey,
 	mintDetails ...*AssetDetails) ([]*asset.Asset, error) {
 
 	assets := make([]*asset.Asset, 0, len(mintDetails))
+	editions := make(map[uint64]struct{})
 	for _, mint := range mintDetails {
 		if mint.Type != genesis.Type {
 			return nil, fmt.Errorf("mint asset type mismatch, "+
@@ -65,6 +75,26 @@ func mintAssets(genesis asset.Genesis, groupKey *asset.GroupKey,
 				mint.Type)
 		}
 
+		if mint.NonDivisible && mint.Type != asset.Normal {
+			return nil, fmt.Errorf("only normal assets can be "+
+				"non-divisible, got %v", mint.Type)
+		}
+
+		if mint.Edition > 0 && mint.Type != asset.Collectible {
+			return nil, fmt.Errorf("only collectible assets can be "+
+				"minted as editions, got %v", mint.Type)
+		}
+
+		// Every edition of a genesis is a unique unit, so the same
+		// serial can't be minted twice.
+		if mint.Edition > 0 {
+			if _, ok := editions[mint.Edition]; ok {
+				return nil, fmt.Errorf("duplicate edition %d",
+					mint.Edition)
+			}
+			editions[mint.Edition] = struct{}{}
+		}
+
 		a, err := asset.New(
 			genesis, amount, mint.LockTime, mint.RelativeLockTime,
 			asset.NewScriptKeyBip86(mint.ScriptKey), groupKey,
@@ -72,6 +102,8 @@ func mintAssets(genesis asset.Genesis, groupKey *asset.GroupKey,
 		if err != nil {
 			return nil, err
 		}
+		a.NonDivisible = mint.NonDivisible
+		a.Edition = mint.Edition
 
 		assets = append(assets, a)
 	}
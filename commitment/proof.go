@@ -2,6 +2,7 @@ package commitment
 
 import (
 	"errors"
+	"fmt"
 	"io"
 
 	"github.com/lightninglabs/taproot-assets/asset"
@@ -14,6 +15,14 @@ var (
 	// ErrMissingAssetProof is an error returned when attempting to derive a
 	// TapCommitment and an AssetProof is required but missing.
 	ErrMissingAssetProof = errors.New("missing asset proof")
+
+	// ErrAssetNotExcluded is returned when attempting to create an
+	// exclusion proof for an asset that is committed to.
+	ErrAssetNotExcluded = errors.New("asset is committed to")
+
+	// ErrInvalidExclusionProof is returned when an exclusion proof doesn't
+	// lead to the expected Taproot Asset commitment.
+	ErrInvalidExclusionProof = errors.New("invalid exclusion proof")
 )
 
 // AssetProof is the proof used along with an asset leaf to arrive at the root
@@ -36,9 +45,9 @@ type AssetProof struct {
 type TaprootAssetProof struct {
 	mssmt.Proof
 
-	// Version is the max version committed of the AssetCommitment's
-	// included in the TapCommitment.
-	Version asset.Version
+	// Version is the version of the TapCommitment, which determines the
+	// layout of its tapscript leaf.
+	Version TapCommitmentVersion
 }
 
 // Proof represents a full commitment proof for a particular `Asset`. It proves
@@ -195,3 +204,47 @@ func (p Proof) DeriveByAssetCommitmentExclusion(tapCommitmentKey [32]byte) (
 		p.TaprootAssetProof.Version, tapProofRoot,
 	), nil
 }
+
+// DeriveByExclusion derives the Taproot Asset commitment excluding the asset
+// identified by the given keys. Depending on whether the proof contains an
+// AssetProof, this either proves that the asset doesn't exist within its
+// AssetCommitment or that there is no AssetCommitment for the asset at all.
+func (p Proof) DeriveByExclusion(tapCommitmentKey,
+	assetCommitmentKey [32]byte) (*TapCommitment, error) {
+
+	if p.AssetProof == nil {
+		return p.DeriveByAssetCommitmentExclusion(tapCommitmentKey)
+	}
+
+	// The asset proof must be for the asset commitment the asset would be
+	// part of, otherwise it doesn't prove anything about the asset.
+	if p.AssetProof.AssetID != tapCommitmentKey {
+		return nil, fmt.Errorf("%w: asset proof for %x instead of %x",
+			ErrInvalidExclusionProof, p.AssetProof.AssetID[:],
+			tapCommitmentKey[:])
+	}
+
+	return p.DeriveByAssetExclusion(assetCommitmentKey)
+}
+
+// VerifyExclusion verifies that the proof proves that the asset identified by
+// the given keys isn't committed to by the given Taproot Asset commitment. The
+// commitment only needs to know its root, so it can be created with
+// NewTapCommitmentWithRoot.
+func (p Proof) VerifyExclusion(tapCommitmentKey, assetCommitmentKey [32]byte,
+	expected *TapCommitment) error {
+
+	derived, err := p.DeriveByExclusion(
+		tapCommitmentKey, assetCommitmentKey,
+	)
+	if err != nil {
+		return err
+	}
+
+	if derived.TapscriptRoot(nil) != expected.TapscriptRoot(nil) {
+		return fmt.Errorf("%w: commitment root mismatch",
+			ErrInvalidExclusionProof)
+	}
+
+	return nil
+}
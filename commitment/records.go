@@ -18,6 +18,12 @@ const (
 
 	ProofAssetProofType        tlv.Type = 0
 	ProofTaprootAssetProofType tlv.Type = 1
+
+	AltLeafKeyType  tlv.Type = 0
+	AltLeafDataType tlv.Type = 1
+
+	AltLeavesProofLeavesType            tlv.Type = 0
+	AltLeavesProofTaprootAssetProofType tlv.Type = 1
 )
 
 func ProofAssetProofRecord(proof **AssetProof) tlv.Record {
@@ -63,11 +69,13 @@ func AssetProofAssetIDRecord(assetID *[32]byte) tlv.Record {
 
 func AssetProofRecord(proof *mssmt.Proof) tlv.Record {
 	sizeFunc := func() uint64 {
-		var buf bytes.Buffer
-		if err := proof.Compress().Encode(&buf); err != nil {
+		size, err := proof.Compress().EncodedSize(
+			mssmt.CompressedProofV1,
+		)
+		if err != nil {
 			panic(err)
 		}
-		return uint64(len(buf.Bytes()))
+		return uint64(size)
 	}
 	return tlv.MakeDynamicRecord(
 		AssetProofType, proof, sizeFunc, TreeProofEncoder,
@@ -75,23 +83,73 @@ func AssetProofRecord(proof *mssmt.Proof) tlv.Record {
 	)
 }
 
-func TaprootAssetProofVersionRecord(version *asset.Version) tlv.Record {
+func TaprootAssetProofVersionRecord(
+	version *TapCommitmentVersion) tlv.Record {
+
 	return tlv.MakeStaticRecord(
-		TaprootAssetProofVersionType, version, 1, asset.VersionEncoder,
-		asset.VersionDecoder,
+		TaprootAssetProofVersionType, version, 1,
+		TapCommitmentVersionEncoder, TapCommitmentVersionDecoder,
 	)
 }
 
 func TaprootAssetProofRecord(proof *mssmt.Proof) tlv.Record {
 	sizeFunc := func() uint64 {
-		var buf bytes.Buffer
-		if err := proof.Compress().Encode(&buf); err != nil {
+		size, err := proof.Compress().EncodedSize(
+			mssmt.CompressedProofV1,
+		)
+		if err != nil {
 			panic(err)
 		}
-		return uint64(len(buf.Bytes()))
+		return uint64(size)
 	}
 	return tlv.MakeDynamicRecord(
 		TaprootAssetProofType, proof, sizeFunc, TreeProofEncoder,
 		TreeProofDecoder,
 	)
 }
+
+func AltLeafKeyRecord(key *[32]byte) tlv.Record {
+	return tlv.MakePrimitiveRecord(AltLeafKeyType, key)
+}
+
+func AltLeafDataRecord(data *[]byte) tlv.Record {
+	sizeFunc := func() uint64 {
+		return uint64(len(*data))
+	}
+	return tlv.MakeDynamicRecord(
+		AltLeafDataType, data, sizeFunc, tlv.EVarBytes,
+		asset.InlineVarBytesDecoder,
+	)
+}
+
+func AltLeavesProofLeavesRecord(leaves *[]*AltLeaf) tlv.Record {
+	sizeFunc := func() uint64 {
+		var buf bytes.Buffer
+		err := AltLeavesEncoder(&buf, leaves, &[8]byte{})
+		if err != nil {
+			panic(err)
+		}
+		return uint64(len(buf.Bytes()))
+	}
+	return tlv.MakeDynamicRecord(
+		AltLeavesProofLeavesType, leaves, sizeFunc, AltLeavesEncoder,
+		AltLeavesDecoder,
+	)
+}
+
+func AltLeavesProofTaprootAssetProofRecord(
+	proof *TaprootAssetProof) tlv.Record {
+
+	sizeFunc := func() uint64 {
+		var buf bytes.Buffer
+		err := TaprootAssetProofEncoder(&buf, proof, &[8]byte{})
+		if err != nil {
+			panic(err)
+		}
+		return uint64(len(buf.Bytes()))
+	}
+	return tlv.MakeDynamicRecord(
+		AltLeavesProofTaprootAssetProofType, proof, sizeFunc,
+		TaprootAssetProofEncoder, TaprootAssetProofDecoder,
+	)
+}
@@ -52,6 +52,13 @@ var (
 	ErrNonZeroSplitAmount = errors.New(
 		"un-spendable root locator has non-zero amount",
 	)
+
+	// ErrNonDivisibleSplit is an error returned when a non-divisible asset
+	// is attempted to be split into multiple non-zero outputs.
+	ErrNonDivisibleSplit = errors.New(
+		"non-divisible asset cannot be split into multiple non-zero " +
+			"outputs",
+	)
 )
 
 // SplitLocator encodes the data that uniquely identifies an asset split within
@@ -168,6 +175,15 @@ func NewSplitCommitment(ctx context.Context, inputs []SplitCommitmentInput,
 		}
 	}
 
+	// A non-divisible asset can only ever be moved as a whole, so at most
+	// one of the locators can carry a non-zero amount.
+	if inputs[0].Asset.NonDivisible && rootLocator.Amount != 0 {
+		return nil, ErrNonDivisibleSplit
+	}
+	if inputs[0].Asset.NonDivisible && len(externalLocators) != 1 {
+		return nil, ErrNonDivisibleSplit
+	}
+
 	// The only valid un-spendable root locator uses the correct
 	// un-spendable script key and has zero value.
 	if rootLocator.Amount == 0 &&
@@ -187,7 +203,7 @@ func NewSplitCommitment(ctx context.Context, inputs []SplitCommitmentInput,
 	// the total input amount.
 	locators := append(externalLocators, rootLocator)
 	splitAssets := make(SplitSet, len(locators))
-	splitTree := mssmt.NewCompactedTree(mssmt.NewDefaultStore())
+	splitLeaves := make(map[[32]byte]*mssmt.LeafNode, len(locators))
 	remainingAmount := totalInputAmount
 	rootIdx := len(locators) - 1
 	addAssetSplit := func(locator *SplitLocator) error {
@@ -211,16 +227,11 @@ func NewSplitCommitment(ctx context.Context, inputs []SplitCommitmentInput,
 			OutputIndex: locator.OutputIndex,
 		}
 
-		splitKey := locator.Hash()
 		splitLeaf, err := assetSplit.Leaf()
 		if err != nil {
 			return err
 		}
-
-		_, err = splitTree.Insert(ctx, splitKey, splitLeaf)
-		if err != nil {
-			return err
-		}
+		splitLeaves[locator.Hash()] = splitLeaf
 
 		// Ensure that we won't underflow the remaining amount. None of
 		// the split amounts should be greater than the input amount.
@@ -245,11 +256,17 @@ func NewSplitCommitment(ctx context.Context, inputs []SplitCommitmentInput,
 		return nil, ErrInvalidSplitAmount
 	}
 
-	// With all the split assets created and inserted into the split
-	// commitment tree, we'll create the root asset. This root asset
-	// commits to the root of the split commitment tree and should have a
-	// valid witness generated over the virtual transaction enabling the
-	// state transition.
+	// With all the split assets created, we insert them into the split
+	// commitment tree at once.
+	splitTree := mssmt.NewCompactedTree(mssmt.NewDefaultStore())
+	if _, err := splitTree.InsertMany(ctx, splitLeaves); err != nil {
+		return nil, err
+	}
+
+	// With the split commitment tree complete, we'll create the root
+	// asset. This root asset commits to the root of the split commitment
+	// tree and should have a valid witness generated over the virtual
+	// transaction enabling the state transition.
 	var err error
 	rootAsset := splitAssets[*rootLocator].Copy()
 
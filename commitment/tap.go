@@ -35,6 +35,25 @@ var (
 		"tap commitment: missing asset commitment",
 	)
 
+	// ErrConflictingDelta is returned if a Taproot Asset commitment delta
+	// both upserts and deletes the same asset.
+	ErrConflictingDelta = errors.New(
+		"tap commitment: asset both upserted and deleted",
+	)
+
+	// ErrUnknownTapCommitmentVersion is returned when encountering a
+	// Taproot Asset commitment version that isn't known.
+	ErrUnknownTapCommitmentVersion = errors.New(
+		"tap commitment: unknown version",
+	)
+
+	// ErrUnsupportedAssetVersion is returned when attempting to commit to
+	// an asset commitment whose version isn't supported by the version of
+	// the Taproot Asset commitment.
+	ErrUnsupportedAssetVersion = errors.New(
+		"tap commitment: asset version not supported",
+	)
+
 	// TaprootAssetCommitmentScriptSize is the size of the Taproot Asset
 	// commitment script:
 	//
@@ -45,6 +64,61 @@ var (
 	TaprootAssetCommitmentScriptSize = 1 + 32 + 32 + 8
 )
 
+// TapCommitmentVersion denotes the version of the layout of a Taproot Asset
+// commitment. The version is committed to as the first byte of the tapscript
+// leaf of the commitment, which allows different layouts to coexist.
+type TapCommitmentVersion uint8
+
+const (
+	// TapCommitmentV0 is the initial Taproot Asset commitment layout,
+	// which can only commit to V0 assets.
+	TapCommitmentV0 TapCommitmentVersion = 0
+
+	// LatestTapCommitmentVersion is the newest known Taproot Asset
+	// commitment layout.
+	LatestTapCommitmentVersion = TapCommitmentV0
+)
+
+// maxAssetVersion returns the highest asset version that can be committed to
+// with the commitment layout of the version.
+func (v TapCommitmentVersion) maxAssetVersion() (asset.Version, error) {
+	switch v {
+	case TapCommitmentV0:
+		return asset.V0, nil
+
+	default:
+		return 0, fmt.Errorf("%w: %d", ErrUnknownTapCommitmentVersion,
+			v)
+	}
+}
+
+// IsKnown returns true if the version is a known Taproot Asset commitment
+// layout.
+func (v TapCommitmentVersion) IsKnown() bool {
+	_, err := v.maxAssetVersion()
+	return err == nil
+}
+
+// tapCommitmentVersionFor returns the oldest commitment version that can commit
+// to assets of the given version.
+func tapCommitmentVersionFor(
+	assetVersion asset.Version) (TapCommitmentVersion, error) {
+
+	for v := TapCommitmentV0; v <= LatestTapCommitmentVersion; v++ {
+		maxAssetVersion, err := v.maxAssetVersion()
+		if err != nil {
+			return 0, err
+		}
+
+		if assetVersion <= maxAssetVersion {
+			return v, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w: asset version %d", ErrUnsupportedAssetVersion,
+		assetVersion)
+}
+
 // AssetCommitments is the set of assetCommitments backing a TapCommitment.
 // The map is keyed by the AssetCommitment's TapCommitmentKey.
 type AssetCommitments map[[32]byte]*AssetCommitment
@@ -54,9 +128,9 @@ type AssetCommitments map[[32]byte]*AssetCommitment
 // leaves represented as `asset_version || asset_tree_root || asset_sum`, are
 // keyed by their `asset_group_key` or `asset_id` otherwise.
 type TapCommitment struct {
-	// Version is the maximum Taproot Asset version found within all of the
-	// assets committed.
-	Version asset.Version
+	// Version is the version of the layout of the commitment. It
+	// determines which versions of asset commitments can be committed to.
+	Version TapCommitmentVersion
 
 	// TreeRoot is the root node of the MS-SMT containing all of the asset
 	// commitments.
@@ -74,14 +148,18 @@ type TapCommitment struct {
 	// NOTE: This is nil when TapCommitment is constructed with
 	// NewTapCommitmentWithRoot.
 	assetCommitments AssetCommitments
+
+	// altLeaves is the commitment to all application-defined alt leaves
+	// found within the tree above. This is nil if there are no alt leaves.
+	altLeaves *altLeafCommitment
 }
 
 // NewTapCommitment creates a new Taproot Asset commitment for the given asset
 // commitments capable of computing merkle proofs.
 func NewTapCommitment(assets ...*AssetCommitment) (*TapCommitment, error) {
 	maxVersion := asset.V0
-	tree := mssmt.NewCompactedTree(mssmt.NewDefaultStore())
 	assetCommitments := make(AssetCommitments, len(assets))
+	leaves := make(map[[32]byte]*mssmt.LeafNode, len(assets))
 	for _, asset := range assets {
 		asset := asset
 
@@ -89,24 +167,30 @@ func NewTapCommitment(assets ...*AssetCommitment) (*TapCommitment, error) {
 			maxVersion = asset.Version
 		}
 		key := asset.TapCommitmentKey()
-		leaf := asset.TapCommitmentLeaf()
-
-		// TODO(bhandras): thread the context through.
-		_, err := tree.Insert(context.TODO(), key, leaf)
-		if err != nil {
-			return nil, err
-		}
+		leaves[key] = asset.TapCommitmentLeaf()
 
 		assetCommitments[key] = asset
 	}
 
+	version, err := tapCommitmentVersionFor(maxVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	// TODO(bhandras): thread the context through.
+	tree := mssmt.NewCompactedTree(mssmt.NewDefaultStore())
+	_, err = tree.InsertMany(context.TODO(), leaves)
+	if err != nil {
+		return nil, err
+	}
+
 	root, err := tree.Root(context.Background())
 	if err != nil {
 		return nil, err
 	}
 
 	return &TapCommitment{
-		Version:          maxVersion,
+		Version:          version,
 		TreeRoot:         root,
 		assetCommitments: assetCommitments,
 		tree:             tree,
@@ -187,6 +271,10 @@ func (c *TapCommitment) Upsert(asset *AssetCommitment) error {
 		return ErrMissingAssetCommitment
 	}
 
+	if err := c.checkAssetVersion(asset.Version); err != nil {
+		return err
+	}
+
 	key := asset.TapCommitmentKey()
 	leaf := asset.TapCommitmentLeaf()
 
@@ -219,6 +307,234 @@ func (c *TapCommitment) Upsert(asset *AssetCommitment) error {
 	return nil
 }
 
+// checkAssetVersion returns an error if assets or asset commitments of the
+// given version can't be committed to with the version of the commitment.
+func (c *TapCommitment) checkAssetVersion(version asset.Version) error {
+	maxAssetVersion, err := c.Version.maxAssetVersion()
+	if err != nil {
+		return err
+	}
+
+	if version > maxAssetVersion {
+		return fmt.Errorf("%w: asset version %d, commitment version %d",
+			ErrUnsupportedAssetVersion, version, c.Version)
+	}
+
+	return nil
+}
+
+// Upgrade changes the version of the commitment to the given newer version.
+// As newer commitment versions can commit to all asset versions supported by
+// older ones, this can be done for any commitment. Only the leaf of the
+// commitment changes, the MS-SMT backing it stays the same.
+func (c *TapCommitment) Upgrade(version TapCommitmentVersion) error {
+	if version < c.Version {
+		return fmt.Errorf("cannot upgrade commitment from version %d "+
+			"to older version %d", c.Version, version)
+	}
+
+	return c.setVersion(version)
+}
+
+// Downgrade changes the version of the commitment to the given older version.
+// This is only possible if all asset commitments can be committed to with the
+// older version, which can only be checked if the commitment wasn't
+// constructed with NewTapCommitmentWithRoot.
+func (c *TapCommitment) Downgrade(version TapCommitmentVersion) error {
+	if version > c.Version {
+		return fmt.Errorf("cannot downgrade commitment from version "+
+			"%d to newer version %d", c.Version, version)
+	}
+
+	if version != c.Version && c.assetCommitments == nil {
+		return fmt.Errorf("cannot downgrade commitment without asset " +
+			"commitments")
+	}
+
+	return c.setVersion(version)
+}
+
+// setVersion sets the version of the commitment after making sure all asset
+// commitments can be committed to with it.
+func (c *TapCommitment) setVersion(version TapCommitmentVersion) error {
+	maxAssetVersion, err := version.maxAssetVersion()
+	if err != nil {
+		return err
+	}
+
+	for _, assetCommitment := range c.assetCommitments {
+		if assetCommitment.Version > maxAssetVersion {
+			return fmt.Errorf("%w: asset version %d, commitment "+
+				"version %d", ErrUnsupportedAssetVersion,
+				assetCommitment.Version, version)
+		}
+	}
+
+	c.Version = version
+
+	return nil
+}
+
+// TapCommitmentDelta is a batch of asset changes that can be applied to a
+// Taproot Asset commitment at once with ApplyDelta.
+type TapCommitmentDelta struct {
+	// Upserts are the assets to insert into (or update within) their asset
+	// commitments. Asset commitments that don't exist yet are created.
+	Upserts []*asset.Asset
+
+	// Deletes are the assets to remove from their asset commitments. Asset
+	// commitments that are empty afterward are pruned from the tree.
+	Deletes []*asset.Asset
+}
+
+// ApplyDelta applies a batch of asset upserts and deletes that can span many
+// asset commitments. The result is the same as calling Upsert and Delete on the
+// asset commitments and then Upsert for each of them, but the affected
+// branches of both the inner and the outer MS-SMTs are only recomputed once.
+// This makes a difference for anchor outputs that carry many passive assets.
+//
+// The delta is validated in full before any change is made. Existing asset
+// commitments are modified in place, like with Upsert.
+func (c *TapCommitment) ApplyDelta(delta TapCommitmentDelta) error {
+	// Without the tree we can't modify the commitment, which is the case
+	// if it was constructed with NewTapCommitmentWithRoot.
+	if c.tree == nil {
+		return fmt.Errorf("cannot apply delta to commitment without " +
+			"asset commitments")
+	}
+
+	// We first group all changes by the asset commitment they belong to.
+	type commitmentDelta struct {
+		commitment *AssetCommitment
+		upserts    []*asset.Asset
+		deletes    []*asset.Asset
+	}
+	deltas := make(map[[32]byte]*commitmentDelta)
+	deltaFor := func(a *asset.Asset) (*commitmentDelta, error) {
+		if a == nil {
+			return nil, ErrNoAssets
+		}
+
+		key := a.TapCommitmentKey()
+		d, ok := deltas[key]
+		if !ok {
+			d = &commitmentDelta{
+				commitment: c.assetCommitments[key],
+			}
+			deltas[key] = d
+		}
+
+		return d, nil
+	}
+	for _, a := range delta.Upserts {
+		d, err := deltaFor(a)
+		if err != nil {
+			return err
+		}
+		if err := c.checkAssetVersion(a.Version); err != nil {
+			return err
+		}
+		d.upserts = append(d.upserts, a)
+	}
+	for _, a := range delta.Deletes {
+		d, err := deltaFor(a)
+		if err != nil {
+			return err
+		}
+		d.deletes = append(d.deletes, a)
+	}
+
+	// Then we validate the changes of each asset commitment, creating the
+	// ones we don't have yet. Deleting from an asset commitment that
+	// doesn't exist is a no-op, just like deleting a missing asset.
+	for key, d := range deltas {
+		if d.commitment != nil {
+			err := d.commitment.validateBatch(d.upserts, d.deletes)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if len(d.upserts) == 0 {
+			delete(deltas, key)
+			continue
+		}
+
+		newCommitment, err := NewAssetCommitment(d.upserts...)
+		if err != nil {
+			return err
+		}
+
+		// The new asset commitment only contains the upserted assets,
+		// so any delete either conflicts with them or is a no-op.
+		for _, a := range d.deletes {
+			key := a.AssetCommitmentKey()
+			if _, ok := newCommitment.Asset(key); ok {
+				return fmt.Errorf("%w: %x", ErrConflictingDelta,
+					key[:])
+			}
+		}
+
+		d.commitment = newCommitment
+		d.upserts, d.deletes = nil, nil
+	}
+
+	// With the delta validated, we can apply the changes to each asset
+	// commitment and collect the resulting changes to the outer tree.
+	var (
+		leaves  = make(map[[32]byte]*mssmt.LeafNode, len(deltas))
+		deleted [][32]byte
+	)
+	for key, d := range deltas {
+		err := d.commitment.applyBatch(d.upserts, d.deletes)
+		if err != nil {
+			return err
+		}
+
+		// Because the Taproot Asset tree has a different root whether
+		// we insert an empty asset tree vs. there being an empty leaf,
+		// we need to remove the whole asset tree if the asset
+		// commitment is empty.
+		if d.commitment.TreeRoot.NodeHash() == mssmt.EmptyTreeRootHash {
+			deleted = append(deleted, key)
+			continue
+		}
+
+		leaves[key] = d.commitment.TapCommitmentLeaf()
+	}
+
+	// TODO(bhandras): thread the context through.
+	ctx := context.TODO()
+
+	if len(deleted) > 0 {
+		if _, err := c.tree.DeleteMany(ctx, deleted); err != nil {
+			return err
+		}
+	}
+	if len(leaves) > 0 {
+		if _, err := c.tree.InsertMany(ctx, leaves); err != nil {
+			return err
+		}
+	}
+
+	var err error
+	c.TreeRoot, err = c.tree.Root(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range deleted {
+		delete(c.assetCommitments, key)
+	}
+	for key := range leaves {
+		c.assetCommitments[key] = deltas[key].commitment
+	}
+
+	return nil
+}
+
 // Commitment returns the asset commitment for the given asset. If the asset
 // commitment is not found, the second returned value is false.
 func (c *TapCommitment) Commitment(a *asset.Asset) (*AssetCommitment, bool) {
@@ -230,7 +546,7 @@ func (c *TapCommitment) Commitment(a *asset.Asset) (*AssetCommitment, bool) {
 // NewTapCommitmentWithRoot creates a new Taproot Asset commitment backed by
 // the root node. The resulting commitment will not be able to compute merkle
 // proofs as it only knows of the tree's root node, and not the tree itself.
-func NewTapCommitmentWithRoot(version asset.Version,
+func NewTapCommitmentWithRoot(version TapCommitmentVersion,
 	root *mssmt.BranchNode) *TapCommitment {
 
 	return &TapCommitment{
@@ -243,11 +559,20 @@ func NewTapCommitmentWithRoot(version asset.Version,
 
 // TapLeaf constructs a new `TapLeaf` for this `TapCommitment`.
 func (c *TapCommitment) TapLeaf() txscript.TapLeaf {
-	rootHash := c.TreeRoot.NodeHash()
+	return TapCommitmentLeaf(c.Version, c.TreeRoot)
+}
+
+// TapCommitmentLeaf constructs the `TapLeaf` of a Taproot Asset commitment of
+// the given version with the given MS-SMT root. The leaf script is the version
+// followed by the Taproot Assets marker and the root hash and sum.
+func TapCommitmentLeaf(version TapCommitmentVersion,
+	root *mssmt.BranchNode) txscript.TapLeaf {
+
+	rootHash := root.NodeHash()
 	var rootSum [8]byte
-	binary.BigEndian.PutUint64(rootSum[:], c.TreeRoot.NodeSum())
+	binary.BigEndian.PutUint64(rootSum[:], root.NodeSum())
 	leafParts := [][]byte{
-		{byte(c.Version)}, TaprootAssetsMarker[:], rootHash[:],
+		{byte(version)}, TaprootAssetsMarker[:], rootHash[:],
 		rootSum[:],
 	}
 	leafScript := bytes.Join(leafParts, nil)
@@ -269,7 +594,7 @@ func IsTaprootAssetCommitmentScript(script []byte) bool {
 	if len(script) != TaprootAssetCommitmentScriptSize {
 		return false
 	}
-	if script[0] != byte(asset.V0) {
+	if !TapCommitmentVersion(script[0]).IsKnown() {
 		return false
 	}
 
@@ -342,6 +667,44 @@ func (c *TapCommitment) Proof(tapCommitmentKey,
 	return a, proof, nil
 }
 
+// ExclusionProof computes the proof that the asset identified by the given
+// keys isn't committed to by the TapCommitment. If the TapCommitment contains
+// an AssetCommitment for the asset, the proof shows the asset doesn't exist
+// within it, otherwise it shows there is no such AssetCommitment. An error is
+// returned if the asset is committed to. The keys of an asset can be obtained
+// with asset.TapCommitmentKey and asset.AssetCommitmentKey.
+func (c *TapCommitment) ExclusionProof(tapCommitmentKey,
+	assetCommitmentKey [32]byte) (*Proof, error) {
+
+	if c.assetCommitments == nil || c.tree == nil {
+		return nil, fmt.Errorf("missing asset commitments to compute " +
+			"proofs")
+	}
+
+	a, proof, err := c.Proof(tapCommitmentKey, assetCommitmentKey)
+	if err != nil {
+		return nil, err
+	}
+	if a != nil {
+		return nil, fmt.Errorf("%w: asset_id=%v, script_key=%x",
+			ErrAssetNotExcluded, a.ID(),
+			a.ScriptKey.PubKey.SerializeCompressed())
+	}
+
+	return proof, nil
+}
+
+// TreeStats returns statistics about the nodes of the outer MS-SMT backing the
+// TapCommitment. Statistics about the inner trees can be obtained from each of
+// the asset commitments.
+func (c *TapCommitment) TreeStats() (*mssmt.TreeStats, error) {
+	if c.tree == nil {
+		return nil, fmt.Errorf("missing tree to compute stats")
+	}
+
+	return c.tree.Stats(context.TODO())
+}
+
 // CommittedAssets returns the set of assets committed to in the Taproot Asset
 // commitment.
 func (c *TapCommitment) CommittedAssets() []*asset.Asset {
@@ -369,7 +732,7 @@ func (c *TapCommitment) Commitments() AssetCommitments {
 func (c *TapCommitment) Copy() (*TapCommitment, error) {
 	// If no commitments are present, then this is a commitment with just
 	// the root, so we just need to copy that over.
-	if len(c.assetCommitments) == 0 {
+	if len(c.assetCommitments) == 0 && c.altLeaves == nil {
 		rootCopy := c.TreeRoot.Copy().(*mssmt.BranchNode)
 		return &TapCommitment{
 			Version:  c.Version,
@@ -385,9 +748,93 @@ func (c *TapCommitment) Copy() (*TapCommitment, error) {
 		return nil, err
 	}
 
+	// If possible, the copy shares all nodes of our tree instead of
+	// re-creating the tree, which is much cheaper for large commitments.
+	if treeCopy, ok := copyTree(c.tree); ok {
+		return c.copyWithTree(treeCopy, newAssetCommitments)
+	}
+
 	// With the internal assets commitments copied, we can just re-create
 	// the Taproot Asset commitment as a whole.
-	return NewTapCommitment(newAssetCommitments...)
+	commitmentCopy, err := NewTapCommitment(newAssetCommitments...)
+	if err != nil {
+		return nil, err
+	}
+
+	// The copy may have been created with an older version than ours, as
+	// that's derived from the asset commitments.
+	if err := commitmentCopy.Upgrade(c.Version); err != nil {
+		return nil, err
+	}
+
+	// Any alt leaves need to be copied over as well.
+	if c.altLeaves != nil {
+		err := commitmentCopy.UpsertAltLeaves(
+			chanutils.Map(c.AltLeaves(), (*AltLeaf).Copy)...,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return commitmentCopy, nil
+}
+
+// copyWithTree creates a copy of the commitment from the given copy of its
+// tree and copies of its asset commitments.
+func (c *TapCommitment) copyWithTree(treeCopy mssmt.Tree,
+	assetCommitments []*AssetCommitment) (*TapCommitment, error) {
+
+	commitmentCopy := &TapCommitment{
+		Version:          c.Version,
+		TreeRoot:         c.TreeRoot,
+		tree:             treeCopy,
+		assetCommitments: make(AssetCommitments, len(assetCommitments)),
+	}
+
+	// The version of a copied asset commitment is derived from its assets,
+	// so it can differ from the version of the original one. Those asset
+	// commitments need to be re-inserted into the tree.
+	changedLeaves := make(map[[32]byte]*mssmt.LeafNode)
+	for _, assetCommitment := range assetCommitments {
+		err := commitmentCopy.checkAssetVersion(assetCommitment.Version)
+		if err != nil {
+			return nil, err
+		}
+
+		key := assetCommitment.TapCommitmentKey()
+		commitmentCopy.assetCommitments[key] = assetCommitment
+
+		original := c.assetCommitments[key]
+		if original.Version != assetCommitment.Version {
+			changedLeaves[key] = assetCommitment.TapCommitmentLeaf()
+		}
+	}
+
+	if len(changedLeaves) > 0 {
+		// TODO(bhandras): thread the context through.
+		ctx := context.TODO()
+
+		_, err := treeCopy.InsertMany(ctx, changedLeaves)
+		if err != nil {
+			return nil, err
+		}
+
+		commitmentCopy.TreeRoot, err = treeCopy.Root(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if c.altLeaves != nil {
+		altLeavesCopy, err := c.altLeaves.copy()
+		if err != nil {
+			return nil, err
+		}
+		commitmentCopy.altLeaves = altLeavesCopy
+	}
+
+	return commitmentCopy, nil
 }
 
 // Merge merges the other commitment into this commitment. If the other
@@ -401,6 +848,17 @@ func (c *TapCommitment) Merge(other *TapCommitment) error {
 			"commitments")
 	}
 
+	// Alt leaves of the other commitment are added to this commitment,
+	// replacing any existing alt leaves with the same key.
+	if other.altLeaves != nil {
+		err := c.UpsertAltLeaves(
+			chanutils.Map(other.AltLeaves(), (*AltLeaf).Copy)...,
+		)
+		if err != nil {
+			return fmt.Errorf("error merging alt leaves: %w", err)
+		}
+	}
+
 	// If the other commitment is empty, then we can just exit early.
 	if len(other.assetCommitments) == 0 {
 		return nil
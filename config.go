@@ -1,13 +1,18 @@
 package taprootassets
 
 import (
+	"io"
 	"net"
+	"net/url"
 	"time"
 
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/lightninglabs/lndclient"
 	"github.com/lightninglabs/taproot-assets/address"
+	"github.com/lightninglabs/taproot-assets/monitoring"
+	"github.com/lightninglabs/taproot-assets/perms"
 	"github.com/lightninglabs/taproot-assets/proof"
+	"github.com/lightninglabs/taproot-assets/rpcperms"
 	"github.com/lightninglabs/taproot-assets/tapdb"
 	"github.com/lightninglabs/taproot-assets/tapfreighter"
 	"github.com/lightninglabs/taproot-assets/tapgarden"
@@ -43,6 +48,10 @@ type RPCConfig struct {
 
 	MacaroonPath string
 
+	UniversePublicAccess perms.UniversePublicAccess
+
+	UniverseRateLimit *rpcperms.RateLimitConfig
+
 	LetsEncryptDir string
 
 	LetsEncryptListen string
@@ -66,6 +75,21 @@ type DatabaseConfig struct {
 	UniverseForest *tapdb.BaseUniverseForest
 
 	FederationDB *tapdb.UniverseFederationDB
+
+	// Backupper is used to take online backups of the database.
+	Backupper tapdb.Backupper
+
+	// BackupDir is the directory that backups of the database are written
+	// to if no path is given.
+	BackupDir string
+
+	// StatsReporter is used to report statistics about the size of the
+	// database.
+	StatsReporter tapdb.StatsReporter
+
+	// Closer closes the database once all subsystems that use it have
+	// stopped.
+	Closer io.Closer
 }
 
 // Config is the main config of the Taproot Assets server.
@@ -85,12 +109,20 @@ type Config struct {
 
 	AssetCustodian *tapgarden.Custodian
 
+	ReorgWatcher *tapgarden.ReorgWatcher
+
+	BatchJanitor *tapgarden.BatchJanitor
+
 	ChainBridge tapgarden.ChainBridge
 
 	AddrBook *address.Book
 
 	ProofArchive proof.Archiver
 
+	// DefaultProofCourierAddr is the address of the proof courier that is
+	// encoded in new addresses if the caller doesn't specify one.
+	DefaultProofCourierAddr *url.URL
+
 	AssetWallet tapfreighter.Wallet
 
 	ChainPorter tapfreighter.Porter
@@ -103,6 +135,23 @@ type Config struct {
 
 	UniverseStats universe.Telemetry
 
+	// MetricsExporter serves the metrics of the daemon so they can be
+	// scraped by a Prometheus server.
+	MetricsExporter *monitoring.PrometheusExporter
+
+	// HealthReporter reports the health of the daemon's subsystems through
+	// the gRPC health service.
+	HealthReporter *monitoring.HealthReporter
+
+	// ShutdownTimeout is the maximum time each subsystem is given to stop
+	// within when shutting down.
+	ShutdownTimeout time.Duration
+
+	// RemoteSignerConn is the optional connection to the remote signer,
+	// which is closed once all subsystems that sign through it have
+	// stopped.
+	RemoteSignerConn io.Closer
+
 	// LogWriter is the root logger that all of the daemon's subloggers are
 	// hooked up to.
 	LogWriter *build.RotatingLogWriter
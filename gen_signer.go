@@ -7,8 +7,10 @@ import (
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcec/v2/schnorr"
 	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
 	"github.com/lightninglabs/lndclient"
 	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightningnetwork/lnd/input"
 	"github.com/lightningnetwork/lnd/keychain"
 )
 
@@ -70,6 +72,59 @@ func (l *LndRpcGenSigner) SignGenesis(keyDesc keychain.KeyDescriptor,
 	return tweakedPubKey, schnorrSig, nil
 }
 
+// SignGroupVirtualTx signs the passed group virtual transaction with the key
+// identified by the passed key descriptor. If no tap leaf is passed, a key
+// spend signature for the group key that commits to the passed tapscript root
+// is created. Otherwise, a script spend signature for the passed tap leaf is
+// created.
+func (l *LndRpcGenSigner) SignGroupVirtualTx(keyDesc keychain.KeyDescriptor,
+	tapscriptRoot []byte, leaf *txscript.TapLeaf, virtualTx *wire.MsgTx,
+	prevOut *wire.TxOut) (*schnorr.Signature, error) {
+
+	signDesc := groupVirtualTxSignDesc(
+		keyDesc, tapscriptRoot, leaf, prevOut,
+	)
+	sigs, err := l.lnd.Signer.SignOutputRaw(
+		context.Background(), virtualTx,
+		[]*lndclient.SignDescriptor{signDesc}, []*wire.TxOut{prevOut},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Our signer should only ever produce one signature or fail before this
+	// point, so accessing the signature directly is safe.
+	return schnorr.ParseSignature(sigs[0])
+}
+
+// groupVirtualTxSignDesc creates the sign descriptor for signing a group
+// virtual transaction. If no tap leaf is passed, the descriptor describes a key
+// spend for the group key that commits to the passed tapscript root.
+// Otherwise, it describes a script spend for the passed tap leaf.
+func groupVirtualTxSignDesc(keyDesc keychain.KeyDescriptor,
+	tapscriptRoot []byte, leaf *txscript.TapLeaf,
+	prevOut *wire.TxOut) *lndclient.SignDescriptor {
+
+	signDesc := &lndclient.SignDescriptor{
+		KeyDesc:    keyDesc,
+		SignMethod: input.TaprootKeySpendSignMethod,
+		TapTweak:   tapscriptRoot,
+		Output:     prevOut,
+		HashType:   txscript.SigHashDefault,
+		InputIndex: 0,
+	}
+
+	// If we're signing for a specific leaf, we need to use the script spend
+	// sign method instead.
+	if leaf != nil {
+		signDesc.SignMethod = input.TaprootScriptSpendSignMethod
+		signDesc.TapTweak = nil
+		signDesc.WitnessScript = leaf.Script
+	}
+
+	return signDesc
+}
+
 // A compile time assertion to ensure LndRpcGenSigner meets the
 // asset.GenesisSigner interface.
 var _ asset.GenesisSigner = (*LndRpcGenSigner)(nil)
@@ -1,7 +1,10 @@
 package test
 
 import (
+	"encoding/json"
 	"math/rand"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/btcsuite/btcd/btcec/v2"
@@ -143,3 +146,43 @@ func ScriptSchnorrSig(t *testing.T, pubKey *btcec.PublicKey) txscript.TapLeaf {
 	require.NoError(t, err)
 	return txscript.NewBaseTapLeaf(script2)
 }
+
+// TestVectors is the common structure of the machine-readable test vector files
+// that are generated by the unit tests of the different packages. Alternative
+// implementations can use the files to verify byte-level compatibility. Valid
+// test cases describe inputs that must be processed successfully and the
+// expected results, while error test cases describe inputs that must be
+// rejected.
+type TestVectors[V, E any] struct {
+	ValidTestCases []*V `json:"valid_test_cases"`
+	ErrorTestCases []*E `json:"error_test_cases"`
+}
+
+// WriteTestVectors writes the given test vectors as indented JSON to the file
+// with the given name.
+func WriteTestVectors(t testing.TB, fileName string, vectors any) {
+	fileBytes, err := json.MarshalIndent(vectors, "", "  ")
+	require.NoError(t, err)
+
+	fileBytes = append(fileBytes, '\n')
+	require.NoError(t, os.WriteFile(fileName, fileBytes, 0644))
+}
+
+// ParseTestVectors parses the JSON test vectors in the file with the given name
+// into the given target.
+func ParseTestVectors(t testing.TB, fileName string, target any) {
+	fileBytes, err := os.ReadFile(fileName)
+	require.NoError(t, err)
+
+	require.NoError(t, json.Unmarshal(fileBytes, target))
+}
+
+// TestVectorFiles returns the names of all test vector files in the given
+// directory that start with the given prefix.
+func TestVectorFiles(t testing.TB, dir, prefix string) []string {
+	fileNames, err := filepath.Glob(filepath.Join(dir, prefix+"*.json"))
+	require.NoError(t, err)
+	require.NotEmpty(t, fileNames)
+
+	return fileNames
+}
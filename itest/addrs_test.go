@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 
+	"github.com/lightninglabs/taproot-assets/internal/test"
 	"github.com/lightninglabs/taproot-assets/tappsbt"
 	"github.com/lightninglabs/taproot-assets/taprpc"
 	wrpc "github.com/lightninglabs/taproot-assets/taprpc/assetwalletrpc"
@@ -122,7 +123,78 @@ func testAddresses(t *harnessTest) {
 		)
 		require.NoError(t.t, err)
 		require.True(t.t, verifyResp.ValidProof)
+
+		// An ownership proof bound to a challenge of the verifier can
+		// only be verified with that same challenge.
+		challenge := test.RandBytes(32)
+		proveResp, err = secondTapd.ProveAssetOwnership(
+			ctxt, &wrpc.ProveAssetOwnershipRequest{
+				AssetId:   receiverAddr.AssetId,
+				ScriptKey: receiverAddr.ScriptKey,
+				Challenge: challenge,
+			},
+		)
+		require.NoError(t.t, err)
+
+		verifyResp, err = t.tapd.VerifyAssetOwnership(
+			ctxt, &wrpc.VerifyAssetOwnershipRequest{
+				ProofWithWitness: proveResp.ProofWithWitness,
+				Challenge:        challenge,
+			},
+		)
+		require.NoError(t.t, err)
+		require.True(t.t, verifyResp.ValidProof)
+
+		_, err = t.tapd.VerifyAssetOwnership(
+			ctxt, &wrpc.VerifyAssetOwnershipRequest{
+				ProofWithWitness: proveResp.ProofWithWitness,
+			},
+		)
+		require.Error(t.t, err)
+	}
+
+	// The receiver can also prove its reserves over all received assets
+	// at once, which the sender can then verify.
+	challenge := test.RandBytes(32)
+	reservesResp, err := secondTapd.ProveReserves(
+		ctxt, &wrpc.ProveReservesRequest{
+			Challenge: challenge,
+		},
+	)
+	require.NoError(t.t, err)
+
+	verifyReservesResp, err := t.tapd.VerifyReserves(
+		ctxt, &wrpc.VerifyReservesRequest{
+			ReservesReport: reservesResp.ReservesReport,
+			Challenge:      challenge,
+		},
+	)
+	require.NoError(t.t, err)
+
+	require.Len(t.t, verifyReservesResp.AssetTotals, len(addresses))
+	for _, addr := range addresses {
+		var found bool
+		for _, total := range verifyReservesResp.AssetTotals {
+			if !bytes.Equal(total.AssetId, addr.AssetId) {
+				continue
+			}
+
+			require.Equal(t.t, addr.Amount, total.Amount)
+			found = true
+		}
+		require.True(t.t, found)
 	}
+	require.Len(t.t, verifyReservesResp.GroupTotals, 1)
+	require.Len(t.t, verifyReservesResp.AnchorOutpoints, len(addresses))
+
+	// The report can't be verified against a different challenge.
+	_, err = t.tapd.VerifyReserves(
+		ctxt, &wrpc.VerifyReservesRequest{
+			ReservesReport: reservesResp.ReservesReport,
+			Challenge:      test.RandBytes(32),
+		},
+	)
+	require.Error(t.t, err)
 }
 
 // testMultiAddress tests that we can send assets to multiple addresses at the
@@ -42,6 +42,7 @@ func testBasicSend(t *harnessTest) {
 		defer wg.Done()
 
 		broadcastState := tapfreighter.SendStateBroadcast.String()
+		broadcastStage := taprpc.TransferStage_TRANSFER_STAGE_BROADCAST
 		targetEventSelector := func(event *taprpc.SendAssetEvent) bool {
 			switch eventTyped := event.Event.(type) {
 			case *taprpc.SendAssetEvent_ExecuteSendStateEvent:
@@ -54,6 +55,22 @@ func testBasicSend(t *harnessTest) {
 					ev.SendState)
 
 				return ev.SendState == broadcastState
+
+			case *taprpc.SendAssetEvent_TransferStageEvent:
+				ev := eventTyped.TransferStageEvent
+
+				// Log completed transfer stages. From the
+				// broadcast on, the anchor transaction must
+				// be known.
+				t.Logf("Completed transfer stage: %v",
+					ev.Stage)
+
+				if ev.Stage == broadcastStage {
+					require.Len(t.t, ev.AnchorTxHash, 32)
+					require.NotEmpty(
+						t.t, ev.AnchorOutputIndexes,
+					)
+				}
 			}
 
 			return false
@@ -97,6 +97,10 @@ func newTapdHarness(ht *harnessTest, cfg tapdConfig,
 	tapCfg.TapdDir = cfg.BaseDir
 	tapCfg.DebugLevel = *logLevel
 
+	// Most tests spend assets right after their anchor transaction
+	// confirmed in a single block.
+	tapCfg.ReorgSafetyDepth = 1
+
 	tapCfg.Universe.AcceptRemoteProofs = true
 
 	// Decide which DB backend to use.
@@ -113,6 +117,15 @@ func newTapdHarness(ht *harnessTest, cfg tapdConfig,
 		})
 		tapCfg.DatabaseBackend = tapcfg.DatabaseBackendPostgres
 		tapCfg.Postgres = fixture.GetConfig()
+
+		// We keep only a few idle connections and recycle all of them
+		// quickly, so the tests also cover connections of the pool
+		// being closed and replaced while tapd is running.
+		pgCfg := tapCfg.Postgres
+		pgCfg.MaxOpenConnections = tapdb.DefaultPostgresMaxConns
+		pgCfg.MaxIdleConnections = 2
+		pgCfg.ConnMaxLifetime = 30 * time.Second
+		pgCfg.ConnMaxIdleTime = 5 * time.Second
 	}
 
 	tapCfg.RpcConf.RawRPCListeners = []string{
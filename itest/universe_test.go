@@ -256,9 +256,10 @@ func testUniverseFederation(t *harnessTest) {
 	// At this point, both nodes should have the same Universe roots.
 	assertUniverseStateEqual(t.t, bob, t.tapd)
 
-	// Bob's Universe stats should show that he now has a single asset. We
-	// should also be able to query for stats specifically for the asset.
-	assertUniverseStats(t.t, bob, 1, 0, 1)
+	// Bob's Universe stats should show that he now has a single asset,
+	// which was synced from the main node. We should also be able to query
+	// for stats specifically for the asset.
+	assertUniverseStats(t.t, bob, 1, 1, 1)
 
 	// We'll now make a new asset with Bob, and ensure that the state is
 	// properly pushed to the main node which is a part of the federation.
@@ -285,7 +286,7 @@ func testUniverseFederation(t *harnessTest) {
 
 	// Bob's stats should also now show that there're two total asset as
 	// well as two proofs.
-	assertUniverseStats(t.t, bob, 2, 0, 2)
+	assertUniverseStats(t.t, bob, 2, 1, 2)
 
 	// We should be able to find both the new assets in the set of universe
 	// stats for an asset.
@@ -1,8 +1,7 @@
-FUZZPKG = brontide lnwire wtwire zpay32
-FUZZ_TEST_RUN_TIME = 30
-FUZZ_TEST_TIMEOUT = 20
+FUZZPKG = asset proof tappsbt
+FUZZ_TEST_RUN_TIME = 30s
+FUZZ_TEST_TIMEOUT = 20m
 FUZZ_NUM_PROCESSES = 4
-FUZZ_BASE_WORKDIR = $(shell pwd)/fuzz
 
 # If specific package is being fuzzed, construct the full name of the
 # subpackage.
@@ -26,9 +25,3 @@ endif
 ifneq ($(processes),)
 FUZZ_NUM_PROCESSES := $(processes)
 endif
-
-# Overwrite the base work directory for the fuzz run. Can be used to supply any
-# previously generated corpus.
-ifneq ($(base_workdir),)
-FUZZ_BASE_WORKDIR := $(base_workdir)
-endif
@@ -0,0 +1,27 @@
+package monitoring
+
+const (
+	// DefaultPrometheusListenAddr is the default address the Prometheus
+	// metrics are exposed on.
+	DefaultPrometheusListenAddr = "127.0.0.1:8989"
+)
+
+// PrometheusConfig is the set of configuration data that specifies if
+// Prometheus metric exporting is activated, and if so the listening address of
+// the Prometheus server.
+type PrometheusConfig struct {
+	// Active, if true, then Prometheus metrics will be exported.
+	Active bool `long:"active" description:"If true Prometheus metrics will be exported"`
+
+	// ListenAddr is the listening address that we should use to allow the
+	// main Prometheus server to scrape our metrics.
+	ListenAddr string `long:"listenaddr" description:"The interface we should listen on for Prometheus"`
+}
+
+// DefaultPrometheusConfig returns the default configuration for the
+// Prometheus metrics exporter, which is disabled.
+func DefaultPrometheusConfig() *PrometheusConfig {
+	return &PrometheusConfig{
+		ListenAddr: DefaultPrometheusListenAddr,
+	}
+}
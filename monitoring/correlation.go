@@ -0,0 +1,131 @@
+package monitoring
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btclog"
+	"github.com/lightningnetwork/lnd/build"
+)
+
+const (
+	// CorrelationKey is the key the correlation ID is logged with.
+	CorrelationKey = "cid"
+
+	// correlationIDLen is the number of bytes a correlation ID is made of.
+	correlationIDLen = 8
+)
+
+// CorrelationID identifies a single unit of work, like a minting batch or an
+// outbound parcel, across all the subsystems that process it. Every log line
+// written on behalf of that unit carries the ID, so its lifecycle can be
+// traced through the logs.
+type CorrelationID string
+
+// NewCorrelationID creates a new random correlation ID for a unit of work of
+// the given kind.
+func NewCorrelationID(kind string) CorrelationID {
+	var id [correlationIDLen]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		// There's no need to fail the operation just because its logs
+		// can't be labeled, so we fall back to the all-zero ID.
+		log.Warnf("Unable to create correlation ID: %v", err)
+	}
+
+	return CorrelationIDFromBytes(kind, id[:])
+}
+
+// CorrelationIDFromBytes creates a correlation ID for a unit of work of the
+// given kind that is identified by the given bytes, like a batch key. Only
+// the leading bytes are used, so the ID still prefix matches the hex encoding
+// of the full identifier.
+func CorrelationIDFromBytes(kind string, id []byte) CorrelationID {
+	if len(id) > correlationIDLen {
+		id = id[:correlationIDLen]
+	}
+
+	return CorrelationID(kind + "-" + hex.EncodeToString(id))
+}
+
+// String returns the string representation of the correlation ID.
+func (c CorrelationID) String() string {
+	return string(c)
+}
+
+// correlationIDKey is the context key the correlation ID is stored under.
+type correlationIDKey struct{}
+
+// WithCorrelationID returns a copy of the given context that carries the
+// given correlation ID. This allows the ID to flow into subsystems that only
+// receive a context from the caller.
+func WithCorrelationID(ctx context.Context,
+	id CorrelationID) context.Context {
+
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+// CorrelationIDFromContext returns the correlation ID carried by the given
+// context, if any.
+func CorrelationIDFromContext(ctx context.Context) (CorrelationID, bool) {
+	id, ok := ctx.Value(correlationIDKey{}).(CorrelationID)
+	return id, ok
+}
+
+// FormatFields formats the given alternating keys and values as space
+// separated key=value pairs. Values that contain spaces or are empty are
+// quoted, so the result can be parsed again unambiguously.
+func FormatFields(keyVals ...interface{}) string {
+	var b strings.Builder
+	for i := 0; i < len(keyVals); i += 2 {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+
+		value := "<missing>"
+		if i+1 < len(keyVals) {
+			value = fmt.Sprint(keyVals[i+1])
+		}
+		if value == "" || strings.ContainsAny(value, " =\"") {
+			value = fmt.Sprintf("%q", value)
+		}
+
+		fmt.Fprintf(&b, "%v=%s", keyVals[i], value)
+	}
+
+	return b.String()
+}
+
+// WithFields returns a logger that prefixes every line written to the given
+// logger with the given alternating keys and values.
+func WithFields(logger btclog.Logger, keyVals ...interface{}) btclog.Logger {
+	if len(keyVals) == 0 {
+		return logger
+	}
+
+	return build.NewPrefixLog(FormatFields(keyVals...), logger)
+}
+
+// CorrelatedLogger returns a logger that prefixes every line written to the
+// given logger with the given correlation ID.
+func CorrelatedLogger(logger btclog.Logger,
+	id CorrelationID) btclog.Logger {
+
+	return WithFields(logger, CorrelationKey, id)
+}
+
+// LoggerFromContext returns a logger that prefixes every line written to the
+// given logger with the correlation ID carried by the given context. If the
+// context doesn't carry an ID, the logger is returned as is.
+func LoggerFromContext(ctx context.Context,
+	logger btclog.Logger) btclog.Logger {
+
+	id, ok := CorrelationIDFromContext(ctx)
+	if !ok {
+		return logger
+	}
+
+	return CorrelatedLogger(logger, id)
+}
@@ -0,0 +1,87 @@
+package monitoring
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btclog"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFormatFields tests that key value pairs are formatted unambiguously.
+func TestFormatFields(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		keyVals  []interface{}
+		expected string
+	}{{
+		name:     "no fields",
+		expected: "",
+	}, {
+		name:     "plain values",
+		keyVals:  []interface{}{"cid", "batch-00", "num", 3},
+		expected: "cid=batch-00 num=3",
+	}, {
+		name:     "quoted values",
+		keyVals:  []interface{}{"msg", "two words", "empty", ""},
+		expected: `msg="two words" empty=""`,
+	}, {
+		name:     "missing value",
+		keyVals:  []interface{}{"cid"},
+		expected: "cid=<missing>",
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual := FormatFields(tc.keyVals...)
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+// TestCorrelationID tests the creation of correlation IDs and that they are
+// carried through contexts into the log output.
+func TestCorrelationID(t *testing.T) {
+	t.Parallel()
+
+	// IDs derived from an identifier prefix match its hex encoding.
+	batchKey := bytes.Repeat([]byte{0xab}, 33)
+	batchID := CorrelationIDFromBytes("batch", batchKey)
+	require.Equal(t, "batch-abababababababab", batchID.String())
+
+	// Random IDs are unique.
+	parcelID := NewCorrelationID("parcel")
+	require.True(t, strings.HasPrefix(parcelID.String(), "parcel-"))
+	require.NotEqual(t, parcelID, NewCorrelationID("parcel"))
+
+	ctx := context.Background()
+	_, ok := CorrelationIDFromContext(ctx)
+	require.False(t, ok)
+
+	ctx = WithCorrelationID(ctx, parcelID)
+	id, ok := CorrelationIDFromContext(ctx)
+	require.True(t, ok)
+	require.Equal(t, parcelID, id)
+
+	var buf bytes.Buffer
+	logger := btclog.NewBackend(&buf).Logger("TEST")
+	logger.SetLevel(btclog.LevelDebug)
+
+	// A context without an ID leaves the log lines untouched.
+	LoggerFromContext(context.Background(), logger).Infof("plain")
+	require.Contains(t, buf.String(), "TEST: plain\n")
+
+	buf.Reset()
+	LoggerFromContext(ctx, logger).Infof("tagged %d", 1)
+	require.Contains(
+		t, buf.String(), "TEST: cid="+parcelID.String()+" tagged 1\n",
+	)
+}
@@ -0,0 +1,100 @@
+package monitoring
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	// metricsPath is the HTTP path the metrics are exposed on.
+	metricsPath = "/metrics"
+
+	// readHeaderTimeout is the maximum time we wait for a scraper to send
+	// the request headers.
+	readHeaderTimeout = 5 * time.Second
+)
+
+// PrometheusExporter is a metrics exporter that serves all metrics of the
+// daemon over HTTP, so they can be scraped by a Prometheus server.
+type PrometheusExporter struct {
+	cfg *PrometheusConfig
+
+	server   *http.Server
+	listener net.Listener
+
+	wg sync.WaitGroup
+}
+
+// NewPrometheusExporter creates a new Prometheus exporter with the given
+// config.
+func NewPrometheusExporter(cfg *PrometheusConfig) *PrometheusExporter {
+	return &PrometheusExporter{
+		cfg: cfg,
+	}
+}
+
+// Start starts serving the metrics on the configured listen address. This is
+// a no-op if the exporter isn't active.
+func (p *PrometheusExporter) Start() error {
+	if !p.cfg.Active {
+		return nil
+	}
+
+	listener, err := net.Listen("tcp", p.cfg.ListenAddr)
+	if err != nil {
+		return fmt.Errorf("unable to listen on %v: %w",
+			p.cfg.ListenAddr, err)
+	}
+	p.listener = listener
+
+	mux := http.NewServeMux()
+	mux.Handle(metricsPath, promhttp.HandlerFor(
+		registry, promhttp.HandlerOpts{},
+	))
+	p.server = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+
+	log.Infof("Prometheus exporter listening on %v", listener.Addr())
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+
+		err := p.server.Serve(listener)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Errorf("Prometheus exporter stopped: %v", err)
+		}
+	}()
+
+	return nil
+}
+
+// Addr returns the address the exporter is listening on, or nil if it isn't
+// active.
+func (p *PrometheusExporter) Addr() net.Addr {
+	if p.listener == nil {
+		return nil
+	}
+
+	return p.listener.Addr()
+}
+
+// Stop stops serving the metrics.
+func (p *PrometheusExporter) Stop() error {
+	if p.server == nil {
+		return nil
+	}
+
+	err := p.server.Close()
+	p.wg.Wait()
+
+	return err
+}
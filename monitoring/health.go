@@ -0,0 +1,156 @@
+package monitoring
+
+import (
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	// DefaultWatchdogInterval is the default interval at which the health
+	// reporter checks the watchdog for stalled operations.
+	DefaultWatchdogInterval = time.Minute
+)
+
+// HealthReporter periodically checks a watchdog for stalled operations and
+// reports the health of each subsystem through the standard gRPC health
+// service. A subsystem with a stalled operation is reported as not serving,
+// as is the daemon as a whole (the empty service name) if any of its
+// subsystems is.
+type HealthReporter struct {
+	watchdog *Watchdog
+	interval time.Duration
+
+	server *health.Server
+
+	// stalled is the set of stalled operations that were already logged,
+	// so each of them is only logged once.
+	stalled map[StalledActivity]struct{}
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+	quit      chan struct{}
+	wg        sync.WaitGroup
+}
+
+// NewHealthReporter creates a new health reporter that checks the given
+// watchdog at the given interval.
+func NewHealthReporter(watchdog *Watchdog,
+	interval time.Duration) *HealthReporter {
+
+	return &HealthReporter{
+		watchdog: watchdog,
+		interval: interval,
+		server:   health.NewServer(),
+		stalled:  make(map[StalledActivity]struct{}),
+		quit:     make(chan struct{}),
+	}
+}
+
+// Server returns the gRPC health server the health is reported through.
+func (h *HealthReporter) Server() healthpb.HealthServer {
+	return h.server
+}
+
+// Start performs an initial health check and starts checking the watchdog
+// periodically.
+func (h *HealthReporter) Start() error {
+	h.startOnce.Do(func() {
+		h.check()
+
+		h.wg.Add(1)
+		go h.checkLoop()
+	})
+
+	return nil
+}
+
+// Stop stops checking the watchdog.
+func (h *HealthReporter) Stop() error {
+	h.stopOnce.Do(func() {
+		close(h.quit)
+		h.wg.Wait()
+	})
+
+	return nil
+}
+
+// Shutdown reports all subsystems as not serving, as the daemon is shutting
+// down. Any later status updates are ignored.
+func (h *HealthReporter) Shutdown() {
+	h.server.Shutdown()
+}
+
+// checkLoop checks the watchdog at the configured interval until the reporter
+// is stopped.
+func (h *HealthReporter) checkLoop() {
+	defer h.wg.Done()
+
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			h.check()
+
+		case <-h.quit:
+			return
+		}
+	}
+}
+
+// check updates the serving status of all subsystems based on the stalled
+// operations reported by the watchdog.
+func (h *HealthReporter) check() {
+	stalled := h.watchdog.Stalled()
+
+	unhealthy := make(map[string]struct{}, len(stalled))
+	current := make(map[StalledActivity]struct{}, len(stalled))
+	for _, a := range stalled {
+		unhealthy[a.Subsystem] = struct{}{}
+		current[a] = struct{}{}
+
+		if _, ok := h.stalled[a]; ok {
+			continue
+		}
+
+		log.Warnf("Subsystem %v stalled: %v didn't complete within "+
+			"%v (started %v)", a.Subsystem, a.Name, a.Deadline,
+			a.Since)
+	}
+
+	for a := range h.stalled {
+		if _, ok := current[a]; !ok {
+			log.Infof("Subsystem %v recovered: %v completed",
+				a.Subsystem, a.Name)
+		}
+	}
+	h.stalled = current
+
+	for _, subsystem := range Subsystems {
+		h.server.SetServingStatus(subsystem, servingStatus(
+			unhealthy, subsystem,
+		))
+	}
+
+	overall := healthpb.HealthCheckResponse_SERVING
+	if len(unhealthy) > 0 {
+		overall = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	h.server.SetServingStatus("", overall)
+}
+
+// servingStatus returns the serving status of the given subsystem based on
+// the set of unhealthy subsystems.
+func servingStatus(unhealthy map[string]struct{},
+	subsystem string) healthpb.HealthCheckResponse_ServingStatus {
+
+	if _, ok := unhealthy[subsystem]; ok {
+		return healthpb.HealthCheckResponse_NOT_SERVING
+	}
+
+	return healthpb.HealthCheckResponse_SERVING
+}
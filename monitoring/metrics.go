@@ -0,0 +1,261 @@
+package monitoring
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+const (
+	// namespace is the namespace all metrics of the daemon are created in.
+	namespace = "tapd"
+
+	// outcomeLabel is the name of the label that distinguishes successful
+	// from failed operations.
+	outcomeLabel = "outcome"
+
+	// outcomeSuccess is the outcome label value of a successful operation.
+	outcomeSuccess = "success"
+
+	// outcomeFailure is the outcome label value of a failed operation.
+	outcomeFailure = "failure"
+
+	// tableLabel is the name of the label that distinguishes the database
+	// tables rows were deleted from.
+	tableLabel = "table"
+
+	// reasonLabel is the name of the label that distinguishes why an RPC
+	// request was rejected.
+	reasonLabel = "reason"
+)
+
+var (
+	// registry is the daemon-wide registry all metrics are registered
+	// with. We use our own registry instead of the global default one, so
+	// only the metrics of the daemon are exported, even if tapd is
+	// embedded in another process.
+	registry = prometheus.NewRegistry()
+
+	// seedlingsQueued counts the seedlings that were added to a minting
+	// batch.
+	seedlingsQueued = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "planter",
+		Name:      "seedlings_total",
+		Help:      "Number of seedlings added to a minting batch.",
+	})
+
+	// batchesMinted counts the minting batches that were confirmed.
+	batchesMinted = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "planter",
+		Name:      "batches_minted_total",
+		Help:      "Number of minting batches confirmed on chain.",
+	})
+
+	// batchDuration tracks the time between the creation of a minting
+	// batch and its confirmation.
+	batchDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "planter",
+		Name:      "batch_duration_seconds",
+		Help: "Time between the creation of a minting batch and " +
+			"its confirmation.",
+		// From one minute up to roughly 1.5 days.
+		Buckets: prometheus.ExponentialBuckets(60, 2, 12),
+	})
+
+	// parcels counts the outbound parcels by whether they were broadcast
+	// or failed before.
+	parcels = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "freighter",
+		Name:      "parcels_total",
+		Help:      "Number of outbound parcels by outcome.",
+	}, []string{outcomeLabel})
+
+	// parcelFees counts the on-chain fees paid for outbound parcels.
+	parcelFees = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "freighter",
+		Name:      "chain_fees_sats_total",
+		Help:      "On-chain fees in sats paid for outbound parcels.",
+	})
+
+	// courierDeliveries counts the proof deliveries by their outcome.
+	courierDeliveries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "courier",
+		Name:      "deliveries_total",
+		Help:      "Number of proof deliveries by outcome.",
+	}, []string{outcomeLabel})
+
+	// courierRetries counts the failed proof delivery attempts that were
+	// retried after backing off.
+	courierRetries = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "courier",
+		Name:      "retries_total",
+		Help:      "Number of proof delivery attempts retried.",
+	})
+
+	// proofVerifications counts the proof file verifications by their
+	// outcome.
+	proofVerifications = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "proof",
+		Name:      "verifications_total",
+		Help:      "Number of proof file verifications by outcome.",
+	}, []string{outcomeLabel})
+
+	// proofVerificationDuration tracks the time it takes to verify a
+	// proof file.
+	proofVerificationDuration = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "proof",
+			Name:      "verification_duration_seconds",
+			Help:      "Time it takes to verify a proof file.",
+			Buckets:   prometheus.DefBuckets,
+		},
+	)
+
+	// universeSyncs counts the syncs with remote universe servers by their
+	// outcome.
+	universeSyncs = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "universe",
+		Name:      "syncs_total",
+		Help:      "Number of universe syncs by outcome.",
+	}, []string{outcomeLabel})
+
+	// universeSyncDuration tracks the time a sync with a remote universe
+	// server takes.
+	universeSyncDuration = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "universe",
+			Name:      "sync_duration_seconds",
+			Help:      "Time a sync with a remote universe takes.",
+			// From 100ms up to roughly 1.5 hours.
+			Buckets: prometheus.ExponentialBuckets(0.1, 2, 16),
+		},
+	)
+
+	// universeSyncedLeaves counts the leaves inserted into the local
+	// universe by syncs.
+	universeSyncedLeaves = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "universe",
+		Name:      "synced_leaves_total",
+		Help:      "Number of leaves inserted by universe syncs.",
+	})
+
+	// prunedRows counts the rows the batch janitor deleted from the
+	// minting store by their table.
+	prunedRows = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "janitor",
+		Name:      "pruned_rows_total",
+		Help: "Number of rows pruned from the minting store by " +
+			"table.",
+	}, []string{tableLabel})
+
+	// rateLimitedRequests counts the universe RPC requests that were
+	// rejected because a peer exceeded its rate limit or quota.
+	rateLimitedRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "rpc",
+		Name:      "rate_limited_requests_total",
+		Help: "Number of universe RPC requests rejected by the rate " +
+			"limiter by reason.",
+	}, []string{reasonLabel})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(
+			collectors.ProcessCollectorOpts{},
+		),
+		seedlingsQueued, batchesMinted, batchDuration, parcels,
+		parcelFees, courierDeliveries, courierRetries,
+		proofVerifications, proofVerificationDuration, universeSyncs,
+		universeSyncDuration, universeSyncedLeaves, prunedRows,
+		rateLimitedRequests,
+	)
+}
+
+// outcome returns the outcome label value for the given error.
+func outcome(err error) string {
+	if err != nil {
+		return outcomeFailure
+	}
+
+	return outcomeSuccess
+}
+
+// ObserveSeedlingQueued records that a seedling was added to a minting batch.
+func ObserveSeedlingQueued() {
+	seedlingsQueued.Inc()
+}
+
+// ObserveBatchMinted records that a minting batch created at the given time
+// was confirmed.
+func ObserveBatchMinted(creationTime time.Time) {
+	batchesMinted.Inc()
+	batchDuration.Observe(time.Since(creationTime).Seconds())
+}
+
+// ObserveParcelBroadcast records that the anchor transaction of an outbound
+// parcel paying the given on-chain fees was broadcast.
+func ObserveParcelBroadcast(chainFees int64) {
+	parcels.WithLabelValues(outcomeSuccess).Inc()
+	if chainFees > 0 {
+		parcelFees.Add(float64(chainFees))
+	}
+}
+
+// ObserveParcelFailed records that an outbound parcel failed before its anchor
+// transaction could be broadcast.
+func ObserveParcelFailed() {
+	parcels.WithLabelValues(outcomeFailure).Inc()
+}
+
+// ObserveCourierDelivery records the outcome of a proof delivery.
+func ObserveCourierDelivery(err error) {
+	courierDeliveries.WithLabelValues(outcome(err)).Inc()
+}
+
+// ObserveCourierRetry records that a failed proof delivery attempt is retried.
+func ObserveCourierRetry() {
+	courierRetries.Inc()
+}
+
+// ObserveProofVerification records the outcome of a proof file verification
+// that was started at the given time.
+func ObserveProofVerification(start time.Time, err error) {
+	proofVerifications.WithLabelValues(outcome(err)).Inc()
+	proofVerificationDuration.Observe(time.Since(start).Seconds())
+}
+
+// ObserveUniverseSync records the outcome of a universe sync that was started
+// at the given time and inserted the given number of new leaves.
+func ObserveUniverseSync(start time.Time, numNewLeaves int, err error) {
+	universeSyncs.WithLabelValues(outcome(err)).Inc()
+	universeSyncDuration.Observe(time.Since(start).Seconds())
+	universeSyncedLeaves.Add(float64(numNewLeaves))
+}
+
+// ObservePrunedRows records that the given number of rows were pruned from
+// the given table of the minting store.
+func ObservePrunedRows(table string, numRows int64) {
+	prunedRows.WithLabelValues(table).Add(float64(numRows))
+}
+
+// ObserveRateLimited records that an RPC request was rejected by the rate
+// limiter for the given reason.
+func ObserveRateLimited(reason string) {
+	rateLimitedRequests.WithLabelValues(reason).Inc()
+}
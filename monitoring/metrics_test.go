@@ -0,0 +1,108 @@
+package monitoring
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// TestObserveMetrics tests that the observe helpers update the metrics they
+// are responsible for.
+func TestObserveMetrics(t *testing.T) {
+	errFail := errors.New("fail")
+
+	seedlingsBefore := testutil.ToFloat64(seedlingsQueued)
+	ObserveSeedlingQueued()
+	require.Equal(
+		t, seedlingsBefore+1, testutil.ToFloat64(seedlingsQueued),
+	)
+
+	batchesBefore := testutil.ToFloat64(batchesMinted)
+	ObserveBatchMinted(time.Now().Add(-time.Hour))
+	require.Equal(t, batchesBefore+1, testutil.ToFloat64(batchesMinted))
+
+	broadcast := parcels.WithLabelValues(outcomeSuccess)
+	failed := parcels.WithLabelValues(outcomeFailure)
+	broadcastBefore := testutil.ToFloat64(broadcast)
+	failedBefore := testutil.ToFloat64(failed)
+	feesBefore := testutil.ToFloat64(parcelFees)
+	ObserveParcelBroadcast(1000)
+	ObserveParcelFailed()
+	require.Equal(t, broadcastBefore+1, testutil.ToFloat64(broadcast))
+	require.Equal(t, failedBefore+1, testutil.ToFloat64(failed))
+	require.Equal(t, feesBefore+1000, testutil.ToFloat64(parcelFees))
+
+	undelivered := courierDeliveries.WithLabelValues(outcomeFailure)
+	retriesBefore := testutil.ToFloat64(courierRetries)
+	undeliveredBefore := testutil.ToFloat64(undelivered)
+	ObserveCourierRetry()
+	ObserveCourierDelivery(errFail)
+	require.Equal(t, retriesBefore+1, testutil.ToFloat64(courierRetries))
+	require.Equal(t, undeliveredBefore+1, testutil.ToFloat64(undelivered))
+
+	verified := proofVerifications.WithLabelValues(outcomeSuccess)
+	verifiedBefore := testutil.ToFloat64(verified)
+	ObserveProofVerification(time.Now(), nil)
+	require.Equal(t, verifiedBefore+1, testutil.ToFloat64(verified))
+
+	synced := universeSyncs.WithLabelValues(outcomeSuccess)
+	syncedBefore := testutil.ToFloat64(synced)
+	leavesBefore := testutil.ToFloat64(universeSyncedLeaves)
+	ObserveUniverseSync(time.Now(), 5, nil)
+	require.Equal(t, syncedBefore+1, testutil.ToFloat64(synced))
+	require.Equal(
+		t, leavesBefore+5, testutil.ToFloat64(universeSyncedLeaves),
+	)
+
+	prunedBatches := prunedRows.WithLabelValues("asset_minting_batches")
+	prunedBefore := testutil.ToFloat64(prunedBatches)
+	ObservePrunedRows("asset_minting_batches", 3)
+	require.Equal(t, prunedBefore+3, testutil.ToFloat64(prunedBatches))
+
+	quotaLimited := rateLimitedRequests.WithLabelValues("quota")
+	limitedBefore := testutil.ToFloat64(quotaLimited)
+	ObserveRateLimited("quota")
+	require.Equal(t, limitedBefore+1, testutil.ToFloat64(quotaLimited))
+}
+
+// TestPrometheusExporter tests that the exporter only serves the metrics if
+// it is active.
+func TestPrometheusExporter(t *testing.T) {
+	// An inactive exporter doesn't listen at all.
+	inactive := NewPrometheusExporter(DefaultPrometheusConfig())
+	require.NoError(t, inactive.Start())
+	require.Nil(t, inactive.Addr())
+	require.NoError(t, inactive.Stop())
+
+	exporter := NewPrometheusExporter(&PrometheusConfig{
+		Active:     true,
+		ListenAddr: "127.0.0.1:0",
+	})
+	require.NoError(t, exporter.Start())
+	t.Cleanup(func() {
+		require.NoError(t, exporter.Stop())
+	})
+
+	ObserveSeedlingQueued()
+
+	url := fmt.Sprintf("http://%v%v", exporter.Addr(), metricsPath)
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.True(t, strings.Contains(
+		string(body), "tapd_planter_seedlings_total",
+	))
+	require.True(t, strings.Contains(string(body), "go_goroutines"))
+}
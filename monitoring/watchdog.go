@@ -0,0 +1,147 @@
+package monitoring
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// SubsystemPlanter is the name the minting planter and its caretakers
+	// report their activities under.
+	SubsystemPlanter = "planter"
+
+	// SubsystemPorter is the name the chain porter reports its activities
+	// under.
+	SubsystemPorter = "porter"
+
+	// SubsystemCourier is the name the proof couriers report their
+	// activities under.
+	SubsystemCourier = "courier"
+
+	// SubsystemUniverseSync is the name the universe syncer reports its
+	// activities under.
+	SubsystemUniverseSync = "universe_sync"
+)
+
+// Subsystems is the list of all subsystems that report their activities to
+// the watchdog.
+var Subsystems = []string{
+	SubsystemPlanter, SubsystemPorter, SubsystemCourier,
+	SubsystemUniverseSync,
+}
+
+// activity is a single blocking operation that is tracked by the watchdog.
+type activity struct {
+	subsystem string
+	name      string
+	start     time.Time
+	deadline  time.Duration
+}
+
+// StalledActivity describes a tracked operation that didn't complete within
+// its deadline.
+type StalledActivity struct {
+	// Subsystem is the name of the subsystem the operation belongs to.
+	Subsystem string
+
+	// Name describes the operation.
+	Name string
+
+	// Since is the time the operation was started.
+	Since time.Time
+
+	// Deadline is the time the operation was expected to complete within.
+	Deadline time.Duration
+}
+
+// Watchdog keeps track of the blocking operations of the daemon's
+// subsystems, like a caretaker waiting for a chain notification, and detects
+// those that didn't complete within their deadline. Such an operation most
+// likely means the goroutine performing it is wedged.
+type Watchdog struct {
+	clock func() time.Time
+
+	mu         sync.Mutex
+	nextID     uint64
+	activities map[uint64]*activity
+}
+
+// NewWatchdog creates a new watchdog that doesn't track any operations yet.
+func NewWatchdog() *Watchdog {
+	return &Watchdog{
+		clock:      time.Now,
+		activities: make(map[uint64]*activity),
+	}
+}
+
+// defaultWatchdog is the daemon-wide watchdog the subsystems report their
+// operations to.
+var defaultWatchdog = NewWatchdog()
+
+// DefaultWatchdog returns the daemon-wide watchdog.
+func DefaultWatchdog() *Watchdog {
+	return defaultWatchdog
+}
+
+// Track starts tracking an operation with the given name of the given
+// subsystem that is expected to complete within the given deadline. The
+// returned function must be called once the operation completed.
+func (w *Watchdog) Track(subsystem, name string,
+	deadline time.Duration) func() {
+
+	w.mu.Lock()
+	id := w.nextID
+	w.nextID++
+	w.activities[id] = &activity{
+		subsystem: subsystem,
+		name:      name,
+		start:     w.clock(),
+		deadline:  deadline,
+	}
+	w.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			w.mu.Lock()
+			delete(w.activities, id)
+			w.mu.Unlock()
+		})
+	}
+}
+
+// Stalled returns all tracked operations that exceeded their deadline, oldest
+// first.
+func (w *Watchdog) Stalled() []StalledActivity {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := w.clock()
+
+	var stalled []StalledActivity
+	for _, a := range w.activities {
+		if now.Sub(a.start) <= a.deadline {
+			continue
+		}
+
+		stalled = append(stalled, StalledActivity{
+			Subsystem: a.subsystem,
+			Name:      a.name,
+			Since:     a.start,
+			Deadline:  a.deadline,
+		})
+	}
+
+	sort.Slice(stalled, func(i, j int) bool {
+		return stalled[i].Since.Before(stalled[j].Since)
+	})
+
+	return stalled
+}
+
+// TrackActivity starts tracking an operation with the daemon-wide watchdog.
+// The returned function must be called once the operation completed.
+func TrackActivity(subsystem, name string, deadline time.Duration) func() {
+	return defaultWatchdog.Track(subsystem, name, deadline)
+}
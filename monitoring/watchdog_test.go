@@ -0,0 +1,110 @@
+package monitoring
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// TestWatchdog tests that the watchdog only reports operations that exceeded
+// their deadline and are still in progress.
+func TestWatchdog(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1_000_000, 0)
+	w := NewWatchdog()
+	w.clock = func() time.Time {
+		return now
+	}
+
+	doneConf := w.Track(SubsystemPlanter, "conf", time.Hour)
+	doneSync := w.Track(SubsystemUniverseSync, "sync", time.Minute)
+	require.Empty(t, w.Stalled())
+
+	// Only the sync exceeded its deadline.
+	now = now.Add(time.Minute + time.Second)
+	require.Equal(t, []StalledActivity{{
+		Subsystem: SubsystemUniverseSync,
+		Name:      "sync",
+		Since:     now.Add(-time.Minute - time.Second),
+		Deadline:  time.Minute,
+	}}, w.Stalled())
+
+	// A completed operation is no longer reported, even if the done
+	// function is called more than once.
+	doneSync()
+	doneSync()
+	require.Empty(t, w.Stalled())
+
+	now = now.Add(time.Hour)
+	stalled := w.Stalled()
+	require.Len(t, stalled, 1)
+	require.Equal(t, SubsystemPlanter, stalled[0].Subsystem)
+
+	doneConf()
+	require.Empty(t, w.Stalled())
+}
+
+// TestHealthReporter tests that stalled operations are reported through the
+// gRPC health service.
+func TestHealthReporter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1_000_000, 0)
+	w := NewWatchdog()
+	w.clock = func() time.Time {
+		return now
+	}
+
+	h := NewHealthReporter(w, time.Hour)
+	require.NoError(t, h.Start())
+	t.Cleanup(func() {
+		require.NoError(t, h.Stop())
+	})
+
+	ctx := context.Background()
+	assertStatus := func(service string,
+		status healthpb.HealthCheckResponse_ServingStatus) {
+
+		t.Helper()
+
+		resp, err := h.Server().Check(ctx, &healthpb.HealthCheckRequest{
+			Service: service,
+		})
+		require.NoError(t, err)
+		require.Equal(t, status, resp.Status)
+	}
+
+	serving := healthpb.HealthCheckResponse_SERVING
+	notServing := healthpb.HealthCheckResponse_NOT_SERVING
+
+	assertStatus("", serving)
+	for _, subsystem := range Subsystems {
+		assertStatus(subsystem, serving)
+	}
+
+	// Once an operation of the porter stalls, the porter and the daemon
+	// as a whole are reported as not serving.
+	done := w.Track(SubsystemPorter, "conf", time.Minute)
+	now = now.Add(2 * time.Minute)
+	h.check()
+
+	assertStatus("", notServing)
+	assertStatus(SubsystemPorter, notServing)
+	assertStatus(SubsystemPlanter, serving)
+
+	// The porter recovers once the operation completes.
+	done()
+	h.check()
+
+	assertStatus("", serving)
+	assertStatus(SubsystemPorter, serving)
+
+	// After shutting down, everything is reported as not serving.
+	h.Shutdown()
+	assertStatus("", notServing)
+	assertStatus(SubsystemPlanter, notServing)
+}
@@ -136,3 +136,44 @@ func BenchmarkTree(b *testing.B) {
 		return mssmt.NewCompactedTree(mssmt.NewDefaultStore())
 	})
 }
+
+func benchmarkBatchedInsert(b *testing.B, numLeaves int, batched bool) {
+	leaves := randTree(numLeaves)
+	batch := make(map[[32]byte]*mssmt.LeafNode, numLeaves)
+	for _, item := range leaves {
+		batch[item.key] = item.leaf
+	}
+
+	ctx := context.Background()
+	b.ResetTimer()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		tree := mssmt.NewCompactedTree(mssmt.NewDefaultStore())
+		if batched {
+			_, err := tree.InsertMany(ctx, batch)
+			require.NoError(b, err)
+
+			continue
+		}
+
+		for _, item := range leaves {
+			_, err := tree.Insert(ctx, item.key, item.leaf)
+			require.NoError(b, err)
+		}
+	}
+}
+
+func BenchmarkBatchedInsert(b *testing.B) {
+	for _, numLeaves := range []int{10, 1_000, 10_000} {
+		numLeaves := numLeaves
+
+		b.Run(fmt.Sprintf("Insert-%v", numLeaves), func(b *testing.B) {
+			benchmarkBatchedInsert(b, numLeaves, false)
+		})
+		b.Run(fmt.Sprintf("InsertMany-%v", numLeaves),
+			func(b *testing.B) {
+				benchmarkBatchedInsert(b, numLeaves, true)
+			},
+		)
+	}
+}
@@ -13,15 +13,34 @@ import (
 // for large trees.
 type CompactedTree struct {
 	store TreeStore
+
+	opts treeOptions
 }
 
 var _ Tree = (*CompactedTree)(nil)
 
 // NewCompactedTree initializes an empty MS-SMT backed by `store`.
-func NewCompactedTree(store TreeStore) *CompactedTree {
+func NewCompactedTree(store TreeStore, opts ...TreeOption) *CompactedTree {
 	return &CompactedTree{
 		store: store,
+		opts:  newTreeOptions(opts...),
+	}
+}
+
+// Copy returns a copy of the tree that can be modified independently of the
+// original tree. Only trees backed by a DefaultStore can be copied. The copy
+// shares all existing nodes with the original tree, so copying is cheap even
+// for large trees and only nodes changed afterward are stored separately.
+func (t *CompactedTree) Copy() (*CompactedTree, error) {
+	store, ok := t.store.(*DefaultStore)
+	if !ok {
+		return nil, fmt.Errorf("cannot copy tree backed by %T", t.store)
 	}
+
+	return &CompactedTree{
+		store: store.Copy(),
+		opts:  t.opts,
+	}, nil
 }
 
 // Root returns the root node of the MS-SMT.
@@ -334,6 +353,189 @@ func (t *CompactedTree) Delete(ctx context.Context, key [hashSize]byte) (
 	return t, nil
 }
 
+// buildSubtree creates a new subtree at the given height from the passed
+// entries, which must all be non-empty leaves. A single leaf results in a
+// compacted leaf, while multiple leaves are split into a branch until they
+// can be compacted. Large subtrees are hashed in parallel before being
+// written to the store.
+func (t *CompactedTree) buildSubtree(tx TreeStoreUpdateTx, height int,
+	entries []batchEntry) (Node, error) {
+
+	subtree := newSubtreeBuilder(
+		true, t.opts.buildConcurrency,
+	).build(height, entries)
+	if err := storeSubtree(tx, height, subtree); err != nil {
+		return nil, err
+	}
+
+	return subtree, nil
+}
+
+// batchedInsert applies the batch of updates to the subtree rooted at the
+// given node of the given height. The children of the node are updated first,
+// which means each affected branch is only recomputed and written once. The
+// new root of the subtree is returned.
+func (t *CompactedTree) batchedInsert(tx TreeStoreUpdateTx, height int,
+	node Node, entries []batchEntry) (Node, error) {
+
+	// If nothing below this node changes, then the node itself doesn't
+	// change either.
+	if len(entries) == 0 {
+		return node, nil
+	}
+
+	var leaves []batchEntry
+	compactedLeaf, isCompactedLeaf := node.(*CompactedLeafNode)
+	switch {
+	// A compacted leaf is the only leaf in this subtree, so we replace it
+	// with a new subtree containing both the existing leaf and the leaves
+	// of the batch.
+	case isCompactedLeaf:
+		err := tx.DeleteCompactedLeaf(compactedLeaf.NodeHash())
+		if err != nil {
+			return nil, err
+		}
+
+		// The existing leaf is kept, unless the batch replaces or
+		// deletes it.
+		keepLeaf := true
+		for _, entry := range entries {
+			if entry.key == compactedLeaf.key {
+				keepLeaf = false
+				break
+			}
+		}
+		if keepLeaf {
+			leaves = append(leaves, batchEntry{
+				key:  compactedLeaf.key,
+				leaf: compactedLeaf.LeafNode,
+			})
+		}
+
+	// An empty subtree is simply replaced with a new subtree containing the
+	// leaves of the batch.
+	case node.NodeHash() == EmptyTree[height].NodeHash():
+
+	// Otherwise, we'll recurse into the children of the branch.
+	default:
+		return t.batchedInsertBranch(tx, height, node, entries)
+	}
+
+	for _, entry := range entries {
+		if !entry.leaf.IsEmpty() {
+			leaves = append(leaves, entry)
+		}
+	}
+
+	return t.buildSubtree(tx, height, leaves)
+}
+
+// batchedInsertBranch applies the batch of updates to the children of the
+// given non-empty branch and then replaces the branch itself.
+func (t *CompactedTree) batchedInsertBranch(tx TreeStoreUpdateTx, height int,
+	node Node, entries []batchEntry) (Node, error) {
+
+	left, right, err := tx.GetChildren(height, node.NodeHash())
+	if err != nil {
+		return nil, err
+	}
+
+	leftEntries, rightEntries := splitBatch(height, entries)
+	newLeft, err := t.batchedInsert(tx, height+1, left, leftEntries)
+	if err != nil {
+		return nil, err
+	}
+	newRight, err := t.batchedInsert(tx, height+1, right, rightEntries)
+	if err != nil {
+		return nil, err
+	}
+
+	if IsEqualNode(left, newLeft) && IsEqualNode(right, newRight) {
+		return node, nil
+	}
+
+	// Delete the old branch and create the new one, which is only inserted
+	// if not a default one.
+	if err := tx.DeleteBranch(node.NodeHash()); err != nil {
+		return nil, err
+	}
+
+	branch := NewBranch(newLeft, newRight)
+	if IsEqualNode(branch, EmptyTree[height]) {
+		return EmptyTree[height], nil
+	}
+
+	if err := tx.InsertBranch(branch); err != nil {
+		return nil, err
+	}
+
+	return branch, nil
+}
+
+// applyBatch applies the batch of updates to the tree and updates the root
+// once all of them are applied.
+func (t *CompactedTree) applyBatch(ctx context.Context,
+	entries []batchEntry) error {
+
+	return t.store.Update(ctx, func(tx TreeStoreUpdateTx) error {
+		currentRoot, err := tx.RootNode()
+		if err != nil {
+			return err
+		}
+
+		// First we'll check if the sum of the root and new leaves will
+		// overflow. If so, we'll return an error.
+		err = checkBatchSumOverflow(currentRoot, entries)
+		if err != nil {
+			return err
+		}
+
+		root, err := t.batchedInsert(tx, 0, currentRoot, entries)
+		if err != nil {
+			return err
+		}
+
+		return tx.UpdateRoot(root.(*BranchNode))
+	})
+}
+
+// InsertMany inserts multiple leaf nodes at their given keys within the
+// MS-SMT. All affected branches are only recomputed once.
+func (t *CompactedTree) InsertMany(ctx context.Context,
+	leaves map[[hashSize]byte]*LeafNode) (Tree, error) {
+
+	if err := t.applyBatch(ctx, newInsertBatch(leaves)); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// DeleteMany deletes the leaf nodes found at the given keys within the
+// MS-SMT. All affected branches are only recomputed once.
+func (t *CompactedTree) DeleteMany(ctx context.Context,
+	keys [][hashSize]byte) (Tree, error) {
+
+	if err := t.applyBatch(ctx, newDeleteBatch(keys)); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// Stats returns statistics about the nodes of the MS-SMT.
+func (t *CompactedTree) Stats(ctx context.Context) (*TreeStats, error) {
+	return treeStats(ctx, t.store)
+}
+
+// ForEachLeaf calls the given closure for each non-empty leaf of the MS-SMT
+// whose key is within the given range, in ascending order of their keys.
+func (t *CompactedTree) ForEachLeaf(ctx context.Context, keyRange *KeyRange,
+	cb LeafIterFunc) error {
+
+	return forEachLeaf(ctx, t.store, keyRange, cb)
+}
+
 // Get returns the leaf node found at the given key within the MS-SMT.
 func (t *CompactedTree) Get(ctx context.Context, key [hashSize]byte) (
 	*LeafNode, error) {
@@ -1,8 +1,10 @@
 package mssmt
 
 import (
+	"bytes"
 	"crypto/sha256"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
 )
@@ -47,8 +49,124 @@ func UnpackBits(bytes []byte) []bool {
 	return bits
 }
 
-// Encode encodes the compressed proof into the provided Writer.
+// CompressedProofVersion denotes the wire format a compressed proof is encoded
+// with.
+type CompressedProofVersion uint8
+
+const (
+	// CompressedProofV1 is the original wire format of a compressed proof.
+	// It consists of the number of non-default nodes as an uint16, each
+	// node as its hash followed by its sum as an uint64 and finally the
+	// packed bit vector of default nodes. As a proof has at most
+	// MaxTreeLevels nodes, the first byte is always either 0 or 1.
+	CompressedProofV1 CompressedProofVersion = 1
+
+	// CompressedProofV2 is the compact wire format of a compressed proof.
+	// It consists of a version byte, the packed bit vector of default
+	// nodes and each non-default node as its hash followed by its sum as a
+	// minimally encoded unsigned varint. The number of nodes is implied by
+	// the bit vector.
+	CompressedProofV2 CompressedProofVersion = 2
+)
+
+const (
+	// proofBitsSize is the size of the packed bit vector of default nodes
+	// of a compressed proof.
+	proofBitsSize = MaxTreeLevels / 8
+
+	// maxCompressedProofSizeV1 is the size of a compressed proof in the
+	// CompressedProofV1 wire format without any default nodes.
+	maxCompressedProofSizeV1 = 2 + MaxTreeLevels*(hashSize+8) +
+		proofBitsSize
+
+	// maxCompressedProofSizeV2 is the size of a compressed proof in the
+	// CompressedProofV2 wire format without any default nodes, of which
+	// all sums take up the maximum varint size.
+	maxCompressedProofSizeV2 = 1 + proofBitsSize +
+		MaxTreeLevels*(hashSize+binary.MaxVarintLen64)
+
+	// MaxCompressedProofSize is the maximum size of an encoded compressed
+	// proof across all wire format versions. As the number of nodes of a
+	// proof is bounded by the height of the tree, no valid proof can
+	// exceed it.
+	MaxCompressedProofSize = maxCompressedProofSizeV2
+)
+
+var (
+	// ErrUnknownProofVersion is returned when decoding a compressed proof
+	// of an unknown wire format version.
+	ErrUnknownProofVersion = errors.New(
+		"mssmt: unknown compressed proof version",
+	)
+
+	// ErrProofTooLarge is returned when an encoded compressed proof
+	// exceeds MaxCompressedProofSize.
+	ErrProofTooLarge = fmt.Errorf(
+		"mssmt: compressed proof exceeds maximum size of %d bytes",
+		MaxCompressedProofSize,
+	)
+)
+
+// Encode encodes the compressed proof into the provided Writer using the
+// original CompressedProofV1 wire format.
 func (p *CompressedProof) Encode(w io.Writer) error {
+	return p.EncodeVersion(w, CompressedProofV1)
+}
+
+// EncodeVersion encodes the compressed proof into the provided Writer using
+// the given wire format version.
+func (p *CompressedProof) EncodeVersion(w io.Writer,
+	version CompressedProofVersion) error {
+
+	switch version {
+	case CompressedProofV1:
+		return p.encodeV1(w)
+
+	case CompressedProofV2:
+		return p.encodeV2(w)
+
+	default:
+		return fmt.Errorf("%w: %d", ErrUnknownProofVersion, version)
+	}
+}
+
+// EncodedSize returns the number of bytes the compressed proof takes up when
+// encoded with the given wire format version, without encoding it.
+func (p *CompressedProof) EncodedSize(
+	version CompressedProofVersion) (int, error) {
+
+	switch version {
+	case CompressedProofV1:
+		return 2 + len(p.Nodes)*(hashSize+8) + proofBitsSize, nil
+
+	case CompressedProofV2:
+		size := 1 + proofBitsSize
+		for _, node := range p.Nodes {
+			size += hashSize + uvarintSize(node.NodeSum())
+		}
+
+		return size, nil
+
+	default:
+		return 0, fmt.Errorf("%w: %d", ErrUnknownProofVersion, version)
+	}
+}
+
+// uvarintSize returns the size of the minimal unsigned varint encoding of the
+// given value.
+func uvarintSize(value uint64) int {
+	size := 1
+	for value >= 0x80 {
+		value >>= 7
+		size++
+	}
+
+	return size
+}
+
+// encodeV1 encodes the compressed proof using the CompressedProofV1 wire
+// format.
+func (p *CompressedProof) encodeV1(w io.Writer) error {
 	if err := binary.Write(w, byteOrder, uint16(len(p.Nodes))); err != nil {
 		return err
 	}
@@ -67,16 +185,133 @@ func (p *CompressedProof) Encode(w io.Writer) error {
 	return err
 }
 
-// Decode decodes the compressed proof encoded within Reader.
+// encodeV2 encodes the compressed proof using the CompressedProofV2 wire
+// format.
+func (p *CompressedProof) encodeV2(w io.Writer) error {
+	if _, err := w.Write([]byte{byte(CompressedProofV2)}); err != nil {
+		return err
+	}
+
+	// The bit vector always has the full size, so we don't need to encode
+	// its length.
+	var bitsBytes [MaxTreeLevels / 8]byte
+	copy(bitsBytes[:], PackBits(p.Bits))
+	if _, err := w.Write(bitsBytes[:]); err != nil {
+		return err
+	}
+
+	var buf [binary.MaxVarintLen64]byte
+	for _, node := range p.Nodes {
+		key := node.NodeHash()
+		if _, err := w.Write(key[:]); err != nil {
+			return err
+		}
+
+		n := binary.PutUvarint(buf[:], node.NodeSum())
+		if _, err := w.Write(buf[:n]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// countingByteReader is an io.ByteReader on top of an io.Reader that counts
+// the number of bytes read.
+type countingByteReader struct {
+	r         io.Reader
+	bytesRead int
+}
+
+// ReadByte reads a single byte.
+//
+// NOTE: This implements the io.ByteReader interface.
+func (c *countingByteReader) ReadByte() (byte, error) {
+	var b [1]byte
+	if _, err := io.ReadFull(c.r, b[:]); err != nil {
+		return 0, err
+	}
+	c.bytesRead++
+
+	return b[0], nil
+}
+
+// readUvarint reads a minimally encoded unsigned varint.
+func readUvarint(r io.Reader) (uint64, error) {
+	byteReader := &countingByteReader{r: r}
+	value, err := binary.ReadUvarint(byteReader)
+	switch {
+	// A truncated varint is reported as io.EOF if not a single byte could
+	// be read.
+	case errors.Is(err, io.EOF):
+		return 0, io.ErrUnexpectedEOF
+
+	case err != nil:
+		return 0, err
+	}
+
+	var buf [binary.MaxVarintLen64]byte
+	if binary.PutUvarint(buf[:], value) != byteReader.bytesRead {
+		return 0, fmt.Errorf("%w: non-minimal varint",
+			ErrInvalidCompressedProof)
+	}
+
+	return value, nil
+}
+
+// Decode decodes the compressed proof encoded within Reader. Both the
+// CompressedProofV1 and the CompressedProofV2 wire formats are supported, the
+// version is detected from the first byte.
 func (p *CompressedProof) Decode(r io.Reader) error {
-	var numNodes uint16
-	if err := binary.Read(r, byteOrder, &numNodes); err != nil {
+	var versionByte [1]byte
+	if _, err := io.ReadFull(r, versionByte[:]); err != nil {
+		return err
+	}
+
+	switch {
+	// The first byte of a proof in the original wire format is the most
+	// significant byte of the number of nodes, which can only be 0 or 1.
+	case versionByte[0] <= 1:
+		return p.decodeV1(r, versionByte[0])
+
+	case versionByte[0] == byte(CompressedProofV2):
+		return p.decodeV2(r)
+
+	default:
+		return fmt.Errorf("%w: %d", ErrUnknownProofVersion,
+			versionByte[0])
+	}
+}
+
+// DecodeBytes decodes the compressed proof encoded within the given bytes in
+// any of the supported wire formats. Encodings that exceed
+// MaxCompressedProofSize are rejected before they're decoded.
+func (p *CompressedProof) DecodeBytes(encoded []byte) error {
+	if len(encoded) > MaxCompressedProofSize {
+		return fmt.Errorf("%w: got %d bytes", ErrProofTooLarge,
+			len(encoded))
+	}
+
+	return p.Decode(bytes.NewReader(encoded))
+}
+
+// decodeV1 decodes a compressed proof in the CompressedProofV1 wire format of
+// which the first byte was already read.
+func (p *CompressedProof) decodeV1(r io.Reader, firstByte byte) error {
+	var secondByte [1]byte
+	if _, err := io.ReadFull(r, secondByte[:]); err != nil {
 		return err
 	}
+	numNodes := uint16(firstByte)<<8 | uint16(secondByte[0])
+	if numNodes > MaxTreeLevels {
+		return fmt.Errorf("%w: too many nodes %d",
+			ErrInvalidCompressedProof, numNodes)
+	}
+
 	nodes := make([]Node, 0, numNodes)
 	for i := uint16(0); i < numNodes; i++ {
 		var keyBytes [sha256.Size]byte
-		if _, err := r.Read(keyBytes[:]); err != nil {
+		if _, err := io.ReadFull(r, keyBytes[:]); err != nil {
 			return err
 		}
 		var sum uint64
@@ -87,7 +322,7 @@ func (p *CompressedProof) Decode(r io.Reader) error {
 	}
 
 	var bitsBytes [MaxTreeLevels / 8]byte
-	if _, err := r.Read(bitsBytes[:]); err != nil {
+	if _, err := io.ReadFull(r, bitsBytes[:]); err != nil {
 		return err
 	}
 	bits := UnpackBits(bitsBytes[:])
@@ -98,3 +333,40 @@ func (p *CompressedProof) Decode(r io.Reader) error {
 	}
 	return nil
 }
+
+// decodeV2 decodes a compressed proof in the CompressedProofV2 wire format of
+// which the version byte was already read.
+func (p *CompressedProof) decodeV2(r io.Reader) error {
+	var bitsBytes [MaxTreeLevels / 8]byte
+	if _, err := io.ReadFull(r, bitsBytes[:]); err != nil {
+		return err
+	}
+	bits := UnpackBits(bitsBytes[:])
+
+	// Each unset bit denotes a non-default node that follows.
+	var numNodes int
+	for _, bit := range bits {
+		if !bit {
+			numNodes++
+		}
+	}
+
+	nodes := make([]Node, 0, numNodes)
+	for i := 0; i < numNodes; i++ {
+		var keyBytes [sha256.Size]byte
+		if _, err := io.ReadFull(r, keyBytes[:]); err != nil {
+			return err
+		}
+		sum, err := readUvarint(r)
+		if err != nil {
+			return err
+		}
+		nodes = append(nodes, NewComputedNode(NodeHash(keyBytes), sum))
+	}
+
+	*p = CompressedProof{
+		Bits:  bits,
+		Nodes: nodes,
+	}
+	return nil
+}
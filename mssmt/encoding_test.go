@@ -3,6 +3,8 @@ package mssmt_test
 import (
 	"bytes"
 	"context"
+	"io"
+	"math"
 	"testing"
 
 	"github.com/lightninglabs/taproot-assets/mssmt"
@@ -66,14 +68,164 @@ func TestProofEncoding(t *testing.T) {
 		err = compressed.Encode(&buf)
 		require.NoError(t, err)
 
-		var decodedCompressed mssmt.CompressedProof
-		err = decodedCompressed.Decode(bytes.NewReader(buf.Bytes()))
+		// The default encoding must be the original wire format.
+		var bufV1 bytes.Buffer
+		err = compressed.EncodeVersion(&bufV1, mssmt.CompressedProofV1)
 		require.NoError(t, err)
-		assertEqualCompressedProof(t, compressed, &decodedCompressed)
+		require.Equal(t, bufV1.Bytes(), buf.Bytes())
 
-		decodedProof, err := decodedCompressed.Decompress()
+		var bufV2 bytes.Buffer
+		err = compressed.EncodeVersion(&bufV2, mssmt.CompressedProofV2)
 		require.NoError(t, err)
-		assertEqualProof(t, proof, decodedProof)
-		assertEqualProof(t, proof, decodedProof.Copy())
+		require.Less(t, bufV2.Len(), bufV1.Len())
+
+		// The encoded sizes are known without encoding the proof.
+		sizeV1, err := compressed.EncodedSize(mssmt.CompressedProofV1)
+		require.NoError(t, err)
+		require.Equal(t, bufV1.Len(), sizeV1)
+		sizeV2, err := compressed.EncodedSize(mssmt.CompressedProofV2)
+		require.NoError(t, err)
+		require.Equal(t, bufV2.Len(), sizeV2)
+
+		for _, encoded := range [][]byte{bufV1.Bytes(), bufV2.Bytes()} {
+			var decodedCompressed mssmt.CompressedProof
+			err = decodedCompressed.Decode(bytes.NewReader(encoded))
+			require.NoError(t, err)
+			assertEqualCompressedProof(
+				t, compressed, &decodedCompressed,
+			)
+
+			decodedProof, err := decodedCompressed.Decompress()
+			require.NoError(t, err)
+			assertEqualProof(t, proof, decodedProof)
+			assertEqualProof(t, proof, decodedProof.Copy())
+		}
+	}
+}
+
+// TestMaxCompressedProofSize tests that no compressed proof exceeds the
+// maximum size in any wire format, and that larger encodings are rejected.
+func TestMaxCompressedProofSize(t *testing.T) {
+	t.Parallel()
+
+	// The largest possible proof has no default nodes, and the sums of
+	// all nodes take up the maximum varint size.
+	largest := &mssmt.CompressedProof{
+		Bits:  make([]bool, mssmt.MaxTreeLevels),
+		Nodes: make([]mssmt.Node, mssmt.MaxTreeLevels),
+	}
+	for i := range largest.Nodes {
+		largest.Nodes[i] = mssmt.NewComputedNode(
+			mssmt.NodeHash{byte(i)}, math.MaxUint64,
+		)
+	}
+
+	versions := []mssmt.CompressedProofVersion{
+		mssmt.CompressedProofV1, mssmt.CompressedProofV2,
+	}
+	var maxSize int
+	for _, version := range versions {
+		var buf bytes.Buffer
+		require.NoError(t, largest.EncodeVersion(&buf, version))
+		require.LessOrEqual(t, buf.Len(), mssmt.MaxCompressedProofSize)
+
+		size, err := largest.EncodedSize(version)
+		require.NoError(t, err)
+		require.Equal(t, buf.Len(), size)
+		if size > maxSize {
+			maxSize = size
+		}
+
+		var decoded mssmt.CompressedProof
+		require.NoError(t, decoded.DecodeBytes(buf.Bytes()))
+		assertEqualCompressedProof(t, largest, &decoded)
+
+		// Any encoding beyond the maximum size is rejected before
+		// it's decoded.
+		padding := make([]byte, mssmt.MaxCompressedProofSize)
+		tooLarge := append(buf.Bytes(), padding...)
+		err = decoded.DecodeBytes(tooLarge)
+		require.ErrorIs(t, err, mssmt.ErrProofTooLarge)
+	}
+
+	// The maximum size is tight.
+	require.Equal(t, mssmt.MaxCompressedProofSize, maxSize)
+
+	_, err := largest.EncodedSize(3)
+	require.ErrorIs(t, err, mssmt.ErrUnknownProofVersion)
+}
+
+// TestProofEncodingInvalid tests that invalid compressed proof encodings are
+// rejected.
+func TestProofEncodingInvalid(t *testing.T) {
+	t.Parallel()
+
+	leaves := randTree(10)
+	tree := mssmt.NewCompactedTree(mssmt.NewDefaultStore())
+	ctx := context.TODO()
+	for _, item := range leaves {
+		_, err := tree.Insert(ctx, item.key, item.leaf)
+		require.NoError(t, err)
+	}
+
+	proof, err := tree.MerkleProof(ctx, leaves[0].key)
+	require.NoError(t, err)
+	compressed := proof.Compress()
+
+	var buf bytes.Buffer
+	err = compressed.EncodeVersion(&buf, mssmt.CompressedProofV2)
+	require.NoError(t, err)
+	encodedV2 := buf.Bytes()
+
+	err = compressed.EncodeVersion(&buf, 3)
+	require.ErrorIs(t, err, mssmt.ErrUnknownProofVersion)
+
+	// A proof with a single non-default node, of which the sum of zero is
+	// encoded with two instead of a single byte.
+	nonMinimalV2 := []byte{byte(mssmt.CompressedProofV2), 0xfe}
+	nonMinimalV2 = append(nonMinimalV2, bytes.Repeat([]byte{0xff}, 31)...)
+	nonMinimalV2 = append(nonMinimalV2, make([]byte, 32)...)
+	nonMinimalV2 = append(nonMinimalV2, 0x80, 0x00)
+
+	testCases := []struct {
+		name    string
+		encoded []byte
+		err     error
+	}{{
+		name:    "empty",
+		encoded: nil,
+		err:     io.EOF,
+	}, {
+		name:    "unknown version",
+		encoded: append([]byte{3}, encodedV2[1:]...),
+		err:     mssmt.ErrUnknownProofVersion,
+	}, {
+		name:    "too many v1 nodes",
+		encoded: []byte{1, 1},
+		err:     mssmt.ErrInvalidCompressedProof,
+	}, {
+		name:    "truncated v2 bits",
+		encoded: encodedV2[:10],
+		err:     io.ErrUnexpectedEOF,
+	}, {
+		name:    "non-minimal v2 sum",
+		encoded: nonMinimalV2,
+		err:     mssmt.ErrInvalidCompressedProof,
+	}, {
+		name:    "truncated v2 nodes",
+		encoded: encodedV2[:len(encodedV2)-40],
+		err:     io.ErrUnexpectedEOF,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var decoded mssmt.CompressedProof
+			err := decoded.Decode(bytes.NewReader(tc.encoded))
+			require.ErrorIs(t, err, tc.err)
+		})
 	}
 }
@@ -11,10 +11,21 @@ type Tree interface {
 	Insert(ctx context.Context, key [hashSize]byte, leaf *LeafNode) (
 		Tree, error)
 
+	// InsertMany inserts multiple leaf nodes at their given keys within the
+	// MS-SMT. All affected branches are only recomputed once, no matter how
+	// many of the leaves are below them.
+	InsertMany(ctx context.Context, leaves map[[hashSize]byte]*LeafNode) (
+		Tree, error)
+
 	// Delete deletes the leaf node found at the given key within the
 	// MS-SMT.
 	Delete(ctx context.Context, key [hashSize]byte) (Tree, error)
 
+	// DeleteMany deletes the leaf nodes found at the given keys within the
+	// MS-SMT. All affected branches are only recomputed once, no matter how
+	// many of the leaves are below them.
+	DeleteMany(ctx context.Context, keys [][hashSize]byte) (Tree, error)
+
 	// Get returns the leaf node found at the given key within the MS-SMT.
 	Get(ctx context.Context, key [hashSize]byte) (*LeafNode, error)
 
@@ -24,4 +35,20 @@ type Tree interface {
 	// proof. This is noted by the returned `Proof` containing an empty
 	// leaf.
 	MerkleProof(ctx context.Context, key [hashSize]byte) (*Proof, error)
+
+	// Stats returns statistics about the nodes of the MS-SMT. This walks
+	// all non-default nodes of the tree, so it should be used with care
+	// for large trees.
+	Stats(ctx context.Context) (*TreeStats, error)
+
+	// ForEachLeaf calls the given closure for each non-empty leaf of the
+	// MS-SMT whose key is within the given range, in ascending order of
+	// their keys. A nil range results in all leaves being iterated over.
+	// The iteration stops at the first error returned by the closure,
+	// which is then returned, unless it is ErrStopIteration.
+	//
+	// NOTE: The closure is called within a read transaction of the
+	// backing store, so it must not modify the tree.
+	ForEachLeaf(ctx context.Context, keyRange *KeyRange,
+		cb LeafIterFunc) error
 }
@@ -0,0 +1,215 @@
+package mssmt
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var (
+	// ErrStopIteration can be returned by the closure passed to
+	// ForEachLeaf to stop the iteration early without an error.
+	ErrStopIteration = errors.New("mssmt: stop iteration")
+)
+
+// LeafIterFunc is the closure that is called for each leaf of a tree when
+// iterating over its leaves.
+type LeafIterFunc = func(key [hashSize]byte, leaf *LeafNode) error
+
+// KeyRange is an inclusive range of leaf keys, ordered lexicographically.
+type KeyRange struct {
+	// Start is the smallest key within the range.
+	Start [hashSize]byte
+
+	// End is the largest key within the range.
+	End [hashSize]byte
+}
+
+// NewKeyRange creates a new inclusive range of leaf keys.
+func NewKeyRange(start, end [hashSize]byte) *KeyRange {
+	return &KeyRange{
+		Start: start,
+		End:   end,
+	}
+}
+
+// NewPrefixKeyRange creates a new range of all leaf keys that start with the
+// given prefix. A prefix longer than a key is truncated.
+func NewPrefixKeyRange(prefix []byte) *KeyRange {
+	var keyRange KeyRange
+	copy(keyRange.Start[:], prefix)
+	copy(keyRange.End[:], prefix)
+	for i := len(prefix); i < hashSize; i++ {
+		keyRange.End[i] = 0xff
+	}
+
+	return &keyRange
+}
+
+// Contains returns true if the given key is within the range.
+func (r *KeyRange) Contains(key [hashSize]byte) bool {
+	return bytes.Compare(key[:], r.Start[:]) >= 0 &&
+		bytes.Compare(key[:], r.End[:]) <= 0
+}
+
+// byteLevelNode is a non-default node found below a branch at the height of
+// the next full byte of the key, or a compacted leaf found above that height.
+type byteLevelNode struct {
+	// byteVal is the value of the key byte the path to the node commits
+	// to.
+	byteVal byte
+
+	node Node
+}
+
+// forEachLeaf calls the given closure for each non-empty leaf of the tree
+// stored in the given store whose key is within the given range, in ascending
+// order of their keys. A nil range results in all leaves being iterated over.
+func forEachLeaf(ctx context.Context, store TreeStore, keyRange *KeyRange,
+	cb LeafIterFunc) error {
+
+	if keyRange == nil {
+		keyRange = NewPrefixKeyRange(nil)
+	}
+
+	err := store.View(ctx, func(tx TreeStoreViewTx) error {
+		root, err := tx.RootNode()
+		if err != nil {
+			return err
+		}
+
+		var prefix [hashSize]byte
+		return walkLeaves(tx, 0, root, &prefix, keyRange, cb)
+	})
+	if errors.Is(err, ErrStopIteration) {
+		return nil
+	}
+
+	return err
+}
+
+// walkLeaves calls the given closure for all leaves below the given node at
+// the given height, which must be a multiple of 8, that are within the given
+// range. The prefix holds the key bytes the path to the node commits to.
+//
+// As the key bits of a single byte are walked from the least to the most
+// significant one, walking the tree in order doesn't result in the leaves
+// being ordered by their key. We therefore collect all nodes at the height of
+// the next full key byte and walk them sorted by that byte.
+func walkLeaves(tx TreeStoreViewTx, height int, node Node,
+	prefix *[hashSize]byte, keyRange *KeyRange, cb LeafIterFunc) error {
+
+	// Default nodes don't contain any leaves.
+	if node.NodeHash() == EmptyTree[height].NodeHash() {
+		return nil
+	}
+
+	switch node := node.(type) {
+	case *CompactedLeafNode:
+		if !keyRange.Contains(node.Key()) {
+			return nil
+		}
+
+		return cb(node.Key(), node.LeafNode)
+
+	case *LeafNode:
+		if !keyRange.Contains(*prefix) {
+			return nil
+		}
+
+		return cb(*prefix, node)
+	}
+
+	byteNodes, err := collectByteLevel(tx, height, node, 0, 0, nil)
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(byteNodes, func(i, j int) bool {
+		return byteNodes[i].byteVal < byteNodes[j].byteVal
+	})
+
+	byteIdx := height / 8
+	defer func() {
+		prefix[byteIdx] = 0
+	}()
+
+	for _, byteNode := range byteNodes {
+		prefix[byteIdx] = byteNode.byteVal
+
+		// All keys below this node start with the prefix, so we can
+		// skip it if the prefix is out of range. As the nodes are
+		// sorted, we're done once we're past the end of the range.
+		pathPrefix := prefix[:byteIdx+1]
+		if bytes.Compare(pathPrefix, keyRange.End[:byteIdx+1]) > 0 {
+			return nil
+		}
+		if bytes.Compare(pathPrefix, keyRange.Start[:byteIdx+1]) < 0 {
+			continue
+		}
+
+		err := walkLeaves(
+			tx, height+8, byteNode.node, prefix, keyRange, cb,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// collectByteLevel collects all non-default nodes below the given node that
+// are found 8 levels below the given byte aligned height, as well as all
+// compacted leaves found above that. The depth is the number of levels the
+// node is below the byte aligned height, while the byte value holds the key
+// bits of the path walked so far.
+func collectByteLevel(tx TreeStoreViewTx, byteHeight int, node Node,
+	depth int, byteVal byte, byteNodes []byteLevelNode) ([]byteLevelNode,
+	error) {
+
+	height := byteHeight + depth
+	if node.NodeHash() == EmptyTree[height].NodeHash() {
+		return byteNodes, nil
+	}
+
+	// A compacted leaf commits to the full key, so we take the byte value
+	// from the key itself.
+	if leaf, ok := node.(*CompactedLeafNode); ok {
+		key := leaf.Key()
+		return append(byteNodes, byteLevelNode{
+			byteVal: key[byteHeight/8],
+			node:    leaf,
+		}), nil
+	}
+
+	if depth == 8 {
+		return append(byteNodes, byteLevelNode{
+			byteVal: byteVal,
+			node:    node,
+		}), nil
+	}
+
+	if _, ok := node.(*BranchNode); !ok {
+		return nil, fmt.Errorf("unexpected node type %T at height %d",
+			node, height)
+	}
+
+	left, right, err := tx.GetChildren(height, node.NodeHash())
+	if err != nil {
+		return nil, err
+	}
+
+	byteNodes, err = collectByteLevel(
+		tx, byteHeight, left, depth+1, byteVal, byteNodes,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectByteLevel(
+		tx, byteHeight, right, depth+1, byteVal|1<<depth, byteNodes,
+	)
+}
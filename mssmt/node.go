@@ -260,10 +260,20 @@ func (n *BranchNode) Copy() Node {
 		*sumCopy = *n.sum
 	}
 
+	// A branch created with NewComputedBranch, e.g. one read from a
+	// persistent store, has no reference to its children.
+	var left, right Node
+	if n.Left != nil {
+		left = NewComputedNode(n.Left.NodeHash(), n.Left.NodeSum())
+	}
+	if n.Right != nil {
+		right = NewComputedNode(n.Right.NodeHash(), n.Right.NodeSum())
+	}
+
 	return &BranchNode{
 		nodeHash: nodeHashCopy,
-		Left:     NewComputedNode(n.Left.NodeHash(), n.Left.NodeSum()),
-		Right:    NewComputedNode(n.Right.NodeHash(), n.Right.NodeSum()),
+		Left:     left,
+		Right:    right,
 		sum:      sumCopy,
 	}
 }
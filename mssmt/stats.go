@@ -0,0 +1,136 @@
+package mssmt
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TreeStats holds statistics about the nodes stored for a MS-SMT.
+type TreeStats struct {
+	// NumLeaves is the number of non-empty leaves in the tree.
+	NumLeaves uint64
+
+	// NumCompactedLeaves is the number of leaves that are stored as
+	// compacted leaves. This is always zero for a FullTree and always
+	// equal to NumLeaves for a CompactedTree.
+	NumCompactedLeaves uint64
+
+	// NumBranches is the number of non-default branches in the tree,
+	// including the root.
+	NumBranches uint64
+
+	// LeafDepths maps the depth at which leaves are stored to the number
+	// of leaves stored at that depth. All leaves of a FullTree are stored
+	// at depth MaxTreeLevels, while the leaves of a CompactedTree are
+	// stored right below the branch at which their path diverges from all
+	// other leaves.
+	LeafDepths map[int]uint64
+
+	// TotalSum is the sum of all leaves in the tree.
+	TotalSum uint64
+}
+
+// NumNodes returns the number of non-default nodes in the tree.
+func (s *TreeStats) NumNodes() uint64 {
+	return s.NumLeaves + s.NumBranches
+}
+
+// MaxLeafDepth returns the maximum depth at which a leaf is stored, or zero
+// if the tree is empty.
+func (s *TreeStats) MaxLeafDepth() int {
+	var maxDepth int
+	for depth := range s.LeafDepths {
+		if depth > maxDepth {
+			maxDepth = depth
+		}
+	}
+
+	return maxDepth
+}
+
+// String returns a human-readable summary of the statistics.
+func (s *TreeStats) String() string {
+	depths := make([]int, 0, len(s.LeafDepths))
+	for depth := range s.LeafDepths {
+		depths = append(depths, depth)
+	}
+	sort.Ints(depths)
+
+	leafDepths := make([]string, 0, len(depths))
+	for _, depth := range depths {
+		leafDepths = append(leafDepths, fmt.Sprintf("%d:%d", depth,
+			s.LeafDepths[depth]))
+	}
+
+	return fmt.Sprintf("leaves=%d (compacted=%d), branches=%d, "+
+		"total_sum=%d, leaf_depths=[%s]", s.NumLeaves,
+		s.NumCompactedLeaves, s.NumBranches, s.TotalSum,
+		strings.Join(leafDepths, " "))
+}
+
+// treeStats walks all non-default nodes of the tree stored in the given
+// store and collects statistics about them.
+func treeStats(ctx context.Context, store TreeStore) (*TreeStats, error) {
+	var stats *TreeStats
+	err := store.View(ctx, func(tx TreeStoreViewTx) error {
+		stats = &TreeStats{
+			LeafDepths: make(map[int]uint64),
+		}
+
+		root, err := tx.RootNode()
+		if err != nil {
+			return err
+		}
+		stats.TotalSum = root.NodeSum()
+
+		return walkStats(tx, 0, root, stats)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// walkStats adds the given node at the given height and all nodes below it to
+// the statistics.
+func walkStats(tx TreeStoreViewTx, height int, node Node,
+	stats *TreeStats) error {
+
+	// Default nodes are never stored, so there's nothing to count.
+	if node.NodeHash() == EmptyTree[height].NodeHash() {
+		return nil
+	}
+
+	switch node.(type) {
+	case *CompactedLeafNode:
+		stats.NumLeaves++
+		stats.NumCompactedLeaves++
+		stats.LeafDepths[height]++
+
+	case *LeafNode:
+		stats.NumLeaves++
+		stats.LeafDepths[height]++
+
+	case *BranchNode:
+		stats.NumBranches++
+
+		left, right, err := tx.GetChildren(height, node.NodeHash())
+		if err != nil {
+			return err
+		}
+		if err := walkStats(tx, height+1, left, stats); err != nil {
+			return err
+		}
+
+		return walkStats(tx, height+1, right, stats)
+
+	default:
+		return fmt.Errorf("unexpected node type %T at height %d", node,
+			height)
+	}
+
+	return nil
+}
@@ -114,11 +114,52 @@ func RegisterTreeStore(driver *TreeStoreDriver) error {
 	return nil
 }
 
-// DefaultStore is an in-memory implementation of the TreeStore interface.
-type DefaultStore struct {
+// maxFrozenLayers is the maximum number of frozen node layers a DefaultStore
+// keeps before they are flattened into a single layer again, which bounds the
+// number of layers a node lookup needs to go through.
+const maxFrozenLayers = 16
+
+// nodeLayer is a set of nodes of a DefaultStore. All nodes are keyed by their
+// hash, so they are immutable. Once a layer is frozen by copying the store, it
+// is shared between all copies and never modified again.
+type nodeLayer struct {
 	branches        map[NodeHash]*BranchNode
 	leaves          map[NodeHash]*LeafNode
 	compactedLeaves map[NodeHash]*CompactedLeafNode
+}
+
+// newNodeLayer creates a new empty node layer.
+func newNodeLayer() *nodeLayer {
+	return &nodeLayer{
+		branches:        make(map[NodeHash]*BranchNode),
+		leaves:          make(map[NodeHash]*LeafNode),
+		compactedLeaves: make(map[NodeHash]*CompactedLeafNode),
+	}
+}
+
+// isEmpty returns true if the layer doesn't contain any nodes.
+func (l *nodeLayer) isEmpty() bool {
+	return len(l.branches) == 0 && len(l.leaves) == 0 &&
+		len(l.compactedLeaves) == 0
+}
+
+// DefaultStore is an in-memory implementation of the TreeStore interface.
+//
+// The store can be copied cheaply with Copy, in which case the nodes stored so
+// far are shared between the original and the copy. Any node written after the
+// copy is only stored in the (copy of the) store that wrote it.
+type DefaultStore struct {
+	// nodeLayer holds all nodes written since the store was created or
+	// last copied.
+	*nodeLayer
+
+	// frozen are the node layers shared with copies of this store, from
+	// oldest to newest.
+	frozen []*nodeLayer
+
+	// removed are the hashes of nodes that were deleted from this store
+	// while they're still part of one of the frozen layers.
+	removed map[NodeHash]struct{}
 
 	root *BranchNode
 
@@ -132,32 +173,163 @@ var _ TreeStore = (*DefaultStore)(nil)
 // NewDefaultStore initializes a new DefaultStore.
 func NewDefaultStore() *DefaultStore {
 	return &DefaultStore{
-		branches:        make(map[NodeHash]*BranchNode),
-		leaves:          make(map[NodeHash]*LeafNode),
-		compactedLeaves: make(map[NodeHash]*CompactedLeafNode),
+		nodeLayer: newNodeLayer(),
+		removed:   make(map[NodeHash]struct{}),
+	}
+}
+
+// Copy returns a copy of the store that can be modified independently of the
+// original store. Instead of copying all nodes, the nodes stored so far are
+// shared between both stores, so this is cheap even for large trees.
+//
+// NOTE: Just like all other methods of the store, this isn't safe for
+// concurrent use, as the original store is modified as well.
+func (d *DefaultStore) Copy() *DefaultStore {
+	// The nodes written so far become a frozen layer that is shared with
+	// the copy. We clip the slice so appending to it in either store never
+	// overwrites a layer of the other one.
+	if !d.nodeLayer.isEmpty() {
+		numFrozen := len(d.frozen)
+		d.frozen = append(d.frozen[:numFrozen:numFrozen], d.nodeLayer)
+		d.nodeLayer = newNodeLayer()
+	}
+	if len(d.frozen) > maxFrozenLayers {
+		d.flatten()
+	}
+
+	removed := make(map[NodeHash]struct{}, len(d.removed))
+	for key := range d.removed {
+		removed[key] = struct{}{}
+	}
+
+	numFrozen := len(d.frozen)
+	return &DefaultStore{
+		nodeLayer: newNodeLayer(),
+		frozen:    d.frozen[:numFrozen:numFrozen],
+		removed:   removed,
+		root:      d.root,
+	}
+}
+
+// flatten merges all frozen layers into a single new layer that is no longer
+// shared with any copy of the store, dropping all removed nodes.
+func (d *DefaultStore) flatten() {
+	flat := newNodeLayer()
+	for _, layer := range d.frozen {
+		for key, branch := range layer.branches {
+			flat.branches[key] = branch
+		}
+		for key, leaf := range layer.leaves {
+			flat.leaves[key] = leaf
+		}
+		for key, leaf := range layer.compactedLeaves {
+			flat.compactedLeaves[key] = leaf
+		}
+	}
+	for key := range d.removed {
+		delete(flat.branches, key)
+		delete(flat.leaves, key)
+		delete(flat.compactedLeaves, key)
+	}
+
+	d.frozen = []*nodeLayer{flat}
+	d.removed = make(map[NodeHash]struct{})
+}
+
+// lookupNode returns the node with the given hash from the given node maps of
+// the store's layers.
+func lookupNode[T any](d *DefaultStore, key NodeHash,
+	nodes func(*nodeLayer) map[NodeHash]T) (T, bool) {
+
+	if node, ok := nodes(d.nodeLayer)[key]; ok {
+		return node, true
+	}
+
+	var empty T
+	if _, ok := d.removed[key]; ok {
+		return empty, false
 	}
+	for i := len(d.frozen) - 1; i >= 0; i-- {
+		if node, ok := nodes(d.frozen[i])[key]; ok {
+			return node, true
+		}
+	}
+
+	return empty, false
+}
+
+// countNodes returns the number of nodes in the given node maps of the store's
+// layers.
+func countNodes[T any](d *DefaultStore,
+	nodes func(*nodeLayer) map[NodeHash]T) int {
+
+	if len(d.frozen) == 0 {
+		return len(nodes(d.nodeLayer))
+	}
+
+	seen := make(map[NodeHash]struct{})
+	for key := range nodes(d.nodeLayer) {
+		seen[key] = struct{}{}
+	}
+	for _, layer := range d.frozen {
+		for key := range nodes(layer) {
+			if _, ok := d.removed[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+		}
+	}
+
+	return len(seen)
+}
+
+// isFrozen returns true if the node with the given hash is part of one of the
+// frozen layers.
+func (d *DefaultStore) isFrozen(key NodeHash) bool {
+	for _, layer := range d.frozen {
+		_, isBranch := layer.branches[key]
+		_, isLeaf := layer.leaves[key]
+		_, isCompactedLeaf := layer.compactedLeaves[key]
+		if isBranch || isLeaf || isCompactedLeaf {
+			return true
+		}
+	}
+
+	return false
+}
+
+func branchNodes(l *nodeLayer) map[NodeHash]*BranchNode {
+	return l.branches
+}
+
+func leafNodes(l *nodeLayer) map[NodeHash]*LeafNode {
+	return l.leaves
+}
+
+func compactedLeafNodes(l *nodeLayer) map[NodeHash]*CompactedLeafNode {
+	return l.compactedLeaves
 }
 
 // NumBranches returns the number of stored branches.
 func (d *DefaultStore) NumBranches() int {
-	return len(d.branches)
+	return countNodes(d, branchNodes)
 }
 
 // NumLeaves returns the number of stored leaves.
 func (d *DefaultStore) NumLeaves() int {
-	return len(d.leaves)
+	return countNodes(d, leafNodes)
 }
 
 // NumCompactedLeaves returns the number of stored compacted leaves.
 func (d *DefaultStore) NumCompactedLeaves() int {
-	return len(d.compactedLeaves)
+	return countNodes(d, compactedLeafNodes)
 }
 
 // Stats returns store statistics as a string (useful for debugging).
 func (d *DefaultStore) Stats() string {
 	return fmt.Sprintf("branches=%v, leaves=%v, cleaves=%v, reads=%v, "+
-		"writes=%v, deletes=%v\n", len(d.branches), len(d.leaves),
-		len(d.compactedLeaves), d.cntReads, d.cntWrites, d.cntDeletes)
+		"writes=%v, deletes=%v\n", d.NumBranches(), d.NumLeaves(),
+		d.NumCompactedLeaves(), d.cntReads, d.cntWrites, d.cntDeletes)
 }
 
 // Update updates the persistent tree in the passed update closure using the
@@ -198,6 +370,7 @@ func (d *DefaultStore) RootNode() (Node, error) {
 // InsertBranch stores a new branch keyed by its NodeHash.
 func (d *DefaultStore) InsertBranch(branch *BranchNode) error {
 	d.branches[branch.NodeHash()] = branch
+	delete(d.removed, branch.NodeHash())
 	d.cntWrites++
 
 	return nil
@@ -206,6 +379,7 @@ func (d *DefaultStore) InsertBranch(branch *BranchNode) error {
 // InsertLeaf stores a new leaf keyed by its NodeHash.
 func (d *DefaultStore) InsertLeaf(leaf *LeafNode) error {
 	d.leaves[leaf.NodeHash()] = leaf
+	delete(d.removed, leaf.NodeHash())
 	d.cntWrites++
 
 	return nil
@@ -215,6 +389,7 @@ func (d *DefaultStore) InsertLeaf(leaf *LeafNode) error {
 // the insertion key).
 func (d *DefaultStore) InsertCompactedLeaf(leaf *CompactedLeafNode) error {
 	d.compactedLeaves[leaf.NodeHash()] = leaf
+	delete(d.removed, leaf.NodeHash())
 	d.cntWrites++
 
 	return nil
@@ -223,6 +398,9 @@ func (d *DefaultStore) InsertCompactedLeaf(leaf *CompactedLeafNode) error {
 // DeleteBranch deletes the branch node keyed by the given NodeHash.
 func (d *DefaultStore) DeleteBranch(key NodeHash) error {
 	delete(d.branches, key)
+	if d.isFrozen(key) {
+		d.removed[key] = struct{}{}
+	}
 	d.cntDeletes++
 
 	return nil
@@ -231,6 +409,9 @@ func (d *DefaultStore) DeleteBranch(key NodeHash) error {
 // DeleteLeaf deletes the leaf node keyed by the given NodeHash.
 func (d *DefaultStore) DeleteLeaf(key NodeHash) error {
 	delete(d.leaves, key)
+	if d.isFrozen(key) {
+		d.removed[key] = struct{}{}
+	}
 	d.cntDeletes++
 
 	return nil
@@ -239,6 +420,9 @@ func (d *DefaultStore) DeleteLeaf(key NodeHash) error {
 // DeleteCompactedLeaf deletes a compacted leaf keyed by the given NodeHash.
 func (d *DefaultStore) DeleteCompactedLeaf(key NodeHash) error {
 	delete(d.compactedLeaves, key)
+	if d.isFrozen(key) {
+		d.removed[key] = struct{}{}
+	}
 	d.cntDeletes++
 
 	return nil
@@ -253,17 +437,18 @@ func (d *DefaultStore) GetChildren(height int, key NodeHash) (
 		if key == EmptyTree[height].NodeHash() {
 			return EmptyTree[height]
 		}
-		if branch, ok := d.branches[key]; ok {
+		if branch, ok := lookupNode(d, key, branchNodes); ok {
 			d.cntReads++
 			return branch
 		}
-		if leaf, ok := d.compactedLeaves[key]; ok {
+		if leaf, ok := lookupNode(d, key, compactedLeafNodes); ok {
 			d.cntReads++
 			return leaf
 		}
 
 		d.cntReads++
-		return d.leaves[key]
+		leaf, _ := lookupNode(d, key, leafNodes)
+		return leaf
 	}
 
 	node := getNode(uint(height), key)
@@ -0,0 +1,202 @@
+package mssmt
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+const (
+	// minParallelBuildLeaves is the minimum number of leaves both halves
+	// of a new subtree must contain for them to be built in parallel.
+	// Below that, the overhead of spawning a goroutine outweighs the time
+	// spent hashing.
+	minParallelBuildLeaves = 16
+)
+
+// TreeOption is a functional option that modifies a tree.
+type TreeOption func(*treeOptions)
+
+// treeOptions is the set of options of a tree.
+type treeOptions struct {
+	// buildConcurrency is the maximum number of goroutines, including the
+	// calling one, that build new subtrees of a batch in parallel.
+	buildConcurrency int
+}
+
+// defaultTreeOptions returns the default set of options of a tree, which
+// builds new subtrees with as many goroutines as there are usable CPUs.
+func defaultTreeOptions() treeOptions {
+	return treeOptions{
+		buildConcurrency: runtime.GOMAXPROCS(0),
+	}
+}
+
+// newTreeOptions returns the set of options resulting from applying the passed
+// options to the default options.
+func newTreeOptions(opts ...TreeOption) treeOptions {
+	options := defaultTreeOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return options
+}
+
+// WithBuildConcurrency is a TreeOption that sets the maximum number of
+// goroutines used to build new subtrees when inserting a batch of leaves,
+// including the calling goroutine. A concurrency of one or less builds all
+// subtrees sequentially.
+func WithBuildConcurrency(concurrency int) TreeOption {
+	return func(o *treeOptions) {
+		o.buildConcurrency = concurrency
+	}
+}
+
+// subtreeBuilder builds new subtrees entirely in memory before they're
+// written to a store. As a new subtree doesn't depend on any node that is
+// already stored, its independent halves can be hashed in parallel, which is
+// where most of the time of large tree builds is spent.
+type subtreeBuilder struct {
+	// compact indicates whether leaves are stored as compacted leaves
+	// right below the height at which their path diverges from all other
+	// leaves, or as plain leaves at the bottom of the tree.
+	compact bool
+
+	// workers is a semaphore that limits the number of additional
+	// goroutines used to build subtrees.
+	workers chan struct{}
+}
+
+// newSubtreeBuilder creates a new subtree builder that uses at most the given
+// number of goroutines.
+func newSubtreeBuilder(compact bool, concurrency int) *subtreeBuilder {
+	// The calling goroutine builds subtrees as well, so we only need one
+	// less worker than the concurrency.
+	numWorkers := concurrency - 1
+	if numWorkers < 0 {
+		numWorkers = 0
+	}
+
+	return &subtreeBuilder{
+		compact: compact,
+		workers: make(chan struct{}, numWorkers),
+	}
+}
+
+// build creates a new subtree at the given height from the passed entries,
+// which must all be non-empty leaves. All nodes of the returned subtree have
+// their hash and sum computed.
+func (b *subtreeBuilder) build(height int, entries []batchEntry) Node {
+	// The same leaf may be inserted at several keys, so we compute the
+	// hash of all leaves up front, as the hash is cached within the leaf
+	// itself and would otherwise be written by several goroutines.
+	for _, entry := range entries {
+		entry.leaf.NodeHash()
+	}
+
+	return b.buildNode(height, entries)
+}
+
+// buildNode recursively creates the subtree at the given height from the
+// passed entries, building its two halves in parallel if possible.
+func (b *subtreeBuilder) buildNode(height int, entries []batchEntry) Node {
+	switch {
+	case len(entries) == 0:
+		return EmptyTree[height]
+
+	// Once we've reached the bottom of the tree, only a single entry can
+	// remain, as all keys within a batch are unique.
+	case height == MaxTreeLevels:
+		return entries[0].leaf
+
+	// The root of the tree is always a branch, so we can only compact the
+	// leaf below it.
+	case b.compact && len(entries) == 1 && height > 0:
+		return NewCompactedLeafNode(
+			height, &entries[0].key, entries[0].leaf,
+		)
+	}
+
+	leftEntries, rightEntries := splitBatch(height, entries)
+
+	var left, right Node
+	if b.tryAcquireWorker(leftEntries, rightEntries) {
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer b.releaseWorker()
+
+			left = b.buildNode(height+1, leftEntries)
+		}()
+
+		right = b.buildNode(height+1, rightEntries)
+		wg.Wait()
+	} else {
+		left = b.buildNode(height+1, leftEntries)
+		right = b.buildNode(height+1, rightEntries)
+	}
+
+	// We force the computation of the hash here, so it's done by the
+	// goroutine that built the branch rather than by the caller.
+	branch := NewBranch(left, right)
+	branch.NodeHash()
+
+	return branch
+}
+
+// tryAcquireWorker attempts to reserve a worker for building one half of a
+// subtree in parallel. A worker is only reserved if both halves are large
+// enough and a worker is available.
+func (b *subtreeBuilder) tryAcquireWorker(left, right []batchEntry) bool {
+	if len(left) < minParallelBuildLeaves ||
+		len(right) < minParallelBuildLeaves {
+
+		return false
+	}
+
+	select {
+	case b.workers <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// releaseWorker releases a worker reserved by tryAcquireWorker.
+func (b *subtreeBuilder) releaseWorker() {
+	<-b.workers
+}
+
+// storeSubtree writes all non-default nodes of a subtree built by a
+// subtreeBuilder to the store. Children are always written before their
+// parent branch.
+func storeSubtree(tx TreeStoreUpdateTx, height int, node Node) error {
+	// Default nodes are never stored.
+	if node.NodeHash() == EmptyTree[height].NodeHash() {
+		return nil
+	}
+
+	switch node := node.(type) {
+	case *CompactedLeafNode:
+		return tx.InsertCompactedLeaf(node)
+
+	case *LeafNode:
+		return tx.InsertLeaf(node)
+
+	case *BranchNode:
+		if err := storeSubtree(tx, height+1, node.Left); err != nil {
+			return err
+		}
+		if err := storeSubtree(tx, height+1, node.Right); err != nil {
+			return err
+		}
+
+		return tx.InsertBranch(node)
+
+	default:
+		return fmt.Errorf("unexpected node type %T at height %d", node,
+			height)
+	}
+}
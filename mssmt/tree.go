@@ -1,10 +1,12 @@
 package mssmt
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"math/bits"
+	"sort"
 )
 
 const (
@@ -63,6 +65,8 @@ func init() {
 // proofs of invalid merkle sum commitments.
 type FullTree struct {
 	store TreeStore
+
+	opts treeOptions
 }
 
 var _ Tree = (*FullTree)(nil)
@@ -70,9 +74,10 @@ var _ Tree = (*FullTree)(nil)
 // NewFullTree initializes an empty MS-SMT backed by `store`. As a result,
 // `store` will only maintain non-empty relevant nodes, i.e., stale parents are
 // deleted and empty nodes are never stored.
-func NewFullTree(store TreeStore) *FullTree {
+func NewFullTree(store TreeStore, opts ...TreeOption) *FullTree {
 	return &FullTree{
 		store: store,
+		opts:  newTreeOptions(opts...),
 	}
 }
 
@@ -278,6 +283,239 @@ func (t *FullTree) Delete(ctx context.Context, key [hashSize]byte) (
 	return t, nil
 }
 
+// batchEntry is a single leaf update within a batch of updates. An empty leaf
+// marks the deletion of the leaf found at the key.
+type batchEntry struct {
+	key  [hashSize]byte
+	leaf *LeafNode
+}
+
+// newInsertBatch creates a batch inserting the given leaves. The batch is
+// sorted by key, so the order in which nodes are written is deterministic.
+func newInsertBatch(leaves map[[hashSize]byte]*LeafNode) []batchEntry {
+	entries := make([]batchEntry, 0, len(leaves))
+	for key, leaf := range leaves {
+		entries = append(entries, batchEntry{
+			key:  key,
+			leaf: leaf,
+		})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return bytes.Compare(entries[i].key[:], entries[j].key[:]) < 0
+	})
+
+	return entries
+}
+
+// newDeleteBatch creates a batch deleting the leaves at the given keys.
+func newDeleteBatch(keys [][hashSize]byte) []batchEntry {
+	leaves := make(map[[hashSize]byte]*LeafNode, len(keys))
+	for _, key := range keys {
+		leaves[key] = EmptyLeafNode
+	}
+
+	return newInsertBatch(leaves)
+}
+
+// splitBatch splits the batch into the entries that belong to the left and
+// the ones that belong to the right subtree of a branch at the given height.
+func splitBatch(height int, entries []batchEntry) ([]batchEntry,
+	[]batchEntry) {
+
+	var left, right []batchEntry
+	for _, entry := range entries {
+		if bitIndex(uint8(height), &entry.key) == 0 {
+			left = append(left, entry)
+		} else {
+			right = append(right, entry)
+		}
+	}
+
+	return left, right
+}
+
+// checkBatchSumOverflow checks if inserting all leaves of the batch into a
+// tree with the given root could overflow the root sum.
+func checkBatchSumOverflow(root Node, entries []batchEntry) error {
+	sum := root.NodeSum()
+	for _, entry := range entries {
+		sumLeaf := entry.leaf.NodeSum()
+		err := CheckSumOverflowUint64(sum, sumLeaf)
+		if err != nil {
+			return fmt.Errorf("batched leaf insert sum overflow, "+
+				"root: %d, leaf: %d; %w", sum, sumLeaf, err)
+		}
+
+		sum += sumLeaf
+	}
+
+	return nil
+}
+
+// batchedInsert applies the batch of updates to the subtree rooted at the
+// given node of the given height. The children of the node are updated first,
+// which means each affected branch is only recomputed and written once. The
+// new root of the subtree is returned.
+func (t *FullTree) batchedInsert(tx TreeStoreUpdateTx, height int, node Node,
+	entries []batchEntry) (Node, error) {
+
+	// If nothing below this node changes, then the node itself doesn't
+	// change either.
+	if len(entries) == 0 {
+		return node, nil
+	}
+
+	// Once we've reached the bottom of the tree, only a single entry can
+	// remain, as all keys within a batch are unique.
+	if height == MaxTreeLevels {
+		entry := entries[0]
+		if entry.leaf.NodeHash() == node.NodeHash() {
+			return node, nil
+		}
+
+		// If we've inserted an empty leaf, then the leaf node found at
+		// the given key is being deleted, otherwise it's being
+		// inserted.
+		if entry.leaf.IsEmpty() {
+			if err := tx.DeleteLeaf(entry.key); err != nil {
+				return nil, err
+			}
+
+			return EmptyTree[height], nil
+		}
+
+		if err := tx.InsertLeaf(entry.leaf); err != nil {
+			return nil, err
+		}
+
+		return entry.leaf, nil
+	}
+
+	// An empty subtree doesn't depend on any stored node, so it's simply
+	// replaced with a new subtree containing the leaves of the batch,
+	// which can be hashed in parallel. Deleting a leaf from an empty
+	// subtree is a no-op.
+	if node.NodeHash() == EmptyTree[height].NodeHash() {
+		leaves := make([]batchEntry, 0, len(entries))
+		for _, entry := range entries {
+			if !entry.leaf.IsEmpty() {
+				leaves = append(leaves, entry)
+			}
+		}
+
+		subtree := newSubtreeBuilder(
+			false, t.opts.buildConcurrency,
+		).build(height, leaves)
+		if err := storeSubtree(tx, height, subtree); err != nil {
+			return nil, err
+		}
+
+		return subtree, nil
+	}
+
+	// Otherwise, we'll recurse into the children of the branch.
+	left, right, err := tx.GetChildren(height, node.NodeHash())
+	if err != nil {
+		return nil, err
+	}
+
+	leftEntries, rightEntries := splitBatch(height, entries)
+	newLeft, err := t.batchedInsert(tx, height+1, left, leftEntries)
+	if err != nil {
+		return nil, err
+	}
+	newRight, err := t.batchedInsert(tx, height+1, right, rightEntries)
+	if err != nil {
+		return nil, err
+	}
+
+	if IsEqualNode(left, newLeft) && IsEqualNode(right, newRight) {
+		return node, nil
+	}
+
+	// Replace the old branch with the new one. Our store should never
+	// track empty branches.
+	if err := tx.DeleteBranch(node.NodeHash()); err != nil {
+		return nil, err
+	}
+
+	branch := NewBranch(newLeft, newRight)
+	if branch.NodeHash() == EmptyTree[height].NodeHash() {
+		return EmptyTree[height], nil
+	}
+
+	if err := tx.InsertBranch(branch); err != nil {
+		return nil, err
+	}
+
+	return branch, nil
+}
+
+// applyBatch applies the batch of updates to the tree and updates the root
+// once all of them are applied.
+func (t *FullTree) applyBatch(ctx context.Context,
+	entries []batchEntry) error {
+
+	return t.store.Update(ctx, func(tx TreeStoreUpdateTx) error {
+		currentRoot, err := tx.RootNode()
+		if err != nil {
+			return err
+		}
+
+		// First we'll check if the sum of the root and new leaves will
+		// overflow. If so, we'll return an error.
+		err = checkBatchSumOverflow(currentRoot, entries)
+		if err != nil {
+			return err
+		}
+
+		root, err := t.batchedInsert(tx, 0, currentRoot, entries)
+		if err != nil {
+			return err
+		}
+
+		return tx.UpdateRoot(root.(*BranchNode))
+	})
+}
+
+// InsertMany inserts multiple leaf nodes at their given keys within the
+// MS-SMT. All affected branches are only recomputed once.
+func (t *FullTree) InsertMany(ctx context.Context,
+	leaves map[[hashSize]byte]*LeafNode) (Tree, error) {
+
+	if err := t.applyBatch(ctx, newInsertBatch(leaves)); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// DeleteMany deletes the leaf nodes found at the given keys within the
+// MS-SMT. All affected branches are only recomputed once.
+func (t *FullTree) DeleteMany(ctx context.Context, keys [][hashSize]byte) (
+	Tree, error) {
+
+	if err := t.applyBatch(ctx, newDeleteBatch(keys)); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// Stats returns statistics about the nodes of the MS-SMT.
+func (t *FullTree) Stats(ctx context.Context) (*TreeStats, error) {
+	return treeStats(ctx, t.store)
+}
+
+// ForEachLeaf calls the given closure for each non-empty leaf of the MS-SMT
+// whose key is within the given range, in ascending order of their keys.
+func (t *FullTree) ForEachLeaf(ctx context.Context, keyRange *KeyRange,
+	cb LeafIterFunc) error {
+
+	return forEachLeaf(ctx, t.store, keyRange, cb)
+}
+
 // Get returns the leaf node found at the given key within the MS-SMT.
 func (t *FullTree) Get(ctx context.Context, key [hashSize]byte) (
 	*LeafNode, error) {
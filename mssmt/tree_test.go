@@ -6,10 +6,13 @@ package mssmt_test
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"math"
 	"math/rand"
 	"path/filepath"
+	"runtime"
+	"sort"
 	"testing"
 
 	"github.com/lightninglabs/taproot-assets/mssmt"
@@ -465,6 +468,546 @@ func testDeletion(t *testing.T, leaves []treeLeaf, tree mssmt.Tree) {
 	require.True(t, mssmt.IsEqualNode(mssmt.EmptyTree[0], treeRoot))
 }
 
+// TestBatchedUpdates tests that inserting and deleting leaves in batches
+// results in the same tree as inserting and deleting them one by one.
+func TestBatchedUpdates(t *testing.T) {
+	t.Parallel()
+
+	leaves := randTree(100)
+
+	for storeName, makeStore := range genTestStores(t) {
+		storeName := storeName
+		makeStore := makeStore
+
+		t.Run(storeName, func(t *testing.T) {
+			t.Run("full SMT", func(t *testing.T) {
+				t.Parallel()
+
+				testBatchedUpdates(
+					t, leaves, makeFullTree, makeStore,
+				)
+			})
+
+			t.Run("smol SMT", func(t *testing.T) {
+				t.Parallel()
+
+				testBatchedUpdates(
+					t, leaves, makeSmolTree, makeStore,
+				)
+			})
+		})
+	}
+}
+
+func testBatchedUpdates(t *testing.T, leaves []treeLeaf,
+	makeTree func(mssmt.TreeStore) mssmt.Tree,
+	makeStore makeTestTreeStoreFunc) {
+
+	ctx := context.TODO()
+
+	store, err := makeStore()
+	require.NoError(t, err)
+	tree := makeTree(store)
+
+	// The reference tree is only updated one leaf at a time.
+	refTree := makeTree(mssmt.NewDefaultStore())
+
+	assertEqualRoots := func() {
+		t.Helper()
+
+		root, err := tree.Root(ctx)
+		require.NoError(t, err)
+		refRoot, err := refTree.Root(ctx)
+		require.NoError(t, err)
+		require.True(t, mssmt.IsEqualNode(refRoot, root))
+	}
+
+	// We'll start by inserting the first half of the leaves in a single
+	// batch into an empty tree.
+	half := len(leaves) / 2
+	_, err = tree.InsertMany(ctx, batchLeaves(leaves[:half]))
+	require.NoError(t, err)
+	for _, item := range leaves[:half] {
+		_, err := refTree.Insert(ctx, item.key, item.leaf)
+		require.NoError(t, err)
+	}
+	assertEqualRoots()
+
+	// Next, we'll insert the second half together with new leaves for
+	// some of the existing keys into the non-empty tree.
+	updated := make([]treeLeaf, 0, len(leaves)-half+10)
+	updated = append(updated, leaves[half:]...)
+	for _, item := range leaves[:10] {
+		updated = append(updated, treeLeaf{
+			key:  item.key,
+			leaf: randLeaf(),
+		})
+	}
+	_, err = tree.InsertMany(ctx, batchLeaves(updated))
+	require.NoError(t, err)
+	for _, item := range updated {
+		_, err := refTree.Insert(ctx, item.key, item.leaf)
+		require.NoError(t, err)
+	}
+	assertEqualRoots()
+
+	// All leaves must be retrievable with valid proofs.
+	current := append(updated, leaves[10:half]...)
+	testProofs(t, current, tree)
+
+	// Inserting an empty batch or deleting keys that aren't in the tree
+	// doesn't change the tree.
+	_, err = tree.InsertMany(ctx, nil)
+	require.NoError(t, err)
+	_, err = tree.DeleteMany(ctx, [][hashSize]byte{randKey(), randKey()})
+	require.NoError(t, err)
+	assertEqualRoots()
+
+	// We'll now delete a third of the leaves in a single batch.
+	var deletedKeys [][hashSize]byte
+	for _, item := range current[:len(current)/3] {
+		deletedKeys = append(deletedKeys, item.key)
+	}
+	_, err = tree.DeleteMany(ctx, deletedKeys)
+	require.NoError(t, err)
+	for _, key := range deletedKeys {
+		_, err := refTree.Delete(ctx, key)
+		require.NoError(t, err)
+
+		leaf, err := tree.Get(ctx, key)
+		require.NoError(t, err)
+		require.True(t, leaf.IsEmpty())
+	}
+	assertEqualRoots()
+	testProofs(t, current[len(current)/3:], tree)
+
+	// Finally, deleting all remaining leaves results in an empty tree.
+	var remainingKeys [][hashSize]byte
+	for _, item := range current[len(current)/3:] {
+		remainingKeys = append(remainingKeys, item.key)
+	}
+	_, err = tree.DeleteMany(ctx, remainingKeys)
+	require.NoError(t, err)
+
+	root, err := tree.Root(ctx)
+	require.NoError(t, err)
+	require.True(t, mssmt.IsEqualNode(mssmt.EmptyTree[0], root))
+
+	// A batch that would overflow the root sum must be rejected as a
+	// whole.
+	_, err = tree.InsertMany(ctx, map[[hashSize]byte]*mssmt.LeafNode{
+		randKey(): mssmt.NewLeafNode([]byte{1}, math.MaxUint64/2+1),
+		randKey(): mssmt.NewLeafNode([]byte{2}, math.MaxUint64/2+1),
+	})
+	require.ErrorIs(t, err, mssmt.ErrIntegerOverflow)
+
+	root, err = tree.Root(ctx)
+	require.NoError(t, err)
+	require.True(t, mssmt.IsEqualNode(mssmt.EmptyTree[0], root))
+}
+
+// TestCompactedTreeCopy tests that a copy of a tree backed by the default store
+// can be modified independently of the original tree, even across many
+// generations of copies.
+func TestCompactedTreeCopy(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	// expectedRoot returns the root of a fresh tree with the given leaves.
+	expectedRoot := func(leaves []treeLeaf) *mssmt.BranchNode {
+		tree := mssmt.NewCompactedTree(mssmt.NewDefaultStore())
+		_, err := tree.InsertMany(ctx, batchLeaves(leaves))
+		require.NoError(t, err)
+
+		root, err := tree.Root(ctx)
+		require.NoError(t, err)
+
+		return root
+	}
+	requireRoot := func(tree mssmt.Tree, leaves []treeLeaf) {
+		root, err := tree.Root(ctx)
+		require.NoError(t, err)
+		require.True(t, mssmt.IsEqualNode(expectedRoot(leaves), root))
+	}
+
+	leaves := randTree(100)
+	store := mssmt.NewDefaultStore()
+	tree := mssmt.NewCompactedTree(store)
+	_, err := tree.InsertMany(ctx, batchLeaves(leaves))
+	require.NoError(t, err)
+
+	treeCopy, err := tree.Copy()
+	require.NoError(t, err)
+
+	// We delete half of the leaves from the copy and add new leaves to
+	// the original tree.
+	keys := make([][32]byte, 0, 50)
+	for _, item := range leaves[:50] {
+		keys = append(keys, item.key)
+	}
+	_, err = treeCopy.DeleteMany(ctx, keys)
+	require.NoError(t, err)
+
+	newLeaves := randTree(20)
+	_, err = tree.InsertMany(ctx, batchLeaves(newLeaves))
+	require.NoError(t, err)
+
+	requireRoot(tree, append(newLeaves, leaves...))
+	requireRoot(treeCopy, leaves[50:])
+	require.Equal(t, 120, store.NumCompactedLeaves())
+
+	// The deleted leaves are gone from the copy, while the original tree
+	// still has them.
+	for _, item := range leaves[:50] {
+		leaf, err := treeCopy.Get(ctx, item.key)
+		require.NoError(t, err)
+		require.True(t, leaf.IsEmpty())
+
+		leaf, err = tree.Get(ctx, item.key)
+		require.NoError(t, err)
+		require.True(t, mssmt.IsEqualNode(item.leaf, leaf))
+	}
+
+	// Copying a copy many times over still results in independent trees.
+	allLeaves := leaves[50:]
+	for i := 0; i < 40; i++ {
+		nextCopy, err := treeCopy.Copy()
+		require.NoError(t, err)
+
+		item := randTree(1)[0]
+		_, err = treeCopy.Insert(ctx, item.key, item.leaf)
+		require.NoError(t, err)
+		_, err = nextCopy.Delete(ctx, allLeaves[0].key)
+		require.NoError(t, err)
+
+		requireRoot(treeCopy, append([]treeLeaf{item}, allLeaves...))
+
+		allLeaves = allLeaves[1:]
+		treeCopy = nextCopy
+	}
+	requireRoot(treeCopy, allLeaves)
+
+	// Only trees backed by the default store can be copied.
+	sqlStore, err := genTestStores(t)["sqlite3"]()
+	require.NoError(t, err)
+	_, err = mssmt.NewCompactedTree(sqlStore).Copy()
+	require.Error(t, err)
+}
+
+// TestParallelTreeBuild tests that building a large tree from scratch, which
+// hashes independent subtrees in parallel, results in the same tree as
+// inserting the leaves one by one.
+func TestParallelTreeBuild(t *testing.T) {
+	t.Parallel()
+
+	const numLeaves = 1_000
+
+	// We'll insert the same leaf at a number of keys, to make sure a leaf
+	// can be shared between subtrees that are built in parallel.
+	sharedLeaf := randLeaf()
+	leaves := make(map[[hashSize]byte]*mssmt.LeafNode, numLeaves)
+	for i := 0; i < numLeaves; i++ {
+		leaf := sharedLeaf
+		if i%2 == 0 {
+			leaf = randLeaf()
+		}
+
+		leaves[randKey()] = leaf
+	}
+
+	testCases := []struct {
+		name     string
+		makeTree func(mssmt.TreeStore, ...mssmt.TreeOption) mssmt.Tree
+	}{{
+		name: "full SMT",
+		makeTree: func(store mssmt.TreeStore,
+			opts ...mssmt.TreeOption) mssmt.Tree {
+
+			return mssmt.NewFullTree(store, opts...)
+		},
+	}, {
+		name: "smol SMT",
+		makeTree: func(store mssmt.TreeStore,
+			opts ...mssmt.TreeOption) mssmt.Tree {
+
+			return mssmt.NewCompactedTree(store, opts...)
+		},
+	}}
+
+	// The tree must be the same no matter how many goroutines build it,
+	// including none other than the calling one.
+	concurrencies := []int{0, 1, 4, runtime.GOMAXPROCS(0)}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+
+			refTree := tc.makeTree(mssmt.NewDefaultStore())
+			for key, leaf := range leaves {
+				_, err := refTree.Insert(ctx, key, leaf)
+				require.NoError(t, err)
+			}
+			refRoot, err := refTree.Root(ctx)
+			require.NoError(t, err)
+
+			for _, concurrency := range concurrencies {
+				tree := tc.makeTree(
+					mssmt.NewDefaultStore(),
+					mssmt.WithBuildConcurrency(concurrency),
+				)
+				_, err := tree.InsertMany(ctx, leaves)
+				require.NoError(t, err)
+
+				root, err := tree.Root(ctx)
+				require.NoError(t, err)
+				require.True(
+					t, mssmt.IsEqualNode(refRoot, root),
+				)
+
+				for key, leaf := range leaves {
+					dbLeaf, err := tree.Get(ctx, key)
+					require.NoError(t, err)
+					require.Equal(t, leaf, dbLeaf)
+				}
+			}
+		})
+	}
+}
+
+// TestTreeStats tests that the statistics of a tree reflect the leaves that
+// were inserted into it.
+func TestTreeStats(t *testing.T) {
+	t.Parallel()
+
+	leaves := randTree(100)
+
+	for storeName, makeStore := range genTestStores(t) {
+		storeName := storeName
+		makeStore := makeStore
+
+		t.Run(storeName, func(t *testing.T) {
+			t.Run("full SMT", func(t *testing.T) {
+				t.Parallel()
+
+				testTreeStats(
+					t, leaves, makeFullTree, makeStore,
+					false,
+				)
+			})
+
+			t.Run("smol SMT", func(t *testing.T) {
+				t.Parallel()
+
+				testTreeStats(
+					t, leaves, makeSmolTree, makeStore,
+					true,
+				)
+			})
+		})
+	}
+}
+
+func testTreeStats(t *testing.T, leaves []treeLeaf,
+	makeTree func(mssmt.TreeStore) mssmt.Tree,
+	makeStore makeTestTreeStoreFunc, compacted bool) {
+
+	ctx := context.Background()
+
+	store, err := makeStore()
+	require.NoError(t, err)
+	tree := makeTree(store)
+
+	// An empty tree doesn't have any non-default nodes.
+	stats, err := tree.Stats(ctx)
+	require.NoError(t, err)
+	require.Zero(t, stats.NumNodes())
+	require.Zero(t, stats.TotalSum)
+	require.Zero(t, stats.MaxLeafDepth())
+	require.Empty(t, stats.LeafDepths)
+
+	var totalSum uint64
+	for _, item := range leaves {
+		_, err := tree.Insert(ctx, item.key, item.leaf)
+		require.NoError(t, err)
+
+		totalSum += item.leaf.NodeSum()
+	}
+
+	stats, err = tree.Stats(ctx)
+	require.NoError(t, err)
+	require.EqualValues(t, len(leaves), stats.NumLeaves)
+	require.Equal(t, totalSum, stats.TotalSum)
+	require.Greater(t, stats.NumBranches, uint64(0))
+	require.Equal(
+		t, stats.NumLeaves+stats.NumBranches, stats.NumNodes(),
+	)
+
+	var numDepthLeaves uint64
+	for _, numLeaves := range stats.LeafDepths {
+		numDepthLeaves += numLeaves
+	}
+	require.Equal(t, stats.NumLeaves, numDepthLeaves)
+
+	if compacted {
+		require.Equal(t, stats.NumLeaves, stats.NumCompactedLeaves)
+		require.Less(t, stats.MaxLeafDepth(), mssmt.MaxTreeLevels)
+	} else {
+		require.Zero(t, stats.NumCompactedLeaves)
+		require.Equal(t, mssmt.MaxTreeLevels, stats.MaxLeafDepth())
+		require.EqualValues(
+			t, len(leaves), stats.LeafDepths[mssmt.MaxTreeLevels],
+		)
+	}
+
+	require.Contains(
+		t, stats.String(), fmt.Sprintf("leaves=%d", len(leaves)),
+	)
+
+	// Deleting a leaf is reflected in the statistics as well.
+	_, err = tree.Delete(ctx, leaves[0].key)
+	require.NoError(t, err)
+
+	stats, err = tree.Stats(ctx)
+	require.NoError(t, err)
+	require.EqualValues(t, len(leaves)-1, stats.NumLeaves)
+	require.Equal(t, totalSum-leaves[0].leaf.NodeSum(), stats.TotalSum)
+}
+
+// TestForEachLeaf tests that iterating over the leaves of a tree returns all
+// leaves within the requested range in ascending order of their keys.
+func TestForEachLeaf(t *testing.T) {
+	t.Parallel()
+
+	// Keys generated from a range share long prefixes, which makes sure
+	// leaves at all depths of the tree are covered.
+	leaves := append(randTree(100), genTreeFromRange(100)...)
+
+	for storeName, makeStore := range genTestStores(t) {
+		storeName := storeName
+		makeStore := makeStore
+
+		t.Run(storeName, func(t *testing.T) {
+			t.Run("full SMT", func(t *testing.T) {
+				t.Parallel()
+
+				testForEachLeaf(
+					t, leaves, makeFullTree, makeStore,
+				)
+			})
+
+			t.Run("smol SMT", func(t *testing.T) {
+				t.Parallel()
+
+				testForEachLeaf(
+					t, leaves, makeSmolTree, makeStore,
+				)
+			})
+		})
+	}
+}
+
+func testForEachLeaf(t *testing.T, leaves []treeLeaf,
+	makeTree func(mssmt.TreeStore) mssmt.Tree,
+	makeStore makeTestTreeStoreFunc) {
+
+	ctx := context.Background()
+
+	store, err := makeStore()
+	require.NoError(t, err)
+	tree := makeTree(store)
+
+	collectLeaves := func(keyRange *mssmt.KeyRange) []treeLeaf {
+		t.Helper()
+
+		var collected []treeLeaf
+		err := tree.ForEachLeaf(ctx, keyRange,
+			func(key [hashSize]byte, leaf *mssmt.LeafNode) error {
+				collected = append(collected, treeLeaf{
+					key:  key,
+					leaf: leaf,
+				})
+				return nil
+			},
+		)
+		require.NoError(t, err)
+
+		return collected
+	}
+
+	// An empty tree doesn't have any leaves to iterate over.
+	require.Empty(t, collectLeaves(nil))
+
+	_, err = tree.InsertMany(ctx, batchLeaves(leaves))
+	require.NoError(t, err)
+
+	sortedLeaves := make([]treeLeaf, len(leaves))
+	copy(sortedLeaves, leaves)
+	sort.Slice(sortedLeaves, func(i, j int) bool {
+		return bytes.Compare(
+			sortedLeaves[i].key[:], sortedLeaves[j].key[:],
+		) < 0
+	})
+
+	assertLeaves := func(expected, actual []treeLeaf) {
+		t.Helper()
+
+		require.Len(t, actual, len(expected))
+		for i := range expected {
+			require.Equal(t, expected[i].key, actual[i].key)
+			require.True(t, mssmt.IsEqualNode(
+				expected[i].leaf, actual[i].leaf,
+			))
+		}
+	}
+
+	// Without a range, all leaves are returned in order.
+	assertLeaves(sortedLeaves, collectLeaves(nil))
+
+	// With a range, only the leaves within it are returned, including
+	// the leaves at its bounds.
+	rangeLeaves := sortedLeaves[120:180]
+	assertLeaves(rangeLeaves, collectLeaves(mssmt.NewKeyRange(
+		rangeLeaves[0].key, rangeLeaves[len(rangeLeaves)-1].key,
+	)))
+
+	// A prefix range returns all leaves whose key starts with the prefix.
+	// All leaves generated from a range share the same 31 byte prefix
+	// and are the smallest keys of the tree.
+	assertLeaves(
+		sortedLeaves[:100],
+		collectLeaves(mssmt.NewPrefixKeyRange(make([]byte, 31))),
+	)
+
+	// Returning ErrStopIteration stops the iteration without an error,
+	// while any other error is returned as is.
+	var numLeaves int
+	err = tree.ForEachLeaf(ctx, nil,
+		func([hashSize]byte, *mssmt.LeafNode) error {
+			numLeaves++
+			if numLeaves == 10 {
+				return mssmt.ErrStopIteration
+			}
+
+			return nil
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, 10, numLeaves)
+
+	errTest := errors.New("test error")
+	err = tree.ForEachLeaf(ctx, nil,
+		func([hashSize]byte, *mssmt.LeafNode) error {
+			return errTest
+		},
+	)
+	require.ErrorIs(t, err, errTest)
+}
+
 func assertEqualProofAfterCompression(t *testing.T, proof *mssmt.Proof) {
 	t.Helper()
 
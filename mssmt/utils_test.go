@@ -45,6 +45,17 @@ func randTree(numLeaves int) []treeLeaf {
 	return leaves
 }
 
+// batchLeaves returns the given leaves as a batch that can be inserted with
+// InsertMany.
+func batchLeaves(leaves []treeLeaf) map[[hashSize]byte]*mssmt.LeafNode {
+	batch := make(map[[hashSize]byte]*mssmt.LeafNode, len(leaves))
+	for _, item := range leaves {
+		batch[item.key] = item.leaf
+	}
+
+	return batch
+}
+
 func genTreeFromRange(numLeaves int) []treeLeaf {
 	leaves := make([]treeLeaf, numLeaves)
 	for i := 0; i < numLeaves; i++ {
@@ -0,0 +1,283 @@
+package mssmt
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+)
+
+// proofVerifier incrementally computes the root of a merkle proof from its
+// siblings, starting at the leaf. Only the hash and sum of the current node
+// are kept in memory, so the siblings never need to be materialized as nodes.
+type proofVerifier struct {
+	key *[hashSize]byte
+
+	// numSiblings is the number of siblings that were added so far.
+	numSiblings int
+
+	hash NodeHash
+	sum  uint64
+}
+
+// newProofVerifier creates a new proof verifier for the given leaf found at
+// the given key. An empty leaf is used to verify a proof of non-inclusion.
+func newProofVerifier(key *[hashSize]byte, leaf *LeafNode) *proofVerifier {
+	return &proofVerifier{
+		key:  key,
+		hash: leaf.NodeHash(),
+		sum:  leaf.NodeSum(),
+	}
+}
+
+// addSibling hashes the current node with its next sibling to arrive at their
+// parent.
+func (v *proofVerifier) addSibling(siblingHash NodeHash,
+	siblingSum uint64) error {
+
+	if v.numSiblings == MaxTreeLevels {
+		return fmt.Errorf("%w: too many nodes",
+			ErrInvalidCompressedProof)
+	}
+
+	// A valid tree can never overflow its sum, so we can reject such a
+	// proof right away.
+	if err := CheckSumOverflowUint64(v.sum, siblingSum); err != nil {
+		return fmt.Errorf("%w: sibling sum overflow",
+			ErrInvalidCompressedProof)
+	}
+
+	// The proof siblings start at the leaf, while the bits of the key
+	// start at the root.
+	left, right := v.hash, siblingHash
+	if bitIndex(uint8(lastBitIndex-v.numSiblings), v.key) == 1 {
+		left, right = siblingHash, v.hash
+	}
+
+	var preimage [hashSize*2 + 8]byte
+	copy(preimage[:hashSize], left[:])
+	copy(preimage[hashSize:], right[:])
+	v.sum += siblingSum
+	byteOrder.PutUint64(preimage[hashSize*2:], v.sum)
+
+	v.hash = sha256.Sum256(preimage[:])
+	v.numSiblings++
+
+	return nil
+}
+
+// addDefaultSibling hashes the current node with its next sibling, which is
+// part of the empty tree.
+func (v *proofVerifier) addDefaultSibling() error {
+	if v.numSiblings == MaxTreeLevels {
+		return fmt.Errorf("%w: too many nodes",
+			ErrInvalidCompressedProof)
+	}
+
+	sibling := EmptyTree[MaxTreeLevels-v.numSiblings]
+	return v.addSibling(sibling.NodeHash(), sibling.NodeSum())
+}
+
+// matchesRoot returns true if all siblings of the proof were added and the
+// resulting root matches the given one.
+func (v *proofVerifier) matchesRoot(root Node) (bool, error) {
+	if v.numSiblings != MaxTreeLevels {
+		return false, fmt.Errorf("%w: expected %d nodes, got %d",
+			ErrInvalidCompressedProof, MaxTreeLevels, v.numSiblings)
+	}
+
+	return v.hash == root.NodeHash() && v.sum == root.NodeSum(), nil
+}
+
+// VerifyCompressedProof determines whether a compressed merkle proof for the
+// leaf found at the given key is valid, without decompressing the proof.
+func VerifyCompressedProof(key [hashSize]byte, leaf *LeafNode,
+	proof *CompressedProof, root Node) (bool, error) {
+
+	if len(proof.Bits) != MaxTreeLevels {
+		return false, fmt.Errorf("%w: expected %d bits, got %d",
+			ErrInvalidCompressedProof, MaxTreeLevels,
+			len(proof.Bits))
+	}
+
+	verifier := newProofVerifier(&key, leaf)
+
+	var nextNodeIdx int
+	for _, isDefault := range proof.Bits {
+		if isDefault {
+			if err := verifier.addDefaultSibling(); err != nil {
+				return false, err
+			}
+
+			continue
+		}
+
+		if nextNodeIdx == len(proof.Nodes) {
+			return false, fmt.Errorf("%w: missing nodes",
+				ErrInvalidCompressedProof)
+		}
+
+		node := proof.Nodes[nextNodeIdx]
+		err := verifier.addSibling(node.NodeHash(), node.NodeSum())
+		if err != nil {
+			return false, err
+		}
+		nextNodeIdx++
+	}
+
+	if nextNodeIdx != len(proof.Nodes) {
+		return false, fmt.Errorf("%w: num_nodes=%v, "+
+			"num_expected=%v", ErrInvalidCompressedProof,
+			len(proof.Nodes), nextNodeIdx)
+	}
+
+	return verifier.matchesRoot(root)
+}
+
+// VerifyEncodedProof determines whether the encoded compressed merkle proof
+// read from the given Reader is valid for the leaf found at the given key.
+// Both the CompressedProofV1 and the CompressedProofV2 wire formats are
+// supported. Contrary to decoding and decompressing the proof first, the
+// memory used is bounded by a constant independent of the input, and no more
+// bytes than the proof itself are read from the Reader.
+//
+// A false value is returned if the proof is well formed but doesn't result in
+// the given root, while an error is returned for a malformed proof.
+func VerifyEncodedProof(r io.Reader, key [hashSize]byte, leaf *LeafNode,
+	root Node) (bool, error) {
+
+	var versionByte [1]byte
+	if _, err := io.ReadFull(r, versionByte[:]); err != nil {
+		return false, err
+	}
+
+	verifier := newProofVerifier(&key, leaf)
+
+	var err error
+	switch {
+	// The first byte of a proof in the original wire format is the most
+	// significant byte of the number of nodes, which can only be 0 or 1.
+	case versionByte[0] <= 1:
+		err = verifyEncodedProofV1(r, versionByte[0], verifier)
+
+	case versionByte[0] == byte(CompressedProofV2):
+		err = verifyEncodedProofV2(r, verifier)
+
+	default:
+		err = fmt.Errorf("%w: %d", ErrUnknownProofVersion,
+			versionByte[0])
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return verifier.matchesRoot(root)
+}
+
+// verifyEncodedProofV1 adds all siblings of a proof in the CompressedProofV1
+// wire format, of which the first byte was already read, to the verifier. As
+// the bit vector follows the nodes in this format, the nodes are buffered in
+// a fixed size array.
+func verifyEncodedProofV1(r io.Reader, firstByte byte,
+	verifier *proofVerifier) error {
+
+	var secondByte [1]byte
+	if _, err := io.ReadFull(r, secondByte[:]); err != nil {
+		return err
+	}
+	numNodes := int(firstByte)<<8 | int(secondByte[0])
+	if numNodes > MaxTreeLevels {
+		return fmt.Errorf("%w: too many nodes %d",
+			ErrInvalidCompressedProof, numNodes)
+	}
+
+	var nodes [MaxTreeLevels]ComputedNode
+	for i := 0; i < numNodes; i++ {
+		var hash NodeHash
+		if _, err := io.ReadFull(r, hash[:]); err != nil {
+			return err
+		}
+
+		var sumBytes [8]byte
+		if _, err := io.ReadFull(r, sumBytes[:]); err != nil {
+			return err
+		}
+
+		nodes[i] = NewComputedNode(hash, byteOrder.Uint64(sumBytes[:]))
+	}
+
+	var bitsBytes [MaxTreeLevels / 8]byte
+	if _, err := io.ReadFull(r, bitsBytes[:]); err != nil {
+		return err
+	}
+
+	var nextNodeIdx int
+	for i := 0; i < MaxTreeLevels; i++ {
+		if isBitSet(bitsBytes[:], i) {
+			if err := verifier.addDefaultSibling(); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if nextNodeIdx == numNodes {
+			return fmt.Errorf("%w: missing nodes",
+				ErrInvalidCompressedProof)
+		}
+
+		node := nodes[nextNodeIdx]
+		if err := verifier.addSibling(node.hash, node.sum); err != nil {
+			return err
+		}
+		nextNodeIdx++
+	}
+
+	if nextNodeIdx != numNodes {
+		return fmt.Errorf("%w: num_nodes=%v, num_expected=%v",
+			ErrInvalidCompressedProof, numNodes, nextNodeIdx)
+	}
+
+	return nil
+}
+
+// verifyEncodedProofV2 adds all siblings of a proof in the CompressedProofV2
+// wire format, of which the version byte was already read, to the verifier.
+// As the bit vector precedes the nodes in this format, each node is added as
+// soon as it is read.
+func verifyEncodedProofV2(r io.Reader, verifier *proofVerifier) error {
+	var bitsBytes [MaxTreeLevels / 8]byte
+	if _, err := io.ReadFull(r, bitsBytes[:]); err != nil {
+		return err
+	}
+
+	for i := 0; i < MaxTreeLevels; i++ {
+		if isBitSet(bitsBytes[:], i) {
+			if err := verifier.addDefaultSibling(); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		var hash NodeHash
+		if _, err := io.ReadFull(r, hash[:]); err != nil {
+			return err
+		}
+		sum, err := readUvarint(r)
+		if err != nil {
+			return err
+		}
+
+		if err := verifier.addSibling(hash, sum); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isBitSet returns true if the bit at the given index of a bit vector packed
+// with PackBits is set.
+func isBitSet(bytes []byte, idx int) bool {
+	return (bytes[idx/8]>>(idx%8))&1 == 1
+}
@@ -0,0 +1,212 @@
+package mssmt_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"math"
+	"testing"
+
+	"github.com/lightninglabs/taproot-assets/mssmt"
+	"github.com/stretchr/testify/require"
+)
+
+// assertProofVerifies asserts that the given proof for the leaf found at the
+// given key results in the expected validity when verified in its compressed
+// form and from both of its wire formats.
+func assertProofVerifies(t *testing.T, key [hashSize]byte,
+	leaf *mssmt.LeafNode, proof *mssmt.Proof, root mssmt.Node,
+	expectValid bool) {
+
+	t.Helper()
+
+	require.Equal(
+		t, expectValid, mssmt.VerifyMerkleProof(key, leaf, proof, root),
+	)
+
+	compressed := proof.Compress()
+	valid, err := mssmt.VerifyCompressedProof(key, leaf, compressed, root)
+	require.NoError(t, err)
+	require.Equal(t, expectValid, valid)
+
+	versions := []mssmt.CompressedProofVersion{
+		mssmt.CompressedProofV1, mssmt.CompressedProofV2,
+	}
+	for _, version := range versions {
+		var buf bytes.Buffer
+		require.NoError(t, compressed.EncodeVersion(&buf, version))
+
+		// We append a trailing byte to make sure the verifier doesn't
+		// read beyond the end of the proof.
+		buf.WriteByte(0xff)
+
+		valid, err := mssmt.VerifyEncodedProof(&buf, key, leaf, root)
+		require.NoError(t, err)
+		require.Equal(t, expectValid, valid)
+		require.Equal(t, 1, buf.Len())
+	}
+}
+
+// TestVerifyEncodedProof tests that inclusion and non-inclusion proofs can be
+// verified directly from their compressed form and wire encoding.
+func TestVerifyEncodedProof(t *testing.T) {
+	t.Parallel()
+
+	leaves := randTree(100)
+	tree := mssmt.NewCompactedTree(mssmt.NewDefaultStore())
+	ctx := context.TODO()
+	for _, item := range leaves {
+		_, err := tree.Insert(ctx, item.key, item.leaf)
+		require.NoError(t, err)
+	}
+
+	root, err := tree.Root(ctx)
+	require.NoError(t, err)
+
+	for _, item := range leaves {
+		proof, err := tree.MerkleProof(ctx, item.key)
+		require.NoError(t, err)
+
+		assertProofVerifies(t, item.key, item.leaf, proof, root, true)
+
+		// The proof must not be valid for a different leaf, key or
+		// root.
+		assertProofVerifies(
+			t, item.key, randLeaf(), proof, root, false,
+		)
+		assertProofVerifies(t, randKey(), item.leaf, proof, root, false)
+		assertProofVerifies(
+			t, item.key, item.leaf, proof,
+			mssmt.NewComputedNode(root.NodeHash(), 1), false,
+		)
+	}
+
+	// A key that isn't in the tree results in a valid proof of
+	// non-inclusion.
+	emptyKey := randKey()
+	proof, err := tree.MerkleProof(ctx, emptyKey)
+	require.NoError(t, err)
+	assertProofVerifies(
+		t, emptyKey, mssmt.EmptyLeafNode, proof, root, true,
+	)
+	assertProofVerifies(t, emptyKey, randLeaf(), proof, root, false)
+}
+
+// TestVerifyEncodedProofInvalid tests that malformed proofs are rejected by
+// the verifier.
+func TestVerifyEncodedProofInvalid(t *testing.T) {
+	t.Parallel()
+
+	key := randKey()
+	leaf := randLeaf()
+	root := mssmt.EmptyTree[0]
+
+	// An all-default proof of an empty tree, which consists of 256 set
+	// bits.
+	allDefaultV1 := append([]byte{0, 0}, bytes.Repeat([]byte{0xff}, 32)...)
+	allDefaultV2 := append(
+		[]byte{byte(mssmt.CompressedProofV2)},
+		bytes.Repeat([]byte{0xff}, 32)...,
+	)
+
+	// A v1 proof with a single explicit node, but no unset bit.
+	extraNodeV1 := []byte{0, 1}
+	extraNodeV1 = append(extraNodeV1, make([]byte, 40)...)
+	extraNodeV1 = append(extraNodeV1, bytes.Repeat([]byte{0xff}, 32)...)
+
+	// A v1 proof without any explicit nodes, but a single unset bit.
+	missingNodeV1 := []byte{0, 0, 0xfe}
+	missingNodeV1 = append(
+		missingNodeV1, bytes.Repeat([]byte{0xff}, 31)...,
+	)
+
+	// A v2 proof with two nodes of which the sums overflow.
+	overflowV2 := []byte{byte(mssmt.CompressedProofV2), 0xfc}
+	overflowV2 = append(overflowV2, bytes.Repeat([]byte{0xff}, 31)...)
+	for i := 0; i < 2; i++ {
+		var buf [10]byte
+		n := binary.PutUvarint(buf[:], math.MaxUint64)
+		overflowV2 = append(overflowV2, make([]byte, 32)...)
+		overflowV2 = append(overflowV2, buf[:n]...)
+	}
+
+	testCases := []struct {
+		name    string
+		encoded []byte
+		err     error
+	}{{
+		name:    "empty",
+		encoded: nil,
+		err:     io.EOF,
+	}, {
+		name:    "unknown version",
+		encoded: append([]byte{3}, allDefaultV2[1:]...),
+		err:     mssmt.ErrUnknownProofVersion,
+	}, {
+		name:    "too many v1 nodes",
+		encoded: []byte{1, 1},
+		err:     mssmt.ErrInvalidCompressedProof,
+	}, {
+		name:    "truncated v1 bits",
+		encoded: allDefaultV1[:10],
+		err:     io.ErrUnexpectedEOF,
+	}, {
+		name:    "extra v1 node",
+		encoded: extraNodeV1,
+		err:     mssmt.ErrInvalidCompressedProof,
+	}, {
+		name:    "missing v1 node",
+		encoded: missingNodeV1,
+		err:     mssmt.ErrInvalidCompressedProof,
+	}, {
+		name:    "truncated v2 bits",
+		encoded: allDefaultV2[:10],
+		err:     io.ErrUnexpectedEOF,
+	}, {
+		name:    "truncated v2 nodes",
+		encoded: overflowV2[:40],
+		err:     io.ErrUnexpectedEOF,
+	}, {
+		name:    "v2 sum overflow",
+		encoded: overflowV2,
+		err:     mssmt.ErrInvalidCompressedProof,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := mssmt.VerifyEncodedProof(
+				bytes.NewReader(tc.encoded), key, leaf, root,
+			)
+			require.ErrorIs(t, err, tc.err)
+		})
+	}
+
+	// The all-default proofs are well formed, but aren't valid for a
+	// non-empty leaf.
+	for _, encoded := range [][]byte{allDefaultV1, allDefaultV2} {
+		valid, err := mssmt.VerifyEncodedProof(
+			bytes.NewReader(encoded), key, leaf, root,
+		)
+		require.NoError(t, err)
+		require.False(t, valid)
+
+		valid, err = mssmt.VerifyEncodedProof(
+			bytes.NewReader(encoded), key, mssmt.EmptyLeafNode,
+			root,
+		)
+		require.NoError(t, err)
+		require.True(t, valid)
+	}
+
+	// A compressed proof with a bit vector of the wrong length is
+	// rejected as well.
+	_, err := mssmt.VerifyCompressedProof(
+		key, leaf, &mssmt.CompressedProof{Bits: []bool{true}}, root,
+	)
+	require.ErrorIs(t, err, mssmt.ErrInvalidCompressedProof)
+}
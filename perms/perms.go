@@ -1,11 +1,19 @@
 package perms
 
-import "gopkg.in/macaroon-bakery.v2/bakery"
+import (
+	"strings"
+
+	"gopkg.in/macaroon-bakery.v2/bakery"
+)
 
 var (
 	// RequiredPermissions is a map of all tapd RPC methods and their
 	// required macaroon permissions to access tapd.
 	//
+	// The universe federation methods that change what the local universe
+	// syncs with additionally require the daemon write permission, so they
+	// can't be called with a universe proof push macaroon.
+	//
 	// TODO(roasbeef): re think these and go instead w/ the * approach?
 	RequiredPermissions = map[string][]bakery.Op{
 		"/taprpc.TaprootAssets/StopDaemon": {{
@@ -20,6 +28,22 @@ var (
 			Entity: "daemon",
 			Action: "read",
 		}},
+		"/taprpc.TaprootAssets/BackupDatabase": {{
+			Entity: "daemon",
+			Action: "write",
+		}},
+		"/taprpc.TaprootAssets/DatabaseStats": {{
+			Entity: "daemon",
+			Action: "read",
+		}},
+		"/grpc.health.v1.Health/Check": {{
+			Entity: "daemon",
+			Action: "read",
+		}},
+		"/grpc.health.v1.Health/Watch": {{
+			Entity: "daemon",
+			Action: "read",
+		}},
 		"/taprpc.TaprootAssets/ListAssets": {{
 			Entity: "assets",
 			Action: "read",
@@ -48,6 +72,10 @@ var (
 			Entity: "addresses",
 			Action: "write",
 		}},
+		"/taprpc.TaprootAssets/PruneAddrs": {{
+			Entity: "addresses",
+			Action: "write",
+		}},
 		"/taprpc.TaprootAssets/DecodeAddr": {{
 			Entity: "addresses",
 			Action: "read",
@@ -68,6 +96,14 @@ var (
 			Entity: "proofs",
 			Action: "write",
 		}},
+		"/taprpc.TaprootAssets/ExportProofBundle": {{
+			Entity: "proofs",
+			Action: "read",
+		}},
+		"/taprpc.TaprootAssets/ImportProofBundle": {{
+			Entity: "proofs",
+			Action: "write",
+		}},
 		"/taprpc.TaprootAssets/SendAsset": {{
 			Entity: "assets",
 			Action: "write",
@@ -76,10 +112,22 @@ var (
 			Entity: "assets",
 			Action: "read",
 		}},
+		"/taprpc.TaprootAssets/FetchGroupMetaHistory": {{
+			Entity: "assets",
+			Action: "read",
+		}},
+		"/taprpc.TaprootAssets/BurnAsset": {{
+			Entity: "assets",
+			Action: "write",
+		}},
 		"/taprpc.TaprootAssets/SubscribeSendAssetEventNtfns": {{
 			Entity: "assets",
 			Action: "write",
 		}},
+		"/taprpc.TaprootAssets/SubscribeReceiveAssetEventNtfns": {{
+			Entity: "addresses",
+			Action: "read",
+		}},
 		"/assetwalletrpc.AssetWallet/FundVirtualPsbt": {{
 			Entity: "assets",
 			Action: "write",
@@ -108,6 +156,38 @@ var (
 			Entity: "assets",
 			Action: "read",
 		}},
+		"/assetwalletrpc.AssetWallet/PrepareInteractiveReceive": {{
+			Entity: "assets",
+			Action: "write",
+		}},
+		"/assetwalletrpc.AssetWallet/VerifyInteractiveReceive": {{
+			Entity: "assets",
+			Action: "read",
+		}},
+		"/assetwalletrpc.AssetWallet/ComputeVirtualPsbtSigHashes": {{
+			Entity: "assets",
+			Action: "read",
+		}},
+		"/assetwalletrpc.AssetWallet/AddVirtualPsbtWitnesses": {{
+			Entity: "assets",
+			Action: "write",
+		}},
+		"/assetwalletrpc.AssetWallet/PrepareAnchorPsbt": {{
+			Entity: "assets",
+			Action: "write",
+		}},
+		"/assetwalletrpc.AssetWallet/PublishAnchorPsbt": {{
+			Entity: "assets",
+			Action: "write",
+		}},
+		"/assetwalletrpc.AssetWallet/ProveReserves": {{
+			Entity: "assets",
+			Action: "write",
+		}},
+		"/assetwalletrpc.AssetWallet/VerifyReserves": {{
+			Entity: "assets",
+			Action: "read",
+		}},
 		"/mintrpc.Mint/MintAsset": {{
 			Entity: "mint",
 			Action: "write",
@@ -151,6 +231,9 @@ var (
 		"/universerpc.Universe/SyncUniverse": {{
 			Entity: "universe",
 			Action: "write",
+		}, {
+			Entity: "daemon",
+			Action: "write",
 		}},
 		"/universerpc.Universe/ListFederationServers": {{
 			Entity: "universe",
@@ -159,10 +242,16 @@ var (
 		"/universerpc.Universe/AddFederationServer": {{
 			Entity: "universe",
 			Action: "write",
+		}, {
+			Entity: "daemon",
+			Action: "write",
 		}},
 		"/universerpc.Universe/DeleteFederationServer": {{
 			Entity: "universe",
 			Action: "write",
+		}, {
+			Entity: "daemon",
+			Action: "write",
 		}},
 		"/universerpc.Universe/UniverseStats": {{
 			Entity: "universe",
@@ -173,17 +262,91 @@ var (
 			Action: "read",
 		}},
 	}
+)
+
+var (
+	// UniverseReadOnlyPermissions are the permissions of the universe
+	// read-only role, which can query the universe trees and proofs.
+	UniverseReadOnlyPermissions = []bakery.Op{{
+		Entity: "universe",
+		Action: "read",
+	}}
+
+	// UniversePushPermissions are the permissions of the universe proof
+	// push role, which can additionally insert new proofs into the
+	// universe, but not manage its federation.
+	UniversePushPermissions = []bakery.Op{{
+		Entity: "universe",
+		Action: "read",
+	}, {
+		Entity: "universe",
+		Action: "write",
+	}}
+
+	// universeReadMethods are the universe methods that can be made public
+	// for reading.
+	universeReadMethods = []string{
+		"/universerpc.Universe/AssetRoots",
+		"/universerpc.Universe/QueryAssetRoots",
+		"/universerpc.Universe/AssetLeafKeys",
+		"/universerpc.Universe/AssetLeaves",
+		"/universerpc.Universe/QueryProof",
+	}
 
-	// MacaroonWhitelist defines methods that we don't require macaroons to
-	// access. For now, these are the Universe related read/write methods.
-	// We permit InsertProof as a valid proof requires an on-chain
-	// transaction, so we gain a layer of DoS defense.
-	MacaroonWhitelist = map[string]struct{}{
-		"/universerpc.Universe/AssetRoots":      {},
-		"/universerpc.Universe/QueryAssetRoots": {},
-		"/universerpc.Universe/AssetLeafKeys":   {},
-		"/universerpc.Universe/AssetLeaves":     {},
-		"/universerpc.Universe/QueryProof":      {},
-		"/universerpc.Universe/InsertProof":     {},
+	// universeWriteMethods are the universe methods that can be made
+	// public for writing. We permit InsertProof as a valid proof requires
+	// an on-chain transaction, so we gain a layer of DoS defense.
+	universeWriteMethods = []string{
+		"/universerpc.Universe/InsertProof",
 	}
 )
+
+// UniversePublicAccess describes which universe RPCs can be called without a
+// macaroon.
+type UniversePublicAccess string
+
+const (
+	// UniversePublicAccessNone requires a macaroon for all universe RPCs.
+	UniversePublicAccessNone UniversePublicAccess = "none"
+
+	// UniversePublicAccessRead allows anyone to query the universe.
+	UniversePublicAccessRead UniversePublicAccess = "read"
+
+	// UniversePublicAccessReadWrite allows anyone to query the universe and
+	// to insert new proofs into it.
+	UniversePublicAccessReadWrite UniversePublicAccess = "readwrite"
+)
+
+// MacaroonWhitelist returns the methods that we don't require macaroons to
+// access, given the public access level of the universe RPCs.
+func MacaroonWhitelist(access UniversePublicAccess) map[string]struct{} {
+	whitelist := make(map[string]struct{})
+
+	switch access {
+	case UniversePublicAccessReadWrite:
+		for _, method := range universeWriteMethods {
+			whitelist[method] = struct{}{}
+		}
+
+		fallthrough
+
+	case UniversePublicAccessRead:
+		for _, method := range universeReadMethods {
+			whitelist[method] = struct{}{}
+		}
+	}
+
+	return whitelist
+}
+
+// UniverseMethods returns all universe RPC methods.
+func UniverseMethods() []string {
+	var methods []string
+	for method := range RequiredPermissions {
+		if strings.HasPrefix(method, "/universerpc.Universe/") {
+			methods = append(methods, method)
+		}
+	}
+
+	return methods
+}
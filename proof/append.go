@@ -136,11 +136,19 @@ func CreateTransitionProof(prevOut wire.OutPoint,
 		return nil, err
 	}
 
+	// Any alt leaves of the output are revealed as well, so the receiver
+	// can re-create the full Taproot Asset commitment of the output.
+	altLeavesProof, err := params.TaprootAssetRoot.AltLeavesProof()
+	if err != nil {
+		return nil, err
+	}
+
 	// With the merkle proof obtained, we can now set that in the main
 	// inclusion proof.
 	proof.InclusionProof.CommitmentProof = &CommitmentProof{
 		Proof:              *assetMerkleProof,
 		TapSiblingPreimage: params.TapscriptSibling,
+		AltLeavesProof:     altLeavesProof,
 	}
 
 	// If the asset is a split asset, we also need to generate MS-SMT
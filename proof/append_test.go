@@ -19,15 +19,13 @@ import (
 )
 
 func genTaprootKeySpend(t testing.TB, privKey btcec.PrivateKey,
-	virtualTx *wire.MsgTx, input *asset.Asset, idx uint32) wire.TxWitness {
+	virtualTx *wire.MsgTx, input *asset.Asset, prevID asset.PrevID,
+	idx uint32) wire.TxWitness {
 
 	t.Helper()
 
-	virtualTxCopy := tapscript.VirtualTxWithInput(
-		virtualTx, input, idx, nil,
-	)
 	sigHash, err := tapscript.InputKeySpendSigHash(
-		virtualTxCopy, input, idx, txscript.SigHashDefault,
+		virtualTx, input, prevID, idx, txscript.SigHashDefault,
 	)
 	require.NoError(t, err)
 
@@ -498,7 +496,7 @@ func signAssetTransfer(t testing.TB, prevProof *Proof, newAsset *asset.Asset,
 	virtualTx, _, err := tapscript.VirtualTx(newAsset, inputs)
 	require.NoError(t, err)
 	newWitness := genTaprootKeySpend(
-		t, *senderPrivKey, virtualTx, &prevProof.Asset, 0,
+		t, *senderPrivKey, virtualTx, &prevProof.Asset, *prevID, 0,
 	)
 	require.NoError(t, err)
 	newAsset.PrevWitnesses[0].TxWitness = newWitness
@@ -7,12 +7,14 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path"
 	"path/filepath"
 	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/wire"
 	"github.com/lightninglabs/taproot-assets/asset"
 	"github.com/lightninglabs/taproot-assets/chanutils"
 )
@@ -55,6 +57,11 @@ type Locator struct {
 	// ScriptKey specifies the script key of the asset to fetch/store. This
 	// field MUST be specified.
 	ScriptKey btcec.PublicKey
+
+	// OutPoint is the outpoint the asset is anchored at. This is an
+	// optional field that is only used by proof couriers that look up
+	// proofs by their anchor outpoint. It isn't part of the locator hash.
+	OutPoint *wire.OutPoint
 }
 
 // Hash returns a SHA256 hash of the bytes serialized locator.
@@ -123,11 +130,27 @@ type FileArchiver struct {
 	// our files.
 	proofPath string
 
+	// encrypter, if set, is used to encrypt all proof files that are
+	// stored, and to decrypt the encrypted ones that are fetched.
+	encrypter *Encrypter
+
 	// eventDistributor is an event distributor that will be used to notify
 	// subscribers about new proofs that are added to the archiver.
 	eventDistributor *chanutils.EventDistributor[Blob]
 }
 
+// FileArchiverOption is a functional option for the file archiver.
+type FileArchiverOption func(*FileArchiver)
+
+// WithEncrypter makes the file archiver encrypt all proof files it stores
+// with the given encrypter. Proof files that were stored in plain text before
+// can still be fetched.
+func WithEncrypter(encrypter *Encrypter) FileArchiverOption {
+	return func(f *FileArchiver) {
+		f.encrypter = encrypter
+	}
+}
+
 // NewFileArchiver creates a new file archive rooted at the passed specified
 // directory.
 //
@@ -135,7 +158,9 @@ type FileArchiver struct {
 //
 // TODO(roasbeef): option to memory map these instead? then don't need to lug
 // around large blobs in user space as much
-func NewFileArchiver(dirName string) (*FileArchiver, error) {
+func NewFileArchiver(dirName string,
+	opts ...FileArchiverOption) (*FileArchiver, error) {
+
 	// First, we'll make sure our main proof directory has already been
 	// created.
 	proofPath := filepath.Join(dirName, ProofDirName)
@@ -143,10 +168,15 @@ func NewFileArchiver(dirName string) (*FileArchiver, error) {
 		return nil, fmt.Errorf("unable to create proof dir: %w", err)
 	}
 
-	return &FileArchiver{
+	archiver := &FileArchiver{
 		proofPath:        proofPath,
 		eventDistributor: chanutils.NewEventDistributor[Blob](),
-	}, nil
+	}
+	for _, opt := range opts {
+		opt(archiver)
+	}
+
+	return archiver, nil
 }
 
 // genProofFilePath generates the full proof file path based on a rootPath and
@@ -191,6 +221,12 @@ func (f *FileArchiver) FetchProof(_ context.Context, id Locator) (Blob, error) {
 		return nil, ErrProofNotFound
 	case err != nil:
 		return nil, fmt.Errorf("unable to find proof: %w", err)
+
+	case f.encrypter != nil:
+		return f.encrypter.Decrypt(proofFile)
+
+	case IsEncrypted(proofFile):
+		return nil, ErrProofEncrypted
 	}
 
 	return proofFile, nil
@@ -214,7 +250,16 @@ func (f *FileArchiver) ImportProofs(_ context.Context,
 			return err
 		}
 
-		err = os.WriteFile(proofPath, proof.Blob, 0666)
+		blob := proof.Blob
+		if f.encrypter != nil {
+			blob, err = f.encrypter.Encrypt(blob)
+			if err != nil {
+				return fmt.Errorf("unable to encrypt proof: %w",
+					err)
+			}
+		}
+
+		err = os.WriteFile(proofPath, blob, 0666)
 		if err != nil {
 			return fmt.Errorf("unable to store proof: %v", err)
 		}
@@ -225,6 +270,85 @@ func (f *FileArchiver) ImportProofs(_ context.Context,
 	return nil
 }
 
+// EncryptProofs encrypts all proof files of the archive that are still stored
+// in plain text, returning the number of files that were encrypted. Each file
+// is replaced atomically, so the migration can safely be resumed if it's
+// interrupted.
+func (f *FileArchiver) EncryptProofs() (int, error) {
+	if f.encrypter == nil {
+		return 0, fmt.Errorf("no encryption key set")
+	}
+
+	var numEncrypted int
+	err := filepath.WalkDir(
+		f.proofPath, func(filePath string, entry fs.DirEntry,
+			err error) error {
+
+			switch {
+			case err != nil:
+				return err
+
+			case entry.IsDir():
+				return nil
+
+			case filepath.Ext(filePath) != TaprootAssetsFileSuffix:
+				return nil
+			}
+
+			encrypted, err := f.encryptFile(filePath)
+			if err != nil {
+				return fmt.Errorf("unable to encrypt proof "+
+					"file %v: %w", filePath, err)
+			}
+			if encrypted {
+				numEncrypted++
+			}
+
+			return nil
+		},
+	)
+
+	return numEncrypted, err
+}
+
+// encryptFile encrypts the proof file at the given path, unless it's encrypted
+// already. The file is replaced by writing the encrypted file next to it first
+// and then renaming it.
+func (f *FileArchiver) encryptFile(filePath string) (bool, error) {
+	blob, err := os.ReadFile(filePath)
+	if err != nil {
+		return false, err
+	}
+	if IsEncrypted(blob) {
+		return false, nil
+	}
+
+	encrypted, err := f.encrypter.Encrypt(blob)
+	if err != nil {
+		return false, err
+	}
+
+	tempFile, err := os.CreateTemp(filepath.Dir(filePath), "encrypt-*")
+	if err != nil {
+		return false, err
+	}
+	defer os.Remove(tempFile.Name())
+
+	if _, err := tempFile.Write(encrypted); err != nil {
+		tempFile.Close()
+		return false, err
+	}
+	if err := tempFile.Sync(); err != nil {
+		tempFile.Close()
+		return false, err
+	}
+	if err := tempFile.Close(); err != nil {
+		return false, err
+	}
+
+	return true, os.Rename(tempFile.Name(), filePath)
+}
+
 // RegisterSubscriber adds a new subscriber for receiving events. The
 // deliverExisting boolean indicates whether already existing items should be
 // sent to the NewItemCreated channel when the subscription is started. An
@@ -370,6 +494,23 @@ func (m *MultiArchiver) ImportProofs(ctx context.Context,
 		return err
 	}
 
+	// The inclusion proofs only commit to the group key of an asset, so we
+	// still need to make sure that each grouped asset was actually
+	// authorized by the group key. We verify all group key signatures in
+	// a single batch, which is much faster when importing many proofs.
+	var groupSigs asset.GroupSigBatch
+	for _, proof := range proofs {
+		finalAsset := proof.AssetSnapshot.Asset
+		if finalAsset.GroupKey == nil {
+			continue
+		}
+
+		groupSigs.Add(finalAsset.Genesis, finalAsset.GroupKey)
+	}
+	if err := groupSigs.Verify(); err != nil {
+		return fmt.Errorf("unable to verify proof: %w", err)
+	}
+
 	// Now that we know all the proofs are valid, and have tacked on some
 	// additional supplementary information into the locator, we'll attempt
 	// to import each proof our archive backends.
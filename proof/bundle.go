@@ -0,0 +1,346 @@
+package proof
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+// BundleVersion denotes the versioning scheme for proof bundles.
+type BundleVersion uint32
+
+const (
+	// BundleV0 is the first version of the proof bundle.
+	BundleV0 BundleVersion = 0
+
+	// MaxBundleSize is the maximum size of a decompressed proof bundle we
+	// decode. This protects us from bundles that decompress to an
+	// excessive amount of data.
+	MaxBundleSize = 1 << 30
+)
+
+var (
+	// ErrUnknownBundleVersion is returned if a proof bundle has a version
+	// we don't know how to interpret.
+	ErrUnknownBundleVersion = errors.New("unknown proof bundle version")
+
+	// ErrBundleManifestMismatch is returned if the manifest of a proof
+	// bundle doesn't match the proof files it contains.
+	ErrBundleManifestMismatch = errors.New("proof bundle manifest " +
+		"mismatch")
+
+	// ErrBundleTooLarge is returned if a proof bundle decompresses to more
+	// than MaxBundleSize bytes.
+	ErrBundleTooLarge = errors.New("proof bundle too large")
+)
+
+// BundleEntry is an entry of the manifest of a proof bundle. It describes a
+// single proof file of the bundle, identified by the asset it proves.
+type BundleEntry struct {
+	// AssetID is the ID of the asset the proof file proves.
+	AssetID asset.ID
+
+	// GroupKey is the group key of the asset the proof file proves, if the
+	// asset is part of a group.
+	GroupKey *btcec.PublicKey
+
+	// ScriptKey is the script key of the asset the proof file proves.
+	ScriptKey *btcec.PublicKey
+
+	// NumProofs is the number of state transitions in the proof file.
+	NumProofs uint64
+
+	// FileHash is the SHA256 hash of the encoded proof file.
+	FileHash [sha256.Size]byte
+}
+
+// newBundleEntry creates the manifest entry for the given proof file.
+func newBundleEntry(blob Blob) (*BundleEntry, error) {
+	var file File
+	if err := file.Decode(bytes.NewReader(blob)); err != nil {
+		return nil, fmt.Errorf("unable to decode proof file: %w", err)
+	}
+
+	lastProof, err := file.LastProof()
+	if err != nil {
+		return nil, err
+	}
+
+	lastAsset := &lastProof.Asset
+	entry := &BundleEntry{
+		AssetID:   lastAsset.ID(),
+		ScriptKey: lastAsset.ScriptKey.PubKey,
+		NumProofs: uint64(file.NumProofs()),
+		FileHash:  sha256.Sum256(blob),
+	}
+	if lastAsset.GroupKey != nil {
+		entry.GroupKey = &lastAsset.GroupKey.GroupPubKey
+	}
+
+	return entry, nil
+}
+
+// Locator returns the locator of the proof file the entry describes.
+func (e *BundleEntry) Locator() Locator {
+	assetID := e.AssetID
+
+	return Locator{
+		AssetID:   &assetID,
+		GroupKey:  e.GroupKey,
+		ScriptKey: *e.ScriptKey,
+	}
+}
+
+// matches returns an error if the given entry doesn't describe the same proof
+// file as the entry.
+func (e *BundleEntry) matches(other *BundleEntry) error {
+	switch {
+	case e.FileHash != other.FileHash:
+		return fmt.Errorf("file hash %x doesn't match %x", e.FileHash,
+			other.FileHash)
+
+	case e.AssetID != other.AssetID:
+		return fmt.Errorf("asset ID %v doesn't match %v", e.AssetID,
+			other.AssetID)
+
+	case (e.GroupKey == nil) != (other.GroupKey == nil),
+		e.GroupKey != nil && !e.GroupKey.IsEqual(other.GroupKey):
+
+		return fmt.Errorf("group key of asset %v doesn't match",
+			e.AssetID)
+
+	case e.ScriptKey == nil || !e.ScriptKey.IsEqual(other.ScriptKey):
+		return fmt.Errorf("script key of asset %v doesn't match",
+			e.AssetID)
+
+	case e.NumProofs != other.NumProofs:
+		return fmt.Errorf("number of proofs %d of asset %v doesn't "+
+			"match %d", e.NumProofs, e.AssetID, other.NumProofs)
+	}
+
+	return nil
+}
+
+// EncodeRecords returns the TLV encode records for the bundle entry.
+func (e *BundleEntry) EncodeRecords() []tlv.Record {
+	records := []tlv.Record{
+		BundleEntryAssetIDRecord(&e.AssetID),
+	}
+	if e.GroupKey != nil {
+		records = append(
+			records, BundleEntryGroupKeyRecord(&e.GroupKey),
+		)
+	}
+
+	return append(
+		records, BundleEntryScriptKeyRecord(&e.ScriptKey),
+		BundleEntryNumProofsRecord(&e.NumProofs),
+		BundleEntryFileHashRecord(&e.FileHash),
+	)
+}
+
+// DecodeRecords returns the TLV decode records for the bundle entry.
+func (e *BundleEntry) DecodeRecords() []tlv.Record {
+	return []tlv.Record{
+		BundleEntryAssetIDRecord(&e.AssetID),
+		BundleEntryGroupKeyRecord(&e.GroupKey),
+		BundleEntryScriptKeyRecord(&e.ScriptKey),
+		BundleEntryNumProofsRecord(&e.NumProofs),
+		BundleEntryFileHashRecord(&e.FileHash),
+	}
+}
+
+// Encode encodes the bundle entry to the given writer.
+func (e *BundleEntry) Encode(w io.Writer) error {
+	stream, err := tlv.NewStream(e.EncodeRecords()...)
+	if err != nil {
+		return err
+	}
+	return stream.Encode(w)
+}
+
+// Decode decodes the bundle entry from the given reader.
+func (e *BundleEntry) Decode(r io.Reader) error {
+	stream, err := tlv.NewStream(e.DecodeRecords()...)
+	if err != nil {
+		return err
+	}
+	return stream.Decode(r)
+}
+
+// Bundle is a set of proof files, along with a manifest that describes them.
+// Bundles are used to export all proofs of a wallet at once, for example to
+// back them up or to migrate them to another node. When encoded, a bundle is
+// compressed.
+type Bundle struct {
+	// Version is the version of the proof bundle.
+	Version BundleVersion
+
+	// Manifest describes the proof files of the bundle. The entry at each
+	// index belongs to the proof file at the same index.
+	Manifest []BundleEntry
+
+	// Files are the encoded proof files of the bundle.
+	Files []Blob
+}
+
+// NewBundle creates a new proof bundle for the given proof files.
+func NewBundle(files ...Blob) (*Bundle, error) {
+	bundle := &Bundle{
+		Version:  BundleV0,
+		Manifest: make([]BundleEntry, 0, len(files)),
+		Files:    files,
+	}
+	for idx, file := range files {
+		entry, err := newBundleEntry(file)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proof file %d: %w", idx,
+				err)
+		}
+
+		bundle.Manifest = append(bundle.Manifest, *entry)
+	}
+
+	return bundle, nil
+}
+
+// Validate makes sure the manifest of the bundle matches the proof files it
+// contains. The proof files themselves aren't verified.
+func (b *Bundle) Validate() error {
+	if b.Version != BundleV0 {
+		return fmt.Errorf("%w: %d", ErrUnknownBundleVersion, b.Version)
+	}
+
+	if len(b.Manifest) != len(b.Files) {
+		return fmt.Errorf("%w: %d entries for %d proof files",
+			ErrBundleManifestMismatch, len(b.Manifest),
+			len(b.Files))
+	}
+
+	for idx, file := range b.Files {
+		entry, err := newBundleEntry(file)
+		if err != nil {
+			return fmt.Errorf("invalid proof file %d: %w", idx, err)
+		}
+
+		if err := b.Manifest[idx].matches(entry); err != nil {
+			return fmt.Errorf("%w: entry %d: %v",
+				ErrBundleManifestMismatch, idx, err)
+		}
+	}
+
+	return nil
+}
+
+// EncodeRecords returns the TLV encode records for the proof bundle.
+func (b *Bundle) EncodeRecords() []tlv.Record {
+	return []tlv.Record{
+		BundleVersionRecord(&b.Version),
+		BundleManifestRecord(&b.Manifest),
+		BundleFilesRecord(&b.Files),
+	}
+}
+
+// DecodeRecords returns the TLV decode records for the proof bundle.
+func (b *Bundle) DecodeRecords() []tlv.Record {
+	return []tlv.Record{
+		BundleVersionRecord(&b.Version),
+		BundleManifestRecord(&b.Manifest),
+		BundleFilesRecord(&b.Files),
+	}
+}
+
+// Encode encodes and compresses the proof bundle to the given writer.
+func (b *Bundle) Encode(w io.Writer) error {
+	stream, err := tlv.NewStream(b.EncodeRecords()...)
+	if err != nil {
+		return err
+	}
+
+	zw := gzip.NewWriter(w)
+	if err := stream.Encode(zw); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+// Decode decompresses and decodes the proof bundle from the given reader.
+func (b *Bundle) Decode(r io.Reader) error {
+	stream, err := tlv.NewStream(b.DecodeRecords()...)
+	if err != nil {
+		return err
+	}
+
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("unable to decompress proof bundle: %w", err)
+	}
+
+	// The stream is decoded until the end of the decompressed data, at
+	// which point the integrity of the compressed data is checked as well.
+	// If we hit our size limit instead, the bundle might decode just fine
+	// but be truncated, so we read one more byte than allowed to detect
+	// that case.
+	limitReader := &io.LimitedReader{R: zr, N: MaxBundleSize + 1}
+	err = stream.Decode(limitReader)
+	switch {
+	case limitReader.N <= 0:
+		return ErrBundleTooLarge
+
+	case err != nil:
+		return err
+	}
+
+	return nil
+}
+
+// ExportBundle fetches the proof files identified by the given locators from
+// the archive and bundles them.
+func ExportBundle(ctx context.Context, archive Archiver,
+	locators ...Locator) (*Bundle, error) {
+
+	files := make([]Blob, 0, len(locators))
+	for _, loc := range locators {
+		file, err := archive.FetchProof(ctx, loc)
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch proof for "+
+				"script key %x: %w",
+				loc.ScriptKey.SerializeCompressed(), err)
+		}
+
+		files = append(files, file)
+	}
+
+	return NewBundle(files...)
+}
+
+// ImportBundle validates the manifest of the given proof bundle and then
+// imports all of its proof files into the archive. The archive is responsible
+// for fully verifying each proof file, which the MultiArchiver does before
+// storing any of them.
+func ImportBundle(ctx context.Context, archive Archiver,
+	headerVerifier HeaderVerifier, bundle *Bundle) error {
+
+	if err := bundle.Validate(); err != nil {
+		return err
+	}
+
+	proofs := make([]*AnnotatedProof, len(bundle.Files))
+	for idx := range bundle.Files {
+		proofs[idx] = &AnnotatedProof{
+			Locator: bundle.Manifest[idx].Locator(),
+			Blob:    bundle.Files[idx],
+		}
+	}
+
+	return archive.ImportProofs(ctx, headerVerifier, proofs...)
+}
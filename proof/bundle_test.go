@@ -0,0 +1,184 @@
+package proof
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"testing"
+
+	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightninglabs/taproot-assets/internal/test"
+	"github.com/stretchr/testify/require"
+)
+
+// randProofFileBlob creates an encoded proof file for a new random asset.
+func randProofFileBlob(t *testing.T, amt uint64) Blob {
+	genesisProof, _ := genRandomGenesisWithProof(
+		t, asset.Normal, &amt, nil, true, nil, nil,
+	)
+
+	file, err := NewFile(V0, genesisProof)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, file.Encode(&buf))
+
+	return buf.Bytes()
+}
+
+// TestBundleEncoding tests that proof bundles survive an encoding round trip
+// and that their manifest is checked against the proof files.
+func TestBundleEncoding(t *testing.T) {
+	t.Parallel()
+
+	files := []Blob{
+		randProofFileBlob(t, 1000),
+		randProofFileBlob(t, 42),
+	}
+	bundle, err := NewBundle(files...)
+	require.NoError(t, err)
+	require.NoError(t, bundle.Validate())
+	require.Len(t, bundle.Manifest, 2)
+
+	var lastProof Proof
+	require.NoError(t, lastProof.Decode(bytes.NewReader(
+		mustRawLastProof(t, files[1]),
+	)))
+	entry := bundle.Manifest[1]
+	require.Equal(t, lastProof.Asset.ID(), entry.AssetID)
+	require.True(t, lastProof.Asset.ScriptKey.PubKey.IsEqual(
+		entry.ScriptKey,
+	))
+	require.True(t, lastProof.Asset.GroupKey.GroupPubKey.IsEqual(
+		entry.GroupKey,
+	))
+	require.EqualValues(t, 1, entry.NumProofs)
+
+	var buf bytes.Buffer
+	require.NoError(t, bundle.Encode(&buf))
+
+	var decoded Bundle
+	require.NoError(t, decoded.Decode(&buf))
+	require.Equal(t, *bundle, decoded)
+	require.NoError(t, decoded.Validate())
+
+	// A manifest that doesn't match the proof files is rejected.
+	swapped := decoded
+	swapped.Manifest = []BundleEntry{
+		decoded.Manifest[1], decoded.Manifest[0],
+	}
+	require.ErrorIs(t, swapped.Validate(), ErrBundleManifestMismatch)
+
+	truncated := decoded
+	truncated.Manifest = decoded.Manifest[:1]
+	require.ErrorIs(t, truncated.Validate(), ErrBundleManifestMismatch)
+
+	tampered := decoded
+	tampered.Manifest = append([]BundleEntry{}, decoded.Manifest...)
+	tampered.Manifest[0].ScriptKey = test.RandPubKey(t)
+	require.ErrorIs(t, tampered.Validate(), ErrBundleManifestMismatch)
+
+	unknownVersion := decoded
+	unknownVersion.Version = BundleV0 + 1
+	require.ErrorIs(t, unknownVersion.Validate(), ErrUnknownBundleVersion)
+
+	// Data that isn't compressed can't be decoded.
+	var uncompressed Bundle
+	err = uncompressed.Decode(bytes.NewReader(files[0]))
+	require.ErrorIs(t, err, gzip.ErrHeader)
+}
+
+// mustRawLastProof returns the encoded last proof of the given proof file.
+func mustRawLastProof(t *testing.T, blob Blob) []byte {
+	var file File
+	require.NoError(t, file.Decode(bytes.NewReader(blob)))
+
+	rawProof, err := file.RawLastProof()
+	require.NoError(t, err)
+
+	return rawProof
+}
+
+// TestBundleExportImport tests that proof bundles exported from one archive
+// can be imported into another one, and that the proofs of a bundle are
+// verified on import.
+func TestBundleExportImport(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	srcFiles, err := NewFileArchiver(t.TempDir())
+	require.NoError(t, err)
+	srcArchive := NewMultiArchiver(&BaseVerifier{}, testTimeout, srcFiles)
+
+	files := []Blob{
+		randProofFileBlob(t, 1000),
+		randProofFileBlob(t, 42),
+	}
+	proofs := make([]*AnnotatedProof, len(files))
+	for idx := range files {
+		proofs[idx] = &AnnotatedProof{
+			Blob: files[idx],
+		}
+	}
+	err = srcArchive.ImportProofs(ctx, MockHeaderVerifier, proofs...)
+	require.NoError(t, err)
+
+	locators := make([]Locator, len(proofs))
+	for idx := range proofs {
+		locators[idx] = proofs[idx].Locator
+	}
+	bundle, err := ExportBundle(ctx, srcArchive, locators...)
+	require.NoError(t, err)
+	require.Equal(t, files, bundle.Files)
+
+	var buf bytes.Buffer
+	require.NoError(t, bundle.Encode(&buf))
+
+	var decoded Bundle
+	require.NoError(t, decoded.Decode(&buf))
+
+	dstFiles, err := NewFileArchiver(t.TempDir())
+	require.NoError(t, err)
+	dstArchive := NewMultiArchiver(&BaseVerifier{}, testTimeout, dstFiles)
+
+	err = ImportBundle(ctx, dstArchive, MockHeaderVerifier, &decoded)
+	require.NoError(t, err)
+
+	for idx, loc := range locators {
+		file, err := dstArchive.FetchProof(ctx, loc)
+		require.NoError(t, err)
+		require.Equal(t, files[idx], file)
+	}
+
+	// A bundle with a proof file that doesn't verify is rejected as a
+	// whole, even if its manifest matches.
+	var invalidFile File
+	require.NoError(t, invalidFile.Decode(bytes.NewReader(files[0])))
+	invalidProof, err := invalidFile.LastProof()
+	require.NoError(t, err)
+	invalidProof.Asset.Amount++
+	require.NoError(t, invalidFile.ReplaceLastProof(*invalidProof))
+
+	var invalidBuf bytes.Buffer
+	require.NoError(t, invalidFile.Encode(&invalidBuf))
+
+	invalidBundle, err := NewBundle(
+		randProofFileBlob(t, 7), invalidBuf.Bytes(),
+	)
+	require.NoError(t, err)
+
+	emptyFiles, err := NewFileArchiver(t.TempDir())
+	require.NoError(t, err)
+	emptyArchive := NewMultiArchiver(
+		&BaseVerifier{}, testTimeout, emptyFiles,
+	)
+
+	err = ImportBundle(ctx, emptyArchive, MockHeaderVerifier, invalidBundle)
+	require.ErrorContains(t, err, "unable to verify proof")
+
+	_, err = emptyArchive.FetchProof(
+		ctx, invalidBundle.Manifest[0].Locator(),
+	)
+	require.ErrorIs(t, err, ErrProofNotFound)
+}
@@ -6,19 +6,28 @@ import (
 	"crypto/sha512"
 	"crypto/tls"
 	"fmt"
+	"net/url"
 	"sync"
 	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/lightninglabs/lightning-node-connect/hashmailrpc"
+	"github.com/lightninglabs/taproot-assets/address"
 	"github.com/lightninglabs/taproot-assets/asset"
 	"github.com/lightninglabs/taproot-assets/chanutils"
+	"github.com/lightninglabs/taproot-assets/monitoring"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/status"
 )
 
+const (
+	// mailboxDeadline is the time after which the watchdog reports an
+	// interaction with the hashmail service as stalled.
+	mailboxDeadline = 10 * time.Minute
+)
+
 // Courier abstracts away from the final proof retrival/delivery process as
 // part of the non-interactive send flow. A sender can use this given the
 // abstracted Addr/source type to send a proof to the receiver. Conversely, a
@@ -39,6 +48,172 @@ type Courier[Addr any] interface {
 	SetSubscribers(map[uint64]*chanutils.EventReceiver[chanutils.Event])
 }
 
+const (
+	// HashmailCourierType is the URL scheme of a proof courier that uses
+	// the hashmail protocol to deliver proofs.
+	HashmailCourierType = address.HashmailCourierType
+
+	// UniverseRpcCourierType is the URL scheme of a proof courier that
+	// deposits proofs in and retrieves them from a universe server using
+	// its RPC interface.
+	UniverseRpcCourierType = address.UniverseRpcCourierType
+)
+
+// ParseCourierAddrString parses the given string as a proof courier address
+// and makes sure it is valid.
+func ParseCourierAddrString(addr string) (*url.URL, error) {
+	addrURL, err := url.ParseRequestURI(addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proof courier URI address: %w",
+			err)
+	}
+
+	if err := ValidateCourierAddress(addrURL); err != nil {
+		return nil, err
+	}
+
+	return addrURL, nil
+}
+
+// ValidateCourierAddress makes sure the given proof courier address uses a
+// known courier type and specifies both a host and a port.
+func ValidateCourierAddress(addr *url.URL) error {
+	return address.ValidateProofCourierAddr(addr)
+}
+
+// CourierDispatch is an interface that abstracts away the different proof
+// courier implementations. It hands out the courier that should be used for a
+// given proof courier address.
+type CourierDispatch interface {
+	// NewCourier returns a courier that delivers or retrieves proofs using
+	// the service at the given proof courier address.
+	NewCourier(addr *url.URL) (Courier[Recipient], error)
+
+	// SetSubscribers sets the set of subscribers that will be notified
+	// of proof courier related events by any of the couriers.
+	SetSubscribers(map[uint64]*chanutils.EventReceiver[chanutils.Event])
+}
+
+// CourierCfg contains the configuration of all proof courier types. A courier
+// type without a configuration can't be used.
+type CourierCfg struct {
+	// HashMailCfg is the configuration of the hashmail courier.
+	HashMailCfg *HashMailCourierCfg
+
+	// UniverseRpcCfg is the configuration of the universe RPC courier.
+	UniverseRpcCfg *UniverseRpcCourierCfg
+
+	// DeliveryLog is the log the couriers record their delivery attempts
+	// in.
+	DeliveryLog DeliveryLog
+}
+
+// URLDispatch is an implementation of the CourierDispatch interface that picks
+// the courier type based on the scheme of the proof courier address. Couriers
+// are created once per address and re-used afterwards.
+type URLDispatch struct {
+	cfg *CourierCfg
+
+	// couriers are the couriers that were created so far, keyed by their
+	// proof courier address.
+	couriers map[string]Courier[Recipient]
+
+	// subscribers is a map of components that want to be notified on new
+	// events, keyed by their subscription ID.
+	subscribers map[uint64]*chanutils.EventReceiver[chanutils.Event]
+
+	// mtx guards the couriers and subscribers maps.
+	mtx sync.Mutex
+}
+
+// NewURLDispatch creates a new proof courier dispatcher with the given courier
+// configuration.
+func NewURLDispatch(cfg *CourierCfg) *URLDispatch {
+	return &URLDispatch{
+		cfg:      cfg,
+		couriers: make(map[string]Courier[Recipient]),
+	}
+}
+
+// NewCourier returns a courier that delivers or retrieves proofs using the
+// service at the given proof courier address.
+func (u *URLDispatch) NewCourier(addr *url.URL) (Courier[Recipient], error) {
+	if err := ValidateCourierAddress(addr); err != nil {
+		return nil, err
+	}
+
+	u.mtx.Lock()
+	defer u.mtx.Unlock()
+
+	if courier, ok := u.couriers[addr.String()]; ok {
+		return courier, nil
+	}
+
+	var (
+		courier Courier[Recipient]
+		err     error
+	)
+	switch addr.Scheme {
+	case HashmailCourierType:
+		if u.cfg.HashMailCfg == nil {
+			return nil, fmt.Errorf("hashmail courier not " +
+				"configured")
+		}
+
+		var mailbox *HashMailBox
+		mailbox, err = NewHashMailBox(
+			addr.Host, u.cfg.HashMailCfg.TlsCertPath,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to make mailbox: %w",
+				err)
+		}
+
+		courier, err = NewHashMailCourier(
+			u.cfg.HashMailCfg, mailbox, u.cfg.DeliveryLog,
+		)
+
+	case UniverseRpcCourierType:
+		if u.cfg.UniverseRpcCfg == nil {
+			return nil, fmt.Errorf("universe RPC courier not " +
+				"configured")
+		}
+
+		courier, err = NewUniverseRpcCourier(
+			u.cfg.UniverseRpcCfg, addr, u.cfg.DeliveryLog,
+		)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to make %v courier: %w",
+			addr.Scheme, err)
+	}
+
+	if u.subscribers != nil {
+		courier.SetSubscribers(u.subscribers)
+	}
+	u.couriers[addr.String()] = courier
+
+	return courier, nil
+}
+
+// SetSubscribers sets the subscribers of all current and future couriers. This
+// method is thread-safe.
+func (u *URLDispatch) SetSubscribers(
+	subscribers map[uint64]*chanutils.EventReceiver[chanutils.Event]) {
+
+	u.mtx.Lock()
+	defer u.mtx.Unlock()
+
+	u.subscribers = subscribers
+	for _, courier := range u.couriers {
+		courier.SetSubscribers(subscribers)
+	}
+}
+
+// A compile-time assertion to ensure the URLDispatch meets the CourierDispatch
+// interface.
+var _ CourierDispatch = (*URLDispatch)(nil)
+
 // ProofMailbox represents an abstract store-and-forward maillbox that can be
 // used to send/receive proofs.
 type ProofMailbox interface {
@@ -332,7 +507,8 @@ type BackoffCfg struct {
 	MaxBackoff time.Duration `long:"maxbackoff" description:"The maximum backoff time to wait before retrying to deliver the proof to the receiver."`
 }
 
-// HashMailCourier is an implementation of the Courier interfaces that
+// HashMailCourier is an implementation of the Courier interfaces that uses a
+// hashmail service to push proofs directly to the receiver.
 type HashMailCourier struct {
 	// cfg contains the courier's configuration parameters.
 	cfg *HashMailCourierCfg
@@ -376,7 +552,8 @@ func NewHashMailCourier(cfg *HashMailCourierCfg, mailbox ProofMailbox,
 func (h *HashMailCourier) DeliverProof(ctx context.Context, recipient Recipient,
 	proof *AnnotatedProof) error {
 
-	log.Infof("Attempting to deliver receiver proof for send of "+
+	ctxLog := monitoring.LoggerFromContext(ctx, log)
+	ctxLog.Infof("Attempting to deliver receiver proof for send of "+
 		"asset_id=%x, amt=%v", recipient.AssetID, recipient.Amount)
 
 	// Compute the stream IDs for the sender and receiver.
@@ -403,11 +580,11 @@ func (h *HashMailCourier) DeliverProof(ctx context.Context, recipient Recipient,
 		timeSinceLastAttempt < backoffResetWait {
 
 		waitDuration := backoffResetWait - timeSinceLastAttempt
-		log.Infof("Waiting %v before attempting to "+
+		ctxLog.Infof("Waiting %v before attempting to "+
 			"deliver receiver proof to receiver "+
 			"using backoff procedure", waitDuration)
 
-		err := h.wait(ctx, waitDuration)
+		err := wait(ctx, waitDuration)
 		if err != nil {
 			return err
 		}
@@ -417,6 +594,14 @@ func (h *HashMailCourier) DeliverProof(ctx context.Context, recipient Recipient,
 	// ensure that we don't overwhelm the service with delivery attempts.
 	err = h.backoffExec(
 		ctx, func() error {
+			doneSending := monitoring.TrackActivity(
+				monitoring.SubsystemCourier, fmt.Sprintf(
+					"send proof via sid=%x",
+					senderStreamID,
+				), mailboxDeadline,
+			)
+			defer doneSending()
+
 			err := h.initMailboxes(
 				ctx, senderStreamID, receiverStreamID,
 			)
@@ -439,7 +624,7 @@ func (h *HashMailCourier) DeliverProof(ctx context.Context, recipient Recipient,
 			// the proof over the stream.
 			//
 			// TODO(roasbeef): do ecies here
-			log.Infof("Sending receiver proof via sid=%x",
+			ctxLog.Infof("Sending receiver proof via sid=%x",
 				senderStreamID)
 			err = h.mailbox.WriteProof(
 				ctx, senderStreamID, proof.Blob,
@@ -449,9 +634,20 @@ func (h *HashMailCourier) DeliverProof(ctx context.Context, recipient Recipient,
 					"to asset transfer receiver: %w", err)
 			}
 
+			doneSending()
+
 			// Wait to receive the ACK from the remote party over
-			// their stream.
-			log.Infof("Waiting (%v) for receiver ACK via sid=%x",
+			// their stream. The wait is bounded by its own timeout,
+			// so we only flag it as stalled if it overruns that.
+			doneAcking := monitoring.TrackActivity(
+				monitoring.SubsystemCourier, fmt.Sprintf(
+					"wait for ACK via sid=%x",
+					receiverStreamID,
+				), h.cfg.ReceiverAckTimeout+mailboxDeadline,
+			)
+			defer doneAcking()
+
+			ctxLog.Infof("Waiting (%v) for receiver ACK via sid=%x",
 				h.cfg.ReceiverAckTimeout, receiverStreamID)
 
 			ctxTimeout, cancel := context.WithTimeout(
@@ -467,12 +663,13 @@ func (h *HashMailCourier) DeliverProof(ctx context.Context, recipient Recipient,
 			return nil
 		},
 	)
+	monitoring.ObserveCourierDelivery(err)
 	if err != nil {
 		return fmt.Errorf("proof backoff delivery attempt has "+
 			"failed: %w", err)
 	}
 
-	log.Infof("Received ACK from receiver! Cleaning up mailboxes...")
+	ctxLog.Infof("Received ACK from receiver! Cleaning up mailboxes...")
 
 	// Once we receive this ACK, we can clean up our mailbox and also the
 	// receiver's mailbox.
@@ -490,12 +687,14 @@ func (h *HashMailCourier) DeliverProof(ctx context.Context, recipient Recipient,
 func (h *HashMailCourier) initMailboxes(ctx context.Context,
 	senderStreamID streamID, receiverStreamID streamID) error {
 
+	ctxLog := monitoring.LoggerFromContext(ctx, log)
+
 	// To deliver the proof to the receiver, we'll use our hashmail box to
 	// create a new session that we'll use to send the proof over.
 	// We'll send on this stream, while the receiver receives on it.
 	//
 	// TODO(roasbeef): should do this as early in the process as possible.
-	log.Infof("Creating sender mailbox w/ sid=%x", senderStreamID)
+	ctxLog.Infof("Creating sender mailbox w/ sid=%x", senderStreamID)
 	if err := h.mailbox.Init(ctx, senderStreamID); err != nil {
 		return fmt.Errorf("failed to init sender stream mailbox: %w",
 			err)
@@ -505,7 +704,7 @@ func (h *HashMailCourier) initMailboxes(ctx context.Context,
 	// ID for a proof delivery ACK.
 	//
 	// TODO(roasbeef): ok that both sides might be on the same side here?
-	log.Infof("Creating receiver mailbox w/ sid=%x", receiverStreamID)
+	ctxLog.Infof("Creating receiver mailbox w/ sid=%x", receiverStreamID)
 	if err := h.mailbox.Init(ctx, receiverStreamID); err != nil {
 		return fmt.Errorf("failed to init receiver ACK mailbox: %w",
 			err)
@@ -555,16 +754,33 @@ func (e *BackoffExecError) Error() string {
 func (h *HashMailCourier) backoffExec(ctx context.Context,
 	targetFunc func() error) error {
 
+	return backoffExec(
+		ctx, h.cfg.BackoffCfg, h.publishSubscriberEvent, targetFunc,
+	)
+}
+
+// backoffExec attempts to execute the given target function using a repeating
+// backoff time delayed strategy as configured by the given backoff config.
+// Before each backoff wait, a ReceiverProofBackoffWaitEvent is published.
+func backoffExec(ctx context.Context, cfg *BackoffCfg,
+	publish func(chanutils.Event), targetFunc func() error) error {
+
+	ctxLog := monitoring.LoggerFromContext(ctx, log)
+
 	var (
-		backoff    = h.cfg.BackoffCfg.InitialBackoff
-		numTries   = h.cfg.BackoffCfg.NumTries
-		maxBackoff = h.cfg.BackoffCfg.MaxBackoff
+		backoff    = cfg.InitialBackoff
+		numTries   = cfg.NumTries
+		maxBackoff = cfg.MaxBackoff
 
 		// Target function execution error.
 		errExec error = nil
 	)
 
 	for i := 0; i < numTries; i++ {
+		if i > 0 {
+			monitoring.ObserveCourierRetry()
+		}
+
 		// Execute target function.
 		errExec = targetFunc()
 		if errExec == nil {
@@ -587,13 +803,13 @@ func (h *HashMailCourier) backoffExec(ctx context.Context,
 		transferEvent := NewReceiverProofBackoffWaitEvent(
 			backoff, int64(i+1),
 		)
-		h.publishSubscriberEvent(transferEvent)
+		publish(transferEvent)
 
-		log.Debugf("Receiver proof delivery failed with "+
+		ctxLog.Debugf("Receiver proof delivery failed with "+
 			"error. Backing off for %s: %v", backoff, errExec)
 
 		// Wait before reattempting execution.
-		err := h.wait(ctx, backoff)
+		err := wait(ctx, backoff)
 		if err != nil {
 			return fmt.Errorf("backoff wait: %w", err)
 		}
@@ -626,14 +842,12 @@ func (h *HashMailCourier) publishSubscriberEvent(event chanutils.Event) {
 }
 
 // wait blocks for a given amount of time.
-func (h *HashMailCourier) wait(ctx context.Context,
-	backoff time.Duration) error {
-
+func wait(ctx context.Context, backoff time.Duration) error {
 	select {
 	case <-time.After(backoff):
 		return nil
 	case <-ctx.Done():
-		return fmt.Errorf("hashmail courier context canceled")
+		return fmt.Errorf("proof courier context canceled")
 	}
 }
 
@@ -0,0 +1,257 @@
+package proof
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/wire"
+	unirpc "github.com/lightninglabs/taproot-assets/taprpc/universerpc"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// TestParseCourierAddrString tests that only proof courier addresses of a
+// known type that specify a host and port are accepted.
+func TestParseCourierAddrString(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		addr     string
+		expected string
+	}{{
+		addr: "hashmail://mailbox.terminal.lightning.today:443",
+	}, {
+		addr: "universerpc://universe.example.com:10029",
+	}, {
+		addr:     "https://mailbox.terminal.lightning.today:443",
+		expected: "unknown proof courier protocol",
+	}, {
+		addr:     "hashmail://mailbox.terminal.lightning.today",
+		expected: "must specify host and port",
+	}, {
+		addr:     "mailbox.terminal.lightning.today",
+		expected: "invalid proof courier URI address",
+	}}
+
+	for _, tc := range testCases {
+		addr, err := ParseCourierAddrString(tc.addr)
+		if tc.expected != "" {
+			require.ErrorContains(t, err, tc.expected, tc.addr)
+			continue
+		}
+
+		require.NoError(t, err, tc.addr)
+		require.Equal(t, tc.addr, addr.String())
+	}
+}
+
+// TestURLDispatch tests that the dispatcher only hands out couriers of the
+// configured types and re-uses them per address.
+func TestURLDispatch(t *testing.T) {
+	t.Parallel()
+
+	dispatch := NewURLDispatch(&CourierCfg{
+		UniverseRpcCfg: &UniverseRpcCourierCfg{
+			BackoffCfg: &BackoffCfg{},
+		},
+		DeliveryLog: newMockDeliveryLog(),
+	})
+
+	uniAddr, err := ParseCourierAddrString("universerpc://localhost:10029")
+	require.NoError(t, err)
+
+	courier, err := dispatch.NewCourier(uniAddr)
+	require.NoError(t, err)
+	require.IsType(t, &UniverseRpcCourier{}, courier)
+
+	sameCourier, err := dispatch.NewCourier(uniAddr)
+	require.NoError(t, err)
+	require.Same(t, courier, sameCourier)
+
+	mailAddr, err := ParseCourierAddrString("hashmail://localhost:443")
+	require.NoError(t, err)
+
+	_, err = dispatch.NewCourier(mailAddr)
+	require.ErrorContains(t, err, "hashmail courier not configured")
+}
+
+// TestUniverseRpcCourier tests that a proof file deposited in a universe by the
+// sender can be re-assembled by the receiver.
+func TestUniverseRpcCourier(t *testing.T) {
+	t.Parallel()
+
+	proofHex, err := os.ReadFile(proofFileHexFileName)
+	require.NoError(t, err)
+	proofBytes, err := hex.DecodeString(
+		strings.Trim(string(proofHex), "\n"),
+	)
+	require.NoError(t, err)
+
+	var proofFile File
+	require.NoError(t, proofFile.Decode(bytes.NewReader(proofBytes)))
+	lastProof, err := proofFile.LastProof()
+	require.NoError(t, err)
+
+	var (
+		ctx     = context.Background()
+		uni     = newMockUniverseClient()
+		backoff = &BackoffCfg{
+			NumTries:       1,
+			InitialBackoff: time.Millisecond,
+			MaxBackoff:     time.Millisecond,
+		}
+		deliveryLog = newMockDeliveryLog()
+		courier     = newUniverseRpcCourier(
+			&UniverseRpcCourierCfg{BackoffCfg: backoff}, uni,
+			deliveryLog,
+		)
+	)
+
+	assetID := lastProof.Asset.ID()
+	outPoint := wire.OutPoint{
+		Hash:  lastProof.AnchorTx.TxHash(),
+		Index: lastProof.InclusionProof.OutputIndex,
+	}
+	loc := Locator{
+		AssetID:   &assetID,
+		ScriptKey: *lastProof.Asset.ScriptKey.PubKey,
+		OutPoint:  &outPoint,
+	}
+	if lastProof.Asset.GroupKey != nil {
+		loc.GroupKey = &lastProof.Asset.GroupKey.GroupPubKey
+	}
+	recipient := Recipient{
+		ScriptKey: lastProof.Asset.ScriptKey.PubKey,
+		AssetID:   assetID,
+		Amount:    lastProof.Asset.Amount,
+	}
+
+	// The receiver keeps polling until the sender deposited the proofs.
+	type result struct {
+		proof *AnnotatedProof
+		err   error
+	}
+	results := make(chan result, 1)
+	go func() {
+		p, err := courier.ReceiveProof(ctx, recipient, loc)
+		results <- result{proof: p, err: err}
+	}()
+
+	err = courier.DeliverProof(ctx, recipient, &AnnotatedProof{
+		Locator: loc,
+		Blob:    proofBytes,
+	})
+	require.NoError(t, err)
+	require.Len(t, uni.leaves, proofFile.NumProofs())
+	require.Len(t, deliveryLog.attempts[loc.Hash()], 1)
+
+	select {
+	case res := <-results:
+		require.NoError(t, res.err)
+		require.Equal(t, Blob(proofBytes), res.proof.Blob)
+
+	case <-time.After(time.Second * 5):
+		t.Fatalf("proof not received")
+	}
+
+	// Without an outpoint, the receiver can't look up the proof.
+	loc.OutPoint = nil
+	_, err = courier.ReceiveProof(ctx, recipient, loc)
+	require.ErrorContains(t, err, "locator outpoint required")
+}
+
+// mockUniverseClient is a universe RPC client that stores the inserted proofs
+// in memory.
+type mockUniverseClient struct {
+	unirpc.UniverseClient
+
+	sync.Mutex
+	leaves map[string][]byte
+}
+
+func newMockUniverseClient() *mockUniverseClient {
+	return &mockUniverseClient{
+		leaves: make(map[string][]byte),
+	}
+}
+
+func mockLeafKey(key *unirpc.UniverseKey) string {
+	return fmt.Sprintf("%x/%x/%s/%x", key.Id.GetAssetId(),
+		key.Id.GetGroupKey(), key.LeafKey.GetOpStr(),
+		key.LeafKey.GetScriptKeyBytes())
+}
+
+func (m *mockUniverseClient) InsertProof(_ context.Context,
+	in *unirpc.AssetProof,
+	_ ...grpc.CallOption) (*unirpc.AssetProofResponse, error) {
+
+	m.Lock()
+	defer m.Unlock()
+
+	m.leaves[mockLeafKey(in.Key)] = in.AssetLeaf.IssuanceProof
+
+	return &unirpc.AssetProofResponse{
+		Req:       in.Key,
+		AssetLeaf: in.AssetLeaf,
+	}, nil
+}
+
+func (m *mockUniverseClient) QueryProof(_ context.Context,
+	in *unirpc.UniverseKey,
+	_ ...grpc.CallOption) (*unirpc.AssetProofResponse, error) {
+
+	m.Lock()
+	defer m.Unlock()
+
+	rawProof, ok := m.leaves[mockLeafKey(in)]
+	if !ok {
+		return nil, fmt.Errorf("no universe proof found")
+	}
+
+	return &unirpc.AssetProofResponse{
+		Req: in,
+		AssetLeaf: &unirpc.AssetLeaf{
+			IssuanceProof: rawProof,
+		},
+	}, nil
+}
+
+// mockDeliveryLog is an in-memory implementation of the DeliveryLog
+// interface.
+type mockDeliveryLog struct {
+	sync.Mutex
+	attempts map[[32]byte][]time.Time
+}
+
+func newMockDeliveryLog() *mockDeliveryLog {
+	return &mockDeliveryLog{
+		attempts: make(map[[32]byte][]time.Time),
+	}
+}
+
+func (m *mockDeliveryLog) StoreProofDeliveryAttempt(_ context.Context,
+	loc Locator) error {
+
+	m.Lock()
+	defer m.Unlock()
+
+	m.attempts[loc.Hash()] = append(m.attempts[loc.Hash()], time.Now())
+
+	return nil
+}
+
+func (m *mockDeliveryLog) QueryProofDeliveryLog(_ context.Context,
+	loc Locator) ([]time.Time, error) {
+
+	m.Lock()
+	defer m.Unlock()
+
+	return m.attempts[loc.Hash()], nil
+}
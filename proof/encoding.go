@@ -19,11 +19,12 @@ func BlockHeaderEncoder(w io.Writer, val any, buf *[8]byte) error {
 func BlockHeaderDecoder(r io.Reader, val any, buf *[8]byte, l uint64) error {
 	if typ, ok := val.(*wire.BlockHeader); ok {
 		var headerBytes []byte
-		if err := tlv.DVarBytes(r, &headerBytes, buf, l); err != nil {
+		err := asset.InlineVarBytesDecoder(r, &headerBytes, buf, l)
+		if err != nil {
 			return err
 		}
 		var header wire.BlockHeader
-		err := header.Deserialize(bytes.NewReader(headerBytes))
+		err = header.Deserialize(bytes.NewReader(headerBytes))
 		if err != nil {
 			return err
 		}
@@ -43,7 +44,8 @@ func TxEncoder(w io.Writer, val any, buf *[8]byte) error {
 func TxDecoder(r io.Reader, val any, buf *[8]byte, l uint64) error {
 	if typ, ok := val.(*wire.MsgTx); ok {
 		var txBytes []byte
-		if err := tlv.DVarBytes(r, &txBytes, buf, l); err != nil {
+		err := asset.InlineVarBytesDecoder(r, &txBytes, buf, l)
+		if err != nil {
 			return err
 		}
 		var tx wire.MsgTx
@@ -66,7 +68,8 @@ func TxMerkleProofEncoder(w io.Writer, val any, buf *[8]byte) error {
 func TxMerkleProofDecoder(r io.Reader, val any, buf *[8]byte, l uint64) error {
 	if typ, ok := val.(*TxMerkleProof); ok {
 		var proofBytes []byte
-		if err := tlv.DVarBytes(r, &proofBytes, buf, l); err != nil {
+		err := asset.InlineVarBytesDecoder(r, &proofBytes, buf, l)
+		if err != nil {
 			return err
 		}
 		var proof TxMerkleProof
@@ -89,7 +92,8 @@ func TaprootProofEncoder(w io.Writer, val any, buf *[8]byte) error {
 func TaprootProofDecoder(r io.Reader, val any, buf *[8]byte, l uint64) error {
 	if typ, ok := val.(*TaprootProof); ok {
 		var proofBytes []byte
-		if err := tlv.DVarBytes(r, &proofBytes, buf, l); err != nil {
+		err := asset.InlineVarBytesDecoder(r, &proofBytes, buf, l)
+		if err != nil {
 			return err
 		}
 		var proof TaprootProof
@@ -112,7 +116,8 @@ func SplitRootProofEncoder(w io.Writer, val any, buf *[8]byte) error {
 func SplitRootProofDecoder(r io.Reader, val any, buf *[8]byte, l uint64) error {
 	if typ, ok := val.(**TaprootProof); ok {
 		var proofBytes []byte
-		if err := tlv.DVarBytes(r, &proofBytes, buf, l); err != nil {
+		err := asset.InlineVarBytesDecoder(r, &proofBytes, buf, l)
+		if err != nil {
 			return err
 		}
 		var proof TaprootProof
@@ -233,7 +238,8 @@ func CommitmentProofEncoder(w io.Writer, val any, buf *[8]byte) error {
 func CommitmentProofDecoder(r io.Reader, val any, buf *[8]byte, l uint64) error {
 	if typ, ok := val.(**CommitmentProof); ok {
 		var proofBytes []byte
-		if err := tlv.DVarBytes(r, &proofBytes, buf, l); err != nil {
+		err := asset.InlineVarBytesDecoder(r, &proofBytes, buf, l)
+		if err != nil {
 			return err
 		}
 		var proof CommitmentProof
@@ -256,7 +262,8 @@ func TapscriptProofEncoder(w io.Writer, val any, buf *[8]byte) error {
 func TapscriptProofDecoder(r io.Reader, val any, buf *[8]byte, l uint64) error {
 	if typ, ok := val.(**TapscriptProof); ok {
 		var proofBytes []byte
-		if err := tlv.DVarBytes(r, &proofBytes, buf, l); err != nil {
+		err := asset.InlineVarBytesDecoder(r, &proofBytes, buf, l)
+		if err != nil {
 			return err
 		}
 		var proof TapscriptProof
@@ -304,11 +311,12 @@ func MetaRevealEncoder(w io.Writer, val any, buf *[8]byte) error {
 func MetaRevealDecoder(r io.Reader, val any, buf *[8]byte, l uint64) error {
 	if typ, ok := val.(**MetaReveal); ok {
 		var revealBytes []byte
-		if err := tlv.DVarBytes(r, &revealBytes, buf, l); err != nil {
+		err := asset.InlineVarBytesDecoder(r, &revealBytes, buf, l)
+		if err != nil {
 			return err
 		}
 		var reveal MetaReveal
-		err := reveal.Decode(bytes.NewReader(revealBytes))
+		err = reveal.Decode(bytes.NewReader(revealBytes))
 		if err != nil {
 			return err
 		}
@@ -336,3 +344,114 @@ func MetaTypeDecoder(r io.Reader, val any, buf *[8]byte, l uint64) error {
 	}
 	return tlv.NewTypeForEncodingErr(val, "MetaType")
 }
+
+func BundleVersionEncoder(w io.Writer, val any, buf *[8]byte) error {
+	if t, ok := val.(*BundleVersion); ok {
+		return tlv.EUint32T(w, uint32(*t), buf)
+	}
+	return tlv.NewTypeForEncodingErr(val, "BundleVersion")
+}
+
+func BundleVersionDecoder(r io.Reader, val any, buf *[8]byte, l uint64) error {
+	if typ, ok := val.(*BundleVersion); ok {
+		var version uint32
+		if err := tlv.DUint32(r, &version, buf, l); err != nil {
+			return err
+		}
+		*typ = BundleVersion(version)
+		return nil
+	}
+	return tlv.NewTypeForDecodingErr(val, "BundleVersion", l, 4)
+}
+
+func BundleManifestEncoder(w io.Writer, val any, buf *[8]byte) error {
+	if t, ok := val.(*[]BundleEntry); ok {
+		numEntries := uint64(len(*t))
+		if err := tlv.WriteVarInt(w, numEntries, buf); err != nil {
+			return err
+		}
+		var entryBuf bytes.Buffer
+		for _, entry := range *t {
+			if err := entry.Encode(&entryBuf); err != nil {
+				return err
+			}
+			entryBytes := entryBuf.Bytes()
+			err := asset.VarBytesEncoder(w, &entryBytes, buf)
+			if err != nil {
+				return err
+			}
+			entryBuf.Reset()
+		}
+		return nil
+	}
+	return tlv.NewTypeForEncodingErr(val, "[]BundleEntry")
+}
+
+func BundleManifestDecoder(r io.Reader, val any, buf *[8]byte, _ uint64) error {
+	if typ, ok := val.(*[]BundleEntry); ok {
+		numEntries, err := tlv.ReadVarInt(r, buf)
+		if err != nil {
+			return err
+		}
+
+		// We don't pre-allocate the manifest based on the untrusted
+		// number of entries, as every entry needs to be read anyway.
+		var entries []BundleEntry
+		for i := uint64(0); i < numEntries; i++ {
+			var entryBytes []byte
+			err := asset.VarBytesDecoder(r, &entryBytes, buf, 0)
+			if err != nil {
+				return err
+			}
+			var entry BundleEntry
+			err = entry.Decode(bytes.NewReader(entryBytes))
+			if err != nil {
+				return err
+			}
+			entries = append(entries, entry)
+		}
+		*typ = entries
+		return nil
+	}
+	return tlv.NewTypeForEncodingErr(val, "[]BundleEntry")
+}
+
+func BundleFilesEncoder(w io.Writer, val any, buf *[8]byte) error {
+	if t, ok := val.(*[]Blob); ok {
+		numFiles := uint64(len(*t))
+		if err := tlv.WriteVarInt(w, numFiles, buf); err != nil {
+			return err
+		}
+		for _, file := range *t {
+			fileBytes := []byte(file)
+			err := asset.VarBytesEncoder(w, &fileBytes, buf)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return tlv.NewTypeForEncodingErr(val, "[]Blob")
+}
+
+func BundleFilesDecoder(r io.Reader, val any, buf *[8]byte, _ uint64) error {
+	if typ, ok := val.(*[]Blob); ok {
+		numFiles, err := tlv.ReadVarInt(r, buf)
+		if err != nil {
+			return err
+		}
+
+		var files []Blob
+		for i := uint64(0); i < numFiles; i++ {
+			var fileBytes []byte
+			err := asset.VarBytesDecoder(r, &fileBytes, buf, 0)
+			if err != nil {
+				return err
+			}
+			files = append(files, fileBytes)
+		}
+		*typ = files
+		return nil
+	}
+	return tlv.NewTypeForEncodingErr(val, "[]Blob")
+}
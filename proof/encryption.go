@@ -0,0 +1,195 @@
+package proof
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightningnetwork/lnd/keychain"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// EncryptionSaltSize is the size of the salt that is used to derive
+	// the encryption key of proof files from a passphrase.
+	EncryptionSaltSize = 32
+
+	// scryptN, scryptR and scryptP are the scrypt parameters used to
+	// derive the encryption key of proof files from a passphrase. These
+	// are the parameters recommended for interactive logins.
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+var (
+	// encryptedBlobMagic is the prefix of proof files that are encrypted
+	// at rest. A plain proof file starts with its big-endian version,
+	// which allows us to tell the two apart.
+	encryptedBlobMagic = [4]byte{'t', 'a', 'p', 'e'}
+
+	// EncryptionKeyLocator is the locator of the wallet key the encryption
+	// key of proof files is derived from. We use a key family of our own,
+	// so the key is never used for anything else.
+	EncryptionKeyLocator = keychain.KeyLocator{
+		Family: asset.TaprootAssetsKeyFamily + 1,
+		Index:  0,
+	}
+
+	// ErrProofEncrypted is returned if a proof file is encrypted at rest,
+	// but no encryption key is known to decrypt it.
+	ErrProofEncrypted = errors.New("proof file is encrypted")
+
+	// ErrProofDecryption is returned if a proof file can't be decrypted,
+	// usually because it was encrypted with a different key.
+	ErrProofDecryption = errors.New("unable to decrypt proof file")
+)
+
+// KeyDeriver is able to derive an arbitrary key of the wallet.
+type KeyDeriver interface {
+	// DeriveKey attempts to derive an arbitrary key specified by the
+	// passed KeyLocator.
+	DeriveKey(context.Context,
+		keychain.KeyLocator) (keychain.KeyDescriptor, error)
+}
+
+// Encrypter encrypts and decrypts proof files that are stored at rest with a
+// symmetric key, using XChaCha20-Poly1305. An encrypted proof file is made up
+// of a magic prefix, a random nonce and the cipher text, which also
+// authenticates the prefix and the nonce.
+type Encrypter struct {
+	key [chacha20poly1305.KeySize]byte
+}
+
+// NewEncrypter creates a new encrypter for the given encryption key.
+func NewEncrypter(key [chacha20poly1305.KeySize]byte) *Encrypter {
+	return &Encrypter{
+		key: key,
+	}
+}
+
+// NewKeyRingEncrypter creates a new encrypter with a key that is derived from
+// the wallet seed. Similar to lnd's static channel backups, the encryption key
+// is the SHA256 hash of the public key found at EncryptionKeyLocator, so the
+// key ring doesn't need to be able to export private keys.
+func NewKeyRingEncrypter(ctx context.Context,
+	keyRing KeyDeriver) (*Encrypter, error) {
+
+	baseKey, err := keyRing.DeriveKey(ctx, EncryptionKeyLocator)
+	if err != nil {
+		return nil, fmt.Errorf("unable to derive base encryption key: "+
+			"%w", err)
+	}
+
+	return NewEncrypter(
+		sha256.Sum256(baseKey.PubKey.SerializeCompressed()),
+	), nil
+}
+
+// NewPassphraseEncrypter creates a new encrypter with a key that is derived
+// from the given passphrase and the salt stored at the given path. If no salt
+// is stored yet, then a new random one is created.
+func NewPassphraseEncrypter(passphrase []byte,
+	saltPath string) (*Encrypter, error) {
+
+	if len(passphrase) == 0 {
+		return nil, fmt.Errorf("passphrase must not be empty")
+	}
+
+	salt, err := os.ReadFile(saltPath)
+	switch {
+	case os.IsNotExist(err):
+		salt = make([]byte, EncryptionSaltSize)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, err
+		}
+
+		if err := os.WriteFile(saltPath, salt, 0600); err != nil {
+			return nil, fmt.Errorf("unable to store encryption "+
+				"salt: %w", err)
+		}
+
+	case err != nil:
+		return nil, fmt.Errorf("unable to read encryption salt: %w",
+			err)
+
+	case len(salt) != EncryptionSaltSize:
+		return nil, fmt.Errorf("invalid encryption salt size %d",
+			len(salt))
+	}
+
+	rawKey, err := scrypt.Key(
+		passphrase, salt, scryptN, scryptR, scryptP,
+		chacha20poly1305.KeySize,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var key [chacha20poly1305.KeySize]byte
+	copy(key[:], rawKey)
+
+	return NewEncrypter(key), nil
+}
+
+// IsEncrypted returns true if the given proof file is encrypted at rest.
+func IsEncrypted(blob Blob) bool {
+	return bytes.HasPrefix(blob, encryptedBlobMagic[:])
+}
+
+// Encrypt encrypts the given proof file.
+func (e *Encrypter) Encrypt(blob Blob) (Blob, error) {
+	cipher, err := chacha20poly1305.NewX(e.key[:])
+	if err != nil {
+		return nil, err
+	}
+
+	headerSize := len(encryptedBlobMagic) + chacha20poly1305.NonceSizeX
+	header := make(
+		[]byte, headerSize, headerSize+len(blob)+cipher.Overhead(),
+	)
+	copy(header, encryptedBlobMagic[:])
+
+	nonce := header[len(encryptedBlobMagic):]
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	// The cipher text is appended to the header, which is also used as
+	// the associated data.
+	return cipher.Seal(header, nonce, blob, header), nil
+}
+
+// Decrypt decrypts the given proof file. Proof files that aren't encrypted are
+// returned as is, so archives can be migrated gradually.
+func (e *Encrypter) Decrypt(blob Blob) (Blob, error) {
+	if !IsEncrypted(blob) {
+		return blob, nil
+	}
+
+	cipher, err := chacha20poly1305.NewX(e.key[:])
+	if err != nil {
+		return nil, err
+	}
+
+	headerSize := len(encryptedBlobMagic) + chacha20poly1305.NonceSizeX
+	if len(blob) < headerSize+cipher.Overhead() {
+		return nil, fmt.Errorf("%w: encrypted proof file too short",
+			ErrProofDecryption)
+	}
+
+	header := blob[:headerSize]
+	nonce := header[len(encryptedBlobMagic):]
+	plainText, err := cipher.Open(nil, nonce, blob[headerSize:], header)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrProofDecryption, err)
+	}
+
+	return plainText, nil
+}
@@ -0,0 +1,192 @@
+package proof
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lightninglabs/taproot-assets/internal/test"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/stretchr/testify/require"
+)
+
+// mockKeyDeriver is a KeyDeriver that returns the same key for every locator.
+type mockKeyDeriver struct {
+	keyDesc keychain.KeyDescriptor
+}
+
+func (m *mockKeyDeriver) DeriveKey(_ context.Context,
+	loc keychain.KeyLocator) (keychain.KeyDescriptor, error) {
+
+	keyDesc := m.keyDesc
+	keyDesc.KeyLocator = loc
+
+	return keyDesc, nil
+}
+
+// TestEncrypter tests that proof files can be encrypted and decrypted with the
+// keys of the different encrypters.
+func TestEncrypter(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	blob := test.RandBytes(1000)
+
+	keyRing := &mockKeyDeriver{
+		keyDesc: keychain.KeyDescriptor{
+			PubKey: test.RandPubKey(t),
+		},
+	}
+	keyRingEncrypter, err := NewKeyRingEncrypter(ctx, keyRing)
+	require.NoError(t, err)
+
+	encrypted, err := keyRingEncrypter.Encrypt(blob)
+	require.NoError(t, err)
+	require.True(t, IsEncrypted(encrypted))
+	require.False(t, IsEncrypted(blob))
+
+	decrypted, err := keyRingEncrypter.Decrypt(encrypted)
+	require.NoError(t, err)
+	require.Equal(t, Blob(blob), decrypted)
+
+	// Encrypting the same proof file twice uses a different nonce.
+	encryptedAgain, err := keyRingEncrypter.Encrypt(blob)
+	require.NoError(t, err)
+	require.NotEqual(t, encrypted, encryptedAgain)
+
+	// Proof files that aren't encrypted are passed through.
+	decrypted, err = keyRingEncrypter.Decrypt(blob)
+	require.NoError(t, err)
+	require.Equal(t, Blob(blob), decrypted)
+
+	// Proof files that were tampered with or truncated are rejected.
+	tampered := append(Blob{}, encrypted...)
+	tampered[len(tampered)-1] ^= 1
+	_, err = keyRingEncrypter.Decrypt(tampered)
+	require.ErrorIs(t, err, ErrProofDecryption)
+
+	_, err = keyRingEncrypter.Decrypt(encrypted[:len(encryptedBlobMagic)])
+	require.ErrorIs(t, err, ErrProofDecryption)
+
+	// A passphrase derives the same key as long as the salt is the same.
+	saltPath := filepath.Join(t.TempDir(), "salt")
+	passphraseEncrypter, err := NewPassphraseEncrypter(
+		[]byte("passphrase"), saltPath,
+	)
+	require.NoError(t, err)
+	require.FileExists(t, saltPath)
+
+	encrypted, err = passphraseEncrypter.Encrypt(blob)
+	require.NoError(t, err)
+
+	_, err = keyRingEncrypter.Decrypt(encrypted)
+	require.ErrorIs(t, err, ErrProofDecryption)
+
+	passphraseEncrypter, err = NewPassphraseEncrypter(
+		[]byte("passphrase"), saltPath,
+	)
+	require.NoError(t, err)
+
+	decrypted, err = passphraseEncrypter.Decrypt(encrypted)
+	require.NoError(t, err)
+	require.Equal(t, Blob(blob), decrypted)
+
+	wrongEncrypter, err := NewPassphraseEncrypter(
+		[]byte("wrong passphrase"), saltPath,
+	)
+	require.NoError(t, err)
+
+	_, err = wrongEncrypter.Decrypt(encrypted)
+	require.ErrorIs(t, err, ErrProofDecryption)
+
+	_, err = NewPassphraseEncrypter(nil, saltPath)
+	require.Error(t, err)
+}
+
+// TestFileArchiverEncryption tests that the file archiver encrypts the proof
+// files it stores, and that plain text archives can be migrated.
+func TestFileArchiverEncryption(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	var key [32]byte
+	copy(key[:], test.RandBytes(32))
+	encrypter := NewEncrypter(key)
+
+	// We first store a proof in plain text, as an archive without
+	// encryption would.
+	plainArchive, err := NewFileArchiver(dir)
+	require.NoError(t, err)
+
+	plainProof := &AnnotatedProof{
+		Locator: Locator{
+			AssetID:   randAssetID(t),
+			ScriptKey: *test.RandPubKey(t),
+		},
+		Blob: test.RandBytes(100),
+	}
+	err = plainArchive.ImportProofs(ctx, MockHeaderVerifier, plainProof)
+	require.NoError(t, err)
+
+	// Once encryption is enabled, new proofs are encrypted on disk, while
+	// the plain text proof can still be fetched.
+	encryptedArchive, err := NewFileArchiver(dir, WithEncrypter(encrypter))
+	require.NoError(t, err)
+
+	encryptedProof := &AnnotatedProof{
+		Locator: Locator{
+			AssetID:   randAssetID(t),
+			ScriptKey: *test.RandPubKey(t),
+		},
+		Blob: test.RandBytes(100),
+	}
+	err = encryptedArchive.ImportProofs(
+		ctx, MockHeaderVerifier, encryptedProof,
+	)
+	require.NoError(t, err)
+
+	readRaw := func(loc Locator) Blob {
+		filePath, err := genProofFilePath(
+			encryptedArchive.proofPath, loc,
+		)
+		require.NoError(t, err)
+
+		blob, err := os.ReadFile(filePath)
+		require.NoError(t, err)
+
+		return blob
+	}
+	require.True(t, IsEncrypted(readRaw(encryptedProof.Locator)))
+	require.False(t, IsEncrypted(readRaw(plainProof.Locator)))
+
+	for _, p := range []*AnnotatedProof{plainProof, encryptedProof} {
+		blob, err := encryptedArchive.FetchProof(ctx, p.Locator)
+		require.NoError(t, err)
+		require.Equal(t, p.Blob, blob)
+	}
+
+	// Without the key, encrypted proofs can't be fetched.
+	_, err = plainArchive.FetchProof(ctx, encryptedProof.Locator)
+	require.ErrorIs(t, err, ErrProofEncrypted)
+
+	// The migration only encrypts the plain text proof, and running it
+	// again is a no-op.
+	_, err = plainArchive.EncryptProofs()
+	require.Error(t, err)
+
+	numEncrypted, err := encryptedArchive.EncryptProofs()
+	require.NoError(t, err)
+	require.Equal(t, 1, numEncrypted)
+	require.True(t, IsEncrypted(readRaw(plainProof.Locator)))
+
+	numEncrypted, err = encryptedArchive.EncryptProofs()
+	require.NoError(t, err)
+	require.Zero(t, numEncrypted)
+
+	blob, err := encryptedArchive.FetchProof(ctx, plainProof.Locator)
+	require.NoError(t, err)
+	require.Equal(t, plainProof.Blob, blob)
+}
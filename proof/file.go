@@ -97,97 +97,141 @@ func NewFile(v Version, proofs ...Proof) (*File, error) {
 
 // Encode encodes the proof file into `w` including its checksum.
 func (f *File) Encode(w io.Writer) error {
-	err := binary.Write(w, binary.BigEndian, uint32(f.Version))
+	var tlvBuf [8]byte
+	err := writeFileHeader(w, f.Version, uint64(len(f.proofs)), &tlvBuf)
 	if err != nil {
 		return err
 	}
 
+	for _, proof := range f.proofs {
+		if err := writeHashedProof(w, proof, &tlvBuf); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Decode decodes a proof file from `r`.
+func (f *File) Decode(r io.Reader) error {
 	var tlvBuf [8]byte
-	if err := tlv.WriteVarInt(w, uint64(len(f.proofs)), &tlvBuf); err != nil {
+	version, numProofs, err := readFileHeader(r, &tlvBuf)
+	if err != nil {
 		return err
 	}
-	for _, proof := range f.proofs {
-		proof := proof
-
-		// To the file we write the proof, followed by its hash, which
-		// is SHA256(prev_hash || proof). That way if we serially read
-		// the whole file using the zero hash as the first prev_hash,
-		// then we can make sure we have no data corruption if the
-		// serially built hash is equal to the last proof's hash. On the
-		// other hand, if we want to append a proof to a file, we just
-		// need to read the last proof, use its hash as the prev_hash
-		// for the one to append, and we're done.
-		err := tlv.WriteVarInt(w, uint64(len(proof.proofBytes)), &tlvBuf)
+	f.Version = version
+
+	// We don't pre-allocate the list of proofs based on the untrusted
+	// number of proofs, as every proof needs to be read from the file
+	// anyway.
+	var prevHash [sha256.Size]byte
+	f.proofs = nil
+	for i := uint64(0); i < numProofs; i++ {
+		proof, err := readHashedProof(r, prevHash, &tlvBuf)
 		if err != nil {
 			return err
 		}
-		if _, err := w.Write(proof.proofBytes); err != nil {
-			return err
-		}
 
-		// The hash is not part of the proof's TLV stream, so we didn't
-		// count it above.
-		if _, err := w.Write(proof.hash[:]); err != nil {
-			return err
-		}
+		f.proofs = append(f.proofs, proof)
+		prevHash = proof.hash
 	}
 
 	return nil
 }
 
-// Decode decodes a proof file from `r`.
-func (f *File) Decode(r io.Reader) error {
+// writeFileHeader writes the header of a proof file, which consists of its
+// version and the number of proofs it contains.
+func writeFileHeader(w io.Writer, version Version, numProofs uint64,
+	tlvBuf *[8]byte) error {
+
+	err := binary.Write(w, binary.BigEndian, uint32(version))
+	if err != nil {
+		return err
+	}
+
+	return tlv.WriteVarInt(w, numProofs, tlvBuf)
+}
+
+// readFileHeader reads the header of a proof file, returning its version and
+// the number of proofs it claims to contain.
+func readFileHeader(r io.Reader, tlvBuf *[8]byte) (Version, uint64, error) {
 	var version uint32
 	if err := binary.Read(r, binary.BigEndian, &version); err != nil {
-		return err
+		return 0, 0, err
 	}
-	f.Version = Version(version)
 
-	var tlvBuf [8]byte
-	numProofs, err := tlv.ReadVarInt(r, &tlvBuf)
+	numProofs, err := tlv.ReadVarInt(r, tlvBuf)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return Version(version), numProofs, nil
+}
+
+// writeHashedProof writes a single proof of a proof file, followed by its
+// chained checksum.
+func writeHashedProof(w io.Writer, proof *hashedProof,
+	tlvBuf *[8]byte) error {
+
+	// To the file we write the proof, followed by its hash, which is
+	// SHA256(prev_hash || proof). That way if we serially read the whole
+	// file using the zero hash as the first prev_hash, then we can make
+	// sure we have no data corruption if the serially built hash is equal
+	// to the last proof's hash. On the other hand, if we want to append a
+	// proof to a file, we just need to read the last proof, use its hash
+	// as the prev_hash for the one to append, and we're done.
+	err := tlv.WriteVarInt(w, uint64(len(proof.proofBytes)), tlvBuf)
 	if err != nil {
 		return err
 	}
+	if _, err := w.Write(proof.proofBytes); err != nil {
+		return err
+	}
 
-	var prevHash, currentHash, proofHash [sha256.Size]byte
-	f.proofs = make([]*hashedProof, numProofs)
-	for i := uint64(0); i < numProofs; i++ {
-		// We need to find out how many bytes we expect for the proof,
-		// so we can limit the TLV reader.
-		numProofBytes, err := tlv.ReadVarInt(r, &tlvBuf)
-		if err != nil {
-			return err
-		}
+	// The hash is not part of the proof's TLV stream, so we didn't count
+	// it above.
+	_, err = w.Write(proof.hash[:])
+	return err
+}
 
-		// Read all bytes that belong to the proof. We don't decode the
-		// proof itself as we usually only need the last proof anyway.
-		proofBytes := make([]byte, numProofBytes)
-		if _, err := io.ReadFull(r, proofBytes); err != nil {
-			return err
-		}
+// readHashedProof reads a single proof of a proof file and makes sure its
+// chained checksum matches the one computed from the given previous hash.
+func readHashedProof(r io.Reader, prevHash [sha256.Size]byte,
+	tlvBuf *[8]byte) (*hashedProof, error) {
 
-		// We now read the proof's hash in the file which reflects the
-		// current checksum.
-		if _, err := io.ReadFull(r, proofHash[:]); err != nil {
-			return err
-		}
+	// We need to find out how many bytes we expect for the proof, so we
+	// can limit the TLV reader.
+	numProofBytes, err := tlv.ReadVarInt(r, tlvBuf)
+	if err != nil {
+		return nil, err
+	}
 
-		// Now that we have read both the proof and the expected
-		// checksum of it, we calculate our own checksum and verify they
-		// match.
-		currentHash = hashProof(proofBytes, prevHash)
-		if proofHash != currentHash {
-			return ErrInvalidChecksum
-		}
+	// Read all bytes that belong to the proof. We don't decode the proof
+	// itself as we usually only need the last proof anyway.
+	var proofBytes []byte
+	err = asset.InlineVarBytesDecoder(r, &proofBytes, tlvBuf, numProofBytes)
+	if err != nil {
+		return nil, err
+	}
 
-		f.proofs[i] = &hashedProof{
-			proofBytes: proofBytes,
-			hash:       currentHash,
-		}
-		prevHash = currentHash
+	// We now read the proof's hash in the file which reflects the current
+	// checksum.
+	var proofHash [sha256.Size]byte
+	if _, err := io.ReadFull(r, proofHash[:]); err != nil {
+		return nil, err
 	}
 
-	return nil
+	// Now that we have read both the proof and the expected checksum of
+	// it, we calculate our own checksum and verify they match.
+	currentHash := hashProof(proofBytes, prevHash)
+	if proofHash != currentHash {
+		return nil, ErrInvalidChecksum
+	}
+
+	return &hashedProof{
+		proofBytes: proofBytes,
+		hash:       currentHash,
+	}, nil
 }
 
 // IsEmpty returns true if the file does not contain any proofs.
@@ -368,6 +412,11 @@ type AssetSnapshot struct {
 	// Taproot Asset root is the only tapscript leaf in the tree.
 	TapscriptSibling *commitment.TapscriptPreimage
 
+	// AltLeaves is the set of alt leaves committed to in the same Taproot
+	// Asset commitment as the asset. These are required to re-create the
+	// full Taproot Asset commitment of the anchor output.
+	AltLeaves []*commitment.AltLeaf
+
 	// SplitAsset is the optional indicator that the asset in the snapshot
 	// resulted from splitting an asset. If this is true then the root asset
 	// of the split can be found in the asset witness' split commitment.
@@ -377,4 +426,8 @@ type AssetSnapshot struct {
 	// asset. This is only populated if the asset is a genesis asset, and
 	// the proof had a valid meta reveal.
 	MetaReveal *MetaReveal
+
+	// IsBurn is true if the asset in the snapshot was burned by assigning
+	// it the canonical, provably un-spendable burn script key.
+	IsBurn bool
 }
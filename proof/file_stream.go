@@ -0,0 +1,318 @@
+package proof
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// FileReader reads the proofs of an encoded proof file one by one, verifying
+// the chained checksum of each proof along the way. In contrast to decoding a
+// whole File, only the proof that is currently being read is kept in memory,
+// which bounds the memory use for assets with a long transfer history.
+type FileReader struct {
+	r io.Reader
+
+	version   Version
+	numProofs uint64
+
+	// numRead is the number of proofs read so far.
+	numRead uint64
+
+	// lastHash is the chained checksum of the last proof read, or the
+	// zero hash if no proof was read yet.
+	lastHash [sha256.Size]byte
+
+	tlvBuf [8]byte
+}
+
+// NewFileReader creates a new FileReader that reads the proof file from the
+// given reader. The header of the file is read right away.
+func NewFileReader(r io.Reader) (*FileReader, error) {
+	f := &FileReader{
+		r: r,
+	}
+
+	var err error
+	f.version, f.numProofs, err = readFileHeader(r, &f.tlvBuf)
+	if err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// Version returns the version of the proof file.
+func (f *FileReader) Version() Version {
+	return f.version
+}
+
+// NumProofs returns the number of proofs the proof file claims to contain.
+func (f *FileReader) NumProofs() uint64 {
+	return f.numProofs
+}
+
+// LastHash returns the chained checksum of the last proof read, which is the
+// zero hash if no proof was read yet.
+func (f *FileReader) LastHash() [sha256.Size]byte {
+	return f.lastHash
+}
+
+// NextRaw reads the next proof of the file and returns it as a byte slice.
+// Once all proofs were read, io.EOF is returned.
+func (f *FileReader) NextRaw() ([]byte, error) {
+	if f.numRead == f.numProofs {
+		return nil, io.EOF
+	}
+
+	proof, err := readHashedProof(f.r, f.lastHash, &f.tlvBuf)
+	switch {
+	// The file claims to contain more proofs than it does.
+	case errors.Is(err, io.EOF):
+		return nil, io.ErrUnexpectedEOF
+
+	case err != nil:
+		return nil, err
+	}
+
+	f.numRead++
+	f.lastHash = proof.hash
+
+	return proof.proofBytes, nil
+}
+
+// Next reads and decodes the next proof of the file. Once all proofs were
+// read, io.EOF is returned.
+func (f *FileReader) Next() (*Proof, error) {
+	proofBytes, err := f.NextRaw()
+	if err != nil {
+		return nil, err
+	}
+
+	var proof Proof
+	if err := proof.Decode(bytes.NewReader(proofBytes)); err != nil {
+		return nil, fmt.Errorf("error decoding proof: %w", err)
+	}
+
+	return &proof, nil
+}
+
+// Verify reads and verifies all proofs of the file, returning the snapshot of
+// the last proof. As every proof is verified against the snapshot of the
+// previous one, no proof must have been read before.
+func (f *FileReader) Verify(ctx context.Context,
+	headerVerifier HeaderVerifier) (*AssetSnapshot, error) {
+
+	if f.numRead != 0 {
+		return nil, fmt.Errorf("cannot verify file after reading %d "+
+			"proofs", f.numRead)
+	}
+
+	var (
+		prev     *AssetSnapshot
+		branches = newBranchCache()
+	)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+
+		proof, err := f.Next()
+		switch {
+		case errors.Is(err, io.EOF):
+			return prev, nil
+
+		case err != nil:
+			return nil, err
+		}
+
+		prev, err = proof.verify(
+			ctx, prev, headerVerifier, nil, branches,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// countingReader is an io.Reader that counts the number of bytes read.
+type countingReader struct {
+	r         io.Reader
+	bytesRead int64
+}
+
+// Read reads from the underlying reader.
+//
+// NOTE: This implements the io.Reader interface.
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.bytesRead += int64(n)
+
+	return n, err
+}
+
+// AppendToFileOnDisk verifies the given proof against the proof file stored
+// at the given path and appends it to the file. The file is streamed from
+// disk and verified in full along the way, so the memory use doesn't depend
+// on the number of proofs in the file. Unless the encoding of the number of
+// proofs grows, the proof is appended in place and only the header of the
+// file is rewritten. Any data following the last proof of the file, like the
+// remains of an interrupted append, is discarded. Proof files that are
+// encrypted at rest aren't supported.
+func AppendToFileOnDisk(ctx context.Context, filePath string, proof Proof,
+	headerVerifier HeaderVerifier) error {
+
+	file, err := os.OpenFile(filePath, os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	// Files that are encrypted at rest can't be appended to in place.
+	buffered := bufio.NewReader(file)
+	magic, err := buffered.Peek(len(encryptedBlobMagic))
+	if err == nil && IsEncrypted(magic) {
+		return ErrProofEncrypted
+	}
+
+	// The counting reader sits on top of the buffered reader, so it only
+	// counts the bytes actually consumed by the file reader.
+	counter := &countingReader{r: buffered}
+	fileReader, err := NewFileReader(counter)
+	if err != nil {
+		return fmt.Errorf("error reading proof file header: %w", err)
+	}
+	headerSize := counter.bytesRead
+
+	prev, err := fileReader.Verify(ctx, headerVerifier)
+	if err != nil {
+		return fmt.Errorf("error verifying proof file: %w", err)
+	}
+	if _, err := proof.Verify(ctx, prev, headerVerifier); err != nil {
+		return fmt.Errorf("error verifying proof: %w", err)
+	}
+
+	proofBytes, err := encodeProof(&proof)
+	if err != nil {
+		return err
+	}
+
+	return appendHashedProof(
+		file, fileReader.Version(), fileReader.NumProofs(), headerSize,
+		counter.bytesRead, &hashedProof{
+			proofBytes: proofBytes,
+			hash: hashProof(
+				proofBytes, fileReader.LastHash(),
+			),
+		},
+	)
+}
+
+// appendHashedProof appends the given proof to a proof file that contains the
+// given number of proofs, of which the header ends at headerSize and the last
+// proof ends at proofsEnd.
+func appendHashedProof(file *os.File, version Version, numProofs uint64,
+	headerSize, proofsEnd int64, proof *hashedProof) error {
+
+	var (
+		tlvBuf [8]byte
+		header bytes.Buffer
+		entry  bytes.Buffer
+	)
+	err := writeFileHeader(&header, version, numProofs+1, &tlvBuf)
+	if err != nil {
+		return err
+	}
+	if err := writeHashedProof(&entry, proof, &tlvBuf); err != nil {
+		return err
+	}
+
+	// If the new header is larger than the existing one, then all proofs
+	// need to be moved, so we write a new file instead.
+	if int64(header.Len()) != headerSize {
+		return rewriteFile(
+			file, header.Bytes(), headerSize, proofsEnd,
+			entry.Bytes(),
+		)
+	}
+
+	// We first append the new proof and only then update the number of
+	// proofs in the header, so an interrupted append leaves a file that
+	// still decodes to the original proofs.
+	if err := file.Truncate(proofsEnd); err != nil {
+		return err
+	}
+	if _, err := file.WriteAt(entry.Bytes(), proofsEnd); err != nil {
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		return err
+	}
+
+	if _, err := file.WriteAt(header.Bytes(), 0); err != nil {
+		return err
+	}
+
+	return file.Sync()
+}
+
+// rewriteFile writes a new proof file with the given header, the proofs of the
+// given file that are found between headerSize and proofsEnd and the given
+// entry of a new proof, and atomically replaces the given file with it.
+func rewriteFile(file *os.File, header []byte, headerSize, proofsEnd int64,
+	entry []byte) error {
+
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	tempFile, err := os.CreateTemp(
+		filepath.Dir(file.Name()), filepath.Base(file.Name())+".*",
+	)
+	if err != nil {
+		return err
+	}
+
+	// We make sure the temporary file doesn't stay around if anything
+	// goes wrong. Once it was renamed, removing it is a no-op.
+	defer func() {
+		_ = tempFile.Close()
+		_ = os.Remove(tempFile.Name())
+	}()
+
+	proofs := io.NewSectionReader(file, headerSize, proofsEnd-headerSize)
+	w := bufio.NewWriter(tempFile)
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	if _, err := io.Copy(w, proofs); err != nil {
+		return err
+	}
+	if _, err := w.Write(entry); err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if err := tempFile.Chmod(fileInfo.Mode().Perm()); err != nil {
+		return err
+	}
+	if err := tempFile.Sync(); err != nil {
+		return err
+	}
+	if err := tempFile.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tempFile.Name(), file.Name())
+}
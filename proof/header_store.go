@@ -0,0 +1,330 @@
+package proof
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// ErrInsufficientWork is returned if a branch of headers is connected to the
+// header store that doesn't have more work than the current best chain.
+var ErrInsufficientWork = errors.New("branch doesn't have more work than " +
+	"best chain")
+
+// HeaderStore is a compact, in-memory header chain. It starts at a trusted
+// base header, usually the genesis block or a checkpoint, and only keeps the
+// headers of the best chain. Every header that is connected is checked for a
+// valid proof of work, for the difficulty required by the consensus rules and
+// against the checkpoints of the chain. If a branch with more work is
+// connected, the store reorgs to it.
+type HeaderStore struct {
+	params *chaincfg.Params
+
+	// blocksPerRetarget is the number of blocks between two difficulty
+	// adjustments.
+	blocksPerRetarget uint32
+
+	mtx sync.RWMutex
+
+	// baseHeight is the height of the base header.
+	baseHeight uint32
+
+	// headers are the headers of the best chain, starting at the base
+	// header.
+	headers []wire.BlockHeader
+
+	// totalWork is the cumulative work of the best chain, starting at the
+	// base header, for each of the headers.
+	totalWork []*big.Int
+
+	// heights maps the hash of every header of the best chain to its
+	// height.
+	heights map[chainhash.Hash]uint32
+}
+
+// A compile-time assertion to ensure HeaderStore satisfies the HeaderChain
+// interface.
+var _ HeaderChain = (*HeaderStore)(nil)
+
+// NewHeaderStore creates a new header store for the given chain that starts at
+// the given base header. As all headers are checked against the base header,
+// it must be trusted, so it should either be the genesis block or a checkpoint
+// of the chain.
+func NewHeaderStore(params *chaincfg.Params, baseHeight uint32,
+	baseHeader wire.BlockHeader) (*HeaderStore, error) {
+
+	if err := CheckProofOfWork(baseHeader, params.PowLimit); err != nil {
+		return nil, err
+	}
+
+	baseHash := baseHeader.BlockHash()
+	if baseHeight == 0 && baseHash != *params.GenesisHash {
+		return nil, fmt.Errorf("%w: base header %v at height 0 isn't "+
+			"the genesis block", ErrCheckpointMismatch, baseHash)
+	}
+	if err := checkCheckpoint(params, baseHeight, baseHash); err != nil {
+		return nil, err
+	}
+
+	return &HeaderStore{
+		params: params,
+		blocksPerRetarget: uint32(
+			params.TargetTimespan / params.TargetTimePerBlock,
+		),
+		baseHeight: baseHeight,
+		headers:    []wire.BlockHeader{baseHeader},
+		totalWork:  []*big.Int{blockchain.CalcWork(baseHeader.Bits)},
+		heights: map[chainhash.Hash]uint32{
+			baseHash: baseHeight,
+		},
+	}, nil
+}
+
+// BestBlock returns the hash and height of the tip of the best chain.
+func (s *HeaderStore) BestBlock() (chainhash.Hash, uint32) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	tip := len(s.headers) - 1
+	return s.headers[tip].BlockHash(), s.baseHeight + uint32(tip)
+}
+
+// BlockHeader returns the header of the block with the given hash, along with
+// its height. ErrUnknownBlockHeader is returned if the block isn't part of
+// the best chain.
+//
+// NOTE: This is part of the HeaderChain interface.
+func (s *HeaderStore) BlockHeader(_ context.Context,
+	hash chainhash.Hash) (*wire.BlockHeader, uint32, error) {
+
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	height, ok := s.heights[hash]
+	if !ok {
+		return nil, 0, fmt.Errorf("%w: %v", ErrUnknownBlockHeader, hash)
+	}
+
+	header := s.headers[height-s.baseHeight]
+	return &header, height, nil
+}
+
+// BlockHash returns the hash of the block at the given height of the best
+// chain.
+//
+// NOTE: This is part of the HeaderChain interface.
+func (s *HeaderStore) BlockHash(_ context.Context,
+	height uint32) (chainhash.Hash, error) {
+
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	if height < s.baseHeight ||
+		height-s.baseHeight >= uint32(len(s.headers)) {
+
+		return chainhash.Hash{}, fmt.Errorf("%w: no block at height %d",
+			ErrUnknownBlockHeader, height)
+	}
+
+	return s.headers[height-s.baseHeight].BlockHash(), nil
+}
+
+// ConnectHeaders validates the given chain of headers and connects it to the
+// store. The first header must build on a header of the best chain. If the
+// headers don't extend the tip, they form a new branch that's only switched to
+// if it has more work than the current best chain, in which case the headers
+// after the fork point are disconnected.
+func (s *HeaderStore) ConnectHeaders(headers ...wire.BlockHeader) error {
+	if len(headers) == 0 {
+		return nil
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	forkHeight, ok := s.heights[headers[0].PrevBlock]
+	if !ok {
+		return fmt.Errorf("%w: previous block %v of header %v",
+			ErrUnknownBlockHeader, headers[0].PrevBlock,
+			headers[0].BlockHash())
+	}
+
+	// Headers that are already part of the best chain are skipped, so
+	// connecting overlapping ranges of headers is fine.
+	for len(headers) > 0 {
+		height, ok := s.heights[headers[0].BlockHash()]
+		if !ok || height != forkHeight+1 {
+			break
+		}
+
+		forkHeight++
+		headers = headers[1:]
+	}
+	if len(headers) == 0 {
+		return nil
+	}
+
+	// headerAt returns the header at the given height of the branch that
+	// is being connected, or nil if it's below the base header.
+	headerAt := func(height uint32) *wire.BlockHeader {
+		switch {
+		case height > forkHeight:
+			return &headers[height-forkHeight-1]
+
+		case height < s.baseHeight:
+			return nil
+
+		default:
+			return &s.headers[height-s.baseHeight]
+		}
+	}
+
+	branchWork := make([]*big.Int, len(headers))
+	work := s.totalWork[forkHeight-s.baseHeight]
+	for i := range headers {
+		header := &headers[i]
+		height := forkHeight + 1 + uint32(i)
+
+		prevHash := headerAt(height - 1).BlockHash()
+		if header.PrevBlock != prevHash {
+			return fmt.Errorf("header %v at height %d doesn't "+
+				"connect to previous header %v",
+				header.BlockHash(), height, prevHash)
+		}
+
+		if err := s.checkHeader(header, height, headerAt); err != nil {
+			return fmt.Errorf("invalid header %v at height %d: %w",
+				header.BlockHash(), height, err)
+		}
+
+		work = new(big.Int).Add(work, blockchain.CalcWork(header.Bits))
+		branchWork[i] = work
+	}
+
+	tip := len(s.headers) - 1
+	if work.Cmp(s.totalWork[tip]) <= 0 {
+		return fmt.Errorf("%w: branch forking at height %d",
+			ErrInsufficientWork, forkHeight)
+	}
+
+	// Any header of the best chain after the fork point is reorged out.
+	keep := forkHeight - s.baseHeight + 1
+	if numStale := uint32(len(s.headers)) - keep; numStale > 0 {
+		log.Infof("Header chain reorg at height %d, disconnecting %d "+
+			"blocks", forkHeight, numStale)
+	}
+	for _, header := range s.headers[keep:] {
+		delete(s.heights, header.BlockHash())
+	}
+	s.headers = s.headers[:keep]
+	s.totalWork = s.totalWork[:keep]
+
+	for i, header := range headers {
+		s.heights[header.BlockHash()] = forkHeight + 1 + uint32(i)
+	}
+	s.headers = append(s.headers, headers...)
+	s.totalWork = append(s.totalWork, branchWork...)
+
+	return nil
+}
+
+// checkHeader checks the proof of work and difficulty of the given header at
+// the given height, as well as the checkpoints of the chain. The headerAt
+// function returns the preceding headers of the branch the header is part of.
+func (s *HeaderStore) checkHeader(header *wire.BlockHeader, height uint32,
+	headerAt func(uint32) *wire.BlockHeader) error {
+
+	if err := CheckProofOfWork(*header, s.params.PowLimit); err != nil {
+		return err
+	}
+
+	bits, ok := s.requiredBits(header, height, headerAt)
+	if ok && header.Bits != bits {
+		return fmt.Errorf("%w: bits %08x, expected %08x",
+			ErrUnexpectedDifficulty, header.Bits, bits)
+	}
+
+	return checkCheckpoint(s.params, height, header.BlockHash())
+}
+
+// requiredBits returns the difficulty the given header at the given height
+// must commit to. If the headers needed to determine the difficulty precede
+// the base header, false is returned.
+func (s *HeaderStore) requiredBits(header *wire.BlockHeader, height uint32,
+	headerAt func(uint32) *wire.BlockHeader) (uint32, bool) {
+
+	params := s.params
+	prev := headerAt(height - 1)
+
+	if height%s.blocksPerRetarget != 0 {
+		if !params.ReduceMinDifficulty {
+			return prev.Bits, true
+		}
+
+		// Test networks allow a block with the minimum difficulty if
+		// no block was found for a while.
+		allowMinTime := prev.Timestamp.Add(params.MinDiffReductionTime)
+		if header.Timestamp.After(allowMinTime) {
+			return params.PowLimitBits, true
+		}
+
+		// Otherwise, the difficulty of the last block that didn't
+		// make use of that rule applies.
+		for h := height - 1; ; h-- {
+			ancestor := headerAt(h)
+			if ancestor == nil {
+				return 0, false
+			}
+
+			if h%s.blocksPerRetarget == 0 ||
+				ancestor.Bits != params.PowLimitBits {
+
+				return ancestor.Bits, true
+			}
+		}
+	}
+
+	// The regression test network shares its magic with testnet3 and
+	// never adjusts its difficulty.
+	if params.Net == wire.TestNet {
+		return prev.Bits, true
+	}
+
+	first := headerAt(height - s.blocksPerRetarget)
+	if first == nil {
+		return 0, false
+	}
+
+	// The adjustment of the difficulty is limited, in either direction, to
+	// the adjustment factor of the chain.
+	targetTimespan := int64(params.TargetTimespan / time.Second)
+	minTimespan := targetTimespan / params.RetargetAdjustmentFactor
+	maxTimespan := targetTimespan * params.RetargetAdjustmentFactor
+
+	timespan := prev.Timestamp.Unix() - first.Timestamp.Unix()
+	switch {
+	case timespan < minTimespan:
+		timespan = minTimespan
+
+	case timespan > maxTimespan:
+		timespan = maxTimespan
+	}
+
+	target := new(big.Int).Mul(
+		blockchain.CompactToBig(prev.Bits), big.NewInt(timespan),
+	)
+	target.Div(target, big.NewInt(targetTimespan))
+	if target.Cmp(params.PowLimit) > 0 {
+		target.Set(params.PowLimit)
+	}
+
+	return blockchain.BigToCompact(target), true
+}
@@ -0,0 +1,249 @@
+package proof
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+// testHeaderParams returns chain parameters with the minimum difficulty of the
+// regression test network, but with regular difficulty adjustments every ten
+// blocks.
+func testHeaderParams() *chaincfg.Params {
+	params := chaincfg.RegressionNetParams
+	params.Net = wire.MainNet
+	params.ReduceMinDifficulty = false
+	params.TargetTimePerBlock = 10 * time.Minute
+	params.TargetTimespan = 10 * params.TargetTimePerBlock
+	params.Checkpoints = nil
+
+	return &params
+}
+
+// mineHeader mines a header with the given difficulty on top of the given
+// header. The tag is used to create distinct branches.
+func mineHeader(t *testing.T, prev *wire.BlockHeader, interval time.Duration,
+	bits uint32, tag byte) wire.BlockHeader {
+
+	header := wire.BlockHeader{
+		Version:    4,
+		PrevBlock:  prev.BlockHash(),
+		MerkleRoot: chainhash.Hash{tag},
+		Timestamp:  prev.Timestamp.Add(interval),
+		Bits:       bits,
+	}
+
+	powLimit := testHeaderParams().PowLimit
+	for CheckProofOfWork(header, powLimit) != nil {
+		header.Nonce++
+		require.NotZero(t, header.Nonce)
+	}
+
+	return header
+}
+
+// mineHeaders mines a chain of headers with the given difficulty on top of the
+// given header.
+func mineHeaders(t *testing.T, prev wire.BlockHeader, num int, bits uint32,
+	tag byte) []wire.BlockHeader {
+
+	headers := make([]wire.BlockHeader, num)
+	for i := range headers {
+		headers[i] = mineHeader(t, &prev, time.Minute, bits, tag)
+		prev = headers[i]
+	}
+
+	return headers
+}
+
+// TestHeaderStore tests that the header store enforces the difficulty and
+// checkpoints of the chain, and switches to branches with more work.
+func TestHeaderStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	params := testHeaderParams()
+	genesis := params.GenesisBlock.Header
+
+	store, err := NewHeaderStore(params, 0, genesis)
+	require.NoError(t, err)
+
+	// A base header at height zero must be the genesis block.
+	_, err = NewHeaderStore(
+		params, 0, mineHeader(t, &genesis, 0, genesis.Bits, 0),
+	)
+	require.ErrorIs(t, err, ErrCheckpointMismatch)
+
+	// Up until the first retarget, the difficulty can't change.
+	chain := mineHeaders(t, genesis, 9, genesis.Bits, 0)
+	require.NoError(t, store.ConnectHeaders(chain...))
+
+	tipHash, tipHeight := store.BestBlock()
+	require.Equal(t, chain[8].BlockHash(), tipHash)
+	require.EqualValues(t, 9, tipHeight)
+
+	// The blocks were mined ten times faster than targeted, so the
+	// difficulty must go up by the maximum adjustment factor at the
+	// retarget. Neither the old nor any other difficulty is accepted.
+	easy := mineHeader(t, &chain[8], time.Minute, genesis.Bits, 0)
+	err = store.ConnectHeaders(easy)
+	require.ErrorIs(t, err, ErrUnexpectedDifficulty)
+
+	harder := mineHeader(t, &chain[8], time.Minute, 0x1f7fffff, 0)
+	err = store.ConnectHeaders(harder)
+	require.ErrorIs(t, err, ErrUnexpectedDifficulty)
+
+	retarget := mineHeader(t, &chain[8], time.Minute, 0x201fffff, 0)
+	require.NoError(t, store.ConnectHeaders(retarget))
+	chain = append(chain, retarget)
+
+	// Headers with an invalid proof of work are rejected.
+	invalid := mineHeader(t, &retarget, time.Minute, retarget.Bits, 0)
+	for CheckProofOfWork(invalid, params.PowLimit) == nil {
+		invalid.Nonce++
+	}
+	err = store.ConnectHeaders(invalid)
+	require.ErrorIs(t, err, ErrInvalidProofOfWork)
+
+	// Headers that don't build on the best chain are rejected.
+	orphan := mineHeader(t, &invalid, time.Minute, retarget.Bits, 0)
+	err = store.ConnectHeaders(orphan)
+	require.ErrorIs(t, err, ErrUnknownBlockHeader)
+
+	// Connecting known headers again is a no-op.
+	require.NoError(t, store.ConnectHeaders(chain[5:]...))
+	tipHash, _ = store.BestBlock()
+	require.Equal(t, retarget.BlockHash(), tipHash)
+
+	// A branch from height five with the same amount of work as the best
+	// chain isn't switched to.
+	branch := mineHeaders(t, chain[4], 4, genesis.Bits, 1)
+	branch = append(branch, mineHeader(
+		t, &branch[3], time.Minute, retarget.Bits, 1,
+	))
+	err = store.ConnectHeaders(branch...)
+	require.ErrorIs(t, err, ErrInsufficientWork)
+
+	// Once the branch has more work, the store reorgs to it.
+	branch = append(branch, mineHeader(
+		t, &branch[4], time.Minute, retarget.Bits, 1,
+	))
+	require.NoError(t, store.ConnectHeaders(branch...))
+
+	tipHash, tipHeight = store.BestBlock()
+	require.Equal(t, branch[5].BlockHash(), tipHash)
+	require.EqualValues(t, 11, tipHeight)
+
+	_, _, err = store.BlockHeader(ctx, chain[7].BlockHash())
+	require.ErrorIs(t, err, ErrUnknownBlockHeader)
+
+	header, height, err := store.BlockHeader(ctx, branch[2].BlockHash())
+	require.NoError(t, err)
+	require.Equal(t, branch[2], *header)
+	require.EqualValues(t, 8, height)
+
+	hash, err := store.BlockHash(ctx, 4)
+	require.NoError(t, err)
+	require.Equal(t, chain[3].BlockHash(), hash)
+
+	_, err = store.BlockHash(ctx, 12)
+	require.ErrorIs(t, err, ErrUnknownBlockHeader)
+
+	// Headers that conflict with a checkpoint are rejected.
+	checkpointParams := *params
+	checkpointParams.Checkpoints = []chaincfg.Checkpoint{{
+		Height: 1,
+		Hash:   &chainhash.Hash{1},
+	}}
+	store, err = NewHeaderStore(&checkpointParams, 0, genesis)
+	require.NoError(t, err)
+
+	err = store.ConnectHeaders(chain[0])
+	require.ErrorIs(t, err, ErrCheckpointMismatch)
+}
+
+// TestHeaderChainVerifier tests that the header chain verifier only accepts
+// headers of the best chain.
+func TestHeaderChainVerifier(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	params := testHeaderParams()
+	genesis := params.GenesisBlock.Header
+
+	store, err := NewHeaderStore(params, 0, genesis)
+	require.NoError(t, err)
+
+	chain := mineHeaders(t, genesis, 3, genesis.Bits, 0)
+	require.NoError(t, store.ConnectHeaders(chain...))
+
+	verifier := NewHeaderChainVerifier(ctx, store, params)
+	require.NoError(t, verifier(genesis))
+	for _, header := range chain {
+		require.NoError(t, verifier(header))
+	}
+
+	// An unknown header is rejected, even with a valid proof of work.
+	unknown := mineHeader(t, &chain[2], time.Minute, genesis.Bits, 1)
+	require.ErrorIs(t, verifier(unknown), ErrUnknownBlockHeader)
+
+	// So is a header with an invalid proof of work.
+	invalid := chain[2]
+	for CheckProofOfWork(invalid, params.PowLimit) == nil {
+		invalid.Nonce++
+	}
+	require.ErrorIs(t, verifier(invalid), ErrInvalidProofOfWork)
+
+	// Once a block is reorged out, it's rejected.
+	branch := mineHeaders(t, chain[0], 3, genesis.Bits, 1)
+	require.NoError(t, store.ConnectHeaders(branch...))
+
+	require.NoError(t, verifier(chain[0]))
+	require.ErrorIs(t, verifier(chain[1]), ErrUnknownBlockHeader)
+	require.NoError(t, verifier(branch[2]))
+
+	// A header chain that knows of a block that's not in its best chain
+	// causes it to be rejected.
+	staleChain := &staleHeaderChain{
+		HeaderChain: store,
+		stale:       chain[1],
+		height:      2,
+	}
+	verifier = NewHeaderChainVerifier(ctx, staleChain, params)
+	require.ErrorIs(t, verifier(chain[1]), ErrBlockNotInBestChain)
+
+	// Blocks that conflict with a checkpoint are rejected.
+	checkpointParams := *params
+	checkpointParams.Checkpoints = []chaincfg.Checkpoint{{
+		Height: 1,
+		Hash:   &chainhash.Hash{1},
+	}}
+	verifier = NewHeaderChainVerifier(ctx, store, &checkpointParams)
+	require.ErrorIs(t, verifier(chain[0]), ErrCheckpointMismatch)
+}
+
+// staleHeaderChain is a header chain that also knows of a single stale block
+// that isn't part of its best chain.
+type staleHeaderChain struct {
+	HeaderChain
+
+	stale  wire.BlockHeader
+	height uint32
+}
+
+// BlockHeader returns the header of the block with the given hash, along with
+// its height.
+func (s *staleHeaderChain) BlockHeader(ctx context.Context,
+	hash chainhash.Hash) (*wire.BlockHeader, uint32, error) {
+
+	if hash == s.stale.BlockHash() {
+		return &s.stale, s.height, nil
+	}
+
+	return s.HeaderChain.BlockHeader(ctx, hash)
+}
@@ -0,0 +1,144 @@
+package proof
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+var (
+	// ErrInvalidProofOfWork is returned if the hash of a block header
+	// doesn't satisfy the target the header claims, or if that target is
+	// out of range.
+	ErrInvalidProofOfWork = errors.New("block header has invalid proof " +
+		"of work")
+
+	// ErrUnexpectedDifficulty is returned if a block header doesn't commit
+	// to the difficulty required by the consensus rules.
+	ErrUnexpectedDifficulty = errors.New("block header has unexpected " +
+		"difficulty")
+
+	// ErrUnknownBlockHeader is returned if a block header isn't known to
+	// the header chain.
+	ErrUnknownBlockHeader = errors.New("block header not found in " +
+		"header chain")
+
+	// ErrBlockNotInBestChain is returned if a block header is known to the
+	// header chain, but isn't part of its best chain (anymore), usually
+	// because of a reorg.
+	ErrBlockNotInBestChain = errors.New("block is not part of the best " +
+		"chain")
+
+	// ErrCheckpointMismatch is returned if a block header conflicts with
+	// a checkpoint of the chain parameters.
+	ErrCheckpointMismatch = errors.New("block header conflicts with " +
+		"checkpoint")
+)
+
+// HeaderChain is a source of block headers that is used to verify that a
+// block header is part of the best chain.
+type HeaderChain interface {
+	// BlockHeader returns the header of the block with the given hash,
+	// along with its height. ErrUnknownBlockHeader is returned if the
+	// block isn't known.
+	BlockHeader(ctx context.Context,
+		hash chainhash.Hash) (*wire.BlockHeader, uint32, error)
+
+	// BlockHash returns the hash of the block at the given height of the
+	// best chain.
+	BlockHash(ctx context.Context, height uint32) (chainhash.Hash, error)
+}
+
+// CheckProofOfWork makes sure the target the given block header commits to is
+// within the given limit, and that the hash of the header satisfies it.
+func CheckProofOfWork(header wire.BlockHeader, powLimit *big.Int) error {
+	target := blockchain.CompactToBig(header.Bits)
+	if target.Sign() <= 0 {
+		return fmt.Errorf("%w: target %064x is not positive",
+			ErrInvalidProofOfWork, target)
+	}
+	if target.Cmp(powLimit) > 0 {
+		return fmt.Errorf("%w: target %064x is higher than limit %064x",
+			ErrInvalidProofOfWork, target, powLimit)
+	}
+
+	hash := header.BlockHash()
+	if blockchain.HashToBig(&hash).Cmp(target) > 0 {
+		return fmt.Errorf("%w: hash %v is higher than target %064x",
+			ErrInvalidProofOfWork, hash, target)
+	}
+
+	return nil
+}
+
+// checkCheckpoint makes sure the block with the given hash and height doesn't
+// conflict with any of the checkpoints of the given chain parameters.
+func checkCheckpoint(params *chaincfg.Params, height uint32,
+	hash chainhash.Hash) error {
+
+	for _, checkpoint := range params.Checkpoints {
+		if checkpoint.Height != int32(height) {
+			continue
+		}
+
+		if *checkpoint.Hash != hash {
+			return fmt.Errorf("%w: block %v at height %d, "+
+				"expected %v", ErrCheckpointMismatch, hash,
+				height, checkpoint.Hash)
+		}
+	}
+
+	return nil
+}
+
+// NewHeaderChainVerifier returns a HeaderVerifier that, instead of trusting
+// the block header of a proof, makes sure that the header:
+//
+//  1. Has a valid proof of work for the given chain parameters.
+//  2. Is part of the given header chain, with the exact same content.
+//  3. Is part of the best chain of the header chain, so blocks that were
+//     reorged out are rejected.
+//  4. Doesn't conflict with any checkpoint of the given chain parameters.
+func NewHeaderChainVerifier(ctx context.Context, chain HeaderChain,
+	params *chaincfg.Params) HeaderVerifier {
+
+	return func(header wire.BlockHeader) error {
+		err := CheckProofOfWork(header, params.PowLimit)
+		if err != nil {
+			return err
+		}
+
+		hash := header.BlockHash()
+		knownHeader, height, err := chain.BlockHeader(ctx, hash)
+		if err != nil {
+			return fmt.Errorf("unable to fetch block header %v: %w",
+				hash, err)
+		}
+
+		// The header chain might be backed by a remote source, so we
+		// don't take the content of the header for granted.
+		if knownHeader.BlockHash() != hash {
+			return fmt.Errorf("header chain returned block %v "+
+				"for hash %v", knownHeader.BlockHash(), hash)
+		}
+
+		bestHash, err := chain.BlockHash(ctx, height)
+		if err != nil {
+			return fmt.Errorf("unable to fetch block hash at "+
+				"height %d: %w", height, err)
+		}
+		if bestHash != hash {
+			return fmt.Errorf("%w: block %v at height %d, best "+
+				"chain has %v", ErrBlockNotInBestChain, hash,
+				height, bestHash)
+		}
+
+		return checkCheckpoint(params, height, hash)
+	}
+}
@@ -3,6 +3,9 @@ package proof
 import (
 	"bytes"
 	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 
 	"github.com/lightninglabs/taproot-assets/asset"
@@ -16,6 +19,30 @@ const (
 	// MetaOpaque signals that the meta data is simply a set of opaque
 	// bytes without any specific interpretation.
 	MetaOpaque MetaType = 1
+
+	// MetaGroupUpdate signals that the meta data is an encoded MetaUpdate
+	// that replaces the display metadata of the asset group the asset is
+	// issued into.
+	MetaGroupUpdate MetaType = 2
+
+	// MetaJSON signals that the meta data is a JSON object. Well known
+	// keys of the object, such as the decimal display of the asset, are
+	// interpreted by wallets when displaying the asset.
+	MetaJSON MetaType = 3
+)
+
+const (
+	// MetaDecimalDisplayKey is the key of the JSON meta data that holds
+	// the number of decimal places amounts of the asset are displayed
+	// with.
+	MetaDecimalDisplayKey = "decimal_display"
+)
+
+var (
+	// ErrDecimalDisplayNotJSON is returned if a decimal display is set for
+	// an asset with meta data that isn't a JSON object.
+	ErrDecimalDisplayNotJSON = errors.New("decimal display requires JSON " +
+		"meta data")
 )
 
 // MetaReveals is an optional TLV type that can be added to the proof of a
@@ -40,6 +67,88 @@ func (m *MetaReveal) MetaHash() [asset.MetaHashLen]byte {
 	return sha256.Sum256(b.Bytes())
 }
 
+// DecimalDisplay returns the decimal display of the asset the meta data
+// belongs to. Assets without JSON meta data, or without a decimal display in
+// their JSON meta data, are displayed in base units, so zero is returned.
+func (m *MetaReveal) DecimalDisplay() (uint32, error) {
+	if m == nil || m.Type != MetaJSON {
+		return 0, nil
+	}
+
+	var meta map[string]json.RawMessage
+	if err := json.Unmarshal(m.Data, &meta); err != nil {
+		return 0, fmt.Errorf("invalid JSON meta data: %w", err)
+	}
+
+	rawDecimalDisplay, ok := meta[MetaDecimalDisplayKey]
+	if !ok {
+		return 0, nil
+	}
+
+	var decimalDisplay uint32
+	err := json.Unmarshal(rawDecimalDisplay, &decimalDisplay)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", MetaDecimalDisplayKey,
+			err)
+	}
+
+	if decimalDisplay > asset.MaxDecimalDisplay {
+		return 0, asset.ErrInvalidDecimalDisplay
+	}
+
+	return decimalDisplay, nil
+}
+
+// SetDecimalDisplay sets the decimal display of the asset the meta data
+// belongs to. JSON meta data without a decimal display is updated to carry
+// the given decimal display, while a conflicting decimal display that's
+// already set results in an error.
+func (m *MetaReveal) SetDecimalDisplay(decimalDisplay uint32) error {
+	if decimalDisplay > asset.MaxDecimalDisplay {
+		return asset.ErrInvalidDecimalDisplay
+	}
+
+	if m.Type != MetaJSON {
+		return ErrDecimalDisplayNotJSON
+	}
+
+	meta := make(map[string]json.RawMessage)
+	if len(m.Data) != 0 {
+		if err := json.Unmarshal(m.Data, &meta); err != nil {
+			return fmt.Errorf("invalid JSON meta data: %w", err)
+		}
+	}
+
+	if _, ok := meta[MetaDecimalDisplayKey]; ok {
+		currentDisplay, err := m.DecimalDisplay()
+		if err != nil {
+			return err
+		}
+
+		if currentDisplay != decimalDisplay {
+			return fmt.Errorf("meta data already has %s of %d",
+				MetaDecimalDisplayKey, currentDisplay)
+		}
+
+		return nil
+	}
+
+	rawDecimalDisplay, err := json.Marshal(decimalDisplay)
+	if err != nil {
+		return err
+	}
+	meta[MetaDecimalDisplayKey] = rawDecimalDisplay
+
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+
+	m.Data = data
+
+	return nil
+}
+
 // EncodeRecords returns the TLV encode records for the meta reveal.
 func (m *MetaReveal) EncodeRecords() []tlv.Record {
 	return []tlv.Record{
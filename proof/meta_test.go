@@ -0,0 +1,158 @@
+package proof
+
+import (
+	"testing"
+
+	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMetaDecimalDisplay tests that the decimal display of an asset is read
+// from its JSON meta data.
+func TestMetaDecimalDisplay(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		meta           *MetaReveal
+		decimalDisplay uint32
+		err            error
+		errContains    string
+	}{{
+		name: "no meta",
+	}, {
+		name: "opaque meta",
+		meta: &MetaReveal{
+			Type: MetaOpaque,
+			Data: []byte(`{"decimal_display": 2}`),
+		},
+	}, {
+		name: "json meta without decimal display",
+		meta: &MetaReveal{
+			Type: MetaJSON,
+			Data: []byte(`{"name": "foo"}`),
+		},
+	}, {
+		name: "json meta with decimal display",
+		meta: &MetaReveal{
+			Type: MetaJSON,
+			Data: []byte(`{"name": "foo", "decimal_display": 6}`),
+		},
+		decimalDisplay: 6,
+	}, {
+		name: "invalid json",
+		meta: &MetaReveal{
+			Type: MetaJSON,
+			Data: []byte(`{"decimal_display": `),
+		},
+		errContains: "invalid JSON meta data",
+	}, {
+		name: "negative decimal display",
+		meta: &MetaReveal{
+			Type: MetaJSON,
+			Data: []byte(`{"decimal_display": -1}`),
+		},
+		errContains: "invalid decimal_display",
+	}, {
+		name: "decimal display too large",
+		meta: &MetaReveal{
+			Type: MetaJSON,
+			Data: []byte(`{"decimal_display": 19}`),
+		},
+		err: asset.ErrInvalidDecimalDisplay,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			decimalDisplay, err := tc.meta.DecimalDisplay()
+			switch {
+			case tc.err != nil:
+				require.ErrorIs(t, err, tc.err)
+
+			case tc.errContains != "":
+				require.ErrorContains(t, err, tc.errContains)
+
+			default:
+				require.NoError(t, err)
+				require.Equal(t, tc.decimalDisplay, decimalDisplay)
+			}
+		})
+	}
+}
+
+// TestMetaSetDecimalDisplay tests that the decimal display of an asset can be
+// added to its JSON meta data.
+func TestMetaSetDecimalDisplay(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		meta        *MetaReveal
+		err         error
+		errContains string
+	}{{
+		name: "empty json meta",
+		meta: &MetaReveal{
+			Type: MetaJSON,
+		},
+	}, {
+		name: "json meta without decimal display",
+		meta: &MetaReveal{
+			Type: MetaJSON,
+			Data: []byte(`{"name": "foo"}`),
+		},
+	}, {
+		name: "json meta with same decimal display",
+		meta: &MetaReveal{
+			Type: MetaJSON,
+			Data: []byte(`{"decimal_display": 2}`),
+		},
+	}, {
+		name: "json meta with other decimal display",
+		meta: &MetaReveal{
+			Type: MetaJSON,
+			Data: []byte(`{"decimal_display": 3}`),
+		},
+		errContains: "already has decimal_display",
+	}, {
+		name: "opaque meta",
+		meta: &MetaReveal{
+			Type: MetaOpaque,
+			Data: []byte("foo"),
+		},
+		err: ErrDecimalDisplayNotJSON,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tc.meta.SetDecimalDisplay(2)
+			switch {
+			case tc.err != nil:
+				require.ErrorIs(t, err, tc.err)
+
+			case tc.errContains != "":
+				require.ErrorContains(t, err, tc.errContains)
+
+			default:
+				require.NoError(t, err)
+
+				decimalDisplay, err := tc.meta.DecimalDisplay()
+				require.NoError(t, err)
+				require.EqualValues(t, 2, decimalDisplay)
+			}
+		})
+	}
+
+	// A decimal display that can't be represented is rejected.
+	meta := &MetaReveal{Type: MetaJSON}
+	err := meta.SetDecimalDisplay(asset.MaxDecimalDisplay + 1)
+	require.ErrorIs(t, err, asset.ErrInvalidDecimalDisplay)
+}
@@ -0,0 +1,292 @@
+package proof
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+var (
+	// ErrInvalidMetaUpdate is returned if a meta reveal of type
+	// MetaGroupUpdate doesn't contain a valid metadata update.
+	ErrInvalidMetaUpdate = errors.New("invalid metadata update")
+
+	// ErrMetaUpdateNotGrouped is returned if a metadata update is revealed
+	// for an asset that isn't part of an asset group.
+	ErrMetaUpdateNotGrouped = errors.New("metadata update requires an " +
+		"asset group")
+
+	// ErrMetaUpdateConflict is returned if the history of an asset group
+	// contains two different metadata updates with the same sequence
+	// number.
+	ErrMetaUpdateConflict = errors.New("conflicting metadata updates")
+
+	// ErrMetaUpdateBrokenChain is returned if a metadata update doesn't
+	// commit to the update that precedes it.
+	ErrMetaUpdateBrokenChain = errors.New("metadata update doesn't " +
+		"extend previous update")
+)
+
+// MetaUpdate is a metadata update for an asset group. It is carried as the
+// meta reveal (of type MetaGroupUpdate) of a new tranche issued into the
+// group. As the asset ID of the tranche commits to the meta hash and the
+// group key signs the asset ID, each update is authorized by the group key.
+//
+// The updates of a group form a hash chain: each update commits to the meta
+// hash of the tranche that carried the previous update, which makes the full
+// history verifiable from the issuance proofs of the group.
+type MetaUpdate struct {
+	// Sequence is the position of this update in the history of the
+	// group's metadata, starting at 1.
+	Sequence uint32
+
+	// PrevHash is the meta hash of the tranche that carried the previous
+	// update. This is all zeroes for the first update.
+	PrevHash [asset.MetaHashLen]byte
+
+	// Meta is the new display metadata of the asset group.
+	Meta *MetaReveal
+}
+
+// Validate checks that the metadata update is well formed.
+func (u *MetaUpdate) Validate() error {
+	var zeroHash [asset.MetaHashLen]byte
+	switch {
+	case u.Sequence == 0:
+		return fmt.Errorf("%w: sequence must be positive",
+			ErrInvalidMetaUpdate)
+
+	case u.Sequence == 1 && u.PrevHash != zeroHash:
+		return fmt.Errorf("%w: first update can't have previous hash",
+			ErrInvalidMetaUpdate)
+
+	case u.Sequence > 1 && u.PrevHash == zeroHash:
+		return fmt.Errorf("%w: missing previous hash",
+			ErrInvalidMetaUpdate)
+
+	case u.Meta == nil:
+		return fmt.Errorf("%w: missing metadata", ErrInvalidMetaUpdate)
+
+	// Updates can't be nested, the new metadata must be final.
+	case u.Meta.Type == MetaGroupUpdate:
+		return fmt.Errorf("%w: nested update", ErrInvalidMetaUpdate)
+	}
+
+	return nil
+}
+
+// MetaReveal returns the meta reveal that carries the metadata update as part
+// of a new tranche of the asset group.
+func (u *MetaUpdate) MetaReveal() (*MetaReveal, error) {
+	if err := u.Validate(); err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	if err := u.Encode(&b); err != nil {
+		return nil, err
+	}
+
+	return &MetaReveal{
+		Type: MetaGroupUpdate,
+		Data: b.Bytes(),
+	}, nil
+}
+
+// EncodeRecords returns the TLV encode records for the metadata update.
+func (u *MetaUpdate) EncodeRecords() []tlv.Record {
+	return []tlv.Record{
+		MetaUpdateSequenceRecord(&u.Sequence),
+		MetaUpdatePrevHashRecord(&u.PrevHash),
+		MetaUpdateMetaRecord(&u.Meta),
+	}
+}
+
+// DecodeRecords returns the TLV decode records for the metadata update.
+func (u *MetaUpdate) DecodeRecords() []tlv.Record {
+	return []tlv.Record{
+		MetaUpdateSequenceRecord(&u.Sequence),
+		MetaUpdatePrevHashRecord(&u.PrevHash),
+		MetaUpdateMetaRecord(&u.Meta),
+	}
+}
+
+// Encode encodes the metadata update to the given writer.
+func (u *MetaUpdate) Encode(w io.Writer) error {
+	stream, err := tlv.NewStream(u.EncodeRecords()...)
+	if err != nil {
+		return err
+	}
+	return stream.Encode(w)
+}
+
+// Decode decodes the metadata update from the given reader.
+func (u *MetaUpdate) Decode(r io.Reader) error {
+	stream, err := tlv.NewStream(u.DecodeRecords()...)
+	if err != nil {
+		return err
+	}
+	return stream.Decode(r)
+}
+
+// MetaUpdate decodes and validates the metadata update carried by the meta
+// reveal.
+func (m *MetaReveal) MetaUpdate() (*MetaUpdate, error) {
+	if m.Type != MetaGroupUpdate {
+		return nil, fmt.Errorf("%w: meta type %d is not an update",
+			ErrInvalidMetaUpdate, m.Type)
+	}
+
+	var update MetaUpdate
+	if err := update.Decode(bytes.NewReader(m.Data)); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidMetaUpdate, err)
+	}
+	if err := update.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &update, nil
+}
+
+// MetaTranche is a single issuance of an asset group along with the meta
+// reveal of its genesis.
+type MetaTranche struct {
+	// Genesis is the genesis of the tranche.
+	Genesis asset.Genesis
+
+	// GroupKey is the group key of the tranche, including the signature or
+	// witness that authorized the issuance.
+	GroupKey *asset.GroupKey
+
+	// MetaReveal is the revealed metadata of the tranche, if any.
+	MetaReveal *MetaReveal
+}
+
+// MetaHistoryEntry is a single verified metadata update of an asset group.
+type MetaHistoryEntry struct {
+	// AssetID is the ID of the tranche that carried the update.
+	AssetID asset.ID
+
+	// Update is the metadata update itself.
+	Update *MetaUpdate
+}
+
+// MetaHistory is the verified history of metadata updates of an asset group,
+// ordered by sequence number.
+type MetaHistory struct {
+	// GroupKey is the key of the asset group.
+	GroupKey *btcec.PublicKey
+
+	// Updates is the ordered list of metadata updates.
+	Updates []MetaHistoryEntry
+}
+
+// Canonical returns the metadata wallets should display for the asset group,
+// which is the metadata of the latest update. If the group was never updated,
+// nil is returned and the metadata of each tranche should be used as is.
+func (h *MetaHistory) Canonical() *MetaReveal {
+	if len(h.Updates) == 0 {
+		return nil
+	}
+
+	return h.Updates[len(h.Updates)-1].Update.Meta
+}
+
+// NewMetaHistory verifies the metadata updates carried by the given tranches
+// of an asset group and returns the resulting history. Tranches without an
+// update are only checked for a valid group signature and meta reveal. An
+// error is returned if any update isn't authorized by the group key or if the
+// updates don't form a single unbroken chain.
+func NewMetaHistory(groupKey *btcec.PublicKey,
+	tranches []*MetaTranche) (*MetaHistory, error) {
+
+	var (
+		groupSigs asset.GroupSigBatch
+		updates   []MetaHistoryEntry
+		metaHash  = make(map[uint32][asset.MetaHashLen]byte)
+	)
+	for _, tranche := range tranches {
+		switch {
+		case tranche.GroupKey == nil:
+			return nil, ErrMetaUpdateNotGrouped
+
+		case !tranche.GroupKey.GroupPubKey.IsEqual(groupKey):
+			return nil, fmt.Errorf("tranche %v has group key %x",
+				tranche.Genesis.ID(), tranche.GroupKey.
+					GroupPubKey.SerializeCompressed())
+		}
+		groupSigs.Add(tranche.Genesis, tranche.GroupKey)
+
+		if tranche.MetaReveal == nil {
+			continue
+		}
+
+		revealHash := tranche.MetaReveal.MetaHash()
+		if revealHash != tranche.Genesis.MetaHash {
+			return nil, fmt.Errorf("%w: %x vs %x",
+				ErrMetaRevealMismatch, revealHash[:],
+				tranche.Genesis.MetaHash[:])
+		}
+
+		if tranche.MetaReveal.Type != MetaGroupUpdate {
+			continue
+		}
+
+		update, err := tranche.MetaReveal.MetaUpdate()
+		if err != nil {
+			return nil, err
+		}
+
+		// The same update may be carried by more than one asset, for
+		// example if it's re-issued in multiple outputs. But two
+		// different updates with the same sequence mean the history
+		// has been forked.
+		if prevHash, ok := metaHash[update.Sequence]; ok {
+			if prevHash != revealHash {
+				return nil, fmt.Errorf("%w: sequence %d",
+					ErrMetaUpdateConflict, update.Sequence)
+			}
+
+			continue
+		}
+		metaHash[update.Sequence] = revealHash
+
+		updates = append(updates, MetaHistoryEntry{
+			AssetID: tranche.Genesis.ID(),
+			Update:  update,
+		})
+	}
+
+	if err := groupSigs.Verify(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(updates, func(i, j int) bool {
+		return updates[i].Update.Sequence < updates[j].Update.Sequence
+	})
+
+	// Finally, each update must directly follow its predecessor.
+	for i, entry := range updates {
+		update := entry.Update
+		if update.Sequence != uint32(i+1) {
+			return nil, fmt.Errorf("%w: missing update with "+
+				"sequence %d", ErrMetaUpdateBrokenChain, i+1)
+		}
+
+		if i > 0 && update.PrevHash != metaHash[update.Sequence-1] {
+			return nil, fmt.Errorf("%w: sequence %d",
+				ErrMetaUpdateBrokenChain, update.Sequence)
+		}
+	}
+
+	return &MetaHistory{
+		GroupKey: groupKey,
+		Updates:  updates,
+	}, nil
+}
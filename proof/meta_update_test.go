@@ -0,0 +1,220 @@
+package proof
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightninglabs/taproot-assets/internal/test"
+	"github.com/stretchr/testify/require"
+)
+
+// metaUpdateTranche creates a new tranche of the given group that carries the
+// passed meta reveal.
+func metaUpdateTranche(t *testing.T, group *asset.AssetGroup,
+	groupPriv []byte, reveal *MetaReveal) *MetaTranche {
+
+	gen := asset.RandGenesis(t, group.Genesis.Type)
+	gen.MetaHash = reveal.MetaHash()
+
+	privKey, _ := btcec.PrivKeyFromBytes(groupPriv)
+	groupKey, err := asset.DeriveGroupKey(
+		asset.NewRawKeyGenesisSigner(privKey), group.GroupKey.RawKey,
+		*group.Genesis, &gen,
+	)
+	require.NoError(t, err)
+
+	return &MetaTranche{
+		Genesis:    gen,
+		GroupKey:   groupKey,
+		MetaReveal: reveal,
+	}
+}
+
+// TestMetaUpdateEncoding tests that metadata updates survive an encoding round
+// trip through a meta reveal and that malformed updates are rejected.
+func TestMetaUpdateEncoding(t *testing.T) {
+	t.Parallel()
+
+	update := &MetaUpdate{
+		Sequence: 2,
+		PrevHash: [asset.MetaHashLen]byte{1, 2, 3},
+		Meta: &MetaReveal{
+			Type: MetaOpaque,
+			Data: []byte("new metadata"),
+		},
+	}
+
+	reveal, err := update.MetaReveal()
+	require.NoError(t, err)
+	require.Equal(t, MetaGroupUpdate, reveal.Type)
+
+	decoded, err := reveal.MetaUpdate()
+	require.NoError(t, err)
+	require.Equal(t, update, decoded)
+
+	// The meta reveal itself must also survive an encoding round trip.
+	var b bytes.Buffer
+	require.NoError(t, reveal.Encode(&b))
+
+	var decodedReveal MetaReveal
+	require.NoError(t, decodedReveal.Decode(&b))
+	require.Equal(t, reveal, &decodedReveal)
+
+	testCases := []struct {
+		name   string
+		update MetaUpdate
+	}{{
+		name: "zero sequence",
+		update: MetaUpdate{
+			Meta: update.Meta,
+		},
+	}, {
+		name: "first update with previous hash",
+		update: MetaUpdate{
+			Sequence: 1,
+			PrevHash: update.PrevHash,
+			Meta:     update.Meta,
+		},
+	}, {
+		name: "missing previous hash",
+		update: MetaUpdate{
+			Sequence: 3,
+			Meta:     update.Meta,
+		},
+	}, {
+		name: "missing meta",
+		update: MetaUpdate{
+			Sequence: 1,
+		},
+	}, {
+		name: "nested update",
+		update: MetaUpdate{
+			Sequence: 1,
+			Meta:     reveal,
+		},
+	}}
+	for _, testCase := range testCases {
+		_, err := testCase.update.MetaReveal()
+		require.ErrorIs(t, err, ErrInvalidMetaUpdate, testCase.name)
+	}
+
+	// A meta reveal of a different type doesn't carry an update.
+	_, err = update.Meta.MetaUpdate()
+	require.ErrorIs(t, err, ErrInvalidMetaUpdate)
+}
+
+// TestMetaHistory tests that the history of metadata updates of an asset group
+// is verified and ordered correctly.
+func TestMetaHistory(t *testing.T) {
+	t.Parallel()
+
+	anchorGen := asset.RandGenesis(t, asset.Normal)
+	groupKey, groupPriv := asset.RandGroupKeyWithSigner(t, anchorGen)
+	group := &asset.AssetGroup{
+		Genesis:  &anchorGen,
+		GroupKey: groupKey,
+	}
+	groupPubKey := &groupKey.GroupPubKey
+
+	anchor := &MetaTranche{
+		Genesis:  anchorGen,
+		GroupKey: groupKey,
+	}
+
+	// Create a chain of three updates.
+	var (
+		updates  []*MetaTranche
+		prevHash [asset.MetaHashLen]byte
+	)
+	for i := uint32(1); i <= 3; i++ {
+		update := &MetaUpdate{
+			Sequence: i,
+			PrevHash: prevHash,
+			Meta: &MetaReveal{
+				Type: MetaOpaque,
+				Data: test.RandBytes(32),
+			},
+		}
+		reveal, err := update.MetaReveal()
+		require.NoError(t, err)
+
+		tranche := metaUpdateTranche(t, group, groupPriv, reveal)
+		updates = append(updates, tranche)
+		prevHash = tranche.Genesis.MetaHash
+	}
+
+	// Without any updates, there's no canonical metadata.
+	history, err := NewMetaHistory(
+		groupPubKey, []*MetaTranche{anchor},
+	)
+	require.NoError(t, err)
+	require.Nil(t, history.Canonical())
+
+	// The order of the tranches doesn't matter, the latest update is
+	// always the canonical one.
+	history, err = NewMetaHistory(groupPubKey, []*MetaTranche{
+		updates[2], anchor, updates[0], updates[1], updates[0],
+	})
+	require.NoError(t, err)
+	require.Len(t, history.Updates, 3)
+	for i, entry := range history.Updates {
+		require.Equal(t, updates[i].Genesis.ID(), entry.AssetID)
+	}
+	latest, err := updates[2].MetaReveal.MetaUpdate()
+	require.NoError(t, err)
+	require.Equal(t, latest.Meta, history.Canonical())
+
+	// A missing update breaks the chain.
+	_, err = NewMetaHistory(groupPubKey, []*MetaTranche{
+		anchor, updates[0], updates[2],
+	})
+	require.ErrorIs(t, err, ErrMetaUpdateBrokenChain)
+
+	// A second update with the same sequence is a conflict.
+	forkUpdate := &MetaUpdate{
+		Sequence: 2,
+		PrevHash: updates[0].Genesis.MetaHash,
+		Meta: &MetaReveal{
+			Type: MetaOpaque,
+			Data: []byte("fork"),
+		},
+	}
+	forkReveal, err := forkUpdate.MetaReveal()
+	require.NoError(t, err)
+	fork := metaUpdateTranche(t, group, groupPriv, forkReveal)
+	_, err = NewMetaHistory(groupPubKey, []*MetaTranche{
+		updates[0], updates[1], fork,
+	})
+	require.ErrorIs(t, err, ErrMetaUpdateConflict)
+
+	// An update that doesn't commit to its predecessor is rejected.
+	badUpdate := &MetaUpdate{
+		Sequence: 2,
+		PrevHash: anchorGen.MetaHash,
+		Meta:     forkUpdate.Meta,
+	}
+	badUpdate.PrevHash[0] ^= 1
+	badReveal, err := badUpdate.MetaReveal()
+	require.NoError(t, err)
+	bad := metaUpdateTranche(t, group, groupPriv, badReveal)
+	_, err = NewMetaHistory(groupPubKey, []*MetaTranche{
+		updates[0], bad,
+	})
+	require.ErrorIs(t, err, ErrMetaUpdateBrokenChain)
+
+	// An update that wasn't signed by the group key is rejected.
+	forged := *updates[0]
+	forgedKey := *forged.GroupKey
+	forgedKey.Sig = updates[1].GroupKey.Sig
+	forged.GroupKey = &forgedKey
+	_, err = NewMetaHistory(groupPubKey, []*MetaTranche{&forged})
+	require.ErrorIs(t, err, asset.ErrInvalidGroupSig)
+
+	// A meta reveal that doesn't match the genesis is rejected.
+	mismatched := *updates[0]
+	mismatched.MetaReveal = updates[1].MetaReveal
+	_, err = NewMetaHistory(groupPubKey, []*MetaTranche{&mismatched})
+	require.ErrorIs(t, err, ErrMetaRevealMismatch)
+}
@@ -139,7 +139,9 @@ func NewMintingBlobs(params *MintParams,
 		return nil, err
 	}
 
-	proofs, err := committedProofs(base, params.TaprootAssetRoot, opts)
+	proofs, err := committedProofs(
+		base, params.TaprootAssetRoot, params.TapscriptSibling, opts,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -206,10 +208,20 @@ func coreProof(params *BaseProofParams) (*Proof, error) {
 }
 
 // committedProofs creates a map of proofs, keyed by the script key of each of
-// the assets committed to in the Taproot Asset root of the given params.
+// the assets committed to in the Taproot Asset root of the given params. If a
+// tapscript sibling is given, it is included in each inclusion proof, just like
+// the alt leaves of the Taproot Asset root.
 func committedProofs(baseProof *Proof, taprootAssetRoot *commitment.TapCommitment,
+	tapSibling *commitment.TapscriptPreimage,
 	opts *mintingBlobOpts) (map[asset.SerializedKey]*Proof, error) {
 
+	// The alt leaves of the Taproot Asset root are the same for all
+	// assets, so we only need to prove them once.
+	altLeavesProof, err := taprootAssetRoot.AltLeavesProof()
+	if err != nil {
+		return nil, err
+	}
+
 	// For each asset we'll construct the asset specific proof information,
 	// then encode that as a proof file blob in the blobs map.
 	assets := taprootAssetRoot.CommittedAssets()
@@ -232,13 +244,13 @@ func committedProofs(baseProof *Proof, taprootAssetRoot *commitment.TapCommitmen
 		}
 
 		// With the merkle proof obtained, we can now set that in the
-		// main inclusion proof.
-		//
-		// NOTE: We don't add a TapSiblingPreimage here since we assume
-		// that this minting output ONLY commits to the Taproot Asset
-		// commitment.
+		// main inclusion proof, along with the tapscript sibling and
+		// the alt leaves of the Taproot Asset commitment, if there are
+		// any.
 		assetProof.InclusionProof.CommitmentProof = &CommitmentProof{
-			Proof: *assetMerkleProof,
+			Proof:              *assetMerkleProof,
+			TapSiblingPreimage: tapSibling,
+			AltLeavesProof:     altLeavesProof,
 		}
 
 		scriptKey := asset.ToSerialized(newAsset.ScriptKey.PubKey)
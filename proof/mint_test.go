@@ -1,6 +1,8 @@
 package proof
 
 import (
+	"bytes"
+	"context"
 	"math/rand"
 	"testing"
 
@@ -50,6 +52,14 @@ func TestNewMintingBlobs(t *testing.T) {
 	)
 	require.NoError(t, err)
 
+	// The minting output also commits to an alt leaf, which must be
+	// revealed in the proof of the asset.
+	altLeaf := &commitment.AltLeaf{
+		Key:  test.RandHash(),
+		Data: test.RandBytes(32),
+	}
+	require.NoError(t, tapCommitment.UpsertAltLeaves(altLeaf))
+
 	internalKey := test.SchnorrPubKey(t, genesisPrivKey)
 	tapscriptRoot := tapCommitment.TapscriptRoot(nil)
 	taprootKey := txscript.ComputeTaprootOutputKey(
@@ -93,7 +103,7 @@ func TestNewMintingBlobs(t *testing.T) {
 
 	// The NewMintingBlobs will return an error if the generated proof is
 	// invalid. We'll also add the optional meta reveal data as well
-	_, err = NewMintingBlobs(&MintParams{
+	blobs, err := NewMintingBlobs(&MintParams{
 		BaseProofParams: BaseProofParams{
 			Block: &wire.MsgBlock{
 				Header:       *blockHeader,
@@ -115,4 +125,28 @@ func TestNewMintingBlobs(t *testing.T) {
 		GenesisPoint: genesisTx.TxIn[0].PreviousOutPoint,
 	}, MockHeaderVerifier, WithAssetMetaReveals(metaReveals))
 	require.NoError(t, err)
+
+	var proofFile File
+	blob := blobs[asset.ToSerialized(assetScriptKey.PubKey)]
+	require.NoError(t, proofFile.Decode(bytes.NewReader(blob)))
+	mintProof, err := proofFile.LastProof()
+	require.NoError(t, err)
+
+	commitmentProof := mintProof.InclusionProof.CommitmentProof
+	altLeavesProof := commitmentProof.AltLeavesProof
+	require.NotNil(t, altLeavesProof)
+	require.Equal(t, []*commitment.AltLeaf{altLeaf}, altLeavesProof.Leaves)
+
+	snapshot, err := mintProof.Verify(
+		context.Background(), nil, MockHeaderVerifier,
+	)
+	require.NoError(t, err)
+	require.Equal(t, []*commitment.AltLeaf{altLeaf}, snapshot.AltLeaves)
+
+	// Revealing different alt leaves than the ones committed to must fail.
+	altLeavesProof.Leaves[0].Data = test.RandBytes(32)
+	_, err = mintProof.Verify(
+		context.Background(), nil, MockHeaderVerifier,
+	)
+	require.ErrorIs(t, err, ErrInvalidAltLeavesProof)
 }
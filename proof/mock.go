@@ -23,12 +23,12 @@ func NewMockVerifier(t *testing.T) *MockVerifier {
 func (m *MockVerifier) Verify(_ context.Context, _ io.Reader,
 	headerVerifier HeaderVerifier) (*AssetSnapshot, error) {
 
+	genesis := asset.RandGenesis(m.t, asset.Normal)
+
 	return &AssetSnapshot{
 		Asset: &asset.Asset{
-
-			GroupKey: &asset.GroupKey{
-				GroupPubKey: *test.RandPubKey(m.t),
-			},
+			Genesis:   genesis,
+			GroupKey:  asset.RandGroupKey(m.t, genesis),
 			ScriptKey: asset.NewScriptKey(test.RandPubKey(m.t)),
 		},
 	}, nil
@@ -36,6 +36,16 @@ var (
 	// genesis asset has a non-zero metahash, but doesn't have a meta
 	// reveal.
 	ErrMetaRevealRequired = errors.New("meta reveal required")
+
+	// ErrInputNotSpent is an error returned if one of the additional input
+	// branches of a proof ends in an outpoint that isn't spent by the
+	// anchor transaction of the proof.
+	ErrInputNotSpent = errors.New("additional input not spent by anchor " +
+		"transaction")
+
+	// ErrDuplicateInput is an error returned if the same asset input is
+	// proven more than once by a proof.
+	ErrDuplicateInput = errors.New("duplicate asset input")
 )
 
 // Proof encodes all of the data necessary to prove a valid state transition for
@@ -85,7 +95,10 @@ type Proof struct {
 	MetaReveal *MetaReveal
 
 	// AdditionalInputs is a nested full proof for any additional inputs
-	// found within the resulting asset.
+	// found within the resulting asset. Each of the nested proof files is
+	// a branch of its own that can contain additional inputs again, so a
+	// proof file for merged assets forms a DAG of state transitions, with
+	// the asset's genesis at every root.
 	AdditionalInputs []File
 
 	// ChallengeWitness is an optional virtual transaction witness that
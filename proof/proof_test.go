@@ -3,9 +3,12 @@ package proof
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
+	"math"
 	"math/rand"
 	"os"
 	"path/filepath"
@@ -24,8 +27,10 @@ import (
 	"github.com/lightninglabs/taproot-assets/asset"
 	"github.com/lightninglabs/taproot-assets/commitment"
 	"github.com/lightninglabs/taproot-assets/internal/test"
+	"github.com/lightninglabs/taproot-assets/vm"
 	"github.com/lightningnetwork/lnd/build"
 	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/tlv"
 	"github.com/stretchr/testify/require"
 )
 
@@ -54,6 +59,7 @@ func assertEqualCommitmentProof(t *testing.T, expected, actual *CommitmentProof)
 		actual.Proof.TaprootAssetProof,
 	)
 	require.Equal(t, expected.TapSiblingPreimage, actual.TapSiblingPreimage)
+	require.Equal(t, expected.AltLeavesProof, actual.AltLeavesProof)
 }
 
 func assertEqualTaprootProof(t *testing.T, expected, actual *TaprootProof) {
@@ -531,6 +537,409 @@ func TestProofFileVerification(t *testing.T) {
 	require.NoError(t, err)
 }
 
+// spendOutPoints makes the anchor transaction of the given proof spend the
+// given outpoints in addition to its previous outpoint, and updates the block
+// header and merkle proof of the proof accordingly.
+func spendOutPoints(t *testing.T, p *Proof, outPoints ...wire.OutPoint) {
+	p.AnchorTx.TxIn = []*wire.TxIn{{
+		PreviousOutPoint: p.PrevOut,
+	}}
+	for _, outPoint := range outPoints {
+		p.AnchorTx.TxIn = append(p.AnchorTx.TxIn, &wire.TxIn{
+			PreviousOutPoint: outPoint,
+		})
+	}
+
+	merkleTree := blockchain.BuildMerkleTreeStore(
+		[]*btcutil.Tx{btcutil.NewTx(&p.AnchorTx)}, false,
+	)
+	p.BlockHeader.MerkleRoot = *merkleTree[len(merkleTree)-1]
+
+	txMerkleProof, err := NewTxMerkleProof(
+		[]*wire.MsgTx{&p.AnchorTx}, 0,
+	)
+	require.NoError(t, err)
+	p.TxMerkleProof = *txMerkleProof
+}
+
+// TestProofDAGVerification tests that the input branches of a proof are
+// verified as part of the proof DAG.
+func TestProofDAGVerification(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	amt := uint64(1000)
+	branchProof, _ := genRandomGenesisWithProof(
+		t, asset.Normal, &amt, nil, true, nil, nil,
+	)
+	branch, err := NewFile(V0, branchProof)
+	require.NoError(t, err)
+
+	branchOutPoint := wire.OutPoint{
+		Hash:  branchProof.AnchorTx.TxHash(),
+		Index: branchProof.InclusionProof.OutputIndex,
+	}
+	invalidBranch := mustCorruptMerkleProof(t, branchProof)
+
+	testCases := []struct {
+		name      string
+		inputs    []File
+		outPoints []wire.OutPoint
+		expectErr func(t *testing.T, err error)
+	}{{
+		name:   "empty branch",
+		inputs: []File{{}},
+		expectErr: func(t *testing.T, err error) {
+			require.ErrorIs(t, err, ErrNoProofAvailable)
+		},
+	}, {
+		name:   "invalid branch",
+		inputs: []File{*invalidBranch},
+		expectErr: func(t *testing.T, err error) {
+			require.ErrorIs(t, err, ErrInvalidTxMerkleProof)
+		},
+	}, {
+		name:   "branch not spent",
+		inputs: []File{*branch},
+		expectErr: func(t *testing.T, err error) {
+			require.ErrorIs(t, err, ErrInputNotSpent)
+		},
+	}, {
+		name:      "duplicate branch",
+		inputs:    []File{*branch, *branch},
+		outPoints: []wire.OutPoint{branchOutPoint},
+		expectErr: func(t *testing.T, err error) {
+			require.ErrorIs(t, err, ErrDuplicateInput)
+		},
+	}, {
+		// The branch itself is fine, but a genesis asset can't have
+		// any inputs, which is caught by the VM.
+		name:      "genesis with input",
+		inputs:    []File{*branch},
+		outPoints: []wire.OutPoint{branchOutPoint},
+		expectErr: func(t *testing.T, err error) {
+			var vmErr vm.Error
+			require.ErrorAs(t, err, &vmErr)
+			require.Equal(
+				t, vm.ErrInvalidGenesisStateTransition,
+				vmErr.Kind,
+			)
+		},
+	}}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			p, _ := genRandomGenesisWithProof(
+				t, asset.Normal, &amt, nil, true, nil, nil,
+			)
+			p.AdditionalInputs = testCase.inputs
+			spendOutPoints(t, &p, testCase.outPoints...)
+
+			_, err := p.Verify(ctx, nil, MockHeaderVerifier)
+			testCase.expectErr(t, err)
+		})
+	}
+}
+
+// mustCorruptMerkleProof returns a proof file for the given proof with an
+// invalid merkle proof.
+func mustCorruptMerkleProof(t *testing.T, p Proof) *File {
+	p.BlockHeader.MerkleRoot = chainhash.Hash{}
+
+	f, err := NewFile(V0, p)
+	require.NoError(t, err)
+
+	return f
+}
+
+// TestBranchCache tests that an input branch is only verified once, even if
+// it's part of the proof DAG multiple times.
+func TestBranchCache(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	amt := uint64(1000)
+	branchProof, _ := genRandomGenesisWithProof(
+		t, asset.Normal, &amt, nil, true, nil, nil,
+	)
+	branch, err := NewFile(V0, branchProof)
+	require.NoError(t, err)
+
+	var numVerified int
+	headerVerifier := func(header wire.BlockHeader) error {
+		numVerified++
+		return nil
+	}
+
+	branches := newBranchCache()
+	snapshot, err := branches.verifyBranch(ctx, branch, headerVerifier)
+	require.NoError(t, err)
+	require.Equal(t, 1, numVerified)
+
+	// A different copy of the same branch is a cache hit.
+	var buf bytes.Buffer
+	require.NoError(t, branch.Encode(&buf))
+
+	var branchCopy File
+	require.NoError(t, branchCopy.Decode(&buf))
+
+	cached, err := branches.verifyBranch(ctx, &branchCopy, headerVerifier)
+	require.NoError(t, err)
+	require.Equal(t, 1, numVerified)
+	require.Equal(t, snapshot, cached)
+
+	// Invalid branches aren't cached.
+	invalid := mustCorruptMerkleProof(t, branchProof)
+	for i := 0; i < 2; i++ {
+		_, err = branches.verifyBranch(ctx, invalid, headerVerifier)
+		require.ErrorIs(t, err, ErrInvalidTxMerkleProof)
+	}
+	require.Equal(t, 3, numVerified)
+}
+
+// TestFileReader tests that reading a proof file sequentially results in the
+// same proofs and snapshot as decoding it as a whole.
+func TestFileReader(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	fileBytes := readHexSeed(t, proofFileHexFileName)
+
+	f := &File{}
+	require.NoError(t, f.Decode(bytes.NewReader(fileBytes)))
+	expectedSnapshot, err := f.Verify(ctx, MockHeaderVerifier)
+	require.NoError(t, err)
+
+	reader, err := NewFileReader(bytes.NewReader(fileBytes))
+	require.NoError(t, err)
+	require.Equal(t, f.Version, reader.Version())
+	require.EqualValues(t, f.NumProofs(), reader.NumProofs())
+
+	for i := 0; i < f.NumProofs(); i++ {
+		rawProof, err := reader.NextRaw()
+		require.NoError(t, err)
+
+		expectedProof, err := f.RawProofAt(uint32(i))
+		require.NoError(t, err)
+		require.Equal(t, expectedProof, rawProof)
+		require.Equal(t, f.proofs[i].hash, reader.LastHash())
+	}
+	_, err = reader.NextRaw()
+	require.ErrorIs(t, err, io.EOF)
+
+	// A file can only be verified if no proof was read yet.
+	_, err = reader.Verify(ctx, MockHeaderVerifier)
+	require.Error(t, err)
+
+	reader, err = NewFileReader(bytes.NewReader(fileBytes))
+	require.NoError(t, err)
+	snapshot, err := reader.Verify(ctx, MockHeaderVerifier)
+	require.NoError(t, err)
+	require.Equal(t, expectedSnapshot, snapshot)
+
+	// A file with a corrupted checksum or missing proof data is rejected
+	// once the affected proof is read.
+	corrupted := append([]byte{}, fileBytes...)
+	corrupted[len(corrupted)-1] ^= 1
+	reader, err = NewFileReader(bytes.NewReader(corrupted))
+	require.NoError(t, err)
+	_, err = reader.Verify(ctx, MockHeaderVerifier)
+	require.ErrorIs(t, err, ErrInvalidChecksum)
+
+	truncated := fileBytes[:len(fileBytes)-sha256.Size]
+	reader, err = NewFileReader(bytes.NewReader(truncated))
+	require.NoError(t, err)
+	_, err = reader.Verify(ctx, MockHeaderVerifier)
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+// TestVerifyTransition tests that verifying each state transition of a proof
+// file given the previous proof results in the same snapshots as verifying
+// the whole file.
+func TestVerifyTransition(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	fileBytes := readHexSeed(t, proofFileHexFileName)
+
+	f := &File{}
+	require.NoError(t, f.Decode(bytes.NewReader(fileBytes)))
+	require.Greater(t, f.NumProofs(), 1)
+
+	var (
+		prevProof *Proof
+		prev      *AssetSnapshot
+	)
+	for i := 0; i < f.NumProofs(); i++ {
+		p, err := f.ProofAt(uint32(i))
+		require.NoError(t, err)
+
+		expected, err := p.Verify(ctx, prev, MockHeaderVerifier)
+		require.NoError(t, err)
+
+		snapshot, err := VerifyTransition(
+			ctx, prevProof, p, MockHeaderVerifier,
+		)
+		require.NoError(t, err)
+		require.Equal(t, expected, snapshot)
+
+		// The snapshot of a trusted proof is the same as the one
+		// resulting from its verification.
+		trusted, err := p.Snapshot()
+		require.NoError(t, err)
+		require.Equal(t, expected, trusted)
+
+		prevProof, prev = p, snapshot
+	}
+
+	// A proof that doesn't spend the asset of the previous proof is
+	// rejected.
+	genesisProof, err := f.ProofAt(0)
+	require.NoError(t, err)
+	_, err = VerifyTransition(
+		ctx, prevProof, genesisProof, MockHeaderVerifier,
+	)
+	require.Error(t, err)
+
+	// And so is a transition proof without a previous proof.
+	_, err = VerifyTransition(ctx, nil, prevProof, MockHeaderVerifier)
+	require.Error(t, err)
+}
+
+// TestAppendToFileOnDisk tests that appending proofs to a proof file on disk
+// results in the same file as encoding all proofs at once.
+func TestAppendToFileOnDisk(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	fileBytes := readHexSeed(t, proofFileHexFileName)
+
+	f := &File{}
+	require.NoError(t, f.Decode(bytes.NewReader(fileBytes)))
+	require.Greater(t, f.NumProofs(), 1)
+
+	proofs := make([]Proof, f.NumProofs())
+	for i := range proofs {
+		p, err := f.ProofAt(uint32(i))
+		require.NoError(t, err)
+		proofs[i] = *p
+	}
+	lastProof := proofs[len(proofs)-1]
+
+	// We store the file without its last proof, followed by some data of
+	// an interrupted append.
+	partialFile, err := NewFile(f.Version, proofs[:len(proofs)-1]...)
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, partialFile.Encode(&buf))
+	partialBytes := buf.Bytes()
+
+	filePath := filepath.Join(t.TempDir(), "proof")
+	err = os.WriteFile(
+		filePath, append(partialBytes, 1, 2, 3), 0600,
+	)
+	require.NoError(t, err)
+
+	// A proof that doesn't spend the last asset of the file isn't
+	// appended.
+	err = AppendToFileOnDisk(ctx, filePath, proofs[0], MockHeaderVerifier)
+	require.Error(t, err)
+
+	err = AppendToFileOnDisk(ctx, filePath, lastProof, MockHeaderVerifier)
+	require.NoError(t, err)
+
+	diskBytes, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	require.Equal(t, fileBytes, diskBytes)
+}
+
+// TestAppendHashedProof tests that a proof can be appended to a proof file on
+// disk both when the size of its header stays the same and when it grows.
+func TestAppendHashedProof(t *testing.T) {
+	t.Parallel()
+
+	proof := readHexSeed(t, proofHexFileName)
+	var p Proof
+	require.NoError(t, p.Decode(bytes.NewReader(proof)))
+
+	encodeFile := func(numProofs int) []byte {
+		proofs := make([]Proof, numProofs)
+		for i := range proofs {
+			proofs[i] = p
+		}
+
+		f, err := NewFile(V0, proofs...)
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		require.NoError(t, f.Encode(&buf))
+
+		return buf.Bytes()
+	}
+
+	// The number of proofs is encoded with a single byte up to 252 proofs
+	// and with three bytes up to 65535 proofs.
+	testCases := []struct {
+		numProofs  int
+		headerSize int64
+	}{{
+		numProofs:  1,
+		headerSize: 5,
+	}, {
+		numProofs:  252,
+		headerSize: 5,
+	}, {
+		numProofs:  253,
+		headerSize: 7,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		name := fmt.Sprintf("%d proofs", tc.numProofs)
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			fileBytes := encodeFile(tc.numProofs)
+			filePath := filepath.Join(t.TempDir(), "proof")
+			err := os.WriteFile(filePath, fileBytes, 0600)
+			require.NoError(t, err)
+
+			var f File
+			err = f.Decode(bytes.NewReader(fileBytes))
+			require.NoError(t, err)
+			lastHash := f.proofs[len(f.proofs)-1].hash
+
+			file, err := os.OpenFile(filePath, os.O_RDWR, 0)
+			require.NoError(t, err)
+			defer file.Close()
+
+			err = appendHashedProof(
+				file, V0, uint64(tc.numProofs), tc.headerSize,
+				int64(len(fileBytes)), &hashedProof{
+					proofBytes: proof,
+					hash:       hashProof(proof, lastHash),
+				},
+			)
+			require.NoError(t, err)
+
+			diskBytes, err := os.ReadFile(filePath)
+			require.NoError(t, err)
+			require.Equal(t, encodeFile(tc.numProofs+1), diskBytes)
+
+			// No temporary files are left behind.
+			dirEntries, err := os.ReadDir(filepath.Dir(filePath))
+			require.NoError(t, err)
+			require.Len(t, dirEntries, 1)
+		})
+	}
+}
+
 // TestProofVerification ensures that the proof encoding and decoding works as
 // expected.
 func TestProofVerification(t *testing.T) {
@@ -624,6 +1033,223 @@ func BenchmarkProofEncoding(b *testing.B) {
 	}
 }
 
+// TestFileDecodeLimits tests that proof files that claim to hold more or
+// larger proofs than they actually do are rejected without allocating memory
+// for the claimed size.
+func TestFileDecodeLimits(t *testing.T) {
+	t.Parallel()
+
+	var buf [8]byte
+	fileWithLen := func(numProofs, numProofBytes uint64) []byte {
+		var b bytes.Buffer
+		_, _ = b.Write([]byte{0, 0, 0, 0})
+		require.NoError(t, tlv.WriteVarInt(&b, numProofs, &buf))
+		require.NoError(t, tlv.WriteVarInt(&b, numProofBytes, &buf))
+
+		return b.Bytes()
+	}
+
+	var f File
+	err := f.Decode(bytes.NewReader(fileWithLen(math.MaxUint64, 1)))
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	err = f.Decode(bytes.NewReader(fileWithLen(1, math.MaxUint64)))
+	require.ErrorIs(t, err, asset.ErrByteSliceTooLarge)
+
+	err = f.Decode(bytes.NewReader(fileWithLen(1, asset.MaxVarBytesLen)))
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+// readHexSeed reads the hex encoded test data file with the given name.
+func readHexSeed(t testing.TB, fileName string) []byte {
+	fileContent, err := os.ReadFile(fileName)
+	require.NoError(t, err)
+
+	rawBytes, err := hex.DecodeString(
+		strings.Trim(string(fileContent), "\n"),
+	)
+	require.NoError(t, err)
+
+	return rawBytes
+}
+
+// FuzzProofDecode tests that decoding arbitrary proofs doesn't panic.
+func FuzzProofDecode(f *testing.F) {
+	f.Add(readHexSeed(f, proofHexFileName))
+	f.Add(readHexSeed(f, ownershipProofHexFileName))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var p Proof
+		_ = p.Decode(bytes.NewReader(data))
+	})
+}
+
+// FuzzFileDecode tests that decoding arbitrary proof files doesn't panic.
+func FuzzFileDecode(f *testing.F) {
+	f.Add(readHexSeed(f, proofFileHexFileName))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var file File
+		if err := file.Decode(bytes.NewReader(data)); err != nil {
+			return
+		}
+
+		_, _ = file.LastProof()
+	})
+}
+
+// generatedTestVectorName is the name of the test vector file that is
+// generated by TestProofTestVectors.
+const generatedTestVectorName = "proof_tlv_encoding_generated.json"
+
+// validProofTestCase is a test vector of a TLV encoded proof and the expected
+// values that can be derived from it.
+type validProofTestCase struct {
+	Proof                string       `json:"proof"`
+	PrevOut              string       `json:"prev_out"`
+	BlockHash            string       `json:"block_hash"`
+	AnchorTxID           string       `json:"anchor_txid"`
+	Asset                *asset.Asset `json:"asset"`
+	InclusionOutputIndex uint32       `json:"inclusion_output_index"`
+	InternalKey          string       `json:"internal_key"`
+	TaprootOutputKey     string       `json:"taproot_output_key"`
+	Comment              string       `json:"comment"`
+}
+
+// errorProofTestCase is a test vector of a TLV encoded proof that must be
+// rejected when decoding.
+type errorProofTestCase struct {
+	Proof   string `json:"proof"`
+	Error   string `json:"error"`
+	Comment string `json:"comment"`
+}
+
+// proofTestVectors are the test vectors for the TLV encoding of proofs.
+type proofTestVectors = test.TestVectors[
+	validProofTestCase, errorProofTestCase,
+]
+
+// newValidProofTestCase creates a test vector from the given encoded proof.
+func newValidProofTestCase(t *testing.T, proofBytes []byte,
+	comment string) *validProofTestCase {
+
+	var p Proof
+	require.NoError(t, p.Decode(bytes.NewReader(proofBytes)))
+
+	outputKey, _, err := p.InclusionProof.DeriveByAssetInclusion(&p.Asset)
+	require.NoError(t, err)
+
+	return &validProofTestCase{
+		Proof:                hex.EncodeToString(proofBytes),
+		PrevOut:              p.PrevOut.String(),
+		BlockHash:            p.BlockHeader.BlockHash().String(),
+		AnchorTxID:           p.AnchorTx.TxHash().String(),
+		Asset:                &p.Asset,
+		InclusionOutputIndex: p.InclusionProof.OutputIndex,
+		InternalKey: hex.EncodeToString(
+			p.InclusionProof.InternalKey.SerializeCompressed(),
+		),
+		TaprootOutputKey: hex.EncodeToString(
+			schnorr.SerializePubKey(outputKey),
+		),
+		Comment: comment,
+	}
+}
+
+// TestProofTestVectors generates the proof encoding test vectors and then
+// asserts that all test vector files in the testdata directory are valid.
+func TestProofTestVectors(t *testing.T) {
+	t.Parallel()
+
+	proofBytes := readHexSeed(t, proofHexFileName)
+	ownershipProofBytes := readHexSeed(t, ownershipProofHexFileName)
+
+	vectors := &proofTestVectors{
+		ValidTestCases: []*validProofTestCase{
+			newValidProofTestCase(t, proofBytes, "transfer proof"),
+			newValidProofTestCase(
+				t, ownershipProofBytes, "ownership proof",
+			),
+		},
+		ErrorTestCases: []*errorProofTestCase{{
+			Proof: hex.EncodeToString(
+				proofBytes[:len(proofBytes)-1],
+			),
+			Error:   io.ErrUnexpectedEOF.Error(),
+			Comment: "truncated proof",
+		}},
+	}
+
+	test.WriteTestVectors(
+		t, filepath.Join(testDataFileName, generatedTestVectorName),
+		vectors,
+	)
+
+	vectorFiles := test.TestVectorFiles(
+		t, testDataFileName, "proof_tlv_encoding_",
+	)
+	for _, fileName := range vectorFiles {
+		var fileVectors proofTestVectors
+		test.ParseTestVectors(t, fileName, &fileVectors)
+
+		t.Run(fileName, func(t *testing.T) {
+			runProofTestVectors(t, &fileVectors)
+		})
+	}
+}
+
+// runProofTestVectors asserts that the given proof test vectors are valid.
+func runProofTestVectors(t *testing.T, vectors *proofTestVectors) {
+	for _, tc := range vectors.ValidTestCases {
+		proofBytes, err := hex.DecodeString(tc.Proof)
+		require.NoError(t, err, tc.Comment)
+
+		// Decoding the proof must result in the expected values.
+		expected := newValidProofTestCase(t, proofBytes, tc.Comment)
+		require.Equal(t, tc.PrevOut, expected.PrevOut, tc.Comment)
+		require.Equal(t, tc.BlockHash, expected.BlockHash, tc.Comment)
+		require.Equal(
+			t, tc.AnchorTxID, expected.AnchorTxID, tc.Comment,
+		)
+		require.Equal(
+			t, tc.InclusionOutputIndex,
+			expected.InclusionOutputIndex, tc.Comment,
+		)
+		require.Equal(
+			t, tc.InternalKey, expected.InternalKey, tc.Comment,
+		)
+		require.Equal(
+			t, tc.TaprootOutputKey, expected.TaprootOutputKey,
+			tc.Comment,
+		)
+
+		var expectedAsset, actualAsset bytes.Buffer
+		require.NoError(t, tc.Asset.Encode(&expectedAsset))
+		require.NoError(t, expected.Asset.Encode(&actualAsset))
+		require.Equal(
+			t, expectedAsset.Bytes(), actualAsset.Bytes(),
+			tc.Comment,
+		)
+
+		// Encoding the decoded proof again must result in the same
+		// bytes.
+		var p Proof
+		require.NoError(t, p.Decode(bytes.NewReader(proofBytes)))
+		var reEncoded bytes.Buffer
+		require.NoError(t, p.Encode(&reEncoded))
+		require.Equal(t, proofBytes, reEncoded.Bytes(), tc.Comment)
+	}
+
+	for _, tc := range vectors.ErrorTestCases {
+		proofBytes, err := hex.DecodeString(tc.Proof)
+		require.NoError(t, err, tc.Comment)
+
+		var p Proof
+		err = p.Decode(bytes.NewReader(proofBytes))
+		require.ErrorContains(t, err, tc.Error, tc.Comment)
+	}
+}
+
 func init() {
 	rand.Seed(time.Now().Unix())
 
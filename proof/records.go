@@ -33,12 +33,33 @@ const (
 	// count from where commitment.ProofTaprootAssetProofType left off.
 	CommitmentProofTapSiblingPreimageType tlv.Type = 2
 
+	// CommitmentProofAltLeavesProofType is the type of the TLV record for
+	// the CommitmentProof's AltLeavesProof field.
+	CommitmentProofAltLeavesProofType tlv.Type = 3
+
 	TapscriptProofTapPreimage1 tlv.Type = 0
 	TapscriptProofTapPreimage2 tlv.Type = 1
 	TapscriptProofBip86        tlv.Type = 2
 
 	MetaRevealEncodingType tlv.Type = 0
 	MetaRevealDataType     tlv.Type = 1
+
+	MetaUpdateSequenceType tlv.Type = 0
+	MetaUpdatePrevHashType tlv.Type = 1
+	MetaUpdateMetaType     tlv.Type = 2
+
+	ReservesReportChallengeType tlv.Type = 0
+	ReservesReportFilesType     tlv.Type = 1
+
+	BundleVersionType  tlv.Type = 0
+	BundleManifestType tlv.Type = 1
+	BundleFilesType    tlv.Type = 2
+
+	BundleEntryAssetIDType   tlv.Type = 0
+	BundleEntryGroupKeyType  tlv.Type = 1
+	BundleEntryScriptKeyType tlv.Type = 2
+	BundleEntryNumProofsType tlv.Type = 3
+	BundleEntryFileHashType  tlv.Type = 4
 )
 
 func PrevOutRecord(prevOut *wire.OutPoint) tlv.Record {
@@ -218,6 +239,24 @@ func CommitmentProofTapSiblingPreimageRecord(
 	)
 }
 
+func CommitmentProofAltLeavesProofRecord(
+	proof **commitment.AltLeavesProof) tlv.Record {
+
+	sizeFunc := func() uint64 {
+		var buf bytes.Buffer
+		err := commitment.AltLeavesProofEncoder(&buf, proof, &[8]byte{})
+		if err != nil {
+			panic(err)
+		}
+		return uint64(len(buf.Bytes()))
+	}
+	return tlv.MakeDynamicRecord(
+		CommitmentProofAltLeavesProofType, proof, sizeFunc,
+		commitment.AltLeavesProofEncoder,
+		commitment.AltLeavesProofDecoder,
+	)
+}
+
 func TapscriptProofTapPreimage1Record(
 	preimage **commitment.TapscriptPreimage) tlv.Record {
 
@@ -277,5 +316,121 @@ func MetaRevealTypeRecord(metaType *MetaType) tlv.Record {
 }
 
 func MetaRevealDataRecord(data *[]byte) tlv.Record {
-	return tlv.MakePrimitiveRecord(MetaRevealDataType, data)
+	sizeFunc := func() uint64 {
+		return uint64(len(*data))
+	}
+	return tlv.MakeDynamicRecord(
+		MetaRevealDataType, data, sizeFunc, tlv.EVarBytes,
+		asset.InlineVarBytesDecoder,
+	)
+}
+
+func MetaUpdateSequenceRecord(sequence *uint32) tlv.Record {
+	return tlv.MakePrimitiveRecord(MetaUpdateSequenceType, sequence)
+}
+
+func MetaUpdatePrevHashRecord(prevHash *[32]byte) tlv.Record {
+	return tlv.MakePrimitiveRecord(MetaUpdatePrevHashType, prevHash)
+}
+
+func MetaUpdateMetaRecord(meta **MetaReveal) tlv.Record {
+	sizeFunc := func() uint64 {
+		var buf bytes.Buffer
+		err := MetaRevealEncoder(&buf, meta, &[8]byte{})
+		if err != nil {
+			panic(err)
+		}
+		return uint64(len(buf.Bytes()))
+	}
+	return tlv.MakeDynamicRecord(
+		MetaUpdateMetaType, meta, sizeFunc, MetaRevealEncoder,
+		MetaRevealDecoder,
+	)
+}
+
+func ReservesReportChallengeRecord(challenge *[32]byte) tlv.Record {
+	return tlv.MakePrimitiveRecord(ReservesReportChallengeType, challenge)
+}
+
+func ReservesReportFilesRecord(files *[]File) tlv.Record {
+	sizeFunc := func() uint64 {
+		var buf bytes.Buffer
+		err := AdditionalInputsEncoder(&buf, files, &[8]byte{})
+		if err != nil {
+			panic(err)
+		}
+		return uint64(len(buf.Bytes()))
+	}
+	return tlv.MakeDynamicRecord(
+		ReservesReportFilesType, files, sizeFunc,
+		AdditionalInputsEncoder, AdditionalInputsDecoder,
+	)
+}
+
+func BundleVersionRecord(version *BundleVersion) tlv.Record {
+	return tlv.MakeStaticRecord(
+		BundleVersionType, version, 4, BundleVersionEncoder,
+		BundleVersionDecoder,
+	)
+}
+
+func BundleManifestRecord(manifest *[]BundleEntry) tlv.Record {
+	sizeFunc := func() uint64 {
+		var buf bytes.Buffer
+		err := BundleManifestEncoder(&buf, manifest, &[8]byte{})
+		if err != nil {
+			panic(err)
+		}
+		return uint64(len(buf.Bytes()))
+	}
+	return tlv.MakeDynamicRecord(
+		BundleManifestType, manifest, sizeFunc, BundleManifestEncoder,
+		BundleManifestDecoder,
+	)
+}
+
+func BundleFilesRecord(files *[]Blob) tlv.Record {
+	sizeFunc := func() uint64 {
+		var buf bytes.Buffer
+		err := BundleFilesEncoder(&buf, files, &[8]byte{})
+		if err != nil {
+			panic(err)
+		}
+		return uint64(len(buf.Bytes()))
+	}
+	return tlv.MakeDynamicRecord(
+		BundleFilesType, files, sizeFunc, BundleFilesEncoder,
+		BundleFilesDecoder,
+	)
+}
+
+func BundleEntryAssetIDRecord(assetID *asset.ID) tlv.Record {
+	return tlv.MakeStaticRecord(
+		BundleEntryAssetIDType, assetID, 32, asset.IDEncoder,
+		asset.IDDecoder,
+	)
+}
+
+func BundleEntryGroupKeyRecord(groupKey **btcec.PublicKey) tlv.Record {
+	return tlv.MakeStaticRecord(
+		BundleEntryGroupKeyType, groupKey,
+		btcec.PubKeyBytesLenCompressed, asset.CompressedPubKeyEncoder,
+		asset.CompressedPubKeyDecoder,
+	)
+}
+
+func BundleEntryScriptKeyRecord(scriptKey **btcec.PublicKey) tlv.Record {
+	return tlv.MakeStaticRecord(
+		BundleEntryScriptKeyType, scriptKey,
+		btcec.PubKeyBytesLenCompressed, asset.CompressedPubKeyEncoder,
+		asset.CompressedPubKeyDecoder,
+	)
+}
+
+func BundleEntryNumProofsRecord(numProofs *uint64) tlv.Record {
+	return tlv.MakePrimitiveRecord(BundleEntryNumProofsType, numProofs)
+}
+
+func BundleEntryFileHashRecord(fileHash *[32]byte) tlv.Record {
+	return tlv.MakePrimitiveRecord(BundleEntryFileHashType, fileHash)
 }
@@ -0,0 +1,227 @@
+package proof
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+var (
+	// ErrReservesChallengeMismatch is returned if a reserves report was
+	// created for a different challenge than the one of the verifier.
+	ErrReservesChallengeMismatch = errors.New("reserves report challenge " +
+		"mismatch")
+
+	// ErrMissingChallengeWitness is returned if an asset in a reserves
+	// report doesn't come with an ownership proof.
+	ErrMissingChallengeWitness = errors.New("missing challenge witness")
+
+	// ErrDuplicateReserve is returned if the same asset is included in a
+	// reserves report more than once.
+	ErrDuplicateReserve = errors.New("duplicate asset in reserves report")
+)
+
+// ReservesReport is a report over a set of asset UTXOs that proves the
+// reporter owns all of them at the time of the report. For each asset, the
+// report contains the full proof file, which proves the existence of the
+// asset and opens the commitment of the anchor output it's in. The last proof
+// of each file carries an ownership proof (challenge witness) that is bound to
+// the challenge of the verifier, so the report can't be replayed or assembled
+// from ownership proofs created for someone else.
+type ReservesReport struct {
+	// Challenge is the challenge chosen by the verifier that all
+	// ownership proofs of the report are bound to.
+	Challenge [32]byte
+
+	// Files is the set of proof files of all reported assets.
+	Files []File
+}
+
+// EncodeRecords returns the TLV encode records for the reserves report.
+func (r *ReservesReport) EncodeRecords() []tlv.Record {
+	return []tlv.Record{
+		ReservesReportChallengeRecord(&r.Challenge),
+		ReservesReportFilesRecord(&r.Files),
+	}
+}
+
+// DecodeRecords returns the TLV decode records for the reserves report.
+func (r *ReservesReport) DecodeRecords() []tlv.Record {
+	return []tlv.Record{
+		ReservesReportChallengeRecord(&r.Challenge),
+		ReservesReportFilesRecord(&r.Files),
+	}
+}
+
+// Encode encodes the reserves report to the given writer.
+func (r *ReservesReport) Encode(w io.Writer) error {
+	stream, err := tlv.NewStream(r.EncodeRecords()...)
+	if err != nil {
+		return err
+	}
+	return stream.Encode(w)
+}
+
+// Decode decodes the reserves report from the given reader.
+func (r *ReservesReport) Decode(rd io.Reader) error {
+	stream, err := tlv.NewStream(r.DecodeRecords()...)
+	if err != nil {
+		return err
+	}
+	return stream.Decode(rd)
+}
+
+// ReservesSummary is the result of verifying a reserves report.
+type ReservesSummary struct {
+	// Challenge is the challenge the report was verified against.
+	Challenge [32]byte
+
+	// Assets is the set of verified assets, including the outpoint of the
+	// anchor output each asset is committed to. Verifiers should make
+	// sure all of these outpoints are still unspent.
+	Assets []*AssetSnapshot
+
+	// Totals is the total amount of reserves per asset ID.
+	Totals map[asset.ID]uint64
+
+	// GroupTotals is the total amount of reserves per asset group, for
+	// all assets that are part of a group.
+	GroupTotals map[asset.SerializedKey]uint64
+}
+
+// addAmount adds the given amount to the total of the given key, making sure
+// the total doesn't overflow.
+func addAmount[K comparable](totals map[K]uint64, key K, amt uint64) error {
+	if totals[key] > math.MaxUint64-amt {
+		return fmt.Errorf("reserves total overflows")
+	}
+
+	totals[key] += amt
+	return nil
+}
+
+// Verify verifies the reserves report against the given challenge. Each proof
+// file is verified in full, and the last proof of each file must carry an
+// ownership proof that is bound to the challenge. A summary of the verified
+// reserves is returned.
+func (r *ReservesReport) Verify(ctx context.Context, challenge [32]byte,
+	headerVerifier HeaderVerifier) (*ReservesSummary, error) {
+
+	if r.Challenge != challenge {
+		return nil, ErrReservesChallengeMismatch
+	}
+
+	summary := &ReservesSummary{
+		Challenge:   challenge,
+		Totals:      make(map[asset.ID]uint64),
+		GroupTotals: make(map[asset.SerializedKey]uint64),
+	}
+	seen := make(map[asset.PrevID]struct{}, len(r.Files))
+	for idx := range r.Files {
+		file := &r.Files[idx]
+
+		snapshot, err := verifyOwnedFile(
+			ctx, file, challenge, headerVerifier,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proof file %d: %w", idx,
+				err)
+		}
+
+		ownedAsset := snapshot.Asset
+		scriptKey := ownedAsset.ScriptKey.PubKey
+		prevID := asset.PrevID{
+			OutPoint:  snapshot.OutPoint,
+			ID:        ownedAsset.ID(),
+			ScriptKey: asset.ToSerialized(scriptKey),
+		}
+		if _, ok := seen[prevID]; ok {
+			return nil, fmt.Errorf("%w: %v", ErrDuplicateReserve,
+				prevID.ID)
+		}
+		seen[prevID] = struct{}{}
+
+		err = addAmount(summary.Totals, prevID.ID, ownedAsset.Amount)
+		if err != nil {
+			return nil, err
+		}
+		if ownedAsset.GroupKey != nil {
+			groupKey := asset.ToSerialized(
+				&ownedAsset.GroupKey.GroupPubKey,
+			)
+			err = addAmount(
+				summary.GroupTotals, groupKey,
+				ownedAsset.Amount,
+			)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		summary.Assets = append(summary.Assets, snapshot)
+	}
+
+	return summary, nil
+}
+
+// verifyOwnedFile verifies the given proof file in full and makes sure the
+// last proof carries an ownership proof that is bound to the challenge.
+func verifyOwnedFile(ctx context.Context, file *File, challenge [32]byte,
+	headerVerifier HeaderVerifier) (*AssetSnapshot, error) {
+
+	numProofs := file.NumProofs()
+	if numProofs == 0 {
+		return nil, ErrNoProofAvailable
+	}
+
+	var prev *AssetSnapshot
+	for idx := 0; idx < numProofs; idx++ {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+
+		p, err := file.ProofAt(uint32(idx))
+		if err != nil {
+			return nil, err
+		}
+
+		if idx < numProofs-1 {
+			prev, err = p.Verify(ctx, prev, headerVerifier)
+			if err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		// The state transition of the last proof is verified like any
+		// other. Its challenge witness would otherwise only be checked
+		// without a challenge (or not at all), so we verify it against
+		// our challenge separately.
+		challengeWitness := p.ChallengeWitness
+		if len(challengeWitness) == 0 {
+			return nil, ErrMissingChallengeWitness
+		}
+
+		p.ChallengeWitness = nil
+		prev, err = p.Verify(ctx, prev, headerVerifier)
+		if err != nil {
+			return nil, err
+		}
+
+		p.ChallengeWitness = challengeWitness
+		if _, err := p.verifyChallengeWitness(&challenge); err != nil {
+			return nil, fmt.Errorf("invalid ownership proof: %w",
+				err)
+		}
+	}
+
+	return prev, nil
+}
@@ -0,0 +1,188 @@
+package proof
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightninglabs/taproot-assets/address"
+	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightninglabs/taproot-assets/commitment"
+	"github.com/lightninglabs/taproot-assets/internal/test"
+	"github.com/lightninglabs/taproot-assets/tappsbt"
+	"github.com/lightninglabs/taproot-assets/tapscript"
+	"github.com/stretchr/testify/require"
+)
+
+// signOwnershipProof creates an ownership proof for the given asset that is
+// bound to the given challenge.
+func signOwnershipProof(t *testing.T, privKey *btcec.PrivateKey,
+	ownedAsset *asset.Asset, challenge *[32]byte) wire.TxWitness {
+
+	vPkt := tappsbt.OwnershipProofPacket(
+		ownedAsset.Copy(), challenge, &address.MainNetTap,
+	)
+	vIn := vPkt.Inputs[0]
+	newAsset := vPkt.Outputs[0].Asset
+
+	prevAssets := commitment.InputSet{
+		vIn.PrevID: vIn.Asset(),
+	}
+	virtualTx, _, err := tapscript.VirtualTx(newAsset, prevAssets)
+	require.NoError(t, err)
+
+	sigHash, err := tapscript.InputKeySpendSigHash(
+		virtualTx, vIn.Asset(), vIn.PrevID, 0, txscript.SigHashDefault,
+	)
+	require.NoError(t, err)
+
+	// The genesis script key is a BIP-0086 key.
+	sig, err := schnorr.Sign(
+		txscript.TweakTaprootPrivKey(*privKey, nil), sigHash,
+	)
+	require.NoError(t, err)
+
+	return wire.TxWitness{sig.Serialize()}
+}
+
+// ownedProofFile creates a proof file for a new asset along with an ownership
+// proof for the given challenge.
+func ownedProofFile(t *testing.T, amt uint64, challenge *[32]byte) File {
+	genesisProof, privKey := genRandomGenesisWithProof(
+		t, asset.Normal, &amt, nil, true, nil, nil,
+	)
+	genesisProof.ChallengeWitness = signOwnershipProof(
+		t, privKey, &genesisProof.Asset, challenge,
+	)
+
+	file, err := NewFile(V0, genesisProof)
+	require.NoError(t, err)
+
+	return *file
+}
+
+// TestReservesReport tests that reserves reports are only accepted if all
+// ownership proofs are bound to the challenge of the verifier.
+func TestReservesReport(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	challenge := [32]byte(test.RandHash())
+
+	report := &ReservesReport{
+		Challenge: challenge,
+		Files: []File{
+			ownedProofFile(t, 1000, &challenge),
+			ownedProofFile(t, 42, &challenge),
+		},
+	}
+
+	// The report should survive an encoding round trip.
+	var b bytes.Buffer
+	require.NoError(t, report.Encode(&b))
+	var decoded ReservesReport
+	require.NoError(t, decoded.Decode(&b))
+	require.Equal(t, report.Challenge, decoded.Challenge)
+	require.Len(t, decoded.Files, 2)
+
+	summary, err := decoded.Verify(ctx, challenge, MockHeaderVerifier)
+	require.NoError(t, err)
+	require.Len(t, summary.Assets, 2)
+	require.Len(t, summary.Totals, 2)
+	require.Len(t, summary.GroupTotals, 2)
+	for _, snapshot := range summary.Assets {
+		require.Equal(
+			t, snapshot.Asset.Amount,
+			summary.Totals[snapshot.Asset.ID()],
+		)
+	}
+
+	// A report for a different challenge is rejected.
+	otherChallenge := [32]byte(test.RandHash())
+	_, err = report.Verify(ctx, otherChallenge, MockHeaderVerifier)
+	require.ErrorIs(t, err, ErrReservesChallengeMismatch)
+
+	// So is a report that claims our challenge, but contains ownership
+	// proofs created for a different one, or for no challenge at all.
+	for _, wrongChallenge := range []*[32]byte{&otherChallenge, nil} {
+		replayed := &ReservesReport{
+			Challenge: challenge,
+			Files: []File{
+				report.Files[0],
+				ownedProofFile(t, 5, wrongChallenge),
+			},
+		}
+		_, err = replayed.Verify(ctx, challenge, MockHeaderVerifier)
+		require.ErrorContains(t, err, "invalid ownership proof")
+	}
+
+	// Every asset needs an ownership proof.
+	amt := uint64(7)
+	genesisProof, _ := genRandomGenesisWithProof(
+		t, asset.Normal, &amt, nil, true, nil, nil,
+	)
+	unsigned, err := NewFile(V0, genesisProof)
+	require.NoError(t, err)
+	missingWitness := &ReservesReport{
+		Challenge: challenge,
+		Files:     []File{*unsigned},
+	}
+	_, err = missingWitness.Verify(ctx, challenge, MockHeaderVerifier)
+	require.ErrorIs(t, err, ErrMissingChallengeWitness)
+
+	// And the same asset can't be counted twice.
+	duplicate := &ReservesReport{
+		Challenge: challenge,
+		Files:     []File{report.Files[0], report.Files[0]},
+	}
+	_, err = duplicate.Verify(ctx, challenge, MockHeaderVerifier)
+	require.ErrorIs(t, err, ErrDuplicateReserve)
+}
+
+// TestVerifyOwnershipProof tests that a stand-alone ownership proof is only
+// accepted for the challenge it was created with.
+func TestVerifyOwnershipProof(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	challenge := [32]byte(test.RandHash())
+	otherChallenge := [32]byte(test.RandHash())
+
+	boundFile := ownedProofFile(t, 1000, &challenge)
+	bound, err := boundFile.LastProof()
+	require.NoError(t, err)
+
+	snapshot, err := bound.VerifyOwnershipProof(
+		ctx, MockHeaderVerifier, &challenge,
+	)
+	require.NoError(t, err)
+	require.Equal(t, bound.Asset.ID(), snapshot.Asset.ID())
+
+	_, err = bound.VerifyOwnershipProof(
+		ctx, MockHeaderVerifier, &otherChallenge,
+	)
+	require.Error(t, err)
+	_, err = bound.VerifyOwnershipProof(ctx, MockHeaderVerifier, nil)
+	require.Error(t, err)
+
+	// A proof without a challenge is only valid without one.
+	unboundFile := ownedProofFile(t, 1000, nil)
+	unbound, err := unboundFile.LastProof()
+	require.NoError(t, err)
+
+	_, err = unbound.VerifyOwnershipProof(ctx, MockHeaderVerifier, nil)
+	require.NoError(t, err)
+	_, err = unbound.VerifyOwnershipProof(
+		ctx, MockHeaderVerifier, &challenge,
+	)
+	require.Error(t, err)
+
+	// A proof without any challenge witness isn't an ownership proof.
+	unbound.ChallengeWitness = nil
+	_, err = unbound.VerifyOwnershipProof(ctx, MockHeaderVerifier, nil)
+	require.ErrorIs(t, err, ErrMissingChallengeWitness)
+}
@@ -23,6 +23,11 @@ var (
 	ErrInvalidCommitmentProof = errors.New(
 		"invalid Taproot Asset commitment proof",
 	)
+
+	// ErrInvalidAltLeavesProof is an error returned when the alt leaves
+	// revealed in a CommitmentProof aren't committed to in the same
+	// Taproot Asset commitment as the asset.
+	ErrInvalidAltLeavesProof = errors.New("invalid alt leaves proof")
 )
 
 // CommitmentProof represents a full commitment proof for an asset. It can
@@ -35,6 +40,11 @@ type CommitmentProof struct {
 	// hash together with the Taproot Asset commitment leaf node to arrive
 	// at the tapscript root of the expected output.
 	TapSiblingPreimage *commitment.TapscriptPreimage
+
+	// AltLeavesProof is an optional proof of all alt leaves committed to
+	// in the same Taproot Asset commitment as the asset. It is required to
+	// re-create the full Taproot Asset commitment of the output.
+	AltLeavesProof *commitment.AltLeavesProof
 }
 
 // EncodeRecords returns the encoding records for the CommitmentProof.
@@ -45,6 +55,11 @@ func (p CommitmentProof) EncodeRecords() []tlv.Record {
 			&p.TapSiblingPreimage,
 		))
 	}
+	if p.AltLeavesProof != nil {
+		records = append(records, CommitmentProofAltLeavesProofRecord(
+			&p.AltLeavesProof,
+		))
+	}
 	return records
 }
 
@@ -54,6 +69,7 @@ func (p *CommitmentProof) DecodeRecords() []tlv.Record {
 	return append(
 		records,
 		CommitmentProofTapSiblingPreimageRecord(&p.TapSiblingPreimage),
+		CommitmentProofAltLeavesProofRecord(&p.AltLeavesProof),
 	)
 }
 
@@ -269,8 +285,7 @@ func (p TaprootProof) DeriveByAssetInclusion(
 	// the inclusion proof without this information. As the output of the
 	// receiver was created without this present.
 	if asset.HasSplitCommitmentWitness() {
-		asset = asset.Copy()
-		asset.PrevWitnesses[0].SplitCommitment = nil
+		asset = asset.CopyWithoutSplitCommitment()
 	}
 
 	// Use the commitment proof to go from the asset leaf all the way up to
@@ -281,6 +296,24 @@ func (p TaprootProof) DeriveByAssetInclusion(
 	if err != nil {
 		return nil, nil, err
 	}
+
+	// If the proof also reveals the alt leaves of the output, they must be
+	// committed to in the same Taproot Asset commitment as the asset.
+	if p.CommitmentProof.AltLeavesProof != nil {
+		altLeavesProof := p.CommitmentProof.AltLeavesProof
+		altCommitment, err := altLeavesProof.DeriveTapCommitment()
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v",
+				ErrInvalidAltLeavesProof, err)
+		}
+
+		altLeaf := altCommitment.TapLeaf()
+		assetLeaf := tapCommitment.TapLeaf()
+		if altLeaf.TapHash() != assetLeaf.TapHash() {
+			return nil, nil, ErrInvalidAltLeavesProof
+		}
+	}
+
 	pubKey, err := deriveTaprootKeysFromTapCommitment(
 		tapCommitment, p.InternalKey,
 		p.CommitmentProof.TapSiblingPreimage,
@@ -321,28 +354,14 @@ func (p TaprootProof) DeriveByAssetExclusion(assetCommitmentKey,
 	// node, if any, to derive the tapscript root and taproot output key.
 	// We'll do this twice, one for the possible branch sibling and another
 	// for the possible leaf sibling.
-	var (
-		commitment *commitment.TapCommitment
-		err        error
+	//
+	// If there's no asset proof, we verify that the specified key maps to
+	// an empty leaf node (no asset ID sub-tree in the root commitment).
+	// Otherwise, the tree contains the asset ID, but we verify that the
+	// particular asset we care about isn't included.
+	commitment, err := p.CommitmentProof.DeriveByExclusion(
+		tapCommitmentKey, assetCommitmentKey,
 	)
-
-	switch {
-	// In this case, there's no asset proof, so we want to verify that the
-	// specified key maps to an empty leaf node (no asset ID sub-tree in
-	// the root commitment).
-	case p.CommitmentProof.AssetProof == nil:
-		log.Debugf("Deriving commitment by asset commitment exclusion")
-		commitment, err = p.CommitmentProof.
-			DeriveByAssetCommitmentExclusion(tapCommitmentKey)
-
-	// Otherwise, we have an asset proof, which means the tree contains the
-	// asset ID, but we want to verify that the particular asset we care
-	// about isn't included.
-	default:
-		log.Debugf("Deriving commitment by asset exclusion")
-		commitment, err = p.CommitmentProof.
-			DeriveByAssetExclusion(assetCommitmentKey)
-	}
 	if err != nil {
 		return nil, err
 	}
@@ -491,6 +510,33 @@ func (p TaprootProof) DeriveByTapscriptProof() (*btcec.PublicKey, error) {
 	return p.TapscriptProof.DeriveTaprootKeys(p.InternalKey)
 }
 
+// CreateTapExclusionProof creates a proof that the given asset isn't committed
+// to by the Taproot Asset commitment of the anchor output with the given index
+// and internal key. The sibling preimage is the optional tapscript sibling of
+// the Taproot Asset commitment in the anchor output.
+func CreateTapExclusionProof(outputIndex uint32,
+	internalKey *btcec.PublicKey, tapCommitment *commitment.TapCommitment,
+	siblingPreimage *commitment.TapscriptPreimage,
+	a *asset.Asset) (*TaprootProof, error) {
+
+	exclusionProof, err := tapCommitment.ExclusionProof(
+		a.TapCommitmentKey(), a.AssetCommitmentKey(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create exclusion proof for "+
+			"output %d: %w", outputIndex, err)
+	}
+
+	return &TaprootProof{
+		OutputIndex: outputIndex,
+		InternalKey: internalKey,
+		CommitmentProof: &CommitmentProof{
+			Proof:              *exclusionProof,
+			TapSiblingPreimage: siblingPreimage,
+		},
+	}, nil
+}
+
 // AddExclusionProofs adds exclusion proofs to the base proof for each P2TR
 // output in the given PSBT that isn't an anchor output itself. To determine
 // which output is the anchor output, the passed isAnchor function should
@@ -0,0 +1,401 @@
+package proof
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightninglabs/taproot-assets/chanutils"
+	"github.com/lightninglabs/taproot-assets/monitoring"
+	unirpc "github.com/lightninglabs/taproot-assets/taprpc/universerpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// UniverseRpcCourierCfg is the config for the universe RPC proof courier.
+type UniverseRpcCourierCfg struct {
+	// BackoffCfg configures the behaviour of the proof delivery and the
+	// polling for proofs on the receiving side.
+	BackoffCfg *BackoffCfg
+}
+
+// UniverseRpcCourier is an implementation of the Courier interface that
+// deposits the proofs of a transfer in a universe server, where the receiver
+// picks them up. Each state transition proof of the proof file is inserted as
+// a separate universe leaf, keyed by the anchor outpoint and script key of the
+// asset it proves.
+//
+// NOTE: The universe server must index the transfer proofs of the asset for
+// the delivery to succeed, not only its issuance proofs.
+type UniverseRpcCourier struct {
+	// cfg contains the courier's configuration parameters.
+	cfg *UniverseRpcCourierCfg
+
+	// client is the RPC client of the universe server.
+	client unirpc.UniverseClient
+
+	// deliveryLog is the log that the courier will use to record the
+	// attempted delivery of proofs to the receiver.
+	deliveryLog DeliveryLog
+
+	// subscribers is a map of components that want to be notified on new
+	// events, keyed by their subscription ID.
+	subscribers map[uint64]*chanutils.EventReceiver[chanutils.Event]
+
+	// subscriberMtx guards the subscribers map and access to the
+	// subscriptionID.
+	subscriberMtx sync.Mutex
+}
+
+// NewUniverseRpcCourier creates a new universe RPC courier that dials out to
+// the universe server at the given address.
+func NewUniverseRpcCourier(cfg *UniverseRpcCourierCfg, addr *url.URL,
+	deliveryLog DeliveryLog) (*UniverseRpcCourier, error) {
+
+	// All the proofs are verified by the receiver, so we don't need to
+	// authenticate the universe server.
+	creds := credentials.NewTLS(&tls.Config{
+		InsecureSkipVerify: true,
+	})
+	conn, err := grpc.Dial(
+		addr.Host, grpc.WithTransportCredentials(creds),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to universe RPC "+
+			"server: %w", err)
+	}
+
+	return newUniverseRpcCourier(
+		cfg, unirpc.NewUniverseClient(conn), deliveryLog,
+	), nil
+}
+
+// newUniverseRpcCourier creates a new universe RPC courier that uses the given
+// universe client.
+func newUniverseRpcCourier(cfg *UniverseRpcCourierCfg,
+	client unirpc.UniverseClient,
+	deliveryLog DeliveryLog) *UniverseRpcCourier {
+
+	subscribers := make(
+		map[uint64]*chanutils.EventReceiver[chanutils.Event],
+	)
+	return &UniverseRpcCourier{
+		cfg:         cfg,
+		client:      client,
+		deliveryLog: deliveryLog,
+		subscribers: subscribers,
+	}
+}
+
+// universeKey returns the universe key of the leaf that holds the given state
+// transition proof.
+func universeKey(p *Proof) *unirpc.UniverseKey {
+	outPoint := wire.OutPoint{
+		Hash:  p.AnchorTx.TxHash(),
+		Index: p.InclusionProof.OutputIndex,
+	}
+
+	var uniID *unirpc.ID
+	if p.Asset.GroupKey != nil {
+		uniID = universeGroupID(&p.Asset.GroupKey.GroupPubKey)
+	} else {
+		uniID = universeAssetID(p.Asset.ID())
+	}
+
+	return &unirpc.UniverseKey{
+		Id:      uniID,
+		LeafKey: universeLeafKey(outPoint, p.Asset.ScriptKey.PubKey),
+	}
+}
+
+// DeliverProof deposits all state transition proofs of the given proof file
+// in the universe server, from which the receiver can retrieve them.
+func (c *UniverseRpcCourier) DeliverProof(ctx context.Context,
+	recipient Recipient, annotatedProof *AnnotatedProof) error {
+
+	ctxLog := monitoring.LoggerFromContext(ctx, log)
+	ctxLog.Infof("Depositing receiver proof in universe for send of "+
+		"asset_id=%v, amt=%v", recipient.AssetID, recipient.Amount)
+
+	var proofFile File
+	err := proofFile.Decode(bytes.NewReader(annotatedProof.Blob))
+	if err != nil {
+		return fmt.Errorf("unable to decode proof file: %w", err)
+	}
+
+	err = backoffExec(
+		ctx, c.cfg.BackoffCfg, c.publishSubscriberEvent, func() error {
+			err := c.deliveryLog.StoreProofDeliveryAttempt(
+				ctx, annotatedProof.Locator,
+			)
+			if err != nil {
+				return fmt.Errorf("unable to log proof "+
+					"delivery attempt: %w", err)
+			}
+
+			return c.insertProofs(ctx, &proofFile)
+		},
+	)
+	monitoring.ObserveCourierDelivery(err)
+	if err != nil {
+		return fmt.Errorf("proof backoff delivery attempt has "+
+			"failed: %w", err)
+	}
+
+	ctxLog.Infof("Deposited %d proofs in universe",
+		proofFile.NumProofs())
+
+	return nil
+}
+
+// insertProofs inserts all proofs of the given proof file into the universe,
+// starting with the genesis proof. Proofs the universe already knows about are
+// left untouched by the universe server.
+func (c *UniverseRpcCourier) insertProofs(ctx context.Context,
+	proofFile *File) error {
+
+	for idx := 0; idx < proofFile.NumProofs(); idx++ {
+		transitionProof, err := proofFile.ProofAt(uint32(idx))
+		if err != nil {
+			return err
+		}
+		rawProof, err := proofFile.RawProofAt(uint32(idx))
+		if err != nil {
+			return err
+		}
+
+		_, err = c.client.InsertProof(ctx, &unirpc.AssetProof{
+			Key: universeKey(transitionProof),
+			AssetLeaf: &unirpc.AssetLeaf{
+				IssuanceProof: rawProof,
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("unable to insert proof %d into "+
+				"universe: %w", idx, err)
+		}
+	}
+
+	return nil
+}
+
+// ReceiveProof polls the universe server for the proof identified by the
+// given locator until it is available, then retrieves all previous state
+// transition proofs to assemble the full proof file. The locator must specify
+// the outpoint the asset was received in.
+func (c *UniverseRpcCourier) ReceiveProof(ctx context.Context,
+	recipient Recipient, loc Locator) (*AnnotatedProof, error) {
+
+	if loc.OutPoint == nil {
+		return nil, fmt.Errorf("locator outpoint required to " +
+			"receive proof from universe")
+	}
+
+	var uniID *unirpc.ID
+	switch {
+	case loc.GroupKey != nil:
+		uniID = universeGroupID(loc.GroupKey)
+
+	case loc.AssetID != nil:
+		uniID = universeAssetID(*loc.AssetID)
+
+	default:
+		return nil, fmt.Errorf("locator asset ID or group key " +
+			"required to receive proof from universe")
+	}
+
+	log.Infof("Attempting to receive proof for script key %x from "+
+		"universe", recipient.ScriptKey.SerializeCompressed())
+
+	// The sender only deposits the proofs once the transfer confirmed, so
+	// we'll keep polling until the last proof shows up.
+	lastProof, err := c.pollProof(ctx, &unirpc.UniverseKey{
+		Id:      uniID,
+		LeafKey: universeLeafKey(*loc.OutPoint, &loc.ScriptKey),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// We now walk back the proof chain until we arrive at the genesis
+	// proof of the asset.
+	proofs := []Proof{*lastProof}
+	seen := map[wire.OutPoint]struct{}{
+		*loc.OutPoint: {},
+	}
+	for !proofs[0].Asset.HasGenesisWitness() {
+		prevID, err := firstPrevID(&proofs[0].Asset)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := seen[prevID.OutPoint]; ok {
+			return nil, fmt.Errorf("proof chain loops back to "+
+				"outpoint %v", prevID.OutPoint)
+		}
+		seen[prevID.OutPoint] = struct{}{}
+
+		scriptKey, err := btcec.ParsePubKey(prevID.ScriptKey[:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid previous script key: "+
+				"%w", err)
+		}
+
+		prevProof, err := c.fetchProof(ctx, &unirpc.UniverseKey{
+			Id:      uniID,
+			LeafKey: universeLeafKey(prevID.OutPoint, scriptKey),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch previous "+
+				"proof for %v: %w", prevID.OutPoint, err)
+		}
+
+		proofs = append([]Proof{*prevProof}, proofs...)
+	}
+
+	proofFile, err := NewFile(V0, proofs...)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := proofFile.Encode(&buf); err != nil {
+		return nil, err
+	}
+
+	return &AnnotatedProof{
+		Locator: loc,
+		Blob:    buf.Bytes(),
+	}, nil
+}
+
+// pollProof fetches the proof with the given universe key, retrying with an
+// increasing backoff until it is found or the context is canceled.
+func (c *UniverseRpcCourier) pollProof(ctx context.Context,
+	key *unirpc.UniverseKey) (*Proof, error) {
+
+	backoff := c.cfg.BackoffCfg.InitialBackoff
+	for {
+		p, err := c.fetchProof(ctx, key)
+		if err == nil {
+			return p, nil
+		}
+
+		log.Debugf("Proof not yet available in universe, retrying "+
+			"in %v: %v", backoff, err)
+
+		if err := wait(ctx, backoff); err != nil {
+			return nil, err
+		}
+
+		backoff *= 2
+		if backoff > c.cfg.BackoffCfg.MaxBackoff {
+			backoff = c.cfg.BackoffCfg.MaxBackoff
+		}
+		if backoff == 0 {
+			backoff = time.Second
+		}
+	}
+}
+
+// fetchProof fetches and decodes the proof with the given universe key.
+func (c *UniverseRpcCourier) fetchProof(ctx context.Context,
+	key *unirpc.UniverseKey) (*Proof, error) {
+
+	resp, err := c.client.QueryProof(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if resp.AssetLeaf == nil {
+		return nil, fmt.Errorf("universe returned no asset leaf")
+	}
+
+	var p Proof
+	err = p.Decode(bytes.NewReader(resp.AssetLeaf.IssuanceProof))
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode proof: %w", err)
+	}
+
+	return &p, nil
+}
+
+// SetSubscribers sets the subscribers for the courier. This method is
+// thread-safe.
+func (c *UniverseRpcCourier) SetSubscribers(
+	subscribers map[uint64]*chanutils.EventReceiver[chanutils.Event]) {
+
+	c.subscriberMtx.Lock()
+	defer c.subscriberMtx.Unlock()
+
+	c.subscribers = subscribers
+}
+
+// publishSubscriberEvent publishes an event to all subscribers.
+func (c *UniverseRpcCourier) publishSubscriberEvent(event chanutils.Event) {
+	c.subscriberMtx.Lock()
+	defer c.subscriberMtx.Unlock()
+
+	for _, sub := range c.subscribers {
+		sub.NewItemCreated.ChanIn() <- event
+	}
+}
+
+// A compile-time assertion to ensure the UniverseRpcCourier meets the
+// proof.Courier interface.
+var _ Courier[Recipient] = (*UniverseRpcCourier)(nil)
+
+// firstPrevID returns the previous ID of the first input of the state
+// transition that created the given asset. For split assets, this is the first
+// input of the split root asset.
+func firstPrevID(a *asset.Asset) (*asset.PrevID, error) {
+	if a.HasSplitCommitmentWitness() {
+		a = &a.PrevWitnesses[0].SplitCommitment.RootAsset
+	}
+
+	if len(a.PrevWitnesses) == 0 || a.PrevWitnesses[0].PrevID == nil {
+		return nil, fmt.Errorf("asset has no previous input")
+	}
+
+	return a.PrevWitnesses[0].PrevID, nil
+}
+
+// universeAssetID returns the universe ID of the given asset ID.
+func universeAssetID(assetID asset.ID) *unirpc.ID {
+	return &unirpc.ID{
+		Id: &unirpc.ID_AssetId{
+			AssetId: assetID[:],
+		},
+	}
+}
+
+// universeGroupID returns the universe ID of the given group key.
+func universeGroupID(groupKey *btcec.PublicKey) *unirpc.ID {
+	return &unirpc.ID{
+		Id: &unirpc.ID_GroupKey{
+			GroupKey: schnorr.SerializePubKey(groupKey),
+		},
+	}
+}
+
+// universeLeafKey returns the universe leaf key of an asset with the given
+// script key anchored at the given outpoint.
+func universeLeafKey(outPoint wire.OutPoint,
+	scriptKey *btcec.PublicKey) *unirpc.AssetKey {
+
+	return &unirpc.AssetKey{
+		Outpoint: &unirpc.AssetKey_OpStr{
+			OpStr: outPoint.String(),
+		},
+		ScriptKey: &unirpc.AssetKey_ScriptKeyBytes{
+			ScriptKeyBytes: schnorr.SerializePubKey(scriptKey),
+		},
+	}
+}
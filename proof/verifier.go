@@ -2,20 +2,22 @@ package proof
 
 import (
 	"context"
+	"crypto/sha256"
 	"fmt"
 	"io"
-	"runtime"
 	"sync"
+	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/lightninglabs/taproot-assets/address"
 	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightninglabs/taproot-assets/chanutils"
 	"github.com/lightninglabs/taproot-assets/commitment"
+	"github.com/lightninglabs/taproot-assets/monitoring"
 	"github.com/lightninglabs/taproot-assets/tappsbt"
 	"github.com/lightninglabs/taproot-assets/vm"
-	"golang.org/x/sync/errgroup"
 )
 
 // Verifier abstracts away from the task of verifying a proof file blob.
@@ -38,13 +40,31 @@ type BaseVerifier struct {
 func (b *BaseVerifier) Verify(ctx context.Context, blobReader io.Reader,
 	headerVerifier HeaderVerifier) (*AssetSnapshot, error) {
 
+	start := time.Now()
+
 	var proofFile File
 	err := proofFile.Decode(blobReader)
 	if err != nil {
+		monitoring.ObserveProofVerification(start, err)
 		return nil, fmt.Errorf("unable to parse proof: %w", err)
 	}
 
-	return proofFile.Verify(ctx, headerVerifier)
+	ctxLog := monitoring.LoggerFromContext(ctx, log)
+	ctxLog.Debugf("Verifying proof file with %d proofs",
+		proofFile.NumProofs())
+
+	snapshot, err := proofFile.Verify(ctx, headerVerifier)
+	monitoring.ObserveProofVerification(start, err)
+	if err != nil {
+		ctxLog.Debugf("Proof file verification failed after %v: %v",
+			time.Since(start), err)
+
+		return nil, err
+	}
+
+	ctxLog.Debugf("Verified proof file in %v", time.Since(start))
+
+	return snapshot, nil
 }
 
 // verifyTaprootProof attempts to verify a TaprootProof for inclusion or
@@ -159,11 +179,65 @@ func (p *Proof) verifyExclusionProofs() error {
 	return nil
 }
 
+// branchCache caches the results of verifying the input branches of a proof
+// DAG. The same branch can be part of the DAG more than once, for example if
+// the outputs of a split are merged again later on, in which case it is only
+// verified once.
+type branchCache struct {
+	sync.Mutex
+
+	// snapshots maps the hash of the last proof of a verified branch to
+	// the snapshot of its final state.
+	snapshots map[[sha256.Size]byte]*AssetSnapshot
+}
+
+// newBranchCache creates a new, empty branch cache.
+func newBranchCache() *branchCache {
+	return &branchCache{
+		snapshots: make(map[[sha256.Size]byte]*AssetSnapshot),
+	}
+}
+
+// verifyBranch verifies the given input branch, unless the same branch was
+// verified before. As the hash of the last proof of a file commits to all of
+// its proofs, including their own input branches, it identifies the full
+// branch.
+func (c *branchCache) verifyBranch(ctx context.Context, branch *File,
+	headerVerifier HeaderVerifier) (*AssetSnapshot, error) {
+
+	if branch.IsEmpty() {
+		return nil, ErrNoProofAvailable
+	}
+	branchHash := branch.proofs[len(branch.proofs)-1].hash
+
+	c.Lock()
+	snapshot, ok := c.snapshots[branchHash]
+	c.Unlock()
+	if ok {
+		return snapshot, nil
+	}
+
+	// Branches are verified in parallel, so we might end up verifying the
+	// same branch more than once if it's shared by concurrent branches.
+	// That's only a waste of work, so we don't hold the lock meanwhile.
+	snapshot, err := branch.verify(ctx, headerVerifier, c)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Lock()
+	c.snapshots[branchHash] = snapshot
+	c.Unlock()
+
+	return snapshot, nil
+}
+
 // verifyAssetStateTransition verifies an asset's witnesses resulting from a
 // state transition. This method returns the split asset information if this
 // state transition represents an asset split.
 func (p *Proof) verifyAssetStateTransition(ctx context.Context,
-	prev *AssetSnapshot, headerVerifier HeaderVerifier) (bool, error) {
+	prev *AssetSnapshot, headerVerifier HeaderVerifier,
+	branches *branchCache) (bool, error) {
 
 	// Determine whether we have an asset split based on the resulting
 	// asset's witness. If so, extract the root asset from the split asset.
@@ -181,55 +255,62 @@ func (p *Proof) verifyAssetStateTransition(ctx context.Context,
 	}
 
 	// Gather the set of asset inputs leading to the state transition.
-	var prevAssets commitment.InputSet
+	prevAssets := make(commitment.InputSet)
 	if prev != nil {
-		prevAssets = commitment.InputSet{
-			asset.PrevID{
-				OutPoint: p.PrevOut,
-				ID:       prev.Asset.Genesis.ID(),
-				ScriptKey: asset.ToSerialized(
-					prev.Asset.ScriptKey.PubKey,
-				),
-			}: prev.Asset,
-		}
+		prevAssets[asset.PrevID{
+			OutPoint: p.PrevOut,
+			ID:       prev.Asset.Genesis.ID(),
+			ScriptKey: asset.ToSerialized(
+				prev.Asset.ScriptKey.PubKey,
+			),
+		}] = prev.Asset
 	}
 
-	// We'll use an err group to be able to validate all the inputs in
+	// We'll use a worker pool to be able to validate all the inputs in
 	// parallel, limiting the total number of goroutines to the number of
-	// available CPUs. We'll also pass in a context, which'll enable us to
-	// bail out as soon as any of the active goroutines encounters an
-	// error.
-	errGroup, ctx := errgroup.WithContext(ctx)
-	errGroup.SetLimit(runtime.NumCPU())
-
-	var assetsMtx sync.Mutex
-	for _, inputProof := range p.AdditionalInputs {
-		inputProof := inputProof
-
-		errGroup.Go(func() error {
-			result, err := inputProof.Verify(ctx, headerVerifier)
-			if err != nil {
-				return err
-			}
-
-			assetsMtx.Lock()
-			defer assetsMtx.Unlock()
-			prevID := asset.PrevID{
-				OutPoint: result.OutPoint,
-				ID:       result.Asset.Genesis.ID(),
-				ScriptKey: asset.ToSerialized(
-					result.Asset.ScriptKey.PubKey,
-				),
-			}
-			prevAssets[prevID] = result.Asset
-
-			return nil
-		})
-	}
-	if err := errGroup.Wait(); err != nil {
+	// available CPUs. The context passed to the pool enables us to bail out
+	// as soon as any of the active goroutines encounters an error. Each
+	// input is a branch of the proof DAG, which is walked all the way back
+	// to the genesis of the asset of the branch.
+	inputResults, err := chanutils.ParSliceResults(
+		ctx, p.AdditionalInputs,
+		func(ctx context.Context, inputProof File) (*AssetSnapshot,
+			error) {
+
+			return branches.verifyBranch(
+				ctx, &inputProof, headerVerifier,
+			)
+		},
+	)
+	if err != nil {
 		return false, fmt.Errorf("inputs invalid: %w", err)
 	}
 
+	// Each branch must end in an output that is spent by the anchor
+	// transaction of this proof, and must prove a distinct asset input.
+	for idx, result := range inputResults {
+		if !txSpendsPrevOut(&p.AnchorTx, &result.OutPoint) {
+			return false, fmt.Errorf("%w: input %d at %v",
+				ErrInputNotSpent, idx, result.OutPoint)
+		}
+
+		prevID := asset.PrevID{
+			OutPoint: result.OutPoint,
+			ID:       result.Asset.Genesis.ID(),
+			ScriptKey: asset.ToSerialized(
+				result.Asset.ScriptKey.PubKey,
+			),
+		}
+		if _, ok := prevAssets[prevID]; ok {
+			return false, fmt.Errorf("%w: input %d at %v",
+				ErrDuplicateInput, idx, result.OutPoint)
+		}
+
+		// The snapshot might be shared with other parts of the DAG,
+		// so we hand a copy of the asset to the VM.
+		prevAssets[prevID] = result.Asset.Copy()
+	}
+
 	// Spawn a new VM instance to verify the asset's state transition.
 	var splitAssets []*commitment.SplitAsset
 	if splitAsset != nil {
@@ -244,14 +325,15 @@ func (p *Proof) verifyAssetStateTransition(ctx context.Context,
 
 // verifyChallengeWitness verifies the challenge witness by constructing a
 // well-defined 1-in-1-out packet and verifying the witness is valid for that
-// virtual transaction.
-func (p *Proof) verifyChallengeWitness() (bool, error) {
+// virtual transaction. If a challenge is given, the witness must be bound to
+// it.
+func (p *Proof) verifyChallengeWitness(challenge *[32]byte) (bool, error) {
 	// The challenge witness packet always has one input and one output,
 	// independent of how the asset was created. The chain params are only
 	// needed when encoding/decoding a vPkt, so it doesn't matter what
 	// network we choose as we only need the packet to get the witness.
 	vPkt := tappsbt.OwnershipProofPacket(
-		p.Asset.Copy(), &address.MainNetTap,
+		p.Asset.Copy(), challenge, &address.MainNetTap,
 	)
 	vIn := vPkt.Inputs[0]
 	vOut := vPkt.Outputs[0]
@@ -283,6 +365,18 @@ func (p *Proof) verifyMetaReveal() error {
 			metaRevealHash[:], p.Asset.Genesis.MetaHash[:])
 	}
 
+	// A metadata update can only be issued into an existing group, and
+	// must be well formed so wallets can apply it.
+	if p.MetaReveal.Type == MetaGroupUpdate {
+		if p.Asset.GroupKey == nil {
+			return ErrMetaUpdateNotGrouped
+		}
+
+		if _, err := p.MetaReveal.MetaUpdate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -303,6 +397,34 @@ type HeaderVerifier func(blockHeader wire.BlockHeader) error
 func (p *Proof) Verify(ctx context.Context, prev *AssetSnapshot,
 	headerVerifier HeaderVerifier) (*AssetSnapshot, error) {
 
+	return p.verify(ctx, prev, headerVerifier, nil, newBranchCache())
+}
+
+// VerifyOwnershipProof verifies a stand-alone ownership proof, which is the
+// last proof of an asset's proof file with a challenge witness that proves the
+// prover can spend the asset without actually spending it. If a challenge is
+// given, the challenge witness must be bound to it.
+func (p *Proof) VerifyOwnershipProof(ctx context.Context,
+	headerVerifier HeaderVerifier,
+	challenge *[32]byte) (*AssetSnapshot, error) {
+
+	if len(p.ChallengeWitness) == 0 {
+		return nil, ErrMissingChallengeWitness
+	}
+
+	return p.verify(
+		ctx, nil, headerVerifier, challenge, newBranchCache(),
+	)
+}
+
+// verify verifies the proof as described in Verify. If the proof is verified
+// without a previous snapshot and contains a challenge witness, the witness is
+// verified against the given challenge instead of the state transition. The
+// given cache is used to verify each input branch of the proof DAG only once.
+func (p *Proof) verify(ctx context.Context, prev *AssetSnapshot,
+	headerVerifier HeaderVerifier, challenge *[32]byte,
+	branches *branchCache) (*AssetSnapshot, error) {
+
 	// 1. A transaction that spends the previous asset output has a valid
 	// merkle proof within a block in the chain.
 	if prev != nil && p.PrevOut != prev.OutPoint {
@@ -377,21 +499,35 @@ func (p *Proof) Verify(ctx context.Context, prev *AssetSnapshot,
 	var splitAsset bool
 	switch {
 	case prev == nil && p.ChallengeWitness != nil:
-		splitAsset, err = p.verifyChallengeWitness()
+		splitAsset, err = p.verifyChallengeWitness(challenge)
 
 	default:
 		splitAsset, err = p.verifyAssetStateTransition(
-			ctx, prev, headerVerifier,
+			ctx, prev, headerVerifier, branches,
 		)
 	}
 	if err != nil {
 		return nil, err
 	}
 
-	// 6. At this point we know there is an inclusion proof, which must be
-	// a commitment proof. So we can extract the tapscript preimage directly
+	return p.snapshot(tapCommitment, splitAsset), nil
+}
+
+// snapshot returns the AssetSnapshot resulting from the proof, given the
+// Taproot Asset commitment the asset is anchored in.
+func (p *Proof) snapshot(tapCommitment *commitment.TapCommitment,
+	splitAsset bool) *AssetSnapshot {
+
+	// At this point we know there is an inclusion proof, which must be a
+	// commitment proof. So we can extract the tapscript preimage directly
 	// from there.
-	tapscriptPreimage := p.InclusionProof.CommitmentProof.TapSiblingPreimage
+	commitmentProof := p.InclusionProof.CommitmentProof
+	tapscriptPreimage := commitmentProof.TapSiblingPreimage
+
+	var altLeaves []*commitment.AltLeaf
+	if commitmentProof.AltLeavesProof != nil {
+		altLeaves = commitmentProof.AltLeavesProof.Leaves
+	}
 
 	// TODO(roasbeef): need tx index and block height as well
 
@@ -407,9 +543,49 @@ func (p *Proof) Verify(ctx context.Context, prev *AssetSnapshot,
 		InternalKey:      p.InclusionProof.InternalKey,
 		ScriptRoot:       tapCommitment,
 		TapscriptSibling: tapscriptPreimage,
+		AltLeaves:        altLeaves,
 		SplitAsset:       splitAsset,
 		MetaReveal:       p.MetaReveal,
-	}, nil
+		IsBurn:           p.Asset.IsBurn(),
+	}
+}
+
+// Snapshot returns the AssetSnapshot resulting from the proof without verifying
+// the proof. This must only be used for trusted proofs, like proofs that were
+// verified before.
+func (p *Proof) Snapshot() (*AssetSnapshot, error) {
+	_, tapCommitment, err := p.InclusionProof.DeriveByAssetInclusion(
+		&p.Asset,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.snapshot(
+		tapCommitment, p.Asset.HasSplitCommitmentWitness(),
+	), nil
+}
+
+// VerifyTransition verifies the state transition of the current proof, which
+// must spend the asset resulting from the previous proof. Only the current
+// proof is verified, the previous proof is trusted, for example because it was
+// verified as part of a proof file before. If the previous proof is nil, the
+// current proof must be a genesis proof. This allows incremental updates of a
+// proof file to be verified without re-verifying the whole file.
+func VerifyTransition(ctx context.Context, prevProof, currentProof *Proof,
+	headerVerifier HeaderVerifier) (*AssetSnapshot, error) {
+
+	var prev *AssetSnapshot
+	if prevProof != nil {
+		var err error
+		prev, err = prevProof.Snapshot()
+		if err != nil {
+			return nil, fmt.Errorf("invalid previous proof: %w",
+				err)
+		}
+	}
+
+	return currentProof.Verify(ctx, prev, headerVerifier)
 }
 
 // Verify attempts to verify a full proof file starting from the asset's
@@ -422,6 +598,14 @@ func (p *Proof) Verify(ctx context.Context, prev *AssetSnapshot,
 func (f *File) Verify(ctx context.Context, headerVerifier HeaderVerifier) (
 	*AssetSnapshot, error) {
 
+	return f.verify(ctx, headerVerifier, newBranchCache())
+}
+
+// verify verifies the proof file as described in Verify, using the given cache
+// to verify each input branch of the proof DAG only once.
+func (f *File) verify(ctx context.Context, headerVerifier HeaderVerifier,
+	branches *branchCache) (*AssetSnapshot, error) {
+
 	select {
 	case <-ctx.Done():
 		return nil, ctx.Err()
@@ -441,7 +625,9 @@ func (f *File) Verify(ctx context.Context, headerVerifier HeaderVerifier) (
 			return nil, err
 		}
 
-		result, err := decodedProof.Verify(ctx, prev, headerVerifier)
+		result, err := decodedProof.verify(
+			ctx, prev, headerVerifier, nil, branches,
+		)
 		if err != nil {
 			return nil, err
 		}
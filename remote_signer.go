@@ -0,0 +1,487 @@
+package taprootassets
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightninglabs/lndclient"
+	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightninglabs/taproot-assets/tappsbt"
+	"github.com/lightninglabs/taproot-assets/tapscript"
+	"github.com/lightningnetwork/lnd/lnrpc/signrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// RemoteSignPacketKey is the gRPC metadata key under which the encoded
+	// virtual packet of a virtual TX sign request is sent to the remote
+	// signer.
+	RemoteSignPacketKey = "tapd-vpacket-bin"
+
+	// RemoteSignInputIndexKey is the gRPC metadata key under which the
+	// index of the virtual packet input being signed is sent to the remote
+	// signer.
+	RemoteSignInputIndexKey = "tapd-vpacket-input"
+
+	// RemoteSignSigHashKey is the gRPC metadata key under which the
+	// sighash the remote signer is expected to sign is sent.
+	RemoteSignSigHashKey = "tapd-sighash-bin"
+)
+
+// RpcRemoteVirtualTxSigner is an implementation of the tapscript.Signer
+// interface that forwards all virtual transaction signing requests to an
+// external signing service over gRPC. The remote service must implement the
+// SignOutputRaw call of lnd's signrpc.Signer service.
+type RpcRemoteVirtualTxSigner struct {
+	client signrpc.SignerClient
+
+	timeout time.Duration
+}
+
+// NewRpcRemoteVirtualTxSigner returns a new remote tx signer instance that
+// uses the passed gRPC connection to reach the external signing service. Each
+// signing request is aborted after the passed timeout.
+func NewRpcRemoteVirtualTxSigner(conn *grpc.ClientConn,
+	timeout time.Duration) *RpcRemoteVirtualTxSigner {
+
+	return &RpcRemoteVirtualTxSigner{
+		client:  signrpc.NewSignerClient(conn),
+		timeout: timeout,
+	}
+}
+
+// SignVirtualTx generates a signature according to the passed signing
+// descriptor and virtual TX by forwarding the request to the remote signer.
+func (r *RpcRemoteVirtualTxSigner) SignVirtualTx(
+	signDesc *lndclient.SignDescriptor, tx *wire.MsgTx,
+	prevOut *wire.TxOut) (*schnorr.Signature, error) {
+
+	req, err := marshalVirtualTxSignReq(signDesc, tx, prevOut)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create sign request: %w", err)
+	}
+
+	return r.signOutputRaw(context.Background(), req)
+}
+
+// SignVirtualPacketInput generates a signature for the input with the given
+// index of the virtual packet by forwarding the request to the remote signer.
+// Next to the raw virtual TX, the remote signer is sent the encoded virtual
+// packet and the sighash to sign as gRPC metadata. This allows the remote
+// signer to check which assets it is authorizing to spend and create, see
+// ParseRemoteVirtualTxSignRequest.
+func (r *RpcRemoteVirtualTxSigner) SignVirtualPacketInput(
+	vPkt *tappsbt.VPacket, inputIdx int, signDesc *lndclient.SignDescriptor,
+	tx *wire.MsgTx, prevOut *wire.TxOut) (*schnorr.Signature, error) {
+
+	req, err := marshalVirtualTxSignReq(signDesc, tx, prevOut)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create sign request: %w", err)
+	}
+
+	sigHash, err := signReqSigHash(tx, req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to calculate sighash: %w", err)
+	}
+
+	var pktBuf bytes.Buffer
+	if err := vPkt.Serialize(&pktBuf); err != nil {
+		return nil, fmt.Errorf("unable to encode virtual packet: %w",
+			err)
+	}
+
+	ctx := metadata.NewOutgoingContext(
+		context.Background(), metadata.Pairs(
+			RemoteSignPacketKey, pktBuf.String(),
+			RemoteSignInputIndexKey, strconv.Itoa(inputIdx),
+			RemoteSignSigHashKey, string(sigHash),
+		),
+	)
+
+	return r.signOutputRaw(ctx, req)
+}
+
+// signOutputRaw sends the given sign request to the remote signer and parses
+// the single signature it returns.
+func (r *RpcRemoteVirtualTxSigner) signOutputRaw(ctx context.Context,
+	req *signrpc.SignReq) (*schnorr.Signature, error) {
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	resp, err := r.client.SignOutputRaw(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("remote signer unable to sign virtual "+
+			"tx: %w", err)
+	}
+
+	if len(resp.RawSigs) != 1 {
+		return nil, fmt.Errorf("expected 1 signature from remote "+
+			"signer, got %d", len(resp.RawSigs))
+	}
+
+	virtualTxSig, err := schnorr.ParseSignature(resp.RawSigs[0])
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse remote signature: %w",
+			err)
+	}
+
+	return virtualTxSig, nil
+}
+
+// marshalVirtualTxSignReq creates the RPC request for signing the given
+// virtual transaction. In contrast to the requests lnd itself expects, the
+// signing key is always identified by both its public key and its locator,
+// and the full previous output is always included. This gives the remote
+// signer everything it needs to re-derive the key, re-compute the sighash and
+// inspect what it is being asked to sign before producing a signature.
+func marshalVirtualTxSignReq(signDesc *lndclient.SignDescriptor,
+	tx *wire.MsgTx, prevOut *wire.TxOut) (*signrpc.SignReq, error) {
+
+	if signDesc.KeyDesc.PubKey == nil {
+		return nil, fmt.Errorf("signing key must be specified")
+	}
+
+	var txBuf bytes.Buffer
+	if err := tx.Serialize(&txBuf); err != nil {
+		return nil, err
+	}
+
+	var doubleTweak []byte
+	if signDesc.DoubleTweak != nil {
+		doubleTweak = signDesc.DoubleTweak.Serialize()
+	}
+
+	// The output being signed is usually the same as the previous output,
+	// but we fall back to it explicitly if it wasn't set.
+	output := signDesc.Output
+	if output == nil {
+		output = prevOut
+	}
+
+	keyLoc := signDesc.KeyDesc.KeyLocator
+	rpcPrevOut := &signrpc.TxOut{
+		PkScript: prevOut.PkScript,
+		Value:    prevOut.Value,
+	}
+
+	return &signrpc.SignReq{
+		RawTxBytes: txBuf.Bytes(),
+		SignDescs: []*signrpc.SignDescriptor{{
+			KeyDesc: &signrpc.KeyDescriptor{
+				RawKeyBytes: signDesc.KeyDesc.PubKey.
+					SerializeCompressed(),
+				KeyLoc: &signrpc.KeyLocator{
+					KeyFamily: int32(keyLoc.Family),
+					KeyIndex:  int32(keyLoc.Index),
+				},
+			},
+			SingleTweak:   signDesc.SingleTweak,
+			DoubleTweak:   doubleTweak,
+			TapTweak:      signDesc.TapTweak,
+			WitnessScript: signDesc.WitnessScript,
+			SignMethod: lndclient.MarshalSignMethod(
+				signDesc.SignMethod,
+			),
+			Output: &signrpc.TxOut{
+				PkScript: output.PkScript,
+				Value:    output.Value,
+			},
+			Sighash:    uint32(signDesc.HashType),
+			InputIndex: int32(signDesc.InputIndex),
+		}},
+		PrevOutputs: []*signrpc.TxOut{rpcPrevOut},
+	}, nil
+}
+
+// signReqSigHash calculates the sighash of the single input of the given
+// virtual TX that the given sign request asks the remote signer to sign.
+func signReqSigHash(tx *wire.MsgTx, req *signrpc.SignReq) ([]byte, error) {
+	if len(req.SignDescs) != 1 || len(req.PrevOutputs) != 1 {
+		return nil, fmt.Errorf("expected exactly one sign descriptor " +
+			"and previous output")
+	}
+
+	desc := req.SignDescs[0]
+	prevOut := req.PrevOutputs[0]
+	if int(desc.InputIndex) >= len(tx.TxIn) || desc.InputIndex < 0 {
+		return nil, fmt.Errorf("invalid input index %d",
+			desc.InputIndex)
+	}
+
+	prevOutFetcher := txscript.NewCannedPrevOutputFetcher(
+		prevOut.PkScript, prevOut.Value,
+	)
+	sigHashes := txscript.NewTxSigHashes(tx, prevOutFetcher)
+	hashType := txscript.SigHashType(desc.Sighash)
+
+	switch desc.SignMethod {
+	case signrpc.SignMethod_SIGN_METHOD_TAPROOT_SCRIPT_SPEND:
+		return txscript.CalcTapscriptSignaturehash(
+			sigHashes, hashType, tx, int(desc.InputIndex),
+			prevOutFetcher,
+			txscript.NewBaseTapLeaf(desc.WitnessScript),
+		)
+
+	case signrpc.SignMethod_SIGN_METHOD_TAPROOT_KEY_SPEND,
+		signrpc.SignMethod_SIGN_METHOD_TAPROOT_KEY_SPEND_BIP0086:
+
+		return txscript.CalcTaprootSignatureHash(
+			sigHashes, hashType, tx, int(desc.InputIndex),
+			prevOutFetcher,
+		)
+
+	default:
+		return nil, fmt.Errorf("unsupported sign method %v",
+			desc.SignMethod)
+	}
+}
+
+// RemoteVirtualTxSignRequest is the virtual transfer context that is sent to a
+// remote signer along with a virtual TX sign request.
+type RemoteVirtualTxSignRequest struct {
+	// VPacket is the virtual packet the signed virtual TX was created
+	// from.
+	VPacket *tappsbt.VPacket
+
+	// InputIndex is the index of the packet input that is being signed.
+	InputIndex int
+
+	// SigHash is the sighash the remote signer is asked to sign.
+	SigHash []byte
+}
+
+// ParseRemoteVirtualTxSignRequest is meant to be used by a remote signer to
+// extract the virtual packet sent along with the given SignOutputRaw request
+// from the incoming gRPC metadata of the request context. The virtual TX and
+// previous output of the request are re-derived from the packet and the
+// sighash is re-computed, so a signer that only signs the returned sighash
+// after inspecting the returned packet knows exactly which assets it
+// authorizes to spend and create.
+func ParseRemoteVirtualTxSignRequest(ctx context.Context,
+	req *signrpc.SignReq) (*RemoteVirtualTxSignRequest, error) {
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("sign request is missing metadata")
+	}
+
+	mdValue := func(key string) (string, error) {
+		values := md.Get(key)
+		if len(values) != 1 {
+			return "", fmt.Errorf("expected exactly one %v "+
+				"metadata value, got %d", key, len(values))
+		}
+
+		return values[0], nil
+	}
+
+	pktBytes, err := mdValue(RemoteSignPacketKey)
+	if err != nil {
+		return nil, err
+	}
+	idxStr, err := mdValue(RemoteSignInputIndexKey)
+	if err != nil {
+		return nil, err
+	}
+	sigHashStr, err := mdValue(RemoteSignSigHashKey)
+	if err != nil {
+		return nil, err
+	}
+
+	vPkt, err := tappsbt.NewFromRawBytes(
+		bytes.NewReader([]byte(pktBytes)), false,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode virtual packet: %w",
+			err)
+	}
+
+	inputIdx, err := strconv.Atoi(idxStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid input index: %w", err)
+	}
+
+	// The virtual TX we're asked to sign must be the one of the packet
+	// input, so it commits to exactly the assets of the packet.
+	virtualTx, err := tapscript.InputVirtualTx(vPkt, inputIdx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to derive virtual tx: %w", err)
+	}
+
+	var txBuf bytes.Buffer
+	if err := virtualTx.Serialize(&txBuf); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(txBuf.Bytes(), req.RawTxBytes) {
+		return nil, fmt.Errorf("virtual tx doesn't match packet")
+	}
+
+	if len(req.SignDescs) != 1 || len(req.PrevOutputs) != 1 {
+		return nil, fmt.Errorf("expected exactly one sign descriptor " +
+			"and previous output")
+	}
+
+	// The previous output commits to the input asset being spent.
+	prevOut, err := tapscript.InputAssetPrevOut(
+		*vPkt.Inputs[inputIdx].Asset(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to derive previous output: %w",
+			err)
+	}
+
+	outputMatches := func(out *signrpc.TxOut) bool {
+		return out != nil && out.Value == prevOut.Value &&
+			bytes.Equal(out.PkScript, prevOut.PkScript)
+	}
+	if !outputMatches(req.PrevOutputs[0]) ||
+		!outputMatches(req.SignDescs[0].Output) {
+
+		return nil, fmt.Errorf("previous output doesn't match packet")
+	}
+
+	sigHash, err := signReqSigHash(virtualTx, req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to calculate sighash: %w", err)
+	}
+	if !bytes.Equal(sigHash, []byte(sigHashStr)) {
+		return nil, fmt.Errorf("sighash doesn't match packet")
+	}
+
+	return &RemoteVirtualTxSignRequest{
+		VPacket:    vPkt,
+		InputIndex: inputIdx,
+		SigHash:    sigHash,
+	}, nil
+}
+
+// A compile time assertion to ensure RpcRemoteVirtualTxSigner meets the
+// tapscript.PacketSigner interface.
+var _ tapscript.PacketSigner = (*RpcRemoteVirtualTxSigner)(nil)
+
+// RpcRemoteGroupSigner is an implementation of the asset.GroupSignRequester
+// interface that forwards group key signing requests to an external signing
+// service over gRPC. The remote service must implement the SignMessage and
+// SignOutputRaw calls of lnd's signrpc.Signer service. It is meant to be
+// wrapped in an asset.ExternalGenesisSigner, which verifies all signatures
+// returned by the remote signer.
+type RpcRemoteGroupSigner struct {
+	client signrpc.SignerClient
+
+	timeout time.Duration
+}
+
+// NewRpcRemoteGroupSigner returns a new remote group signer instance that
+// uses the passed gRPC connection to reach the external signing service. Each
+// signing request is aborted after the passed timeout.
+func NewRpcRemoteGroupSigner(conn *grpc.ClientConn,
+	timeout time.Duration) *RpcRemoteGroupSigner {
+
+	return &RpcRemoteGroupSigner{
+		client:  signrpc.NewSignerClient(conn),
+		timeout: timeout,
+	}
+}
+
+// RequestGroupSig sends the request to the remote signer and blocks until it
+// responded.
+func (r *RpcRemoteGroupSigner) RequestGroupSig(
+	req *asset.GroupSignRequest) (*asset.GroupSignResponse, error) {
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+
+	switch req.Method {
+	case asset.GroupSignGenesis:
+		id := req.InitialGen.ID()
+		if req.CurrentGen != nil {
+			id = req.CurrentGen.ID()
+		}
+
+		tweak := req.InitialGen.GroupKeyTweak()
+		keyLoc := req.KeyDesc.KeyLocator
+		resp, err := r.client.SignMessage(ctx, &signrpc.SignMessageReq{
+			Msg: id[:],
+			KeyLoc: &signrpc.KeyLocator{
+				KeyFamily: int32(keyLoc.Family),
+				KeyIndex:  int32(keyLoc.Index),
+			},
+			SchnorrSig:         true,
+			SchnorrSigTapTweak: tweak,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("remote signer unable to sign "+
+				"genesis: %w", err)
+		}
+
+		sig, err := schnorr.ParseSignature(resp.Signature)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse remote "+
+				"signature: %w", err)
+		}
+
+		// The remote signer only returns the signature, so we tweak
+		// the group key ourselves.
+		return &asset.GroupSignResponse{
+			GroupPubKey: txscript.ComputeTaprootOutputKey(
+				req.KeyDesc.PubKey, tweak,
+			),
+			Sig: *sig,
+		}, nil
+
+	case asset.GroupSignVirtualTx:
+		if req.VirtualTx == nil || req.PrevOut == nil {
+			return nil, fmt.Errorf("virtual tx sign request " +
+				"missing tx or previous output")
+		}
+
+		signDesc := groupVirtualTxSignDesc(
+			req.KeyDesc, req.TapscriptRoot, req.TapLeaf,
+			req.PrevOut,
+		)
+		rpcReq, err := marshalVirtualTxSignReq(
+			signDesc, req.VirtualTx, req.PrevOut,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create sign "+
+				"request: %w", err)
+		}
+
+		resp, err := r.client.SignOutputRaw(ctx, rpcReq)
+		if err != nil {
+			return nil, fmt.Errorf("remote signer unable to sign "+
+				"group virtual tx: %w", err)
+		}
+
+		if len(resp.RawSigs) != 1 {
+			return nil, fmt.Errorf("expected 1 signature from "+
+				"remote signer, got %d", len(resp.RawSigs))
+		}
+
+		sig, err := schnorr.ParseSignature(resp.RawSigs[0])
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse remote "+
+				"signature: %w", err)
+		}
+
+		return &asset.GroupSignResponse{
+			Sig: *sig,
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown group sign method: %v",
+			req.Method)
+	}
+}
+
+// A compile time assertion to ensure RpcRemoteGroupSigner meets the
+// asset.GroupSignRequester interface.
+var _ asset.GroupSignRequester = (*RpcRemoteGroupSigner)(nil)
@@ -0,0 +1,403 @@
+package taprootassets
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightninglabs/lndclient"
+	"github.com/lightninglabs/taproot-assets/address"
+	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightninglabs/taproot-assets/internal/test"
+	"github.com/lightninglabs/taproot-assets/tappsbt"
+	"github.com/lightninglabs/taproot-assets/tapscript"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnrpc/signrpc"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// mockSignerClient is a signrpc.SignerClient that returns a fixed response to
+// all SignOutputRaw calls and records the requests it received.
+type mockSignerClient struct {
+	signrpc.SignerClient
+
+	rawSigs [][]byte
+	err     error
+
+	// signFn, if set, is called with the request and a context carrying
+	// the metadata of the request as incoming metadata, like a remote
+	// signer would see it, and returns the signatures to respond with.
+	signFn func(context.Context, *signrpc.SignReq) ([][]byte, error)
+
+	reqs        []*signrpc.SignReq
+	mds         []metadata.MD
+	hasDeadline bool
+}
+
+func (m *mockSignerClient) SignOutputRaw(ctx context.Context,
+	req *signrpc.SignReq, _ ...grpc.CallOption) (*signrpc.SignResp,
+	error) {
+
+	md, _ := metadata.FromOutgoingContext(ctx)
+
+	m.reqs = append(m.reqs, req)
+	m.mds = append(m.mds, md)
+	_, m.hasDeadline = ctx.Deadline()
+
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	if m.signFn != nil {
+		rawSigs, err := m.signFn(
+			metadata.NewIncomingContext(ctx, md), req,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		return &signrpc.SignResp{
+			RawSigs: rawSigs,
+		}, nil
+	}
+
+	return &signrpc.SignResp{
+		RawSigs: m.rawSigs,
+	}, nil
+}
+
+// randSignDesc creates a sign descriptor for a taproot key spend with a random
+// key and previous output.
+func randSignDesc(t *testing.T) (*lndclient.SignDescriptor, *wire.MsgTx,
+	*wire.TxOut) {
+
+	tx := wire.NewMsgTx(2)
+	tx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: test.RandOp(t),
+	})
+	tx.AddTxOut(&wire.TxOut{
+		PkScript: test.RandBytes(34),
+		Value:    1000,
+	})
+
+	prevOut := &wire.TxOut{
+		PkScript: test.RandBytes(34),
+		Value:    2000,
+	}
+
+	return &lndclient.SignDescriptor{
+		KeyDesc: keychain.KeyDescriptor{
+			KeyLocator: keychain.KeyLocator{
+				Family: 212,
+				Index:  7,
+			},
+			PubKey: test.RandPubKey(t),
+		},
+		SingleTweak: test.RandBytes(32),
+		TapTweak:    test.RandBytes(32),
+		SignMethod:  input.TaprootKeySpendSignMethod,
+		HashType:    txscript.SigHashDefault,
+		InputIndex:  0,
+	}, tx, prevOut
+}
+
+// TestMarshalVirtualTxSignReq tests that a virtual transaction sign request
+// identifies the signing key by both its public key and locator and always
+// includes the previous output.
+func TestMarshalVirtualTxSignReq(t *testing.T) {
+	t.Parallel()
+
+	signDesc, tx, prevOut := randSignDesc(t)
+
+	req, err := marshalVirtualTxSignReq(signDesc, tx, prevOut)
+	require.NoError(t, err)
+
+	var decodedTx wire.MsgTx
+	require.NoError(t, decodedTx.Deserialize(
+		bytes.NewReader(req.RawTxBytes),
+	))
+	require.Equal(t, tx.TxHash(), decodedTx.TxHash())
+
+	require.Len(t, req.SignDescs, 1)
+	rpcDesc := req.SignDescs[0]
+	require.Equal(
+		t, signDesc.KeyDesc.PubKey.SerializeCompressed(),
+		rpcDesc.KeyDesc.RawKeyBytes,
+	)
+	require.EqualValues(t, 212, rpcDesc.KeyDesc.KeyLoc.KeyFamily)
+	require.EqualValues(t, 7, rpcDesc.KeyDesc.KeyLoc.KeyIndex)
+	require.Equal(t, signDesc.SingleTweak, rpcDesc.SingleTweak)
+	require.Nil(t, rpcDesc.DoubleTweak)
+	require.Equal(t, signDesc.TapTweak, rpcDesc.TapTweak)
+	require.Equal(
+		t, signrpc.SignMethod_SIGN_METHOD_TAPROOT_KEY_SPEND,
+		rpcDesc.SignMethod,
+	)
+	require.EqualValues(t, txscript.SigHashDefault, rpcDesc.Sighash)
+
+	// Without an explicit output, the previous output is signed.
+	require.Equal(t, prevOut.PkScript, rpcDesc.Output.PkScript)
+	require.Equal(t, prevOut.Value, rpcDesc.Output.Value)
+	require.Len(t, req.PrevOutputs, 1)
+	require.Equal(t, prevOut.PkScript, req.PrevOutputs[0].PkScript)
+	require.Equal(t, prevOut.Value, req.PrevOutputs[0].Value)
+
+	// An explicit output and a double tweak are passed on as well.
+	doubleTweak := test.RandPrivKey(t)
+	signDesc.DoubleTweak = doubleTweak
+	signDesc.Output = &wire.TxOut{
+		PkScript: test.RandBytes(34),
+		Value:    3000,
+	}
+
+	req, err = marshalVirtualTxSignReq(signDesc, tx, prevOut)
+	require.NoError(t, err)
+
+	rpcDesc = req.SignDescs[0]
+	require.Equal(t, doubleTweak.Serialize(), rpcDesc.DoubleTweak)
+	require.Equal(t, signDesc.Output.PkScript, rpcDesc.Output.PkScript)
+	require.Equal(t, signDesc.Output.Value, rpcDesc.Output.Value)
+	require.Equal(t, prevOut.Value, req.PrevOutputs[0].Value)
+
+	// A request without a public key can't be created.
+	signDesc.KeyDesc.PubKey = nil
+	_, err = marshalVirtualTxSignReq(signDesc, tx, prevOut)
+	require.ErrorContains(t, err, "signing key must be specified")
+}
+
+// TestRemoteSignVirtualTx tests that the remote virtual tx signer returns the
+// single signature of the remote signer and rejects any other response.
+func TestRemoteSignVirtualTx(t *testing.T) {
+	t.Parallel()
+
+	signDesc, tx, prevOut := randSignDesc(t)
+
+	sig, err := schnorr.Sign(test.RandPrivKey(t), test.RandBytes(32))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name    string
+		rawSigs [][]byte
+		err     error
+		expErr  string
+	}{{
+		name:    "valid signature",
+		rawSigs: [][]byte{sig.Serialize()},
+	}, {
+		name:   "rpc error",
+		err:    errors.New("signer offline"),
+		expErr: "signer offline",
+	}, {
+		name:   "no signature",
+		expErr: "expected 1 signature from remote signer, got 0",
+	}, {
+		name:    "too many signatures",
+		rawSigs: [][]byte{sig.Serialize(), sig.Serialize()},
+		expErr:  "expected 1 signature from remote signer, got 2",
+	}, {
+		name:    "invalid signature",
+		rawSigs: [][]byte{test.RandBytes(10)},
+		expErr:  "unable to parse remote signature",
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &mockSignerClient{
+				rawSigs: tc.rawSigs,
+				err:     tc.err,
+			}
+			signer := &RpcRemoteVirtualTxSigner{
+				client:  client,
+				timeout: time.Minute,
+			}
+
+			virtualTxSig, err := signer.SignVirtualTx(
+				signDesc, tx, prevOut,
+			)
+
+			// The request is always sent with a deadline.
+			require.Len(t, client.reqs, 1)
+			require.True(t, client.hasDeadline)
+
+			if tc.expErr != "" {
+				require.ErrorContains(t, err, tc.expErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(
+				t, sig.Serialize(), virtualTxSig.Serialize(),
+			)
+		})
+	}
+}
+
+// TestRemoteSignVirtualPacket tests that the virtual packet sent along with a
+// virtual TX sign request can be parsed and checked by the remote signer, and
+// that the signature created from it is valid.
+func TestRemoteSignVirtualPacket(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	// We create a packet that spends an asset with a BIP-0086 script key
+	// the remote signer holds the private key for.
+	privKey := test.RandPrivKey(t)
+	keyDesc := keychain.KeyDescriptor{
+		PubKey: privKey.PubKey(),
+		KeyLocator: keychain.KeyLocator{
+			Family: 212,
+			Index:  7,
+		},
+	}
+
+	inputAsset := asset.RandAsset(t, asset.Normal)
+	inputAsset.ScriptKey = asset.NewScriptKeyBip86(keyDesc)
+	inputAsset.GroupKey = nil
+
+	prevID := asset.PrevID{
+		OutPoint:  test.RandOp(t),
+		ID:        inputAsset.ID(),
+		ScriptKey: asset.ToSerialized(inputAsset.ScriptKey.PubKey),
+	}
+
+	vPkt := &tappsbt.VPacket{
+		Inputs: []*tappsbt.VInput{{
+			PrevID: prevID,
+		}},
+		Outputs: []*tappsbt.VOutput{{
+			Interactive:             true,
+			Amount:                  inputAsset.Amount,
+			ScriptKey:               asset.RandScriptKey(t),
+			AnchorOutputIndex:       0,
+			AnchorOutputInternalKey: test.RandPubKey(t),
+		}},
+		ChainParams: &address.RegressionNetTap,
+	}
+	vPkt.SetInputAsset(0, inputAsset, nil)
+	require.NoError(t, tapscript.PrepareOutputAssets(ctx, vPkt))
+
+	// The remote signer only signs after successfully parsing and checking
+	// the packet.
+	var parsed *RemoteVirtualTxSignRequest
+	client := &mockSignerClient{
+		signFn: func(ctx context.Context,
+			req *signrpc.SignReq) ([][]byte, error) {
+
+			var err error
+			parsed, err = ParseRemoteVirtualTxSignRequest(ctx, req)
+			if err != nil {
+				return nil, err
+			}
+
+			sig, err := schnorr.Sign(
+				txscript.TweakTaprootPrivKey(*privKey, nil),
+				parsed.SigHash,
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			return [][]byte{sig.Serialize()}, nil
+		},
+	}
+	signer := &RpcRemoteVirtualTxSigner{
+		client:  client,
+		timeout: time.Minute,
+	}
+
+	err := tapscript.SignVirtualTransaction(
+		vPkt, signer, &ValidatorV0{},
+	)
+	require.NoError(t, err)
+
+	require.Len(t, client.reqs, 1)
+	require.NotNil(t, parsed)
+	require.Equal(t, 0, parsed.InputIndex)
+	require.Len(t, parsed.VPacket.Outputs, 1)
+	require.Equal(
+		t, inputAsset.Amount, parsed.VPacket.Outputs[0].Amount,
+	)
+	require.Equal(
+		t, vPkt.Outputs[0].ScriptKey.PubKey,
+		parsed.VPacket.Outputs[0].ScriptKey.PubKey,
+	)
+
+	// Any packet or sighash that doesn't match the request must be
+	// rejected by the remote signer.
+	req, md := client.reqs[0], client.mds[0]
+
+	sigHash := parsed.SigHash
+	tamperedPkt := parsed.VPacket
+	tamperedPkt.Outputs[0].Asset.ScriptKey = asset.RandScriptKey(t)
+	var tamperedBuf bytes.Buffer
+	require.NoError(t, tamperedPkt.Serialize(&tamperedBuf))
+
+	testCases := []struct {
+		name   string
+		md     metadata.MD
+		expErr string
+	}{{
+		name:   "missing metadata",
+		expErr: "sign request is missing metadata",
+	}, {
+		name: "missing packet",
+		md: metadata.Pairs(
+			RemoteSignInputIndexKey, "0",
+			RemoteSignSigHashKey, string(sigHash),
+		),
+		expErr: "expected exactly one " + RemoteSignPacketKey,
+	}, {
+		name: "tampered packet",
+		md: metadata.Pairs(
+			RemoteSignPacketKey, tamperedBuf.String(),
+			RemoteSignInputIndexKey, "0",
+			RemoteSignSigHashKey, string(sigHash),
+		),
+		expErr: "virtual tx doesn't match packet",
+	}, {
+		name: "invalid input index",
+		md: metadata.Pairs(
+			RemoteSignPacketKey, md.Get(RemoteSignPacketKey)[0],
+			RemoteSignInputIndexKey, "1",
+			RemoteSignSigHashKey, string(sigHash),
+		),
+		expErr: "invalid input index 1",
+	}, {
+		name: "tampered sighash",
+		md: metadata.Pairs(
+			RemoteSignPacketKey, md.Get(RemoteSignPacketKey)[0],
+			RemoteSignInputIndexKey, "0",
+			RemoteSignSigHashKey, string(test.RandBytes(32)),
+		),
+		expErr: "sighash doesn't match packet",
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			reqCtx := ctx
+			if tc.md != nil {
+				reqCtx = metadata.NewIncomingContext(ctx, tc.md)
+			}
+
+			_, err := ParseRemoteVirtualTxSignRequest(reqCtx, req)
+			require.ErrorContains(t, err, tc.expErr)
+		})
+	}
+}
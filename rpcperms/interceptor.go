@@ -64,6 +64,8 @@ var (
 //	  +----------------------------------+
 //	  | RPC State Interceptor            |
 //	  +----------------------------------+
+//	  | Rate Limit Interceptor           |
+//	  +----------------------------------+
 //	  | Macaroon Interceptor             |
 //	  +----------------------------------+
 //	  | Prometheus Interceptor           |
@@ -104,6 +106,10 @@ type InterceptorChain struct {
 	// need to exclude those calls from the mandatory middleware check.
 	macaroonWhitelist map[string]struct{}
 
+	// rateLimiter is the optional rate limiter that rejects requests of
+	// peers that exceed their rate limit or quota.
+	rateLimiter *RateLimiter
+
 	quit chan struct{}
 	sync.RWMutex
 }
@@ -203,6 +209,15 @@ func (r *InterceptorChain) Permissions() map[string][]bakery.Op {
 	return c
 }
 
+// AddRateLimiter adds a rate limiter to the chain. It must be called before
+// the server options are created.
+func (r *InterceptorChain) AddRateLimiter(limiter *RateLimiter) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.rateLimiter = limiter
+}
+
 // CreateServerOpts creates the GRPC server options that can be added to a GRPC
 // server in order to add this InterceptorChain.
 func (r *InterceptorChain) CreateServerOpts() []grpc.ServerOption {
@@ -228,6 +243,20 @@ func (r *InterceptorChain) CreateServerOpts() []grpc.ServerOption {
 		strmInterceptors, r.rpcStateStreamServerInterceptor(),
 	)
 
+	// If a rate limiter is set, we'll reject requests of peers that are
+	// over their limits before spending any effort on authenticating them.
+	r.RLock()
+	rateLimiter := r.rateLimiter
+	r.RUnlock()
+	if rateLimiter != nil {
+		unaryInterceptors = append(
+			unaryInterceptors, rateLimiter.UnaryServerInterceptor(),
+		)
+		strmInterceptors = append(
+			strmInterceptors, rateLimiter.StreamServerInterceptor(),
+		)
+	}
+
 	// We'll add the macaroon interceptors. If macaroons aren't disabled,
 	// then these interceptors will enforce macaroon authentication.
 	unaryInterceptors = append(
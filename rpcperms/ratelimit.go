@@ -0,0 +1,273 @@
+package rpcperms
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	tapmonitoring "github.com/lightninglabs/taproot-assets/monitoring"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// DefaultQuotaPeriod is the default period over which the request
+	// quota of a peer is accounted.
+	DefaultQuotaPeriod = time.Hour
+
+	// peerIdleTimeout is the minimum time a peer needs to be idle for
+	// before its limiter state is discarded.
+	peerIdleTimeout = 10 * time.Minute
+
+	// forwardedForHeader is the metadata key the REST proxy uses to pass
+	// the address of the original HTTP client on to the gRPC server.
+	forwardedForHeader = "x-forwarded-for"
+
+	// reasonRate is the reason reported for requests that exceed the
+	// request rate of a peer.
+	reasonRate = "rate"
+
+	// reasonQuota is the reason reported for requests that exceed the
+	// request quota of a peer.
+	reasonQuota = "quota"
+)
+
+// RateLimitConfig is the config of the per-peer rate limiting of the universe
+// RPCs.
+type RateLimitConfig struct {
+	RequestsPerSecond float64 `long:"requestspersecond" description:"The number of universe RPC requests per second a single peer may make on average. Set to 0 to disable rate limiting."`
+
+	Burst int `long:"burst" description:"The number of universe RPC requests a single peer may make in a burst before being limited to the configured requests per second."`
+
+	Quota uint64 `long:"quota" description:"The maximum number of universe RPC requests a single peer may make within a quota period. Set to 0 to disable the quota."`
+
+	QuotaPeriod time.Duration `long:"quotaperiod" description:"The period over which the request quota of a peer is accounted."`
+}
+
+// DefaultRateLimitConfig returns a rate limit config that doesn't limit any
+// requests.
+func DefaultRateLimitConfig() *RateLimitConfig {
+	return &RateLimitConfig{
+		QuotaPeriod: DefaultQuotaPeriod,
+	}
+}
+
+// Enabled returns true if the config limits requests in any way.
+func (c *RateLimitConfig) Enabled() bool {
+	return c != nil && (c.RequestsPerSecond > 0 || c.Quota > 0)
+}
+
+// peerState is the limiter state of a single peer.
+type peerState struct {
+	// limiter is the token bucket that limits the request rate of the
+	// peer.
+	limiter *rate.Limiter
+
+	// quotaStart is the start of the current quota period of the peer.
+	quotaStart time.Time
+
+	// numRequests is the number of requests the peer made in the current
+	// quota period.
+	numRequests uint64
+
+	// lastSeen is the time of the last request of the peer.
+	lastSeen time.Time
+}
+
+// RateLimiter limits the rate of requests and the total number of requests
+// per quota period that a single peer can make to a set of RPC methods. Peers
+// are identified by their IP address.
+type RateLimiter struct {
+	cfg RateLimitConfig
+
+	// methods is the set of RPC methods that are limited.
+	methods map[string]struct{}
+
+	// peers is the limiter state of every peer, keyed by IP address.
+	peers map[string]*peerState
+
+	// lastPrune is the time idle peers were last discarded.
+	lastPrune time.Time
+
+	mu sync.Mutex
+}
+
+// NewRateLimiter creates a new rate limiter that limits calls to the given
+// RPC methods according to the given config.
+func NewRateLimiter(cfg RateLimitConfig, methods []string) *RateLimiter {
+	methodSet := make(map[string]struct{}, len(methods))
+	for _, method := range methods {
+		methodSet[method] = struct{}{}
+	}
+
+	if cfg.QuotaPeriod <= 0 {
+		cfg.QuotaPeriod = DefaultQuotaPeriod
+	}
+
+	return &RateLimiter{
+		cfg:       cfg,
+		methods:   methodSet,
+		peers:     make(map[string]*peerState),
+		lastPrune: time.Now(),
+	}
+}
+
+// allow returns a non-nil error if the given peer isn't allowed to call the
+// given RPC method right now.
+func (r *RateLimiter) allow(peerKey, fullMethod string) error {
+	if _, ok := r.methods[fullMethod]; !ok {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	r.pruneIdlePeers(now)
+
+	state, ok := r.peers[peerKey]
+	if !ok {
+		state = &peerState{
+			quotaStart: now,
+		}
+		if r.cfg.RequestsPerSecond > 0 {
+			burst := r.cfg.Burst
+			if burst < 1 {
+				burst = 1
+			}
+
+			state.limiter = rate.NewLimiter(
+				rate.Limit(r.cfg.RequestsPerSecond), burst,
+			)
+		}
+		r.peers[peerKey] = state
+	}
+	state.lastSeen = now
+
+	if state.limiter != nil && !state.limiter.AllowN(now, 1) {
+		tapmonitoring.ObserveRateLimited(reasonRate)
+
+		return status.Errorf(codes.ResourceExhausted, "rate limit of "+
+			"%v requests per second exceeded",
+			r.cfg.RequestsPerSecond)
+	}
+
+	if r.cfg.Quota == 0 {
+		return nil
+	}
+
+	if now.Sub(state.quotaStart) >= r.cfg.QuotaPeriod {
+		state.quotaStart = now
+		state.numRequests = 0
+	}
+
+	if state.numRequests >= r.cfg.Quota {
+		tapmonitoring.ObserveRateLimited(reasonQuota)
+
+		resetIn := state.quotaStart.Add(r.cfg.QuotaPeriod).Sub(now)
+		return status.Errorf(codes.ResourceExhausted, "quota of %d "+
+			"requests per %v exceeded, resets in %v", r.cfg.Quota,
+			r.cfg.QuotaPeriod, resetIn.Round(time.Second))
+	}
+	state.numRequests++
+
+	return nil
+}
+
+// pruneIdlePeers discards the state of all peers that haven't made a request
+// in a while. A discarded peer starts over with a full token bucket and a new
+// quota period, so we only discard peers once both would have been reset
+// anyway. The caller must hold the mutex.
+func (r *RateLimiter) pruneIdlePeers(now time.Time) {
+	idleTimeout := peerIdleTimeout
+	if r.cfg.Quota > 0 && r.cfg.QuotaPeriod > idleTimeout {
+		idleTimeout = r.cfg.QuotaPeriod
+	}
+
+	if now.Sub(r.lastPrune) < idleTimeout {
+		return
+	}
+	r.lastPrune = now
+
+	for peerKey, state := range r.peers {
+		if now.Sub(state.lastSeen) >= idleTimeout {
+			delete(r.peers, peerKey)
+		}
+	}
+}
+
+// peerKey returns the key that identifies the peer that made the request of
+// the given context. Requests proxied by the local REST proxy are attributed
+// to the original HTTP client.
+func peerKey(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		host = p.Addr.String()
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || !ip.IsLoopback() {
+		return host
+	}
+
+	// The REST proxy appends the address of the HTTP client it received
+	// the request from to the header, so only the last entry can be
+	// trusted.
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return host
+	}
+	forwarded := md.Get(forwardedForHeader)
+	if len(forwarded) == 0 {
+		return host
+	}
+
+	addrs := strings.Split(forwarded[len(forwarded)-1], ",")
+	if client := strings.TrimSpace(addrs[len(addrs)-1]); client != "" {
+		return client
+	}
+
+	return host
+}
+
+// UnaryServerInterceptor is a GRPC interceptor that rejects requests of peers
+// that exceeded their rate limit or quota.
+func (r *RateLimiter) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+
+		err := r.allow(peerKey(ctx), info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor is a GRPC interceptor that rejects streams of peers
+// that exceeded their rate limit or quota.
+func (r *RateLimiter) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream,
+		info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+
+		err := r.allow(peerKey(ss.Context()), info.FullMethod)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, ss)
+	}
+}
@@ -7,7 +7,11 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -16,6 +20,8 @@ import (
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/psbt"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/davecgh/go-spew/spew"
@@ -40,14 +46,24 @@ import (
 	"github.com/lightningnetwork/lnd/build"
 	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/lightningnetwork/lnd/lnrpc/walletrpc"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/signal"
 	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 const (
 	// tapdMacaroonLocation is the value we use for the tapd macaroons'
 	// "Location" field when baking them.
 	tapdMacaroonLocation = "tapd"
+
+	// universeReadOnlyMacaroon is the file name of the macaroon of the
+	// universe read-only role.
+	universeReadOnlyMacaroon = "universe-readonly.macaroon"
+
+	// universePushMacaroon is the file name of the macaroon of the universe
+	// proof push role.
+	universePushMacaroon = "universe-push.macaroon"
 )
 
 // rpcServer is the main RPC server for the Taproot Assets daemon that handles
@@ -121,6 +137,13 @@ func (r *rpcServer) RegisterWithGrpcServer(grpcServer *grpc.Server) error {
 	wrpc.RegisterAssetWalletServer(grpcServer, r)
 	mintrpc.RegisterMintServer(grpcServer, r)
 	unirpc.RegisterUniverseServer(grpcServer, r)
+
+	// Register the standard gRPC health service, which reports whether
+	// any of our subsystems is wedged.
+	healthpb.RegisterHealthServer(
+		grpcServer, r.cfg.HealthReporter.Server(),
+	)
+
 	return nil
 }
 
@@ -290,6 +313,8 @@ func (r *rpcServer) MintAsset(ctx context.Context,
 		AssetName:      req.Asset.Name,
 		Amount:         uint64(req.Asset.Amount),
 		EnableEmission: req.EnableEmission,
+		DecimalDisplay: req.Asset.DecimalDisplay,
+		Editions:       req.Asset.Editions,
 	}
 
 	// If a group key is provided, parse the provided group public key
@@ -333,7 +358,23 @@ func (r *rpcServer) MintAsset(ctx context.Context,
 		}
 	}
 
-	updates, err := r.cfg.AssetMinter.QueueNewSeedling(seedling)
+	// If a batch key is given, the asset is added to that batch, which
+	// is created first if it doesn't exist yet.
+	var batchKey *btcec.PublicKey
+	if len(req.BatchKey) != 0 {
+		var err error
+		batchKey, err = btcec.ParsePubKey(req.BatchKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid batch key: %w", err)
+		}
+
+		err = r.ensurePendingBatch(batchKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	updates, err := r.cfg.AssetMinter.QueueNewSeedling(seedling, batchKey)
 	if err != nil {
 		return nil, fmt.Errorf("unable to mint new asset: %w", err)
 	}
@@ -356,12 +397,73 @@ func (r *rpcServer) MintAsset(ctx context.Context,
 	}
 }
 
+// ensurePendingBatch makes sure a pending batch with the given key exists, by
+// creating a new batch with the key as its internal key if there's none yet.
+func (r *rpcServer) ensurePendingBatch(batchKey *btcec.PublicKey) error {
+	pendingBatches, err := r.cfg.AssetMinter.PendingBatches()
+	if err != nil {
+		return fmt.Errorf("unable to list pending batches: %w", err)
+	}
+
+	for _, batch := range pendingBatches {
+		if batch.BatchKey.PubKey.IsEqual(batchKey) {
+			return nil
+		}
+	}
+
+	_, err = r.cfg.AssetMinter.NewBatch(&keychain.KeyDescriptor{
+		PubKey: batchKey,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to create batch: %w", err)
+	}
+
+	return nil
+}
+
 // FinalizeBatch attempts to finalize the current pending batch.
 func (r *rpcServer) FinalizeBatch(_ context.Context,
-	_ *mintrpc.FinalizeBatchRequest) (*mintrpc.FinalizeBatchResponse,
+	req *mintrpc.FinalizeBatchRequest) (*mintrpc.FinalizeBatchResponse,
 	error) {
 
-	batchKey, err := r.cfg.AssetMinter.FinalizeBatch()
+	// A zero fee rate means the minter should estimate one itself.
+	var feeRate *chainfee.SatPerKWeight
+	if req.SatPerVbyte != 0 {
+		satPerKw := chainfee.SatPerKVByte(
+			req.SatPerVbyte * 1000,
+		).FeePerKWeight()
+		feeRate = &satPerKw
+	}
+
+	tapSibling, _, err := commitment.MaybeDecodeTapscriptPreimage(
+		req.TapscriptSibling,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tapscript sibling: %w", err)
+	}
+
+	// Without any constraints, the minter may fund the genesis
+	// transaction from any UTXOs of the default wallet account.
+	var coinControl *tapgarden.CoinControl
+	if len(req.Outpoints) > 0 || req.Account != "" {
+		coinControl = &tapgarden.CoinControl{
+			Account: req.Account,
+		}
+		for _, rpcOutpoint := range req.Outpoints {
+			outpoint, err := unmarshalOutPoint(rpcOutpoint)
+			if err != nil {
+				return nil, fmt.Errorf("invalid outpoint %v: "+
+					"%w", rpcOutpoint, err)
+			}
+			coinControl.Outpoints = append(
+				coinControl.Outpoints, outpoint,
+			)
+		}
+	}
+
+	batchKey, err := r.cfg.AssetMinter.FinalizeBatch(
+		nil, feeRate, tapSibling, coinControl,
+	)
 	if err != nil {
 		return nil, fmt.Errorf("unable to finalize batch: %w", err)
 	}
@@ -381,7 +483,7 @@ func (r *rpcServer) CancelBatch(_ context.Context,
 	_ *mintrpc.CancelBatchRequest) (*mintrpc.CancelBatchResponse,
 	error) {
 
-	batchKey, err := r.cfg.AssetMinter.CancelBatch()
+	batchKey, err := r.cfg.AssetMinter.CancelBatch(nil)
 	if err != nil {
 		return nil, fmt.Errorf("unable to cancel batch: %w", err)
 	}
@@ -490,19 +592,196 @@ func (r *rpcServer) checkBalanceOverflow(ctx context.Context,
 	return nil
 }
 
+// selectGroupTranche selects the asset ID of the tranche of the given group
+// that has the largest balance, as long as that balance covers the amount to
+// send.
+func (r *rpcServer) selectGroupTranche(ctx context.Context,
+	groupKey *btcec.PublicKey, amount uint64) (asset.ID, error) {
+
+	balances, err := r.cfg.AssetStore.QueryBalancesByAsset(ctx, nil)
+	if err != nil {
+		return asset.ID{}, fmt.Errorf("unable to query asset "+
+			"balances: %w", err)
+	}
+
+	var (
+		selectedID      asset.ID
+		selectedBalance uint64
+	)
+	for assetID, balance := range balances {
+		if balance.Balance < amount ||
+			balance.Balance <= selectedBalance {
+
+			continue
+		}
+
+		assetGroup, err := r.cfg.TapAddrBook.QueryAssetGroup(
+			ctx, assetID,
+		)
+		if err != nil {
+			return asset.ID{}, fmt.Errorf("unable to query asset "+
+				"group: %w", err)
+		}
+		if assetGroup.GroupKey == nil ||
+			!assetGroup.GroupPubKey.IsEqual(groupKey) {
+
+			continue
+		}
+
+		selectedID = assetID
+		selectedBalance = balance.Balance
+	}
+
+	if selectedBalance == 0 {
+		return asset.ID{}, fmt.Errorf("no asset of group %x with "+
+			"balance of at least %d",
+			groupKey.SerializeCompressed(), amount)
+	}
+
+	return selectedID, nil
+}
+
+// fetchGroupAddr returns our own copy of the given address that accepts any
+// asset of a group, which unlike the decoded address knows the asset ID the
+// address was created for.
+func (r *rpcServer) fetchGroupAddr(ctx context.Context,
+	addr *address.Tap) (*address.AddrWithKeyInfo, error) {
+
+	groupAddrs, err := r.cfg.AddrBook.ListAddrs(ctx, address.QueryParams{
+		GroupOnly: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list group addrs: %w", err)
+	}
+
+	for idx := range groupAddrs {
+		ownAddr := &groupAddrs[idx]
+		if ownAddr.ScriptKey.IsEqual(&addr.ScriptKey) &&
+			ownAddr.InternalKey.IsEqual(&addr.InternalKey) {
+
+			return ownAddr, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown group addr: %w", address.ErrNoAddr)
+}
+
 // ListAssets lists the set of assets owned by the target daemon.
 func (r *rpcServer) ListAssets(ctx context.Context,
 	req *taprpc.ListAssetRequest) (*taprpc.ListAssetResponse, error) {
 
-	rpcAssets, err := r.fetchRpcAssets(
-		ctx, req.WithWitness, req.IncludeSpent,
+	query, err := unmarshalAssetQuery(req)
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := unmarshalPageParams(
+		req.Limit, req.Cursor, req.SortDescending,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	assets, nextCursor, err := r.cfg.AssetStore.FetchAssetsPage(
+		ctx, req.IncludeSpent, query, page,
 	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read chain assets: %w", err)
+	}
+
+	rpcAssets, err := r.marshalChainAssets(ctx, assets, req.WithWitness)
 	if err != nil {
 		return nil, err
 	}
 
 	return &taprpc.ListAssetResponse{
-		Assets: rpcAssets,
+		Assets:     rpcAssets,
+		NextCursor: nextCursor,
+	}, nil
+}
+
+// unmarshalAssetQuery parses the filters and sort order of the given asset
+// listing request.
+func unmarshalAssetQuery(
+	req *taprpc.ListAssetRequest) (*tapdb.AssetQueryFilters, error) {
+
+	query := &tapdb.AssetQueryFilters{
+		CommitmentConstraints: tapfreighter.CommitmentConstraints{
+			MinAmt: req.MinAmount,
+		},
+		SpentOnly:    req.SpentOnly,
+		SortByAmount: req.SortByAmount,
+	}
+
+	if len(req.AssetId) != 0 {
+		if len(req.AssetId) != sha256.Size {
+			return nil, fmt.Errorf("asset ID must be 32 bytes")
+		}
+
+		var assetID asset.ID
+		copy(assetID[:], req.AssetId)
+		query.AssetID = &assetID
+	}
+
+	if len(req.GroupKey) != 0 {
+		groupKey, err := btcec.ParsePubKey(req.GroupKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid group key: %w", err)
+		}
+
+		query.GroupKey = groupKey
+	}
+
+	if len(req.ScriptKey) != 0 {
+		scriptKey, err := btcec.ParsePubKey(req.ScriptKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid script key: %w", err)
+		}
+
+		query.ScriptKey = scriptKey
+	}
+
+	switch req.ScriptKeyType {
+	case taprpc.ScriptKeyType_SCRIPT_KEY_TYPE_ANY:
+		query.ScriptKeyType = tapdb.ScriptKeyTypeAny
+
+	case taprpc.ScriptKeyType_SCRIPT_KEY_TYPE_BIP86:
+		query.ScriptKeyType = tapdb.ScriptKeyTypeBip86
+
+	case taprpc.ScriptKeyType_SCRIPT_KEY_TYPE_SCRIPT_PATH:
+		query.ScriptKeyType = tapdb.ScriptKeyTypeScriptPath
+
+	default:
+		return nil, fmt.Errorf("unknown script key type: %v",
+			req.ScriptKeyType)
+	}
+
+	if req.AnchorOutpoint != "" {
+		anchorPoint, err := unmarshalOutPoint(req.AnchorOutpoint)
+		if err != nil {
+			return nil, fmt.Errorf("invalid anchor outpoint: %w",
+				err)
+		}
+
+		query.AnchorPoint = &anchorPoint
+	}
+
+	return query, nil
+}
+
+// unmarshalPageParams parses the page of a listing request.
+func unmarshalPageParams(limit uint32, cursor []byte,
+	descending bool) (tapdb.PageParams, error) {
+
+	if limit > math.MaxInt32 {
+		return tapdb.PageParams{}, fmt.Errorf("limit must not exceed "+
+			"%d", math.MaxInt32)
+	}
+
+	return tapdb.PageParams{
+		Limit:      int32(limit),
+		Cursor:     cursor,
+		Descending: descending,
 	}, nil
 }
 
@@ -514,8 +793,17 @@ func (r *rpcServer) fetchRpcAssets(ctx context.Context,
 		return nil, fmt.Errorf("unable to read chain assets: %w", err)
 	}
 
+	return r.marshalChainAssets(ctx, assets, withWitness)
+}
+
+// marshalChainAssets converts the given chain assets to their RPC
+// representation.
+func (r *rpcServer) marshalChainAssets(ctx context.Context,
+	assets []*tapdb.ChainAsset, withWitness bool) ([]*taprpc.Asset, error) {
+
 	rpcAssets := make([]*taprpc.Asset, len(assets))
 	for i, a := range assets {
+		var err error
 		rpcAssets[i], err = r.marshalChainAsset(ctx, a, withWitness)
 		if err != nil {
 			return nil, fmt.Errorf("unable to marshal asset: %w",
@@ -534,6 +822,8 @@ func (r *rpcServer) marshalChainAsset(ctx context.Context, a *tapdb.ChainAsset,
 		return nil, err
 	}
 
+	rpcAsset.DecimalDisplay = a.DecimalDisplay
+
 	var anchorTxBytes []byte
 	if a.AnchorTx != nil {
 		var anchorTxBuf bytes.Buffer
@@ -595,6 +885,7 @@ func MarshalAsset(ctx context.Context, a *asset.Asset,
 		ScriptKey:        a.ScriptKey.PubKey.SerializeCompressed(),
 		ScriptKeyIsLocal: scriptKeyIsLocal,
 		IsSpent:          isSpent,
+		Edition:          a.Edition,
 	}
 
 	if a.GroupKey != nil {
@@ -855,13 +1146,44 @@ func (r *rpcServer) ListTransfers(ctx context.Context,
 	in *taprpc.ListTransfersRequest) (*taprpc.ListTransfersResponse,
 	error) {
 
-	parcels, err := r.cfg.AssetStore.QueryParcels(ctx, false)
+	filters := tapdb.TransferFilters{
+		PendingOnly: in.PendingOnly,
+	}
+	if len(in.AssetId) != 0 {
+		if len(in.AssetId) != sha256.Size {
+			return nil, fmt.Errorf("asset ID must be 32 bytes")
+		}
+
+		var assetID asset.ID
+		copy(assetID[:], in.AssetId)
+		filters.AssetID = &assetID
+	}
+	if in.AnchorTxid != "" {
+		txid, err := chainhash.NewHashFromStr(in.AnchorTxid)
+		if err != nil {
+			return nil, fmt.Errorf("invalid anchor txid: %w", err)
+		}
+
+		filters.AnchorTxHash = txid
+	}
+
+	page, err := unmarshalPageParams(
+		in.Limit, in.Cursor, in.SortDescending,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	parcels, nextCursor, err := r.cfg.AssetStore.QueryTransfers(
+		ctx, filters, page,
+	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query parcels: %w", err)
 	}
 
 	resp := &taprpc.ListTransfersResponse{
-		Transfers: make([]*taprpc.AssetTransfer, len(parcels)),
+		Transfers:  make([]*taprpc.AssetTransfer, len(parcels)),
+		NextCursor: nextCursor,
 	}
 
 	for idx := range parcels {
@@ -952,14 +1274,67 @@ func (r *rpcServer) NewAddr(ctx context.Context,
 		return nil, fmt.Errorf("invalid tapscript sibling: %w", err)
 	}
 
+	// If no proof courier address was specified, we'll advertise our
+	// default courier, if one is configured.
+	courierAddr := r.cfg.DefaultProofCourierAddr
+	if in.ProofCourierAddr != "" {
+		courierAddr, err = proof.ParseCourierAddrString(
+			in.ProofCourierAddr,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proof courier "+
+				"address: %w", err)
+		}
+	}
+	addrOpts := []address.NewAddrOpt{
+		address.WithProofCourierAddr(courierAddr),
+	}
+
+	// The asset ID of an address that accepts any asset of a group only
+	// selects the group.
+	if in.AnyAssetInGroup {
+		addrOpts = append(addrOpts, address.WithAnyAssetInGroup())
+	}
+
+	if in.ExpiryUnixSeconds != 0 {
+		expiry := time.Unix(int64(in.ExpiryUnixSeconds), 0)
+		if !expiry.After(time.Now()) {
+			return nil, fmt.Errorf("address expiry must be in " +
+				"the future")
+		}
+
+		addrOpts = append(addrOpts, address.WithExpiry(expiry))
+	}
+	if in.SingleUse {
+		addrOpts = append(addrOpts, address.WithSingleUse())
+	}
+
 	var addr *address.AddrWithKeyInfo
 	switch {
+	// A static address was requested, its keys are always derived by the
+	// address book.
+	case in.Static:
+		if in.ScriptKey != nil || in.InternalKey != nil ||
+			tapscriptSibling != nil {
+
+			return nil, fmt.Errorf("static address cannot have " +
+				"custom keys or a tapscript sibling")
+		}
+
+		addr, err = r.cfg.AddrBook.NewStaticAddress(
+			ctx, assetID, in.Amt, addrOpts...,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to make new static "+
+				"addr: %w", err)
+		}
+
 	// No key was specified, we'll let the address book derive them.
 	case in.ScriptKey == nil && in.InternalKey == nil:
 		// Now that we have all the params, we'll try to add a new
 		// address to the addr book.
 		addr, err = r.cfg.AddrBook.NewAddress(
-			ctx, assetID, in.Amt, tapscriptSibling,
+			ctx, assetID, in.Amt, tapscriptSibling, addrOpts...,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("unable to make new addr: %w",
@@ -999,7 +1374,7 @@ func (r *rpcServer) NewAddr(ctx context.Context,
 		// address to the addr book.
 		addr, err = r.cfg.AddrBook.NewAddressWithKeys(
 			ctx, assetID, in.Amt, *scriptKey, internalKey,
-			tapscriptSibling,
+			tapscriptSibling, addrOpts...,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("unable to make new addr: %w",
@@ -1130,101 +1505,342 @@ func (r *rpcServer) ImportProof(ctx context.Context,
 	return &taprpc.ImportProofResponse{}, nil
 }
 
-// AddrReceives lists all receives for incoming asset transfers for addresses
-// that were created previously.
-func (r *rpcServer) AddrReceives(ctx context.Context,
-	in *taprpc.AddrReceivesRequest) (*taprpc.AddrReceivesResponse,
-	error) {
-
-	var sqlQuery address.EventQueryParams
-
-	if len(in.FilterAddr) > 0 {
-		tapParams := address.ParamsForChain(r.cfg.ChainParams.Name)
-
-		addr, err := address.DecodeAddress(in.FilterAddr, &tapParams)
-		if err != nil {
-			return nil, fmt.Errorf("unable to decode addr: %w", err)
-		}
-
-		// Now that we've decoded the address, we'll check to make sure
-		// that we can fetch the genesis for this address. Otherwise,
-		// that means we don't know anything about what it should look
-		// like on chain (the genesis is required to derive the taproot
-		// output key).
-		assetGroup, err := r.cfg.TapAddrBook.QueryAssetGroup(
-			ctx, addr.AssetID,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("unknown asset=%x: %w",
-				addr.AssetID[:], err)
+// ExportProofBundle exports the proof files of all assets owned by the daemon,
+// or only of those with the given asset IDs, as a single compressed proof
+// bundle.
+func (r *rpcServer) ExportProofBundle(ctx context.Context,
+	in *taprpc.ExportProofBundleRequest) (*taprpc.ProofBundle, error) {
+
+	// If only the proofs of specific assets are requested, we keep track
+	// of which of them we've found, so we can fail if one is unknown.
+	assetIDs := make(map[asset.ID]bool, len(in.AssetIds))
+	for _, rawAssetID := range in.AssetIds {
+		if len(rawAssetID) != sha256.Size {
+			return nil, fmt.Errorf("asset ID must be 32 bytes")
 		}
 
-		rpcsLog.Tracef("Listing receives for group: %v",
-			spew.Sdump(assetGroup))
+		var assetID asset.ID
+		copy(assetID[:], rawAssetID)
+		assetIDs[assetID] = false
+	}
 
-		addr.AttachGenesis(*assetGroup.Genesis)
+	assets, err := r.cfg.AssetStore.FetchAllAssets(ctx, false, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read chain assets: %w", err)
+	}
 
-		if assetGroup.GroupKey != nil {
-			addr.AttachGroupSig(assetGroup.GroupKey.Sig)
+	// Multiple assets can share the same proof file, so we make sure to
+	// only export each of them once.
+	var (
+		locators     []proof.Locator
+		seenLocators = make(map[[32]byte]struct{})
+	)
+	for _, a := range assets {
+		assetID := a.ID()
+		if len(assetIDs) > 0 {
+			if _, ok := assetIDs[assetID]; !ok {
+				continue
+			}
+			assetIDs[assetID] = true
 		}
 
-		taprootOutputKey, err := addr.TaprootOutputKey()
-		if err != nil {
-			return nil, fmt.Errorf("error deriving Taproot key: %w",
-				err)
+		loc := proof.Locator{
+			AssetID:   &assetID,
+			ScriptKey: *a.ScriptKey.PubKey,
 		}
+		if _, ok := seenLocators[loc.Hash()]; ok {
+			continue
+		}
+		seenLocators[loc.Hash()] = struct{}{}
 
-		sqlQuery.AddrTaprootOutputKey = schnorr.SerializePubKey(
-			taprootOutputKey,
-		)
+		locators = append(locators, loc)
 	}
 
-	if in.FilterStatus != taprpc.AddrEventStatus_ADDR_EVENT_STATUS_UNKNOWN {
-		status, err := unmarshalAddrEventStatus(in.FilterStatus)
-		if err != nil {
-			return nil, fmt.Errorf("error parsing status: %w", err)
+	for assetID, found := range assetIDs {
+		if !found {
+			return nil, fmt.Errorf("no owned assets with asset ID "+
+				"%v", assetID)
 		}
-
-		sqlQuery.StatusFrom = &status
-		sqlQuery.StatusTo = &status
 	}
 
-	events, err := r.cfg.AddrBook.QueryEvents(ctx, sqlQuery)
+	bundle, err := proof.ExportBundle(ctx, r.cfg.ProofArchive, locators...)
 	if err != nil {
-		return nil, fmt.Errorf("error querying events: %w", err)
+		return nil, fmt.Errorf("unable to export proof bundle: %w", err)
 	}
 
-	resp := &taprpc.AddrReceivesResponse{
-		Events: make([]*taprpc.AddrEvent, len(events)),
+	var bundleBuf bytes.Buffer
+	if err := bundle.Encode(&bundleBuf); err != nil {
+		return nil, fmt.Errorf("unable to encode proof bundle: %w", err)
 	}
 
-	for idx, event := range events {
-		resp.Events[idx], err = marshalAddrEvent(
-			event, r.cfg.TapAddrBook,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("error marshaling event: %w",
-				err)
-		}
-	}
+	rpcsLog.Infof("Exported proof bundle with %d proof files",
+		len(bundle.Files))
 
-	return resp, nil
+	return &taprpc.ProofBundle{
+		RawBundle: bundleBuf.Bytes(),
+		Manifest:  marshalBundleManifest(bundle.Manifest),
+	}, nil
 }
 
-// FundVirtualPsbt selects inputs from the available asset commitments to fund
-// a virtual transaction matching the template.
-func (r *rpcServer) FundVirtualPsbt(ctx context.Context,
-	in *wrpc.FundVirtualPsbtRequest) (*wrpc.FundVirtualPsbtResponse,
+// ImportProofBundle fully verifies all proof files of the given proof bundle
+// and then imports them into the daemon, resulting in a spendable asset for
+// each of them.
+func (r *rpcServer) ImportProofBundle(ctx context.Context,
+	in *taprpc.ImportProofBundleRequest) (*taprpc.ImportProofBundleResponse,
 	error) {
 
-	var fundedVPkt *tapfreighter.FundedVPacket
-	switch {
-	case in.GetPsbt() != nil:
-		vPkt, err := tappsbt.NewFromRawBytes(
-			bytes.NewReader(in.GetPsbt()), false,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("unable to decode psbt: %w", err)
+	if len(in.RawBundle) == 0 {
+		return nil, fmt.Errorf("proof bundle must be specified")
+	}
+
+	var bundle proof.Bundle
+	if err := bundle.Decode(bytes.NewReader(in.RawBundle)); err != nil {
+		return nil, fmt.Errorf("unable to decode proof bundle: %w", err)
+	}
+
+	headerVerifier := tapgarden.GenHeaderVerifier(ctx, r.cfg.ChainBridge)
+	err := proof.ImportBundle(
+		ctx, r.cfg.ProofArchive, headerVerifier, &bundle,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to import proof bundle: %w", err)
+	}
+
+	rpcsLog.Infof("Imported proof bundle with %d proof files",
+		len(bundle.Files))
+
+	return &taprpc.ImportProofBundleResponse{
+		Imported: marshalBundleManifest(bundle.Manifest),
+	}, nil
+}
+
+// BackupDatabase writes a consistent snapshot of the daemon's database to a
+// file, without interrupting any minting or transfers that are in progress.
+func (r *rpcServer) BackupDatabase(ctx context.Context,
+	in *taprpc.BackupDatabaseRequest) (*taprpc.BackupDatabaseResponse,
+	error) {
+
+	backupPath := in.BackupPath
+	if backupPath == "" {
+		timestamp := time.Now().UTC().Format("20060102-150405")
+		fileName := fmt.Sprintf("tapd-%s.backup", timestamp)
+		backupPath = filepath.Join(r.cfg.BackupDir, fileName)
+	}
+
+	rpcsLog.Infof("[BackupDatabase]: writing database backup to %v",
+		backupPath)
+
+	err := r.cfg.Backupper.Backup(ctx, backupPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to back up database: %w", err)
+	}
+
+	backupInfo, err := os.Stat(backupPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &taprpc.BackupDatabaseResponse{
+		BackupPath: backupPath,
+		Size:       uint64(backupInfo.Size()),
+	}, nil
+}
+
+// PruneAddrs removes all addresses from the address book that can no longer
+// receive any transfers, together with their events.
+func (r *rpcServer) PruneAddrs(ctx context.Context,
+	_ *taprpc.PruneAddrsRequest) (*taprpc.PruneAddrsResponse, error) {
+
+	pruned, err := r.cfg.AddrBook.PruneAddrs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to prune addrs: %w", err)
+	}
+
+	rpcsLog.Infof("[PruneAddrs]: pruned %d addrs", len(pruned))
+
+	resp := &taprpc.PruneAddrsResponse{
+		PrunedAddrs: make([]*taprpc.Addr, len(pruned)),
+	}
+	for idx := range pruned {
+		resp.PrunedAddrs[idx], err = marshalAddr(
+			pruned[idx].Tap, r.cfg.TapAddrBook,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to marshal addr: %w",
+				err)
+		}
+	}
+
+	return resp, nil
+}
+
+// DatabaseStats returns statistics about the size of the daemon's database,
+// which can be used to monitor its growth.
+func (r *rpcServer) DatabaseStats(ctx context.Context,
+	_ *taprpc.DatabaseStatsRequest) (*taprpc.DatabaseStatsResponse,
+	error) {
+
+	stats, err := r.cfg.StatsReporter.Stats(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query database stats: %w",
+			err)
+	}
+
+	tables := make([]*taprpc.TableStats, len(stats.Tables))
+	for idx, table := range stats.Tables {
+		tables[idx] = &taprpc.TableStats{
+			TableName: table.Name,
+			NumRows:   uint64(table.NumRows),
+		}
+	}
+
+	return &taprpc.DatabaseStatsResponse{
+		Tables:           tables,
+		NumProofs:        uint64(stats.NumProofs),
+		ProofArchiveSize: uint64(stats.ProofArchiveSize),
+		NumMssmtTrees:    uint64(stats.NumMssmtTrees),
+		NumMssmtBranches: uint64(stats.NumMssmtBranches),
+		NumMssmtLeaves:   uint64(stats.NumMssmtLeaves),
+		NumMssmtCompactedLeaves: uint64(
+			stats.NumMssmtCompactedLeaves,
+		),
+		MigrationVersion: uint64(stats.MigrationVersion),
+		MigrationDirty:   stats.MigrationDirty,
+	}, nil
+}
+
+// marshalBundleManifest converts the manifest of a proof bundle to its RPC
+// representation.
+func marshalBundleManifest(
+	manifest []proof.BundleEntry) []*taprpc.ProofBundleEntry {
+
+	rpcManifest := make([]*taprpc.ProofBundleEntry, len(manifest))
+	for idx := range manifest {
+		entry := &manifest[idx]
+
+		var groupKey []byte
+		if entry.GroupKey != nil {
+			groupKey = entry.GroupKey.SerializeCompressed()
+		}
+
+		rpcManifest[idx] = &taprpc.ProofBundleEntry{
+			AssetId:   entry.AssetID[:],
+			GroupKey:  groupKey,
+			ScriptKey: entry.ScriptKey.SerializeCompressed(),
+			NumProofs: entry.NumProofs,
+			FileHash:  entry.FileHash[:],
+		}
+	}
+
+	return rpcManifest
+}
+
+// AddrReceives lists all receives for incoming asset transfers for addresses
+// that were created previously.
+func (r *rpcServer) AddrReceives(ctx context.Context,
+	in *taprpc.AddrReceivesRequest) (*taprpc.AddrReceivesResponse,
+	error) {
+
+	var sqlQuery address.EventQueryParams
+
+	if len(in.FilterAddr) > 0 {
+		tapParams := address.ParamsForChain(r.cfg.ChainParams.Name)
+
+		addr, err := address.DecodeAddress(in.FilterAddr, &tapParams)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode addr: %w", err)
+		}
+
+		// An address that accepts any asset of a group doesn't encode
+		// the asset ID it was created for, so we use our own copy of
+		// it, which knows the template asset ID.
+		if addr.AnyAssetInGroup {
+			ownAddr, err := r.fetchGroupAddr(ctx, addr)
+			if err != nil {
+				return nil, err
+			}
+			addr = ownAddr.Tap
+		}
+
+		// Now that we've decoded the address, we'll check to make sure
+		// that we can fetch the genesis for this address. Otherwise,
+		// that means we don't know anything about what it should look
+		// like on chain (the genesis is required to derive the taproot
+		// output key).
+		assetGroup, err := r.cfg.TapAddrBook.QueryAssetGroup(
+			ctx, addr.AssetID,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unknown asset=%x: %w",
+				addr.AssetID[:], err)
+		}
+
+		rpcsLog.Tracef("Listing receives for group: %v",
+			spew.Sdump(assetGroup))
+
+		addr.AttachGenesis(*assetGroup.Genesis)
+
+		if assetGroup.GroupKey != nil {
+			addr.AttachGroupSig(assetGroup.GroupKey.Sig)
+		}
+
+		taprootOutputKey, err := addr.TaprootOutputKey()
+		if err != nil {
+			return nil, fmt.Errorf("error deriving Taproot key: %w",
+				err)
+		}
+
+		sqlQuery.AddrTaprootOutputKey = schnorr.SerializePubKey(
+			taprootOutputKey,
+		)
+	}
+
+	if in.FilterStatus != taprpc.AddrEventStatus_ADDR_EVENT_STATUS_UNKNOWN {
+		status, err := unmarshalAddrEventStatus(in.FilterStatus)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing status: %w", err)
+		}
+
+		sqlQuery.StatusFrom = &status
+		sqlQuery.StatusTo = &status
+	}
+
+	events, err := r.cfg.AddrBook.QueryEvents(ctx, sqlQuery)
+	if err != nil {
+		return nil, fmt.Errorf("error querying events: %w", err)
+	}
+
+	resp := &taprpc.AddrReceivesResponse{
+		Events: make([]*taprpc.AddrEvent, len(events)),
+	}
+
+	for idx, event := range events {
+		resp.Events[idx], err = marshalAddrEvent(
+			event, r.cfg.TapAddrBook,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling event: %w",
+				err)
+		}
+	}
+
+	return resp, nil
+}
+
+// FundVirtualPsbt selects inputs from the available asset commitments to fund
+// a virtual transaction matching the template.
+func (r *rpcServer) FundVirtualPsbt(ctx context.Context,
+	in *wrpc.FundVirtualPsbtRequest) (*wrpc.FundVirtualPsbtResponse,
+	error) {
+
+	var fundedVPkt *tapfreighter.FundedVPacket
+	switch {
+	case in.GetPsbt() != nil:
+		vPkt, err := tappsbt.NewFromRawBytes(
+			bytes.NewReader(in.GetPsbt()), false,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode psbt: %w", err)
 		}
 
 		// Extract the recipient information from the packet. This
@@ -1239,7 +1855,29 @@ func (r *rpcServer) FundVirtualPsbt(ctx context.Context,
 		}
 
 		fundedVPkt, err = r.cfg.AssetWallet.FundPacket(
-			ctx, desc, vPkt,
+			ctx, desc, tapfreighter.DefaultSelectStrategy, vPkt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error funding packet: %w", err)
+		}
+
+	case in.GetInteractive() != nil:
+		vPkt, err := r.unmarshalInteractiveTemplate(in.GetInteractive())
+		if err != nil {
+			return nil, fmt.Errorf("invalid interactive template: "+
+				"%w", err)
+		}
+
+		desc, err := tapscript.DescribeRecipients(
+			ctx, vPkt, r.cfg.TapAddrBook,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to describe packet "+
+				"recipients: %w", err)
+		}
+
+		fundedVPkt, err = r.cfg.AssetWallet.FundPacket(
+			ctx, desc, tapfreighter.DefaultSelectStrategy, vPkt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("error funding packet: %w", err)
@@ -1247,10 +1885,6 @@ func (r *rpcServer) FundVirtualPsbt(ctx context.Context,
 
 	case in.GetRaw() != nil:
 		raw := in.GetRaw()
-		if len(raw.Inputs) > 0 {
-			return nil, fmt.Errorf("template inputs not yet " +
-				"supported")
-		}
 		if len(raw.Recipients) > 1 {
 			return nil, fmt.Errorf("only one recipient supported")
 		}
@@ -1274,15 +1908,49 @@ func (r *rpcServer) FundVirtualPsbt(ctx context.Context,
 			return nil, fmt.Errorf("no recipients specified")
 		}
 
-		fundedVPkt, err = r.cfg.AssetWallet.FundAddressSend(ctx, addr)
+		prevIDs, err := unmarshalPrevIDs(raw.Inputs)
 		if err != nil {
-			return nil, fmt.Errorf("error funding address send: "+
-				"%w", err)
+			return nil, fmt.Errorf("invalid template inputs: %w",
+				err)
+		}
+
+		// Without any template inputs, we can just let the wallet
+		// select the coins to send to the address.
+		if len(prevIDs) == 0 {
+			fundedVPkt, err = r.cfg.AssetWallet.FundAddressSend(
+				ctx, tapfreighter.DefaultSelectStrategy, addr,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("error funding address "+
+					"send: %w", err)
+			}
+
+			break
+		}
+
+		vPkt, err := tappsbt.FromAddresses([]*address.Tap{addr}, 1)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create virtual "+
+				"transaction from address: %w", err)
+		}
+
+		desc, err := tapscript.DescribeAddrs([]*address.Tap{addr})
+		if err != nil {
+			return nil, fmt.Errorf("unable to describe "+
+				"recipient: %w", err)
+		}
+		desc.PrevIDs = prevIDs
+
+		fundedVPkt, err = r.cfg.AssetWallet.FundPacket(
+			ctx, desc, tapfreighter.DefaultSelectStrategy, vPkt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error funding packet: %w", err)
 		}
 
 	default:
-		return nil, fmt.Errorf("either PSBT or raw template must be " +
-			"specified")
+		return nil, fmt.Errorf("either PSBT, raw or interactive " +
+			"template must be specified")
 	}
 
 	var b bytes.Buffer
@@ -1290,12 +1958,103 @@ func (r *rpcServer) FundVirtualPsbt(ctx context.Context,
 		return nil, fmt.Errorf("error serializing packet: %w", err)
 	}
 
+	// Any change ends up in the split root output. A split root without
+	// any value is just a tombstone or an anchor for passive assets.
+	changeOutputIndex := int32(-1)
+	for idx, vOut := range fundedVPkt.VPacket.Outputs {
+		if vOut.Type.IsSplitRoot() && vOut.Amount > 0 {
+			changeOutputIndex = int32(idx)
+			break
+		}
+	}
+
 	return &wrpc.FundVirtualPsbtResponse{
 		FundedPsbt:        b.Bytes(),
-		ChangeOutputIndex: 0,
+		ChangeOutputIndex: changeOutputIndex,
 	}, nil
 }
 
+// unmarshalPrevIDs parses the given RPC asset inputs into their native
+// counterparts.
+func unmarshalPrevIDs(rpcInputs []*wrpc.PrevId) ([]asset.PrevID, error) {
+	prevIDs := make([]asset.PrevID, 0, len(rpcInputs))
+	for idx, rpcInput := range rpcInputs {
+		if rpcInput.Outpoint == nil {
+			return nil, fmt.Errorf("input %d: outpoint missing",
+				idx)
+		}
+
+		txid, err := chainhash.NewHash(rpcInput.Outpoint.Txid)
+		if err != nil {
+			return nil, fmt.Errorf("input %d: invalid txid: %w",
+				idx, err)
+		}
+
+		if len(rpcInput.Id) != sha256.Size {
+			return nil, fmt.Errorf("input %d: invalid asset id "+
+				"length", idx)
+		}
+
+		scriptKey, err := btcec.ParsePubKey(rpcInput.ScriptKey)
+		if err != nil {
+			return nil, fmt.Errorf("input %d: invalid script key: "+
+				"%w", idx, err)
+		}
+
+		prevID := asset.PrevID{
+			OutPoint: wire.OutPoint{
+				Hash:  *txid,
+				Index: rpcInput.Outpoint.OutputIndex,
+			},
+			ScriptKey: asset.ToSerialized(scriptKey),
+		}
+		copy(prevID.ID[:], rpcInput.Id)
+
+		prevIDs = append(prevIDs, prevID)
+	}
+
+	return prevIDs, nil
+}
+
+// unmarshalInteractiveTemplate creates a virtual packet for an interactive
+// send to the receiver keys of the given template. The receiver's output is
+// anchored at the first anchor output, the funding will add a change output if
+// required.
+func (r *rpcServer) unmarshalInteractiveTemplate(
+	tpl *wrpc.InteractiveTemplate) (*tappsbt.VPacket, error) {
+
+	if len(tpl.AssetId) != sha256.Size {
+		return nil, fmt.Errorf("invalid asset id length")
+	}
+	if tpl.Amount == 0 {
+		return nil, fmt.Errorf("amount must be greater than zero")
+	}
+
+	var assetID asset.ID
+	copy(assetID[:], tpl.AssetId)
+
+	if tpl.ScriptKey == nil || tpl.AnchorInternalKey == nil {
+		return nil, fmt.Errorf("script key and anchor internal key " +
+			"must be specified")
+	}
+
+	scriptKey, err := UnmarshalScriptKey(tpl.ScriptKey)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode script key: %w", err)
+	}
+
+	internalKey, err := UnmarshalKeyDescriptor(tpl.AnchorInternalKey)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode anchor internal "+
+			"key: %w", err)
+	}
+
+	tapParams := address.ParamsForChain(r.cfg.ChainParams.Name)
+	return tappsbt.ForInteractiveSend(
+		assetID, tpl.Amount, *scriptKey, 0, internalKey, &tapParams,
+	), nil
+}
+
 // SignVirtualPsbt signs the inputs of a virtual transaction and prepares the
 // commitments of the inputs and outputs.
 func (r *rpcServer) SignVirtualPsbt(_ context.Context,
@@ -1325,51 +2084,152 @@ func (r *rpcServer) SignVirtualPsbt(_ context.Context,
 	}, nil
 }
 
+// ComputeVirtualPsbtSigHashes returns the signature hash of a funded virtual
+// transaction for each of its inputs, so the inputs can be signed by an
+// external signer.
+func (r *rpcServer) ComputeVirtualPsbtSigHashes(_ context.Context,
+	in *wrpc.ComputeVirtualPsbtSigHashesRequest) (
+	*wrpc.ComputeVirtualPsbtSigHashesResponse, error) {
+
+	vPkt, err := tappsbt.NewFromRawBytes(
+		bytes.NewReader(in.FundedPsbt), false,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding packet: %w", err)
+	}
+
+	sigHashes, err := tapscript.VirtualTxSigHashes(vPkt)
+	if err != nil {
+		return nil, fmt.Errorf("error computing sighashes: %w", err)
+	}
+
+	return &wrpc.ComputeVirtualPsbtSigHashesResponse{
+		SigHashes: sigHashes,
+	}, nil
+}
+
+// AddVirtualPsbtWitnesses adds the witnesses created by an external signer to
+// the inputs of a funded virtual transaction and validates the transfer.
+func (r *rpcServer) AddVirtualPsbtWitnesses(_ context.Context,
+	in *wrpc.AddVirtualPsbtWitnessesRequest) (
+	*wrpc.AddVirtualPsbtWitnessesResponse, error) {
+
+	vPkt, err := tappsbt.NewFromRawBytes(
+		bytes.NewReader(in.FundedPsbt), false,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding packet: %w", err)
+	}
+
+	witnesses := make([]wire.TxWitness, len(in.Witnesses))
+	for idx := range in.Witnesses {
+		if in.Witnesses[idx] == nil {
+			return nil, fmt.Errorf("witness for input %d missing",
+				idx)
+		}
+
+		witnesses[idx] = in.Witnesses[idx].Witness
+	}
+
+	err = r.cfg.AssetWallet.AddVirtualPacketWitnesses(vPkt, witnesses)
+	if err != nil {
+		return nil, fmt.Errorf("error adding witnesses: %w", err)
+	}
+
+	var b bytes.Buffer
+	if err := vPkt.Serialize(&b); err != nil {
+		return nil, fmt.Errorf("error serializing packet: %w", err)
+	}
+
+	return &wrpc.AddVirtualPsbtWitnessesResponse{
+		SignedPsbt: b.Bytes(),
+	}, nil
+}
+
 // AnchorVirtualPsbts merges and then commits multiple virtual transactions in
 // a single BTC level anchor transaction.
-//
-// TODO(guggero): Actually implement accepting and merging multiple
-// transactions.
 func (r *rpcServer) AnchorVirtualPsbts(ctx context.Context,
 	in *wrpc.AnchorVirtualPsbtsRequest) (*taprpc.SendAssetResponse,
 	error) {
 
-	if len(in.VirtualPsbts) > 1 {
-		return nil, fmt.Errorf("only one virtual PSBT supported")
+	vPackets, inputCommitments, err := r.decodeAnchorPackets(
+		ctx, in.VirtualPsbts,
+	)
+	if err != nil {
+		return nil, err
 	}
 
-	vPacket, err := tappsbt.NewFromRawBytes(
-		bytes.NewReader(in.VirtualPsbts[0]), false,
+	rpcsLog.Debugf("Requesting delivery of %d virtual packets",
+		len(vPackets))
+
+	resp, err := r.cfg.ChainPorter.RequestShipment(
+		tapfreighter.NewPreSignedParcel(vPackets, inputCommitments),
 	)
 	if err != nil {
-		return nil, fmt.Errorf("error decoding packet: %w", err)
+		return nil, fmt.Errorf("error requesting delivery: %w", err)
 	}
 
-	if len(vPacket.Inputs) != 1 {
-		return nil, fmt.Errorf("only one input is currently supported")
+	parcel, err := marshalOutboundParcel(resp)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling outbound parcel: %w",
+			err)
 	}
 
-	inputAsset := vPacket.Inputs[0].Asset()
-	prevID := vPacket.Inputs[0].PrevID
-	inputCommitment, err := r.cfg.AssetStore.FetchCommitment(
-		ctx, inputAsset.ID(), prevID.OutPoint, inputAsset.GroupKey,
-		&inputAsset.ScriptKey,
+	return &taprpc.SendAssetResponse{
+		Transfer: parcel,
+	}, nil
+}
+
+// PrepareAnchorPsbt merges multiple signed virtual transactions into a single
+// BTC level anchor transaction, but returns the funded anchor PSBT to be signed
+// by an external wallet instead of signing and broadcasting it.
+func (r *rpcServer) PrepareAnchorPsbt(ctx context.Context,
+	in *wrpc.PrepareAnchorPsbtRequest) (*wrpc.PrepareAnchorPsbtResponse,
+	error) {
+
+	vPackets, inputCommitments, err := r.decodeAnchorPackets(
+		ctx, in.VirtualPsbts,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcsLog.Debugf("Requesting anchor PSBT for %d virtual packets",
+		len(vPackets))
+
+	anchorPsbt, err := r.cfg.ChainPorter.RequestAnchorPsbt(
+		tapfreighter.NewPreSignedParcel(vPackets, inputCommitments),
 	)
 	if err != nil {
-		return nil, fmt.Errorf("error fetching input commitment: %w",
+		return nil, fmt.Errorf("error requesting anchor psbt: %w", err)
+	}
+
+	var b bytes.Buffer
+	if err := anchorPsbt.Serialize(&b); err != nil {
+		return nil, fmt.Errorf("error serializing anchor psbt: %w",
 			err)
 	}
 
-	rpcsLog.Debugf("Selected commitment for anchor point %v, requesting "+
-		"delivery", inputCommitment.AnchorPoint)
+	return &wrpc.PrepareAnchorPsbtResponse{
+		AnchorPsbt: b.Bytes(),
+	}, nil
+}
+
+// PublishAnchorPsbt resumes a transfer prepared with PrepareAnchorPsbt using
+// the anchor PSBT that was signed by an external wallet.
+func (r *rpcServer) PublishAnchorPsbt(_ context.Context,
+	in *wrpc.PublishAnchorPsbtRequest) (*taprpc.SendAssetResponse, error) {
 
-	resp, err := r.cfg.ChainPorter.RequestShipment(
-		tapfreighter.NewPreSignedParcel(
-			vPacket, inputCommitment.Commitment,
-		),
+	signedPsbt, err := psbt.NewFromRawBytes(
+		bytes.NewReader(in.SignedPsbt), false,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("error requesting delivery: %w", err)
+		return nil, fmt.Errorf("error decoding anchor psbt: %w", err)
+	}
+
+	resp, err := r.cfg.ChainPorter.PublishAnchorPsbt(signedPsbt)
+	if err != nil {
+		return nil, fmt.Errorf("error publishing anchor psbt: %w", err)
 	}
 
 	parcel, err := marshalOutboundParcel(resp)
@@ -1383,6 +2243,63 @@ func (r *rpcServer) AnchorVirtualPsbts(ctx context.Context,
 	}, nil
 }
 
+// decodeAnchorPackets decodes the given signed virtual transactions and fetches
+// the commitments of their inputs, so they can be anchored in a single BTC
+// level anchor transaction.
+func (r *rpcServer) decodeAnchorPackets(ctx context.Context,
+	virtualPsbts [][]byte) ([]*tappsbt.VPacket,
+	[]tappsbt.InputCommitments, error) {
+
+	if len(virtualPsbts) == 0 {
+		return nil, nil, fmt.Errorf("no virtual PSBTs specified")
+	}
+
+	var (
+		numPkts          = len(virtualPsbts)
+		vPackets         = make([]*tappsbt.VPacket, numPkts)
+		inputCommitments = make([]tappsbt.InputCommitments, numPkts)
+	)
+	for pktIdx := range virtualPsbts {
+		vPacket, err := tappsbt.NewFromRawBytes(
+			bytes.NewReader(virtualPsbts[pktIdx]), false,
+		)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error decoding packet "+
+				"%d: %w", pktIdx, err)
+		}
+
+		if len(vPacket.Inputs) == 0 {
+			return nil, nil, fmt.Errorf("packet %d has no inputs",
+				pktIdx)
+		}
+
+		pktCommitments := make(tappsbt.InputCommitments)
+		for inIdx := range vPacket.Inputs {
+			inputAsset := vPacket.Inputs[inIdx].Asset()
+			prevID := vPacket.Inputs[inIdx].PrevID
+			assetStore := r.cfg.AssetStore
+			inputCommitment, err := assetStore.FetchCommitment(
+				ctx, inputAsset.ID(), prevID.OutPoint,
+				inputAsset.GroupKey, &inputAsset.ScriptKey,
+			)
+			if err != nil {
+				return nil, nil, fmt.Errorf("error fetching "+
+					"input commitment: %w", err)
+			}
+
+			rpcsLog.Debugf("Selected commitment for anchor point "+
+				"%v", inputCommitment.AnchorPoint)
+
+			pktCommitments[inIdx] = inputCommitment.Commitment
+		}
+
+		vPackets[pktIdx] = vPacket
+		inputCommitments[pktIdx] = pktCommitments
+	}
+
+	return vPackets, inputCommitments, nil
+}
+
 // NextInternalKey derives the next internal key for the given key family and
 // stores it as an internal key in the database to make sure it is identified
 // as a local key later on when importing proofs. While an internal key can
@@ -1478,21 +2395,34 @@ func marshalAddr(addr *address.Tap,
 			err)
 	}
 
-	id := addr.AssetID
 	rpcAddr := &taprpc.Addr{
 		Encoded:          addrStr,
-		AssetId:          id[:],
 		Amount:           addr.Amount,
 		ScriptKey:        addr.ScriptKey.SerializeCompressed(),
 		InternalKey:      addr.InternalKey.SerializeCompressed(),
 		TapscriptSibling: siblingBytes,
 		TaprootOutputKey: taprootOutputKey,
 		AssetType:        taprpc.AssetType(addr.AssetType()),
+		Static:           addr.Static,
+		AnyAssetInGroup:  addr.AnyAssetInGroup,
+		SingleUse:        addr.SingleUse,
 	}
 
+	// The asset ID of an address that accepts any asset of a group is not
+	// part of the address.
+	if !addr.AnyAssetInGroup {
+		id := addr.AssetID
+		rpcAddr.AssetId = id[:]
+	}
 	if addr.GroupKey != nil {
 		rpcAddr.GroupKey = addr.GroupKey.SerializeCompressed()
 	}
+	if addr.ProofCourierAddr != nil {
+		rpcAddr.ProofCourierAddr = addr.ProofCourierAddr.String()
+	}
+	if addr.HasExpiry() {
+		rpcAddr.ExpiryUnixSeconds = uint64(addr.Expiry.Unix())
+	}
 
 	return rpcAddr, nil
 }
@@ -1511,6 +2441,22 @@ func marshalAddrEvent(event *address.Event,
 		return nil, fmt.Errorf("error marshaling status: %w", err)
 	}
 
+	statusChanges := make(
+		[]*taprpc.AddrEventStatusChange, len(event.StatusChanges),
+	)
+	for idx, change := range event.StatusChanges {
+		changeStatus, err := marshalAddrEventStatus(change.Status)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling status "+
+				"change: %w", err)
+		}
+
+		statusChanges[idx] = &taprpc.AddrEventStatusChange{
+			Status:          changeStatus,
+			TimeUnixSeconds: uint64(change.Time.Unix()),
+		}
+	}
+
 	return &taprpc.AddrEvent{
 		CreationTimeUnixSeconds: uint64(event.CreationTime.Unix()),
 		Addr:                    rpcAddr,
@@ -1519,6 +2465,7 @@ func marshalAddrEvent(event *address.Event,
 		UtxoAmtSat:              uint64(event.Amt),
 		ConfirmationHeight:      event.ConfirmationHeight,
 		HasProof:                event.HasProof,
+		StatusChanges:           statusChanges,
 	}, nil
 }
 
@@ -1577,56 +2524,105 @@ func marshalAddrEventStatus(status address.Status) (taprpc.AddrEventStatus,
 // complete an asset send. The method returns information w.r.t the on chain
 // send, as well as the proof file information the receiver needs to fully
 // receive the asset.
-func (r *rpcServer) SendAsset(_ context.Context,
+func (r *rpcServer) SendAsset(ctx context.Context,
 	in *taprpc.SendAssetRequest) (*taprpc.SendAssetResponse, error) {
 
 	if len(in.TapAddrs) == 0 {
 		return nil, fmt.Errorf("at least one addr is required")
 	}
 
+	selectStrategy, err := unmarshalCoinSelectStrategy(
+		in.CoinSelectStrategy,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	shipmentOpts, err := unmarshalShipmentOptions(in)
+	if err != nil {
+		return nil, err
+	}
+
 	var (
-		tapParams = address.ParamsForChain(r.cfg.ChainParams.Name)
-		tapAddrs  = make([]*address.Tap, len(in.TapAddrs))
-		err       error
+		tapParams     = address.ParamsForChain(r.cfg.ChainParams.Name)
+		tapAddrs      = make([]*address.Tap, len(in.TapAddrs))
+		numAmountless int
 	)
 	for idx := range in.TapAddrs {
 		if len(in.TapAddrs[idx]) == 0 {
 			return nil, fmt.Errorf("addr %d must be specified", idx)
 		}
 
-		tapAddrs[idx], err = address.DecodeAddress(
+		addr, err := address.DecodeAddress(
 			in.TapAddrs[idx], &tapParams,
 		)
 		if err != nil {
 			return nil, err
 		}
 
-		// Ensure all addrs are of the same asset ID. Within a single
-		// transfer (=a single virtual packet), we expect only to have
-		// inputs and outputs of the same asset ID. Multiple assets can
-		// be moved in a single BTC level anchor output, but the
-		// expectation is that they would be in separate virtual
-		// packets, one for each asset ID. They would then be merged
-		// into the same anchor output in the wallet's
-		// AnchorVirtualTransactions call.
-		//
-		// TODO(guggero): Support creating multiple virtual packets, one
-		// for each asset ID when the user wants to send multiple asset
-		// IDs at the same time without going through the PSBT flow.
-		//
-		// TODO(guggero): Revisit after we have a way to send fungible
-		// assets with different IDs to an address (non-interactive).
-		if idx > 0 {
-			if tapAddrs[idx].AssetID != tapAddrs[0].AssetID {
-				return nil, fmt.Errorf("all addrs must be of "+
-					"the same asset ID %v",
-					tapAddrs[0].AssetID)
+		// We refuse to send to an address that no longer accepts any
+		// transfers, as the receiver wouldn't pick them up.
+		if addr.IsExpired(time.Now()) {
+			return nil, fmt.Errorf("addr %d: %w", idx,
+				address.ErrAddrExpired)
+		}
+
+		// The amount to send to an address that doesn't specify one
+		// is chosen by us.
+		if !addr.HasAmount() {
+			numAmountless++
+			addr, err = addr.ForAmount(in.Amount)
+			if err != nil {
+				return nil, fmt.Errorf("addr %d has no "+
+					"amount, the amount to send must be "+
+					"specified: %w", idx, err)
+			}
+		}
+
+		// For an address that accepts any asset of a group, we choose
+		// the tranche of the group to send from.
+		if addr.AnyAssetInGroup {
+			assetID, err := r.selectGroupTranche(
+				ctx, addr.GroupKey, addr.Amount,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("unable to select "+
+					"asset for addr %d: %w", idx, err)
+			}
+
+			addr, err = addr.ForAssetID(assetID)
+			if err != nil {
+				return nil, err
 			}
 		}
+		tapAddrs[idx] = addr
 	}
 
+	if in.Amount != 0 && numAmountless == 0 {
+		return nil, fmt.Errorf("amount can only be specified for " +
+			"addresses that don't specify an amount")
+	}
+
+	// We make sure the requested edition is actually ours to send, so the
+	// request fails early instead of during coin selection.
+	if in.Edition != 0 {
+		err := r.checkEdition(ctx, tapAddrs[0].AssetID, in.Edition)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The addresses may be of different asset IDs. Within a single
+	// transfer (=a single virtual packet), we expect only to have inputs
+	// and outputs of the same asset ID, so the porter creates a separate
+	// virtual packet for each asset ID. They are then merged into the same
+	// anchor transaction in the wallet's AnchorVirtualTransactions call.
+	//
+	// TODO(guggero): Revisit after we have a way to send fungible assets
+	// with different IDs to an address (non-interactive).
 	resp, err := r.cfg.ChainPorter.RequestShipment(
-		tapfreighter.NewAddressParcel(tapAddrs...),
+		tapfreighter.NewAddressParcel(selectStrategy, tapAddrs...),
+		shipmentOpts...,
 	)
 	if err != nil {
 		return nil, err
@@ -1643,6 +2639,81 @@ func (r *rpcServer) SendAsset(_ context.Context,
 	}, nil
 }
 
+// AssetBurnConfirmationText is the text that needs to be set on the RPC to
+// confirm an asset burn.
+const AssetBurnConfirmationText = "assets will be destroyed"
+
+// BurnAsset burns the given number of units of a given asset by sending them
+// to a provably un-spendable script key.
+func (r *rpcServer) BurnAsset(ctx context.Context,
+	in *taprpc.BurnAssetRequest) (*taprpc.BurnAssetResponse, error) {
+
+	if len(in.AssetId) != sha256.Size {
+		return nil, fmt.Errorf("asset ID must be 32 bytes")
+	}
+	var assetID asset.ID
+	copy(assetID[:], in.AssetId)
+
+	if in.AmountToBurn == 0 {
+		return nil, fmt.Errorf("amount to burn must be specified")
+	}
+	if in.ConfirmationText != AssetBurnConfirmationText {
+		return nil, fmt.Errorf("invalid confirmation text, please " +
+			"read the API docs and confirm the safety measure to " +
+			"avoid accidental asset burns")
+	}
+
+	// The coin selection needs to know the group key of grouped assets, as
+	// they are committed to under the group key in the anchor output.
+	var groupKey *btcec.PublicKey
+	assetGroup, err := r.cfg.TapAddrBook.QueryAssetGroup(ctx, assetID)
+	switch {
+	case err == nil && assetGroup.GroupKey != nil:
+		groupKey = &assetGroup.GroupPubKey
+
+	case err != nil:
+		return nil, fmt.Errorf("unable to query asset group: %w", err)
+	}
+
+	rpcsLog.Infof("Burning %d units of asset %v", in.AmountToBurn, assetID)
+
+	resp, err := r.cfg.ChainPorter.RequestShipment(
+		tapfreighter.NewBurnParcel(&tapscript.FundingDescriptor{
+			ID:       assetID,
+			GroupKey: groupKey,
+			Amount:   in.AmountToBurn,
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	parcel, err := marshalOutboundParcel(resp)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling outbound parcel: %w",
+			err)
+	}
+
+	// The burn output is the only output of the burn type, so we can
+	// return its proof to the caller.
+	var burnProof []byte
+	for idx := range resp.Outputs {
+		out := resp.Outputs[idx]
+		if out.Type == tappsbt.TypeBurn {
+			burnProof = out.ProofSuffix
+			break
+		}
+	}
+	if burnProof == nil {
+		return nil, fmt.Errorf("burn output not found in transfer")
+	}
+
+	return &taprpc.BurnAssetResponse{
+		BurnTransfer: parcel,
+		BurnProof:    burnProof,
+	}, nil
+}
+
 // marshalOutboundParcel turns a pending parcel into its RPC counterpart.
 func marshalOutboundParcel(
 	parcel *tapfreighter.OutboundParcel) (*taprpc.AssetTransfer,
@@ -1691,6 +2762,18 @@ func marshalOutboundParcel(
 			return nil, err
 		}
 
+		rpcDeliveryStatus, err := marshalProofDeliveryStatus(
+			out.ProofDeliveryStatus,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		var courierAddr string
+		if out.ProofCourierAddr != nil {
+			courierAddr = out.ProofCourierAddr.String()
+		}
+
 		rpcOutputs[idx] = &taprpc.TransferOutput{
 			Anchor:              rpcAnchor,
 			ScriptKey:           scriptPubKey.SerializeCompressed(),
@@ -1699,39 +2782,163 @@ func marshalOutboundParcel(
 			NewProofBlob:        out.ProofSuffix,
 			SplitCommitRootHash: splitCommitRoot,
 			OutputType:          rpcOutType,
+			ProofDeliveryStatus: rpcDeliveryStatus,
+			ProofCourierAddr:    courierAddr,
+		}
+	}
+
+	anchorTxHash := parcel.AnchorTx.TxHash()
+	return &taprpc.AssetTransfer{
+		TransferTimestamp:  parcel.TransferTime.Unix(),
+		AnchorTxHash:       anchorTxHash[:],
+		AnchorTxHeightHint: parcel.AnchorTxHeightHint,
+		AnchorTxChainFees:  parcel.ChainFees,
+		Inputs:             rpcInputs,
+		Outputs:            rpcOutputs,
+	}, nil
+}
+
+// marshalOutputType turns the transfer output type into the RPC counterpart.
+func marshalOutputType(outputType tappsbt.VOutputType) (taprpc.OutputType,
+	error) {
+
+	switch outputType {
+	case tappsbt.TypeSimple:
+		return taprpc.OutputType_OUTPUT_TYPE_SIMPLE, nil
+
+	case tappsbt.TypeSplitRoot:
+		return taprpc.OutputType_OUTPUT_TYPE_SPLIT_ROOT, nil
+
+	case tappsbt.TypePassiveAssetsOnly:
+		return taprpc.OutputType_OUTPUT_TYPE_PASSIVE_ASSETS_ONLY, nil
+
+	case tappsbt.TypePassiveSplitRoot:
+		return taprpc.OutputType_OUTPUT_TYPE_PASSIVE_SPLIT_ROOT, nil
+
+	case tappsbt.TypeBurn:
+		return taprpc.OutputType_OUTPUT_TYPE_BURN, nil
+
+	default:
+		return 0, fmt.Errorf("unknown output type: %d", outputType)
+	}
+}
+
+// marshalProofDeliveryStatus turns the proof delivery status of a transfer
+// output into the RPC counterpart.
+func marshalProofDeliveryStatus(
+	status tapfreighter.ProofDeliveryStatus) (taprpc.ProofDeliveryStatus,
+	error) {
+
+	switch status {
+	case tapfreighter.ProofDeliveryNotRequired:
+		return taprpc.ProofDeliveryStatus_PROOF_DELIVERY_STATUS_NOT_REQUIRED,
+			nil
+
+	case tapfreighter.ProofDeliveryPending:
+		return taprpc.ProofDeliveryStatus_PROOF_DELIVERY_STATUS_PENDING,
+			nil
+
+	case tapfreighter.ProofDeliveryComplete:
+		return taprpc.ProofDeliveryStatus_PROOF_DELIVERY_STATUS_COMPLETE,
+			nil
+
+	default:
+		return 0, fmt.Errorf("unknown proof delivery status: %d",
+			status)
+	}
+}
+
+// unmarshalShipmentOptions parses the fee and passive asset related fields of
+// the RPC send request into the shipment options of the porter.
+func unmarshalShipmentOptions(
+	in *taprpc.SendAssetRequest) ([]tapfreighter.ShipmentOption, error) {
+
+	if in.SatPerVbyte != 0 && in.ConfTarget != 0 {
+		return nil, fmt.Errorf("only one of sat_per_vbyte and " +
+			"conf_target can be set")
+	}
+
+	var opts []tapfreighter.ShipmentOption
+	if in.SatPerVbyte != 0 {
+		feeRate := chainfee.SatPerKVByte(
+			in.SatPerVbyte * 1000,
+		).FeePerKWeight()
+		opts = append(opts, tapfreighter.WithFeeRate(feeRate))
+	}
+	if in.ConfTarget != 0 {
+		opts = append(opts, tapfreighter.WithConfTarget(in.ConfTarget))
+	}
+	if in.MaxFeeSats != 0 {
+		if in.MaxFeeSats > btcutil.MaxSatoshi {
+			return nil, fmt.Errorf("max fee of %d sats exceeds "+
+				"max supply", in.MaxFeeSats)
+		}
+
+		maxFee := btcutil.Amount(in.MaxFeeSats)
+		opts = append(opts, tapfreighter.WithMaxFee(maxFee))
+	}
+	if in.ForceStrandPassiveAssets {
+		opts = append(
+			opts, tapfreighter.WithForceStrandPassiveAssets(),
+		)
+	}
+	if in.Edition != 0 {
+		if len(in.TapAddrs) != 1 {
+			return nil, fmt.Errorf("an edition can only be sent " +
+				"to a single address")
+		}
+
+		opts = append(opts, tapfreighter.WithEdition(in.Edition))
+	}
+
+	return opts, nil
+}
+
+// checkEdition makes sure the given edition of the collectible with the given
+// asset ID is owned by the wallet and not spent yet.
+func (r *rpcServer) checkEdition(ctx context.Context, assetID asset.ID,
+	edition uint64) error {
+
+	editions, err := r.cfg.AssetStore.FetchEditions(ctx, assetID)
+	if err != nil {
+		return fmt.Errorf("unable to fetch editions: %w", err)
+	}
+
+	for _, chainAsset := range editions {
+		if chainAsset.Edition == edition {
+			return nil
 		}
 	}
 
-	anchorTxHash := parcel.AnchorTx.TxHash()
-	return &taprpc.AssetTransfer{
-		TransferTimestamp:  parcel.TransferTime.Unix(),
-		AnchorTxHash:       anchorTxHash[:],
-		AnchorTxHeightHint: parcel.AnchorTxHeightHint,
-		AnchorTxChainFees:  parcel.ChainFees,
-		Inputs:             rpcInputs,
-		Outputs:            rpcOutputs,
-	}, nil
+	return fmt.Errorf("edition %d of asset %v not found", edition,
+		assetID)
 }
 
-// marshalOutputType turns the transfer output type into the RPC counterpart.
-func marshalOutputType(outputType tappsbt.VOutputType) (taprpc.OutputType,
-	error) {
+// unmarshalCoinSelectStrategy parses the RPC coin selection strategy into the
+// native counterpart.
+func unmarshalCoinSelectStrategy(
+	rpcStrategy taprpc.CoinSelectStrategy) (
+	tapfreighter.MultiCommitmentSelectStrategy, error) {
 
-	switch outputType {
-	case tappsbt.TypeSimple:
-		return taprpc.OutputType_OUTPUT_TYPE_SIMPLE, nil
+	switch rpcStrategy {
+	case taprpc.CoinSelectStrategy_COIN_SELECT_STRATEGY_DEFAULT:
+		return tapfreighter.DefaultSelectStrategy, nil
 
-	case tappsbt.TypeSplitRoot:
-		return taprpc.OutputType_OUTPUT_TYPE_SPLIT_ROOT, nil
+	case taprpc.CoinSelectStrategy_COIN_SELECT_STRATEGY_MAX_AMOUNT:
+		return tapfreighter.PreferMaxAmount, nil
 
-	case tappsbt.TypePassiveAssetsOnly:
-		return taprpc.OutputType_OUTPUT_TYPE_PASSIVE_ASSETS_ONLY, nil
+	case taprpc.CoinSelectStrategy_COIN_SELECT_STRATEGY_MIN_AMOUNT:
+		return tapfreighter.PreferMinAmount, nil
 
-	case tappsbt.TypePassiveSplitRoot:
-		return taprpc.OutputType_OUTPUT_TYPE_PASSIVE_SPLIT_ROOT, nil
+	case taprpc.CoinSelectStrategy_COIN_SELECT_STRATEGY_SINGLE_COIN:
+		return tapfreighter.PreferSingleCoin, nil
+
+	case taprpc.CoinSelectStrategy_COIN_SELECT_STRATEGY_RANDOM:
+		return tapfreighter.PreferRandom, nil
 
 	default:
-		return 0, fmt.Errorf("unknown output type: %d", outputType)
+		return 0, fmt.Errorf("unknown coin selection strategy <%d>",
+			rpcStrategy)
 	}
 }
 
@@ -1797,6 +3004,79 @@ func (r *rpcServer) SubscribeSendAssetEventNtfns(
 	}
 }
 
+// SubscribeReceiveAssetEventNtfns registers a subscription to the event
+// notification stream which relates to the asset receiving process.
+func (r *rpcServer) SubscribeReceiveAssetEventNtfns(
+	in *taprpc.SubscribeReceiveAssetEventNtfnsRequest,
+	ntfnStream taprpc.TaprootAssets_SubscribeReceiveAssetEventNtfnsServer) error {
+
+	// Create a new event subscriber and pass a copy to the custodian. We
+	// will then read events from the subscriber. The subscriber is stopped
+	// when it is removed from the custodian again.
+	eventSubscriber := chanutils.NewEventReceiver[*address.Event](
+		chanutils.DefaultQueueSize,
+	)
+
+	// If requested, all inbound transfers that aren't completed yet are
+	// delivered first.
+	var (
+		from         = address.StatusTransactionDetected
+		to           = address.StatusProofReceived
+		pendingQuery = address.EventQueryParams{
+			StatusFrom: &from,
+			StatusTo:   &to,
+		}
+	)
+	err := r.cfg.AssetCustodian.RegisterSubscriber(
+		eventSubscriber, in.DeliverPending, pendingQuery,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to register event notifications "+
+			"subscription: %w", err)
+	}
+	defer func() {
+		err := r.cfg.AssetCustodian.RemoveSubscriber(eventSubscriber)
+		if err != nil {
+			rpcsLog.Warnf("Unable to remove receive event "+
+				"subscriber: %v", err)
+		}
+	}()
+
+	for {
+		select {
+		case event := <-eventSubscriber.NewItemCreated.ChanOut():
+			rpcEvent, err := marshalAddrEvent(
+				event, r.cfg.TapAddrBook,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to marshal address "+
+					"event: %w", err)
+			}
+
+			err = ntfnStream.Send(rpcEvent)
+			if err != nil {
+				return fmt.Errorf("failed to RPC stream send "+
+					"event: %w", err)
+			}
+
+		case <-ntfnStream.Context().Done():
+			// Don't return an error if a normal context
+			// cancellation has occurred.
+			isCanceledContext := errors.Is(
+				ntfnStream.Context().Err(), context.Canceled,
+			)
+			if isCanceledContext {
+				return nil
+			}
+
+			return ntfnStream.Context().Err()
+
+		case <-r.quit:
+			return nil
+		}
+	}
+}
+
 // marshallSendAssetEvent maps a ChainPorter event to its RPC counterpart.
 func marshallSendAssetEvent(
 	eventInterface chanutils.Event) (*taprpc.SendAssetEvent, error) {
@@ -1825,11 +3105,61 @@ func marshallSendAssetEvent(
 			Event: &eventRpc,
 		}, nil
 
+	case *tapfreighter.TransferStageEvent:
+		rpcStage, err := marshalTransferStage(event.Stage)
+		if err != nil {
+			return nil, err
+		}
+
+		stageEvent := &taprpc.TransferStageEvent{
+			Timestamp:           event.Timestamp().UnixMicro(),
+			Stage:               rpcStage,
+			AnchorOutputIndexes: event.AnchorOutputIndexes,
+			BlockHeight:         event.BlockHeight,
+		}
+		if event.AnchorTxHash != nil {
+			stageEvent.AnchorTxHash = event.AnchorTxHash[:]
+		}
+
+		return &taprpc.SendAssetEvent{
+			Event: &taprpc.SendAssetEvent_TransferStageEvent{
+				TransferStageEvent: stageEvent,
+			},
+		}, nil
+
 	default:
 		return nil, fmt.Errorf("unknown event type: %T", eventInterface)
 	}
 }
 
+// marshalTransferStage maps a transfer stage to its RPC counterpart.
+func marshalTransferStage(
+	stage tapfreighter.TransferStage) (taprpc.TransferStage, error) {
+
+	switch stage {
+	case tapfreighter.TransferStageCoinsSelected:
+		return taprpc.TransferStage_TRANSFER_STAGE_COINS_SELECTED, nil
+
+	case tapfreighter.TransferStageVirtualSigned:
+		return taprpc.TransferStage_TRANSFER_STAGE_VIRTUAL_SIGNED, nil
+
+	case tapfreighter.TransferStageAnchorFunded:
+		return taprpc.TransferStage_TRANSFER_STAGE_ANCHOR_FUNDED, nil
+
+	case tapfreighter.TransferStageBroadcast:
+		return taprpc.TransferStage_TRANSFER_STAGE_BROADCAST, nil
+
+	case tapfreighter.TransferStageConfirmed:
+		return taprpc.TransferStage_TRANSFER_STAGE_CONFIRMED, nil
+
+	case tapfreighter.TransferStageProofsDelivered:
+		return taprpc.TransferStage_TRANSFER_STAGE_PROOFS_DELIVERED, nil
+
+	default:
+		return 0, fmt.Errorf("unknown transfer stage: %v", stage)
+	}
+}
+
 // marshalMintingBatch marshals a minting batch into the RPC counterpart.
 func marshalMintingBatch(batch *tapgarden.MintingBatch) (*mintrpc.MintingBatch,
 	error) {
@@ -1857,11 +3187,13 @@ func marshalMintingBatch(batch *tapgarden.MintingBatch) (*mintrpc.MintingBatch,
 		}
 
 		rpcAssets = append(rpcAssets, &mintrpc.MintAsset{
-			AssetType: taprpc.AssetType(seedling.AssetType),
-			Name:      seedling.AssetName,
-			AssetMeta: seedlingMeta,
-			Amount:    seedling.Amount,
-			GroupKey:  groupKeyBytes,
+			AssetType:      taprpc.AssetType(seedling.AssetType),
+			Name:           seedling.AssetName,
+			AssetMeta:      seedlingMeta,
+			Amount:         seedling.Amount,
+			GroupKey:       groupKeyBytes,
+			DecimalDisplay: seedling.DecimalDisplay,
+			Editions:       seedling.Editions,
 		})
 	}
 
@@ -1888,7 +3220,11 @@ func marshalBatchState(batch *tapgarden.MintingBatch) (mintrpc.BatchState,
 	case tapgarden.BatchStateFrozen:
 		return mintrpc.BatchState_BATCH_STATE_FROZEN, nil
 
-	case tapgarden.BatchStateCommitted:
+	// A batch awaiting its externally signed genesis transaction is
+	// reported as committed, as its sprouts were already committed to.
+	case tapgarden.BatchStateCommitted,
+		tapgarden.BatchStateAwaitingExternalSig:
+
 		return mintrpc.BatchState_BATCH_STATE_COMMITTED, nil
 
 	case tapgarden.BatchStateBroadcast:
@@ -1906,12 +3242,40 @@ func marshalBatchState(batch *tapgarden.MintingBatch) (mintrpc.BatchState,
 	case tapgarden.BatchStateSproutCancelled:
 		return mintrpc.BatchState_BATCH_STATE_SPROUT_CANCELLED, nil
 
+	case tapgarden.BatchStateFundingFailed:
+		return mintrpc.BatchState_BATCH_STATE_FUNDING_FAILED, nil
+
 	default:
 		return 0, fmt.Errorf("unknown batch state: %d",
 			batch.BatchState)
 	}
 }
 
+// unmarshalOutPoint parses an outpoint in the txid:index format.
+func unmarshalOutPoint(outpoint string) (wire.OutPoint, error) {
+	txidStr, indexStr, ok := strings.Cut(outpoint, ":")
+	if !ok {
+		return wire.OutPoint{}, errors.New("outpoint should be of " +
+			"the form txid:index")
+	}
+
+	txid, err := chainhash.NewHashFromStr(txidStr)
+	if err != nil {
+		return wire.OutPoint{}, err
+	}
+
+	outputIndex, err := strconv.ParseUint(indexStr, 10, 32)
+	if err != nil {
+		return wire.OutPoint{}, fmt.Errorf("invalid output index: %w",
+			err)
+	}
+
+	return wire.OutPoint{
+		Hash:  *txid,
+		Index: uint32(outputIndex),
+	}, nil
+}
+
 // UnmarshalScriptKey parses the RPC script key into the native counterpart.
 func UnmarshalScriptKey(rpcKey *taprpc.ScriptKey) (*asset.ScriptKey, error) {
 	var (
@@ -2044,12 +3408,63 @@ func (r *rpcServer) FetchAssetMeta(ctx context.Context,
 			"meta: %w", err)
 	}
 
+	return marshalAssetMeta(assetMeta), nil
+}
+
+// marshalAssetMeta converts an asset meta reveal into its RPC counterpart.
+func marshalAssetMeta(assetMeta *proof.MetaReveal) *taprpc.AssetMeta {
 	metaHash := assetMeta.MetaHash()
 	return &taprpc.AssetMeta{
 		Data:     assetMeta.Data,
 		Type:     taprpc.AssetMetaType(assetMeta.Type),
 		MetaHash: metaHash[:],
-	}, nil
+	}
+}
+
+// FetchGroupMetaHistory returns the verified history of the metadata updates
+// carried by the tranches of an asset group, along with the metadata wallets
+// should display for the group.
+func (r *rpcServer) FetchGroupMetaHistory(ctx context.Context,
+	in *taprpc.FetchGroupMetaHistoryRequest) (
+	*taprpc.FetchGroupMetaHistoryResponse, error) {
+
+	groupKey, err := btcec.ParsePubKey(in.GroupKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid group key: %w", err)
+	}
+
+	history, err := r.cfg.AssetStore.FetchGroupMetaHistory(ctx, groupKey)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch group meta history: "+
+			"%w", err)
+	}
+
+	return marshalGroupMetaHistory(history), nil
+}
+
+// marshalGroupMetaHistory converts the metadata history of an asset group
+// into its RPC counterpart.
+func marshalGroupMetaHistory(
+	history *proof.MetaHistory) *taprpc.FetchGroupMetaHistoryResponse {
+
+	resp := &taprpc.FetchGroupMetaHistoryResponse{
+		Updates: make([]*taprpc.GroupMetaUpdate, len(history.Updates)),
+	}
+	for i, entry := range history.Updates {
+		update := entry.Update
+		resp.Updates[i] = &taprpc.GroupMetaUpdate{
+			AssetId:  chanutils.ByteSlice(entry.AssetID),
+			Sequence: update.Sequence,
+			PrevHash: chanutils.ByteSlice(update.PrevHash),
+			Meta:     marshalAssetMeta(update.Meta),
+		}
+	}
+
+	if canonical := history.Canonical(); canonical != nil {
+		resp.CanonicalMeta = marshalAssetMeta(canonical)
+	}
+
+	return resp
 }
 
 func marshalUniID(id universe.Identifier) *unirpc.ID {
@@ -2406,12 +3821,14 @@ func unmarshalLeafKey(key *unirpc.AssetKey) (universe.BaseKey, error) {
 	return baseKey, nil
 }
 
-// marshalUniverseProof marshals a universe proof into the RPC form.
+// marshalUniverseProof marshals a universe proof into the RPC form. The proof
+// is encoded in the compact wire format to keep sync payloads small.
 func marshalUniverseProof(proof *mssmt.Proof) ([]byte, error) {
 	compressedProof := proof.Compress()
 
 	var b bytes.Buffer
-	if err := compressedProof.Encode(&b); err != nil {
+	err := compressedProof.EncodeVersion(&b, mssmt.CompressedProofV2)
+	if err != nil {
 		return nil, err
 	}
 
@@ -2708,10 +4125,26 @@ func (r *rpcServer) DeleteFederationServer(ctx context.Context,
 	return &unirpc.DeleteFederationServerResponse{}, nil
 }
 
+// parseOwnershipChallenge parses the optional challenge of an ownership proof.
+func parseOwnershipChallenge(challengeBytes []byte) (*[32]byte, error) {
+	switch len(challengeBytes) {
+	case 0:
+		return nil, nil
+
+	case 32:
+		challenge := chanutils.ToArray[[32]byte](challengeBytes)
+		return &challenge, nil
+
+	default:
+		return nil, fmt.Errorf("challenge must be 32 bytes")
+	}
+}
+
 // ProveAssetOwnership creates an ownership proof embedded in an asset
 // transition proof. That ownership proof is a signed virtual transaction
 // spending the asset with a valid witness to prove the prover owns the keys
-// that can spend the asset.
+// that can spend the asset. If a challenge is given, the ownership proof is
+// bound to it.
 func (r *rpcServer) ProveAssetOwnership(ctx context.Context,
 	in *wrpc.ProveAssetOwnershipRequest) (*wrpc.ProveAssetOwnershipResponse,
 	error) {
@@ -2729,6 +4162,11 @@ func (r *rpcServer) ProveAssetOwnership(ctx context.Context,
 		return nil, fmt.Errorf("asset ID must be 32 bytes")
 	}
 
+	challenge, err := parseOwnershipChallenge(in.Challenge)
+	if err != nil {
+		return nil, err
+	}
+
 	assetID := chanutils.ToArray[asset.ID](in.AssetId)
 	proofBlob, err := r.cfg.ProofArchive.FetchProof(ctx, proof.Locator{
 		AssetID:   &assetID,
@@ -2760,7 +4198,7 @@ func (r *rpcServer) ProveAssetOwnership(ctx context.Context,
 	}
 
 	challengeWitness, err := r.cfg.AssetWallet.SignOwnershipProof(
-		inputCommitment.Asset.Copy(),
+		inputCommitment.Asset.Copy(), challenge,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("error signing ownership proof: %w", err)
@@ -2785,6 +4223,7 @@ func (r *rpcServer) ProveAssetOwnership(ctx context.Context,
 
 // VerifyAssetOwnership verifies the asset ownership proof embedded in the
 // given transition proof of an asset and returns true if the proof is valid.
+// If the proof was created with a challenge, the same challenge must be given.
 func (r *rpcServer) VerifyAssetOwnership(ctx context.Context,
 	in *wrpc.VerifyAssetOwnershipRequest) (*wrpc.VerifyAssetOwnershipResponse,
 	error) {
@@ -2793,14 +4232,19 @@ func (r *rpcServer) VerifyAssetOwnership(ctx context.Context,
 		return nil, fmt.Errorf("a valid proof must be specified")
 	}
 
+	challenge, err := parseOwnershipChallenge(in.Challenge)
+	if err != nil {
+		return nil, err
+	}
+
 	p := &proof.Proof{}
-	err := p.Decode(bytes.NewReader(in.ProofWithWitness))
+	err = p.Decode(bytes.NewReader(in.ProofWithWitness))
 	if err != nil {
 		return nil, fmt.Errorf("cannot decode proof file: %w", err)
 	}
 
 	headerVerifier := tapgarden.GenHeaderVerifier(ctx, r.cfg.ChainBridge)
-	_, err = p.Verify(ctx, nil, headerVerifier)
+	_, err = p.VerifyOwnershipProof(ctx, headerVerifier, challenge)
 	if err != nil {
 		return nil, fmt.Errorf("error verifying proof: %w", err)
 	}
@@ -2810,6 +4254,215 @@ func (r *rpcServer) VerifyAssetOwnership(ctx context.Context,
 	}, nil
 }
 
+// ProveReserves creates a reserves report over all unspent assets owned by the
+// wallet. Each asset's proof file is included, with an ownership proof bound
+// to the given challenge in its last proof.
+func (r *rpcServer) ProveReserves(ctx context.Context,
+	in *wrpc.ProveReservesRequest) (*wrpc.ProveReservesResponse, error) {
+
+	if len(in.Challenge) != 32 {
+		return nil, fmt.Errorf("challenge must be 32 bytes")
+	}
+
+	challenge := chanutils.ToArray[[32]byte](in.Challenge)
+	report, err := r.cfg.AssetWallet.ProveReserves(ctx, challenge)
+	if err != nil {
+		return nil, fmt.Errorf("error proving reserves: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := report.Encode(&buf); err != nil {
+		return nil, fmt.Errorf("error encoding reserves report: %w",
+			err)
+	}
+
+	return &wrpc.ProveReservesResponse{
+		ReservesReport: buf.Bytes(),
+	}, nil
+}
+
+// VerifyReserves verifies a reserves report against the given challenge and
+// returns the total reserves it proves, along with the anchor outputs the
+// caller needs to check are still unspent.
+func (r *rpcServer) VerifyReserves(ctx context.Context,
+	in *wrpc.VerifyReservesRequest) (*wrpc.VerifyReservesResponse, error) {
+
+	if len(in.ReservesReport) == 0 {
+		return nil, fmt.Errorf("a reserves report must be specified")
+	}
+
+	if len(in.Challenge) != 32 {
+		return nil, fmt.Errorf("challenge must be 32 bytes")
+	}
+
+	report := &proof.ReservesReport{}
+	err := report.Decode(bytes.NewReader(in.ReservesReport))
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode reserves report: %w", err)
+	}
+
+	challenge := chanutils.ToArray[[32]byte](in.Challenge)
+	headerVerifier := tapgarden.GenHeaderVerifier(ctx, r.cfg.ChainBridge)
+	summary, err := report.Verify(ctx, challenge, headerVerifier)
+	if err != nil {
+		return nil, fmt.Errorf("error verifying reserves report: %w",
+			err)
+	}
+
+	return marshalReservesSummary(summary), nil
+}
+
+// marshalReservesSummary converts a verified reserves summary into its RPC
+// counterpart. All totals and outpoints are sorted so the response is
+// deterministic.
+func marshalReservesSummary(
+	summary *proof.ReservesSummary) *wrpc.VerifyReservesResponse {
+
+	resp := &wrpc.VerifyReservesResponse{}
+	for id, amount := range summary.Totals {
+		id := id
+		resp.AssetTotals = append(
+			resp.AssetTotals, &wrpc.AssetReserveTotal{
+				AssetId: id[:],
+				Amount:  amount,
+			},
+		)
+	}
+	sort.Slice(resp.AssetTotals, func(i, j int) bool {
+		a, b := resp.AssetTotals[i], resp.AssetTotals[j]
+		return bytes.Compare(a.AssetId, b.AssetId) < 0
+	})
+
+	for groupKey, amount := range summary.GroupTotals {
+		groupKey := groupKey
+		resp.GroupTotals = append(
+			resp.GroupTotals, &wrpc.GroupReserveTotal{
+				GroupKey: groupKey[:],
+				Amount:   amount,
+			},
+		)
+	}
+	sort.Slice(resp.GroupTotals, func(i, j int) bool {
+		a, b := resp.GroupTotals[i], resp.GroupTotals[j]
+		return bytes.Compare(a.GroupKey, b.GroupKey) < 0
+	})
+
+	// Multiple assets can be committed to the same anchor output, which
+	// only needs to be checked once.
+	outPoints := make(map[wire.OutPoint]struct{}, len(summary.Assets))
+	for _, snapshot := range summary.Assets {
+		outPoints[snapshot.OutPoint] = struct{}{}
+	}
+	for op := range outPoints {
+		op := op
+		resp.AnchorOutpoints = append(
+			resp.AnchorOutpoints, &wrpc.OutPoint{
+				Txid:        op.Hash[:],
+				OutputIndex: op.Index,
+			},
+		)
+	}
+	sort.Slice(resp.AnchorOutpoints, func(i, j int) bool {
+		a, b := resp.AnchorOutpoints[i], resp.AnchorOutpoints[j]
+		if cmp := bytes.Compare(a.Txid, b.Txid); cmp != 0 {
+			return cmp < 0
+		}
+
+		return a.OutputIndex < b.OutputIndex
+	})
+
+	return resp
+}
+
+// PrepareInteractiveReceive is called on the receiving node of an interactive
+// transfer. It validates the requested transfer and derives a new script key
+// and anchor internal key the sender should send the asset to.
+func (r *rpcServer) PrepareInteractiveReceive(ctx context.Context,
+	in *wrpc.PrepareInteractiveReceiveRequest) (
+	*wrpc.PrepareInteractiveReceiveResponse, error) {
+
+	if len(in.AssetId) != sha256.Size {
+		return nil, fmt.Errorf("invalid asset id length")
+	}
+	if in.Amount == 0 {
+		return nil, fmt.Errorf("amount must be greater than zero")
+	}
+
+	var assetID asset.ID
+	copy(assetID[:], in.AssetId)
+
+	// We need to know the genesis of the asset, otherwise we won't be
+	// able to verify and import the proof of the transfer later on.
+	_, err := r.cfg.TapAddrBook.QueryAssetGroup(ctx, assetID)
+	if err != nil {
+		return nil, fmt.Errorf("unknown asset %x: %w", assetID[:], err)
+	}
+
+	err = r.checkBalanceOverflow(ctx, &assetID, nil, in.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	scriptKey, err := r.cfg.AddrBook.NextScriptKey(
+		ctx, asset.TaprootAssetsKeyFamily,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error deriving script key: %w", err)
+	}
+
+	internalKey, err := r.cfg.AddrBook.NextInternalKey(
+		ctx, asset.TaprootAssetsKeyFamily,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error deriving internal key: %w", err)
+	}
+
+	rpcsLog.Infof("[PrepareInteractiveReceive]: receiving %d units of "+
+		"asset_id=%x with script_key=%x", in.Amount, assetID[:],
+		scriptKey.PubKey.SerializeCompressed())
+
+	return &wrpc.PrepareInteractiveReceiveResponse{
+		ScriptKey:         marshalScriptKey(scriptKey),
+		AnchorInternalKey: marshalKeyDescriptor(internalKey),
+	}, nil
+}
+
+// VerifyInteractiveReceive is called on the receiving node of an interactive
+// transfer to countersign the virtual PSBT signed by the sender, before the
+// sender anchors it on chain.
+func (r *rpcServer) VerifyInteractiveReceive(ctx context.Context,
+	in *wrpc.VerifyInteractiveReceiveRequest) (
+	*wrpc.VerifyInteractiveReceiveResponse, error) {
+
+	if len(in.SignedPsbt) == 0 {
+		return nil, fmt.Errorf("signed PSBT must be specified")
+	}
+
+	vPkt, err := tappsbt.NewFromRawBytes(
+		bytes.NewReader(in.SignedPsbt), false,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding packet: %w", err)
+	}
+
+	receiverOutputs, err := r.cfg.AssetWallet.VerifyInteractiveReceive(
+		ctx, vPkt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error verifying packet: %w", err)
+	}
+
+	var amount uint64
+	for _, idx := range receiverOutputs {
+		amount += vPkt.Outputs[idx].Amount
+	}
+
+	return &wrpc.VerifyInteractiveReceiveResponse{
+		ReceiverOutputs: receiverOutputs,
+		Amount:          amount,
+	}, nil
+}
+
 // UniverseStats returns a set of aggregrate statistics for the current state
 // of the Universe.
 func (r *rpcServer) UniverseStats(ctx context.Context,
@@ -2821,9 +4474,12 @@ func (r *rpcServer) UniverseStats(ctx context.Context,
 	}
 
 	return &unirpc.StatsResponse{
-		NumTotalAssets: int64(universeStats.NumTotalAssets),
-		NumTotalSyncs:  int64(universeStats.NumTotalSyncs),
-		NumTotalProofs: int64(universeStats.NumTotalProofs),
+		NumTotalAssets:  int64(universeStats.NumTotalAssets),
+		NumTotalSyncs:   int64(universeStats.NumTotalSyncs),
+		NumTotalProofs:  int64(universeStats.NumTotalProofs),
+		NumTotalQueries: int64(universeStats.NumTotalQueries),
+		NumTotalGroups:  int64(universeStats.NumTotalGroups),
+		NumTotalLeaves:  int64(universeStats.NumTotalLeaves),
 	}, nil
 }
 
@@ -2840,6 +4496,33 @@ func marshalAssetSyncSnapshot(a universe.AssetSyncSnapshot,
 		GenesisHeight: int32(a.GenesisHeight),
 		TotalSyncs:    int64(a.TotalSyncs),
 		TotalProofs:   int64(a.TotalProofs),
+		TotalQueries:  int64(a.TotalQueries),
+	}
+}
+
+// unmarshalAssetQuerySort maps an RPC asset stats sort order to the universe
+// counterpart.
+func unmarshalAssetQuerySort(
+	sortBy unirpc.AssetQuerySort) (universe.SyncStatsSort, error) {
+
+	switch sortBy {
+	case unirpc.AssetQuerySort_SORT_BY_NONE:
+		return universe.SortByNone, nil
+
+	case unirpc.AssetQuerySort_SORT_BY_ASSET_NAME:
+		return universe.SortByAssetName, nil
+
+	case unirpc.AssetQuerySort_SORT_BY_ASSET_ID:
+		return universe.SortByAssetID, nil
+
+	case unirpc.AssetQuerySort_SORT_BY_ASSET_TYPE:
+		return universe.SortByAssetType, nil
+
+	case unirpc.AssetQuerySort_SORT_BY_TOTAL_QUERIES:
+		return universe.SortByTotalQueries, nil
+
+	default:
+		return 0, fmt.Errorf("unknown sort order: %v", sortBy)
 	}
 }
 
@@ -2850,6 +4533,11 @@ func marshalAssetSyncSnapshot(a universe.AssetSyncSnapshot,
 func (r *rpcServer) QueryAssetStats(ctx context.Context,
 	req *unirpc.AssetStatsQuery) (*unirpc.UniverseAssetStats, error) {
 
+	sortBy, err := unmarshalAssetQuerySort(req.SortBy)
+	if err != nil {
+		return nil, err
+	}
+
 	assetStats, err := r.cfg.UniverseStats.QuerySyncStats(
 		ctx, universe.SyncStatsQuery{
 			AssetNameFilter: req.AssetNameFilter,
@@ -2868,7 +4556,7 @@ func (r *rpcServer) QueryAssetStats(ctx context.Context,
 			AssetIDFilter: chanutils.ToArray[asset.ID](
 				req.AssetIdFilter,
 			),
-			SortBy: universe.SyncStatsSort(req.SortBy),
+			SortBy: sortBy,
 			Offset: int(req.Offset),
 			Limit:  int(req.Limit),
 		},
@@ -0,0 +1,150 @@
+package taprootassets
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightninglabs/taproot-assets/internal/test"
+	"github.com/lightninglabs/taproot-assets/proof"
+	"github.com/lightninglabs/taproot-assets/taprpc"
+	"github.com/lightninglabs/taproot-assets/taprpc/assetwalletrpc"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMarshalGroupMetaHistory tests that the metadata history of an asset
+// group is returned in order, with the latest update as canonical metadata.
+func TestMarshalGroupMetaHistory(t *testing.T) {
+	t.Parallel()
+
+	// A group that was never updated has no canonical metadata.
+	history := &proof.MetaHistory{
+		GroupKey: test.RandPubKey(t),
+	}
+	resp := marshalGroupMetaHistory(history)
+	require.Empty(t, resp.Updates)
+	require.Nil(t, resp.CanonicalMeta)
+
+	first := &proof.MetaReveal{
+		Type: proof.MetaOpaque,
+		Data: []byte("first"),
+	}
+	second := &proof.MetaReveal{
+		Type: proof.MetaOpaque,
+		Data: []byte("second"),
+	}
+
+	var firstID, secondID asset.ID
+	copy(firstID[:], test.RandBytes(32))
+	copy(secondID[:], test.RandBytes(32))
+
+	var prevHash [asset.MetaHashLen]byte
+	copy(prevHash[:], test.RandBytes(32))
+
+	history.Updates = []proof.MetaHistoryEntry{{
+		AssetID: firstID,
+		Update: &proof.MetaUpdate{
+			Sequence: 1,
+			Meta:     first,
+		},
+	}, {
+		AssetID: secondID,
+		Update: &proof.MetaUpdate{
+			Sequence: 2,
+			PrevHash: prevHash,
+			Meta:     second,
+		},
+	}}
+
+	resp = marshalGroupMetaHistory(history)
+	require.Len(t, resp.Updates, 2)
+
+	require.Equal(t, firstID[:], resp.Updates[0].AssetId)
+	require.EqualValues(t, 1, resp.Updates[0].Sequence)
+	require.Equal(
+		t, make([]byte, asset.MetaHashLen), resp.Updates[0].PrevHash,
+	)
+	require.Equal(t, first.Data, resp.Updates[0].Meta.Data)
+
+	secondHash := second.MetaHash()
+	require.Equal(t, secondID[:], resp.Updates[1].AssetId)
+	require.EqualValues(t, 2, resp.Updates[1].Sequence)
+	require.Equal(t, prevHash[:], resp.Updates[1].PrevHash)
+	require.Equal(t, second.Data, resp.Updates[1].Meta.Data)
+	require.Equal(t, secondHash[:], resp.Updates[1].Meta.MetaHash)
+
+	require.Equal(t, &taprpc.AssetMeta{
+		Data:     second.Data,
+		Type:     taprpc.AssetMetaType(second.Type),
+		MetaHash: secondHash[:],
+	}, resp.CanonicalMeta)
+}
+
+// TestMarshalReservesSummary tests that a verified reserves summary is returned
+// with sorted totals and a deduplicated, sorted list of anchor outpoints.
+func TestMarshalReservesSummary(t *testing.T) {
+	t.Parallel()
+
+	resp := marshalReservesSummary(&proof.ReservesSummary{})
+	require.Empty(t, resp.AssetTotals)
+	require.Empty(t, resp.GroupTotals)
+	require.Empty(t, resp.AnchorOutpoints)
+
+	var id1, id2 asset.ID
+	copy(id1[:], test.RandBytes(32))
+	copy(id2[:], test.RandBytes(32))
+	if bytes.Compare(id1[:], id2[:]) > 0 {
+		id1, id2 = id2, id1
+	}
+
+	groupKey := asset.ToSerialized(test.RandPubKey(t))
+
+	op1, op2 := test.RandOp(t), test.RandOp(t)
+	if bytes.Compare(op1.Hash[:], op2.Hash[:]) > 0 {
+		op1, op2 = op2, op1
+	}
+	op1b := op1
+	op1b.Index = op1.Index + 1
+
+	// Two assets are committed to the same anchor output, which must only
+	// be reported once.
+	resp = marshalReservesSummary(&proof.ReservesSummary{
+		Assets: []*proof.AssetSnapshot{
+			{OutPoint: op2},
+			{OutPoint: op1b},
+			{OutPoint: op1},
+			{OutPoint: op2},
+		},
+		Totals: map[asset.ID]uint64{
+			id2: 20,
+			id1: 10,
+		},
+		GroupTotals: map[asset.SerializedKey]uint64{
+			groupKey: 30,
+		},
+	})
+
+	require.Equal(t, []*assetwalletrpc.AssetReserveTotal{{
+		AssetId: id1[:],
+		Amount:  10,
+	}, {
+		AssetId: id2[:],
+		Amount:  20,
+	}}, resp.AssetTotals)
+
+	require.Equal(t, []*assetwalletrpc.GroupReserveTotal{{
+		GroupKey: groupKey[:],
+		Amount:   30,
+	}}, resp.GroupTotals)
+
+	require.Equal(t, []*assetwalletrpc.OutPoint{{
+		Txid:        op1.Hash[:],
+		OutputIndex: op1.Index,
+	}, {
+		Txid:        op1b.Hash[:],
+		OutputIndex: op1b.Index,
+	}, {
+		Txid:        op2.Hash[:],
+		OutputIndex: op2.Index,
+	}}, resp.AnchorOutpoints)
+}
@@ -0,0 +1,23 @@
+#!/bin/bash
+
+set -e
+
+# run_fuzz runs all fuzz tests of the given packages one after the other, as Go
+# only allows fuzzing a single test at a time.
+function run_fuzz() {
+  PACKAGES=$1
+  RUN_TIME=$2
+  TIMEOUT=$3
+  PROCESSES=$4
+
+  for pkg in $PACKAGES; do
+    for fuzz in $(go test -list '^Fuzz' "./$pkg" | grep '^Fuzz'); do
+      echo "----- Fuzz testing $pkg:$fuzz for $RUN_TIME -----"
+      go test "./$pkg" -run '^$' -fuzz "^$fuzz\$" \
+        -fuzztime "$RUN_TIME" -test.timeout "$TIMEOUT" \
+        -parallel "$PROCESSES"
+    done
+  done
+}
+
+run_fuzz "$@"
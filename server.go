@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -102,6 +104,11 @@ func (s *Server) initialize(interceptorChain *rpcperms.InterceptorChain) error {
 
 		shutdownFuncs["macaroonService"] = s.macaroonService.Stop
 
+		if err := s.bakeUniverseMacaroons(); err != nil {
+			return fmt.Errorf("unable to bake universe "+
+				"macaroons: %v", err)
+		}
+
 		if interceptorChain != nil {
 			// Register the macaroon service with the main
 			// interceptor chain.
@@ -142,6 +149,14 @@ func (s *Server) initialize(interceptorChain *rpcperms.InterceptorChain) error {
 		return fmt.Errorf("unable to start asset custodian: %v", err)
 	}
 
+	if err := s.cfg.ReorgWatcher.Start(); err != nil {
+		return fmt.Errorf("unable to start reorg watcher: %v", err)
+	}
+
+	if err := s.cfg.BatchJanitor.Start(); err != nil {
+		return fmt.Errorf("unable to start batch janitor: %v", err)
+	}
+
 	if err := s.cfg.ChainPorter.Start(); err != nil {
 		return fmt.Errorf("unable to start chain porter: %v", err)
 	}
@@ -151,6 +166,14 @@ func (s *Server) initialize(interceptorChain *rpcperms.InterceptorChain) error {
 			"federation: %v", err)
 	}
 
+	if err := s.cfg.MetricsExporter.Start(); err != nil {
+		return fmt.Errorf("unable to start metrics exporter: %v", err)
+	}
+
+	if err := s.cfg.HealthReporter.Start(); err != nil {
+		return fmt.Errorf("unable to start health reporter: %v", err)
+	}
+
 	// Now we have created all dependencies necessary to populate and
 	// start the RPC server.
 	if err := s.rpcServer.Start(); err != nil {
@@ -225,8 +248,14 @@ func (s *Server) RunUntilShutdown(mainErrChan <-chan error) error {
 	// will be used to log the API calls invoked on the GRPC server.
 	interceptorChain := rpcperms.NewInterceptorChain(
 		rpcsLog, s.cfg.RPCConfig.NoMacaroons, nil,
-		perms.MacaroonWhitelist,
+		perms.MacaroonWhitelist(s.cfg.RPCConfig.UniversePublicAccess),
 	)
+	if s.cfg.RPCConfig.UniverseRateLimit.Enabled() {
+		interceptorChain.AddRateLimiter(rpcperms.NewRateLimiter(
+			*s.cfg.RPCConfig.UniverseRateLimit,
+			perms.UniverseMethods(),
+		))
+	}
 	if err := interceptorChain.Start(); err != nil {
 		return mkErr("error starting interceptor chain: %v", err)
 	}
@@ -324,6 +353,52 @@ func (s *Server) StartAsSubserver(lndGrpc *lndclient.GrpcLndServices) error {
 	return nil
 }
 
+// bakeUniverseMacaroons bakes the macaroons of the universe read-only and
+// proof push roles next to the admin macaroon, unless they already exist.
+// These can be handed out to the clients of a universe server that doesn't
+// allow public access.
+func (s *Server) bakeUniverseMacaroons() error {
+	if s.cfg.MacaroonPath == "" {
+		return nil
+	}
+
+	macDir := filepath.Dir(s.cfg.MacaroonPath)
+	roles := map[string][]bakery.Op{
+		universeReadOnlyMacaroon: perms.UniverseReadOnlyPermissions,
+		universePushMacaroon:     perms.UniversePushPermissions,
+	}
+	for fileName, ops := range roles {
+		macPath := filepath.Join(macDir, fileName)
+		if lnrpc.FileExists(macPath) {
+			continue
+		}
+
+		idCtx := macaroons.ContextWithRootKeyID(
+			context.Background(), macaroons.DefaultRootKeyID,
+		)
+		mac, err := s.macaroonService.Oven.NewMacaroon(
+			idCtx, bakery.LatestVersion, nil, ops...,
+		)
+		if err != nil {
+			return err
+		}
+
+		macBytes, err := mac.M().MarshalBinary()
+		if err != nil {
+			return err
+		}
+
+		err = os.WriteFile(macPath, macBytes, 0644)
+		if err != nil {
+			return err
+		}
+
+		rpcsLog.Infof("Baked universe macaroon at: %v", macPath)
+	}
+
+	return nil
+}
+
 // ValidateMacaroon extracts the macaroon from the context's gRPC metadata,
 // checks its signature, makes sure all specified permissions for the called
 // method are contained within and finally ensures all caveat conditions are
@@ -502,34 +577,105 @@ func (s *Server) Stop() error {
 
 	srvrLog.Infof("Stopping Main Server")
 
-	if err := s.rpcServer.Stop(); err != nil {
-		return err
-	}
-	if err := s.cfg.AssetMinter.Stop(); err != nil {
-		return err
-	}
-	if err := s.cfg.AssetCustodian.Stop(); err != nil {
+	// Signal to health checkers that we're going away before we start
+	// tearing down the subsystems.
+	s.cfg.HealthReporter.Shutdown()
+
+	if err := s.newShutdownCoordinator().Stop(); err != nil {
 		return err
 	}
 
-	if err := s.cfg.ChainPorter.Stop(); err != nil {
-		return err
+	close(s.quit)
+
+	s.wg.Wait()
+
+	return nil
+}
+
+// newShutdownCoordinator creates a shutdown coordinator that stops all
+// subsystems of the server in dependency order. The RPC server is stopped
+// first, so no new requests reach the subsystems while they shut down.
+func (s *Server) newShutdownCoordinator() *chanutils.ShutdownCoordinator {
+	c := chanutils.NewShutdownCoordinator(s.cfg.ShutdownTimeout)
+
+	// mustRegister registers a subsystem. This can only fail if a
+	// subsystem is registered twice or before its dependencies, which
+	// would be a programming error.
+	mustRegister := func(name string, stop chanutils.StopFunc,
+		dependsOn ...string) {
+
+		if err := c.Register(name, stop, 0, dependsOn...); err != nil {
+			panic(err)
+		}
 	}
 
-	if err := s.cfg.UniverseFederation.Start(); err != nil {
-		return err
+	// The database is closed last, once all subsystems that read from or
+	// write to it have stopped.
+	closeDB := func() error {
+		if s.cfg.DatabaseConfig.Closer == nil {
+			return nil
+		}
+
+		return s.cfg.DatabaseConfig.Closer.Close()
 	}
+	mustRegister("database", closeDB)
 
-	if s.macaroonService != nil {
-		err := s.macaroonService.Stop()
-		if err != nil {
-			return err
+	// The minter, the porter and the RPC server sign through the remote
+	// signer, if one is configured, so its connection is closed last as
+	// well.
+	closeSigner := func() error {
+		if s.cfg.RemoteSignerConn == nil {
+			return nil
 		}
+
+		return s.cfg.RemoteSignerConn.Close()
 	}
+	mustRegister("remote signer", closeSigner)
 
-	close(s.quit)
+	// The minter pushes the issuance proofs of its batches to the
+	// universe federation, so the federation must outlive it.
+	mustRegister(
+		"universe federation", s.cfg.UniverseFederation.Stop,
+		"database",
+	)
+	mustRegister(
+		"asset minter", s.cfg.AssetMinter.Stop, "universe federation",
+		"database", "remote signer",
+	)
 
-	s.wg.Wait()
+	// The custodian and the porter deliver proofs through proof couriers
+	// and store them in the proof archive. A courier only lives for a
+	// single delivery and is closed by the subsystem that created it, and
+	// the archive is backed by the database, so they only need to stop
+	// before the database.
+	mustRegister("asset custodian", s.cfg.AssetCustodian.Stop, "database")
+	mustRegister(
+		"chain porter", s.cfg.ChainPorter.Stop, "database",
+		"remote signer",
+	)
+	mustRegister("reorg watcher", s.cfg.ReorgWatcher.Stop, "database")
+	mustRegister("batch janitor", s.cfg.BatchJanitor.Stop, "database")
+	mustRegister(
+		"metrics exporter", s.cfg.MetricsExporter.Stop, "database",
+	)
+	mustRegister("health reporter", s.cfg.HealthReporter.Stop)
 
-	return nil
+	subsystems := []string{
+		"universe federation", "asset minter", "asset custodian",
+		"chain porter", "reorg watcher", "batch janitor",
+		"metrics exporter", "health reporter",
+	}
+	if s.macaroonService != nil {
+		// The macaroon service keeps its root keys in the database.
+		mustRegister(
+			"macaroon service", s.macaroonService.Stop, "database",
+		)
+		subsystems = append(subsystems, "macaroon service")
+	}
+
+	// The RPC server is stopped first, so no new requests reach any of
+	// the subsystems while they shut down.
+	mustRegister("rpc server", s.rpcServer.Stop, subsystems...)
+
+	return c
 }
@@ -10,6 +10,7 @@ import (
 	"os/user"
 	"path"
 	"path/filepath"
+	"runtime"
 	"strconv"
 	"strings"
 	"time"
@@ -21,8 +22,13 @@ import (
 	"github.com/jessevdk/go-flags"
 	"github.com/lightninglabs/lndclient"
 	tap "github.com/lightninglabs/taproot-assets"
+	"github.com/lightninglabs/taproot-assets/monitoring"
+	"github.com/lightninglabs/taproot-assets/perms"
 	"github.com/lightninglabs/taproot-assets/proof"
+	"github.com/lightninglabs/taproot-assets/rpcperms"
 	"github.com/lightninglabs/taproot-assets/tapdb"
+	"github.com/lightninglabs/taproot-assets/tapgarden"
+	"github.com/lightninglabs/taproot-assets/vm"
 	"github.com/lightningnetwork/lnd/build"
 	"github.com/lightningnetwork/lnd/cert"
 	"github.com/lightningnetwork/lnd/lncfg"
@@ -53,6 +59,10 @@ const (
 
 	defaultAcceptRemoteProofs = false
 
+	// defaultUniversePublicAccess is the default public access level of the
+	// universe RPCs.
+	defaultUniversePublicAccess = perms.UniversePublicAccessReadWrite
+
 	defaultTestnetFederationServer = "testnet.universe.lightning.finance:12010"
 
 	// DefaultAutogenValidity is the default validity of a self-signed
@@ -67,6 +77,23 @@ const (
 	// batch.
 	defaultBatchMintingInterval = time.Minute * 10
 
+	// defaultCoinSelectStrategy is the default coin selection strategy
+	// used to select the assets that fund a send.
+	defaultCoinSelectStrategy = "max-amount"
+
+	// defaultBatchRetryAttempts is the default number of times a step of
+	// a minting batch that failed, like the funding or broadcast of its
+	// genesis transaction, is attempted.
+	defaultBatchRetryAttempts = 5
+
+	// defaultBatchRetryInitialBackoff is the default time to wait before
+	// retrying a failed step of a minting batch for the first time.
+	defaultBatchRetryInitialBackoff = time.Second * 5
+
+	// defaultBatchRetryMaxBackoff is the default maximum time to wait
+	// between two attempts of a failed step of a minting batch.
+	defaultBatchRetryMaxBackoff = time.Minute
+
 	// defaultHashMailAddr is the default address we'll use to deliver
 	// optionally deliver proofs for asynchronous sends.
 	defaultHashMailAddr = "mailbox.terminal.lightning.today:443"
@@ -77,6 +104,18 @@ const (
 	// DatabaseBackendPostgres is the name of the Postgres database backend.
 	DatabaseBackendPostgres = "postgres"
 
+	// VMLimitsStandard is the name of the VM limits that are stricter than
+	// consensus and reject unusually large witnesses and scripts.
+	VMLimitsStandard = "standard"
+
+	// VMLimitsConsensus is the name of the VM limits that are equivalent
+	// to the limits of Bitcoin consensus.
+	VMLimitsConsensus = "consensus"
+
+	// defaultProofRetryInterval is the default interval at which we
+	// re-attempt the delivery of proofs that couldn't be delivered yet.
+	defaultProofRetryInterval = 10 * time.Minute
+
 	// defaultProofTransferBackoffResetWait is the default amount of time
 	// we'll wait before resetting the backoff of a proof transfer.
 	defaultProofTransferBackoffResetWait = 10 * time.Minute
@@ -100,6 +139,14 @@ const (
 	// defaultuniverseSyncInterval is the default interval that we'll use
 	// to sync Universe state with the federation.
 	defaultUniverseSyncInterval = time.Minute * 10
+
+	// defaultRemoteSignerTimeout is the default timeout we'll use for a
+	// single signing request sent to a remote signer.
+	defaultRemoteSignerTimeout = 30 * time.Second
+
+	// defaultShutdownTimeout is the default time we give each subsystem to
+	// stop within when shutting down.
+	defaultShutdownTimeout = 30 * time.Second
 )
 
 var (
@@ -127,6 +174,21 @@ var (
 
 	defaultSqliteDatabaseFileName = "tapd.db"
 
+	// defaultProofEncryptionSaltFileName is the name of the file within
+	// the network directory that holds the salt used to derive the
+	// encryption key of proof files from a passphrase.
+	defaultProofEncryptionSaltFileName = "proofencryption.salt"
+
+	// defaultDatabaseBackupDirname is the name of the directory within
+	// the network directory that database backups are written to by
+	// default.
+	defaultDatabaseBackupDirname = "backups"
+
+	// defaultRestoredBackupFileName is the name of the file within the
+	// network directory that records the hash of the last backup that was
+	// restored, so the same backup is never restored twice.
+	defaultRestoredBackupFileName = "restoredbackup"
+
 	// defaultLndMacaroon is the default macaroon file we use if the old,
 	// deprecated --lnd.macaroondir config option is used.
 	defaultLndMacaroon = "admin.macaroon"
@@ -223,6 +285,47 @@ type LndConfig struct {
 	TLSPath string `long:"tlspath" description:"Path to lnd tls certificate"`
 }
 
+// RemoteSignerConfig is the config used to connect to an external signing
+// service that signs asset virtual transactions and asset group keys instead of
+// the lnd node that backs tapd. The remote signer must implement lnd's
+// signrpc.Signer service.
+type RemoteSignerConfig struct {
+	Enable bool `long:"enable" description:"Use a remote signer for signing asset virtual transactions and group keys instead of the backing lnd node"`
+
+	Host string `long:"host" description:"The remote signer's rpc address"`
+
+	MacaroonPath string `long:"macaroonpath" description:"The full path to the macaroon to use for authenticating with the remote signer"`
+
+	TLSPath string `long:"tlspath" description:"Path to the remote signer's tls certificate"`
+
+	Timeout time.Duration `long:"timeout" description:"The timeout for a single signing request sent to the remote signer"`
+}
+
+// ProofEncryptionConfig is the config that governs the encryption of the proof
+// files that are stored on disk.
+type ProofEncryptionConfig struct {
+	Enable bool `long:"enable" description:"Encrypt all proof files that are stored on disk. Unless a passphrase is set, the encryption key is derived from the seed of the backing lnd node. Proof files that were stored in plain text before can still be read."`
+
+	Passphrase string `long:"passphrase" description:"If set, the encryption key of the proof files is derived from this passphrase instead of the seed of the backing lnd node."`
+
+	Migrate bool `long:"migrate" description:"If set, all proof files that are still stored in plain text are encrypted on startup."`
+}
+
+// VMConfig is the config of the Taproot Asset VM that validates the witnesses
+// of outgoing transfers.
+type VMConfig struct {
+	Limits string `long:"limits" description:"The limits enforced on the witnesses of outgoing transfers before they are broadcast. With 'standard', transfers with unusually large witnesses or scripts are rejected. With 'consensus', only transfers that would be invalid on chain are rejected. Received proofs are always verified with the consensus limits." choice:"standard" choice:"consensus"`
+}
+
+// VMLimits returns the VM limits that correspond to the configured name.
+func (c *VMConfig) VMLimits() vm.Limits {
+	if c.Limits == VMLimitsConsensus {
+		return vm.DefaultLimits()
+	}
+
+	return vm.StandardLimits()
+}
+
 // UniverseConfig is the config that houses any Universe related config
 // values.
 type UniverseConfig struct {
@@ -230,7 +333,37 @@ type UniverseConfig struct {
 
 	AcceptRemoteProofs bool `long:"accept-remote-proofs" description:"If true, then if the Universe server is on a public interface, valid proof from remote parties will be accepted"`
 
+	PublicAccess string `long:"publicaccess" description:"The universe RPCs that can be called without a macaroon. With 'read', anyone can query the universe, with 'readwrite', anyone can additionally insert proofs. With 'none', clients need a macaroon such as the universe-readonly.macaroon or universe-push.macaroon baked next to the admin macaroon." choice:"none" choice:"read" choice:"readwrite"`
+
+	// RateLimit configures the per-peer rate limiting and request quota of
+	// the universe RPCs.
+	RateLimit *rpcperms.RateLimitConfig `group:"ratelimit" namespace:"ratelimit"`
+
 	FederationServers []string `long:"federationserver" description:"The host:port of a Universe server peer with. These servers will be added as the default set of federation servers. Can be specified multiple times."`
+
+	SyncAssetIDs []string `long:"syncassetid" description:"The hex encoded asset ID of a universe to sync with the federation. If any asset IDs or group keys are set, only their universes are synced instead of all the universes known to a federation server. Can be specified multiple times."`
+
+	SyncGroupKeys []string `long:"syncgroupkey" description:"The hex encoded x-only group key of a universe to sync with the federation. If any asset IDs or group keys are set, only their universes are synced instead of all the universes known to a federation server. Can be specified multiple times."`
+
+	IndexTransfers bool `long:"indextransfers" description:"If true, all universes index the proofs of asset transfers in addition to issuance proofs. This allows receivers to fetch the full provenance of an asset from this universe server."`
+
+	TransferAssetIDs []string `long:"transferassetid" description:"The hex encoded asset ID of a universe that indexes the proofs of asset transfers in addition to issuance proofs. Has no effect if indextransfers is set. Can be specified multiple times."`
+
+	TransferGroupKeys []string `long:"transfergroupkey" description:"The hex encoded x-only group key of a universe that indexes the proofs of asset transfers in addition to issuance proofs. Has no effect if indextransfers is set. Can be specified multiple times."`
+
+	MintProofPushServer string `long:"mintproofpushserver" description:"The host:port of a Universe server that the genesis proofs of every confirmed minting batch are pushed to. A batch is only finalized once its proofs were pushed successfully."`
+
+	// MintProofPushBackoff configures how failed pushes of genesis proofs
+	// to the MintProofPushServer are retried.
+	MintProofPushBackoff *proof.BackoffCfg `group:"mintproofpush" namespace:"mintproofpush"`
+
+	NodeCacheSize uint64 `long:"nodecachesize" description:"The maximum number of MS-SMT nodes of the universe trees that are kept in memory, so they can be served without hitting the database. Set to 0 to disable the cache."`
+
+	TreeBuildConcurrency int `long:"treebuildconcurrency" description:"The maximum number of goroutines used to build the MS-SMT of a universe when many leaves are inserted at once, such as when the tree is rebuilt. Set to 1 to build trees sequentially."`
+
+	RebuildTrees bool `long:"rebuildtrees" description:"If set, the MS-SMTs of all universes are rebuilt from their stored leaves on startup."`
+
+	StrictHeaderVerification bool `long:"strictheaderverification" description:"If true, the block headers of proofs that are inserted into the universe are checked for a valid proof of work and for being part of the best chain of the chain backend, instead of only being looked up. Requires the blocks of the chain to commit to their height (BIP 34)."`
 }
 
 // Config is the main config for the tapd cli command.
@@ -251,22 +384,53 @@ type Config struct {
 	Profile    string `long:"profile" description:"Enable HTTP profiling on either a port or host:port"`
 
 	BatchMintingInterval time.Duration `long:"batch-minting-interval" description:"A duration (1m, 2h, etc) that governs how frequently pending assets are gather into a batch to be minted."`
+	BatchMaxSeedlings    int           `long:"batch-max-seedlings" description:"If set, the pending batch is finalized as soon as it holds this many seedlings, without waiting for the minting interval."`
+	BatchMaxBlocks       uint32        `long:"batch-max-blocks" description:"If set, the pending batch is finalized once this many blocks were mined since its creation, without waiting for the minting interval."`
+	BatchExternalKeys    bool          `long:"batch-allow-external-keys" description:"If set, minting batches can be created with an internal key that isn't derived by the backing lnd node, like a key of a cold-storage keychain."`
+
+	BatchRetryAttempts       uint32        `long:"batch-retry-attempts" description:"The number of times a step of a minting batch that failed, like the fee estimation, funding or broadcast of its genesis transaction, is attempted. A batch that can't be funded is marked as failed, without affecting any other batches."`
+	BatchRetryInitialBackoff time.Duration `long:"batch-retry-initial-backoff" description:"The time to wait before retrying a failed step of a minting batch for the first time. The time is doubled for every subsequent retry."`
+	BatchRetryMaxBackoff     time.Duration `long:"batch-retry-max-backoff" description:"The maximum time to wait between two attempts of a failed step of a minting batch."`
+	BatchRetention           time.Duration `long:"batch-retention" description:"If set, minting batches that were cancelled or couldn't be funded are deleted once they're older than this duration (720h, etc), along with their seedlings. The seedlings of finalized batches older than this duration are deleted as well, while the minted assets are kept."`
+
+	ShutdownTimeout  time.Duration `long:"shutdowntimeout" description:"The maximum time each subsystem is given to stop within when shutting down."`
+	WatchdogInterval time.Duration `long:"watchdoginterval" description:"The interval at which subsystems are checked for stalled operations, which are reported through the gRPC health service."`
+
+	ReorgSafetyDepth uint32 `long:"reorgsafetydepth" description:"The number of blocks below the chain tip in which the anchor transactions of assets are watched for reorgs. Assets are only spent once their anchor transaction has at least this many confirmations, so their proofs never need to be re-bound after they were sent on."`
+
+	SendBatchInterval time.Duration `long:"send-batch-interval" description:"If set, sends to addresses are queued and all sends queued within this duration (1m, 2h, etc) are shipped in a single anchor transaction to save on chain fees. Sends with a max fee are always shipped immediately."`
+
+	CoinSelectStrategy string `long:"coinselectstrategy" choice:"max-amount" choice:"min-amount" choice:"single-coin" choice:"random" description:"The default strategy used to select the assets that fund a send, unless a send requests a specific one. max-amount uses the largest assets first to minimize the number of inputs, min-amount uses the smallest assets first to consolidate dust, single-coin prefers the smallest single asset that covers the full amount and random selects assets in a random order for better privacy."`
 
 	// The following options are used to configure the proof courier.
-	ProofCourierMode string                    `long:"proofcouriermode" choice:"hashmail" description:"Type of proof courier to use."`
-	HashMailCourier  *proof.HashMailCourierCfg `group:"proofcourier" namespace:"hashmailcourier"`
+	ProofCourierMode   string                       `long:"proofcouriermode" choice:"hashmail" choice:"universerpc" description:"Type of proof courier to use by default. The default proof courier address is embedded in new addresses and used for sends to addresses that don't specify a proof courier address."`
+	ProofCourierAddr   string                       `long:"proofcourieraddr" description:"The default proof courier address (for example universerpc://host:port). If not set, the hashmail courier address is used if the proof courier mode is hashmail."`
+	ProofRetryInterval time.Duration                `long:"proofretryinterval" description:"The interval at which the delivery of proofs that couldn't be delivered to their receiver yet is re-attempted."`
+	HashMailCourier    *proof.HashMailCourierCfg    `group:"proofcourier" namespace:"hashmailcourier"`
+	UniverseRpcCourier *proof.UniverseRpcCourierCfg `group:"universerpccourier" namespace:"universerpccourier"`
 
 	ChainConf *ChainConfig
 	RpcConf   *RpcConfig
 
 	Lnd *LndConfig `group:"lnd" namespace:"lnd"`
 
+	RemoteSigner *RemoteSignerConfig `group:"remotesigner" namespace:"remotesigner"`
+
 	DatabaseBackend string                `long:"databasebackend" description:"The database backend to use for storing all asset related data." choice:"sqlite" choice:"postgres"`
 	Sqlite          *tapdb.SqliteConfig   `group:"sqlite" namespace:"sqlite"`
 	Postgres        *tapdb.PostgresConfig `group:"postgres" namespace:"postgres"`
 
+	DatabaseBackupDir   string `long:"databasebackupdir" description:"The directory that online backups of the database are written to if the BackupDatabase call doesn't specify a path. Defaults to the backups directory within the network directory."`
+	DatabaseRestoreFile string `long:"databaserestorefile" description:"If set, the database is restored from this backup file on startup, before it is opened. For the sqlite backend, the replaced database file is kept next to the restored one. A backup is only restored once, later startups with the same backup file skip the restore. The backup file must match the configured database backend."`
+
 	Universe *UniverseConfig `group:"universe" namespace:"universe"`
 
+	ProofEncryption *ProofEncryptionConfig `group:"proofencryption" namespace:"proofencryption"`
+
+	VM *VMConfig `group:"vm" namespace:"vm"`
+
+	Prometheus *monitoring.PrometheusConfig `group:"prometheus" namespace:"prometheus"`
+
 	// LogWriter is the root logger that all of the daemon's subloggers are
 	// hooked up to.
 	LogWriter *build.RotatingLogWriter
@@ -312,6 +476,9 @@ func DefaultConfig() Config {
 			Host:         "localhost:10009",
 			MacaroonPath: defaultLndMacaroonPath,
 		},
+		RemoteSigner: &RemoteSignerConfig{
+			Timeout: defaultRemoteSignerTimeout,
+		},
 		DatabaseBackend: DatabaseBackendSqlite,
 		Sqlite: &tapdb.SqliteConfig{
 			DatabaseFileName: defaultSqliteDatabasePath,
@@ -319,10 +486,16 @@ func DefaultConfig() Config {
 		Postgres: &tapdb.PostgresConfig{
 			Host:               "localhost",
 			Port:               5432,
-			MaxOpenConnections: 10,
+			MaxOpenConnections: tapdb.DefaultPostgresMaxConns,
+			ConnMaxLifetime:    tapdb.DefaultPostgresConnLifetime,
+			ConnMaxIdleTime:    tapdb.DefaultPostgresConnIdleTime,
 		},
 		LogWriter:            build.NewRotatingLogWriter(),
 		BatchMintingInterval: defaultBatchMintingInterval,
+		ShutdownTimeout:      defaultShutdownTimeout,
+		WatchdogInterval:     monitoring.DefaultWatchdogInterval,
+		ReorgSafetyDepth:     tapgarden.DefaultReorgSafetyDepth,
+		CoinSelectStrategy:   defaultCoinSelectStrategy,
 		HashMailCourier: &proof.HashMailCourierCfg{
 			Addr:               defaultHashMailAddr,
 			ReceiverAckTimeout: defaultProofTransferReceiverAckTimeout,
@@ -333,10 +506,38 @@ func DefaultConfig() Config {
 				MaxBackoff:       defaultProofTransferMaxBackoff,
 			},
 		},
+		UniverseRpcCourier: &proof.UniverseRpcCourierCfg{
+			BackoffCfg: &proof.BackoffCfg{
+				BackoffResetWait: defaultProofTransferBackoffResetWait,
+				NumTries:         defaultProofTransferNumTries,
+				InitialBackoff:   defaultProofTransferInitialBackoff,
+				MaxBackoff:       defaultProofTransferMaxBackoff,
+			},
+		},
+		ProofRetryInterval: defaultProofRetryInterval,
 		Universe: &UniverseConfig{
 			SyncInterval:       defaultUniverseSyncInterval,
 			AcceptRemoteProofs: defaultAcceptRemoteProofs,
+			PublicAccess:       string(defaultUniversePublicAccess),
+			RateLimit:          rpcperms.DefaultRateLimitConfig(),
+			NodeCacheSize:      tapdb.DefaultTreeNodeCacheSize,
+			MintProofPushBackoff: &proof.BackoffCfg{
+				BackoffResetWait: defaultProofTransferBackoffResetWait,
+				NumTries:         defaultProofTransferNumTries,
+				InitialBackoff:   defaultProofTransferInitialBackoff,
+				MaxBackoff:       defaultProofTransferMaxBackoff,
+			},
+			TreeBuildConcurrency: runtime.GOMAXPROCS(0),
+		},
+		ProofEncryption: &ProofEncryptionConfig{},
+		VM: &VMConfig{
+			Limits: VMLimitsStandard,
 		},
+		Prometheus: monitoring.DefaultPrometheusConfig(),
+
+		BatchRetryAttempts:       defaultBatchRetryAttempts,
+		BatchRetryInitialBackoff: defaultBatchRetryInitialBackoff,
+		BatchRetryMaxBackoff:     defaultBatchRetryMaxBackoff,
 	}
 }
 
@@ -590,6 +791,13 @@ func ValidateConfig(cfg Config, cfgLogger btclog.Logger) (*Config, error) {
 			cfg.networkDir, defaultSqliteDatabaseFileName,
 		)
 	}
+	if cfg.DatabaseBackupDir == "" {
+		cfg.DatabaseBackupDir = filepath.Join(
+			cfg.networkDir, defaultDatabaseBackupDirname,
+		)
+	}
+	cfg.DatabaseBackupDir = CleanAndExpandPath(cfg.DatabaseBackupDir)
+	cfg.DatabaseRestoreFile = CleanAndExpandPath(cfg.DatabaseRestoreFile)
 
 	// If a custom macaroon directory wasn't specified and the data
 	// directory has changed from the default path, then we'll also update
@@ -637,6 +845,30 @@ func ValidateConfig(cfg Config, cfgLogger btclog.Logger) (*Config, error) {
 		)
 	}
 
+	// If a remote signer is used, we need to know where to find it.
+	if cfg.RemoteSigner.Enable {
+		switch {
+		case cfg.RemoteSigner.Host == "":
+			return nil, fmt.Errorf("must specify " +
+				"--remotesigner.host")
+
+		case cfg.RemoteSigner.MacaroonPath == "":
+			return nil, fmt.Errorf("must specify " +
+				"--remotesigner.macaroonpath")
+
+		case cfg.RemoteSigner.Timeout <= 0:
+			return nil, fmt.Errorf("--remotesigner.timeout must " +
+				"be positive")
+		}
+
+		cfg.RemoteSigner.MacaroonPath = lncfg.CleanAndExpandPath(
+			cfg.RemoteSigner.MacaroonPath,
+		)
+		cfg.RemoteSigner.TLSPath = lncfg.CleanAndExpandPath(
+			cfg.RemoteSigner.TLSPath,
+		)
+	}
+
 	// Create the tapd directory and all other sub-directories if they
 	// don't already exist. This makes sure that directory trees are also
 	// created for files that point to outside the tapddir.
@@ -644,7 +876,7 @@ func ValidateConfig(cfg Config, cfgLogger btclog.Logger) (*Config, error) {
 		tapdDir, cfg.DataDir, cfg.networkDir,
 		filepath.Dir(cfg.RpcConf.TLSCertPath),
 		filepath.Dir(cfg.RpcConf.TLSKeyPath),
-		filepath.Dir(cfg.RpcConf.MacaroonPath),
+		filepath.Dir(cfg.RpcConf.MacaroonPath), cfg.DatabaseBackupDir,
 	}
 	for _, dir := range dirs {
 		if err := makeDirectory(dir); err != nil {
@@ -742,6 +974,59 @@ func ValidateConfig(cfg Config, cfgLogger btclog.Logger) (*Config, error) {
 		}
 	}
 
+	// The metrics exporter needs an address to listen on if it's active.
+	if cfg.Prometheus.Active && cfg.Prometheus.ListenAddr == "" {
+		return nil, mkErr("prometheus.listenaddr must be set if the " +
+			"Prometheus exporter is active")
+	}
+
+	if cfg.BatchMaxSeedlings < 0 {
+		return nil, mkErr("batch-max-seedlings must not be negative")
+	}
+
+	if cfg.Universe.MintProofPushServer != "" &&
+		cfg.Universe.MintProofPushBackoff.NumTries <= 0 {
+
+		return nil, mkErr("universe.mintproofpush.numtries must be " +
+			"positive")
+	}
+
+	if cfg.Universe.TreeBuildConcurrency < 1 {
+		return nil, mkErr("universe.treebuildconcurrency must be " +
+			"positive")
+	}
+
+	rateLimit := cfg.Universe.RateLimit
+	if rateLimit.RequestsPerSecond < 0 || rateLimit.Burst < 0 {
+		return nil, mkErr("universe.ratelimit.requestspersecond and " +
+			"universe.ratelimit.burst must not be negative")
+	}
+	if rateLimit.Quota > 0 && rateLimit.QuotaPeriod <= 0 {
+		return nil, mkErr("universe.ratelimit.quotaperiod must be " +
+			"positive if a quota is set")
+	}
+
+	if !cfg.ProofEncryption.Enable &&
+		(cfg.ProofEncryption.Passphrase != "" ||
+			cfg.ProofEncryption.Migrate) {
+
+		return nil, mkErr("proofencryption.enable must be set to use " +
+			"a passphrase or migrate proof files")
+	}
+
+	if cfg.ShutdownTimeout <= 0 {
+		return nil, mkErr("shutdowntimeout must be positive")
+	}
+	if cfg.WatchdogInterval <= 0 {
+		return nil, mkErr("watchdoginterval must be positive")
+	}
+	if cfg.BatchRetention < 0 {
+		return nil, mkErr("batch-retention must not be negative")
+	}
+	if cfg.ReorgSafetyDepth == 0 {
+		return nil, mkErr("reorgsafetydepth must be positive")
+	}
+
 	// All good, return the sanitized result.
 	return &cfg, nil
 }
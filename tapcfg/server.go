@@ -1,19 +1,32 @@
 package tapcfg
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
 
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
 	"github.com/btcsuite/btclog"
 	"github.com/lightninglabs/lndclient"
 	tap "github.com/lightninglabs/taproot-assets"
 	"github.com/lightninglabs/taproot-assets/address"
+	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightninglabs/taproot-assets/monitoring"
+	"github.com/lightninglabs/taproot-assets/mssmt"
+	"github.com/lightninglabs/taproot-assets/perms"
 	"github.com/lightninglabs/taproot-assets/proof"
 	"github.com/lightninglabs/taproot-assets/tapdb"
 	"github.com/lightninglabs/taproot-assets/tapdb/sqlc"
 	"github.com/lightninglabs/taproot-assets/tapfreighter"
 	"github.com/lightninglabs/taproot-assets/tapgarden"
+	"github.com/lightninglabs/taproot-assets/tapscript"
 	"github.com/lightninglabs/taproot-assets/universe"
 	"github.com/lightningnetwork/lnd"
 	"github.com/lightningnetwork/lnd/signal"
@@ -24,7 +37,10 @@ import (
 // database backends implement.
 type databaseBackend interface {
 	tapdb.BatchedQuerier
+	tapdb.Backupper
+	tapdb.StatsReporter
 	WithTx(tx *sql.Tx) *sqlc.Queries
+	Close() error
 }
 
 // genServerConfig generates a server config from the given tapd config.
@@ -37,6 +53,14 @@ func genServerConfig(cfg *Config, cfgLogger btclog.Logger,
 
 	var err error
 
+	// If the operator asked for it, we restore the database from a backup
+	// before it is opened.
+	if cfg.DatabaseRestoreFile != "" {
+		if err := restoreDatabase(cfg, cfgLogger); err != nil {
+			return nil, err
+		}
+	}
+
 	// Now that we know where the database will live, we'll go ahead and
 	// open up the default implementation of it.
 	var db databaseBackend
@@ -122,15 +146,63 @@ func genServerConfig(cfg *Config, cfgLogger btclog.Logger,
 	headerVerifier := tapgarden.GenHeaderVerifier(
 		context.Background(), chainBridge,
 	)
+
+	// Proofs inserted into the universe usually come from remote parties,
+	// so their block headers can optionally be checked against the best
+	// chain of our chain backend, detecting headers of reorged blocks.
+	if cfg.Universe.StrictHeaderVerification {
+		headerVerifier = tapgarden.GenChainHeaderVerifier(
+			context.Background(), chainBridge,
+			&cfg.ActiveNetParams,
+		)
+	}
+
+	// All universe trees share a single node cache, so frequently used
+	// nodes can be served without hitting the database. Without a cache,
+	// all nodes are read from the database.
+	var uniNodeCache *tapdb.TreeNodeCache
+	if cfg.Universe.NodeCacheSize > 0 {
+		uniNodeCache = tapdb.NewTreeNodeCache(
+			cfg.Universe.NodeCacheSize,
+		)
+	}
+
+	// Transfer proofs are only indexed by the configured universes, all
+	// others only accept issuance proofs.
+	transferIDs, err := parseUniverseIDs(
+		cfg.Universe.TransferAssetIDs, cfg.Universe.TransferGroupKeys,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid universe transfer IDs: %w", err)
+	}
+
+	uniTreeOpts := []mssmt.TreeOption{
+		mssmt.WithBuildConcurrency(cfg.Universe.TreeBuildConcurrency),
+	}
+
+	if cfg.Universe.RebuildTrees {
+		err := rebuildUniverseTrees(
+			cfgLogger, uniForest, uniDB, uniNodeCache,
+			uniTreeOpts...,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to rebuild universe "+
+				"trees: %w", err)
+		}
+	}
+
 	uniCfg := universe.MintingArchiveConfig{
 		NewBaseTree: func(id universe.Identifier) universe.BaseBackend {
 			return tapdb.NewBaseUniverseTree(
-				uniDB, id,
+				uniDB, id, uniNodeCache, uniTreeOpts...,
 			)
 		},
 		HeaderVerifier: headerVerifier,
 		UniverseForest: uniForest,
 		UniverseStats:  universeStats,
+		TransferPolicy: universe.NewTransferPolicy(
+			cfg.Universe.IndexTransfers, transferIDs...,
+		),
 	}
 
 	federationStore := tapdb.NewTransactionExecutor(db,
@@ -140,33 +212,55 @@ func genServerConfig(cfg *Config, cfgLogger btclog.Logger,
 	)
 	federationDB := tapdb.NewUniverseFederationDB(federationStore)
 
-	proofFileStore, err := proof.NewFileArchiver(cfg.networkDir)
+	var fileArchiverOpts []proof.FileArchiverOption
+	if cfg.ProofEncryption.Enable {
+		encrypter, err := proofEncrypter(cfg, keyRing)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create proof "+
+				"encrypter: %v", err)
+		}
+
+		fileArchiverOpts = append(
+			fileArchiverOpts, proof.WithEncrypter(encrypter),
+		)
+	}
+
+	proofFileStore, err := proof.NewFileArchiver(
+		cfg.networkDir, fileArchiverOpts...,
+	)
 	if err != nil {
 		return nil, fmt.Errorf("unable to open disk archive: %v", err)
 	}
+
+	if cfg.ProofEncryption.Migrate {
+		cfgLogger.Infof("Encrypting plain text proof files")
+
+		numEncrypted, err := proofFileStore.EncryptProofs()
+		if err != nil {
+			return nil, fmt.Errorf("unable to encrypt proof "+
+				"files: %v", err)
+		}
+
+		cfgLogger.Infof("Encrypted %d proof files", numEncrypted)
+	}
 	proofArchive := proof.NewMultiArchiver(
 		&proof.BaseVerifier{}, tapdb.DefaultStoreTimeout,
 		assetStore, proofFileStore,
 	)
 
-	var hashMailCourier proof.Courier[proof.Recipient]
-	if cfg.HashMailCourier != nil {
-		hashMailBox, err := proof.NewHashMailBox(
-			cfg.HashMailCourier.Addr,
-			cfg.HashMailCourier.TlsCertPath,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("unable to make mailbox: %v",
-				err)
-		}
+	proofCourierDispatcher := proof.NewURLDispatch(&proof.CourierCfg{
+		HashMailCfg:    cfg.HashMailCourier,
+		UniverseRpcCfg: cfg.UniverseRpcCourier,
+		DeliveryLog:    assetStore,
+	})
+	defaultProofCourierAddr, err := defaultCourierAddr(cfg)
+	if err != nil {
+		return nil, err
+	}
 
-		hashMailCourier, err = proof.NewHashMailCourier(
-			cfg.HashMailCourier, hashMailBox, assetStore,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("unable to make hashmail "+
-				"courier: %v", err)
-		}
+	var proofRetryTicker ticker.Ticker
+	if cfg.ProofRetryInterval > 0 {
+		proofRetryTicker = ticker.New(cfg.ProofRetryInterval)
 	}
 
 	baseUni := universe.NewMintingArchive(uniCfg)
@@ -175,8 +269,19 @@ func genServerConfig(cfg *Config, cfgLogger btclog.Logger,
 		LocalDiffEngine:     baseUni,
 		NewRemoteDiffEngine: tap.NewRpcUniverseDiff,
 		LocalRegistrar:      baseUni,
+		ProvenanceLog:       federationDB,
+		UniverseStats:       universeStats,
 	})
 
+	// If no asset IDs or group keys are configured, then all the universes
+	// known to a federation server are synced.
+	syncIDs, err := parseUniverseIDs(
+		cfg.Universe.SyncAssetIDs, cfg.Universe.SyncGroupKeys,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid universe sync IDs: %w", err)
+	}
+
 	federationMembers := cfg.Universe.FederationServers
 	switch cfg.ChainConf.Network {
 	case "testnet":
@@ -194,44 +299,142 @@ func genServerConfig(cfg *Config, cfgLogger btclog.Logger,
 			UniverseSyncer:          universeSyncer,
 			LocalRegistrar:          baseUni,
 			SyncInterval:            cfg.Universe.SyncInterval,
+			SyncIDs:                 syncIDs,
 			NewRemoteRegistrar:      tap.NewRpcUniverseRegistar,
 			StaticFederationMembers: federationMembers,
 			ErrChan:                 mainErrChan,
 		},
 	)
 
-	virtualTxSigner := tap.NewLndRpcVirtualTxSigner(lndServices)
-	coinSelect := tapfreighter.NewCoinSelect(assetStore)
+	var proofPush *tapgarden.ProofPushConfig
+	if cfg.Universe.MintProofPushServer != "" {
+		cfgLogger.Infof("Pushing genesis proofs of minted batches to "+
+			"Universe server %v", cfg.Universe.MintProofPushServer)
+
+		pushRegistrar, err := tap.NewRpcUniverseRegistar(
+			universe.NewServerAddrFromStr(
+				cfg.Universe.MintProofPushServer,
+			),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to connect to proof "+
+				"push server: %w", err)
+		}
+
+		proofPush = &tapgarden.ProofPushConfig{
+			Registrar:  pushRegistrar,
+			BackoffCfg: cfg.Universe.MintProofPushBackoff,
+		}
+	}
+
+	coinSelectStrategy, err := tapfreighter.ParseSelectStrategy(
+		cfg.CoinSelectStrategy,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		virtualTxSigner tapscript.Signer = tap.NewLndRpcVirtualTxSigner(
+			lndServices,
+		)
+		genSigner asset.GenesisSigner = tap.NewLndRpcGenSigner(
+			lndServices,
+		)
+		remoteSignerConn io.Closer
+	)
+	if cfg.RemoteSigner.Enable {
+		cfgLogger.Infof("Using remote signer at %v for signing "+
+			"virtual transactions and group keys",
+			cfg.RemoteSigner.Host)
+
+		signerConn, err := lndclient.NewBasicConn(
+			cfg.RemoteSigner.Host, cfg.RemoteSigner.TLSPath,
+			filepath.Dir(cfg.RemoteSigner.MacaroonPath),
+			cfg.ChainConf.Network, lndclient.MacFilename(
+				filepath.Base(cfg.RemoteSigner.MacaroonPath),
+			),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to connect to remote "+
+				"signer: %w", err)
+		}
+
+		virtualTxSigner = tap.NewRpcRemoteVirtualTxSigner(
+			signerConn, cfg.RemoteSigner.Timeout,
+		)
+		genSigner = asset.NewExternalGenesisSigner(
+			tap.NewRpcRemoteGroupSigner(
+				signerConn, cfg.RemoteSigner.Timeout,
+			),
+		)
+		remoteSignerConn = signerConn
+	}
+
+	coinSelect := tapfreighter.NewCoinSelect(assetStore, coinSelectStrategy)
+
+	// Batched shipping of sends is only enabled if an interval is set.
+	var sendBatchTicker *ticker.Force
+	if cfg.SendBatchInterval > 0 {
+		sendBatchTicker = ticker.NewForce(cfg.SendBatchInterval)
+	}
+
+	// Outgoing transfers are validated with the configured limits before
+	// they are signed and broadcast, while proofs are always verified with
+	// the consensus limits.
+	sendLimits := cfg.VM.VMLimits()
+	sendValidator := &tap.ValidatorV0{
+		Limits: &sendLimits,
+	}
+
 	assetWallet := tapfreighter.NewAssetWallet(&tapfreighter.WalletConfig{
-		CoinSelector: coinSelect,
-		AssetProofs:  proofArchive,
-		AddrBook:     tapdbAddrBook,
-		KeyRing:      keyRing,
-		Signer:       virtualTxSigner,
-		TxValidator:  &tap.ValidatorV0{},
-		Wallet:       walletAnchor,
-		ChainParams:  &tapChainParams,
+		CoinSelector:  coinSelect,
+		AssetProofs:   proofArchive,
+		AddrBook:      tapdbAddrBook,
+		KeyRing:       keyRing,
+		Signer:        virtualTxSigner,
+		TxValidator:   sendValidator,
+		Wallet:        walletAnchor,
+		ChainParams:   &tapChainParams,
+		ChainBridge:   chainBridge,
+		MinInputConfs: cfg.ReorgSafetyDepth,
 	})
 
+	metricsExporter := monitoring.NewPrometheusExporter(cfg.Prometheus)
+	healthReporter := monitoring.NewHealthReporter(
+		monitoring.DefaultWatchdog(), cfg.WatchdogInterval,
+	)
+
 	return &tap.Config{
 		DebugLevel:                 cfg.DebugLevel,
 		AcceptRemoteUniverseProofs: cfg.Universe.AcceptRemoteProofs,
 		Lnd:                        lndServices,
 		ChainParams:                cfg.ActiveNetParams,
+		DefaultProofCourierAddr:    defaultProofCourierAddr,
 		AssetMinter: tapgarden.NewChainPlanter(tapgarden.PlanterConfig{
 			GardenKit: tapgarden.GardenKit{
 				Wallet:      walletAnchor,
 				ChainBridge: chainBridge,
 				Log:         assetMintingStore,
 				KeyRing:     keyRing,
-				GenSigner: tap.NewLndRpcGenSigner(
-					lndServices,
-				),
-				ProofFiles: proofFileStore,
-				Universe:   universeFederation,
+				GenSigner:   genSigner,
+				ProofFiles:  proofFileStore,
+				Universe:    universeFederation,
+				ProofPush:   proofPush,
+				RetryPolicy: tapgarden.RetryPolicy{
+					MaxAttempts: cfg.BatchRetryAttempts,
+					InitialBackoff: cfg.
+						BatchRetryInitialBackoff,
+					MaxBackoff: cfg.BatchRetryMaxBackoff,
+				},
 			},
 			BatchTicker: ticker.NewForce(cfg.BatchMintingInterval),
-			ErrChan:     mainErrChan,
+			AutoFinalize: tapgarden.AutoFinalizeConfig{
+				MaxSeedlings:   cfg.BatchMaxSeedlings,
+				MaxBatchBlocks: cfg.BatchMaxBlocks,
+			},
+			AllowExternalBatchKeys: cfg.BatchExternalKeys,
+			ErrChan:                mainErrChan,
 		}),
 		AssetCustodian: tapgarden.NewCustodian(
 			&tapgarden.CustodianConfig{
@@ -242,7 +445,30 @@ func genServerConfig(cfg *Config, cfgLogger btclog.Logger,
 				ProofArchive:  proofArchive,
 				ProofNotifier: assetStore,
 				ErrChan:       mainErrChan,
-				ProofCourier:  hashMailCourier,
+
+				ProofCourierDispatcher:  proofCourierDispatcher,
+				DefaultProofCourierAddr: defaultProofCourierAddr,
+			},
+		),
+		ReorgWatcher: tapgarden.NewReorgWatcher(
+			&tapgarden.ReorgWatcherConfig{
+				ChainBridge:   chainBridge,
+				AnchorTxStore: assetStore,
+				ProofFiles:    proofFileStore,
+				HeaderVerifier: tapgarden.GenHeaderVerifier(
+					context.Background(), chainBridge,
+				),
+				SafetyDepth: cfg.ReorgSafetyDepth,
+				ErrChan:     mainErrChan,
+			},
+		),
+		BatchJanitor: tapgarden.NewBatchJanitor(
+			&tapgarden.BatchJanitorConfig{
+				Store:     assetMintingStore,
+				Retention: cfg.BatchRetention,
+				PruneTicker: ticker.New(
+					tapgarden.DefaultBatchPruneInterval,
+				),
 			},
 		),
 		ChainBridge:  chainBridge,
@@ -253,21 +479,29 @@ func genServerConfig(cfg *Config, cfgLogger btclog.Logger,
 			&tapfreighter.ChainPorterConfig{
 				CoinSelector: coinSelect,
 				Signer:       virtualTxSigner,
-				TxValidator:  &tap.ValidatorV0{},
+				TxValidator:  sendValidator,
 				ExportLog:    assetStore,
 				ChainBridge:  chainBridge,
 				Wallet:       walletAnchor,
 				KeyRing:      keyRing,
 				AssetWallet:  assetWallet,
 				AssetProofs:  proofFileStore,
-				ProofCourier: hashMailCourier,
+				BatchTicker:  sendBatchTicker,
 				ErrChan:      mainErrChan,
+
+				ProofCourierDispatcher:  proofCourierDispatcher,
+				DefaultProofCourierAddr: defaultProofCourierAddr,
+				ProofRetryTicker:        proofRetryTicker,
 			},
 		),
 		BaseUniverse:       baseUni,
 		UniverseSyncer:     universeSyncer,
 		UniverseFederation: universeFederation,
 		UniverseStats:      universeStats,
+		MetricsExporter:    metricsExporter,
+		HealthReporter:     healthReporter,
+		ShutdownTimeout:    cfg.ShutdownTimeout,
+		RemoteSignerConn:   remoteSignerConn,
 		LogWriter:          cfg.LogWriter,
 		DatabaseConfig: &tap.DatabaseConfig{
 			RootKeyStore:   tapdb.NewRootKeyStore(rksDB),
@@ -276,10 +510,201 @@ func genServerConfig(cfg *Config, cfgLogger btclog.Logger,
 			TapAddrBook:    tapdbAddrBook,
 			UniverseForest: uniForest,
 			FederationDB:   federationDB,
+			Backupper:      db,
+			BackupDir:      cfg.DatabaseBackupDir,
+			StatsReporter:  db,
+			Closer:         db,
 		},
 	}, nil
 }
 
+// backupHash returns the hex encoded SHA-256 hash of the backup file at the
+// given path.
+func backupHash(backupPath string) (string, error) {
+	backup, err := os.Open(backupPath)
+	if err != nil {
+		return "", fmt.Errorf("unable to open backup: %w", err)
+	}
+	defer backup.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, backup); err != nil {
+		return "", fmt.Errorf("unable to read backup: %w", err)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// restoreDatabase restores the database of the configured backend from the
+// configured backup file. The hash of a restored backup is recorded in the
+// network directory, so the same backup isn't restored again over a database
+// that was already used after the restore, if the option is left in place.
+func restoreDatabase(cfg *Config, cfgLogger btclog.Logger) error {
+	restoreHash, err := backupHash(cfg.DatabaseRestoreFile)
+	if err != nil {
+		return err
+	}
+
+	markerPath := filepath.Join(
+		cfg.networkDir, defaultRestoredBackupFileName,
+	)
+	restoredHash, err := os.ReadFile(markerPath)
+	switch {
+	case err == nil &&
+		string(bytes.TrimSpace(restoredHash)) == restoreHash:
+
+		cfgLogger.Warnf("Database backup %v was already restored, "+
+			"skipping restore, the databaserestorefile option "+
+			"should be removed", cfg.DatabaseRestoreFile)
+
+		return nil
+
+	case err != nil && !os.IsNotExist(err):
+		return fmt.Errorf("unable to read restore marker: %w", err)
+	}
+
+	cfgLogger.Infof("Restoring %v database from backup: %v",
+		cfg.DatabaseBackend, cfg.DatabaseRestoreFile)
+
+	switch cfg.DatabaseBackend {
+	case DatabaseBackendSqlite:
+		err = tapdb.RestoreSqliteBackup(
+			cfg.Sqlite, cfg.DatabaseRestoreFile,
+		)
+
+	case DatabaseBackendPostgres:
+		err = tapdb.RestorePostgresBackup(
+			context.Background(), cfg.Postgres,
+			cfg.DatabaseRestoreFile,
+		)
+
+	default:
+		return fmt.Errorf("unknown database backend: %s",
+			cfg.DatabaseBackend)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to restore database: %w", err)
+	}
+
+	err = os.MkdirAll(cfg.networkDir, 0700)
+	if err != nil {
+		return err
+	}
+	err = os.WriteFile(markerPath, []byte(restoreHash+"\n"), 0600)
+	if err != nil {
+		return fmt.Errorf("unable to write restore marker: %w", err)
+	}
+
+	cfgLogger.Infof("Database restored, the databaserestorefile option " +
+		"should now be removed")
+
+	return nil
+}
+
+// defaultCourierAddr returns the default proof courier address that is used
+// for addresses that don't specify a proof courier address themselves. If no
+// default proof courier is configured, nil is returned.
+func defaultCourierAddr(cfg *Config) (*url.URL, error) {
+	var addr string
+	switch {
+	case cfg.ProofCourierAddr != "":
+		addr = cfg.ProofCourierAddr
+
+	case cfg.ProofCourierMode == proof.UniverseRpcCourierType:
+		return nil, fmt.Errorf("proof courier mode %v requires a "+
+			"proof courier address", cfg.ProofCourierMode)
+
+	case cfg.HashMailCourier != nil:
+		addr = fmt.Sprintf("%s://%s", proof.HashmailCourierType,
+			cfg.HashMailCourier.Addr)
+
+	default:
+		return nil, nil
+	}
+
+	courierAddr, err := proof.ParseCourierAddrString(addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid default proof courier "+
+			"address: %w", err)
+	}
+
+	return courierAddr, nil
+}
+
+// parseUniverseIDs parses the given hex encoded asset IDs and x-only group
+// keys into universe identifiers. If neither are given, nil is returned.
+func parseUniverseIDs(assetIDs,
+	groupKeys []string) ([]universe.Identifier, error) {
+
+	var ids []universe.Identifier
+	for _, assetIDStr := range assetIDs {
+		assetIDBytes, err := hex.DecodeString(assetIDStr)
+		if err != nil || len(assetIDBytes) != len(asset.ID{}) {
+			return nil, fmt.Errorf("invalid asset ID: %v",
+				assetIDStr)
+		}
+
+		var id universe.Identifier
+		copy(id.AssetID[:], assetIDBytes)
+		ids = append(ids, id)
+	}
+
+	for _, groupKeyStr := range groupKeys {
+		groupKeyBytes, err := hex.DecodeString(groupKeyStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid group key: %v",
+				groupKeyStr)
+		}
+
+		groupKey, err := schnorr.ParsePubKey(groupKeyBytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid group key %v: %w",
+				groupKeyStr, err)
+		}
+
+		ids = append(ids, universe.Identifier{
+			GroupKey: groupKey,
+		})
+	}
+
+	return ids, nil
+}
+
+// rebuildUniverseTrees rebuilds the MS-SMTs of all known universes from their
+// stored leaves.
+func rebuildUniverseTrees(cfgLogger btclog.Logger,
+	uniForest *tapdb.BaseUniverseForest, uniDB tapdb.BatchedUniverseTree,
+	nodeCache *tapdb.TreeNodeCache, treeOpts ...mssmt.TreeOption) error {
+
+	ctx := context.Background()
+
+	roots, err := uniForest.RootNodes(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to fetch universe roots: %w", err)
+	}
+
+	cfgLogger.Infof("Rebuilding %d universe trees", len(roots))
+
+	for _, root := range roots {
+		uniTree := tapdb.NewBaseUniverseTree(
+			uniDB, root.ID, nodeCache, treeOpts...,
+		)
+		newRoot, err := uniTree.RebuildTree(ctx)
+		if err != nil {
+			return fmt.Errorf("unable to rebuild universe %v: %w",
+				root.ID.String(), err)
+		}
+
+		if !mssmt.IsEqualNode(root.Node, newRoot) {
+			cfgLogger.Warnf("Root of universe %v changed from %v "+
+				"to %v after rebuild", root.ID.String(),
+				root.Node.NodeHash(), newRoot.NodeHash())
+		}
+	}
+
+	return nil
+}
+
 // CreateServerFromConfig creates a new Taproot Asset server from the given CLI
 // config.
 func CreateServerFromConfig(cfg *Config, cfgLogger btclog.Logger,
@@ -334,6 +759,11 @@ func CreateServerFromConfig(cfg *Config, cfgLogger btclog.Logger,
 		LetsEncryptListen: cfg.RpcConf.LetsEncryptListen,
 		LetsEncryptEmail:  cfg.RpcConf.LetsEncryptEmail,
 		LetsEncryptDomain: cfg.RpcConf.LetsEncryptDomain,
+
+		UniversePublicAccess: perms.UniversePublicAccess(
+			cfg.Universe.PublicAccess,
+		),
+		UniverseRateLimit: cfg.Universe.RateLimit,
 	}
 
 	return tap.NewServer(serverCfg), nil
@@ -360,3 +790,22 @@ func CreateSubServerFromConfig(cfg *Config, cfgLogger btclog.Logger,
 
 	return tap.NewServer(serverCfg), nil
 }
+
+// proofEncrypter creates the encrypter for the proof files stored on disk,
+// with a key that is either derived from the configured passphrase or from the
+// seed of the backing lnd node.
+func proofEncrypter(cfg *Config,
+	keyRing proof.KeyDeriver) (*proof.Encrypter, error) {
+
+	if cfg.ProofEncryption.Passphrase != "" {
+		saltPath := filepath.Join(
+			cfg.networkDir, defaultProofEncryptionSaltFileName,
+		)
+
+		return proof.NewPassphraseEncrypter(
+			[]byte(cfg.ProofEncryption.Passphrase), saltPath,
+		)
+	}
+
+	return proof.NewKeyRingEncrypter(context.Background(), keyRing)
+}
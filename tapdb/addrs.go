@@ -7,12 +7,14 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"net/url"
 	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcec/v2/schnorr"
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/lightninglabs/lndclient"
 	"github.com/lightninglabs/taproot-assets/address"
@@ -49,6 +51,18 @@ type (
 	// AddrEvent is a type alias for fetching an address event row.
 	AddrEvent = sqlc.FetchAddrEventRow
 
+	// AddrEventStatusChange is a type alias for recording the time at
+	// which an address event first reached a status.
+	AddrEventStatusChange = sqlc.InsertAddrEventStatusChangeParams
+
+	// AddrEventStatusChangeRow is a type alias for fetching the status
+	// changes of an address event.
+	AddrEventStatusChangeRow = sqlc.FetchAddrEventStatusChangesRow
+
+	// DeadAddrQuery is a type alias for a query into the set of addresses
+	// that can no longer receive any transfers.
+	DeadAddrQuery = sqlc.QueryDeadAddrsParams
+
 	// AddrEventQuery is a type alias for a query into the set of known
 	// address events.
 	AddrEventQuery = sqlc.QueryEventIDsParams
@@ -62,6 +76,10 @@ type (
 
 	// ScriptKey is a type alias for fetching the script key information.
 	ScriptKey = sqlc.FetchScriptKeyByTweakedKeyRow
+
+	// ScriptKeyLeaf is a type alias for fetching the tapscript leaves of a
+	// script key.
+	ScriptKeyLeaf = sqlc.FetchScriptKeyLeavesRow
 )
 
 // AddrBook is an interface that represents the storage backed needed to create
@@ -118,11 +136,39 @@ type AddrBook interface {
 	// key.
 	FetchAddrEvent(ctx context.Context, id int32) (AddrEvent, error)
 
+	// InsertAddrEventStatusChange records the time at which an address
+	// event reached a status, unless that status was already recorded.
+	InsertAddrEventStatusChange(ctx context.Context,
+		arg AddrEventStatusChange) error
+
+	// FetchAddrEventStatusChanges returns all recorded status changes of
+	// an address event, ordered by status.
+	FetchAddrEventStatusChanges(ctx context.Context,
+		eventID int32) ([]AddrEventStatusChangeRow, error)
+
 	// QueryEventIDs returns a list of event IDs and their corresponding
 	// address IDs that match the given query parameters.
 	QueryEventIDs(ctx context.Context, query AddrEventQuery) ([]AddrEventID,
 		error)
 
+	// QueryDeadAddrs returns the Taproot output keys of all addresses
+	// that can no longer receive any transfers and have no pending
+	// events.
+	QueryDeadAddrs(ctx context.Context, arg DeadAddrQuery) ([][]byte,
+		error)
+
+	// DeleteAddrEventStatusChanges deletes the status changes of all
+	// events of the address with the given Taproot output key.
+	DeleteAddrEventStatusChanges(ctx context.Context,
+		taprootOutputKey []byte) error
+
+	// DeleteAddrEvents deletes all events of the address with the given
+	// Taproot output key.
+	DeleteAddrEvents(ctx context.Context, taprootOutputKey []byte) error
+
+	// DeleteAddr deletes the address with the given Taproot output key.
+	DeleteAddr(ctx context.Context, taprootOutputKey []byte) error
+
 	// FetchAssetProof fetches the asset proof for a given asset identified
 	// by its script key.
 	FetchAssetProof(ctx context.Context, scriptKey []byte) (AssetProofI,
@@ -137,6 +183,11 @@ type AddrBook interface {
 	// corresponding internal key from the database.
 	FetchScriptKeyByTweakedKey(ctx context.Context,
 		tweakedScriptKey []byte) (ScriptKey, error)
+
+	// FetchScriptKeyLeaves fetches the tapscript leaves of a script key in
+	// the order they were assembled into the tapscript tree.
+	FetchScriptKeyLeaves(ctx context.Context,
+		tweakedScriptKey []byte) ([]ScriptKeyLeaf, error)
 }
 
 // AddrBookTxOptions defines the set of db txn options the AddrBook
@@ -270,6 +321,13 @@ func (t *TapAddressBook) InsertAddrs(ctx context.Context,
 				Amount:       int64(addr.Amount),
 				AssetType:    int16(assetGen.AssetType),
 				CreationTime: addr.CreationTime.UTC(),
+				ProofCourierAddr: encodeCourierAddr(
+					addr.ProofCourierAddr,
+				),
+				Static:          addr.Static,
+				AnyAssetInGroup: addr.AnyAssetInGroup,
+				Expiry:          encodeAddrExpiry(addr.Tap),
+				SingleUse:       addr.SingleUse,
 			})
 			if err != nil {
 				return fmt.Errorf("unable to insert addr: %w",
@@ -306,14 +364,19 @@ func (t *TapAddressBook) QueryAddrs(ctx context.Context,
 
 	readOpts := NewAddrBookReadTx()
 	err := t.db.ExecTx(ctx, &readOpts, func(db AddrBook) error {
+		addrs = nil
+
 		// First, fetch the set of addresses based on the set of query
 		// parameters.
 		dbAddrs, err := db.FetchAddrs(ctx, AddrQuery{
-			CreatedAfter:  params.CreatedAfter.UTC(),
-			CreatedBefore: params.CreatedBefore.UTC(),
-			NumOffset:     int32(params.Offset),
-			NumLimit:      limit,
-			UnmanagedOnly: params.UnmanagedOnly,
+			CreatedAfter:   params.CreatedAfter.UTC(),
+			CreatedBefore:  params.CreatedBefore.UTC(),
+			NumOffset:      int32(params.Offset),
+			NumLimit:       limit,
+			UnmanagedOnly:  params.UnmanagedOnly,
+			StaticOnly:     params.StaticOnly,
+			AmountlessOnly: params.AmountlessOnly,
+			GroupOnly:      params.GroupOnly,
 		})
 		if err != nil {
 			return err
@@ -413,15 +476,27 @@ func (t *TapAddressBook) QueryAddrs(ctx context.Context,
 					"sibling: %w", err)
 			}
 
+			courierAddr, err := decodeCourierAddr(
+				addr.ProofCourierAddr,
+			)
+			if err != nil {
+				return err
+			}
+
 			tapAddr, err := address.New(
 				assetGenesis, groupKey, groupSig, *scriptKey,
 				*internalKey, uint64(addr.Amount),
 				tapscriptSibling, t.params,
+				address.WithProofCourierAddr(courierAddr),
 			)
 			if err != nil {
 				return fmt.Errorf("unable to make addr: %w", err)
 			}
 			tapAddr.Version = asset.Version(addr.Version)
+			tapAddr.Static = addr.Static
+			tapAddr.AnyAssetInGroup = addr.AnyAssetInGroup
+			tapAddr.Expiry = decodeAddrExpiry(addr.Expiry)
+			tapAddr.SingleUse = addr.SingleUse
 
 			addrs = append(addrs, address.AddrWithKeyInfo{
 				Tap: tapAddr,
@@ -550,14 +625,24 @@ func fetchAddr(ctx context.Context, db AddrBook, params *address.ChainParams,
 			err)
 	}
 
+	courierAddr, err := decodeCourierAddr(dbAddr.ProofCourierAddr)
+	if err != nil {
+		return nil, err
+	}
+
 	tapAddr, err := address.New(
 		genesis, groupKey, groupSig, *scriptKey, *internalKey,
 		uint64(dbAddr.Amount), tapscriptSibling, params,
+		address.WithProofCourierAddr(courierAddr),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("unable to make addr: %w", err)
 	}
 	tapAddr.Version = asset.Version(dbAddr.Version)
+	tapAddr.Static = dbAddr.Static
+	tapAddr.AnyAssetInGroup = dbAddr.AnyAssetInGroup
+	tapAddr.Expiry = decodeAddrExpiry(dbAddr.Expiry)
+	tapAddr.SingleUse = dbAddr.SingleUse
 
 	return &address.AddrWithKeyInfo{
 		Tap: tapAddr,
@@ -571,6 +656,74 @@ func fetchAddr(ctx context.Context, db AddrBook, params *address.ChainParams,
 	}, nil
 }
 
+// PruneAddrs deletes all addresses that can no longer receive any transfers
+// at the given time, because they either expired or are single-use and
+// already received a transfer. Addresses with pending events are kept until
+// all their receives completed. The events of the pruned addresses are deleted
+// as well. The pruned addresses are returned.
+func (t *TapAddressBook) PruneAddrs(ctx context.Context,
+	now time.Time) ([]address.AddrWithKeyInfo, error) {
+
+	var (
+		prunedAddrs []address.AddrWithKeyInfo
+		writeTxOpts AddrBookTxOptions
+	)
+	err := t.db.ExecTx(ctx, &writeTxOpts, func(db AddrBook) error {
+		prunedAddrs = nil
+
+		deadKeys, err := db.QueryDeadAddrs(ctx, DeadAddrQuery{
+			Now: sql.NullTime{
+				Time:  now.UTC(),
+				Valid: true,
+			},
+			CompletedStatus: int16(address.StatusCompleted),
+		})
+		if err != nil {
+			return fmt.Errorf("unable to query dead addrs: %w", err)
+		}
+
+		for _, deadKey := range deadKeys {
+			taprootOutputKey, err := schnorr.ParsePubKey(deadKey)
+			if err != nil {
+				return fmt.Errorf("unable to parse taproot "+
+					"output key: %w", err)
+			}
+
+			addr, err := fetchAddr(
+				ctx, db, t.params, taprootOutputKey,
+			)
+			if err != nil {
+				return fmt.Errorf("unable to fetch addr: %w",
+					err)
+			}
+
+			err = db.DeleteAddrEventStatusChanges(ctx, deadKey)
+			if err != nil {
+				return fmt.Errorf("unable to delete addr "+
+					"event status changes: %w", err)
+			}
+			err = db.DeleteAddrEvents(ctx, deadKey)
+			if err != nil {
+				return fmt.Errorf("unable to delete addr "+
+					"events: %w", err)
+			}
+			if err := db.DeleteAddr(ctx, deadKey); err != nil {
+				return fmt.Errorf("unable to delete addr: %w",
+					err)
+			}
+
+			prunedAddrs = append(prunedAddrs, *addr)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return prunedAddrs, nil
+}
+
 // SetAddrManaged sets an address as being managed by the internal
 // wallet.
 func (t *TapAddressBook) SetAddrManaged(ctx context.Context,
@@ -626,12 +779,18 @@ func (t *TapAddressBook) InsertScriptKey(ctx context.Context,
 			return fmt.Errorf("error inserting internal key: %w",
 				err)
 		}
-		_, err = q.UpsertScriptKey(ctx, NewScriptKey{
+		scriptKeyID, err := q.UpsertScriptKey(ctx, NewScriptKey{
 			InternalKeyID:    internalKeyID,
 			TweakedScriptKey: scriptKey.PubKey.SerializeCompressed(),
 			Tweak:            scriptKey.Tweak,
 		})
-		return err
+		if err != nil {
+			return fmt.Errorf("error inserting script key: %w", err)
+		}
+
+		return insertScriptKeyLeaves(
+			ctx, q, scriptKeyID, scriptKey.Leaves,
+		)
 	})
 }
 
@@ -719,11 +878,12 @@ func (t *TapAddressBook) GetOrCreateEvent(ctx context.Context,
 			return fmt.Errorf("error upserting utxo: %w", err)
 		}
 
+		now := time.Now().UTC()
 		eventID, err := db.UpsertAddrEvent(ctx, UpsertAddrEvent{
 			TaprootOutputKey: schnorr.SerializePubKey(
 				&addr.TaprootOutputKey,
 			),
-			CreationTime:        time.Now().UTC(),
+			CreationTime:        now,
 			Status:              int16(status),
 			Txid:                txHash[:],
 			ChainTxnOutputIndex: int32(outputIdx),
@@ -734,6 +894,16 @@ func (t *TapAddressBook) GetOrCreateEvent(ctx context.Context,
 				err)
 		}
 
+		err = db.InsertAddrEventStatusChange(ctx, AddrEventStatusChange{
+			EventID:    eventID,
+			Status:     int16(status),
+			ChangeTime: now,
+		})
+		if err != nil {
+			return fmt.Errorf("error recording status change: %w",
+				err)
+		}
+
 		event, err = fetchEvent(ctx, db, eventID, addr)
 		return err
 	})
@@ -834,6 +1004,37 @@ func fetchEvent(ctx context.Context, db AddrBook, eventID int32,
 		Index: uint32(dbEvent.OutputIndex),
 	}
 
+	// Events of a static address belong to a single receive through that
+	// address, which is identified by the tapscript sibling of the anchor
+	// output.
+	if addr.Static && len(dbEvent.TapscriptSibling) > 0 {
+		sibling, _, err := commitment.MaybeDecodeTapscriptPreimage(
+			dbEvent.TapscriptSibling,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode tapscript "+
+				"sibling: %w", err)
+		}
+
+		addr, err = addr.StaticReceiveFromSibling(sibling)
+		if err != nil {
+			return nil, fmt.Errorf("unable to derive static "+
+				"receive: %w", err)
+		}
+	}
+
+	dbChanges, err := db.FetchAddrEventStatusChanges(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching status changes: %w", err)
+	}
+	statusChanges := make([]address.StatusChange, len(dbChanges))
+	for idx, dbChange := range dbChanges {
+		statusChanges[idx] = address.StatusChange{
+			Status: address.Status(dbChange.Status),
+			Time:   dbChange.ChangeTime.UTC(),
+		}
+	}
+
 	return &address.Event{
 		ID:                 eventID,
 		CreationTime:       dbEvent.CreationTime.UTC(),
@@ -844,6 +1045,7 @@ func fetchEvent(ctx context.Context, db AddrBook, eventID int32,
 		InternalKey:        internalKey,
 		ConfirmationHeight: uint32(dbEvent.ConfirmationHeight.Int32),
 		HasProof:           dbEvent.AssetProofID.Valid,
+		StatusChanges:      statusChanges,
 	}, nil
 }
 
@@ -862,7 +1064,7 @@ func (a *TapAddressBook) CompleteEvent(ctx context.Context,
 			return fmt.Errorf("error fetching asset proof: %w", err)
 		}
 
-		_, err = db.UpsertAddrEvent(ctx, UpsertAddrEvent{
+		eventID, err := db.UpsertAddrEvent(ctx, UpsertAddrEvent{
 			TaprootOutputKey: schnorr.SerializePubKey(
 				&event.Addr.TaprootOutputKey,
 			),
@@ -872,7 +1074,16 @@ func (a *TapAddressBook) CompleteEvent(ctx context.Context,
 			AssetProofID:        sqlInt32(proofData.ProofID),
 			AssetID:             sqlInt32(proofData.AssetID),
 		})
-		return err
+		if err != nil {
+			return err
+		}
+
+		statusChange := AddrEventStatusChange{
+			EventID:    eventID,
+			Status:     int16(status),
+			ChangeTime: time.Now().UTC(),
+		}
+		return db.InsertAddrEventStatusChange(ctx, statusChange)
 	})
 }
 
@@ -922,7 +1133,8 @@ func (a *TapAddressBook) QueryAssetGroup(ctx context.Context,
 
 		assetGroup.GroupKey, err = parseGroupKeyInfo(
 			groupInfo.TweakedGroupKey, groupInfo.RawKey,
-			groupInfo.GenesisSig, groupInfo.KeyFamily,
+			groupInfo.GenesisSig, groupInfo.WitnessStack,
+			groupInfo.TapscriptRoot, groupInfo.KeyFamily,
 			groupInfo.KeyIndex,
 		)
 
@@ -1013,6 +1225,23 @@ func (t *TapAddressBook) FetchScriptKey(ctx context.Context,
 			return fmt.Errorf("unable to parse raw key: %w", err)
 		}
 
+		dbLeaves, err := db.FetchScriptKeyLeaves(
+			ctx, tweakedScriptKey.SerializeCompressed(),
+		)
+		if err != nil {
+			return fmt.Errorf("unable to fetch script key "+
+				"leaves: %w", err)
+		}
+
+		var leaves []txscript.TapLeaf
+		for _, dbLeaf := range dbLeaves {
+			leaves = append(leaves, txscript.NewTapLeaf(
+				txscript.TapscriptLeafVersion(
+					dbLeaf.LeafVersion,
+				), dbLeaf.Script,
+			))
+		}
+
 		scriptKey = &asset.TweakedScriptKey{
 			Tweak: dbKey.Tweak,
 			RawKey: keychain.KeyDescriptor{
@@ -1024,6 +1253,7 @@ func (t *TapAddressBook) FetchScriptKey(ctx context.Context,
 					Index: uint32(dbKey.KeyIndex),
 				},
 			},
+			Leaves: leaves,
 		}
 
 		return nil
@@ -1043,3 +1273,50 @@ func (t *TapAddressBook) FetchScriptKey(ctx context.Context,
 // address.Storage and address.EventStorage interface.
 var _ address.Storage = (*TapAddressBook)(nil)
 var _ address.EventStorage = (*TapAddressBook)(nil)
+
+// encodeCourierAddr serializes an optional proof courier address for storage.
+func encodeCourierAddr(addr *url.URL) []byte {
+	if addr == nil {
+		return nil
+	}
+
+	return []byte(addr.String())
+}
+
+// decodeCourierAddr parses an optional proof courier address from its stored
+// form.
+func decodeCourierAddr(addrBytes []byte) (*url.URL, error) {
+	if len(addrBytes) == 0 {
+		return nil, nil
+	}
+
+	addr, err := url.ParseRequestURI(string(addrBytes))
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode proof courier "+
+			"address: %w", err)
+	}
+
+	return addr, nil
+}
+
+// encodeAddrExpiry converts the optional expiry of an address for storage.
+func encodeAddrExpiry(addr *address.Tap) sql.NullTime {
+	if !addr.HasExpiry() {
+		return sql.NullTime{}
+	}
+
+	return sql.NullTime{
+		Time:  addr.Expiry.UTC(),
+		Valid: true,
+	}
+}
+
+// decodeAddrExpiry converts the optional expiry of an address from its stored
+// form. A missing expiry results in the zero time.
+func decodeAddrExpiry(expiry sql.NullTime) time.Time {
+	if !expiry.Valid {
+		return time.Time{}
+	}
+
+	return time.Unix(expiry.Time.Unix(), 0)
+}
@@ -8,11 +8,14 @@ import (
 	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/lightninglabs/lndclient"
 	"github.com/lightninglabs/taproot-assets/address"
+	"github.com/lightninglabs/taproot-assets/asset"
 	"github.com/lightninglabs/taproot-assets/internal/test"
 	"github.com/lightninglabs/taproot-assets/tapdb/sqlc"
+	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/stretchr/testify/require"
 )
@@ -132,6 +135,20 @@ func assertEqualAddrEvent(t *testing.T, expected, actual address.Event) {
 	actual.CreationTime = time.Time{}
 	expected.CreationTime = time.Time{}
 
+	// The same goes for the times of the status changes.
+	require.Len(t, actual.StatusChanges, len(expected.StatusChanges))
+	for idx := range expected.StatusChanges {
+		expectedChange := expected.StatusChanges[idx]
+		actualChange := actual.StatusChanges[idx]
+
+		require.Equal(t, expectedChange.Status, actualChange.Status)
+		require.Equal(
+			t, expectedChange.Time.Unix(), actualChange.Time.Unix(),
+		)
+	}
+	actual.StatusChanges = nil
+	expected.StatusChanges = nil
+
 	require.Equal(t, expected, actual)
 	require.Equal(t, expectedTime.Unix(), actualTime.Unix())
 }
@@ -244,6 +261,22 @@ func TestAddressQuery(t *testing.T) {
 	for i := 0; i < numAddrs; i++ {
 		addr, assetGen, assetGroup := address.RandAddr(t, chainParams)
 
+		// Only addresses of normal assets can omit the amount, so we
+		// make sure the second one is for a normal asset.
+		for i == 1 && addr.AssetType() != asset.Normal {
+			addr, assetGen, assetGroup = address.RandAddr(
+				t, chainParams,
+			)
+		}
+
+		// Only addresses of grouped assets can accept any asset of
+		// the group, which we want the third one to do.
+		for i == 2 && addr.GroupKey == nil {
+			addr, assetGen, assetGroup = address.RandAddr(
+				t, chainParams,
+			)
+		}
+
 		err := addrBook.db.ExecTx(
 			ctx, &writeTxOpts,
 			insertFullAssetGen(ctx, assetGen, assetGroup),
@@ -252,6 +285,13 @@ func TestAddressQuery(t *testing.T) {
 
 		addrs[i] = *addr
 	}
+
+	// We'll also mark one of the addresses as static, remove the amount
+	// from another one and let a third one accept any asset of its group,
+	// so we can filter for them below.
+	addrs[0].Static = true
+	addrs[1].Amount = 0
+	addrs[2].AnyAssetInGroup = true
 	require.NoError(t, addrBook.InsertAddrs(ctx, addrs...))
 
 	tests := []struct {
@@ -262,6 +302,9 @@ func TestAddressQuery(t *testing.T) {
 		limit         int32
 		offset        int32
 		unmanagedOnly bool
+		staticOnly    bool
+		amountless    bool
+		groupOnly     bool
 
 		numAddrs   int
 		firstIndex int
@@ -323,24 +366,112 @@ func TestAddressQuery(t *testing.T) {
 			unmanagedOnly: true,
 			numAddrs:      numAddrs,
 		},
+
+		// Static only, which is just the first address.
+		{
+			name: "static only",
+
+			staticOnly: true,
+			numAddrs:   1,
+		},
+
+		// Amountless only, which is just the second address.
+		{
+			name: "amountless only",
+
+			amountless: true,
+			numAddrs:   1,
+		},
+
+		// Group only, which is just the third address.
+		{
+			name: "group only",
+
+			groupOnly: true,
+			numAddrs:  1,
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			dbAddrs, err := addrBook.QueryAddrs(
 				ctx, address.QueryParams{
-					CreatedAfter:  test.createdAfter,
-					CreatedBefore: test.createdBefore,
-					Offset:        test.offset,
-					Limit:         test.limit,
-					UnmanagedOnly: test.unmanagedOnly,
+					CreatedAfter:   test.createdAfter,
+					CreatedBefore:  test.createdBefore,
+					Offset:         test.offset,
+					Limit:          test.limit,
+					UnmanagedOnly:  test.unmanagedOnly,
+					StaticOnly:     test.staticOnly,
+					AmountlessOnly: test.amountless,
+					GroupOnly:      test.groupOnly,
 				},
 			)
 			require.NoError(t, err)
 			require.Len(t, dbAddrs, test.numAddrs)
+
+			if test.staticOnly {
+				require.True(t, dbAddrs[0].Static)
+			}
+			if test.amountless {
+				require.False(t, dbAddrs[0].HasAmount())
+			}
+			if test.groupOnly {
+				require.True(t, dbAddrs[0].AnyAssetInGroup)
+				require.Equal(
+					t, addrs[2].AssetID,
+					dbAddrs[0].AssetID,
+				)
+			}
 		})
 	}
 }
 
+// TestScriptKeyLeaves tests that the tapscript leaves of a script key are
+// persisted along with the key and are returned in the same order.
+func TestScriptKeyLeaves(t *testing.T) {
+	t.Parallel()
+
+	addrBook, _ := newAddrBook(t)
+	ctx := context.Background()
+
+	leaves := []txscript.TapLeaf{
+		txscript.NewBaseTapLeaf(test.RandBytes(40)),
+		txscript.NewBaseTapLeaf(test.RandBytes(20)),
+		txscript.NewTapLeaf(0xc2, test.RandBytes(30)),
+	}
+	scriptKey := asset.ScriptKey{
+		PubKey: test.RandPubKey(t),
+		TweakedScriptKey: &asset.TweakedScriptKey{
+			RawKey: keychain.KeyDescriptor{
+				PubKey: test.RandPubKey(t),
+				KeyLocator: keychain.KeyLocator{
+					Family: 212,
+					Index:  7,
+				},
+			},
+			Tweak:  test.RandBytes(32),
+			Leaves: leaves,
+		},
+	}
+
+	// Inserting the same key twice shouldn't duplicate the leaves.
+	require.NoError(t, addrBook.InsertScriptKey(ctx, scriptKey))
+	require.NoError(t, addrBook.InsertScriptKey(ctx, scriptKey))
+
+	dbKey, err := addrBook.FetchScriptKey(ctx, scriptKey.PubKey)
+	require.NoError(t, err)
+	require.Equal(t, scriptKey.Tweak, dbKey.Tweak)
+	require.Equal(t, leaves, dbKey.Leaves)
+
+	// A script key without leaves is returned without leaves.
+	scriptKey.PubKey = test.RandPubKey(t)
+	scriptKey.TweakedScriptKey.Leaves = nil
+	require.NoError(t, addrBook.InsertScriptKey(ctx, scriptKey))
+
+	dbKey, err = addrBook.FetchScriptKey(ctx, scriptKey.PubKey)
+	require.NoError(t, err)
+	require.Nil(t, dbKey.Leaves)
+}
+
 // TestAddrEventStatusDBEnum makes sure we cannot insert an event with an
 // invalid status into the database.
 func TestAddrEventStatusDBEnum(t *testing.T) {
@@ -411,6 +542,18 @@ func TestAddrEventCreation(t *testing.T) {
 		)
 		require.NoError(t, err)
 
+		// The detection of the transaction should've been recorded at
+		// the creation time of the event.
+		require.Len(t, event.StatusChanges, 1)
+		require.Equal(
+			t, address.StatusTransactionDetected,
+			event.StatusChanges[0].Status,
+		)
+		require.Equal(
+			t, event.CreationTime.Unix(),
+			event.StatusChanges[0].Time.Unix(),
+		)
+
 		events[i] = event
 	}
 
@@ -446,10 +589,97 @@ func TestAddrEventCreation(t *testing.T) {
 		)
 		require.NoError(t, err)
 
+		// The confirmation should've been recorded as a new status
+		// change, while the detection time stays the same.
+		require.Len(t, actual.StatusChanges, 2)
+		confirmChange := actual.StatusChanges[1]
+		require.Equal(
+			t, address.StatusTransactionConfirmed,
+			confirmChange.Status,
+		)
+		require.False(
+			t, confirmChange.Time.Before(events[idx].CreationTime),
+		)
+		events[idx].StatusChanges = append(
+			events[idx].StatusChanges, confirmChange,
+		)
+
 		assertEqualAddrEvent(t, *events[idx], *actual)
 	}
 }
 
+// TestStaticAddrEvent tests that the events of receives through a static
+// address are returned with the unique receive they belong to.
+func TestStaticAddrEvent(t *testing.T) {
+	t.Parallel()
+
+	// First, make a new addr book instance we'll use in the test below.
+	addrBook, _ := newAddrBook(t)
+
+	ctx := context.Background()
+
+	addr, assetGen, assetGroup := address.RandAddr(t, chainParams)
+	addr.Static = true
+	addr.TapscriptSibling = nil
+	taprootOutputKey, err := addr.Tap.TaprootOutputKey()
+	require.NoError(t, err)
+	addr.TaprootOutputKey = *taprootOutputKey
+
+	var writeTxOpts AddrBookTxOptions
+	err = addrBook.db.ExecTx(
+		ctx, &writeTxOpts,
+		insertFullAssetGen(ctx, assetGen, assetGroup),
+	)
+	require.NoError(t, err)
+	require.NoError(t, addrBook.InsertAddrs(ctx, *addr))
+
+	// We'll now create events for two different receives through the
+	// same static address.
+	const numReceives = 2
+	receives := make([]*address.AddrWithKeyInfo, numReceives)
+	for i := 0; i < numReceives; i++ {
+		nonce, err := address.RandStaticNonce()
+		require.NoError(t, err)
+
+		receives[i], err = addr.StaticReceive(nonce)
+		require.NoError(t, err)
+
+		walletTx := randWalletTx()
+		_, err = addrBook.GetOrCreateEvent(
+			ctx, address.StatusTransactionDetected, receives[i],
+			walletTx, 0,
+		)
+		require.NoError(t, err)
+	}
+
+	// Each event should be returned with the receive it belongs to, even
+	// though both are stored for the same static address.
+	events, err := addrBook.QueryAddrEvents(
+		ctx, address.EventQueryParams{},
+	)
+	require.NoError(t, err)
+	require.Len(t, events, numReceives)
+
+	receivesByKey := make(map[asset.SerializedKey]*address.AddrWithKeyInfo)
+	for _, receive := range receives {
+		receivesByKey[asset.ToSerialized(&receive.ScriptKey)] = receive
+	}
+	for _, event := range events {
+		receive, ok := receivesByKey[asset.ToSerialized(
+			&event.Addr.ScriptKey,
+		)]
+		require.True(t, ok)
+		require.False(t, event.Addr.Static)
+		require.Equal(
+			t, receive.ScriptKeyTweak.Tweak,
+			event.Addr.ScriptKeyTweak.Tweak,
+		)
+		require.Equal(
+			t, addr.TaprootOutputKey, event.Addr.TaprootOutputKey,
+		)
+	}
+}
+
 // TestAddressEventQuery tests that we're able to properly retrieve rows based
 // on various combinations of the query parameters.
 func TestAddressEventQuery(t *testing.T) {
@@ -578,3 +808,105 @@ func TestAddressEventQuery(t *testing.T) {
 		})
 	}
 }
+
+// TestPruneAddrs tests that only addresses that can no longer receive any
+// transfers and don't have any pending receives are pruned.
+func TestPruneAddrs(t *testing.T) {
+	t.Parallel()
+
+	// First, make a new addr book instance we'll use in the test below.
+	addrBook, _ := newAddrBook(t)
+
+	ctx := context.Background()
+	now := time.Now()
+
+	testCases := []struct {
+		name      string
+		expiry    time.Time
+		singleUse bool
+		events    []address.Status
+		pruned    bool
+	}{{
+		name:   "expired",
+		expiry: now.Add(-time.Hour),
+		pruned: true,
+	}, {
+		name:   "not expired",
+		expiry: now.Add(time.Hour),
+	}, {
+		name:   "expired with pending event",
+		expiry: now.Add(-time.Hour),
+		events: []address.Status{
+			address.StatusTransactionConfirmed,
+		},
+	}, {
+		name:      "single-use with completed event",
+		singleUse: true,
+		events:    []address.Status{address.StatusCompleted},
+		pruned:    true,
+	}, {
+		name:      "single-use with pending event",
+		singleUse: true,
+		events:    []address.Status{address.StatusProofReceived},
+	}, {
+		name:      "single-use without event",
+		singleUse: true,
+	}, {
+		name:   "regular address with completed event",
+		events: []address.Status{address.StatusCompleted},
+	}}
+
+	var (
+		writeTxOpts   AddrBookTxOptions
+		expectedPrune []address.AddrWithKeyInfo
+		expectedKeep  []address.AddrWithKeyInfo
+	)
+	for _, tc := range testCases {
+		addr, assetGen, assetGroup := address.RandAddr(t, chainParams)
+		if !tc.expiry.IsZero() {
+			addr.Expiry = time.Unix(tc.expiry.Unix(), 0)
+		}
+		addr.SingleUse = tc.singleUse
+
+		err := addrBook.db.ExecTx(
+			ctx, &writeTxOpts,
+			insertFullAssetGen(ctx, assetGen, assetGroup),
+		)
+		require.NoError(t, err)
+		require.NoError(t, addrBook.InsertAddrs(ctx, *addr), tc.name)
+
+		for _, status := range tc.events {
+			_, err := addrBook.GetOrCreateEvent(
+				ctx, status, addr, randWalletTx(), 0,
+			)
+			require.NoError(t, err, tc.name)
+		}
+
+		if tc.pruned {
+			expectedPrune = append(expectedPrune, *addr)
+		} else {
+			expectedKeep = append(expectedKeep, *addr)
+		}
+	}
+
+	pruned, err := addrBook.PruneAddrs(ctx, now)
+	require.NoError(t, err)
+	assertEqualAddrs(t, expectedPrune, pruned)
+
+	// Only the addresses that weren't pruned should be left, and pruning
+	// again shouldn't remove anything else.
+	remaining, err := addrBook.QueryAddrs(ctx, address.QueryParams{})
+	require.NoError(t, err)
+	assertEqualAddrs(t, expectedKeep, remaining)
+
+	pruned, err = addrBook.PruneAddrs(ctx, now)
+	require.NoError(t, err)
+	require.Empty(t, pruned)
+
+	// The events of the pruned addresses should be gone as well.
+	events, err := addrBook.QueryAddrEvents(
+		ctx, address.EventQueryParams{},
+	)
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+}
@@ -6,6 +6,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcec/v2/schnorr"
@@ -54,6 +55,9 @@ type (
 	// AssetSeedlingTuple is used to look up the ID of a seedling.
 	AssetSeedlingTuple = sqlc.FetchSeedlingIDParams
 
+	// AssetSeedlingDelete is used to delete a seedling from a batch.
+	AssetSeedlingDelete = sqlc.DeleteSeedlingParams
+
 	// MintingBatchTuple is used to update a batch state based on the raw
 	// key.
 	MintingBatchTuple = sqlc.UpdateMintingBatchStateParams
@@ -65,6 +69,14 @@ type (
 	// transaction associated with it.
 	BatchChainUpdate = sqlc.BindMintingBatchWithTxParams
 
+	// BatchTapSiblingUpdate is used to update a batch with the tapscript
+	// sibling of its genesis output.
+	BatchTapSiblingUpdate = sqlc.BindMintingBatchWithTapSiblingParams
+
+	// BatchProofPushAttempt is used to log an attempt to push the genesis
+	// proofs of a batch.
+	BatchProofPushAttempt = sqlc.InsertBatchProofPushAttemptParams
+
 	// GenesisTxUpdate is used to update the existing batch TX associated
 	// with a batch.
 	GenesisTxUpdate = sqlc.UpdateBatchGenesisTxParams
@@ -114,9 +126,17 @@ type (
 	// disk.
 	NewScriptKey = sqlc.UpsertScriptKeyParams
 
+	// NewScriptKeyLeaf wraps the params needed to insert a new tapscript
+	// leaf of a script key on disk.
+	NewScriptKeyLeaf = sqlc.InsertScriptKeyLeafParams
+
 	// NewAssetMeta wraps the params needed to insert a new asset meta on
 	// disk.
 	NewAssetMeta = sqlc.UpsertAssetMetaParams
+
+	// GroupKeyLocator wraps the params needed to look up asset groups by
+	// the key locator of their raw group key.
+	GroupKeyLocator = sqlc.FetchGroupKeysByKeyLocatorParams
 )
 
 // PendingAssetStore is a sub-set of the main sqlc.Querier interface that
@@ -174,14 +194,56 @@ type PendingAssetStore interface {
 	FetchSeedlingByID(ctx context.Context,
 		seedlingID int32) (AssetSeedling, error)
 
+	// DeleteSeedling is used to delete a specific seedling from a batch.
+	DeleteSeedling(ctx context.Context, arg AssetSeedlingDelete) error
+
 	// BindMintingBatchWithTx adds the minting transaction to an existing
 	// batch.
 	BindMintingBatchWithTx(ctx context.Context, arg BatchChainUpdate) error
 
+	// BindMintingBatchWithTapSibling adds a tapscript sibling to an
+	// existing batch.
+	BindMintingBatchWithTapSibling(ctx context.Context,
+		arg BatchTapSiblingUpdate) error
+
 	// UpdateBatchGenesisTx updates the batch tx attached to an existing
 	// batch.
 	UpdateBatchGenesisTx(ctx context.Context, arg GenesisTxUpdate) error
 
+	// InsertBatchProofPushAttempt logs an attempt to push the genesis
+	// proofs of a batch to a universe server.
+	InsertBatchProofPushAttempt(ctx context.Context,
+		arg BatchProofPushAttempt) error
+
+	// QueryBatchProofPushAttempts returns the timestamps of all logged
+	// attempts to push the genesis proofs of a batch.
+	QueryBatchProofPushAttempts(ctx context.Context,
+		rawKey []byte) ([]time.Time, error)
+
+	// DeleteBatchSprouts deletes the sprouts of all batches in the given
+	// state that were created before the given time and returns the
+	// number of deleted sprouts.
+	DeleteBatchSprouts(ctx context.Context,
+		arg sqlc.DeleteBatchSproutsParams) (int64, error)
+
+	// DeleteBatchSeedlings deletes the seedlings of all batches in the
+	// given state that were created before the given time and returns the
+	// number of deleted seedlings.
+	DeleteBatchSeedlings(ctx context.Context,
+		arg sqlc.DeleteBatchSeedlingsParams) (int64, error)
+
+	// DeleteBatchProofPushAttempts deletes the logged proof push attempts
+	// of all batches in the given state that were created before the given
+	// time and returns the number of deleted attempts.
+	DeleteBatchProofPushAttempts(ctx context.Context,
+		arg sqlc.DeleteBatchProofPushAttemptsParams) (int64, error)
+
+	// DeleteMintingBatches deletes all batches in the given state that
+	// were created before the given time and returns the number of deleted
+	// batches.
+	DeleteMintingBatches(ctx context.Context,
+		arg sqlc.DeleteMintingBatchesParams) (int64, error)
+
 	// UpsertManagedUTXO inserts a new or updates an existing managed UTXO
 	// to disk and returns the primary key.
 	UpsertManagedUTXO(ctx context.Context, arg RawManagedUTXO) (int32,
@@ -319,6 +381,13 @@ func (a *AssetMintingStore) CommitMintingBatch(ctx context.Context,
 				return err
 			}
 
+			scriptSpend, err := encodeGroupScriptSpend(
+				seedling.GroupScriptSpend,
+			)
+			if err != nil {
+				return err
+			}
+
 			dbSeedling := AssetSeedlingShell{
 				BatchID:         batchID,
 				AssetName:       seedling.AssetName,
@@ -326,6 +395,11 @@ func (a *AssetMintingStore) CommitMintingBatch(ctx context.Context,
 				AssetSupply:     int64(seedling.Amount),
 				AssetMetaID:     assetMetaID,
 				EmissionEnabled: seedling.EnableEmission,
+				NonDivisible:    seedling.NonDivisible,
+				GroupTapscriptRoot: seedling.
+					GroupTapscriptRoot,
+				Editions:         seedling.Editions,
+				GroupScriptSpend: scriptSpend,
 			}
 
 			// If this seedling is being issued to an existing
@@ -392,6 +466,13 @@ func (a *AssetMintingStore) AddSeedlingsToBatch(ctx context.Context,
 				return err
 			}
 
+			scriptSpend, err := encodeGroupScriptSpend(
+				seedling.GroupScriptSpend,
+			)
+			if err != nil {
+				return err
+			}
+
 			dbSeedling := AssetSeedlingItem{
 				RawKey:          rawBatchKey,
 				AssetName:       seedling.AssetName,
@@ -399,6 +480,11 @@ func (a *AssetMintingStore) AddSeedlingsToBatch(ctx context.Context,
 				AssetSupply:     int64(seedling.Amount),
 				AssetMetaID:     assetMetaID,
 				EmissionEnabled: seedling.EnableEmission,
+				NonDivisible:    seedling.NonDivisible,
+				GroupTapscriptRoot: seedling.
+					GroupTapscriptRoot,
+				Editions:         seedling.Editions,
+				GroupScriptSpend: scriptSpend,
 			}
 
 			// If this seedling is being issued to an existing
@@ -441,6 +527,20 @@ func (a *AssetMintingStore) AddSeedlingsToBatch(ctx context.Context,
 	})
 }
 
+// DeleteSeedlingFromBatch removes the seedling with the given asset name from
+// the batch identified by the given batch key.
+func (a *AssetMintingStore) DeleteSeedlingFromBatch(ctx context.Context,
+	batchKey *btcec.PublicKey, assetName string) error {
+
+	var writeTxOpts AssetStoreTxOptions
+	return a.db.ExecTx(ctx, &writeTxOpts, func(q PendingAssetStore) error {
+		return q.DeleteSeedling(ctx, AssetSeedlingDelete{
+			SeedlingName: assetName,
+			BatchKey:     batchKey.SerializeCompressed(),
+		})
+	})
+}
+
 // fetchSeedlingID attempts to fetch the ID for a seedling from a specific
 // batch. This is performed within the context of a greater DB transaction.
 func fetchSeedlingID(ctx context.Context, q PendingAssetStore,
@@ -478,7 +578,20 @@ func fetchAssetSeedlings(ctx context.Context, q PendingAssetStore,
 			Amount: uint64(
 				dbSeedling.AssetSupply,
 			),
-			EnableEmission: dbSeedling.EmissionEnabled,
+			EnableEmission:     dbSeedling.EmissionEnabled,
+			NonDivisible:       dbSeedling.NonDivisible,
+			GroupTapscriptRoot: dbSeedling.GroupTapscriptRoot,
+			Editions:           dbSeedling.Editions,
+			DecimalDisplay: extractSqlInt32[uint32](
+				dbSeedling.DecimalDisplay,
+			),
+		}
+
+		seedling.GroupScriptSpend, err = decodeGroupScriptSpend(
+			dbSeedling.GroupScriptSpend,
+		)
+		if err != nil {
+			return nil, err
 		}
 
 		// Fetch the group info for seedlings with a specific group.
@@ -580,6 +693,12 @@ func fetchAssetSprouts(ctx context.Context, q PendingAssetStore,
 			if err != nil {
 				return nil, err
 			}
+			groupWitness, err := parseGroupWitness(
+				sprout.GroupWitness,
+			)
+			if err != nil {
+				return nil, err
+			}
 
 			groupKey = &asset.GroupKey{
 				RawKey: keychain.KeyDescriptor{
@@ -595,8 +714,10 @@ func fetchAssetSprouts(ctx context.Context, q PendingAssetStore,
 						),
 					},
 				},
-				GroupPubKey: *tweakedGroupKey,
-				Sig:         *groupSig,
+				GroupPubKey:   *tweakedGroupKey,
+				Sig:           *groupSig,
+				TapscriptRoot: sprout.GroupTapscriptRoot,
+				Witness:       groupWitness,
 			}
 		}
 
@@ -644,6 +765,8 @@ func fetchAssetSprouts(ctx context.Context, q PendingAssetStore,
 			return nil, fmt.Errorf("unable to create new sprout: "+
 				"%v", err)
 		}
+		assetSprout.NonDivisible = sprout.NonDivisible
+		assetSprout.Edition = uint64(sprout.Edition)
 
 		// TODO(roasbeef): need to update the above to set the
 		// witnesses of a valid asset
@@ -848,6 +971,14 @@ func marshalMintingBatch(ctx context.Context, q PendingAssetStore,
 		CreationTime: dbBatch.CreationTimeUnix.UTC(),
 	}
 
+	batch.TapSibling, _, err = commitment.MaybeDecodeTapscriptPreimage(
+		dbBatch.TapscriptSibling,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode tapscript sibling: %w",
+			err)
+	}
+
 	if dbBatch.MintingTxPsbt != nil {
 		genesisPkt, err := psbt.NewFromRawBytes(
 			bytes.NewReader(dbBatch.MintingTxPsbt), false,
@@ -979,8 +1110,59 @@ func (a *AssetMintingStore) AddSproutsToBatch(ctx context.Context,
 	})
 }
 
+// CommitBatchTx stores the funded genesis packet of a frozen batch, so it can
+// be reused after a restart instead of funding a new one. The batch state is
+// left untouched.
+func (a *AssetMintingStore) CommitBatchTx(ctx context.Context,
+	batchKey *btcec.PublicKey, genesisPacket *tapgarden.FundedPsbt) error {
+
+	var psbtBuf bytes.Buffer
+	if err := genesisPacket.Pkt.Serialize(&psbtBuf); err != nil {
+		return fmt.Errorf("unable to encode psbt: %w", err)
+	}
+
+	rawBatchKey := batchKey.SerializeCompressed()
+
+	var writeTxOpts AssetStoreTxOptions
+	return a.db.ExecTx(ctx, &writeTxOpts, func(q PendingAssetStore) error {
+		return q.BindMintingBatchWithTx(ctx, BatchChainUpdate{
+			RawKey:        rawBatchKey,
+			MintingTxPsbt: psbtBuf.Bytes(),
+			ChangeOutputIndex: sqlInt32(
+				genesisPacket.ChangeOutputIndex,
+			),
+		})
+	})
+}
+
+// CommitBatchTapSibling stores the tapscript sibling preimage that is committed
+// to alongside the Taproot Asset commitment in the genesis output of a batch.
+func (a *AssetMintingStore) CommitBatchTapSibling(ctx context.Context,
+	batchKey *btcec.PublicKey,
+	tapSibling *commitment.TapscriptPreimage) error {
+
+	siblingBytes, _, err := commitment.MaybeEncodeTapscriptPreimage(
+		tapSibling,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to encode tapscript sibling: %w", err)
+	}
+
+	rawBatchKey := batchKey.SerializeCompressed()
+
+	var writeTxOpts AssetStoreTxOptions
+	return a.db.ExecTx(ctx, &writeTxOpts, func(q PendingAssetStore) error {
+		return q.BindMintingBatchWithTapSibling(
+			ctx, BatchTapSiblingUpdate{
+				RawKey:           rawBatchKey,
+				TapscriptSibling: siblingBytes,
+			},
+		)
+	})
+}
+
 // CommitSignedGenesisTx binds a fully signed genesis transaction to a pending
-// batch on disk. The anchor output index and script root are also stored to
+// batch on disk. The anchor output index and script roots are also stored to
 // ensure we can reconstruct the private key needed to sign for the batch. The
 // genesis transaction itself is inserted as a new chain transaction, which all
 // other components then reference.
@@ -989,7 +1171,8 @@ func (a *AssetMintingStore) AddSproutsToBatch(ctx context.Context,
 // root manually?
 func (a *AssetMintingStore) CommitSignedGenesisTx(ctx context.Context,
 	batchKey *btcec.PublicKey, genesisPkt *tapgarden.FundedPsbt,
-	anchorOutputIndex uint32, merkleRoot []byte) error {
+	anchorOutputIndex uint32, merkleRoot, tapTreeRoot,
+	tapSibling []byte) error {
 
 	// The managed UTXO we'll insert only contains the raw tx of the
 	// genesis packet, so we'll extract that now.
@@ -1060,10 +1243,10 @@ func (a *AssetMintingStore) CommitSignedGenesisTx(ctx context.Context,
 			RawKey:   rawBatchKey,
 			Outpoint: anchorOutpoint,
 			AmtSats:  anchorOutput.Value,
-			// When minting, we never have a tapscript sibling, so
-			// the TaprootAssetRoot root is always equal to the
-			// merkle root.
-			TaprootAssetRoot: merkleRoot,
+			// The merkle root only differs from the Taproot Asset
+			// root if the batch has a tapscript sibling.
+			TapscriptSibling: tapSibling,
+			TaprootAssetRoot: tapTreeRoot,
 			MerkleRoot:       merkleRoot,
 			TxnID:            chainTXID,
 		})
@@ -1147,6 +1330,162 @@ func (a *AssetMintingStore) MarkBatchConfirmed(ctx context.Context,
 	})
 }
 
+// StoreProofPushAttempt logs an attempt to push the genesis proofs of the
+// batch with the given key to a universe server.
+func (a *AssetMintingStore) StoreProofPushAttempt(ctx context.Context,
+	batchKey *btcec.PublicKey) error {
+
+	rawBatchKey := batchKey.SerializeCompressed()
+
+	var writeTxOpts AssetStoreTxOptions
+	return a.db.ExecTx(ctx, &writeTxOpts, func(q PendingAssetStore) error {
+		err := q.InsertBatchProofPushAttempt(
+			ctx, BatchProofPushAttempt{
+				RawKey:   rawBatchKey,
+				TimeUnix: time.Now().UTC(),
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("unable to insert proof push "+
+				"attempt: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// QueryProofPushLog returns the timestamps of all logged attempts to push the
+// genesis proofs of the batch with the given key.
+func (a *AssetMintingStore) QueryProofPushLog(ctx context.Context,
+	batchKey *btcec.PublicKey) ([]time.Time, error) {
+
+	var (
+		timestamps  []time.Time
+		rawBatchKey = batchKey.SerializeCompressed()
+	)
+
+	readOpts := NewAssetStoreReadTx()
+	dbErr := a.db.ExecTx(ctx, &readOpts, func(q PendingAssetStore) error {
+		var err error
+		timestamps, err = q.QueryBatchProofPushAttempts(
+			ctx, rawBatchKey,
+		)
+		return err
+	})
+	if dbErr != nil {
+		return nil, fmt.Errorf("unable to query proof push log: %w",
+			dbErr)
+	}
+
+	return timestamps, nil
+}
+
+// PruneMintingBatches deletes all batches that were created before the given
+// time and were either cancelled or couldn't be funded, along with their
+// seedlings, sprouts and proof push log. The seedlings of finalized batches
+// that were created before the given time are deleted as well, as they were
+// superseded by the minted assets.
+func (a *AssetMintingStore) PruneMintingBatches(ctx context.Context,
+	createdBefore time.Time) (*tapgarden.MintingPruneResult, error) {
+
+	var (
+		result tapgarden.MintingPruneResult
+		cutoff = createdBefore.UTC()
+	)
+
+	var writeTxOpts AssetStoreTxOptions
+	pruneBatches := func(q PendingAssetStore) error {
+		result = tapgarden.MintingPruneResult{}
+
+		// The seedlings of a finalized batch are never read again, as
+		// the batch is represented by the assets it minted.
+		numSeedlings, err := q.DeleteBatchSeedlings(
+			ctx, sqlc.DeleteBatchSeedlingsParams{
+				BatchState: int16(
+					tapgarden.BatchStateFinalized,
+				),
+				CreatedBefore: cutoff,
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("unable to delete seedlings: %w",
+				err)
+		}
+		result.NumSeedlings += numSeedlings
+
+		// Batches in any of the terminal states that didn't lead to a
+		// minting transaction are deleted entirely. All rows that
+		// reference a batch need to be deleted before the batch
+		// itself.
+		prunableStates := []tapgarden.BatchState{
+			tapgarden.BatchStateSeedlingCancelled,
+			tapgarden.BatchStateSproutCancelled,
+			tapgarden.BatchStateFundingFailed,
+		}
+		for _, state := range prunableStates {
+			batchState := int16(state)
+
+			numSprouts, err := q.DeleteBatchSprouts(
+				ctx, sqlc.DeleteBatchSproutsParams{
+					BatchState:    batchState,
+					CreatedBefore: cutoff,
+				},
+			)
+			if err != nil {
+				return fmt.Errorf("unable to delete sprouts: "+
+					"%w", err)
+			}
+
+			numSeedlings, err := q.DeleteBatchSeedlings(
+				ctx, sqlc.DeleteBatchSeedlingsParams{
+					BatchState:    batchState,
+					CreatedBefore: cutoff,
+				},
+			)
+			if err != nil {
+				return fmt.Errorf("unable to delete "+
+					"seedlings: %w", err)
+			}
+
+			numAttempts, err := q.DeleteBatchProofPushAttempts(
+				ctx, sqlc.DeleteBatchProofPushAttemptsParams{
+					BatchState:    batchState,
+					CreatedBefore: cutoff,
+				},
+			)
+			if err != nil {
+				return fmt.Errorf("unable to delete proof "+
+					"push attempts: %w", err)
+			}
+
+			numBatches, err := q.DeleteMintingBatches(
+				ctx, sqlc.DeleteMintingBatchesParams{
+					BatchState:    batchState,
+					CreatedBefore: cutoff,
+				},
+			)
+			if err != nil {
+				return fmt.Errorf("unable to delete batches: "+
+					"%w", err)
+			}
+
+			result.NumSprouts += numSprouts
+			result.NumSeedlings += numSeedlings
+			result.NumProofPushAttempts += numAttempts
+			result.NumBatches += numBatches
+		}
+
+		return nil
+	}
+	dbErr := a.db.ExecTx(ctx, &writeTxOpts, pruneBatches)
+	if dbErr != nil {
+		return nil, fmt.Errorf("unable to prune minting batches: %w",
+			dbErr)
+	}
+
+	return &result, nil
+}
+
 // FetchGroupByGenesis fetches the asset group created by the genesis referenced
 // by the given ID.
 func (a *AssetMintingStore) FetchGroupByGenesis(ctx context.Context,
@@ -1193,6 +1532,69 @@ func (a *AssetMintingStore) FetchGroupByGroupKey(ctx context.Context,
 	return dbGroup, nil
 }
 
+// FetchGroupByKeyLocator fetches the asset group whose raw group key was
+// derived with the given key locator. An error is returned if no group or more
+// than one group uses a key with that locator.
+func (a *AssetMintingStore) FetchGroupByKeyLocator(ctx context.Context,
+	keyLoc keychain.KeyLocator) (*asset.AssetGroup, error) {
+
+	var (
+		dbGroup *asset.AssetGroup
+		err     error
+	)
+
+	readOpts := NewAssetStoreReadTx()
+	dbErr := a.db.ExecTx(ctx, &readOpts, func(a PendingAssetStore) error {
+		dbGroup, err = fetchGroupByKeyLocator(ctx, a, keyLoc)
+		return err
+	})
+
+	if dbErr != nil {
+		return nil, dbErr
+	}
+
+	return dbGroup, nil
+}
+
 // A compile-time assertion to ensure that AssetMintingStore meets the
 // tapgarden.MintingStore interface.
 var _ tapgarden.MintingStore = (*AssetMintingStore)(nil)
+
+// A compile-time assertion to ensure that AssetMintingStore meets the
+// tapgarden.MintingStorePruner interface.
+var _ tapgarden.MintingStorePruner = (*AssetMintingStore)(nil)
+
+// encodeGroupScriptSpend encodes the optional group script spend of a
+// seedling.
+func encodeGroupScriptSpend(spend *asset.GroupScriptSpend) ([]byte, error) {
+	if spend == nil {
+		return nil, nil
+	}
+
+	var b bytes.Buffer
+	if err := spend.Encode(&b); err != nil {
+		return nil, fmt.Errorf("unable to encode group script spend: "+
+			"%w", err)
+	}
+
+	return b.Bytes(), nil
+}
+
+// decodeGroupScriptSpend decodes the optional group script spend of a
+// seedling.
+func decodeGroupScriptSpend(spendBytes []byte) (*asset.GroupScriptSpend,
+	error) {
+
+	if len(spendBytes) == 0 {
+		return nil, nil
+	}
+
+	var spend asset.GroupScriptSpend
+	err := spend.Decode(bytes.NewReader(spendBytes))
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode group script spend: "+
+			"%w", err)
+	}
+
+	return &spend, nil
+}
@@ -13,10 +13,12 @@ import (
 	"github.com/btcsuite/btcd/btcec/v2/schnorr"
 	"github.com/btcsuite/btcd/btcutil/psbt"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/lightninglabs/taproot-assets/asset"
 	"github.com/lightninglabs/taproot-assets/chanutils"
 	"github.com/lightninglabs/taproot-assets/commitment"
+	"github.com/lightninglabs/taproot-assets/internal/test"
 	"github.com/lightninglabs/taproot-assets/proof"
 	"github.com/lightninglabs/taproot-assets/tapdb/sqlc"
 	"github.com/lightninglabs/taproot-assets/tapgarden"
@@ -247,8 +249,28 @@ func TestCommitMintingBatchSeedlings(t *testing.T) {
 	// Now we'll add an additional set of seedlings.
 	seedlings := tapgarden.RandSeedlings(t, numSeedlings)
 
-	// Pick a random seedling and give it a specific group.
-	addRandGroupToBatch(t, assetStore, ctx, seedlings)
+	// Pick a random seedling and give it a specific group, which it is
+	// issued into through a script spend.
+	_, seedlingGroups, _ := addRandGroupToBatch(
+		t, assetStore, ctx, seedlings,
+	)
+	for seedlingName := range seedlingGroups {
+		leaf := txscript.NewBaseTapLeaf(test.RandBytes(64))
+		seedling := seedlings[seedlingName]
+		seedling.GroupScriptSpend = &asset.GroupScriptSpend{
+			Leaf:           leaf,
+			InclusionProof: test.RandBytes(64),
+			Signers: []*keychain.KeyDescriptor{
+				nil, {
+					PubKey: test.RandPubKey(t),
+					KeyLocator: keychain.KeyLocator{
+						Family: 212,
+						Index:  7,
+					},
+				},
+			},
+		}
+	}
 	mintingBatch.Seedlings = mergeMap(mintingBatch.Seedlings, seedlings)
 	require.NoError(t,
 		assetStore.AddSeedlingsToBatch(
@@ -272,6 +294,18 @@ func TestCommitMintingBatchSeedlings(t *testing.T) {
 	assertSeedlingBatchLen(t, mintingBatches, 1, numSeedlings*2)
 	assertBatchState(t, mintingBatches[0], tapgarden.BatchStateFrozen)
 
+	// Once the frozen batch is funded, the genesis packet should be stored
+	// without moving the batch to a new state.
+	genesisPacket := randGenesisPacket(t)
+	require.NoError(t, assetStore.CommitBatchTx(
+		ctx, batchKey, genesisPacket,
+	))
+
+	mintingBatches = noError1(t, assetStore.FetchNonFinalBatches, ctx)
+	assertSeedlingBatchLen(t, mintingBatches, 1, numSeedlings*2)
+	assertBatchState(t, mintingBatches[0], tapgarden.BatchStateFrozen)
+	assertPsbtEqual(t, genesisPacket, mintingBatches[0].GenesisPacket)
+
 	// If we finalize the batch, then the next query to
 	// FetchNonFinalBatches should return zero batches.
 	require.NoError(t, assetStore.UpdateBatchState(
@@ -304,6 +338,54 @@ func TestCommitMintingBatchSeedlings(t *testing.T) {
 	assertSeedlingBatchLen(t, mintingBatches, 1, numSeedlings)
 }
 
+// TestDeleteSeedlingFromBatch tests that we're able to remove a single seedling
+// from a batch on disk, without affecting the other seedlings or batches.
+func TestDeleteSeedlingFromBatch(t *testing.T) {
+	t.Parallel()
+
+	assetStore, _, _ := newAssetStore(t)
+
+	ctx := context.Background()
+	const numSeedlings = 5
+
+	// We'll write two batches to disk, so we can make sure only the
+	// seedling of the target batch is removed.
+	mintingBatch := tapgarden.RandSeedlingMintingBatch(t, numSeedlings)
+	require.NoError(t, assetStore.CommitMintingBatch(ctx, mintingBatch))
+
+	otherBatch := tapgarden.RandSeedlingMintingBatch(t, numSeedlings)
+	require.NoError(t, assetStore.CommitMintingBatch(ctx, otherBatch))
+
+	batchKey := mintingBatch.BatchKey.PubKey
+	assetName := maps.Keys(mintingBatch.Seedlings)[0]
+	require.NoError(t, assetStore.DeleteSeedlingFromBatch(
+		ctx, batchKey, assetName,
+	))
+	delete(mintingBatch.Seedlings, assetName)
+
+	// The seedling should be gone from the batch, while all other
+	// seedlings are still there.
+	dbBatch, err := assetStore.FetchMintingBatch(ctx, batchKey)
+	require.NoError(t, err)
+	require.Len(t, dbBatch.Seedlings, numSeedlings-1)
+	assertBatchEqual(t, mintingBatch, dbBatch)
+
+	// The other batch should be untouched.
+	dbBatch, err = assetStore.FetchMintingBatch(
+		ctx, otherBatch.BatchKey.PubKey,
+	)
+	require.NoError(t, err)
+	assertBatchEqual(t, otherBatch, dbBatch)
+
+	// Deleting a seedling that doesn't exist is a no-op.
+	require.NoError(t, assetStore.DeleteSeedlingFromBatch(
+		ctx, batchKey, assetName,
+	))
+	dbBatch, err = assetStore.FetchMintingBatch(ctx, batchKey)
+	require.NoError(t, err)
+	require.Len(t, dbBatch.Seedlings, numSeedlings-1)
+}
+
 func randKeyDesc(t *testing.T) (keychain.KeyDescriptor, *btcec.PrivateKey) {
 	priv, err := btcec.NewPrivateKey()
 	require.NoError(t, err)
@@ -366,7 +448,17 @@ func seedlingsToAssetRoot(t *testing.T, genesisPoint wire.OutPoint,
 			require.True(t, ok)
 		}
 
-		if groupInfo != nil {
+		switch {
+		case groupInfo != nil &&
+			len(groupInfo.GroupKey.TapscriptRoot) != 0:
+
+			groupKey, err = asset.DeriveTapscriptGroupKey(
+				asset.NewRawKeyGenesisSigner(groupPriv),
+				groupInfo.GroupKey.RawKey,
+				groupInfo.GroupKey.TapscriptRoot, assetGen,
+			)
+
+		case groupInfo != nil:
 			groupKey, err = asset.DeriveGroupKey(
 				asset.NewRawKeyGenesisSigner(groupPriv),
 				groupInfo.GroupKey.RawKey,
@@ -374,7 +466,23 @@ func seedlingsToAssetRoot(t *testing.T, genesisPoint wire.OutPoint,
 			)
 		}
 
-		if seedling.EnableEmission {
+		switch {
+		case seedling.EnableEmission &&
+			len(seedling.GroupTapscriptRoot) != 0:
+
+			groupKeyRaw, newGroupPriv := randKeyDesc(t)
+			groupKey, err = asset.DeriveTapscriptGroupKey(
+				asset.NewRawKeyGenesisSigner(newGroupPriv),
+				groupKeyRaw, seedling.GroupTapscriptRoot,
+				assetGen,
+			)
+			newGroupPrivs[seedling.AssetName] = newGroupPriv
+			newGroupInfo[seedling.AssetName] = &asset.AssetGroup{
+				Genesis:  &assetGen,
+				GroupKey: groupKey,
+			}
+
+		case seedling.EnableEmission:
 			groupKeyRaw, newGroupPriv := randKeyDesc(t)
 			groupKey, err = asset.DeriveGroupKey(
 				asset.NewRawKeyGenesisSigner(newGroupPriv),
@@ -402,6 +510,7 @@ func seedlingsToAssetRoot(t *testing.T, genesisPoint wire.OutPoint,
 			asset.NewScriptKeyBip86(scriptKey), groupKey,
 		)
 		require.NoError(t, err)
+		newAsset.NonDivisible = seedling.NonDivisible
 
 		// Finally make a new asset commitment (the inner SMT tree) for
 		// this newly created asset.
@@ -479,7 +588,11 @@ func assertAssetsEqual(t *testing.T, a, b *commitment.TapCommitment) {
 	dbAssets := b.CommittedAssets()
 	for _, memAsset := range memAssets {
 		for _, dbAsset := range dbAssets {
-			if memAsset.Genesis.Tag == dbAsset.Genesis.Tag {
+			if memAsset.Genesis.Tag == dbAsset.Genesis.Tag &&
+				memAsset.ScriptKey.PubKey.IsEqual(
+					dbAsset.ScriptKey.PubKey,
+				) {
+
 				require.Equal(t, memAsset, dbAsset)
 				numFound++
 				break
@@ -603,11 +716,25 @@ func TestCommitBatchChainActions(t *testing.T) {
 	// TODO(roasbeef): move the tx extraction up one layer?
 	genesisPkt.Pkt.Inputs[0].FinalScriptSig = []byte{}
 
+	// The batch commits to a tapscript sibling next to the Taproot Asset
+	// commitment, which should be stored along with the batch.
+	tapSibling := commitment.NewPreimageFromLeaf(
+		txscript.NewBaseTapLeaf([]byte{txscript.OP_TRUE}),
+	)
+	require.NoError(t, assetStore.CommitBatchTapSibling(
+		ctx, batchKey, tapSibling,
+	))
+	siblingBytes, siblingHash, err :=
+		commitment.MaybeEncodeTapscriptPreimage(tapSibling)
+	require.NoError(t, err)
+	merkleRoot := assetRoot.TapscriptRoot(siblingHash)
+
 	// With our assets inserted, we'll now commit the signed genesis packet
-	// to disk, along with the Taproot Asset script root that's stored
+	// to disk, along with the script roots and sibling that are stored
 	// alongside any managed UTXOs.
 	require.NoError(t, assetStore.CommitSignedGenesisTx(
-		ctx, batchKey, genesisPkt, 2, scriptRoot,
+		ctx, batchKey, genesisPkt, 2, merkleRoot[:], scriptRoot,
+		siblingBytes,
 	))
 
 	// The batch updated above should be found, with the batch state
@@ -618,6 +745,7 @@ func TestCommitBatchChainActions(t *testing.T) {
 		t, mintingBatches[0], tapgarden.BatchStateBroadcast,
 	)
 	assertPsbtEqual(t, genesisPkt, mintingBatches[0].GenesisPacket)
+	require.Equal(t, tapSibling, mintingBatches[0].TapSibling)
 
 	var rawTxBytes bytes.Buffer
 	rawGenTx, err := psbt.Extract(genesisPkt.Pkt)
@@ -642,7 +770,9 @@ func TestCommitBatchChainActions(t *testing.T) {
 		TxnID: sqlInt32(dbGenTx.TxnID),
 	})
 	require.NoError(t, err)
-	require.Equal(t, scriptRoot, managedUTXO.MerkleRoot)
+	require.Equal(t, merkleRoot[:], managedUTXO.MerkleRoot)
+	require.Equal(t, scriptRoot, managedUTXO.TaprootAssetRoot)
+	require.Equal(t, siblingBytes, managedUTXO.TapscriptSibling)
 
 	// Next, we'll confirm that all the assets inserted previously now are
 	// able to be queried according to the anchor UTXO primary key.
@@ -695,9 +825,19 @@ func TestCommitBatchChainActions(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, numSeedlings, len(assets))
 
-	// All the assets returned should have the genesis prev ID set up.
+	// All the assets returned should have the genesis prev ID set up, and
+	// carry the decimal display committed to in their meta data.
 	for _, dbAsset := range assets {
 		require.True(t, dbAsset.HasGenesisWitness())
+
+		assetMeta, err := confAssets.FetchAssetMetaByHash(
+			ctx, dbAsset.Genesis.MetaHash,
+		)
+		require.NoError(t, err)
+
+		decimalDisplay, err := assetMeta.DecimalDisplay()
+		require.NoError(t, err)
+		require.Equal(t, decimalDisplay, dbAsset.DecimalDisplay)
 	}
 
 	// Now that the batch has been committed on disk, we should be able to
@@ -772,6 +912,162 @@ func TestCommitBatchChainActions(t *testing.T) {
 	}
 }
 
+// TestBatchProofPushLog tests that attempts to push the genesis proofs of a
+// batch are logged per batch.
+func TestBatchProofPushLog(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	assetStore, _, _ := newAssetStore(t)
+
+	batchKey, _, _, _, _, _ := addRandAssets(t, ctx, assetStore, 2)
+	otherBatchKey, _, _, _, _, _ := addRandAssets(t, ctx, assetStore, 2)
+
+	// Before any push was attempted, the log is empty.
+	attempts, err := assetStore.QueryProofPushLog(ctx, batchKey)
+	require.NoError(t, err)
+	require.Empty(t, attempts)
+
+	const numAttempts = 3
+	for i := 0; i < numAttempts; i++ {
+		err := assetStore.StoreProofPushAttempt(ctx, batchKey)
+		require.NoError(t, err)
+	}
+
+	// All attempts are returned, with the most recent one first.
+	attempts, err = assetStore.QueryProofPushLog(ctx, batchKey)
+	require.NoError(t, err)
+	require.Len(t, attempts, numAttempts)
+	for i := 1; i < len(attempts); i++ {
+		require.False(t, attempts[i].After(attempts[i-1]))
+	}
+
+	// The attempts of one batch don't show up for another batch.
+	attempts, err = assetStore.QueryProofPushLog(ctx, otherBatchKey)
+	require.NoError(t, err)
+	require.Empty(t, attempts)
+}
+
+// TestPruneMintingBatches tests that only cancelled batches that exceeded the
+// retention age are pruned, and that the seedlings of finalized batches are
+// pruned along with them.
+func TestPruneMintingBatches(t *testing.T) {
+	t.Parallel()
+
+	const numSeedlings = 3
+
+	ctx := context.Background()
+	assetStore, _, _ := newAssetStore(t)
+
+	now := time.Now()
+	cutoff := now.Add(-time.Hour)
+
+	// addBatch adds a new batch that was created at the given time, with
+	// its seedlings turned into sprouts if requested, and moves it to the
+	// given state.
+	addBatch := func(creationTime time.Time, sprouted bool,
+		state tapgarden.BatchState) *btcec.PublicKey {
+
+		mintingBatch := tapgarden.RandSeedlingMintingBatch(
+			t, numSeedlings,
+		)
+		mintingBatch.CreationTime = creationTime
+		_, seedlingGroups, _ := addRandGroupToBatch(
+			t, assetStore, ctx, mintingBatch.Seedlings,
+		)
+		require.NoError(t, assetStore.CommitMintingBatch(
+			ctx, mintingBatch,
+		))
+
+		batchKey := mintingBatch.BatchKey.PubKey
+		if sprouted {
+			genesisPacket := randGenesisPacket(t)
+			assetRoot := seedlingsToAssetRoot(
+				t, genesisPacket.Pkt.UnsignedTx.TxIn[0].
+					PreviousOutPoint,
+				mintingBatch.Seedlings, seedlingGroups,
+			)
+			require.NoError(t, assetStore.AddSproutsToBatch(
+				ctx, batchKey, genesisPacket, assetRoot,
+			))
+		}
+
+		require.NoError(t, assetStore.UpdateBatchState(
+			ctx, batchKey, state,
+		))
+
+		return batchKey
+	}
+
+	// We add an old batch for each of the states that are pruned
+	// entirely, an old finalized batch of which only the seedlings are
+	// pruned, and a recent cancelled batch that is kept.
+	old := cutoff.Add(-time.Minute)
+	addBatch(old, false, tapgarden.BatchStateSeedlingCancelled)
+	addBatch(old, false, tapgarden.BatchStateFundingFailed)
+	sproutCancelledKey := addBatch(
+		old, true, tapgarden.BatchStateSproutCancelled,
+	)
+	finalizedKey := addBatch(old, true, tapgarden.BatchStateFinalized)
+	recentKey := addBatch(
+		now, false, tapgarden.BatchStateSeedlingCancelled,
+	)
+
+	require.NoError(t, assetStore.StoreProofPushAttempt(
+		ctx, sproutCancelledKey,
+	))
+	require.NoError(t, assetStore.StoreProofPushAttempt(
+		ctx, finalizedKey,
+	))
+
+	result, err := assetStore.PruneMintingBatches(ctx, cutoff)
+	require.NoError(t, err)
+	require.Equal(t, &tapgarden.MintingPruneResult{
+		NumBatches:           3,
+		NumSeedlings:         4 * numSeedlings,
+		NumSprouts:           numSeedlings,
+		NumProofPushAttempts: 1,
+	}, result)
+
+	// Only the finalized and the recent batch are left.
+	batches, err := assetStore.FetchAllBatches(ctx)
+	require.NoError(t, err)
+	require.Len(t, batches, 2)
+
+	for _, batch := range batches {
+		switch {
+		case batch.BatchKey.PubKey.IsEqual(finalizedKey):
+			require.Equal(
+				t, tapgarden.BatchStateFinalized,
+				batch.BatchState,
+			)
+
+			// The minted assets of the finalized batch are kept.
+			require.Len(
+				t, batch.RootAssetCommitment.CommittedAssets(),
+				numSeedlings,
+			)
+
+		case batch.BatchKey.PubKey.IsEqual(recentKey):
+			require.Len(t, batch.Seedlings, numSeedlings)
+
+		default:
+			t.Fatalf("unexpected batch %x",
+				batch.BatchKey.PubKey.SerializeCompressed())
+		}
+	}
+
+	// The proof push log of the finalized batch is kept.
+	attempts, err := assetStore.QueryProofPushLog(ctx, finalizedKey)
+	require.NoError(t, err)
+	require.Len(t, attempts, 1)
+
+	// Pruning again doesn't find anything else to prune.
+	result, err = assetStore.PruneMintingBatches(ctx, cutoff)
+	require.NoError(t, err)
+	require.Equal(t, &tapgarden.MintingPruneResult{}, result)
+}
+
 // TestDuplicateGroupKey tests that if we attempt to insert a group key with
 // the exact same tweaked key blob, then the noop UPSERT logic triggers, and we
 // get the ID of that same key.
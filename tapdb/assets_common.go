@@ -9,6 +9,7 @@ import (
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/lightninglabs/taproot-assets/asset"
 	"github.com/lightninglabs/taproot-assets/proof"
@@ -49,6 +50,10 @@ type UpsertAssetStore interface {
 	// UpsertScriptKey inserts a new script key on disk into the DB.
 	UpsertScriptKey(context.Context, NewScriptKey) (int32, error)
 
+	// InsertScriptKeyLeaf inserts a new tapscript leaf of a script key into
+	// the DB.
+	InsertScriptKeyLeaf(ctx context.Context, arg NewScriptKeyLeaf) error
+
 	// UpsertAssetGroupSig inserts a new asset group sig into the DB.
 	UpsertAssetGroupSig(ctx context.Context, arg AssetGroupSig) (int32, error)
 
@@ -204,6 +209,8 @@ func upsertAssetsWithGenesis(ctx context.Context, q UpsertAssetStore,
 				LockTime:         sqlInt32(a.LockTime),
 				RelativeLockTime: sqlInt32(a.RelativeLockTime),
 				AnchorUtxoID:     anchorUtxoID,
+				NonDivisible:     a.NonDivisible,
+				Edition:          int64(a.Edition),
 			},
 		)
 		if err != nil {
@@ -255,6 +262,7 @@ func upsertGroupKey(ctx context.Context, groupKey *asset.GroupKey,
 		TweakedGroupKey: tweakedKeyBytes,
 		InternalKeyID:   keyID,
 		GenesisPointID:  genesisPointID,
+		TapscriptRoot:   groupKey.TapscriptRoot,
 	})
 	if err != nil {
 		return nullID, fmt.Errorf("unable to insert group key: %w",
@@ -267,10 +275,24 @@ func upsertGroupKey(ctx context.Context, groupKey *asset.GroupKey,
 	// together otherwise disparate asset IDs).
 	//
 	// TODO(roasbeef): sig here doesn't actually matter?
+	var witnessStack []byte
+	if len(groupKey.Witness) != 0 {
+		var (
+			b   bytes.Buffer
+			buf [8]byte
+		)
+		err := asset.TxWitnessEncoder(&b, &groupKey.Witness, &buf)
+		if err != nil {
+			return nullID, fmt.Errorf("unable to encode group "+
+				"witness: %w", err)
+		}
+		witnessStack = b.Bytes()
+	}
 	groupSigID, err := q.UpsertAssetGroupSig(ctx, AssetGroupSig{
-		GenesisSig: groupKey.Sig.Serialize(),
-		GenAssetID: genAssetID,
-		GroupKeyID: groupID,
+		GenesisSig:   groupKey.Sig.Serialize(),
+		GenAssetID:   genAssetID,
+		GroupKeyID:   groupID,
+		WitnessStack: witnessStack,
 	})
 	if err != nil {
 		return nullID, fmt.Errorf("unable to insert group sig: %w", err)
@@ -279,6 +301,27 @@ func upsertGroupKey(ctx context.Context, groupKey *asset.GroupKey,
 	return sqlInt32(groupSigID), nil
 }
 
+// insertScriptKeyLeaves stores the given tapscript leaves of a script key in
+// the order they were assembled into the tapscript tree.
+func insertScriptKeyLeaves(ctx context.Context, q UpsertAssetStore,
+	scriptKeyID int32, leaves []txscript.TapLeaf) error {
+
+	for idx, leaf := range leaves {
+		err := q.InsertScriptKeyLeaf(ctx, NewScriptKeyLeaf{
+			ScriptKeyID: scriptKeyID,
+			LeafIndex:   int32(idx),
+			LeafVersion: int16(leaf.LeafVersion),
+			Script:      leaf.Script,
+		})
+		if err != nil {
+			return fmt.Errorf("unable to insert script key leaf: "+
+				"%w", err)
+		}
+	}
+
+	return nil
+}
+
 // upsertScriptKey inserts or updates a script key and its associated internal
 // key.
 func upsertScriptKey(ctx context.Context, scriptKey asset.ScriptKey,
@@ -304,6 +347,13 @@ func upsertScriptKey(ctx context.Context, scriptKey asset.ScriptKey,
 				"%w", err)
 		}
 
+		err = insertScriptKeyLeaves(
+			ctx, q, scriptKeyID, scriptKey.Leaves,
+		)
+		if err != nil {
+			return 0, err
+		}
+
 		return scriptKeyID, nil
 	}
 
@@ -395,6 +445,11 @@ type GroupStore interface {
 	// a matching group key.
 	FetchGroupByGroupKey(ctx context.Context,
 		groupKey []byte) (sqlc.FetchGroupByGroupKeyRow, error)
+
+	// FetchGroupKeysByKeyLocator fetches the tweaked keys of all asset
+	// groups with a raw group key derived with the given key locator.
+	FetchGroupKeysByKeyLocator(ctx context.Context,
+		arg GroupKeyLocator) ([][]byte, error)
 }
 
 // fetchGroupByGenesis fetches the asset group created by the genesis referenced
@@ -418,7 +473,9 @@ func fetchGroupByGenesis(ctx context.Context, q GroupStore,
 
 	groupKey, err := parseGroupKeyInfo(
 		groupInfo.TweakedGroupKey, groupInfo.RawKey,
-		groupInfo.GenesisSig, groupInfo.KeyFamily, groupInfo.KeyIndex,
+		groupInfo.GenesisSig, groupInfo.WitnessStack,
+		groupInfo.TapscriptRoot, groupInfo.KeyFamily,
+		groupInfo.KeyIndex,
 	)
 	if err != nil {
 		return nil, err
@@ -452,6 +509,7 @@ func fetchGroupByGroupKey(ctx context.Context, q GroupStore,
 
 	groupKey, err := parseGroupKeyInfo(
 		groupKeyQuery, groupInfo.RawKey, groupInfo.GenesisSig,
+		groupInfo.WitnessStack, groupInfo.TapscriptRoot,
 		groupInfo.KeyFamily, groupInfo.KeyIndex,
 	)
 	if err != nil {
@@ -464,9 +522,41 @@ func fetchGroupByGroupKey(ctx context.Context, q GroupStore,
 	}, nil
 }
 
+// fetchGroupByKeyLocator fetches the asset group with a raw group key that
+// was derived with the given key locator. A raw key could back several asset
+// groups, in which case the group is ambiguous and an error is returned.
+func fetchGroupByKeyLocator(ctx context.Context, q GroupStore,
+	keyLoc keychain.KeyLocator) (*asset.AssetGroup, error) {
+
+	groupKeys, err := q.FetchGroupKeysByKeyLocator(ctx, GroupKeyLocator{
+		KeyFamily: int32(keyLoc.Family),
+		KeyIndex:  int32(keyLoc.Index),
+	})
+	switch {
+	case err != nil:
+		return nil, err
+
+	case len(groupKeys) == 0:
+		return nil, fmt.Errorf("no matching asset group: %w",
+			sql.ErrNoRows)
+
+	case len(groupKeys) > 1:
+		return nil, fmt.Errorf("%d asset groups use key locator %v",
+			len(groupKeys), keyLoc)
+	}
+
+	tweakedKey, err := btcec.ParsePubKey(groupKeys[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return fetchGroupByGroupKey(ctx, q, tweakedKey)
+}
+
 // parseGroupKeyInfo maps information on a group key into a GroupKey.
-func parseGroupKeyInfo(tweakedKey, rawKey, genesisSig []byte,
-	keyFamily, keyIndex int32) (*asset.GroupKey, error) {
+func parseGroupKeyInfo(tweakedKey, rawKey, genesisSig, witnessStack,
+	tapscriptRoot []byte, keyFamily, keyIndex int32) (*asset.GroupKey,
+	error) {
 
 	tweakedGroupKey, err := btcec.ParsePubKey(tweakedKey)
 	if err != nil {
@@ -491,13 +581,43 @@ func parseGroupKeyInfo(tweakedKey, rawKey, genesisSig []byte,
 		return nil, err
 	}
 
+	groupWitness, err := parseGroupWitness(witnessStack)
+	if err != nil {
+		return nil, err
+	}
+
 	return &asset.GroupKey{
-		RawKey:      groupRawKey,
-		GroupPubKey: *tweakedGroupKey,
-		Sig:         *groupSig,
+		RawKey:        groupRawKey,
+		GroupPubKey:   *tweakedGroupKey,
+		Sig:           *groupSig,
+		TapscriptRoot: tapscriptRoot,
+		Witness:       groupWitness,
 	}, nil
 }
 
+// parseGroupWitness decodes the serialized witness of a group key that commits
+// to a tapscript tree. A nil witness is returned for all other group keys.
+func parseGroupWitness(witnessStack []byte) (wire.TxWitness, error) {
+	if len(witnessStack) == 0 {
+		return nil, nil
+	}
+
+	var (
+		witness wire.TxWitness
+		buf     [8]byte
+	)
+	err := asset.TxWitnessDecoder(
+		bytes.NewReader(witnessStack), &witness, &buf,
+		uint64(len(witnessStack)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode group witness: %w",
+			err)
+	}
+
+	return witness, nil
+}
+
 // maybeUpsertAssetMeta inserts a meta on disk and returns the primary key of
 // that meta if metaReveal is non nil.
 func maybeUpsertAssetMeta(ctx context.Context, db UpsertAssetStore,
@@ -508,6 +628,8 @@ func maybeUpsertAssetMeta(ctx context.Context, db UpsertAssetStore,
 		metaBlob []byte
 		metaType sql.NullInt16
 
+		decimalDisplay int32
+
 		err error
 	)
 
@@ -522,6 +644,15 @@ func maybeUpsertAssetMeta(ctx context.Context, db UpsertAssetStore,
 			Valid: true,
 		}
 
+		// Meta data we can't interpret, for example from a proof we
+		// imported, is simply displayed in base units.
+		displayDecimals, err := metaReveal.DecimalDisplay()
+		if err != nil {
+			log.Warnf("Unable to parse decimal display of meta "+
+				"%x: %v", metaHash[:], err)
+		}
+		decimalDisplay = int32(displayDecimals)
+
 	// Otherwise, we'll just be inserting only the meta hash. At a later
 	// time, the reveal/blob can also be inserted.
 	case assetGen != nil:
@@ -533,9 +664,10 @@ func maybeUpsertAssetMeta(ctx context.Context, db UpsertAssetStore,
 	}
 
 	assetMetaID, err := db.UpsertAssetMeta(ctx, NewAssetMeta{
-		MetaDataHash: metaHash[:],
-		MetaDataBlob: metaBlob,
-		MetaDataType: metaType,
+		MetaDataHash:   metaHash[:],
+		MetaDataBlob:   metaBlob,
+		MetaDataType:   metaType,
+		DecimalDisplay: decimalDisplay,
 	})
 	if err != nil {
 		return assetMetaID, err
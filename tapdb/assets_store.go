@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"sort"
 	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
@@ -21,6 +22,7 @@ import (
 	"github.com/lightninglabs/taproot-assets/proof"
 	"github.com/lightninglabs/taproot-assets/tapdb/sqlc"
 	"github.com/lightninglabs/taproot-assets/tapfreighter"
+	"github.com/lightninglabs/taproot-assets/tapgarden"
 	"github.com/lightninglabs/taproot-assets/tappsbt"
 	"github.com/lightningnetwork/lnd/keychain"
 )
@@ -123,6 +125,35 @@ type (
 
 	// ReAnchorParams wraps the params needed to re-anchor a passive asset.
 	ReAnchorParams = sqlc.ReAnchorPassiveAssetsParams
+
+	// ProofDeliveryStatusParams wraps the params needed to update the proof
+	// delivery status of a transfer output.
+	ProofDeliveryStatusParams = sqlc.SetTransferOutputProofDeliveryStatusParams
+
+	// NewAssetBurn wraps the params needed to insert a new asset burn.
+	NewAssetBurn = sqlc.InsertBurnParams
+
+	// AssetBurnRow wraps a single asset burn row.
+	AssetBurnRow = sqlc.QueryBurnsRow
+
+	// AnchorTxQuery wraps the params needed to query the anchor
+	// transactions of proofs and transfers.
+	AnchorTxQuery = sqlc.FetchAnchorTxsParams
+
+	// AnchorTxProof wraps a single proof file of an asset anchored in a
+	// given transaction.
+	AnchorTxProof = sqlc.FetchAnchorTxProofsRow
+
+	// ProofInvalidation wraps the params needed to set the invalidation
+	// of the proofs anchored in a transaction.
+	ProofInvalidation = sqlc.SetAnchorTxProofsInvalidatedParams
+
+	// TransferInvalidation wraps the params needed to set the
+	// invalidation of the transfers anchored in a transaction.
+	TransferInvalidation = sqlc.SetAnchorTxTransfersInvalidatedParams
+
+	// ProofRebind wraps the params needed to re-bind a proof file.
+	ProofRebind = sqlc.RebindAssetProofParams
 )
 
 // ActiveAssetsStore is a sub-set of the main sqlc.Querier interface that
@@ -202,6 +233,19 @@ type ActiveAssetsStore interface {
 	// serialized outpoint.
 	DeleteManagedUTXO(ctx context.Context, outpoint []byte) error
 
+	// UpdateUTXOLease leases a managed UTXO identified by the passed
+	// serialized outpoint.
+	UpdateUTXOLease(ctx context.Context,
+		arg sqlc.UpdateUTXOLeaseParams) error
+
+	// DeleteUTXOLease deletes the lease of a managed UTXO identified by
+	// the passed serialized outpoint.
+	DeleteUTXOLease(ctx context.Context, outpoint []byte) error
+
+	// DeleteExpiredUTXOLeases deletes all managed UTXO leases that have
+	// expired before the given time.
+	DeleteExpiredUTXOLeases(ctx context.Context, now sql.NullTime) error
+
 	// ConfirmChainAnchorTx marks a new anchor transaction that was
 	// previously unconfirmed as confirmed.
 	ConfirmChainAnchorTx(ctx context.Context, arg AnchorTxConf) error
@@ -233,6 +277,11 @@ type ActiveAssetsStore interface {
 		query sqlc.QueryAssetTransfersParams) ([]AssetTransferRow,
 		error)
 
+	// SetTransferOutputProofDeliveryStatus updates the proof delivery
+	// status of a transfer output.
+	SetTransferOutputProofDeliveryStatus(ctx context.Context,
+		arg ProofDeliveryStatusParams) error
+
 	// DeleteAssetWitnesses deletes the witnesses on disk associated with a
 	// given asset ID.
 	DeleteAssetWitnesses(ctx context.Context, assetID int32) error
@@ -260,6 +309,14 @@ type ActiveAssetsStore interface {
 	// the passed params.
 	ReAnchorPassiveAssets(ctx context.Context, arg ReAnchorParams) error
 
+	// InsertBurn inserts a new row that records an asset burn of a
+	// transfer.
+	InsertBurn(ctx context.Context, arg NewAssetBurn) (int32, error)
+
+	// QueryBurns returns the asset burns of all transfers, optionally
+	// filtered by asset ID.
+	QueryBurns(ctx context.Context, assetID []byte) ([]AssetBurnRow, error)
+
 	// FetchAssetMetaByHash fetches the asset meta for a given meta hash.
 	//
 	// TODO(roasbeef): split into MetaStore?
@@ -269,6 +326,35 @@ type ActiveAssetsStore interface {
 	// FetchAssetMetaForAsset fetches the asset meta for a given asset.
 	FetchAssetMetaForAsset(ctx context.Context,
 		assetID []byte) (sqlc.FetchAssetMetaForAssetRow, error)
+
+	// FetchGroupTranches fetches the genesis, group key and revealed meta
+	// of all tranches issued into the asset group with the given key.
+	FetchGroupTranches(ctx context.Context,
+		groupKey []byte) ([]sqlc.FetchGroupTranchesRow, error)
+
+	// FetchAnchorTxs fetches the confirmed anchor transactions of proofs
+	// and transfers that were confirmed at or above a given height.
+	FetchAnchorTxs(ctx context.Context,
+		arg AnchorTxQuery) ([]ChainTx, error)
+
+	// FetchAnchorTxProofs fetches the proof files of all assets anchored
+	// in the transaction with the given DB ID.
+	FetchAnchorTxProofs(ctx context.Context,
+		txnID int32) ([]AnchorTxProof, error)
+
+	// SetAnchorTxProofsInvalidated sets the invalidation of the proofs of
+	// all assets anchored in a transaction.
+	SetAnchorTxProofsInvalidated(ctx context.Context,
+		arg ProofInvalidation) error
+
+	// SetAnchorTxTransfersInvalidated sets the invalidation of all
+	// transfers anchored in a transaction.
+	SetAnchorTxTransfersInvalidated(ctx context.Context,
+		arg TransferInvalidation) error
+
+	// RebindAssetProof replaces the proof file of an asset and clears its
+	// invalidation.
+	RebindAssetProof(ctx context.Context, arg ProofRebind) error
 }
 
 type InsertRecvProofTxAttemptParams = sqlc.InsertReceiverProofTransferAttemptParams
@@ -354,6 +440,11 @@ type ChainAsset struct {
 	// AnchorTapscriptSibling hash is equal to the Taproot root hash of the
 	// anchor output.
 	AnchorTapscriptSibling []byte
+
+	// DecimalDisplay is the number of decimal places wallets should use
+	// when displaying amounts of the asset, as committed to in the meta
+	// data of its genesis.
+	DecimalDisplay uint32
 }
 
 // ManagedUTXO holds information about a given UTXO we manage.
@@ -552,6 +643,12 @@ func dbAssetsToChainAssets(dbAssets []ConfirmedAsset,
 			if err != nil {
 				return nil, err
 			}
+			groupWitness, err := parseGroupWitness(
+				sprout.GroupWitness,
+			)
+			if err != nil {
+				return nil, err
+			}
 
 			groupKey = &asset.GroupKey{
 				RawKey: keychain.KeyDescriptor{
@@ -565,8 +662,10 @@ func dbAssetsToChainAssets(dbAssets []ConfirmedAsset,
 						),
 					},
 				},
-				GroupPubKey: *tweakedGroupKey,
-				Sig:         *groupSig,
+				GroupPubKey:   *tweakedGroupKey,
+				Sig:           *groupSig,
+				TapscriptRoot: sprout.GroupTapscriptRoot,
+				Witness:       groupWitness,
 			}
 		}
 
@@ -627,6 +726,9 @@ func dbAssetsToChainAssets(dbAssets []ConfirmedAsset,
 				"%v", err)
 		}
 
+		assetSprout.NonDivisible = sprout.NonDivisible
+		assetSprout.Edition = uint64(sprout.Edition)
+
 		// We cannot use 0 as the amount when creating a new asset with
 		// the New function above. But if this is a tombstone asset, we
 		// actually have to set the amount to 0.
@@ -713,6 +815,9 @@ func dbAssetsToChainAssets(dbAssets []ConfirmedAsset,
 			AnchorInternalKey:      anchorInternalKey,
 			AnchorMerkleRoot:       sprout.AnchorMerkleRoot,
 			AnchorTapscriptSibling: sprout.AnchorTapscriptSibling,
+			DecimalDisplay: extractSqlInt32[uint32](
+				sprout.DecimalDisplay,
+			),
 		}
 	}
 
@@ -738,6 +843,21 @@ func constraintsToDbFilter(query *AssetQueryFilters) QueryAssetFilters {
 			groupKey := query.GroupKey.SerializeCompressed()
 			assetFilter.KeyGroupFilter = groupKey
 		}
+		if query.ScriptKey != nil {
+			scriptKey := query.ScriptKey.SerializeCompressed()
+			assetFilter.TweakedScriptKey = scriptKey
+		}
+		if query.ScriptKeyType != ScriptKeyTypeAny {
+			assetFilter.ScriptKeyType = sqlInt32(
+				query.ScriptKeyType,
+			)
+		}
+		if query.MaxAnchorHeight != 0 {
+			assetFilter.MaxAnchorHeight = sqlInt32(
+				query.MaxAnchorHeight,
+			)
+		}
+		assetFilter.SortByAmount = query.SortByAmount
 		// TODO(roasbeef): only want to allow asset ID or other and not
 		// both?
 	}
@@ -802,10 +922,43 @@ func fetchAssetsWithWitness(ctx context.Context, q ActiveAssetsStore,
 	return dbAssets, assetWitnesses, nil
 }
 
+// ScriptKeyType is the type of script key an asset can be filtered by.
+type ScriptKeyType uint8
+
+const (
+	// ScriptKeyTypeAny matches assets with any type of script key.
+	ScriptKeyTypeAny ScriptKeyType = 0
+
+	// ScriptKeyTypeBip86 matches assets with a BIP-0086 script key, which
+	// doesn't commit to a tapscript tree.
+	ScriptKeyTypeBip86 ScriptKeyType = 1
+
+	// ScriptKeyTypeScriptPath matches assets with a script key that
+	// commits to a tapscript tree.
+	ScriptKeyTypeScriptPath ScriptKeyType = 2
+)
+
 // AssetQueryFilters is a wrapper struct over the CommitmentConstraints struct
 // which lets us filter the results of the set of assets returned.
 type AssetQueryFilters struct {
 	tapfreighter.CommitmentConstraints
+
+	// ScriptKey, if set, only selects the assets with this script key.
+	ScriptKey *btcec.PublicKey
+
+	// ScriptKeyType only selects the assets with this type of script key.
+	ScriptKeyType ScriptKeyType
+
+	// AnchorPoint, if set, only selects the assets anchored at this
+	// outpoint.
+	AnchorPoint *wire.OutPoint
+
+	// SpentOnly only selects assets that were already spent.
+	SpentOnly bool
+
+	// SortByAmount sorts the assets by their amount instead of the order
+	// they were created in.
+	SortByAmount bool
 }
 
 // QueryBalancesByAsset queries the balances for assets or alternatively
@@ -964,6 +1117,19 @@ func (a *AssetStore) FetchGroupedAssets(ctx context.Context) (
 func (a *AssetStore) FetchAllAssets(ctx context.Context, includeSpent bool,
 	query *AssetQueryFilters) ([]*ChainAsset, error) {
 
+	assets, _, err := a.FetchAssetsPage(
+		ctx, includeSpent, query, PageParams{},
+	)
+	return assets, err
+}
+
+// FetchAssetsPage fetches a single page of the confirmed assets stored on disk
+// that match the given query. Along with the assets, the cursor of the next
+// page is returned, which is nil if there are no more pages.
+func (a *AssetStore) FetchAssetsPage(ctx context.Context, includeSpent bool,
+	query *AssetQueryFilters, page PageParams) ([]*ChainAsset, []byte,
+	error) {
+
 	var (
 		dbAssets       []ConfirmedAsset
 		assetWitnesses map[int32][]AssetWitness
@@ -977,10 +1143,36 @@ func (a *AssetStore) FetchAllAssets(ctx context.Context, includeSpent bool,
 	// By default, the spent boolean is null, which means we'll fetch all
 	// assets. Only if we should exclude spent assets, we'll set the spent
 	// boolean to false.
-	if !includeSpent {
+	switch {
+	case query != nil && query.SpentOnly:
+		assetFilter.Spent = sqlBool(true)
+
+	case !includeSpent:
 		assetFilter.Spent = sqlBool(false)
 	}
 
+	if query != nil && query.AnchorPoint != nil {
+		anchorPoint, err := encodeOutpoint(*query.AnchorPoint)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to encode "+
+				"outpoint: %w", err)
+		}
+		assetFilter.AnchorPoint = anchorPoint
+	}
+
+	// Finally, we'll add the position and size of the requested page.
+	if page.Cursor != nil {
+		amount, assetPrimaryKey, err := decodeAssetCursor(page.Cursor)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		assetFilter.CursorAmount = sqlInt64(amount)
+		assetFilter.CursorID = sqlInt32(assetPrimaryKey)
+	}
+	assetFilter.SortDescending = page.Descending
+	assetFilter.NumLimit = page.numLimit()
+
 	// With the query constructed, we can now fetch the assets along w/
 	// their witness information.
 	readOpts := NewAssetStoreReadTx()
@@ -992,10 +1184,54 @@ func (a *AssetStore) FetchAllAssets(ctx context.Context, includeSpent bool,
 		return err
 	})
 	if dbErr != nil {
-		return nil, dbErr
+		return nil, nil, dbErr
+	}
+
+	chainAssets, err := dbAssetsToChainAssets(dbAssets, assetWitnesses)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var nextCursor []byte
+	if page.isFull(len(dbAssets)) {
+		lastAsset := dbAssets[len(dbAssets)-1]
+		nextCursor = encodeAssetCursor(
+			lastAsset.Amount, lastAsset.AssetPrimaryKey,
+		)
+	}
+
+	return chainAssets, nextCursor, nil
+}
+
+// FetchEditions fetches the unspent editions of the collectible with the
+// given asset ID, sorted by their edition number. Assets of the given ID that
+// don't carry an edition number are not returned.
+func (a *AssetStore) FetchEditions(ctx context.Context,
+	assetID asset.ID) ([]*ChainAsset, error) {
+
+	assets, err := a.FetchAllAssets(ctx, false, &AssetQueryFilters{
+		CommitmentConstraints: tapfreighter.CommitmentConstraints{
+			AssetID: &assetID,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	editions := make([]*ChainAsset, 0, len(assets))
+	for _, chainAsset := range assets {
+		if chainAsset.Edition == 0 {
+			continue
+		}
+
+		editions = append(editions, chainAsset)
 	}
 
-	return dbAssetsToChainAssets(dbAssets, assetWitnesses)
+	sort.Slice(editions, func(i, j int) bool {
+		return editions[i].Edition < editions[j].Edition
+	})
+
+	return editions, nil
 }
 
 // FetchManagedUTXOs fetches all UTXOs we manage.
@@ -1216,6 +1452,40 @@ func (a *AssetStore) insertAssetWitnesses(ctx context.Context,
 // information associated with the annotated proofs. This will result in a new
 // asset inserted on disk, with all dependencies such as the asset witnesses
 // inserted along the way.
+// encodeAltLeaves encodes the given set of alt leaves to be stored with a
+// managed UTXO. If there are no alt leaves, nil is returned.
+func encodeAltLeaves(leaves []*commitment.AltLeaf) ([]byte, error) {
+	if len(leaves) == 0 {
+		return nil, nil
+	}
+
+	var b bytes.Buffer
+	err := commitment.AltLeavesEncoder(&b, &leaves, &[8]byte{})
+	if err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// decodeAltLeaves decodes the alt leaves stored with a managed UTXO.
+func decodeAltLeaves(leavesBytes []byte) ([]*commitment.AltLeaf, error) {
+	if len(leavesBytes) == 0 {
+		return nil, nil
+	}
+
+	var leaves []*commitment.AltLeaf
+	err := commitment.AltLeavesDecoder(
+		bytes.NewReader(leavesBytes), &leaves, &[8]byte{},
+		uint64(len(leavesBytes)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return leaves, nil
+}
+
 func (a *AssetStore) importAssetFromProof(ctx context.Context,
 	db ActiveAssetsStore, proof *proof.AnnotatedProof) error {
 
@@ -1269,6 +1539,13 @@ func (a *AssetStore) importAssetFromProof(ctx context.Context,
 			err)
 	}
 
+	// Any alt leaves of the output are stored with it, as we'll need them
+	// to re-create the full commitment when spending it.
+	altLeaves, err := encodeAltLeaves(proof.AltLeaves)
+	if err != nil {
+		return fmt.Errorf("unable to encode alt leaves: %w", err)
+	}
+
 	// Next, we'll insert the managed UTXO that points to the output in our
 	// control for the specified asset.
 	merkleRoot := proof.ScriptRoot.TapscriptRoot(siblingHash)
@@ -1281,6 +1558,7 @@ func (a *AssetStore) importAssetFromProof(ctx context.Context,
 		MerkleRoot:       merkleRoot[:],
 		TapscriptSibling: siblingBytes,
 		TxnID:            chainTXID,
+		AltLeaves:        altLeaves,
 	})
 	if err != nil {
 		return fmt.Errorf("unable to insert managed utxo: %w", err)
@@ -1463,13 +1741,115 @@ func (a *AssetStore) ListEligibleCoins(
 	// First, we'll map the commitment constraints to our database query
 	// filters.
 	assetFilter := constraintsToDbFilter(&AssetQueryFilters{
-		constraints,
+		CommitmentConstraints: constraints,
 	})
 
 	// We only want to select unspent commitments.
 	assetFilter.Spent = sqlBool(false)
 
-	return a.queryCommitments(ctx, assetFilter)
+	// Unless requested otherwise, we also exclude all commitments that
+	// are anchored in a UTXO that is currently leased.
+	if !constraints.IncludeLeased {
+		assetFilter.Now = sql.NullTime{
+			Time:  time.Now().UTC(),
+			Valid: true,
+		}
+	}
+
+	commitments, err := a.queryCommitments(ctx, assetFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	if constraints.Edition == 0 {
+		return commitments, nil
+	}
+
+	// A specific edition of a collectible was requested, so we'll only
+	// return the commitment that holds that edition.
+	for _, c := range commitments {
+		if c.Asset.Edition == constraints.Edition {
+			return []*tapfreighter.AnchoredCommitment{c}, nil
+		}
+	}
+
+	return nil, tapfreighter.ErrMatchingAssetsNotFound
+}
+
+// LeaseCoins leases the UTXOs identified by the given outpoints for the given
+// lease owner until the given expiry. Commitments anchored in a leased UTXO
+// aren't returned by ListEligibleCoins until the lease is released or has
+// expired.
+func (a *AssetStore) LeaseCoins(ctx context.Context, leaseOwner [32]byte,
+	expiry time.Time, utxoOutpoints ...wire.OutPoint) error {
+
+	var writeTxOpts AssetStoreTxOptions
+	return a.db.ExecTx(ctx, &writeTxOpts, func(q ActiveAssetsStore) error {
+		return leaseUTXOs(ctx, q, leaseOwner, expiry, utxoOutpoints)
+	})
+}
+
+// leaseUTXOs leases the UTXOs identified by the given outpoints for the given
+// lease owner until the given expiry.
+func leaseUTXOs(ctx context.Context, q ActiveAssetsStore, leaseOwner [32]byte,
+	expiry time.Time, utxoOutpoints []wire.OutPoint) error {
+
+	for _, utxoOutpoint := range utxoOutpoints {
+		outpoint, err := encodeOutpoint(utxoOutpoint)
+		if err != nil {
+			return err
+		}
+
+		err = q.UpdateUTXOLease(ctx, sqlc.UpdateUTXOLeaseParams{
+			LeaseOwner: leaseOwner[:],
+			LeaseExpiry: sql.NullTime{
+				Time:  expiry.UTC(),
+				Valid: true,
+			},
+			Outpoint: outpoint,
+		})
+		if err != nil {
+			return fmt.Errorf("unable to lease UTXO %v: %w",
+				utxoOutpoint, err)
+		}
+	}
+
+	return nil
+}
+
+// ReleaseCoins releases the leases of the UTXOs identified by the given
+// outpoints.
+func (a *AssetStore) ReleaseCoins(ctx context.Context,
+	utxoOutpoints ...wire.OutPoint) error {
+
+	var writeTxOpts AssetStoreTxOptions
+	return a.db.ExecTx(ctx, &writeTxOpts, func(q ActiveAssetsStore) error {
+		for _, utxoOutpoint := range utxoOutpoints {
+			outpoint, err := encodeOutpoint(utxoOutpoint)
+			if err != nil {
+				return err
+			}
+
+			err = q.DeleteUTXOLease(ctx, outpoint)
+			if err != nil {
+				return fmt.Errorf("unable to release UTXO "+
+					"%v: %w", utxoOutpoint, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// DeleteExpiredLeases deletes all leases that have expired.
+func (a *AssetStore) DeleteExpiredLeases(ctx context.Context) error {
+	var writeTxOpts AssetStoreTxOptions
+	return a.db.ExecTx(ctx, &writeTxOpts, func(q ActiveAssetsStore) error {
+		return q.DeleteExpiredUTXOLeases(ctx, sql.NullTime{
+			Time:  time.Now().UTC(),
+			Valid: true,
+		})
+	})
 }
 
 // queryCommitments queries the database for commitments matching the passed
@@ -1566,11 +1946,26 @@ func (a *AssetStore) queryCommitments(ctx context.Context,
 		assets := chanutils.Map(anchoredAssets, fetchAsset)
 
 		tapCommitment, err := commitment.FromAssets(assets...)
-
 		if err != nil {
 			return nil, err
 		}
 
+		// The commitment of the anchor output also includes any alt
+		// leaves, otherwise it wouldn't match the output's key.
+		altLeaves, err := decodeAltLeaves(
+			anchorPoints[anchorPoint].AltLeaves,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode alt "+
+				"leaves: %w", err)
+		}
+		if len(altLeaves) > 0 {
+			err := tapCommitment.UpsertAltLeaves(altLeaves...)
+			if err != nil {
+				return nil, err
+			}
+		}
+
 		anchorPointToCommitment[anchorPoint] = tapCommitment
 	}
 
@@ -1622,9 +2017,12 @@ func (a *AssetStore) queryCommitments(ctx context.Context,
 
 // LogPendingParcel marks an outbound parcel as pending on disk. This commits
 // the set of changes to disk (the pending inputs and outputs) but doesn't mark
-// the batched spend as being finalized.
+// the batched spend as being finalized. The anchor UTXOs of the inputs are
+// leased with the given lease owner and expiry, so they aren't selected for
+// another transfer until the parcel confirms.
 func (a *AssetStore) LogPendingParcel(ctx context.Context,
-	spend *tapfreighter.OutboundParcel) error {
+	spend *tapfreighter.OutboundParcel, finalLeaseOwner [32]byte,
+	finalLeaseExpiry time.Time) error {
 
 	// Before we enter the DB transaction below, we'll use this space to
 	// encode a few values outside the transaction closure.
@@ -1663,6 +2061,7 @@ func (a *AssetStore) LogPendingParcel(ctx context.Context,
 		}
 
 		// Next, we'll insert the inputs to this transfer.
+		inputAnchors := make([]wire.OutPoint, 0, len(spend.Inputs))
 		for idx := range spend.Inputs {
 			err := insertAssetTransferInput(
 				ctx, q, transferID, spend.Inputs[idx],
@@ -1671,6 +2070,19 @@ func (a *AssetStore) LogPendingParcel(ctx context.Context,
 				return fmt.Errorf("unable to insert asset "+
 					"transfer input: %w", err)
 			}
+
+			inputAnchors = append(
+				inputAnchors, spend.Inputs[idx].OutPoint,
+			)
+		}
+
+		// The inputs are spent by the anchor transaction, so we keep
+		// them leased until the transfer confirms.
+		err = leaseUTXOs(
+			ctx, q, finalLeaseOwner, finalLeaseExpiry, inputAnchors,
+		)
+		if err != nil {
+			return fmt.Errorf("unable to lease inputs: %w", err)
 		}
 
 		// And then finally the outputs.
@@ -1685,10 +2097,52 @@ func (a *AssetStore) LogPendingParcel(ctx context.Context,
 			}
 		}
 
+		// Burns are tracked separately as well, so they can be listed
+		// without going through all transfers.
+		err = insertAssetBurns(ctx, q, transferID, spend)
+		if err != nil {
+			return fmt.Errorf("unable to insert asset burns: %w",
+				err)
+		}
+
 		return nil
 	})
 }
 
+// insertAssetBurns inserts a burn record for each output of the given parcel
+// that assigns the transferred assets a burn script key.
+func insertAssetBurns(ctx context.Context, q ActiveAssetsStore,
+	transferID int32, spend *tapfreighter.OutboundParcel) error {
+
+	for idx := range spend.Outputs {
+		out := spend.Outputs[idx]
+
+		scriptKey := out.ScriptKey.PubKey
+		if len(out.WitnessData) == 0 ||
+			!asset.IsBurnKey(scriptKey, out.WitnessData[0]) {
+
+			continue
+		}
+
+		// A transfer can move multiple asset IDs, so we take the ID
+		// from the input the burn key commits to.
+		burnPrevID, _ := asset.SpentPrevID(out.WitnessData[0])
+		assetID := burnPrevID.ID
+
+		_, err := q.InsertBurn(ctx, NewAssetBurn{
+			TransferID: transferID,
+			AssetID:    assetID[:],
+			ScriptKey:  scriptKey.SerializeCompressed(),
+			Amount:     int64(out.Amount),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // insertAssetTransferInput inserts a new asset transfer input into the DB.
 func insertAssetTransferInput(ctx context.Context, q ActiveAssetsStore,
 	transferID int32, input tapfreighter.TransferInput) error {
@@ -1776,6 +2230,11 @@ func insertAssetTransferOutput(ctx context.Context, q ActiveAssetsStore,
 		return fmt.Errorf("unable to upsert internal key: %w", err)
 	}
 
+	altLeaves, err := encodeAltLeaves(anchor.AltLeaves)
+	if err != nil {
+		return fmt.Errorf("unable to encode alt leaves: %w", err)
+	}
+
 	// Now that the chain transaction has been inserted, we can now insert
 	// a _new_ managed UTXO which houses the information related to the new
 	// anchor point of the transaction.
@@ -1787,6 +2246,7 @@ func insertAssetTransferOutput(ctx context.Context, q ActiveAssetsStore,
 		MerkleRoot:       anchor.MerkleRoot,
 		TapscriptSibling: anchor.TapscriptSibling,
 		TxnID:            txnID,
+		AltLeaves:        altLeaves,
 	})
 	if err != nil {
 		return fmt.Errorf("unable to insert new managed utxo: %w", err)
@@ -1853,6 +2313,8 @@ func insertAssetTransferOutput(ctx context.Context, q ActiveAssetsStore,
 		ProofSuffix:         output.ProofSuffix,
 		NumPassiveAssets:    int32(output.Anchor.NumPassiveAssets),
 		OutputType:          int16(output.Type),
+		ProofDeliveryStatus: int16(output.ProofDeliveryStatus),
+		ProofCourierAddr:    encodeCourierAddr(output.ProofCourierAddr),
 	}
 
 	// There might not have been a split, so we can't rely on the split root
@@ -1923,6 +2385,11 @@ func fetchAssetTransferOutputs(ctx context.Context, q ActiveAssetsStore,
 		var splitRootHash mssmt.NodeHash
 		copy(splitRootHash[:], dbOut.SplitCommitmentRootHash)
 
+		courierAddr, err := decodeCourierAddr(dbOut.ProofCourierAddr)
+		if err != nil {
+			return nil, err
+		}
+
 		var witnessData []asset.Witness
 		err = asset.WitnessDecoder(
 			bytes.NewReader(dbOut.SerializedWitnesses),
@@ -1974,8 +2441,12 @@ func fetchAssetTransferOutputs(ctx context.Context, q ActiveAssetsStore,
 				splitRootHash,
 				uint64(dbOut.SplitCommitmentRootValue.Int64),
 			),
-			ProofSuffix: dbOut.ProofSuffix,
-			Type:        tappsbt.VOutputType(dbOut.OutputType),
+			ProofSuffix:      dbOut.ProofSuffix,
+			Type:             tappsbt.VOutputType(dbOut.OutputType),
+			ProofCourierAddr: courierAddr,
+			ProofDeliveryStatus: tapfreighter.ProofDeliveryStatus(
+				dbOut.ProofDeliveryStatus,
+			),
 		}
 
 		err = readOutPoint(
@@ -2111,6 +2582,8 @@ func (a *AssetStore) ConfirmParcelDelivery(ctx context.Context,
 		localProofKeys []asset.SerializedKey
 	)
 	err := a.db.ExecTx(ctx, &writeTxOpts, func(q ActiveAssetsStore) error {
+		localProofKeys = nil
+
 		// First, we'll fetch the asset transfer based on its outpoint
 		// bytes, so we can apply the delta it describes.
 		assetTransfers, err := q.QueryAssetTransfers(ctx, TransferQuery{
@@ -2131,11 +2604,14 @@ func (a *AssetStore) ConfirmParcelDelivery(ctx context.Context,
 		}
 
 		// We'll keep around the IDs of the assets that we set to being
-		// spent. We'll need one of them as our template to create the
-		// new assets.
-		spentAssetIDs := make([]int32, len(inputs))
+		// spent, by their genesis asset ID. We'll need one of them as
+		// our template to create the new assets.
+		spentAssetIDs := make(map[asset.ID]int32, len(inputs))
 		for idx := range inputs {
-			spentAssetIDs[idx], err = q.SetAssetSpent(
+			var genAssetID asset.ID
+			copy(genAssetID[:], inputs[idx].AssetID)
+
+			spentAssetIDs[genAssetID], err = q.SetAssetSpent(
 				ctx, SetAssetSpentParams{
 					ScriptKey:  inputs[idx].ScriptKey,
 					GenAssetID: inputs[idx].AssetID,
@@ -2145,6 +2621,14 @@ func (a *AssetStore) ConfirmParcelDelivery(ctx context.Context,
 				return fmt.Errorf("unable to set asset spent: "+
 					"%w", err)
 			}
+
+			// The input is now spent on chain, so there's no need
+			// to keep it leased anymore.
+			err = q.DeleteUTXOLease(ctx, inputs[idx].AnchorPoint)
+			if err != nil {
+				return fmt.Errorf("unable to delete input "+
+					"lease: %w", err)
+			}
 		}
 
 		// Now is the time to fetch our outputs and create new assets
@@ -2166,26 +2650,63 @@ func (a *AssetStore) ConfirmParcelDelivery(ctx context.Context,
 					tappsbt.TypePassiveAssetsOnly,
 				)
 
+			// Burned assets are recorded like tombstones: we keep
+			// them on disk for reference, but they're marked as
+			// spent so they never count towards any balance.
+			var witnessData []asset.Witness
+			err = asset.WitnessDecoder(
+				bytes.NewReader(out.SerializedWitnesses),
+				&witnessData, &[8]byte{},
+				uint64(len(out.SerializedWitnesses)),
+			)
+			if err != nil {
+				return fmt.Errorf("unable to decode "+
+					"witness: %w", err)
+			}
+			scriptPubKey, err := btcec.ParsePubKey(
+				out.ScriptKeyBytes,
+			)
+			if err != nil {
+				return fmt.Errorf("unable to parse script "+
+					"key: %w", err)
+			}
+			isBurn := len(witnessData) > 0 && asset.IsBurnKey(
+				scriptPubKey, witnessData[0],
+			)
+
 			// If this is an outbound transfer (meaning that our
 			// node doesn't control the script key), we don't create
 			// an asset entry in the DB. The transfer will be the
 			// only reference to the asset leaving the node. The
 			// same goes for outputs that are only used to anchor
 			// passive assets, which are handled separately.
-			if !isTombstone && !out.ScriptKeyLocal {
+			if !isTombstone && !isBurn && !out.ScriptKeyLocal {
 				continue
 			}
 
-			// Since we define that a transfer can only move assets
-			// within the same asset ID, we can take any of the
-			// inputs as a template for the new asset, since the
-			// genesis and group key will be the same. We'll
-			// overwrite all other fields.
+			var scriptKey asset.SerializedKey
+			copy(scriptKey[:], out.ScriptKeyBytes)
+			receiverProof, ok := conf.FinalProofs[scriptKey]
+			if !ok {
+				return fmt.Errorf("no proof found for output "+
+					"with script key %x",
+					out.ScriptKeyBytes)
+			}
+
+			// A transfer can move multiple asset IDs, but each
+			// output only carries a single one. So we can take any
+			// of the inputs of the same asset ID as a template for
+			// the new asset, since the genesis and group key will
+			// be the same. We'll overwrite all other fields.
 			//
 			// TODO(guggero): This will need an update once we want
 			// to support full lock_time and relative_lock_time
 			// support.
-			templateID := spentAssetIDs[0]
+			templateID, ok := spentAssetIDs[*receiverProof.AssetID]
+			if !ok {
+				return fmt.Errorf("no input found for output "+
+					"of asset %v", *receiverProof.AssetID)
+			}
 			params := ApplyPendingOutput{
 				ScriptKeyID: out.ScriptKeyID,
 				AnchorUtxoID: sqlInt32(
@@ -2195,7 +2716,7 @@ func (a *AssetStore) ConfirmParcelDelivery(ctx context.Context,
 				SplitCommitmentRootHash:  out.SplitCommitmentRootHash,
 				SplitCommitmentRootValue: out.SplitCommitmentRootValue,
 				SpentAssetID:             templateID,
-				Spent:                    isTombstone,
+				Spent:                    isTombstone || isBurn,
 			}
 			newAssetID, err := q.ApplyPendingOutput(ctx, params)
 			if err != nil {
@@ -2205,16 +2726,6 @@ func (a *AssetStore) ConfirmParcelDelivery(ctx context.Context,
 
 			// With the old witnesses removed, we'll insert the new
 			// set on disk.
-			var witnessData []asset.Witness
-			err = asset.WitnessDecoder(
-				bytes.NewReader(out.SerializedWitnesses),
-				&witnessData, &[8]byte{},
-				uint64(len(out.SerializedWitnesses)),
-			)
-			if err != nil {
-				return fmt.Errorf("unable to decode "+
-					"witness: %w", err)
-			}
 			err = a.insertAssetWitnesses(
 				ctx, q, newAssetID, witnessData,
 			)
@@ -2223,14 +2734,6 @@ func (a *AssetStore) ConfirmParcelDelivery(ctx context.Context,
 					"witnesses: %w", err)
 			}
 
-			var scriptKey asset.SerializedKey
-			copy(scriptKey[:], out.ScriptKeyBytes)
-			receiverProof, ok := conf.FinalProofs[scriptKey]
-			if !ok {
-				return fmt.Errorf("no proof found for output "+
-					"with script key %x",
-					out.ScriptKeyBytes)
-			}
 			localProofKeys = append(localProofKeys, scriptKey)
 
 			// Now we can update the asset proof for the sender for
@@ -2392,19 +2895,117 @@ func (a *AssetStore) PendingParcels(
 func (a *AssetStore) QueryParcels(ctx context.Context,
 	pending bool) ([]*tapfreighter.OutboundParcel, error) {
 
-	var transfers []*tapfreighter.OutboundParcel
+	// If we want every unconfirmed transfer, then we only pass in the
+	// UnconfOnly field.
+	parcels, _, err := a.queryParcels(ctx, TransferQuery{
+		UnconfOnly: pending,
+	}, PageParams{})
+	return parcels, err
+}
+
+// TransferFilters lets us filter the set of transfers returned.
+type TransferFilters struct {
+	// AssetID, if set, only selects the transfers that spent at least one
+	// input of this asset ID.
+	AssetID *asset.ID
+
+	// AnchorTxHash, if set, only selects the transfers with this anchor
+	// transaction.
+	AnchorTxHash *chainhash.Hash
+
+	// PendingOnly only selects the transfers that aren't confirmed yet.
+	PendingOnly bool
+}
+
+// QueryTransfers returns a single page of the transfers that match the given
+// filters. Along with the transfers, the cursor of the next page is returned,
+// which is nil if there are no more pages.
+func (a *AssetStore) QueryTransfers(ctx context.Context,
+	filters TransferFilters,
+	page PageParams) ([]*tapfreighter.OutboundParcel, []byte, error) {
+
+	query := TransferQuery{
+		UnconfOnly: filters.PendingOnly,
+	}
+	if filters.AssetID != nil {
+		query.AssetIDFilter = filters.AssetID[:]
+	}
+	if filters.AnchorTxHash != nil {
+		query.AnchorTxHash = filters.AnchorTxHash[:]
+	}
+
+	return a.queryParcels(ctx, query, page)
+}
+
+// PendingProofDeliveries returns the set of confirmed parcels that still have
+// at least one output with a pending proof delivery.
+func (a *AssetStore) PendingProofDeliveries(
+	ctx context.Context) ([]*tapfreighter.OutboundParcel, error) {
+
+	parcels, _, err := a.queryParcels(ctx, TransferQuery{
+		PendingProofsOnly: true,
+	}, PageParams{})
+	return parcels, err
+}
+
+// ConfirmProofDelivery marks the proof of the transfer output with the given
+// anchor outpoint and script key as delivered to the receiver.
+func (a *AssetStore) ConfirmProofDelivery(ctx context.Context,
+	anchorPoint wire.OutPoint, scriptKey *btcec.PublicKey) error {
+
+	anchorPointBytes, err := encodeOutpoint(anchorPoint)
+	if err != nil {
+		return err
+	}
+
+	params := ProofDeliveryStatusParams{
+		SerializedAnchorOutpoint: anchorPointBytes,
+		ScriptKeyBytes:           scriptKey.SerializeCompressed(),
+		DeliveryStatus:           int16(tapfreighter.ProofDeliveryComplete),
+	}
+
+	var writeTxOpts AssetStoreTxOptions
+	return a.db.ExecTx(ctx, &writeTxOpts, func(q ActiveAssetsStore) error {
+		return q.SetTransferOutputProofDeliveryStatus(ctx, params)
+	})
+}
+
+// queryParcels returns a single page of the parcels that match the given
+// transfer query. Along with the parcels, the cursor of the next page is
+// returned, which is nil if there are no more pages.
+func (a *AssetStore) queryParcels(ctx context.Context, query TransferQuery,
+	page PageParams) ([]*tapfreighter.OutboundParcel, []byte, error) {
+
+	if page.Cursor != nil {
+		transferID, err := decodeTransferCursor(page.Cursor)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		query.CursorID = sqlInt32(transferID)
+	}
+	query.SortDescending = page.Descending
+	query.NumLimit = page.numLimit()
+
+	var (
+		transfers  []*tapfreighter.OutboundParcel
+		nextCursor []byte
+	)
 
 	readOpts := NewAssetStoreReadTx()
 	dbErr := a.db.ExecTx(ctx, &readOpts, func(q ActiveAssetsStore) error {
-		// If we want every unconfirmed transfer, then we only pass in
-		// the UnconfOnly field.
-		dbTransfers, err := q.QueryAssetTransfers(ctx, TransferQuery{
-			UnconfOnly: pending,
-		})
+		transfers = nil
+
+		dbTransfers, err := q.QueryAssetTransfers(ctx, query)
 		if err != nil {
 			return err
 		}
 
+		if page.isFull(len(dbTransfers)) {
+			lastTransfer := dbTransfers[len(dbTransfers)-1]
+			nextCursor = encodeTransferCursor(lastTransfer.ID)
+		}
+
 		for idx := range dbTransfers {
 			dbT := dbTransfers[idx]
 
@@ -2457,11 +3058,56 @@ func (a *AssetStore) QueryParcels(ctx context.Context,
 
 		return nil
 	})
+	if dbErr != nil {
+		return nil, nil, dbErr
+	}
+
+	return transfers, nextCursor, nil
+}
+
+// QueryBurns returns the asset burns that were logged as part of an outbound
+// parcel. If an asset ID is given, only the burns of that asset are returned.
+func (a *AssetStore) QueryBurns(ctx context.Context,
+	assetID *asset.ID) ([]*tapfreighter.AssetBurn, error) {
+
+	var assetIDBytes []byte
+	if assetID != nil {
+		assetIDBytes = assetID[:]
+	}
+
+	var burns []*tapfreighter.AssetBurn
+	readOpts := NewAssetStoreReadTx()
+	dbErr := a.db.ExecTx(ctx, &readOpts, func(q ActiveAssetsStore) error {
+		dbBurns, err := q.QueryBurns(ctx, assetIDBytes)
+		if err != nil {
+			return err
+		}
+
+		burns = make([]*tapfreighter.AssetBurn, 0, len(dbBurns))
+		for _, dbBurn := range dbBurns {
+			scriptKey, err := btcec.ParsePubKey(dbBurn.ScriptKey)
+			if err != nil {
+				return fmt.Errorf("unable to parse burn "+
+					"script key: %w", err)
+			}
+
+			burn := &tapfreighter.AssetBurn{
+				ScriptKey: scriptKey,
+				Amount:    uint64(dbBurn.Amount),
+			}
+			copy(burn.AnchorTxid[:], dbBurn.AnchorTxid)
+			copy(burn.AssetID[:], dbBurn.AssetID)
+
+			burns = append(burns, burn)
+		}
+
+		return nil
+	})
 	if dbErr != nil {
 		return nil, dbErr
 	}
 
-	return transfers, nil
+	return burns, nil
 }
 
 // ErrAssetMetaNotFound is returned when an asset meta is not found in the
@@ -2528,6 +3174,296 @@ func (a *AssetStore) FetchAssetMetaByHash(ctx context.Context,
 	return assetMeta, nil
 }
 
+// FetchGroupMetaHistory fetches all known tranches of the asset group with the
+// given key and verifies the history of metadata updates they carry. The
+// canonical display metadata of the group is the latest update in the
+// returned history.
+func (a *AssetStore) FetchGroupMetaHistory(ctx context.Context,
+	groupKey *btcec.PublicKey) (*proof.MetaHistory, error) {
+
+	var tranches []*proof.MetaTranche
+
+	readOpts := NewAssetStoreReadTx()
+	dbErr := a.db.ExecTx(ctx, &readOpts, func(q ActiveAssetsStore) error {
+		tranches = nil
+
+		groupKeyBytes := groupKey.SerializeCompressed()
+		dbTranches, err := q.FetchGroupTranches(ctx, groupKeyBytes)
+		if err != nil {
+			return err
+		}
+
+		tranches = make([]*proof.MetaTranche, 0, len(dbTranches))
+		for _, dbTranche := range dbTranches {
+			var genesisPrevOut wire.OutPoint
+			err := readOutPoint(
+				bytes.NewReader(dbTranche.PrevOut), 0, 0,
+				&genesisPrevOut,
+			)
+			if err != nil {
+				return fmt.Errorf("unable to read outpoint: "+
+					"%w", err)
+			}
+
+			gen := asset.Genesis{
+				FirstPrevOut: genesisPrevOut,
+				Tag:          dbTranche.AssetTag,
+				OutputIndex:  uint32(dbTranche.OutputIndex),
+				Type:         asset.Type(dbTranche.AssetType),
+			}
+			copy(gen.MetaHash[:], dbTranche.MetaHash)
+
+			trancheGroupKey, err := parseGroupKeyInfo(
+				groupKeyBytes, dbTranche.RawKey,
+				dbTranche.GenesisSig, dbTranche.WitnessStack,
+				dbTranche.TapscriptRoot, dbTranche.KeyFamily,
+				dbTranche.KeyIndex,
+			)
+			if err != nil {
+				return err
+			}
+
+			tranche := &proof.MetaTranche{
+				Genesis:  gen,
+				GroupKey: trancheGroupKey,
+			}
+
+			// We may not know the meta reveal of every tranche.
+			if dbTranche.MetaDataType.Valid {
+				tranche.MetaReveal = &proof.MetaReveal{
+					Type: proof.MetaType(
+						dbTranche.MetaDataType.Int16,
+					),
+					Data: dbTranche.MetaDataBlob,
+				}
+			}
+
+			tranches = append(tranches, tranche)
+		}
+
+		return nil
+	})
+	if dbErr != nil {
+		return nil, dbErr
+	}
+
+	return proof.NewMetaHistory(groupKey, tranches)
+}
+
+// FetchAnchorTxs returns the anchor transactions of all stored proofs and
+// transfers that were confirmed at or above the given height. If invalidated
+// is true, only the anchor transactions that were reorged out are returned,
+// along with the block they were confirmed in before the reorg. Otherwise,
+// only valid ones are returned.
+//
+// NOTE: This is part of the tapgarden.AnchorTxStore interface.
+func (a *AssetStore) FetchAnchorTxs(ctx context.Context, minHeight uint32,
+	invalidated bool) ([]*tapgarden.AnchorTx, error) {
+
+	var anchorTxs []*tapgarden.AnchorTx
+
+	readOpts := NewAssetStoreReadTx()
+	dbErr := a.db.ExecTx(ctx, &readOpts, func(q ActiveAssetsStore) error {
+		dbTxs, err := q.FetchAnchorTxs(ctx, AnchorTxQuery{
+			MinHeight:   sqlInt32(minHeight),
+			Invalidated: invalidated,
+		})
+		if err != nil {
+			return err
+		}
+
+		anchorTxs = make([]*tapgarden.AnchorTx, len(dbTxs))
+		for idx := range dbTxs {
+			dbTx := dbTxs[idx]
+
+			var tx wire.MsgTx
+			err := tx.Deserialize(bytes.NewReader(dbTx.RawTx))
+			if err != nil {
+				return fmt.Errorf("unable to decode anchor "+
+					"tx: %w", err)
+			}
+
+			blockHash, err := chainhash.NewHash(dbTx.BlockHash)
+			if err != nil {
+				return err
+			}
+
+			anchorTxs[idx] = &tapgarden.AnchorTx{
+				Tx:          &tx,
+				BlockHash:   *blockHash,
+				BlockHeight: uint32(dbTx.BlockHeight.Int32),
+				TxIndex:     uint32(dbTx.TxIndex.Int32),
+			}
+		}
+
+		return nil
+	})
+	if dbErr != nil {
+		return nil, fmt.Errorf("unable to fetch anchor txs: %w", dbErr)
+	}
+
+	return anchorTxs, nil
+}
+
+// InvalidateAnchorTx marks all proofs and transfers anchored in the given
+// transaction as invalidated, as its block was reorged out.
+//
+// NOTE: This is part of the tapgarden.AnchorTxStore interface.
+func (a *AssetStore) InvalidateAnchorTx(ctx context.Context,
+	txid chainhash.Hash) error {
+
+	var writeTxOpts AssetStoreTxOptions
+	return a.db.ExecTx(ctx, &writeTxOpts, func(q ActiveAssetsStore) error {
+		return setAnchorTxInvalidated(ctx, q, txid, true)
+	})
+}
+
+// setAnchorTxInvalidated sets the invalidation of all proofs and transfers
+// anchored in the given transaction.
+func setAnchorTxInvalidated(ctx context.Context, q ActiveAssetsStore,
+	txid chainhash.Hash, invalidated bool) error {
+
+	dbTx, err := q.FetchChainTx(ctx, txid[:])
+	if err != nil {
+		return fmt.Errorf("unable to fetch anchor tx: %w", err)
+	}
+
+	err = q.SetAnchorTxProofsInvalidated(ctx, ProofInvalidation{
+		Invalidated: invalidated,
+		TxnID:       dbTx.TxnID,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to update proofs: %w", err)
+	}
+
+	err = q.SetAnchorTxTransfersInvalidated(ctx, TransferInvalidation{
+		Invalidated: invalidated,
+		TxnID:       dbTx.TxnID,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to update transfers: %w", err)
+	}
+
+	return nil
+}
+
+// FetchAnchorTxProofs returns the proof files of all assets anchored in the
+// given transaction. Only the script key of the locator of each proof is set.
+//
+// NOTE: This is part of the tapgarden.AnchorTxStore interface.
+func (a *AssetStore) FetchAnchorTxProofs(ctx context.Context,
+	txid chainhash.Hash) ([]*proof.AnnotatedProof, error) {
+
+	var proofs []*proof.AnnotatedProof
+
+	readOpts := NewAssetStoreReadTx()
+	dbErr := a.db.ExecTx(ctx, &readOpts, func(q ActiveAssetsStore) error {
+		dbTx, err := q.FetchChainTx(ctx, txid[:])
+		if err != nil {
+			return fmt.Errorf("unable to fetch anchor tx: %w", err)
+		}
+
+		dbProofs, err := q.FetchAnchorTxProofs(ctx, dbTx.TxnID)
+		if err != nil {
+			return err
+		}
+
+		proofs = make([]*proof.AnnotatedProof, len(dbProofs))
+		for idx := range dbProofs {
+			dbProof := dbProofs[idx]
+
+			scriptKey, err := btcec.ParsePubKey(
+				dbProof.TweakedScriptKey,
+			)
+			if err != nil {
+				return err
+			}
+
+			proofs[idx] = &proof.AnnotatedProof{
+				Locator: proof.Locator{
+					ScriptKey: *scriptKey,
+				},
+				Blob: dbProof.ProofFile,
+			}
+		}
+
+		return nil
+	})
+	if dbErr != nil {
+		return nil, fmt.Errorf("unable to fetch anchor tx proofs: %w",
+			dbErr)
+	}
+
+	return proofs, nil
+}
+
+// RebindAnchorTx marks the given anchor transaction as confirmed in its new
+// block, replaces the proof files of the assets anchored in it with the given
+// ones and clears the invalidation of those proofs and all transfers anchored
+// in the transaction.
+//
+// NOTE: This is part of the tapgarden.AnchorTxStore interface.
+func (a *AssetStore) RebindAnchorTx(ctx context.Context,
+	anchorTx *tapgarden.AnchorTx, proofs []*proof.AnnotatedProof) error {
+
+	txid := anchorTx.Tx.TxHash()
+
+	proofsByKey := make(map[asset.SerializedKey]proof.Blob, len(proofs))
+	for _, p := range proofs {
+		proofsByKey[asset.ToSerialized(&p.ScriptKey)] = p.Blob
+	}
+
+	var writeTxOpts AssetStoreTxOptions
+	err := a.db.ExecTx(ctx, &writeTxOpts, func(q ActiveAssetsStore) error {
+		dbTx, err := q.FetchChainTx(ctx, txid[:])
+		if err != nil {
+			return fmt.Errorf("unable to fetch anchor tx: %w", err)
+		}
+
+		dbProofs, err := q.FetchAnchorTxProofs(ctx, dbTx.TxnID)
+		if err != nil {
+			return err
+		}
+
+		for _, dbProof := range dbProofs {
+			var scriptKey asset.SerializedKey
+			copy(scriptKey[:], dbProof.TweakedScriptKey)
+
+			proofFile, ok := proofsByKey[scriptKey]
+			if !ok {
+				return fmt.Errorf("no re-bound proof for "+
+					"script key %x", scriptKey[:])
+			}
+
+			err := q.RebindAssetProof(ctx, ProofRebind{
+				ProofFile: proofFile,
+				AssetID:   dbProof.AssetID,
+			})
+			if err != nil {
+				return fmt.Errorf("unable to update proof: %w",
+					err)
+			}
+		}
+
+		err = q.ConfirmChainAnchorTx(ctx, AnchorTxConf{
+			Txid:        txid[:],
+			BlockHash:   anchorTx.BlockHash[:],
+			BlockHeight: sqlInt32(anchorTx.BlockHeight),
+			TxIndex:     sqlInt32(anchorTx.TxIndex),
+		})
+		if err != nil {
+			return err
+		}
+
+		return setAnchorTxInvalidated(ctx, q, txid, false)
+	})
+	if err != nil {
+		return fmt.Errorf("unable to re-bind anchor tx: %w", err)
+	}
+
+	return nil
+}
+
 // A compile-time constraint to ensure that AssetStore meets the
 // proof.NotifyArchiver interface.
 var _ proof.NotifyArchiver = (*AssetStore)(nil)
@@ -2539,3 +3475,7 @@ var _ tapfreighter.CoinLister = (*AssetStore)(nil)
 // A compile-time constraint to ensure that AssetStore meets the
 // tapfreighter.ExportLog interface.
 var _ tapfreighter.ExportLog = (*AssetStore)(nil)
+
+// A compile-time constraint to ensure that AssetStore meets the
+// tapgarden.AnchorTxStore interface.
+var _ tapgarden.AnchorTxStore = (*AssetStore)(nil)
@@ -4,9 +4,12 @@ import (
 	"bytes"
 	"context"
 	"crypto/sha256"
+	"database/sql"
 	"math/rand"
+	"net/url"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
@@ -17,7 +20,10 @@ import (
 	"github.com/lightninglabs/taproot-assets/internal/test"
 	"github.com/lightninglabs/taproot-assets/mssmt"
 	"github.com/lightninglabs/taproot-assets/proof"
+	"github.com/lightninglabs/taproot-assets/tapdb/sqlc"
 	"github.com/lightninglabs/taproot-assets/tapfreighter"
+	"github.com/lightninglabs/taproot-assets/tapgarden"
+	"github.com/lightninglabs/taproot-assets/tappsbt"
 	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/stretchr/testify/require"
 )
@@ -38,6 +44,8 @@ type assetGenOptions struct {
 	genesisPoint wire.OutPoint
 
 	scriptKey asset.ScriptKey
+
+	edition uint64
 }
 
 func defaultAssetGenOpts(t *testing.T) *assetGenOptions {
@@ -97,6 +105,12 @@ func withScriptKey(k asset.ScriptKey) assetGenOpt {
 	}
 }
 
+func withEdition(edition uint64) assetGenOpt {
+	return func(opt *assetGenOptions) {
+		opt.edition = edition
+	}
+}
+
 func withNoGroupKey() assetGenOpt {
 	return func(opt *assetGenOptions) {
 		opt.noGroupKey = true
@@ -143,6 +157,7 @@ func randAsset(t *testing.T, genOpts ...assetGenOpt) *asset.Asset {
 		LockTime:         uint64(test.RandInt[int32]()),
 		RelativeLockTime: uint64(test.RandInt[int32]()),
 		ScriptKey:        opts.scriptKey,
+		Edition:          opts.edition,
 	}
 
 	// 50/50 chance that we'll actually have a group key. Or we'll always
@@ -218,17 +233,13 @@ func assertAssetEqual(t *testing.T, a, b *asset.Asset) {
 	}
 }
 
-// TestImportAssetProof tests that given a valid asset proof (mainly the final
-// snapshot information), we're able to properly import all the components on
-// disk, then retrieve the asset as if it were ours.
-func TestImportAssetProof(t *testing.T) {
-	t.Parallel()
+// importRandAssetProof imports the proof of a new random asset, along with its
+// keys and anchor transaction, into the given asset store.
+func importRandAssetProof(t *testing.T, assetStore *AssetStore,
+	db sqlc.Querier) *proof.AnnotatedProof {
 
-	// First, we'll create a new instance of the database.
-	_, assetStore, db := newAssetStore(t)
-
-	// Next, we'll make a new random asset that also has a few inputs with
-	// dummy witness information.
+	// We'll make a new random asset that also has a few inputs with dummy
+	// witness information.
 	testAsset := randAsset(t)
 
 	assetRoot, err := commitment.NewAssetCommitment(testAsset)
@@ -237,6 +248,14 @@ func TestImportAssetProof(t *testing.T) {
 	taprootAssetRoot, err := commitment.NewTapCommitment(assetRoot)
 	require.NoError(t, err)
 
+	// The anchor output also commits to an alt leaf, which needs to be
+	// stored to re-create the full commitment later on.
+	altLeaf := &commitment.AltLeaf{
+		Key:  test.RandHash(),
+		Data: test.RandBytes(32),
+	}
+	require.NoError(t, taprootAssetRoot.UpsertAltLeaves(altLeaf))
+
 	// With our asset created, we can now create the AnnotatedProof we use
 	// to import assets into the database.
 	var blockHash chainhash.Hash
@@ -265,12 +284,13 @@ func TestImportAssetProof(t *testing.T) {
 			Asset:             testAsset,
 			OutPoint:          anchorPoint,
 			AnchorBlockHash:   blockHash,
-			AnchorBlockHeight: test.RandInt[uint32](),
+			AnchorBlockHeight: uint32(rand.Int31()),
 			AnchorTxIndex:     test.RandInt[uint32](),
 			AnchorTx:          anchorTx,
 			OutputIndex:       0,
 			InternalKey:       test.RandPubKey(t),
 			ScriptRoot:        taprootAssetRoot,
+			AltLeaves:         []*commitment.AltLeaf{altLeaf},
 		},
 	}
 	if testAsset.GroupKey != nil {
@@ -323,6 +343,23 @@ func TestImportAssetProof(t *testing.T) {
 		),
 	)
 
+	return testProof
+}
+
+// TestImportAssetProof tests that given a valid asset proof (mainly the final
+// snapshot information), we're able to properly import all the components on
+// disk, then retrieve the asset as if it were ours.
+func TestImportAssetProof(t *testing.T) {
+	t.Parallel()
+
+	// First, we'll create a new instance of the database.
+	_, assetStore, db := newAssetStore(t)
+
+	testProof := importRandAssetProof(t, assetStore, db)
+	testAsset := testProof.Asset
+	assetID := testAsset.ID()
+	ctx := context.Background()
+
 	// We should now be able to retrieve the set of all assets inserted on
 	// disk.
 	assets, err := assetStore.FetchAllAssets(
@@ -339,7 +376,7 @@ func TestImportAssetProof(t *testing.T) {
 	// Finally, we'll verify all the anchor information that was inserted
 	// on disk.
 	require.Equal(t, testProof.AnchorBlockHash, dbAsset.AnchorBlockHash)
-	require.Equal(t, testProof.OutPoint, dbAsset.AnchorOutpoint)
+	require.Equal(t, testProof.AssetSnapshot.OutPoint, dbAsset.AnchorOutpoint)
 	require.Equal(t, testProof.AnchorTx.TxHash(), dbAsset.AnchorTx.TxHash())
 
 	// We should also be able to fetch the proof we just inserted using the
@@ -364,6 +401,98 @@ func TestImportAssetProof(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, selectedAssets, 1)
 	assertAssetEqual(t, testAsset, selectedAssets[0].Asset)
+
+	// The commitment of the selected asset must also include the alt
+	// leaves of the anchor output, so it matches the output's key.
+	selectedCommitment := selectedAssets[0].Commitment
+	require.Equal(t, testProof.AltLeaves, selectedCommitment.AltLeaves())
+	require.Equal(
+		t, testProof.ScriptRoot.TapscriptRoot(nil),
+		selectedCommitment.TapscriptRoot(nil),
+	)
+}
+
+// TestAnchorTxReorg tests that the proofs anchored in a transaction can be
+// invalidated and re-bound to a new block.
+func TestAnchorTxReorg(t *testing.T) {
+	t.Parallel()
+
+	_, assetStore, db := newAssetStore(t)
+	ctx := context.Background()
+
+	testProof := importRandAssetProof(t, assetStore, db)
+	txid := testProof.AnchorTx.TxHash()
+	height := testProof.AnchorBlockHeight
+
+	// The anchor transaction is only returned if it was confirmed at or
+	// above the given height, and as long as it's valid.
+	anchorTxs, err := assetStore.FetchAnchorTxs(ctx, height, false)
+	require.NoError(t, err)
+	require.Len(t, anchorTxs, 1)
+	require.Equal(t, txid, anchorTxs[0].Tx.TxHash())
+	require.Equal(t, testProof.AnchorBlockHash, anchorTxs[0].BlockHash)
+	require.Equal(t, height, anchorTxs[0].BlockHeight)
+	require.Equal(t, testProof.AnchorTxIndex, anchorTxs[0].TxIndex)
+
+	anchorTxs, err = assetStore.FetchAnchorTxs(ctx, height+1, false)
+	require.NoError(t, err)
+	require.Empty(t, anchorTxs)
+
+	anchorTxs, err = assetStore.FetchAnchorTxs(ctx, 0, true)
+	require.NoError(t, err)
+	require.Empty(t, anchorTxs)
+
+	// Once the anchor transaction is invalidated, it's only returned
+	// along with the other invalidated ones.
+	require.NoError(t, assetStore.InvalidateAnchorTx(ctx, txid))
+
+	anchorTxs, err = assetStore.FetchAnchorTxs(ctx, 0, false)
+	require.NoError(t, err)
+	require.Empty(t, anchorTxs)
+
+	anchorTxs, err = assetStore.FetchAnchorTxs(ctx, 0, true)
+	require.NoError(t, err)
+	require.Len(t, anchorTxs, 1)
+	require.Equal(t, testProof.AnchorBlockHash, anchorTxs[0].BlockHash)
+
+	proofs, err := assetStore.FetchAnchorTxProofs(ctx, txid)
+	require.NoError(t, err)
+	require.Len(t, proofs, 1)
+	require.True(t, testProof.ScriptKey.IsEqual(&proofs[0].ScriptKey))
+	require.Equal(t, testProof.Blob, proofs[0].Blob)
+
+	// A proof is needed for every asset anchored in the transaction.
+	reboundTx := &tapgarden.AnchorTx{
+		Tx:          testProof.AnchorTx,
+		BlockHash:   test.RandHash(),
+		BlockHeight: height + 1,
+		TxIndex:     testProof.AnchorTxIndex + 1,
+	}
+	err = assetStore.RebindAnchorTx(ctx, reboundTx, nil)
+	require.ErrorContains(t, err, "no re-bound proof")
+
+	// Re-binding the anchor transaction makes it valid again, with the
+	// new block and proof file.
+	proofs[0].Blob = bytes.Repeat([]byte{0x01}, 100)
+	require.NoError(t, assetStore.RebindAnchorTx(ctx, reboundTx, proofs))
+
+	anchorTxs, err = assetStore.FetchAnchorTxs(ctx, 0, true)
+	require.NoError(t, err)
+	require.Empty(t, anchorTxs)
+
+	anchorTxs, err = assetStore.FetchAnchorTxs(ctx, 0, false)
+	require.NoError(t, err)
+	require.Len(t, anchorTxs, 1)
+	require.Equal(t, txid, anchorTxs[0].Tx.TxHash())
+	require.Equal(t, reboundTx.BlockHash, anchorTxs[0].BlockHash)
+	require.Equal(t, reboundTx.BlockHeight, anchorTxs[0].BlockHeight)
+	require.Equal(t, reboundTx.TxIndex, anchorTxs[0].TxIndex)
+
+	proofBlob, err := assetStore.FetchProof(ctx, proof.Locator{
+		ScriptKey: testProof.ScriptKey,
+	})
+	require.NoError(t, err)
+	require.Equal(t, proofs[0].Blob, proofBlob)
 }
 
 // TestInternalKeyUpsert tests that if we insert an internal key that's a
@@ -411,6 +540,10 @@ type assetDesc struct {
 	scriptKey *asset.ScriptKey
 
 	amt uint64
+
+	edition uint64
+
+	anchorHeight uint32
 }
 
 type assetGenerator struct {
@@ -504,6 +637,9 @@ func (a *assetGenerator) genAssets(t *testing.T, assetStore *AssetStore,
 		if desc.groupAnchorGen != nil {
 			opts = append(opts, withGroupAnchorGen(desc.groupAnchorGen))
 		}
+		if desc.edition != 0 {
+			opts = append(opts, withEdition(desc.edition))
+		}
 		asset := randAsset(t, opts...)
 
 		// TODO(roasbeef): should actually group them all together?
@@ -517,10 +653,11 @@ func (a *assetGenerator) genAssets(t *testing.T, assetStore *AssetStore,
 		err = assetStore.importAssetFromProof(
 			ctx, assetStore.db, &proof.AnnotatedProof{
 				AssetSnapshot: &proof.AssetSnapshot{
-					AnchorTx:    anchorPoint,
-					InternalKey: test.RandPubKey(t),
-					Asset:       asset,
-					ScriptRoot:  tapCommitment,
+					AnchorTx:          anchorPoint,
+					AnchorBlockHeight: desc.anchorHeight,
+					InternalKey:       test.RandPubKey(t),
+					Asset:             asset,
+					ScriptRoot:        tapCommitment,
 				},
 				Blob: bytes.Repeat([]byte{1}, 100),
 			},
@@ -667,6 +804,59 @@ func TestSelectCommitment(t *testing.T) {
 			},
 			numAssets: 1,
 		},
+
+		// Create two editions of the same collectible, only the
+		// requested edition should be returned.
+		{
+			name: "specific edition",
+			assets: []assetDesc{
+				{
+					assetGen: assetGen.assetGens[0],
+					amt:      1,
+					edition:  1,
+
+					anchorPoint: assetGen.anchorPoints[0],
+				},
+				{
+					assetGen: assetGen.assetGens[0],
+					amt:      1,
+					edition:  2,
+
+					anchorPoint: assetGen.anchorPoints[0],
+				},
+			},
+			constraints: tapfreighter.CommitmentConstraints{
+				AssetID: assetGen.bindAssetID(
+					0, assetGen.anchorPoints[0],
+				),
+				MinAmt:  1,
+				Edition: 2,
+			},
+			numAssets: 1,
+		},
+
+		// An edition we don't own can't be selected.
+		{
+			name: "no match edition",
+			assets: []assetDesc{
+				{
+					assetGen: assetGen.assetGens[0],
+					amt:      1,
+					edition:  1,
+
+					anchorPoint: assetGen.anchorPoints[0],
+				},
+			},
+			constraints: tapfreighter.CommitmentConstraints{
+				AssetID: assetGen.bindAssetID(
+					0, assetGen.anchorPoints[0],
+				),
+				MinAmt:  1,
+				Edition: 3,
+			},
+			numAssets: 0,
+			err:       tapfreighter.ErrMatchingAssetsNotFound,
+		},
 	}
 
 	ctx := context.Background()
@@ -701,6 +891,8 @@ func TestSelectCommitment(t *testing.T) {
 			}
 
 			sa := selectedAssets[0]
+			require.Equal(t, tc.constraints.Edition, sa.Asset.Edition)
+
 			assetCommitment, err := assetsStore.FetchCommitment(
 				ctx, sa.Asset.ID(), sa.AnchorPoint,
 				sa.Asset.GroupKey, &sa.Asset.ScriptKey,
@@ -727,6 +919,382 @@ func TestSelectCommitment(t *testing.T) {
 	}
 }
 
+// TestFetchEditions tests that we can fetch the editions of a collectible,
+// sorted by their edition number.
+func TestFetchEditions(t *testing.T) {
+	t.Parallel()
+
+	assetGen := newAssetGenerator(t, 2, 1)
+	_, assetsStore, _ := newAssetStore(t)
+
+	// We'll import a few editions out of order, along with an asset of
+	// the same ID without an edition and an edition of another asset.
+	assetDescs := make([]assetDesc, 0, 5)
+	for _, edition := range []uint64{3, 1, 2, 0} {
+		assetDescs = append(assetDescs, assetDesc{
+			assetGen:    assetGen.assetGens[0],
+			amt:         1,
+			edition:     edition,
+			anchorPoint: assetGen.anchorPoints[0],
+		})
+	}
+	assetDescs = append(assetDescs, assetDesc{
+		assetGen:    assetGen.assetGens[1],
+		amt:         1,
+		edition:     4,
+		anchorPoint: assetGen.anchorPoints[1],
+	})
+	assetGen.genAssets(t, assetsStore, assetDescs)
+
+	ctx := context.Background()
+	assetID := assetGen.bindAssetID(0, assetGen.anchorPoints[0])
+	editions, err := assetsStore.FetchEditions(ctx, *assetID)
+	require.NoError(t, err)
+	require.Len(t, editions, 3)
+	for idx, edition := range editions {
+		require.Equal(t, *assetID, edition.ID())
+		require.EqualValues(t, idx+1, edition.Edition)
+	}
+}
+
+// TestFetchAssetsPage tests that assets can be filtered, sorted and fetched in
+// pages.
+func TestFetchAssetsPage(t *testing.T) {
+	t.Parallel()
+
+	assetGen := newAssetGenerator(t, 2, 1)
+	_, assetsStore, _ := newAssetStore(t)
+
+	scriptPathKey := asset.ScriptKey{
+		PubKey: test.RandPubKey(t),
+		TweakedScriptKey: &asset.TweakedScriptKey{
+			RawKey: keychain.KeyDescriptor{
+				PubKey: test.RandPubKey(t),
+			},
+			Tweak: test.RandBytes(32),
+		},
+	}
+
+	// We'll import five assets of two asset IDs at two anchor points, one
+	// of them with a script key that commits to a tapscript tree.
+	// The test assets always have an even amount.
+	amounts := []uint64{10, 2, 8, 4, 6}
+	assetDescs := make([]assetDesc, len(amounts))
+	for idx, amt := range amounts {
+		assetDescs[idx] = assetDesc{
+			assetGen:    assetGen.assetGens[idx%2],
+			amt:         amt,
+			anchorPoint: assetGen.anchorPoints[idx%2],
+		}
+	}
+	assetDescs[2].scriptKey = &scriptPathKey
+	assetGen.genAssets(t, assetsStore, assetDescs)
+
+	ctx := context.Background()
+	fetchAll := func(query *AssetQueryFilters, limit int32,
+		descending bool) []uint64 {
+
+		var (
+			amts   []uint64
+			cursor []byte
+		)
+		for {
+			page := PageParams{
+				Limit:      limit,
+				Cursor:     cursor,
+				Descending: descending,
+			}
+			assets, nextCursor, err := assetsStore.FetchAssetsPage(
+				ctx, false, query, page,
+			)
+			require.NoError(t, err)
+			require.LessOrEqual(t, len(assets), int(limit))
+
+			for _, a := range assets {
+				amts = append(amts, a.Amount)
+			}
+
+			if nextCursor == nil {
+				return amts
+			}
+			cursor = nextCursor
+		}
+	}
+
+	// Without any sorting, assets are returned in the order they were
+	// created in, no matter the page size.
+	require.Equal(t, amounts, fetchAll(nil, 2, false))
+	require.Equal(t, amounts, fetchAll(nil, 5, false))
+	require.Equal(t, []uint64{6, 4, 8, 2, 10}, fetchAll(nil, 2, true))
+
+	// Sorting by amount works in both directions.
+	byAmount := &AssetQueryFilters{
+		SortByAmount: true,
+	}
+	require.Equal(
+		t, []uint64{2, 4, 6, 8, 10}, fetchAll(byAmount, 2, false),
+	)
+	require.Equal(
+		t, []uint64{10, 8, 6, 4, 2}, fetchAll(byAmount, 3, true),
+	)
+
+	// Filters are applied across pages.
+	assetID := assetGen.bindAssetID(1, assetGen.anchorPoints[1])
+	require.Equal(t, []uint64{2, 4}, fetchAll(&AssetQueryFilters{
+		CommitmentConstraints: tapfreighter.CommitmentConstraints{
+			AssetID: assetID,
+		},
+	}, 1, false))
+
+	require.Equal(t, []uint64{8, 10}, fetchAll(&AssetQueryFilters{
+		CommitmentConstraints: tapfreighter.CommitmentConstraints{
+			MinAmt: 7,
+		},
+		AnchorPoint:  &assetGen.anchorPoints[0],
+		SortByAmount: true,
+	}, 2, false))
+
+	require.Equal(t, []uint64{8}, fetchAll(&AssetQueryFilters{
+		ScriptKeyType: ScriptKeyTypeScriptPath,
+	}, 2, false))
+	require.Equal(t, []uint64{10, 2, 4, 6}, fetchAll(&AssetQueryFilters{
+		ScriptKeyType: ScriptKeyTypeBip86,
+	}, 2, false))
+
+	require.Equal(t, []uint64{8}, fetchAll(&AssetQueryFilters{
+		ScriptKey: scriptPathKey.PubKey,
+	}, 2, false))
+
+	// None of the assets is spent yet.
+	spent, _, err := assetsStore.FetchAssetsPage(
+		ctx, true, &AssetQueryFilters{
+			SpentOnly: true,
+		}, PageParams{},
+	)
+	require.NoError(t, err)
+	require.Empty(t, spent)
+
+	// A cursor of another kind is rejected.
+	_, _, err = assetsStore.FetchAssetsPage(ctx, false, nil, PageParams{
+		Cursor: encodeTransferCursor(1),
+	})
+	require.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+// TestQueryAssetTransfersPage tests that transfers can be filtered by asset ID
+// and fetched in pages.
+func TestQueryAssetTransfersPage(t *testing.T) {
+	t.Parallel()
+
+	_, _, db := newAssetStore(t)
+	ctx := context.Background()
+
+	// We'll insert five transfers, spending inputs of two asset IDs in
+	// turns.
+	assetIDs := [][]byte{test.RandBytes(32), test.RandBytes(32)}
+	transferIDs := make([]int32, 5)
+	for idx := range transferIDs {
+		txid := test.RandBytes(32)
+		_, err := db.UpsertChainTx(ctx, ChainTxParams{
+			Txid:  txid,
+			RawTx: test.RandBytes(100),
+		})
+		require.NoError(t, err)
+
+		transferIDs[idx], err = db.InsertAssetTransfer(
+			ctx, NewAssetTransfer{
+				HeightHint:       int32(idx),
+				TransferTimeUnix: time.Now().UTC(),
+				AnchorTxid:       txid,
+			},
+		)
+		require.NoError(t, err)
+
+		err = db.InsertAssetTransferInput(ctx, NewTransferInput{
+			TransferID:  transferIDs[idx],
+			AnchorPoint: test.RandBytes(36),
+			AssetID:     assetIDs[idx%2],
+			ScriptKey:   test.RandBytes(33),
+			Amount:      1,
+		})
+		require.NoError(t, err)
+	}
+
+	fetchAll := func(assetID []byte, limit int32,
+		descending bool) []int32 {
+
+		var (
+			ids    []int32
+			cursor sql.NullInt32
+		)
+		for {
+			transfers, err := db.QueryAssetTransfers(
+				ctx, TransferQuery{
+					AssetIDFilter:  assetID,
+					CursorID:       cursor,
+					SortDescending: descending,
+					NumLimit:       sqlInt32(limit),
+				},
+			)
+			require.NoError(t, err)
+			require.LessOrEqual(t, len(transfers), int(limit))
+
+			for _, transfer := range transfers {
+				ids = append(ids, transfer.ID)
+			}
+
+			if len(transfers) < int(limit) {
+				return ids
+			}
+			cursor = sqlInt32(transfers[len(transfers)-1].ID)
+		}
+	}
+
+	require.Equal(t, transferIDs, fetchAll(nil, 2, false))
+	require.Equal(t, []int32{
+		transferIDs[4], transferIDs[3], transferIDs[2], transferIDs[1],
+		transferIDs[0],
+	}, fetchAll(nil, 3, true))
+	require.Equal(t, []int32{
+		transferIDs[0], transferIDs[2], transferIDs[4],
+	}, fetchAll(assetIDs[0], 1, false))
+	require.Equal(t, []int32{
+		transferIDs[3], transferIDs[1],
+	}, fetchAll(assetIDs[1], 2, true))
+}
+
+// TestLeaseCoins tests that leased coins are excluded from coin selection until
+// their lease is released or has expired.
+func TestLeaseCoins(t *testing.T) {
+	t.Parallel()
+
+	_, assetsStore, db := newAssetStore(t)
+	ctx := context.Background()
+
+	// We'll create two assets, each anchored in its own UTXO.
+	assetGen := newAssetGenerator(t, 2, 1)
+	assetGen.genAssets(t, assetsStore, []assetDesc{
+		{
+			assetGen:    assetGen.assetGens[0],
+			anchorPoint: assetGen.anchorPoints[0],
+			amt:         10,
+		},
+		{
+			assetGen:    assetGen.assetGens[1],
+			anchorPoint: assetGen.anchorPoints[1],
+			amt:         20,
+		},
+	})
+
+	assertEligible := func(includeLeased bool, numCoins int) {
+		t.Helper()
+
+		coins, err := assetsStore.ListEligibleCoins(
+			ctx, tapfreighter.CommitmentConstraints{
+				IncludeLeased: includeLeased,
+			},
+		)
+		require.NoError(t, err)
+		require.Len(t, coins, numCoins)
+	}
+	assertEligible(false, 2)
+
+	// Once we lease the first UTXO, its asset isn't eligible anymore,
+	// unless we explicitly ask for leased coins as well.
+	leaseOwner := test.RandHash()
+	err := assetsStore.LeaseCoins(
+		ctx, leaseOwner, time.Now().Add(time.Hour),
+		assetGen.anchorPoints[0],
+	)
+	require.NoError(t, err)
+	assertEligible(false, 1)
+	assertEligible(true, 2)
+
+	coins, err := assetsStore.ListEligibleCoins(
+		ctx, tapfreighter.CommitmentConstraints{},
+	)
+	require.NoError(t, err)
+	require.Equal(t, assetGen.anchorPoints[1], coins[0].AnchorPoint)
+
+	// Releasing the lease makes the asset eligible again.
+	err = assetsStore.ReleaseCoins(ctx, assetGen.anchorPoints[0])
+	require.NoError(t, err)
+	assertEligible(false, 2)
+
+	// A lease that has already expired doesn't exclude the asset and is
+	// removed when deleting the expired leases.
+	err = assetsStore.LeaseCoins(
+		ctx, leaseOwner, time.Now().Add(-time.Minute),
+		assetGen.anchorPoints...,
+	)
+	require.NoError(t, err)
+	assertEligible(false, 2)
+
+	require.NoError(t, assetsStore.DeleteExpiredLeases(ctx))
+	utxos, err := db.FetchManagedUTXOs(ctx)
+	require.NoError(t, err)
+	require.Len(t, utxos, 2)
+	for _, utxo := range utxos {
+		require.Nil(t, utxo.LeaseOwner)
+		require.False(t, utxo.LeaseExpiry.Valid)
+	}
+}
+
+// TestListEligibleCoinsMaxAnchorHeight tests that coin selection can exclude
+// assets of which the anchor transaction was confirmed too recently.
+func TestListEligibleCoinsMaxAnchorHeight(t *testing.T) {
+	t.Parallel()
+
+	_, assetsStore, _ := newAssetStore(t)
+	ctx := context.Background()
+
+	// We'll create two assets, anchored in UTXOs that were confirmed at
+	// different heights.
+	assetGen := newAssetGenerator(t, 2, 1)
+	assetGen.genAssets(t, assetsStore, []assetDesc{
+		{
+			assetGen:     assetGen.assetGens[0],
+			anchorPoint:  assetGen.anchorPoints[0],
+			amt:          10,
+			anchorHeight: 100,
+		},
+		{
+			assetGen:     assetGen.assetGens[1],
+			anchorPoint:  assetGen.anchorPoints[1],
+			amt:          20,
+			anchorHeight: 105,
+		},
+	})
+
+	listCoins := func(maxHeight uint32) ([]*tapfreighter.AnchoredCommitment,
+		error) {
+
+		return assetsStore.ListEligibleCoins(
+			ctx, tapfreighter.CommitmentConstraints{
+				MaxAnchorHeight: maxHeight,
+			},
+		)
+	}
+
+	// Without a max height, both assets are eligible.
+	coins, err := listCoins(0)
+	require.NoError(t, err)
+	require.Len(t, coins, 2)
+
+	coins, err = listCoins(105)
+	require.NoError(t, err)
+	require.Len(t, coins, 2)
+
+	// Only the asset confirmed at or below the max height is eligible.
+	coins, err = listCoins(104)
+	require.NoError(t, err)
+	require.Len(t, coins, 1)
+	require.Equal(t, assetGen.anchorPoints[0], coins[0].AnchorPoint)
+
+	_, err = listCoins(99)
+	require.ErrorIs(t, err, tapfreighter.ErrMatchingAssetsNotFound)
+}
+
 // TestAssetExportLog tests that were able to properly spend/transfer assets on
 // disk. This ensures we can properly commit the end result of an asset
 // transfer initiated at a higher level.
@@ -819,6 +1387,9 @@ func TestAssetExportLog(t *testing.T) {
 
 	chainFees := int64(100)
 
+	courierAddr, err := url.ParseRequestURI("hashmail://localhost:10029")
+	require.NoError(t, err)
+
 	allAssets, err := assetsStore.FetchAllAssets(ctx, true, nil)
 	require.NoError(t, err)
 	require.Len(t, allAssets, numAssets)
@@ -908,9 +1479,29 @@ func TestAssetExportLog(t *testing.T) {
 				newRootHash, newRootValue,
 			),
 			ProofSuffix: senderBlob,
+			// We pretend the proof of this output still needs to
+			// be delivered to a remote receiver.
+			ProofCourierAddr:    courierAddr,
+			ProofDeliveryStatus: tapfreighter.ProofDeliveryPending,
 		}},
 	}
-	require.NoError(t, assetsStore.LogPendingParcel(ctx, spendDelta))
+	require.NoError(t, assetsStore.LogPendingParcel(
+		ctx, spendDelta, test.RandHash(), time.Now().Add(time.Hour),
+	))
+
+	// The input anchor UTXO is now leased, so none of the assets anchored
+	// in it can be selected for another transfer.
+	_, err = assetsStore.ListEligibleCoins(
+		ctx, tapfreighter.CommitmentConstraints{},
+	)
+	require.ErrorIs(t, err, tapfreighter.ErrMatchingAssetsNotFound)
+	leasedCoins, err := assetsStore.ListEligibleCoins(
+		ctx, tapfreighter.CommitmentConstraints{
+			IncludeLeased: true,
+		},
+	)
+	require.NoError(t, err)
+	require.Len(t, leasedCoins, numAssets)
 
 	assetID := inputAsset.ID()
 	proofs := map[asset.SerializedKey]*proof.AnnotatedProof{
@@ -984,6 +1575,12 @@ func TestAssetExportLog(t *testing.T) {
 	require.Equal(t, 1, len(parcels))
 	require.Equal(t, spendDelta, parcels[0])
 
+	// The proof delivery queue only contains confirmed parcels, so it
+	// should still be empty.
+	parcels, err = assetsStore.PendingProofDeliveries(ctx)
+	require.NoError(t, err)
+	require.Empty(t, parcels)
+
 	// With the asset delta committed and verified, we'll now mark the
 	// delta as being confirmed on chain.
 	fakeBlockHash := chainhash.Hash(sha256.Sum256([]byte("fake")))
@@ -1000,6 +1597,16 @@ func TestAssetExportLog(t *testing.T) {
 	)
 	require.NoError(t, err)
 
+	// The spent input anchor UTXO shouldn't be leased anymore.
+	inputAnchorBytes, err := encodeOutpoint(assetGen.anchorPoints[0])
+	require.NoError(t, err)
+	inputAnchor, err := db.FetchManagedUTXO(ctx, UtxoQuery{
+		Outpoint: inputAnchorBytes,
+	})
+	require.NoError(t, err)
+	require.Nil(t, inputAnchor.LeaseOwner)
+	require.False(t, inputAnchor.LeaseExpiry.Valid)
+
 	// We'll now fetch all the assets to verify that they were updated
 	// properly on disk.
 	chainAssets, err := assetsStore.FetchAllAssets(ctx, false, nil)
@@ -1056,6 +1663,156 @@ func TestAssetExportLog(t *testing.T) {
 	parcels, err = assetsStore.PendingParcels(ctx)
 	require.NoError(t, err)
 	require.Equal(t, 0, len(parcels))
+
+	// The proof of the second output wasn't delivered yet, so the parcel
+	// should now show up in the proof delivery queue.
+	parcels, err = assetsStore.PendingProofDeliveries(ctx)
+	require.NoError(t, err)
+	require.Len(t, parcels, 1)
+	require.Equal(
+		t, tapfreighter.ProofDeliveryPending,
+		parcels[0].Outputs[1].ProofDeliveryStatus,
+	)
+	require.Equal(t, courierAddr, parcels[0].Outputs[1].ProofCourierAddr)
+
+	// Once we mark the proof as delivered, the queue should be empty.
+	secondOutput := spendDelta.Outputs[1]
+	err = assetsStore.ConfirmProofDelivery(
+		ctx, secondOutput.Anchor.OutPoint,
+		secondOutput.ScriptKey.PubKey,
+	)
+	require.NoError(t, err)
+
+	parcels, err = assetsStore.PendingProofDeliveries(ctx)
+	require.NoError(t, err)
+	require.Empty(t, parcels)
+
+	parcels, err = assetsStore.QueryParcels(ctx, false)
+	require.NoError(t, err)
+	require.Len(t, parcels, 1)
+	require.Equal(
+		t, tapfreighter.ProofDeliveryComplete,
+		parcels[0].Outputs[1].ProofDeliveryStatus,
+	)
+}
+
+// TestAssetBurnLog tests that burn outputs of a logged parcel are recorded in
+// the burn table and can be queried by asset ID.
+func TestAssetBurnLog(t *testing.T) {
+	t.Parallel()
+
+	_, assetsStore, _ := newAssetStore(t)
+	ctx := context.Background()
+
+	const numAssets = 1
+	assetGen := newAssetGenerator(t, numAssets, 1)
+	assetGen.genAssets(t, assetsStore, []assetDesc{{
+		assetGen:    assetGen.assetGens[0],
+		anchorPoint: assetGen.anchorPoints[0],
+		amt:         20,
+	}})
+
+	allAssets, err := assetsStore.FetchAllAssets(ctx, true, nil)
+	require.NoError(t, err)
+	require.Len(t, allAssets, numAssets)
+
+	inputAsset := allAssets[0]
+	inputPrevID := asset.PrevID{
+		OutPoint: assetGen.anchorPoints[0],
+		ID:       inputAsset.ID(),
+		ScriptKey: asset.ToSerialized(
+			inputAsset.ScriptKey.PubKey,
+		),
+	}
+
+	newAnchorTx := wire.NewMsgTx(2)
+	newAnchorTx.AddTxIn(&wire.TxIn{})
+	newAnchorTx.TxIn[0].SignatureScript = []byte{}
+	newAnchorTx.AddTxOut(&wire.TxOut{
+		PkScript: bytes.Repeat([]byte{0x01}, 34),
+		Value:    1000,
+	})
+	newAnchorTx.AddTxOut(&wire.TxOut{
+		PkScript: bytes.Repeat([]byte{0x02}, 34),
+		Value:    1000,
+	})
+	anchorTxHash := newAnchorTx.TxHash()
+
+	newAnchor := func(idx uint32) tapfreighter.Anchor {
+		return tapfreighter.Anchor{
+			Value: 1000,
+			OutPoint: wire.OutPoint{
+				Hash:  anchorTxHash,
+				Index: idx,
+			},
+			InternalKey: keychain.KeyDescriptor{
+				PubKey: test.RandPubKey(t),
+			},
+			TaprootAssetRoot: bytes.Repeat([]byte{0x1}, 32),
+			MerkleRoot:       bytes.Repeat([]byte{0x1}, 32),
+		}
+	}
+
+	// We'll burn part of the asset and keep the rest as change. Only the
+	// output with the burn key that commits to the first input should be
+	// recorded as a burn.
+	const burnAmt = 8
+	burnKey := asset.NewBurnScriptKey(inputPrevID)
+	changeKey := asset.NewScriptKeyBip86(keychain.KeyDescriptor{
+		PubKey: test.RandPubKey(t),
+	})
+	burnWitness := asset.Witness{
+		PrevID:    &inputPrevID,
+		TxWitness: [][]byte{{0x01}, {0x02}},
+	}
+	spendDelta := &tapfreighter.OutboundParcel{
+		AnchorTx:           newAnchorTx,
+		AnchorTxHeightHint: 1450,
+		ChainFees:          100,
+		Inputs: []tapfreighter.TransferInput{{
+			PrevID: inputPrevID,
+			Amount: inputAsset.Amount,
+		}},
+		Outputs: []tapfreighter.TransferOutput{{
+			Anchor:      newAnchor(0),
+			Type:        tappsbt.TypeSimple,
+			ScriptKey:   burnKey,
+			Amount:      burnAmt,
+			WitnessData: []asset.Witness{burnWitness},
+			ProofSuffix: bytes.Repeat([]byte{0x01}, 100),
+		}, {
+			Anchor:         newAnchor(1),
+			Type:           tappsbt.TypeSplitRoot,
+			ScriptKey:      changeKey,
+			ScriptKeyLocal: true,
+			Amount:         inputAsset.Amount - burnAmt,
+			WitnessData:    []asset.Witness{burnWitness},
+			ProofSuffix:    bytes.Repeat([]byte{0x02}, 100),
+		}},
+	}
+	require.NoError(t, assetsStore.LogPendingParcel(
+		ctx, spendDelta, test.RandHash(), time.Now().Add(time.Hour),
+	))
+
+	burns, err := assetsStore.QueryBurns(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, burns, 1)
+	require.Equal(t, anchorTxHash, burns[0].AnchorTxid)
+	require.Equal(t, inputAsset.ID(), burns[0].AssetID)
+	require.True(t, burnKey.PubKey.IsEqual(burns[0].ScriptKey))
+	require.EqualValues(t, burnAmt, burns[0].Amount)
+
+	// Filtering by the burnt asset's ID should return the same burn, while
+	// any other asset ID shouldn't match anything.
+	assetID := inputAsset.ID()
+	burns, err = assetsStore.QueryBurns(ctx, &assetID)
+	require.NoError(t, err)
+	require.Len(t, burns, 1)
+
+	otherID := asset.RandID(t)
+	burns, err = assetsStore.QueryBurns(ctx, &otherID)
+	require.NoError(t, err)
+	require.Empty(t, burns)
 }
 
 // TestAssetGroupSigUpsert tests that if you try to insert another asset
@@ -1209,3 +1966,83 @@ func TestFetchGroupedAssets(t *testing.T) {
 	equalityCheck(allAssets[2].Asset, groupedAssets[1])
 	equalityCheck(allAssets[3].Asset, groupedAssets[2])
 }
+
+// TestFetchGroupMetaHistory tests that the metadata updates carried by the
+// tranches of an asset group can be fetched and verified.
+func TestFetchGroupMetaHistory(t *testing.T) {
+	t.Parallel()
+
+	_, assetStore, db := newAssetStore(t)
+	ctx := context.Background()
+
+	anchorGen := asset.RandGenesis(t, asset.Normal)
+	groupKey, groupPriv := asset.RandGroupKeyWithSigner(t, anchorGen)
+	privKey, _ := btcec.PrivKeyFromBytes(groupPriv)
+	genSigner := asset.NewRawKeyGenesisSigner(privKey)
+
+	insertTranche := func(gen asset.Genesis, reveal *proof.MetaReveal) {
+		trancheKey, err := asset.DeriveGroupKey(
+			genSigner, groupKey.RawKey, anchorGen, &gen,
+		)
+		require.NoError(t, err)
+
+		genesisPointID, err := upsertGenesisPoint(
+			ctx, db, gen.FirstPrevOut,
+		)
+		require.NoError(t, err)
+
+		_, err = maybeUpsertAssetMeta(ctx, db, &gen, reveal)
+		require.NoError(t, err)
+
+		genAssetID, err := upsertGenesis(ctx, db, genesisPointID, gen)
+		require.NoError(t, err)
+
+		_, err = upsertGroupKey(
+			ctx, trancheKey, db, genesisPointID, genAssetID,
+		)
+		require.NoError(t, err)
+	}
+
+	// The anchor of the group doesn't reveal its metadata.
+	insertTranche(anchorGen, nil)
+
+	history, err := assetStore.FetchGroupMetaHistory(
+		ctx, &groupKey.GroupPubKey,
+	)
+	require.NoError(t, err)
+	require.Nil(t, history.Canonical())
+
+	// Now we'll issue two tranches that update the metadata of the group.
+	var (
+		prevHash [asset.MetaHashLen]byte
+		metas    []*proof.MetaReveal
+	)
+	for i := uint32(1); i <= 2; i++ {
+		meta := &proof.MetaReveal{
+			Type: proof.MetaOpaque,
+			Data: test.RandBytes(32),
+		}
+		update := &proof.MetaUpdate{
+			Sequence: i,
+			PrevHash: prevHash,
+			Meta:     meta,
+		}
+		reveal, err := update.MetaReveal()
+		require.NoError(t, err)
+
+		gen := asset.RandGenesis(t, asset.Normal)
+		gen.MetaHash = reveal.MetaHash()
+		insertTranche(gen, reveal)
+
+		prevHash = gen.MetaHash
+		metas = append(metas, meta)
+	}
+
+	history, err = assetStore.FetchGroupMetaHistory(
+		ctx, &groupKey.GroupPubKey,
+	)
+	require.NoError(t, err)
+	require.Len(t, history.Updates, 2)
+	require.Equal(t, metas[0], history.Updates[0].Update.Meta)
+	require.Equal(t, metas[1], history.Canonical())
+}
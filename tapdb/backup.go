@@ -0,0 +1,265 @@
+package tapdb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+)
+
+const (
+	// pgDumpCmd is the name of the Postgres utility that we use to create
+	// backups of a Postgres database.
+	pgDumpCmd = "pg_dump"
+
+	// pgRestoreCmd is the name of the Postgres utility that we use to
+	// restore backups of a Postgres database.
+	pgRestoreCmd = "pg_restore"
+)
+
+var (
+	// ErrBackupExists is returned if a backup is requested to be written
+	// to a file that already exists.
+	ErrBackupExists = errors.New("backup file already exists")
+
+	// ErrInvalidBackup is returned if a backup that should be restored
+	// isn't in the format of the configured database backend.
+	ErrInvalidBackup = errors.New("invalid backup file")
+
+	// sqliteHeader is the header every SQLite database file starts with.
+	sqliteHeader = []byte("SQLite format 3\x00")
+
+	// pgDumpHeader is the header every archive in the custom format of
+	// pg_dump starts with.
+	pgDumpHeader = []byte("PGDMP")
+)
+
+// Backupper is a database that is able to write a consistent snapshot of its
+// contents to a file while it's being used, so backups can be taken without
+// stopping the daemon.
+type Backupper interface {
+	// Backup writes a consistent snapshot of the database to the file at
+	// the given path. The file must not exist yet.
+	Backup(ctx context.Context, filePath string) error
+}
+
+// checkBackupPath makes sure a new backup can be written to the given path.
+func checkBackupPath(filePath string) error {
+	_, err := os.Stat(filePath)
+	switch {
+	case err == nil:
+		return fmt.Errorf("%w: %v", ErrBackupExists, filePath)
+
+	case !os.IsNotExist(err):
+		return err
+	}
+
+	return nil
+}
+
+// checkBackupHeader makes sure the backup file at the given path starts with
+// the given header, so we never replace a database with a file that can't be
+// restored.
+func checkBackupHeader(backupPath string, header []byte) error {
+	backup, err := os.Open(backupPath)
+	if err != nil {
+		return fmt.Errorf("unable to open backup: %w", err)
+	}
+	defer backup.Close()
+
+	fileHeader := make([]byte, len(header))
+	_, err = io.ReadFull(backup, fileHeader)
+	switch {
+	case err == io.EOF || err == io.ErrUnexpectedEOF:
+		return fmt.Errorf("%w: %v is too short", ErrInvalidBackup,
+			backupPath)
+
+	case err != nil:
+		return fmt.Errorf("unable to read backup: %w", err)
+
+	case !bytes.Equal(fileHeader, header):
+		return fmt.Errorf("%w: unexpected header of %v",
+			ErrInvalidBackup, backupPath)
+	}
+
+	return nil
+}
+
+// Backup writes a consistent snapshot of the database to the file at the given
+// path, using VACUUM INTO. The snapshot is taken within a read transaction,
+// so writers aren't blocked meanwhile. The backup is a regular SQLite database
+// file that can be restored with RestoreSqliteBackup.
+//
+// NOTE: This is part of the Backupper interface.
+func (s *SqliteStore) Backup(ctx context.Context, filePath string) error {
+	if err := checkBackupPath(filePath); err != nil {
+		return err
+	}
+
+	// We first write the backup to a temporary file, so an interrupted
+	// backup never leaves a partial backup file behind.
+	tempPath := filePath + ".tmp"
+	if err := os.Remove(tempPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	_, err := s.DB.ExecContext(ctx, "VACUUM INTO ?", tempPath)
+	if err != nil {
+		return fmt.Errorf("unable to write backup: %w", err)
+	}
+
+	return os.Rename(tempPath, filePath)
+}
+
+// RestoreSqliteBackup restores the SQLite database of the given config from
+// the backup file at the given path. This must be called before the database
+// is opened. An existing database is kept next to the restored one, with the
+// current unix timestamp as an additional suffix. The backup must be a SQLite
+// database file, otherwise ErrInvalidBackup is returned and the existing
+// database is left untouched.
+func RestoreSqliteBackup(cfg *SqliteConfig, backupPath string) error {
+	if err := checkBackupHeader(backupPath, sqliteHeader); err != nil {
+		return err
+	}
+
+	backup, err := os.Open(backupPath)
+	if err != nil {
+		return fmt.Errorf("unable to open backup: %w", err)
+	}
+	defer backup.Close()
+
+	dbPath := cfg.DatabaseFileName
+	suffix := "." + strconv.FormatInt(time.Now().Unix(), 10)
+
+	// The write-ahead log and shared memory files of an existing database
+	// belong to it and must not be applied to the restored database.
+	dbFiles := []string{dbPath, dbPath + "-wal", dbPath + "-shm"}
+	for _, path := range dbFiles {
+		err := os.Rename(path, path+suffix)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("unable to move existing database: "+
+				"%w", err)
+		}
+	}
+
+	tempPath := dbPath + ".tmp"
+	restored, err := os.OpenFile(
+		tempPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600,
+	)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(restored, backup); err != nil {
+		restored.Close()
+		return fmt.Errorf("unable to copy backup: %w", err)
+	}
+	if err := restored.Sync(); err != nil {
+		restored.Close()
+		return err
+	}
+	if err := restored.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tempPath, dbPath)
+}
+
+// pgCommand creates a command that runs the given Postgres utility against the
+// database of the given config. The password is passed through the
+// environment, so it doesn't show up in the list of processes.
+func pgCommand(ctx context.Context, cfg *PostgresConfig, name string,
+	args ...string) *exec.Cmd {
+
+	sslMode := "disable"
+	if cfg.RequireSSL {
+		sslMode = "require"
+	}
+
+	args = append([]string{
+		"--host", cfg.Host,
+		"--port", strconv.Itoa(cfg.Port),
+		"--username", cfg.User,
+		"--dbname", cfg.DBName,
+		"--no-password",
+	}, args...)
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Env = append(
+		os.Environ(), "PGPASSWORD="+cfg.Password, "PGSSLMODE="+sslMode,
+	)
+
+	return cmd
+}
+
+// runPgCommand runs the given Postgres utility command, returning its error
+// output as part of the error if it fails.
+func runPgCommand(cmd *exec.Cmd) error {
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%v failed: %w: %s", cmd.Path, err,
+			bytes.TrimSpace(stderr.Bytes()))
+	}
+
+	return nil
+}
+
+// Backup writes a consistent snapshot of the database to the file at the given
+// path, using pg_dump, which needs to be installed on the system. The snapshot
+// is taken within a single transaction, so other database users aren't
+// blocked meanwhile. The backup is an archive in the custom format of pg_dump
+// that can be restored with RestorePostgresBackup.
+//
+// NOTE: This is part of the Backupper interface.
+func (s *PostgresStore) Backup(ctx context.Context, filePath string) error {
+	if err := checkBackupPath(filePath); err != nil {
+		return err
+	}
+
+	tempPath := filePath + ".tmp"
+	cmd := pgCommand(
+		ctx, s.cfg, pgDumpCmd, "--format=custom", "--file", tempPath,
+	)
+	if err := runPgCommand(cmd); err != nil {
+		_ = os.Remove(tempPath)
+		return fmt.Errorf("unable to write backup: %w", err)
+	}
+
+	return os.Rename(tempPath, filePath)
+}
+
+// RestorePostgresBackup restores the Postgres database of the given config from
+// the pg_dump archive at the given path, using pg_restore, which needs to be
+// installed on the system. All existing objects of the database are replaced
+// within a single transaction. This must be called before the database is
+// opened. The backup must be a pg_dump archive, otherwise ErrInvalidBackup is
+// returned and the existing database is left untouched.
+func RestorePostgresBackup(ctx context.Context, cfg *PostgresConfig,
+	backupPath string) error {
+
+	if err := checkBackupHeader(backupPath, pgDumpHeader); err != nil {
+		return err
+	}
+
+	cmd := pgCommand(
+		ctx, cfg, pgRestoreCmd, "--clean", "--if-exists", "--no-owner",
+		"--single-transaction", backupPath,
+	)
+	if err := runPgCommand(cmd); err != nil {
+		return fmt.Errorf("unable to restore backup: %w", err)
+	}
+
+	return nil
+}
+
+// A compile-time assertion to ensure both database backends implement the
+// Backupper interface.
+var _ Backupper = (*SqliteStore)(nil)
+var _ Backupper = (*PostgresStore)(nil)
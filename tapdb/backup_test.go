@@ -0,0 +1,101 @@
+package tapdb
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/macaroons"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSqliteBackupRestore tests that a backup of a SQLite database can be taken
+// while it is in use, and that the backup can be restored over an existing
+// database.
+func TestSqliteBackupRestore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	newRootKeyStore := func(db *SqliteStore) *RootKeyStore {
+		rksDB := NewTransactionExecutor(db, func(tx *sql.Tx) KeyStore {
+			return db.WithTx(tx)
+		})
+		return NewRootKeyStore(rksDB)
+	}
+
+	// We start with a database that contains a single root key.
+	db := NewTestSqliteDB(t)
+	rks := newRootKeyStore(db)
+
+	backedUpID := []byte("backed up")
+	rootKey, _, err := rks.RootKey(
+		macaroons.ContextWithRootKeyID(ctx, backedUpID),
+	)
+	require.NoError(t, err)
+
+	backupPath := filepath.Join(t.TempDir(), "backup.db")
+	require.NoError(t, db.Backup(ctx, backupPath))
+	require.FileExists(t, backupPath)
+	require.NoFileExists(t, backupPath+".tmp")
+
+	// An existing backup is never overwritten.
+	err = db.Backup(ctx, backupPath)
+	require.ErrorIs(t, err, ErrBackupExists)
+
+	// We now add another root key that isn't part of the backup, before
+	// restoring the backup over a copy of the current database.
+	_, _, err = rks.RootKey(
+		macaroons.ContextWithRootKeyID(ctx, []byte("not backed up")),
+	)
+	require.NoError(t, err)
+
+	dbPath := filepath.Join(t.TempDir(), "restored.db")
+	require.NoError(t, db.Backup(ctx, dbPath))
+
+	cfg := &SqliteConfig{
+		DatabaseFileName: dbPath,
+	}
+	require.NoError(t, RestoreSqliteBackup(cfg, backupPath))
+
+	restoredDB, err := NewSqliteStore(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, restoredDB.DB.Close())
+	})
+
+	// Only the root key of the backup is part of the restored database.
+	restoredRks := newRootKeyStore(restoredDB)
+	restoredKey, err := restoredRks.Get(ctx, backedUpID)
+	require.NoError(t, err)
+	require.Equal(t, rootKey, restoredKey)
+
+	_, err = restoredRks.Get(ctx, []byte("not backed up"))
+	require.Equal(t, sql.ErrNoRows, err)
+
+	// The replaced database was kept next to the restored one.
+	oldDBs, err := filepath.Glob(dbPath + ".*")
+	require.NoError(t, err)
+	require.Len(t, oldDBs, 1)
+
+	// Restoring a backup that doesn't exist fails.
+	err = RestoreSqliteBackup(cfg, filepath.Join(t.TempDir(), "missing"))
+	require.Error(t, err)
+
+	// A file that isn't a SQLite database is rejected before the existing
+	// database is moved out of the way.
+	invalidPath := filepath.Join(t.TempDir(), "invalid.db")
+	require.NoError(t, os.WriteFile(invalidPath, []byte("PGDMP"), 0600))
+	err = RestoreSqliteBackup(cfg, invalidPath)
+	require.ErrorIs(t, err, ErrInvalidBackup)
+
+	oldDBs, err = filepath.Glob(dbPath + ".*")
+	require.NoError(t, err)
+	require.Len(t, oldDBs, 1)
+	require.FileExists(t, dbPath)
+
+	// The same is true for a Postgres restore.
+	err = RestorePostgresBackup(ctx, &PostgresConfig{}, backupPath)
+	require.ErrorIs(t, err, ErrInvalidBackup)
+}
@@ -3,6 +3,9 @@ package tapdb
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"math/rand"
 	"time"
 
 	"github.com/lightninglabs/taproot-assets/tapdb/sqlc"
@@ -12,6 +15,25 @@ var (
 	// DefaultStoreTimeout is the default timeout used for any interaction
 	// with the storage/database.
 	DefaultStoreTimeout = time.Second * 10
+
+	// ErrRetriesExceeded is returned when a transaction failed to be
+	// serialized with concurrent transactions more often than the number
+	// of allowed retries.
+	ErrRetriesExceeded = errors.New("db tx retries exceeded")
+)
+
+const (
+	// DefaultNumTxRetries is the default number of times a transaction is
+	// retried after it failed to be serialized with concurrent ones.
+	DefaultNumTxRetries = 10
+
+	// DefaultInitialRetryDelay is the default delay before the first
+	// retry of a transaction. The delay is doubled with every retry.
+	DefaultInitialRetryDelay = 10 * time.Millisecond
+
+	// DefaultMaxRetryDelay is the default upper bound of the delay between
+	// two retries of a transaction.
+	DefaultMaxRetryDelay = time.Second
 )
 
 // TxOptions represents a set of options one can use to control what type of
@@ -21,6 +43,32 @@ type TxOptions interface {
 	ReadOnly() bool
 }
 
+// txOptions is a generic implementation of the TxOptions interface, which can
+// be used by storage interfaces that don't need their own type of options.
+type txOptions struct {
+	// readOnly governs if a read only transaction is needed or not.
+	readOnly bool
+}
+
+// ReadOnly returns true if the transaction should be read only.
+//
+// NOTE: This implements the TxOptions interface.
+func (t *txOptions) ReadOnly() bool {
+	return t.readOnly
+}
+
+// ReadTxOption returns a TxOptions that marks a transaction as read only.
+func ReadTxOption() TxOptions {
+	return &txOptions{
+		readOnly: true,
+	}
+}
+
+// WriteTxOption returns a TxOptions that marks a transaction as read-write.
+func WriteTxOption() TxOptions {
+	return &txOptions{}
+}
+
 // BatchedTx is a generic interface that represents the ability to execute
 // several operations to a given storage interface in a single atomic
 // transaction. Typically, Q here will be some subset of the main sqlc.Querier
@@ -69,6 +117,92 @@ type BatchedQuerier interface {
 	BeginTx(ctx context.Context, options TxOptions) (*sql.Tx, error)
 }
 
+// txExecutorOptions houses the options that control how a TransactionExecutor
+// executes transactions.
+type txExecutorOptions struct {
+	// numRetries is the number of times a transaction is retried after it
+	// failed to be serialized with concurrent ones.
+	numRetries int
+
+	// initialRetryDelay is the delay before the first retry of a
+	// transaction.
+	initialRetryDelay time.Duration
+
+	// maxRetryDelay is the upper bound of the delay between two retries.
+	maxRetryDelay time.Duration
+
+	// txTimeout is the deadline for each call to ExecTx, including all of
+	// its retries. If zero, only the deadline of the passed context
+	// applies.
+	txTimeout time.Duration
+}
+
+// defaultTxExecutorOptions returns the default options of a
+// TransactionExecutor.
+func defaultTxExecutorOptions() *txExecutorOptions {
+	return &txExecutorOptions{
+		numRetries:        DefaultNumTxRetries,
+		initialRetryDelay: DefaultInitialRetryDelay,
+		maxRetryDelay:     DefaultMaxRetryDelay,
+	}
+}
+
+// randRetryDelay returns the delay before the given retry attempt. The delay
+// grows exponentially with each attempt up to the maximum delay, and is
+// jittered so concurrent transactions that conflicted with each other don't
+// retry in lockstep.
+func (t *txExecutorOptions) randRetryDelay(attempt int) time.Duration {
+	delay := t.initialRetryDelay
+	for i := 0; i < attempt && delay < t.maxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > t.maxRetryDelay {
+		delay = t.maxRetryDelay
+	}
+
+	// We wait for at least half of the delay, so the backoff still grows
+	// with every attempt.
+	halfDelay := delay / 2
+	if halfDelay <= 0 {
+		return delay
+	}
+
+	return halfDelay + time.Duration(rand.Int63n(int64(halfDelay)))
+}
+
+// TxExecutorOption is a functional option that allows callers to modify the
+// behavior of a TransactionExecutor.
+type TxExecutorOption func(*txExecutorOptions)
+
+// WithTxRetries is a functional option that sets the number of times a
+// transaction is retried after it failed to be serialized with concurrent
+// ones. A value of zero disables retries.
+func WithTxRetries(numRetries int) TxExecutorOption {
+	return func(o *txExecutorOptions) {
+		o.numRetries = numRetries
+	}
+}
+
+// WithTxRetryDelay is a functional option that sets the delay before the first
+// retry of a transaction, and the upper bound of the delay between two
+// retries.
+func WithTxRetryDelay(initialDelay,
+	maxDelay time.Duration) TxExecutorOption {
+
+	return func(o *txExecutorOptions) {
+		o.initialRetryDelay = initialDelay
+		o.maxRetryDelay = maxDelay
+	}
+}
+
+// WithTxTimeout is a functional option that sets a deadline for each call to
+// ExecTx, including all of its retries.
+func WithTxTimeout(timeout time.Duration) TxExecutorOption {
+	return func(o *txExecutorOptions) {
+		o.txTimeout = timeout
+	}
+}
+
 // TransactionExecutor is a generic struct that abstracts away from the type of
 // query a type needs to run under a database transaction, and also the set of
 // options for that transaction. The QueryCreator is used to create a query
@@ -77,17 +211,26 @@ type TransactionExecutor[Query any] struct {
 	BatchedQuerier
 
 	createQuery QueryCreator[Query]
+
+	opts *txExecutorOptions
 }
 
 // NewTransactionExecutor creates a new instance of a TransactionExecutor given
 // a Querier query object and a concrete type for the type of transactions the
 // Querier understands.
 func NewTransactionExecutor[Querier any](db BatchedQuerier,
-	createQuery QueryCreator[Querier]) *TransactionExecutor[Querier] {
+	createQuery QueryCreator[Querier],
+	opts ...TxExecutorOption) *TransactionExecutor[Querier] {
+
+	txOpts := defaultTxExecutorOptions()
+	for _, optFunc := range opts {
+		optFunc(txOpts)
+	}
 
 	return &TransactionExecutor[Querier]{
 		BatchedQuerier: db,
 		createQuery:    createQuery,
+		opts:           txOpts,
 	}
 }
 
@@ -97,13 +240,54 @@ func NewTransactionExecutor[Querier any](db BatchedQuerier,
 // atomically. This can be used by other storage interfaces to parameterize the
 // type of query and options run, in order to have access to batched operations
 // related to a storage object.
+//
+// If the transaction fails to be serialized with concurrent transactions, it
+// is rolled back and retried after a backoff delay. As a result, txBody may be
+// executed several times and must reset any state it captures from outside of
+// the transaction.
 func (t *TransactionExecutor[Q]) ExecTx(ctx context.Context,
 	txOptions TxOptions, txBody func(Q) error) error {
 
+	if t.opts.txTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, t.opts.txTimeout)
+		defer cancel()
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := t.execTxOnce(ctx, txOptions, txBody)
+		if !IsSerializationError(err) {
+			return err
+		}
+
+		if attempt >= t.opts.numRetries {
+			return fmt.Errorf("%w after %d attempts: %v",
+				ErrRetriesExceeded, attempt+1, err)
+		}
+
+		delay := t.opts.randRetryDelay(attempt)
+		log.Debugf("Retrying db transaction in %v after serialization "+
+			"error (attempt %d): %v", delay, attempt+1, err)
+
+		select {
+		case <-time.After(delay):
+
+		case <-ctx.Done():
+			return fmt.Errorf("unable to retry db transaction: %w "+
+				"(last error: %v)", ctx.Err(), err)
+		}
+	}
+}
+
+// execTxOnce executes the passed txBody in a single db transaction, without
+// retrying it on failure.
+func (t *TransactionExecutor[Q]) execTxOnce(ctx context.Context,
+	txOptions TxOptions, txBody func(Q) error) error {
+
 	// Create the db transaction.
 	tx, err := t.BatchedQuerier.BeginTx(ctx, txOptions)
 	if err != nil {
-		return err
+		return MapSQLError(err)
 	}
 
 	// Rollback is safe to call even if the tx is already closed, so if the
@@ -117,8 +301,6 @@ func (t *TransactionExecutor[Q]) ExecTx(ctx context.Context,
 	}
 
 	// Commit transaction.
-	//
-	// TODO(roasbeef): need to handle SQLITE_BUSY here?
 	if err = tx.Commit(); err != nil {
 		return MapSQLError(err)
 	}
@@ -0,0 +1,103 @@
+package tapdb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lightninglabs/taproot-assets/tapdb/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+// TestExecTxRetry tests that transactions that fail to be serialized are
+// rolled back and retried, while any other error is returned right away.
+func TestExecTxRetry(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	db := NewTestDB(t)
+
+	newExecutor := func(
+		opts ...TxExecutorOption) *TransactionExecutor[KeyStore] {
+
+		// We don't want to slow down the test with long backoff delays.
+		fastRetries := WithTxRetryDelay(0, time.Millisecond)
+		opts = append([]TxExecutorOption{fastRetries}, opts...)
+
+		return NewTransactionExecutor(db, func(tx *sql.Tx) KeyStore {
+			return db.WithTx(tx)
+		}, opts...)
+	}
+
+	serializationErr := &ErrSerializationError{
+		DbError: errors.New("database is locked"),
+	}
+
+	// A transaction that fails to be serialized twice is committed on the
+	// third attempt. Each attempt inserts the same root key, which would
+	// violate the unique constraint if the failed attempts weren't rolled
+	// back.
+	var numAttempts int
+	err := newExecutor().ExecTx(
+		ctx, WriteTxOption(), func(q KeyStore) error {
+			numAttempts++
+
+			err := q.InsertRootKey(ctx, sqlc.InsertRootKeyParams{
+				ID:      []byte("id"),
+				RootKey: []byte("key"),
+			})
+			if err != nil {
+				return err
+			}
+
+			if numAttempts < 3 {
+				return serializationErr
+			}
+
+			return nil
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, 3, numAttempts)
+
+	rootKey, err := db.GetRootKey(ctx, []byte("id"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("key"), rootKey.RootKey)
+
+	// Once the retries are exhausted, the transaction fails.
+	numAttempts = 0
+	err = newExecutor(WithTxRetries(2)).ExecTx(
+		ctx, WriteTxOption(), func(q KeyStore) error {
+			numAttempts++
+			return serializationErr
+		},
+	)
+	require.ErrorIs(t, err, ErrRetriesExceeded)
+	require.Equal(t, 3, numAttempts)
+
+	// Any other error isn't retried.
+	numAttempts = 0
+	err = newExecutor().ExecTx(
+		ctx, ReadTxOption(), func(q KeyStore) error {
+			numAttempts++
+			return sql.ErrNoRows
+		},
+	)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	require.Equal(t, 1, numAttempts)
+
+	// If the deadline of the call expires while waiting for the next
+	// retry, we give up.
+	numAttempts = 0
+	err = newExecutor(
+		WithTxRetryDelay(time.Hour, time.Hour),
+		WithTxTimeout(10*time.Millisecond),
+	).ExecTx(ctx, WriteTxOption(), func(q KeyStore) error {
+		numAttempts++
+		return serializationErr
+	})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Equal(t, 1, numAttempts)
+}
@@ -98,17 +98,22 @@ type BatchedTreeStore interface {
 type TaprootAssetTreeStore struct {
 	db        BatchedTreeStore
 	namespace string
+
+	nodeCache *TreeNodeCache
 }
 
 // NewTaprootAssetTreeStore creates a new TaprootAssetTreeStore instance given
 // an open BatchedTreeStore storage backend. The namespace argument is required,
 // as it allow us to store several distinct trees on disk in the same table.
-func NewTaprootAssetTreeStore(db BatchedTreeStore,
-	namespace string) *TaprootAssetTreeStore {
+// The optional node cache is used to serve nodes without hitting the database,
+// passing nil disables caching.
+func NewTaprootAssetTreeStore(db BatchedTreeStore, namespace string,
+	nodeCache *TreeNodeCache) *TaprootAssetTreeStore {
 
 	return &TaprootAssetTreeStore{
 		db:        db,
 		namespace: namespace,
+		nodeCache: nodeCache,
 	}
 }
 
@@ -119,18 +124,29 @@ var _ mssmt.TreeStore = (*TaprootAssetTreeStore)(nil)
 func (t *TaprootAssetTreeStore) Update(ctx context.Context,
 	update func(tx mssmt.TreeStoreUpdateTx) error) error {
 
+	var cacheTx *treeNodeCacheTx
 	txBody := func(dbTx TreeStore) error {
+		cacheTx = t.nodeCache.newTx(t.namespace)
 		updateTx := &taprootAssetTreeStoreTx{
 			ctx:       ctx,
 			dbTx:      dbTx,
 			namespace: t.namespace,
+			nodeCache: cacheTx,
 		}
 
 		return update(updateTx)
 	}
 
 	var writeTxOpts TreeStoreTxOptions
-	return t.db.ExecTx(ctx, &writeTxOpts, txBody)
+	if err := t.db.ExecTx(ctx, &writeTxOpts, txBody); err != nil {
+		return err
+	}
+
+	// Only now that the transaction is committed can we add the nodes we
+	// read and wrote to the cache.
+	cacheTx.commit()
+
+	return nil
 }
 
 // View gives a view of the persistent tree in the passed view closure using
@@ -138,11 +154,14 @@ func (t *TaprootAssetTreeStore) Update(ctx context.Context,
 func (t *TaprootAssetTreeStore) View(ctx context.Context,
 	update func(tx mssmt.TreeStoreViewTx) error) error {
 
+	var cacheTx *treeNodeCacheTx
 	txBody := func(dbTx TreeStore) error {
+		cacheTx = t.nodeCache.newTx(t.namespace)
 		viewTx := &taprootAssetTreeStoreTx{
 			ctx:       ctx,
 			dbTx:      dbTx,
 			namespace: t.namespace,
+			nodeCache: cacheTx,
 		}
 
 		return update(viewTx)
@@ -152,13 +171,23 @@ func (t *TaprootAssetTreeStore) View(ctx context.Context,
 		readOnly: true,
 	}
 
-	return t.db.ExecTx(ctx, &readTxOpts, txBody)
+	if err := t.db.ExecTx(ctx, &readTxOpts, txBody); err != nil {
+		return err
+	}
+
+	cacheTx.commit()
+
+	return nil
 }
 
 type taprootAssetTreeStoreTx struct {
 	ctx       context.Context
 	dbTx      TreeStore
 	namespace string
+
+	// nodeCache is the node cache of this transaction. If nil, then all
+	// nodes are read from the database.
+	nodeCache *treeNodeCacheTx
 }
 
 // InsertBranch stores a new branch keyed by its NodeHash.
@@ -177,6 +206,12 @@ func (t *taprootAssetTreeStoreTx) InsertBranch(branch *mssmt.BranchNode) error {
 		return fmt.Errorf("unable to insert branch: %w", err)
 	}
 
+	t.nodeCache.stage(hashKey, &cachedNode{
+		node:     mssmt.NewComputedBranch(hashKey, branch.NodeSum()),
+		lHashKey: lHashKey,
+		rHashKey: rHashKey,
+	})
+
 	return nil
 }
 
@@ -193,6 +228,14 @@ func (t *taprootAssetTreeStoreTx) InsertLeaf(leaf *mssmt.LeafNode) error {
 		return fmt.Errorf("unable to insert leaf: %w", err)
 	}
 
+	// We cache a copy of the leaf, as the caller may still hold on to the
+	// one passed in.
+	cachedLeaf := mssmt.NewLeafNode(leaf.Value, leaf.NodeSum())
+	cachedLeaf.NodeHash()
+	t.nodeCache.stage(hashKey, &cachedNode{
+		node: cachedLeaf,
+	})
+
 	return nil
 }
 
@@ -214,11 +257,17 @@ func (t *taprootAssetTreeStoreTx) InsertCompactedLeaf(
 		return fmt.Errorf("unable to insert compacted leaf: %w", err)
 	}
 
+	t.nodeCache.stage(hashKey, &cachedNode{
+		node: leaf,
+	})
+
 	return nil
 }
 
 // DeleteBranch deletes the branch node keyed by the given NodeHash.
 func (t *taprootAssetTreeStoreTx) DeleteBranch(hashKey mssmt.NodeHash) error {
+	t.nodeCache.remove(hashKey)
+
 	_, err := t.dbTx.DeleteNode(t.ctx, DelNode{
 		HashKey:   hashKey[:],
 		Namespace: t.namespace,
@@ -228,6 +277,8 @@ func (t *taprootAssetTreeStoreTx) DeleteBranch(hashKey mssmt.NodeHash) error {
 
 // DeleteLeaf deletes the leaf node keyed by the given NodeHash.
 func (t *taprootAssetTreeStoreTx) DeleteLeaf(hashKey mssmt.NodeHash) error {
+	t.nodeCache.remove(hashKey)
+
 	_, err := t.dbTx.DeleteNode(t.ctx, DelNode{
 		HashKey:   hashKey[:],
 		Namespace: t.namespace,
@@ -237,6 +288,8 @@ func (t *taprootAssetTreeStoreTx) DeleteLeaf(hashKey mssmt.NodeHash) error {
 
 // DeleteCompactedLeaf deletes a compacted leaf keyed by the given NodeHash.
 func (t *taprootAssetTreeStoreTx) DeleteCompactedLeaf(hashKey mssmt.NodeHash) error {
+	t.nodeCache.remove(hashKey)
+
 	_, err := t.dbTx.DeleteNode(t.ctx, DelNode{
 		HashKey:   hashKey[:],
 		Namespace: t.namespace,
@@ -262,6 +315,13 @@ func newKey(data []byte) ([32]byte, error) {
 func (t *taprootAssetTreeStoreTx) GetChildren(height int, hashKey mssmt.NodeHash) (
 	mssmt.Node, mssmt.Node, error) {
 
+	// Before we hit the database, we'll check if we can serve both
+	// children from the node cache.
+	left, right, ok := t.cachedChildren(height, hashKey)
+	if ok {
+		return left, right, nil
+	}
+
 	dbRows, err := t.dbTx.FetchChildren(t.ctx, ChildQuery{
 		HashKey:   hashKey[:],
 		Namespace: t.namespace,
@@ -270,10 +330,8 @@ func (t *taprootAssetTreeStoreTx) GetChildren(height int, hashKey mssmt.NodeHash
 		return nil, nil, err
 	}
 
-	var (
-		left  mssmt.Node = mssmt.EmptyTree[height+1]
-		right mssmt.Node = mssmt.EmptyTree[height+1]
-	)
+	left = mssmt.EmptyTree[height+1]
+	right = mssmt.EmptyTree[height+1]
 
 	var lHashKey, rHashKey []byte
 
@@ -283,6 +341,12 @@ func (t *taprootAssetTreeStoreTx) GetChildren(height int, hashKey mssmt.NodeHash
 			// children, so we skip this node.
 			lHashKey = row.LHashKey
 			rHashKey = row.RHashKey
+
+			err := t.cacheBranchRow(hashKey, row)
+			if err != nil {
+				return nil, nil, err
+			}
+
 			continue
 		}
 
@@ -318,6 +382,10 @@ func (t *taprootAssetTreeStoreTx) GetChildren(height int, hashKey mssmt.NodeHash
 			} else {
 				node = leaf
 			}
+
+			t.nodeCache.stage(node.NodeHash(), &cachedNode{
+				node: node,
+			})
 		} else {
 			hashKey, err := newKey(row.HashKey)
 			if err != nil {
@@ -325,6 +393,10 @@ func (t *taprootAssetTreeStoreTx) GetChildren(height int, hashKey mssmt.NodeHash
 			}
 
 			node = mssmt.NewComputedBranch(hashKey, uint64(row.Sum))
+
+			if err := t.cacheBranchRow(hashKey, row); err != nil {
+				return nil, nil, err
+			}
 		}
 
 		if isLeft {
@@ -337,6 +409,79 @@ func (t *taprootAssetTreeStoreTx) GetChildren(height int, hashKey mssmt.NodeHash
 	return left, right, nil
 }
 
+// cacheBranchRow stages the branch stored in the given row in the node cache.
+// Rows that don't belong to a branch are ignored.
+func (t *taprootAssetTreeStoreTx) cacheBranchRow(hashKey mssmt.NodeHash,
+	row StoredNode) error {
+
+	if !t.nodeCache.enabled() {
+		return nil
+	}
+
+	if row.LHashKey == nil || row.RHashKey == nil {
+		return nil
+	}
+
+	lHashKey, err := newKey(row.LHashKey)
+	if err != nil {
+		return err
+	}
+	rHashKey, err := newKey(row.RHashKey)
+	if err != nil {
+		return err
+	}
+
+	t.nodeCache.stage(hashKey, &cachedNode{
+		node:     mssmt.NewComputedBranch(hashKey, uint64(row.Sum)),
+		lHashKey: lHashKey,
+		rHashKey: rHashKey,
+	})
+
+	return nil
+}
+
+// cachedChildren attempts to serve the children of the branch keyed by the
+// given NodeHash from the node cache. False is returned if the branch or any
+// of its non-empty children isn't cached.
+func (t *taprootAssetTreeStoreTx) cachedChildren(height int,
+	hashKey mssmt.NodeHash) (mssmt.Node, mssmt.Node, bool) {
+
+	branch, ok := t.nodeCache.fetch(hashKey)
+	if !ok || !branch.isBranch() {
+		return nil, nil, false
+	}
+
+	left, ok := t.cachedChild(height, branch.lHashKey)
+	if !ok {
+		return nil, nil, false
+	}
+	right, ok := t.cachedChild(height, branch.rHashKey)
+	if !ok {
+		return nil, nil, false
+	}
+
+	return left, right, true
+}
+
+// cachedChild returns the child at the given height of a branch keyed by the
+// given NodeHash from the node cache. Empty children are never stored, so
+// those are returned directly.
+func (t *taprootAssetTreeStoreTx) cachedChild(height int,
+	hashKey mssmt.NodeHash) (mssmt.Node, bool) {
+
+	empty := mssmt.EmptyTree[height+1]
+	if hashKey == empty.NodeHash() {
+		return empty, true
+	}
+
+	child, ok := t.nodeCache.fetch(hashKey)
+	if !ok {
+		return nil, false
+	}
+
+	return child.node, true
+}
+
 // RootNode returns the root nodes of the MS-SMT. If the tree has no elements,
 // then a nil node is returned.
 func (t *taprootAssetTreeStoreTx) RootNode() (mssmt.Node, error) {
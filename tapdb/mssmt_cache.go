@@ -0,0 +1,166 @@
+package tapdb
+
+import (
+	"github.com/lightninglabs/neutrino/cache/lru"
+	"github.com/lightninglabs/taproot-assets/mssmt"
+)
+
+const (
+	// DefaultTreeNodeCacheSize is the default number of MS-SMT nodes that
+	// are kept in memory by a TreeNodeCache.
+	DefaultTreeNodeCacheSize = 100_000
+)
+
+// nodeCacheKey is the key of a node in the TreeNodeCache. As several trees
+// share the same table on disk, the node hash is scoped by the namespace of
+// its tree.
+type nodeCacheKey struct {
+	namespace string
+	hashKey   mssmt.NodeHash
+}
+
+// cachedNode is a single MS-SMT node stored in the TreeNodeCache.
+type cachedNode struct {
+	// node is the node itself. Branches are stored as computed branches,
+	// leaves and compacted leaves are stored as is.
+	node mssmt.Node
+
+	// lHashKey and rHashKey are the hashes of the children of a branch
+	// node. They are only set for branches.
+	lHashKey mssmt.NodeHash
+	rHashKey mssmt.NodeHash
+}
+
+// isBranch returns true if the cached node is a branch.
+func (c *cachedNode) isBranch() bool {
+	_, ok := c.node.(*mssmt.BranchNode)
+	return ok
+}
+
+// Size returns the size of the cached node. Each node counts as a single
+// element, so the capacity of the cache is expressed in number of nodes.
+//
+// NOTE: This implements the cache.Value interface.
+func (c *cachedNode) Size() (uint64, error) {
+	return 1, nil
+}
+
+// TreeNodeCache is an LRU cache of MS-SMT nodes that sits in front of the
+// nodes stored on disk. As nodes are keyed by their hash, a node never changes
+// once stored, which means cached entries never need to be updated, only
+// removed once the node is deleted.
+//
+// NOTE: A single cache can safely be shared between several trees, as all
+// entries are scoped by the namespace of their tree.
+type TreeNodeCache struct {
+	cache *lru.Cache[nodeCacheKey, *cachedNode]
+}
+
+// NewTreeNodeCache creates a new node cache that holds at most the given
+// number of nodes.
+func NewTreeNodeCache(capacity uint64) *TreeNodeCache {
+	return &TreeNodeCache{
+		cache: lru.NewCache[nodeCacheKey, *cachedNode](capacity),
+	}
+}
+
+// Len returns the number of nodes currently in the cache.
+func (c *TreeNodeCache) Len() int {
+	return c.cache.Len()
+}
+
+// newTx creates a new cache transaction for the tree with the given
+// namespace. A nil cache results in a cache transaction that never caches
+// anything.
+func (c *TreeNodeCache) newTx(namespace string) *treeNodeCacheTx {
+	return &treeNodeCacheTx{
+		cache:     c,
+		namespace: namespace,
+		staged:    make(map[mssmt.NodeHash]*cachedNode),
+	}
+}
+
+// treeNodeCacheTx tracks the nodes read or written within a single database
+// transaction. The nodes are only added to the shared cache once the database
+// transaction is committed, so a rolled back transaction never leaves nodes
+// in the cache that don't exist on disk.
+type treeNodeCacheTx struct {
+	cache     *TreeNodeCache
+	namespace string
+
+	staged map[mssmt.NodeHash]*cachedNode
+}
+
+// enabled returns true if there's a cache to read from and write to.
+func (t *treeNodeCacheTx) enabled() bool {
+	return t != nil && t.cache != nil
+}
+
+// fetch looks up the node with the given hash, first in the set of nodes
+// staged in this transaction, then in the shared cache.
+func (t *treeNodeCacheTx) fetch(hashKey mssmt.NodeHash) (*cachedNode, bool) {
+	if !t.enabled() {
+		return nil, false
+	}
+
+	if node, ok := t.staged[hashKey]; ok {
+		return node, true
+	}
+
+	node, err := t.cache.cache.Get(nodeCacheKey{
+		namespace: t.namespace,
+		hashKey:   hashKey,
+	})
+	if err != nil {
+		return nil, false
+	}
+
+	return node, true
+}
+
+// stage adds the given node to the set of nodes that are added to the shared
+// cache once the transaction is committed.
+func (t *treeNodeCacheTx) stage(hashKey mssmt.NodeHash, node *cachedNode) {
+	if !t.enabled() {
+		return
+	}
+
+	t.staged[hashKey] = node
+}
+
+// remove removes the node with the given hash from both the staged nodes and
+// the shared cache. The node is removed from the shared cache right away, as
+// serving a node that was deleted on disk would be incorrect while serving a
+// node from disk that is no longer cached is always safe.
+func (t *treeNodeCacheTx) remove(hashKey mssmt.NodeHash) {
+	if !t.enabled() {
+		return
+	}
+
+	delete(t.staged, hashKey)
+	t.cache.cache.Delete(nodeCacheKey{
+		namespace: t.namespace,
+		hashKey:   hashKey,
+	})
+}
+
+// commit adds all staged nodes to the shared cache. This must only be called
+// once the database transaction the nodes were read or written in has been
+// committed.
+func (t *treeNodeCacheTx) commit() {
+	if !t.enabled() {
+		return
+	}
+
+	for hashKey, node := range t.staged {
+		// The only error that can be returned is if the node is larger
+		// than the capacity of the cache, in which case there's
+		// nothing to cache anyway.
+		_, _ = t.cache.cache.Put(nodeCacheKey{
+			namespace: t.namespace,
+			hashKey:   hashKey,
+		}, node)
+	}
+
+	t.staged = make(map[mssmt.NodeHash]*cachedNode)
+}
@@ -0,0 +1,63 @@
+package tapdb
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/lightninglabs/taproot-assets/mssmt"
+)
+
+const (
+	// sqliteTreeStoreDriverName is the name of the sqlite backed MS-SMT
+	// tree store driver.
+	sqliteTreeStoreDriverName = "sqlite3"
+)
+
+// newSqliteTreeStore creates a new sqlite backed MS-SMT tree store with a
+// node cache of the default size. The expected arguments are the full path of
+// the database file and the namespace of the tree.
+func newSqliteTreeStore(args ...any) (mssmt.TreeStore, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("expected 2 arguments, got %d",
+			len(args))
+	}
+
+	dbFileName, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("expected database file name, got %T",
+			args[0])
+	}
+
+	namespace, ok := args[1].(string)
+	if !ok {
+		return nil, fmt.Errorf("expected namespace, got %T", args[1])
+	}
+
+	sqlDB, err := NewSqliteStore(&SqliteConfig{
+		DatabaseFileName: dbFileName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to open sqlite db: %w", err)
+	}
+
+	treeDB := NewTransactionExecutor(sqlDB,
+		func(tx *sql.Tx) TreeStore {
+			return sqlDB.WithTx(tx)
+		},
+	)
+
+	return NewTaprootAssetTreeStore(
+		treeDB, namespace, NewTreeNodeCache(DefaultTreeNodeCacheSize),
+	), nil
+}
+
+func init() {
+	err := mssmt.RegisterTreeStore(&mssmt.TreeStoreDriver{
+		Name: sqliteTreeStoreDriverName,
+		New:  newSqliteTreeStore,
+	})
+	if err != nil {
+		panic(fmt.Sprintf("unable to register tree store driver: %v",
+			err))
+	}
+}
@@ -3,6 +3,9 @@ package tapdb
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"math/rand"
+	"sync/atomic"
 	"testing"
 
 	"github.com/lightninglabs/taproot-assets/mssmt"
@@ -23,7 +26,9 @@ func newTaprootAssetTreeStore(t *testing.T,
 
 	treeDB := NewTransactionExecutor(db, txCreator)
 
-	return NewTaprootAssetTreeStore(treeDB, namespace), db
+	nodeCache := NewTreeNodeCache(DefaultTreeNodeCacheSize)
+
+	return NewTaprootAssetTreeStore(treeDB, namespace, nodeCache), db
 }
 
 // assertNodesEq is a helper to check equivalency or equality based on the
@@ -683,3 +688,149 @@ func TestTreeNamespaceIsolation(t *testing.T) {
 	})
 	require.NoError(t, err)
 }
+
+// countingTreeStore is a TreeStore that counts the number of times the
+// children of a node are fetched from the database.
+type countingTreeStore struct {
+	TreeStore
+
+	numFetches *atomic.Int32
+}
+
+// FetchChildren fetches the children of the passed branch hash key and
+// increments the fetch counter.
+func (c *countingTreeStore) FetchChildren(ctx context.Context,
+	q ChildQuery) ([]StoredNode, error) {
+
+	c.numFetches.Add(1)
+
+	return c.TreeStore.FetchChildren(ctx, q)
+}
+
+// TestTreeNodeCache tests that the node cache serves the nodes of a tree
+// without hitting the database, and that a tree backed by a cache always
+// matches the same tree read directly from the database.
+func TestTreeNodeCache(t *testing.T) {
+	t.Parallel()
+
+	const (
+		namespace = "cached"
+		numLeaves = 50
+	)
+
+	db := NewTestDB(t)
+
+	var numFetches atomic.Int32
+	treeDB := NewTransactionExecutor(db, func(tx *sql.Tx) TreeStore {
+		return &countingTreeStore{
+			TreeStore:  db.WithTx(tx),
+			numFetches: &numFetches,
+		}
+	})
+
+	nodeCache := NewTreeNodeCache(DefaultTreeNodeCacheSize)
+	cachedTree := mssmt.NewCompactedTree(
+		NewTaprootAssetTreeStore(treeDB, namespace, nodeCache),
+	)
+	dbTree := mssmt.NewCompactedTree(
+		NewTaprootAssetTreeStore(treeDB, namespace, nil),
+	)
+	smallCache := NewTreeNodeCache(10)
+	smallCacheTree := mssmt.NewCompactedTree(
+		NewTaprootAssetTreeStore(treeDB, namespace, smallCache),
+	)
+
+	ctx := context.Background()
+	leaves := make(map[[32]byte]*mssmt.LeafNode, numLeaves)
+	for i := 0; i < numLeaves; i++ {
+		var key [32]byte
+		_, _ = rand.Read(key[:])
+
+		value := make([]byte, 32)
+		_, _ = rand.Read(value)
+		leaf := mssmt.NewLeafNode(value, uint64(i+1))
+
+		_, err := cachedTree.Insert(ctx, key, leaf)
+		require.NoError(t, err)
+
+		leaves[key] = leaf
+	}
+
+	// We'll also delete some of the leaves again, to make sure deleted
+	// nodes are removed from the cache.
+	var deletedKeys [][32]byte
+	for key := range leaves {
+		if len(deletedKeys) == numLeaves/5 {
+			break
+		}
+
+		_, err := cachedTree.Delete(ctx, key)
+		require.NoError(t, err)
+
+		deletedKeys = append(deletedKeys, key)
+		delete(leaves, key)
+	}
+	require.NotZero(t, nodeCache.Len())
+
+	rootHash := func(tree *mssmt.CompactedTree) mssmt.NodeHash {
+		root, err := tree.Root(ctx)
+		require.NoError(t, err)
+		return root.NodeHash()
+	}
+	expectedRoot := rootHash(dbTree)
+	require.Equal(t, expectedRoot, rootHash(cachedTree))
+
+	// All the nodes read or written while modifying the tree are now
+	// cached, so we don't expect any children to be fetched from the
+	// database when querying the tree through the cache.
+	numFetches.Store(0)
+	for key, leaf := range leaves {
+		dbLeaf, err := cachedTree.Get(ctx, key)
+		require.NoError(t, err)
+		require.Equal(t, leaf.NodeHash(), dbLeaf.NodeHash())
+
+		proof, err := cachedTree.MerkleProof(ctx, key)
+		require.NoError(t, err)
+		require.Equal(
+			t, expectedRoot, proof.Root(key, leaf).NodeHash(),
+		)
+	}
+	require.Zero(t, numFetches.Load())
+
+	// The deleted leaves must no longer be found, both through the cache
+	// and when reading from the database directly.
+	trees := []*mssmt.CompactedTree{cachedTree, dbTree}
+	for _, key := range deletedKeys {
+		for _, tree := range trees {
+			leaf, err := tree.Get(ctx, key)
+			require.NoError(t, err)
+			require.True(t, leaf.IsEmpty())
+		}
+	}
+
+	// A cache that is too small to hold the whole tree must still result
+	// in the same tree, as evicted nodes are read from the database.
+	for key, leaf := range leaves {
+		proof, err := smallCacheTree.MerkleProof(ctx, key)
+		require.NoError(t, err)
+		require.Equal(
+			t, expectedRoot, proof.Root(key, leaf).NodeHash(),
+		)
+	}
+	require.LessOrEqual(t, smallCache.Len(), 10)
+
+	// Finally, nodes written in a transaction that is rolled back must
+	// not end up in the cache.
+	numCached := nodeCache.Len()
+	errRollback := errors.New("rollback")
+	err := NewTaprootAssetTreeStore(treeDB, namespace, nodeCache).Update(
+		ctx, func(tx mssmt.TreeStoreUpdateTx) error {
+			leaf := mssmt.NewLeafNode([]byte{1, 2, 3}, 1)
+			require.NoError(t, tx.InsertLeaf(leaf))
+
+			return errRollback
+		},
+	)
+	require.ErrorIs(t, err, errRollback)
+	require.Equal(t, numCached, nodeCache.Len())
+}
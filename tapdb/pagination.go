@@ -0,0 +1,96 @@
+package tapdb
+
+import (
+	"database/sql"
+	"encoding/binary"
+	"errors"
+)
+
+const (
+	// assetCursorSize is the size of an encoded asset cursor, made up of
+	// the amount and the primary key of the asset it points to.
+	assetCursorSize = 8 + 4
+
+	// transferCursorSize is the size of an encoded transfer cursor, made up
+	// of the primary key of the transfer it points to.
+	transferCursorSize = 4
+)
+
+var (
+	// ErrInvalidCursor is returned if a page is requested with a cursor
+	// that wasn't returned by a query of the same kind.
+	ErrInvalidCursor = errors.New("invalid page cursor")
+)
+
+// PageParams describes which page of a sorted query result should be
+// returned.
+type PageParams struct {
+	// Limit is the maximum number of results that are returned. If zero,
+	// all results are returned.
+	Limit int32
+
+	// Cursor is the opaque cursor that was returned along with the
+	// previous page. If nil, the first page is returned.
+	Cursor []byte
+
+	// Descending indicates that the results should be sorted in
+	// descending order.
+	Descending bool
+}
+
+// numLimit maps the page limit to the limit used in the SQL queries.
+func (p PageParams) numLimit() sql.NullInt32 {
+	if p.Limit <= 0 {
+		return sql.NullInt32{}
+	}
+
+	return sqlInt32(p.Limit)
+}
+
+// isFull returns true if the given number of results fills a page, which
+// means that there might be more results on the next page.
+func (p PageParams) isFull(numResults int) bool {
+	return p.Limit > 0 && numResults >= int(p.Limit)
+}
+
+// encodeAssetCursor encodes the cursor of an asset with the given amount and
+// primary key.
+func encodeAssetCursor(amount int64, assetPrimaryKey int32) []byte {
+	var cursor [assetCursorSize]byte
+	binary.BigEndian.PutUint64(cursor[:8], uint64(amount))
+	binary.BigEndian.PutUint32(cursor[8:], uint32(assetPrimaryKey))
+
+	return cursor[:]
+}
+
+// decodeAssetCursor decodes the amount and primary key of the asset the given
+// cursor points to.
+func decodeAssetCursor(cursor []byte) (int64, int32, error) {
+	if len(cursor) != assetCursorSize {
+		return 0, 0, ErrInvalidCursor
+	}
+
+	amount := int64(binary.BigEndian.Uint64(cursor[:8]))
+	assetPrimaryKey := int32(binary.BigEndian.Uint32(cursor[8:]))
+
+	return amount, assetPrimaryKey, nil
+}
+
+// encodeTransferCursor encodes the cursor of the transfer with the given
+// primary key.
+func encodeTransferCursor(transferID int32) []byte {
+	var cursor [transferCursorSize]byte
+	binary.BigEndian.PutUint32(cursor[:], uint32(transferID))
+
+	return cursor[:]
+}
+
+// decodeTransferCursor decodes the primary key of the transfer the given
+// cursor points to.
+func decodeTransferCursor(cursor []byte) (int32, error) {
+	if len(cursor) != transferCursorSize {
+		return 0, ErrInvalidCursor
+	}
+
+	return int32(binary.BigEndian.Uint32(cursor)), nil
+}
@@ -14,6 +14,20 @@ import (
 
 const (
 	dsnTemplate = "postgres://%v:%v@%v:%d/%v?sslmode=%v"
+
+	// DefaultPostgresMaxConns is the default number of open connections to
+	// the database server we keep in the connection pool.
+	DefaultPostgresMaxConns = 10
+
+	// DefaultPostgresConnLifetime is the default maximum amount of time
+	// a connection to the database server is reused for. Recycling
+	// connections allows a managed database to move them to a different
+	// server, for example after a fail over.
+	DefaultPostgresConnLifetime = 30 * time.Minute
+
+	// DefaultPostgresConnIdleTime is the default maximum amount of time
+	// a connection to the database server can be idle before it's closed.
+	DefaultPostgresConnIdleTime = 5 * time.Minute
 )
 
 var (
@@ -35,6 +49,10 @@ type PostgresConfig struct {
 	DBName             string `long:"dbname" description:"Database name to use."`
 	MaxOpenConnections int32  `long:"maxconnections" description:"Max open connections to keep alive to the database server."`
 	RequireSSL         bool   `long:"requiressl" description:"Whether to require using SSL (mode: require) when connecting to the server."`
+
+	MaxIdleConnections int32         `long:"maxidleconnections" description:"Max idle connections to keep in the connection pool. If 0, the number of max open connections is used."`
+	ConnMaxLifetime    time.Duration `long:"connmaxlifetime" description:"Max amount of time a connection to the database server is reused for. If 0, connections are reused forever."`
+	ConnMaxIdleTime    time.Duration `long:"connmaxidletime" description:"Max amount of time a connection to the database server can be idle before it's closed. If 0, idle connections are kept forever."`
 }
 
 // DSN returns the dns to connect to the database.
@@ -54,6 +72,33 @@ func (s *PostgresConfig) DSN(hidePassword bool) string {
 		s.DBName, sslMode)
 }
 
+// connPool is the part of a database handle that configures its connection
+// pool.
+type connPool interface {
+	SetMaxOpenConns(n int)
+	SetMaxIdleConns(n int)
+	SetConnMaxLifetime(d time.Duration)
+	SetConnMaxIdleTime(d time.Duration)
+}
+
+// configureConnPool applies the connection pool settings of the given config
+// to the connection pool, falling back to the defaults for unset limits.
+func configureConnPool(pool connPool, cfg *PostgresConfig) {
+	maxConns := int(cfg.MaxOpenConnections)
+	if maxConns <= 0 {
+		maxConns = DefaultPostgresMaxConns
+	}
+	maxIdleConns := int(cfg.MaxIdleConnections)
+	if maxIdleConns <= 0 {
+		maxIdleConns = maxConns
+	}
+
+	pool.SetMaxOpenConns(maxConns)
+	pool.SetMaxIdleConns(maxIdleConns)
+	pool.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	pool.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+}
+
 // PostgresStore is a database store implementation that uses a Postgres
 // backend.
 type PostgresStore struct {
@@ -72,6 +117,10 @@ func NewPostgresStore(cfg *PostgresConfig) (*PostgresStore, error) {
 		return nil, err
 	}
 
+	// We limit the size of the connection pool, so we don't exhaust the
+	// connections a (managed) database server allows for.
+	configureConnPool(rawDb, cfg)
+
 	if !cfg.SkipMigrations {
 		// Now that the database is open, populate the database with
 		// our set of schemas based on our embedded in-memory file
@@ -0,0 +1,109 @@
+package tapdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// mockConnPool is a connPool that records the settings applied to it.
+type mockConnPool struct {
+	maxOpenConns    int
+	maxIdleConns    int
+	connMaxLifetime time.Duration
+	connMaxIdleTime time.Duration
+}
+
+func (m *mockConnPool) SetMaxOpenConns(n int) {
+	m.maxOpenConns = n
+}
+
+func (m *mockConnPool) SetMaxIdleConns(n int) {
+	m.maxIdleConns = n
+}
+
+func (m *mockConnPool) SetConnMaxLifetime(d time.Duration) {
+	m.connMaxLifetime = d
+}
+
+func (m *mockConnPool) SetConnMaxIdleTime(d time.Duration) {
+	m.connMaxIdleTime = d
+}
+
+// TestConfigureConnPool tests that the connection pool settings of a Postgres
+// config are applied to the connection pool, with defaults for unset limits.
+func TestConfigureConnPool(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		cfg      *PostgresConfig
+		expected *mockConnPool
+	}{{
+		name: "defaults",
+		cfg:  &PostgresConfig{},
+		expected: &mockConnPool{
+			maxOpenConns: DefaultPostgresMaxConns,
+			maxIdleConns: DefaultPostgresMaxConns,
+		},
+	}, {
+		name: "idle connections default to max connections",
+		cfg: &PostgresConfig{
+			MaxOpenConnections: 3,
+			ConnMaxLifetime:    time.Minute,
+		},
+		expected: &mockConnPool{
+			maxOpenConns:    3,
+			maxIdleConns:    3,
+			connMaxLifetime: time.Minute,
+		},
+	}, {
+		name: "all settings",
+		cfg: &PostgresConfig{
+			MaxOpenConnections: 20,
+			MaxIdleConnections: 5,
+			ConnMaxLifetime:    time.Hour,
+			ConnMaxIdleTime:    time.Second,
+		},
+		expected: &mockConnPool{
+			maxOpenConns:    20,
+			maxIdleConns:    5,
+			connMaxLifetime: time.Hour,
+			connMaxIdleTime: time.Second,
+		},
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			pool := &mockConnPool{}
+			configureConnPool(pool, tc.cfg)
+
+			require.Equal(t, tc.expected, pool)
+		})
+	}
+}
+
+// TestNewPostgresStorePool tests that a new Postgres store limits the size of
+// its connection pool. Without migrations, no connection to the database
+// server is opened, so no server is needed.
+func TestNewPostgresStorePool(t *testing.T) {
+	t.Parallel()
+
+	store, err := NewPostgresStore(&PostgresConfig{
+		SkipMigrations:     true,
+		Host:               "localhost",
+		Port:               5432,
+		MaxOpenConnections: 3,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, store.DB.Close())
+	})
+
+	require.Equal(t, 3, store.DB.Stats().MaxOpenConnections)
+}
@@ -11,10 +11,51 @@ import (
 	"time"
 )
 
+const deleteAddr = `-- name: DeleteAddr :exec
+DELETE FROM addrs
+WHERE taproot_output_key = $1
+`
+
+func (q *Queries) DeleteAddr(ctx context.Context, taprootOutputKey []byte) error {
+	_, err := q.db.ExecContext(ctx, deleteAddr, taprootOutputKey)
+	return err
+}
+
+const deleteAddrEventStatusChanges = `-- name: DeleteAddrEventStatusChanges :exec
+DELETE FROM addr_event_status_changes
+WHERE event_id IN (
+    SELECT addr_events.id
+    FROM addr_events
+    JOIN addrs
+      ON addr_events.addr_id = addrs.id
+    WHERE addrs.taproot_output_key = $1
+)
+`
+
+func (q *Queries) DeleteAddrEventStatusChanges(ctx context.Context, taprootOutputKey []byte) error {
+	_, err := q.db.ExecContext(ctx, deleteAddrEventStatusChanges, taprootOutputKey)
+	return err
+}
+
+const deleteAddrEvents = `-- name: DeleteAddrEvents :exec
+DELETE FROM addr_events
+WHERE addr_id IN (
+    SELECT id
+    FROM addrs
+    WHERE addrs.taproot_output_key = $1
+)
+`
+
+func (q *Queries) DeleteAddrEvents(ctx context.Context, taprootOutputKey []byte) error {
+	_, err := q.db.ExecContext(ctx, deleteAddrEvents, taprootOutputKey)
+	return err
+}
+
 const fetchAddrByTaprootOutputKey = `-- name: FetchAddrByTaprootOutputKey :one
 SELECT
     version, genesis_asset_id, group_key, tapscript_sibling, taproot_output_key,
-    amount, asset_type, creation_time, managed_from,
+    amount, asset_type, creation_time, managed_from, proof_courier_addr,
+    static, any_asset_in_group, expiry, single_use,
     script_keys.tweaked_script_key,
     script_keys.tweak AS script_key_tweak,
     raw_script_keys.raw_key as raw_script_key,
@@ -43,6 +84,11 @@ type FetchAddrByTaprootOutputKeyRow struct {
 	AssetType        int16
 	CreationTime     time.Time
 	ManagedFrom      sql.NullTime
+	ProofCourierAddr []byte
+	Static           bool
+	AnyAssetInGroup  bool
+	Expiry           sql.NullTime
+	SingleUse        bool
 	TweakedScriptKey []byte
 	ScriptKeyTweak   []byte
 	RawScriptKey     []byte
@@ -66,6 +112,11 @@ func (q *Queries) FetchAddrByTaprootOutputKey(ctx context.Context, taprootOutput
 		&i.AssetType,
 		&i.CreationTime,
 		&i.ManagedFrom,
+		&i.ProofCourierAddr,
+		&i.Static,
+		&i.AnyAssetInGroup,
+		&i.Expiry,
+		&i.SingleUse,
 		&i.TweakedScriptKey,
 		&i.ScriptKeyTweak,
 		&i.RawScriptKey,
@@ -128,10 +179,46 @@ func (q *Queries) FetchAddrEvent(ctx context.Context, id int32) (FetchAddrEventR
 	return i, err
 }
 
+const fetchAddrEventStatusChanges = `-- name: FetchAddrEventStatusChanges :many
+SELECT status, change_time
+FROM addr_event_status_changes
+WHERE event_id = $1
+ORDER BY status
+`
+
+type FetchAddrEventStatusChangesRow struct {
+	Status     int16
+	ChangeTime time.Time
+}
+
+func (q *Queries) FetchAddrEventStatusChanges(ctx context.Context, eventID int32) ([]FetchAddrEventStatusChangesRow, error) {
+	rows, err := q.db.QueryContext(ctx, fetchAddrEventStatusChanges, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FetchAddrEventStatusChangesRow
+	for rows.Next() {
+		var i FetchAddrEventStatusChangesRow
+		if err := rows.Scan(&i.Status, &i.ChangeTime); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const fetchAddrs = `-- name: FetchAddrs :many
 SELECT 
     version, genesis_asset_id, group_key, tapscript_sibling, taproot_output_key,
-    amount, asset_type, creation_time, managed_from,
+    amount, asset_type, creation_time, managed_from, proof_courier_addr,
+    static, any_asset_in_group, expiry, single_use,
     script_keys.tweaked_script_key,
     script_keys.tweak AS script_key_tweak,
     raw_script_keys.raw_key AS raw_script_key,
@@ -151,16 +238,22 @@ WHERE creation_time >= $1
     AND creation_time <= $2
     AND ($3 = false OR
          (CASE WHEN managed_from IS NULL THEN true ELSE false END) = $3)
+    AND ($4 = false OR static = $4)
+    AND ($5 = false OR amount = 0)
+    AND ($6 = false OR any_asset_in_group = $6)
 ORDER BY addrs.creation_time
-LIMIT $5 OFFSET $4
+LIMIT $8 OFFSET $7
 `
 
 type FetchAddrsParams struct {
-	CreatedAfter  time.Time
-	CreatedBefore time.Time
-	UnmanagedOnly interface{}
-	NumOffset     int32
-	NumLimit      int32
+	CreatedAfter   time.Time
+	CreatedBefore  time.Time
+	UnmanagedOnly  interface{}
+	StaticOnly     interface{}
+	AmountlessOnly interface{}
+	GroupOnly      interface{}
+	NumOffset      int32
+	NumLimit       int32
 }
 
 type FetchAddrsRow struct {
@@ -173,6 +266,11 @@ type FetchAddrsRow struct {
 	AssetType        int16
 	CreationTime     time.Time
 	ManagedFrom      sql.NullTime
+	ProofCourierAddr []byte
+	Static           bool
+	AnyAssetInGroup  bool
+	Expiry           sql.NullTime
+	SingleUse        bool
 	TweakedScriptKey []byte
 	ScriptKeyTweak   []byte
 	RawScriptKey     []byte
@@ -188,6 +286,9 @@ func (q *Queries) FetchAddrs(ctx context.Context, arg FetchAddrsParams) ([]Fetch
 		arg.CreatedAfter,
 		arg.CreatedBefore,
 		arg.UnmanagedOnly,
+		arg.StaticOnly,
+		arg.AmountlessOnly,
+		arg.GroupOnly,
 		arg.NumOffset,
 		arg.NumLimit,
 	)
@@ -208,6 +309,11 @@ func (q *Queries) FetchAddrs(ctx context.Context, arg FetchAddrsParams) ([]Fetch
 			&i.AssetType,
 			&i.CreationTime,
 			&i.ManagedFrom,
+			&i.ProofCourierAddr,
+			&i.Static,
+			&i.AnyAssetInGroup,
+			&i.Expiry,
+			&i.SingleUse,
 			&i.TweakedScriptKey,
 			&i.ScriptKeyTweak,
 			&i.RawScriptKey,
@@ -233,8 +339,11 @@ func (q *Queries) FetchAddrs(ctx context.Context, arg FetchAddrsParams) ([]Fetch
 const insertAddr = `-- name: InsertAddr :one
 INSERT INTO addrs (
     version, genesis_asset_id, group_key, script_key_id, taproot_key_id,
-    tapscript_sibling, taproot_output_key, amount, asset_type, creation_time
-) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id
+    tapscript_sibling, taproot_output_key, amount, asset_type, creation_time,
+    proof_courier_addr, static, any_asset_in_group, expiry, single_use
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
+) RETURNING id
 `
 
 type InsertAddrParams struct {
@@ -248,6 +357,11 @@ type InsertAddrParams struct {
 	Amount           int64
 	AssetType        int16
 	CreationTime     time.Time
+	ProofCourierAddr []byte
+	Static           bool
+	AnyAssetInGroup  bool
+	Expiry           sql.NullTime
+	SingleUse        bool
 }
 
 func (q *Queries) InsertAddr(ctx context.Context, arg InsertAddrParams) (int32, error) {
@@ -262,12 +376,83 @@ func (q *Queries) InsertAddr(ctx context.Context, arg InsertAddrParams) (int32,
 		arg.Amount,
 		arg.AssetType,
 		arg.CreationTime,
+		arg.ProofCourierAddr,
+		arg.Static,
+		arg.AnyAssetInGroup,
+		arg.Expiry,
+		arg.SingleUse,
 	)
 	var id int32
 	err := row.Scan(&id)
 	return id, err
 }
 
+const insertAddrEventStatusChange = `-- name: InsertAddrEventStatusChange :exec
+INSERT INTO addr_event_status_changes (
+    event_id, status, change_time
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (event_id, status) DO NOTHING
+`
+
+type InsertAddrEventStatusChangeParams struct {
+	EventID    int32
+	Status     int16
+	ChangeTime time.Time
+}
+
+func (q *Queries) InsertAddrEventStatusChange(ctx context.Context, arg InsertAddrEventStatusChangeParams) error {
+	_, err := q.db.ExecContext(ctx, insertAddrEventStatusChange, arg.EventID, arg.Status, arg.ChangeTime)
+	return err
+}
+
+const queryDeadAddrs = `-- name: QueryDeadAddrs :many
+SELECT taproot_output_key
+FROM addrs
+WHERE ((expiry IS NOT NULL AND expiry <= $1) OR
+       (single_use = true AND EXISTS (
+           SELECT 1
+           FROM addr_events
+           WHERE addr_events.addr_id = addrs.id
+       )))
+    AND NOT EXISTS (
+        SELECT 1
+        FROM addr_events
+        WHERE addr_events.addr_id = addrs.id
+            AND addr_events.status != $2
+    )
+ORDER BY addrs.creation_time
+`
+
+type QueryDeadAddrsParams struct {
+	Now             sql.NullTime
+	CompletedStatus int16
+}
+
+func (q *Queries) QueryDeadAddrs(ctx context.Context, arg QueryDeadAddrsParams) ([][]byte, error) {
+	rows, err := q.db.QueryContext(ctx, queryDeadAddrs, arg.Now, arg.CompletedStatus)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items [][]byte
+	for rows.Next() {
+		var taproot_output_key []byte
+		if err := rows.Scan(&taproot_output_key); err != nil {
+			return nil, err
+		}
+		items = append(items, taproot_output_key)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const queryEventIDs = `-- name: QueryEventIDs :many
 SELECT
     addr_events.id as event_id, addrs.taproot_output_key as taproot_output_key
@@ -12,7 +12,7 @@ import (
 )
 
 const allAssets = `-- name: AllAssets :many
-SELECT asset_id, genesis_id, version, script_key_id, asset_group_sig_id, script_version, amount, lock_time, relative_lock_time, split_commitment_root_hash, split_commitment_root_value, anchor_utxo_id, spent 
+SELECT asset_id, genesis_id, version, script_key_id, asset_group_sig_id, script_version, amount, lock_time, relative_lock_time, split_commitment_root_hash, split_commitment_root_value, anchor_utxo_id, spent, non_divisible, edition 
 FROM assets
 `
 
@@ -39,6 +39,8 @@ func (q *Queries) AllAssets(ctx context.Context) ([]Asset, error) {
 			&i.SplitCommitmentRootValue,
 			&i.AnchorUtxoID,
 			&i.Spent,
+			&i.NonDivisible,
+			&i.Edition,
 		); err != nil {
 			return nil, err
 		}
@@ -87,7 +89,7 @@ func (q *Queries) AllInternalKeys(ctx context.Context) ([]InternalKey, error) {
 }
 
 const allMintingBatches = `-- name: AllMintingBatches :many
-SELECT batch_id, batch_state, minting_tx_psbt, change_output_index, genesis_id, height_hint, creation_time_unix, key_id, raw_key, key_family, key_index 
+SELECT batch_id, batch_state, minting_tx_psbt, change_output_index, genesis_id, height_hint, creation_time_unix, tapscript_sibling, key_id, raw_key, key_family, key_index 
 FROM asset_minting_batches
 JOIN internal_keys 
 ON asset_minting_batches.batch_id = internal_keys.key_id
@@ -101,6 +103,7 @@ type AllMintingBatchesRow struct {
 	GenesisID         sql.NullInt32
 	HeightHint        int32
 	CreationTimeUnix  time.Time
+	TapscriptSibling  []byte
 	KeyID             int32
 	RawKey            []byte
 	KeyFamily         int32
@@ -124,6 +127,7 @@ func (q *Queries) AllMintingBatches(ctx context.Context) ([]AllMintingBatchesRow
 			&i.GenesisID,
 			&i.HeightHint,
 			&i.CreationTimeUnix,
+			&i.TapscriptSibling,
 			&i.KeyID,
 			&i.RawKey,
 			&i.KeyFamily,
@@ -189,7 +193,7 @@ func (q *Queries) AnchorPendingAssets(ctx context.Context, arg AnchorPendingAsse
 }
 
 const assetsByGenesisPoint = `-- name: AssetsByGenesisPoint :many
-SELECT assets.asset_id, assets.genesis_id, version, script_key_id, asset_group_sig_id, script_version, amount, lock_time, relative_lock_time, split_commitment_root_hash, split_commitment_root_value, anchor_utxo_id, spent, gen_asset_id, genesis_assets.asset_id, asset_tag, meta_data_id, output_index, asset_type, genesis_point_id, genesis_points.genesis_id, prev_out, anchor_tx_id
+SELECT assets.asset_id, assets.genesis_id, version, script_key_id, asset_group_sig_id, script_version, amount, lock_time, relative_lock_time, split_commitment_root_hash, split_commitment_root_value, anchor_utxo_id, spent, non_divisible, edition, gen_asset_id, genesis_assets.asset_id, asset_tag, meta_data_id, output_index, asset_type, genesis_point_id, genesis_points.genesis_id, prev_out, anchor_tx_id
 FROM assets 
 JOIN genesis_assets 
     ON assets.genesis_id = genesis_assets.gen_asset_id
@@ -212,6 +216,8 @@ type AssetsByGenesisPointRow struct {
 	SplitCommitmentRootValue sql.NullInt64
 	AnchorUtxoID             sql.NullInt32
 	Spent                    bool
+	NonDivisible             bool
+	Edition                  int64
 	GenAssetID               int32
 	AssetID_2                []byte
 	AssetTag                 string
@@ -247,6 +253,8 @@ func (q *Queries) AssetsByGenesisPoint(ctx context.Context, prevOut []byte) ([]A
 			&i.SplitCommitmentRootValue,
 			&i.AnchorUtxoID,
 			&i.Spent,
+			&i.NonDivisible,
+			&i.Edition,
 			&i.GenAssetID,
 			&i.AssetID_2,
 			&i.AssetTag,
@@ -328,6 +336,29 @@ func (q *Queries) AssetsInBatch(ctx context.Context, rawKey []byte) ([]AssetsInB
 	return items, nil
 }
 
+const bindMintingBatchWithTapSibling = `-- name: BindMintingBatchWithTapSibling :exec
+WITH target_batch AS (
+    SELECT batch_id
+    FROM asset_minting_batches batches
+    JOIN internal_keys keys
+        ON batches.batch_id = keys.key_id
+    WHERE keys.raw_key = $1
+)
+UPDATE asset_minting_batches
+SET tapscript_sibling = $2
+WHERE batch_id IN (SELECT batch_id FROM target_batch)
+`
+
+type BindMintingBatchWithTapSiblingParams struct {
+	RawKey           []byte
+	TapscriptSibling []byte
+}
+
+func (q *Queries) BindMintingBatchWithTapSibling(ctx context.Context, arg BindMintingBatchWithTapSiblingParams) error {
+	_, err := q.db.ExecContext(ctx, bindMintingBatchWithTapSibling, arg.RawKey, arg.TapscriptSibling)
+	return err
+}
+
 const bindMintingBatchWithTx = `-- name: BindMintingBatchWithTx :exec
 WITH target_batch AS (
     SELECT batch_id
@@ -413,6 +444,92 @@ func (q *Queries) ConfirmChainTx(ctx context.Context, arg ConfirmChainTxParams)
 	return err
 }
 
+const deleteBatchProofPushAttempts = `-- name: DeleteBatchProofPushAttempts :execrows
+DELETE FROM batch_proof_push_attempts
+WHERE batch_id IN (
+    SELECT batch_id
+    FROM asset_minting_batches
+    WHERE batch_state = $1 AND
+        creation_time_unix < $2
+)
+`
+
+type DeleteBatchProofPushAttemptsParams struct {
+	BatchState    int16
+	CreatedBefore time.Time
+}
+
+func (q *Queries) DeleteBatchProofPushAttempts(ctx context.Context, arg DeleteBatchProofPushAttemptsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteBatchProofPushAttempts, arg.BatchState, arg.CreatedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteBatchSeedlings = `-- name: DeleteBatchSeedlings :execrows
+DELETE FROM asset_seedlings
+WHERE batch_id IN (
+    SELECT batch_id
+    FROM asset_minting_batches
+    WHERE batch_state = $1 AND
+        creation_time_unix < $2
+)
+`
+
+type DeleteBatchSeedlingsParams struct {
+	BatchState    int16
+	CreatedBefore time.Time
+}
+
+func (q *Queries) DeleteBatchSeedlings(ctx context.Context, arg DeleteBatchSeedlingsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteBatchSeedlings, arg.BatchState, arg.CreatedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteBatchSprouts = `-- name: DeleteBatchSprouts :execrows
+DELETE FROM assets
+WHERE anchor_utxo_id IS NULL AND genesis_id IN (
+    SELECT gen_asset_id
+    FROM genesis_assets
+    JOIN asset_minting_batches batches
+        ON genesis_assets.genesis_point_id = batches.genesis_id
+    WHERE batches.batch_state = $1 AND
+        batches.creation_time_unix < $2
+)
+`
+
+type DeleteBatchSproutsParams struct {
+	BatchState    int16
+	CreatedBefore time.Time
+}
+
+// Sprouts are only anchored once the genesis transaction of their batch is
+// signed, so the sprouts of a cancelled batch were never anchored.
+func (q *Queries) DeleteBatchSprouts(ctx context.Context, arg DeleteBatchSproutsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteBatchSprouts, arg.BatchState, arg.CreatedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteExpiredUTXOLeases = `-- name: DeleteExpiredUTXOLeases :exec
+UPDATE managed_utxos
+SET lease_owner = NULL, lease_expiry = NULL
+WHERE lease_owner IS NOT NULL AND
+      lease_expiry IS NOT NULL AND
+      lease_expiry < $1
+`
+
+func (q *Queries) DeleteExpiredUTXOLeases(ctx context.Context, now sql.NullTime) error {
+	_, err := q.db.ExecContext(ctx, deleteExpiredUTXOLeases, now)
+	return err
+}
+
 const deleteManagedUTXO = `-- name: DeleteManagedUTXO :exec
 DELETE FROM managed_utxos
 WHERE outpoint = $1
@@ -423,6 +540,156 @@ func (q *Queries) DeleteManagedUTXO(ctx context.Context, outpoint []byte) error
 	return err
 }
 
+const deleteMintingBatches = `-- name: DeleteMintingBatches :execrows
+DELETE FROM asset_minting_batches
+WHERE batch_state = $1 AND creation_time_unix < $2
+`
+
+type DeleteMintingBatchesParams struct {
+	BatchState    int16
+	CreatedBefore time.Time
+}
+
+func (q *Queries) DeleteMintingBatches(ctx context.Context, arg DeleteMintingBatchesParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteMintingBatches, arg.BatchState, arg.CreatedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteSeedling = `-- name: DeleteSeedling :exec
+DELETE FROM asset_seedlings
+WHERE asset_seedlings.asset_name = $1 AND
+    asset_seedlings.batch_id IN (
+        SELECT key_id
+        FROM internal_keys keys
+        WHERE keys.raw_key = $2
+    )
+`
+
+type DeleteSeedlingParams struct {
+	SeedlingName string
+	BatchKey     []byte
+}
+
+const deleteUTXOLease = `-- name: DeleteUTXOLease :exec
+UPDATE managed_utxos
+SET lease_owner = NULL, lease_expiry = NULL
+WHERE outpoint = $1
+`
+
+func (q *Queries) DeleteUTXOLease(ctx context.Context, outpoint []byte) error {
+	_, err := q.db.ExecContext(ctx, deleteUTXOLease, outpoint)
+	return err
+}
+
+func (q *Queries) DeleteSeedling(ctx context.Context, arg DeleteSeedlingParams) error {
+	_, err := q.db.ExecContext(ctx, deleteSeedling, arg.SeedlingName, arg.BatchKey)
+	return err
+}
+
+const fetchAnchorTxProofs = `-- name: FetchAnchorTxProofs :many
+SELECT proofs.asset_id, proofs.proof_file, script_keys.tweaked_script_key
+FROM asset_proofs proofs
+JOIN assets
+    ON proofs.asset_id = assets.asset_id
+JOIN script_keys
+    ON assets.script_key_id = script_keys.script_key_id
+JOIN managed_utxos utxos
+    ON assets.anchor_utxo_id = utxos.utxo_id
+WHERE utxos.txn_id = $1
+`
+
+type FetchAnchorTxProofsRow struct {
+	AssetID          int32
+	ProofFile        []byte
+	TweakedScriptKey []byte
+}
+
+func (q *Queries) FetchAnchorTxProofs(ctx context.Context, txnID int32) ([]FetchAnchorTxProofsRow, error) {
+	rows, err := q.db.QueryContext(ctx, fetchAnchorTxProofs, txnID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FetchAnchorTxProofsRow
+	for rows.Next() {
+		var i FetchAnchorTxProofsRow
+		if err := rows.Scan(&i.AssetID, &i.ProofFile, &i.TweakedScriptKey); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const fetchAnchorTxs = `-- name: FetchAnchorTxs :many
+SELECT txn_id, txid, chain_fees, raw_tx, block_height, block_hash, tx_index
+FROM chain_txns txns
+WHERE txns.block_hash IS NOT NULL AND
+    txns.block_height >= $1 AND (
+    EXISTS (
+        SELECT 1
+        FROM asset_proofs proofs
+        JOIN assets
+            ON proofs.asset_id = assets.asset_id
+        JOIN managed_utxos utxos
+            ON assets.anchor_utxo_id = utxos.utxo_id
+        WHERE utxos.txn_id = txns.txn_id AND
+            proofs.invalidated = $2
+    ) OR EXISTS (
+        SELECT 1
+        FROM asset_transfers transfers
+        WHERE transfers.anchor_txn_id = txns.txn_id AND
+            transfers.invalidated = $2
+    )
+)
+ORDER BY txns.block_height
+`
+
+type FetchAnchorTxsParams struct {
+	MinHeight   sql.NullInt32
+	Invalidated bool
+}
+
+func (q *Queries) FetchAnchorTxs(ctx context.Context, arg FetchAnchorTxsParams) ([]ChainTxn, error) {
+	rows, err := q.db.QueryContext(ctx, fetchAnchorTxs, arg.MinHeight, arg.Invalidated)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ChainTxn
+	for rows.Next() {
+		var i ChainTxn
+		if err := rows.Scan(
+			&i.TxnID,
+			&i.Txid,
+			&i.ChainFees,
+			&i.RawTx,
+			&i.BlockHeight,
+			&i.BlockHash,
+			&i.TxIndex,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const fetchAssetMeta = `-- name: FetchAssetMeta :one
 SELECT meta_data_hash, meta_data_blob, meta_data_type
 FROM assets_meta
@@ -609,7 +876,7 @@ func (q *Queries) FetchAssetWitnesses(ctx context.Context, assetID sql.NullInt32
 }
 
 const fetchAssetsByAnchorTx = `-- name: FetchAssetsByAnchorTx :many
-SELECT asset_id, genesis_id, version, script_key_id, asset_group_sig_id, script_version, amount, lock_time, relative_lock_time, split_commitment_root_hash, split_commitment_root_value, anchor_utxo_id, spent
+SELECT asset_id, genesis_id, version, script_key_id, asset_group_sig_id, script_version, amount, lock_time, relative_lock_time, split_commitment_root_hash, split_commitment_root_value, anchor_utxo_id, spent, non_divisible, edition
 FROM assets
 WHERE anchor_utxo_id = $1
 `
@@ -637,6 +904,8 @@ func (q *Queries) FetchAssetsByAnchorTx(ctx context.Context, anchorUtxoID sql.Nu
 			&i.SplitCommitmentRootValue,
 			&i.AnchorUtxoID,
 			&i.Spent,
+			&i.NonDivisible,
+			&i.Edition,
 		); err != nil {
 			return nil, err
 		}
@@ -680,7 +949,8 @@ WITH genesis_info AS (
     -- assets we care about. We obtain only the assets found in the batch
     -- above, with the WHERE query at the bottom.
     SELECT 
-        sig_id, gen_asset_id, genesis_sig, tweaked_group_key, raw_key, key_index, key_family
+        sig_id, gen_asset_id, genesis_sig, witness_stack, tweaked_group_key,
+        tapscript_root, raw_key, key_index, key_family
     FROM asset_group_sigs sigs
     JOIN asset_groups groups
         ON sigs.group_key_id = groups.group_id
@@ -695,12 +965,14 @@ SELECT
     internal_keys.key_family AS script_key_fam,
     internal_keys.key_index AS script_key_index,
     key_group_info.genesis_sig, 
+    key_group_info.witness_stack AS group_witness,
     key_group_info.tweaked_group_key,
+    key_group_info.tapscript_root AS group_tapscript_root,
     key_group_info.raw_key AS group_key_raw,
     key_group_info.key_family AS group_key_family,
     key_group_info.key_index AS group_key_index,
     script_version, amount, lock_time, relative_lock_time, spent,
-    genesis_info.asset_id, genesis_info.asset_tag, genesis_info.meta_hash, 
+    non_divisible, edition, genesis_info.asset_id, genesis_info.asset_tag, genesis_info.meta_hash, 
     genesis_info.meta_type, genesis_info.meta_blob, 
     genesis_info.output_index AS genesis_output_index, genesis_info.asset_type,
     genesis_info.prev_out AS genesis_prev_out
@@ -723,7 +995,9 @@ type FetchAssetsForBatchRow struct {
 	ScriptKeyFam       int32
 	ScriptKeyIndex     int32
 	GenesisSig         []byte
+	GroupWitness       []byte
 	TweakedGroupKey    []byte
+	GroupTapscriptRoot []byte
 	GroupKeyRaw        []byte
 	GroupKeyFamily     sql.NullInt32
 	GroupKeyIndex      sql.NullInt32
@@ -732,6 +1006,8 @@ type FetchAssetsForBatchRow struct {
 	LockTime           sql.NullInt32
 	RelativeLockTime   sql.NullInt32
 	Spent              bool
+	NonDivisible       bool
+	Edition            int64
 	AssetID            []byte
 	AssetTag           string
 	MetaHash           []byte
@@ -763,7 +1039,9 @@ func (q *Queries) FetchAssetsForBatch(ctx context.Context, rawKey []byte) ([]Fet
 			&i.ScriptKeyFam,
 			&i.ScriptKeyIndex,
 			&i.GenesisSig,
+			&i.GroupWitness,
 			&i.TweakedGroupKey,
+			&i.GroupTapscriptRoot,
 			&i.GroupKeyRaw,
 			&i.GroupKeyFamily,
 			&i.GroupKeyIndex,
@@ -772,6 +1050,8 @@ func (q *Queries) FetchAssetsForBatch(ctx context.Context, rawKey []byte) ([]Fet
 			&i.LockTime,
 			&i.RelativeLockTime,
 			&i.Spent,
+			&i.NonDivisible,
+			&i.Edition,
 			&i.AssetID,
 			&i.AssetTag,
 			&i.MetaHash,
@@ -933,7 +1213,9 @@ SELECT
     key_group_info_view.raw_key AS raw_key,
     key_group_info_view.key_index AS key_index,
     key_group_info_view.key_family AS key_family,
-    key_group_info_view.genesis_sig AS genesis_sig
+    key_group_info_view.genesis_sig AS genesis_sig,
+    key_group_info_view.witness_stack AS witness_stack,
+    key_group_info_view.tapscript_root AS tapscript_root
 FROM key_group_info_view
 WHERE (
     key_group_info_view.gen_asset_id = $1
@@ -946,6 +1228,8 @@ type FetchGroupByGenesisRow struct {
 	KeyIndex        int32
 	KeyFamily       int32
 	GenesisSig      []byte
+	WitnessStack    []byte
+	TapscriptRoot   []byte
 }
 
 func (q *Queries) FetchGroupByGenesis(ctx context.Context, genesisID int32) (FetchGroupByGenesisRow, error) {
@@ -957,6 +1241,8 @@ func (q *Queries) FetchGroupByGenesis(ctx context.Context, genesisID int32) (Fet
 		&i.KeyIndex,
 		&i.KeyFamily,
 		&i.GenesisSig,
+		&i.WitnessStack,
+		&i.TapscriptRoot,
 	)
 	return i, err
 }
@@ -967,7 +1253,9 @@ SELECT
     key_group_info_view.raw_key AS raw_key,
     key_group_info_view.key_index AS key_index,
     key_group_info_view.key_family AS key_family,
-    key_group_info_view.genesis_sig AS genesis_sig
+    key_group_info_view.genesis_sig AS genesis_sig,
+    key_group_info_view.witness_stack AS witness_stack,
+    key_group_info_view.tapscript_root AS tapscript_root
 FROM key_group_info_view
 WHERE (
     key_group_info_view.tweaked_group_key = $1
@@ -977,11 +1265,13 @@ LIMIT 1
 `
 
 type FetchGroupByGroupKeyRow struct {
-	GenAssetID int32
-	RawKey     []byte
-	KeyIndex   int32
-	KeyFamily  int32
-	GenesisSig []byte
+	GenAssetID    int32
+	RawKey        []byte
+	KeyIndex      int32
+	KeyFamily     int32
+	GenesisSig    []byte
+	WitnessStack  []byte
+	TapscriptRoot []byte
 }
 
 // Sort and limit to return the genesis ID for initial genesis of the group.
@@ -994,10 +1284,121 @@ func (q *Queries) FetchGroupByGroupKey(ctx context.Context, groupKey []byte) (Fe
 		&i.KeyIndex,
 		&i.KeyFamily,
 		&i.GenesisSig,
+		&i.WitnessStack,
+		&i.TapscriptRoot,
 	)
 	return i, err
 }
 
+const fetchGroupKeysByKeyLocator = `-- name: FetchGroupKeysByKeyLocator :many
+SELECT DISTINCT tweaked_group_key
+FROM key_group_info_view
+WHERE key_family = $1 AND key_index = $2
+`
+
+type FetchGroupKeysByKeyLocatorParams struct {
+	KeyFamily int32
+	KeyIndex  int32
+}
+
+func (q *Queries) FetchGroupKeysByKeyLocator(ctx context.Context, arg FetchGroupKeysByKeyLocatorParams) ([][]byte, error) {
+	rows, err := q.db.QueryContext(ctx, fetchGroupKeysByKeyLocator, arg.KeyFamily, arg.KeyIndex)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items [][]byte
+	for rows.Next() {
+		var tweaked_group_key []byte
+		if err := rows.Scan(&tweaked_group_key); err != nil {
+			return nil, err
+		}
+		items = append(items, tweaked_group_key)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const fetchGroupTranches = `-- name: FetchGroupTranches :many
+SELECT
+    genesis_info_view.asset_id, genesis_info_view.asset_tag,
+    genesis_info_view.meta_hash, genesis_info_view.output_index,
+    genesis_info_view.asset_type, genesis_info_view.prev_out,
+    assets_meta.meta_data_blob, assets_meta.meta_data_type,
+    key_group_info_view.raw_key, key_group_info_view.key_index,
+    key_group_info_view.key_family, key_group_info_view.genesis_sig,
+    key_group_info_view.witness_stack, key_group_info_view.tapscript_root
+FROM key_group_info_view
+JOIN genesis_info_view
+    ON key_group_info_view.gen_asset_id = genesis_info_view.gen_asset_id
+-- We do a LEFT JOIN here, as not every tranche reveals its metadata.
+LEFT JOIN assets_meta
+    ON genesis_info_view.meta_hash = assets_meta.meta_data_hash
+WHERE key_group_info_view.tweaked_group_key = $1
+ORDER BY key_group_info_view.sig_id
+`
+
+type FetchGroupTranchesRow struct {
+	AssetID       []byte
+	AssetTag      string
+	MetaHash      []byte
+	OutputIndex   int32
+	AssetType     int16
+	PrevOut       []byte
+	MetaDataBlob  []byte
+	MetaDataType  sql.NullInt16
+	RawKey        []byte
+	KeyIndex      int32
+	KeyFamily     int32
+	GenesisSig    []byte
+	WitnessStack  []byte
+	TapscriptRoot []byte
+}
+
+// We do a LEFT JOIN here, as not every tranche reveals its metadata.
+func (q *Queries) FetchGroupTranches(ctx context.Context, groupKey []byte) ([]FetchGroupTranchesRow, error) {
+	rows, err := q.db.QueryContext(ctx, fetchGroupTranches, groupKey)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FetchGroupTranchesRow
+	for rows.Next() {
+		var i FetchGroupTranchesRow
+		if err := rows.Scan(
+			&i.AssetID,
+			&i.AssetTag,
+			&i.MetaHash,
+			&i.OutputIndex,
+			&i.AssetType,
+			&i.PrevOut,
+			&i.MetaDataBlob,
+			&i.MetaDataType,
+			&i.RawKey,
+			&i.KeyIndex,
+			&i.KeyFamily,
+			&i.GenesisSig,
+			&i.WitnessStack,
+			&i.TapscriptRoot,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const fetchGroupedAssets = `-- name: FetchGroupedAssets :many
 SELECT
     assets.asset_id AS asset_primary_key,
@@ -1063,7 +1464,7 @@ func (q *Queries) FetchGroupedAssets(ctx context.Context) ([]FetchGroupedAssetsR
 }
 
 const fetchManagedUTXO = `-- name: FetchManagedUTXO :one
-SELECT utxo_id, outpoint, amt_sats, internal_key_id, taproot_asset_root, tapscript_sibling, merkle_root, txn_id, key_id, raw_key, key_family, key_index
+SELECT utxo_id, outpoint, amt_sats, internal_key_id, taproot_asset_root, tapscript_sibling, merkle_root, txn_id, lease_owner, lease_expiry, alt_leaves, key_id, raw_key, key_family, key_index
 FROM managed_utxos utxos
 JOIN internal_keys keys
     ON utxos.internal_key_id = keys.key_id
@@ -1087,6 +1488,9 @@ type FetchManagedUTXORow struct {
 	TapscriptSibling []byte
 	MerkleRoot       []byte
 	TxnID            int32
+	LeaseOwner       []byte
+	LeaseExpiry      sql.NullTime
+	AltLeaves        []byte
 	KeyID            int32
 	RawKey           []byte
 	KeyFamily        int32
@@ -1105,6 +1509,9 @@ func (q *Queries) FetchManagedUTXO(ctx context.Context, arg FetchManagedUTXOPara
 		&i.TapscriptSibling,
 		&i.MerkleRoot,
 		&i.TxnID,
+		&i.LeaseOwner,
+		&i.LeaseExpiry,
+		&i.AltLeaves,
 		&i.KeyID,
 		&i.RawKey,
 		&i.KeyFamily,
@@ -1114,7 +1521,7 @@ func (q *Queries) FetchManagedUTXO(ctx context.Context, arg FetchManagedUTXOPara
 }
 
 const fetchManagedUTXOs = `-- name: FetchManagedUTXOs :many
-SELECT utxo_id, outpoint, amt_sats, internal_key_id, taproot_asset_root, tapscript_sibling, merkle_root, txn_id, key_id, raw_key, key_family, key_index
+SELECT utxo_id, outpoint, amt_sats, internal_key_id, taproot_asset_root, tapscript_sibling, merkle_root, txn_id, lease_owner, lease_expiry, alt_leaves, key_id, raw_key, key_family, key_index
 FROM managed_utxos utxos
 JOIN internal_keys keys
     ON utxos.internal_key_id = keys.key_id
@@ -1129,6 +1536,9 @@ type FetchManagedUTXOsRow struct {
 	TapscriptSibling []byte
 	MerkleRoot       []byte
 	TxnID            int32
+	LeaseOwner       []byte
+	LeaseExpiry      sql.NullTime
+	AltLeaves        []byte
 	KeyID            int32
 	RawKey           []byte
 	KeyFamily        int32
@@ -1153,6 +1563,9 @@ func (q *Queries) FetchManagedUTXOs(ctx context.Context) ([]FetchManagedUTXOsRow
 			&i.TapscriptSibling,
 			&i.MerkleRoot,
 			&i.TxnID,
+			&i.LeaseOwner,
+			&i.LeaseExpiry,
+			&i.AltLeaves,
 			&i.KeyID,
 			&i.RawKey,
 			&i.KeyFamily,
@@ -1183,7 +1596,7 @@ WITH target_batch AS (
         ON batches.batch_id = keys.key_id
     WHERE keys.raw_key = $1
 )
-SELECT batch_id, batch_state, minting_tx_psbt, change_output_index, genesis_id, height_hint, creation_time_unix, key_id, raw_key, key_family, key_index
+SELECT batch_id, batch_state, minting_tx_psbt, change_output_index, genesis_id, height_hint, creation_time_unix, tapscript_sibling, key_id, raw_key, key_family, key_index
 FROM asset_minting_batches batches
 JOIN internal_keys keys
     ON batches.batch_id = keys.key_id
@@ -1198,6 +1611,7 @@ type FetchMintingBatchRow struct {
 	GenesisID         sql.NullInt32
 	HeightHint        int32
 	CreationTimeUnix  time.Time
+	TapscriptSibling  []byte
 	KeyID             int32
 	RawKey            []byte
 	KeyFamily         int32
@@ -1215,6 +1629,7 @@ func (q *Queries) FetchMintingBatch(ctx context.Context, rawKey []byte) (FetchMi
 		&i.GenesisID,
 		&i.HeightHint,
 		&i.CreationTimeUnix,
+		&i.TapscriptSibling,
 		&i.KeyID,
 		&i.RawKey,
 		&i.KeyFamily,
@@ -1224,7 +1639,7 @@ func (q *Queries) FetchMintingBatch(ctx context.Context, rawKey []byte) (FetchMi
 }
 
 const fetchMintingBatchesByInverseState = `-- name: FetchMintingBatchesByInverseState :many
-SELECT batch_id, batch_state, minting_tx_psbt, change_output_index, genesis_id, height_hint, creation_time_unix, key_id, raw_key, key_family, key_index
+SELECT batch_id, batch_state, minting_tx_psbt, change_output_index, genesis_id, height_hint, creation_time_unix, tapscript_sibling, key_id, raw_key, key_family, key_index
 FROM asset_minting_batches batches
 JOIN internal_keys keys
     ON batches.batch_id = keys.key_id
@@ -1239,6 +1654,7 @@ type FetchMintingBatchesByInverseStateRow struct {
 	GenesisID         sql.NullInt32
 	HeightHint        int32
 	CreationTimeUnix  time.Time
+	TapscriptSibling  []byte
 	KeyID             int32
 	RawKey            []byte
 	KeyFamily         int32
@@ -1262,6 +1678,7 @@ func (q *Queries) FetchMintingBatchesByInverseState(ctx context.Context, batchSt
 			&i.GenesisID,
 			&i.HeightHint,
 			&i.CreationTimeUnix,
+			&i.TapscriptSibling,
 			&i.KeyID,
 			&i.RawKey,
 			&i.KeyFamily,
@@ -1320,8 +1737,45 @@ func (q *Queries) FetchScriptKeyIDByTweakedKey(ctx context.Context, tweakedScrip
 	return script_key_id, err
 }
 
+const fetchScriptKeyLeaves = `-- name: FetchScriptKeyLeaves :many
+SELECT leaf_version, script
+FROM script_key_leaves
+JOIN script_keys
+  ON script_key_leaves.script_key_id = script_keys.script_key_id
+WHERE script_keys.tweaked_script_key = $1
+ORDER BY leaf_index
+`
+
+type FetchScriptKeyLeavesRow struct {
+	LeafVersion int16
+	Script      []byte
+}
+
+func (q *Queries) FetchScriptKeyLeaves(ctx context.Context, tweakedScriptKey []byte) ([]FetchScriptKeyLeavesRow, error) {
+	rows, err := q.db.QueryContext(ctx, fetchScriptKeyLeaves, tweakedScriptKey)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FetchScriptKeyLeavesRow
+	for rows.Next() {
+		var i FetchScriptKeyLeavesRow
+		if err := rows.Scan(&i.LeafVersion, &i.Script); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const fetchSeedlingByID = `-- name: FetchSeedlingByID :one
-SELECT seedling_id, asset_name, asset_type, asset_supply, asset_meta_id, emission_enabled, batch_id, group_genesis_id, group_anchor_id
+SELECT seedling_id, asset_name, asset_type, asset_supply, asset_meta_id, emission_enabled, batch_id, group_genesis_id, group_anchor_id, non_divisible, group_tapscript_root, editions, group_script_spend
 FROM asset_seedlings
 WHERE seedling_id = $1
 `
@@ -1339,6 +1793,10 @@ func (q *Queries) FetchSeedlingByID(ctx context.Context, seedlingID int32) (Asse
 		&i.BatchID,
 		&i.GroupGenesisID,
 		&i.GroupAnchorID,
+		&i.NonDivisible,
+		&i.GroupTapscriptRoot,
+		&i.Editions,
+		&i.GroupScriptSpend,
 	)
 	return i, err
 }
@@ -1384,7 +1842,9 @@ WITH target_batch(batch_id) AS (
 SELECT seedling_id, asset_name, asset_type, asset_supply, 
     assets_meta.meta_data_hash, assets_meta.meta_data_type, 
     assets_meta.meta_data_blob, emission_enabled, batch_id, 
-    group_genesis_id, group_anchor_id
+    group_genesis_id, group_anchor_id, non_divisible,
+    group_tapscript_root, editions, assets_meta.decimal_display,
+    group_script_spend
 FROM asset_seedlings 
 LEFT JOIN assets_meta
     ON asset_seedlings.asset_meta_id = assets_meta.meta_id
@@ -1392,17 +1852,22 @@ WHERE asset_seedlings.batch_id in (SELECT batch_id FROM target_batch)
 `
 
 type FetchSeedlingsForBatchRow struct {
-	SeedlingID      int32
-	AssetName       string
-	AssetType       int16
-	AssetSupply     int64
-	MetaDataHash    []byte
-	MetaDataType    sql.NullInt16
-	MetaDataBlob    []byte
-	EmissionEnabled bool
-	BatchID         int32
-	GroupGenesisID  sql.NullInt32
-	GroupAnchorID   sql.NullInt32
+	SeedlingID         int32
+	AssetName          string
+	AssetType          int16
+	AssetSupply        int64
+	MetaDataHash       []byte
+	MetaDataType       sql.NullInt16
+	MetaDataBlob       []byte
+	EmissionEnabled    bool
+	BatchID            int32
+	GroupGenesisID     sql.NullInt32
+	GroupAnchorID      sql.NullInt32
+	NonDivisible       bool
+	GroupTapscriptRoot []byte
+	Editions           bool
+	DecimalDisplay     sql.NullInt32
+	GroupScriptSpend   []byte
 }
 
 func (q *Queries) FetchSeedlingsForBatch(ctx context.Context, rawKey []byte) ([]FetchSeedlingsForBatchRow, error) {
@@ -1426,6 +1891,11 @@ func (q *Queries) FetchSeedlingsForBatch(ctx context.Context, rawKey []byte) ([]
 			&i.BatchID,
 			&i.GroupGenesisID,
 			&i.GroupAnchorID,
+			&i.NonDivisible,
+			&i.GroupTapscriptRoot,
+			&i.Editions,
+			&i.DecimalDisplay,
+			&i.GroupScriptSpend,
 		); err != nil {
 			return nil, err
 		}
@@ -1507,22 +1977,28 @@ func (q *Queries) GenesisPoints(ctx context.Context) ([]GenesisPoint, error) {
 const insertAssetSeedling = `-- name: InsertAssetSeedling :exec
 INSERT INTO asset_seedlings (
     asset_name, asset_type, asset_supply, asset_meta_id,
-    emission_enabled, batch_id, group_genesis_id, group_anchor_id
+    emission_enabled, batch_id, group_genesis_id, group_anchor_id,
+    non_divisible, group_tapscript_root, editions, group_script_spend
 ) VALUES (
    $1, $2, $3, $4, $5, $6,
-   $7, $8
+   $7, $8,
+   $9, $10, $11, $12
 )
 `
 
 type InsertAssetSeedlingParams struct {
-	AssetName       string
-	AssetType       int16
-	AssetSupply     int64
-	AssetMetaID     int32
-	EmissionEnabled bool
-	BatchID         int32
-	GroupGenesisID  sql.NullInt32
-	GroupAnchorID   sql.NullInt32
+	AssetName          string
+	AssetType          int16
+	AssetSupply        int64
+	AssetMetaID        int32
+	EmissionEnabled    bool
+	BatchID            int32
+	GroupGenesisID     sql.NullInt32
+	GroupAnchorID      sql.NullInt32
+	NonDivisible       bool
+	GroupTapscriptRoot []byte
+	Editions           bool
+	GroupScriptSpend   []byte
 }
 
 func (q *Queries) InsertAssetSeedling(ctx context.Context, arg InsertAssetSeedlingParams) error {
@@ -1535,6 +2011,10 @@ func (q *Queries) InsertAssetSeedling(ctx context.Context, arg InsertAssetSeedli
 		arg.BatchID,
 		arg.GroupGenesisID,
 		arg.GroupAnchorID,
+		arg.NonDivisible,
+		arg.GroupTapscriptRoot,
+		arg.Editions,
+		arg.GroupScriptSpend,
 	)
 	return err
 }
@@ -1552,23 +2032,29 @@ WITH target_key_id AS (
 )
 INSERT INTO asset_seedlings(
     asset_name, asset_type, asset_supply, asset_meta_id,
-    emission_enabled, batch_id, group_genesis_id, group_anchor_id
+    emission_enabled, batch_id, group_genesis_id, group_anchor_id,
+    non_divisible, group_tapscript_root, editions, group_script_spend
 ) VALUES (
     $2, $3, $4, $5, $6,
     (SELECT key_id FROM target_key_id),
-    $7, $8
+    $7, $8,
+    $9, $10, $11, $12
 )
 `
 
 type InsertAssetSeedlingIntoBatchParams struct {
-	RawKey          []byte
-	AssetName       string
-	AssetType       int16
-	AssetSupply     int64
-	AssetMetaID     int32
-	EmissionEnabled bool
-	GroupGenesisID  sql.NullInt32
-	GroupAnchorID   sql.NullInt32
+	RawKey             []byte
+	AssetName          string
+	AssetType          int16
+	AssetSupply        int64
+	AssetMetaID        int32
+	EmissionEnabled    bool
+	GroupGenesisID     sql.NullInt32
+	GroupAnchorID      sql.NullInt32
+	NonDivisible       bool
+	GroupTapscriptRoot []byte
+	Editions           bool
+	GroupScriptSpend   []byte
 }
 
 func (q *Queries) InsertAssetSeedlingIntoBatch(ctx context.Context, arg InsertAssetSeedlingIntoBatchParams) error {
@@ -1581,6 +2067,10 @@ func (q *Queries) InsertAssetSeedlingIntoBatch(ctx context.Context, arg InsertAs
 		arg.EmissionEnabled,
 		arg.GroupGenesisID,
 		arg.GroupAnchorID,
+		arg.NonDivisible,
+		arg.GroupTapscriptRoot,
+		arg.Editions,
+		arg.GroupScriptSpend,
 	)
 	return err
 }
@@ -1615,12 +2105,38 @@ func (q *Queries) InsertAssetWitness(ctx context.Context, arg InsertAssetWitness
 	return err
 }
 
+const insertBatchProofPushAttempt = `-- name: InsertBatchProofPushAttempt :exec
+WITH target_batch AS (
+    SELECT batch_id
+    FROM asset_minting_batches batches
+    JOIN internal_keys keys
+        ON batches.batch_id = keys.key_id
+    WHERE keys.raw_key = $1
+)
+INSERT INTO batch_proof_push_attempts (
+    batch_id, time_unix
+) VALUES (
+    (SELECT batch_id FROM target_batch), $2
+)
+`
+
+type InsertBatchProofPushAttemptParams struct {
+	RawKey   []byte
+	TimeUnix time.Time
+}
+
+func (q *Queries) InsertBatchProofPushAttempt(ctx context.Context, arg InsertBatchProofPushAttemptParams) error {
+	_, err := q.db.ExecContext(ctx, insertBatchProofPushAttempt, arg.RawKey, arg.TimeUnix)
+	return err
+}
+
 const insertNewAsset = `-- name: InsertNewAsset :one
 INSERT INTO assets (
     genesis_id, version, script_key_id, asset_group_sig_id, script_version, 
-    amount, lock_time, relative_lock_time, anchor_utxo_id, spent
+    amount, lock_time, relative_lock_time, anchor_utxo_id, spent,
+    non_divisible, edition
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
 ) RETURNING asset_id
 `
 
@@ -1635,6 +2151,8 @@ type InsertNewAssetParams struct {
 	RelativeLockTime sql.NullInt32
 	AnchorUtxoID     sql.NullInt32
 	Spent            bool
+	NonDivisible     bool
+	Edition          int64
 }
 
 func (q *Queries) InsertNewAsset(ctx context.Context, arg InsertNewAssetParams) (int32, error) {
@@ -1649,12 +2167,42 @@ func (q *Queries) InsertNewAsset(ctx context.Context, arg InsertNewAssetParams)
 		arg.RelativeLockTime,
 		arg.AnchorUtxoID,
 		arg.Spent,
+		arg.NonDivisible,
+		arg.Edition,
 	)
 	var asset_id int32
 	err := row.Scan(&asset_id)
 	return asset_id, err
 }
 
+const insertScriptKeyLeaf = `-- name: InsertScriptKeyLeaf :exec
+INSERT INTO script_key_leaves (
+    script_key_id, leaf_index, leaf_version, script
+) VALUES (
+    $1, $2, $3, $4
+) ON CONFLICT (script_key_id, leaf_index)
+    -- The leaves of a script key are fixed by its tweak, so there's nothing
+    -- to update.
+    DO NOTHING
+`
+
+type InsertScriptKeyLeafParams struct {
+	ScriptKeyID int32
+	LeafIndex   int32
+	LeafVersion int16
+	Script      []byte
+}
+
+func (q *Queries) InsertScriptKeyLeaf(ctx context.Context, arg InsertScriptKeyLeafParams) error {
+	_, err := q.db.ExecContext(ctx, insertScriptKeyLeaf,
+		arg.ScriptKeyID,
+		arg.LeafIndex,
+		arg.LeafVersion,
+		arg.Script,
+	)
+	return err
+}
+
 const newMintingBatch = `-- name: NewMintingBatch :exec
 INSERT INTO asset_minting_batches (
     batch_state, batch_id, height_hint, creation_time_unix
@@ -1788,11 +2336,14 @@ SELECT
     internal_keys.key_family AS script_key_fam,
     internal_keys.key_index AS script_key_index,
     key_group_info_view.genesis_sig, 
+    key_group_info_view.witness_stack AS group_witness,
     key_group_info_view.tweaked_group_key,
+    key_group_info_view.tapscript_root AS group_tapscript_root,
     key_group_info_view.raw_key AS group_key_raw,
     key_group_info_view.key_family AS group_key_family,
     key_group_info_view.key_index AS group_key_index,
-    script_version, amount, lock_time, relative_lock_time, 
+    script_version, amount, lock_time, relative_lock_time, non_divisible,
+    edition,
     genesis_info_view.asset_id AS asset_id,
     genesis_info_view.asset_tag,
     genesis_info_view.meta_hash, 
@@ -1807,12 +2358,15 @@ SELECT
     utxos.merkle_root AS anchor_merkle_root,
     utxos.taproot_asset_root AS anchor_taproot_asset_root,
     utxo_internal_keys.raw_key AS anchor_internal_key,
-    split_commitment_root_hash, split_commitment_root_value
+    split_commitment_root_hash, split_commitment_root_value,
+    assets_meta.decimal_display
 FROM assets
 JOIN genesis_info_view
     ON assets.genesis_id = genesis_info_view.gen_asset_id AND
       (genesis_info_view.asset_id = $1 OR
         $1 IS NULL)
+LEFT JOIN assets_meta
+    ON genesis_info_view.meta_hash = assets_meta.meta_data_hash
 LEFT JOIN key_group_info_view
     ON assets.genesis_id = key_group_info_view.gen_asset_id
 JOIN script_keys
@@ -1833,8 +2387,44 @@ WHERE (
     assets.amount >= COALESCE($4, assets.amount) AND
     assets.spent = COALESCE($5, assets.spent) AND
     (key_group_info_view.tweaked_group_key = $6 OR
-      $6 IS NULL)
+      $6 IS NULL) AND
+    -- If a time is given, we'll exclude all assets that are anchored in a UTXO
+    -- that is leased at that time.
+    ($7 IS NULL OR utxos.lease_owner IS NULL OR
+      utxos.lease_expiry IS NULL OR utxos.lease_expiry <= $7) AND
+    -- Script keys without a tweak are BIP-0086 keys (type 1), all others
+    -- commit to a tapscript tree (type 2).
+    ($8 IS NULL OR
+      (CASE WHEN COALESCE(length(script_keys.tweak), 0) = 0 THEN 1 ELSE 2 END) =
+        $8) AND
+    -- If a max anchor height is given, we'll only return assets of which the
+    -- anchor transaction was confirmed at or below that height.
+    ($9 IS NULL OR
+      (txns.block_height > 0 AND
+        txns.block_height <= $9)) AND
+    -- If a cursor is given, we'll only return the assets that come after it
+    -- in the requested order. Assets are sorted by their amount if requested,
+    -- with their primary key breaking ties, or by their primary key otherwise.
+    ($10 IS NULL OR
+      ($11 = false AND (
+        ($12 = true AND assets.amount > $13) OR
+        (($12 = false OR assets.amount = $13) AND
+          assets.asset_id > $10)
+      )) OR
+      ($11 = true AND (
+        ($12 = true AND assets.amount < $13) OR
+        (($12 = false OR assets.amount = $13) AND
+          assets.asset_id < $10)
+      )))
 )
+ORDER BY
+    CASE WHEN $11 = false AND $12 = true
+      THEN assets.amount END ASC,
+    CASE WHEN $11 = false THEN assets.asset_id END ASC,
+    CASE WHEN $11 = true AND $12 = true
+      THEN assets.amount END DESC,
+    CASE WHEN $11 = true THEN assets.asset_id END DESC
+LIMIT COALESCE($14, 2147483647)
 `
 
 type QueryAssetsParams struct {
@@ -1844,6 +2434,14 @@ type QueryAssetsParams struct {
 	MinAmt           sql.NullInt64
 	Spent            sql.NullBool
 	KeyGroupFilter   []byte
+	Now              sql.NullTime
+	ScriptKeyType    sql.NullInt32
+	MaxAnchorHeight  sql.NullInt32
+	CursorID         sql.NullInt32
+	SortDescending   bool
+	SortByAmount     bool
+	CursorAmount     sql.NullInt64
+	NumLimit         sql.NullInt32
 }
 
 type QueryAssetsRow struct {
@@ -1857,7 +2455,9 @@ type QueryAssetsRow struct {
 	ScriptKeyFam             int32
 	ScriptKeyIndex           int32
 	GenesisSig               []byte
+	GroupWitness             []byte
 	TweakedGroupKey          []byte
+	GroupTapscriptRoot       []byte
 	GroupKeyRaw              []byte
 	GroupKeyFamily           sql.NullInt32
 	GroupKeyIndex            sql.NullInt32
@@ -1865,6 +2465,8 @@ type QueryAssetsRow struct {
 	Amount                   int64
 	LockTime                 sql.NullInt32
 	RelativeLockTime         sql.NullInt32
+	NonDivisible             bool
+	Edition                  int64
 	AssetID                  []byte
 	AssetTag                 string
 	MetaHash                 []byte
@@ -1881,6 +2483,7 @@ type QueryAssetsRow struct {
 	AnchorInternalKey        []byte
 	SplitCommitmentRootHash  []byte
 	SplitCommitmentRootValue sql.NullInt64
+	DecimalDisplay           sql.NullInt32
 }
 
 // We use a LEFT JOIN here as not every asset has a group key, so this'll
@@ -1899,6 +2502,14 @@ func (q *Queries) QueryAssets(ctx context.Context, arg QueryAssetsParams) ([]Que
 		arg.MinAmt,
 		arg.Spent,
 		arg.KeyGroupFilter,
+		arg.Now,
+		arg.ScriptKeyType,
+		arg.MaxAnchorHeight,
+		arg.CursorID,
+		arg.SortDescending,
+		arg.SortByAmount,
+		arg.CursorAmount,
+		arg.NumLimit,
 	)
 	if err != nil {
 		return nil, err
@@ -1918,7 +2529,9 @@ func (q *Queries) QueryAssets(ctx context.Context, arg QueryAssetsParams) ([]Que
 			&i.ScriptKeyFam,
 			&i.ScriptKeyIndex,
 			&i.GenesisSig,
+			&i.GroupWitness,
 			&i.TweakedGroupKey,
+			&i.GroupTapscriptRoot,
 			&i.GroupKeyRaw,
 			&i.GroupKeyFamily,
 			&i.GroupKeyIndex,
@@ -1926,6 +2539,8 @@ func (q *Queries) QueryAssets(ctx context.Context, arg QueryAssetsParams) ([]Que
 			&i.Amount,
 			&i.LockTime,
 			&i.RelativeLockTime,
+			&i.NonDivisible,
+			&i.Edition,
 			&i.AssetID,
 			&i.AssetTag,
 			&i.MetaHash,
@@ -1942,6 +2557,7 @@ func (q *Queries) QueryAssets(ctx context.Context, arg QueryAssetsParams) ([]Que
 			&i.AnchorInternalKey,
 			&i.SplitCommitmentRootHash,
 			&i.SplitCommitmentRootValue,
+			&i.DecimalDisplay,
 		); err != nil {
 			return nil, err
 		}
@@ -1956,6 +2572,76 @@ func (q *Queries) QueryAssets(ctx context.Context, arg QueryAssetsParams) ([]Que
 	return items, nil
 }
 
+const queryBatchProofPushAttempts = `-- name: QueryBatchProofPushAttempts :many
+SELECT time_unix
+FROM batch_proof_push_attempts attempts
+JOIN internal_keys keys
+    ON attempts.batch_id = keys.key_id
+WHERE keys.raw_key = $1
+ORDER BY time_unix DESC
+`
+
+func (q *Queries) QueryBatchProofPushAttempts(ctx context.Context, rawKey []byte) ([]time.Time, error) {
+	rows, err := q.db.QueryContext(ctx, queryBatchProofPushAttempts, rawKey)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []time.Time
+	for rows.Next() {
+		var time_unix time.Time
+		if err := rows.Scan(&time_unix); err != nil {
+			return nil, err
+		}
+		items = append(items, time_unix)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const rebindAssetProof = `-- name: RebindAssetProof :exec
+UPDATE asset_proofs
+SET proof_file = $1, invalidated = false
+WHERE asset_id = $2
+`
+
+type RebindAssetProofParams struct {
+	ProofFile []byte
+	AssetID   int32
+}
+
+func (q *Queries) RebindAssetProof(ctx context.Context, arg RebindAssetProofParams) error {
+	_, err := q.db.ExecContext(ctx, rebindAssetProof, arg.ProofFile, arg.AssetID)
+	return err
+}
+
+const setAnchorTxProofsInvalidated = `-- name: SetAnchorTxProofsInvalidated :exec
+UPDATE asset_proofs
+SET invalidated = $1
+WHERE asset_id IN (
+    SELECT assets.asset_id
+    FROM assets
+    JOIN managed_utxos utxos
+        ON assets.anchor_utxo_id = utxos.utxo_id
+    WHERE utxos.txn_id = $2
+)
+`
+
+type SetAnchorTxProofsInvalidatedParams struct {
+	Invalidated bool
+	TxnID       int32
+}
+
+func (q *Queries) SetAnchorTxProofsInvalidated(ctx context.Context, arg SetAnchorTxProofsInvalidatedParams) error {
+	_, err := q.db.ExecContext(ctx, setAnchorTxProofsInvalidated, arg.Invalidated, arg.TxnID)
+	return err
+}
+
 const setAssetSpent = `-- name: SetAssetSpent :one
 WITH target_asset(asset_id) AS (
     SELECT assets.asset_id
@@ -2038,11 +2724,28 @@ func (q *Queries) UpdateMintingBatchState(ctx context.Context, arg UpdateMinting
 	return err
 }
 
+const updateUTXOLease = `-- name: UpdateUTXOLease :exec
+UPDATE managed_utxos
+SET lease_owner = $1, lease_expiry = $2
+WHERE outpoint = $3
+`
+
+type UpdateUTXOLeaseParams struct {
+	LeaseOwner  []byte
+	LeaseExpiry sql.NullTime
+	Outpoint    []byte
+}
+
+func (q *Queries) UpdateUTXOLease(ctx context.Context, arg UpdateUTXOLeaseParams) error {
+	_, err := q.db.ExecContext(ctx, updateUTXOLease, arg.LeaseOwner, arg.LeaseExpiry, arg.Outpoint)
+	return err
+}
+
 const upsertAssetGroupKey = `-- name: UpsertAssetGroupKey :one
 INSERT INTO asset_groups (
-    tweaked_group_key, internal_key_id, genesis_point_id 
+    tweaked_group_key, internal_key_id, genesis_point_id, tapscript_root
 ) VALUES (
-    $1, $2, $3
+    $1, $2, $3, $4
 ) ON CONFLICT (tweaked_group_key)
     -- This is not a NOP, update the genesis point ID in case it wasn't set
     -- before.
@@ -2054,10 +2757,16 @@ type UpsertAssetGroupKeyParams struct {
 	TweakedGroupKey []byte
 	InternalKeyID   int32
 	GenesisPointID  int32
+	TapscriptRoot   []byte
 }
 
 func (q *Queries) UpsertAssetGroupKey(ctx context.Context, arg UpsertAssetGroupKeyParams) (int32, error) {
-	row := q.db.QueryRowContext(ctx, upsertAssetGroupKey, arg.TweakedGroupKey, arg.InternalKeyID, arg.GenesisPointID)
+	row := q.db.QueryRowContext(ctx, upsertAssetGroupKey,
+		arg.TweakedGroupKey,
+		arg.InternalKeyID,
+		arg.GenesisPointID,
+		arg.TapscriptRoot,
+	)
 	var group_id int32
 	err := row.Scan(&group_id)
 	return group_id, err
@@ -2065,22 +2774,28 @@ func (q *Queries) UpsertAssetGroupKey(ctx context.Context, arg UpsertAssetGroupK
 
 const upsertAssetGroupSig = `-- name: UpsertAssetGroupSig :one
 INSERT INTO asset_group_sigs (
-    genesis_sig, gen_asset_id, group_key_id
+    genesis_sig, gen_asset_id, group_key_id, witness_stack
 ) VALUES (
-    $1, $2, $3
+    $1, $2, $3, $4
 ) ON CONFLICT (gen_asset_id)
     DO UPDATE SET gen_asset_id = EXCLUDED.gen_asset_id
 RETURNING sig_id
 `
 
 type UpsertAssetGroupSigParams struct {
-	GenesisSig []byte
-	GenAssetID int32
-	GroupKeyID int32
+	GenesisSig   []byte
+	GenAssetID   int32
+	GroupKeyID   int32
+	WitnessStack []byte
 }
 
 func (q *Queries) UpsertAssetGroupSig(ctx context.Context, arg UpsertAssetGroupSigParams) (int32, error) {
-	row := q.db.QueryRowContext(ctx, upsertAssetGroupSig, arg.GenesisSig, arg.GenAssetID, arg.GroupKeyID)
+	row := q.db.QueryRowContext(ctx, upsertAssetGroupSig,
+		arg.GenesisSig,
+		arg.GenAssetID,
+		arg.GroupKeyID,
+		arg.WitnessStack,
+	)
 	var sig_id int32
 	err := row.Scan(&sig_id)
 	return sig_id, err
@@ -2088,26 +2803,33 @@ func (q *Queries) UpsertAssetGroupSig(ctx context.Context, arg UpsertAssetGroupS
 
 const upsertAssetMeta = `-- name: UpsertAssetMeta :one
 INSERT INTO assets_meta (
-    meta_data_hash, meta_data_blob, meta_data_type
+    meta_data_hash, meta_data_blob, meta_data_type, decimal_display
 ) VALUES (
-    $1, $2, $3 
+    $1, $2, $3, $4
 ) ON CONFLICT (meta_data_hash)
     -- In this case, we may be inserting the data+type for an existing blob. So
-    -- we'll set both of those values. At this layer we assume the meta hash
+    -- we'll set all of those values. At this layer we assume the meta hash
     -- has been validated elsewhere.
     DO UPDATE SET meta_data_blob = EXCLUDED.meta_data_blob, 
-        meta_data_type = EXCLUDED.meta_data_type
+        meta_data_type = EXCLUDED.meta_data_type,
+        decimal_display = EXCLUDED.decimal_display
 RETURNING meta_id
 `
 
 type UpsertAssetMetaParams struct {
-	MetaDataHash []byte
-	MetaDataBlob []byte
-	MetaDataType sql.NullInt16
+	MetaDataHash   []byte
+	MetaDataBlob   []byte
+	MetaDataType   sql.NullInt16
+	DecimalDisplay int32
 }
 
 func (q *Queries) UpsertAssetMeta(ctx context.Context, arg UpsertAssetMetaParams) (int32, error) {
-	row := q.db.QueryRowContext(ctx, upsertAssetMeta, arg.MetaDataHash, arg.MetaDataBlob, arg.MetaDataType)
+	row := q.db.QueryRowContext(ctx, upsertAssetMeta,
+		arg.MetaDataHash,
+		arg.MetaDataBlob,
+		arg.MetaDataType,
+		arg.DecimalDisplay,
+	)
 	var meta_id int32
 	err := row.Scan(&meta_id)
 	return meta_id, err
@@ -2276,13 +2998,14 @@ WITH target_key(key_id) AS (
 )
 INSERT INTO managed_utxos (
     outpoint, amt_sats, internal_key_id, tapscript_sibling, merkle_root, txn_id,
-    taproot_asset_root
+    taproot_asset_root, alt_leaves
 ) VALUES (
-    $2, $3, (SELECT key_id FROM target_key), $4, $5, $6, $7
+    $2, $3, (SELECT key_id FROM target_key), $4, $5, $6, $7, $8
 ) ON CONFLICT (outpoint)
    -- Not a NOP but instead update any nullable fields that aren't null in the
    -- args.
-   DO UPDATE SET tapscript_sibling = COALESCE(EXCLUDED.tapscript_sibling, managed_utxos.tapscript_sibling)
+   DO UPDATE SET tapscript_sibling = COALESCE(EXCLUDED.tapscript_sibling, managed_utxos.tapscript_sibling),
+        alt_leaves = COALESCE(EXCLUDED.alt_leaves, managed_utxos.alt_leaves)
 RETURNING utxo_id
 `
 
@@ -2294,6 +3017,7 @@ type UpsertManagedUTXOParams struct {
 	MerkleRoot       []byte
 	TxnID            int32
 	TaprootAssetRoot []byte
+	AltLeaves        []byte
 }
 
 func (q *Queries) UpsertManagedUTXO(ctx context.Context, arg UpsertManagedUTXOParams) (int32, error) {
@@ -2305,6 +3029,7 @@ func (q *Queries) UpsertManagedUTXO(ctx context.Context, arg UpsertManagedUTXOPa
 		arg.MerkleRoot,
 		arg.TxnID,
 		arg.TaprootAssetRoot,
+		arg.AltLeaves,
 	)
 	var utxo_id int32
 	err := row.Scan(&utxo_id)
@@ -0,0 +1,2 @@
+ALTER TABLE asset_seedlings DROP COLUMN non_divisible;
+ALTER TABLE assets DROP COLUMN non_divisible;
@@ -0,0 +1,8 @@
+-- non_divisible marks a normal asset as non-divisible. Such an asset can't be
+-- split into multiple non-zero outputs. The flag is set at genesis and then
+-- carried along unchanged for every transfer of the asset.
+ALTER TABLE assets ADD COLUMN non_divisible BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- non_divisible is set for seedlings that should be minted as non-divisible
+-- normal assets.
+ALTER TABLE asset_seedlings ADD COLUMN non_divisible BOOLEAN NOT NULL DEFAULT FALSE;
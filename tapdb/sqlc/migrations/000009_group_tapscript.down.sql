@@ -0,0 +1,15 @@
+DROP VIEW IF EXISTS key_group_info_view;
+
+ALTER TABLE asset_seedlings DROP COLUMN group_tapscript_root;
+ALTER TABLE asset_group_sigs DROP COLUMN witness_stack;
+ALTER TABLE asset_groups DROP COLUMN tapscript_root;
+
+CREATE VIEW key_group_info_view AS
+    SELECT
+        sig_id, gen_asset_id, genesis_sig, tweaked_group_key, raw_key, key_index, key_family
+    FROM asset_group_sigs sigs
+    JOIN asset_groups groups
+        ON sigs.group_key_id = groups.group_id
+    JOIN internal_keys keys
+        ON keys.key_id = groups.internal_key_id
+    WHERE sigs.gen_asset_id IN (SELECT gen_asset_id FROM genesis_info_view);
@@ -0,0 +1,26 @@
+-- tapscript_root is the root of the tapscript tree an asset group key commits
+-- to. This is only set for group keys that allow new tranches to be issued by
+-- satisfying a script, in addition to a key spend with the internal key.
+ALTER TABLE asset_groups ADD COLUMN tapscript_root BLOB;
+
+-- witness_stack is the serialized group witness for asset group keys that
+-- commit to a tapscript tree. If this is set, then the genesis_sig isn't used.
+ALTER TABLE asset_group_sigs ADD COLUMN witness_stack BLOB;
+
+-- group_tapscript_root is the tapscript root the group key of a seedling that
+-- anchors a new asset group should commit to.
+ALTER TABLE asset_seedlings ADD COLUMN group_tapscript_root BLOB;
+
+-- We re-create the group info view to also expose the new group witness
+-- related columns.
+DROP VIEW IF EXISTS key_group_info_view;
+CREATE VIEW key_group_info_view AS
+    SELECT
+        sig_id, gen_asset_id, genesis_sig, witness_stack, tweaked_group_key,
+        tapscript_root, raw_key, key_index, key_family
+    FROM asset_group_sigs sigs
+    JOIN asset_groups groups
+        ON sigs.group_key_id = groups.group_id
+    JOIN internal_keys keys
+        ON keys.key_id = groups.internal_key_id
+    WHERE sigs.gen_asset_id IN (SELECT gen_asset_id FROM genesis_info_view);
@@ -0,0 +1,2 @@
+ALTER TABLE asset_seedlings DROP COLUMN editions;
+ALTER TABLE assets DROP COLUMN edition;
@@ -0,0 +1,9 @@
+-- edition is the serial number of a collectible that was minted as one of
+-- several numbered editions sharing the same genesis. A value of 0 means the
+-- asset isn't an edition. The serial is set at genesis and then carried along
+-- unchanged for every transfer of the asset.
+ALTER TABLE assets ADD COLUMN edition BIGINT NOT NULL DEFAULT 0;
+
+-- editions is set for collectible seedlings that should be minted as a series
+-- of numbered editions, one for each unit of the seedling's supply.
+ALTER TABLE asset_seedlings ADD COLUMN editions BOOLEAN NOT NULL DEFAULT FALSE;
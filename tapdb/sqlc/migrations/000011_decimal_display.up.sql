@@ -0,0 +1,5 @@
+-- decimal_display is the number of decimal places wallets should use when
+-- displaying amounts of an asset, as found in the JSON meta data blob. It's
+-- extracted when the blob is inserted so listings don't need to parse it. A
+-- value of 0 means amounts are displayed in base units.
+ALTER TABLE assets_meta ADD COLUMN decimal_display INTEGER NOT NULL DEFAULT 0;
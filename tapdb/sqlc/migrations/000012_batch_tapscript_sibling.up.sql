@@ -0,0 +1,5 @@
+-- tapscript_sibling is the serialized tapscript sibling preimage that is
+-- committed to alongside the Taproot Asset commitment in the genesis output of
+-- the batch. If NULL, the Taproot Asset commitment is the only leaf of the
+-- tapscript tree of the genesis output.
+ALTER TABLE asset_minting_batches ADD COLUMN tapscript_sibling BLOB;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS batch_proof_push_attempts_idx;
+DROP TABLE IF EXISTS batch_proof_push_attempts;
@@ -0,0 +1,10 @@
+-- batch_proof_push_attempts logs every attempt to push the genesis proofs of a
+-- confirmed minting batch to the configured universe server, so failed pushes
+-- can be retried with backoff across restarts.
+CREATE TABLE IF NOT EXISTS batch_proof_push_attempts (
+    batch_id INTEGER NOT NULL REFERENCES asset_minting_batches(batch_id),
+
+    time_unix TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS batch_proof_push_attempts_idx
+    ON batch_proof_push_attempts (batch_id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS asset_burn_transfers_asset_id_idx;
+DROP TABLE IF EXISTS asset_burn_transfers;
@@ -0,0 +1,15 @@
+-- asset_burn_transfers records every output of an asset transfer that burns
+-- assets by assigning them a provably un-spendable burn script key.
+CREATE TABLE IF NOT EXISTS asset_burn_transfers (
+    burn_id INTEGER PRIMARY KEY,
+
+    transfer_id INTEGER NOT NULL REFERENCES asset_transfers(id),
+
+    asset_id BLOB NOT NULL,
+
+    script_key BLOB NOT NULL,
+
+    amount BIGINT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS asset_burn_transfers_asset_id_idx
+    ON asset_burn_transfers (asset_id);
@@ -0,0 +1,2 @@
+ALTER TABLE managed_utxos DROP COLUMN lease_expiry;
+ALTER TABLE managed_utxos DROP COLUMN lease_owner;
@@ -0,0 +1,8 @@
+-- lease_owner is the identifier of the party that currently holds a lease
+-- (lock) on the managed UTXO. Assets anchored in a leased UTXO aren't selected
+-- to fund a new transfer until the lease is released or has expired. If NULL,
+-- the UTXO isn't leased.
+ALTER TABLE managed_utxos ADD COLUMN lease_owner BLOB;
+
+-- lease_expiry is the time at which the lease on the managed UTXO expires.
+ALTER TABLE managed_utxos ADD COLUMN lease_expiry TIMESTAMP;
@@ -0,0 +1,13 @@
+-- proof_courier_addr is the optional proof courier address hint that was
+-- encoded in the Taproot Asset address.
+ALTER TABLE addrs ADD COLUMN proof_courier_addr BLOB;
+
+-- proof_delivery_status tracks whether the proof for a transfer output still
+-- needs to be delivered to the receiver through a proof courier:
+-- 0: not required, 1: pending, 2: complete.
+ALTER TABLE asset_transfer_outputs ADD COLUMN proof_delivery_status SMALLINT NOT NULL DEFAULT 0;
+
+-- proof_courier_addr is the address of the proof courier that should be used
+-- to deliver the proof for a transfer output. If NULL, the default courier is
+-- used.
+ALTER TABLE asset_transfer_outputs ADD COLUMN proof_courier_addr BLOB;
@@ -0,0 +1,21 @@
+-- script_key_leaves stores the tapscript leaves a script key commits to, in
+-- the order they were assembled into the tapscript tree. This allows the
+-- wallet to re-create the control block of a leaf to spend an asset through
+-- the script path later on.
+CREATE TABLE IF NOT EXISTS script_key_leaves (
+    leaf_id INTEGER PRIMARY KEY,
+
+    -- The script key the leaf belongs to.
+    script_key_id INTEGER NOT NULL REFERENCES script_keys(script_key_id),
+
+    -- The index of the leaf within the list of leaves of the script key.
+    leaf_index INTEGER NOT NULL,
+
+    -- The tapscript leaf version.
+    leaf_version SMALLINT NOT NULL,
+
+    -- The raw leaf script.
+    script BLOB NOT NULL,
+
+    UNIQUE(script_key_id, leaf_index)
+);
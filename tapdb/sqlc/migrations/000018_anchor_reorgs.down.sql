@@ -0,0 +1,2 @@
+ALTER TABLE asset_transfers DROP COLUMN invalidated;
+ALTER TABLE asset_proofs DROP COLUMN invalidated;
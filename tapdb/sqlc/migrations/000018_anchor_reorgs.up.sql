@@ -0,0 +1,9 @@
+-- invalidated is set once the block the anchor transaction of a proof file
+-- was confirmed in is reorged out of the best chain. The proof file can't be
+-- verified until it's re-bound to the block the transaction re-confirms in.
+ALTER TABLE asset_proofs ADD COLUMN invalidated BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- invalidated is set once the block the anchor transaction of a transfer was
+-- confirmed in is reorged out of the best chain, until the transaction
+-- re-confirms.
+ALTER TABLE asset_transfers ADD COLUMN invalidated BOOLEAN NOT NULL DEFAULT FALSE;
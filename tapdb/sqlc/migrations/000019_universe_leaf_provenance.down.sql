@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS universe_leaf_provenance_server_idx;
+DROP TABLE IF EXISTS universe_leaf_provenance;
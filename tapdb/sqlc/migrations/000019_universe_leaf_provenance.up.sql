@@ -0,0 +1,14 @@
+-- universe_leaf_provenance tracks the universe server each leaf that was
+-- synced from the federation was fetched from. We only store the host of the
+-- server, so the provenance of a leaf is kept once the server is removed from
+-- the federation.
+CREATE TABLE IF NOT EXISTS universe_leaf_provenance (
+    leaf_id INTEGER PRIMARY KEY REFERENCES universe_leaves(id),
+
+    server_host TEXT NOT NULL,
+
+    sync_time TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS universe_leaf_provenance_server_idx
+    ON universe_leaf_provenance(server_host);
@@ -0,0 +1,35 @@
+DROP VIEW IF EXISTS universe_stats;
+
+CREATE TABLE IF NOT EXISTS universe_events_old (
+    event_id INTEGER PRIMARY KEY,
+
+    event_type VARCHAR NOT NULL CHECK (event_type IN ('SYNC', 'NEW_PROOF', 'NEW_ROOT')),
+
+    universe_root_id INTEGER NOT NULL REFERENCES universe_roots(id),
+
+    event_time TIMESTAMP NOT NULL
+);
+
+INSERT INTO universe_events_old (event_type, universe_root_id, event_time)
+    SELECT
+        CASE WHEN event_type = 'PROOF_QUERY' THEN 'SYNC' ELSE event_type END,
+        universe_root_id, event_time
+    FROM universe_events
+    ORDER BY event_id;
+
+DROP TABLE universe_events;
+ALTER TABLE universe_events_old RENAME TO universe_events;
+
+CREATE INDEX IF NOT EXISTS universe_events_event_time_idx ON universe_events(event_time);
+CREATE INDEX IF NOT EXISTS universe_events_type_idx ON universe_events(event_type);
+
+CREATE VIEW universe_stats AS
+    SELECT
+        COUNT(CASE WHEN u.event_type = 'SYNC' THEN 1 ELSE NULL END) AS total_asset_syncs,
+        COUNT(CASE WHEN u.event_type = 'NEW_PROOF' THEN 1 ELSE NULL END) AS total_asset_proofs,
+        roots.asset_id,
+        roots.group_key,
+        roots.namespace_root
+    FROM universe_events u
+    JOIN universe_roots roots ON u.universe_root_id = roots.id
+    GROUP BY roots.asset_id, roots.group_key, roots.namespace_root;
@@ -0,0 +1,42 @@
+-- We re-create the universe events table to allow the new PROOF_QUERY event
+-- type, which is logged each time a proof is queried from the universe. Proof
+-- queries used to be logged as sync events, so we convert those. From now on,
+-- sync events are logged for leaves this node synced from the federation.
+DROP VIEW IF EXISTS universe_stats;
+
+CREATE TABLE IF NOT EXISTS universe_events_new (
+    event_id INTEGER PRIMARY KEY,
+
+    event_type VARCHAR NOT NULL CHECK (event_type IN ('SYNC', 'NEW_PROOF', 'NEW_ROOT', 'PROOF_QUERY')),
+
+    universe_root_id INTEGER NOT NULL REFERENCES universe_roots(id),
+
+    event_time TIMESTAMP NOT NULL
+);
+
+INSERT INTO universe_events_new (event_type, universe_root_id, event_time)
+    SELECT
+        CASE WHEN event_type = 'SYNC' THEN 'PROOF_QUERY' ELSE event_type END,
+        universe_root_id, event_time
+    FROM universe_events
+    ORDER BY event_id;
+
+DROP TABLE universe_events;
+ALTER TABLE universe_events_new RENAME TO universe_events;
+
+CREATE INDEX IF NOT EXISTS universe_events_event_time_idx ON universe_events(event_time);
+CREATE INDEX IF NOT EXISTS universe_events_type_idx ON universe_events(event_type);
+
+-- universe_stats is a view that gives us easy access to the total number of
+-- syncs, proofs and proof queries for a given asset.
+CREATE VIEW universe_stats AS
+    SELECT
+        COUNT(CASE WHEN u.event_type = 'SYNC' THEN 1 ELSE NULL END) AS total_asset_syncs,
+        COUNT(CASE WHEN u.event_type = 'NEW_PROOF' THEN 1 ELSE NULL END) AS total_asset_proofs,
+        COUNT(CASE WHEN u.event_type = 'PROOF_QUERY' THEN 1 ELSE NULL END) AS total_asset_queries,
+        roots.asset_id,
+        roots.group_key,
+        roots.namespace_root
+    FROM universe_events u
+    JOIN universe_roots roots ON u.universe_root_id = roots.id
+    GROUP BY roots.asset_id, roots.group_key, roots.namespace_root;
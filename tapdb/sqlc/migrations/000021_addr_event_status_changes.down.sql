@@ -0,0 +1 @@
+DROP TABLE IF EXISTS addr_event_status_changes;
@@ -0,0 +1,14 @@
+-- addr_event_status_changes records the time each address event first reached
+-- each of its statuses. Address events created before this table existed only
+-- have the status changes that happen after the migration.
+CREATE TABLE IF NOT EXISTS addr_event_status_changes (
+    event_id INTEGER NOT NULL REFERENCES addr_events(id),
+
+    -- status is the status the address event transitioned to.
+    status SMALLINT NOT NULL CHECK (status IN (0, 1, 2, 3)),
+
+    -- change_time is the time the address event first reached the status.
+    change_time TIMESTAMP NOT NULL,
+
+    UNIQUE(event_id, status)
+);
@@ -0,0 +1,4 @@
+-- static indicates that the address can be used to receive any number of
+-- transfers, each of which pays to a unique script key that the sender derives
+-- from the script key of the address.
+ALTER TABLE addrs ADD COLUMN static BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1 @@
+ALTER TABLE addrs DROP COLUMN any_asset_in_group;
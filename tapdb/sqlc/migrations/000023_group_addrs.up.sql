@@ -0,0 +1,4 @@
+-- any_asset_in_group indicates that the address accepts any asset of the group
+-- identified by its group key. The genesis asset ID of such an address is only
+-- the template the address was created for.
+ALTER TABLE addrs ADD COLUMN any_asset_in_group BOOLEAN NOT NULL DEFAULT FALSE;
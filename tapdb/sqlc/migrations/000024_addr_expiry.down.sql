@@ -0,0 +1,2 @@
+ALTER TABLE addrs DROP COLUMN single_use;
+ALTER TABLE addrs DROP COLUMN expiry;
@@ -0,0 +1,6 @@
+-- expiry is the optional time after which the address no longer accepts
+-- transfers.
+ALTER TABLE addrs ADD COLUMN expiry TIMESTAMP;
+
+-- single_use indicates that the address only accepts a single transfer.
+ALTER TABLE addrs ADD COLUMN single_use BOOLEAN NOT NULL DEFAULT FALSE;
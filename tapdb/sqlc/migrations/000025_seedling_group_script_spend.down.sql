@@ -0,0 +1 @@
+ALTER TABLE asset_seedlings DROP COLUMN group_script_spend;
@@ -0,0 +1,4 @@
+-- group_script_spend is the encoded leaf, inclusion proof and signers of the
+-- group tapscript tree the issuance of a seedling into its group is
+-- authorized through. If NULL, the issuance is authorized with a key spend.
+ALTER TABLE asset_seedlings ADD COLUMN group_script_spend BLOB;
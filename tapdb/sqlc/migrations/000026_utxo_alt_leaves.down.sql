@@ -0,0 +1 @@
+ALTER TABLE managed_utxos DROP COLUMN alt_leaves;
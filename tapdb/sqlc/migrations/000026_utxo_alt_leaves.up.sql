@@ -0,0 +1,4 @@
+-- alt_leaves is the serialized set of alt leaves that are committed to in the
+-- Taproot Asset commitment of the UTXO next to the assets. They are required
+-- to re-create the full commitment when spending the UTXO.
+ALTER TABLE managed_utxos ADD COLUMN alt_leaves BLOB;
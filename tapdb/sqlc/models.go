@@ -22,6 +22,11 @@ type Addr struct {
 	AssetType        int16
 	CreationTime     time.Time
 	ManagedFrom      sql.NullTime
+	ProofCourierAddr []byte
+	Static           bool
+	AnyAssetInGroup  bool
+	Expiry           sql.NullTime
+	SingleUse        bool
 }
 
 type AddrEvent struct {
@@ -36,6 +41,12 @@ type AddrEvent struct {
 	AssetID             sql.NullInt32
 }
 
+type AddrEventStatusChange struct {
+	EventID    int32
+	Status     int16
+	ChangeTime time.Time
+}
+
 type Asset struct {
 	AssetID                  int32
 	GenesisID                int32
@@ -50,6 +61,16 @@ type Asset struct {
 	SplitCommitmentRootValue sql.NullInt64
 	AnchorUtxoID             sql.NullInt32
 	Spent                    bool
+	NonDivisible             bool
+	Edition                  int64
+}
+
+type AssetBurnTransfer struct {
+	BurnID     int32
+	TransferID int32
+	AssetID    []byte
+	ScriptKey  []byte
+	Amount     int64
 }
 
 type AssetGroup struct {
@@ -57,13 +78,15 @@ type AssetGroup struct {
 	TweakedGroupKey []byte
 	InternalKeyID   int32
 	GenesisPointID  int32
+	TapscriptRoot   []byte
 }
 
 type AssetGroupSig struct {
-	SigID      int32
-	GenesisSig []byte
-	GenAssetID int32
-	GroupKeyID int32
+	SigID        int32
+	GenesisSig   []byte
+	GenAssetID   int32
+	GroupKeyID   int32
+	WitnessStack []byte
 }
 
 type AssetMintingBatch struct {
@@ -74,24 +97,30 @@ type AssetMintingBatch struct {
 	GenesisID         sql.NullInt32
 	HeightHint        int32
 	CreationTimeUnix  time.Time
+	TapscriptSibling  []byte
 }
 
 type AssetProof struct {
-	ProofID   int32
-	AssetID   int32
-	ProofFile []byte
+	ProofID     int32
+	AssetID     int32
+	ProofFile   []byte
+	Invalidated bool
 }
 
 type AssetSeedling struct {
-	SeedlingID      int32
-	AssetName       string
-	AssetType       int16
-	AssetSupply     int64
-	AssetMetaID     int32
-	EmissionEnabled bool
-	BatchID         int32
-	GroupGenesisID  sql.NullInt32
-	GroupAnchorID   sql.NullInt32
+	SeedlingID         int32
+	AssetName          string
+	AssetType          int16
+	AssetSupply        int64
+	AssetMetaID        int32
+	EmissionEnabled    bool
+	BatchID            int32
+	GroupGenesisID     sql.NullInt32
+	GroupAnchorID      sql.NullInt32
+	NonDivisible       bool
+	GroupTapscriptRoot []byte
+	Editions           bool
+	GroupScriptSpend   []byte
 }
 
 type AssetTransfer struct {
@@ -99,6 +128,7 @@ type AssetTransfer struct {
 	HeightHint       int32
 	AnchorTxnID      int32
 	TransferTimeUnix time.Time
+	Invalidated      bool
 }
 
 type AssetTransferInput struct {
@@ -123,6 +153,8 @@ type AssetTransferOutput struct {
 	ProofSuffix              []byte
 	NumPassiveAssets         int32
 	OutputType               int16
+	ProofDeliveryStatus      int16
+	ProofCourierAddr         []byte
 }
 
 type AssetWitness struct {
@@ -136,10 +168,16 @@ type AssetWitness struct {
 }
 
 type AssetsMetum struct {
-	MetaID       int32
-	MetaDataHash []byte
-	MetaDataBlob []byte
-	MetaDataType sql.NullInt16
+	MetaID         int32
+	MetaDataHash   []byte
+	MetaDataBlob   []byte
+	MetaDataType   sql.NullInt16
+	DecimalDisplay int32
+}
+
+type BatchProofPushAttempt struct {
+	BatchID  int32
+	TimeUnix time.Time
 }
 
 type ChainTxn struct {
@@ -189,7 +227,9 @@ type KeyGroupInfoView struct {
 	SigID           int32
 	GenAssetID      int32
 	GenesisSig      []byte
+	WitnessStack    []byte
 	TweakedGroupKey []byte
+	TapscriptRoot   []byte
 	RawKey          []byte
 	KeyIndex        int32
 	KeyFamily       int32
@@ -209,6 +249,9 @@ type ManagedUtxo struct {
 	TapscriptSibling []byte
 	MerkleRoot       []byte
 	TxnID            int32
+	LeaseOwner       []byte
+	LeaseExpiry      sql.NullTime
+	AltLeaves        []byte
 }
 
 type MssmtNode struct {
@@ -248,6 +291,14 @@ type ScriptKey struct {
 	Tweak            []byte
 }
 
+type ScriptKeyLeafe struct {
+	LeafID      int32
+	ScriptKeyID int32
+	LeafIndex   int32
+	LeafVersion int16
+	Script      []byte
+}
+
 type UniverseEvent struct {
 	EventID        int32
 	EventType      string
@@ -265,6 +316,12 @@ type UniverseLeafe struct {
 	LeafNodeNamespace string
 }
 
+type UniverseLeafProvenance struct {
+	LeafID     int32
+	ServerHost string
+	SyncTime   time.Time
+}
+
 type UniverseRoot struct {
 	ID            int32
 	NamespaceRoot string
@@ -279,9 +336,10 @@ type UniverseServer struct {
 }
 
 type UniverseStat struct {
-	TotalAssetSyncs  int64
-	TotalAssetProofs int64
-	AssetID          []byte
-	GroupKey         []byte
-	NamespaceRoot    string
+	TotalAssetSyncs   int64
+	TotalAssetProofs  int64
+	TotalAssetQueries int64
+	AssetID           []byte
+	GroupKey          []byte
+	NamespaceRoot     string
 }
@@ -9,6 +9,18 @@ import (
 	"context"
 )
 
+const deleteAllNodes = `-- name: DeleteAllNodes :execrows
+DELETE FROM mssmt_nodes WHERE namespace = $1
+`
+
+func (q *Queries) DeleteAllNodes(ctx context.Context, namespace string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteAllNodes, namespace)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const deleteNode = `-- name: DeleteNode :execrows
 DELETE FROM mssmt_nodes WHERE hash_key = $1 AND namespace = $2
 `
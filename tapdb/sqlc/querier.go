@@ -19,17 +19,34 @@ type Querier interface {
 	ApplyPendingOutput(ctx context.Context, arg ApplyPendingOutputParams) (int32, error)
 	AssetsByGenesisPoint(ctx context.Context, prevOut []byte) ([]AssetsByGenesisPointRow, error)
 	AssetsInBatch(ctx context.Context, rawKey []byte) ([]AssetsInBatchRow, error)
+	BindMintingBatchWithTapSibling(ctx context.Context, arg BindMintingBatchWithTapSiblingParams) error
 	BindMintingBatchWithTx(ctx context.Context, arg BindMintingBatchWithTxParams) error
 	ConfirmChainAnchorTx(ctx context.Context, arg ConfirmChainAnchorTxParams) error
 	ConfirmChainTx(ctx context.Context, arg ConfirmChainTxParams) error
+	DeleteAddr(ctx context.Context, taprootOutputKey []byte) error
+	DeleteAddrEventStatusChanges(ctx context.Context, taprootOutputKey []byte) error
+	DeleteAddrEvents(ctx context.Context, taprootOutputKey []byte) error
 	DeleteAssetWitnesses(ctx context.Context, assetID int32) error
+	DeleteBatchProofPushAttempts(ctx context.Context, arg DeleteBatchProofPushAttemptsParams) (int64, error)
+	DeleteBatchSeedlings(ctx context.Context, arg DeleteBatchSeedlingsParams) (int64, error)
+	// Sprouts are only anchored once the genesis transaction of their batch is
+	// signed, so the sprouts of a cancelled batch were never anchored.
+	DeleteBatchSprouts(ctx context.Context, arg DeleteBatchSproutsParams) (int64, error)
+	DeleteExpiredUTXOLeases(ctx context.Context, now sql.NullTime) error
 	DeleteManagedUTXO(ctx context.Context, outpoint []byte) error
+	DeleteMintingBatches(ctx context.Context, arg DeleteMintingBatchesParams) (int64, error)
+	DeleteAllNodes(ctx context.Context, namespace string) (int64, error)
 	DeleteNode(ctx context.Context, arg DeleteNodeParams) (int64, error)
+	DeleteSeedling(ctx context.Context, arg DeleteSeedlingParams) error
+	DeleteUTXOLease(ctx context.Context, outpoint []byte) error
 	DeleteUniverseServer(ctx context.Context, arg DeleteUniverseServerParams) error
 	FetchAddrByTaprootOutputKey(ctx context.Context, taprootOutputKey []byte) (FetchAddrByTaprootOutputKeyRow, error)
 	FetchAddrEvent(ctx context.Context, id int32) (FetchAddrEventRow, error)
+	FetchAddrEventStatusChanges(ctx context.Context, eventID int32) ([]FetchAddrEventStatusChangesRow, error)
 	FetchAddrs(ctx context.Context, arg FetchAddrsParams) ([]FetchAddrsRow, error)
 	FetchAllNodes(ctx context.Context) ([]MssmtNode, error)
+	FetchAnchorTxProofs(ctx context.Context, txnID int32) ([]FetchAnchorTxProofsRow, error)
+	FetchAnchorTxs(ctx context.Context, arg FetchAnchorTxsParams) ([]ChainTxn, error)
 	FetchAssetMeta(ctx context.Context, metaID int32) (FetchAssetMetaRow, error)
 	FetchAssetMetaByHash(ctx context.Context, metaDataHash []byte) (FetchAssetMetaByHashRow, error)
 	FetchAssetMetaForAsset(ctx context.Context, assetID []byte) (FetchAssetMetaForAssetRow, error)
@@ -52,6 +69,9 @@ type Querier interface {
 	FetchGroupByGenesis(ctx context.Context, genesisID int32) (FetchGroupByGenesisRow, error)
 	// Sort and limit to return the genesis ID for initial genesis of the group.
 	FetchGroupByGroupKey(ctx context.Context, groupKey []byte) (FetchGroupByGroupKeyRow, error)
+	FetchGroupKeysByKeyLocator(ctx context.Context, arg FetchGroupKeysByKeyLocatorParams) ([][]byte, error)
+	// We do a LEFT JOIN here, as not every tranche reveals its metadata.
+	FetchGroupTranches(ctx context.Context, groupKey []byte) ([]FetchGroupTranchesRow, error)
 	FetchGroupedAssets(ctx context.Context) ([]FetchGroupedAssetsRow, error)
 	FetchManagedUTXO(ctx context.Context, arg FetchManagedUTXOParams) (FetchManagedUTXORow, error)
 	FetchManagedUTXOs(ctx context.Context) ([]FetchManagedUTXOsRow, error)
@@ -60,32 +80,41 @@ type Querier interface {
 	FetchRootNode(ctx context.Context, namespace string) (MssmtNode, error)
 	FetchScriptKeyByTweakedKey(ctx context.Context, tweakedScriptKey []byte) (FetchScriptKeyByTweakedKeyRow, error)
 	FetchScriptKeyIDByTweakedKey(ctx context.Context, tweakedScriptKey []byte) (int32, error)
+	FetchScriptKeyLeaves(ctx context.Context, tweakedScriptKey []byte) ([]FetchScriptKeyLeavesRow, error)
 	FetchSeedlingByID(ctx context.Context, seedlingID int32) (AssetSeedling, error)
 	FetchSeedlingID(ctx context.Context, arg FetchSeedlingIDParams) (int32, error)
 	FetchSeedlingsForBatch(ctx context.Context, rawKey []byte) ([]FetchSeedlingsForBatchRow, error)
 	FetchTransferInputs(ctx context.Context, transferID int32) ([]FetchTransferInputsRow, error)
 	FetchTransferOutputs(ctx context.Context, transferID int32) ([]FetchTransferOutputsRow, error)
 	FetchUniverseKeys(ctx context.Context, namespace string) ([]FetchUniverseKeysRow, error)
+	FetchUniverseLeafID(ctx context.Context, arg FetchUniverseLeafIDParams) (int32, error)
+	FetchUniverseLeafNodes(ctx context.Context, namespace string) ([]FetchUniverseLeafNodesRow, error)
 	FetchUniverseRoot(ctx context.Context, namespace string) (FetchUniverseRootRow, error)
 	GenesisAssets(ctx context.Context) ([]GenesisAsset, error)
 	GenesisPoints(ctx context.Context) ([]GenesisPoint, error)
 	GetRootKey(ctx context.Context, id []byte) (Macaroon, error)
 	InsertAddr(ctx context.Context, arg InsertAddrParams) (int32, error)
+	InsertAddrEventStatusChange(ctx context.Context, arg InsertAddrEventStatusChangeParams) error
 	InsertAssetSeedling(ctx context.Context, arg InsertAssetSeedlingParams) error
 	InsertAssetSeedlingIntoBatch(ctx context.Context, arg InsertAssetSeedlingIntoBatchParams) error
 	InsertAssetTransfer(ctx context.Context, arg InsertAssetTransferParams) (int32, error)
 	InsertAssetTransferInput(ctx context.Context, arg InsertAssetTransferInputParams) error
 	InsertAssetTransferOutput(ctx context.Context, arg InsertAssetTransferOutputParams) error
 	InsertAssetWitness(ctx context.Context, arg InsertAssetWitnessParams) error
+	InsertBatchProofPushAttempt(ctx context.Context, arg InsertBatchProofPushAttemptParams) error
 	InsertBranch(ctx context.Context, arg InsertBranchParams) error
+	InsertBurn(ctx context.Context, arg InsertBurnParams) (int32, error)
 	InsertCompactedLeaf(ctx context.Context, arg InsertCompactedLeafParams) error
 	InsertLeaf(ctx context.Context, arg InsertLeafParams) error
+	InsertLeafProvenance(ctx context.Context, arg InsertLeafProvenanceParams) error
 	InsertNewAsset(ctx context.Context, arg InsertNewAssetParams) (int32, error)
 	InsertNewProofEvent(ctx context.Context, arg InsertNewProofEventParams) error
+	InsertNewProofQueryEvent(ctx context.Context, arg InsertNewProofQueryEventParams) error
 	InsertNewSyncEvent(ctx context.Context, arg InsertNewSyncEventParams) error
 	InsertPassiveAsset(ctx context.Context, arg InsertPassiveAssetParams) error
 	InsertReceiverProofTransferAttempt(ctx context.Context, arg InsertReceiverProofTransferAttemptParams) error
 	InsertRootKey(ctx context.Context, arg InsertRootKeyParams) error
+	InsertScriptKeyLeaf(ctx context.Context, arg InsertScriptKeyLeafParams) error
 	InsertUniverseLeaf(ctx context.Context, arg InsertUniverseLeafParams) error
 	InsertUniverseServer(ctx context.Context, arg InsertUniverseServerParams) error
 	ListUniverseServers(ctx context.Context) ([]UniverseServer, error)
@@ -101,6 +130,9 @@ type Querier interface {
 	// unconfirmed. But only if the unconf_only field is set.
 	// Here we have another optional query clause to select a given transfer
 	// based on the anchor_tx_hash, but only if it's specified.
+	// This clause selects only confirmed transfers that still have at least one
+	// output with a pending proof delivery, but only if the pending_proofs_only
+	// field is set.
 	QueryAssetTransfers(ctx context.Context, arg QueryAssetTransfersParams) ([]QueryAssetTransfersRow, error)
 	// We use a LEFT JOIN here as not every asset has a group key, so this'll
 	// generate rows that have NULL values for the group key fields if an asset
@@ -111,21 +143,37 @@ type Querier interface {
 	// make the entire statement evaluate to true, if none of these extra args are
 	// specified.
 	QueryAssets(ctx context.Context, arg QueryAssetsParams) ([]QueryAssetsRow, error)
+	QueryBatchProofPushAttempts(ctx context.Context, rawKey []byte) ([]time.Time, error)
+	// We'll only return the burns of a given asset if the asset ID is
+	// specified.
+	QueryBurns(ctx context.Context, assetID []byte) ([]QueryBurnsRow, error)
+	QueryDeadAddrs(ctx context.Context, arg QueryDeadAddrsParams) ([][]byte, error)
 	QueryEventIDs(ctx context.Context, arg QueryEventIDsParams) ([]QueryEventIDsRow, error)
+	QueryLeafProvenance(ctx context.Context, namespace string) ([]QueryLeafProvenanceRow, error)
+	// Branches are the only nodes without a value, compacted leaves are the only
+	// nodes with a key.
+	QueryMssmtNodeStats(ctx context.Context) (QueryMssmtNodeStatsRow, error)
 	QueryPassiveAssets(ctx context.Context, transferID int32) ([]QueryPassiveAssetsRow, error)
+	QueryProofArchiveStats(ctx context.Context) (QueryProofArchiveStatsRow, error)
 	QueryReceiverProofTransferAttempt(ctx context.Context, proofLocatorHash []byte) ([]time.Time, error)
+	QueryTableRowCounts(ctx context.Context) ([]QueryTableRowCountsRow, error)
 	// TODO(roasbeef): use the universe id instead for the grouping? so namespace
 	// root, simplifies queries
 	QueryUniverseAssetStats(ctx context.Context, arg QueryUniverseAssetStatsParams) ([]QueryUniverseAssetStatsRow, error)
 	QueryUniverseLeaves(ctx context.Context, arg QueryUniverseLeavesParams) ([]QueryUniverseLeavesRow, error)
 	QueryUniverseStats(ctx context.Context) (QueryUniverseStatsRow, error)
 	ReAnchorPassiveAssets(ctx context.Context, arg ReAnchorPassiveAssetsParams) error
+	RebindAssetProof(ctx context.Context, arg RebindAssetProofParams) error
 	SetAddrManaged(ctx context.Context, arg SetAddrManagedParams) error
+	SetAnchorTxProofsInvalidated(ctx context.Context, arg SetAnchorTxProofsInvalidatedParams) error
+	SetAnchorTxTransfersInvalidated(ctx context.Context, arg SetAnchorTxTransfersInvalidatedParams) error
 	SetAssetSpent(ctx context.Context, arg SetAssetSpentParams) (int32, error)
+	SetTransferOutputProofDeliveryStatus(ctx context.Context, arg SetTransferOutputProofDeliveryStatusParams) error
 	UniverseLeaves(ctx context.Context) ([]UniverseLeafe, error)
 	UniverseRoots(ctx context.Context) ([]UniverseRootsRow, error)
 	UpdateBatchGenesisTx(ctx context.Context, arg UpdateBatchGenesisTxParams) error
 	UpdateMintingBatchState(ctx context.Context, arg UpdateMintingBatchStateParams) error
+	UpdateUTXOLease(ctx context.Context, arg UpdateUTXOLeaseParams) error
 	UpsertAddrEvent(ctx context.Context, arg UpsertAddrEventParams) (int32, error)
 	UpsertAssetGroupKey(ctx context.Context, arg UpsertAssetGroupKeyParams) (int32, error)
 	UpsertAssetGroupSig(ctx context.Context, arg UpsertAssetGroupSigParams) (int32, error)
@@ -1,13 +1,17 @@
 -- name: InsertAddr :one
 INSERT INTO addrs (
     version, genesis_asset_id, group_key, script_key_id, taproot_key_id,
-    tapscript_sibling, taproot_output_key, amount, asset_type, creation_time
-) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id;
+    tapscript_sibling, taproot_output_key, amount, asset_type, creation_time,
+    proof_courier_addr, static, any_asset_in_group, expiry, single_use
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
+) RETURNING id;
 
 -- name: FetchAddrs :many
 SELECT 
     version, genesis_asset_id, group_key, tapscript_sibling, taproot_output_key,
-    amount, asset_type, creation_time, managed_from,
+    amount, asset_type, creation_time, managed_from, proof_courier_addr,
+    static, any_asset_in_group, expiry, single_use,
     script_keys.tweaked_script_key,
     script_keys.tweak AS script_key_tweak,
     raw_script_keys.raw_key AS raw_script_key,
@@ -27,13 +31,17 @@ WHERE creation_time >= @created_after
     AND creation_time <= @created_before
     AND (@unmanaged_only = false OR
          (CASE WHEN managed_from IS NULL THEN true ELSE false END) = @unmanaged_only)
+    AND (@static_only = false OR static = @static_only)
+    AND (@amountless_only = false OR amount = 0)
+    AND (@group_only = false OR any_asset_in_group = @group_only)
 ORDER BY addrs.creation_time
 LIMIT @num_limit OFFSET @num_offset;
 
 -- name: FetchAddrByTaprootOutputKey :one
 SELECT
     version, genesis_asset_id, group_key, tapscript_sibling, taproot_output_key,
-    amount, asset_type, creation_time, managed_from,
+    amount, asset_type, creation_time, managed_from, proof_courier_addr,
+    static, any_asset_in_group, expiry, single_use,
     script_keys.tweaked_script_key,
     script_keys.tweak AS script_key_tweak,
     raw_script_keys.raw_key as raw_script_key,
@@ -84,6 +92,20 @@ ON CONFLICT (addr_id, chain_txn_id, chain_txn_output_index)
                   asset_id = COALESCE(EXCLUDED.asset_id, addr_events.asset_id)
 RETURNING id;
 
+-- name: InsertAddrEventStatusChange :exec
+INSERT INTO addr_event_status_changes (
+    event_id, status, change_time
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (event_id, status) DO NOTHING;
+
+-- name: FetchAddrEventStatusChanges :many
+SELECT status, change_time
+FROM addr_event_status_changes
+WHERE event_id = $1
+ORDER BY status;
+
 -- name: FetchAddrEvent :one
 SELECT
     creation_time, status, asset_proof_id, asset_id,
@@ -112,3 +134,42 @@ WHERE addr_events.status >= @status_from
   AND addr_events.status <= @status_to
   AND COALESCE(@addr_taproot_key, addrs.taproot_output_key) = addrs.taproot_output_key
 ORDER by addr_events.creation_time;
+
+-- name: QueryDeadAddrs :many
+SELECT taproot_output_key
+FROM addrs
+WHERE ((expiry IS NOT NULL AND expiry <= @now) OR
+       (single_use = true AND EXISTS (
+           SELECT 1
+           FROM addr_events
+           WHERE addr_events.addr_id = addrs.id
+       )))
+    AND NOT EXISTS (
+        SELECT 1
+        FROM addr_events
+        WHERE addr_events.addr_id = addrs.id
+            AND addr_events.status != @completed_status
+    )
+ORDER BY addrs.creation_time;
+
+-- name: DeleteAddrEventStatusChanges :exec
+DELETE FROM addr_event_status_changes
+WHERE event_id IN (
+    SELECT addr_events.id
+    FROM addr_events
+    JOIN addrs
+      ON addr_events.addr_id = addrs.id
+    WHERE addrs.taproot_output_key = $1
+);
+
+-- name: DeleteAddrEvents :exec
+DELETE FROM addr_events
+WHERE addr_id IN (
+    SELECT id
+    FROM addrs
+    WHERE addrs.taproot_output_key = $1
+);
+
+-- name: DeleteAddr :exec
+DELETE FROM addrs
+WHERE taproot_output_key = $1;
@@ -57,10 +57,12 @@ WHERE batch_id in (SELECT batch_id FROM target_batch);
 -- name: InsertAssetSeedling :exec
 INSERT INTO asset_seedlings (
     asset_name, asset_type, asset_supply, asset_meta_id,
-    emission_enabled, batch_id, group_genesis_id, group_anchor_id
+    emission_enabled, batch_id, group_genesis_id, group_anchor_id,
+    non_divisible, group_tapscript_root, editions, group_script_spend
 ) VALUES (
    $1, $2, $3, $4, $5, $6,
-   sqlc.narg('group_genesis_id'), sqlc.narg('group_anchor_id')
+   sqlc.narg('group_genesis_id'), sqlc.narg('group_anchor_id'),
+   @non_divisible, @group_tapscript_root, @editions, @group_script_spend
 );
 
 -- name: FetchSeedlingID :one
@@ -80,6 +82,15 @@ WHERE (
     asset_seedlings.asset_name = @seedling_name
 );
 
+-- name: DeleteSeedling :exec
+DELETE FROM asset_seedlings
+WHERE asset_seedlings.asset_name = @seedling_name AND
+    asset_seedlings.batch_id IN (
+        SELECT key_id
+        FROM internal_keys keys
+        WHERE keys.raw_key = @batch_key
+    );
+
 -- name: FetchSeedlingByID :one
 SELECT *
 FROM asset_seedlings
@@ -108,11 +119,13 @@ WITH target_key_id AS (
 )
 INSERT INTO asset_seedlings(
     asset_name, asset_type, asset_supply, asset_meta_id,
-    emission_enabled, batch_id, group_genesis_id, group_anchor_id
+    emission_enabled, batch_id, group_genesis_id, group_anchor_id,
+    non_divisible, group_tapscript_root, editions, group_script_spend
 ) VALUES (
     $2, $3, $4, $5, $6,
     (SELECT key_id FROM target_key_id),
-    sqlc.narg('group_genesis_id'), sqlc.narg('group_anchor_id')
+    sqlc.narg('group_genesis_id'), sqlc.narg('group_anchor_id'),
+    @non_divisible, @group_tapscript_root, @editions, @group_script_spend
 );
 
 -- name: FetchSeedlingsForBatch :many
@@ -126,7 +139,9 @@ WITH target_batch(batch_id) AS (
 SELECT seedling_id, asset_name, asset_type, asset_supply, 
     assets_meta.meta_data_hash, assets_meta.meta_data_type, 
     assets_meta.meta_data_blob, emission_enabled, batch_id, 
-    group_genesis_id, group_anchor_id
+    group_genesis_id, group_anchor_id, non_divisible,
+    group_tapscript_root, editions, assets_meta.decimal_display,
+    group_script_spend
 FROM asset_seedlings 
 LEFT JOIN assets_meta
     ON asset_seedlings.asset_meta_id = assets_meta.meta_id
@@ -144,9 +159,9 @@ RETURNING genesis_id;
 
 -- name: UpsertAssetGroupKey :one
 INSERT INTO asset_groups (
-    tweaked_group_key, internal_key_id, genesis_point_id 
+    tweaked_group_key, internal_key_id, genesis_point_id, tapscript_root
 ) VALUES (
-    $1, $2, $3
+    $1, $2, $3, $4
 ) ON CONFLICT (tweaked_group_key)
     -- This is not a NOP, update the genesis point ID in case it wasn't set
     -- before.
@@ -155,9 +170,9 @@ RETURNING group_id;
 
 -- name: UpsertAssetGroupSig :one
 INSERT INTO asset_group_sigs (
-    genesis_sig, gen_asset_id, group_key_id
+    genesis_sig, gen_asset_id, group_key_id, witness_stack
 ) VALUES (
-    $1, $2, $3
+    $1, $2, $3, $4
 ) ON CONFLICT (gen_asset_id)
     DO UPDATE SET gen_asset_id = EXCLUDED.gen_asset_id
 RETURNING sig_id;
@@ -181,9 +196,10 @@ RETURNING gen_asset_id;
 -- name: InsertNewAsset :one
 INSERT INTO assets (
     genesis_id, version, script_key_id, asset_group_sig_id, script_version, 
-    amount, lock_time, relative_lock_time, anchor_utxo_id, spent
+    amount, lock_time, relative_lock_time, anchor_utxo_id, spent,
+    non_divisible, edition
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
 ) RETURNING asset_id;
 
 -- name: FetchAssetsForBatch :many
@@ -215,7 +231,8 @@ WITH genesis_info AS (
     -- assets we care about. We obtain only the assets found in the batch
     -- above, with the WHERE query at the bottom.
     SELECT 
-        sig_id, gen_asset_id, genesis_sig, tweaked_group_key, raw_key, key_index, key_family
+        sig_id, gen_asset_id, genesis_sig, witness_stack, tweaked_group_key,
+        tapscript_root, raw_key, key_index, key_family
     FROM asset_group_sigs sigs
     JOIN asset_groups groups
         ON sigs.group_key_id = groups.group_id
@@ -230,12 +247,14 @@ SELECT
     internal_keys.key_family AS script_key_fam,
     internal_keys.key_index AS script_key_index,
     key_group_info.genesis_sig, 
+    key_group_info.witness_stack AS group_witness,
     key_group_info.tweaked_group_key,
+    key_group_info.tapscript_root AS group_tapscript_root,
     key_group_info.raw_key AS group_key_raw,
     key_group_info.key_family AS group_key_family,
     key_group_info.key_index AS group_key_index,
     script_version, amount, lock_time, relative_lock_time, spent,
-    genesis_info.asset_id, genesis_info.asset_tag, genesis_info.meta_hash, 
+    non_divisible, edition, genesis_info.asset_id, genesis_info.asset_tag, genesis_info.meta_hash, 
     genesis_info.meta_type, genesis_info.meta_blob, 
     genesis_info.output_index AS genesis_output_index, genesis_info.asset_type,
     genesis_info.prev_out AS genesis_prev_out
@@ -328,7 +347,9 @@ SELECT
     key_group_info_view.raw_key AS raw_key,
     key_group_info_view.key_index AS key_index,
     key_group_info_view.key_family AS key_family,
-    key_group_info_view.genesis_sig AS genesis_sig
+    key_group_info_view.genesis_sig AS genesis_sig,
+    key_group_info_view.witness_stack AS witness_stack,
+    key_group_info_view.tapscript_root AS tapscript_root
 FROM key_group_info_view
 WHERE (
     key_group_info_view.tweaked_group_key = @group_key
@@ -343,12 +364,37 @@ SELECT
     key_group_info_view.raw_key AS raw_key,
     key_group_info_view.key_index AS key_index,
     key_group_info_view.key_family AS key_family,
-    key_group_info_view.genesis_sig AS genesis_sig
+    key_group_info_view.genesis_sig AS genesis_sig,
+    key_group_info_view.witness_stack AS witness_stack,
+    key_group_info_view.tapscript_root AS tapscript_root
 FROM key_group_info_view
 WHERE (
     key_group_info_view.gen_asset_id = @genesis_id
 );
 
+-- name: FetchGroupKeysByKeyLocator :many
+SELECT DISTINCT tweaked_group_key
+FROM key_group_info_view
+WHERE key_family = @key_family AND key_index = @key_index;
+
+-- name: FetchGroupTranches :many
+SELECT
+    genesis_info_view.asset_id, genesis_info_view.asset_tag,
+    genesis_info_view.meta_hash, genesis_info_view.output_index,
+    genesis_info_view.asset_type, genesis_info_view.prev_out,
+    assets_meta.meta_data_blob, assets_meta.meta_data_type,
+    key_group_info_view.raw_key, key_group_info_view.key_index,
+    key_group_info_view.key_family, key_group_info_view.genesis_sig,
+    key_group_info_view.witness_stack, key_group_info_view.tapscript_root
+FROM key_group_info_view
+JOIN genesis_info_view
+    ON key_group_info_view.gen_asset_id = genesis_info_view.gen_asset_id
+-- We do a LEFT JOIN here, as not every tranche reveals its metadata.
+LEFT JOIN assets_meta
+    ON genesis_info_view.meta_hash = assets_meta.meta_data_hash
+WHERE key_group_info_view.tweaked_group_key = @group_key
+ORDER BY key_group_info_view.sig_id;
+
 -- name: QueryAssets :many
 SELECT
     assets.asset_id AS asset_primary_key, assets.genesis_id, version, spent,
@@ -358,11 +404,14 @@ SELECT
     internal_keys.key_family AS script_key_fam,
     internal_keys.key_index AS script_key_index,
     key_group_info_view.genesis_sig, 
+    key_group_info_view.witness_stack AS group_witness,
     key_group_info_view.tweaked_group_key,
+    key_group_info_view.tapscript_root AS group_tapscript_root,
     key_group_info_view.raw_key AS group_key_raw,
     key_group_info_view.key_family AS group_key_family,
     key_group_info_view.key_index AS group_key_index,
-    script_version, amount, lock_time, relative_lock_time, 
+    script_version, amount, lock_time, relative_lock_time, non_divisible,
+    edition,
     genesis_info_view.asset_id AS asset_id,
     genesis_info_view.asset_tag,
     genesis_info_view.meta_hash, 
@@ -377,12 +426,15 @@ SELECT
     utxos.merkle_root AS anchor_merkle_root,
     utxos.taproot_asset_root AS anchor_taproot_asset_root,
     utxo_internal_keys.raw_key AS anchor_internal_key,
-    split_commitment_root_hash, split_commitment_root_value
+    split_commitment_root_hash, split_commitment_root_value,
+    assets_meta.decimal_display
 FROM assets
 JOIN genesis_info_view
     ON assets.genesis_id = genesis_info_view.gen_asset_id AND
       (genesis_info_view.asset_id = sqlc.narg('asset_id_filter') OR
         sqlc.narg('asset_id_filter') IS NULL)
+LEFT JOIN assets_meta
+    ON genesis_info_view.meta_hash = assets_meta.meta_data_hash
 -- We use a LEFT JOIN here as not every asset has a group key, so this'll
 -- generate rows that have NULL values for the group key fields if an asset
 -- doesn't have a group key. See the comment in fetchAssetSprouts for a work
@@ -411,8 +463,46 @@ WHERE (
     assets.amount >= COALESCE(sqlc.narg('min_amt'), assets.amount) AND
     assets.spent = COALESCE(sqlc.narg('spent'), assets.spent) AND
     (key_group_info_view.tweaked_group_key = sqlc.narg('key_group_filter') OR
-      sqlc.narg('key_group_filter') IS NULL)
-);
+      sqlc.narg('key_group_filter') IS NULL) AND
+    -- If a time is given, we'll exclude all assets that are anchored in a UTXO
+    -- that is leased at that time.
+    (sqlc.narg('now') IS NULL OR utxos.lease_owner IS NULL OR
+      utxos.lease_expiry IS NULL OR utxos.lease_expiry <= sqlc.narg('now')) AND
+    -- Script keys without a tweak are BIP-0086 keys (type 1), all others
+    -- commit to a tapscript tree (type 2).
+    (sqlc.narg('script_key_type') IS NULL OR
+      (CASE WHEN COALESCE(length(script_keys.tweak), 0) = 0 THEN 1 ELSE 2 END) =
+        sqlc.narg('script_key_type')) AND
+    -- If a max anchor height is given, we'll only return assets of which the
+    -- anchor transaction was confirmed at or below that height.
+    (sqlc.narg('max_anchor_height') IS NULL OR
+      (txns.block_height > 0 AND
+        txns.block_height <= sqlc.narg('max_anchor_height'))) AND
+    -- If a cursor is given, we'll only return the assets that come after it
+    -- in the requested order. Assets are sorted by their amount if requested,
+    -- with their primary key breaking ties, or by their primary key otherwise.
+    (sqlc.narg('cursor_id') IS NULL OR
+      (@sort_descending = false AND (
+        (@sort_by_amount = true AND assets.amount > sqlc.narg('cursor_amount')) OR
+        ((@sort_by_amount = false OR assets.amount = sqlc.narg('cursor_amount')) AND
+          assets.asset_id > sqlc.narg('cursor_id'))
+      )) OR
+      (@sort_descending = true AND (
+        (@sort_by_amount = true AND assets.amount < sqlc.narg('cursor_amount')) OR
+        ((@sort_by_amount = false OR assets.amount = sqlc.narg('cursor_amount')) AND
+          assets.asset_id < sqlc.narg('cursor_id'))
+      )))
+)
+ORDER BY
+    CASE WHEN @sort_descending = false AND @sort_by_amount = true
+      THEN assets.amount END ASC,
+    CASE WHEN @sort_descending = false THEN assets.asset_id END ASC,
+    CASE WHEN @sort_descending = true AND @sort_by_amount = true
+      THEN assets.amount END DESC,
+    CASE WHEN @sort_descending = true THEN assets.asset_id END DESC
+-- Without a limit, all assets are returned. We use the int32 max value
+-- instead of NULL, as that works for both sqlite and Postgres.
+LIMIT COALESCE(sqlc.narg('num_limit'), 2147483647);
 
 -- name: AllAssets :many
 SELECT * 
@@ -445,6 +535,18 @@ UPDATE asset_minting_batches
 SET minting_tx_psbt = $2, change_output_index = $3, genesis_id = $4
 WHERE batch_id IN (SELECT batch_id FROM target_batch);
 
+-- name: BindMintingBatchWithTapSibling :exec
+WITH target_batch AS (
+    SELECT batch_id
+    FROM asset_minting_batches batches
+    JOIN internal_keys keys
+        ON batches.batch_id = keys.key_id
+    WHERE keys.raw_key = $1
+)
+UPDATE asset_minting_batches
+SET tapscript_sibling = $2
+WHERE batch_id IN (SELECT batch_id FROM target_batch);
+
 -- name: UpdateBatchGenesisTx :exec
 WITH target_batch AS (
     SELECT batch_id
@@ -484,13 +586,14 @@ WITH target_key(key_id) AS (
 )
 INSERT INTO managed_utxos (
     outpoint, amt_sats, internal_key_id, tapscript_sibling, merkle_root, txn_id,
-    taproot_asset_root
+    taproot_asset_root, alt_leaves
 ) VALUES (
-    $2, $3, (SELECT key_id FROM target_key), $4, $5, $6, $7
+    $2, $3, (SELECT key_id FROM target_key), $4, $5, $6, $7, $8
 ) ON CONFLICT (outpoint)
    -- Not a NOP but instead update any nullable fields that aren't null in the
    -- args.
-   DO UPDATE SET tapscript_sibling = COALESCE(EXCLUDED.tapscript_sibling, managed_utxos.tapscript_sibling)
+   DO UPDATE SET tapscript_sibling = COALESCE(EXCLUDED.tapscript_sibling, managed_utxos.tapscript_sibling),
+        alt_leaves = COALESCE(EXCLUDED.alt_leaves, managed_utxos.alt_leaves)
 RETURNING utxo_id;
 
 -- name: FetchManagedUTXO :one
@@ -676,6 +779,23 @@ WHERE (
 DELETE FROM managed_utxos
 WHERE outpoint = $1;
 
+-- name: UpdateUTXOLease :exec
+UPDATE managed_utxos
+SET lease_owner = @lease_owner, lease_expiry = @lease_expiry
+WHERE outpoint = @outpoint;
+
+-- name: DeleteUTXOLease :exec
+UPDATE managed_utxos
+SET lease_owner = NULL, lease_expiry = NULL
+WHERE outpoint = @outpoint;
+
+-- name: DeleteExpiredUTXOLeases :exec
+UPDATE managed_utxos
+SET lease_owner = NULL, lease_expiry = NULL
+WHERE lease_owner IS NOT NULL AND
+      lease_expiry IS NOT NULL AND
+      lease_expiry < @now;
+
 -- name: ConfirmChainAnchorTx :exec
 UPDATE chain_txns
 SET block_height = $2, block_hash = $3, tx_index = $4
@@ -704,6 +824,24 @@ JOIN internal_keys
   ON script_keys.internal_key_id = internal_keys.key_id
 WHERE script_keys.tweaked_script_key = $1;
 
+-- name: InsertScriptKeyLeaf :exec
+INSERT INTO script_key_leaves (
+    script_key_id, leaf_index, leaf_version, script
+) VALUES (
+    $1, $2, $3, $4
+) ON CONFLICT (script_key_id, leaf_index)
+    -- The leaves of a script key are fixed by its tweak, so there's nothing
+    -- to update.
+    DO NOTHING;
+
+-- name: FetchScriptKeyLeaves :many
+SELECT leaf_version, script
+FROM script_key_leaves
+JOIN script_keys
+  ON script_key_leaves.script_key_id = script_keys.script_key_id
+WHERE script_keys.tweaked_script_key = $1
+ORDER BY leaf_index;
+
 -- name: FetchGenesisByAssetID :one
 SELECT * 
 FROM genesis_info_view
@@ -711,15 +849,16 @@ WHERE asset_id = $1;
 
 -- name: UpsertAssetMeta :one
 INSERT INTO assets_meta (
-    meta_data_hash, meta_data_blob, meta_data_type
+    meta_data_hash, meta_data_blob, meta_data_type, decimal_display
 ) VALUES (
-    $1, $2, $3 
+    $1, $2, $3, $4
 ) ON CONFLICT (meta_data_hash)
     -- In this case, we may be inserting the data+type for an existing blob. So
-    -- we'll set both of those values. At this layer we assume the meta hash
+    -- we'll set all of those values. At this layer we assume the meta hash
     -- has been validated elsewhere.
     DO UPDATE SET meta_data_blob = EXCLUDED.meta_data_blob, 
-        meta_data_type = EXCLUDED.meta_data_type
+        meta_data_type = EXCLUDED.meta_data_type,
+        decimal_display = EXCLUDED.decimal_display
 RETURNING meta_id;
 
 -- name: FetchAssetMeta :one
@@ -738,3 +877,110 @@ FROM genesis_assets assets
 JOIN assets_meta
     ON assets.meta_data_id = assets_meta.meta_id
 WHERE assets.asset_id = $1;
+
+-- name: InsertBatchProofPushAttempt :exec
+WITH target_batch AS (
+    SELECT batch_id
+    FROM asset_minting_batches batches
+    JOIN internal_keys keys
+        ON batches.batch_id = keys.key_id
+    WHERE keys.raw_key = $1
+)
+INSERT INTO batch_proof_push_attempts (
+    batch_id, time_unix
+) VALUES (
+    (SELECT batch_id FROM target_batch), $2
+);
+
+-- name: QueryBatchProofPushAttempts :many
+SELECT time_unix
+FROM batch_proof_push_attempts attempts
+JOIN internal_keys keys
+    ON attempts.batch_id = keys.key_id
+WHERE keys.raw_key = $1
+ORDER BY time_unix DESC;
+
+-- Sprouts are only anchored once the genesis transaction of their batch is
+-- signed, so the sprouts of a cancelled batch were never anchored.
+-- name: DeleteBatchSprouts :execrows
+DELETE FROM assets
+WHERE anchor_utxo_id IS NULL AND genesis_id IN (
+    SELECT gen_asset_id
+    FROM genesis_assets
+    JOIN asset_minting_batches batches
+        ON genesis_assets.genesis_point_id = batches.genesis_id
+    WHERE batches.batch_state = @batch_state AND
+        batches.creation_time_unix < @created_before
+);
+
+-- name: DeleteBatchSeedlings :execrows
+DELETE FROM asset_seedlings
+WHERE batch_id IN (
+    SELECT batch_id
+    FROM asset_minting_batches
+    WHERE batch_state = @batch_state AND
+        creation_time_unix < @created_before
+);
+
+-- name: DeleteBatchProofPushAttempts :execrows
+DELETE FROM batch_proof_push_attempts
+WHERE batch_id IN (
+    SELECT batch_id
+    FROM asset_minting_batches
+    WHERE batch_state = @batch_state AND
+        creation_time_unix < @created_before
+);
+
+-- name: DeleteMintingBatches :execrows
+DELETE FROM asset_minting_batches
+WHERE batch_state = @batch_state AND creation_time_unix < @created_before;
+
+-- name: FetchAnchorTxs :many
+SELECT *
+FROM chain_txns txns
+WHERE txns.block_hash IS NOT NULL AND
+    txns.block_height >= @min_height AND (
+    EXISTS (
+        SELECT 1
+        FROM asset_proofs proofs
+        JOIN assets
+            ON proofs.asset_id = assets.asset_id
+        JOIN managed_utxos utxos
+            ON assets.anchor_utxo_id = utxos.utxo_id
+        WHERE utxos.txn_id = txns.txn_id AND
+            proofs.invalidated = @invalidated
+    ) OR EXISTS (
+        SELECT 1
+        FROM asset_transfers transfers
+        WHERE transfers.anchor_txn_id = txns.txn_id AND
+            transfers.invalidated = @invalidated
+    )
+)
+ORDER BY txns.block_height;
+
+-- name: FetchAnchorTxProofs :many
+SELECT proofs.asset_id, proofs.proof_file, script_keys.tweaked_script_key
+FROM asset_proofs proofs
+JOIN assets
+    ON proofs.asset_id = assets.asset_id
+JOIN script_keys
+    ON assets.script_key_id = script_keys.script_key_id
+JOIN managed_utxos utxos
+    ON assets.anchor_utxo_id = utxos.utxo_id
+WHERE utxos.txn_id = $1;
+
+-- name: SetAnchorTxProofsInvalidated :exec
+UPDATE asset_proofs
+SET invalidated = @invalidated
+WHERE asset_id IN (
+    SELECT assets.asset_id
+    FROM assets
+    JOIN managed_utxos utxos
+        ON assets.anchor_utxo_id = utxos.utxo_id
+    WHERE utxos.txn_id = @txn_id
+);
+
+-- name: RebindAssetProof :exec
+UPDATE asset_proofs
+SET proof_file = @proof_file, invalidated = false
+WHERE asset_id = @asset_id;
@@ -48,6 +48,9 @@ WITH subtree_cte (
     INNER JOIN subtree_cte r ON r.l_hash_key=c.hash_key OR r.r_hash_key=c.hash_key
 ) SELECT * from subtree_cte WHERE depth < 3;
 
+-- name: DeleteAllNodes :execrows
+DELETE FROM mssmt_nodes WHERE namespace = $1;
+
 -- name: DeleteNode :execrows
 DELETE FROM mssmt_nodes WHERE hash_key = $1 AND namespace = $2; 
 
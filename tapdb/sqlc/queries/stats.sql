@@ -0,0 +1,83 @@
+-- name: QueryTableRowCounts :many
+SELECT 'macaroons' AS table_name, COUNT(*) AS num_rows FROM macaroons
+UNION ALL
+SELECT 'chain_txns', COUNT(*) FROM chain_txns
+UNION ALL
+SELECT 'genesis_points', COUNT(*) FROM genesis_points
+UNION ALL
+SELECT 'assets_meta', COUNT(*) FROM assets_meta
+UNION ALL
+SELECT 'genesis_assets', COUNT(*) FROM genesis_assets
+UNION ALL
+SELECT 'internal_keys', COUNT(*) FROM internal_keys
+UNION ALL
+SELECT 'asset_groups', COUNT(*) FROM asset_groups
+UNION ALL
+SELECT 'asset_group_sigs', COUNT(*) FROM asset_group_sigs
+UNION ALL
+SELECT 'managed_utxos', COUNT(*) FROM managed_utxos
+UNION ALL
+SELECT 'script_keys', COUNT(*) FROM script_keys
+UNION ALL
+SELECT 'assets', COUNT(*) FROM assets
+UNION ALL
+SELECT 'asset_witnesses', COUNT(*) FROM asset_witnesses
+UNION ALL
+SELECT 'asset_proofs', COUNT(*) FROM asset_proofs
+UNION ALL
+SELECT 'asset_minting_batches', COUNT(*) FROM asset_minting_batches
+UNION ALL
+SELECT 'asset_seedlings', COUNT(*) FROM asset_seedlings
+UNION ALL
+SELECT 'addrs', COUNT(*) FROM addrs
+UNION ALL
+SELECT 'mssmt_nodes', COUNT(*) FROM mssmt_nodes
+UNION ALL
+SELECT 'mssmt_roots', COUNT(*) FROM mssmt_roots
+UNION ALL
+SELECT 'asset_transfers', COUNT(*) FROM asset_transfers
+UNION ALL
+SELECT 'asset_transfer_inputs', COUNT(*) FROM asset_transfer_inputs
+UNION ALL
+SELECT 'asset_transfer_outputs', COUNT(*) FROM asset_transfer_outputs
+UNION ALL
+SELECT 'receiver_proof_transfer_attempts', COUNT(*) FROM receiver_proof_transfer_attempts
+UNION ALL
+SELECT 'passive_assets', COUNT(*) FROM passive_assets
+UNION ALL
+SELECT 'addr_events', COUNT(*) FROM addr_events
+UNION ALL
+SELECT 'addr_event_status_changes', COUNT(*) FROM addr_event_status_changes
+UNION ALL
+SELECT 'universe_roots', COUNT(*) FROM universe_roots
+UNION ALL
+SELECT 'universe_leaves', COUNT(*) FROM universe_leaves
+UNION ALL
+SELECT 'universe_leaf_provenance', COUNT(*) FROM universe_leaf_provenance
+UNION ALL
+SELECT 'universe_servers', COUNT(*) FROM universe_servers
+UNION ALL
+SELECT 'universe_events', COUNT(*) FROM universe_events
+UNION ALL
+SELECT 'batch_proof_push_attempts', COUNT(*) FROM batch_proof_push_attempts
+UNION ALL
+SELECT 'asset_burn_transfers', COUNT(*) FROM asset_burn_transfers
+UNION ALL
+SELECT 'script_key_leaves', COUNT(*) FROM script_key_leaves;
+
+-- name: QueryProofArchiveStats :one
+SELECT COUNT(*) AS num_proofs,
+       CAST(COALESCE(SUM(LENGTH(proof_file)), 0) AS BIGINT) AS total_size
+FROM asset_proofs;
+
+-- Branches are the only nodes without a value, compacted leaves are the only
+-- nodes with a key.
+
+-- name: QueryMssmtNodeStats :one
+SELECT COUNT(DISTINCT namespace) AS num_trees,
+       COUNT(CASE WHEN value IS NULL THEN 1 END) AS num_branches,
+       COUNT(
+           CASE WHEN value IS NOT NULL AND key IS NULL THEN 1 END
+       ) AS num_leaves,
+       COUNT(CASE WHEN key IS NOT NULL THEN 1 END) AS num_compacted_leaves
+FROM mssmt_nodes;
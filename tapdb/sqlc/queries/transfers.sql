@@ -22,9 +22,9 @@ INSERT INTO asset_transfer_outputs (
     transfer_id, anchor_utxo, script_key, script_key_local,
     amount, serialized_witnesses, split_commitment_root_hash,
     split_commitment_root_value, proof_suffix, num_passive_assets,
-    output_type
+    output_type, proof_delivery_status, proof_courier_addr
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
 );
 
 -- name: QueryAssetTransfers :many
@@ -42,7 +42,37 @@ WHERE (@unconf_only = false OR @unconf_only IS NULL OR
 -- based on the anchor_tx_hash, but only if it's specified.
 AND (txns.txid = sqlc.narg('anchor_tx_hash') OR
     sqlc.narg('anchor_tx_hash') IS NULL)
-ORDER BY transfer_time_unix;
+
+-- This clause selects only confirmed transfers that still have at least one
+-- output with a pending proof delivery, but only if the pending_proofs_only
+-- field is set.
+AND (@pending_proofs_only = false OR @pending_proofs_only IS NULL OR
+    (txns.block_hash IS NOT NULL AND EXISTS (
+        SELECT 1
+        FROM asset_transfer_outputs outputs
+        WHERE outputs.transfer_id = transfers.id
+            AND outputs.proof_delivery_status = 1
+    )))
+
+-- This clause selects only transfers that spent at least one input of the
+-- given asset ID, but only if it's specified.
+AND (sqlc.narg('asset_id_filter') IS NULL OR EXISTS (
+        SELECT 1
+        FROM asset_transfer_inputs inputs
+        WHERE inputs.transfer_id = transfers.id
+            AND inputs.asset_id = sqlc.narg('asset_id_filter')
+    ))
+
+-- If a cursor is given, we'll only return the transfers that come after it
+-- in the requested order. Transfers are sorted by their primary key, which
+-- is the order they were created in.
+AND (sqlc.narg('cursor_id') IS NULL OR
+    (@sort_descending = false AND transfers.id > sqlc.narg('cursor_id')) OR
+    (@sort_descending = true AND transfers.id < sqlc.narg('cursor_id')))
+ORDER BY
+    CASE WHEN @sort_descending = false THEN transfers.id END ASC,
+    CASE WHEN @sort_descending = true THEN transfers.id END DESC
+LIMIT COALESCE(sqlc.narg('num_limit'), 2147483647);
 
 -- name: FetchTransferInputs :many
 SELECT input_id, anchor_point, asset_id, script_key, amount
@@ -54,7 +84,7 @@ ORDER BY input_id;
 SELECT
     output_id, proof_suffix, amount, serialized_witnesses, script_key_local,
     split_commitment_root_hash, split_commitment_root_value, num_passive_assets,
-    output_type,
+    output_type, proof_delivery_status, proof_courier_addr,
     utxos.utxo_id AS anchor_utxo_id,
     utxos.outpoint AS anchor_outpoint,
     utxos.amt_sats AS anchor_value,
@@ -85,13 +115,14 @@ ORDER BY output_id;
 -- name: ApplyPendingOutput :one
 WITH spent_asset AS (
     SELECT genesis_id, version, asset_group_sig_id, script_version, lock_time,
-           relative_lock_time
+           relative_lock_time, non_divisible, edition
     FROM assets
     WHERE assets.asset_id = @spent_asset_id
 )
 INSERT INTO assets (
     genesis_id, version, asset_group_sig_id, script_version, lock_time,
-    relative_lock_time, script_key_id, anchor_utxo_id, amount,
+    relative_lock_time, non_divisible, edition, script_key_id, anchor_utxo_id,
+    amount,
     split_commitment_root_hash, split_commitment_root_value, spent
 ) VALUES (
     (SELECT genesis_id FROM spent_asset),
@@ -100,6 +131,8 @@ INSERT INTO assets (
     (SELECT script_version FROM spent_asset),
     (SELECT lock_time FROM spent_asset),
     (SELECT relative_lock_time FROM spent_asset),
+    (SELECT non_divisible FROM spent_asset),
+    (SELECT edition FROM spent_asset),
     @script_key_id, @anchor_utxo_id, @amount, @split_commitment_root_hash,
     @split_commitment_root_value, @spent
 )
@@ -159,3 +192,45 @@ FROM passive_assets as passive
     JOIN genesis_assets
         ON assets.genesis_id = genesis_assets.gen_asset_id
 WHERE passive.transfer_id = @transfer_id;
+
+-- name: InsertBurn :one
+INSERT INTO asset_burn_transfers (
+    transfer_id, asset_id, script_key, amount
+) VALUES (
+    @transfer_id, @asset_id, @script_key, @amount
+) RETURNING burn_id;
+
+-- name: QueryBurns :many
+SELECT
+    burns.asset_id, burns.script_key, burns.amount,
+    txns.txid AS anchor_txid
+FROM asset_burn_transfers burns
+JOIN asset_transfers transfers
+    ON burns.transfer_id = transfers.id
+JOIN chain_txns txns
+    ON transfers.anchor_txn_id = txns.txn_id
+-- We'll only return the burns of a given asset if the asset ID is
+-- specified.
+WHERE (burns.asset_id = sqlc.narg('asset_id') OR
+    sqlc.narg('asset_id') IS NULL)
+ORDER BY burns.burn_id;
+
+-- name: SetTransferOutputProofDeliveryStatus :exec
+WITH target_output(output_id) AS (
+    SELECT output_id
+    FROM asset_transfer_outputs outputs
+    JOIN managed_utxos utxos
+        ON outputs.anchor_utxo = utxos.utxo_id
+    JOIN script_keys
+        ON outputs.script_key = script_keys.script_key_id
+    WHERE utxos.outpoint = @serialized_anchor_outpoint
+        AND script_keys.tweaked_script_key = @script_key_bytes
+)
+UPDATE asset_transfer_outputs
+SET proof_delivery_status = @delivery_status
+WHERE output_id IN (SELECT output_id FROM target_output);
+
+-- name: SetAnchorTxTransfersInvalidated :exec
+UPDATE asset_transfers
+SET invalidated = @invalidated
+WHERE anchor_txn_id = @txn_id;
@@ -51,6 +51,14 @@ SELECT leaves.minting_point, leaves.script_key_bytes
 FROM universe_leaves leaves
 WHERE leaves.leaf_node_namespace = @namespace;
 
+-- name: FetchUniverseLeafNodes :many
+SELECT leaves.leaf_node_key, nodes.value, nodes.sum
+FROM universe_leaves leaves
+JOIN mssmt_nodes nodes
+    ON leaves.leaf_node_key = nodes.key AND
+        leaves.leaf_node_namespace = nodes.namespace
+WHERE leaves.leaf_node_namespace = @namespace;
+
 -- name: UniverseLeaves :many
 SELECT * FROM universe_leaves;
 
@@ -85,6 +93,28 @@ WHERE server_host = @target_server;
 -- name: ListUniverseServers :many
 SELECT * FROM universe_servers;
 
+-- name: FetchUniverseLeafID :one
+SELECT id
+FROM universe_leaves
+WHERE leaf_node_key = @leaf_node_key AND
+      leaf_node_namespace = @namespace;
+
+-- name: InsertLeafProvenance :exec
+INSERT INTO universe_leaf_provenance (
+    leaf_id, server_host, sync_time
+) VALUES (
+    @leaf_id, @server_host, @sync_time
+) ON CONFLICT (leaf_id) DO NOTHING;
+
+-- name: QueryLeafProvenance :many
+SELECT leaves.minting_point, leaves.script_key_bytes, prov.server_host,
+       prov.sync_time
+FROM universe_leaf_provenance prov
+JOIN universe_leaves leaves
+    ON prov.leaf_id = leaves.id
+WHERE leaves.leaf_node_namespace = @namespace
+ORDER BY prov.leaf_id;
+
 -- name: InsertNewSyncEvent :exec
 WITH root_asset_id AS (
     SELECT id
@@ -109,6 +139,18 @@ INSERT INTO universe_events (
     'NEW_PROOF', (SELECT id FROM root_asset_id), @event_time
 );
 
+-- name: InsertNewProofQueryEvent :exec
+WITH root_asset_id AS (
+    SELECT id
+    FROM universe_roots
+    WHERE asset_id = @asset_id
+)
+INSERT INTO universe_events (
+    event_type, universe_root_id, event_time
+) VALUES (
+    'PROOF_QUERY', (SELECT id FROM root_asset_id), @event_time
+);
+
 -- name: QueryUniverseStats :one
 WITH num_assets As (
     SELECT COUNT(*) AS num_assets
@@ -116,7 +158,14 @@ WITH num_assets As (
 )
 SELECT COALESCE(SUM(universe_stats.total_asset_syncs), 0) AS total_syncs,
        COALESCE(SUM(universe_stats.total_asset_proofs), 0) AS total_proofs,
-       COUNT(num_assets) AS total_num_assets
+       COALESCE(SUM(universe_stats.total_asset_queries), 0) AS total_queries,
+       COUNT(num_assets) AS total_num_assets,
+       (
+           SELECT COUNT(DISTINCT group_key)
+           FROM universe_roots
+           WHERE group_key IS NOT NULL
+       ) AS total_num_groups,
+       (SELECT COUNT(*) FROM universe_leaves) AS total_num_leaves
 FROM universe_stats, num_assets;
 
 -- TODO(roasbeef): use the universe id instead for the grouping? so namespace
@@ -145,7 +194,8 @@ WITH asset_supply AS (
 SELECT asset_info.supply AS asset_supply, asset_info.asset_name AS asset_name,
     asset_info.asset_type AS asset_type, asset_info.asset_id AS asset_id,
     universe_stats.total_asset_syncs AS total_syncs,
-    universe_stats.total_asset_proofs AS total_proofs
+    universe_stats.total_asset_proofs AS total_proofs,
+    universe_stats.total_asset_queries AS total_queries
 FROM asset_info
 JOIN universe_stats
     ON asset_info.asset_id = universe_stats.asset_id
@@ -161,5 +211,9 @@ ORDER BY
     CASE
         WHEN sqlc.narg('sort_by') = 'asset_type' THEN asset_info.asset_type
         ELSE NULL
-    END
+    END,
+    CASE
+        WHEN sqlc.narg('sort_by') = 'total_queries' THEN universe_stats.total_asset_queries
+        ELSE NULL
+    END DESC
 LIMIT @num_limit OFFSET @num_offset;
@@ -14,13 +14,14 @@ import (
 const applyPendingOutput = `-- name: ApplyPendingOutput :one
 WITH spent_asset AS (
     SELECT genesis_id, version, asset_group_sig_id, script_version, lock_time,
-           relative_lock_time
+           relative_lock_time, non_divisible, edition
     FROM assets
     WHERE assets.asset_id = $7
 )
 INSERT INTO assets (
     genesis_id, version, asset_group_sig_id, script_version, lock_time,
-    relative_lock_time, script_key_id, anchor_utxo_id, amount,
+    relative_lock_time, non_divisible, edition, script_key_id, anchor_utxo_id,
+    amount,
     split_commitment_root_hash, split_commitment_root_value, spent
 ) VALUES (
     (SELECT genesis_id FROM spent_asset),
@@ -29,6 +30,8 @@ INSERT INTO assets (
     (SELECT script_version FROM spent_asset),
     (SELECT lock_time FROM spent_asset),
     (SELECT relative_lock_time FROM spent_asset),
+    (SELECT non_divisible FROM spent_asset),
+    (SELECT edition FROM spent_asset),
     $1, $2, $3, $4,
     $5, $6
 )
@@ -118,7 +121,7 @@ const fetchTransferOutputs = `-- name: FetchTransferOutputs :many
 SELECT
     output_id, proof_suffix, amount, serialized_witnesses, script_key_local,
     split_commitment_root_hash, split_commitment_root_value, num_passive_assets,
-    output_type,
+    output_type, proof_delivery_status, proof_courier_addr,
     utxos.utxo_id AS anchor_utxo_id,
     utxos.outpoint AS anchor_outpoint,
     utxos.amt_sats AS anchor_value,
@@ -157,6 +160,8 @@ type FetchTransferOutputsRow struct {
 	SplitCommitmentRootValue sql.NullInt64
 	NumPassiveAssets         int32
 	OutputType               int16
+	ProofDeliveryStatus      int16
+	ProofCourierAddr         []byte
 	AnchorUtxoID             int32
 	AnchorOutpoint           []byte
 	AnchorValue              int64
@@ -193,6 +198,8 @@ func (q *Queries) FetchTransferOutputs(ctx context.Context, transferID int32) ([
 			&i.SplitCommitmentRootValue,
 			&i.NumPassiveAssets,
 			&i.OutputType,
+			&i.ProofDeliveryStatus,
+			&i.ProofCourierAddr,
 			&i.AnchorUtxoID,
 			&i.AnchorOutpoint,
 			&i.AnchorValue,
@@ -280,9 +287,9 @@ INSERT INTO asset_transfer_outputs (
     transfer_id, anchor_utxo, script_key, script_key_local,
     amount, serialized_witnesses, split_commitment_root_hash,
     split_commitment_root_value, proof_suffix, num_passive_assets,
-    output_type
+    output_type, proof_delivery_status, proof_courier_addr
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
 )
 `
 
@@ -298,6 +305,8 @@ type InsertAssetTransferOutputParams struct {
 	ProofSuffix              []byte
 	NumPassiveAssets         int32
 	OutputType               int16
+	ProofDeliveryStatus      int16
+	ProofCourierAddr         []byte
 }
 
 func (q *Queries) InsertAssetTransferOutput(ctx context.Context, arg InsertAssetTransferOutputParams) error {
@@ -313,10 +322,39 @@ func (q *Queries) InsertAssetTransferOutput(ctx context.Context, arg InsertAsset
 		arg.ProofSuffix,
 		arg.NumPassiveAssets,
 		arg.OutputType,
+		arg.ProofDeliveryStatus,
+		arg.ProofCourierAddr,
 	)
 	return err
 }
 
+const insertBurn = `-- name: InsertBurn :one
+INSERT INTO asset_burn_transfers (
+    transfer_id, asset_id, script_key, amount
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING burn_id
+`
+
+type InsertBurnParams struct {
+	TransferID int32
+	AssetID    []byte
+	ScriptKey  []byte
+	Amount     int64
+}
+
+func (q *Queries) InsertBurn(ctx context.Context, arg InsertBurnParams) (int32, error) {
+	row := q.db.QueryRowContext(ctx, insertBurn,
+		arg.TransferID,
+		arg.AssetID,
+		arg.ScriptKey,
+		arg.Amount,
+	)
+	var burn_id int32
+	err := row.Scan(&burn_id)
+	return burn_id, err
+}
+
 const insertPassiveAsset = `-- name: InsertPassiveAsset :exec
 WITH target_asset(asset_id) AS (
     SELECT assets.asset_id
@@ -392,12 +430,39 @@ WHERE ($1 = false OR $1 IS NULL OR
 
 AND (txns.txid = $2 OR
     $2 IS NULL)
-ORDER BY transfer_time_unix
+
+AND ($3 = false OR $3 IS NULL OR
+    (txns.block_hash IS NOT NULL AND EXISTS (
+        SELECT 1
+        FROM asset_transfer_outputs outputs
+        WHERE outputs.transfer_id = transfers.id
+            AND outputs.proof_delivery_status = 1
+    )))
+
+AND ($4 IS NULL OR EXISTS (
+        SELECT 1
+        FROM asset_transfer_inputs inputs
+        WHERE inputs.transfer_id = transfers.id
+            AND inputs.asset_id = $4
+    ))
+
+AND ($5 IS NULL OR
+    ($6 = false AND transfers.id > $5) OR
+    ($6 = true AND transfers.id < $5))
+ORDER BY
+    CASE WHEN $6 = false THEN transfers.id END ASC,
+    CASE WHEN $6 = true THEN transfers.id END DESC
+LIMIT COALESCE($7, 2147483647)
 `
 
 type QueryAssetTransfersParams struct {
-	UnconfOnly   interface{}
-	AnchorTxHash []byte
+	UnconfOnly        interface{}
+	AnchorTxHash      []byte
+	PendingProofsOnly interface{}
+	AssetIDFilter     []byte
+	CursorID          sql.NullInt32
+	SortDescending    bool
+	NumLimit          sql.NullInt32
 }
 
 type QueryAssetTransfersRow struct {
@@ -411,8 +476,19 @@ type QueryAssetTransfersRow struct {
 // unconfirmed. But only if the unconf_only field is set.
 // Here we have another optional query clause to select a given transfer
 // based on the anchor_tx_hash, but only if it's specified.
+// This clause selects only confirmed transfers that still have at least one
+// output with a pending proof delivery, but only if the pending_proofs_only
+// field is set.
 func (q *Queries) QueryAssetTransfers(ctx context.Context, arg QueryAssetTransfersParams) ([]QueryAssetTransfersRow, error) {
-	rows, err := q.db.QueryContext(ctx, queryAssetTransfers, arg.UnconfOnly, arg.AnchorTxHash)
+	rows, err := q.db.QueryContext(ctx, queryAssetTransfers,
+		arg.UnconfOnly,
+		arg.AnchorTxHash,
+		arg.PendingProofsOnly,
+		arg.AssetIDFilter,
+		arg.CursorID,
+		arg.SortDescending,
+		arg.NumLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -439,6 +515,57 @@ func (q *Queries) QueryAssetTransfers(ctx context.Context, arg QueryAssetTransfe
 	return items, nil
 }
 
+const queryBurns = `-- name: QueryBurns :many
+SELECT
+    burns.asset_id, burns.script_key, burns.amount,
+    txns.txid AS anchor_txid
+FROM asset_burn_transfers burns
+JOIN asset_transfers transfers
+    ON burns.transfer_id = transfers.id
+JOIN chain_txns txns
+    ON transfers.anchor_txn_id = txns.txn_id
+WHERE (burns.asset_id = $1 OR
+    $1 IS NULL)
+ORDER BY burns.burn_id
+`
+
+type QueryBurnsRow struct {
+	AssetID    []byte
+	ScriptKey  []byte
+	Amount     int64
+	AnchorTxid []byte
+}
+
+// We'll only return the burns of a given asset if the asset ID is
+// specified.
+func (q *Queries) QueryBurns(ctx context.Context, assetID []byte) ([]QueryBurnsRow, error) {
+	rows, err := q.db.QueryContext(ctx, queryBurns, assetID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []QueryBurnsRow
+	for rows.Next() {
+		var i QueryBurnsRow
+		if err := rows.Scan(
+			&i.AssetID,
+			&i.ScriptKey,
+			&i.Amount,
+			&i.AnchorTxid,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const queryPassiveAssets = `-- name: QueryPassiveAssets :many
 SELECT passive.asset_id, passive.new_anchor_utxo, passive.script_key,
        passive.new_witness_stack, passive.new_proof,
@@ -535,3 +662,46 @@ func (q *Queries) ReAnchorPassiveAssets(ctx context.Context, arg ReAnchorPassive
 	_, err := q.db.ExecContext(ctx, reAnchorPassiveAssets, arg.NewAnchorUtxoID, arg.AssetID)
 	return err
 }
+
+const setAnchorTxTransfersInvalidated = `-- name: SetAnchorTxTransfersInvalidated :exec
+UPDATE asset_transfers
+SET invalidated = $1
+WHERE anchor_txn_id = $2
+`
+
+type SetAnchorTxTransfersInvalidatedParams struct {
+	Invalidated bool
+	TxnID       int32
+}
+
+func (q *Queries) SetAnchorTxTransfersInvalidated(ctx context.Context, arg SetAnchorTxTransfersInvalidatedParams) error {
+	_, err := q.db.ExecContext(ctx, setAnchorTxTransfersInvalidated, arg.Invalidated, arg.TxnID)
+	return err
+}
+
+const setTransferOutputProofDeliveryStatus = `-- name: SetTransferOutputProofDeliveryStatus :exec
+WITH target_output(output_id) AS (
+    SELECT output_id
+    FROM asset_transfer_outputs outputs
+    JOIN managed_utxos utxos
+        ON outputs.anchor_utxo = utxos.utxo_id
+    JOIN script_keys
+        ON outputs.script_key = script_keys.script_key_id
+    WHERE utxos.outpoint = $1
+        AND script_keys.tweaked_script_key = $2
+)
+UPDATE asset_transfer_outputs
+SET proof_delivery_status = $3
+WHERE output_id IN (SELECT output_id FROM target_output)
+`
+
+type SetTransferOutputProofDeliveryStatusParams struct {
+	SerializedAnchorOutpoint []byte
+	ScriptKeyBytes           []byte
+	DeliveryStatus           int16
+}
+
+func (q *Queries) SetTransferOutputProofDeliveryStatus(ctx context.Context, arg SetTransferOutputProofDeliveryStatusParams) error {
+	_, err := q.db.ExecContext(ctx, setTransferOutputProofDeliveryStatus, arg.SerializedAnchorOutpoint, arg.ScriptKeyBytes, arg.DeliveryStatus)
+	return err
+}
@@ -60,6 +60,63 @@ func (q *Queries) FetchUniverseKeys(ctx context.Context, namespace string) ([]Fe
 	return items, nil
 }
 
+const fetchUniverseLeafID = `-- name: FetchUniverseLeafID :one
+SELECT id
+FROM universe_leaves
+WHERE leaf_node_key = $1 AND
+      leaf_node_namespace = $2
+`
+
+type FetchUniverseLeafIDParams struct {
+	LeafNodeKey []byte
+	Namespace   string
+}
+
+func (q *Queries) FetchUniverseLeafID(ctx context.Context, arg FetchUniverseLeafIDParams) (int32, error) {
+	row := q.db.QueryRowContext(ctx, fetchUniverseLeafID, arg.LeafNodeKey, arg.Namespace)
+	var id int32
+	err := row.Scan(&id)
+	return id, err
+}
+
+const fetchUniverseLeafNodes = `-- name: FetchUniverseLeafNodes :many
+SELECT leaves.leaf_node_key, nodes.value, nodes.sum
+FROM universe_leaves leaves
+JOIN mssmt_nodes nodes
+    ON leaves.leaf_node_key = nodes.key AND
+        leaves.leaf_node_namespace = nodes.namespace
+WHERE leaves.leaf_node_namespace = $1
+`
+
+type FetchUniverseLeafNodesRow struct {
+	LeafNodeKey []byte
+	Value       []byte
+	Sum         int64
+}
+
+func (q *Queries) FetchUniverseLeafNodes(ctx context.Context, namespace string) ([]FetchUniverseLeafNodesRow, error) {
+	rows, err := q.db.QueryContext(ctx, fetchUniverseLeafNodes, namespace)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FetchUniverseLeafNodesRow
+	for rows.Next() {
+		var i FetchUniverseLeafNodesRow
+		if err := rows.Scan(&i.LeafNodeKey, &i.Value, &i.Sum); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const fetchUniverseRoot = `-- name: FetchUniverseRoot :one
 SELECT universe_roots.asset_id, group_key, mssmt_nodes.hash_key root_hash, 
        mssmt_nodes.sum root_sum, genesis_assets.asset_tag asset_name
@@ -95,6 +152,25 @@ func (q *Queries) FetchUniverseRoot(ctx context.Context, namespace string) (Fetc
 	return i, err
 }
 
+const insertLeafProvenance = `-- name: InsertLeafProvenance :exec
+INSERT INTO universe_leaf_provenance (
+    leaf_id, server_host, sync_time
+) VALUES (
+    $1, $2, $3
+) ON CONFLICT (leaf_id) DO NOTHING
+`
+
+type InsertLeafProvenanceParams struct {
+	LeafID     int32
+	ServerHost string
+	SyncTime   time.Time
+}
+
+func (q *Queries) InsertLeafProvenance(ctx context.Context, arg InsertLeafProvenanceParams) error {
+	_, err := q.db.ExecContext(ctx, insertLeafProvenance, arg.LeafID, arg.ServerHost, arg.SyncTime)
+	return err
+}
+
 const insertNewProofEvent = `-- name: InsertNewProofEvent :exec
 WITH root_asset_id AS (
     SELECT id
@@ -118,6 +194,29 @@ func (q *Queries) InsertNewProofEvent(ctx context.Context, arg InsertNewProofEve
 	return err
 }
 
+const insertNewProofQueryEvent = `-- name: InsertNewProofQueryEvent :exec
+WITH root_asset_id AS (
+    SELECT id
+    FROM universe_roots
+    WHERE asset_id = $2
+)
+INSERT INTO universe_events (
+    event_type, universe_root_id, event_time
+) VALUES (
+    'PROOF_QUERY', (SELECT id FROM root_asset_id), $1
+)
+`
+
+type InsertNewProofQueryEventParams struct {
+	EventTime time.Time
+	AssetID   []byte
+}
+
+func (q *Queries) InsertNewProofQueryEvent(ctx context.Context, arg InsertNewProofQueryEventParams) error {
+	_, err := q.db.ExecContext(ctx, insertNewProofQueryEvent, arg.EventTime, arg.AssetID)
+	return err
+}
+
 const insertNewSyncEvent = `-- name: InsertNewSyncEvent :exec
 WITH root_asset_id AS (
     SELECT id
@@ -233,6 +332,51 @@ func (q *Queries) LogServerSync(ctx context.Context, arg LogServerSyncParams) er
 	return err
 }
 
+const queryLeafProvenance = `-- name: QueryLeafProvenance :many
+SELECT leaves.minting_point, leaves.script_key_bytes, prov.server_host,
+       prov.sync_time
+FROM universe_leaf_provenance prov
+JOIN universe_leaves leaves
+    ON prov.leaf_id = leaves.id
+WHERE leaves.leaf_node_namespace = $1
+ORDER BY prov.leaf_id
+`
+
+type QueryLeafProvenanceRow struct {
+	MintingPoint   []byte
+	ScriptKeyBytes []byte
+	ServerHost     string
+	SyncTime       time.Time
+}
+
+func (q *Queries) QueryLeafProvenance(ctx context.Context, namespace string) ([]QueryLeafProvenanceRow, error) {
+	rows, err := q.db.QueryContext(ctx, queryLeafProvenance, namespace)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []QueryLeafProvenanceRow
+	for rows.Next() {
+		var i QueryLeafProvenanceRow
+		if err := rows.Scan(
+			&i.MintingPoint,
+			&i.ScriptKeyBytes,
+			&i.ServerHost,
+			&i.SyncTime,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const queryUniverseAssetStats = `-- name: QueryUniverseAssetStats :many
 
 WITH asset_supply AS (
@@ -257,7 +401,8 @@ WITH asset_supply AS (
 SELECT asset_info.supply AS asset_supply, asset_info.asset_name AS asset_name,
     asset_info.asset_type AS asset_type, asset_info.asset_id AS asset_id,
     universe_stats.total_asset_syncs AS total_syncs,
-    universe_stats.total_asset_proofs AS total_proofs
+    universe_stats.total_asset_proofs AS total_proofs,
+    universe_stats.total_asset_queries AS total_queries
 FROM asset_info
 JOIN universe_stats
     ON asset_info.asset_id = universe_stats.asset_id
@@ -273,7 +418,11 @@ ORDER BY
     CASE
         WHEN $1 = 'asset_type' THEN asset_info.asset_type
         ELSE NULL
-    END
+    END,
+    CASE
+        WHEN $1 = 'total_queries' THEN universe_stats.total_asset_queries
+        ELSE NULL
+    END DESC
 LIMIT $3 OFFSET $2
 `
 
@@ -287,12 +436,13 @@ type QueryUniverseAssetStatsParams struct {
 }
 
 type QueryUniverseAssetStatsRow struct {
-	AssetSupply int64
-	AssetName   string
-	AssetType   int16
-	AssetID     []byte
-	TotalSyncs  int64
-	TotalProofs int64
+	AssetSupply  int64
+	AssetName    string
+	AssetType    int16
+	AssetID      []byte
+	TotalSyncs   int64
+	TotalProofs  int64
+	TotalQueries int64
 }
 
 // TODO(roasbeef): use the universe id instead for the grouping? so namespace
@@ -320,6 +470,7 @@ func (q *Queries) QueryUniverseAssetStats(ctx context.Context, arg QueryUniverse
 			&i.AssetID,
 			&i.TotalSyncs,
 			&i.TotalProofs,
+			&i.TotalQueries,
 		); err != nil {
 			return nil, err
 		}
@@ -400,20 +551,37 @@ WITH num_assets As (
 )
 SELECT COALESCE(SUM(universe_stats.total_asset_syncs), 0) AS total_syncs,
        COALESCE(SUM(universe_stats.total_asset_proofs), 0) AS total_proofs,
-       COUNT(num_assets) AS total_num_assets
+       COALESCE(SUM(universe_stats.total_asset_queries), 0) AS total_queries,
+       COUNT(num_assets) AS total_num_assets,
+       (
+           SELECT COUNT(DISTINCT group_key)
+           FROM universe_roots
+           WHERE group_key IS NOT NULL
+       ) AS total_num_groups,
+       (SELECT COUNT(*) FROM universe_leaves) AS total_num_leaves
 FROM universe_stats, num_assets
 `
 
 type QueryUniverseStatsRow struct {
 	TotalSyncs     interface{}
 	TotalProofs    interface{}
+	TotalQueries   interface{}
 	TotalNumAssets int64
+	TotalNumGroups int64
+	TotalNumLeaves int64
 }
 
 func (q *Queries) QueryUniverseStats(ctx context.Context) (QueryUniverseStatsRow, error) {
 	row := q.db.QueryRowContext(ctx, queryUniverseStats)
 	var i QueryUniverseStatsRow
-	err := row.Scan(&i.TotalSyncs, &i.TotalProofs, &i.TotalNumAssets)
+	err := row.Scan(
+		&i.TotalSyncs,
+		&i.TotalProofs,
+		&i.TotalQueries,
+		&i.TotalNumAssets,
+		&i.TotalNumGroups,
+		&i.TotalNumLeaves,
+	)
 	return i, err
 }
 
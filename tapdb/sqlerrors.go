@@ -40,6 +40,16 @@ func parseSqliteError(sqliteErr *sqlite.Error) error {
 			DbError: sqliteErr,
 		}
 
+	// Handle the database being locked by a concurrent transaction. The
+	// busy timeout only covers some of the cases in which SQLite reports
+	// the database as busy, the remaining ones are retried by the caller.
+	case sqlite3.SQLITE_BUSY, sqlite3.SQLITE_BUSY_RECOVERY,
+		sqlite3.SQLITE_BUSY_SNAPSHOT:
+
+		return &ErrSerializationError{
+			DbError: sqliteErr,
+		}
+
 	default:
 		return fmt.Errorf("unknown sqlite error: %w", sqliteErr)
 	}
@@ -55,6 +65,12 @@ func parsePostgresError(pqErr *pgconn.PgError) error {
 			DbError: pqErr,
 		}
 
+	// Handle conflicts with concurrent transactions.
+	case pgerrcode.SerializationFailure, pgerrcode.DeadlockDetected:
+		return &ErrSerializationError{
+			DbError: pqErr,
+		}
+
 	default:
 		return fmt.Errorf("unknown postgres error: %w", pqErr)
 	}
@@ -69,3 +85,26 @@ type ErrSqlUniqueConstraintViolation struct {
 func (e ErrSqlUniqueConstraintViolation) Error() string {
 	return fmt.Sprintf("sql unique constraint violation: %v", e.DbError)
 }
+
+// ErrSerializationError is an error type which represents a database agnostic
+// failure to serialize a transaction with concurrent ones. Transactions that
+// fail with this error can be retried.
+type ErrSerializationError struct {
+	DbError error
+}
+
+// Unwrap returns the wrapped database specific error.
+func (e ErrSerializationError) Unwrap() error {
+	return e.DbError
+}
+
+func (e ErrSerializationError) Error() string {
+	return fmt.Sprintf("sql serialization error: %v", e.DbError)
+}
+
+// IsSerializationError returns true if the given error is a serialization
+// error, which means the transaction that caused it can be retried.
+func IsSerializationError(err error) bool {
+	var serializationErr *ErrSerializationError
+	return errors.As(err, &serializationErr)
+}
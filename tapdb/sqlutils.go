@@ -30,6 +30,18 @@ func sqlInt32[T constraints.Integer](num T) sql.NullInt32 {
 	}
 }
 
+// sqlInt64 turns a numerical integer type into the NullInt64 that sql/sqlc
+// uses when an integer field can be permitted to be NULL.
+//
+// We use this constraints.Integer constraint here which maps to all signed and
+// unsigned integer types.
+func sqlInt64[T constraints.Integer](num T) sql.NullInt64 {
+	return sql.NullInt64{
+		Int64: int64(num),
+		Valid: true,
+	}
+}
+
 // sqlInt16 turns a numerical integer type into the NullInt16 that sql/sqlc
 // uses when an integer field can be permitted to be NULL.
 //
@@ -0,0 +1,130 @@
+package tapdb
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	// migrationVersionQuery is the query we use to fetch the version of the
+	// last migration that was applied to the database. The migrations
+	// table isn't part of our schema, as it is created by the migration
+	// library, so this query can't be generated by sqlc. Both the SQLite
+	// and the Postgres driver of the library use the same default table
+	// name, which we never override.
+	migrationVersionQuery = "SELECT version, dirty FROM schema_migrations"
+)
+
+// TableStats holds the statistics of a single database table.
+type TableStats struct {
+	// Name is the name of the table.
+	Name string
+
+	// NumRows is the number of rows in the table.
+	NumRows int64
+}
+
+// DatabaseStats is a snapshot of the size of the database, which operators
+// can use to monitor its growth.
+type DatabaseStats struct {
+	// Tables holds the row count of each of our tables.
+	Tables []TableStats
+
+	// NumProofs is the number of proof files in the proof archive.
+	NumProofs int64
+
+	// ProofArchiveSize is the total size of all proof files in the proof
+	// archive in bytes.
+	ProofArchiveSize int64
+
+	// NumMssmtTrees is the number of MS-SMT trees with at least one node.
+	NumMssmtTrees int64
+
+	// NumMssmtBranches is the number of branch nodes of all MS-SMT trees.
+	NumMssmtBranches int64
+
+	// NumMssmtLeaves is the number of leaf nodes of all MS-SMT trees.
+	NumMssmtLeaves int64
+
+	// NumMssmtCompactedLeaves is the number of compacted leaf nodes of all
+	// MS-SMT trees.
+	NumMssmtCompactedLeaves int64
+
+	// MigrationVersion is the version of the last migration that was
+	// applied to the database.
+	MigrationVersion int64
+
+	// MigrationDirty is true if the last migration failed to be applied
+	// completely, in which case the database needs to be fixed manually.
+	MigrationDirty bool
+}
+
+// StatsReporter is a database that is able to report statistics about its
+// contents.
+type StatsReporter interface {
+	// Stats returns a snapshot of the size of the database.
+	Stats(ctx context.Context) (*DatabaseStats, error)
+}
+
+// Stats returns a snapshot of the size of the database. All statistics are
+// queried within a single transaction, so they're consistent with each other.
+//
+// NOTE: This is part of the StatsReporter interface.
+func (s *BaseDB) Stats(ctx context.Context) (*DatabaseStats, error) {
+	tx, err := s.BeginTx(ctx, ReadTxOption())
+	if err != nil {
+		return nil, err
+	}
+
+	// We never write within the transaction, so there's nothing to commit.
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var stats DatabaseStats
+	err = tx.QueryRowContext(ctx, migrationVersionQuery).Scan(
+		&stats.MigrationVersion, &stats.MigrationDirty,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query migration version: %w",
+			err)
+	}
+
+	q := s.Queries.WithTx(tx)
+
+	rowCounts, err := q.QueryTableRowCounts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query row counts: %w", err)
+	}
+	stats.Tables = make([]TableStats, len(rowCounts))
+	for idx, rowCount := range rowCounts {
+		stats.Tables[idx] = TableStats{
+			Name:    rowCount.TableName,
+			NumRows: rowCount.NumRows,
+		}
+	}
+
+	proofStats, err := q.QueryProofArchiveStats(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query proof archive stats: "+
+			"%w", err)
+	}
+	stats.NumProofs = proofStats.NumProofs
+	stats.ProofArchiveSize = proofStats.TotalSize
+
+	mssmtStats, err := q.QueryMssmtNodeStats(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query MS-SMT stats: %w", err)
+	}
+	stats.NumMssmtTrees = mssmtStats.NumTrees
+	stats.NumMssmtBranches = mssmtStats.NumBranches
+	stats.NumMssmtLeaves = mssmtStats.NumLeaves
+	stats.NumMssmtCompactedLeaves = mssmtStats.NumCompactedLeaves
+
+	return &stats, nil
+}
+
+// A compile-time assertion to ensure both database backends implement the
+// StatsReporter interface.
+var _ StatsReporter = (*SqliteStore)(nil)
+var _ StatsReporter = (*PostgresStore)(nil)
@@ -0,0 +1,84 @@
+package tapdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lightninglabs/taproot-assets/tapdb/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDatabaseStats tests that the database stats reflect the contents of the
+// database.
+func TestDatabaseStats(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	db := NewTestDB(t)
+
+	// The database is migrated to the latest version, which is the number
+	// of our migrations.
+	migrations, err := sqlSchemas.ReadDir("sqlc/migrations")
+	require.NoError(t, err)
+
+	stats, err := db.Stats(ctx)
+	require.NoError(t, err)
+	require.EqualValues(t, len(migrations), stats.MigrationVersion)
+	require.False(t, stats.MigrationDirty)
+
+	// A fresh database is empty.
+	require.NotEmpty(t, stats.Tables)
+	for _, table := range stats.Tables {
+		require.Zero(t, table.NumRows, table.Name)
+	}
+	require.Zero(t, stats.NumProofs)
+	require.Zero(t, stats.ProofArchiveSize)
+	require.Zero(t, stats.NumMssmtTrees)
+
+	// We now add a root key and nodes of each type to two different
+	// trees.
+	err = db.InsertRootKey(ctx, sqlc.InsertRootKeyParams{
+		ID:      []byte("id"),
+		RootKey: []byte("key"),
+	})
+	require.NoError(t, err)
+
+	for _, namespace := range []string{"tree 1", "tree 2"} {
+		err = db.InsertLeaf(ctx, sqlc.InsertLeafParams{
+			HashKey:   []byte("leaf"),
+			Value:     []byte("value"),
+			Namespace: namespace,
+		})
+		require.NoError(t, err)
+	}
+	err = db.InsertCompactedLeaf(ctx, sqlc.InsertCompactedLeafParams{
+		HashKey:   []byte("compacted leaf"),
+		Key:       []byte("key"),
+		Value:     []byte("value"),
+		Namespace: "tree 1",
+	})
+	require.NoError(t, err)
+	err = db.InsertBranch(ctx, sqlc.InsertBranchParams{
+		HashKey:   []byte("branch"),
+		LHashKey:  []byte("leaf"),
+		RHashKey:  []byte("compacted leaf"),
+		Namespace: "tree 1",
+	})
+	require.NoError(t, err)
+
+	stats, err = db.Stats(ctx)
+	require.NoError(t, err)
+
+	rowCounts := make(map[string]int64, len(stats.Tables))
+	for _, table := range stats.Tables {
+		rowCounts[table.Name] = table.NumRows
+	}
+	require.EqualValues(t, 1, rowCounts["macaroons"])
+	require.EqualValues(t, 4, rowCounts["mssmt_nodes"])
+	require.Zero(t, rowCounts["assets"])
+
+	require.EqualValues(t, 2, stats.NumMssmtTrees)
+	require.EqualValues(t, 1, stats.NumMssmtBranches)
+	require.EqualValues(t, 2, stats.NumMssmtLeaves)
+	require.EqualValues(t, 1, stats.NumMssmtCompactedLeaves)
+}
@@ -38,6 +38,9 @@ type (
 
 	// UniverseLeaf is a universe leaf.
 	UniverseLeaf = sqlc.QueryUniverseLeavesRow
+
+	// UniverseLeafNode is the MS-SMT leaf node of a universe leaf.
+	UniverseLeafNode = sqlc.FetchUniverseLeafNodesRow
 )
 
 var (
@@ -81,6 +84,14 @@ type BaseUniverseStore interface {
 	// for a given namespace.
 	FetchUniverseKeys(ctx context.Context,
 		namespace string) ([]UniverseKeys, error)
+
+	// FetchUniverseLeafNodes fetches the MS-SMT leaf nodes of all the
+	// universe leaves stored for a given namespace.
+	FetchUniverseLeafNodes(ctx context.Context,
+		namespace string) ([]UniverseLeafNode, error)
+
+	// DeleteAllNodes deletes all the MS-SMT nodes of a given namespace.
+	DeleteAllNodes(ctx context.Context, namespace string) (int64, error)
 }
 
 // BaseUniverseStoreOptions is the set of options for universe tree queries.
@@ -120,6 +131,10 @@ type BaseUniverseTree struct {
 	id universe.Identifier
 
 	smtNamespace string
+
+	nodeCache *TreeNodeCache
+
+	treeOpts []mssmt.TreeOption
 }
 
 // idToNameSpace maps a universe ID to a string namespace.
@@ -132,9 +147,13 @@ func idToNameSpace(id universe.Identifier) string {
 	return hex.EncodeToString(id.AssetID[:])
 }
 
-// NewBaseUniverseTree creates a new base Universe tree.
-func NewBaseUniverseTree(db BatchedUniverseTree,
-	id universe.Identifier) *BaseUniverseTree {
+// NewBaseUniverseTree creates a new base Universe tree. The optional node
+// cache, which can be shared between all universe trees, is used to serve
+// tree nodes without hitting the database. The tree options are applied to
+// all the MS-SMT instances of the universe tree.
+func NewBaseUniverseTree(db BatchedUniverseTree, id universe.Identifier,
+	nodeCache *TreeNodeCache,
+	treeOpts ...mssmt.TreeOption) *BaseUniverseTree {
 
 	namespace := idToNameSpace(id)
 
@@ -142,6 +161,8 @@ func NewBaseUniverseTree(db BatchedUniverseTree,
 		db:           db,
 		id:           id,
 		smtNamespace: namespace,
+		nodeCache:    nodeCache,
+		treeOpts:     treeOpts,
 	}
 }
 
@@ -182,15 +203,19 @@ func (b *BaseUniverseTree) RootNode(ctx context.Context) (mssmt.Node, string,
 type treeStoreWrapperTx struct {
 	universeTx BaseUniverseStore
 	namespace  string
+	nodeCache  *treeNodeCacheTx
 }
 
-// newTreeStoreWrapperTx makes a new wrapper tx.
-func newTreeStoreWrapperTx(universeTx BaseUniverseStore,
-	namespace string) *treeStoreWrapperTx {
+// newTreeStoreWrapperTx makes a new wrapper tx. The caller is responsible for
+// committing the node cache transaction once the outer database transaction
+// has been committed.
+func newTreeStoreWrapperTx(universeTx BaseUniverseStore, namespace string,
+	nodeCache *treeNodeCacheTx) *treeStoreWrapperTx {
 
 	return &treeStoreWrapperTx{
 		universeTx: universeTx,
 		namespace:  namespace,
+		nodeCache:  nodeCache,
 	}
 }
 
@@ -202,6 +227,7 @@ func (t *treeStoreWrapperTx) Update(ctx context.Context,
 		ctx:       ctx,
 		dbTx:      t.universeTx,
 		namespace: t.namespace,
+		nodeCache: t.nodeCache,
 	}
 
 	return update(updateTx)
@@ -215,6 +241,7 @@ func (t *treeStoreWrapperTx) View(ctx context.Context,
 		ctx:       ctx,
 		dbTx:      t.universeTx,
 		namespace: t.namespace,
+		nodeCache: t.nodeCache,
 	}
 
 	return view(viewTx)
@@ -287,12 +314,16 @@ func (b *BaseUniverseTree) RegisterIssuance(ctx context.Context,
 
 		leafInclusionProof *mssmt.Proof
 		universeRoot       mssmt.Node
+
+		cacheTx *treeNodeCacheTx
 	)
 	dbErr := b.db.ExecTx(ctx, &writeTx, func(db BaseUniverseStore) error {
 		// First, we'll instantiate a new compact tree instance from the
 		// backing tree store.
+		cacheTx = b.nodeCache.newTx(b.smtNamespace)
 		universeTree := mssmt.NewCompactedTree(
-			newTreeStoreWrapperTx(db, b.smtNamespace),
+			newTreeStoreWrapperTx(db, b.smtNamespace, cacheTx),
+			b.treeOpts...,
 		)
 
 		// Now that we have a tree instance linked to this DB
@@ -365,6 +396,10 @@ func (b *BaseUniverseTree) RegisterIssuance(ctx context.Context,
 		return nil, dbErr
 	}
 
+	// With the transaction committed, the nodes we touched can now be
+	// added to the node cache.
+	cacheTx.commit()
+
 	return &universe.IssuanceProof{
 		MintingKey:     key,
 		UniverseRoot:   universeRoot,
@@ -395,15 +430,22 @@ func (b *BaseUniverseTree) FetchIssuanceProof(ctx context.Context,
 		return nil, err
 	}
 
-	var proofs []*universe.IssuanceProof
+	var (
+		proofs  []*universe.IssuanceProof
+		cacheTx *treeNodeCacheTx
+	)
 
 	readTx := NewBaseUniverseReadTx()
 	dbErr := b.db.ExecTx(ctx, &readTx, func(db BaseUniverseStore) error {
+		proofs = nil
+
 		// First, we'll make a new instance of the universe tree, as
 		// we'll query it directly to obtain the set of leaves we care
 		// about.
+		cacheTx = b.nodeCache.newTx(b.smtNamespace)
 		universeTree := mssmt.NewCompactedTree(
-			newTreeStoreWrapperTx(db, b.smtNamespace),
+			newTreeStoreWrapperTx(db, b.smtNamespace, cacheTx),
+			b.treeOpts...,
 		)
 
 		// Each response will include a merkle proof of inclusion for
@@ -501,6 +543,8 @@ func (b *BaseUniverseTree) FetchIssuanceProof(ctx context.Context,
 		return nil, dbErr
 	}
 
+	cacheTx.commit()
+
 	return proofs, nil
 }
 
@@ -512,6 +556,8 @@ func (b *BaseUniverseTree) MintingKeys(ctx context.Context,
 
 	readTx := NewBaseUniverseReadTx()
 	dbErr := b.db.ExecTx(ctx, &readTx, func(db BaseUniverseStore) error {
+		baseKeys = nil
+
 		universeKeys, err := db.FetchUniverseKeys(ctx, b.smtNamespace)
 		if err != nil {
 			return err
@@ -558,6 +604,8 @@ func (b *BaseUniverseTree) MintingLeaves(ctx context.Context,
 
 	readTx := NewBaseUniverseReadTx()
 	dbErr := b.db.ExecTx(ctx, &readTx, func(db BaseUniverseStore) error {
+		leaves = nil
+
 		// First, we'll query the set of Universe leaves we have
 		// directly to determine which ones we care about. We only
 		// filter on the namespace here, as we want all the leaves for
@@ -608,4 +656,96 @@ func (b *BaseUniverseTree) MintingLeaves(ctx context.Context,
 	return leaves, nil
 }
 
+// TreeStats returns statistics about the nodes of the MS-SMT backing the
+// universe.
+func (b *BaseUniverseTree) TreeStats(
+	ctx context.Context) (*mssmt.TreeStats, error) {
+
+	var stats *mssmt.TreeStats
+
+	readTx := NewBaseUniverseReadTx()
+	dbErr := b.db.ExecTx(ctx, &readTx, func(db BaseUniverseStore) error {
+		// We'll walk the whole tree here, so we don't use the node
+		// cache, as that would evict the nodes that are actually used
+		// frequently.
+		universeTree := mssmt.NewCompactedTree(
+			newTreeStoreWrapperTx(db, b.smtNamespace, nil),
+			b.treeOpts...,
+		)
+
+		var err error
+		stats, err = universeTree.Stats(ctx)
+		return err
+	})
+	if dbErr != nil {
+		return nil, dbErr
+	}
+
+	return stats, nil
+}
+
+// RebuildTree rebuilds the MS-SMT backing the universe from the leaves stored
+// in the universe, replacing all its existing nodes. The leaves are inserted
+// as a single batch, which builds the new tree in parallel. The root of the
+// rebuilt tree is returned.
+func (b *BaseUniverseTree) RebuildTree(ctx context.Context) (mssmt.Node,
+	error) {
+
+	var (
+		writeTx      BaseUniverseStoreOptions
+		universeRoot mssmt.Node
+	)
+	dbErr := b.db.ExecTx(ctx, &writeTx, func(db BaseUniverseStore) error {
+		leafNodes, err := db.FetchUniverseLeafNodes(ctx, b.smtNamespace)
+		if err != nil {
+			return fmt.Errorf("unable to fetch leaf nodes: %w", err)
+		}
+
+		// Without any leaves, there's no tree to rebuild.
+		if len(leafNodes) == 0 {
+			universeRoot = mssmt.EmptyTree[0]
+			return nil
+		}
+
+		leaves := make(map[[32]byte]*mssmt.LeafNode, len(leafNodes))
+		for _, leafNode := range leafNodes {
+			var key [32]byte
+			copy(key[:], leafNode.LeafNodeKey)
+
+			leaves[key] = mssmt.NewLeafNode(
+				leafNode.Value, uint64(leafNode.Sum),
+			)
+		}
+
+		// The universe root references the namespace of the tree with
+		// a deferred constraint, so we can delete all the nodes of the
+		// tree before building it again from scratch.
+		_, err = db.DeleteAllNodes(ctx, b.smtNamespace)
+		if err != nil {
+			return fmt.Errorf("unable to delete tree nodes: %w",
+				err)
+		}
+
+		// We don't use the node cache here. Cached nodes are keyed by
+		// their hash, so they're still valid nodes of the namespace
+		// after the rebuild.
+		universeTree := mssmt.NewCompactedTree(
+			newTreeStoreWrapperTx(db, b.smtNamespace, nil),
+			b.treeOpts...,
+		)
+		_, err = universeTree.InsertMany(ctx, leaves)
+		if err != nil {
+			return fmt.Errorf("unable to insert leaves: %w", err)
+		}
+
+		universeRoot, err = universeTree.Root(ctx)
+		return err
+	})
+	if dbErr != nil {
+		return nil, dbErr
+	}
+
+	return universeRoot, nil
+}
+
 var _ universe.BaseBackend = (*BaseUniverseTree)(nil)
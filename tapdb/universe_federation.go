@@ -1,11 +1,15 @@
 package tapdb
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"time"
 
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightninglabs/taproot-assets/asset"
 	"github.com/lightninglabs/taproot-assets/chanutils"
 	"github.com/lightninglabs/taproot-assets/tapdb/sqlc"
 	"github.com/lightninglabs/taproot-assets/universe"
@@ -17,6 +21,16 @@ type (
 
 	// DelUniverseServer is used to delete a universe server.
 	DelUniverseServer = sqlc.DeleteUniverseServerParams
+
+	// UniverseLeafIDQuery is used to look up the primary key of a universe
+	// leaf.
+	UniverseLeafIDQuery = sqlc.FetchUniverseLeafIDParams
+
+	// NewLeafProvenance is used to record the origin of a synced leaf.
+	NewLeafProvenance = sqlc.InsertLeafProvenanceParams
+
+	// LeafProvenance is the origin of a synced leaf.
+	LeafProvenance = sqlc.QueryLeafProvenanceRow
 )
 
 // UniverseServerStore is used to managed the set of Universe servers as part
@@ -33,6 +47,20 @@ type UniverseServerStore interface {
 
 	// ListUniverseServers returns the total set of all universe servers.
 	ListUniverseServers(ctx context.Context) ([]sqlc.UniverseServer, error)
+
+	// FetchUniverseLeafID returns the primary key of the universe leaf
+	// stored at the given leaf key within the given namespace.
+	FetchUniverseLeafID(ctx context.Context,
+		arg UniverseLeafIDQuery) (int32, error)
+
+	// InsertLeafProvenance records the server a universe leaf was synced
+	// from, unless its origin is already known.
+	InsertLeafProvenance(ctx context.Context, arg NewLeafProvenance) error
+
+	// QueryLeafProvenance returns the origin of all the synced leaves of
+	// the universe with the given namespace.
+	QueryLeafProvenance(ctx context.Context,
+		namespace string) ([]LeafProvenance, error)
 }
 
 // UniverseFederationOptions is the database tx object for the universe server store.
@@ -173,4 +201,89 @@ func (u *UniverseFederationDB) LogNewSyncs(ctx context.Context,
 	})
 }
 
+// LogLeafProvenance records that the leaf stored at the given key of the
+// target universe was fetched from the given server. If the origin of the leaf
+// is already known, then it's left untouched.
+func (u *UniverseFederationDB) LogLeafProvenance(ctx context.Context,
+	addr universe.ServerAddr, id universe.Identifier,
+	key universe.BaseKey) error {
+
+	leafKey := key.UniverseKey()
+	namespace := idToNameSpace(id)
+
+	var writeTx UniverseFederationOptions
+	return u.db.ExecTx(ctx, &writeTx, func(db UniverseServerStore) error {
+		leafID, err := db.FetchUniverseLeafID(ctx, UniverseLeafIDQuery{
+			LeafNodeKey: leafKey[:],
+			Namespace:   namespace,
+		})
+		if err != nil {
+			return fmt.Errorf("unable to fetch universe leaf: %w",
+				err)
+		}
+
+		return db.InsertLeafProvenance(ctx, NewLeafProvenance{
+			LeafID:     leafID,
+			ServerHost: addr.HostStr(),
+			SyncTime:   time.Now().UTC(),
+		})
+	})
+}
+
+// LeafProvenance returns the origin of each leaf of the target universe that
+// was synced from a remote Universe server.
+func (u *UniverseFederationDB) LeafProvenance(ctx context.Context,
+	id universe.Identifier) ([]universe.LeafProvenance, error) {
+
+	var provenance []universe.LeafProvenance
+
+	readTx := NewUniverseFederationReadTx()
+	dbErr := u.db.ExecTx(ctx, &readTx, func(db UniverseServerStore) error {
+		provenance = nil
+
+		leaves, err := db.QueryLeafProvenance(ctx, idToNameSpace(id))
+		if err != nil {
+			return err
+		}
+
+		for _, leaf := range leaves {
+			scriptKeyPub, err := schnorr.ParsePubKey(
+				leaf.ScriptKeyBytes,
+			)
+			if err != nil {
+				return err
+			}
+			scriptKey := asset.NewScriptKey(scriptKeyPub)
+
+			var mintingPoint wire.OutPoint
+			err = readOutPoint(
+				bytes.NewReader(leaf.MintingPoint), 0, 0,
+				&mintingPoint,
+			)
+			if err != nil {
+				return err
+			}
+
+			provenance = append(provenance, universe.LeafProvenance{
+				Key: universe.BaseKey{
+					MintingOutpoint: mintingPoint,
+					ScriptKey:       &scriptKey,
+				},
+				Server: universe.NewServerAddrFromStr(
+					leaf.ServerHost,
+				),
+				SyncTime: leaf.SyncTime.UTC(),
+			})
+		}
+
+		return nil
+	})
+	if dbErr != nil {
+		return nil, dbErr
+	}
+
+	return provenance, nil
+}
+
 var _ universe.FederationLog = (*UniverseFederationDB)(nil)
+var _ universe.ProvenanceLog = (*UniverseFederationDB)(nil)
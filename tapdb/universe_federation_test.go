@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/lightninglabs/taproot-assets/tapdb/sqlc"
 	"github.com/lightninglabs/taproot-assets/universe"
@@ -90,3 +91,65 @@ func TestUniverseFederationCRUD(t *testing.T) {
 	err = fedDB.LogNewSyncs(ctx, addrToUpdate)
 	require.NoError(t, err)
 }
+
+// TestUniverseFederationLeafProvenance tests that we're able to record and
+// query the server each synced universe leaf was fetched from.
+func TestUniverseFederationLeafProvenance(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	db := NewTestDB(t)
+
+	fedDB := NewUniverseFederationDB(NewTransactionExecutor(db,
+		func(tx *sql.Tx) UniverseServerStore {
+			return db.WithTx(tx)
+		},
+	))
+
+	id := randUniverseID(t, false)
+	baseUniverse, _ := newTestUniverseWithDb(t, db.BaseDB, id)
+
+	// Without any synced leaves, there's no provenance to report.
+	provenance, err := fedDB.LeafProvenance(ctx, id)
+	require.NoError(t, err)
+	require.Empty(t, provenance)
+
+	// We'll now insert two leaves, only the first of which was synced
+	// from a remote server.
+	syncedLeaf, err := insertRandLeaf(t, ctx, baseUniverse, nil)
+	require.NoError(t, err)
+	_, err = insertRandLeaf(t, ctx, baseUniverse, nil)
+	require.NoError(t, err)
+
+	server1 := universe.NewServerAddrFromStr("localhost:10000")
+	err = fedDB.LogLeafProvenance(ctx, server1, id, syncedLeaf.MintingKey)
+	require.NoError(t, err)
+
+	// If the same leaf is synced again from another server, we keep the
+	// server it was first fetched from.
+	server2 := universe.NewServerAddrFromStr("localhost:10001")
+	err = fedDB.LogLeafProvenance(ctx, server2, id, syncedLeaf.MintingKey)
+	require.NoError(t, err)
+
+	provenance, err = fedDB.LeafProvenance(ctx, id)
+	require.NoError(t, err)
+	require.Len(t, provenance, 1)
+	require.Equal(
+		t, syncedLeaf.MintingKey.UniverseKey(),
+		provenance[0].Key.UniverseKey(),
+	)
+	require.Equal(t, server1.HostStr(), provenance[0].Server.HostStr())
+	require.WithinDuration(
+		t, time.Now(), provenance[0].SyncTime, time.Minute,
+	)
+
+	// The provenance is tracked per universe, so another universe doesn't
+	// report any synced leaves.
+	provenance, err = fedDB.LeafProvenance(ctx, randUniverseID(t, true))
+	require.NoError(t, err)
+	require.Empty(t, provenance)
+
+	// We can't log the provenance of a leaf we don't know of.
+	err = fedDB.LogLeafProvenance(ctx, server1, id, randBaseKey(t))
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
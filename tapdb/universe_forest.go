@@ -75,6 +75,8 @@ func (b *BaseUniverseForest) RootNodes(ctx context.Context) ([]universe.BaseRoot
 	readTx := NewBaseUniverseForestReadTx()
 
 	dbErr := b.db.ExecTx(ctx, &readTx, func(db BaseUniverseForestStore) error {
+		uniRoots = nil
+
 		dbRoots, err := db.UniverseRoots(ctx)
 		if err != nil {
 			return err
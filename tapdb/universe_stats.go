@@ -23,6 +23,10 @@ type (
 	// leaf sync.
 	NewSyncEvent = sqlc.InsertNewSyncEventParams
 
+	// NewProofQueryEvent is used to create a new event that logs a query
+	// of a Universe proof.
+	NewProofQueryEvent = sqlc.InsertNewProofQueryEventParams
+
 	// UniverseStatsQuery is used to query the stats for a given universe.
 	UniverseStatsQuery = sqlc.QueryUniverseAssetStatsParams
 
@@ -43,6 +47,11 @@ type UniverseStatsStore interface {
 	// InsertNewSyncEvent inserts a new sync event into the database.
 	InsertNewSyncEvent(ctx context.Context, arg NewSyncEvent) error
 
+	// InsertNewProofQueryEvent inserts a new proof query event into the
+	// database.
+	InsertNewProofQueryEvent(ctx context.Context,
+		arg NewProofQueryEvent) error
+
 	// QueryUniverseStats returns the aggregated stats for the entire
 	QueryUniverseStats(ctx context.Context) (AggregateStats, error)
 
@@ -120,6 +129,42 @@ func (u *UniverseStats) LogNewProofEvent(ctx context.Context,
 	})
 }
 
+// LogProofQueryEvent logs that the proof of the given leaf of the target
+// universe was queried.
+func (u *UniverseStats) LogProofQueryEvent(ctx context.Context,
+	uniID universe.Identifier, key universe.BaseKey) error {
+
+	var writeTxOpts UniverseStatsOptions
+	return u.db.ExecTx(ctx, &writeTxOpts, func(db UniverseStatsStore) error {
+		return db.InsertNewProofQueryEvent(ctx, NewProofQueryEvent{
+			// TODO(roasbeef): use clock interface
+			EventTime: time.Now(),
+			AssetID:   uniID.AssetID[:],
+		})
+	})
+}
+
+// parseEventSum parses the sum of a number of universe events. We'll need to
+// do a type cast here as sqlite will give us a NULL value as an int, while
+// postgres will give us a "0" string.
+func parseEventSum(sum interface{}) (uint64, error) {
+	switch sum := sum.(type) {
+	case int64:
+		return uint64(sum), nil
+
+	case string:
+		sumInt, err := strconv.ParseInt(sum, 10, 64)
+		if err != nil {
+			return 0, err
+		}
+
+		return uint64(sumInt), nil
+
+	default:
+		return 0, nil
+	}
+}
+
 // AggreagateSyncStats returns stats aggregated over all assets within the
 // Universe.
 func (u *UniverseStats) AggregateSyncStats(ctx context.Context,
@@ -135,36 +180,27 @@ func (u *UniverseStats) AggregateSyncStats(ctx context.Context,
 		}
 
 		stats.NumTotalAssets = uint64(uniStats.TotalNumAssets)
+		stats.NumTotalGroups = uint64(uniStats.TotalNumGroups)
+		stats.NumTotalLeaves = uint64(uniStats.TotalNumLeaves)
 
-		// We'll need to do a type cast here as sqlite will give us a
-		// NULL value as an int, while postgres will give us a "0"
-		// string.
-		switch numSyncs := uniStats.TotalSyncs.(type) {
-		case int64:
-			stats.NumTotalSyncs = uint64(numSyncs)
-
-		case string:
-			numSyncsInt, err := strconv.ParseInt(numSyncs, 10, 64)
-			if err != nil {
-				return fmt.Errorf("unable to parse total "+
-					"syncs: %v", err)
-			}
-
-			stats.NumTotalSyncs = uint64(numSyncsInt)
+		stats.NumTotalSyncs, err = parseEventSum(uniStats.TotalSyncs)
+		if err != nil {
+			return fmt.Errorf("unable to parse total syncs: %v",
+				err)
 		}
 
-		switch numProofs := uniStats.TotalProofs.(type) {
-		case int64:
-			stats.NumTotalProofs = uint64(numProofs)
-
-		case string:
-			numProofsInt, err := strconv.ParseInt(numProofs, 10, 64)
-			if err != nil {
-				return fmt.Errorf("unable to parse total "+
-					"proofs: %v", err)
-			}
+		stats.NumTotalProofs, err = parseEventSum(uniStats.TotalProofs)
+		if err != nil {
+			return fmt.Errorf("unable to parse total proofs: %v",
+				err)
+		}
 
-			stats.NumTotalProofs = uint64(numProofsInt)
+		stats.NumTotalQueries, err = parseEventSum(
+			uniStats.TotalQueries,
+		)
+		if err != nil {
+			return fmt.Errorf("unable to parse total queries: %v",
+				err)
 		}
 
 		return nil
@@ -189,6 +225,9 @@ func sortTypeToOrderBy(s universe.SyncStatsSort) string {
 	case universe.SortByAssetID:
 		return "asset_id"
 
+	case universe.SortByTotalQueries:
+		return "total_queries"
+
 	default:
 		return ""
 	}
@@ -207,6 +246,8 @@ func (u *UniverseStats) QuerySyncStats(ctx context.Context,
 
 	readTx := NewUniverseStatsReadTx()
 	err := u.db.ExecTx(ctx, &readTx, func(db UniverseStatsStore) error {
+		resp.SyncStats = nil
+
 		// First, we'll map the external query to our SQL specific
 		// struct. We'll need to use the proper null types so the query
 		// works as expected.
@@ -254,10 +295,11 @@ func (u *UniverseStats) QuerySyncStats(ctx context.Context,
 				AssetID: chanutils.ToArray[asset.ID](
 					assetStat.AssetID,
 				),
-				AssetName:   assetStat.AssetName,
-				AssetType:   asset.Type(assetStat.AssetType),
-				TotalSyncs:  uint64(assetStat.TotalSyncs),
-				TotalProofs: uint64(assetStat.TotalProofs),
+				AssetName:    assetStat.AssetName,
+				AssetType:    asset.Type(assetStat.AssetType),
+				TotalSyncs:   uint64(assetStat.TotalSyncs),
+				TotalProofs:  uint64(assetStat.TotalProofs),
+				TotalQueries: uint64(assetStat.TotalQueries),
 			}
 
 			resp.SyncStats = append(resp.SyncStats, stats)
@@ -91,6 +91,14 @@ func (u *uniStatsHarness) logSyncEventByIndex(i int) {
 	require.NoError(u.t, err)
 }
 
+func (u *uniStatsHarness) logProofQueryEventByIndex(i int) {
+	ctx := context.Background()
+	err := u.db.LogProofQueryEvent(
+		ctx, u.assetUniverses[i].id, u.universeLeaves[i].MintingKey,
+	)
+	require.NoError(u.t, err)
+}
+
 func (u *uniStatsHarness) assertUniverseStatsEqual(t *testing.T,
 	stats universe.AggregateStats) {
 
@@ -115,12 +123,23 @@ func TestUniverseStatsEvents(t *testing.T) {
 
 	sh := newUniStatsHarness(t, numAssets, db.BaseDB, statsDB)
 
-	// Before we insert anything into the DB, we should have all zeroes for
-	// the main set of stats.
+	// Some of the universes are randomly created with a group key, those
+	// count as asset groups.
+	var numGroups uint64
+	for _, assetUniverse := range sh.assetUniverses {
+		if assetUniverse.id.GroupKey != nil {
+			numGroups++
+		}
+	}
+
+	// Before we insert any events into the DB, we should have all zeroes
+	// for the main set of stats, except for the leaves inserted above.
 	sh.assertUniverseStatsEqual(t, universe.AggregateStats{
 		NumTotalAssets: 0,
 		NumTotalProofs: 0,
 		NumTotalSyncs:  0,
+		NumTotalGroups: numGroups,
+		NumTotalLeaves: numAssets,
 	})
 
 	// Now that we have our assets, we'll insert a new sync event for each
@@ -135,6 +154,8 @@ func TestUniverseStatsEvents(t *testing.T) {
 		NumTotalAssets: numAssets,
 		NumTotalProofs: numAssets,
 		NumTotalSyncs:  0,
+		NumTotalGroups: numGroups,
+		NumTotalLeaves: numAssets,
 	})
 
 	// Next, we'll simulate a new sync event for a random asset. If we
@@ -148,11 +169,26 @@ func TestUniverseStatsEvents(t *testing.T) {
 		NumTotalAssets: numAssets,
 		NumTotalProofs: numAssets,
 		NumTotalSyncs:  1,
+		NumTotalGroups: numGroups,
+		NumTotalLeaves: numAssets,
+	})
+
+	// Queries of the proof of an asset are counted separately from syncs.
+	sh.logProofQueryEventByIndex(assetToSync)
+	sh.logProofQueryEventByIndex(assetToSync)
+
+	sh.assertUniverseStatsEqual(t, universe.AggregateStats{
+		NumTotalAssets:  numAssets,
+		NumTotalProofs:  numAssets,
+		NumTotalSyncs:   1,
+		NumTotalQueries: 2,
+		NumTotalGroups:  numGroups,
+		NumTotalLeaves:  numAssets,
 	})
 
-	// We'll now query for the set of Universe events. There should be 4
-	// total events: 3 new proofs, and one sync event. Each event should
-	// match up with the set of items we inserted above.
+	// We'll now query for the set of Universe events. There should be 6
+	// total events: 3 new proofs, one sync event and two proof queries.
+	// Each event should match up with the set of items we inserted above.
 	syncStats, err := statsDB.QuerySyncStats(
 		ctx, universe.SyncStatsQuery{},
 	)
@@ -171,6 +207,7 @@ func TestUniverseStatsEvents(t *testing.T) {
 			leaf.MintingKey {
 
 			require.Equal(t, int(assetStat.TotalSyncs), 1)
+			require.Equal(t, int(assetStat.TotalQueries), 2)
 		}
 
 		require.Equal(t, int(assetStat.TotalProofs), 1)
@@ -190,8 +227,8 @@ func TestUniverseQuerySyncStatsSorting(t *testing.T) {
 
 	sh := newUniStatsHarness(t, numAssets, db.BaseDB, statsDB)
 
-	// Next, we'll log 2 proof events, and a random amount of syncs for
-	// each asset.
+	// Next, we'll log 2 proof events, and a random amount of syncs and
+	// proof queries for each asset.
 	for i := 0; i < numAssets; i++ {
 		sh.logProofEventByIndex(i)
 		sh.logProofEventByIndex(i)
@@ -200,6 +237,11 @@ func TestUniverseQuerySyncStatsSorting(t *testing.T) {
 		for j := 0; j < numSyncs; j++ {
 			sh.logSyncEventByIndex(i)
 		}
+
+		numQueries := rand.Int() % 10
+		for j := 0; j < numQueries; j++ {
+			sh.logProofQueryEventByIndex(i)
+		}
 	}
 
 	// sortCheck is used to generate an IsSorted func bound to the
@@ -247,6 +289,18 @@ func TestUniverseQuerySyncStatsSorting(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:     "total queries",
+			sortType: universe.SortByTotalQueries,
+			isSortedFunc: func(s []universe.AssetSyncSnapshot,
+			) func(i, j int) bool {
+
+				return func(i, j int) bool {
+					return s[i].TotalQueries >
+						s[j].TotalQueries
+				}
+			},
+		},
 	}
 	for _, testCase := range tests {
 		t.Run(testCase.name, func(t *testing.T) {
@@ -280,8 +334,8 @@ func TestUniverseQuerySyncFilters(t *testing.T) {
 
 	sh := newUniStatsHarness(t, numAssets, db.BaseDB, statsDB)
 
-	// Next, we'll log 2 proof events, and a random amount of syncs for
-	// each asset.
+	// Next, we'll log 2 proof events, and a random amount of syncs and
+	// proof queries for each asset.
 	for i := 0; i < numAssets; i++ {
 		sh.logProofEventByIndex(i)
 		sh.logProofEventByIndex(i)
@@ -290,6 +344,11 @@ func TestUniverseQuerySyncFilters(t *testing.T) {
 		for j := 0; j < numSyncs; j++ {
 			sh.logSyncEventByIndex(i)
 		}
+
+		numQueries := rand.Int() % 10
+		for j := 0; j < numQueries; j++ {
+			sh.logProofQueryEventByIndex(i)
+		}
 	}
 
 	// For each test case, we define a filter, then a function that can
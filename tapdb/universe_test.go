@@ -47,7 +47,9 @@ func newTestUniverse(t *testing.T,
 		},
 	)
 
-	return NewBaseUniverseTree(dbTxer, id), db
+	return NewBaseUniverseTree(
+		dbTxer, id, NewTreeNodeCache(DefaultTreeNodeCacheSize),
+	), db
 }
 
 func newTestUniverseWithDb(t *testing.T, db *BaseDB,
@@ -59,7 +61,9 @@ func newTestUniverseWithDb(t *testing.T, db *BaseDB,
 		},
 	)
 
-	return NewBaseUniverseTree(dbTxer, id), db
+	return NewBaseUniverseTree(
+		dbTxer, id, NewTreeNodeCache(DefaultTreeNodeCacheSize),
+	), db
 }
 
 // TestUniverseEmptyTree tests that an empty Universe tree returns the expected
@@ -229,6 +233,13 @@ func TestUniverseIssuanceProofs(t *testing.T) {
 		}
 		return false
 	}))
+
+	// The tree statistics should also reflect all the leaves we inserted.
+	stats, err := baseUniverse.TreeStats(ctx)
+	require.NoError(t, err)
+	require.EqualValues(t, numLeaves, stats.NumLeaves)
+	require.Equal(t, stats.NumLeaves, stats.NumCompactedLeaves)
+	require.Equal(t, leafSum, stats.TotalSum)
 }
 
 // TestUniverseMetaBlob tests that leaves inserted with a meta reveal can be
@@ -356,6 +367,115 @@ func TestUniverseTreeIsolation(t *testing.T) {
 	}))
 }
 
+// countTreeNodes returns the number of MS-SMT nodes stored in the namespace of
+// the given universe tree.
+func countTreeNodes(t *testing.T, ctx context.Context, db sqlc.Querier,
+	tree *BaseUniverseTree) int {
+
+	nodes, err := db.FetchAllNodes(ctx)
+	require.NoError(t, err)
+
+	var numNodes int
+	for _, node := range nodes {
+		if node.Namespace == tree.smtNamespace {
+			numNodes++
+		}
+	}
+
+	return numNodes
+}
+
+// TestUniverseTreeRebuild tests that rebuilding a universe tree from its
+// leaves results in the same tree, regardless of the build concurrency, and
+// doesn't affect other universe trees.
+func TestUniverseTreeRebuild(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	db := NewTestDB(t)
+
+	id := randUniverseID(t, false)
+	baseUniverse, _ := newTestUniverseWithDb(t, db.BaseDB, id)
+
+	otherUniverse, _ := newTestUniverseWithDb(
+		t, db.BaseDB, randUniverseID(t, true),
+	)
+	_, err := insertRandLeaf(t, ctx, otherUniverse, nil)
+	require.NoError(t, err)
+
+	otherRoot, _, err := otherUniverse.RootNode(ctx)
+	require.NoError(t, err)
+
+	// Rebuilding a universe without any leaves results in an empty tree.
+	emptyRoot, err := baseUniverse.RebuildTree(ctx)
+	require.NoError(t, err)
+	require.True(t, mssmt.IsEqualNode(mssmt.EmptyTree[0], emptyRoot))
+
+	const numLeaves = 50
+
+	assetGen := asset.RandGenesis(t, asset.Normal)
+	leafKeys := make([]universe.BaseKey, 0, numLeaves)
+	for i := 0; i < numLeaves; i++ {
+		issuanceProof, err := insertRandLeaf(
+			t, ctx, baseUniverse, &assetGen,
+		)
+		require.NoError(t, err)
+
+		leafKeys = append(leafKeys, issuanceProof.MintingKey)
+	}
+
+	rootNode, _, err := baseUniverse.RootNode(ctx)
+	require.NoError(t, err)
+
+	numNodes := countTreeNodes(t, ctx, db, baseUniverse)
+
+	// A node that isn't part of the tree should be removed by a rebuild.
+	err = db.InsertLeaf(ctx, NewLeaf{
+		HashKey:   test.RandBytes(32),
+		Value:     test.RandBytes(32),
+		Sum:       1,
+		Namespace: baseUniverse.smtNamespace,
+	})
+	require.NoError(t, err)
+	require.Equal(t, numNodes+1, countTreeNodes(t, ctx, db, baseUniverse))
+
+	for _, concurrency := range []int{1, 4} {
+		dbTxer := NewTransactionExecutor(db,
+			func(tx *sql.Tx) BaseUniverseStore {
+				return db.WithTx(tx)
+			},
+		)
+		rebuildUniverse := NewBaseUniverseTree(
+			dbTxer, id, NewTreeNodeCache(DefaultTreeNodeCacheSize),
+			mssmt.WithBuildConcurrency(concurrency),
+		)
+
+		newRoot, err := rebuildUniverse.RebuildTree(ctx)
+		require.NoError(t, err)
+		require.True(t, mssmt.IsEqualNode(rootNode, newRoot))
+		require.Equal(
+			t, numNodes, countTreeNodes(t, ctx, db, baseUniverse),
+		)
+
+		// All the leaves should still be provable against the root
+		// of the rebuilt tree.
+		for _, leafKey := range leafKeys {
+			proofs, err := baseUniverse.FetchIssuanceProof(
+				ctx, leafKey,
+			)
+			require.NoError(t, err)
+			require.Len(t, proofs, 1)
+			require.True(t, proofs[0].VerifyRoot(rootNode))
+		}
+	}
+
+	// The other universe tree must not have been touched.
+	newOtherRoot, _, err := otherUniverse.RootNode(ctx)
+	require.NoError(t, err)
+	require.True(t, mssmt.IsEqualNode(otherRoot, newOtherRoot))
+}
+
 // TestUniverseLeafQuery tests that we're able to properly query for the set of
 // leaves in a Universe based on either the outpoint or the script key.
 func TestUniverseLeafQuery(t *testing.T) {
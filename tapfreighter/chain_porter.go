@@ -5,21 +5,38 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/url"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
+	"github.com/lightninglabs/taproot-assets/address"
 	"github.com/lightninglabs/taproot-assets/asset"
 	"github.com/lightninglabs/taproot-assets/chanutils"
+	"github.com/lightninglabs/taproot-assets/commitment"
+	"github.com/lightninglabs/taproot-assets/monitoring"
 	"github.com/lightninglabs/taproot-assets/proof"
 	"github.com/lightninglabs/taproot-assets/tapgarden"
 	"github.com/lightninglabs/taproot-assets/tappsbt"
 	"github.com/lightninglabs/taproot-assets/tapscript"
 	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/ticker"
+)
+
+const (
+	// confWaitDeadline is the time after which the watchdog reports the
+	// porter as stalled if it's still waiting for a transfer transaction
+	// to confirm.
+	confWaitDeadline = 24 * time.Hour
 )
 
 // ChainPorterConfig is the main config for the chain porter.
@@ -59,9 +76,27 @@ type ChainPorterConfig struct {
 	// TODO(roasbeef): replace with proof.Courier in the future/
 	AssetProofs proof.Archiver
 
-	// ProofCourier is used to optionally deliver the final proof to the
-	// user using an asynchronous transport mechanism.
-	ProofCourier proof.Courier[proof.Recipient]
+	// ProofCourierDispatcher is used to optionally deliver the final proof
+	// to the receiver using an asynchronous transport mechanism. The
+	// dispatcher hands out a courier for the proof courier address of each
+	// output.
+	ProofCourierDispatcher proof.CourierDispatch
+
+	// DefaultProofCourierAddr is the address of the proof courier that is
+	// used for outputs that don't specify a proof courier address
+	// themselves.
+	DefaultProofCourierAddr *url.URL
+
+	// ProofRetryTicker is an optional ticker that periodically triggers a
+	// new delivery attempt for all proofs that couldn't be delivered to
+	// their receiver yet.
+	ProofRetryTicker ticker.Ticker
+
+	// BatchTicker is an optional ticker that enables batched shipping. If
+	// set, sends to addresses are queued and all parcels queued between
+	// two ticks are shipped in a single anchor transaction. Parcels with a
+	// max fee, burns and pre-signed parcels are never queued.
+	BatchTicker *ticker.Force
 
 	// ErrChan is the main error channel the custodian will report back
 	// critical errors to the main server.
@@ -89,9 +124,31 @@ type ChainPorter struct {
 	// subscriptionID.
 	subscriberMtx sync.Mutex
 
+	// deliveriesInFlight is the set of transfer outputs for which a proof
+	// delivery attempt is currently running.
+	deliveriesInFlight map[outputKey]struct{}
+
+	// deliveryMtx guards the deliveriesInFlight set.
+	deliveryMtx sync.Mutex
+
+	// pendingAnchorSigns are the packages that wait for their anchor
+	// transaction to be signed by an external wallet, keyed by the txid of
+	// the anchor transaction.
+	pendingAnchorSigns map[chainhash.Hash]*sendPackage
+
+	// anchorSignMtx guards the pendingAnchorSigns map.
+	anchorSignMtx sync.Mutex
+
 	*chanutils.ContextGuard
 }
 
+// outputKey uniquely identifies a transfer output by its anchor outpoint and
+// script key.
+type outputKey struct {
+	anchorPoint wire.OutPoint
+	scriptKey   asset.SerializedKey
+}
+
 // NewChainPorter creates a new instance of the ChainPorter given a valid
 // config.
 func NewChainPorter(cfg *ChainPorterConfig) *ChainPorter {
@@ -99,9 +156,11 @@ func NewChainPorter(cfg *ChainPorterConfig) *ChainPorter {
 		map[uint64]*chanutils.EventReceiver[chanutils.Event],
 	)
 	return &ChainPorter{
-		cfg:         cfg,
-		exportReqs:  make(chan Parcel),
-		subscribers: subscribers,
+		cfg:                cfg,
+		exportReqs:         make(chan Parcel),
+		subscribers:        subscribers,
+		deliveriesInFlight: make(map[outputKey]struct{}),
+		pendingAnchorSigns: make(map[chainhash.Hash]*sendPackage),
 		ContextGuard: &chanutils.ContextGuard{
 			DefaultTimeout: tapgarden.DefaultTimeout,
 			Quit:           make(chan struct{}),
@@ -139,6 +198,20 @@ func (p *ChainPorter) Start() error {
 			go p.resumePendingParcel(parcel)
 		}
 
+		// We'll also re-attempt the delivery of all proofs of
+		// confirmed parcels that didn't reach their receiver yet.
+		if p.cfg.ProofCourierDispatcher != nil {
+			p.Wg.Add(1)
+			go p.retryProofDeliveries()
+		}
+
+		if p.cfg.BatchTicker != nil {
+			p.cfg.BatchTicker.Resume()
+		}
+		if p.cfg.ProofRetryTicker != nil {
+			p.cfg.ProofRetryTicker.Resume()
+		}
+
 		p.Wg.Add(1)
 		go p.assetsPorter()
 	})
@@ -153,6 +226,13 @@ func (p *ChainPorter) Stop() error {
 		close(p.Quit)
 		p.Wg.Wait()
 
+		if p.cfg.BatchTicker != nil {
+			p.cfg.BatchTicker.Stop()
+		}
+		if p.cfg.ProofRetryTicker != nil {
+			p.cfg.ProofRetryTicker.Stop()
+		}
+
 		// Remove all subscribers.
 		for _, sub := range p.subscribers {
 			err := p.RemoveSubscriber(sub)
@@ -167,8 +247,27 @@ func (p *ChainPorter) Stop() error {
 }
 
 // RequestShipment is the main external entry point to the porter. This request
-// a new transfer take place.
-func (p *ChainPorter) RequestShipment(req Parcel) (*OutboundParcel, error) {
+// a new transfer take place. The given options control how the anchor
+// transaction of the transfer is funded.
+func (p *ChainPorter) RequestShipment(req Parcel,
+	optFuncs ...ShipmentOption) (*OutboundParcel, error) {
+
+	if err := req.validate(); err != nil {
+		return nil, fmt.Errorf("invalid parcel: %w", err)
+	}
+
+	opts := defaultShipmentOptions()
+	for _, optFunc := range optFuncs {
+		optFunc(opts)
+	}
+
+	// We validate the options before the parcel is handed to the porter,
+	// so an invalid request fails early.
+	if err := opts.validate(); err != nil {
+		return nil, fmt.Errorf("invalid shipment options: %w", err)
+	}
+	req.kit().opts = opts
+
 	if !chanutils.SendOrQuit(p.exportReqs, req, p.Quit) {
 		return nil, fmt.Errorf("ChainPorter shutting down")
 	}
@@ -185,6 +284,176 @@ func (p *ChainPorter) RequestShipment(req Parcel) (*OutboundParcel, error) {
 	}
 }
 
+// RequestAnchorPsbt requests a new transfer just like RequestShipment, but
+// instead of signing and broadcasting the anchor transaction, the funded
+// anchor PSBT is returned to be signed by an external wallet. The transfer is
+// resumed once the signed PSBT is handed back through PublishAnchorPsbt.
+//
+// NOTE: Transfers that wait for their anchor transaction to be signed are only
+// kept in memory, so they need to be requested again after a restart.
+func (p *ChainPorter) RequestAnchorPsbt(req Parcel,
+	optFuncs ...ShipmentOption) (*psbt.Packet, error) {
+
+	if err := req.validate(); err != nil {
+		return nil, fmt.Errorf("invalid parcel: %w", err)
+	}
+
+	opts := defaultShipmentOptions()
+	for _, optFunc := range optFuncs {
+		optFunc(opts)
+	}
+	opts.ExternalAnchorSign = true
+
+	if err := opts.validate(); err != nil {
+		return nil, fmt.Errorf("invalid shipment options: %w", err)
+	}
+	req.kit().opts = opts
+
+	if !chanutils.SendOrQuit(p.exportReqs, req, p.Quit) {
+		return nil, fmt.Errorf("ChainPorter shutting down")
+	}
+
+	select {
+	case err := <-req.kit().errChan:
+		return nil, err
+
+	case anchorPsbt := <-req.kit().anchorPsbtChan:
+		return anchorPsbt, nil
+
+	case <-p.Quit:
+		return nil, fmt.Errorf("ChainPorter shutting down")
+	}
+}
+
+// PublishAnchorPsbt resumes a transfer that was requested through
+// RequestAnchorPsbt with the given anchor PSBT that was signed by an external
+// wallet. The signed transaction is verified before the transfer is logged to
+// disk and broadcast. The outbound parcel is returned once it is broadcast.
+func (p *ChainPorter) PublishAnchorPsbt(
+	signedPsbt *psbt.Packet) (*OutboundParcel, error) {
+
+	txHash := signedPsbt.UnsignedTx.TxHash()
+
+	p.anchorSignMtx.Lock()
+	pkg, ok := p.pendingAnchorSigns[txHash]
+	delete(p.pendingAnchorSigns, txHash)
+	p.anchorSignMtx.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("no transfer waiting for anchor "+
+			"transaction %v", txHash)
+	}
+
+	finalTx, err := finalizeAnchorPsbt(signedPsbt)
+	if err != nil {
+		// The caller can try again with a correctly signed PSBT, so
+		// the transfer keeps waiting.
+		p.anchorSignMtx.Lock()
+		p.pendingAnchorSigns[txHash] = pkg
+		p.anchorSignMtx.Unlock()
+
+		return nil, fmt.Errorf("invalid signed anchor transaction: %w",
+			err)
+	}
+
+	pkg.logger().Infof("Received externally signed anchor transaction")
+
+	pkg.AnchorTx.FinalTx = finalTx
+	pkg.SendState = SendStateLogCommit
+
+	kit := pkg.Parcel.kit()
+
+	p.Wg.Add(1)
+	go func() {
+		defer p.Wg.Done()
+
+		if err := p.advanceState(pkg); err != nil {
+			pkg.logger().Warnf("Unable to advance state machine: "+
+				"%v", err)
+			kit.deliverErr(err)
+		}
+	}()
+
+	select {
+	case err := <-kit.errChan:
+		return nil, err
+
+	case resp := <-kit.respChan:
+		return resp, nil
+
+	case <-p.Quit:
+		return nil, fmt.Errorf("ChainPorter shutting down")
+	}
+}
+
+// exportAnchorPsbt hands out a copy of the funded anchor PSBT of the given
+// package to be signed externally and parks the package until the signed PSBT
+// is handed back.
+func (p *ChainPorter) exportAnchorPsbt(pkg *sendPackage) error {
+	anchorPsbt, err := copyPsbt(pkg.AnchorTx.FundedPsbt.Pkt)
+	if err != nil {
+		return fmt.Errorf("unable to copy anchor psbt: %w", err)
+	}
+
+	txHash := anchorPsbt.UnsignedTx.TxHash()
+	pkg.logger().Infof("Waiting for anchor transaction %v to be signed "+
+		"externally", txHash)
+
+	p.anchorSignMtx.Lock()
+	p.pendingAnchorSigns[txHash] = pkg
+	p.anchorSignMtx.Unlock()
+
+	pkg.Parcel.kit().deliverAnchorPsbt(anchorPsbt)
+
+	return nil
+}
+
+// finalizeAnchorPsbt finalizes and extracts the given signed anchor PSBT and
+// makes sure all its inputs are spent with a valid witness.
+func finalizeAnchorPsbt(signedPsbt *psbt.Packet) (*wire.MsgTx, error) {
+	if err := psbt.MaybeFinalizeAll(signedPsbt); err != nil {
+		return nil, fmt.Errorf("unable to finalize psbt: %w", err)
+	}
+
+	finalTx, err := psbt.Extract(signedPsbt)
+	if err != nil {
+		return nil, fmt.Errorf("unable to extract psbt: %w", err)
+	}
+
+	prevOuts := make(map[wire.OutPoint]*wire.TxOut, len(finalTx.TxIn))
+	for idx, txIn := range finalTx.TxIn {
+		utxo := signedPsbt.Inputs[idx].WitnessUtxo
+		if utxo == nil {
+			return nil, fmt.Errorf("input %d has no witness utxo",
+				idx)
+		}
+
+		prevOuts[txIn.PreviousOutPoint] = utxo
+	}
+
+	prevOutFetcher := txscript.NewMultiPrevOutFetcher(prevOuts)
+	sigHashes := txscript.NewTxSigHashes(finalTx, prevOutFetcher)
+	for idx, txIn := range finalTx.TxIn {
+		prevOut := prevOuts[txIn.PreviousOutPoint]
+		engine, err := txscript.NewEngine(
+			prevOut.PkScript, finalTx, idx,
+			txscript.StandardVerifyFlags, nil, sigHashes,
+			prevOut.Value, prevOutFetcher,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create script "+
+				"engine for input %d: %w", idx, err)
+		}
+
+		if err := engine.Execute(); err != nil {
+			return nil, fmt.Errorf("invalid witness for input "+
+				"%d: %w", idx, err)
+		}
+	}
+
+	return finalTx, nil
+}
+
 // resumePendingParcel attempts to resume a pending parcel. A pending parcel
 // has already had its transfer transaction broadcast. In this state, we'll
 // rebroadcast and then wait for the transfer to confirm.
@@ -194,62 +463,180 @@ func (p *ChainPorter) RequestShipment(req Parcel) (*OutboundParcel, error) {
 func (p *ChainPorter) resumePendingParcel(pkg *OutboundParcel) {
 	defer p.Wg.Done()
 
-	log.Infof("Attempting to resume delivery for anchor_txid=%v",
-		pkg.AnchorTx.TxHash().String())
-
 	// To resume the state machine, we'll make a skeleton of a sendPackage,
 	// basically just what we need to drive the state machine to further
 	// completion.
 	restartSendPkg := sendPackage{
-		OutboundPkg: pkg,
-		SendState:   SendStateBroadcast,
+		CorrelationID: newParcelCorrelationID(),
+		OutboundPkg:   pkg,
+		SendState:     SendStateBroadcast,
 	}
+	pkgLog := restartSendPkg.logger()
+
+	pkgLog.Infof("Attempting to resume delivery for anchor_txid=%v",
+		pkg.AnchorTx.TxHash().String())
 
 	err := p.advanceState(&restartSendPkg)
 	if err != nil {
 		// TODO(roasbef): no req to send the error back to here
-		log.Warnf("Unable to advance state machine: %v", err)
+		pkgLog.Warnf("Unable to advance state machine: %v", err)
 		return
 	}
 }
 
+// parcelBatchKey identifies the queued parcels that can be shipped together in
+// the same anchor transaction.
+type parcelBatchKey struct {
+	selectStrategy MultiCommitmentSelectStrategy
+	feeRate        chainfee.SatPerKWeight
+	confTarget     uint32
+}
+
+// batchKey returns the batch key of the given parcel and whether the parcel
+// can be queued for batched shipping at all. A max fee is a limit for a single
+// transfer and forcing the broadcast of stranded passive assets must not apply
+// to other transfers, so such parcels are always shipped on their own. The
+// same is true for a parcel that sends a specific edition of a collectible.
+func batchKey(req Parcel) (parcelBatchKey, bool) {
+	addrParcel, ok := req.(*AddressParcel)
+	if !ok {
+		return parcelBatchKey{}, false
+	}
+
+	opts := addrParcel.opts
+	if opts == nil {
+		opts = defaultShipmentOptions()
+	}
+	if opts.MaxFee != 0 || opts.ForceStrandPassiveAssets ||
+		opts.ExternalAnchorSign || opts.Edition != 0 {
+
+		return parcelBatchKey{}, false
+	}
+
+	key := parcelBatchKey{
+		selectStrategy: addrParcel.selectStrategy,
+		confTarget:     opts.ConfTarget,
+	}
+	if opts.FeeRate != nil {
+		key.feeRate = *opts.FeeRate
+	}
+
+	return key, true
+}
+
 // assetsPorter is the main goroutine of the ChainPorter. This takes in incoming
 // requests, and attempt to complete a transfer. A response is sent back to the
 // caller if a transfer can be completed. Otherwise, an error is returned.
 func (p *ChainPorter) assetsPorter() {
 	defer p.Wg.Done()
 
+	// If batched shipping is enabled, we queue the parcels by their batch
+	// key, remembering the order in which the keys were first seen.
+	var (
+		batchTicks   <-chan time.Time
+		retryTicks   <-chan time.Time
+		batchKeys    []parcelBatchKey
+		queuedByKeys = make(map[parcelBatchKey][]*AddressParcel)
+	)
+	if p.cfg.BatchTicker != nil {
+		batchTicks = p.cfg.BatchTicker.Ticks()
+	}
+	if p.cfg.ProofRetryTicker != nil &&
+		p.cfg.ProofCourierDispatcher != nil {
+
+		retryTicks = p.cfg.ProofRetryTicker.Ticks()
+	}
+
 	for {
 		select {
 		case req := <-p.exportReqs:
-			// The request either has a destination address we want
-			// to send to, or a send package is already initialized.
-			sendPkg := req.pkg()
-
-			// Advance the state machine for this package as far as
-			// possible.
-			err := p.advanceState(sendPkg)
-			if err != nil {
-				log.Warnf("Unable to advance state machine: %v",
-					err)
-				req.kit().errChan <- err
+			key, ok := batchKey(req)
+			if batchTicks == nil || !ok {
+				p.shipParcel(req)
 				continue
 			}
 
+			if _, ok := queuedByKeys[key]; !ok {
+				batchKeys = append(batchKeys, key)
+			}
+			queuedByKeys[key] = append(
+				queuedByKeys[key], req.(*AddressParcel),
+			)
+
+			log.Debugf("Queued parcel for batched shipping, %d "+
+				"parcels queued with the same options",
+				len(queuedByKeys[key]))
+
+		case <-batchTicks:
+			for _, key := range batchKeys {
+				p.shipBatch(queuedByKeys[key])
+			}
+
+			batchKeys = nil
+			queuedByKeys = make(
+				map[parcelBatchKey][]*AddressParcel,
+			)
+
+		case <-retryTicks:
+			p.Wg.Add(1)
+			go p.retryProofDeliveries()
+
 		case <-p.Quit:
 			return
 		}
 	}
 }
 
+// shipBatch ships the given queued parcels in a single anchor transaction. If
+// the parcels can't be merged, because they pay the same recipient, each of
+// them is shipped on its own instead.
+func (p *ChainPorter) shipBatch(parcels []*AddressParcel) {
+	if len(parcels) == 1 {
+		p.shipParcel(parcels[0])
+		return
+	}
+
+	merged := mergeAddressParcels(parcels...)
+	if err := merged.validate(); err != nil {
+		log.Warnf("Unable to merge %d queued parcels, shipping them "+
+			"individually: %v", len(parcels), err)
+
+		for _, parcel := range parcels {
+			p.shipParcel(parcel)
+		}
+		return
+	}
+
+	log.Infof("Shipping batch of %d parcels to %d addresses",
+		len(parcels), len(merged.destAddrs))
+
+	p.shipParcel(merged)
+}
+
+// shipParcel advances the state machine for the given parcel as far as
+// possible, delivering an error back to the caller if that fails.
+func (p *ChainPorter) shipParcel(req Parcel) {
+	// The request either has a destination address we want to send to, or
+	// a send package is already initialized.
+	sendPkg := req.pkg()
+
+	err := p.advanceState(sendPkg)
+	if err != nil {
+		sendPkg.logger().Warnf("Unable to advance state machine: %v",
+			err)
+		req.kit().deliverErr(err)
+	}
+}
+
 // waitForTransferTxConf waits for the confirmation of the final transaction
 // within the delta. Once confirmed, the parcel will be marked as delivered on
 // chain, with the goroutine cleaning up its state.
 func (p *ChainPorter) waitForTransferTxConf(pkg *sendPackage) error {
+	pkgLog := pkg.logger()
 	outboundPkg := pkg.OutboundPkg
 
 	txHash := outboundPkg.AnchorTx.TxHash()
-	log.Infof("Waiting for confirmation of transfer_txid=%v", txHash)
+	pkgLog.Infof("Waiting for confirmation of transfer_txid=%v", txHash)
 
 	confCtx, confCancel := p.WithCtxQuitNoTimeout()
 	confNtfn, errChan, err := p.cfg.ChainBridge.RegisterConfirmationsNtfn(
@@ -264,10 +651,20 @@ func (p *ChainPorter) waitForTransferTxConf(pkg *sendPackage) error {
 	// Launch a goroutine that'll notify us when the transaction confirms.
 	defer confCancel()
 
+	// A transfer transaction that isn't confirmed within a day most likely
+	// means we missed the notification.
+	doneWaiting := monitoring.TrackActivity(
+		monitoring.SubsystemPorter,
+		fmt.Sprintf("wait for conf of transfer_txid=%v", txHash),
+		confWaitDeadline,
+	)
+	defer doneWaiting()
+
 	var confEvent *chainntnfs.TxConfirmation
 	select {
 	case confEvent = <-confNtfn.Confirmed:
-		log.Debugf("Got chain confirmation: %v", confEvent.Tx.TxHash())
+		pkgLog.Debugf("Got chain confirmation: %v",
+			confEvent.Tx.TxHash())
 		pkg.TransferTxConfEvent = confEvent
 		pkg.SendState = SendStateStoreProofs
 
@@ -276,10 +673,10 @@ func (p *ChainPorter) waitForTransferTxConf(pkg *sendPackage) error {
 			"confirmation: %w", err)
 
 	case <-confCtx.Done():
-		log.Debugf("Skipping TX confirmation, context done")
+		pkgLog.Debugf("Skipping TX confirmation, context done")
 
 	case <-p.Quit:
-		log.Debugf("Skipping TX confirmation, exiting")
+		pkgLog.Debugf("Skipping TX confirmation, exiting")
 		return nil
 	}
 
@@ -294,12 +691,15 @@ func (p *ChainPorter) waitForTransferTxConf(pkg *sendPackage) error {
 // storeProofs writes the updated sender and receiver proof files to the proof
 // archive.
 func (p *ChainPorter) storeProofs(sendPkg *sendPackage) error {
+	pkgLog := sendPkg.logger()
+
 	// Now we'll enter the final phase of the send process, where we'll
 	// write the receiver's proof file to disk.
 	//
 	// First, we'll fetch the sender's current proof file.
 	ctx, cancel := p.CtxBlocking()
 	defer cancel()
+	ctx = monitoring.WithCorrelationID(ctx, sendPkg.CorrelationID)
 
 	parcel := sendPkg.OutboundPkg
 	confEvent := sendPkg.TransferTxConfEvent
@@ -327,7 +727,7 @@ func (p *ChainPorter) storeProofs(sendPkg *sendPackage) error {
 		)
 	}
 
-	log.Infof("Importing %d passive asset proofs into local Proof "+
+	pkgLog.Infof("Importing %d passive asset proofs into local Proof "+
 		"Archive", len(passiveAssetProofFiles))
 	err := p.cfg.AssetProofs.ImportProofs(
 		ctx, headerVerifier, passiveAssetProofFiles...,
@@ -341,7 +741,7 @@ func (p *ChainPorter) storeProofs(sendPkg *sendPackage) error {
 	// assets, such as in a Pool account, where the anchor UTXO is spent or
 	// re-created but the actual asset remains unchanged.
 	if len(parcel.Inputs) == 0 {
-		log.Debugf("Not updating proofs as there are no active " +
+		pkgLog.Debugf("Not updating proofs as there are no active " +
 			"transfers")
 
 		sendPkg.SendState = SendStateReceiverProofTransfer
@@ -352,7 +752,6 @@ func (p *ChainPorter) storeProofs(sendPkg *sendPackage) error {
 		map[asset.SerializedKey]*proof.AnnotatedProof,
 		len(parcel.Outputs),
 	)
-	firstInput := parcel.Inputs[0]
 	for idx := range parcel.Outputs {
 		out := parcel.Outputs[idx]
 
@@ -383,18 +782,32 @@ func (p *ChainPorter) storeProofs(sendPkg *sendPackage) error {
 				"%d: %w", idx, err)
 		}
 
+		// A parcel can transfer multiple asset IDs, so we only look at
+		// the inputs of the same asset ID as this output.
+		assetID := proofSuffix.Asset.ID()
+		var assetInputs []TransferInput
+		for _, in := range parcel.Inputs {
+			if in.ID == assetID {
+				assetInputs = append(assetInputs, in)
+			}
+		}
+		if len(assetInputs) == 0 {
+			return fmt.Errorf("no inputs found for output %d of "+
+				"asset %v", idx, assetID)
+		}
+
 		// The suffix is complete, so we need to fetch the input proof
 		// in order to append the suffix to it.
-		inputProofFile, err := p.fetchInputProof(ctx, firstInput)
+		inputProofFile, err := p.fetchInputProof(ctx, assetInputs[0])
 		if err != nil {
 			return fmt.Errorf("error fetching input proof: %w", err)
 		}
 
 		// Are there more inputs? Then this is a merge, and we need to
 		// add those additional files to the suffix as well.
-		for idx := 1; idx < len(parcel.Inputs); idx++ {
+		for idx := 1; idx < len(assetInputs); idx++ {
 			additionalInputProofFile, err := p.fetchInputProof(
-				ctx, parcel.Inputs[idx],
+				ctx, assetInputs[idx],
 			)
 			if err != nil {
 				return fmt.Errorf("error fetching input "+
@@ -420,7 +833,7 @@ func (p *ChainPorter) storeProofs(sendPkg *sendPackage) error {
 		// Now we just need to identify the new proof correctly before
 		// adding it to the proof archive.
 		outputProofLocator := proof.Locator{
-			AssetID:   &firstInput.ID,
+			AssetID:   &assetID,
 			ScriptKey: *out.ScriptKey.PubKey,
 		}
 		outputProof := &proof.AnnotatedProof{
@@ -431,7 +844,7 @@ func (p *ChainPorter) storeProofs(sendPkg *sendPackage) error {
 		sendPkg.FinalProofs[serializedScriptKey] = outputProof
 
 		// Import proof into proof archive.
-		log.Infof("Importing proof for output %d into local Proof "+
+		pkgLog.Infof("Importing proof for output %d into local Proof "+
 			"Archive", idx)
 		err = p.cfg.AssetProofs.ImportProofs(
 			ctx, headerVerifier, outputProof,
@@ -440,7 +853,7 @@ func (p *ChainPorter) storeProofs(sendPkg *sendPackage) error {
 			return fmt.Errorf("error importing proof: %w", err)
 		}
 
-		log.Debugf("Updated proofs for output %d (new_len=%d)",
+		pkgLog.Debugf("Updated proofs for output %d (new_len=%d)",
 			idx, inputProofFile.NumProofs())
 	}
 
@@ -528,78 +941,17 @@ func (p *ChainPorter) updateAssetProofFile(ctx context.Context, assetID asset.ID
 	return newAnnotatedProofFile, nil
 }
 
-// transferReceiverProof retrieves the sender and receiver proofs from the
-// archive and then transfers the receiver's proof to the receiver. Upon
-// successful transfer, the asset parcel delivery is marked as complete.
+// transferReceiverProof marks the asset parcel delivery as complete and then
+// transfers the receivers' proofs to the receivers. Proofs that can't be
+// delivered right away remain in the persisted proof delivery queue and are
+// re-attempted later.
 func (p *ChainPorter) transferReceiverProof(pkg *sendPackage) error {
+	pkgLog := pkg.logger()
+
 	ctx, cancel := p.WithCtxQuitNoTimeout()
 	defer cancel()
 
-	deliver := func(ctx context.Context, out TransferOutput) error {
-		key := out.ScriptKey.PubKey
-
-		// If this is an output that is going to our own node/wallet,
-		// we don't need to transfer the proof.
-		if out.ScriptKey.TweakedScriptKey != nil && out.ScriptKeyLocal {
-			log.Debugf("Not transferring proof for local output "+
-				"script key %x", key.SerializeCompressed())
-			return nil
-		}
-
-		// We just look for the full proof in the list of final proofs
-		// by matching the content of the proof suffix.
-		var receiverProof *proof.AnnotatedProof
-		for idx := range pkg.FinalProofs {
-			finalFile := pkg.FinalProofs[idx]
-			if finalFile.ScriptKey.IsEqual(out.ScriptKey.PubKey) {
-				receiverProof = finalFile
-				break
-			}
-		}
-		if receiverProof == nil {
-			return fmt.Errorf("no proof found for output with "+
-				"script key %x", key.SerializeCompressed())
-		}
-
-		log.Debugf("Attempting to deliver proof for script key %x",
-			key.SerializeCompressed())
-
-		recipient := proof.Recipient{
-			ScriptKey: key,
-			AssetID:   *receiverProof.AssetID,
-			Amount:    out.Amount,
-		}
-		err := p.cfg.ProofCourier.DeliverProof(
-			ctx, recipient, receiverProof,
-		)
-
-		// If the proof courier returned a backoff error, then
-		// we'll just return nil here so that we can retry
-		// later.
-		var backoffExecErr *proof.BackoffExecError
-		if errors.As(err, &backoffExecErr) {
-			return nil
-		}
-		if err != nil {
-			return fmt.Errorf("error delivering proof: %w", err)
-		}
-
-		return nil
-	}
-
-	// If we have a proof courier instance active, then we'll launch several
-	// goroutines to deliver the proof(s) to the receiver(s).
-	if p.cfg.ProofCourier != nil {
-		ctx, cancel := p.WithCtxQuitNoTimeout()
-		defer cancel()
-
-		err := chanutils.ParSlice(ctx, pkg.OutboundPkg.Outputs, deliver)
-		if err != nil {
-			return fmt.Errorf("error delivering proof(s): %w", err)
-		}
-	}
-
-	log.Infof("Marking parcel (txid=%v) as confirmed!",
+	pkgLog.Infof("Marking parcel (txid=%v) as confirmed!",
 		pkg.OutboundPkg.AnchorTx.TxHash())
 
 	// Load passive asset proof files from archive.
@@ -620,7 +972,9 @@ func (p *ChainPorter) transferReceiverProof(pkg *sendPackage) error {
 	}
 
 	// At this point we have the confirmation signal, so we can mark the
-	// parcel delivery as completed in the database.
+	// parcel delivery as completed in the database. We do this before
+	// delivering the proofs, so the parcel doesn't need to be re-broadcast
+	// if a proof courier is unreachable.
 	err := p.cfg.ExportLog.ConfirmParcelDelivery(ctx, &AssetConfirmEvent{
 		AnchorTXID:             pkg.OutboundPkg.AnchorTx.TxHash(),
 		BlockHash:              *pkg.TransferTxConfEvent.BlockHash,
@@ -635,9 +989,252 @@ func (p *ChainPorter) transferReceiverProof(pkg *sendPackage) error {
 	}
 
 	pkg.SendState = SendStateComplete
+
+	// If we have a proof courier dispatcher, then we'll now deliver the
+	// proof(s) to the receiver(s).
+	if p.cfg.ProofCourierDispatcher == nil {
+		return nil
+	}
+
+	// The courier picks up the correlation ID from the context, so the
+	// delivery attempts can be traced back to the parcel.
+	ctx = monitoring.WithCorrelationID(ctx, pkg.CorrelationID)
+
+	return p.deliverProofs(ctx, pkg.OutboundPkg, pkg.FinalProofs)
+}
+
+// retryProofDeliveries re-attempts the delivery of all proofs that are still
+// pending in the persisted proof delivery queue.
+func (p *ChainPorter) retryProofDeliveries() {
+	defer p.Wg.Done()
+
+	ctx, cancel := p.WithCtxQuitNoTimeout()
+	defer cancel()
+
+	parcels, err := p.cfg.ExportLog.PendingProofDeliveries(ctx)
+	if err != nil {
+		log.Errorf("Unable to fetch pending proof deliveries: %v", err)
+		return
+	}
+
+	if len(parcels) == 0 {
+		return
+	}
+
+	log.Infof("Retrying proof delivery for %d parcels", len(parcels))
+
+	for _, parcel := range parcels {
+		err := p.deliverProofs(ctx, parcel, nil)
+		if err != nil {
+			log.Warnf("Unable to deliver proofs for parcel "+
+				"(txid=%v): %v", parcel.AnchorTx.TxHash(), err)
+		}
+	}
+}
+
+// deliverProofs attempts to deliver the proofs of all outputs of the given
+// parcel that have a pending proof delivery. Each successful delivery is
+// recorded in the export log. If a delivery fails, the output remains in the
+// proof delivery queue and an error is returned once all other deliveries were
+// attempted.
+func (p *ChainPorter) deliverProofs(ctx context.Context,
+	parcel *OutboundParcel,
+	finalProofs map[asset.SerializedKey]*proof.AnnotatedProof) error {
+
+	var (
+		numFailed int
+		failedMtx sync.Mutex
+	)
+	deliver := func(ctx context.Context, out TransferOutput) error {
+		if out.ProofDeliveryStatus != ProofDeliveryPending {
+			return nil
+		}
+
+		key := outputKey{
+			anchorPoint: out.Anchor.OutPoint,
+			scriptKey:   asset.ToSerialized(out.ScriptKey.PubKey),
+		}
+
+		// Another goroutine might currently be delivering the same
+		// proof, in which case we don't attempt it again.
+		p.deliveryMtx.Lock()
+		if _, ok := p.deliveriesInFlight[key]; ok {
+			p.deliveryMtx.Unlock()
+			return nil
+		}
+		p.deliveriesInFlight[key] = struct{}{}
+		p.deliveryMtx.Unlock()
+
+		defer func() {
+			p.deliveryMtx.Lock()
+			delete(p.deliveriesInFlight, key)
+			p.deliveryMtx.Unlock()
+		}()
+
+		err := p.deliverProof(ctx, out, finalProofs)
+		if err != nil {
+			log.Warnf("Unable to deliver proof for script key "+
+				"%x, will retry later: %v", key.scriptKey[:],
+				err)
+
+			failedMtx.Lock()
+			numFailed++
+			failedMtx.Unlock()
+		}
+
+		return nil
+	}
+
+	err := chanutils.ParSlice(ctx, parcel.Outputs, deliver)
+	if err != nil {
+		return fmt.Errorf("error delivering proof(s): %w", err)
+	}
+
+	if numFailed > 0 {
+		return fmt.Errorf("unable to deliver %d proof(s), delivery "+
+			"will be retried", numFailed)
+	}
+
 	return nil
 }
 
+// deliverProof delivers the proof of a single transfer output through the
+// proof courier of the output and marks the delivery as complete.
+func (p *ChainPorter) deliverProof(ctx context.Context, out TransferOutput,
+	finalProofs map[asset.SerializedKey]*proof.AnnotatedProof) error {
+
+	key := out.ScriptKey.PubKey
+
+	courierAddr := out.ProofCourierAddr
+	if courierAddr == nil {
+		courierAddr = p.cfg.DefaultProofCourierAddr
+	}
+	if courierAddr == nil {
+		return fmt.Errorf("no proof courier address for output with "+
+			"script key %x", key.SerializeCompressed())
+	}
+
+	courier, err := p.cfg.ProofCourierDispatcher.NewCourier(courierAddr)
+	if err != nil {
+		return fmt.Errorf("unable to create proof courier: %w", err)
+	}
+
+	receiverProof, err := p.fetchOutputProof(ctx, out, finalProofs)
+	if err != nil {
+		return err
+	}
+
+	// The receiver of a transfer to a static address doesn't know the
+	// unique script key of the receive yet, so the proof is delivered to
+	// the script key of the static address instead.
+	recipientKey, err := staticReceiveKey(out)
+	if err != nil {
+		return err
+	}
+	if recipientKey == nil {
+		recipientKey = key
+	}
+
+	log.Debugf("Attempting to deliver proof for script key %x using "+
+		"courier %v", key.SerializeCompressed(), courierAddr)
+
+	recipient := proof.Recipient{
+		ScriptKey: recipientKey,
+		AssetID:   *receiverProof.AssetID,
+		Amount:    out.Amount,
+	}
+	err = courier.DeliverProof(ctx, recipient, receiverProof)
+	if err != nil {
+		return fmt.Errorf("error delivering proof: %w", err)
+	}
+
+	err = p.cfg.ExportLog.ConfirmProofDelivery(
+		ctx, out.Anchor.OutPoint, key,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to confirm proof delivery: %w", err)
+	}
+
+	return nil
+}
+
+// fetchOutputProof returns the full proof file of the given transfer output,
+// either from the given set of final proofs or from the proof archive.
+func (p *ChainPorter) fetchOutputProof(ctx context.Context, out TransferOutput,
+	finalProofs map[asset.SerializedKey]*proof.AnnotatedProof) (
+	*proof.AnnotatedProof, error) {
+
+	serializedKey := asset.ToSerialized(out.ScriptKey.PubKey)
+	if finalProof, ok := finalProofs[serializedKey]; ok {
+		return finalProof, nil
+	}
+
+	// The proof suffix tells us which asset ID to look up the proof for.
+	var proofSuffix proof.Proof
+	err := proofSuffix.Decode(bytes.NewReader(out.ProofSuffix))
+	if err != nil {
+		return nil, fmt.Errorf("error decoding proof suffix: %w", err)
+	}
+
+	assetID := proofSuffix.Asset.ID()
+	locator := proof.Locator{
+		AssetID:   &assetID,
+		ScriptKey: *out.ScriptKey.PubKey,
+	}
+	proofBlob, err := p.cfg.AssetProofs.FetchProof(ctx, locator)
+	if err != nil {
+		return nil, fmt.Errorf("no proof found for output with "+
+			"script key %x: %w", serializedKey[:], err)
+	}
+
+	return &proof.AnnotatedProof{
+		Locator: locator,
+		Blob:    proofBlob,
+	}, nil
+}
+
+// proofDeliveryStatus returns the initial proof delivery status of the given
+// transfer output. Only the proofs of outputs that go to a remote receiver
+// need to be delivered, and only if we know how to reach them.
+func (p *ChainPorter) proofDeliveryStatus(
+	out *TransferOutput) ProofDeliveryStatus {
+
+	key := out.ScriptKey.PubKey
+
+	switch {
+	// Without a proof courier, we can't deliver any proofs.
+	case p.cfg.ProofCourierDispatcher == nil:
+		return ProofDeliveryNotRequired
+
+	// If this is an output that is going to our own node/wallet, we don't
+	// need to transfer the proof.
+	case out.ScriptKeyLocal:
+		return ProofDeliveryNotRequired
+
+	// Outputs that only carry passive assets don't have a receiver.
+	case out.Type == tappsbt.TypePassiveAssetsOnly:
+		return ProofDeliveryNotRequired
+
+	// Burned assets don't have a receiver, so there's nobody we could
+	// deliver the proof to.
+	case out.Type == tappsbt.TypeBurn:
+		return ProofDeliveryNotRequired
+
+	case len(out.WitnessData) > 0 &&
+		asset.IsBurnKey(key, out.WitnessData[0]):
+
+		return ProofDeliveryNotRequired
+
+	case out.ProofCourierAddr == nil &&
+		p.cfg.DefaultProofCourierAddr == nil:
+
+		return ProofDeliveryNotRequired
+
+	default:
+		return ProofDeliveryPending
+	}
+}
+
 // importLocalAddresses imports the addresses for outputs that go to ourselves,
 // from the given outbound parcel.
 func (p *ChainPorter) importLocalAddresses(ctx context.Context,
@@ -690,12 +1287,127 @@ func (p *ChainPorter) importLocalAddresses(ctx context.Context,
 	return nil
 }
 
+// staticReceiveKey returns the script key of the static address the given
+// transfer output pays to, or nil if the output isn't a receive through a
+// static address.
+func staticReceiveKey(out TransferOutput) (*btcec.PublicKey, error) {
+	sibling, _, err := commitment.MaybeDecodeTapscriptPreimage(
+		out.Anchor.TapscriptSibling,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode tapscript sibling: %w",
+			err)
+	}
+
+	baseKey, nonce, err := address.ParseStaticReceiveSibling(sibling)
+	switch {
+	case errors.Is(err, address.ErrNoStaticReceive):
+		return nil, nil
+
+	case err != nil:
+		return nil, err
+	}
+
+	// Only outputs that actually pay to the receive derived from the leaf
+	// are delivered to the static address.
+	receiveKey := address.StaticReceiveScriptKey(baseKey, nonce)
+	receiveKeyBytes := schnorr.SerializePubKey(receiveKey)
+	outKeyBytes := schnorr.SerializePubKey(out.ScriptKey.PubKey)
+	if !bytes.Equal(receiveKeyBytes, outKeyBytes) {
+		return nil, nil
+	}
+
+	return baseKey, nil
+}
+
+// fundAddressParcel funds the virtual packets for a send to the given
+// addresses. Addresses of different asset IDs are funded in separate virtual
+// packets, each using its own range of anchor output indexes, so they can all
+// be anchored in the same BTC level transaction. All packets are funded using
+// the given coin selection strategy. If an edition is given, the send must be
+// to a single address and only that edition of the collectible is selected.
+func (p *ChainPorter) fundAddressParcel(ctx context.Context,
+	strategy MultiCommitmentSelectStrategy, edition uint64,
+	addrs []*address.Tap) ([]*FundedVPacket, error) {
+
+	if edition != 0 && len(addrs) != 1 {
+		return nil, fmt.Errorf("an edition can only be sent to a " +
+			"single address")
+	}
+
+	// Group the addresses by asset ID, keeping the order in which the
+	// asset IDs first appear.
+	var (
+		assetIDs  []asset.ID
+		addrsByID = make(map[asset.ID][]*address.Tap)
+	)
+	for _, addr := range addrs {
+		if _, ok := addrsByID[addr.AssetID]; !ok {
+			assetIDs = append(assetIDs, addr.AssetID)
+		}
+		addrsByID[addr.AssetID] = append(addrsByID[addr.AssetID], addr)
+	}
+
+	// Sending a single asset ID is the common case that the wallet knows
+	// how to fund directly, unless a specific edition must be selected.
+	if len(assetIDs) <= 1 && edition == 0 {
+		fundedPkt, err := p.cfg.AssetWallet.FundAddressSend(
+			ctx, strategy, addrs...,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		return []*FundedVPacket{fundedPkt}, nil
+	}
+
+	// Each packet gets a change output, followed by one output for each of
+	// its recipients. The anchor output indexes must be assigned before
+	// funding, since the split commitments commit to them.
+	var (
+		fundedPkts    []*FundedVPacket
+		nextAnchorIdx uint32
+	)
+	for _, assetID := range assetIDs {
+		idAddrs := addrsByID[assetID]
+		vPkt, err := tappsbt.FromAddresses(idAddrs, nextAnchorIdx+1)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create virtual "+
+				"transaction for asset %v: %w", assetID, err)
+		}
+
+		// The change output is always the first output of the packet.
+		vPkt.Outputs[0].AnchorOutputIndex = nextAnchorIdx
+		nextAnchorIdx += uint32(len(idAddrs)) + 1
+
+		fundDesc, err := tapscript.DescribeAddrs(idAddrs)
+		if err != nil {
+			return nil, fmt.Errorf("unable to describe "+
+				"recipients: %w", err)
+		}
+		fundDesc.Edition = edition
+
+		fundedPkt, err := p.cfg.AssetWallet.FundPacket(
+			ctx, fundDesc, strategy, vPkt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to fund send of asset "+
+				"%v: %w", assetID, err)
+		}
+		fundedPkts = append(fundedPkts, fundedPkt)
+	}
+
+	return fundedPkts, nil
+}
+
 // advanceState advances the state machine.
 func (p *ChainPorter) advanceState(pkg *sendPackage) error {
+	pkgLog := pkg.logger()
+
 	// Continue state transitions whilst state complete has not yet
 	// been reached.
 	for pkg.SendState < SendStateComplete {
-		log.Infof("ChainPorter executing state: %v",
+		pkgLog.Infof("ChainPorter executing state: %v",
 			pkg.SendState)
 
 		// Before we attempt a state transition, make sure that
@@ -709,18 +1421,69 @@ func (p *ChainPorter) advanceState(pkg *sendPackage) error {
 
 		updatedPkg, err := p.stateStep(*pkg)
 		if err != nil {
+			// A parcel that fails after its anchor transaction
+			// was broadcast was already counted as broadcast.
+			if pkg.SendState <= SendStateBroadcast {
+				monitoring.ObserveParcelFailed()
+			}
+
+			// If the parcel wasn't logged as pending yet, its
+			// inputs can be used for another transfer again.
+			if pkg.SendState <= SendStateLogCommit {
+				p.releaseParcelInputs(pkg)
+			}
+
 			p.cfg.ErrChan <- err
-			log.Errorf("Error evaluating state (%v): %v",
+			pkgLog.Errorf("Error evaluating state (%v): %v",
 				pkg.SendState, err)
 			return err
 		}
 
+		// Let subscribers know about the transfer stage that was
+		// completed, if the state machine made any progress.
+		stage, ok := completedTransferStage(pkg.SendState)
+		if ok && updatedPkg.SendState > pkg.SendState {
+			p.publishSubscriberEvent(
+				NewTransferStageEvent(stage, updatedPkg),
+			)
+		}
+
 		pkg = updatedPkg
+
+		// If the anchor transaction is signed by an external wallet,
+		// the state machine can't make any progress until the signed
+		// transaction is handed back.
+		if pkg.SendState == SendStateWaitAnchorSign {
+			return p.exportAnchorPsbt(pkg)
+		}
 	}
 
 	return nil
 }
 
+// releaseParcelInputs releases the leases of the asset inputs of the given
+// package, so they can be selected for another transfer again.
+func (p *ChainPorter) releaseParcelInputs(pkg *sendPackage) {
+	ctx, cancel := p.WithCtxQuit()
+	defer cancel()
+
+	var inputs []wire.OutPoint
+	for _, vPkt := range pkg.VirtualPackets {
+		for _, vIn := range vPkt.Inputs {
+			inputs = append(inputs, vIn.PrevID.OutPoint)
+		}
+	}
+
+	if len(inputs) == 0 {
+		return
+	}
+
+	err := p.cfg.CoinSelector.ReleaseCoins(ctx, inputs...)
+	if err != nil {
+		pkg.logger().Errorf("Unable to release inputs: %v", err)
+	}
+}
+
 // createDummyOutput creates a new Bitcoin transaction output that is later
 // used to embed a Taproot Asset commitment.
 func createDummyOutput() *wire.TxOut {
@@ -735,6 +1498,8 @@ func createDummyOutput() *wire.TxOut {
 // stateStep attempts to step through the state machine to complete a Taproot
 // Asset transfer.
 func (p *ChainPorter) stateStep(currentPkg sendPackage) (*sendPackage, error) {
+	pkgLog := currentPkg.logger()
+
 	// Notify subscribers that the state machine is about to execute a
 	// state.
 	stateEvent := NewExecuteSendStateEvent(currentPkg.SendState)
@@ -749,23 +1514,46 @@ func (p *ChainPorter) stateStep(currentPkg sendPackage) (*sendPackage, error) {
 		defer cancel()
 
 		// We know that the porter is only initialized with this state
-		// for a send to an address parcel. If not, something was called
-		// incorrectly.
-		addrParcel, ok := currentPkg.Parcel.(*AddressParcel)
-		if !ok {
-			return nil, fmt.Errorf("unable to cast parcel to " +
-				"address parcel")
-		}
-		fundSendRes, err := p.cfg.AssetWallet.FundAddressSend(
-			ctx, addrParcel.destAddrs...,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("unable to fund address send: "+
-				"%w", err)
+		// for a send to an address parcel or a burn parcel. If not,
+		// something was called incorrectly.
+		var fundedPkts []*FundedVPacket
+		switch parcel := currentPkg.Parcel.(type) {
+		case *AddressParcel:
+			var err error
+			opts := currentPkg.shipmentOptions()
+			fundedPkts, err = p.fundAddressParcel(
+				ctx, parcel.selectStrategy, opts.Edition,
+				parcel.destAddrs,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("unable to fund "+
+					"address send: %w", err)
+			}
+
+		case *BurnParcel:
+			fundSendRes, err := p.cfg.AssetWallet.FundBurn(
+				ctx, parcel.fundDesc,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("unable to fund burn: "+
+					"%w", err)
+			}
+			fundedPkts = []*FundedVPacket{fundSendRes}
+
+		default:
+			return nil, fmt.Errorf("unable to fund parcel of "+
+				"type %T", currentPkg.Parcel)
 		}
 
-		currentPkg.VirtualPacket = fundSendRes.VPacket
-		currentPkg.InputCommitments = fundSendRes.InputCommitments
+		for _, fundedPkt := range fundedPkts {
+			currentPkg.VirtualPackets = append(
+				currentPkg.VirtualPackets, fundedPkt.VPacket,
+			)
+			currentPkg.InputCommitments = append(
+				currentPkg.InputCommitments,
+				fundedPkt.InputCommitments,
+			)
+		}
 
 		currentPkg.SendState = SendStateVirtualSign
 
@@ -774,18 +1562,25 @@ func (p *ChainPorter) stateStep(currentPkg sendPackage) (*sendPackage, error) {
 	// At this point, we have everything we need to sign our _virtual_
 	// transaction on the Taproot Asset layer.
 	case SendStateVirtualSign:
-		vPacket := currentPkg.VirtualPacket
-		receiverScriptKey := vPacket.Outputs[1].ScriptKey.PubKey
-		log.Infof("Generating Taproot Asset witnesses for send to: %x",
-			receiverScriptKey.SerializeCompressed())
-
-		// Now we'll use the signer to sign all the inputs for the new
-		// Taproot Asset leaves. The witness data for each input will be
-		// assigned for us.
-		_, err := p.cfg.AssetWallet.SignVirtualPacket(vPacket)
-		if err != nil {
-			return nil, fmt.Errorf("unable to sign and commit "+
-				"virtual packet: %w", err)
+		for _, vPacket := range currentPkg.VirtualPackets {
+			firstRecipient, err := vPacket.FirstNonSplitRootOutput()
+			if err != nil {
+				return nil, fmt.Errorf("unable to get first "+
+					"recipient output: %w", err)
+			}
+			receiverScriptKey := firstRecipient.ScriptKey.PubKey
+			pkgLog.Infof("Generating Taproot Asset witnesses for "+
+				"send to: %x",
+				receiverScriptKey.SerializeCompressed())
+
+			// Now we'll use the signer to sign all the inputs for
+			// the new Taproot Asset leaves. The witness data for
+			// each input will be assigned for us.
+			_, err = p.cfg.AssetWallet.SignVirtualPacket(vPacket)
+			if err != nil {
+				return nil, fmt.Errorf("unable to sign and "+
+					"commit virtual packet: %w", err)
+			}
 		}
 
 		currentPkg.SendState = SendStateAnchorSign
@@ -799,36 +1594,52 @@ func (p *ChainPorter) stateStep(currentPkg sendPackage) (*sendPackage, error) {
 		ctx, cancel := p.WithCtxQuitNoTimeout()
 		defer cancel()
 
-		// Submit the template PSBT to the wallet for funding.
+		// Submit the template PSBT to the wallet for funding. Unless
+		// an explicit fee rate was requested, we estimate one for the
+		// requested confirmation target.
 		//
 		// TODO(roasbeef): unlock the input UTXOs of things fail
-		feeRate, err := p.cfg.ChainBridge.EstimateFee(
-			ctx, tapscript.SendConfTarget,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("unable to estimate fee: %w",
-				err)
-		}
-
-		vPacket := currentPkg.VirtualPacket
-		firstRecipient, err := vPacket.FirstNonSplitRootOutput()
-		if err != nil {
-			return nil, fmt.Errorf("unable to get first "+
-				"interactive output: %w", err)
+		opts := currentPkg.shipmentOptions()
+		var feeRate chainfee.SatPerKWeight
+		if opts.FeeRate != nil {
+			feeRate = *opts.FeeRate
+		} else {
+			var err error
+			feeRate, err = p.cfg.ChainBridge.EstimateFee(
+				ctx, opts.ConfTarget,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("unable to estimate "+
+					"fee: %w", err)
+			}
 		}
-		receiverScriptKey := firstRecipient.ScriptKey.PubKey
-		log.Infof("Constructing new Taproot Asset commitments for "+
-			"send to: %x", receiverScriptKey.SerializeCompressed())
 
-		// Gather passive assets virtual packets and sign them.
+		// Gather passive assets virtual packets and sign them. Each
+		// active packet brings along the passive assets of the anchor
+		// outputs it spends.
 		wallet := p.cfg.AssetWallet
+		currentPkg.PassiveAssets = nil
+		for idx, vPacket := range currentPkg.VirtualPackets {
+			firstRecipient, err := vPacket.FirstNonSplitRootOutput()
+			if err != nil {
+				return nil, fmt.Errorf("unable to get first "+
+					"interactive output: %w", err)
+			}
+			receiverScriptKey := firstRecipient.ScriptKey.PubKey
+			pkgLog.Infof("Constructing new Taproot Asset "+
+				"commitments for send to: %x",
+				receiverScriptKey.SerializeCompressed())
 
-		currentPkg.PassiveAssets, err = wallet.SignPassiveAssets(
-			vPacket, currentPkg.InputCommitments,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("unable to sign passive "+
-				"assets: %w", err)
+			passiveAssets, err := wallet.SignPassiveAssets(
+				vPacket, currentPkg.InputCommitments[idx],
+			)
+			if err != nil {
+				return nil, fmt.Errorf("unable to sign "+
+					"passive assets: %w", err)
+			}
+			currentPkg.PassiveAssets = append(
+				currentPkg.PassiveAssets, passiveAssets...,
+			)
 		}
 
 		var passiveVPackets []*tappsbt.VPacket
@@ -841,9 +1652,10 @@ func (p *ChainPorter) stateStep(currentPkg sendPackage) (*sendPackage, error) {
 		anchorTx, err := wallet.AnchorVirtualTransactions(
 			ctx, &AnchorVTxnsParams{
 				FeeRate:            feeRate,
-				VPkts:              []*tappsbt.VPacket{vPacket},
+				VPkts:              currentPkg.VirtualPackets,
 				InputCommitments:   currentPkg.InputCommitments,
 				PassiveAssetsVPkts: passiveVPackets,
+				SkipAnchorSign:     opts.ExternalAnchorSign,
 			},
 		)
 		if err != nil {
@@ -851,6 +1663,28 @@ func (p *ChainPorter) stateStep(currentPkg sendPackage) (*sendPackage, error) {
 				"transactions: %w", err)
 		}
 
+		// Before we go any further, we make sure the anchor
+		// transaction stays within the requested fee budget.
+		chainFees := btcutil.Amount(anchorTx.ChainFees)
+		pkgLog.Infof("Anchor transaction pays %v in chain fees at "+
+			"fee rate %v (max_fee=%v)", chainFees, feeRate,
+			opts.MaxFee)
+		if opts.MaxFee != 0 && chainFees > opts.MaxFee {
+			// The BTC level inputs of the anchor transaction
+			// were leased when funding it, so we release them.
+			err := p.cfg.Wallet.UnlockInput(
+				ctx, anchorTx.FundedPsbt.LockedUTXOs,
+			)
+			if err != nil {
+				pkgLog.Errorf("Unable to unlock anchor "+
+					"inputs: %v", err)
+			}
+
+			return nil, fmt.Errorf("%w: chain fees of %v exceed "+
+				"max fee of %v", ErrMaxFeeExceeded, chainFees,
+				opts.MaxFee)
+		}
+
 		// We keep the original funded PSBT with all the wallet's output
 		// information on the change output preserved but continue the
 		// signing process with a copy to avoid clearing the info on
@@ -858,6 +1692,9 @@ func (p *ChainPorter) stateStep(currentPkg sendPackage) (*sendPackage, error) {
 		currentPkg.AnchorTx = anchorTx
 
 		currentPkg.SendState = SendStateLogCommit
+		if opts.ExternalAnchorSign {
+			currentPkg.SendState = SendStateWaitAnchorSign
+		}
 
 		return &currentPkg, nil
 
@@ -883,6 +1720,32 @@ func (p *ChainPorter) stateStep(currentPkg sendPackage) (*sendPackage, error) {
 		}
 		currentPkg.OutboundPkg = parcel
 
+		// Writing the parcel to disk is the point of no return, so we
+		// make sure no passive asset is left behind in an anchor output
+		// we spend, unless the caller explicitly opted out.
+		opts := currentPkg.shipmentOptions()
+		err = currentPkg.verifyPassiveAssets()
+		switch {
+		case err != nil && opts.ForceStrandPassiveAssets:
+
+			pkgLog.Warnf("Force broadcasting transfer despite "+
+				"failed passive asset check: %v", err)
+
+		case err != nil:
+			// The BTC level inputs of the anchor transaction
+			// were leased when funding it, so we release them.
+			unlockErr := p.cfg.Wallet.UnlockInput(
+				ctx, currentPkg.AnchorTx.FundedPsbt.LockedUTXOs,
+			)
+			if unlockErr != nil {
+				pkgLog.Errorf("Unable to unlock anchor "+
+					"inputs: %v", unlockErr)
+			}
+
+			return nil, fmt.Errorf("refusing to broadcast "+
+				"transfer: %w", err)
+		}
+
 		// We now need to find out if this is a transfer to ourselves
 		// (e.g. a change output) or an outbound transfer. A key being
 		// local means the lnd node connected to this daemon knows how
@@ -895,15 +1758,23 @@ func (p *ChainPorter) stateStep(currentPkg sendPackage) (*sendPackage, error) {
 
 				out.ScriptKeyLocal = true
 			}
+
+			out.ProofDeliveryStatus = p.proofDeliveryStatus(out)
 		}
 
 		// Don't allow shutdown while we're attempting to store proofs.
 		ctx, cancel = p.CtxBlocking()
 		defer cancel()
 
-		log.Infof("Committing pending parcel to disk")
+		pkgLog.Infof("Committing pending parcel to disk")
 
-		err = p.cfg.ExportLog.LogPendingParcel(ctx, parcel)
+		// The inputs of the parcel stay leased until the parcel
+		// confirms, since the assets they anchor can't be spent in
+		// another transfer anymore.
+		err = p.cfg.ExportLog.LogPendingParcel(
+			ctx, parcel, defaultWalletLeaseIdentifier,
+			time.Now().Add(defaultBroadcastCoinLeaseDuration),
+		)
 		if err != nil {
 			return nil, fmt.Errorf("unable to write send pkg to "+
 				"disk: %v", err)
@@ -927,7 +1798,7 @@ func (p *ChainPorter) stateStep(currentPkg sendPackage) (*sendPackage, error) {
 				"addresses: %w", err)
 		}
 
-		log.Infof("Broadcasting new transfer tx, txid=%v",
+		pkgLog.Infof("Broadcasting new transfer tx, txid=%v",
 			currentPkg.OutboundPkg.AnchorTx.TxHash())
 
 		// With the public key imported, we can now broadcast to the
@@ -939,6 +1810,10 @@ func (p *ChainPorter) stateStep(currentPkg sendPackage) (*sendPackage, error) {
 			return nil, err
 		}
 
+		monitoring.ObserveParcelBroadcast(
+			currentPkg.OutboundPkg.ChainFees,
+		)
+
 		// With the transaction broadcast, we'll deliver a
 		// notification via the transaction broadcast response channel.
 		currentPkg.deliverTxBroadcastResp()
@@ -974,9 +1849,14 @@ func (p *ChainPorter) stateStep(currentPkg sendPackage) (*sendPackage, error) {
 
 			err := p.transferReceiverProof(&currentPkg)
 			if err != nil {
-				log.Errorf("unable to transfer receiver "+
+				pkgLog.Errorf("unable to transfer receiver "+
 					"proof: %v", err)
+				return
 			}
+
+			p.publishSubscriberEvent(NewTransferStageEvent(
+				TransferStageProofsDelivered, &currentPkg,
+			))
 		}()
 
 		return &currentPkg, nil
@@ -1000,9 +1880,10 @@ func (p *ChainPorter) RegisterSubscriber(
 
 	p.subscribers[receiver.ID()] = receiver
 
-	// If we have a proof courier, we'll also update its subscribers.
-	if p.cfg.ProofCourier != nil {
-		p.cfg.ProofCourier.SetSubscribers(p.subscribers)
+	// If we have a proof courier dispatcher, we'll also update the
+	// subscribers of its couriers.
+	if p.cfg.ProofCourierDispatcher != nil {
+		p.cfg.ProofCourierDispatcher.SetSubscribers(p.subscribers)
 	}
 
 	return nil
@@ -1025,9 +1906,10 @@ func (p *ChainPorter) RemoveSubscriber(
 	subscriber.Stop()
 	delete(p.subscribers, subscriber.ID())
 
-	// If we have a proof courier, we'll also update its subscribers.
-	if p.cfg.ProofCourier != nil {
-		p.cfg.ProofCourier.SetSubscribers(p.subscribers)
+	// If we have a proof courier dispatcher, we'll also update the
+	// subscribers of its couriers.
+	if p.cfg.ProofCourierDispatcher != nil {
+		p.cfg.ProofCourierDispatcher.SetSubscribers(p.subscribers)
 	}
 
 	return nil
@@ -1071,3 +1953,164 @@ func NewExecuteSendStateEvent(state SendState) *ExecuteSendStateEvent {
 		SendState: state,
 	}
 }
+
+// TransferStage is an enum that describes a stage of an asset transfer that
+// was completed.
+type TransferStage uint8
+
+const (
+	// TransferStageCoinsSelected is the stage in which the asset coins
+	// that fund the transfer were selected.
+	TransferStageCoinsSelected TransferStage = iota
+
+	// TransferStageVirtualSigned is the stage in which the virtual
+	// transactions of the transfer were signed.
+	TransferStageVirtualSigned
+
+	// TransferStageAnchorFunded is the stage in which the anchor
+	// transaction was funded and signed.
+	TransferStageAnchorFunded
+
+	// TransferStageBroadcast is the stage in which the anchor transaction
+	// was broadcast to the network.
+	TransferStageBroadcast
+
+	// TransferStageConfirmed is the stage in which the anchor transaction
+	// confirmed on-chain.
+	TransferStageConfirmed
+
+	// TransferStageProofsDelivered is the stage in which the proofs of the
+	// transfer were stored and delivered to the receivers.
+	TransferStageProofsDelivered
+)
+
+// String returns a human-readable version of TransferStage.
+func (s TransferStage) String() string {
+	switch s {
+	case TransferStageCoinsSelected:
+		return "TransferStageCoinsSelected"
+
+	case TransferStageVirtualSigned:
+		return "TransferStageVirtualSigned"
+
+	case TransferStageAnchorFunded:
+		return "TransferStageAnchorFunded"
+
+	case TransferStageBroadcast:
+		return "TransferStageBroadcast"
+
+	case TransferStageConfirmed:
+		return "TransferStageConfirmed"
+
+	case TransferStageProofsDelivered:
+		return "TransferStageProofsDelivered"
+
+	default:
+		return fmt.Sprintf("<unknown_stage(%d)>", s)
+	}
+}
+
+// completedTransferStage returns the transfer stage that is completed once the
+// given send state was executed, if any.
+func completedTransferStage(state SendState) (TransferStage, bool) {
+	switch state {
+	case SendStateVirtualCommitmentSelect:
+		return TransferStageCoinsSelected, true
+
+	case SendStateVirtualSign:
+		return TransferStageVirtualSigned, true
+
+	case SendStateAnchorSign:
+		return TransferStageAnchorFunded, true
+
+	case SendStateBroadcast:
+		return TransferStageBroadcast, true
+
+	case SendStateWaitTxConf:
+		return TransferStageConfirmed, true
+
+	default:
+		return 0, false
+	}
+}
+
+// TransferStageEvent is an event which is sent to the ChainPorter's event
+// subscribers after a stage of a transfer was completed.
+type TransferStageEvent struct {
+	// timestamp is the time the event was created.
+	timestamp time.Time
+
+	// Stage is the transfer stage that was completed.
+	Stage TransferStage
+
+	// AnchorTxHash is the hash of the anchor transaction of the transfer.
+	// This is only known once the anchor transaction was funded.
+	AnchorTxHash *chainhash.Hash
+
+	// AnchorOutputIndexes are the indexes of the anchor transaction
+	// outputs that carry the assets of the transfer, in ascending order.
+	AnchorOutputIndexes []uint32
+
+	// BlockHeight is the height of the block the anchor transaction
+	// confirmed in. This is only known once the transaction confirmed.
+	BlockHeight uint32
+}
+
+// Timestamp returns the timestamp of the event.
+func (e *TransferStageEvent) Timestamp() time.Time {
+	return e.timestamp
+}
+
+// NewTransferStageEvent creates a new TransferStageEvent for the given stage,
+// extracting the anchor transaction information known so far from the given
+// send package.
+func NewTransferStageEvent(stage TransferStage,
+	pkg *sendPackage) *TransferStageEvent {
+
+	event := &TransferStageEvent{
+		timestamp: time.Now().UTC(),
+		Stage:     stage,
+	}
+
+	// Once the transfer was logged, the outbound parcel is the source of
+	// truth, also for transfers that were resumed after a restart.
+	indexes := make(map[uint32]struct{})
+	switch {
+	case pkg.OutboundPkg != nil:
+		txHash := pkg.OutboundPkg.AnchorTx.TxHash()
+		event.AnchorTxHash = &txHash
+
+		for _, out := range pkg.OutboundPkg.Outputs {
+			indexes[out.Anchor.OutPoint.Index] = struct{}{}
+		}
+
+	default:
+		if pkg.AnchorTx != nil && pkg.AnchorTx.FinalTx != nil {
+			txHash := pkg.AnchorTx.FinalTx.TxHash()
+			event.AnchorTxHash = &txHash
+		}
+
+		for _, vPkt := range pkg.VirtualPackets {
+			for _, vOut := range vPkt.Outputs {
+				indexes[vOut.AnchorOutputIndex] = struct{}{}
+			}
+		}
+	}
+
+	event.AnchorOutputIndexes = make([]uint32, 0, len(indexes))
+	for idx := range indexes {
+		event.AnchorOutputIndexes = append(
+			event.AnchorOutputIndexes, idx,
+		)
+	}
+	sort.Slice(event.AnchorOutputIndexes, func(i, j int) bool {
+		return event.AnchorOutputIndexes[i] <
+			event.AnchorOutputIndexes[j]
+	})
+
+	if pkg.TransferTxConfEvent != nil {
+		event.BlockHeight = pkg.TransferTxConfEvent.BlockHeight
+	}
+
+	return event
+}
@@ -1,17 +1,538 @@
 package tapfreighter
 
 import (
+	"context"
+	"errors"
 	"math/rand"
 	"testing"
 	"time"
 
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightninglabs/taproot-assets/address"
+	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightninglabs/taproot-assets/commitment"
+	"github.com/lightninglabs/taproot-assets/internal/test"
+	"github.com/lightninglabs/taproot-assets/proof"
+	"github.com/lightninglabs/taproot-assets/tappsbt"
+	"github.com/lightninglabs/taproot-assets/tapscript"
 	"github.com/lightningnetwork/lnd/build"
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRunChainPorter(t *testing.T) {
 	t.Parallel()
 }
 
+// TestShipmentOptions tests that the shipment options are applied and
+// validated as expected.
+func TestShipmentOptions(t *testing.T) {
+	t.Parallel()
+
+	feeRate := chainfee.SatPerKWeight(1000)
+	testCases := []struct {
+		name         string
+		optFuncs     []ShipmentOption
+		expectedOpts *ShipmentOptions
+		expectedErr  string
+	}{{
+		name: "defaults",
+		expectedOpts: &ShipmentOptions{
+			ConfTarget: tapscript.SendConfTarget,
+		},
+	}, {
+		name: "all options",
+		optFuncs: []ShipmentOption{
+			WithFeeRate(feeRate), WithConfTarget(2),
+			WithMaxFee(1234), WithEdition(3),
+		},
+		expectedOpts: &ShipmentOptions{
+			FeeRate:    &feeRate,
+			ConfTarget: 2,
+			MaxFee:     1234,
+			Edition:    3,
+		},
+	}, {
+		name: "fee rate below floor",
+		optFuncs: []ShipmentOption{
+			WithFeeRate(chainfee.FeePerKwFloor - 1),
+		},
+		expectedErr: "below floor",
+	}, {
+		name:        "zero conf target",
+		optFuncs:    []ShipmentOption{WithConfTarget(0)},
+		expectedErr: "conf target must be positive",
+	}, {
+		name:        "negative max fee",
+		optFuncs:    []ShipmentOption{WithMaxFee(-btcutil.Amount(1))},
+		expectedErr: "max fee must not be negative",
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			opts := defaultShipmentOptions()
+			for _, optFunc := range tc.optFuncs {
+				optFunc(opts)
+			}
+
+			err := opts.validate()
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedOpts, opts)
+		})
+	}
+}
+
+// TestAddressParcelValidate tests that a parcel with multiple recipients is
+// validated before it is handed to the porter.
+func TestAddressParcelValidate(t *testing.T) {
+	t.Parallel()
+
+	addr1, _, _ := address.RandAddr(t, &address.RegressionNetTap)
+	addr2, _, _ := address.RandAddr(t, &address.RegressionNetTap)
+	addr3, _, _ := address.RandAddr(t, &address.TestNet3Tap)
+
+	// A second recipient of the same asset as the first one.
+	sameAsset := addr2.Tap.Copy()
+	sameAsset.AssetID = addr1.AssetID
+
+	testCases := []struct {
+		name        string
+		addrs       []*address.Tap
+		expectedErr string
+	}{{
+		name:        "no addresses",
+		expectedErr: "at least one address must be specified",
+	}, {
+		name:  "single address",
+		addrs: []*address.Tap{addr1.Tap},
+	}, {
+		name:  "multiple assets",
+		addrs: []*address.Tap{addr1.Tap, addr2.Tap},
+	}, {
+		name:  "multiple recipients of same asset",
+		addrs: []*address.Tap{addr1.Tap, sameAsset, addr2.Tap},
+	}, {
+		name:        "mixed networks",
+		addrs:       []*address.Tap{addr1.Tap, addr3.Tap},
+		expectedErr: "different network",
+	}, {
+		name:        "duplicate recipient",
+		addrs:       []*address.Tap{addr1.Tap, addr2.Tap, addr1.Tap},
+		expectedErr: "address 2 is a duplicate recipient",
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			parcel := NewAddressParcel(
+				DefaultSelectStrategy, tc.addrs...,
+			)
+
+			err := parcel.validate()
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+// TestBatchedParcels tests that queued parcels are only batched with parcels
+// that use the same options, and that the merged parcel delivers its response
+// and errors to all of them.
+func TestBatchedParcels(t *testing.T) {
+	t.Parallel()
+
+	addr1, _, _ := address.RandAddr(t, &address.RegressionNetTap)
+	addr2, _, _ := address.RandAddr(t, &address.RegressionNetTap)
+	addr3, _, _ := address.RandAddr(t, &address.RegressionNetTap)
+
+	newParcel := func(strategy MultiCommitmentSelectStrategy,
+		optFuncs []ShipmentOption,
+		addrs ...*address.Tap) *AddressParcel {
+
+		parcel := NewAddressParcel(strategy, addrs...)
+		parcel.opts = defaultShipmentOptions()
+		for _, optFunc := range optFuncs {
+			optFunc(parcel.opts)
+		}
+
+		return parcel
+	}
+
+	parcel1 := newParcel(DefaultSelectStrategy, nil, addr1.Tap)
+	parcel2 := newParcel(
+		DefaultSelectStrategy, nil, addr2.Tap, addr3.Tap,
+	)
+
+	// Parcels with the same options share a batch key, while different
+	// options or a max fee prevent them from being batched together.
+	key1, ok := batchKey(parcel1)
+	require.True(t, ok)
+	key2, ok := batchKey(parcel2)
+	require.True(t, ok)
+	require.Equal(t, key1, key2)
+
+	otherStrategy := newParcel(PreferRandom, nil, addr1.Tap)
+	otherKey, ok := batchKey(otherStrategy)
+	require.True(t, ok)
+	require.NotEqual(t, key1, otherKey)
+
+	otherFeeRate := newParcel(
+		DefaultSelectStrategy, []ShipmentOption{
+			WithFeeRate(chainfee.FeePerKwFloor),
+		}, addr1.Tap,
+	)
+	otherKey, ok = batchKey(otherFeeRate)
+	require.True(t, ok)
+	require.NotEqual(t, key1, otherKey)
+
+	withMaxFee := newParcel(
+		DefaultSelectStrategy, []ShipmentOption{WithMaxFee(1000)},
+		addr1.Tap,
+	)
+	_, ok = batchKey(withMaxFee)
+	require.False(t, ok)
+
+	externalSign := newParcel(DefaultSelectStrategy, nil, addr1.Tap)
+	externalSign.opts.ExternalAnchorSign = true
+	_, ok = batchKey(externalSign)
+	require.False(t, ok)
+
+	withEdition := newParcel(
+		DefaultSelectStrategy, []ShipmentOption{WithEdition(2)},
+		addr1.Tap,
+	)
+	_, ok = batchKey(withEdition)
+	require.False(t, ok)
+
+	_, ok = batchKey(NewBurnParcel(&tapscript.FundingDescriptor{
+		Amount: 1,
+	}))
+	require.False(t, ok)
+
+	// The merged parcel pays all recipients of the queued parcels.
+	merged := mergeAddressParcels(parcel1, parcel2)
+	require.NoError(t, merged.validate())
+	require.Equal(t, parcel1.opts, merged.opts)
+	require.Equal(
+		t, []*address.Tap{addr1.Tap, addr2.Tap, addr3.Tap},
+		merged.destAddrs,
+	)
+
+	// A response of the merged parcel is delivered to each of the queued
+	// parcels.
+	resp := &OutboundParcel{}
+	merged.kit().deliverResp(resp)
+	require.Same(t, resp, <-parcel1.respChan)
+	require.Same(t, resp, <-parcel2.respChan)
+
+	errShipment := errors.New("shipment failed")
+	merged.kit().deliverErr(errShipment)
+	require.ErrorIs(t, <-parcel1.errChan, errShipment)
+	require.ErrorIs(t, <-parcel2.errChan, errShipment)
+
+	// Queued parcels that pay the same recipient can't be merged.
+	duplicate := newParcel(DefaultSelectStrategy, nil, addr1.Tap)
+	merged = mergeAddressParcels(parcel1, duplicate)
+	require.ErrorContains(t, merged.validate(), "duplicate recipient")
+}
+
+// fundingWallet is a Wallet that records the funding descriptors it is asked
+// to fund packets for.
+type fundingWallet struct {
+	Wallet
+
+	addrSends int
+	fundDescs []*tapscript.FundingDescriptor
+}
+
+func (w *fundingWallet) FundAddressSend(context.Context,
+	MultiCommitmentSelectStrategy, ...*address.Tap) (*FundedVPacket,
+	error) {
+
+	w.addrSends++
+	return &FundedVPacket{}, nil
+}
+
+func (w *fundingWallet) FundPacket(_ context.Context,
+	fundDesc *tapscript.FundingDescriptor, _ MultiCommitmentSelectStrategy,
+	vPkt *tappsbt.VPacket) (*FundedVPacket, error) {
+
+	w.fundDescs = append(w.fundDescs, fundDesc)
+	return &FundedVPacket{VPacket: vPkt}, nil
+}
+
+// TestFundAddressParcelEdition tests that the edition of a shipment is passed
+// to coin selection, and is only accepted for a send to a single address.
+func TestFundAddressParcelEdition(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	addr1, _, _ := address.RandAddr(t, &address.RegressionNetTap)
+	addr2, _, _ := address.RandAddr(t, &address.RegressionNetTap)
+
+	wallet := &fundingWallet{}
+	porter := &ChainPorter{
+		cfg: &ChainPorterConfig{
+			AssetWallet: wallet,
+		},
+	}
+
+	// Without an edition, a send of a single asset is funded by the
+	// wallet directly.
+	_, err := porter.fundAddressParcel(
+		ctx, DefaultSelectStrategy, 0, []*address.Tap{addr1.Tap},
+	)
+	require.NoError(t, err)
+	require.Equal(t, 1, wallet.addrSends)
+	require.Empty(t, wallet.fundDescs)
+
+	// With an edition, only that edition may be selected.
+	fundedPkts, err := porter.fundAddressParcel(
+		ctx, DefaultSelectStrategy, 7, []*address.Tap{addr1.Tap},
+	)
+	require.NoError(t, err)
+	require.Equal(t, 1, wallet.addrSends)
+	require.Len(t, wallet.fundDescs, 1)
+	require.EqualValues(t, 7, wallet.fundDescs[0].Edition)
+	require.Equal(t, addr1.AssetID, wallet.fundDescs[0].ID)
+	require.Equal(t, addr1.Amount, wallet.fundDescs[0].Amount)
+
+	// The packet has the change output first, followed by the recipient.
+	require.Len(t, fundedPkts, 1)
+	vPkt := fundedPkts[0].VPacket
+	require.Len(t, vPkt.Outputs, 2)
+	require.EqualValues(t, 0, vPkt.Outputs[0].AnchorOutputIndex)
+	require.EqualValues(t, 1, vPkt.Outputs[1].AnchorOutputIndex)
+
+	// An edition can't be sent to multiple addresses.
+	_, err = porter.fundAddressParcel(
+		ctx, DefaultSelectStrategy, 7,
+		[]*address.Tap{addr1.Tap, addr2.Tap},
+	)
+	require.ErrorContains(t, err, "single address")
+}
+
+// TestTransferStageEvent tests that transfer stage events contain the anchor
+// transaction information known at the time of the event.
+func TestTransferStageEvent(t *testing.T) {
+	t.Parallel()
+
+	// Only the states that complete a transfer stage create an event.
+	stage, ok := completedTransferStage(SendStateAnchorSign)
+	require.True(t, ok)
+	require.Equal(t, TransferStageAnchorFunded, stage)
+
+	_, ok = completedTransferStage(SendStateLogCommit)
+	require.False(t, ok)
+
+	// Before the anchor transaction is funded, only the output indexes
+	// are known.
+	pkg := &sendPackage{
+		VirtualPackets: []*tappsbt.VPacket{{
+			Outputs: []*tappsbt.VOutput{{
+				AnchorOutputIndex: 2,
+			}, {
+				AnchorOutputIndex: 0,
+			}},
+		}, {
+			Outputs: []*tappsbt.VOutput{{
+				AnchorOutputIndex: 2,
+			}},
+		}},
+	}
+	event := NewTransferStageEvent(TransferStageCoinsSelected, pkg)
+	require.Equal(t, TransferStageCoinsSelected, event.Stage)
+	require.Nil(t, event.AnchorTxHash)
+	require.Equal(t, []uint32{0, 2}, event.AnchorOutputIndexes)
+	require.Zero(t, event.BlockHeight)
+
+	// Once funded, the anchor transaction hash is known as well.
+	anchorTx := wire.NewMsgTx(2)
+	anchorTx.AddTxOut(&wire.TxOut{Value: 1000})
+	pkg.AnchorTx = &AnchorTransaction{
+		FinalTx: anchorTx,
+	}
+	txHash := anchorTx.TxHash()
+
+	event = NewTransferStageEvent(TransferStageAnchorFunded, pkg)
+	require.Equal(t, &txHash, event.AnchorTxHash)
+
+	// After the transfer was logged, the outbound parcel is used, which
+	// is also the case for resumed transfers without virtual packets.
+	pkg = &sendPackage{
+		OutboundPkg: &OutboundParcel{
+			AnchorTx: anchorTx,
+			Outputs: []TransferOutput{{
+				Anchor: Anchor{
+					OutPoint: wire.OutPoint{Index: 1},
+				},
+			}, {
+				Anchor: Anchor{
+					OutPoint: wire.OutPoint{Index: 0},
+				},
+			}},
+		},
+		TransferTxConfEvent: &chainntnfs.TxConfirmation{
+			BlockHeight: 123,
+		},
+	}
+	event = NewTransferStageEvent(TransferStageConfirmed, pkg)
+	require.Equal(t, &txHash, event.AnchorTxHash)
+	require.Equal(t, []uint32{0, 1}, event.AnchorOutputIndexes)
+	require.EqualValues(t, 123, event.BlockHeight)
+}
+
+// TestVerifyPassiveAssets tests that a package is only considered safe to
+// broadcast if all passive assets of the spent anchor outputs are re-anchored.
+func TestVerifyPassiveAssets(t *testing.T) {
+	t.Parallel()
+
+	activeAsset := asset.RandAsset(t, asset.Normal)
+	passiveAsset := asset.RandAsset(t, asset.Normal)
+
+	anchorPoint := wire.OutPoint{Index: 1}
+	vPkt := &tappsbt.VPacket{
+		ChainParams: &address.RegressionNetTap,
+	}
+	vPkt.SetInputAsset(0, activeAsset, nil)
+	vPkt.Inputs[0].PrevID.OutPoint = anchorPoint
+
+	anchorTx := wire.NewMsgTx(2)
+	anchorTx.AddTxOut(&wire.TxOut{Value: 1000})
+
+	newPkg := func(inputAssets ...*asset.Asset) *sendPackage {
+		inputCommitment, err := commitment.FromAssets(inputAssets...)
+		require.NoError(t, err)
+
+		return &sendPackage{
+			VirtualPackets: []*tappsbt.VPacket{vPkt},
+			InputCommitments: []tappsbt.InputCommitments{{
+				0: inputCommitment,
+			}},
+			AnchorTx: &AnchorTransaction{
+				FinalTx: anchorTx,
+			},
+		}
+	}
+
+	// Without any passive assets in the spent anchor output, there is
+	// nothing to re-anchor.
+	require.NoError(t, newPkg(activeAsset).verifyPassiveAssets())
+
+	// A passive asset that isn't re-anchored at all is detected.
+	pkg := newPkg(activeAsset, passiveAsset)
+	err := pkg.verifyPassiveAssets()
+	require.ErrorIs(t, err, ErrStrandedPassiveAsset)
+	require.ErrorContains(t, err, "isn't re-anchored")
+
+	// A re-anchor without a signed virtual packet is rejected.
+	reAnchor := &PassiveAssetReAnchor{
+		GenesisID:       passiveAsset.ID(),
+		PrevAnchorPoint: anchorPoint,
+		ScriptKey:       passiveAsset.ScriptKey,
+	}
+	pkg.PassiveAssets = []*PassiveAssetReAnchor{reAnchor}
+	err = pkg.verifyPassiveAssets()
+	require.ErrorIs(t, err, ErrStrandedPassiveAsset)
+	require.ErrorContains(t, err, "invalid re-anchor virtual packet")
+
+	// The same goes for a signed virtual packet without a proof.
+	reAnchoredAsset := passiveAsset.Copy()
+	reAnchoredAsset.PrevWitnesses = []asset.Witness{{
+		TxWitness: [][]byte{{0x01}},
+	}}
+	reAnchor.VPacket = &tappsbt.VPacket{
+		Inputs: []*tappsbt.VInput{{}},
+		Outputs: []*tappsbt.VOutput{{
+			Asset: reAnchoredAsset,
+		}},
+	}
+	err = pkg.verifyPassiveAssets()
+	require.ErrorIs(t, err, ErrStrandedPassiveAsset)
+	require.ErrorContains(t, err, "re-anchor proof missing")
+
+	// A proof for a different anchor transaction doesn't help either.
+	reAnchor.NewProof = &proof.Proof{
+		AnchorTx: *wire.NewMsgTx(2),
+		Asset:    *reAnchoredAsset,
+	}
+	err = pkg.verifyPassiveAssets()
+	require.ErrorIs(t, err, ErrStrandedPassiveAsset)
+	require.ErrorContains(t, err, "different anchor transaction")
+}
+
+// TestProofDeliveryStatus tests that only the proofs of outputs going to a
+// remote receiver are queued for delivery.
+func TestProofDeliveryStatus(t *testing.T) {
+	t.Parallel()
+
+	courierAddr, err := proof.ParseCourierAddrString(
+		"universerpc://localhost:10029",
+	)
+	require.NoError(t, err)
+
+	porter := NewChainPorter(&ChainPorterConfig{})
+	newOut := func() *TransferOutput {
+		randAsset := asset.RandAsset(t, asset.Normal)
+		return &TransferOutput{
+			ScriptKey:        randAsset.ScriptKey,
+			Type:             tappsbt.TypeSimple,
+			ProofCourierAddr: courierAddr,
+		}
+	}
+
+	// Without a dispatcher, no proofs are delivered.
+	out := newOut()
+	require.Equal(
+		t, ProofDeliveryNotRequired, porter.proofDeliveryStatus(out),
+	)
+
+	porter.cfg.ProofCourierDispatcher = proof.NewURLDispatch(
+		&proof.CourierCfg{},
+	)
+	require.Equal(t, ProofDeliveryPending, porter.proofDeliveryStatus(out))
+
+	// Local outputs and passive asset outputs don't need a delivery.
+	out.ScriptKeyLocal = true
+	require.Equal(
+		t, ProofDeliveryNotRequired, porter.proofDeliveryStatus(out),
+	)
+
+	out = newOut()
+	out.Type = tappsbt.TypePassiveAssetsOnly
+	require.Equal(
+		t, ProofDeliveryNotRequired, porter.proofDeliveryStatus(out),
+	)
+
+	// An output without a courier address hint falls back to the default
+	// courier, if there is one.
+	out = newOut()
+	out.ProofCourierAddr = nil
+	require.Equal(
+		t, ProofDeliveryNotRequired, porter.proofDeliveryStatus(out),
+	)
+
+	porter.cfg.DefaultProofCourierAddr = courierAddr
+	require.Equal(t, ProofDeliveryPending, porter.proofDeliveryStatus(out))
+}
+
 func init() {
 	rand.Seed(time.Now().Unix())
 
@@ -20,3 +541,53 @@ func init() {
 	logWriter.RegisterSubLogger(Subsystem, logger)
 	UseLogger(logger)
 }
+
+// TestFinalizeAnchorPsbt tests that an externally signed anchor PSBT is only
+// accepted if all its inputs are spent with a valid witness.
+func TestFinalizeAnchorPsbt(t *testing.T) {
+	t.Parallel()
+
+	privKey := test.RandPrivKey(t)
+	outputKey := txscript.ComputeTaprootKeyNoScript(privKey.PubKey())
+	pkScript, err := txscript.PayToTaprootScript(outputKey)
+	require.NoError(t, err)
+
+	utxo := &wire.TxOut{
+		Value:    100_000,
+		PkScript: pkScript,
+	}
+	newSignedPsbt := func(signKey *btcec.PrivateKey) *psbt.Packet {
+		tx := wire.NewMsgTx(2)
+		tx.AddTxIn(&wire.TxIn{
+			PreviousOutPoint: test.RandOp(t),
+		})
+		tx.AddTxOut(&wire.TxOut{
+			Value:    utxo.Value - 1_000,
+			PkScript: pkScript,
+		})
+
+		pkt, err := psbt.NewFromUnsignedTx(tx)
+		require.NoError(t, err)
+		pkt.Inputs[0].WitnessUtxo = utxo
+
+		prevOutFetcher := txscript.NewCannedPrevOutputFetcher(
+			utxo.PkScript, utxo.Value,
+		)
+		sig, err := txscript.RawTxInTaprootSignature(
+			tx, txscript.NewTxSigHashes(tx, prevOutFetcher), 0,
+			utxo.Value, utxo.PkScript, nil,
+			txscript.SigHashDefault, signKey,
+		)
+		require.NoError(t, err)
+		pkt.Inputs[0].TaprootKeySpendSig = sig
+
+		return pkt
+	}
+
+	finalTx, err := finalizeAnchorPsbt(newSignedPsbt(privKey))
+	require.NoError(t, err)
+	require.Len(t, finalTx.TxIn[0].Witness, 1)
+
+	_, err = finalizeAnchorPsbt(newSignedPsbt(test.RandPrivKey(t)))
+	require.ErrorContains(t, err, "invalid witness for input 0")
+}
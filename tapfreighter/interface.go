@@ -2,7 +2,9 @@ package tapfreighter
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/url"
 	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
@@ -39,6 +41,23 @@ type CommitmentConstraints struct {
 	// MinAmt is the minimum amount that an asset commitment needs to hold
 	// to satisfy the constraints.
 	MinAmt uint64
+
+	// Edition is the edition number of a collectible that needs to be
+	// selected. This is an optional field, if zero then any edition (or
+	// an asset without an edition) satisfies the constraints.
+	Edition uint64
+
+	// MaxAnchorHeight is the maximum height of the block the anchor
+	// transaction of a commitment was confirmed in. This is an optional
+	// field, if zero then commitments of any confirmation depth satisfy
+	// the constraints.
+	MaxAnchorHeight uint32
+
+	// IncludeLeased indicates whether commitments that are anchored in a
+	// currently leased UTXO should be returned as well. By default, leased
+	// commitments are excluded, as they are reserved for a pending
+	// transfer.
+	IncludeLeased bool
 }
 
 // AnchoredCommitment is the response to satisfying the set of
@@ -76,6 +95,15 @@ var (
 	// constraints.
 	ErrMatchingAssetsNotFound = fmt.Errorf("failed to find coin(s) which" +
 		"satisfy given constraints")
+
+	// ErrMaxFeeExceeded is returned when the funded anchor transaction of
+	// a shipment would pay more chain fees than the requested maximum.
+	ErrMaxFeeExceeded = errors.New("anchor transaction exceeds max fee")
+
+	// ErrStrandedPassiveAsset is returned when the anchor transaction of a
+	// shipment would spend a passive asset without correctly re-anchoring
+	// it.
+	ErrStrandedPassiveAsset = errors.New("passive asset would be stranded")
 )
 
 // CoinLister attracts over the coin selection process needed to be
@@ -89,6 +117,21 @@ type CoinLister interface {
 	// should be returned.
 	ListEligibleCoins(context.Context,
 		CommitmentConstraints) ([]*AnchoredCommitment, error)
+
+	// LeaseCoins leases (locks) the UTXOs identified by the given outpoints
+	// for the given lease owner until the given expiry. Commitments
+	// anchored in a leased UTXO are excluded from coin selection until the
+	// lease is released or has expired.
+	LeaseCoins(ctx context.Context, leaseOwner [32]byte, expiry time.Time,
+		utxoOutpoints ...wire.OutPoint) error
+
+	// ReleaseCoins releases the leases of the UTXOs identified by the given
+	// outpoints, making the commitments anchored in them available for
+	// coin selection again.
+	ReleaseCoins(ctx context.Context, utxoOutpoints ...wire.OutPoint) error
+
+	// DeleteExpiredLeases deletes all leases that have expired.
+	DeleteExpiredLeases(ctx context.Context) error
 }
 
 // MultiCommitmentSelectStrategy is an enum that describes the strategy that
@@ -96,12 +139,82 @@ type CoinLister interface {
 type MultiCommitmentSelectStrategy uint8
 
 const (
+	// DefaultSelectStrategy indicates that no specific strategy was
+	// requested and the default strategy of the coin selector should be
+	// used.
+	DefaultSelectStrategy MultiCommitmentSelectStrategy = iota
+
 	// PreferMaxAmount is a strategy which considers commitments in order of
 	// descending amounts and selects the first subset which cumulatively
-	// sums to at least the minimum target amount.
-	PreferMaxAmount MultiCommitmentSelectStrategy = iota
+	// sums to at least the minimum target amount. This minimizes the number
+	// of inputs used.
+	PreferMaxAmount
+
+	// PreferMinAmount is a strategy which considers commitments in order of
+	// ascending amounts and selects the first subset which cumulatively
+	// sums to at least the minimum target amount. This consolidates small
+	// commitments (dust) over time.
+	PreferMinAmount
+
+	// PreferSingleCoin is a strategy which selects the smallest single
+	// commitment that covers the minimum target amount on its own. If no
+	// such commitment exists, it falls back to PreferMaxAmount.
+	PreferSingleCoin
+
+	// PreferRandom is a strategy which considers commitments in a random
+	// order and selects the first subset which cumulatively sums to at
+	// least the minimum target amount. This avoids leaking information
+	// about the wallet's coins through a deterministic selection.
+	PreferRandom
 )
 
+// String returns a human-readable string for the strategy.
+func (s MultiCommitmentSelectStrategy) String() string {
+	switch s {
+	case DefaultSelectStrategy:
+		return "default"
+
+	case PreferMaxAmount:
+		return "max-amount"
+
+	case PreferMinAmount:
+		return "min-amount"
+
+	case PreferSingleCoin:
+		return "single-coin"
+
+	case PreferRandom:
+		return "random"
+
+	default:
+		return fmt.Sprintf("<unknown_strategy(%d)>", s)
+	}
+}
+
+// ParseSelectStrategy parses the given string into a coin selection strategy.
+// An empty string maps to DefaultSelectStrategy.
+func ParseSelectStrategy(s string) (MultiCommitmentSelectStrategy, error) {
+	switch s {
+	case "", DefaultSelectStrategy.String():
+		return DefaultSelectStrategy, nil
+
+	case PreferMaxAmount.String():
+		return PreferMaxAmount, nil
+
+	case PreferMinAmount.String():
+		return PreferMinAmount, nil
+
+	case PreferSingleCoin.String():
+		return PreferSingleCoin, nil
+
+	case PreferRandom.String():
+		return PreferRandom, nil
+
+	default:
+		return 0, fmt.Errorf("unknown coin selection strategy: %v", s)
+	}
+}
+
 // CoinSelector is an interface that describes the functionality used in
 // selecting coins during the asset send process.
 type CoinSelector interface {
@@ -151,11 +264,52 @@ type Anchor struct {
 	// of the Taproot Asset commitment.
 	TapscriptSibling []byte
 
+	// AltLeaves is the set of alt leaves committed to in the Taproot Asset
+	// commitment of the anchor output.
+	AltLeaves []*commitment.AltLeaf
+
 	// NumPassiveAssets is the number of passive assets in the commitment
 	// for this anchor output.
 	NumPassiveAssets uint32
 }
 
+// ProofDeliveryStatus is the status of the delivery of a transfer output's
+// proof to the receiver of the output.
+type ProofDeliveryStatus uint8
+
+const (
+	// ProofDeliveryNotRequired indicates that the proof of the output
+	// doesn't need to be delivered, for example because the output goes to
+	// our own wallet, it is a burn or no proof courier is configured.
+	ProofDeliveryNotRequired ProofDeliveryStatus = 0
+
+	// ProofDeliveryPending indicates that the proof of the output still
+	// needs to be delivered to the receiver through a proof courier.
+	ProofDeliveryPending ProofDeliveryStatus = 1
+
+	// ProofDeliveryComplete indicates that the proof of the output was
+	// successfully delivered to the receiver.
+	ProofDeliveryComplete ProofDeliveryStatus = 2
+)
+
+// String returns a human-readable string representation of the proof delivery
+// status.
+func (s ProofDeliveryStatus) String() string {
+	switch s {
+	case ProofDeliveryNotRequired:
+		return "not_required"
+
+	case ProofDeliveryPending:
+		return "pending"
+
+	case ProofDeliveryComplete:
+		return "complete"
+
+	default:
+		return fmt.Sprintf("unknown <%d>", s)
+	}
+}
+
 // TransferOutput represents the database level output to an asset transfer.
 type TransferOutput struct {
 	// Anchor is the new location of the Taproot Asset commitment referenced
@@ -190,6 +344,15 @@ type TransferOutput struct {
 	// includes all the proof information other than the final chain
 	// information.
 	ProofSuffix []byte
+
+	// ProofCourierAddr is the address of the proof courier that should be
+	// used to deliver the proof of this output to the receiver. If this is
+	// nil, the default proof courier is used.
+	ProofCourierAddr *url.URL
+
+	// ProofDeliveryStatus is the status of the delivery of this output's
+	// proof to the receiver.
+	ProofDeliveryStatus ProofDeliveryStatus
 }
 
 // OutboundParcel represents the database level delta of an outbound Taproot
@@ -279,6 +442,23 @@ type PassiveAssetReAnchor struct {
 	NewWitnessData []asset.Witness
 }
 
+// AssetBurn is a record of an asset burn, an output of an outbound parcel that
+// assigned a provably un-spendable burn script key to a number of asset units.
+type AssetBurn struct {
+	// AnchorTxid is the TXID of the anchor transaction of the transfer
+	// that contains the burn.
+	AnchorTxid chainhash.Hash
+
+	// AssetID is the ID of the burnt asset.
+	AssetID asset.ID
+
+	// ScriptKey is the burn script key the asset units were assigned to.
+	ScriptKey *btcec.PublicKey
+
+	// Amount is the number of asset units that were burned.
+	Amount uint64
+}
+
 // ExportLog is used to track the state of outbound Taproot Asset parcels
 // (batched spends). This log is used by the ChainPorter to mark pending
 // outbound deliveries, and finally confirm the deliveries once they've been
@@ -286,8 +466,12 @@ type PassiveAssetReAnchor struct {
 type ExportLog interface {
 	// LogPendingParcel marks an outbound parcel as pending on disk. This
 	// commits the set of changes to disk (the asset deltas) but doesn't
-	// mark the batched spend as being finalized.
-	LogPendingParcel(context.Context, *OutboundParcel) error
+	// mark the batched spend as being finalized. The given lease owner and
+	// expiry are set on the UTXOs spent by the parcel, which keeps the
+	// assets they anchor from being selected again until the parcel is
+	// confirmed.
+	LogPendingParcel(context.Context, *OutboundParcel, [32]byte,
+		time.Time) error
 
 	// PendingParcels returns the set of parcels that haven't yet been
 	// finalized. This can be used to query the set of unconfirmed
@@ -298,6 +482,20 @@ type ExportLog interface {
 	// updates the on-chain reference information on disk to point to this
 	// new spend.
 	ConfirmParcelDelivery(context.Context, *AssetConfirmEvent) error
+
+	// QueryBurns returns the asset burns that were logged as part of an
+	// outbound parcel, optionally filtered by asset ID.
+	QueryBurns(context.Context, *asset.ID) ([]*AssetBurn, error)
+
+	// PendingProofDeliveries returns the set of confirmed parcels that
+	// still have at least one output with a pending proof delivery. This
+	// is the persisted retry queue of the proof courier.
+	PendingProofDeliveries(context.Context) ([]*OutboundParcel, error)
+
+	// ConfirmProofDelivery marks the proof of the transfer output with the
+	// given anchor outpoint and script key as delivered to the receiver.
+	ConfirmProofDelivery(context.Context, wire.OutPoint,
+		*btcec.PublicKey) error
 }
 
 // ChainBridge aliases into the ChainBridge of the tapgarden package.
@@ -323,8 +521,20 @@ type Signer = tapscript.Signer
 type Porter interface {
 	// RequestShipment attempts to request that a new send be funneled
 	// through the chain porter. If successful, an initial response will be
-	// returned with the pending transfer information.
-	RequestShipment(req Parcel) (*OutboundParcel, error)
+	// returned with the pending transfer information. The given options
+	// control how the anchor transaction of the shipment is funded.
+	RequestShipment(req Parcel,
+		optFuncs ...ShipmentOption) (*OutboundParcel, error)
+
+	// RequestAnchorPsbt requests a new transfer just like RequestShipment,
+	// but returns the funded anchor PSBT to be signed by an external
+	// wallet instead of signing and broadcasting it.
+	RequestAnchorPsbt(req Parcel,
+		optFuncs ...ShipmentOption) (*psbt.Packet, error)
+
+	// PublishAnchorPsbt resumes a transfer requested through
+	// RequestAnchorPsbt with the given externally signed anchor PSBT.
+	PublishAnchorPsbt(signedPsbt *psbt.Packet) (*OutboundParcel, error)
 
 	// Start signals that the asset minter should being operations.
 	Start() error
@@ -7,16 +7,22 @@ import (
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btclog"
 	"github.com/lightninglabs/taproot-assets/address"
 	"github.com/lightninglabs/taproot-assets/asset"
 	"github.com/lightninglabs/taproot-assets/chanutils"
 	"github.com/lightninglabs/taproot-assets/commitment"
+	"github.com/lightninglabs/taproot-assets/monitoring"
 	"github.com/lightninglabs/taproot-assets/mssmt"
 	"github.com/lightninglabs/taproot-assets/proof"
 	"github.com/lightninglabs/taproot-assets/tappsbt"
+	"github.com/lightninglabs/taproot-assets/tapscript"
 	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 )
 
 // SendState is an enum that describes the current state of a pending outbound
@@ -37,6 +43,12 @@ const (
 	// then finalize to place the necessary signatures in the transaction.
 	SendStateAnchorSign
 
+	// SendStateWaitAnchorSign is the state in which the funded anchor
+	// transaction was handed out to be signed by an external wallet. The
+	// parcel stays in memory in this state until the signed transaction
+	// is handed back.
+	SendStateWaitAnchorSign
+
 	// SendStateLogCommit is the final in memory state. In this state,
 	// we'll extract the signed transaction from the PSBT and log the
 	// transfer information to disk. At this point, after a restart, the
@@ -77,6 +89,9 @@ func (s SendState) String() string {
 	case SendStateAnchorSign:
 		return "SendStateAnchorSign"
 
+	case SendStateWaitAnchorSign:
+		return "SendStateWaitAnchorSign"
+
 	case SendStateLogCommit:
 		return "SendStateLogCommit"
 
@@ -107,6 +122,9 @@ type Parcel interface {
 
 	// kit returns the parcel kit used for delivery.
 	kit() *parcelKit
+
+	// validate validates the parcel before it is handed to the porter.
+	validate() error
 }
 
 // parcelKit is a struct that contains the channels that are used to deliver
@@ -117,6 +135,153 @@ type parcelKit struct {
 
 	// errChan is the channel the error will be sent over.
 	errChan chan error
+
+	// opts are the options the shipment of the parcel was requested with.
+	opts *ShipmentOptions
+
+	// anchorPsbtChan is the channel the funded anchor PSBT is sent over
+	// if the anchor transaction is signed by an external wallet.
+	anchorPsbtChan chan *psbt.Packet
+
+	// batched are the kits of the parcels that were merged into this
+	// parcel to be shipped in a single anchor transaction. If set, the
+	// response or error is delivered to each of them instead.
+	batched []*parcelKit
+}
+
+// deliverResp delivers the response for the parcel.
+func (k *parcelKit) deliverResp(resp *OutboundParcel) {
+	if len(k.batched) == 0 {
+		k.respChan <- resp
+		return
+	}
+
+	for _, batchedKit := range k.batched {
+		batchedKit.deliverResp(resp)
+	}
+}
+
+// deliverAnchorPsbt delivers the funded anchor PSBT of the parcel that needs to
+// be signed by an external wallet. Parcels with an externally signed anchor
+// transaction are never batched.
+func (k *parcelKit) deliverAnchorPsbt(anchorPsbt *psbt.Packet) {
+	k.anchorPsbtChan <- anchorPsbt
+}
+
+// deliverErr delivers the error that occurred while shipping the parcel.
+func (k *parcelKit) deliverErr(err error) {
+	if len(k.batched) == 0 {
+		k.errChan <- err
+		return
+	}
+
+	for _, batchedKit := range k.batched {
+		batchedKit.deliverErr(err)
+	}
+}
+
+// ShipmentOptions is a set of options that control how the anchor transaction
+// of a shipment is funded.
+type ShipmentOptions struct {
+	// FeeRate is the fee rate the anchor transaction is funded with. If
+	// nil, the fee rate is estimated using the confirmation target.
+	FeeRate *chainfee.SatPerKWeight
+
+	// ConfTarget is the confirmation target used to estimate the fee rate
+	// of the anchor transaction if no explicit fee rate is set.
+	ConfTarget uint32
+
+	// MaxFee is the maximum amount of chain fees the anchor transaction is
+	// allowed to pay. If zero, the chain fees aren't capped.
+	MaxFee btcutil.Amount
+
+	// ForceStrandPassiveAssets skips the check that every passive asset in
+	// the anchor outputs spent by the shipment is correctly re-anchored
+	// before the anchor transaction is broadcast. Any passive asset that
+	// isn't re-anchored is lost once the anchor transaction confirms.
+	ForceStrandPassiveAssets bool
+
+	// ExternalAnchorSign indicates that the anchor transaction is signed
+	// by an external wallet instead of the connected lnd node. The funded
+	// anchor PSBT is handed out instead of being signed and broadcast.
+	ExternalAnchorSign bool
+
+	// Edition is the edition number of the collectible that is sent to
+	// the single address of the shipment. If zero, coin selection may
+	// pick any edition.
+	Edition uint64
+}
+
+// defaultShipmentOptions returns the set of default options for a shipment.
+func defaultShipmentOptions() *ShipmentOptions {
+	return &ShipmentOptions{
+		ConfTarget: tapscript.SendConfTarget,
+	}
+}
+
+// validate makes sure the shipment options are sane.
+func (o *ShipmentOptions) validate() error {
+	if o.FeeRate != nil && *o.FeeRate < chainfee.FeePerKwFloor {
+		return fmt.Errorf("fee rate %v below floor of %v", *o.FeeRate,
+			chainfee.FeePerKwFloor)
+	}
+
+	if o.ConfTarget == 0 {
+		return fmt.Errorf("conf target must be positive")
+	}
+
+	if o.MaxFee < 0 {
+		return fmt.Errorf("max fee must not be negative")
+	}
+
+	return nil
+}
+
+// ShipmentOption is a functional option that allows a caller to modify how the
+// anchor transaction of a shipment is funded.
+type ShipmentOption func(*ShipmentOptions)
+
+// WithFeeRate sets an explicit fee rate for the anchor transaction, instead of
+// estimating one.
+func WithFeeRate(feeRate chainfee.SatPerKWeight) ShipmentOption {
+	return func(o *ShipmentOptions) {
+		o.FeeRate = &feeRate
+	}
+}
+
+// WithConfTarget sets the confirmation target that is used to estimate the fee
+// rate of the anchor transaction.
+func WithConfTarget(confTarget uint32) ShipmentOption {
+	return func(o *ShipmentOptions) {
+		o.ConfTarget = confTarget
+	}
+}
+
+// WithMaxFee caps the chain fees the anchor transaction is allowed to pay. The
+// shipment fails before the anchor transaction is broadcast if the cap would be
+// exceeded.
+func WithMaxFee(maxFee btcutil.Amount) ShipmentOption {
+	return func(o *ShipmentOptions) {
+		o.MaxFee = maxFee
+	}
+}
+
+// WithForceStrandPassiveAssets allows the anchor transaction of the shipment to
+// be broadcast even if it would strand passive assets that weren't correctly
+// re-anchored. This should only be used to recover from a situation where the
+// passive assets can't be re-anchored and are given up on purpose.
+func WithForceStrandPassiveAssets() ShipmentOption {
+	return func(o *ShipmentOptions) {
+		o.ForceStrandPassiveAssets = true
+	}
+}
+
+// WithEdition selects the edition of a collectible minted as a series of
+// numbered editions that is sent to the single address of the shipment.
+func WithEdition(edition uint64) ShipmentOption {
+	return func(o *ShipmentOptions) {
+		o.Edition = edition
+	}
 }
 
 // AddressParcel is the main request to issue an asset transfer. This packages a
@@ -127,32 +292,44 @@ type AddressParcel struct {
 	// destAddrs is the list of address that should be used to satisfy the
 	// transfer.
 	destAddrs []*address.Tap
+
+	// selectStrategy is the coin selection strategy that should be used
+	// to fund the transfer.
+	selectStrategy MultiCommitmentSelectStrategy
 }
 
 // A compile-time assertion to ensure AddressParcel implements the parcel
 // interface.
 var _ Parcel = (*AddressParcel)(nil)
 
-// NewAddressParcel creates a new AddressParcel.
-func NewAddressParcel(destAddrs ...*address.Tap) *AddressParcel {
+// NewAddressParcel creates a new AddressParcel that is funded using the given
+// coin selection strategy.
+func NewAddressParcel(selectStrategy MultiCommitmentSelectStrategy,
+	destAddrs ...*address.Tap) *AddressParcel {
+
 	return &AddressParcel{
 		parcelKit: &parcelKit{
-			respChan: make(chan *OutboundParcel, 1),
-			errChan:  make(chan error, 1),
+			respChan:       make(chan *OutboundParcel, 1),
+			errChan:        make(chan error, 1),
+			anchorPsbtChan: make(chan *psbt.Packet, 1),
 		},
-		destAddrs: destAddrs,
+		destAddrs:      destAddrs,
+		selectStrategy: selectStrategy,
 	}
 }
 
 // pkg returns the send package that should be delivered.
 func (p *AddressParcel) pkg() *sendPackage {
-	log.Infof("Received to send request to %d addrs: %v", len(p.destAddrs),
-		p.destAddrs)
-
 	// Initialize a package with the destination address.
-	return &sendPackage{
-		Parcel: p,
+	pkg := &sendPackage{
+		CorrelationID: newParcelCorrelationID(),
+		Parcel:        p,
 	}
+
+	pkg.logger().Infof("Received to send request to %d addrs: %v",
+		len(p.destAddrs), p.destAddrs)
+
+	return pkg
 }
 
 // kit returns the parcel kit used for delivery.
@@ -160,18 +337,133 @@ func (p *AddressParcel) kit() *parcelKit {
 	return p.parcelKit
 }
 
-// PreSignedParcel is a request to issue an asset transfer of a pre-signed
-// parcel. This packages a virtual transaction, the input commitment, and also
-// the response context.
+// mergeAddressParcels merges the given address parcels into a single parcel
+// that pays all their recipients in the same anchor transaction. The response
+// or error of the merged parcel is delivered to each of the given parcels,
+// which are expected to use the same coin selection strategy and shipment
+// options.
+func mergeAddressParcels(parcels ...*AddressParcel) *AddressParcel {
+	merged := NewAddressParcel(parcels[0].selectStrategy)
+	merged.opts = parcels[0].opts
+	for _, parcel := range parcels {
+		merged.destAddrs = append(merged.destAddrs, parcel.destAddrs...)
+		merged.batched = append(merged.batched, parcel.parcelKit)
+	}
+
+	return merged
+}
+
+// validate validates the parcel. All recipient addresses must be for the same
+// network, and each recipient may only be listed once per asset, since all of
+// them are paid in the same anchor transaction.
+func (p *AddressParcel) validate() error {
+	if len(p.destAddrs) == 0 {
+		return fmt.Errorf("at least one address must be specified")
+	}
+
+	type recipient struct {
+		assetID   asset.ID
+		scriptKey asset.SerializedKey
+	}
+	var (
+		network    = p.destAddrs[0].ChainParams
+		recipients = make(map[recipient]struct{}, len(p.destAddrs))
+	)
+	for idx, addr := range p.destAddrs {
+		if addr == nil {
+			return fmt.Errorf("address %d is nil", idx)
+		}
+
+		if addr.ChainParams == nil || network == nil ||
+			addr.ChainParams.TapHRP != network.TapHRP {
+
+			return fmt.Errorf("address %d is for a different "+
+				"network than address 0", idx)
+		}
+
+		r := recipient{
+			assetID:   addr.AssetID,
+			scriptKey: asset.ToSerialized(&addr.ScriptKey),
+		}
+		if _, ok := recipients[r]; ok {
+			return fmt.Errorf("address %d is a duplicate "+
+				"recipient of asset %v", idx, addr.AssetID)
+		}
+		recipients[r] = struct{}{}
+	}
+
+	return nil
+}
+
+// BurnParcel is a request to burn (destroy) a given amount of units of an
+// asset. The burnt units are sent to a provably un-spendable script key, which
+// is derived from the first input of the burn transfer.
+type BurnParcel struct {
+	*parcelKit
+
+	// fundDesc describes the asset and the amount that should be burned.
+	fundDesc *tapscript.FundingDescriptor
+}
+
+// A compile-time assertion to ensure BurnParcel implements the parcel
+// interface.
+var _ Parcel = (*BurnParcel)(nil)
+
+// NewBurnParcel creates a new BurnParcel.
+func NewBurnParcel(fundDesc *tapscript.FundingDescriptor) *BurnParcel {
+	return &BurnParcel{
+		parcelKit: &parcelKit{
+			respChan:       make(chan *OutboundParcel, 1),
+			errChan:        make(chan error, 1),
+			anchorPsbtChan: make(chan *psbt.Packet, 1),
+		},
+		fundDesc: fundDesc,
+	}
+}
+
+// pkg returns the send package that should be delivered.
+func (p *BurnParcel) pkg() *sendPackage {
+	// Initialize a package that starts with coin selection, the burn output
+	// is created once we know which inputs are being spent.
+	pkg := &sendPackage{
+		CorrelationID: newParcelCorrelationID(),
+		Parcel:        p,
+	}
+
+	pkg.logger().Infof("Received request to burn %d units of asset %x",
+		p.fundDesc.Amount, p.fundDesc.ID[:])
+
+	return pkg
+}
+
+// kit returns the parcel kit used for delivery.
+func (p *BurnParcel) kit() *parcelKit {
+	return p.parcelKit
+}
+
+// validate validates the parcel.
+func (p *BurnParcel) validate() error {
+	if p.fundDesc == nil || p.fundDesc.Amount == 0 {
+		return fmt.Errorf("burn amount must be specified")
+	}
+
+	return nil
+}
+
+// PreSignedParcel is a request to issue an asset transfer of one or more
+// pre-signed virtual transactions. This packages the virtual transactions
+// (one for each asset ID), their input commitments, and also the response
+// context. All virtual transactions are anchored in the same BTC level
+// transaction.
 type PreSignedParcel struct {
 	*parcelKit
 
-	// vPkt is the virtual transaction that should be delivered.
-	vPkt *tappsbt.VPacket
+	// vPkts are the virtual transactions that should be delivered.
+	vPkts []*tappsbt.VPacket
 
-	// inputCommitment is the commitment for the input that is being spent
-	// in the virtual transaction.
-	inputCommitment *commitment.TapCommitment
+	// inputCommitments are the commitments for the inputs that are being
+	// spent in the virtual transactions, in the same order as vPkts.
+	inputCommitments []tappsbt.InputCommitments
 }
 
 // A compile-time assertion to ensure AddressParcel implements the parcel
@@ -179,36 +471,40 @@ type PreSignedParcel struct {
 var _ Parcel = (*PreSignedParcel)(nil)
 
 // NewPreSignedParcel creates a new PreSignedParcel.
-//
-// TODO(ffranr): Add support for multiple inputs (commitments).
-func NewPreSignedParcel(vPkt *tappsbt.VPacket,
-	inputCommitment *commitment.TapCommitment) *PreSignedParcel {
+func NewPreSignedParcel(vPkts []*tappsbt.VPacket,
+	inputCommitments []tappsbt.InputCommitments) *PreSignedParcel {
 
 	return &PreSignedParcel{
 		parcelKit: &parcelKit{
-			respChan: make(chan *OutboundParcel, 1),
-			errChan:  make(chan error, 1),
+			respChan:       make(chan *OutboundParcel, 1),
+			errChan:        make(chan error, 1),
+			anchorPsbtChan: make(chan *psbt.Packet, 1),
 		},
-		vPkt:            vPkt,
-		inputCommitment: inputCommitment,
+		vPkts:            vPkts,
+		inputCommitments: inputCommitments,
 	}
 }
 
 // pkg returns the send package that should be delivered.
 func (p *PreSignedParcel) pkg() *sendPackage {
-	log.Infof("New signed delivery request with %d outputs",
-		len(p.vPkt.Outputs))
+	// Initialize a package the signed virtual transactions and input
+	// commitments.
+	pkg := &sendPackage{
+		CorrelationID:    newParcelCorrelationID(),
+		Parcel:           p,
+		SendState:        SendStateAnchorSign,
+		VirtualPackets:   p.vPkts,
+		InputCommitments: p.inputCommitments,
+	}
 
-	// Initialize a package the signed virtual transaction and input
-	// commitment.
-	return &sendPackage{
-		Parcel:        p,
-		SendState:     SendStateAnchorSign,
-		VirtualPacket: p.vPkt,
-		InputCommitments: tappsbt.InputCommitments{
-			0: p.inputCommitment,
-		},
+	var numOutputs int
+	for _, vPkt := range p.vPkts {
+		numOutputs += len(vPkt.Outputs)
 	}
+	pkg.logger().Infof("New signed delivery request with %d virtual "+
+		"packets and %d outputs", len(p.vPkts), numOutputs)
+
+	return pkg
 }
 
 // kit returns the parcel kit used for delivery.
@@ -216,18 +512,41 @@ func (p *PreSignedParcel) kit() *parcelKit {
 	return p.parcelKit
 }
 
+// validate validates the parcel.
+func (p *PreSignedParcel) validate() error {
+	if len(p.vPkts) == 0 {
+		return fmt.Errorf("at least one virtual packet must be " +
+			"specified")
+	}
+
+	if len(p.vPkts) != len(p.inputCommitments) {
+		return fmt.Errorf("expected %d input commitments, got %d",
+			len(p.vPkts), len(p.inputCommitments))
+	}
+
+	return nil
+}
+
 // sendPackage houses the information we need to complete a package transfer.
 type sendPackage struct {
+	// CorrelationID identifies the package in the logs of all the
+	// subsystems involved in delivering it.
+	CorrelationID monitoring.CorrelationID
+
 	// SendState is the current send state of this parcel.
 	SendState SendState
 
-	// VirtualPacket is the virtual packet that we'll use to construct the
-	// virtual asset transition transaction.
-	VirtualPacket *tappsbt.VPacket
+	// VirtualPackets are the virtual packets that we'll use to construct
+	// the virtual asset transition transactions, one for each asset ID
+	// that is transferred. All of them are anchored in the same BTC level
+	// anchor transaction.
+	VirtualPackets []*tappsbt.VPacket
 
-	// InputCommitments is a map from virtual package input index to its
-	// associated Taproot Asset commitment.
-	InputCommitments tappsbt.InputCommitments
+	// InputCommitments is the list of input commitments of each virtual
+	// packet, in the same order as VirtualPackets. Each entry is a map
+	// from virtual package input index to its associated Taproot Asset
+	// commitment.
+	InputCommitments []tappsbt.InputCommitments
 
 	// PassiveAssets is the data used in re-anchoring passive assets.
 	PassiveAssets []*PassiveAssetReAnchor
@@ -253,6 +572,27 @@ type sendPackage struct {
 	TransferTxConfEvent *chainntnfs.TxConfirmation
 }
 
+// newParcelCorrelationID creates a new correlation ID for a send package.
+func newParcelCorrelationID() monitoring.CorrelationID {
+	return monitoring.NewCorrelationID("parcel")
+}
+
+// logger returns a logger that tags every line with the correlation ID of the
+// package.
+func (s *sendPackage) logger() btclog.Logger {
+	return monitoring.CorrelatedLogger(log, s.CorrelationID)
+}
+
+// shipmentOptions returns the options the shipment of the package was requested
+// with.
+func (s *sendPackage) shipmentOptions() *ShipmentOptions {
+	if s.Parcel == nil || s.Parcel.kit().opts == nil {
+		return defaultShipmentOptions()
+	}
+
+	return s.Parcel.kit().opts
+}
+
 // prepareForStorage prepares the send package for storing to the database.
 func (s *sendPackage) prepareForStorage(currentHeight uint32) (*OutboundParcel,
 	error) {
@@ -273,19 +613,41 @@ func (s *sendPackage) prepareForStorage(currentHeight uint32) (*OutboundParcel,
 		passiveAsset.NewWitnessData = signedAsset.PrevWitnesses
 	}
 
-	vPkt := s.VirtualPacket
-	anchorTXID := s.AnchorTx.FinalTx.TxHash()
 	parcel := &OutboundParcel{
 		AnchorTx:           s.AnchorTx.FinalTx,
 		AnchorTxHeightHint: currentHeight,
 		// TODO(bhandras): use clock.Clock instead.
 		TransferTime:  time.Now(),
 		ChainFees:     s.AnchorTx.ChainFees,
-		Inputs:        make([]TransferInput, len(vPkt.Inputs)),
-		Outputs:       make([]TransferOutput, len(vPkt.Outputs)),
 		PassiveAssets: s.PassiveAssets,
 	}
 
+	// The inputs and outputs of all virtual packets are combined into a
+	// single parcel, since they are all anchored in the same transaction.
+	for idx := range s.VirtualPackets {
+		vPkt := s.VirtualPackets[idx]
+
+		inputs, err := s.transferInputs(vPkt)
+		if err != nil {
+			return nil, err
+		}
+		parcel.Inputs = append(parcel.Inputs, inputs...)
+
+		outputs, err := s.transferOutputs(vPkt)
+		if err != nil {
+			return nil, err
+		}
+		parcel.Outputs = append(parcel.Outputs, outputs...)
+	}
+
+	return parcel, nil
+}
+
+// transferInputs creates the transfer inputs for the given virtual packet.
+func (s *sendPackage) transferInputs(
+	vPkt *tappsbt.VPacket) ([]TransferInput, error) {
+
+	inputs := make([]TransferInput, len(vPkt.Inputs))
 	for idx := range vPkt.Inputs {
 		vIn := vPkt.Inputs[idx]
 
@@ -310,7 +672,7 @@ func (s *sendPackage) prepareForStorage(currentHeight uint32) (*OutboundParcel,
 				"outpoint for input %d", idx)
 		}
 
-		parcel.Inputs[idx] = TransferInput{
+		inputs[idx] = TransferInput{
 			PrevID: asset.PrevID{
 				OutPoint: *anchorOutPoint,
 				ID:       vIn.Asset().ID(),
@@ -322,7 +684,17 @@ func (s *sendPackage) prepareForStorage(currentHeight uint32) (*OutboundParcel,
 		}
 	}
 
+	return inputs, nil
+}
+
+// transferOutputs creates the transfer outputs for the given virtual packet,
+// including the proof suffix for each output that carries an asset.
+func (s *sendPackage) transferOutputs(
+	vPkt *tappsbt.VPacket) ([]TransferOutput, error) {
+
+	anchorTXID := s.AnchorTx.FinalTx.TxHash()
 	outputCommitments := s.AnchorTx.OutputCommitments
+	outputs := make([]TransferOutput, len(vPkt.Outputs))
 	for idx := range vPkt.Outputs {
 		vOut := vPkt.Outputs[idx]
 
@@ -360,7 +732,9 @@ func (s *sendPackage) prepareForStorage(currentHeight uint32) (*OutboundParcel,
 		// If there are passive assets, they are always committed to the
 		// output that is marked as the split root.
 		if vOut.Type.CanCarryPassive() {
-			numPassiveAssets = uint32(len(s.PassiveAssets))
+			numPassiveAssets = s.numPassiveAssets(
+				vOut.AnchorOutputIndex,
+			)
 		}
 
 		// Either we have an asset that we commit to or we have an
@@ -376,11 +750,21 @@ func (s *sendPackage) prepareForStorage(currentHeight uint32) (*OutboundParcel,
 		// In any other case we expect an active asset transfer to be
 		// committed to.
 		case vOut.Asset != nil:
-			proofSuffix, err := s.createProofSuffix(idx)
+			proofSuffix, err := s.createProofSuffix(vPkt, idx)
 			if err != nil {
 				return nil, fmt.Errorf("unable to create "+
 					"proof %d: %w", idx, err)
 			}
+
+			// Wallets recognize a burn by the burn key of the
+			// asset in the proof, so we make sure the proof of a
+			// burn output is flagged as such.
+			if vOut.Type == tappsbt.TypeBurn &&
+				!proofSuffix.Asset.IsBurn() {
+
+				return nil, fmt.Errorf("proof %d of burn "+
+					"output doesn't use burn key", idx)
+			}
 			err = proofSuffix.Encode(&proofSuffixBuf)
 			if err != nil {
 				return nil, fmt.Errorf("unable to encode "+
@@ -396,7 +780,7 @@ func (s *sendPackage) prepareForStorage(currentHeight uint32) (*OutboundParcel,
 		}
 
 		txOut := s.AnchorTx.FinalTx.TxOut[vOut.AnchorOutputIndex]
-		parcel.Outputs[idx] = TransferOutput{
+		outputs[idx] = TransferOutput{
 			Anchor: Anchor{
 				OutPoint: wire.OutPoint{
 					Hash:  anchorTXID,
@@ -407,6 +791,7 @@ func (s *sendPackage) prepareForStorage(currentHeight uint32) (*OutboundParcel,
 				TaprootAssetRoot: taprootAssetRoot[:],
 				MerkleRoot:       merkleRoot[:],
 				TapscriptSibling: preimageBytes,
+				AltLeaves:        outCommitment.AltLeaves(),
 				NumPassiveAssets: numPassiveAssets,
 			},
 			Type:                vOut.Type,
@@ -415,40 +800,85 @@ func (s *sendPackage) prepareForStorage(currentHeight uint32) (*OutboundParcel,
 			WitnessData:         witness,
 			SplitCommitmentRoot: splitCommitmentRoot,
 			ProofSuffix:         proofSuffixBuf.Bytes(),
+			ProofCourierAddr:    vOut.ProofDeliveryAddress,
 		}
 	}
 
-	return parcel, nil
+	return outputs, nil
+}
+
+// numPassiveAssets returns the number of passive assets that are re-anchored
+// into the given anchor output.
+func (s *sendPackage) numPassiveAssets(anchorOutputIndex uint32) uint32 {
+	var numPassiveAssets uint32
+	for _, passiveAsset := range s.PassiveAssets {
+		passiveOut := passiveAsset.VPacket.Outputs[0]
+		if passiveOut.AnchorOutputIndex == anchorOutputIndex {
+			numPassiveAssets++
+		}
+	}
+
+	return numPassiveAssets
 }
 
-// createProofSuffix creates the new proof for the given output. This is the
-// final state transition that will be added to the proofs of the receiver. The
-// proof returned will have all the Taproot Asset level proof information, but
-// contains dummy data for the on-chain part.
-func (s *sendPackage) createProofSuffix(outIndex int) (*proof.Proof, error) {
-	inputPrevID := s.VirtualPacket.Inputs[0].PrevID
+// allOutputs returns the virtual outputs of all virtual packets of the send
+// package.
+func (s *sendPackage) allOutputs() []*tappsbt.VOutput {
+	var outputs []*tappsbt.VOutput
+	for idx := range s.VirtualPackets {
+		outputs = append(outputs, s.VirtualPackets[idx].Outputs...)
+	}
+
+	return outputs
+}
 
-	params, err := proofParams(s.AnchorTx, s.VirtualPacket, outIndex)
+// isAnchorOutput returns true if any of the virtual outputs of the send package
+// is committed to the anchor output with the given index.
+func (s *sendPackage) isAnchorOutput(anchorOutputIndex uint32) bool {
+	for _, vOut := range s.allOutputs() {
+		if vOut.AnchorOutputIndex == anchorOutputIndex {
+			return true
+		}
+	}
+
+	return false
+}
+
+// createProofSuffix creates the new proof for the given output of the given
+// virtual packet. This is the final state transition that will be added to the
+// proofs of the receiver. The proof returned will have all the Taproot Asset
+// level proof information, but contains dummy data for the on-chain part.
+func (s *sendPackage) createProofSuffix(vPkt *tappsbt.VPacket,
+	outIndex int) (*proof.Proof, error) {
+
+	inputPrevID := vPkt.Inputs[0].PrevID
+
+	params, err := proofParams(s.AnchorTx, vPkt, outIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	// The anchor outputs of any other virtual packets in the same anchor
+	// transaction need an exclusion proof as well, unless they share the
+	// anchor output with this output.
+	vOut := vPkt.Outputs[outIndex]
+	err = addOtherOutputExclusionProofs(
+		s.allOutputs(), vOut.Asset, params,
+		s.AnchorTx.OutputCommitments,
+		func(_ int, otherOut *tappsbt.VOutput) bool {
+			return otherOut.AnchorOutputIndex ==
+				vOut.AnchorOutputIndex
+		},
+	)
 	if err != nil {
 		return nil, err
 	}
 
 	// We also need to account for any P2TR change outputs.
 	if len(s.AnchorTx.FundedPsbt.Pkt.UnsignedTx.TxOut) > 1 {
-		isAnchor := func(idx uint32) bool {
-			for outIdx := range s.VirtualPacket.Outputs {
-				vOut := s.VirtualPacket.Outputs[outIdx]
-				if vOut.AnchorOutputIndex == idx {
-					return true
-				}
-			}
-
-			return false
-		}
-
 		err := proof.AddExclusionProofs(
 			&params.BaseProofParams, s.AnchorTx.FundedPsbt.Pkt,
-			isAnchor,
+			s.isAnchorOutput,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("error adding exclusion "+
@@ -548,15 +978,15 @@ func proofParams(anchorTx *AnchorTransaction, vPkt *tappsbt.VPacket,
 	splitIndex := splitOut.AnchorOutputIndex
 	splitTapTree := outputCommitments[splitIndex]
 
-	_, splitRootExclusionProof, err := splitRootTree.Proof(
-		splitOut.Asset.TapCommitmentKey(),
-		splitOut.Asset.AssetCommitmentKey(),
+	splitRootExclusionProof, err := proof.CreateTapExclusionProof(
+		splitRootIndex, splitRootOut.AnchorOutputInternalKey,
+		splitRootTree, splitRootOut.AnchorOutputTapscriptSibling,
+		splitOut.Asset,
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	splitRootPreimage := splitRootOut.AnchorOutputTapscriptSibling
 	splitParams := newParams(
 		anchorTx, splitOut.Asset, int(splitIndex),
 		splitOut.AnchorOutputInternalKey, splitTapTree,
@@ -565,14 +995,9 @@ func proofParams(anchorTx *AnchorTransaction, vPkt *tappsbt.VPacket,
 	splitParams.RootOutputIndex = splitRootIndex
 	splitParams.RootInternalKey = splitRootOut.AnchorOutputInternalKey
 	splitParams.RootTaprootAssetTree = splitRootTree
-	splitParams.ExclusionProofs = []proof.TaprootProof{{
-		OutputIndex: splitRootIndex,
-		InternalKey: splitRootOut.AnchorOutputInternalKey,
-		CommitmentProof: &proof.CommitmentProof{
-			Proof:              *splitRootExclusionProof,
-			TapSiblingPreimage: splitRootPreimage,
-		},
-	}}
+	splitParams.ExclusionProofs = []proof.TaprootProof{
+		*splitRootExclusionProof,
+	}
 
 	// Add exclusion proofs for all the other outputs.
 	err = addOtherOutputExclusionProofs(
@@ -613,9 +1038,9 @@ func addOtherOutputExclusionProofs(outputs []*tappsbt.VOutput,
 		outIndex := vOut.AnchorOutputIndex
 		tapTree := outputCommitments[outIndex]
 
-		_, splitExclusionProof, err := tapTree.Proof(
-			asset.TapCommitmentKey(),
-			asset.AssetCommitmentKey(),
+		exclusionProof, err := proof.CreateTapExclusionProof(
+			outIndex, vOut.AnchorOutputInternalKey, tapTree,
+			vOut.AnchorOutputTapscriptSibling, asset,
 		)
 		if err != nil {
 			return err
@@ -627,17 +1052,8 @@ func addOtherOutputExclusionProofs(outputs []*tappsbt.VOutput,
 			chanutils.ByteSlice(tapTree.TapscriptRoot(nil)),
 			vOut.AnchorOutputInternalKey.SerializeCompressed())
 
-		siblingPreimage := vOut.AnchorOutputTapscriptSibling
-		exclusionProof := proof.TaprootProof{
-			OutputIndex: outIndex,
-			InternalKey: vOut.AnchorOutputInternalKey,
-			CommitmentProof: &proof.CommitmentProof{
-				Proof:              *splitExclusionProof,
-				TapSiblingPreimage: siblingPreimage,
-			},
-		}
 		params.ExclusionProofs = append(
-			params.ExclusionProofs, exclusionProof,
+			params.ExclusionProofs, *exclusionProof,
 		)
 	}
 
@@ -653,11 +1069,12 @@ func (s *sendPackage) createReAnchorProof(
 	passiveIn := passivePkt.Inputs[0]
 	passiveOut := passivePkt.Outputs[0]
 
-	// Passive assets are always anchored at the "split root", which
-	// normally contains asset change. But it can also be that the split
-	// root output was just created for the passive assets, if there is no
-	// active transfer or no change.
-	changeOut, err := s.VirtualPacket.SplitRootOutput()
+	// Passive assets are always anchored at the "split root" of the active
+	// packet that spends the same anchor output, which normally contains
+	// asset change. But it can also be that the split root output was just
+	// created for the passive assets, if there is no active transfer or no
+	// change.
+	changeOut, err := s.passiveAnchorOutput(passiveIn.PrevID.OutPoint)
 	if err != nil {
 		return nil, fmt.Errorf("anchor output for passive assets not "+
 			"found: %w", err)
@@ -679,7 +1096,7 @@ func (s *sendPackage) createReAnchorProof(
 	// provide an exclusion proof of the passive asset for each of the other
 	// BTC level outputs.
 	err = addOtherOutputExclusionProofs(
-		s.VirtualPacket.Outputs, passiveOut.Asset, passiveParams,
+		s.allOutputs(), passiveOut.Asset, passiveParams,
 		outputCommitments, func(i int, vOut *tappsbt.VOutput) bool {
 			return vOut.AnchorOutputIndex == passiveOutputIndex
 		},
@@ -691,20 +1108,9 @@ func (s *sendPackage) createReAnchorProof(
 	// Add exclusion proof(s) for any P2TR (=BIP-0086, not carrying any
 	// assets) change outputs.
 	if len(s.AnchorTx.FundedPsbt.Pkt.UnsignedTx.TxOut) > 1 {
-		isAnchor := func(idx uint32) bool {
-			for outIdx := range s.VirtualPacket.Outputs {
-				vOut := s.VirtualPacket.Outputs[outIdx]
-				if vOut.AnchorOutputIndex == idx {
-					return true
-				}
-			}
-
-			return false
-		}
-
 		err := proof.AddExclusionProofs(
 			&passiveParams.BaseProofParams,
-			s.AnchorTx.FundedPsbt.Pkt, isAnchor,
+			s.AnchorTx.FundedPsbt.Pkt, s.isAnchorOutput,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("error adding exclusion "+
@@ -724,6 +1130,198 @@ func (s *sendPackage) createReAnchorProof(
 	return reAnchorProof, nil
 }
 
+// passiveAnchorOutput returns the split root output of the virtual packet that
+// spends the given anchor output. Any passive assets of that anchor output are
+// re-anchored into the split root output.
+func (s *sendPackage) passiveAnchorOutput(
+	anchorPoint wire.OutPoint) (*tappsbt.VOutput, error) {
+
+	for _, vPkt := range s.VirtualPackets {
+		for _, vIn := range vPkt.Inputs {
+			if vIn.PrevID.OutPoint != anchorPoint {
+				continue
+			}
+
+			return vPkt.SplitRootOutput()
+		}
+	}
+
+	return nil, fmt.Errorf("no virtual packet spends anchor output %v",
+		anchorPoint)
+}
+
+// passiveAssetKey uniquely identifies a passive asset by the anchor output it
+// is committed to before the transfer, its asset ID and script key.
+type passiveAssetKey struct {
+	anchorPoint wire.OutPoint
+	assetID     asset.ID
+	scriptKey   asset.SerializedKey
+}
+
+// verifyPassiveAssets makes sure that every passive asset committed to any of
+// the anchor outputs spent by the package is re-anchored by it. Each passive
+// asset must have a signed re-anchor virtual packet and a proof that commits
+// the re-anchored asset to the anchor transaction. Otherwise, broadcasting the
+// anchor transaction would strand the passive asset.
+func (s *sendPackage) verifyPassiveAssets() error {
+	if s.AnchorTx == nil || s.AnchorTx.FinalTx == nil {
+		return fmt.Errorf("anchor transaction missing")
+	}
+	if len(s.InputCommitments) != len(s.VirtualPackets) {
+		return fmt.Errorf("expected input commitments for %d "+
+			"virtual packets, got %d", len(s.VirtualPackets),
+			len(s.InputCommitments))
+	}
+
+	reAnchors := make(
+		map[passiveAssetKey]*PassiveAssetReAnchor, len(s.PassiveAssets),
+	)
+	for _, reAnchor := range s.PassiveAssets {
+		scriptKey := reAnchor.ScriptKey.PubKey
+		key := passiveAssetKey{
+			anchorPoint: reAnchor.PrevAnchorPoint,
+			assetID:     reAnchor.GenesisID,
+			scriptKey:   asset.ToSerialized(scriptKey),
+		}
+		reAnchors[key] = reAnchor
+	}
+
+	// We enumerate the passive assets from the input commitments instead
+	// of trusting the list of re-anchors, so we notice any asset that was
+	// left out.
+	passiveAssets, err := s.spentPassiveAssets()
+	if err != nil {
+		return err
+	}
+
+	anchorTxHash := s.AnchorTx.FinalTx.TxHash()
+	for _, key := range passiveAssets {
+		reAnchor, ok := reAnchors[key]
+		if !ok {
+			return fmt.Errorf("%w: asset %v with script key %x in "+
+				"anchor output %v isn't re-anchored",
+				ErrStrandedPassiveAsset, key.assetID,
+				key.scriptKey[:], key.anchorPoint)
+		}
+
+		err := verifyReAnchor(reAnchor, anchorTxHash)
+		if err != nil {
+			return fmt.Errorf("%w: asset %v with script key %x in "+
+				"anchor output %v: %v", ErrStrandedPassiveAsset,
+				key.assetID, key.scriptKey[:], key.anchorPoint,
+				err)
+		}
+	}
+
+	return nil
+}
+
+// spentPassiveAssets returns the keys of all passive assets that are committed
+// to the anchor outputs spent by the virtual packets of the package.
+func (s *sendPackage) spentPassiveAssets() ([]passiveAssetKey, error) {
+	var keys []passiveAssetKey
+	for pktIdx, vPkt := range s.VirtualPackets {
+		inputCommitments := s.InputCommitments[pktIdx]
+		for inputIdx, inputCommitment := range inputCommitments {
+			if inputIdx < 0 || inputIdx >= len(vPkt.Inputs) {
+				return nil, fmt.Errorf("input commitment for "+
+					"unknown input %d of packet %d",
+					inputIdx, pktIdx)
+			}
+
+			passiveCommitments, err := removeActiveCommitments(
+				inputCommitment, vPkt,
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			anchorPoint := vPkt.Inputs[inputIdx].PrevID.OutPoint
+			for _, passiveCommitment := range passiveCommitments {
+				for _, a := range passiveCommitment.Assets() {
+					scriptKey := a.ScriptKey.PubKey
+					keys = append(keys, passiveAssetKey{
+						anchorPoint: anchorPoint,
+						assetID:     a.ID(),
+						scriptKey: asset.ToSerialized(
+							scriptKey,
+						),
+					})
+				}
+			}
+		}
+	}
+
+	return keys, nil
+}
+
+// verifyReAnchor makes sure the given passive asset re-anchor has a signed
+// virtual packet and a proof that commits the re-anchored asset to an output of
+// the anchor transaction with the given hash.
+func verifyReAnchor(reAnchor *PassiveAssetReAnchor,
+	anchorTxHash chainhash.Hash) error {
+
+	vPkt := reAnchor.VPacket
+	if vPkt == nil || len(vPkt.Inputs) != 1 || len(vPkt.Outputs) != 1 {
+		return fmt.Errorf("invalid re-anchor virtual packet")
+	}
+
+	vOut := vPkt.Outputs[0]
+	if vOut.Asset == nil || vOut.Asset.ID() != reAnchor.GenesisID {
+		return fmt.Errorf("re-anchor virtual packet doesn't contain " +
+			"asset")
+	}
+	if !vOut.Asset.ScriptKey.PubKey.IsEqual(reAnchor.ScriptKey.PubKey) {
+		return fmt.Errorf("re-anchor virtual packet changes script " +
+			"key")
+	}
+	if len(vOut.Asset.PrevWitnesses) == 0 ||
+		len(vOut.Asset.PrevWitnesses[0].TxWitness) == 0 {
+
+		return fmt.Errorf("re-anchor virtual packet not signed")
+	}
+
+	reAnchorProof := reAnchor.NewProof
+	if reAnchorProof == nil {
+		return fmt.Errorf("re-anchor proof missing")
+	}
+	if reAnchorProof.AnchorTx.TxHash() != anchorTxHash {
+		return fmt.Errorf("re-anchor proof is for different anchor " +
+			"transaction")
+	}
+	if reAnchorProof.Asset.ID() != reAnchor.GenesisID {
+		return fmt.Errorf("re-anchor proof is for different asset")
+	}
+
+	inclusionProof := &reAnchorProof.InclusionProof
+	if inclusionProof.OutputIndex != vOut.AnchorOutputIndex {
+		return fmt.Errorf("re-anchor proof is for output %d, expected "+
+			"%d", inclusionProof.OutputIndex,
+			vOut.AnchorOutputIndex)
+	}
+
+	expectedKey, err := proof.ExtractTaprootKey(
+		&reAnchorProof.AnchorTx, inclusionProof.OutputIndex,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to extract anchor output key: %w",
+			err)
+	}
+	derivedKey, _, err := inclusionProof.DeriveByAssetInclusion(
+		&reAnchorProof.Asset,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to derive re-anchor output key: %w",
+			err)
+	}
+	if !derivedKey.IsEqual(expectedKey) {
+		return fmt.Errorf("re-anchor proof doesn't commit to anchor " +
+			"output")
+	}
+
+	return nil
+}
+
 // deliverTxBroadcastResp delivers a response for the parcel back to the
 // receiver over the response channel.
 func (s *sendPackage) deliverTxBroadcastResp() {
@@ -741,5 +1339,5 @@ func (s *sendPackage) deliverTxBroadcastResp() {
 		"num_outputs=%d), delivering notification", txHash,
 		len(s.OutboundPkg.Inputs), len(s.OutboundPkg.Outputs))
 
-	s.Parcel.kit().respChan <- s.OutboundPkg
+	s.Parcel.kit().deliverResp(s.OutboundPkg)
 }
@@ -3,12 +3,18 @@ package tapfreighter
 import (
 	"bytes"
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
 	"errors"
 	"fmt"
+	"math/big"
 	"sort"
+	"sync"
+	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/btcutil/psbt"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
@@ -25,10 +31,29 @@ import (
 	"github.com/lightninglabs/taproot-assets/tappsbt"
 	"github.com/lightninglabs/taproot-assets/tapscript"
 	"github.com/lightningnetwork/lnd/input"
-	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 )
 
+const (
+	// defaultCoinLeaseDuration is the duration for which the asset coins
+	// selected to fund a transfer are leased. If the transfer isn't logged
+	// as pending within this time, the coins can be selected again.
+	defaultCoinLeaseDuration = 10 * time.Minute
+
+	// defaultBroadcastCoinLeaseDuration is the duration for which the
+	// asset coins spent by a transfer are leased once the transfer is
+	// logged as pending. The lease is removed when the transfer confirms.
+	defaultBroadcastCoinLeaseDuration = 365 * 24 * time.Hour
+)
+
+var (
+	// defaultWalletLeaseIdentifier is the lease owner we use for leasing
+	// the asset coins selected by the wallet.
+	defaultWalletLeaseIdentifier = sha256.Sum256(
+		[]byte("tapd-asset-wallet-coin-lease"),
+	)
+)
+
 // AnchorTransaction is a type that holds all information about a BTC level
 // anchor transaction that anchors multiple virtual asset transfer transactions.
 type AnchorTransaction struct {
@@ -38,7 +63,8 @@ type AnchorTransaction struct {
 	FundedPsbt *tapgarden.FundedPsbt
 
 	// FinalTx is the fully signed and finalized anchor TX that can be
-	// broadcast to the network.
+	// broadcast to the network. This is nil until an anchor TX that is
+	// signed by an external wallet was handed back.
 	FinalTx *wire.MsgTx
 
 	// TargetFeeRate is the fee rate that was used to fund the anchor TX.
@@ -61,21 +87,35 @@ type Wallet interface {
 	// spend in order to pay the given address. It also returns supporting
 	// data which assists in processing the virtual transaction: passive
 	// asset re-anchors and the Taproot Asset level commitment of the
-	// selected assets.
+	// selected assets. The given strategy is used to select the assets.
 	FundAddressSend(ctx context.Context,
+		strategy MultiCommitmentSelectStrategy,
 		receiverAddrs ...*address.Tap) (*FundedVPacket, error)
 
 	// FundPacket funds a virtual transaction, selecting assets to spend
-	// in order to pay the given recipient. The selected input is then added
-	// to the given virtual transaction.
+	// in order to pay the given recipient using the given strategy. The
+	// selected input is then added to the given virtual transaction.
 	FundPacket(ctx context.Context, fundDesc *tapscript.FundingDescriptor,
+		strategy MultiCommitmentSelectStrategy,
 		vPkt *tappsbt.VPacket) (*FundedVPacket, error)
 
+	// FundBurn funds a virtual transaction for burning the given amount of
+	// units of an asset, selecting assets to spend in order to cover the
+	// amount.
+	FundBurn(ctx context.Context,
+		fundDesc *tapscript.FundingDescriptor) (*FundedVPacket, error)
+
 	// SignVirtualPacket signs the virtual transaction of the given packet
 	// and returns the input indexes that were signed.
 	SignVirtualPacket(vPkt *tappsbt.VPacket,
 		optFuncs ...SignVirtualPacketOption) ([]uint32, error)
 
+	// AddVirtualPacketWitnesses adds the given witnesses that were created
+	// by an external signer to the inputs of the virtual transaction of the
+	// given packet and validates the resulting transfer.
+	AddVirtualPacketWitnesses(vPkt *tappsbt.VPacket,
+		witnesses []wire.TxWitness) error
+
 	// SignPassiveAssets creates and signs the passive asset packets for the
 	// given input commitment and virtual packet that contains the active
 	// asset transfer.
@@ -94,10 +134,24 @@ type Wallet interface {
 	AnchorVirtualTransactions(ctx context.Context,
 		params *AnchorVTxnsParams) (*AnchorTransaction, error)
 
+	// VerifyInteractiveReceive verifies the signed virtual transaction of
+	// an interactive transfer to the local wallet and returns the indexes
+	// of the outputs that pay to local keys.
+	VerifyInteractiveReceive(ctx context.Context,
+		vPkt *tappsbt.VPacket) ([]uint32, error)
+
 	// SignOwnershipProof creates and signs an ownership proof for the given
 	// owned asset. The ownership proof consists of a valid witness of a
-	// signed virtual packet that spends the asset fully to the NUMS key.
-	SignOwnershipProof(ownedAsset *asset.Asset) (wire.TxWitness, error)
+	// signed virtual packet that spends the asset fully to the NUMS key,
+	// tweaked with the optional challenge of the verifier.
+	SignOwnershipProof(ownedAsset *asset.Asset,
+		challenge *[32]byte) (wire.TxWitness, error)
+
+	// ProveReserves creates a reserves report over all unspent assets
+	// owned by the wallet, with each ownership proof bound to the given
+	// challenge of the verifier.
+	ProveReserves(ctx context.Context,
+		challenge [32]byte) (*proof.ReservesReport, error)
 }
 
 // AddrBook is an interface that provides access to the address book.
@@ -118,22 +172,41 @@ type AnchorVTxnsParams struct {
 	FeeRate chainfee.SatPerKWeight
 
 	// VPkts is a list of all the virtual transactions that should be
-	// anchored by the anchor transaction.
+	// anchored by the anchor transaction. Each virtual transaction must
+	// transfer a different asset ID and spend a distinct set of anchor
+	// outputs.
 	VPkts []*tappsbt.VPacket
 
-	// InputCommitments is a map from virtual package input index to its
-	// associated Taproot Assets commitment.
-	InputCommitments tappsbt.InputCommitments
+	// InputCommitments is the list of input commitments of each virtual
+	// transaction, in the same order as VPkts. Each entry is a map from
+	// virtual package input index to its associated Taproot Assets
+	// commitment.
+	InputCommitments []tappsbt.InputCommitments
 
 	// PassiveAssetsVPkts is a list of all the virtual transactions which
 	// re-anchor passive assets.
 	PassiveAssetsVPkts []*tappsbt.VPacket
+
+	// SkipAnchorSign indicates that the anchor transaction should only be
+	// funded but not signed, because it is signed by an external wallet.
+	// The FinalTx of the returned anchor transaction is nil in that case.
+	SkipAnchorSign bool
 }
 
-// NewCoinSelect creates a new CoinSelect.
-func NewCoinSelect(coinLister CoinLister) *CoinSelect {
+// NewCoinSelect creates a new CoinSelect that uses the given strategy whenever
+// DefaultSelectStrategy is requested.
+func NewCoinSelect(coinLister CoinLister,
+	defaultStrategy MultiCommitmentSelectStrategy) *CoinSelect {
+
+	// A default strategy that refers to itself can't be resolved, so we
+	// fall back to the strategy that minimizes the number of inputs.
+	if defaultStrategy == DefaultSelectStrategy {
+		defaultStrategy = PreferMaxAmount
+	}
+
 	return &CoinSelect{
-		coinLister: coinLister,
+		coinLister:      coinLister,
+		defaultStrategy: defaultStrategy,
 	}
 }
 
@@ -141,6 +214,10 @@ func NewCoinSelect(coinLister CoinLister) *CoinSelect {
 // transaction.
 type CoinSelect struct {
 	coinLister CoinLister
+
+	// defaultStrategy is the strategy that is used if the caller doesn't
+	// request a specific one.
+	defaultStrategy MultiCommitmentSelectStrategy
 }
 
 // ListEligibleCoins lists eligible commitments given a set of constraints.
@@ -150,6 +227,29 @@ func (s *CoinSelect) ListEligibleCoins(ctx context.Context,
 	return s.coinLister.ListEligibleCoins(ctx, constraints)
 }
 
+// LeaseCoins leases the UTXOs identified by the given outpoints for the given
+// lease owner until the given expiry.
+func (s *CoinSelect) LeaseCoins(ctx context.Context, leaseOwner [32]byte,
+	expiry time.Time, utxoOutpoints ...wire.OutPoint) error {
+
+	return s.coinLister.LeaseCoins(
+		ctx, leaseOwner, expiry, utxoOutpoints...,
+	)
+}
+
+// ReleaseCoins releases the leases of the UTXOs identified by the given
+// outpoints.
+func (s *CoinSelect) ReleaseCoins(ctx context.Context,
+	utxoOutpoints ...wire.OutPoint) error {
+
+	return s.coinLister.ReleaseCoins(ctx, utxoOutpoints...)
+}
+
+// DeleteExpiredLeases deletes all leases that have expired.
+func (s *CoinSelect) DeleteExpiredLeases(ctx context.Context) error {
+	return s.coinLister.DeleteExpiredLeases(ctx)
+}
+
 // SelectForAmount selects a subset of the given eligible commitments which
 // cumulatively sum to at least the minimum required amount. The selection
 // strategy determines how the commitments are selected.
@@ -158,48 +258,68 @@ func (s *CoinSelect) SelectForAmount(minTotalAmount uint64,
 	strategy MultiCommitmentSelectStrategy) ([]*AnchoredCommitment,
 	error) {
 
-	// Select the first subset of eligible commitments which cumulatively
-	// sum to at least the minimum required amount.
-	var selectedCommitments []*AnchoredCommitment
-	amountSum := uint64(0)
+	if strategy == DefaultSelectStrategy {
+		strategy = s.defaultStrategy
+	}
 
 	switch strategy {
 	case PreferMaxAmount:
 		// Sort eligible commitments from the largest amount to
 		// smallest.
-		sort.Slice(
-			eligibleCommitments, func(i, j int) bool {
-				isLess := eligibleCommitments[i].Asset.Amount <
-					eligibleCommitments[j].Asset.Amount
-
-				// Negate the result to sort in descending
-				// order.
-				return !isLess
-			},
-		)
-
-		// Select the first subset of eligible commitments which
-		// cumulatively sum to at least the minimum required amount.
+		sortByAmount(eligibleCommitments, true)
+
+	case PreferMinAmount:
+		// Sort eligible commitments from the smallest amount to
+		// largest.
+		sortByAmount(eligibleCommitments, false)
+
+	case PreferSingleCoin:
+		// Sort eligible commitments from the smallest amount to
+		// largest, so the first one that covers the full amount is the
+		// smallest such commitment.
+		sortByAmount(eligibleCommitments, false)
 		for _, anchoredCommitment := range eligibleCommitments {
-			selectedCommitments = append(
-				selectedCommitments, anchoredCommitment,
-			)
-
-			// Keep track of the total amount of assets we've seen
-			// so far.
-			amountSum += uint64(anchoredCommitment.Asset.Amount)
-			if amountSum >= minTotalAmount {
-				// At this point a target min amount was
-				// specified and has been reached.
-				break
+			amount := anchoredCommitment.Asset.Amount
+			if amount >= minTotalAmount {
+				return []*AnchoredCommitment{
+					anchoredCommitment,
+				}, nil
 			}
 		}
 
+		// No single commitment is large enough, so we use as few
+		// commitments as possible instead.
+		sortByAmount(eligibleCommitments, true)
+
+	case PreferRandom:
+		err := shuffleCommitments(eligibleCommitments)
+		if err != nil {
+			return nil, err
+		}
+
 	default:
 		return nil, fmt.Errorf("unknown multi coin selection "+
 			"strategy: %v", strategy)
 	}
 
+	// Select the first subset of eligible commitments which cumulatively
+	// sum to at least the minimum required amount.
+	var selectedCommitments []*AnchoredCommitment
+	amountSum := uint64(0)
+	for _, anchoredCommitment := range eligibleCommitments {
+		selectedCommitments = append(
+			selectedCommitments, anchoredCommitment,
+		)
+
+		// Keep track of the total amount of assets we've seen so far.
+		amountSum += anchoredCommitment.Asset.Amount
+		if amountSum >= minTotalAmount {
+			// At this point a target min amount was specified and
+			// has been reached.
+			break
+		}
+	}
+
 	// Having examined all the eligible commitments, return an error if the
 	// minimal funding amount was not reached.
 	if amountSum < minTotalAmount {
@@ -208,6 +328,39 @@ func (s *CoinSelect) SelectForAmount(minTotalAmount uint64,
 	return selectedCommitments, nil
 }
 
+// sortByAmount sorts the given commitments by their asset amount, either in
+// descending or ascending order.
+func sortByAmount(commitments []*AnchoredCommitment, descending bool) {
+	sort.SliceStable(commitments, func(i, j int) bool {
+		if descending {
+			return commitments[i].Asset.Amount >
+				commitments[j].Asset.Amount
+		}
+
+		return commitments[i].Asset.Amount <
+			commitments[j].Asset.Amount
+	})
+}
+
+// shuffleCommitments shuffles the given commitments in place, using a
+// cryptographically secure source of randomness so the order can't be
+// predicted by an observer.
+func shuffleCommitments(commitments []*AnchoredCommitment) error {
+	for i := len(commitments) - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return fmt.Errorf("unable to shuffle commitments: %w",
+				err)
+		}
+
+		idx := j.Int64()
+		commitments[i], commitments[idx] = commitments[idx],
+			commitments[i]
+	}
+
+	return nil
+}
+
 var _ CoinSelector = (*CoinSelect)(nil)
 
 // WalletConfig holds the configuration for a new Wallet.
@@ -243,12 +396,28 @@ type WalletConfig struct {
 
 	// ChainParams is the chain params of the chain we operate on.
 	ChainParams *address.ChainParams
+
+	// ChainBridge is used to look up the current block height, which is
+	// needed to enforce MinInputConfs.
+	ChainBridge tapgarden.ChainBridge
+
+	// MinInputConfs is the minimum number of confirmations the anchor
+	// transaction of an asset needs before it is selected as an input.
+	// This should match the reorg safety depth, as the proofs of assets
+	// that are sent on can no longer be re-bound after a reorg. If zero,
+	// any confirmed asset is selected.
+	MinInputConfs uint32
 }
 
 // AssetWallet is an implementation of the Wallet interface that can create
 // virtual transactions, sign them and commit them on-chain.
 type AssetWallet struct {
 	cfg *WalletConfig
+
+	// coinLock makes sure that listing, selecting and leasing asset coins
+	// happens atomically, so concurrent transfers never select the same
+	// coins.
+	coinLock sync.Mutex
 }
 
 // NewAssetWallet creates a new AssetWallet instance from the given
@@ -274,10 +443,12 @@ type FundedVPacket struct {
 // FundAddressSend funds a virtual transaction, selecting assets to spend in
 // order to pay the given address. It also returns supporting data which assists
 // in processing the virtual transaction: passive asset re-anchors and the
-// Taproot Asset level commitment of the selected assets.
+// Taproot Asset level commitment of the selected assets. The given strategy is
+// used to select the assets.
 //
 // NOTE: This is part of the Wallet interface.
 func (f *AssetWallet) FundAddressSend(ctx context.Context,
+	strategy MultiCommitmentSelectStrategy,
 	receiverAddrs ...*address.Tap) (*FundedVPacket, error) {
 
 	// We start by creating a new virtual transaction that will be used to
@@ -295,7 +466,7 @@ func (f *AssetWallet) FundAddressSend(ctx context.Context,
 		return nil, fmt.Errorf("unable to describe recipients: %w", err)
 	}
 
-	fundedVPkt, err := f.FundPacket(ctx, fundDesc, vPkt)
+	fundedVPkt, err := f.FundPacket(ctx, fundDesc, strategy, vPkt)
 	if err != nil {
 		return nil, err
 	}
@@ -303,78 +474,114 @@ func (f *AssetWallet) FundAddressSend(ctx context.Context,
 	return fundedVPkt, nil
 }
 
-// passiveAssetVPacket creates a virtual packet for the given passive asset.
-func (f *AssetWallet) passiveAssetVPacket(passiveAsset *asset.Asset,
-	anchorPoint wire.OutPoint, anchorOutputIndex uint32,
-	internalKey *keychain.KeyDescriptor) *tappsbt.VPacket {
+// FundPacket funds a virtual transaction, selecting assets to spend in order to
+// pay the given recipient using the given strategy. The selected input is then
+// added to the given virtual transaction.
+func (f *AssetWallet) FundPacket(ctx context.Context,
+	fundDesc *tapscript.FundingDescriptor,
+	strategy MultiCommitmentSelectStrategy,
+	vPkt *tappsbt.VPacket) (*FundedVPacket, error) {
 
-	// Specify virtual input.
-	inputAsset := passiveAsset.Copy()
-	inputPrevId := asset.PrevID{
-		OutPoint: anchorPoint,
-		ID:       inputAsset.ID(),
-		ScriptKey: asset.ToSerialized(
-			inputAsset.ScriptKey.PubKey,
-		),
-	}
-	vInput := tappsbt.VInput{
-		PrevID: inputPrevId,
+	// The input and address networks must match.
+	if !address.IsForNet(vPkt.ChainParams.TapHRP, f.cfg.ChainParams) {
+		return nil, address.ErrMismatchedHRP
 	}
 
-	// Specify virtual output.
-	outputAsset := passiveAsset.Copy()
-
-	// Clear the split commitment root, as we'll be transferring the
-	// whole asset.
-	outputAsset.SplitCommitmentRoot = nil
-
-	// Clear the output asset witness data. We'll be creating a new witness.
-	outputAsset.PrevWitnesses = []asset.Witness{{
-		PrevID: &inputPrevId,
-	}}
+	selectedCommitments, err := f.selectInputs(ctx, fundDesc, strategy)
+	if err != nil {
+		return nil, err
+	}
 
-	vOutput := tappsbt.VOutput{
-		Amount: outputAsset.Amount,
+	fundedPkt, err := f.fundPacketWithInputs(
+		ctx, fundDesc, vPkt, selectedCommitments,
+	)
+	if err != nil {
+		f.releaseInputs(ctx, selectedCommitments)
+		return nil, err
+	}
 
-		// In this case, the receiver of the output is also the sender.
-		// We therefore set interactive to true to indicate that the
-		// receiver is aware of the transfer.
-		Interactive: true,
+	return fundedPkt, nil
+}
 
-		AnchorOutputIndex: anchorOutputIndex,
-		ScriptKey:         outputAsset.ScriptKey,
-		Asset:             outputAsset,
+// FundBurn funds a virtual transaction that burns the given amount of assets,
+// selecting assets to spend in order to cover the amount. The burnt assets are
+// assigned a provably un-spendable script key that is derived from the first
+// input of the virtual transaction.
+func (f *AssetWallet) FundBurn(ctx context.Context,
+	fundDesc *tapscript.FundingDescriptor) (*FundedVPacket, error) {
+
+	// We need to know which input is going to be the first one, since the
+	// burn key commits to it. So we need to select the inputs before we
+	// can create the virtual transaction.
+	selectedCommitments, err := f.selectInputs(
+		ctx, fundDesc, DefaultSelectStrategy,
+	)
+	if err != nil {
+		return nil, err
 	}
 
-	// Set output internal key.
-	vOutput.SetAnchorInternalKey(
-		*internalKey, f.cfg.ChainParams.HDCoinType,
-	)
+	firstInput := selectedCommitments[0]
+	firstPrevID := asset.PrevID{
+		OutPoint: firstInput.AnchorPoint,
+		ID:       firstInput.Asset.ID(),
+		ScriptKey: asset.ToSerialized(
+			firstInput.Asset.ScriptKey.PubKey,
+		),
+	}
 
-	// Create VPacket.
-	vPacket := &tappsbt.VPacket{
-		Inputs:      []*tappsbt.VInput{&vInput},
-		Outputs:     []*tappsbt.VOutput{&vOutput},
+	// There is no receiver that could be aware of a burn, so the burn
+	// output is never interactive and we always need a split root. It
+	// either carries the change or the tombstone of a full value burn. The
+	// split root goes into a separate anchor output, so the burnt asset
+	// ends up in an anchor output of its own and doesn't need to be
+	// re-anchored when spending the change later. Just like for address
+	// sends, we use the NUMS key for the split root, which is replaced
+	// with a real key during funding if there is any change.
+	vPkt := &tappsbt.VPacket{
+		Outputs: []*tappsbt.VOutput{{
+			Amount:            fundDesc.Amount,
+			Type:              tappsbt.TypeBurn,
+			Interactive:       false,
+			AnchorOutputIndex: 0,
+			ScriptKey:         asset.NewBurnScriptKey(firstPrevID),
+		}, {
+			Amount:            0,
+			Type:              tappsbt.TypeSplitRoot,
+			Interactive:       false,
+			AnchorOutputIndex: 1,
+			ScriptKey:         asset.NUMSScriptKey,
+		}},
 		ChainParams: f.cfg.ChainParams,
 	}
 
-	// Set the input asset. The input asset proof is not provided as it is
-	// not needed for the re-anchoring process.
-	vPacket.SetInputAsset(0, inputAsset, nil)
+	fundedPkt, err := f.fundPacketWithInputs(
+		ctx, fundDesc, vPkt, selectedCommitments,
+	)
+	if err != nil {
+		f.releaseInputs(ctx, selectedCommitments)
+		return nil, err
+	}
 
-	return vPacket
+	return fundedPkt, nil
 }
 
-// FundPacket funds a virtual transaction, selecting assets to spend in order to
-// pay the given recipient. The selected input is then added to the given
-// virtual transaction.
-func (f *AssetWallet) FundPacket(ctx context.Context,
+// selectInputs selects the asset inputs that are going to be spent in order to
+// cover the amount of the given funding descriptor, using the given strategy.
+func (f *AssetWallet) selectInputs(ctx context.Context,
 	fundDesc *tapscript.FundingDescriptor,
-	vPkt *tappsbt.VPacket) (*FundedVPacket, error) {
+	strategy MultiCommitmentSelectStrategy) ([]*AnchoredCommitment, error) {
 
-	// The input and address networks must match.
-	if !address.IsForNet(vPkt.ChainParams.TapHRP, f.cfg.ChainParams) {
-		return nil, address.ErrMismatchedHRP
+	// We need to make sure no other transfer selects the same coins while
+	// we're selecting and leasing them.
+	f.coinLock.Lock()
+	defer f.coinLock.Unlock()
+
+	// Before we select any coins, we'll clean up the leases that have
+	// expired. Those coins can be selected again.
+	err := f.cfg.CoinSelector.DeleteExpiredLeases(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to delete expired leases: %w",
+			err)
 	}
 
 	// We need to find a commitment that has enough assets to satisfy this
@@ -384,7 +591,26 @@ func (f *AssetWallet) FundPacket(ctx context.Context,
 		GroupKey: fundDesc.GroupKey,
 		AssetID:  &fundDesc.ID,
 		MinAmt:   1,
+		Edition:  fundDesc.Edition,
 	}
+
+	// Assets that were confirmed too recently might still be reorged out,
+	// so we only spend them once they're buried deep enough.
+	if f.cfg.MinInputConfs > 0 {
+		height, err := f.cfg.ChainBridge.CurrentHeight(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch current "+
+				"height: %w", err)
+		}
+
+		if height+1 < f.cfg.MinInputConfs {
+			return nil, fmt.Errorf("%w: chain height %d below "+
+				"required input confirmations",
+				ErrMatchingAssetsNotFound, height)
+		}
+		constraints.MaxAnchorHeight = height + 1 - f.cfg.MinInputConfs
+	}
+
 	eligibleCommitments, err := f.cfg.CoinSelector.ListEligibleCoins(
 		ctx, constraints,
 	)
@@ -396,15 +622,131 @@ func (f *AssetWallet) FundPacket(ctx context.Context,
 	log.Infof("Identified %v eligible asset inputs for send of %d to %x",
 		len(eligibleCommitments), fundDesc.Amount, fundDesc.ID[:])
 
-	selectedCommitments, err := f.cfg.CoinSelector.SelectForAmount(
-		fundDesc.Amount, eligibleCommitments, PreferMaxAmount,
-	)
+	// If the caller asked for specific inputs, we only make sure those are
+	// eligible and cover the amount, otherwise we let the coin selector
+	// pick the inputs.
+	var selectedCommitments []*AnchoredCommitment
+	if len(fundDesc.PrevIDs) > 0 {
+		selectedCommitments, err = selectPrevIDs(
+			fundDesc.Amount, eligibleCommitments, fundDesc.PrevIDs,
+		)
+	} else {
+		selectedCommitments, err = f.cfg.CoinSelector.SelectForAmount(
+			fundDesc.Amount, eligibleCommitments, strategy,
+		)
+	}
 	if err != nil {
 		return nil, err
 	}
 	log.Infof("Selected %v asset inputs for send of %d to %x",
 		len(selectedCommitments), fundDesc.Amount, fundDesc.ID[:])
 
+	// We now lease the anchor outputs of the selected coins, so they
+	// aren't selected again by a concurrent transfer. Leased coins are
+	// excluded from the eligible coins listed above.
+	expiry := time.Now().Add(defaultCoinLeaseDuration)
+	err = f.cfg.CoinSelector.LeaseCoins(
+		ctx, defaultWalletLeaseIdentifier, expiry,
+		anchorOutpoints(selectedCommitments)...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to lease coins: %w", err)
+	}
+
+	return selectedCommitments, nil
+}
+
+// selectPrevIDs picks the commitments of the given asset inputs from the list
+// of eligible commitments. An error is returned if any of the inputs isn't
+// eligible or if the inputs don't cover the minimum total amount.
+func selectPrevIDs(minTotalAmount uint64,
+	eligibleCommitments []*AnchoredCommitment,
+	prevIDs []asset.PrevID) ([]*AnchoredCommitment, error) {
+
+	eligible := make(map[asset.PrevID]*AnchoredCommitment)
+	for _, anchoredCommitment := range eligibleCommitments {
+		prevID := asset.PrevID{
+			OutPoint: anchoredCommitment.AnchorPoint,
+			ID:       anchoredCommitment.Asset.ID(),
+			ScriptKey: asset.ToSerialized(
+				anchoredCommitment.Asset.ScriptKey.PubKey,
+			),
+		}
+		eligible[prevID] = anchoredCommitment
+	}
+
+	var (
+		selectedCommitments []*AnchoredCommitment
+		amountSum           uint64
+		seen                = make(map[asset.PrevID]struct{})
+	)
+	for _, prevID := range prevIDs {
+		if _, ok := seen[prevID]; ok {
+			return nil, fmt.Errorf("duplicate input %v",
+				prevID.OutPoint)
+		}
+		seen[prevID] = struct{}{}
+
+		anchoredCommitment, ok := eligible[prevID]
+		if !ok {
+			return nil, fmt.Errorf("%w: input %v with asset ID %v "+
+				"is not eligible", ErrMatchingAssetsNotFound,
+				prevID.OutPoint, prevID.ID)
+		}
+
+		selectedCommitments = append(
+			selectedCommitments, anchoredCommitment,
+		)
+		amountSum += anchoredCommitment.Asset.Amount
+	}
+
+	if amountSum < minTotalAmount {
+		return nil, fmt.Errorf("%w: inputs only sum to %d of %d",
+			ErrMatchingAssetsNotFound, amountSum, minTotalAmount)
+	}
+
+	return selectedCommitments, nil
+}
+
+// releaseInputs releases the leases of the given selected asset inputs, so
+// they can be selected again. This is used if funding a transfer fails after
+// its inputs were selected.
+func (f *AssetWallet) releaseInputs(ctx context.Context,
+	selectedCommitments []*AnchoredCommitment) {
+
+	err := f.cfg.CoinSelector.ReleaseCoins(
+		ctx, anchorOutpoints(selectedCommitments)...,
+	)
+	if err != nil {
+		log.Errorf("Unable to release coins: %v", err)
+	}
+}
+
+// anchorOutpoints returns the unique anchor outpoints of the given
+// commitments.
+func anchorOutpoints(commitments []*AnchoredCommitment) []wire.OutPoint {
+	var (
+		outpoints []wire.OutPoint
+		seen      = make(map[wire.OutPoint]struct{})
+	)
+	for _, c := range commitments {
+		if _, ok := seen[c.AnchorPoint]; ok {
+			continue
+		}
+
+		seen[c.AnchorPoint] = struct{}{}
+		outpoints = append(outpoints, c.AnchorPoint)
+	}
+
+	return outpoints
+}
+
+// fundPacketWithInputs funds a virtual transaction with the given selected
+// inputs and prepares the output assets, adding a change output if needed.
+func (f *AssetWallet) fundPacketWithInputs(ctx context.Context,
+	fundDesc *tapscript.FundingDescriptor, vPkt *tappsbt.VPacket,
+	selectedCommitments []*AnchoredCommitment) (*FundedVPacket, error) {
+
 	assetType := selectedCommitments[0].Asset.Type
 
 	totalInputAmt := uint64(0)
@@ -741,6 +1083,13 @@ func (f *AssetWallet) SignVirtualPacket(vPkt *tappsbt.VPacket,
 		optFunc(opts)
 	}
 
+	// We refuse to sign anything that isn't a structurally valid transfer,
+	// as a malformed packet might only be rejected by the VM after we've
+	// already produced a signature for it.
+	if err := vPkt.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid virtual packet: %w", err)
+	}
+
 	for idx := range vPkt.Inputs {
 		// Conditionally skip the inclusion proof verification. We may
 		// not need to verify the input proof if we're only using the
@@ -778,6 +1127,36 @@ func (f *AssetWallet) SignVirtualPacket(vPkt *tappsbt.VPacket,
 	return signedInputs, nil
 }
 
+// AddVirtualPacketWitnesses adds the given witnesses that were created by an
+// external signer to the inputs of the virtual transaction of the given packet
+// and validates the resulting transfer.
+//
+// NOTE: This is part of the Wallet interface.
+func (f *AssetWallet) AddVirtualPacketWitnesses(vPkt *tappsbt.VPacket,
+	witnesses []wire.TxWitness) error {
+
+	// Just like when signing locally, we make sure the inputs are actually
+	// committed in their anchor transactions before accepting the
+	// witnesses.
+	for idx := range vPkt.Inputs {
+		err := verifyInclusionProof(vPkt.Inputs[idx])
+		if err != nil {
+			return fmt.Errorf("unable to verify inclusion proof: "+
+				"%w", err)
+		}
+	}
+
+	err := tapscript.AddVirtualTxWitnesses(
+		vPkt, witnesses, f.cfg.TxValidator,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to add Taproot Asset witness data: "+
+			"%w", err)
+	}
+
+	return nil
+}
+
 // verifyInclusionProof verifies that the given virtual input's asset is
 // actually committed in the anchor transaction.
 func verifyInclusionProof(vIn *tappsbt.VInput) error {
@@ -967,10 +1346,10 @@ func (f *AssetWallet) SignPassiveAssets(vPkt *tappsbt.VPacket,
 
 		for _, passiveCommitment := range passiveCommitments {
 			for _, passiveAsset := range passiveCommitment.Assets() {
-				passivePkt := f.passiveAssetVPacket(
+				passivePkt := tapscript.NewReAnchorPacket(
 					passiveAsset, anchorPoint,
 					changeOut.AnchorOutputIndex,
-					&changeInternalKey,
+					changeInternalKey, f.cfg.ChainParams,
 				)
 				reAnchor := &PassiveAssetReAnchor{
 					VPacket:         passivePkt,
@@ -1009,33 +1388,62 @@ func (f *AssetWallet) SignPassiveAssets(vPkt *tappsbt.VPacket,
 func (f *AssetWallet) AnchorVirtualTransactions(ctx context.Context,
 	params *AnchorVTxnsParams) (*AnchorTransaction, error) {
 
-	// We currently only support anchoring a single virtual transaction.
+	if len(params.VPkts) == 0 {
+		return nil, fmt.Errorf("no virtual transactions to anchor")
+	}
+	if len(params.InputCommitments) != len(params.VPkts) {
+		return nil, fmt.Errorf("expected input commitments for %d "+
+			"virtual transactions, got %d", len(params.VPkts),
+			len(params.InputCommitments))
+	}
+
+	// Each virtual transaction carries the transfer of a single asset ID,
+	// so we can merge them into the same anchor transaction as long as
+	// they don't spend the same anchor outputs.
 	//
-	// TODO(guggero): Support merging and anchoring multiple virtual
-	// transactions.
-	if len(params.VPkts) != 1 {
-		return nil, fmt.Errorf("only a single virtual transaction is " +
-			"supported for now")
+	// TODO(guggero): Support packets that spend different assets from the
+	// same anchor output. That requires each packet to treat the assets
+	// of the other packets as active instead of passive assets.
+	anchorInputs, err := uniqueAnchorInputs(params.VPkts)
+	if err != nil {
+		return nil, err
 	}
-	vPacket := params.VPkts[0]
 
-	outputCommitments, err := tapscript.CreateOutputCommitments(
-		params.InputCommitments, vPacket, params.PassiveAssetsVPkts,
+	var (
+		allOutputs        []*tappsbt.VOutput
+		outputCommitments = make(
+			[][]*commitment.TapCommitment, len(params.VPkts),
+		)
 	)
-	if err != nil {
-		return nil, fmt.Errorf("unable to create new output "+
-			"commitments: %w", err)
+	for idx := range params.VPkts {
+		vPacket := params.VPkts[idx]
+
+		// The passive assets of a packet are the ones that were
+		// anchored in the same outputs as the packet's inputs.
+		passiveVPkts := passivePacketsForInputs(
+			vPacket, params.PassiveAssetsVPkts,
+		)
+
+		outputCommitments[idx], err = tapscript.CreateOutputCommitments(
+			params.InputCommitments[idx], vPacket, passiveVPkts,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create new output "+
+				"commitments for packet %d: %w", idx, err)
+		}
+
+		allOutputs = append(allOutputs, vPacket.Outputs...)
 	}
 
 	// Construct our template PSBT to commits to the set of dummy locators
 	// we use to make fee estimation work.
-	sendPacket, err := tapscript.CreateAnchorTx(vPacket.Outputs)
+	sendPacket, err := tapscript.CreateAnchorTx(allOutputs)
 	if err != nil {
 		return nil, fmt.Errorf("error creating anchor TX: %w", err)
 	}
 
 	anchorPkt, err := f.cfg.Wallet.FundPsbt(
-		ctx, sendPacket, 1, params.FeeRate,
+		ctx, sendPacket, 1, params.FeeRate, nil,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("unable to fund psbt: %w", err)
@@ -1050,7 +1458,11 @@ func (f *AssetWallet) AnchorVirtualTransactions(ctx context.Context,
 	// TODO(jhb): Do we need richer handling for the change output?
 	// We could reassign the change value to our Taproot Asset change output
 	// and remove the change output entirely.
-	adjustFundedPsbt(&anchorPkt, int64(vPacket.Inputs[0].Anchor.Value))
+	var anchorInputValue int64
+	for _, anchorValue := range anchorInputs {
+		anchorInputValue += int64(anchorValue)
+	}
+	adjustFundedPsbt(&anchorPkt, anchorInputValue)
 
 	log.Infof("Received funded PSBT packet")
 	log.Tracef("Packet: %v", spew.Sdump(anchorPkt.Pkt))
@@ -1065,8 +1477,8 @@ func (f *AssetWallet) AnchorVirtualTransactions(ctx context.Context,
 
 	// First, we'll update the PSBT packets to insert the _real_ outputs we
 	// need to commit to the asset transfer.
-	mergedCommitments, err := tapscript.UpdateTaprootOutputKeys(
-		signAnchorPkt, vPacket, outputCommitments,
+	mergedCommitments, err := tapscript.UpdateTaprootOutputKeysForPackets(
+		signAnchorPkt, params.VPkts, outputCommitments,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("error updating taproot output keys: %w",
@@ -1077,7 +1489,7 @@ func (f *AssetWallet) AnchorVirtualTransactions(ctx context.Context,
 	// add our anchor inputs as well, since the wallet can sign for
 	// it itself.
 	err = addAnchorPsbtInputs(
-		signAnchorPkt, vPacket, params.FeeRate,
+		signAnchorPkt, params.VPkts, params.FeeRate,
 		f.cfg.ChainParams.Params,
 	)
 	if err != nil {
@@ -1085,6 +1497,24 @@ func (f *AssetWallet) AnchorVirtualTransactions(ctx context.Context,
 	}
 	anchorPkt.Pkt = signAnchorPkt
 
+	// If the anchor transaction is signed externally, the funded packet
+	// is all we can provide. The fees can already be calculated, since
+	// they can't change without changing the transaction itself.
+	if params.SkipAnchorSign {
+		chainFees, err := tapgarden.GetTxFee(signAnchorPkt)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get on-chain fees "+
+				"for psbt: %w", err)
+		}
+
+		return &AnchorTransaction{
+			FundedPsbt:        &anchorPkt,
+			TargetFeeRate:     params.FeeRate,
+			ChainFees:         chainFees,
+			OutputCommitments: mergedCommitments,
+		}, nil
+	}
+
 	// With all the input and output information in the packet, we
 	// can now ask lnd to sign it, and then extract the final
 	// version ourselves.
@@ -1126,17 +1556,193 @@ func (f *AssetWallet) AnchorVirtualTransactions(ctx context.Context,
 	}, nil
 }
 
+// uniqueAnchorInputs makes sure the given virtual packets transfer different
+// asset IDs and don't spend the same anchor outputs. It returns the value of
+// each anchor output that is spent by the packets.
+func uniqueAnchorInputs(
+	vPkts []*tappsbt.VPacket) (map[wire.OutPoint]btcutil.Amount, error) {
+
+	var (
+		assetIDs     = make(map[asset.ID]struct{})
+		anchorInputs = make(map[wire.OutPoint]btcutil.Amount)
+	)
+	for pktIdx := range vPkts {
+		vPkt := vPkts[pktIdx]
+		if len(vPkt.Inputs) == 0 {
+			return nil, fmt.Errorf("virtual packet %d has no "+
+				"inputs", pktIdx)
+		}
+
+		assetID := vPkt.Inputs[0].PrevID.ID
+		if _, ok := assetIDs[assetID]; ok {
+			return nil, fmt.Errorf("multiple virtual packets for "+
+				"asset ID %v", assetID)
+		}
+		assetIDs[assetID] = struct{}{}
+
+		// Multiple inputs of the same packet can be anchored in the
+		// same output, but different packets can't share anchors.
+		pktAnchors := make(map[wire.OutPoint]btcutil.Amount)
+		for _, vIn := range vPkt.Inputs {
+			anchorPoint := vIn.PrevID.OutPoint
+			if _, ok := anchorInputs[anchorPoint]; ok {
+				return nil, fmt.Errorf("anchor output %v is "+
+					"spent by multiple virtual packets",
+					anchorPoint)
+			}
+			pktAnchors[anchorPoint] = vIn.Anchor.Value
+		}
+		for anchorPoint, value := range pktAnchors {
+			anchorInputs[anchorPoint] = value
+		}
+	}
+
+	return anchorInputs, nil
+}
+
+// passivePacketsForInputs returns the passive asset re-anchoring packets that
+// spend from any of the anchor outputs of the given active virtual packet.
+func passivePacketsForInputs(vPkt *tappsbt.VPacket,
+	passiveVPkts []*tappsbt.VPacket) []*tappsbt.VPacket {
+
+	anchorPoints := make(map[wire.OutPoint]struct{}, len(vPkt.Inputs))
+	for _, vIn := range vPkt.Inputs {
+		anchorPoints[vIn.PrevID.OutPoint] = struct{}{}
+	}
+
+	var result []*tappsbt.VPacket
+	for _, passiveVPkt := range passiveVPkts {
+		passiveAnchor := passiveVPkt.Inputs[0].PrevID.OutPoint
+		if _, ok := anchorPoints[passiveAnchor]; ok {
+			result = append(result, passiveVPkt)
+		}
+	}
+
+	return result
+}
+
+// VerifyInteractiveReceive verifies the signed virtual transaction of an
+// interactive transfer to the local wallet before the sender anchors it. All
+// outputs that pay to a script key of the local wallet must be interactive,
+// carry a signed asset of the announced amount and be anchored to an internal
+// key of the local wallet. If the transfer moves the full input amount to us,
+// it also must not create a split with a tombstone output. The indexes of the
+// outputs that pay to the local wallet are returned.
+func (f *AssetWallet) VerifyInteractiveReceive(ctx context.Context,
+	vPkt *tappsbt.VPacket) ([]uint32, error) {
+
+	var inputAmount uint64
+	for idx := range vPkt.Inputs {
+		vIn := vPkt.Inputs[idx]
+		if vIn.Asset() == nil {
+			return nil, fmt.Errorf("input %d asset missing", idx)
+		}
+		inputAmount += vIn.Asset().Amount
+
+		// We don't know anything about the inputs of the sender, so we
+		// at least want to make sure they're committed to in their
+		// anchor transaction.
+		if err := verifyInclusionProof(vIn); err != nil {
+			return nil, fmt.Errorf("unable to verify inclusion "+
+				"proof of input %d: %w", idx, err)
+		}
+	}
+
+	var (
+		receiveIndexes []uint32
+		receiveAmount  uint64
+	)
+	for idx, vOut := range vPkt.Outputs {
+		if vOut.ScriptKey.PubKey == nil {
+			return nil, fmt.Errorf("output %d script key missing",
+				idx)
+		}
+
+		_, err := f.cfg.AddrBook.FetchScriptKey(
+			ctx, vOut.ScriptKey.PubKey,
+		)
+		switch {
+		// This output doesn't go to us, probably the change output of
+		// the sender.
+		case errors.Is(err, address.ErrScriptKeyNotFound):
+			continue
+
+		case err != nil:
+			return nil, fmt.Errorf("unable to fetch script key: %w",
+				err)
+		}
+
+		if !vOut.Interactive {
+			return nil, fmt.Errorf("output %d to local script key "+
+				"is not interactive", idx)
+		}
+
+		if vOut.Asset == nil {
+			return nil, fmt.Errorf("output %d asset missing", idx)
+		}
+
+		outputKey := vOut.Asset.ScriptKey.PubKey
+		if outputKey == nil ||
+			!outputKey.IsEqual(vOut.ScriptKey.PubKey) {
+			return nil, fmt.Errorf("output %d asset script key "+
+				"mismatch", idx)
+		}
+
+		if vOut.Asset.Amount != vOut.Amount {
+			return nil, fmt.Errorf("output %d asset amount %d "+
+				"doesn't match output amount %d", idx,
+				vOut.Asset.Amount, vOut.Amount)
+		}
+
+		anchorKeyDesc, err := vOut.AnchorKeyToDesc()
+		if err != nil {
+			return nil, fmt.Errorf("output %d anchor internal key "+
+				"invalid: %w", idx, err)
+		}
+		if !f.cfg.KeyRing.IsLocalKey(ctx, anchorKeyDesc) {
+			return nil, fmt.Errorf("output %d anchor internal key "+
+				"is not a local key", idx)
+		}
+
+		receiveIndexes = append(receiveIndexes, uint32(idx))
+		receiveAmount += vOut.Amount
+	}
+
+	if len(receiveIndexes) == 0 {
+		return nil, fmt.Errorf("packet doesn't pay to any local " +
+			"script key")
+	}
+
+	// A full-value transfer to us doesn't need a split, so there shouldn't
+	// be a tombstone output we'd need to carry around.
+	isSplit, err := vPkt.HasSplitCommitment()
+	if err != nil {
+		return nil, err
+	}
+	if receiveAmount == inputAmount && isSplit {
+		return nil, fmt.Errorf("full-value transfer must not create " +
+			"a split with a tombstone output")
+	}
+
+	err = tapscript.VerifyVirtualTransaction(vPkt, f.cfg.TxValidator)
+	if err != nil {
+		return nil, fmt.Errorf("invalid virtual transaction: %w", err)
+	}
+
+	return receiveIndexes, nil
+}
+
 // SignOwnershipProof creates and signs an ownership proof for the given owned
 // asset. The ownership proof consists of a signed virtual packet that spends
-// the asset fully to the NUMS key.
-func (f *AssetWallet) SignOwnershipProof(
-	ownedAsset *asset.Asset) (wire.TxWitness, error) {
+// the asset fully to the NUMS key, tweaked with the optional challenge.
+func (f *AssetWallet) SignOwnershipProof(ownedAsset *asset.Asset,
+	challenge *[32]byte) (wire.TxWitness, error) {
 
 	outputAsset := ownedAsset.Copy()
 	log.Infof("Generating ownership proof for asset %v", outputAsset.ID())
 
 	vPkt := tappsbt.OwnershipProofPacket(
-		ownedAsset.Copy(), f.cfg.ChainParams,
+		ownedAsset.Copy(), challenge, f.cfg.ChainParams,
 	)
 	err := tapscript.SignVirtualTransaction(
 		vPkt, f.cfg.Signer, f.cfg.TxValidator,
@@ -1149,6 +1755,90 @@ func (f *AssetWallet) SignOwnershipProof(
 	return vPkt.Outputs[0].Asset.PrevWitnesses[0].TxWitness, nil
 }
 
+// ProveReserves creates a reserves report over all unspent assets owned by
+// the wallet. For each asset, the report contains its proof file, with the
+// last proof carrying an ownership proof bound to the given challenge.
+func (f *AssetWallet) ProveReserves(ctx context.Context,
+	challenge [32]byte) (*proof.ReservesReport, error) {
+
+	// Coins that are leased for a pending transfer are still owned by us,
+	// so they are part of the reserves.
+	ownedCoins, err := f.cfg.CoinSelector.ListEligibleCoins(
+		ctx, CommitmentConstraints{
+			IncludeLeased: true,
+		},
+	)
+	switch {
+	// Not owning any assets is a valid, if boring, report.
+	case errors.Is(err, ErrMatchingAssetsNotFound):
+		ownedCoins = nil
+
+	case err != nil:
+		return nil, fmt.Errorf("unable to list owned assets: %w", err)
+	}
+
+	report := &proof.ReservesReport{
+		Challenge: challenge,
+	}
+	for _, coin := range ownedCoins {
+		ownedAsset := coin.Asset
+
+		// Tombstones and burns don't hold any value, and we can't
+		// create an ownership proof for them anyway.
+		unSpendable, err := ownedAsset.ScriptKey.IsUnSpendable()
+		if err != nil {
+			return nil, err
+		}
+		if ownedAsset.Amount == 0 || unSpendable {
+			continue
+		}
+
+		assetID := ownedAsset.ID()
+		proofBlob, err := f.cfg.AssetProofs.FetchProof(
+			ctx, proof.Locator{
+				AssetID:   &assetID,
+				ScriptKey: *ownedAsset.ScriptKey.PubKey,
+			},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch proof for "+
+				"asset %v: %w", assetID, err)
+		}
+
+		var proofFile proof.File
+		err = proofFile.Decode(bytes.NewReader(proofBlob))
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode proof for "+
+				"asset %v: %w", assetID, err)
+		}
+
+		lastProof, err := proofFile.LastProof()
+		if err != nil {
+			return nil, err
+		}
+
+		challengeWitness, err := f.SignOwnershipProof(
+			ownedAsset.Copy(), &challenge,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to sign ownership "+
+				"proof for asset %v: %w", assetID, err)
+		}
+
+		lastProof.ChallengeWitness = challengeWitness
+		if err := proofFile.ReplaceLastProof(*lastProof); err != nil {
+			return nil, err
+		}
+
+		report.Files = append(report.Files, proofFile)
+	}
+
+	log.Infof("Created reserves report over %d assets",
+		len(report.Files))
+
+	return report, nil
+}
+
 // inputAnchorPkScript returns the top-level Taproot output script of the input
 // anchor output as well as the Taproot Asset script root of the output (the
 // Taproot tweak).
@@ -1203,14 +1893,12 @@ func trimSplitWitnesses(
 
 	allAssets := tapCommitmentCopy.CommittedAssets()
 	for _, inputAsset := range allAssets {
-		inputAssetCopy := inputAsset.Copy()
-
 		// Assets received via non-interactive split should have one
 		// witness, with an empty PrevID and a SplitCommitment present.
-		if inputAssetCopy.HasSplitCommitmentWitness() &&
-			*inputAssetCopy.PrevWitnesses[0].PrevID == asset.ZeroPrevID {
+		if inputAsset.HasSplitCommitmentWitness() &&
+			*inputAsset.PrevWitnesses[0].PrevID == asset.ZeroPrevID {
 
-			inputAssetCopy.PrevWitnesses[0].SplitCommitment = nil
+			inputAssetCopy := inputAsset.CopyWithoutSplitCommitment()
 
 			// Build the new Taproot Asset tree by first updating
 			// the asset commitment tree with the new asset leaf,
@@ -1278,16 +1966,22 @@ func adjustFundedPsbt(fPkt *tapgarden.FundedPsbt, anchorInputValue int64) {
 	fPkt.ChangeOutputIndex = int32(maxOutputIndex)
 }
 
-// addAnchorPsbtInputs adds anchor information from all inputs to the PSBT
-// packet. This is called after the PSBT has been funded, but before signing.
-func addAnchorPsbtInputs(btcPkt *psbt.Packet, vPkt *tappsbt.VPacket,
+// addAnchorPsbtInputs adds anchor information from all inputs of all the
+// given virtual packets to the PSBT packet. This is called after the PSBT has
+// been funded, but before signing.
+func addAnchorPsbtInputs(btcPkt *psbt.Packet, vPkts []*tappsbt.VPacket,
 	feeRate chainfee.SatPerKWeight, params *chaincfg.Params) error {
 
-	for idx := range vPkt.Inputs {
+	var vInputs []*tappsbt.VInput
+	for idx := range vPkts {
+		vInputs = append(vInputs, vPkts[idx].Inputs...)
+	}
+
+	for idx := range vInputs {
 		// With the BIP-0032 information completed, we'll now add the
 		// information as a partial input and also add the input to the
 		// unsigned transaction.
-		vIn := vPkt.Inputs[idx]
+		vIn := vInputs[idx]
 		btcPkt.Inputs = append(btcPkt.Inputs, psbt.PInput{
 			WitnessUtxo: &wire.TxOut{
 				Value:    int64(vIn.Anchor.Value),
@@ -3,30 +3,67 @@ package tapfreighter
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/btcsuite/btcd/wire"
 	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightninglabs/taproot-assets/internal/test"
+	"github.com/lightninglabs/taproot-assets/tapgarden"
+	"github.com/lightninglabs/taproot-assets/tapscript"
 	"github.com/stretchr/testify/require"
 )
 
 // mockCoinLister is a mock implementation of the CoinLister interface.
 type mockCoinLister struct {
 	eligibleCommitments []*AnchoredCommitment
+
+	constraints []CommitmentConstraints
 }
 
 func (m *mockCoinLister) ListEligibleCoins(
 	ctx context.Context, constraints CommitmentConstraints) (
 	[]*AnchoredCommitment, error) {
 
+	m.constraints = append(m.constraints, constraints)
 	return m.eligibleCommitments, nil
 }
 
+func (m *mockCoinLister) LeaseCoins(context.Context, [32]byte, time.Time,
+	...wire.OutPoint) error {
+
+	return nil
+}
+
+func (m *mockCoinLister) ReleaseCoins(context.Context, ...wire.OutPoint) error {
+	return nil
+}
+
+func (m *mockCoinLister) DeleteExpiredLeases(context.Context) error {
+	return nil
+}
+
 // TestCoinSelection tests that the coin selection logic behaves as expected.
 func TestCoinSelection(t *testing.T) {
 	t.Parallel()
 
+	// withAmounts creates a set of commitments with the given amounts.
+	withAmounts := func(amounts ...uint64) []*AnchoredCommitment {
+		commitments := make([]*AnchoredCommitment, len(amounts))
+		for idx, amount := range amounts {
+			commitments[idx] = &AnchoredCommitment{
+				Asset: &asset.Asset{
+					Amount: amount,
+				},
+			}
+		}
+
+		return commitments
+	}
+
 	type testCase struct {
 		minTotalAmount      uint64
 		eligibleCommitments []*AnchoredCommitment
+		defaultStrategy     MultiCommitmentSelectStrategy
 		strategy            MultiCommitmentSelectStrategy
 
 		// Result analysis parameters.
@@ -114,6 +151,81 @@ func TestCoinSelection(t *testing.T) {
 				},
 			},
 		},
+
+		// Test that when the PreferMinAmount strategy is employed
+		// the smallest commitments are selected first.
+		{
+			minTotalAmount:           1000,
+			eligibleCommitments:      withAmounts(510, 2000, 490),
+			strategy:                 PreferMinAmount,
+			checkSelectedCommitments: true,
+			expectedCommitments:      withAmounts(490, 510),
+		},
+
+		// Test that when the PreferSingleCoin strategy is employed
+		// the smallest commitment covering the full amount is
+		// selected.
+		{
+			minTotalAmount: 1000,
+			eligibleCommitments: withAmounts(
+				2000, 400, 1500, 1000,
+			),
+			strategy:                 PreferSingleCoin,
+			checkSelectedCommitments: true,
+			expectedCommitments:      withAmounts(1000),
+		},
+
+		// Test that when the PreferSingleCoin strategy is employed
+		// and no single commitment covers the full amount, the
+		// largest commitments are selected first.
+		{
+			minTotalAmount:           1000,
+			eligibleCommitments:      withAmounts(980, 10, 999),
+			strategy:                 PreferSingleCoin,
+			checkSelectedCommitments: true,
+			expectedCommitments:      withAmounts(999, 980),
+		},
+
+		// Test that when the PreferRandom strategy is employed and
+		// all commitments are needed, all of them are selected.
+		{
+			minTotalAmount:           1500,
+			eligibleCommitments:      withAmounts(500, 500, 500),
+			strategy:                 PreferRandom,
+			checkSelectedCommitments: true,
+			expectedCommitments:      withAmounts(500, 500, 500),
+		},
+
+		// Test that the PreferRandom strategy returns an error if the
+		// commitments don't cover the full amount.
+		{
+			minTotalAmount:      2000,
+			eligibleCommitments: withAmounts(500, 500, 500),
+			strategy:            PreferRandom,
+			expectedSomeErr:     true,
+		},
+
+		// Test that the default strategy of the coin selector is used
+		// if no specific strategy is requested.
+		{
+			minTotalAmount:           1000,
+			eligibleCommitments:      withAmounts(510, 2000, 490),
+			defaultStrategy:          PreferMinAmount,
+			strategy:                 DefaultSelectStrategy,
+			checkSelectedCommitments: true,
+			expectedCommitments:      withAmounts(490, 510),
+		},
+
+		// Test that the coin selector falls back to the
+		// PreferMaxAmount strategy if no default strategy is
+		// configured.
+		{
+			minTotalAmount:           1000,
+			eligibleCommitments:      withAmounts(510, 2000, 490),
+			strategy:                 DefaultSelectStrategy,
+			checkSelectedCommitments: true,
+			expectedCommitments:      withAmounts(2000),
+		},
 	}
 
 	// Execute test cases.
@@ -121,7 +233,9 @@ func TestCoinSelection(t *testing.T) {
 		coinLister := &mockCoinLister{
 			eligibleCommitments: testCase.eligibleCommitments,
 		}
-		coinSelect := NewCoinSelect(coinLister)
+		coinSelect := NewCoinSelect(
+			coinLister, testCase.defaultStrategy,
+		)
 
 		resultCommitments, err := coinSelect.SelectForAmount(
 			testCase.minTotalAmount, testCase.eligibleCommitments,
@@ -147,3 +261,106 @@ func TestCoinSelection(t *testing.T) {
 		_ = idx
 	}
 }
+
+// heightChainBridge is a chain bridge that only reports a fixed chain height.
+type heightChainBridge struct {
+	tapgarden.ChainBridge
+
+	height uint32
+}
+
+func (h *heightChainBridge) CurrentHeight(context.Context) (uint32, error) {
+	return h.height, nil
+}
+
+// TestSelectInputsMinConfs tests that only assets with enough confirmations
+// are selected as inputs if a minimum number of confirmations is configured.
+func TestSelectInputsMinConfs(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	coinLister := &mockCoinLister{
+		eligibleCommitments: []*AnchoredCommitment{{
+			Asset: &asset.Asset{
+				Amount: 10,
+			},
+		}},
+	}
+	chainBridge := &heightChainBridge{
+		height: 110,
+	}
+	wallet := NewAssetWallet(&WalletConfig{
+		CoinSelector: NewCoinSelect(coinLister, PreferMaxAmount),
+		ChainBridge:  chainBridge,
+	})
+	fundDesc := &tapscript.FundingDescriptor{
+		Amount: 5,
+	}
+
+	// Without a minimum, assets of any confirmation depth are eligible.
+	_, err := wallet.selectInputs(ctx, fundDesc, DefaultSelectStrategy)
+	require.NoError(t, err)
+	require.Zero(t, coinLister.constraints[0].MaxAnchorHeight)
+
+	// With six confirmations required at a height of 110, the anchor
+	// transaction must be confirmed at height 105 or below.
+	wallet.cfg.MinInputConfs = 6
+	_, err = wallet.selectInputs(ctx, fundDesc, DefaultSelectStrategy)
+	require.NoError(t, err)
+	require.EqualValues(t, 105, coinLister.constraints[1].MaxAnchorHeight)
+
+	// A chain that is too short can't have any eligible assets.
+	chainBridge.height = 4
+	_, err = wallet.selectInputs(ctx, fundDesc, DefaultSelectStrategy)
+	require.ErrorIs(t, err, ErrMatchingAssetsNotFound)
+	require.Len(t, coinLister.constraints, 2)
+}
+
+// TestSelectPrevIDs tests that explicitly requested inputs are only selected
+// if they are eligible and cover the requested amount.
+func TestSelectPrevIDs(t *testing.T) {
+	t.Parallel()
+
+	newCommitment := func(amount uint64) (*AnchoredCommitment,
+		asset.PrevID) {
+
+		a := &asset.Asset{
+			Amount:    amount,
+			ScriptKey: asset.NewScriptKey(test.RandPubKey(t)),
+		}
+		c := &AnchoredCommitment{
+			AnchorPoint: test.RandOp(t),
+			Asset:       a,
+		}
+
+		return c, asset.PrevID{
+			OutPoint:  c.AnchorPoint,
+			ID:        a.ID(),
+			ScriptKey: asset.ToSerialized(a.ScriptKey.PubKey),
+		}
+	}
+
+	c1, prevID1 := newCommitment(500)
+	c2, prevID2 := newCommitment(700)
+	_, unknownPrevID := newCommitment(1000)
+	eligible := []*AnchoredCommitment{c1, c2}
+
+	selected, err := selectPrevIDs(
+		1000, eligible, []asset.PrevID{prevID2, prevID1},
+	)
+	require.NoError(t, err)
+	require.Equal(t, []*AnchoredCommitment{c2, c1}, selected)
+
+	_, err = selectPrevIDs(1000, eligible, []asset.PrevID{prevID2})
+	require.ErrorIs(t, err, ErrMatchingAssetsNotFound)
+
+	_, err = selectPrevIDs(
+		500, eligible, []asset.PrevID{prevID1, unknownPrevID},
+	)
+	require.ErrorIs(t, err, ErrMatchingAssetsNotFound)
+
+	_, err = selectPrevIDs(
+		500, eligible, []asset.PrevID{prevID1, prevID1},
+	)
+	require.ErrorContains(t, err, "duplicate input")
+}
@@ -5,9 +5,12 @@ import (
 	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
 	"github.com/lightninglabs/taproot-assets/asset"
 	"github.com/lightninglabs/taproot-assets/commitment"
+	"github.com/lightninglabs/taproot-assets/monitoring"
 	"github.com/lightninglabs/taproot-assets/proof"
 	"github.com/lightninglabs/taproot-assets/tapscript"
 	"github.com/lightningnetwork/lnd/keychain"
@@ -62,12 +65,20 @@ type MintingBatch struct {
 	// reveal for that asset, if it has one.
 	AssetMetas AssetMetas
 
+	// TapSibling is an optional tapscript sibling preimage that is
+	// committed to alongside the Taproot Asset commitment in the minting
+	// output. If this is nil, the Taproot Asset commitment is the only
+	// leaf of the tapscript tree of the minting output.
+	TapSibling *commitment.TapscriptPreimage
+
 	// mintingPubKey is the top-level Taproot output key that will be
 	// used to commit to the Taproot Asset commitment above.
 	mintingPubKey *btcec.PublicKey
 
-	// taprootAssetScriptRoot is the root hash of the Taproot Asset
-	// commitment. If this is nil, then the mintingPubKey will be as well.
+	// taprootAssetScriptRoot is the merkle root of the tapscript tree of
+	// the minting output, which commits to the Taproot Asset commitment
+	// and the tapscript sibling, if there is one. If this is nil, then the
+	// mintingPubKey will be as well.
 	taprootAssetScriptRoot []byte
 }
 
@@ -101,6 +112,14 @@ func (m *MintingBatch) validateGroupAnchor(s *Seedling) error {
 	return nil
 }
 
+// CorrelationID returns the ID that identifies the batch in the logs of all
+// the subsystems involved in minting it.
+func (m *MintingBatch) CorrelationID() monitoring.CorrelationID {
+	return monitoring.CorrelationIDFromBytes(
+		"batch", m.BatchKey.PubKey.SerializeCompressed(),
+	)
+}
+
 // MintingOutputKey derives the output key that once mined, will commit to the
 // Taproot asset root, thereby creating the set of included assets.
 func (m *MintingBatch) MintingOutputKey() (*btcec.PublicKey, []byte, error) {
@@ -112,7 +131,14 @@ func (m *MintingBatch) MintingOutputKey() (*btcec.PublicKey, []byte, error) {
 		return nil, nil, fmt.Errorf("no asset commitment present")
 	}
 
-	taprootAssetScriptRoot := m.RootAssetCommitment.TapscriptRoot(nil)
+	siblingHash, err := tapSiblingHash(m.TapSibling)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	taprootAssetScriptRoot := m.RootAssetCommitment.TapscriptRoot(
+		siblingHash,
+	)
 
 	m.taprootAssetScriptRoot = taprootAssetScriptRoot[:]
 	m.mintingPubKey = txscript.ComputeTaprootOutputKey(
@@ -132,3 +158,67 @@ func (m *MintingBatch) genesisScript() ([]byte, error) {
 
 	return tapscript.PayToTaprootScript(mintingOutputKey)
 }
+
+// BatchPreview describes what a pending batch would create if it was finalized
+// right away. The genesis transaction of the finalized batch will differ, as
+// it is funded again and new script keys are derived for its assets.
+type BatchPreview struct {
+	// BatchKey is the key of the previewed batch.
+	BatchKey *btcec.PublicKey
+
+	// GenesisPacket is the unsigned genesis packet of the batch. Its chain
+	// fees are set to the fees the transaction would pay.
+	GenesisPacket *FundedPsbt
+
+	// AnchorOutputIndex is the index of the output of the genesis
+	// transaction that would commit to the assets of the batch.
+	AnchorOutputIndex uint32
+
+	// AnchorOutput is the output of the genesis transaction that would
+	// commit to the assets of the batch.
+	AnchorOutput *wire.TxOut
+
+	// RootAssetCommitment is the Taproot Asset commitment the anchor
+	// output would commit to.
+	RootAssetCommitment *commitment.TapCommitment
+
+	// Assets holds the TLV encoding of every asset the batch would create.
+	Assets [][]byte
+}
+
+// tapSiblingHash returns the tap hash of the given tapscript sibling preimage,
+// or nil if there is no sibling.
+func tapSiblingHash(
+	tapSibling *commitment.TapscriptPreimage) (*chainhash.Hash, error) {
+
+	if tapSibling == nil {
+		return nil, nil
+	}
+
+	siblingHash, err := tapSibling.TapHash()
+	if err != nil {
+		return nil, fmt.Errorf("invalid tapscript sibling: %w", err)
+	}
+
+	return siblingHash, nil
+}
+
+// mintingOutputScript returns the script of a minting output that commits to
+// the given Taproot Asset commitment and the optional tapscript sibling, using
+// the batch key as internal key.
+func mintingOutputScript(batchKey *btcec.PublicKey,
+	tapCommitment *commitment.TapCommitment,
+	tapSibling *commitment.TapscriptPreimage) ([]byte, error) {
+
+	siblingHash, err := tapSiblingHash(tapSibling)
+	if err != nil {
+		return nil, err
+	}
+
+	tapscriptRoot := tapCommitment.TapscriptRoot(siblingHash)
+	mintingOutputKey := txscript.ComputeTaprootOutputKey(
+		batchKey, tapscriptRoot[:],
+	)
+
+	return tapscript.PayToTaprootScript(mintingOutputKey)
+}
@@ -3,6 +3,7 @@ package tapgarden
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"sync"
@@ -11,17 +12,25 @@ import (
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/btcutil/psbt"
 	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btclog"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/lightninglabs/taproot-assets/asset"
 	"github.com/lightninglabs/taproot-assets/chanutils"
 	"github.com/lightninglabs/taproot-assets/commitment"
+	"github.com/lightninglabs/taproot-assets/monitoring"
 	"github.com/lightninglabs/taproot-assets/proof"
 	"github.com/lightninglabs/taproot-assets/universe"
 	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"golang.org/x/exp/maps"
 )
 
 var (
+	// errCaretakerShutdown is returned if a step of the batch is aborted
+	// because the caretaker is shutting down.
+	errCaretakerShutdown = errors.New("caretaker shutting down")
+
 	// GenesisDummyScript is a dummy script that we'll use to fund the
 	// initial PSBT packet that'll create initial set of assets. It's the
 	// same size as a encoded P2TR output.
@@ -49,6 +58,11 @@ const (
 	// DefaultTimeout is the default timeout we use for RPC and database
 	// operations.
 	DefaultTimeout = 30 * time.Second
+
+	// confWaitDeadline is the time after which the watchdog reports a
+	// caretaker that is still waiting for its minting transaction to
+	// confirm as stalled.
+	confWaitDeadline = 24 * time.Hour
 )
 
 // BatchCaretakerConfig houses all the items that the BatchCaretaker needs to
@@ -71,9 +85,34 @@ type BatchCaretakerConfig struct {
 	// attempted batch cancellation to the planter.
 	CancelRespChan chan CancelResp
 
-	// ErrChan is the main error channel the caretaker will report back
-	// critical errors to the main server.
-	ErrChan chan<- error
+	// BatchFeeRate is an optional manually set fee rate that is used to
+	// fund the genesis transaction of the batch. If nil, the fee rate is
+	// estimated for GenesisConfTarget.
+	BatchFeeRate *chainfee.SatPerKWeight
+
+	// CoinControl optionally restricts the wallet UTXOs the genesis
+	// transaction of the batch may spend.
+	CoinControl *CoinControl
+
+	// ExternalPsbt is an optional externally funded PSBT packet the
+	// genesis output of the batch is committed into. If set, the
+	// caretaker doesn't have the wallet fund and sign the genesis
+	// transaction, but waits for the signed packet to be handed back.
+	ExternalPsbt *psbt.Packet
+}
+
+// anchorPsbtResp is the result of committing the genesis output of a batch
+// into an externally funded PSBT packet.
+type anchorPsbtResp struct {
+	pkt *psbt.Packet
+	err error
+}
+
+// signedPsbtReq is a request to broadcast the externally signed genesis PSBT
+// packet of a batch.
+type signedPsbtReq struct {
+	pkt *psbt.Packet
+	err chan error
 }
 
 // BatchCaretaker is the caretaker for a MintingBatch. It'll handle validating
@@ -87,6 +126,10 @@ type BatchCaretaker struct {
 
 	cfg *BatchCaretakerConfig
 
+	// log is the logger of the caretaker, which tags every line with the
+	// correlation ID of the batch.
+	log btclog.Logger
+
 	// confEvent is used to deliver a confirmation event to the caretaker.
 	confEvent chan *chainntnfs.TxConfirmation
 
@@ -97,6 +140,15 @@ type BatchCaretaker struct {
 	// the Taproot Asset commitment.
 	anchorOutputIndex uint32
 
+	// anchorPsbts is used to deliver the unsigned genesis packet of an
+	// externally funded batch once the genesis output was committed into
+	// it.
+	anchorPsbts chan anchorPsbtResp
+
+	// signedPsbts is used to hand the externally signed genesis packet to
+	// the caretaker.
+	signedPsbts chan *signedPsbtReq
+
 	// ContextGuard provides a wait group and main quit channel that can be
 	// used to create guarded contexts.
 	*chanutils.ContextGuard
@@ -107,10 +159,18 @@ type BatchCaretaker struct {
 //
 // TODO(roasbeef): rename to Cultivator?
 func NewBatchCaretaker(cfg *BatchCaretakerConfig) *BatchCaretaker {
+	batchLog := monitoring.CorrelatedLogger(
+		log, cfg.Batch.CorrelationID(),
+	)
+
 	return &BatchCaretaker{
 		batchKey:  asset.ToSerialized(cfg.Batch.BatchKey.PubKey),
 		cfg:       cfg,
+		log:       batchLog,
 		confEvent: make(chan *chainntnfs.TxConfirmation, 1),
+
+		anchorPsbts: make(chan anchorPsbtResp, 1),
+		signedPsbts: make(chan *signedPsbtReq),
 		ContextGuard: &chanutils.ContextGuard{
 			DefaultTimeout: DefaultTimeout,
 			Quit:           make(chan struct{}),
@@ -166,7 +226,7 @@ func (b *BatchCaretaker) Cancel() CancelResp {
 
 		return CancelResp{&finalBatchState, err}
 
-	case BatchStateCommitted:
+	case BatchStateCommitted, BatchStateAwaitingExternalSig:
 		finalBatchState := BatchStateSproutCancelled
 		err := b.cfg.Log.UpdateBatchState(
 			ctx, b.cfg.Batch.BatchKey.PubKey,
@@ -191,8 +251,8 @@ func (b *BatchCaretaker) Cancel() CancelResp {
 func (b *BatchCaretaker) advanceStateUntil(currentState,
 	targetState BatchState) (BatchState, error) {
 
-	log.Infof("BatchCaretaker(%x), advancing from state=%v to state=%v",
-		b.batchKey[:], currentState, targetState)
+	b.log.Infof("Advancing from state=%v to state=%v", currentState,
+		targetState)
 
 	var terminalState bool
 	for !terminalState {
@@ -251,13 +311,13 @@ func (b *BatchCaretaker) assetCultivator() {
 	// If the batch is already marked as confirmed, then we just need to
 	// advance it one more level to be finalized.
 	if b.cfg.Batch.BatchState == BatchStateConfirmed {
-		log.Infof("MintingBatch(%x): already confirmed!", b.batchKey[:])
+		b.log.Infof("Batch already confirmed!")
 
 		_, err := b.advanceStateUntil(
 			BatchStateFinalized, BatchStateFinalized,
 		)
 		if err != nil {
-			log.Error(err)
+			b.log.Error(err)
 			return
 		}
 
@@ -270,14 +330,44 @@ func (b *BatchCaretaker) assetCultivator() {
 	// for some reason. If we can broadcast, then we'll await a
 	// confirmation notification, which'll let us advance to the final
 	// state.
-	_, err := b.advanceStateUntil(
+	batchState, err := b.advanceStateUntil(
 		b.cfg.Batch.BatchState, BatchStateBroadcast,
 	)
 	if err != nil {
-		log.Errorf("unable to advance state machine: %v", err)
+		b.log.Errorf("unable to advance state machine: %v", err)
+
+		// Anyone waiting for the genesis packet of an externally
+		// funded batch needs to know that it won't be committed.
+		b.sendAnchorPsbt(anchorPsbtResp{err: err})
 		return
 	}
 
+	// If the batch couldn't be funded, there's nothing left to do for us,
+	// so the planter can clean up after this caretaker.
+	if batchState == BatchStateFundingFailed {
+		b.cfg.SignalCompletion()
+		return
+	}
+
+	// If the genesis transaction is funded externally, the state machine
+	// pauses until the signed transaction is handed back to us, after
+	// which it can be broadcast.
+	if batchState == BatchStateAwaitingExternalSig {
+		if !b.awaitExternalSig() {
+			return
+		}
+
+		b.cfg.Batch.BatchState = BatchStateBroadcast
+		_, err = b.advanceStateUntil(
+			BatchStateBroadcast, BatchStateBroadcast,
+		)
+		if err != nil {
+			b.log.Errorf("unable to advance state machine: %v",
+				err)
+			return
+		}
+	}
+
 	// TODO(roasbeef): proper restart logic?
 
 	// At this point, we've advanced all the way to broadcasting the
@@ -290,9 +380,9 @@ func (b *BatchCaretaker) assetCultivator() {
 		// We've received the confirmation notification, so we can
 		// advance our state machine through the final two phases.
 		case confInfo := <-b.confEvent:
-			log.Infof("MintingBatch(%x): confirmed at block("+
-				"hash=%v, height=%v)", b.batchKey[:],
-				confInfo.BlockHash, confInfo.BlockHeight)
+			b.log.Infof("Batch confirmed at block(hash=%v, "+
+				"height=%v)", confInfo.BlockHash,
+				confInfo.BlockHeight)
 
 			b.confInfo = confInfo
 			b.cfg.Batch.BatchState = BatchStateConfirmed
@@ -302,7 +392,7 @@ func (b *BatchCaretaker) assetCultivator() {
 				b.cfg.Batch.BatchState, BatchStateFinalized,
 			)
 			if err != nil {
-				log.Error(err)
+				b.log.Error(err)
 				return
 			}
 
@@ -329,8 +419,7 @@ func (b *BatchCaretaker) assetCultivator() {
 // We need to use a dummy script as we can't know the actual script key since
 // that's dependent on the genesis outpoint.
 func (b *BatchCaretaker) fundGenesisPsbt(ctx context.Context) (*FundedPsbt, error) {
-	log.Infof("BatchCaretaker(%x): attempting to fund GenesisPacket",
-		b.batchKey[:])
+	b.log.Infof("Attempting to fund GenesisPacket")
 
 	txTemplate := wire.NewMsgTx(2)
 	txTemplate.AddTxOut(&DummyGenesisTxOut)
@@ -339,35 +428,309 @@ func (b *BatchCaretaker) fundGenesisPsbt(ctx context.Context) (*FundedPsbt, erro
 		return nil, fmt.Errorf("unable to make psbt packet: %w", err)
 	}
 
-	log.Infof("BatchCaretaker(%x): creating skeleton PSBT", b.batchKey[:])
-	log.Tracef("PSBT: %v", spew.Sdump(genesisPkt))
+	b.log.Infof("Creating skeleton PSBT")
+	b.log.Tracef("PSBT: %v", spew.Sdump(genesisPkt))
 
-	feeRate, err := b.cfg.ChainBridge.EstimateFee(
-		ctx, GenesisConfTarget,
-	)
-	if err != nil {
-		return nil, fmt.Errorf("unable to estimate fee: %w", err)
+	var feeRate chainfee.SatPerKWeight
+	switch {
+	// If a fee rate was manually assigned for this batch, we use that
+	// instead of a fee rate estimate.
+	case b.cfg.BatchFeeRate != nil:
+		feeRate = *b.cfg.BatchFeeRate
+		b.log.Infof("Using manual fee rate: %v (%d sat/vB)", feeRate,
+			feeRate.FeePerKVByte()/1000)
+
+	default:
+		feeRate, err = b.cfg.ChainBridge.EstimateFee(
+			ctx, GenesisConfTarget,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to estimate fee: %w",
+				err)
+		}
 	}
 
 	fundedGenesisPkt, err := b.cfg.Wallet.FundPsbt(
-		ctx, genesisPkt, 1, feeRate,
+		ctx, genesisPkt, 1, feeRate, b.cfg.CoinControl,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("unable to fund psbt: %w", err)
 	}
 
-	log.Infof("BatchCaretaker(%x): funded GenesisPacket", b.batchKey[:])
-	log.Tracef("GenesisPacket: %v", spew.Sdump(fundedGenesisPkt))
+	b.log.Infof("Funded GenesisPacket")
+	b.log.Tracef("GenesisPacket: %v", spew.Sdump(fundedGenesisPkt))
 
 	return &fundedGenesisPkt, nil
 }
 
+// anchorExternalPsbt appends the genesis output of the batch to a copy of the
+// externally funded PSBT packet. The first input of the packet is used as the
+// genesis point of the batch.
+func (b *BatchCaretaker) anchorExternalPsbt() (*FundedPsbt, error) {
+	b.log.Infof("Anchoring GenesisPacket in external PSBT")
+
+	// We'll work on a copy of the packet, so the caller's packet isn't
+	// modified.
+	var buf bytes.Buffer
+	if err := b.cfg.ExternalPsbt.Serialize(&buf); err != nil {
+		return nil, fmt.Errorf("unable to encode external psbt: %w",
+			err)
+	}
+	genesisPkt, err := psbt.NewFromRawBytes(&buf, false)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode external psbt: %w",
+			err)
+	}
+
+	if len(genesisPkt.UnsignedTx.TxIn) == 0 {
+		return nil, fmt.Errorf("external psbt has no inputs")
+	}
+
+	genesisOut := DummyGenesisTxOut
+	genesisPkt.UnsignedTx.AddTxOut(&genesisOut)
+	genesisPkt.Outputs = append(genesisPkt.Outputs, psbt.POutput{})
+
+	b.log.Tracef("GenesisPacket: %v", spew.Sdump(genesisPkt))
+
+	// None of the outputs of an external packet are ours, so there's no
+	// change output.
+	return &FundedPsbt{
+		Pkt:               genesisPkt,
+		ChangeOutputIndex: -1,
+	}, nil
+}
+
+// commitSignedGenesisPsbt writes the fully signed genesis packet of the batch
+// to disk, and imports the minting output into the wallet.
+func (b *BatchCaretaker) commitSignedGenesisPsbt(ctx context.Context,
+	signedPkt *psbt.Packet) error {
+
+	b.cfg.Batch.GenesisPacket.Pkt = signedPkt
+
+	// Populate how much this tx paid in on-chain fees.
+	chainFees, err := GetTxFee(signedPkt)
+	if err != nil {
+		return fmt.Errorf("unable to get on-chain fees for psbt: %w",
+			err)
+	}
+	b.cfg.Batch.GenesisPacket.ChainFees = chainFees
+
+	b.log.Infof("GenesisPacket finalized")
+	b.log.Tracef("GenesisPacket: %v", spew.Sdump(signedPkt))
+
+	// At this point we have a fully signed PSBT packet which'll create our
+	// set of assets once mined. We'll write this to disk, then import the
+	// public key into the wallet.
+	//
+	// TODO(roasbeef): re-run during the broadcast phase to ensure it's
+	// fully imported?
+	mintingOutputKey, merkleRoot, err := b.cfg.Batch.MintingOutputKey()
+	if err != nil {
+		return err
+	}
+	tapTreeRoot := b.cfg.Batch.RootAssetCommitment.TapscriptRoot(nil)
+	siblingBytes, _, err := commitment.MaybeEncodeTapscriptPreimage(
+		b.cfg.Batch.TapSibling,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to encode tapscript sibling: %w",
+			err)
+	}
+	err = b.cfg.Log.CommitSignedGenesisTx(
+		ctx, b.cfg.Batch.BatchKey.PubKey, b.cfg.Batch.GenesisPacket,
+		b.anchorOutputIndex, merkleRoot, tapTreeRoot[:], siblingBytes,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to commit genesis tx: %w", err)
+	}
+
+	// With the genesis transaction committed to disk, we'll also import
+	// this public key into the backing wallet, so it recognizes the de
+	// minimis amt sats under out control.
+	//
+	// TODO(roasbeef): should be idempotent along w/ all other operations
+	// above
+	importCtx, cancel := b.WithCtxQuit()
+	defer cancel()
+	_, err = b.cfg.Wallet.ImportTaprootOutput(importCtx, mintingOutputKey)
+	switch {
+	case err == nil:
+		break
+
+	// On restart, we'll get an error that the output has already been
+	// added to the wallet, so we'll catch this now and move along if so.
+	case strings.Contains(err.Error(), "already exists"):
+		break
+
+	case err != nil:
+		return fmt.Errorf("unable to import key: %w", err)
+	}
+
+	return nil
+}
+
+// sendAnchorPsbt delivers the result of committing the genesis output of the
+// batch into an externally funded packet. If an earlier result wasn't picked
+// up yet, the new result is dropped.
+func (b *BatchCaretaker) sendAnchorPsbt(resp anchorPsbtResp) {
+	select {
+	case b.anchorPsbts <- resp:
+	default:
+	}
+}
+
+// waitForAnchorPsbt blocks until the genesis output of the externally funded
+// batch was committed into the funded packet, and returns the unsigned genesis
+// packet.
+func (b *BatchCaretaker) waitForAnchorPsbt() (*psbt.Packet, error) {
+	select {
+	case resp := <-b.anchorPsbts:
+		return resp.pkt, resp.err
+
+	case <-b.Quit:
+		return nil, fmt.Errorf("BatchCaretaker(%x), shutting down",
+			b.batchKey[:])
+	}
+}
+
+// submitSignedPsbt hands the externally signed genesis packet to the
+// caretaker, and blocks until it was either accepted or rejected.
+func (b *BatchCaretaker) submitSignedPsbt(signedPkt *psbt.Packet) error {
+	req := &signedPsbtReq{
+		pkt: signedPkt,
+		err: make(chan error, 1),
+	}
+
+	// The caretaker only accepts a signed packet while it's waiting for
+	// one, so we don't wait forever if the batch is in any other state.
+	select {
+	case b.signedPsbts <- req:
+	case <-time.After(DefaultTimeout):
+		return fmt.Errorf("BatchCaretaker(%x), not awaiting signed "+
+			"psbt", b.batchKey[:])
+
+	case <-b.Quit:
+		return fmt.Errorf("BatchCaretaker(%x), shutting down",
+			b.batchKey[:])
+	}
+
+	select {
+	case err := <-req.err:
+		return err
+
+	case <-b.Quit:
+		return fmt.Errorf("BatchCaretaker(%x), shutting down",
+			b.batchKey[:])
+	}
+}
+
+// awaitExternalSig waits until the externally signed genesis packet of the
+// batch was handed to the caretaker and committed to disk. False is returned
+// if the batch was cancelled or the caretaker is shutting down instead.
+func (b *BatchCaretaker) awaitExternalSig() bool {
+	b.log.Infof("Waiting for externally signed GenesisPacket")
+
+	for {
+		select {
+		case req := <-b.signedPsbts:
+			err := b.commitExternalSig(req.pkt)
+			req.err <- err
+			if err != nil {
+				b.log.Warnf("Rejected signed GenesisPacket: %v",
+					err)
+				continue
+			}
+
+			b.log.Infof("Transition states: %v -> %v",
+				BatchStateAwaitingExternalSig,
+				BatchStateBroadcast)
+
+			return true
+
+		case <-b.cfg.CancelReqChan:
+			cancelResp := b.Cancel()
+			b.cfg.CancelRespChan <- cancelResp
+
+			if cancelResp.finalState != nil {
+				return false
+			}
+
+		case <-b.Quit:
+			return false
+		}
+	}
+}
+
+// commitExternalSig verifies that the externally signed packet spends and
+// creates the same outputs as the unsigned genesis packet of the batch, then
+// finalizes it and writes it to disk.
+func (b *BatchCaretaker) commitExternalSig(signedPkt *psbt.Packet) error {
+	genesisTx := b.cfg.Batch.GenesisPacket.Pkt.UnsignedTx
+	if signedPkt.UnsignedTx.TxHash() != genesisTx.TxHash() {
+		return fmt.Errorf("signed psbt doesn't match genesis tx %v",
+			genesisTx.TxHash())
+	}
+
+	if err := psbt.MaybeFinalizeAll(signedPkt); err != nil {
+		return fmt.Errorf("unable to finalize psbt: %w", err)
+	}
+	if !signedPkt.IsComplete() {
+		return fmt.Errorf("signed psbt isn't complete")
+	}
+
+	ctx, cancel := b.WithCtxQuit()
+	defer cancel()
+
+	return b.commitSignedGenesisPsbt(ctx, signedPkt)
+}
+
 // extractGenesisOutpoint extracts the genesis point (the first output from the
 // genesis transaction).
 func extractGenesisOutpoint(tx *wire.MsgTx) wire.OutPoint {
 	return tx.TxIn[0].PreviousOutPoint
 }
 
+// deriveTapscriptGroupKey creates the group key and witness for a new asset in
+// a group that commits to the given tapscript root. The issuance is authorized
+// through the given script spend if one is set, or with a key spend of the
+// group's internal key otherwise.
+func deriveTapscriptGroupKey(genSigner asset.GenesisSigner,
+	rawKey keychain.KeyDescriptor, tapscriptRoot []byte,
+	spend *asset.GroupScriptSpend, gen asset.Genesis) (*asset.GroupKey,
+	error) {
+
+	if spend == nil {
+		return asset.DeriveTapscriptGroupKey(
+			genSigner, rawKey, tapscriptRoot, gen,
+		)
+	}
+
+	return asset.DeriveScriptSpendGroupKey(
+		genSigner, rawKey, tapscriptRoot, spend, gen,
+	)
+}
+
+// validateGroupMember checks that the group key created for a new asset with
+// the given genesis belongs to the given existing group, and that its witness
+// authorizes the issuance of the asset into the group.
+func validateGroupMember(gen asset.Genesis, group *asset.AssetGroup,
+	groupKey *asset.GroupKey) error {
+
+	if groupKey == nil ||
+		!groupKey.GroupPubKey.IsEqual(&group.GroupPubKey) {
+
+		return fmt.Errorf("%w: group key mismatch",
+			ErrInvalidGroupMember)
+	}
+
+	if !gen.VerifyGroupWitness(groupKey) {
+		return fmt.Errorf("%w: invalid group witness",
+			ErrInvalidGroupMember)
+	}
+
+	return nil
+}
+
 // seedlingsToAssetSprouts maps a set of seedlings in the internal batch into a
 // set of sprouts: Assets that aren't yet fully linked to broadcast genesis
 // transaction.
@@ -375,9 +738,8 @@ func (b *BatchCaretaker) seedlingsToAssetSprouts(ctx context.Context,
 	genesisPoint wire.OutPoint,
 	assetOutputIndex uint32) (*commitment.TapCommitment, error) {
 
-	log.Infof("BatchCaretaker(%x): mapping %v seedlings to asset sprouts, "+
-		"with genesis_point=%v", b.batchKey[:],
-		len(b.cfg.Batch.Seedlings), genesisPoint)
+	b.log.Infof("Mapping %v seedlings to asset sprouts, with "+
+		"genesis_point=%v", len(b.cfg.Batch.Seedlings), genesisPoint)
 
 	newAssets := make([]*asset.Asset, 0, len(b.cfg.Batch.Seedlings))
 
@@ -432,7 +794,27 @@ func (b *BatchCaretaker) seedlingsToAssetSprouts(ctx context.Context,
 			groupInfo = newGroups[*seedling.GroupAnchor]
 		}
 
-		if groupInfo != nil {
+		switch {
+		// Groups that commit to a tapscript tree are signed over the
+		// group virtual transaction.
+		case groupInfo != nil &&
+			len(groupInfo.GroupKey.TapscriptRoot) != 0:
+
+			sproutGroupKey, err = deriveTapscriptGroupKey(
+				b.cfg.GenSigner, groupInfo.GroupKey.RawKey,
+				groupInfo.GroupKey.TapscriptRoot,
+				seedling.GroupScriptSpend, assetGen,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("unable to sign "+
+					"group witness: %w", err)
+			}
+
+		case groupInfo != nil && seedling.GroupScriptSpend != nil:
+			return nil, fmt.Errorf("seedling %v: %w", seedlingName,
+				ErrInvalidGroupScriptSpend)
+
+		case groupInfo != nil:
 			sproutGroupKey, err = asset.DeriveGroupKey(
 				b.cfg.GenSigner, groupInfo.GroupKey.RawKey,
 				*groupInfo.Genesis, &assetGen,
@@ -443,6 +825,19 @@ func (b *BatchCaretaker) seedlingsToAssetSprouts(ctx context.Context,
 			}
 		}
 
+		// A new tranche of an existing group must end up with the key
+		// of that group, and carry a valid witness for it, otherwise
+		// it wouldn't be recognized as a member of the group.
+		if groupInfo != nil {
+			err := validateGroupMember(
+				assetGen, groupInfo, sproutGroupKey,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("seedling %v: %w",
+					seedlingName, err)
+			}
+		}
+
 		// If emission is enabled without a group key specified,
 		// then we'll need to generate another public key,
 		// then use that to derive the key group signature
@@ -455,10 +850,19 @@ func (b *BatchCaretaker) seedlingsToAssetSprouts(ctx context.Context,
 				return nil, fmt.Errorf("unable to"+
 					"derive group key: %w", err)
 			}
-			sproutGroupKey, err = asset.DeriveGroupKey(
-				b.cfg.GenSigner, rawGroupKey,
-				assetGen, nil,
-			)
+			tapscriptRoot := seedling.GroupTapscriptRoot
+			if len(tapscriptRoot) != 0 {
+				sproutGroupKey, err = deriveTapscriptGroupKey(
+					b.cfg.GenSigner, rawGroupKey,
+					tapscriptRoot,
+					seedling.GroupScriptSpend, assetGen,
+				)
+			} else {
+				sproutGroupKey, err = asset.DeriveGroupKey(
+					b.cfg.GenSigner, rawGroupKey,
+					assetGen, nil,
+				)
+			}
 			if err != nil {
 				return nil, fmt.Errorf("unable to"+
 					"tweak group key: %w", err)
@@ -488,8 +892,36 @@ func (b *BatchCaretaker) seedlingsToAssetSprouts(ctx context.Context,
 			return nil, fmt.Errorf("unable to create new asset: %w",
 				err)
 		}
+		newAsset.NonDivisible = seedling.NonDivisible
+
+		if !seedling.Editions {
+			newAssets = append(newAssets, newAsset)
+			continue
+		}
 
+		// A collectible minted as editions results in one asset per
+		// edition, all sharing the same genesis and group key. Each
+		// edition gets its own script key, so it can be addressed and
+		// transferred individually.
+		newAsset.Edition = 1
 		newAssets = append(newAssets, newAsset)
+		for edition := uint64(2); edition <= seedling.Amount; edition++ {
+			editionKey, err := b.cfg.KeyRing.DeriveNextKey(
+				ctx, asset.TaprootAssetsKeyFamily,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("unable to obtain "+
+					"script key: %w", err)
+			}
+
+			editionAsset := newAsset.Copy()
+			editionAsset.ScriptKey = asset.NewScriptKeyBip86(
+				editionKey,
+			)
+			editionAsset.Edition = edition
+
+			newAssets = append(newAssets, editionAsset)
+		}
 	}
 
 	// Now that we have all our assets created, we'll make a new
@@ -498,6 +930,154 @@ func (b *BatchCaretaker) seedlingsToAssetSprouts(ctx context.Context,
 	return commitment.FromAssets(newAssets...)
 }
 
+// genesisPsbt returns the funded genesis packet of the batch, with the anchor
+// output still carrying the dummy genesis script, and sets the index of the
+// anchor output. A packet that was already funded before a restart is reused,
+// so the wallet doesn't lease a second set of inputs for the batch.
+func (b *BatchCaretaker) genesisPsbt(ctx context.Context) (*FundedPsbt, error) {
+	switch {
+	// If the batch is funded externally, the genesis output is appended to
+	// the packet that was handed to us.
+	case b.cfg.ExternalPsbt != nil:
+		genesisTxPkt, err := b.anchorExternalPsbt()
+		if err != nil {
+			return nil, err
+		}
+
+		outputs := genesisTxPkt.Pkt.UnsignedTx.TxOut
+		b.anchorOutputIndex = uint32(len(outputs) - 1)
+
+		return genesisTxPkt, nil
+
+	// If we funded the genesis packet before a restart, it was written to
+	// disk, so we only need to locate the anchor output again.
+	case b.cfg.Batch.GenesisPacket != nil:
+		b.log.Infof("Reusing funded GenesisPacket")
+
+		genesisTxPkt := b.cfg.Batch.GenesisPacket
+		anchorIndex, err := locateAnchorOutput(
+			genesisTxPkt.Pkt, GenesisDummyScript[:],
+		)
+		if err != nil {
+			return nil, err
+		}
+		b.anchorOutputIndex = anchorIndex
+
+		return genesisTxPkt, nil
+
+	default:
+		genesisTxPkt, err := b.fundGenesisPsbt(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		// If the change output is first, then our commitment is
+		// second, and vice versa.
+		b.anchorOutputIndex = 0
+		if genesisTxPkt.ChangeOutputIndex == 0 {
+			b.anchorOutputIndex = 1
+		}
+
+		return genesisTxPkt, nil
+	}
+}
+
+// sproutGenesisPsbt turns the seedlings of the batch into asset sprouts that
+// are anchored at the anchor output of the given genesis packet, and sets the
+// script of the anchor output to commit to them.
+func (b *BatchCaretaker) sproutGenesisPsbt(ctx context.Context,
+	genesisTxPkt *FundedPsbt) (*commitment.TapCommitment, error) {
+
+	genesisPoint := extractGenesisOutpoint(genesisTxPkt.Pkt.UnsignedTx)
+
+	// First, we'll turn all the seedlings into actual taproot assets.
+	tapCommitment, err := b.seedlingsToAssetSprouts(
+		ctx, genesisPoint, b.anchorOutputIndex,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to map seedlings to sprouts: "+
+			"%v", err)
+	}
+
+	// With the commitment Taproot Asset root SMT constructed, we'll map
+	// that into the tapscript root we'll insert into the genesis
+	// transaction.
+	genesisScript, err := mintingOutputScript(
+		b.cfg.Batch.BatchKey.PubKey, tapCommitment,
+		b.cfg.Batch.TapSibling,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create genesis script: %v",
+			err)
+	}
+
+	anchorOutput := genesisTxPkt.Pkt.UnsignedTx.TxOut[b.anchorOutputIndex]
+	anchorOutput.PkScript = genesisScript
+
+	return tapCommitment, nil
+}
+
+// locateAnchorOutput returns the index of the output of the given packet that
+// carries the given script.
+func locateAnchorOutput(pkt *psbt.Packet, pkScript []byte) (uint32, error) {
+	for i, txOut := range pkt.UnsignedTx.TxOut {
+		if bytes.Equal(txOut.PkScript, pkScript) {
+			return uint32(i), nil
+		}
+	}
+
+	return 0, fmt.Errorf("genesis output not found in genesis psbt")
+}
+
+// previewBatch builds the genesis packet and the sprouts of the batch without
+// committing to them. The inputs the wallet leased to fund the genesis packet
+// are unlocked again.
+func (b *BatchCaretaker) previewBatch(ctx context.Context) (*BatchPreview,
+	error) {
+
+	genesisTxPkt, err := b.genesisPsbt(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tapCommitment, err := b.sproutGenesisPsbt(ctx, genesisTxPkt)
+	if err != nil {
+		return nil, err
+	}
+
+	err = b.cfg.Wallet.UnlockInput(ctx, genesisTxPkt.LockedUTXOs)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unlock inputs: %w", err)
+	}
+
+	chainFees, err := GetTxFee(genesisTxPkt.Pkt)
+	if err != nil {
+		return nil, fmt.Errorf("unable to estimate on-chain fees for "+
+			"psbt: %w", err)
+	}
+	genesisTxPkt.ChainFees = chainFees
+
+	committedAssets := tapCommitment.CommittedAssets()
+	assets := make([][]byte, 0, len(committedAssets))
+	for _, newAsset := range committedAssets {
+		var buf bytes.Buffer
+		if err := newAsset.Encode(&buf); err != nil {
+			return nil, fmt.Errorf("unable to encode asset: %w",
+				err)
+		}
+		assets = append(assets, buf.Bytes())
+	}
+
+	outputs := genesisTxPkt.Pkt.UnsignedTx.TxOut
+	return &BatchPreview{
+		BatchKey:            b.cfg.Batch.BatchKey.PubKey,
+		GenesisPacket:       genesisTxPkt,
+		AnchorOutputIndex:   b.anchorOutputIndex,
+		AnchorOutput:        outputs[b.anchorOutputIndex],
+		RootAssetCommitment: tapCommitment,
+		Assets:              assets,
+	}, nil
+}
+
 // stateStep attempts to transition the state machine from one state to
 // another. Two states are terminal: the broadcast state, and the finalized
 // state.
@@ -516,8 +1096,8 @@ func (b *BatchCaretaker) stateStep(currentState BatchState) (BatchState, error)
 			return 0, err
 		}
 
-		log.Infof("BatchCaretaker(%x): transition states: %v -> %v",
-			b.batchKey, BatchStatePending, BatchStateFrozen)
+		b.log.Infof("Transition states: %v -> %v", BatchStatePending,
+			BatchStateFrozen)
 
 		return BatchStateFrozen, nil
 
@@ -531,48 +1111,51 @@ func (b *BatchCaretaker) stateStep(currentState BatchState) (BatchState, error)
 		//
 		// TODO(roasbeef): need to invalidate asset creation if on
 		// restart leases are gone
-		ctx, cancel := b.WithCtxQuit()
-		defer cancel()
-		genesisTxPkt, err := b.fundGenesisPsbt(ctx)
-		if err != nil {
+		var genesisTxPkt *FundedPsbt
+		err := b.retryStep("fund genesis psbt", func(
+			ctx context.Context) error {
+
+			var err error
+			genesisTxPkt, err = b.genesisPsbt(ctx)
+			return err
+		})
+		switch {
+		case errors.Is(err, errCaretakerShutdown):
 			return 0, err
+
+		// If we couldn't fund the batch, even after retrying, we'll
+		// mark only this batch as failed, so the planter can move on
+		// with any other batches.
+		case err != nil:
+			return b.failFunding(err)
 		}
 
-		genesisPoint := extractGenesisOutpoint(
-			genesisTxPkt.Pkt.UnsignedTx,
-		)
+		ctx, cancel := b.WithCtxQuit()
+		defer cancel()
 
-		// If the change output is first, then our commitment is second,
-		// and vice versa.
-		b.anchorOutputIndex = 0
-		if genesisTxPkt.ChangeOutputIndex == 0 {
-			b.anchorOutputIndex = 1
+		// A packet funded by our wallet is written to disk right away,
+		// so a restart before the sprouts are committed doesn't lease
+		// another set of inputs for the same batch.
+		walletFunded := b.cfg.ExternalPsbt == nil
+		if walletFunded && b.cfg.Batch.GenesisPacket == nil {
+			err = b.cfg.Log.CommitBatchTx(
+				ctx, b.cfg.Batch.BatchKey.PubKey, genesisTxPkt,
+			)
+			if err != nil {
+				return 0, fmt.Errorf("unable to commit funded "+
+					"genesis psbt: %w", err)
+			}
+			b.cfg.Batch.GenesisPacket = genesisTxPkt
 		}
 
-		// First, we'll turn all the seedlings into actual taproot assets.
-		tapCommitment, err := b.seedlingsToAssetSprouts(
-			ctx, genesisPoint, b.anchorOutputIndex,
-		)
+		tapCommitment, err := b.sproutGenesisPsbt(ctx, genesisTxPkt)
 		if err != nil {
-			return 0, fmt.Errorf("unable to map seedlings to "+
-				"sprouts: %v", err)
+			return 0, err
 		}
 
 		b.cfg.Batch.RootAssetCommitment = tapCommitment
 
-		// With the commitment Taproot Asset root SMT constructed, we'll
-		// map that into the tapscript root we'll insert into the
-		// genesis transaction.
-		genesisScript, err := b.cfg.Batch.genesisScript()
-		if err != nil {
-			return 0, fmt.Errorf("unable to create genesis "+
-				"script: %v", err)
-		}
-
-		genesisTxPkt.Pkt.UnsignedTx.TxOut[b.anchorOutputIndex].PkScript = genesisScript
-
-		log.Infof("BatchCaretaker(%x): committing sprouts to disk",
-			b.batchKey[:])
+		b.log.Infof("Committing sprouts to disk")
 
 		// With all our commitments created, we'll commit them to disk,
 		// replacing the existing seedlings we had created for each of
@@ -603,8 +1186,26 @@ func (b *BatchCaretaker) stateStep(currentState BatchState) (BatchState, error)
 			b.cfg.Batch.AssetMetas[scriptKey] = seedling.Meta
 		}
 
-		log.Infof("BatchCaretaker(%x): transition states: %v -> %v",
-			b.batchKey, BatchStateFrozen, BatchStateCommitted)
+		// An externally funded batch can't be signed by our wallet, so
+		// we'll wait for the signed packet instead.
+		if b.cfg.ExternalPsbt != nil {
+			err = b.cfg.Log.UpdateBatchState(
+				ctx, b.cfg.Batch.BatchKey.PubKey,
+				BatchStateAwaitingExternalSig,
+			)
+			if err != nil {
+				return 0, fmt.Errorf("unable to update batch "+
+					"state: %w", err)
+			}
+
+			b.log.Infof("Transition states: %v -> %v",
+				BatchStateFrozen, BatchStateAwaitingExternalSig)
+
+			return BatchStateAwaitingExternalSig, nil
+		}
+
+		b.log.Infof("Transition states: %v -> %v", BatchStateFrozen,
+			BatchStateCommitted)
 
 		return BatchStateCommitted, nil
 
@@ -613,8 +1214,21 @@ func (b *BatchCaretaker) stateStep(currentState BatchState) (BatchState, error)
 	// We'll have the backing wallet sign the transaction, then import the
 	// resulting key into the wallet so it tracks the balance.
 	case BatchStateCommitted:
-		log.Infof("BatchCaretaker(%x): finalizing GenesisPacket",
-			b.batchKey[:])
+		b.log.Infof("Finalizing GenesisPacket")
+
+		// After a restart, we'll need to locate the genesis output in
+		// the packet again.
+		genesisScript, err := b.cfg.Batch.genesisScript()
+		if err != nil {
+			return 0, fmt.Errorf("unable to create genesis "+
+				"script: %v", err)
+		}
+		b.anchorOutputIndex, err = locateAnchorOutput(
+			b.cfg.Batch.GenesisPacket.Pkt, genesisScript,
+		)
+		if err != nil {
+			return 0, err
+		}
 
 		// First, we'll have the wallet sign the PSBT is created, which
 		// was then modified.
@@ -628,67 +1242,45 @@ func (b *BatchCaretaker) stateStep(currentState BatchState) (BatchState, error)
 		if err != nil {
 			return 0, fmt.Errorf("unable to sign psbt: %w", err)
 		}
-		b.cfg.Batch.GenesisPacket.Pkt = signedPkt
 
-		// Populate how much this tx paid in on-chain fees.
-		chainFees, err := GetTxFee(signedPkt)
+		err = b.commitSignedGenesisPsbt(ctx, signedPkt)
 		if err != nil {
-			return 0, fmt.Errorf("unable to get on-chain fees "+
-				"for psbt: %w", err)
+			return 0, err
 		}
-		b.cfg.Batch.GenesisPacket.ChainFees = chainFees
 
-		log.Infof("BatchCaretaker(%x): GenesisPacket finalized",
-			b.batchKey[:])
-		log.Tracef("GenesisPacket: %v", spew.Sdump(signedPkt))
+		b.log.Infof("Transition states: %v -> %v", BatchStateCommitted,
+			BatchStateBroadcast)
 
-		// At this point we have a fully signed PSBT packet which'll
-		// create our set of assets once mined. We'll write this to
-		// disk, then import the public key into the wallet.
-		//
-		// TODO(roasbeef): re-run during the broadcast phase to ensure
-		// it's fully imported?
-		mintingOutputKey, tapRoot, err := b.cfg.Batch.MintingOutputKey()
+		return BatchStateBroadcast, nil
+
+	// In this state, the genesis output was committed into an externally
+	// funded packet, which needs to be signed externally. The state
+	// machine pauses here until the signed packet is handed back.
+	case BatchStateAwaitingExternalSig:
+		genesisScript, err := b.cfg.Batch.genesisScript()
 		if err != nil {
-			return 0, err
+			return 0, fmt.Errorf("unable to create genesis "+
+				"script: %v", err)
 		}
-		err = b.cfg.Log.CommitSignedGenesisTx(
-			ctx, b.cfg.Batch.BatchKey.PubKey,
-			b.cfg.Batch.GenesisPacket, b.anchorOutputIndex,
-			tapRoot,
+
+		// After a restart, we'll need to locate the genesis output in
+		// the packet again.
+		genesisPkt := b.cfg.Batch.GenesisPacket.Pkt
+		b.anchorOutputIndex, err = locateAnchorOutput(
+			genesisPkt, genesisScript,
 		)
 		if err != nil {
-			return 0, fmt.Errorf("unable to commit genesis "+
-				"tx: %w", err)
+			return 0, err
 		}
 
-		// With the genesis transaction committed to disk, we'll also
-		// import this public key into the backing wallet, so it
-		// recognizes the de minimis amt sats under out control.
-		//
-		// TODO(roasbeef): should be idempotent along w/ all other
-		// operations above
-		ctx, cancel = b.WithCtxQuit()
-		defer cancel()
-		_, err = b.cfg.Wallet.ImportTaprootOutput(ctx, mintingOutputKey)
-		switch {
-		case err == nil:
-			break
+		b.sendAnchorPsbt(anchorPsbtResp{pkt: genesisPkt})
 
-		// On restart, we'll get an error that the output has already
-		// been added to the wallet, so we'll catch this now and move
-		// along if so.
-		case strings.Contains(err.Error(), "already exists"):
-			break
-
-		case err != nil:
-			return 0, fmt.Errorf("unable to import key: %w", err)
-		}
+		return BatchStateAwaitingExternalSig, nil
 
-		log.Infof("BatchCaretaker(%x): transition states: %v -> %v",
-			b.batchKey, BatchStateCommitted, BatchStateBroadcast)
-
-		return BatchStateBroadcast, nil
+	// A batch that couldn't be funded remains in this state, so it's
+	// terminal.
+	case BatchStateFundingFailed:
+		return BatchStateFundingFailed, nil
 
 	// In this case the genesis transaction has already been rebroadcast.
 	// So we'll attempt to re-broadcast it, then wait for enough
@@ -703,15 +1295,21 @@ func (b *BatchCaretaker) stateStep(currentState BatchState) (BatchState, error)
 				"signed tx: %w", err)
 		}
 
-		log.Infof("BatchCaretaker(%x): extracted finalized GenesisTx",
-			b.batchKey[:])
-		log.Tracef("GenesisTx: %v", spew.Sdump(signedTx))
+		b.log.Infof("Extracted finalized GenesisTx")
+		b.log.Tracef("GenesisTx: %v", spew.Sdump(signedTx))
 
 		// With the final transaction extracted, we'll broadcast the
-		// transaction, then request a confirmation notification.
-		ctx, cancel := b.WithCtxQuit()
-		defer cancel()
-		err = b.cfg.ChainBridge.PublishTransaction(ctx, signedTx)
+		// transaction, then request a confirmation notification. If
+		// we're unable to broadcast it, the batch remains in this
+		// state, as the transaction may still confirm, and the
+		// broadcast is attempted again on restart.
+		err = b.retryStep("publish genesis tx", func(
+			ctx context.Context) error {
+
+			return b.cfg.ChainBridge.PublishTransaction(
+				ctx, signedTx,
+			)
+		})
 		if err != nil {
 			return 0, fmt.Errorf("unable to publish "+
 				"transaction: %w", err)
@@ -745,32 +1343,46 @@ func (b *BatchCaretaker) stateStep(currentState BatchState) (BatchState, error)
 			defer confCancel()
 			defer b.Wg.Done()
 
+			// A minting transaction that isn't confirmed within a
+			// day most likely means we missed the notification.
+			doneWaiting := monitoring.TrackActivity(
+				monitoring.SubsystemPlanter, fmt.Sprintf(
+					"wait for conf of minting_txid=%v",
+					txHash,
+				), confWaitDeadline,
+			)
+			defer doneWaiting()
+
 			var confEvent *chainntnfs.TxConfirmation
 			select {
 			case confEvent = <-confNtfn.Confirmed:
-				log.Debugf("Got chain confirmation: %v",
+				b.log.Debugf("Got chain confirmation: %v",
 					confEvent.Tx.TxHash())
 
+			// An error only affects this batch, which remains in
+			// the broadcast state and registers for a confirmation
+			// again on restart.
 			case err := <-errChan:
-				b.cfg.ErrChan <- fmt.Errorf("error getting "+
-					"confirmation: %w", err)
+				b.log.Errorf("Error getting confirmation: %v",
+					err)
 				return
 
 			case <-confCtx.Done():
-				log.Debugf("Skipping TX confirmation, context " +
-					"done")
+				b.log.Debugf("Skipping TX confirmation, " +
+					"context done")
 
 			case <-b.cfg.CancelReqChan:
 				b.cfg.CancelRespChan <- b.Cancel()
 
 			case <-b.Quit:
-				log.Debugf("Skipping TX confirmation, exiting")
+				b.log.Debugf("Skipping TX confirmation, " +
+					"exiting")
 				return
 			}
 
 			if confEvent == nil {
-				b.cfg.ErrChan <- fmt.Errorf("got empty " +
-					"confirmation event in batch")
+				b.log.Errorf("Got empty confirmation event " +
+					"in batch")
 				return
 			}
 
@@ -778,20 +1390,21 @@ func (b *BatchCaretaker) stateStep(currentState BatchState) (BatchState, error)
 			case b.confEvent <- confEvent:
 
 			case <-confCtx.Done():
-				log.Debugf("Skipping TX confirmation, context " +
-					"done")
+				b.log.Debugf("Skipping TX confirmation, " +
+					"context done")
 
 			case <-b.cfg.CancelReqChan:
 				b.cfg.CancelRespChan <- b.Cancel()
 
 			case <-b.Quit:
-				log.Debugf("Skipping TX confirmation, exiting")
+				b.log.Debugf("Skipping TX confirmation, " +
+					"exiting")
 				return
 			}
 		}()
 
-		log.Infof("BatchCaretaker(%x): transition states: %v -> %v",
-			b.batchKey, BatchStateBroadcast, BatchStateBroadcast)
+		b.log.Infof("Transition states: %v -> %v", BatchStateBroadcast,
+			BatchStateBroadcast)
 
 		return BatchStateBroadcast, nil
 
@@ -803,6 +1416,12 @@ func (b *BatchCaretaker) stateStep(currentState BatchState) (BatchState, error)
 		ctx, cancel := b.WithCtxQuit()
 		defer cancel()
 
+		// We tag the context with the batch, so the verification of
+		// the minting proofs can be traced back to it.
+		ctx = monitoring.WithCorrelationID(
+			ctx, b.cfg.Batch.CorrelationID(),
+		)
+
 		headerVerifier := GenHeaderVerifier(ctx, b.cfg.ChainBridge)
 
 		// Now that the minting transaction has been confirmed, we'll
@@ -818,6 +1437,7 @@ func (b *BatchCaretaker) stateStep(currentState BatchState) (BatchState, error)
 				OutputIndex:      int(b.anchorOutputIndex),
 				InternalKey:      b.cfg.Batch.BatchKey.PubKey,
 				TaprootAssetRoot: b.cfg.Batch.RootAssetCommitment,
+				TapscriptSibling: b.cfg.Batch.TapSibling,
 			},
 			GenesisPoint: extractGenesisOutpoint(
 				b.cfg.Batch.GenesisPacket.Pkt.UnsignedTx,
@@ -875,74 +1495,15 @@ func (b *BatchCaretaker) stateStep(currentState BatchState) (BatchState, error)
 			// TODO(roasbeef): can combine with minting proof
 			// creation above?
 			if b.cfg.Universe != nil {
-				// The universe ID serves to identifier the
-				// universe root we want to add this asset to.
-				// This is either the assetID or the group key.
-				uniID := universe.Identifier{
-					AssetID: assetID,
-				}
-
-				groupKey := newAsset.GroupKey
-				if groupKey != nil {
-					uniID.GroupKey = &groupKey.GroupPubKey
-				}
-
-				log.Debugf("Registering asset with "+
-					"universe, key=%v", spew.Sdump(uniID))
-
-				// The base key is the set of bytes that keys
-				// into the universe, this'll be the outpoint.
-				// where it was created at and the script key
-				// for that asset.
-				baseKey := universe.BaseKey{
-					MintingOutpoint: wire.OutPoint{
-						Hash:  confInfo.Tx.TxHash(),
-						Index: b.anchorOutputIndex,
-					},
-					ScriptKey: &newAsset.ScriptKey,
-				}
-
-				// The universe tree store the only the asset
-				// state transition and not also the proof file
-				// checksum (as the root is effectively a
-				// checksum), so we'll re-encode just the state
-				// transition.
-				//
-				// TODO(roasbeef): this path ends up doing
-				// waaay too many proof encode round trips
-				var proofFile proof.File
-				err := proofFile.Decode(
-					bytes.NewReader(mintingProof),
-				)
-				if err != nil {
-					return 0, fmt.Errorf("unable to decode "+
-						"proof: %w", err)
-				}
-
-				var proofBuf bytes.Buffer
-				issuanceProof, err := proofFile.LastProof()
-				if err != nil {
-					return 0, err
-				}
-				err = issuanceProof.Encode(&proofBuf)
+				uniID, baseKey, mintingLeaf, err :=
+					newMintingLeaf(newAsset, mintingProof)
 				if err != nil {
 					return 0, err
 				}
 
-				// With both of those assembled, we can now
-				// register issuance which takes the amount and
-				// proof of the minting event.
-				uniGen := universe.GenesisWithGroup{
-					Genesis: newAsset.Genesis,
-				}
-				if groupKey != nil {
-					uniGen.GroupKey = groupKey
-				}
-				mintingLeaf := &universe.MintingLeaf{
-					GenesisWithGroup: uniGen,
-					GenesisProof:     proofBuf.Bytes(),
-					Amt:              newAsset.Amount,
-				}
+				b.log.Debugf("Registering asset with "+
+					"universe, key=%v", spew.Sdump(uniID))
+
 				_, err = b.cfg.Universe.RegisterIssuance(
 					ctx, uniID, baseKey, mintingLeaf,
 				)
@@ -961,16 +1522,30 @@ func (b *BatchCaretaker) stateStep(currentState BatchState) (BatchState, error)
 			return 0, fmt.Errorf("unable to confirm batch: %w", err)
 		}
 
-		log.Infof("BatchCaretaker(%x): transition states: %v -> %v",
-			b.batchKey, BatchStateConfirmed, BatchStateFinalized)
+		monitoring.ObserveBatchMinted(b.cfg.Batch.CreationTime)
+
+		b.log.Infof("Transition states: %v -> %v", BatchStateConfirmed,
+			BatchStateFinalized)
 
 		return BatchStateFinalized, nil
 
 	// This is a terminal state, in this state we have nothing left to do,
 	// so we just go back to batch finalized.
 	case BatchStateFinalized:
-		log.Infof("BatchCaretaker(%x): transition states: %v -> %v",
-			b.batchKey, BatchStateFinalized, BatchStateFinalized)
+		// If configured, we'll push the genesis proofs to the universe
+		// server first. Until that succeeds, the batch stays confirmed
+		// on disk, so the push is resumed after a restart.
+		if b.cfg.ProofPush != nil {
+			pushCtx, pushCancel := b.WithCtxQuitNoTimeout()
+			err := b.pushBatchProofs(pushCtx)
+			pushCancel()
+			if err != nil {
+				return 0, err
+			}
+		}
+
+		b.log.Infof("Transition states: %v -> %v", BatchStateFinalized,
+			BatchStateFinalized)
 
 		// TODO(roasbeef): confirmed should just be the final state?
 		ctx, cancel := b.WithCtxQuit()
@@ -1031,3 +1606,268 @@ func GenHeaderVerifier(ctx context.Context,
 		return err
 	}
 }
+
+// newMintingLeaf assembles the universe leaf of the given newly minted asset
+// from its minting proof file, along with the ID of the universe the leaf
+// belongs to and the key it's stored at.
+func newMintingLeaf(newAsset *asset.Asset,
+	mintingProof proof.Blob) (universe.Identifier, universe.BaseKey,
+	*universe.MintingLeaf, error) {
+
+	// The universe ID serves to identifier the universe root we want to
+	// add this asset to. This is either the assetID or the group key.
+	uniID := universe.Identifier{
+		AssetID: newAsset.ID(),
+	}
+
+	groupKey := newAsset.GroupKey
+	if groupKey != nil {
+		uniID.GroupKey = &groupKey.GroupPubKey
+	}
+
+	// The universe tree store the only the asset state transition and not
+	// also the proof file checksum (as the root is effectively a
+	// checksum), so we'll re-encode just the state transition.
+	//
+	// TODO(roasbeef): this path ends up doing waaay too many proof encode
+	// round trips
+	var proofFile proof.File
+	err := proofFile.Decode(bytes.NewReader(mintingProof))
+	if err != nil {
+		return uniID, universe.BaseKey{}, nil, fmt.Errorf("unable to "+
+			"decode proof: %w", err)
+	}
+
+	var proofBuf bytes.Buffer
+	issuanceProof, err := proofFile.LastProof()
+	if err != nil {
+		return uniID, universe.BaseKey{}, nil, err
+	}
+	if err := issuanceProof.Encode(&proofBuf); err != nil {
+		return uniID, universe.BaseKey{}, nil, err
+	}
+
+	// The base key is the set of bytes that keys into the universe, this'll
+	// be the outpoint where it was created at and the script key for that
+	// asset.
+	baseKey := universe.BaseKey{
+		MintingOutpoint: wire.OutPoint{
+			Hash:  issuanceProof.AnchorTx.TxHash(),
+			Index: issuanceProof.InclusionProof.OutputIndex,
+		},
+		ScriptKey: &newAsset.ScriptKey,
+	}
+
+	// With both of those assembled, we can now assemble the leaf which
+	// holds the amount and proof of the minting event.
+	uniGen := universe.GenesisWithGroup{
+		Genesis: newAsset.Genesis,
+	}
+	if groupKey != nil {
+		uniGen.GroupKey = groupKey
+	}
+	mintingLeaf := &universe.MintingLeaf{
+		GenesisWithGroup: uniGen,
+		GenesisProof:     proofBuf.Bytes(),
+		Amt:              newAsset.Amount,
+	}
+
+	return uniID, baseKey, mintingLeaf, nil
+}
+
+// pushBatchProofs pushes the genesis proofs of all assets of the batch to the
+// configured universe server. Failed pushes are retried with backoff. Every
+// attempt is logged, so a new series of attempts after a restart only starts
+// once the backoff reset wait has passed since the last one.
+func (b *BatchCaretaker) pushBatchProofs(ctx context.Context) error {
+	var (
+		batchKey   = b.cfg.Batch.BatchKey.PubKey
+		backoffCfg = b.cfg.ProofPush.BackoffCfg
+		backoff    = backoffCfg.InitialBackoff
+	)
+
+	timestamps, err := b.cfg.Log.QueryProofPushLog(ctx, batchKey)
+	if err != nil {
+		return fmt.Errorf("unable to query proof push log: %w", err)
+	}
+
+	// The timestamps are ordered from the most recent attempt, so we only
+	// need to look at the first one to determine whether we need to wait.
+	if len(timestamps) > 0 {
+		sinceLastAttempt := time.Since(timestamps[0])
+		if sinceLastAttempt < backoffCfg.BackoffResetWait {
+			waitDuration := backoffCfg.BackoffResetWait -
+				sinceLastAttempt
+
+			b.log.Infof("Waiting %v before pushing genesis "+
+				"proofs again", waitDuration)
+
+			if err := waitOrQuit(ctx, waitDuration); err != nil {
+				return err
+			}
+		}
+	}
+
+	var pushErr error
+	for i := 0; i < backoffCfg.NumTries; i++ {
+		// Before attempting the push, we'll log that an attempt is
+		// about to occur.
+		err := b.cfg.Log.StoreProofPushAttempt(ctx, batchKey)
+		if err != nil {
+			return fmt.Errorf("unable to log proof push attempt: "+
+				"%w", err)
+		}
+
+		pushErr = b.pushProofs(ctx)
+		if pushErr == nil {
+			b.log.Infof("Pushed genesis proofs of batch to " +
+				"universe server")
+
+			return nil
+		}
+
+		// There's no need to back off after the last attempt.
+		if i == backoffCfg.NumTries-1 || backoff == 0 {
+			continue
+		}
+
+		b.log.Warnf("Unable to push genesis proofs, backing off for "+
+			"%v: %v", backoff, pushErr)
+
+		if err := waitOrQuit(ctx, backoff); err != nil {
+			return err
+		}
+
+		backoff *= 2
+		if backoff > backoffCfg.MaxBackoff {
+			backoff = backoffCfg.MaxBackoff
+		}
+	}
+
+	return fmt.Errorf("unable to push genesis proofs after %d "+
+		"attempts: %w", backoffCfg.NumTries, pushErr)
+}
+
+// pushProofs pushes the genesis proof of every asset of the batch to the
+// configured universe server once.
+func (b *BatchCaretaker) pushProofs(ctx context.Context) error {
+	rootCommitment := b.cfg.Batch.RootAssetCommitment
+	for _, newAsset := range rootCommitment.CommittedAssets() {
+		assetID := newAsset.ID()
+		mintingProof, err := b.cfg.ProofFiles.FetchProof(
+			ctx, proof.Locator{
+				AssetID:   &assetID,
+				ScriptKey: *newAsset.ScriptKey.PubKey,
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("unable to fetch genesis proof: %w",
+				err)
+		}
+
+		uniID, baseKey, mintingLeaf, err := newMintingLeaf(
+			newAsset, mintingProof,
+		)
+		if err != nil {
+			return err
+		}
+
+		_, err = b.cfg.ProofPush.Registrar.RegisterIssuance(
+			ctx, uniID, baseKey, mintingLeaf,
+		)
+		if err != nil {
+			return fmt.Errorf("unable to push genesis proof of "+
+				"asset %v: %w", assetID, err)
+		}
+	}
+
+	return nil
+}
+
+// waitOrQuit blocks for the given duration, or until the context is done.
+func waitOrQuit(ctx context.Context, duration time.Duration) error {
+	select {
+	case <-time.After(duration):
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("caretaker shutting down")
+	}
+}
+
+// retryStep runs the given step of the batch until it succeeds, the retry
+// policy of the caretaker is exhausted, or the caretaker shuts down. Every
+// attempt gets a fresh context, so the time spent backing off doesn't count
+// against the timeout of an attempt.
+func (b *BatchCaretaker) retryStep(stepName string,
+	step func(ctx context.Context) error) error {
+
+	var (
+		policy      = b.cfg.RetryPolicy
+		maxAttempts = policy.MaxAttempts
+		backoff     = policy.InitialBackoff
+	)
+	if maxAttempts == 0 {
+		maxAttempts = 1
+	}
+
+	for attempt := uint32(1); ; attempt++ {
+		ctx, cancel := b.WithCtxQuit()
+		err := step(ctx)
+		cancel()
+		if err == nil {
+			return nil
+		}
+
+		// An attempt that failed because we're shutting down doesn't
+		// count as a failure of the step.
+		select {
+		case <-b.Quit:
+			return errCaretakerShutdown
+		default:
+		}
+
+		if attempt >= maxAttempts {
+			return fmt.Errorf("unable to %v after %d attempts: %w",
+				stepName, attempt, err)
+		}
+
+		b.log.Warnf("Unable to %v (attempt %d of %d), backing off "+
+			"for %v: %v", stepName, attempt, maxAttempts, backoff,
+			err)
+
+		select {
+		case <-time.After(backoff):
+		case <-b.Quit:
+			return errCaretakerShutdown
+		}
+
+		backoff *= 2
+		if policy.MaxBackoff > 0 && backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+	}
+}
+
+// failFunding marks the batch as failed on disk, after its genesis packet
+// couldn't be funded.
+func (b *BatchCaretaker) failFunding(fundErr error) (BatchState, error) {
+	b.log.Errorf("Giving up on funding batch: %v", fundErr)
+
+	// Anyone waiting for the genesis packet of an externally funded batch
+	// needs to know that it won't be committed.
+	b.sendAnchorPsbt(anchorPsbtResp{err: fundErr})
+
+	ctx, cancel := b.WithCtxQuit()
+	defer cancel()
+	err := b.cfg.Log.UpdateBatchState(
+		ctx, b.cfg.Batch.BatchKey.PubKey, BatchStateFundingFailed,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("unable to mark batch as failed: %w", err)
+	}
+
+	b.log.Infof("Transition states: %v -> %v", BatchStateFrozen,
+		BatchStateFundingFailed)
+
+	return BatchStateFundingFailed, nil
+}
@@ -4,10 +4,12 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"net/url"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcwallet/waddrmgr"
 	"github.com/lightninglabs/lndclient"
@@ -18,6 +20,22 @@ import (
 	"github.com/lightningnetwork/lnd/lnrpc"
 )
 
+const (
+	// courierReceiveRetryDelay is the time we wait before we try to receive
+	// the next proof for an address after the proof courier failed.
+	courierReceiveRetryDelay = 10 * time.Second
+)
+
+// courierReceive is a proof that was delivered to us through the proof courier
+// for a receive through one of our addresses that can't be detected on chain.
+type courierReceive struct {
+	// addr is the address the proof was delivered for.
+	addr *address.AddrWithKeyInfo
+
+	// blob is the proof file that was delivered.
+	blob proof.Blob
+}
+
 // CustodianConfig houses all the items that the Custodian needs to carry out
 // its duties.
 type CustodianConfig struct {
@@ -46,9 +64,14 @@ type CustodianConfig struct {
 	// being available in the relational database).
 	ProofNotifier proof.NotifyArchiver
 
-	// ProofCourier is used to optionally deliver the final proof to the
-	// user using an asynchronous transport mechanism.
-	ProofCourier proof.Courier[proof.Recipient]
+	// ProofCourierDispatcher is used to optionally receive the final proof
+	// of an inbound transfer through an asynchronous transport mechanism.
+	ProofCourierDispatcher proof.CourierDispatch
+
+	// DefaultProofCourierAddr is the address of the proof courier that is
+	// used for addresses that don't specify a proof courier address
+	// themselves.
+	DefaultProofCourierAddr *url.URL
 
 	// ErrChan is the main error channel the custodian will report back
 	// critical errors to the main server.
@@ -78,6 +101,19 @@ type Custodian struct {
 	// address events of inbound assets.
 	events map[wire.OutPoint]*address.Event
 
+	// eventDistributor is used to notify subscribers about new and updated
+	// address events of inbound assets, starting with transactions that
+	// are only seen in the mempool.
+	eventDistributor *chanutils.EventDistributor[*address.Event]
+
+	// courierListeners is the set of script keys of the addresses we're
+	// currently waiting for proofs for through the proof courier.
+	courierListeners map[asset.SerializedKey]struct{}
+
+	// courierReceives is the channel through which the proof courier
+	// listeners hand the received proofs over to the main event loop.
+	courierReceives chan *courierReceive
+
 	// ContextGuard provides a wait group and main quit channel that can be
 	// used to create guarded contexts.
 	*chanutils.ContextGuard
@@ -92,11 +128,15 @@ func NewCustodian(cfg *CustodianConfig) *Custodian {
 	proofSub := chanutils.NewEventReceiver[proof.Blob](
 		chanutils.DefaultQueueSize,
 	)
+	eventDistributor := chanutils.NewEventDistributor[*address.Event]()
 	return &Custodian{
 		cfg:               cfg,
 		addrSubscription:  addrSub,
 		proofSubscription: proofSub,
 		events:            make(map[wire.OutPoint]*address.Event),
+		eventDistributor:  eventDistributor,
+		courierListeners:  make(map[asset.SerializedKey]struct{}),
+		courierReceives:   make(chan *courierReceive),
 		ContextGuard: &chanutils.ContextGuard{
 			DefaultTimeout: DefaultTimeout,
 			Quit:           make(chan struct{}),
@@ -244,12 +284,46 @@ func (c *Custodian) watchInboundAssets() {
 		}
 	}
 
+	// Transfers to static addresses, to addresses without an amount and
+	// to addresses that accept any asset of a group can't be detected on
+	// chain, as their output key depends on what the sender chose. We
+	// instead wait for the proofs to be delivered to us through the proof
+	// courier.
+	courierQueries := []address.QueryParams{
+		{StaticOnly: true},
+		{AmountlessOnly: true},
+		{GroupOnly: true},
+	}
+	for _, query := range courierQueries {
+		ctxt, cancel = c.WithCtxQuit()
+		courierAddrs, err := c.cfg.AddrBook.ListAddrs(ctxt, query)
+		cancel()
+		if err != nil {
+			reportErr(err)
+			return
+		}
+
+		for idx := range courierAddrs {
+			err := c.listenProofCourier(&courierAddrs[idx])
+			if err != nil {
+				reportErr(err)
+				return
+			}
+		}
+	}
+
 	log.Infof("Starting main custodian event loop")
 	for {
 		var err error
 		select {
 		case newAddr := <-c.addrSubscription.NewItemCreated.ChanOut():
 			err = c.importAddrToWallet(newAddr)
+			if err == nil && receivesThroughCourier(newAddr) {
+				err = c.listenProofCourier(newAddr)
+			}
+
+		case receive := <-c.courierReceives:
+			err = c.mapCourierReceive(receive)
 
 		case tx := <-newTxChan:
 			err = c.inspectWalletTx(&tx)
@@ -323,6 +397,7 @@ func (c *Custodian) inspectWalletTx(walletTx *lndclient.Transaction) error {
 				}
 
 				c.events[op] = event
+				c.eventDistributor.NotifySubscribers(event)
 			}
 
 			continue
@@ -335,12 +410,31 @@ func (c *Custodian) inspectWalletTx(walletTx *lndclient.Transaction) error {
 			return err
 		}
 
-		if c.cfg.ProofCourier == nil || addr == nil {
+		if c.cfg.ProofCourierDispatcher == nil || addr == nil {
+			continue
+		}
+
+		courierAddr := addr.ProofCourierAddr
+		if courierAddr == nil {
+			courierAddr = c.cfg.DefaultProofCourierAddr
+		}
+		if courierAddr == nil {
+			log.Warnf("No proof courier address for script "+
+				"key %x, not waiting for proof",
+				addr.ScriptKey.SerializeCompressed())
 			continue
 		}
 
+		courier, err := c.cfg.ProofCourierDispatcher.NewCourier(
+			courierAddr,
+		)
+		if err != nil {
+			return fmt.Errorf("unable to create proof courier: %w",
+				err)
+		}
+
 		// Now that we've seen this output on chain, we'll launch a
-		// goroutine to use the ProofCourier to import the proof into
+		// goroutine to use the proof courier to import the proof into
 		// our local DB.
 		c.Wg.Add(1)
 		go func() {
@@ -358,10 +452,11 @@ func (c *Custodian) inspectWalletTx(walletTx *lndclient.Transaction) error {
 				AssetID:   assetID,
 				Amount:    addr.Amount,
 			}
-			addrProof, err := c.cfg.ProofCourier.ReceiveProof(
+			addrProof, err := courier.ReceiveProof(
 				ctx, recipient, proof.Locator{
 					AssetID:   &assetID,
 					ScriptKey: addr.ScriptKey,
+					OutPoint:  &op,
 				},
 			)
 			if err != nil {
@@ -424,6 +519,14 @@ func (c *Custodian) mapToTapAddr(walletTx *lndclient.Transaction,
 		return nil, fmt.Errorf("unable to encode address: %v", err)
 	}
 
+	accepted, err := c.acceptsReceive(addr, op, walletTx.Timestamp)
+	if err != nil {
+		return nil, err
+	}
+	if !accepted {
+		return nil, nil
+	}
+
 	// Make sure we have an event registered for the transaction, since it
 	// is now clear that it is an incoming asset that is being received with
 	// a Taproot Asset address.
@@ -444,12 +547,335 @@ func (c *Custodian) mapToTapAddr(walletTx *lndclient.Transaction,
 		return nil, fmt.Errorf("error creating event: %w", err)
 	}
 
-	// Let's update our cache of ongoing events.
+	// Let's update our cache of ongoing events and let our subscribers
+	// know about the inbound transfer, even if it's only in the mempool.
 	c.events[op] = event
+	c.eventDistributor.NotifySubscribers(event)
 
 	return addr.Tap, nil
 }
 
+// listenProofCourier launches a goroutine that waits for proofs of receives
+// through the given address to be delivered by the proof courier. As a static
+// address can be used any number of times, and we can't tell when the sender
+// of an address without an amount is done, the goroutine keeps waiting for new
+// proofs until the custodian shuts down.
+func (c *Custodian) listenProofCourier(addr *address.AddrWithKeyInfo) error {
+	baseKey := asset.ToSerialized(&addr.ScriptKey)
+	if _, ok := c.courierListeners[baseKey]; ok {
+		return nil
+	}
+
+	if c.cfg.ProofCourierDispatcher == nil {
+		return nil
+	}
+
+	courierAddr := addr.ProofCourierAddr
+	if courierAddr == nil {
+		courierAddr = c.cfg.DefaultProofCourierAddr
+	}
+	if courierAddr == nil {
+		log.Warnf("No proof courier address for address with script "+
+			"key %x, not waiting for proofs", baseKey[:])
+		return nil
+	}
+
+	courier, err := c.cfg.ProofCourierDispatcher.NewCourier(courierAddr)
+	if err != nil {
+		return fmt.Errorf("unable to create proof courier: %w", err)
+	}
+
+	c.courierListeners[baseKey] = struct{}{}
+
+	c.Wg.Add(1)
+	go func() {
+		defer c.Wg.Done()
+
+		ctx, cancel := c.WithCtxQuitNoTimeout()
+		defer cancel()
+
+		assetID := addr.AssetID
+		recipient := proof.Recipient{
+			ScriptKey: &addr.ScriptKey,
+			AssetID:   assetID,
+			Amount:    addr.Amount,
+		}
+		locator := proof.Locator{
+			AssetID:   &assetID,
+			ScriptKey: addr.ScriptKey,
+		}
+		for {
+			log.Debugf("Waiting to receive proof for address "+
+				"with script key %x", baseKey[:])
+
+			addrProof, err := courier.ReceiveProof(
+				ctx, recipient, locator,
+			)
+			switch {
+			case chanutils.IsCanceled(err):
+				return
+
+			case err != nil:
+				log.Errorf("Unable to recv proof for address "+
+					"with script key %x: %v", baseKey[:],
+					err)
+
+				select {
+				case <-time.After(courierReceiveRetryDelay):
+					continue
+				case <-c.Quit:
+					return
+				}
+			}
+
+			select {
+			case c.courierReceives <- &courierReceive{
+				addr: addr,
+				blob: addrProof.Blob,
+			}:
+			case <-c.Quit:
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+// mapCourierReceive maps a proof that was delivered through the proof courier
+// to the receive through the address it belongs to. For a static address, the
+// unique script key of the receive is added to the wallet first. An event is
+// created for the receive once the proof is imported, which the notification
+// about the imported proof then completes.
+func (c *Custodian) mapCourierReceive(receive *courierReceive) error {
+	addr := receive.addr
+
+	file := proof.NewEmptyFile(proof.V0)
+	err := file.Decode(bytes.NewReader(receive.blob))
+	if err != nil || file.IsEmpty() {
+		log.Warnf("Received invalid proof file for address with "+
+			"script key %x: %v",
+			addr.ScriptKey.SerializeCompressed(), err)
+		return nil
+	}
+
+	lastProof, err := file.LastProof()
+	if err != nil {
+		return fmt.Errorf("error fetching last proof: %w", err)
+	}
+
+	// The tapscript sibling of the anchor output tells us which receive
+	// through a static address the proof is for. Anything that doesn't
+	// check out was not sent to us, so we just ignore it.
+	receiveAddr := addr
+	if addr.Static {
+		commitmentProof := lastProof.InclusionProof.CommitmentProof
+		if commitmentProof == nil {
+			log.Warnf("Received proof without commitment proof " +
+				"for static address")
+			return nil
+		}
+		receiveAddr, err = addr.StaticReceiveFromSibling(
+			commitmentProof.TapSiblingPreimage,
+		)
+		if err != nil {
+			log.Warnf("Received proof for static address that "+
+				"isn't a receive through it: %v", err)
+			return nil
+		}
+	}
+
+	// An address that accepts any asset of a group is resolved to the
+	// tranche the sender chose, so the received asset can be committed to
+	// and looked up under its actual asset ID.
+	if receiveAddr.AnyAssetInGroup {
+		groupKey := lastProof.Asset.GroupKey
+		if groupKey == nil ||
+			!groupKey.GroupPubKey.IsEqual(receiveAddr.GroupKey) {
+
+			log.Warnf("Received proof for asset outside of the "+
+				"group of address with script key %x",
+				receiveAddr.ScriptKey.SerializeCompressed())
+			return nil
+		}
+
+		receiveAddr, err = receiveAddr.ForGroupAsset(
+			lastProof.Asset.Genesis, groupKey.Sig,
+		)
+		if err != nil {
+			return fmt.Errorf("unable to resolve group address: "+
+				"%w", err)
+		}
+	}
+	if !AddrMatchesAsset(receiveAddr, &lastProof.Asset) {
+		log.Warnf("Received proof that doesn't match the address "+
+			"with script key %x",
+			receiveAddr.ScriptKey.SerializeCompressed())
+		return nil
+	}
+
+	// An address without an amount accepts whatever amount the sender
+	// chose, while any other address must receive the exact amount.
+	receivedAmt := lastProof.Asset.Amount
+	switch {
+	case !receiveAddr.HasAmount():
+		receiveAddr, err = receiveAddr.ForAmount(receivedAmt)
+		if err != nil {
+			log.Warnf("Received proof with invalid amount: %v",
+				err)
+			return nil
+		}
+
+	case receiveAddr.Amount != receivedAmt:
+		log.Warnf("Received proof for amount %d, but address with "+
+			"script key %x expects %d", receivedAmt,
+			receiveAddr.ScriptKey.SerializeCompressed(),
+			receiveAddr.Amount)
+		return nil
+	}
+
+	op := wire.OutPoint{
+		Hash:  lastProof.AnchorTx.TxHash(),
+		Index: lastProof.InclusionProof.OutputIndex,
+	}
+	accepted, err := c.acceptsReceive(
+		receiveAddr, op, lastProof.BlockHeader.Timestamp,
+	)
+	if err != nil {
+		return err
+	}
+	if !accepted {
+		return nil
+	}
+
+	ctxt, cancel := c.CtxBlocking()
+	defer cancel()
+
+	// We need to know the unique script key of a receive through a static
+	// address before we import the proof, so the asset is recognized as
+	// ours.
+	if addr.Static {
+		err = c.cfg.AddrBook.InsertScriptKey(ctxt, asset.ScriptKey{
+			PubKey:           &receiveAddr.ScriptKey,
+			TweakedScriptKey: &receiveAddr.ScriptKeyTweak,
+		})
+		if err != nil {
+			return fmt.Errorf("unable to insert script key: %w",
+				err)
+		}
+	}
+
+	// The proof already contains everything we'd otherwise learn from our
+	// wallet about the on-chain transaction.
+	anchorTx := lastProof.AnchorTx
+	blockHash := lastProof.BlockHeader.BlockHash()
+	_, blockHeight, err := NewChainBridgeHeaderChain(
+		c.cfg.ChainBridge,
+	).BlockHeader(ctxt, blockHash)
+	if err != nil {
+		log.Warnf("Unable to look up anchor block of received "+
+			"proof: %v", err)
+		return nil
+	}
+
+	walletTx := &lndclient.Transaction{
+		Tx:            &anchorTx,
+		TxHash:        anchorTx.TxHash().String(),
+		Confirmations: 1,
+		BlockHash:     blockHash.String(),
+		BlockHeight:   int32(blockHeight),
+		Timestamp:     lastProof.BlockHeader.Timestamp,
+	}
+	for _, txOut := range anchorTx.TxOut {
+		walletTx.OutputDetails = append(
+			walletTx.OutputDetails, &lnrpc.OutputDetail{
+				Amount: txOut.Value,
+			},
+		)
+	}
+
+	headerVerifier := GenHeaderVerifier(ctxt, c.cfg.ChainBridge)
+	err = c.cfg.ProofArchive.ImportProofs(
+		ctxt, headerVerifier, &proof.AnnotatedProof{
+			Locator: proof.Locator{
+				AssetID:   &receiveAddr.AssetID,
+				ScriptKey: receiveAddr.ScriptKey,
+			},
+			Blob: receive.blob,
+		},
+	)
+	if err != nil {
+		log.Warnf("Unable to import received proof: %v", err)
+		return nil
+	}
+
+	log.Infof("Found inbound asset transfer (asset_id=%x, amount=%d) "+
+		"through proof courier in %s", receiveAddr.AssetID[:],
+		receivedAmt, op.String())
+
+	event, err := c.cfg.AddrBook.GetOrCreateEvent(
+		ctxt, address.StatusTransactionConfirmed, receiveAddr,
+		walletTx, op.Index,
+	)
+	if err != nil {
+		return fmt.Errorf("error creating event: %w", err)
+	}
+
+	// The notification about the imported proof is processed by the main
+	// event loop after we return, which then completes the event.
+	c.events[op] = event
+	c.eventDistributor.NotifySubscribers(event)
+
+	return nil
+}
+
+// acceptsReceive returns true if the given address still accepts the transfer
+// in the given outpoint, which our wallet first saw at the given time. An
+// expired address doesn't accept any new transfers, while a single-use address
+// only accepts the first one.
+func (c *Custodian) acceptsReceive(addr *address.AddrWithKeyInfo,
+	op wire.OutPoint, seenTime time.Time) (bool, error) {
+
+	if addr.IsExpired(seenTime) {
+		log.Warnf("Ignoring transfer in %v to address with script key "+
+			"%x that expired at %v", op,
+			addr.ScriptKey.SerializeCompressed(), addr.Expiry)
+		return false, nil
+	}
+
+	if !addr.SingleUse {
+		return true, nil
+	}
+
+	ctxt, cancel := c.WithCtxQuit()
+	defer cancel()
+
+	events, err := c.cfg.AddrBook.QueryEvents(
+		ctxt, address.EventQueryParams{
+			AddrTaprootOutputKey: schnorr.SerializePubKey(
+				&addr.TaprootOutputKey,
+			),
+		},
+	)
+	if err != nil {
+		return false, fmt.Errorf("error querying address events: %w",
+			err)
+	}
+
+	for _, event := range events {
+		if event.Outpoint != op {
+			log.Warnf("Ignoring transfer in %v to single-use "+
+				"address with script key %x that already "+
+				"received a transfer in %v", op,
+				addr.ScriptKey.SerializeCompressed(),
+				event.Outpoint)
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
 // importAddrToWallet imports the given Taproot Asset address into the
 // lnd-internal btcwallet instance by tracking the on-chain Taproot output key
 // the assets must be sent to in order to be received.
@@ -497,11 +923,16 @@ func (c *Custodian) checkProofAvailable(event *address.Event) error {
 	// be a multi archiver that includes file based storage) to make sure
 	// the proof is available in the relational database. If the proof is
 	// not in the DB, we can't update the event.
-	blob, err := c.cfg.ProofNotifier.FetchProof(ctxt, proof.Locator{
-		AssetID:   chanutils.Ptr(event.Addr.AssetID),
+	// The asset ID of an address that accepts any asset of a group is only
+	// a template, so we look up the proof by group key alone.
+	locator := proof.Locator{
 		GroupKey:  event.Addr.GroupKey,
 		ScriptKey: event.Addr.ScriptKey,
-	})
+	}
+	if !event.Addr.AnyAssetInGroup {
+		locator.AssetID = chanutils.Ptr(event.Addr.AssetID)
+	}
+	blob, err := c.cfg.ProofNotifier.FetchProof(ctxt, locator)
 	switch {
 	case errors.Is(err, proof.ErrProofNotFound):
 		return nil
@@ -587,9 +1018,75 @@ func (c *Custodian) setReceiveCompleted(event *address.Event,
 		Index: p.InclusionProof.OutputIndex,
 	}
 
-	return c.cfg.AddrBook.CompleteEvent(
+	err := c.cfg.AddrBook.CompleteEvent(
 		ctxt, event, address.StatusCompleted, anchorPoint,
 	)
+	if err != nil {
+		return err
+	}
+
+	// The event in our cache is shared, so we notify our subscribers with
+	// a copy that reflects the completion.
+	completedEvent := *event
+	completedEvent.Status = address.StatusCompleted
+	completedEvent.HasProof = true
+	c.eventDistributor.NotifySubscribers(&completedEvent)
+
+	return nil
+}
+
+// RegisterSubscriber adds a new subscriber for receiving address events of
+// inbound assets. The deliverExisting boolean indicates whether already
+// existing events matching the deliverFrom query should be sent to the
+// NewItemCreated channel when the subscription is started.
+func (c *Custodian) RegisterSubscriber(
+	receiver *chanutils.EventReceiver[*address.Event],
+	deliverExisting bool, deliverFrom address.EventQueryParams) error {
+
+	c.eventDistributor.RegisterSubscriber(receiver)
+
+	// No delivery of existing items requested, we're done here.
+	if !deliverExisting {
+		return nil
+	}
+
+	ctxt, cancel := c.WithCtxQuit()
+	defer cancel()
+
+	events, err := c.cfg.AddrBook.QueryEvents(ctxt, deliverFrom)
+	if err != nil {
+		// We don't want to leave a half registered subscriber behind.
+		_ = c.eventDistributor.RemoveSubscriber(receiver)
+
+		return fmt.Errorf("error querying existing events: %w", err)
+	}
+
+	for idx := range events {
+		receiver.NewItemCreated.ChanIn() <- events[idx]
+	}
+
+	return nil
+}
+
+// RemoveSubscriber removes the given subscriber and also stops it from
+// processing events.
+func (c *Custodian) RemoveSubscriber(
+	subscriber *chanutils.EventReceiver[*address.Event]) error {
+
+	return c.eventDistributor.RemoveSubscriber(subscriber)
+}
+
+// A compile-time assertion to make sure Custodian satisfies the
+// chanutils.EventPublisher interface.
+var _ chanutils.EventPublisher[
+	*address.Event, address.EventQueryParams,
+] = (*Custodian)(nil)
+
+// receivesThroughCourier returns true if transfers to the given address can't
+// be detected on chain and are instead only learned about through the proofs
+// delivered by the proof courier.
+func receivesThroughCourier(addr *address.AddrWithKeyInfo) bool {
+	return addr.Static || !addr.HasAmount() || addr.AnyAssetInGroup
 }
 
 // hasWalletTaprootOutput returns true if one of the outputs of the given
@@ -617,7 +1114,8 @@ func isWalletTaprootOutput(out *lnrpc.OutputDetail) bool {
 }
 
 // AddrMatchesAsset returns true if the given asset state (ID, group key,
-// script key) matches the state represented in the address.
+// script key) matches the state represented in the address. An address that
+// accepts any asset of a group matches any asset ID within that group.
 func AddrMatchesAsset(addr *address.AddrWithKeyInfo, a *asset.Asset) bool {
 	groupKeyBothNil := (addr.GroupKey == nil) && (a.GroupKey == nil)
 	groupKeyNoneNil := (addr.GroupKey != nil) && (a.GroupKey != nil)
@@ -631,6 +1129,8 @@ func AddrMatchesAsset(addr *address.AddrWithKeyInfo, a *asset.Asset) bool {
 	groupKeyEqual := groupKeyBothNil ||
 		addr.GroupKey.IsEqual(&a.GroupKey.GroupPubKey)
 
-	return addr.AssetID == a.ID() && groupKeyEqual &&
+	assetIDEqual := addr.AnyAssetInGroup || addr.AssetID == a.ID()
+
+	return assetIDEqual && groupKeyEqual &&
 		addr.ScriptKey.IsEqual(a.ScriptKey.PubKey)
 }
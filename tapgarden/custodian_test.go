@@ -305,6 +305,92 @@ func TestTransactionHandling(t *testing.T) {
 	})
 }
 
+// TestInboundEventNtfns makes sure that subscribers are notified about inbound
+// transfers as soon as their transaction is seen in the mempool, and again once
+// it confirms.
+func TestInboundEventNtfns(t *testing.T) {
+	h := newHarness(t, nil)
+
+	ctx := context.Background()
+	addr := randAddr(h)
+	err := h.tapdbBook.InsertAddrs(ctx, *addr)
+	require.NoError(t, err)
+
+	eventSub := chanutils.NewEventReceiver[*address.Event](
+		chanutils.DefaultQueueSize,
+	)
+	err = h.c.RegisterSubscriber(
+		eventSub, false, address.EventQueryParams{},
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, h.c.Start())
+	t.Cleanup(func() {
+		require.NoError(t, h.c.RemoveSubscriber(eventSub))
+		require.NoError(t, h.c.Stop())
+	})
+	h.assertStartup()
+	h.assertAddrsRegistered(addr)
+
+	assertEvent := func(status address.Status) *address.Event {
+		event, err := chanutils.RecvOrTimeout(
+			eventSub.NewItemCreated.ChanOut(), testTimeout,
+		)
+		require.NoError(t, err)
+		require.Equal(t, status, (*event).Status)
+		require.Equal(t, addr.AssetID, (*event).Addr.AssetID)
+
+		return *event
+	}
+
+	// A transaction that is only in the mempool already creates an event
+	// that is delivered to the subscriber.
+	sendTx := func(tx *lndclient.Transaction) {
+		select {
+		case h.walletAnchor.SubscribeTx <- *tx:
+		case <-time.After(testTimeout):
+			t.Fatalf("timeout sending wallet tx")
+		}
+	}
+
+	outputIdx, tx := randWalletTx(addr)
+	sendTx(tx)
+
+	event := assertEvent(address.StatusTransactionDetected)
+	require.EqualValues(t, outputIdx, event.Outpoint.Index)
+	require.Zero(t, event.ConfirmationHeight)
+
+	// Once the transaction confirms, the subscriber is notified again.
+	tx.Confirmations = 1
+	tx.BlockHeight = 123
+	sendTx(tx)
+
+	event = assertEvent(address.StatusTransactionConfirmed)
+	require.EqualValues(t, 123, event.ConfirmationHeight)
+
+	// A new subscriber can request the pending events on registration.
+	pendingSub := chanutils.NewEventReceiver[*address.Event](
+		chanutils.DefaultQueueSize,
+	)
+	from := address.StatusTransactionDetected
+	err = h.c.RegisterSubscriber(
+		pendingSub, true, address.EventQueryParams{
+			StatusFrom: &from,
+		},
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, h.c.RemoveSubscriber(pendingSub))
+	})
+
+	pending, err := chanutils.RecvOrTimeout(
+		pendingSub.NewItemCreated.ChanOut(), testTimeout,
+	)
+	require.NoError(t, err)
+	require.Equal(t, event.Outpoint, (*pending).Outpoint)
+	require.Equal(t, address.StatusTransactionConfirmed, (*pending).Status)
+}
+
 func mustMakeAddr(t *testing.T,
 	gen asset.Genesis, groupKey *btcec.PublicKey,
 	groupSig *schnorr.Signature, scriptKey btcec.PublicKey) *address.Tap {
@@ -431,6 +517,26 @@ func TestAddrMatchesAsset(t *testing.T) {
 			},
 		},
 		result: true,
+	}, {
+		name: "any asset in group",
+		addr: &address.AddrWithKeyInfo{
+			Tap: func() *address.Tap {
+				addr := mustMakeAddr(
+					t, randGen1, &randGroup1.GroupPubKey,
+					&randGroup1.Sig, *randKey2,
+				)
+				addr.AnyAssetInGroup = true
+				return addr
+			}(),
+		},
+		a: &asset.Asset{
+			Genesis:  randGen2,
+			GroupKey: randGroup1,
+			ScriptKey: asset.ScriptKey{
+				PubKey: randKey2,
+			},
+		},
+		result: true,
 	}}
 
 	for _, tc := range testCases {
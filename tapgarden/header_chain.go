@@ -0,0 +1,86 @@
+package tapgarden
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightninglabs/taproot-assets/proof"
+)
+
+// ChainBridgeHeaderChain is a proof.HeaderChain that is backed by the chain
+// backend of a chain bridge, which fully validates the chain it follows.
+type ChainBridgeHeaderChain struct {
+	chainBridge ChainBridge
+}
+
+// A compile-time assertion to ensure ChainBridgeHeaderChain satisfies the
+// proof.HeaderChain interface.
+var _ proof.HeaderChain = (*ChainBridgeHeaderChain)(nil)
+
+// NewChainBridgeHeaderChain creates a new header chain backed by the given
+// chain bridge.
+func NewChainBridgeHeaderChain(
+	chainBridge ChainBridge) *ChainBridgeHeaderChain {
+
+	return &ChainBridgeHeaderChain{
+		chainBridge: chainBridge,
+	}
+}
+
+// BlockHeader returns the header of the block with the given hash, along with
+// its height. As the chain backend doesn't expose the height of a block, it is
+// taken from the coinbase transaction of the block (BIP 34).
+//
+// NOTE: This is part of the proof.HeaderChain interface.
+func (c *ChainBridgeHeaderChain) BlockHeader(ctx context.Context,
+	hash chainhash.Hash) (*wire.BlockHeader, uint32, error) {
+
+	block, err := c.chainBridge.GetBlock(ctx, hash)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: %v", proof.ErrUnknownBlockHeader,
+			err)
+	}
+
+	if len(block.Transactions) == 0 {
+		return nil, 0, fmt.Errorf("block %v has no coinbase "+
+			"transaction", hash)
+	}
+
+	height, err := blockchain.ExtractCoinbaseHeight(
+		btcutil.NewTx(block.Transactions[0]),
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("unable to extract height of block "+
+			"%v: %w", hash, err)
+	}
+
+	return &block.Header, uint32(height), nil
+}
+
+// BlockHash returns the hash of the block at the given height of the best
+// chain.
+//
+// NOTE: This is part of the proof.HeaderChain interface.
+func (c *ChainBridgeHeaderChain) BlockHash(ctx context.Context,
+	height uint32) (chainhash.Hash, error) {
+
+	return c.chainBridge.GetBlockHash(ctx, int64(height))
+}
+
+// GenChainHeaderVerifier generates a block header verification callback
+// function that, unlike the one created by GenHeaderVerifier, checks the proof
+// of work of a header and makes sure it's part of the best chain of the chain
+// backend of the given chain bridge, so headers of blocks that were reorged
+// out are rejected.
+func GenChainHeaderVerifier(ctx context.Context, chainBridge ChainBridge,
+	params *chaincfg.Params) proof.HeaderVerifier {
+
+	return proof.NewHeaderChainVerifier(
+		ctx, NewChainBridgeHeaderChain(chainBridge), params,
+	)
+}
@@ -3,6 +3,7 @@ package tapgarden
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcutil"
@@ -24,9 +25,21 @@ import (
 type Planter interface {
 	// QueueNewSeedling attempts to queue a new seedling request (the
 	// intent for New asset creation or ongoing issuance) to the Planter.
-	// A channel is returned where future updates will be sent over. If an
-	// error is returned no issuance operation was possible.
-	QueueNewSeedling(req *Seedling) (SeedlingUpdates, error)
+	// If a batch key is given, the seedling is added to that pending
+	// batch, otherwise it's added to the default pending batch. A channel
+	// is returned where future updates will be sent over. If an error is
+	// returned no issuance operation was possible.
+	QueueNewSeedling(req *Seedling,
+		batchKey *btcec.PublicKey) (SeedlingUpdates, error)
+
+	// NewBatch creates a new, empty pending batch that is staged and
+	// finalized independently of the default pending batch, and returns
+	// its key. If a batch key is given, it's used as the internal key of
+	// the batch instead of a freshly derived key.
+	NewBatch(batchKey *keychain.KeyDescriptor) (*btcec.PublicKey, error)
+
+	// PendingBatches returns all pending batches, oldest first.
+	PendingBatches() ([]*MintingBatch, error)
 
 	// TODO(roasbeef): list seeds, their pending state, etc, etc
 
@@ -36,19 +49,52 @@ type Planter interface {
 	// details of a specific batch.
 	ListBatches(batchKey *btcec.PublicKey) ([]*MintingBatch, error)
 
-	// CancelSeedling attempts to cancel the creation of a new asset
-	// identified by its name. If the seedling has already progressed to a
-	// point where the genesis PSBT has been broadcasted, an error is
-	// returned.
-	CancelSeedling() error
-
-	// FinalizeBatch signals that the asset minter should finalize
-	// the current batch, if one exists.
-	FinalizeBatch() (*btcec.PublicKey, error)
-
-	// CancelBatch signals that the asset minter should cancel the
-	// current batch, if one exists.
-	CancelBatch() (*btcec.PublicKey, error)
+	// CancelSeedling removes the seedling with the given asset name from
+	// the pending batch with the given key, or the default pending batch
+	// if no key is given. Once the batch has been finalized, its
+	// seedlings can no longer be cancelled individually.
+	CancelSeedling(assetName string, batchKey *btcec.PublicKey) error
+
+	// FinalizeBatch signals that the asset minter should finalize the
+	// pending batch with the given key, or the default pending batch if
+	// no key is given. If a fee rate is given, the genesis transaction of
+	// the batch is funded at that rate instead of an estimated one. If a
+	// tapscript sibling is given, it is committed to alongside the Taproot
+	// Asset commitment in the genesis output. If coin control is given,
+	// the genesis transaction only spends the wallet UTXOs it allows.
+	FinalizeBatch(batchKey *btcec.PublicKey,
+		feeRate *chainfee.SatPerKWeight,
+		tapSibling *commitment.TapscriptPreimage,
+		coinControl *CoinControl) (*btcec.PublicKey, error)
+
+	// PreviewBatch builds the genesis transaction, the asset sprouts and
+	// the Taproot Asset commitment of the pending batch with the given
+	// key, or the default pending batch if no key is given, without
+	// finalizing or broadcasting anything. If a fee rate is given, the
+	// genesis transaction is funded at that rate instead of an estimated
+	// one.
+	PreviewBatch(batchKey *btcec.PublicKey,
+		feeRate *chainfee.SatPerKWeight) (*BatchPreview, error)
+
+	// FundBatch finalizes the pending batch with the given key, or the
+	// default pending batch if no key is given, and commits its genesis
+	// output into the given externally funded PSBT packet instead of
+	// having the wallet fund it. The returned packet contains the genesis
+	// output and needs to be signed externally, then handed back through
+	// SubmitSignedBatchPsbt.
+	FundBatch(batchKey *btcec.PublicKey,
+		fundedPsbt *psbt.Packet) (*psbt.Packet, error)
+
+	// SubmitSignedBatchPsbt hands the externally signed genesis PSBT
+	// packet of the batch with the given key back to the asset minter,
+	// which then broadcasts it.
+	SubmitSignedBatchPsbt(batchKey *btcec.PublicKey,
+		signedPsbt *psbt.Packet) error
+
+	// CancelBatch signals that the asset minter should cancel the batch
+	// with the given key. If no key is given, the only existing batch is
+	// cancelled.
+	CancelBatch(batchKey *btcec.PublicKey) (*btcec.PublicKey, error)
 
 	// Start signals that the asset minter should being operations.
 	Start() error
@@ -96,6 +142,17 @@ const (
 	// BatchStateSproutedCancelled denotes that a batch has been cancelled
 	// after being passed to a caretaker and sprouting.
 	BatchStateSproutCancelled BatchState = 7
+
+	// BatchStateAwaitingExternalSig denotes that the genesis output of a
+	// batch was committed into an externally funded PSBT packet, and the
+	// batch is waiting for the signed packet to be handed back.
+	BatchStateAwaitingExternalSig BatchState = 8
+
+	// BatchStateFundingFailed denotes that the genesis transaction of a
+	// frozen batch couldn't be funded, even after retrying as often as the
+	// retry policy of the caretaker allows. The batch isn't resumed on
+	// restart.
+	BatchStateFundingFailed BatchState = 9
 )
 
 // String returns a human-readable string for the target batch state.
@@ -125,6 +182,12 @@ func (b BatchState) String() string {
 	case BatchStateSproutCancelled:
 		return "BatchStateSproutCancelled"
 
+	case BatchStateAwaitingExternalSig:
+		return "BatchStateAwaitingExternalSig"
+
+	case BatchStateFundingFailed:
+		return "BatchStateFundingFailed"
+
 	default:
 		return fmt.Sprintf("UnknownState(%v)", int(b))
 	}
@@ -151,6 +214,11 @@ type MintingStore interface {
 	AddSeedlingsToBatch(ctx context.Context, batchKey *btcec.PublicKey,
 		seedlings ...*Seedling) error
 
+	// DeleteSeedlingFromBatch removes the seedling with the given asset
+	// name from an existing batch that hasn't been frozen yet.
+	DeleteSeedlingFromBatch(ctx context.Context, batchKey *btcec.PublicKey,
+		assetName string) error
+
 	// FetchAllBetches fetches all the batches on disk.
 	FetchAllBatches(ctx context.Context) ([]*MintingBatch, error)
 
@@ -172,16 +240,27 @@ type MintingStore interface {
 	AddSproutsToBatch(ctx context.Context, batchKey *btcec.PublicKey,
 		genesisPacket *FundedPsbt, assets *commitment.TapCommitment) error
 
+	// CommitBatchTx stores the funded genesis packet of a frozen batch, so
+	// it can be reused after a restart instead of funding a new one.
+	CommitBatchTx(ctx context.Context, batchKey *btcec.PublicKey,
+		genesisTx *FundedPsbt) error
+
+	// CommitBatchTapSibling stores the tapscript sibling preimage that is
+	// committed to alongside the Taproot Asset commitment in the genesis
+	// output of the batch.
+	CommitBatchTapSibling(ctx context.Context, batchKey *btcec.PublicKey,
+		tapSibling *commitment.TapscriptPreimage) error
+
 	// CommitSignedGenesisTx adds a fully signed genesis transaction to the
-	// batch, along with the Taproot Asset script root, which is the
-	// left/right sibling for the Taproot Asset tapscript commitment in the
-	// transaction.
+	// batch, along with the merkle root of the tapscript tree of the
+	// genesis output, the root of the Taproot Asset commitment and the
+	// encoded tapscript sibling preimage, if there is one.
 	//
 	// NOTE: The BatchState should transition to the BatchStateBroadcast
 	// state upon a successful call.
 	CommitSignedGenesisTx(ctx context.Context, batchKey *btcec.PublicKey,
 		genesisTx *FundedPsbt, anchorOutputIndex uint32,
-		tapRoot []byte) error
+		merkleRoot, tapTreeRoot, tapSibling []byte) error
 
 	// MarkBatchConfirmed marks the batch as confirmed on chain. The passed
 	// block location information determines where exactly in the chain the
@@ -193,6 +272,16 @@ type MintingStore interface {
 		blockHash *chainhash.Hash, blockHeight uint32,
 		txIndex uint32, mintingProofs proof.AssetBlobs) error
 
+	// StoreProofPushAttempt logs an attempt to push the genesis proofs of
+	// the batch with the given key to a universe server.
+	StoreProofPushAttempt(ctx context.Context,
+		batchKey *btcec.PublicKey) error
+
+	// QueryProofPushLog returns the timestamps of all logged attempts to
+	// push the genesis proofs of the batch with the given key.
+	QueryProofPushLog(ctx context.Context,
+		batchKey *btcec.PublicKey) ([]time.Time, error)
+
 	// FetchGroupByGenesis fetches the asset group created by the genesis
 	// referenced by the given ID.
 	FetchGroupByGenesis(ctx context.Context,
@@ -202,6 +291,12 @@ type MintingStore interface {
 	// key, including the genesis information used to create the group.
 	FetchGroupByGroupKey(ctx context.Context,
 		groupKey *btcec.PublicKey) (*asset.AssetGroup, error)
+
+	// FetchGroupByKeyLocator fetches the asset group whose raw group key
+	// was derived with the given key locator. An error is returned if no
+	// group or more than one group uses a key with that locator.
+	FetchGroupByKeyLocator(ctx context.Context,
+		keyLoc keychain.KeyLocator) (*asset.AssetGroup, error)
 }
 
 // ChainBridge is our bridge to the target chain. It's used to get confirmation
@@ -215,9 +310,20 @@ type ChainBridge interface {
 		includeBlock bool) (*chainntnfs.ConfirmationEvent, chan error,
 		error)
 
+	// RegisterBlockEpochNtfn registers an intent to be notified of each
+	// new block connected to the tip of the main chain. The height of the
+	// current tip is sent immediately.
+	RegisterBlockEpochNtfn(ctx context.Context) (chan int32, chan error,
+		error)
+
 	// GetBlock returns a chain block given its hash.
 	GetBlock(context.Context, chainhash.Hash) (*wire.MsgBlock, error)
 
+	// GetBlockHash returns the hash of the block in the best chain at the
+	// given height.
+	GetBlockHash(ctx context.Context, blockHeight int64) (chainhash.Hash,
+		error)
+
 	// CurrentHeight return the current height of the main chain.
 	CurrentHeight(context.Context) (uint32, error)
 
@@ -249,13 +355,26 @@ type FundedPsbt struct {
 	LockedUTXOs []wire.OutPoint
 }
 
+// CoinControl restricts which wallet UTXOs may be used to fund a PSBT packet.
+type CoinControl struct {
+	// Outpoints, if set, are the wallet UTXOs the packet is funded with.
+	// All of them are spent, and no other inputs are selected.
+	Outpoints []wire.OutPoint
+
+	// Account is the name of the wallet account that funds the packet and
+	// receives its change. If empty, the default account is used.
+	Account string
+}
+
 // WalletAnchor is the main wallet interface used to managed PSBT packets, and
 // import public keys into the wallet.
 type WalletAnchor interface {
 	// FundPsbt attaches enough inputs to the target PSBT packet for it to
-	// be valid.
+	// be valid. If coin control is given, only the UTXOs it allows are
+	// used as inputs.
 	FundPsbt(ctx context.Context, packet *psbt.Packet, minConfs uint32,
-		feeRate chainfee.SatPerKWeight) (FundedPsbt, error)
+		feeRate chainfee.SatPerKWeight,
+		coinControl *CoinControl) (FundedPsbt, error)
 
 	// SignAndFinalizePsbt fully signs and finalizes the target PSBT
 	// packet.
@@ -267,7 +386,7 @@ type WalletAnchor interface {
 
 	// UnlockInput unlocks the set of target inputs after a batch is
 	// abandoned.
-	UnlockInput(context.Context) error
+	UnlockInput(context.Context, []wire.OutPoint) error
 
 	// ListUnspentImportScripts lists all UTXOs of the imported Taproot
 	// scripts.
@@ -0,0 +1,176 @@
+package tapgarden
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/lightninglabs/taproot-assets/chanutils"
+	"github.com/lightninglabs/taproot-assets/monitoring"
+	"github.com/lightningnetwork/lnd/ticker"
+)
+
+const (
+	// DefaultBatchPruneInterval is the default interval at which the
+	// minting store is checked for batches that exceeded their retention
+	// age.
+	DefaultBatchPruneInterval = time.Hour
+)
+
+// MintingPruneResult holds the number of rows that were deleted from each
+// table of the minting store while pruning it.
+type MintingPruneResult struct {
+	// NumBatches is the number of deleted minting batches.
+	NumBatches int64
+
+	// NumSeedlings is the number of deleted seedlings.
+	NumSeedlings int64
+
+	// NumSprouts is the number of deleted sprouts of cancelled batches.
+	NumSprouts int64
+
+	// NumProofPushAttempts is the number of deleted attempts to push the
+	// genesis proofs of a batch.
+	NumProofPushAttempts int64
+}
+
+// MintingStorePruner is a minting store that is able to garbage-collect
+// batches that reached a terminal state.
+type MintingStorePruner interface {
+	// PruneMintingBatches deletes all batches that were created before
+	// the given time and were either cancelled or couldn't be funded,
+	// along with their seedlings, sprouts and proof push log. The
+	// seedlings of finalized batches that were created before the given
+	// time are deleted as well, as they were superseded by the minted
+	// assets.
+	PruneMintingBatches(ctx context.Context,
+		createdBefore time.Time) (*MintingPruneResult, error)
+}
+
+// BatchJanitorConfig houses all the items that the batch janitor needs to
+// carry out its duties.
+type BatchJanitorConfig struct {
+	// Store is the minting store that is pruned.
+	Store MintingStorePruner
+
+	// Retention is the age after which batches that reached a terminal
+	// state are pruned. If zero, the janitor is disabled.
+	Retention time.Duration
+
+	// PruneTicker is the ticker that triggers pruning the minting store.
+	PruneTicker ticker.Ticker
+}
+
+// BatchJanitor periodically garbage-collects minting batches that were
+// cancelled or couldn't be funded, and the seedlings of finalized batches,
+// once they exceed the configured retention age.
+type BatchJanitor struct {
+	startOnce sync.Once
+	stopOnce  sync.Once
+
+	cfg *BatchJanitorConfig
+
+	// ContextGuard provides a wait group and main quit channel that can be
+	// used to create guarded contexts.
+	*chanutils.ContextGuard
+}
+
+// NewBatchJanitor creates a new batch janitor based on the passed config.
+func NewBatchJanitor(cfg *BatchJanitorConfig) *BatchJanitor {
+	return &BatchJanitor{
+		cfg: cfg,
+		ContextGuard: &chanutils.ContextGuard{
+			DefaultTimeout: DefaultTimeout,
+			Quit:           make(chan struct{}),
+		},
+	}
+}
+
+// Start attempts to start the batch janitor.
+func (j *BatchJanitor) Start() error {
+	j.startOnce.Do(func() {
+		if j.cfg.Retention == 0 {
+			log.Info("Batch janitor disabled, no retention set")
+			return
+		}
+
+		log.Infof("Starting batch janitor, retention=%v",
+			j.cfg.Retention)
+
+		j.cfg.PruneTicker.Resume()
+
+		j.Wg.Add(1)
+		go j.pruneBatches()
+	})
+
+	return nil
+}
+
+// Stop signals for the batch janitor to gracefully exit.
+func (j *BatchJanitor) Stop() error {
+	j.stopOnce.Do(func() {
+		log.Info("Stopping batch janitor")
+
+		close(j.Quit)
+		j.Wg.Wait()
+
+		if j.cfg.Retention != 0 {
+			j.cfg.PruneTicker.Stop()
+		}
+	})
+
+	return nil
+}
+
+// pruneBatches is the main event loop of the batch janitor. It prunes the
+// minting store once on startup, and then every time the prune ticker fires.
+func (j *BatchJanitor) pruneBatches() {
+	defer j.Wg.Done()
+
+	for {
+		// A failure to prune the store isn't critical, so we only log
+		// it and try again on the next tick.
+		if err := j.prune(); err != nil {
+			log.Errorf("Unable to prune minting batches: %v", err)
+		}
+
+		select {
+		case <-j.cfg.PruneTicker.Ticks():
+
+		case <-j.Quit:
+			return
+		}
+	}
+}
+
+// prune deletes all batches and seedlings that exceeded the retention age
+// from the minting store.
+func (j *BatchJanitor) prune() error {
+	ctx, cancel := j.WithCtxQuit()
+	defer cancel()
+
+	createdBefore := time.Now().Add(-j.cfg.Retention)
+	result, err := j.cfg.Store.PruneMintingBatches(ctx, createdBefore)
+	if err != nil {
+		return err
+	}
+
+	monitoring.ObservePrunedRows(
+		"asset_minting_batches", result.NumBatches,
+	)
+	monitoring.ObservePrunedRows("asset_seedlings", result.NumSeedlings)
+	monitoring.ObservePrunedRows("assets", result.NumSprouts)
+	monitoring.ObservePrunedRows(
+		"batch_proof_push_attempts", result.NumProofPushAttempts,
+	)
+
+	if result.NumBatches > 0 || result.NumSeedlings > 0 {
+		log.Infof("Pruned %d minting batches created before %v, "+
+			"along with %d seedlings, %d sprouts and %d proof "+
+			"push attempts", result.NumBatches, createdBefore,
+			result.NumSeedlings, result.NumSprouts,
+			result.NumProofPushAttempts)
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package tapgarden_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lightninglabs/taproot-assets/tapgarden"
+	"github.com/lightningnetwork/lnd/ticker"
+	"github.com/stretchr/testify/require"
+)
+
+// mockMintingStorePruner is a tapgarden.MintingStorePruner that reports the
+// cutoff time of every prune request.
+type mockMintingStorePruner struct {
+	prunes chan time.Time
+}
+
+func (m *mockMintingStorePruner) PruneMintingBatches(_ context.Context,
+	createdBefore time.Time) (*tapgarden.MintingPruneResult, error) {
+
+	m.prunes <- createdBefore
+
+	return &tapgarden.MintingPruneResult{}, nil
+}
+
+// TestBatchJanitor tests that the batch janitor prunes the minting store on
+// startup and on every tick, using the configured retention age.
+func TestBatchJanitor(t *testing.T) {
+	t.Parallel()
+
+	const retention = 24 * time.Hour
+
+	store := &mockMintingStorePruner{
+		prunes: make(chan time.Time, 1),
+	}
+	pruneTicker := ticker.NewForce(time.Hour)
+	janitor := tapgarden.NewBatchJanitor(&tapgarden.BatchJanitorConfig{
+		Store:       store,
+		Retention:   retention,
+		PruneTicker: pruneTicker,
+	})
+
+	assertPrune := func() {
+		t.Helper()
+
+		select {
+		case createdBefore := <-store.prunes:
+			require.WithinDuration(
+				t, time.Now().Add(-retention), createdBefore,
+				time.Minute,
+			)
+
+		case <-time.After(defaultTimeout):
+			t.Fatalf("minting store wasn't pruned")
+		}
+	}
+
+	require.NoError(t, janitor.Start())
+	t.Cleanup(func() {
+		require.NoError(t, janitor.Stop())
+	})
+
+	// The store is pruned right away, and then on every tick.
+	assertPrune()
+
+	pruneTicker.Force <- time.Now()
+	assertPrune()
+
+	// Without a retention age, the janitor doesn't prune anything.
+	disabledStore := &mockMintingStorePruner{
+		prunes: make(chan time.Time, 1),
+	}
+	disabledJanitor := tapgarden.NewBatchJanitor(
+		&tapgarden.BatchJanitorConfig{
+			Store:       disabledStore,
+			PruneTicker: ticker.NewForce(time.Hour),
+		},
+	)
+	require.NoError(t, disabledJanitor.Start())
+	require.NoError(t, disabledJanitor.Stop())
+	require.Empty(t, disabledStore.prunes)
+}
@@ -5,6 +5,8 @@ import (
 	"encoding/hex"
 	"fmt"
 	"math/rand"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -33,14 +35,38 @@ func RandSeedlings(t testing.TB, numSeedlings int) map[string]*Seedling {
 	for i := 0; i < numSeedlings; i++ {
 		metaBlob := test.RandBytes(32)
 		assetName := hex.EncodeToString(test.RandBytes(32))
+		assetType := asset.Type(rand.Int31n(2))
 		seedlings[assetName] = &Seedling{
-			AssetType: asset.Type(rand.Int31n(2)),
+			AssetType: assetType,
 			AssetName: assetName,
 			Meta: &proof.MetaReveal{
 				Data: metaBlob,
 			},
 			Amount:         uint64(rand.Int31()),
 			EnableEmission: test.RandBool(),
+			NonDivisible: assetType == asset.Normal &&
+				test.RandBool(),
+		}
+
+		// Some of the new groups should commit to a tapscript tree.
+		if seedlings[assetName].EnableEmission && test.RandBool() {
+			seedlings[assetName].GroupTapscriptRoot = test.RandBytes(
+				32,
+			)
+		}
+
+		// Some of the normal assets should be displayed with decimal
+		// places, which are committed to in their JSON meta data.
+		if assetType == asset.Normal && test.RandBool() {
+			seedling := seedlings[assetName]
+			seedling.Meta = nil
+			seedling.DecimalDisplay = uint32(
+				rand.Int31n(asset.MaxDecimalDisplay) + 1,
+			)
+			if err := seedling.encodeDecimalDisplay(); err != nil {
+				t.Fatalf("unable to encode decimal display: %v",
+					err)
+			}
 		}
 	}
 
@@ -75,6 +101,18 @@ type MockWalletAnchor struct {
 
 	Transactions  []lndclient.Transaction
 	ImportedUtxos []*lnwallet.Utxo
+
+	// FundPsbtFeeRate is the fee rate of the last funding request. It is
+	// set before the request is signaled on FundPsbtSignal.
+	FundPsbtFeeRate chainfee.SatPerKWeight
+
+	// FundPsbtCoinControl is the coin control of the last funding request.
+	// It is set before the request is signaled on FundPsbtSignal.
+	FundPsbtCoinControl *CoinControl
+
+	// FundPsbtFailures is the number of upcoming funding requests that
+	// fail, before packets are funded again.
+	FundPsbtFailures atomic.Int32
 }
 
 func NewMockWalletAnchor() *MockWalletAnchor {
@@ -90,22 +128,35 @@ func NewMockWalletAnchor() *MockWalletAnchor {
 }
 
 func (m *MockWalletAnchor) FundPsbt(_ context.Context, packet *psbt.Packet,
-	_ uint32, _ chainfee.SatPerKWeight) (FundedPsbt, error) {
+	_ uint32, feeRate chainfee.SatPerKWeight,
+	coinControl *CoinControl) (FundedPsbt, error) {
+
+	if m.FundPsbtFailures.Load() > 0 {
+		m.FundPsbtFailures.Add(-1)
+		return FundedPsbt{}, fmt.Errorf("insufficient funds available")
+	}
 
 	// Take the PSBT packet and add an additional input and output to
-	// simulate the wallet funding the transaction.
-	packet.UnsignedTx.AddTxIn(&wire.TxIn{
-		PreviousOutPoint: wire.OutPoint{
-			Index: rand.Uint32(),
-		},
-	})
-	packet.Inputs = append(packet.Inputs, psbt.PInput{
-		WitnessUtxo: &wire.TxOut{
-			Value:    100000,
-			PkScript: []byte{0x1},
-		},
-		SighashType: txscript.SigHashDefault,
-	})
+	// simulate the wallet funding the transaction. If the inputs to spend
+	// are given, we'll use those instead.
+	outpoints := []wire.OutPoint{{
+		Index: rand.Uint32(),
+	}}
+	if coinControl != nil && len(coinControl.Outpoints) > 0 {
+		outpoints = coinControl.Outpoints
+	}
+	for _, outpoint := range outpoints {
+		packet.UnsignedTx.AddTxIn(&wire.TxIn{
+			PreviousOutPoint: outpoint,
+		})
+		packet.Inputs = append(packet.Inputs, psbt.PInput{
+			WitnessUtxo: &wire.TxOut{
+				Value:    100000,
+				PkScript: []byte{0x1},
+			},
+			SighashType: txscript.SigHashDefault,
+		})
+	}
 	packet.UnsignedTx.AddTxOut(&wire.TxOut{
 		Value:    50000,
 		PkScript: []byte{0x2},
@@ -119,6 +170,8 @@ func (m *MockWalletAnchor) FundPsbt(_ context.Context, packet *psbt.Packet,
 		ChangeOutputIndex: 1,
 	}
 
+	m.FundPsbtFeeRate = feeRate
+	m.FundPsbtCoinControl = coinControl
 	m.FundPsbtSignal <- &pkt
 
 	return pkt, nil
@@ -161,7 +214,9 @@ func (m *MockWalletAnchor) ImportTaprootOutput(ctx context.Context,
 	)
 }
 
-func (m *MockWalletAnchor) UnlockInput(_ context.Context) error {
+func (m *MockWalletAnchor) UnlockInput(_ context.Context,
+	_ []wire.OutPoint) error {
+
 	return nil
 }
 
@@ -237,6 +292,15 @@ type MockChainBridge struct {
 
 	ReqCount int
 	ConfReqs map[int]*chainntnfs.ConfirmationEvent
+
+	// BlockEpochs is the channel new block heights are sent over to all
+	// block epoch subscribers.
+	BlockEpochs chan int32
+
+	// blockHashes are the hashes of the blocks of the best chain, keyed
+	// by their height.
+	blockHashes    map[int64]chainhash.Hash
+	blockHashesMtx sync.Mutex
 }
 
 func NewMockChainBridge() *MockChainBridge {
@@ -245,9 +309,22 @@ func NewMockChainBridge() *MockChainBridge {
 		PublishReq:        make(chan *wire.MsgTx),
 		ConfReqs:          make(map[int]*chainntnfs.ConfirmationEvent),
 		ConfReqSignal:     make(chan int),
+		BlockEpochs:       make(chan int32),
+		blockHashes:       make(map[int64]chainhash.Hash),
 	}
 }
 
+// SetBlockHash sets the hash of the block at the given height of the best
+// chain.
+func (m *MockChainBridge) SetBlockHash(blockHeight int64,
+	hash chainhash.Hash) {
+
+	m.blockHashesMtx.Lock()
+	defer m.blockHashesMtx.Unlock()
+
+	m.blockHashes[blockHeight] = hash
+}
+
 func (m *MockChainBridge) SendConfNtfn(reqNo int, blockHash *chainhash.Hash,
 	blockHeight, blockIndex int, block *wire.MsgBlock,
 	tx *wire.MsgTx) {
@@ -291,6 +368,19 @@ func (m *MockChainBridge) RegisterConfirmationsNtfn(ctx context.Context,
 	return req, errChan, nil
 }
 
+// RegisterBlockEpochNtfn returns the mock's block epoch channel.
+func (m *MockChainBridge) RegisterBlockEpochNtfn(
+	ctx context.Context) (chan int32, chan error, error) {
+
+	select {
+	case <-ctx.Done():
+		return nil, nil, fmt.Errorf("shutting down")
+	default:
+	}
+
+	return m.BlockEpochs, make(chan error), nil
+}
+
 // GetBlock returns a chain block given its hash.
 func (m *MockChainBridge) GetBlock(ctx context.Context,
 	hash chainhash.Hash) (*wire.MsgBlock, error) {
@@ -298,6 +388,17 @@ func (m *MockChainBridge) GetBlock(ctx context.Context,
 	return &wire.MsgBlock{}, nil
 }
 
+// GetBlockHash returns the hash of the block in the best chain at the given
+// height, as set by SetBlockHash, or the zero hash if none was set.
+func (m *MockChainBridge) GetBlockHash(ctx context.Context,
+	blockHeight int64) (chainhash.Hash, error) {
+
+	m.blockHashesMtx.Lock()
+	defer m.blockHashesMtx.Unlock()
+
+	return m.blockHashes[blockHeight], nil
+}
+
 func (m *MockChainBridge) CurrentHeight(_ context.Context) (uint32, error) {
 	return 0, nil
 }
@@ -323,7 +424,6 @@ func (m *MockChainBridge) EstimateFee(ctx context.Context,
 }
 
 type MockKeyRing struct {
-	FamIndex keychain.KeyFamily
 	KeyIndex uint32
 
 	Keys map[keychain.KeyLocator]*btcec.PrivateKey
@@ -356,7 +456,6 @@ func (m *MockKeyRing) DeriveNextKey(ctx context.Context,
 	}
 
 	defer func() {
-		m.FamIndex++
 		m.KeyIndex++
 	}()
 
@@ -367,7 +466,7 @@ func (m *MockKeyRing) DeriveNextKey(ctx context.Context,
 
 	loc := keychain.KeyLocator{
 		Index:  m.KeyIndex,
-		Family: m.FamIndex,
+		Family: keyFam,
 	}
 
 	m.Keys[loc] = priv
@@ -387,7 +486,7 @@ func (m *MockKeyRing) DeriveNextKey(ctx context.Context,
 }
 
 func (m *MockKeyRing) DeriveKey(ctx context.Context,
-	_ keychain.KeyLocator) (keychain.KeyDescriptor, error) {
+	keyLoc keychain.KeyLocator) (keychain.KeyDescriptor, error) {
 
 	select {
 	case <-ctx.Done():
@@ -395,7 +494,22 @@ func (m *MockKeyRing) DeriveKey(ctx context.Context,
 	default:
 	}
 
-	return keychain.KeyDescriptor{}, nil
+	// The same locator always derives the same key.
+	priv, ok := m.Keys[keyLoc]
+	if !ok {
+		var err error
+		priv, err = btcec.NewPrivateKey()
+		if err != nil {
+			return keychain.KeyDescriptor{}, err
+		}
+
+		m.Keys[keyLoc] = priv
+	}
+
+	return keychain.KeyDescriptor{
+		KeyLocator: keyLoc,
+		PubKey:     priv.PubKey(),
+	}, nil
 }
 
 func (m *MockKeyRing) IsLocalKey(context.Context, keychain.KeyDescriptor) bool {
@@ -421,18 +535,53 @@ func (m *MockGenSigner) SignGenesis(desc keychain.KeyDescriptor,
 	return signer.SignGenesis(desc, initialGen, currentGen)
 }
 
+func (m *MockGenSigner) SignGroupVirtualTx(desc keychain.KeyDescriptor,
+	tapscriptRoot []byte, leaf *txscript.TapLeaf, virtualTx *wire.MsgTx,
+	prevOut *wire.TxOut) (*schnorr.Signature, error) {
+
+	priv := m.KeyRing.Keys[desc.KeyLocator]
+	signer := asset.NewRawKeyGenesisSigner(priv)
+	return signer.SignGroupVirtualTx(
+		desc, tapscriptRoot, leaf, virtualTx, prevOut,
+	)
+}
+
 type MockProofArchive struct {
+	sync.Mutex
+
+	proofs map[[32]byte]proof.Blob
+}
+
+func NewMockProofArchive() *MockProofArchive {
+	return &MockProofArchive{
+		proofs: make(map[[32]byte]proof.Blob),
+	}
 }
 
 func (m *MockProofArchive) FetchProof(ctx context.Context,
 	id proof.Locator) (proof.Blob, error) {
 
-	return nil, nil
+	m.Lock()
+	defer m.Unlock()
+
+	blob, ok := m.proofs[id.Hash()]
+	if !ok {
+		return nil, proof.ErrProofNotFound
+	}
+
+	return blob, nil
 }
 
 func (m *MockProofArchive) ImportProofs(ctx context.Context,
 	headerVerifier proof.HeaderVerifier,
 	proofs ...*proof.AnnotatedProof) error {
 
+	m.Lock()
+	defer m.Unlock()
+
+	for _, p := range proofs {
+		m.proofs[p.Locator.Hash()] = p.Blob
+	}
+
 	return nil
 }
@@ -3,14 +3,21 @@ package tapgarden
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/wire"
 	"github.com/lightninglabs/taproot-assets/asset"
 	"github.com/lightninglabs/taproot-assets/chanutils"
+	"github.com/lightninglabs/taproot-assets/commitment"
+	"github.com/lightninglabs/taproot-assets/monitoring"
 	"github.com/lightninglabs/taproot-assets/proof"
 	"github.com/lightninglabs/taproot-assets/universe"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/ticker"
 	"golang.org/x/exp/maps"
 )
@@ -45,6 +52,16 @@ type GardenKit struct {
 	// Universe is used to register new asset issuance with a local/remote
 	// base universe instance.
 	Universe universe.Registrar
+
+	// ProofPush optionally configures a universe server that the genesis
+	// proofs of every confirmed batch are pushed to. If nil, the proofs
+	// aren't pushed anywhere.
+	ProofPush *ProofPushConfig
+
+	// RetryPolicy configures how the caretakers retry steps of a batch that
+	// failed with a possibly transient error, like a fee estimation, the
+	// funding of the genesis transaction or its broadcast.
+	RetryPolicy RetryPolicy
 }
 
 // PlanterConfig is the main config for the ChainPlanter.
@@ -59,9 +76,61 @@ type PlanterConfig struct {
 	// critical errors to the main server.
 	ErrChan chan<- error
 
+	// AutoFinalize configures the limits at which the default pending
+	// batch is finalized automatically, in addition to the batch ticker.
+	AutoFinalize AutoFinalizeConfig
+
+	// AllowExternalBatchKeys allows callers to create a batch with their
+	// own internal key, like a key of a cold-storage keychain, instead of
+	// a key derived by the backing wallet.
+	AllowExternalBatchKeys bool
+
 	// TODO(roasbeef): something notification related?
 }
 
+// AutoFinalizeConfig configures when the planter finalizes the default
+// pending batch on its own, without waiting for the batch ticker. This allows
+// unattended minting services to bound the latency of a batch
+// deterministically. A zero value for a limit disables it.
+type AutoFinalizeConfig struct {
+	// MaxSeedlings is the number of seedlings at which the default
+	// pending batch is finalized.
+	MaxSeedlings int
+
+	// MaxBatchBlocks is the number of blocks mined after the creation of
+	// the default pending batch at which it's finalized.
+	MaxBatchBlocks uint32
+}
+
+// RetryPolicy configures how often, and at which pace, a caretaker retries a
+// step of its batch that failed. A zero value disables retries.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times a step is attempted
+	// before the caretaker gives up on it.
+	MaxAttempts uint32
+
+	// InitialBackoff is the time to wait before the first retry. The
+	// backoff is doubled after every failed retry.
+	InitialBackoff time.Duration
+
+	// MaxBackoff is the upper bound of the time to wait between two
+	// attempts.
+	MaxBackoff time.Duration
+}
+
+// ProofPushConfig configures the push of the genesis proofs of confirmed
+// batches to a universe server, so issuer proofs are available to receivers
+// without being exported manually.
+type ProofPushConfig struct {
+	// Registrar is the universe server the genesis proofs are pushed to.
+	Registrar universe.Registrar
+
+	// BackoffCfg configures how failed pushes are retried. Every attempt
+	// is logged in the MintingStore, so the backoff also holds across
+	// restarts.
+	BackoffCfg *proof.BackoffCfg
+}
+
 // BatchKey is a type alias for a serialized public key.
 type BatchKey = asset.SerializedKey
 
@@ -141,14 +210,62 @@ func typedParam[T any](req stateRequest) (*T, error) {
 	return nil, fmt.Errorf("invalid type")
 }
 
+// finalizeParams are the parameters of a request to finalize a pending
+// batch.
+type finalizeParams struct {
+	// batchKey is the key of the pending batch to finalize. If nil, the
+	// default pending batch is finalized.
+	batchKey *btcec.PublicKey
+
+	// feeRate is the optional fee rate to fund the genesis transaction
+	// of the batch at.
+	feeRate *chainfee.SatPerKWeight
+
+	// tapSibling is the optional tapscript sibling preimage to commit to
+	// alongside the Taproot Asset commitment in the genesis output.
+	tapSibling *commitment.TapscriptPreimage
+
+	// coinControl optionally restricts the wallet UTXOs the genesis
+	// transaction of the batch may spend.
+	coinControl *CoinControl
+}
+
+// fundParams are the parameters of a request to finalize a pending batch
+// with an externally funded genesis transaction.
+type fundParams struct {
+	// batchKey is the key of the pending batch to finalize. If nil, the
+	// default pending batch is finalized.
+	batchKey *btcec.PublicKey
+
+	// fundedPsbt is the externally funded packet the genesis output of
+	// the batch is committed into.
+	fundedPsbt *psbt.Packet
+}
+
+// cancelSeedlingParams are the parameters of a request to cancel a seedling
+// in a pending batch.
+type cancelSeedlingParams struct {
+	// batchKey is the key of the pending batch the seedling is in. If
+	// nil, the seedling is removed from the default pending batch.
+	batchKey *btcec.PublicKey
+
+	// assetName is the name of the asset the seedling would create.
+	assetName string
+}
+
 type reqType uint8
 
 const (
-	reqTypePendingBatch = iota
+	reqTypePendingBatches = iota
+	reqTypeNewBatch
 	reqTypeNumActiveBatches
 	reqTypeListBatches
 	reqTypeFinalizeBatch
 	reqTypeCancelBatch
+	reqTypeCancelSeedling
+	reqTypeFundBatch
+	reqTypeSubmitSignedPsbt
+	reqTypePreviewBatch
 )
 
 // ChainPlanter is responsible for accepting new incoming requests to create
@@ -165,9 +282,16 @@ type ChainPlanter struct {
 	// seedlingReqs is used to accept new asset issuance requests.
 	seedlingReqs chan *Seedling
 
-	// pendingBatch is the current pending, non-frozen batch. Only one of
-	// these will exist at any given time.
-	pendingBatch *MintingBatch
+	// pendingBatches maps the batch key of each pending, non-frozen batch
+	// to the batch. Each of these batches can be added to and finalized
+	// independently.
+	pendingBatches map[BatchKey]*MintingBatch
+
+	// defaultBatch is the key of the pending batch that seedlings queued
+	// without a batch key are added to, if one exists. Only this batch is
+	// frozen by the batch ticker, all other pending batches must be
+	// finalized explicitly.
+	defaultBatch *BatchKey
 
 	// caretakers maps a batch key (which is used as the internal key for
 	// the transaction that mints the assets) to the caretaker that will
@@ -192,6 +316,7 @@ type ChainPlanter struct {
 func NewChainPlanter(cfg PlanterConfig) *ChainPlanter {
 	return &ChainPlanter{
 		cfg:               cfg,
+		pendingBatches:    make(map[BatchKey]*MintingBatch),
 		caretakers:        make(map[BatchKey]*BatchCaretaker),
 		completionSignals: make(chan BatchKey),
 		seedlingReqs:      make(chan *Seedling),
@@ -205,17 +330,22 @@ func NewChainPlanter(cfg PlanterConfig) *ChainPlanter {
 
 // newCaretakerForBatch creates a new BatchCaretaker for a given batch and
 // inserts it into the caretaker map.
-func (c *ChainPlanter) newCaretakerForBatch(batch *MintingBatch) *BatchCaretaker {
+func (c *ChainPlanter) newCaretakerForBatch(batch *MintingBatch,
+	feeRate *chainfee.SatPerKWeight, coinControl *CoinControl,
+	externalPsbt *psbt.Packet) *BatchCaretaker {
+
 	batchKey := asset.ToSerialized(batch.BatchKey.PubKey)
 	caretaker := NewBatchCaretaker(&BatchCaretakerConfig{
-		Batch:     batch,
-		GardenKit: c.cfg.GardenKit,
+		Batch:        batch,
+		GardenKit:    c.cfg.GardenKit,
+		BatchFeeRate: feeRate,
+		CoinControl:  coinControl,
+		ExternalPsbt: externalPsbt,
 		SignalCompletion: func() {
 			c.completionSignals <- batchKey
 		},
 		CancelReqChan:  make(chan struct{}, 1),
 		CancelRespChan: make(chan CancelResp, 1),
-		ErrChan:        c.cfg.ErrChan,
 	})
 	c.caretakers[batchKey] = caretaker
 
@@ -252,10 +382,13 @@ func (c *ChainPlanter) Start() error {
 
 		// Now for each of these non-final batches, we'll make a new
 		// caretaker which'll handle progressing each batch to
-		// completion. We'll skip batches that were cancelled.
+		// completion. We'll skip batches that were cancelled, or that
+		// couldn't be funded.
 		for _, batch := range nonFinalBatches {
-			if batch.BatchState == BatchStateSeedlingCancelled ||
-				batch.BatchState == BatchStateSproutCancelled {
+			switch batch.BatchState {
+			case BatchStateSeedlingCancelled,
+				BatchStateSproutCancelled,
+				BatchStateFundingFailed:
 
 				continue
 			}
@@ -270,18 +403,42 @@ func (c *ChainPlanter) Start() error {
 				batch.AssetMetas = make(AssetMetas)
 			}
 
-			caretaker := c.newCaretakerForBatch(batch)
+			// A manually set fee rate or coin control isn't
+			// persisted, so a batch that wasn't funded before the
+			// restart is funded at an estimated fee rate, from any
+			// wallet UTXOs.
+			caretaker := c.newCaretakerForBatch(
+				batch, nil, nil, nil,
+			)
 			if err := caretaker.Start(); err != nil {
 				startErr = err
 				return
 			}
 		}
 
+		// If batches should be finalized at a certain height, we'll
+		// need to know about each new block.
+		var (
+			blockEpochs chan int32
+			epochErrs   chan error
+		)
+		if c.cfg.AutoFinalize.MaxBatchBlocks > 0 {
+			// The subscription is cancelled once we shut down.
+			epochCtx, epochCancel := c.WithCtxQuitNoTimeout()
+			blockEpochs, epochErrs, err = c.cfg.ChainBridge.
+				RegisterBlockEpochNtfn(epochCtx)
+			if err != nil {
+				epochCancel()
+				startErr = err
+				return
+			}
+		}
+
 		// With all the caretakers for each minting batch launched,
 		// we'll start up the main gardener goroutine so we can accept
 		// new minting requests.
 		c.Wg.Add(1)
-		go c.gardener()
+		go c.gardener(blockEpochs, epochErrs)
 	})
 
 	return startErr
@@ -320,8 +477,9 @@ func freezeMintingBatch(ctx context.Context, batchStore MintingStore,
 	batch *MintingBatch) error {
 
 	batchKey := batch.BatchKey.PubKey
+	batchLog := monitoring.CorrelatedLogger(log, batch.CorrelationID())
 
-	log.Infof("Freezing MintingBatch(key=%x, num_assets=%v",
+	batchLog.Infof("Freezing MintingBatch(key=%x, num_assets=%v)",
 		batchKey.SerializeCompressed(), len(batch.Seedlings))
 
 	// In order to freeze a batch, we need to update the state of the batch
@@ -333,6 +491,49 @@ func freezeMintingBatch(ctx context.Context, batchStore MintingStore,
 	)
 }
 
+// checkFeeRate returns an error if the given manually set fee rate can't be
+// used to fund a genesis transaction.
+func checkFeeRate(feeRate chainfee.SatPerKWeight) error {
+	if feeRate < chainfee.FeePerKwFloor {
+		return fmt.Errorf("fee rate %v below floor of %v", feeRate,
+			chainfee.FeePerKwFloor)
+	}
+
+	return nil
+}
+
+// checkTapSibling returns an error if the given tapscript sibling preimage
+// can't be committed to alongside the Taproot Asset commitment of a batch.
+func checkTapSibling(tapSibling *commitment.TapscriptPreimage) error {
+	if tapSibling.IsEmpty() {
+		return fmt.Errorf("tapscript sibling preimage is empty")
+	}
+
+	if err := tapSibling.VerifyNoCommitment(); err != nil {
+		return fmt.Errorf("invalid tapscript sibling: %w", err)
+	}
+
+	if _, err := tapSibling.TapHash(); err != nil {
+		return fmt.Errorf("invalid tapscript sibling: %w", err)
+	}
+
+	return nil
+}
+
+// checkCoinControl returns an error if the given coin control can't be used to
+// fund the genesis transaction of a batch.
+func checkCoinControl(coinControl *CoinControl) error {
+	outpoints := make(map[wire.OutPoint]struct{})
+	for _, outpoint := range coinControl.Outpoints {
+		if _, ok := outpoints[outpoint]; ok {
+			return fmt.Errorf("duplicate outpoint %v", outpoint)
+		}
+		outpoints[outpoint] = struct{}{}
+	}
+
+	return nil
+}
+
 // ListBatches returns the single batch specified by the batch key, or the set
 // of batches not yet finalized on disk.
 func listBatches(ctx context.Context, batchStore MintingStore,
@@ -350,41 +551,77 @@ func listBatches(ctx context.Context, batchStore MintingStore,
 	return []*MintingBatch{batch}, nil
 }
 
-// canCancelBatch returns a batch key if the planter is in a state where a batch
-// can be cancelled. This does not account for the state of a caretaker that
-// may be managing a batch.
-func (c *ChainPlanter) canCancelBatch() (*btcec.PublicKey, error) {
-	caretakerCount := len(c.caretakers)
+// pendingBatch returns the pending batch with the given key, or the default
+// pending batch if no key is given. An error is returned if there's no such
+// pending batch.
+func (c *ChainPlanter) pendingBatch(
+	batchKey *btcec.PublicKey) (*MintingBatch, error) {
 
-	switch caretakerCount {
-	case 0:
-		// If there are no caretakers, the only batch we could cancel
-		// would be the current pending batch.
-		if c.pendingBatch == nil {
+	if batchKey == nil {
+		if c.defaultBatch == nil {
 			return nil, fmt.Errorf("no pending batch")
 		}
 
-		return c.pendingBatch.BatchKey.PubKey, nil
-	case 1:
-		// TODO(jhb): Update once we support multiple batches.
-		// If there is exactly one caretaker, our pending batch should
-		// be empty. Otherwise, the batch to cancel is ambiguous.
-		if c.pendingBatch != nil {
-			return nil, fmt.Errorf("multiple batches not supported")
+		return c.pendingBatches[*c.defaultBatch], nil
+	}
+
+	batch, ok := c.pendingBatches[asset.ToSerialized(batchKey)]
+	if !ok {
+		return nil, fmt.Errorf("no pending batch with key %x",
+			batchKey.SerializeCompressed())
+	}
+
+	return batch, nil
+}
+
+// removePendingBatch removes the pending batch with the given key, clearing
+// the default batch if it's the one removed.
+func (c *ChainPlanter) removePendingBatch(batchKey BatchKey) {
+	delete(c.pendingBatches, batchKey)
+
+	if c.defaultBatch != nil && *c.defaultBatch == batchKey {
+		c.defaultBatch = nil
+	}
+}
+
+// canCancelBatch returns a batch key if the planter is in a state where a batch
+// can be cancelled. If no batch key is given, there must be exactly one batch
+// that is either pending or managed by a caretaker, as the batch to cancel is
+// ambiguous otherwise. This does not account for the state of a caretaker that
+// may be managing a batch.
+func (c *ChainPlanter) canCancelBatch(
+	batchKey *btcec.PublicKey) (*btcec.PublicKey, error) {
+
+	if batchKey != nil {
+		key := asset.ToSerialized(batchKey)
+		_, isPending := c.pendingBatches[key]
+		_, hasCaretaker := c.caretakers[key]
+		if !isPending && !hasCaretaker {
+			return nil, fmt.Errorf("no batch with key %x",
+				batchKey.SerializeCompressed())
 		}
 
-		batchKeys := maps.Keys(c.caretakers)
-		batchKey, err := btcec.ParsePubKey(batchKeys[0][:])
+		return batchKey, nil
+	}
+
+	batchKeys := append(
+		maps.Keys(c.pendingBatches), maps.Keys(c.caretakers)...,
+	)
+	switch len(batchKeys) {
+	case 0:
+		return nil, fmt.Errorf("no pending batch")
+
+	case 1:
+		key, err := btcec.ParsePubKey(batchKeys[0][:])
 		if err != nil {
-			return nil, fmt.Errorf("bad caretaker key: %w", err)
+			return nil, fmt.Errorf("bad batch key: %w", err)
 		}
 
-		return batchKey, nil
+		return key, nil
+
 	default:
+		return nil, fmt.Errorf("multiple batches, batch key required")
 	}
-
-	// TODO(jhb): Update once we support multiple batches.
-	return nil, fmt.Errorf("multiple caretakers not supported")
 }
 
 // cancelMintingBatch attempts to cancel a target minting batch. This can fail
@@ -422,8 +659,16 @@ func (c *ChainPlanter) cancelMintingBatch(ctx context.Context,
 		}
 	}
 
+	batch := c.pendingBatches[batchKeySerialized]
 	log.Infof("Cancelling MintingBatch(key=%x, num_assets=%v)",
-		batchKeySerialized, len(c.pendingBatch.Seedlings))
+		batchKeySerialized, len(batch.Seedlings))
+
+	// A batch without any seedlings was never written to disk, so there's
+	// nothing to update.
+	if len(batch.Seedlings) == 0 {
+		c.removePendingBatch(batchKeySerialized)
+		return nil
+	}
 
 	// If the target batch was not assigned a caretaker, we only need to
 	// update the batch state on disk to cancel it.
@@ -434,14 +679,140 @@ func (c *ChainPlanter) cancelMintingBatch(ctx context.Context,
 		return fmt.Errorf("unable to cancel minting batch: %w", err)
 	}
 
+	c.removePendingBatch(batchKeySerialized)
+
+	return nil
+}
+
+// cancelSeedling removes the seedling with the given asset name from the
+// given pending batch, both in memory and on disk. If it was the last seedling
+// of the batch, the whole batch is cancelled.
+func (c *ChainPlanter) cancelSeedling(ctx context.Context, batch *MintingBatch,
+	assetName string) error {
+
+	if _, ok := batch.Seedlings[assetName]; !ok {
+		return fmt.Errorf("asset with name %v not in batch", assetName)
+	}
+
+	// Other seedlings may be issued into a group anchored by this
+	// seedling, in which case it can't be removed on its own.
+	for _, seedling := range batch.Seedlings {
+		if seedling.GroupAnchor != nil &&
+			*seedling.GroupAnchor == assetName {
+
+			return fmt.Errorf("asset %v is the group anchor of "+
+				"asset %v", assetName, seedling.AssetName)
+		}
+	}
+
+	batchKey := batch.BatchKey.PubKey
+	batchLog := monitoring.CorrelatedLogger(log, batch.CorrelationID())
+	batchLog.Infof("Cancelling seedling %v of MintingBatch(key=%x)",
+		assetName, batchKey.SerializeCompressed())
+
+	// A batch is written to disk along with its first seedling, so an
+	// empty batch would be committed a second time once a new seedling is
+	// added. Instead, we cancel a batch once its last seedling is gone.
+	if len(batch.Seedlings) == 1 {
+		return c.cancelMintingBatch(ctx, batchKey)
+	}
+
+	err := c.cfg.Log.DeleteSeedlingFromBatch(ctx, batchKey, assetName)
+	if err != nil {
+		return fmt.Errorf("unable to delete seedling: %w", err)
+	}
+
+	delete(batch.Seedlings, assetName)
+
 	return nil
 }
 
+// finalizeBatch freezes the given pending batch, so no new seedlings can be
+// added to it, and launches a caretaker that'll fund the genesis transaction
+// of the batch at the given fee rate, or an estimated one if it's nil, from
+// the wallet UTXOs the given coin control allows. If an externally funded
+// packet is given, the genesis output is committed into it instead.
+func (c *ChainPlanter) finalizeBatch(batch *MintingBatch,
+	feeRate *chainfee.SatPerKWeight, coinControl *CoinControl,
+	externalPsbt *psbt.Packet) error {
+
+	if len(batch.Seedlings) == 0 {
+		return fmt.Errorf("batch %x has no seedlings",
+			batch.BatchKey.PubKey.SerializeCompressed())
+	}
+
+	ctx, cancel := c.WithCtxQuit()
+	defer cancel()
+
+	// If the batch commits to a tapscript sibling, we'll store it before
+	// freezing the batch, so the caretaker can still construct the
+	// genesis output after a restart.
+	if batch.TapSibling != nil {
+		err := c.cfg.Log.CommitBatchTapSibling(
+			ctx, batch.BatchKey.PubKey, batch.TapSibling,
+		)
+		if err != nil {
+			return fmt.Errorf("unable to commit tapscript "+
+				"sibling: %w", err)
+		}
+	}
+
+	// First, we'll finalize the batch on disk. This means no further
+	// seedlings can be added to this batch.
+	err := freezeMintingBatch(ctx, c.cfg.Log, batch)
+	if err != nil {
+		return fmt.Errorf("unable to freeze minting batch: %w", err)
+	}
+
+	// Now that the batch has been frozen, we'll launch a new caretaker
+	// state machine for the batch that'll drive all the seedlings do
+	// adulthood.
+	caretaker := c.newCaretakerForBatch(
+		batch, feeRate, coinControl, externalPsbt,
+	)
+	if err := caretaker.Start(); err != nil {
+		delete(c.caretakers, asset.ToSerialized(batch.BatchKey.PubKey))
+		return fmt.Errorf("unable to start new caretaker: %w", err)
+	}
+
+	// Now that we have a caretaker launched for this batch, it's no longer
+	// pending.
+	c.removePendingBatch(asset.ToSerialized(batch.BatchKey.PubKey))
+
+	return nil
+}
+
+// previewBatch builds the genesis transaction and the sprouts of the given
+// pending batch with a caretaker that is never started, so the batch is
+// neither frozen nor committed to disk.
+func (c *ChainPlanter) previewBatch(batch *MintingBatch,
+	feeRate *chainfee.SatPerKWeight) (*BatchPreview, error) {
+
+	if len(batch.Seedlings) == 0 {
+		return nil, fmt.Errorf("batch %x has no seedlings",
+			batch.BatchKey.PubKey.SerializeCompressed())
+	}
+
+	caretaker := NewBatchCaretaker(&BatchCaretakerConfig{
+		Batch:        batch,
+		GardenKit:    c.cfg.GardenKit,
+		BatchFeeRate: feeRate,
+	})
+
+	ctx, cancel := c.WithCtxQuit()
+	defer cancel()
+
+	return caretaker.previewBatch(ctx)
+}
+
 // gardener is responsible for collecting new potential taproot asset
 // seeds/seedlings into a batch to ultimately be anchored in a genesis output
 // creating the assets from seedlings into sprouts, and eventually fully grown
-// assets.
-func (c *ChainPlanter) gardener() {
+// assets. If batches are finalized at a certain height, the new block heights
+// are delivered over the given block epoch channel.
+func (c *ChainPlanter) gardener(blockEpochs chan int32,
+	epochErrs chan error) {
+
 	defer c.Wg.Done()
 
 	// When this exits due to the quit signal, we also want to stop all the
@@ -453,51 +824,60 @@ func (c *ChainPlanter) gardener() {
 	for {
 		select {
 		case <-c.cfg.BatchTicker.Ticks():
-			// No pending batch, so we can just continue back to
-			// the top of the loop.
-			if c.pendingBatch == nil {
+			// No default batch, so we can just continue back to
+			// the top of the loop. Any other pending batches are
+			// only finalized when explicitly requested.
+			batch, err := c.pendingBatch(nil)
+			if err != nil {
 				log.Debugf("No batches pending...doing nothing")
 				continue
 			}
 
-			// Prep the new care taker that'll be launched assuming
-			// the call below to freeze the batch succeeds.
-			caretaker := c.newCaretakerForBatch(c.pendingBatch)
+			err = c.finalizeBatch(batch, nil, nil, nil)
+			if err != nil {
+				c.cfg.ErrChan <- err
+				continue
+			}
 
-			// At this point, we have a non-empty batch, so we'll
-			// first finalize it on disk. This means no further
-			// seedlings can be added to this batch.
-			ctx, cancel := c.WithCtxQuit()
-			err := freezeMintingBatch(ctx, c.cfg.Log, c.pendingBatch)
-			cancel()
+		// A new block was mined, so the default batch may have reached
+		// its maximum age.
+		case height := <-blockEpochs:
+			batch, err := c.pendingBatch(nil)
 			if err != nil {
-				c.cfg.ErrChan <- fmt.Errorf("unable to freeze "+
-					"minting batch: %w", err)
 				continue
 			}
 
-			// Now that the batch has been frozen, we'll launch a
-			// new caretaker state machine for the batch that'll
-			// drive all the seedlings do adulthood.
-			if err := caretaker.Start(); err != nil {
-				c.cfg.ErrChan <- fmt.Errorf("unable to start "+
-					"new caretaker: %w", err)
+			maxBlocks := c.cfg.AutoFinalize.MaxBatchBlocks
+			if uint32(height) < batch.HeightHint+maxBlocks {
 				continue
 			}
 
-			// Now that we have a caretaker launched for this
-			// batch, we'll set the pending batch to nil
-			c.pendingBatch = nil
+			batchLog := monitoring.CorrelatedLogger(
+				log, batch.CorrelationID(),
+			)
+			batchLog.Infof("Auto-finalizing batch created at "+
+				"height %v at height %v", batch.HeightHint,
+				height)
+
+			err = c.finalizeBatch(batch, nil, nil, nil)
+			if err != nil {
+				c.cfg.ErrChan <- err
+				continue
+			}
+
+		case err := <-epochErrs:
+			c.cfg.ErrChan <- fmt.Errorf("unable to receive block "+
+				"epoch: %w", err)
 
 		// A request for new asset issuance just arrived, add this to
-		// the pending batch and acknowledge the receipt back to the
-		// caller.
+		// the target pending batch and acknowledge the receipt back to
+		// the caller.
 		case req := <-c.seedlingReqs:
 			// After some basic validation, prepare the asset
 			// seedling (soon to be a sprout) by committing it to
-			// disk as part of the latest batch.
+			// disk as part of the target batch.
 			ctx, cancel := c.WithCtxQuit()
-			err := c.prepAssetSeedling(ctx, req)
+			batch, err := c.prepAssetSeedling(ctx, req)
 			cancel()
 			if err != nil {
 				// Something went wrong, so then an error
@@ -508,7 +888,11 @@ func (c *ChainPlanter) gardener() {
 				continue
 			}
 
-			log.Infof("Request for new seedling: %v", req)
+			batchLog := monitoring.CorrelatedLogger(
+				log, batch.CorrelationID(),
+			)
+			batchLog.Infof("Request for new seedling: %v", req)
+			monitoring.ObserveSeedlingQueued()
 
 			// Otherwise if we've got to this point then we can
 			// return a response back to the caller that the
@@ -517,10 +901,28 @@ func (c *ChainPlanter) gardener() {
 			// TODO(roasbeef): extend the ticker by a certain
 			// portion?
 			req.updates <- SeedlingUpdate{
-				BatchKey: c.pendingBatch.BatchKey.PubKey,
+				BatchKey: batch.BatchKey.PubKey,
 				NewState: MintingStateSeed,
 			}
 
+			// If the seedling filled up the default batch, we'll
+			// finalize it right away.
+			maxSeedlings := c.cfg.AutoFinalize.MaxSeedlings
+			if req.batchKey != nil || maxSeedlings == 0 ||
+				len(batch.Seedlings) < maxSeedlings {
+
+				continue
+			}
+
+			batchLog.Infof("Auto-finalizing batch with %v "+
+				"seedlings", len(batch.Seedlings))
+
+			err = c.finalizeBatch(batch, nil, nil, nil)
+			if err != nil {
+				c.cfg.ErrChan <- err
+				continue
+			}
+
 		// A caretaker has finished processing their batch to full
 		// Taproot Asset maturity. We'll clean up our local state, and
 		// signal that it can exit.
@@ -547,8 +949,38 @@ func (c *ChainPlanter) gardener() {
 		// A new request just came along to query our internal state.
 		case req := <-c.stateReqs:
 			switch req.Type() {
-			case reqTypePendingBatch:
-				req.Resolve(c.pendingBatch)
+			case reqTypePendingBatches:
+				batches := maps.Values(c.pendingBatches)
+				sort.Slice(batches, func(i, j int) bool {
+					return batches[i].CreationTime.Before(
+						batches[j].CreationTime,
+					)
+				})
+				req.Resolve(batches)
+			case reqTypeNewBatch:
+				keyDesc, err := typedParam[*keychain.KeyDescriptor](
+					req,
+				)
+				if err != nil {
+					req.Error(fmt.Errorf("bad batch "+
+						"key: %w", err))
+					break
+				}
+
+				ctx, cancel := c.WithCtxQuit()
+				batch, err := c.newMintingBatch(ctx, *keyDesc)
+				cancel()
+				if err != nil {
+					req.Error(err)
+					break
+				}
+
+				batchKey := asset.ToSerialized(
+					batch.BatchKey.PubKey,
+				)
+				c.pendingBatches[batchKey] = batch
+
+				req.Resolve(batch.BatchKey.PubKey)
 			case reqTypeNumActiveBatches:
 				req.Resolve(len(c.caretakers))
 			case reqTypeListBatches:
@@ -571,38 +1003,188 @@ func (c *ChainPlanter) gardener() {
 
 				req.Resolve(batches)
 			case reqTypeFinalizeBatch:
-				if c.pendingBatch == nil {
-					req.Error(fmt.Errorf("no pending batch"))
+				params, err := typedParam[finalizeParams](req)
+				if err != nil {
+					req.Error(fmt.Errorf("bad finalize "+
+						"params: %w", err))
+					break
+				}
+
+				batch, err := c.pendingBatch(params.batchKey)
+				if err != nil {
+					req.Error(err)
 					break
 				}
 
-				batchKey := c.pendingBatch.BatchKey.PubKey
-				log.Infof("Finalizing batch %x",
+				feeRate := params.feeRate
+				if feeRate != nil {
+					err := checkFeeRate(*feeRate)
+					if err != nil {
+						req.Error(err)
+						break
+					}
+				}
+
+				coinControl := params.coinControl
+				if coinControl != nil {
+					err := checkCoinControl(coinControl)
+					if err != nil {
+						req.Error(err)
+						break
+					}
+				}
+
+				tapSibling := params.tapSibling
+				if tapSibling != nil {
+					err := checkTapSibling(tapSibling)
+					if err != nil {
+						req.Error(err)
+						break
+					}
+
+					batch.TapSibling = tapSibling
+				}
+
+				batchKey := batch.BatchKey.PubKey
+				batchLog := monitoring.CorrelatedLogger(
+					log, batch.CorrelationID(),
+				)
+				batchLog.Infof("Finalizing batch %x",
 					batchKey.SerializeCompressed())
 
-				// A force tick must be sent in a separate
-				// goroutine to prevent deadlock.
-				go func() {
-					c.cfg.BatchTicker.Force <- time.Time{}
-				}()
+				err = c.finalizeBatch(
+					batch, feeRate, coinControl, nil,
+				)
+				if err != nil {
+					req.Error(err)
+					break
+				}
+
 				req.Resolve(batchKey)
 			case reqTypeCancelBatch:
-				batchKey, err := c.canCancelBatch()
+				batchKey, err := typedParam[*btcec.PublicKey](req)
+				if err != nil {
+					req.Error(fmt.Errorf("bad batch "+
+						"key: %w", err))
+					break
+				}
+
+				cancelKey, err := c.canCancelBatch(*batchKey)
 				if err != nil {
 					req.Error(err)
 					break
 				}
 
-				// Attempt to cancel the current batch, and then
-				// clear the pending batch in the planter.
+				// Attempt to cancel the target batch, which
+				// also removes it from the pending batches.
 				ctx, cancel := c.WithCtxQuit()
-				err = c.cancelMintingBatch(ctx, batchKey)
+				err = c.cancelMintingBatch(ctx, cancelKey)
 				cancel()
-				c.pendingBatch = nil
 
 				// Always return the key of the batch we tried
 				// to cancel.
-				req.Return(batchKey, err)
+				req.Return(cancelKey, err)
+			case reqTypeCancelSeedling:
+				params, err := typedParam[cancelSeedlingParams](
+					req,
+				)
+				if err != nil {
+					req.Error(fmt.Errorf("bad cancel "+
+						"params: %w", err))
+					break
+				}
+
+				batch, err := c.pendingBatch(params.batchKey)
+				if err != nil {
+					req.Error(err)
+					break
+				}
+
+				ctx, cancel := c.WithCtxQuit()
+				err = c.cancelSeedling(
+					ctx, batch, params.assetName,
+				)
+				cancel()
+				if err != nil {
+					req.Error(err)
+					break
+				}
+
+				req.Resolve(true)
+			case reqTypeFundBatch:
+				params, err := typedParam[fundParams](req)
+				if err != nil {
+					req.Error(fmt.Errorf("bad fund "+
+						"params: %w", err))
+					break
+				}
+
+				batch, err := c.pendingBatch(params.batchKey)
+				if err != nil {
+					req.Error(err)
+					break
+				}
+
+				batchKey := asset.ToSerialized(
+					batch.BatchKey.PubKey,
+				)
+				batchLog := monitoring.CorrelatedLogger(
+					log, batch.CorrelationID(),
+				)
+				batchLog.Infof("Finalizing batch %x with "+
+					"external psbt", batchKey[:])
+
+				err = c.finalizeBatch(
+					batch, nil, nil, params.fundedPsbt,
+				)
+				if err != nil {
+					req.Error(err)
+					break
+				}
+
+				req.Resolve(c.caretakers[batchKey])
+			case reqTypeSubmitSignedPsbt:
+				batchKey, err := typedParam[*btcec.PublicKey](req)
+				if err != nil {
+					req.Error(fmt.Errorf("bad batch "+
+						"key: %w", err))
+					break
+				}
+
+				key := asset.ToSerialized(*batchKey)
+				caretaker, ok := c.caretakers[key]
+				if !ok {
+					req.Error(fmt.Errorf("no active batch "+
+						"with key %x", key[:]))
+					break
+				}
+
+				req.Resolve(caretaker)
+			case reqTypePreviewBatch:
+				params, err := typedParam[finalizeParams](req)
+				if err != nil {
+					req.Error(fmt.Errorf("bad preview "+
+						"params: %w", err))
+					break
+				}
+
+				batch, err := c.pendingBatch(params.batchKey)
+				if err != nil {
+					req.Error(err)
+					break
+				}
+
+				feeRate := params.feeRate
+				if feeRate != nil {
+					err := checkFeeRate(*feeRate)
+					if err != nil {
+						req.Error(err)
+						break
+					}
+				}
+
+				preview, err := c.previewBatch(batch, feeRate)
+				req.Return(preview, err)
 			}
 
 		case <-c.Quit:
@@ -611,10 +1193,9 @@ func (c *ChainPlanter) gardener() {
 	}
 }
 
-// PendingBatch returns the current pending batch. If there's no pending batch,
-// then an error is returned.
-func (c *ChainPlanter) PendingBatch() (*MintingBatch, error) {
-	req := newStateReq[*MintingBatch](reqTypePendingBatch)
+// PendingBatches returns all pending batches, oldest first.
+func (c *ChainPlanter) PendingBatches() ([]*MintingBatch, error) {
+	req := newStateReq[[]*MintingBatch](reqTypePendingBatches)
 
 	if !chanutils.SendOrQuit[stateRequest](c.stateReqs, req, c.Quit) {
 		return nil, fmt.Errorf("chain planter shutting down")
@@ -623,6 +1204,29 @@ func (c *ChainPlanter) PendingBatch() (*MintingBatch, error) {
 	return <-req.resp, nil
 }
 
+// NewBatch creates a new, empty pending batch and returns its key. Seedlings
+// can then be added to the batch by passing the key to QueueNewSeedling, and
+// the batch is only frozen once it's explicitly finalized. The batch is only
+// written to disk once its first seedling is added.
+//
+// If a batch key is given, it's used as the internal key of the batch instead
+// of a freshly derived key. A key that only specifies a key locator is derived
+// by the backing wallet, while a public key is used as is, which requires
+// external batch keys to be allowed.
+//
+// NOTE: This is part of the Planter interface.
+func (c *ChainPlanter) NewBatch(
+	batchKey *keychain.KeyDescriptor) (*btcec.PublicKey, error) {
+
+	req := newStateParamReq[*btcec.PublicKey](reqTypeNewBatch, batchKey)
+
+	if !chanutils.SendOrQuit[stateRequest](c.stateReqs, req, c.Quit) {
+		return nil, fmt.Errorf("chain planter shutting down")
+	}
+
+	return <-req.resp, <-req.err
+}
+
 // NumActiveBatches returns the total number of active batches that have an
 // outstanding caretaker assigned.
 func (c *ChainPlanter) NumActiveBatches() (int, error) {
@@ -649,9 +1253,26 @@ func (c *ChainPlanter) ListBatches(batchKey *btcec.PublicKey) ([]*MintingBatch,
 	return <-req.resp, <-req.err
 }
 
-// FinalizeBatch sends a signal to the planter to finalize the current batch.
-func (c *ChainPlanter) FinalizeBatch() (*btcec.PublicKey, error) {
-	req := newStateReq[*btcec.PublicKey](reqTypeFinalizeBatch)
+// FinalizeBatch sends a signal to the planter to finalize the pending batch
+// with the given key, or the default pending batch if no key is given. If a
+// fee rate is given, the genesis transaction of the batch is funded at that
+// rate instead of an estimated one. If a tapscript sibling is given, it is
+// committed to alongside the Taproot Asset commitment in the genesis output.
+// If coin control is given, the genesis transaction only spends the wallet
+// UTXOs it allows.
+func (c *ChainPlanter) FinalizeBatch(batchKey *btcec.PublicKey,
+	feeRate *chainfee.SatPerKWeight,
+	tapSibling *commitment.TapscriptPreimage,
+	coinControl *CoinControl) (*btcec.PublicKey, error) {
+
+	req := newStateParamReq[*btcec.PublicKey](
+		reqTypeFinalizeBatch, finalizeParams{
+			batchKey:    batchKey,
+			feeRate:     feeRate,
+			tapSibling:  tapSibling,
+			coinControl: coinControl,
+		},
+	)
 
 	if !chanutils.SendOrQuit[stateRequest](c.stateReqs, req, c.Quit) {
 		return nil, fmt.Errorf("chain planter shutting down")
@@ -660,9 +1281,20 @@ func (c *ChainPlanter) FinalizeBatch() (*btcec.PublicKey, error) {
 	return <-req.resp, <-req.err
 }
 
-// CancelBatch sends a signal to the planter to cancel the current batch.
-func (c *ChainPlanter) CancelBatch() (*btcec.PublicKey, error) {
-	req := newStateReq[*btcec.PublicKey](reqTypeCancelBatch)
+// PreviewBatch sends a signal to the planter to build the genesis transaction,
+// the asset sprouts and the Taproot Asset commitment of the pending batch with
+// the given key, or the default pending batch if no key is given. Nothing is
+// finalized or broadcast, and the batch stays pending. If a fee rate is given,
+// the genesis transaction is funded at that rate instead of an estimated one.
+func (c *ChainPlanter) PreviewBatch(batchKey *btcec.PublicKey,
+	feeRate *chainfee.SatPerKWeight) (*BatchPreview, error) {
+
+	req := newStateParamReq[*BatchPreview](
+		reqTypePreviewBatch, finalizeParams{
+			batchKey: batchKey,
+			feeRate:  feeRate,
+		},
+	)
 
 	if !chanutils.SendOrQuit[stateRequest](c.stateReqs, req, c.Quit) {
 		return nil, fmt.Errorf("chain planter shutting down")
@@ -671,15 +1303,201 @@ func (c *ChainPlanter) CancelBatch() (*btcec.PublicKey, error) {
 	return <-req.resp, <-req.err
 }
 
+// FundBatch sends a signal to the planter to finalize the pending batch with
+// the given key, or the default pending batch if no key is given, and to
+// commit its genesis output into the given externally funded PSBT packet. The
+// first input of the packet is used as the genesis point of the batch. The
+// returned packet contains the genesis output, and needs to be signed
+// externally, then handed back through SubmitSignedBatchPsbt.
+func (c *ChainPlanter) FundBatch(batchKey *btcec.PublicKey,
+	fundedPsbt *psbt.Packet) (*psbt.Packet, error) {
+
+	if fundedPsbt == nil {
+		return nil, fmt.Errorf("funded psbt required")
+	}
+
+	req := newStateParamReq[*BatchCaretaker](
+		reqTypeFundBatch, fundParams{
+			batchKey:   batchKey,
+			fundedPsbt: fundedPsbt,
+		},
+	)
+
+	if !chanutils.SendOrQuit[stateRequest](c.stateReqs, req, c.Quit) {
+		return nil, fmt.Errorf("chain planter shutting down")
+	}
+
+	caretaker, err := <-req.resp, <-req.err
+	if err != nil {
+		return nil, err
+	}
+
+	// The caretaker commits the genesis output into the packet in the
+	// background, so we'll wait for it to hand us the result.
+	return caretaker.waitForAnchorPsbt()
+}
+
+// SubmitSignedBatchPsbt hands the externally signed genesis PSBT packet of
+// the batch with the given key to the batch's caretaker, which then
+// broadcasts it. The batch must have been funded through FundBatch.
+func (c *ChainPlanter) SubmitSignedBatchPsbt(batchKey *btcec.PublicKey,
+	signedPsbt *psbt.Packet) error {
+
+	if batchKey == nil {
+		return fmt.Errorf("batch key required")
+	}
+	if signedPsbt == nil {
+		return fmt.Errorf("signed psbt required")
+	}
+
+	req := newStateParamReq[*BatchCaretaker](
+		reqTypeSubmitSignedPsbt, batchKey,
+	)
+
+	if !chanutils.SendOrQuit[stateRequest](c.stateReqs, req, c.Quit) {
+		return fmt.Errorf("chain planter shutting down")
+	}
+
+	caretaker, err := <-req.resp, <-req.err
+	if err != nil {
+		return err
+	}
+
+	return caretaker.submitSignedPsbt(signedPsbt)
+}
+
+// CancelBatch sends a signal to the planter to cancel the batch with the given
+// key. If no key is given, the only existing batch is cancelled.
+func (c *ChainPlanter) CancelBatch(
+	batchKey *btcec.PublicKey) (*btcec.PublicKey, error) {
+
+	req := newStateParamReq[*btcec.PublicKey](
+		reqTypeCancelBatch, batchKey,
+	)
+
+	if !chanutils.SendOrQuit[stateRequest](c.stateReqs, req, c.Quit) {
+		return nil, fmt.Errorf("chain planter shutting down")
+	}
+
+	return <-req.resp, <-req.err
+}
+
+// newMintingBatch creates a new, empty minting batch with the given batch key,
+// or a freshly derived one if no key is given. The batch isn't written to disk.
+func (c *ChainPlanter) newMintingBatch(ctx context.Context,
+	batchKey *keychain.KeyDescriptor) (*MintingBatch, error) {
+
+	// To create a new batch we'll first need to grab a new internal key,
+	// which'll be used in the output we create, and also will serve as the
+	// primary identifier for a batch.
+	newInternalKey, err := c.batchInternalKey(ctx, batchKey)
+	if err != nil {
+		return nil, err
+	}
+
+	currentHeight, err := c.cfg.ChainBridge.CurrentHeight(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get current height: %v", err)
+	}
+
+	return &MintingBatch{
+		CreationTime: time.Now(),
+		HeightHint:   currentHeight,
+		BatchState:   BatchStatePending,
+		BatchKey:     newInternalKey,
+		Seedlings:    make(map[string]*Seedling),
+		AssetMetas:   make(AssetMetas),
+	}, nil
+}
+
+// batchInternalKey returns the internal key for a new batch. If no batch key
+// is given, the next key of the Taproot Assets key family is derived. A batch
+// key without a public key is derived from its key locator, otherwise the
+// public key is used as is. In any case, the key must not be used by another
+// batch already.
+func (c *ChainPlanter) batchInternalKey(ctx context.Context,
+	batchKey *keychain.KeyDescriptor) (keychain.KeyDescriptor, error) {
+
+	var (
+		internalKey keychain.KeyDescriptor
+		err         error
+	)
+	switch {
+	case batchKey == nil:
+		return c.cfg.KeyRing.DeriveNextKey(
+			ctx, asset.TaprootAssetsKeyFamily,
+		)
+
+	case batchKey.PubKey == nil:
+		internalKey, err = c.cfg.KeyRing.DeriveKey(
+			ctx, batchKey.KeyLocator,
+		)
+		if err != nil {
+			return internalKey, fmt.Errorf("unable to derive "+
+				"batch key: %w", err)
+		}
+
+	case !c.cfg.AllowExternalBatchKeys:
+		return internalKey, fmt.Errorf("external batch keys not " +
+			"allowed")
+
+	default:
+		internalKey = *batchKey
+	}
+
+	serializedKey := asset.ToSerialized(internalKey.PubKey)
+	_, isPending := c.pendingBatches[serializedKey]
+	_, hasCaretaker := c.caretakers[serializedKey]
+	if isPending || hasCaretaker {
+		return internalKey, fmt.Errorf("batch key %x already in use",
+			serializedKey[:])
+	}
+
+	// A batch that was already finalized or cancelled isn't tracked in
+	// memory, so we'll also make sure it isn't on disk.
+	existingBatch, err := c.cfg.Log.FetchMintingBatch(
+		ctx, internalKey.PubKey,
+	)
+	if err == nil && existingBatch != nil {
+		return internalKey, fmt.Errorf("batch key %x already in use",
+			serializedKey[:])
+	}
+
+	return internalKey, nil
+}
+
 // prepAssetSeedling performs some basic validation for the Seedling, then
-// either adds it to an existing pending batch or creates a new batch for it. A
-// bool indicating if a new batch should immediately be created is returned.
+// adds it to its target pending batch. If the seedling doesn't target a batch
+// and there's no default pending batch yet, a new default batch is created for
+// it. The batch the seedling was added to is returned.
 func (c *ChainPlanter) prepAssetSeedling(ctx context.Context,
-	req *Seedling) error {
+	req *Seedling) (*MintingBatch, error) {
 
 	// First, we'll perform some basic validation for the seedling.
 	if err := req.validateFields(); err != nil {
-		return err
+		return nil, err
+	}
+
+	// The decimal display is committed to as part of the asset's meta
+	// data, so we'll encode it before the seedling is persisted.
+	if err := req.encodeDecimalDisplay(); err != nil {
+		return nil, err
+	}
+
+	// If the seedling picks an existing group by the locator of its raw
+	// key, we'll look up the group, so it's validated just like a group
+	// that's specified by its tweaked key.
+	if req.GroupKeyLocator != nil {
+		groupInfo, err := c.cfg.Log.FetchGroupByKeyLocator(
+			ctx, *req.GroupKeyLocator,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("group key locator %v not "+
+				"found: %w", *req.GroupKeyLocator, err)
+		}
+
+		req.GroupInfo = groupInfo
+		req.GroupKeyLocator = nil
 	}
 
 	// If emission is enabled and a group key is specified, we need to
@@ -691,119 +1509,100 @@ func (c *ChainPlanter) prepAssetSeedling(ctx context.Context,
 		if err != nil {
 			groupKeyBytes := req.GroupInfo.GroupPubKey.
 				SerializeCompressed()
-			return fmt.Errorf("group key %x not found: %w",
+			return nil, fmt.Errorf("group key %x not found: %w",
 				groupKeyBytes, err,
 			)
 		}
 
 		if err := req.validateGroupKey(*groupInfo); err != nil {
-			return err
+			return nil, err
 		}
 
 		req.GroupInfo = groupInfo
 	}
 
-	// If a group anchor is specified, we need to ensure that the anchor
-	// seedling is already in the batch and has emission enabled.
-	if req.GroupAnchor != nil {
-		if c.pendingBatch == nil {
-			return fmt.Errorf("batch empty, group anchor %v "+
-				"invalid", *req.GroupAnchor)
-		}
-
-		err := c.pendingBatch.validateGroupAnchor(req)
-		if err != nil {
-			return err
-		}
-	}
-
-	// Now that we know the field are valid, we'll check to see if a batch
-	// already exists.
+	// Now that we know the field are valid, we'll look up the batch the
+	// seedling should be added to.
+	batch, err := c.pendingBatch(req.batchKey)
 	switch {
-	// No batch, so we'll create a new one with only this seedling as part
-	// of the batch.
-	case c.pendingBatch == nil:
+	// No default batch, so we'll create a new one that'll become the
+	// default batch.
+	case err != nil && req.batchKey == nil:
 		log.Infof("Creating new MintingBatch w/ %v", req)
 
-		// To create a new batch we'll first need to grab a new
-		// internal key, which'll be used in the output we create, and
-		// also will serve as the primary identifier for a batch.
-		newInternalKey, err := c.cfg.KeyRing.DeriveNextKey(
-			ctx, asset.TaprootAssetsKeyFamily,
-		)
+		batch, err = c.newMintingBatch(ctx, nil)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		ctx, cancel := c.WithCtxQuit()
-		defer cancel()
-		currentHeight, err := c.cfg.ChainBridge.CurrentHeight(ctx)
-		if err != nil {
-			return fmt.Errorf("unable to get current height: %v",
-				err)
-		}
+	// The seedling targets a batch that doesn't exist.
+	case err != nil:
+		return nil, err
+	}
 
-		// Create a new batch and commit it to disk so we can pick up
-		// where we left off upon restart.
-		newBatch := &MintingBatch{
-			CreationTime: time.Now(),
-			HeightHint:   currentHeight,
-			BatchState:   BatchStatePending,
-			BatchKey:     newInternalKey,
-			Seedlings: map[string]*Seedling{
-				req.AssetName: req,
-			},
-			AssetMetas: make(AssetMetas),
-		}
-		ctx, cancel = c.WithCtxQuit()
-		defer cancel()
-		err = c.cfg.Log.CommitMintingBatch(ctx, newBatch)
-		if err != nil {
-			return err
+	// If a group anchor is specified, we need to ensure that the anchor
+	// seedling is already in the batch and has emission enabled.
+	if req.GroupAnchor != nil {
+		if len(batch.Seedlings) == 0 {
+			return nil, fmt.Errorf("batch empty, group anchor %v "+
+				"invalid", *req.GroupAnchor)
 		}
 
-		c.pendingBatch = newBatch
+		if err := batch.validateGroupAnchor(req); err != nil {
+			return nil, err
+		}
+	}
 
-	// A batch already exists, so we'll add this seedling to the batch,
-	// committing it to disk fully before we move on.
-	case c.pendingBatch != nil:
-		log.Infof("Adding %v to existing MintingBatch", req)
+	batchLog := monitoring.CorrelatedLogger(log, batch.CorrelationID())
+	batchLog.Infof("Adding %v to MintingBatch", req)
 
-		// First attempt to add the seedling to our pending batch, if
-		// this name is already taken (in the batch), then an error
-		// will be returned.
-		//
-		// TODO(roasbeef): unique constraint below? will trigger on the
-		// name?
-		if err := c.pendingBatch.addSeedling(req); err != nil {
-			return err
-		}
+	// First attempt to add the seedling to the batch, if this name is
+	// already taken (in the batch), then an error will be returned.
+	//
+	// TODO(roasbeef): unique constraint below? will trigger on the name?
+	if err := batch.addSeedling(req); err != nil {
+		return nil, err
+	}
 
-		// Now that we know the seedling is ok, we'll write it to disk.
-		ctx, cancel := c.WithCtxQuit()
-		defer cancel()
-		err := c.cfg.Log.AddSeedlingsToBatch(
-			ctx, c.pendingBatch.BatchKey.PubKey, req,
+	// Now that we know the seedling is ok, we'll write it to disk. A batch
+	// is only committed to disk along with its first seedling, so we can
+	// pick up where we left off upon restart.
+	if len(batch.Seedlings) == 1 {
+		err = c.cfg.Log.CommitMintingBatch(ctx, batch)
+	} else {
+		err = c.cfg.Log.AddSeedlingsToBatch(
+			ctx, batch.BatchKey.PubKey, req,
 		)
-		if err != nil {
-			return err
-		}
+	}
+	if err != nil {
+		// Remove the seedling again, so the batch in memory still
+		// matches the one on disk.
+		delete(batch.Seedlings, req.AssetName)
+		return nil, err
 	}
 
-	// Now that we have the batch committed to disk, we'll return back to
-	// the caller if we should finalize the batch immediately or not based
-	// on its preference.
-	return nil
+	batchKey := asset.ToSerialized(batch.BatchKey.PubKey)
+	c.pendingBatches[batchKey] = batch
+	if req.batchKey == nil {
+		c.defaultBatch = &batchKey
+	}
+
+	return batch, nil
 }
 
 // QueueNewSeedling attempts to queue a new seedling request (the intent for
-// New asset creation or on going issuance) to the ChainPlanter. A channel is
-// returned where future updates will be sent over. If an error is returned no
-// issuance operation was possible.
+// New asset creation or on going issuance) to the ChainPlanter. If a batch key
+// is given, the seedling is added to that pending batch, otherwise it's added
+// to the default pending batch. A channel is returned where future updates
+// will be sent over. If an error is returned no issuance operation was
+// possible.
 //
 // NOTE: This is part of the Planter interface.
-func (c *ChainPlanter) QueueNewSeedling(req *Seedling) (SeedlingUpdates, error) {
+func (c *ChainPlanter) QueueNewSeedling(req *Seedling,
+	batchKey *btcec.PublicKey) (SeedlingUpdates, error) {
+
 	req.updates = make(SeedlingUpdates, 1)
+	req.batchKey = batchKey
 
 	// Attempt to send the new request, or exit if the quit channel
 	// triggered first.
@@ -814,14 +1613,29 @@ func (c *ChainPlanter) QueueNewSeedling(req *Seedling) (SeedlingUpdates, error)
 	return req.updates, nil
 }
 
-// CancelSeedling attempts to cancel the creation of a new asset identified by
-// its name. If the seedling has already progressed to a point where the
-// genesis PSBT has been broadcasted, an error is returned.
+// CancelSeedling removes the seedling with the given asset name from the
+// pending batch with the given key, or the default pending batch if no key is
+// given. The seedling is also deleted from disk, so it's no longer minted once
+// the batch is finalized. If it was the last seedling of the batch, the whole
+// batch is cancelled.
 //
 // NOTE: This is part of the Planter interface.
-func (c *ChainPlanter) CancelSeedling() error {
-	// TODO(roasbeef): actually needed?
-	return nil
+func (c *ChainPlanter) CancelSeedling(assetName string,
+	batchKey *btcec.PublicKey) error {
+
+	req := newStateParamReq[bool](
+		reqTypeCancelSeedling, cancelSeedlingParams{
+			batchKey:  batchKey,
+			assetName: assetName,
+		},
+	)
+
+	if !chanutils.SendOrQuit[stateRequest](c.stateReqs, req, c.Quit) {
+		return fmt.Errorf("chain planter shutting down")
+	}
+
+	<-req.resp
+	return <-req.err
 }
 
 // A compile-time assertion to make sure that ChainPlanter implements the
@@ -6,13 +6,17 @@ import (
 	"database/sql"
 	"encoding/hex"
 	"fmt"
+	"math"
 	"math/rand"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/btcsuite/btcd/blockchain"
 	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
 	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/psbt"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/txscript"
@@ -20,14 +24,18 @@ import (
 	"github.com/davecgh/go-spew/spew"
 	"github.com/lightninglabs/taproot-assets/asset"
 	"github.com/lightninglabs/taproot-assets/chanutils"
+	"github.com/lightninglabs/taproot-assets/commitment"
 	"github.com/lightninglabs/taproot-assets/internal/test"
 	"github.com/lightninglabs/taproot-assets/proof"
 	"github.com/lightninglabs/taproot-assets/tapdb"
 	_ "github.com/lightninglabs/taproot-assets/tapdb" // Register relevant drivers.
 	"github.com/lightninglabs/taproot-assets/tapgarden"
+	"github.com/lightninglabs/taproot-assets/tapscript"
+	"github.com/lightninglabs/taproot-assets/universe"
 	"github.com/lightningnetwork/lnd/build"
 	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/lightningnetwork/lnd/lntest/wait"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/ticker"
 	"github.com/stretchr/testify/require"
 )
@@ -74,6 +82,20 @@ type mintingTestHarness struct {
 
 	proofFiles *tapgarden.MockProofArchive
 
+	// autoFinalize is the auto-finalize config the planter is created
+	// with.
+	autoFinalize tapgarden.AutoFinalizeConfig
+
+	// allowExternalBatchKeys is whether the planter is created with
+	// external batch keys allowed.
+	allowExternalBatchKeys bool
+
+	// proofPush is the proof push config the planter is created with.
+	proofPush *tapgarden.ProofPushConfig
+
+	// retryPolicy is the retry policy the planter is created with.
+	retryPolicy tapgarden.RetryPolicy
+
 	*testing.T
 
 	errChan chan error
@@ -88,14 +110,15 @@ func newMintingTestHarness(t *testing.T, store tapgarden.MintingStore,
 	genSigner := tapgarden.NewMockGenSigner(keyRing)
 
 	return &mintingTestHarness{
-		T:         t,
-		store:     store,
-		ticker:    ticker.NewForce(interval),
-		wallet:    tapgarden.NewMockWalletAnchor(),
-		chain:     tapgarden.NewMockChainBridge(),
-		keyRing:   keyRing,
-		genSigner: genSigner,
-		errChan:   make(chan error, 10),
+		T:          t,
+		store:      store,
+		ticker:     ticker.NewForce(interval),
+		wallet:     tapgarden.NewMockWalletAnchor(),
+		chain:      tapgarden.NewMockChainBridge(),
+		keyRing:    keyRing,
+		genSigner:  genSigner,
+		proofFiles: tapgarden.NewMockProofArchive(),
+		errChan:    make(chan error, 10),
 	}
 }
 
@@ -115,9 +138,14 @@ func (t *mintingTestHarness) refreshChainPlanter() {
 			KeyRing:     t.keyRing,
 			GenSigner:   t.genSigner,
 			ProofFiles:  t.proofFiles,
+			ProofPush:   t.proofPush,
+			RetryPolicy: t.retryPolicy,
 		},
-		BatchTicker: t.ticker,
-		ErrChan:     t.errChan,
+		BatchTicker:  t.ticker,
+		ErrChan:      t.errChan,
+		AutoFinalize: t.autoFinalize,
+
+		AllowExternalBatchKeys: t.allowExternalBatchKeys,
 	})
 	require.NoError(t, t.planter.Start())
 }
@@ -171,7 +199,7 @@ func (t *mintingTestHarness) queueSeedlingsInBatch(
 		// Queue the new seedling for a batch.
 		//
 		// TODO(roasbeef): map of update chans?
-		updates, err := t.planter.QueueNewSeedling(seedling)
+		updates, err := t.planter.QueueNewSeedling(seedling, nil)
 		require.NoError(t, err)
 
 		// For the first seedlings sent, we should get a new request
@@ -192,24 +220,60 @@ func (t *mintingTestHarness) queueSeedlingsInBatch(
 	}
 }
 
+// newPendingBatch creates a new, empty pending batch and returns its key.
+func (t *mintingTestHarness) newPendingBatch() *btcec.PublicKey {
+	t.Helper()
+
+	type result struct {
+		key *btcec.PublicKey
+		err error
+	}
+	results := make(chan result, 1)
+	go func() {
+		key, err := t.planter.NewBatch(nil)
+		results <- result{key, err}
+	}()
+
+	// Creating a batch derives a new batch key.
+	derivedKey := t.assertKeyDerived()
+
+	res, err := chanutils.RecvOrTimeout(results, defaultTimeout)
+	require.NoError(t, err)
+	require.NoError(t, res.err)
+	require.True(t, res.key.IsEqual(derivedKey.PubKey))
+
+	return res.key
+}
+
+// sendBlockEpoch notifies the planter of a new block at the given height.
+func (t *mintingTestHarness) sendBlockEpoch(height int32) {
+	t.Helper()
+
+	select {
+	case t.chain.BlockEpochs <- height:
+	case <-time.After(defaultTimeout):
+		t.Fatalf("block epoch at height %v not received", height)
+	}
+}
+
 // assertPendingBatchExists asserts that a pending batch is found and it has
 // numSeedlings assets registered.
 func (t *mintingTestHarness) assertPendingBatchExists(numSeedlings int) {
 	t.Helper()
 
-	batch, err := t.planter.PendingBatch()
+	batches, err := t.planter.PendingBatches()
 	require.NoError(t, err)
-	require.NotNil(t, batch)
-	require.Len(t, batch.Seedlings, numSeedlings)
+	require.Len(t, batches, 1)
+	require.Len(t, batches[0].Seedlings, numSeedlings)
 }
 
 // assertNoActiveBatch asserts that no pending batch exists.
 func (t *mintingTestHarness) assertNoPendingBatch() {
 	t.Helper()
 
-	batch, err := t.planter.PendingBatch()
+	batches, err := t.planter.PendingBatches()
 	require.NoError(t, err)
-	require.Nil(t, batch)
+	require.Empty(t, batches)
 }
 
 // tickMintingBatch first the ticker that forces the planter to create a new
@@ -217,7 +281,7 @@ func (t *mintingTestHarness) assertNoPendingBatch() {
 func (t *mintingTestHarness) tickMintingBatch(noBatch bool) *btcec.PublicKey {
 	t.Helper()
 
-	batchKey, err := t.planter.FinalizeBatch()
+	batchKey, err := t.planter.FinalizeBatch(nil, nil, nil, nil)
 	if noBatch {
 		require.ErrorContains(t, err, "no pending batch")
 		require.Nil(t, batchKey)
@@ -232,7 +296,7 @@ func (t *mintingTestHarness) tickMintingBatch(noBatch bool) *btcec.PublicKey {
 func (t *mintingTestHarness) cancelMintingBatch(noBatch bool) *btcec.PublicKey {
 	t.Helper()
 
-	batchKey, err := t.planter.CancelBatch()
+	batchKey, err := t.planter.CancelBatch(nil)
 	if noBatch {
 		require.ErrorContains(t, err, "no pending batch")
 		require.Nil(t, batchKey)
@@ -269,20 +333,29 @@ func (t *mintingTestHarness) assertNumCaretakersActive(n int) {
 }
 
 // assertGenesisTxFunded asserts that a caretaker attempted to fund a new
-// genesis transaction.
-func (t *mintingTestHarness) assertGenesisTxFunded() *tapgarden.FundedPsbt {
+// genesis transaction. If a manual fee rate is given, the transaction must be
+// funded at that rate without estimating a fee.
+func (t *mintingTestHarness) assertGenesisTxFunded(
+	manualFeeRate *chainfee.SatPerKWeight) *tapgarden.FundedPsbt {
+
 	// In order to fund a transaction, we expect a call to estimate the
 	// fee, followed by a request to fund a new PSBT packet.
-	_, err := chanutils.RecvOrTimeout(
-		t.chain.FeeEstimateSignal, defaultTimeout,
-	)
-	require.NoError(t, err)
+	if manualFeeRate == nil {
+		_, err := chanutils.RecvOrTimeout(
+			t.chain.FeeEstimateSignal, defaultTimeout,
+		)
+		require.NoError(t, err)
+	}
 
 	pkt, err := chanutils.RecvOrTimeout(
 		t.wallet.FundPsbtSignal, defaultTimeout,
 	)
 	require.NoError(t, err)
 
+	if manualFeeRate != nil {
+		require.Equal(t, *manualFeeRate, t.wallet.FundPsbtFeeRate)
+	}
+
 	// Finally, we'll assert that the dummy output or a valid P2TR output
 	// is found in the packet.
 	var found bool
@@ -515,6 +588,43 @@ func (t *mintingTestHarness) assertNoError() {
 	}
 }
 
+// assertGenesisPsbtStored asserts that the funded genesis packet of the single
+// non-final batch is eventually written to disk.
+func (t *mintingTestHarness) assertGenesisPsbtStored() {
+	t.Helper()
+
+	err := wait.Predicate(func() bool {
+		pendingBatches, err := t.store.FetchNonFinalBatches(
+			context.Background(),
+		)
+		require.NoError(t, err)
+		require.Len(t, pendingBatches, 1)
+
+		return pendingBatches[0].GenesisPacket != nil
+	}, defaultTimeout)
+	require.NoError(t, err)
+}
+
+// confirmGenesisTx makes a block that includes the given genesis transaction,
+// and delivers the confirmation of the transaction to the caretaker.
+func (t *mintingTestHarness) confirmGenesisTx(tx *wire.MsgTx) {
+	t.Helper()
+
+	merkleTree := blockchain.BuildMerkleTreeStore(
+		[]*btcutil.Tx{btcutil.NewTx(tx)}, false,
+	)
+	blockHeader := wire.NewBlockHeader(
+		0, chaincfg.MainNetParams.GenesisHash,
+		merkleTree[len(merkleTree)-1], 0, 0,
+	)
+	block := &wire.MsgBlock{
+		Header:       *blockHeader,
+		Transactions: []*wire.MsgTx{tx},
+	}
+	sendConfNtfn := t.assertConfReqSent(tx, block)
+	sendConfNtfn()
+}
+
 // testBasicAssetCreation tests that we're able to properly progress the state
 // machine through the various stages of asset minting and creation.
 //
@@ -549,14 +659,14 @@ func testBasicAssetCreation(t *mintingTestHarness) {
 	// Now that the planter is back up, a single caretaker should have been
 	// launched as well. Next, assert that the caretaker has requested a
 	// genesis tx to be funded.
-	_ = t.assertGenesisTxFunded()
+	_ = t.assertGenesisTxFunded(nil)
 	t.assertNumCaretakersActive(1)
 
 	// We'll now force yet another restart to ensure correctness of the
-	// state machine, we expect the PSBT packet to be funded again as well,
-	// since we didn't get a chance to write it to disk.
+	// state machine. The funded PSBT packet is written to disk before any
+	// keys are derived, so the caretaker shouldn't fund it a second time.
+	t.assertGenesisPsbtStored()
 	t.refreshChainPlanter()
-	_ = t.assertGenesisTxFunded()
 
 	// For each seedling created above, we expect a new set of keys to be
 	// created for the asset script key and an additional key if emission
@@ -666,7 +776,7 @@ func testMintingTicker(t *mintingTestHarness) {
 
 	// A single caretaker should have been launched as well. Next, assert
 	// that the caretaker has requested a genesis tx to be funded.
-	_ = t.assertGenesisTxFunded()
+	_ = t.assertGenesisTxFunded(nil)
 	t.assertNumCaretakersActive(1)
 
 	// For each seedling created above, we expect a new set of keys to be
@@ -768,7 +878,7 @@ func testMintingCancelFinalize(t *mintingTestHarness) {
 
 	// If we attempt to queue a seedling with the same name as a pending
 	// seedling, the planter should reject it.
-	updates, err := t.planter.QueueNewSeedling(firstSeedling)
+	updates, err := t.planter.QueueNewSeedling(firstSeedling, nil)
 	require.NoError(t, err)
 	planterErr := <-updates
 	require.NotNil(t, planterErr.Error)
@@ -779,7 +889,7 @@ func testMintingCancelFinalize(t *mintingTestHarness) {
 
 	// A single caretaker should have been launched as well. Next, assert
 	// that the caretaker has requested a genesis tx to be funded.
-	_ = t.assertGenesisTxFunded()
+	_ = t.assertGenesisTxFunded(nil)
 	t.assertNumCaretakersActive(1)
 
 	// For each seedling created above, we expect a new set of keys to be
@@ -810,7 +920,7 @@ func testMintingCancelFinalize(t *mintingTestHarness) {
 	thirdBatchKey := t.tickMintingBatch(false)
 	require.NotNil(t, thirdBatchKey)
 
-	_ = t.assertGenesisTxFunded()
+	_ = t.assertGenesisTxFunded(nil)
 	t.assertNumCaretakersActive(1)
 
 	for i := 0; i < numSeedlings; i++ {
@@ -882,6 +992,688 @@ func testMintingCancelFinalize(t *mintingTestHarness) {
 	t.assertNumCaretakersActive(0)
 }
 
+// testFinalizeWithFeeRate tests that a batch finalized with a manual fee rate
+// is funded at that rate, and that fee rates below the floor are rejected.
+func testFinalizeWithFeeRate(t *mintingTestHarness) {
+	// First, create a new chain planter instance using the supplied test
+	// harness.
+	t.refreshChainPlanter()
+
+	// Next make 5 new random seedlings, and queue each of them up within
+	// the main state machine for batched minting.
+	const numSeedlings = 5
+	seedlings := t.newRandSeedlings(numSeedlings)
+	t.queueSeedlingsInBatch(seedlings...)
+	t.assertPendingBatchExists(numSeedlings)
+
+	// A fee rate below the floor is rejected, leaving the batch pending.
+	lowFeeRate := chainfee.FeePerKwFloor - 1
+	batchKey, err := t.planter.FinalizeBatch(nil, &lowFeeRate, nil, nil)
+	require.ErrorContains(t, err, "below floor")
+	require.Nil(t, batchKey)
+	t.assertPendingBatchExists(numSeedlings)
+
+	// With a valid fee rate, the caretaker funds the genesis transaction
+	// at that rate instead of asking for a fee estimate.
+	feeRate := chainfee.SatPerKVByte(20_000).FeePerKWeight()
+	batchKey, err = t.planter.FinalizeBatch(nil, &feeRate, nil, nil)
+	require.NoError(t, err)
+	require.NotNil(t, batchKey)
+
+	_ = t.assertGenesisTxFunded(&feeRate)
+	t.assertNumCaretakersActive(1)
+	t.assertNoPendingBatch()
+}
+
+// testFinalizeWithCoinControl tests that the genesis transaction of a batch
+// that is finalized with coin control only spends the allowed wallet UTXOs,
+// and that invalid coin control is rejected.
+func testFinalizeWithCoinControl(t *mintingTestHarness) {
+	t.refreshChainPlanter()
+
+	const numSeedlings = 3
+	seedlings := t.newRandSeedlings(numSeedlings)
+	t.queueSeedlingsInBatch(seedlings...)
+	t.assertPendingBatchExists(numSeedlings)
+
+	outpoints := []wire.OutPoint{
+		{Hash: test.RandHash(), Index: 1},
+		{Hash: test.RandHash(), Index: 3},
+	}
+
+	// Listing the same outpoint twice is rejected, leaving the batch
+	// pending.
+	_, err := t.planter.FinalizeBatch(nil, nil, nil, &tapgarden.CoinControl{
+		Outpoints: []wire.OutPoint{outpoints[0], outpoints[0]},
+	})
+	require.ErrorContains(t, err, "duplicate outpoint")
+	t.assertPendingBatchExists(numSeedlings)
+
+	coinControl := &tapgarden.CoinControl{
+		Outpoints: outpoints,
+		Account:   "minting",
+	}
+	batchKey, err := t.planter.FinalizeBatch(nil, nil, nil, coinControl)
+	require.NoError(t, err)
+	require.NotNil(t, batchKey)
+
+	// The coin control is handed to the wallet, and the funded packet
+	// only spends the allowed outpoints.
+	genesisPkt := t.assertGenesisTxFunded(nil)
+	require.Equal(t, coinControl, t.wallet.FundPsbtCoinControl)
+
+	txIns := genesisPkt.Pkt.UnsignedTx.TxIn
+	require.Len(t, txIns, len(outpoints))
+	for i, txIn := range txIns {
+		require.Equal(t, outpoints[i], txIn.PreviousOutPoint)
+	}
+
+	t.assertNumCaretakersActive(1)
+	t.assertNoPendingBatch()
+}
+
+// testMultiplePendingBatches tests that seedlings can be staged in multiple
+// pending batches that are finalized and cancelled independently.
+func testMultiplePendingBatches(t *mintingTestHarness) {
+	// First, create a new chain planter instance using the supplied test
+	// harness.
+	t.refreshChainPlanter()
+
+	// We'll start by queueing a few seedlings in the default batch.
+	defaultSeedlings := t.newRandSeedlings(2)
+	t.queueSeedlingsInBatch(defaultSeedlings...)
+	defaultBatchKey := t.batchKey.PubKey
+
+	// Next, we'll create a second, named batch and stage a different set
+	// of seedlings in it.
+	namedBatchKey := t.newPendingBatch()
+	namedSeedlings := t.newRandSeedlings(3)
+	for _, seedling := range namedSeedlings {
+		updates, err := t.planter.QueueNewSeedling(
+			seedling, namedBatchKey,
+		)
+		require.NoError(t, err)
+
+		update, err := chanutils.RecvOrTimeout(updates, defaultTimeout)
+		require.NoError(t, err)
+		require.NoError(t, update.Error)
+		require.True(t, update.BatchKey.IsEqual(namedBatchKey))
+	}
+
+	// Both batches should be pending, each with its own seedlings.
+	batches, err := t.planter.PendingBatches()
+	require.NoError(t, err)
+	require.Len(t, batches, 2)
+	require.True(t, batches[0].BatchKey.PubKey.IsEqual(defaultBatchKey))
+	require.Len(t, batches[0].Seedlings, len(defaultSeedlings))
+	require.True(t, batches[1].BatchKey.PubKey.IsEqual(namedBatchKey))
+	require.Len(t, batches[1].Seedlings, len(namedSeedlings))
+
+	// A seedling for a batch that doesn't exist is rejected.
+	unknownKey := test.RandPubKey(t)
+	updates, err := t.planter.QueueNewSeedling(
+		t.newRandSeedlings(1)[0], unknownKey,
+	)
+	require.NoError(t, err)
+	update, err := chanutils.RecvOrTimeout(updates, defaultTimeout)
+	require.NoError(t, err)
+	require.ErrorContains(t, update.Error, "no pending batch with key")
+
+	// Without a batch key, the batch to cancel is ambiguous.
+	_, err = t.planter.CancelBatch(nil)
+	require.ErrorContains(t, err, "multiple batches")
+
+	// An empty batch can't be finalized, but can be cancelled.
+	emptyBatchKey := t.newPendingBatch()
+	_, err = t.planter.FinalizeBatch(emptyBatchKey, nil, nil, nil)
+	require.ErrorContains(t, err, "no seedlings")
+	cancelKey, err := t.planter.CancelBatch(emptyBatchKey)
+	require.NoError(t, err)
+	require.True(t, cancelKey.IsEqual(emptyBatchKey))
+
+	// Finalizing the named batch launches a caretaker for it, while the
+	// default batch stays pending.
+	batchKey, err := t.planter.FinalizeBatch(namedBatchKey, nil, nil, nil)
+	require.NoError(t, err)
+	require.True(t, batchKey.IsEqual(namedBatchKey))
+
+	_ = t.assertGenesisTxFunded(nil)
+	t.assertNumCaretakersActive(1)
+	t.assertPendingBatchExists(len(defaultSeedlings))
+
+	// Finally, we'll cancel the default batch by its key.
+	cancelKey, err = t.planter.CancelBatch(defaultBatchKey)
+	require.NoError(t, err)
+	require.True(t, cancelKey.IsEqual(defaultBatchKey))
+	t.assertNoPendingBatch()
+	t.assertBatchState(
+		defaultBatchKey, tapgarden.BatchStateSeedlingCancelled,
+	)
+}
+
+// testAutoFinalize tests that the default pending batch is finalized once it
+// reaches the configured number of seedlings or blocks.
+func testAutoFinalize(t *mintingTestHarness) {
+	// First, create a new chain planter instance that finalizes batches
+	// after three seedlings or six blocks.
+	const maxSeedlings = 3
+	t.autoFinalize = tapgarden.AutoFinalizeConfig{
+		MaxSeedlings:   maxSeedlings,
+		MaxBatchBlocks: 6,
+	}
+	t.refreshChainPlanter()
+
+	// Once the batch is full, it's finalized without a tick.
+	seedlings := t.newRandSeedlings(maxSeedlings)
+	t.queueSeedlingsInBatch(seedlings...)
+
+	_ = t.assertGenesisTxFunded(nil)
+	t.assertNumCaretakersActive(1)
+	t.assertNoPendingBatch()
+
+	for i := 0; i < maxSeedlings; i++ {
+		t.assertKeyDerived()
+
+		if seedlings[i].EnableEmission {
+			t.assertKeyDerived()
+		}
+	}
+
+	// A new batch stays pending until enough blocks were mined since its
+	// creation at height zero.
+	t.queueSeedlingsInBatch(t.newRandSeedlings(1)...)
+	t.sendBlockEpoch(5)
+	t.assertPendingBatchExists(1)
+
+	t.sendBlockEpoch(6)
+	_ = t.assertGenesisTxFunded(nil)
+	t.assertNumCaretakersActive(2)
+	t.assertNoPendingBatch()
+}
+
+// testCancelSeedling tests that a single seedling can be removed from a pending
+// batch, and that the batch is cancelled once its last seedling is removed.
+func testCancelSeedling(t *mintingTestHarness) {
+	// First, create a new chain planter instance using the supplied test
+	// harness.
+	t.refreshChainPlanter()
+
+	// We'll queue a few seedlings in the default batch.
+	const numSeedlings = 3
+	seedlings := t.newRandSeedlings(numSeedlings)
+	t.queueSeedlingsInBatch(seedlings...)
+	t.assertPendingBatchExists(numSeedlings)
+	batchKey := t.batchKey.PubKey
+
+	// Cancelling a seedling that isn't part of the batch fails.
+	err := t.planter.CancelSeedling("unknown", nil)
+	require.ErrorContains(t, err, "not in batch")
+	t.assertPendingBatchExists(numSeedlings)
+
+	// Cancelling one of the seedlings removes it from the pending batch,
+	// both in memory and on disk.
+	require.NoError(t, t.planter.CancelSeedling(
+		seedlings[0].AssetName, batchKey,
+	))
+	t.assertPendingBatchExists(numSeedlings - 1)
+	t.assertSeedlingsExist(seedlings[1:], batchKey)
+
+	// Once the last seedling is removed, the whole batch is cancelled.
+	require.NoError(t, t.planter.CancelSeedling(
+		seedlings[1].AssetName, nil,
+	))
+	require.NoError(t, t.planter.CancelSeedling(
+		seedlings[2].AssetName, nil,
+	))
+	t.assertNoPendingBatch()
+	t.assertBatchState(batchKey, tapgarden.BatchStateSeedlingCancelled)
+
+	// Without a pending batch, there's nothing left to cancel.
+	err = t.planter.CancelSeedling(seedlings[2].AssetName, nil)
+	require.ErrorContains(t, err, "no pending batch")
+}
+
+// testFundBatchExternalPsbt tests that the genesis output of a batch can be
+// committed into an externally funded PSBT, which is only broadcast once the
+// externally signed packet is handed back.
+func testFundBatchExternalPsbt(t *mintingTestHarness) {
+	// First, create a new chain planter instance using the supplied test
+	// harness.
+	t.refreshChainPlanter()
+
+	const numSeedlings = 3
+	seedlings := t.newRandSeedlings(numSeedlings)
+	t.queueSeedlingsInBatch(seedlings...)
+	batchKey := t.batchKey.PubKey
+
+	// We'll fund the batch with a packet that spends a single external
+	// input, and sends the change back to the external wallet.
+	fundedTx := wire.NewMsgTx(2)
+	fundedTx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{
+			Index: rand.Uint32(),
+		},
+	})
+	fundedTx.AddTxOut(&wire.TxOut{
+		Value:    50_000,
+		PkScript: []byte{0x2},
+	})
+	fundedPsbt, err := psbt.NewFromUnsignedTx(fundedTx)
+	require.NoError(t, err)
+	fundedPsbt.Inputs[0].WitnessUtxo = &wire.TxOut{
+		Value:    100_000,
+		PkScript: []byte{0x1},
+	}
+
+	// The caretaker derives the script keys of the new assets before it
+	// hands us the genesis packet.
+	type result struct {
+		pkt *psbt.Packet
+		err error
+	}
+	results := make(chan result, 1)
+	go func() {
+		pkt, err := t.planter.FundBatch(nil, fundedPsbt)
+		results <- result{pkt, err}
+	}()
+	for i := 0; i < numSeedlings; i++ {
+		t.assertKeyDerived()
+
+		if seedlings[i].EnableEmission {
+			t.assertKeyDerived()
+		}
+	}
+
+	res, err := chanutils.RecvOrTimeout(results, defaultTimeout)
+	require.NoError(t, err)
+	require.NoError(t, res.err)
+	genesisPsbt := res.pkt
+
+	// The genesis output was appended to a copy of the funded packet, and
+	// the batch now waits for the signed packet.
+	require.Len(t, fundedPsbt.UnsignedTx.TxOut, 1)
+	require.Len(t, genesisPsbt.UnsignedTx.TxOut, 2)
+	genesisOut := genesisPsbt.UnsignedTx.TxOut[1]
+	require.EqualValues(t, tapgarden.GenesisAmtSats, genesisOut.Value)
+	require.True(t, txscript.IsPayToTaproot(genesisOut.PkScript))
+
+	t.assertNoPendingBatch()
+	t.assertNumCaretakersActive(1)
+	t.assertBatchState(batchKey, tapgarden.BatchStateAwaitingExternalSig)
+
+	// A packet for a different transaction is rejected, as is a packet
+	// that wasn't signed.
+	err = t.planter.SubmitSignedBatchPsbt(batchKey, fundedPsbt)
+	require.ErrorContains(t, err, "doesn't match genesis tx")
+	err = t.planter.SubmitSignedBatchPsbt(batchKey, genesisPsbt)
+	require.ErrorContains(t, err, "unable to finalize")
+
+	// Once we hand back the signed packet, the minting output is imported
+	// into the wallet, and the genesis transaction is broadcast.
+	genesisPsbt.Inputs[0].FinalScriptSig = []byte{}
+	submitErrs := make(chan error, 1)
+	go func() {
+		submitErrs <- t.planter.SubmitSignedBatchPsbt(
+			batchKey, genesisPsbt,
+		)
+	}()
+
+	_, err = chanutils.RecvOrTimeout(
+		t.wallet.ImportPubKeySignal, defaultTimeout,
+	)
+	require.NoError(t, err)
+
+	submitErr, err := chanutils.RecvOrTimeout(submitErrs, defaultTimeout)
+	require.NoError(t, err)
+	require.NoError(t, *submitErr)
+
+	tx := t.assertTxPublished()
+	require.Equal(t, genesisPsbt.UnsignedTx.TxHash(), tx.TxHash())
+	_ = t.assertConfReqSent(tx, nil)
+	t.assertBatchState(batchKey, tapgarden.BatchStateBroadcast)
+}
+
+// testExternalBatchKeys tests that a batch can be created with a batch key
+// supplied by the caller, either as a key locator or as an external key.
+func testExternalBatchKeys(t *mintingTestHarness) {
+	// First, create a new chain planter instance that doesn't allow
+	// external batch keys.
+	t.refreshChainPlanter()
+
+	_, err := t.planter.NewBatch(&keychain.KeyDescriptor{
+		PubKey: test.RandPubKey(t),
+	})
+	require.ErrorContains(t, err, "external batch keys not allowed")
+
+	// A key locator is derived by the wallet, so it's always allowed.
+	keyLoc := keychain.KeyLocator{
+		Family: asset.TaprootAssetsKeyFamily,
+		Index:  42,
+	}
+	batchKey, err := t.planter.NewBatch(&keychain.KeyDescriptor{
+		KeyLocator: keyLoc,
+	})
+	require.NoError(t, err)
+	require.True(t, batchKey.IsEqual(t.keyRing.Keys[keyLoc].PubKey()))
+
+	// The same key can't be used for two batches.
+	_, err = t.planter.NewBatch(&keychain.KeyDescriptor{
+		KeyLocator: keyLoc,
+	})
+	require.ErrorContains(t, err, "already in use")
+
+	// Once external batch keys are allowed, a batch can be created with
+	// any key.
+	t.allowExternalBatchKeys = true
+	t.refreshChainPlanter()
+
+	externalKey := test.RandPubKey(t)
+	batchKey, err = t.planter.NewBatch(&keychain.KeyDescriptor{
+		PubKey: externalKey,
+	})
+	require.NoError(t, err)
+	require.True(t, batchKey.IsEqual(externalKey))
+
+	seedlings := t.newRandSeedlings(1)
+	updates, err := t.planter.QueueNewSeedling(seedlings[0], externalKey)
+	require.NoError(t, err)
+	update, err := chanutils.RecvOrTimeout(updates, defaultTimeout)
+	require.NoError(t, err)
+	require.NoError(t, update.Error)
+	t.assertSeedlingsExist(seedlings, externalKey)
+
+	// The key is still in use once the batch was written to disk.
+	_, err = t.planter.NewBatch(&keychain.KeyDescriptor{
+		PubKey: externalKey,
+	})
+	require.ErrorContains(t, err, "already in use")
+}
+
+// testDecimalDisplay tests that the decimal display of a seedling is committed
+// to in its JSON meta data, and that invalid decimal displays are rejected.
+func testDecimalDisplay(t *mintingTestHarness) {
+	// First, create a new chain planter instance using the supplied test
+	// harness.
+	t.refreshChainPlanter()
+
+	// queueInvalidSeedling asserts that the planter rejects the seedling.
+	queueInvalidSeedling := func(seedling *tapgarden.Seedling,
+		errContains string) {
+
+		updates, err := t.planter.QueueNewSeedling(seedling, nil)
+		require.NoError(t, err)
+		update, err := chanutils.RecvOrTimeout(updates, defaultTimeout)
+		require.NoError(t, err)
+		require.ErrorContains(t, update.Error, errContains)
+	}
+
+	// Collectibles can't be displayed with decimal places.
+	seedling := t.newRandSeedlings(1)[0]
+	seedling.AssetType = asset.Collectible
+	seedling.Amount = 1
+	seedling.DecimalDisplay = 2
+	queueInvalidSeedling(
+		seedling, tapgarden.ErrInvalidDecimalDisplay.Error(),
+	)
+
+	// The decimal display can't be added to opaque meta data.
+	seedling = t.newRandSeedlings(1)[0]
+	seedling.AssetType = asset.Normal
+	seedling.Amount = 1000
+	seedling.Meta.Type = proof.MetaOpaque
+	seedling.DecimalDisplay = 2
+	queueInvalidSeedling(seedling, proof.ErrDecimalDisplayNotJSON.Error())
+
+	// A normal asset with JSON meta data has its decimal display added to
+	// the meta data, while the rest of the meta data is kept.
+	withMeta := t.newRandSeedlings(1)[0]
+	withMeta.AssetType = asset.Normal
+	withMeta.Amount = 1000
+	withMeta.Meta = &proof.MetaReveal{
+		Type: proof.MetaJSON,
+		Data: []byte(`{"name": "foo"}`),
+	}
+	withMeta.DecimalDisplay = 2
+
+	// A normal asset without meta data gets JSON meta data that only
+	// carries the decimal display.
+	withoutMeta := t.newRandSeedlings(1)[0]
+	withoutMeta.AssetType = asset.Normal
+	withoutMeta.Amount = 1000
+	withoutMeta.Meta = nil
+	withoutMeta.DecimalDisplay = 6
+
+	t.queueSeedlingsInBatch(withMeta, withoutMeta)
+	t.assertPendingBatchExists(2)
+	t.assertSeedlingsExist(
+		[]*tapgarden.Seedling{withMeta, withoutMeta}, nil,
+	)
+
+	require.JSONEq(
+		t, `{"name": "foo", "decimal_display": 2}`,
+		string(withMeta.Meta.Data),
+	)
+	require.Equal(t, proof.MetaJSON, withoutMeta.Meta.Type)
+	require.JSONEq(
+		t, `{"decimal_display": 6}`, string(withoutMeta.Meta.Data),
+	)
+}
+
+// committedSprout waits until the batch is committed and returns the asset
+// that was created for the given seedling.
+func (t *mintingTestHarness) committedSprout(batchKey *btcec.PublicKey,
+	seedling *tapgarden.Seedling) *asset.Asset {
+
+	t.Helper()
+
+	var batch *tapgarden.MintingBatch
+	err := wait.Predicate(func() bool {
+		var err error
+		batch, err = t.store.FetchMintingBatch(
+			context.Background(), batchKey,
+		)
+		require.NoError(t, err)
+
+		return batch.BatchState == tapgarden.BatchStateCommitted
+	}, defaultTimeout)
+	require.NoError(t, err)
+
+	sprouts := batch.RootAssetCommitment.CommittedAssets()
+	for _, sprout := range sprouts {
+		if sprout.Genesis.Tag == seedling.AssetName {
+			return sprout
+		}
+	}
+
+	t.Fatalf("asset for seedling %v not found", seedling.AssetName)
+	return nil
+}
+
+// testMintIntoExistingGroup tests that a new tranche can be issued into an
+// asset group that was created by an earlier batch, by picking the group with
+// the key locator of its raw key.
+func testMintIntoExistingGroup(t *mintingTestHarness) {
+	// First, create a new chain planter instance using the supplied test
+	// harness.
+	t.refreshChainPlanter()
+
+	// We'll start by minting a new asset group in its own batch.
+	groupAnchor := t.newRandSeedlings(1)[0]
+	groupAnchor.AssetType = asset.Normal
+	groupAnchor.Amount = 1000
+	groupAnchor.EnableEmission = true
+	t.queueSeedlingsInBatch(groupAnchor)
+
+	anchorBatchKey := t.tickMintingBatch(false)
+	_ = t.assertGenesisTxFunded(nil)
+	t.assertKeyDerived()
+	t.assertKeyDerived()
+
+	anchorSprout := t.committedSprout(anchorBatchKey, groupAnchor)
+	require.NotNil(t, anchorSprout.GroupKey)
+	groupKeyLoc := anchorSprout.GroupKey.RawKey.KeyLocator
+
+	// A group key locator can't be combined with another way of picking
+	// the group of the asset, and must match an existing group.
+	invalidSeedling := t.newRandSeedlings(1)[0]
+	invalidSeedling.GroupKeyLocator = &groupKeyLoc
+	invalidSeedling.EnableEmission = true
+	updates, err := t.planter.QueueNewSeedling(invalidSeedling, nil)
+	require.NoError(t, err)
+	update, err := chanutils.RecvOrTimeout(updates, defaultTimeout)
+	require.NoError(t, err)
+	require.ErrorIs(t, update.Error, tapgarden.ErrInvalidGroupKeyLocator)
+
+	unknownKeyLoc := keychain.KeyLocator{
+		Family: asset.TaprootAssetsKeyFamily,
+		Index:  groupKeyLoc.Index + 1000,
+	}
+	invalidSeedling.GroupKeyLocator = &unknownKeyLoc
+	invalidSeedling.EnableEmission = false
+	updates, err = t.planter.QueueNewSeedling(invalidSeedling, nil)
+	require.NoError(t, err)
+	update, err = chanutils.RecvOrTimeout(updates, defaultTimeout)
+	require.NoError(t, err)
+	require.ErrorContains(t, update.Error, "not found")
+
+	// A seedling with the locator of the group's raw key is resolved into
+	// the existing group when it's queued.
+	tranche := t.newRandSeedlings(1)[0]
+	tranche.AssetType = asset.Normal
+	tranche.Amount = 500
+	tranche.EnableEmission = false
+	tranche.GroupKeyLocator = &groupKeyLoc
+	t.queueSeedlingsInBatch(tranche)
+
+	require.Nil(t, tranche.GroupKeyLocator)
+	require.True(t, tranche.HasGroupKey())
+	require.True(t, tranche.GroupInfo.GroupPubKey.IsEqual(
+		&anchorSprout.GroupKey.GroupPubKey,
+	))
+
+	// Once the batch with the new tranche is committed, the new asset is
+	// a member of the existing group.
+	trancheBatchKey := t.tickMintingBatch(false)
+	_ = t.assertGenesisTxFunded(nil)
+	t.assertKeyDerived()
+
+	trancheSprout := t.committedSprout(trancheBatchKey, tranche)
+	require.NotNil(t, trancheSprout.GroupKey)
+	require.True(t, trancheSprout.GroupKey.IsEqualGroup(
+		anchorSprout.GroupKey,
+	))
+	require.True(t, trancheSprout.Genesis.VerifyGroupWitness(
+		trancheSprout.GroupKey,
+	))
+}
+
+// testMintTrancheWithScriptSpend tests that a new tranche can be issued into a
+// group that commits to a tapscript tree by satisfying a multisig leaf of the
+// tree instead of signing with the group's internal key.
+func testMintTrancheWithScriptSpend(t *mintingTestHarness) {
+	// First, create a new chain planter instance using the supplied test
+	// harness.
+	t.refreshChainPlanter()
+
+	// The issuers of the group are three keys of the node's key ring,
+	// which can be derived without being announced on the key channel.
+	ctx := context.Background()
+	issuers := make([]*keychain.KeyDescriptor, 3)
+	for idx := range issuers {
+		issuer, err := t.keyRing.DeriveKey(ctx, keychain.KeyLocator{
+			Family: asset.TaprootAssetsKeyFamily,
+			Index:  uint32(10_000 + idx),
+		})
+		require.NoError(t, err)
+
+		issuers[idx] = &issuer
+	}
+
+	multiSigScript, err := txscript.NewScriptBuilder().
+		AddData(schnorr.SerializePubKey(issuers[0].PubKey)).
+		AddOp(txscript.OP_CHECKSIG).
+		AddData(schnorr.SerializePubKey(issuers[1].PubKey)).
+		AddOp(txscript.OP_CHECKSIGADD).
+		AddData(schnorr.SerializePubKey(issuers[2].PubKey)).
+		AddOp(txscript.OP_CHECKSIGADD).
+		AddInt64(2).
+		AddOp(txscript.OP_NUMEQUAL).
+		Script()
+	require.NoError(t, err)
+
+	multiSigLeaf := txscript.NewBaseTapLeaf(multiSigScript)
+	otherLeaf := txscript.NewBaseTapLeaf([]byte{txscript.OP_RETURN})
+	tree := txscript.AssembleTaprootScriptTree(multiSigLeaf, otherLeaf)
+	rootHash := tree.RootNode.TapHash()
+
+	// We'll start by minting the group in its own batch, with the group
+	// committing to the tapscript tree.
+	groupAnchor := t.newRandSeedlings(1)[0]
+	groupAnchor.AssetType = asset.Normal
+	groupAnchor.Amount = 1000
+	groupAnchor.EnableEmission = true
+	groupAnchor.GroupTapscriptRoot = rootHash[:]
+	t.queueSeedlingsInBatch(groupAnchor)
+
+	anchorBatchKey := t.tickMintingBatch(false)
+	_ = t.assertGenesisTxFunded(nil)
+	t.assertKeyDerived()
+	t.assertKeyDerived()
+
+	anchorSprout := t.committedSprout(anchorBatchKey, groupAnchor)
+	require.NotNil(t, anchorSprout.GroupKey)
+	groupKeyLoc := anchorSprout.GroupKey.RawKey.KeyLocator
+
+	// A script spend requires a group that commits to a tapscript tree.
+	scriptSpend := &asset.GroupScriptSpend{
+		Leaf:           multiSigLeaf,
+		InclusionProof: tree.LeafMerkleProofs[0].InclusionProof,
+		Signers: []*keychain.KeyDescriptor{
+			issuers[2], nil, issuers[0],
+		},
+	}
+	invalidSeedling := t.newRandSeedlings(1)[0]
+	invalidSeedling.EnableEmission = true
+	invalidSeedling.GroupScriptSpend = scriptSpend
+	updates, err := t.planter.QueueNewSeedling(invalidSeedling, nil)
+	require.NoError(t, err)
+	update, err := chanutils.RecvOrTimeout(updates, defaultTimeout)
+	require.NoError(t, err)
+	require.ErrorIs(
+		t, update.Error, tapgarden.ErrInvalidGroupScriptSpend,
+	)
+
+	// The new tranche is authorized by two of the three issuers through
+	// the multisig leaf.
+	tranche := t.newRandSeedlings(1)[0]
+	tranche.AssetType = asset.Normal
+	tranche.Amount = 500
+	tranche.EnableEmission = false
+	tranche.GroupKeyLocator = &groupKeyLoc
+	tranche.GroupScriptSpend = scriptSpend
+	t.queueSeedlingsInBatch(tranche)
+
+	trancheBatchKey := t.tickMintingBatch(false)
+	_ = t.assertGenesisTxFunded(nil)
+	t.assertKeyDerived()
+
+	trancheSprout := t.committedSprout(trancheBatchKey, tranche)
+	require.NotNil(t, trancheSprout.GroupKey)
+	require.True(t, trancheSprout.GroupKey.IsEqualGroup(
+		anchorSprout.GroupKey,
+	))
+
+	// The witness is a script path spend: one element per signer slot,
+	// followed by the script and the control block.
+	require.Len(t, trancheSprout.GroupKey.Witness, 5)
+	require.Equal(
+		t, multiSigScript, []byte(trancheSprout.GroupKey.Witness[3]),
+	)
+	require.True(t, trancheSprout.Genesis.VerifyGroupWitness(
+		trancheSprout.GroupKey,
+	))
+}
+
 // mintingStoreTestCase is used to programmatically run a series of test cases
 // that are parametrized based on a fresh minting store.
 type mintingStoreTestCase struct {
@@ -891,6 +1683,352 @@ type mintingStoreTestCase struct {
 }
 
 // testCases houses the set of minting store test cases.
+// testPreviewBatch tests that a pending batch can be previewed without being
+// finalized, and that the preview describes the assets and the anchor output
+// of the batch.
+func testPreviewBatch(t *mintingTestHarness) {
+	// First, create a new chain planter instance using the supplied test
+	// harness.
+	t.refreshChainPlanter()
+
+	// We'll use seedlings without groups, so we know exactly how many keys
+	// the preview derives.
+	const numSeedlings = 3
+	seedlings := t.newRandSeedlings(numSeedlings)
+	seedlingNames := make(map[string]struct{}, numSeedlings)
+	for _, seedling := range seedlings {
+		seedling.EnableEmission = false
+		seedling.GroupTapscriptRoot = nil
+		seedlingNames[seedling.AssetName] = struct{}{}
+	}
+	t.queueSeedlingsInBatch(seedlings...)
+	t.assertPendingBatchExists(numSeedlings)
+
+	// A fee rate below the floor is rejected.
+	lowFeeRate := chainfee.FeePerKwFloor - 1
+	_, err := t.planter.PreviewBatch(nil, &lowFeeRate)
+	require.ErrorContains(t, err, "below floor")
+
+	// The preview funds the genesis transaction and derives a script key
+	// for every seedling, so we'll request it in the background.
+	type previewResult struct {
+		preview *tapgarden.BatchPreview
+		err     error
+	}
+	results := make(chan previewResult, 1)
+	feeRate := chainfee.SatPerKVByte(20_000).FeePerKWeight()
+	go func() {
+		preview, err := t.planter.PreviewBatch(nil, &feeRate)
+		results <- previewResult{preview, err}
+	}()
+
+	_ = t.assertGenesisTxFunded(&feeRate)
+	for i := 0; i < numSeedlings; i++ {
+		_ = t.assertKeyDerived()
+	}
+
+	res, err := chanutils.RecvOrTimeout(results, defaultTimeout)
+	require.NoError(t, err)
+	require.NoError(t, res.err)
+	preview := res.preview
+
+	require.True(t, preview.BatchKey.IsEqual(t.batchKey.PubKey))
+
+	// Every seedling is turned into exactly one asset.
+	require.Len(t, preview.Assets, numSeedlings)
+	for _, assetBytes := range preview.Assets {
+		var newAsset asset.Asset
+		err := newAsset.Decode(bytes.NewReader(assetBytes))
+		require.NoError(t, err)
+		require.Contains(t, seedlingNames, newAsset.Tag)
+	}
+
+	// The anchor output must commit to the assets of the batch, and the
+	// estimated fees must match the genesis packet.
+	genesisTx := preview.GenesisPacket.Pkt.UnsignedTx
+	require.Equal(
+		t, genesisTx.TxOut[preview.AnchorOutputIndex],
+		preview.AnchorOutput,
+	)
+
+	tapscriptRoot := preview.RootAssetCommitment.TapscriptRoot(nil)
+	anchorKey := txscript.ComputeTaprootOutputKey(
+		preview.BatchKey, tapscriptRoot[:],
+	)
+	anchorScript, err := tapscript.PayToTaprootScript(anchorKey)
+	require.NoError(t, err)
+	require.Equal(t, anchorScript, preview.AnchorOutput.PkScript)
+
+	chainFees, err := tapgarden.GetTxFee(preview.GenesisPacket.Pkt)
+	require.NoError(t, err)
+	require.Positive(t, chainFees)
+	require.Equal(t, chainFees, preview.GenesisPacket.ChainFees)
+
+	// The batch must still be pending, and can be finalized as usual.
+	t.assertPendingBatchExists(numSeedlings)
+	t.assertNumCaretakersActive(0)
+
+	batchKey, err := t.planter.FinalizeBatch(nil, &feeRate, nil, nil)
+	require.NoError(t, err)
+	require.True(t, batchKey.IsEqual(preview.BatchKey))
+
+	_ = t.assertGenesisTxFunded(&feeRate)
+	t.assertNumCaretakersActive(1)
+	t.assertNoPendingBatch()
+}
+
+// testMintWithTapSibling tests that a batch can be finalized with a tapscript
+// sibling that is committed to next to the Taproot Asset commitment in the
+// genesis output, and that the batch is confirmed with valid proofs.
+func testMintWithTapSibling(t *mintingTestHarness) {
+	// First, create a new chain planter instance using the supplied test
+	// harness.
+	t.refreshChainPlanter()
+
+	// We'll use seedlings without groups, so we know exactly how many keys
+	// are derived for the batch.
+	const numSeedlings = 3
+	seedlings := t.newRandSeedlings(numSeedlings)
+	for _, seedling := range seedlings {
+		seedling.EnableEmission = false
+		seedling.GroupTapscriptRoot = nil
+	}
+	t.queueSeedlingsInBatch(seedlings...)
+	t.assertPendingBatchExists(numSeedlings)
+
+	// An empty sibling preimage is rejected, leaving the batch pending.
+	_, err := t.planter.FinalizeBatch(
+		nil, nil, &commitment.TapscriptPreimage{}, nil,
+	)
+	require.ErrorContains(t, err, "empty")
+	t.assertPendingBatchExists(numSeedlings)
+
+	// We'll now finalize the batch with a valid sibling leaf.
+	tapSibling := commitment.NewPreimageFromLeaf(
+		txscript.NewBaseTapLeaf([]byte{txscript.OP_TRUE}),
+	)
+	batchKey, err := t.planter.FinalizeBatch(nil, nil, tapSibling, nil)
+	require.NoError(t, err)
+	require.NotNil(t, batchKey)
+
+	_ = t.assertGenesisTxFunded(nil)
+	for i := 0; i < numSeedlings; i++ {
+		_ = t.assertKeyDerived()
+	}
+	t.assertNoPendingBatch()
+	t.assertGenesisPsbtFinalized()
+	tx := t.assertTxPublished()
+
+	// The sibling is stored along with the batch on disk.
+	ctx := context.Background()
+	batch, err := t.store.FetchMintingBatch(ctx, batchKey)
+	require.NoError(t, err)
+	require.Equal(t, tapSibling, batch.TapSibling)
+
+	// The genesis output must commit to both the assets of the batch and
+	// the sibling.
+
+	siblingHash, err := tapSibling.TapHash()
+	require.NoError(t, err)
+	merkleRoot := batch.RootAssetCommitment.TapscriptRoot(siblingHash)
+	outputKey := txscript.ComputeTaprootOutputKey(
+		batchKey, merkleRoot[:],
+	)
+	expectedScript, err := txscript.PayToTaprootScript(outputKey)
+	require.NoError(t, err)
+
+	var foundAnchor bool
+	for _, txOut := range tx.TxOut {
+		if bytes.Equal(txOut.PkScript, expectedScript) {
+			foundAnchor = true
+		}
+	}
+	require.True(t, foundAnchor)
+
+	// Once the transaction confirms, the proofs of the batch are created
+	// and verified, which requires them to carry the sibling preimage.
+	t.confirmGenesisTx(tx)
+
+	t.assertNoError()
+	t.assertNumCaretakersActive(0)
+}
+
+// mockPushRegistrar is a universe registrar that rejects a configurable
+// number of pushes before accepting any.
+type mockPushRegistrar struct {
+	sync.Mutex
+
+	numFailures int
+
+	leaves []*universe.MintingLeaf
+}
+
+func (m *mockPushRegistrar) RegisterIssuance(_ context.Context,
+	_ universe.Identifier, key universe.BaseKey,
+	leaf *universe.MintingLeaf) (*universe.IssuanceProof, error) {
+
+	m.Lock()
+	defer m.Unlock()
+
+	if m.numFailures > 0 {
+		m.numFailures--
+		return nil, fmt.Errorf("universe server unavailable")
+	}
+
+	m.leaves = append(m.leaves, leaf)
+
+	return &universe.IssuanceProof{
+		MintingKey: key,
+		Leaf:       leaf,
+	}, nil
+}
+
+func (m *mockPushRegistrar) numLeaves() int {
+	m.Lock()
+	defer m.Unlock()
+
+	return len(m.leaves)
+}
+
+// testProofPushOnConfirmation tests that the genesis proofs of a confirmed
+// batch are pushed to the configured universe server, and that failed pushes
+// are resumed after a restart.
+func testProofPushOnConfirmation(t *mintingTestHarness) {
+	// We'll start with a universe server that rejects every push.
+	registrar := &mockPushRegistrar{
+		numFailures: math.MaxInt32,
+	}
+	t.proofPush = &tapgarden.ProofPushConfig{
+		Registrar: registrar,
+		BackoffCfg: &proof.BackoffCfg{
+			NumTries:   2,
+			MaxBackoff: time.Second,
+		},
+	}
+	t.refreshChainPlanter()
+
+	// We'll use seedlings without groups, so we know exactly how many keys
+	// are derived for the batch.
+	const numSeedlings = 3
+	seedlings := t.newRandSeedlings(numSeedlings)
+	for _, seedling := range seedlings {
+		seedling.EnableEmission = false
+		seedling.GroupTapscriptRoot = nil
+	}
+	t.queueSeedlingsInBatch(seedlings...)
+	t.assertPendingBatchExists(numSeedlings)
+
+	batchKey, err := t.planter.FinalizeBatch(nil, nil, nil, nil)
+	require.NoError(t, err)
+
+	_ = t.assertGenesisTxFunded(nil)
+	for i := 0; i < numSeedlings; i++ {
+		_ = t.assertKeyDerived()
+	}
+	t.assertGenesisPsbtFinalized()
+	tx := t.assertTxPublished()
+	t.confirmGenesisTx(tx)
+
+	// Both push attempts fail, so the batch stays confirmed and both
+	// attempts are logged.
+	ctx := context.Background()
+	assertPushState := func(numAttempts int, state tapgarden.BatchState) {
+		err := wait.NoError(func() error {
+			attempts, err := t.store.QueryProofPushLog(
+				ctx, batchKey,
+			)
+			if err != nil {
+				return err
+			}
+			if len(attempts) != numAttempts {
+				return fmt.Errorf("expected %d push attempts, "+
+					"got %d", numAttempts, len(attempts))
+			}
+
+			batch, err := t.store.FetchMintingBatch(ctx, batchKey)
+			if err != nil {
+				return err
+			}
+			if batch.BatchState != state {
+				return fmt.Errorf("expected batch state %v, "+
+					"got %v", state, batch.BatchState)
+			}
+
+			return nil
+		}, defaultTimeout)
+		require.NoError(t, err)
+	}
+	assertPushState(2, tapgarden.BatchStateConfirmed)
+	require.Zero(t, registrar.numLeaves())
+
+	// Once the universe server is available again, the push is resumed
+	// after a restart, and the batch is finalized.
+	registrar.Lock()
+	registrar.numFailures = 0
+	registrar.Unlock()
+	t.refreshChainPlanter()
+
+	assertPushState(3, tapgarden.BatchStateFinalized)
+	require.Equal(t, numSeedlings, registrar.numLeaves())
+	t.assertNumCaretakersActive(0)
+}
+
+// testFundingFailure tests that a batch that can't be funded is retried as
+// often as the retry policy allows, and is then marked as failed without
+// affecting any other batches.
+func testFundingFailure(t *mintingTestHarness) {
+	const numAttempts = 3
+	t.retryPolicy = tapgarden.RetryPolicy{
+		MaxAttempts:    numAttempts,
+		InitialBackoff: time.Millisecond * 10,
+		MaxBackoff:     time.Millisecond * 20,
+	}
+	t.refreshChainPlanter()
+
+	// We'll make the wallet fail every attempt to fund the first batch.
+	t.wallet.FundPsbtFailures.Store(numAttempts)
+
+	const numSeedlings = 3
+	seedlings := t.newRandSeedlings(numSeedlings)
+	t.queueSeedlingsInBatch(seedlings...)
+	failedBatchKey := t.tickMintingBatch(false)
+
+	// Every attempt estimates the fee before asking the wallet to fund the
+	// genesis packet.
+	for i := 0; i < numAttempts; i++ {
+		_, err := chanutils.RecvOrTimeout(
+			t.chain.FeeEstimateSignal, defaultTimeout,
+		)
+		require.NoError(t, err)
+	}
+
+	// Once the retry policy is exhausted, the batch is marked as failed,
+	// and its caretaker exits. No error is reported to the main server.
+	err := wait.Predicate(func() bool {
+		batch, err := t.store.FetchMintingBatch(
+			context.Background(), failedBatchKey,
+		)
+		require.NoError(t, err)
+
+		return batch.BatchState == tapgarden.BatchStateFundingFailed
+	}, defaultTimeout)
+	require.NoError(t, err)
+	t.assertNumCaretakersActive(0)
+	t.assertNoError()
+
+	// The failed batch isn't resumed on restart.
+	t.refreshChainPlanter()
+	t.assertNumCaretakersActive(0)
+	t.assertBatchState(failedBatchKey, tapgarden.BatchStateFundingFailed)
+
+	// A new batch can still be funded as usual.
+	seedlings = t.newRandSeedlings(numSeedlings)
+	t.queueSeedlingsInBatch(seedlings...)
+	t.tickMintingBatch(false)
+	_ = t.assertGenesisTxFunded(nil)
+	t.assertNumCaretakersActive(1)
+}
+
 var testCases = []mintingStoreTestCase{
 	{
 		name:     "basic_asset_creation",
@@ -907,6 +2045,76 @@ var testCases = []mintingStoreTestCase{
 		interval: minterInterval,
 		testFunc: testMintingCancelFinalize,
 	},
+	{
+		name:     "finalize_with_fee_rate",
+		interval: defaultInterval,
+		testFunc: testFinalizeWithFeeRate,
+	},
+	{
+		name:     "finalize_with_coin_control",
+		interval: defaultInterval,
+		testFunc: testFinalizeWithCoinControl,
+	},
+	{
+		name:     "multiple_pending_batches",
+		interval: defaultInterval,
+		testFunc: testMultiplePendingBatches,
+	},
+	{
+		name:     "auto_finalize",
+		interval: defaultInterval,
+		testFunc: testAutoFinalize,
+	},
+	{
+		name:     "cancel_seedling",
+		interval: defaultInterval,
+		testFunc: testCancelSeedling,
+	},
+	{
+		name:     "fund_batch_external_psbt",
+		interval: defaultInterval,
+		testFunc: testFundBatchExternalPsbt,
+	},
+	{
+		name:     "external_batch_keys",
+		interval: defaultInterval,
+		testFunc: testExternalBatchKeys,
+	},
+	{
+		name:     "decimal_display",
+		interval: defaultInterval,
+		testFunc: testDecimalDisplay,
+	},
+	{
+		name:     "mint_into_existing_group",
+		interval: defaultInterval,
+		testFunc: testMintIntoExistingGroup,
+	},
+	{
+		name:     "mint_tranche_with_script_spend",
+		interval: defaultInterval,
+		testFunc: testMintTrancheWithScriptSpend,
+	},
+	{
+		name:     "preview_batch",
+		interval: defaultInterval,
+		testFunc: testPreviewBatch,
+	},
+	{
+		name:     "mint_with_tap_sibling",
+		interval: defaultInterval,
+		testFunc: testMintWithTapSibling,
+	},
+	{
+		name:     "proof_push_on_confirmation",
+		interval: defaultInterval,
+		testFunc: testProofPushOnConfirmation,
+	},
+	{
+		name:     "funding_failure",
+		interval: defaultInterval,
+		testFunc: testFundingFailure,
+	},
 }
 
 // TestBatchedAssetIssuance runs a test of tests to ensure that the set of
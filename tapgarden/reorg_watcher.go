@@ -0,0 +1,634 @@
+package tapgarden
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightninglabs/taproot-assets/chanutils"
+	"github.com/lightninglabs/taproot-assets/proof"
+	"github.com/lightningnetwork/lnd/chainntnfs"
+)
+
+const (
+	// DefaultReorgSafetyDepth is the default number of blocks below the
+	// chain tip in which the anchor transactions of proofs are checked
+	// for reorgs.
+	DefaultReorgSafetyDepth = 6
+)
+
+// AnchorTx is an on-chain transaction that anchors assets, along with the
+// block it was confirmed in.
+type AnchorTx struct {
+	// Tx is the anchor transaction itself.
+	Tx *wire.MsgTx
+
+	// BlockHash is the hash of the block the transaction was confirmed
+	// in.
+	BlockHash chainhash.Hash
+
+	// BlockHeight is the height of the block the transaction was
+	// confirmed in.
+	BlockHeight uint32
+
+	// TxIndex is the index of the transaction within the block.
+	TxIndex uint32
+}
+
+// AnchorTxStore is the storage backend of the anchor transactions of proofs
+// and transfers that is used to keep track of reorgs.
+type AnchorTxStore interface {
+	// FetchAnchorTxs returns the anchor transactions of all stored proofs
+	// and transfers that were confirmed at or above the given height. If
+	// invalidated is true, only the anchor transactions that were
+	// reorged out are returned, along with the block they were confirmed
+	// in before the reorg. Otherwise, only valid ones are returned.
+	FetchAnchorTxs(ctx context.Context, minHeight uint32,
+		invalidated bool) ([]*AnchorTx, error)
+
+	// InvalidateAnchorTx marks all proofs and transfers anchored in the
+	// given transaction as invalidated, as its block was reorged out.
+	InvalidateAnchorTx(ctx context.Context, txid chainhash.Hash) error
+
+	// FetchAnchorTxProofs returns the proof files of all assets anchored
+	// in the given transaction. Only the script key of the locator of each
+	// proof is set.
+	FetchAnchorTxProofs(ctx context.Context,
+		txid chainhash.Hash) ([]*proof.AnnotatedProof, error)
+
+	// RebindAnchorTx marks the given anchor transaction as confirmed in
+	// its new block, replaces the proof files of the assets anchored in
+	// it with the given ones and clears the invalidation of those proofs
+	// and all transfers anchored in the transaction.
+	RebindAnchorTx(ctx context.Context, anchorTx *AnchorTx,
+		proofs []*proof.AnnotatedProof) error
+}
+
+// AnchorTxEventType is the type of event about an anchor transaction.
+type AnchorTxEventType uint8
+
+const (
+	// AnchorTxReorged is the event type of an anchor transaction of which
+	// the block was reorged out. All proofs and transfers anchored in it
+	// are invalidated.
+	AnchorTxReorged AnchorTxEventType = iota
+
+	// AnchorTxRebound is the event type of a reorged anchor transaction
+	// that re-confirmed in a new block. All proofs anchored in it were
+	// re-bound to that block.
+	AnchorTxRebound
+)
+
+// String returns a human-readable version of the event type.
+func (t AnchorTxEventType) String() string {
+	switch t {
+	case AnchorTxReorged:
+		return "reorged"
+
+	case AnchorTxRebound:
+		return "rebound"
+
+	default:
+		return fmt.Sprintf("<unknown: %d>", t)
+	}
+}
+
+// AnchorTxEvent is an event about a reorg of an anchor transaction.
+type AnchorTxEvent struct {
+	// Type is the type of the event.
+	Type AnchorTxEventType
+
+	// Txid is the ID of the anchor transaction.
+	Txid chainhash.Hash
+
+	// BlockHash is the hash of the block the transaction was reorged out
+	// of, or re-confirmed in, depending on the event type.
+	BlockHash chainhash.Hash
+
+	// BlockHeight is the height of the block the transaction was reorged
+	// out of, or re-confirmed in, depending on the event type.
+	BlockHeight uint32
+
+	// timestamp is the time the event was created.
+	timestamp time.Time
+}
+
+// Timestamp returns the time the event was created.
+//
+// NOTE: This is part of the chanutils.Event interface.
+func (e *AnchorTxEvent) Timestamp() time.Time {
+	return e.timestamp
+}
+
+// A compile-time assertion to make sure AnchorTxEvent satisfies the
+// chanutils.Event interface.
+var _ chanutils.Event = (*AnchorTxEvent)(nil)
+
+// ReorgWatcherConfig houses all the items that the reorg watcher needs to
+// carry out its duties.
+type ReorgWatcherConfig struct {
+	// ChainBridge is the main interface for interacting with the chain
+	// backend.
+	ChainBridge ChainBridge
+
+	// AnchorTxStore is the storage backend of the anchor transactions of
+	// proofs and transfers.
+	AnchorTxStore AnchorTxStore
+
+	// ProofFiles is an optional proof archive that holds copies of the
+	// proof files of the AnchorTxStore, like the on-disk proof archive.
+	// Re-bound proofs are imported into it as well.
+	ProofFiles proof.Archiver
+
+	// HeaderVerifier is used when importing re-bound proofs into the
+	// ProofFiles archive.
+	HeaderVerifier proof.HeaderVerifier
+
+	// SafetyDepth is the number of blocks below the chain tip in which
+	// the anchor transactions are checked for reorgs.
+	SafetyDepth uint32
+
+	// ErrChan is the main error channel the watcher will report back
+	// critical errors to the main server. Errors that are likely to be
+	// transient, like a failing chain backend or database call, are only
+	// logged and retried on the next block instead.
+	ErrChan chan<- error
+}
+
+// ReorgWatcher watches the blocks the anchor transactions of stored proofs and
+// transfers were confirmed in. Once such a block is reorged out of the best
+// chain, all proofs and transfers anchored in the transaction are
+// invalidated, until the transaction re-confirms in a new block. The proofs
+// are then re-bound to that block, by updating their block header and merkle
+// proof.
+type ReorgWatcher struct {
+	startOnce sync.Once
+	stopOnce  sync.Once
+
+	cfg *ReorgWatcherConfig
+
+	// pendingTxs is the set of reorged anchor transactions we're waiting
+	// on to re-confirm or to be re-bound. This is only accessed by the
+	// main event loop.
+	pendingTxs map[chainhash.Hash]struct{}
+
+	// failedConfs holds the re-confirmations of reorged anchor
+	// transactions that couldn't be re-bound yet. They are retried on
+	// every new block. This is only accessed by the main event loop.
+	failedConfs map[chainhash.Hash]*chainntnfs.TxConfirmation
+
+	// reloadReorged is set if the reorged anchor transactions need to be
+	// re-fetched from the store on the next block, because we might have
+	// missed watching some of them for their re-confirmation. This is
+	// only accessed by the main event loop.
+	reloadReorged bool
+
+	// failedHeight is the chain height at which checking the anchor
+	// transactions first failed, or zero if the last check succeeded.
+	// This is only accessed by the main event loop.
+	failedHeight uint32
+
+	// reConfs is used to deliver the re-confirmations of reorged anchor
+	// transactions to the main event loop.
+	reConfs chan *chainntnfs.TxConfirmation
+
+	// reConfErrs is used to deliver the IDs of reorged anchor
+	// transactions we failed to wait on to re-confirm to the main event
+	// loop.
+	reConfErrs chan chainhash.Hash
+
+	// eventDistributor is used to notify subscribers about reorged and
+	// re-bound anchor transactions.
+	eventDistributor *chanutils.EventDistributor[*AnchorTxEvent]
+
+	// ContextGuard provides a wait group and main quit channel that can be
+	// used to create guarded contexts.
+	*chanutils.ContextGuard
+}
+
+// NewReorgWatcher creates a new reorg watcher based on the passed config.
+func NewReorgWatcher(cfg *ReorgWatcherConfig) *ReorgWatcher {
+	eventDistributor := chanutils.NewEventDistributor[*AnchorTxEvent]()
+	failedConfs := make(map[chainhash.Hash]*chainntnfs.TxConfirmation)
+	return &ReorgWatcher{
+		cfg:              cfg,
+		pendingTxs:       make(map[chainhash.Hash]struct{}),
+		failedConfs:      failedConfs,
+		reConfs:          make(chan *chainntnfs.TxConfirmation),
+		reConfErrs:       make(chan chainhash.Hash),
+		eventDistributor: eventDistributor,
+		ContextGuard: &chanutils.ContextGuard{
+			DefaultTimeout: DefaultTimeout,
+			Quit:           make(chan struct{}),
+		},
+	}
+}
+
+// Start attempts to start the reorg watcher.
+func (w *ReorgWatcher) Start() error {
+	w.startOnce.Do(func() {
+		log.Info("Starting reorg watcher")
+
+		w.Wg.Add(1)
+		go w.watchAnchorTxs()
+	})
+
+	return nil
+}
+
+// Stop signals for the reorg watcher to gracefully exit.
+func (w *ReorgWatcher) Stop() error {
+	w.stopOnce.Do(func() {
+		log.Info("Stopping reorg watcher")
+
+		close(w.Quit)
+		w.Wg.Wait()
+	})
+
+	return nil
+}
+
+// RegisterSubscriber adds a new subscriber for receiving events about reorged
+// and re-bound anchor transactions.
+func (w *ReorgWatcher) RegisterSubscriber(
+	receiver *chanutils.EventReceiver[*AnchorTxEvent]) {
+
+	w.eventDistributor.RegisterSubscriber(receiver)
+}
+
+// RemoveSubscriber removes the given subscriber and also stops it from
+// processing events.
+func (w *ReorgWatcher) RemoveSubscriber(
+	subscriber *chanutils.EventReceiver[*AnchorTxEvent]) error {
+
+	return w.eventDistributor.RemoveSubscriber(subscriber)
+}
+
+// reportErr reports a critical error to the main server.
+func (w *ReorgWatcher) reportErr(err error) {
+	select {
+	case w.cfg.ErrChan <- err:
+	case <-w.Quit:
+	}
+}
+
+// watchAnchorTxs is the main event loop of the reorg watcher. On every new
+// block, it checks the recently confirmed anchor transactions for reorgs, and
+// it re-binds the proofs of reorged transactions once they re-confirm.
+func (w *ReorgWatcher) watchAnchorTxs() {
+	defer w.Wg.Done()
+
+	ctxStream, cancel := w.WithCtxQuitNoTimeout()
+	defer cancel()
+
+	chainBridge := w.cfg.ChainBridge
+	blockChan, blockErrChan, err := chainBridge.RegisterBlockEpochNtfn(
+		ctxStream,
+	)
+	if err != nil {
+		w.reportErr(fmt.Errorf("unable to register for block "+
+			"epochs: %w", err))
+		return
+	}
+
+	// Any anchor transaction that was reorged out before a restart still
+	// needs to be watched for its re-confirmation.
+	w.watchReorgedTxs()
+
+	for {
+		select {
+		case height := <-blockChan:
+			if w.reloadReorged {
+				w.watchReorgedTxs()
+			}
+
+			failedConfs := w.failedConfs
+			w.failedConfs = make(
+				map[chainhash.Hash]*chainntnfs.TxConfirmation,
+			)
+			for _, conf := range failedConfs {
+				w.handleReConf(conf)
+			}
+
+			// If the last check failed, we also need to cover the
+			// blocks that were within the safety depth back then.
+			checkHeight := uint32(height)
+			if w.failedHeight != 0 {
+				checkHeight = w.failedHeight
+			}
+
+			err := w.checkAnchorTxs(checkHeight, uint32(height))
+			if err != nil {
+				log.Errorf("Unable to check anchor "+
+					"transactions at height %d, retrying "+
+					"on next block: %v", height, err)
+
+				w.failedHeight = checkHeight
+				w.reloadReorged = true
+				continue
+			}
+
+			w.failedHeight = 0
+
+		case conf := <-w.reConfs:
+			w.handleReConf(conf)
+
+		case txid := <-w.reConfErrs:
+			delete(w.pendingTxs, txid)
+			w.reloadReorged = true
+
+		case err := <-blockErrChan:
+			w.reportErr(fmt.Errorf("unable to receive block "+
+				"epochs: %w", err))
+			return
+
+		case <-w.Quit:
+			return
+		}
+	}
+}
+
+// watchReorgedTxs fetches all reorged anchor transactions from the store and
+// waits for their re-confirmation, unless we're already doing so. If that
+// fails, it is retried on the next block.
+func (w *ReorgWatcher) watchReorgedTxs() {
+	ctxt, cancel := w.WithCtxQuit()
+	reorgedTxs, err := w.cfg.AnchorTxStore.FetchAnchorTxs(ctxt, 0, true)
+	cancel()
+	if err != nil {
+		log.Errorf("Unable to fetch reorged anchor transactions, "+
+			"retrying on next block: %v", err)
+
+		w.reloadReorged = true
+		return
+	}
+
+	w.reloadReorged = false
+	for _, anchorTx := range reorgedTxs {
+		if err := w.watchReConf(anchorTx); err != nil {
+			log.Errorf("Retrying on next block: %v", err)
+
+			w.reloadReorged = true
+		}
+	}
+}
+
+// handleReConf re-binds the proofs of a re-confirmed anchor transaction. If
+// that fails, it is retried on the next block.
+func (w *ReorgWatcher) handleReConf(conf *chainntnfs.TxConfirmation) {
+	txid := conf.Tx.TxHash()
+	if err := w.rebindAnchorTx(conf); err != nil {
+		log.Errorf("Unable to re-bind anchor transaction %v, "+
+			"retrying on next block: %v", txid, err)
+
+		w.failedConfs[txid] = conf
+		return
+	}
+
+	delete(w.pendingTxs, txid)
+}
+
+// checkAnchorTxs makes sure that the blocks of all anchor transactions that
+// were confirmed within the safety depth below the given check height, up to
+// the given chain tip, are still part of the best chain. Any anchor
+// transaction of which the block was reorged out is invalidated.
+func (w *ReorgWatcher) checkAnchorTxs(checkHeight, height uint32) error {
+	ctxt, cancel := w.WithCtxQuit()
+	defer cancel()
+
+	var minHeight uint32
+	if checkHeight > w.cfg.SafetyDepth {
+		minHeight = checkHeight - w.cfg.SafetyDepth
+	}
+
+	anchorTxs, err := w.cfg.AnchorTxStore.FetchAnchorTxs(
+		ctxt, minHeight, false,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to fetch anchor transactions: %w",
+			err)
+	}
+
+	for _, anchorTx := range anchorTxs {
+		// The chain backend might still be catching up with the blocks
+		// our proofs were confirmed in.
+		if anchorTx.BlockHeight > height {
+			continue
+		}
+
+		bestHash, err := w.cfg.ChainBridge.GetBlockHash(
+			ctxt, int64(anchorTx.BlockHeight),
+		)
+		if err != nil {
+			return err
+		}
+		if bestHash == anchorTx.BlockHash {
+			continue
+		}
+
+		txid := anchorTx.Tx.TxHash()
+		log.Warnf("Block %v at height %d of anchor transaction %v "+
+			"was reorged out, invalidating proofs and transfers",
+			anchorTx.BlockHash, anchorTx.BlockHeight, txid)
+
+		err = w.cfg.AnchorTxStore.InvalidateAnchorTx(ctxt, txid)
+		if err != nil {
+			return fmt.Errorf("unable to invalidate anchor "+
+				"transaction %v: %w", txid, err)
+		}
+
+		w.eventDistributor.NotifySubscribers(&AnchorTxEvent{
+			Type:        AnchorTxReorged,
+			Txid:        txid,
+			BlockHash:   anchorTx.BlockHash,
+			BlockHeight: anchorTx.BlockHeight,
+			timestamp:   time.Now(),
+		})
+
+		// The transaction is invalidated in the store now, so if we
+		// fail to watch it, we'll pick it up again when reloading the
+		// reorged transactions.
+		if err := w.watchReConf(anchorTx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// watchReConf registers for the re-confirmation of the given reorged anchor
+// transaction, which is delivered to the main event loop.
+func (w *ReorgWatcher) watchReConf(anchorTx *AnchorTx) error {
+	txid := anchorTx.Tx.TxHash()
+	if _, ok := w.pendingTxs[txid]; ok {
+		return nil
+	}
+
+	// The transaction can re-confirm in a block below the one it was
+	// reorged out of, but not below the fork point of the reorg.
+	heightHint := uint32(1)
+	if anchorTx.BlockHeight > w.cfg.SafetyDepth+1 {
+		heightHint = anchorTx.BlockHeight - w.cfg.SafetyDepth
+	}
+
+	ctx, cancel := w.WithCtxQuitNoTimeout()
+	confEvent, errChan, err := w.cfg.ChainBridge.RegisterConfirmationsNtfn(
+		ctx, &txid, anchorTx.Tx.TxOut[0].PkScript, 1, heightHint,
+		true,
+	)
+	if err != nil {
+		cancel()
+		return fmt.Errorf("unable to register for re-confirmation of "+
+			"anchor transaction %v: %w", txid, err)
+	}
+
+	w.pendingTxs[txid] = struct{}{}
+
+	w.Wg.Add(1)
+	go func() {
+		defer w.Wg.Done()
+		defer cancel()
+
+		select {
+		case conf := <-confEvent.Confirmed:
+			select {
+			case w.reConfs <- conf:
+			case <-w.Quit:
+			}
+
+		case err := <-errChan:
+			log.Errorf("Error waiting for re-confirmation of "+
+				"anchor transaction %v, retrying on next "+
+				"block: %v", txid, err)
+
+			select {
+			case w.reConfErrs <- txid:
+			case <-w.Quit:
+			}
+
+		case <-w.Quit:
+		}
+	}()
+
+	return nil
+}
+
+// rebindAnchorTx re-binds the proofs of all assets anchored in a reorged
+// transaction to the block the transaction re-confirmed in.
+func (w *ReorgWatcher) rebindAnchorTx(conf *chainntnfs.TxConfirmation) error {
+	txid := conf.Tx.TxHash()
+
+	if conf.Block == nil {
+		return fmt.Errorf("confirmation doesn't include block")
+	}
+
+	merkleProof, err := proof.NewTxMerkleProof(
+		conf.Block.Transactions, int(conf.TxIndex),
+	)
+	if err != nil {
+		return fmt.Errorf("unable to create merkle proof: %w", err)
+	}
+
+	ctxt, cancel := w.WithCtxQuit()
+	defer cancel()
+
+	proofs, err := w.cfg.AnchorTxStore.FetchAnchorTxProofs(ctxt, txid)
+	if err != nil {
+		return fmt.Errorf("unable to fetch proofs: %w", err)
+	}
+
+	for _, p := range proofs {
+		err := rebindProofFile(p, txid, conf.Block.Header, merkleProof)
+		if err != nil {
+			return fmt.Errorf("unable to re-bind proof for script "+
+				"key %x: %w", p.ScriptKey.SerializeCompressed(),
+				err)
+		}
+	}
+
+	anchorTx := &AnchorTx{
+		Tx:          conf.Tx,
+		BlockHash:   *conf.BlockHash,
+		BlockHeight: conf.BlockHeight,
+		TxIndex:     conf.TxIndex,
+	}
+	err = w.cfg.AnchorTxStore.RebindAnchorTx(ctxt, anchorTx, proofs)
+	if err != nil {
+		return err
+	}
+
+	if w.cfg.ProofFiles != nil && len(proofs) > 0 {
+		err := w.cfg.ProofFiles.ImportProofs(
+			ctxt, w.cfg.HeaderVerifier, proofs...,
+		)
+		if err != nil {
+			return fmt.Errorf("unable to import re-bound proofs: "+
+				"%w", err)
+		}
+	}
+
+	log.Infof("Re-bound %d proofs of anchor transaction %v to block %v "+
+		"at height %d", len(proofs), txid, conf.BlockHash,
+		conf.BlockHeight)
+
+	w.eventDistributor.NotifySubscribers(&AnchorTxEvent{
+		Type:        AnchorTxRebound,
+		Txid:        txid,
+		BlockHash:   *conf.BlockHash,
+		BlockHeight: conf.BlockHeight,
+		timestamp:   time.Now(),
+	})
+
+	return nil
+}
+
+// rebindProofFile replaces the block header and merkle proof of the last proof
+// of the given proof file, which must be anchored in the transaction with the
+// given ID. The locator of the proof is completed along the way.
+//
+// NOTE: Proofs of assets that were already sent on are not re-bound, as they
+// are no longer the last proof of any proof file we store. The asset wallet
+// must therefore be configured to not spend assets of which the anchor
+// transaction has fewer confirmations than the safety depth.
+func rebindProofFile(p *proof.AnnotatedProof, txid chainhash.Hash,
+	header wire.BlockHeader, merkleProof *proof.TxMerkleProof) error {
+
+	var proofFile proof.File
+	if err := proofFile.Decode(bytes.NewReader(p.Blob)); err != nil {
+		return fmt.Errorf("unable to decode proof file: %w", err)
+	}
+
+	lastProof, err := proofFile.LastProof()
+	if err != nil {
+		return err
+	}
+
+	if lastProof.AnchorTx.TxHash() != txid {
+		return fmt.Errorf("last proof is anchored in %v",
+			lastProof.AnchorTx.TxHash())
+	}
+
+	lastProof.BlockHeader = header
+	lastProof.TxMerkleProof = *merkleProof
+	if err := proofFile.ReplaceLastProof(*lastProof); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := proofFile.Encode(&buf); err != nil {
+		return fmt.Errorf("unable to encode proof file: %w", err)
+	}
+	p.Blob = buf.Bytes()
+
+	assetID := lastProof.Asset.ID()
+	p.AssetID = &assetID
+	if lastProof.Asset.GroupKey != nil {
+		p.GroupKey = &lastProof.Asset.GroupKey.GroupPubKey
+	}
+
+	return nil
+}
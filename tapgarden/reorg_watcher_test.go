@@ -0,0 +1,438 @@
+package tapgarden_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightninglabs/taproot-assets/chanutils"
+	"github.com/lightninglabs/taproot-assets/internal/test"
+	"github.com/lightninglabs/taproot-assets/proof"
+	"github.com/lightninglabs/taproot-assets/tapgarden"
+	"github.com/stretchr/testify/require"
+)
+
+// mockAnchorTxStore is an in-memory tapgarden.AnchorTxStore that holds the
+// proofs of a single anchor transaction.
+type mockAnchorTxStore struct {
+	sync.Mutex
+
+	anchorTx    *tapgarden.AnchorTx
+	proofs      []*proof.AnnotatedProof
+	invalidated bool
+
+	// fetchErrs and rebindErrs are the number of calls to FetchAnchorTxs
+	// and RebindAnchorTx that fail before they succeed again.
+	fetchErrs  int
+	rebindErrs int
+}
+
+// errStoreUnavailable is the error returned by the mock anchor tx store for
+// failing calls.
+var errStoreUnavailable = errors.New("store unavailable")
+
+func (m *mockAnchorTxStore) FetchAnchorTxs(_ context.Context, minHeight uint32,
+	invalidated bool) ([]*tapgarden.AnchorTx, error) {
+
+	m.Lock()
+	defer m.Unlock()
+
+	if m.fetchErrs > 0 {
+		m.fetchErrs--
+		return nil, errStoreUnavailable
+	}
+
+	if m.invalidated != invalidated || m.anchorTx.BlockHeight < minHeight {
+		return nil, nil
+	}
+
+	anchorTx := *m.anchorTx
+	return []*tapgarden.AnchorTx{&anchorTx}, nil
+}
+
+func (m *mockAnchorTxStore) InvalidateAnchorTx(_ context.Context,
+	_ chainhash.Hash) error {
+
+	m.Lock()
+	defer m.Unlock()
+
+	m.invalidated = true
+
+	return nil
+}
+
+func (m *mockAnchorTxStore) FetchAnchorTxProofs(_ context.Context,
+	_ chainhash.Hash) ([]*proof.AnnotatedProof, error) {
+
+	m.Lock()
+	defer m.Unlock()
+
+	return chanutils.Map(
+		m.proofs, func(p *proof.AnnotatedProof) *proof.AnnotatedProof {
+			return &proof.AnnotatedProof{
+				Locator: proof.Locator{
+					ScriptKey: p.ScriptKey,
+				},
+				Blob: p.Blob,
+			}
+		},
+	), nil
+}
+
+func (m *mockAnchorTxStore) RebindAnchorTx(_ context.Context,
+	anchorTx *tapgarden.AnchorTx, proofs []*proof.AnnotatedProof) error {
+
+	m.Lock()
+	defer m.Unlock()
+
+	if m.rebindErrs > 0 {
+		m.rebindErrs--
+		return errStoreUnavailable
+	}
+
+	m.anchorTx = anchorTx
+	m.proofs = proofs
+	m.invalidated = false
+
+	return nil
+}
+
+func (m *mockAnchorTxStore) isInvalidated() bool {
+	m.Lock()
+	defer m.Unlock()
+
+	return m.invalidated
+}
+
+var _ tapgarden.AnchorTxStore = (*mockAnchorTxStore)(nil)
+
+// newAnchorTxStore creates a mock anchor tx store with the proof of a random
+// asset that is anchored in a transaction confirmed in the given block.
+func newAnchorTxStore(t *testing.T, block *wire.MsgBlock,
+	height uint32) *mockAnchorTxStore {
+
+	anchorTx := block.Transactions[1]
+	merkleProof, err := proof.NewTxMerkleProof(block.Transactions, 1)
+	require.NoError(t, err)
+
+	testAsset := asset.RandAsset(t, asset.Normal)
+	assetProof := proof.Proof{
+		PrevOut:       test.RandOp(t),
+		BlockHeader:   block.Header,
+		AnchorTx:      *anchorTx,
+		TxMerkleProof: *merkleProof,
+		Asset:         *testAsset,
+		InclusionProof: proof.TaprootProof{
+			InternalKey: test.RandPubKey(t),
+		},
+	}
+	proofFile, err := proof.NewFile(proof.V0, assetProof)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, proofFile.Encode(&buf))
+
+	return &mockAnchorTxStore{
+		anchorTx: &tapgarden.AnchorTx{
+			Tx:          anchorTx,
+			BlockHash:   block.BlockHash(),
+			BlockHeight: height,
+			TxIndex:     1,
+		},
+		proofs: []*proof.AnnotatedProof{{
+			Locator: proof.Locator{
+				ScriptKey: *testAsset.ScriptKey.PubKey,
+			},
+			Blob: buf.Bytes(),
+		}},
+	}
+}
+
+// randBlock creates a random block that contains the given transaction as its
+// second transaction.
+func randBlock(t *testing.T, tx *wire.MsgTx) *wire.MsgBlock {
+	coinbase := wire.NewMsgTx(2)
+	coinbase.AddTxIn(&wire.TxIn{
+		SignatureScript: test.RandBytes(8),
+	})
+	coinbase.AddTxOut(&wire.TxOut{})
+
+	merkleTree := blockchain.BuildMerkleTreeStore(
+		[]*btcutil.Tx{btcutil.NewTx(coinbase), btcutil.NewTx(tx)},
+		false,
+	)
+	prevBlock := test.RandHash()
+	blockHeader := wire.NewBlockHeader(
+		0, &prevBlock, merkleTree[len(merkleTree)-1], 0, 0,
+	)
+
+	return &wire.MsgBlock{
+		Header:       *blockHeader,
+		Transactions: []*wire.MsgTx{coinbase, tx},
+	}
+}
+
+// randAnchorTx creates a random anchor transaction.
+func randAnchorTx(t *testing.T) *wire.MsgTx {
+	anchorTx := wire.NewMsgTx(2)
+	anchorTx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: test.RandOp(t),
+	})
+	anchorTx.AddTxOut(&wire.TxOut{
+		PkScript: test.RandBytes(34),
+		Value:    1000,
+	})
+
+	return anchorTx
+}
+
+// nextAnchorTxEvent waits for the next anchor tx event of the given receiver.
+func nextAnchorTxEvent(t *testing.T,
+	receiver *chanutils.EventReceiver[*tapgarden.AnchorTxEvent],
+) *tapgarden.AnchorTxEvent {
+
+	select {
+	case event := <-receiver.NewItemCreated.ChanOut():
+		return event
+
+	case <-time.After(testTimeout):
+		t.Fatalf("no anchor tx event received")
+		return nil
+	}
+}
+
+// TestReorgWatcher tests that the reorg watcher invalidates the proofs of an
+// anchor transaction that was reorged out, and re-binds them to the block the
+// transaction re-confirms in.
+func TestReorgWatcher(t *testing.T) {
+	t.Parallel()
+
+	const height = 100
+
+	anchorTx := randAnchorTx(t)
+	oldBlock := randBlock(t, anchorTx)
+	newBlock := randBlock(t, anchorTx)
+
+	store := newAnchorTxStore(t, oldBlock, height)
+	chainBridge := tapgarden.NewMockChainBridge()
+	chainBridge.SetBlockHash(height, oldBlock.BlockHash())
+
+	errChan := make(chan error, 1)
+	watcher := tapgarden.NewReorgWatcher(&tapgarden.ReorgWatcherConfig{
+		ChainBridge:   chainBridge,
+		AnchorTxStore: store,
+		SafetyDepth:   tapgarden.DefaultReorgSafetyDepth,
+		ErrChan:       errChan,
+	})
+	require.NoError(t, watcher.Start())
+	t.Cleanup(func() {
+		require.NoError(t, watcher.Stop())
+	})
+
+	receiver := chanutils.NewEventReceiver[*tapgarden.AnchorTxEvent](
+		chanutils.DefaultQueueSize,
+	)
+	watcher.RegisterSubscriber(receiver)
+
+	// As long as the block of the anchor transaction is part of the best
+	// chain, nothing happens. The epochs are delivered over an unbuffered
+	// channel, so the first one was processed once the second one is sent.
+	chainBridge.BlockEpochs <- height + 1
+	chainBridge.BlockEpochs <- height + 2
+	require.False(t, store.isInvalidated())
+
+	// Once the block is reorged out, the proofs are invalidated and the
+	// watcher waits for the transaction to re-confirm. The reorg might
+	// already be noticed while processing the last epoch, in which case
+	// the watcher doesn't read the next one until we've received the
+	// confirmation request.
+	chainBridge.SetBlockHash(height, test.RandHash())
+	go func() {
+		select {
+		case chainBridge.BlockEpochs <- height + 3:
+		case <-time.After(testTimeout):
+		}
+	}()
+
+	event := nextAnchorTxEvent(t, receiver)
+	require.Equal(t, tapgarden.AnchorTxReorged, event.Type)
+	require.Equal(t, anchorTx.TxHash(), event.Txid)
+	require.Equal(t, oldBlock.BlockHash(), event.BlockHash)
+	require.EqualValues(t, height, event.BlockHeight)
+	require.True(t, store.isInvalidated())
+
+	var reqNo int
+	select {
+	case reqNo = <-chainBridge.ConfReqSignal:
+	case <-time.After(testTimeout):
+		t.Fatalf("no re-confirmation requested")
+	}
+
+	// Further blocks don't invalidate the transaction again.
+	chainBridge.BlockEpochs <- height + 4
+
+	// Once the transaction re-confirms, the proofs are re-bound to the new
+	// block.
+	newBlockHash := newBlock.BlockHash()
+	chainBridge.SendConfNtfn(
+		reqNo, &newBlockHash, height+1, 1, newBlock, anchorTx,
+	)
+
+	event = nextAnchorTxEvent(t, receiver)
+	require.Equal(t, tapgarden.AnchorTxRebound, event.Type)
+	require.Equal(t, anchorTx.TxHash(), event.Txid)
+	require.Equal(t, newBlockHash, event.BlockHash)
+	require.EqualValues(t, height+1, event.BlockHeight)
+	require.False(t, store.isInvalidated())
+
+	store.Lock()
+	require.Equal(t, newBlockHash, store.anchorTx.BlockHash)
+	require.EqualValues(t, height+1, store.anchorTx.BlockHeight)
+	require.Len(t, store.proofs, 1)
+
+	var proofFile proof.File
+	err := proofFile.Decode(bytes.NewReader(store.proofs[0].Blob))
+	require.NoError(t, err)
+	store.Unlock()
+
+	lastProof, err := proofFile.LastProof()
+	require.NoError(t, err)
+	require.Equal(t, newBlock.Header, lastProof.BlockHeader)
+	require.True(t, lastProof.TxMerkleProof.Verify(
+		anchorTx, newBlock.Header.MerkleRoot,
+	))
+
+	select {
+	case err := <-errChan:
+		t.Fatalf("unexpected error: %v", err)
+	default:
+	}
+}
+
+// TestReorgWatcherRestart tests that the reorg watcher resumes waiting for the
+// re-confirmation of anchor transactions that were reorged out before.
+func TestReorgWatcherRestart(t *testing.T) {
+	t.Parallel()
+
+	anchorTx := randAnchorTx(t)
+	store := newAnchorTxStore(t, randBlock(t, anchorTx), 100)
+	store.invalidated = true
+
+	chainBridge := tapgarden.NewMockChainBridge()
+	watcher := tapgarden.NewReorgWatcher(&tapgarden.ReorgWatcherConfig{
+		ChainBridge:   chainBridge,
+		AnchorTxStore: store,
+		SafetyDepth:   tapgarden.DefaultReorgSafetyDepth,
+		ErrChan:       make(chan error, 1),
+	})
+	require.NoError(t, watcher.Start())
+	t.Cleanup(func() {
+		require.NoError(t, watcher.Stop())
+	})
+
+	select {
+	case <-chainBridge.ConfReqSignal:
+	case <-time.After(testTimeout):
+		t.Fatalf("no re-confirmation requested")
+	}
+}
+
+// TestReorgWatcherTransientErrors tests that the reorg watcher retries on the
+// next block if the store fails, instead of reporting a critical error.
+func TestReorgWatcherTransientErrors(t *testing.T) {
+	t.Parallel()
+
+	const height = 100
+
+	anchorTx := randAnchorTx(t)
+	oldBlock := randBlock(t, anchorTx)
+	newBlock := randBlock(t, anchorTx)
+
+	// The initial fetch of the reorged transactions, its retry on the
+	// first block and the first check for reorgs all fail.
+	store := newAnchorTxStore(t, oldBlock, height)
+	store.fetchErrs = 3
+	store.rebindErrs = 1
+
+	chainBridge := tapgarden.NewMockChainBridge()
+	chainBridge.SetBlockHash(height, test.RandHash())
+
+	errChan := make(chan error, 1)
+	watcher := tapgarden.NewReorgWatcher(&tapgarden.ReorgWatcherConfig{
+		ChainBridge:   chainBridge,
+		AnchorTxStore: store,
+		SafetyDepth:   tapgarden.DefaultReorgSafetyDepth,
+		ErrChan:       errChan,
+	})
+	require.NoError(t, watcher.Start())
+	t.Cleanup(func() {
+		require.NoError(t, watcher.Stop())
+	})
+
+	receiver := chanutils.NewEventReceiver[*tapgarden.AnchorTxEvent](
+		chanutils.DefaultQueueSize,
+	)
+	watcher.RegisterSubscriber(receiver)
+
+	// The reorg is only noticed while processing the second block, even
+	// though the anchor transaction is below the safety depth by then.
+	chainBridge.BlockEpochs <- height + 1
+	go func() {
+		select {
+		case chainBridge.BlockEpochs <- height +
+			tapgarden.DefaultReorgSafetyDepth + 1:
+
+		case <-time.After(testTimeout):
+		}
+	}()
+
+	event := nextAnchorTxEvent(t, receiver)
+	require.Equal(t, tapgarden.AnchorTxReorged, event.Type)
+	require.True(t, store.isInvalidated())
+
+	var reqNo int
+	select {
+	case reqNo = <-chainBridge.ConfReqSignal:
+	case <-time.After(testTimeout):
+		t.Fatalf("no re-confirmation requested")
+	}
+
+	// The first attempt to re-bind the proofs fails, so they're only
+	// re-bound on one of the next blocks.
+	newBlockHash := newBlock.BlockHash()
+	chainBridge.SendConfNtfn(
+		reqNo, &newBlockHash, height+1, 1, newBlock, anchorTx,
+	)
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		epoch := int32(height + tapgarden.DefaultReorgSafetyDepth + 2)
+		for ; ; epoch++ {
+			select {
+			case chainBridge.BlockEpochs <- epoch:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	event = nextAnchorTxEvent(t, receiver)
+	require.Equal(t, tapgarden.AnchorTxRebound, event.Type)
+	require.Equal(t, newBlockHash, event.BlockHash)
+	require.False(t, store.isInvalidated())
+
+	select {
+	case err := <-errChan:
+		t.Fatalf("unexpected error: %v", err)
+	default:
+	}
+}
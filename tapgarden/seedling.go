@@ -1,11 +1,20 @@
 package tapgarden
 
 import (
+	"crypto/sha256"
 	"fmt"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/lightninglabs/taproot-assets/asset"
 	"github.com/lightninglabs/taproot-assets/proof"
+	"github.com/lightningnetwork/lnd/keychain"
+)
+
+const (
+	// MaxEditions is the maximum number of editions that can be minted
+	// from a single seedling. Each edition is a separate asset leaf with
+	// its own script key, so this bounds the size of a minting batch.
+	MaxEditions = 1_000
 )
 
 var (
@@ -22,6 +31,47 @@ var (
 	// ErrInvalidAssetAmt is returned in an asset request has an invalid
 	// amount.
 	ErrInvalidAssetAmt = fmt.Errorf("asset amt cannot be zero")
+
+	// ErrInvalidNonDivisible is returned if an asset request marks an
+	// asset other than a normal asset as non-divisible.
+	ErrInvalidNonDivisible = fmt.Errorf("only normal assets can be " +
+		"non-divisible")
+
+	// ErrInvalidEditions is returned if an asset request asks for an asset
+	// other than a collectible to be minted as editions, or for more than
+	// the maximum number of editions.
+	ErrInvalidEditions = fmt.Errorf("only collectibles can be minted as "+
+		"up to %d editions", MaxEditions)
+
+	// ErrInvalidGroupTapscriptRoot is returned if an asset request
+	// specifies a group tapscript root that isn't valid for the request.
+	ErrInvalidGroupTapscriptRoot = fmt.Errorf("group tapscript root " +
+		"must be 32 bytes and requires emission to be enabled")
+
+	// ErrInvalidGroupScriptSpend is returned if an asset request
+	// specifies a script spend for its group witness, but isn't issued
+	// into a group that commits to a tapscript tree.
+	ErrInvalidGroupScriptSpend = fmt.Errorf("group script spend " +
+		"requires a group that commits to a tapscript tree")
+
+	// ErrInvalidGroupKeyLocator is returned if an asset request specifies
+	// the key locator of an existing group along with any other way of
+	// picking the group of the asset, or a locator outside of the Taproot
+	// Assets key family.
+	ErrInvalidGroupKeyLocator = fmt.Errorf("group key locator must be " +
+		"in the taproot assets key family and can't be combined with " +
+		"a group key, group anchor or emission")
+
+	// ErrInvalidGroupMember is returned if a new asset that should be
+	// issued into an existing group doesn't have a valid witness for the
+	// key of that group.
+	ErrInvalidGroupMember = fmt.Errorf("asset isn't a valid member of " +
+		"its group")
+
+	// ErrInvalidDecimalDisplay is returned if an asset request specifies a
+	// decimal display for an asset other than a normal asset.
+	ErrInvalidDecimalDisplay = fmt.Errorf("only normal assets can have " +
+		"a decimal display")
 )
 
 // MintingState is an enum that tracks an asset through the various minting
@@ -91,13 +141,51 @@ type Seedling struct {
 	// for this asset meaning future assets linked to it can be created.
 	EnableEmission bool
 
+	// GroupKeyLocator is the key locator of the raw key of an existing
+	// asset group the asset should be issued into, as an alternative to
+	// specifying the tweaked group key in GroupInfo. Once the seedling is
+	// queued, the locator is resolved into the matching GroupInfo.
+	GroupKeyLocator *keychain.KeyLocator
+
 	// GroupAnchor is the name of another seedling in the pending batch that
 	// will anchor an asset group. This seedling will be minted with the
 	// same group key as the anchor asset.
 	GroupAnchor *string
 
+	// NonDivisible if true, then the minted normal asset can never be split
+	// into multiple non-zero outputs by any future state transition.
+	NonDivisible bool
+
+	// Editions if true, then the collectible is minted as Amount
+	// individually numbered editions that share the same genesis. Each
+	// edition is a unique unit with its own script key and serial number.
+	Editions bool
+
+	// GroupTapscriptRoot is the root of the tapscript tree the group key
+	// of a new asset group should commit to. This can only be set if
+	// emission is enabled and allows future tranches to be issued by
+	// satisfying one of the scripts in the tree.
+	GroupTapscriptRoot []byte
+
+	// GroupScriptSpend is the leaf of the group's tapscript tree, along
+	// with the keys that sign for it, through which the issuance of the
+	// asset into its group is authorized. If nil, the issuance of an asset
+	// into a group that commits to a tapscript tree is authorized with a
+	// key spend of the group's internal key.
+	GroupScriptSpend *asset.GroupScriptSpend
+
+	// DecimalDisplay is the number of decimal places wallets should use
+	// when displaying amounts of the asset. If non-zero, it's encoded into
+	// the JSON meta data of the asset, so it's committed to at genesis.
+	DecimalDisplay uint32
+
 	// update is used to send updates w.r.t the state of the batch.
 	updates SeedlingUpdates
+
+	// batchKey is the key of the pending batch the seedling should be
+	// added to. If nil, the seedling is added to the default pending
+	// batch.
+	batchKey *btcec.PublicKey
 }
 
 // validateFields attempts to validate the set of input fields for the passed
@@ -123,11 +211,97 @@ func (c Seedling) validateFields() error {
 	// Creating an asset with zero available supply is not allowed.
 	case c.Amount == 0:
 		return ErrInvalidAssetAmt
+
+	// Collectibles are already indivisible, so the flag only has a
+	// meaning for normal assets.
+	case c.NonDivisible && c.AssetType != asset.Normal:
+		return ErrInvalidNonDivisible
+
+	// Every unit of a normal asset is fungible, so only collectibles can
+	// be minted as a series of numbered editions.
+	case c.Editions && (c.AssetType != asset.Collectible ||
+		c.Amount > MaxEditions):
+
+		return ErrInvalidEditions
+
+	// A group tapscript root only makes sense when creating a new group,
+	// and must be a valid tap hash.
+	case len(c.GroupTapscriptRoot) != 0 && (!c.EnableEmission ||
+		len(c.GroupTapscriptRoot) != sha256.Size):
+
+		return ErrInvalidGroupTapscriptRoot
+
+	// A script spend needs a group with a tapscript tree, which is either
+	// created with this seedling or already exists. If the existing group
+	// doesn't commit to a tapscript tree, this is caught once the group
+	// witness is created.
+	case c.GroupScriptSpend != nil && len(c.GroupTapscriptRoot) == 0 &&
+		!c.HasGroupKey() && c.GroupKeyLocator == nil &&
+		c.GroupAnchor == nil:
+
+		return ErrInvalidGroupScriptSpend
+
+	// A group key locator picks the existing group of the asset, so it
+	// can't be combined with any other way of choosing a group. Only keys
+	// of our own key family can be used to sign for the group.
+	case c.GroupKeyLocator != nil && (c.HasGroupKey() ||
+		c.GroupAnchor != nil || c.EnableEmission ||
+		c.GroupKeyLocator.Family != asset.TaprootAssetsKeyFamily):
+
+		return ErrInvalidGroupKeyLocator
+
+	// Collectibles are only ever held in whole units, so they can't be
+	// displayed with decimal places.
+	case c.DecimalDisplay != 0 && c.AssetType != asset.Normal:
+		return ErrInvalidDecimalDisplay
+
+	case c.DecimalDisplay > asset.MaxDecimalDisplay:
+		return asset.ErrInvalidDecimalDisplay
+	}
+
+	// A metadata update can only be carried by a new tranche of an
+	// existing asset group, as it replaces the metadata of that group.
+	if c.Meta != nil && c.Meta.Type == proof.MetaGroupUpdate {
+		if c.GroupInfo == nil {
+			return proof.ErrMetaUpdateNotGrouped
+		}
+
+		update, err := c.Meta.MetaUpdate()
+		if err != nil {
+			return err
+		}
+
+		if _, err := update.Meta.DecimalDisplay(); err != nil {
+			return err
+		}
+	}
+
+	// Wallets need to be able to interpret the decimal display of the
+	// asset, so we make sure it's valid before we commit to it.
+	if _, err := c.Meta.DecimalDisplay(); err != nil {
+		return err
 	}
 
 	return nil
 }
 
+// encodeDecimalDisplay encodes the decimal display of the seedling into its
+// JSON meta data. If the seedling doesn't have any meta data yet, a new JSON
+// meta data object that only carries the decimal display is created.
+func (c *Seedling) encodeDecimalDisplay() error {
+	if c.DecimalDisplay == 0 {
+		return nil
+	}
+
+	if c.Meta == nil {
+		c.Meta = &proof.MetaReveal{
+			Type: proof.MetaJSON,
+		}
+	}
+
+	return c.Meta.SetDecimalDisplay(c.DecimalDisplay)
+}
+
 // validateGroupKey attempts to validate that the non-zero group key provided
 // with a seedling is owned by the daemon and can be used with this seedling.
 func (c Seedling) validateGroupKey(group asset.AssetGroup) error {
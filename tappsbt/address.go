@@ -11,7 +11,11 @@ import (
 
 // FromAddresses creates an empty virtual transaction packet from the given
 // addresses. Because sending to an address is always non-interactive, a change
-// output is also added to the packet.
+// output is also added to the packet. Each static address is paid through a
+// unique receive address that is derived from a fresh random nonce. The amount
+// to send to addresses that don't specify one must be set with ForAmount
+// first, just like the asset ID to send to addresses that accept any asset of
+// a group must be set with ForAssetID.
 func FromAddresses(receiverAddrs []*address.Tap,
 	firstOutputIndex uint32) (*VPacket, error) {
 
@@ -51,6 +55,29 @@ func FromAddresses(receiverAddrs []*address.Tap,
 	// index, but start at the first one indicated by the caller.
 	for idx := range receiverAddrs {
 		addr := receiverAddrs[idx]
+		if !addr.HasAmount() {
+			return nil, fmt.Errorf("address %d has no amount, the "+
+				"amount to send must be chosen first", idx)
+		}
+		if addr.AnyAssetInGroup {
+			return nil, fmt.Errorf("address %d accepts any asset "+
+				"of a group, the asset to send must be chosen "+
+				"first", idx)
+		}
+
+		if addr.Static {
+			nonce, err := address.RandStaticNonce()
+			if err != nil {
+				return nil, err
+			}
+
+			addr, err = addr.StaticReceive(nonce)
+			if err != nil {
+				return nil, fmt.Errorf("unable to derive "+
+					"static receive: %w", err)
+			}
+		}
+
 		pkt.Outputs = append(pkt.Outputs, &VOutput{
 			Amount:            addr.Amount,
 			Interactive:       false,
@@ -60,6 +87,7 @@ func FromAddresses(receiverAddrs []*address.Tap,
 			),
 			AnchorOutputInternalKey:      &addr.InternalKey,
 			AnchorOutputTapscriptSibling: addr.TapscriptSibling,
+			ProofDeliveryAddress:         addr.ProofCourierAddr,
 		})
 	}
 
@@ -113,9 +141,10 @@ func AddOutput(pkt *VPacket, amount uint64, scriptAddr asset.ScriptKey,
 
 // OwnershipProofPacket creates a virtual transaction packet that is used to
 // prove ownership of an asset. It creates a 1-in-1-out transaction that spends
-// the owned asset to the NUMS key. The witness is created over an empty
-// previous outpoint, so it can never be used in an actual state transition.
-func OwnershipProofPacket(ownedAsset *asset.Asset,
+// the owned asset to the NUMS key, which is tweaked with the optional challenge
+// of the verifier. The witness is created over an empty previous outpoint, so
+// it can never be used in an actual state transition.
+func OwnershipProofPacket(ownedAsset *asset.Asset, challenge *[32]byte,
 	chainParams *address.ChainParams) *VPacket {
 
 	// We create the ownership proof by creating a virtual packet that
@@ -136,11 +165,10 @@ func OwnershipProofPacket(ownedAsset *asset.Asset,
 		),
 	}
 
+	scriptKey := asset.GenChallengeNUMS(challenge)
 	outputAsset := ownedAsset.Copy()
-	outputAsset.ScriptKey = asset.NUMSScriptKey
-	outputAsset.PrevWitnesses = []asset.Witness{{
-		PrevID: &prevId,
-	}}
+	outputAsset.ScriptKey = scriptKey
+	outputAsset.ReplaceWitnesses(prevId)
 
 	vPkt := &VPacket{
 		Inputs: []*VInput{{
@@ -151,7 +179,7 @@ func OwnershipProofPacket(ownedAsset *asset.Asset,
 			Amount:            outputAsset.Amount,
 			Interactive:       true,
 			AnchorOutputIndex: 0,
-			ScriptKey:         asset.NUMSScriptKey,
+			ScriptKey:         scriptKey,
 		}},
 		ChainParams: chainParams,
 	}
@@ -159,3 +187,123 @@ func OwnershipProofPacket(ownedAsset *asset.Asset,
 
 	return vPkt
 }
+
+// SplitByAssetID creates one virtual transaction packet per asset ID from the
+// given selected inputs, which may span multiple asset IDs. The recipient
+// outputs for each asset ID are taken from the given map and every asset ID
+// that is spent must have at least one recipient output. Each packet gets its
+// own split root change output that is anchored in the given change output
+// index and carries the difference between the input and recipient amounts.
+// The change output is only omitted if the full input value goes to
+// interactive recipients. The change output's script key is set to the NUMS
+// key and must be replaced with a real key by the caller if there is any
+// change. The packets are returned in the order in which their asset IDs
+// first appear in the list of inputs.
+func SplitByAssetID(inputs []*VInput, recipients map[asset.ID][]*VOutput,
+	changeOutputIndex uint32, chainParams *address.ChainParams) ([]*VPacket,
+	error) {
+
+	if len(inputs) == 0 {
+		return nil, fmt.Errorf("at least one input must be specified")
+	}
+
+	var (
+		assetIDs    []asset.ID
+		packets     = make(map[asset.ID]*VPacket)
+		inputTotals = make(map[asset.ID]uint64)
+		prevIDs     = make(map[asset.PrevID]struct{})
+	)
+	for idx := range inputs {
+		vIn := inputs[idx]
+		if vIn.Asset() == nil {
+			return nil, fmt.Errorf("input %d has no asset set", idx)
+		}
+
+		if _, ok := prevIDs[vIn.PrevID]; ok {
+			return nil, fmt.Errorf("input %d is a duplicate", idx)
+		}
+		prevIDs[vIn.PrevID] = struct{}{}
+
+		id := vIn.PrevID.ID
+		if vIn.Asset().ID() != id {
+			return nil, fmt.Errorf("input %d asset ID %v does not "+
+				"match previous ID %v", idx, vIn.Asset().ID(),
+				id)
+		}
+
+		vPkt, ok := packets[id]
+		if !ok {
+			vPkt = &VPacket{
+				ChainParams: chainParams,
+			}
+			packets[id] = vPkt
+			assetIDs = append(assetIDs, id)
+		}
+
+		vPkt.Inputs = append(vPkt.Inputs, vIn)
+		inputTotals[id] += vIn.Asset().Amount
+	}
+
+	for id := range recipients {
+		if _, ok := packets[id]; !ok {
+			return nil, fmt.Errorf("no inputs selected for asset "+
+				"ID %v", id)
+		}
+	}
+
+	result := make([]*VPacket, 0, len(assetIDs))
+	for _, id := range assetIDs {
+		vPkt := packets[id]
+
+		outputs := recipients[id]
+		if len(outputs) == 0 {
+			return nil, fmt.Errorf("no outputs for asset ID %v", id)
+		}
+
+		var (
+			outputTotal    uint64
+			allInteractive = true
+		)
+		for idx := range outputs {
+			vOut := outputs[idx]
+			if vOut.Type.IsSplitRoot() {
+				return nil, fmt.Errorf("output %d of asset ID "+
+					"%v is a split root, change outputs "+
+					"are added automatically", idx, id)
+			}
+
+			outputTotal += vOut.Amount
+			allInteractive = allInteractive && vOut.Interactive
+		}
+
+		if outputTotal > inputTotals[id] {
+			return nil, fmt.Errorf("outputs of asset ID %v exceed "+
+				"inputs: %d > %d", id, outputTotal,
+				inputTotals[id])
+		}
+
+		vPkt.Outputs = make([]*VOutput, 0, len(outputs)+1)
+		vPkt.Outputs = append(vPkt.Outputs, outputs...)
+
+		// A full value send to interactive recipients doesn't need a
+		// change output. In all other cases we either have actual
+		// change or need a split root to carry the tombstone of a
+		// non-interactive full value send.
+		change := inputTotals[id] - outputTotal
+		if change == 0 && allInteractive {
+			result = append(result, vPkt)
+			continue
+		}
+
+		vPkt.Outputs = append(vPkt.Outputs, &VOutput{
+			Amount:            change,
+			Type:              TypeSplitRoot,
+			Interactive:       allInteractive,
+			AnchorOutputIndex: changeOutputIndex,
+			ScriptKey:         asset.NUMSScriptKey,
+		})
+		result = append(result, vPkt)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,147 @@
+package tappsbt
+
+import (
+	"testing"
+
+	"github.com/lightninglabs/taproot-assets/address"
+	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightninglabs/taproot-assets/internal/test"
+	"github.com/stretchr/testify/require"
+)
+
+// randInput creates a virtual input that spends a random asset with the given
+// amount.
+func randInput(t *testing.T, genesis asset.Genesis, amount uint64) *VInput {
+	inputAsset := asset.RandAssetWithValues(
+		t, genesis, nil, asset.RandScriptKey(t),
+	)
+	inputAsset.Amount = amount
+
+	vIn := &VInput{
+		PrevID: asset.PrevID{
+			OutPoint: test.RandOp(t),
+			ID:       inputAsset.ID(),
+			ScriptKey: asset.ToSerialized(
+				inputAsset.ScriptKey.PubKey,
+			),
+		},
+	}
+	vIn.asset = inputAsset
+
+	return vIn
+}
+
+// TestSplitByAssetID tests that a mixed set of inputs is split into one
+// virtual packet per asset ID with the correct change outputs.
+func TestSplitByAssetID(t *testing.T) {
+	t.Parallel()
+
+	genesis1 := asset.RandGenesis(t, asset.Normal)
+	genesis2 := asset.RandGenesis(t, asset.Normal)
+	id1, id2 := genesis1.ID(), genesis2.ID()
+
+	in1a := randInput(t, genesis1, 30)
+	in2 := randInput(t, genesis2, 50)
+	in1b := randInput(t, genesis1, 20)
+	inputs := []*VInput{in1a, in2, in1b}
+
+	recipient := func(amount uint64, interactive bool) *VOutput {
+		return &VOutput{
+			Amount:            amount,
+			Interactive:       interactive,
+			AnchorOutputIndex: 1,
+			ScriptKey:         asset.RandScriptKey(t),
+		}
+	}
+
+	// Asset 1 has change, asset 2 is sent in full to an interactive
+	// recipient and therefore doesn't need a change output.
+	packets, err := SplitByAssetID(inputs, map[asset.ID][]*VOutput{
+		id1: {recipient(40, false)},
+		id2: {recipient(50, true)},
+	}, 0, testParams)
+	require.NoError(t, err)
+	require.Len(t, packets, 2)
+
+	require.Equal(t, []*VInput{in1a, in1b}, packets[0].Inputs)
+	require.Len(t, packets[0].Outputs, 2)
+	changeOut, err := packets[0].SplitRootOutput()
+	require.NoError(t, err)
+	require.EqualValues(t, 10, changeOut.Amount)
+	require.EqualValues(t, 0, changeOut.AnchorOutputIndex)
+	require.Equal(t, asset.NUMSScriptKey, changeOut.ScriptKey)
+	require.False(t, changeOut.Interactive)
+
+	require.Equal(t, []*VInput{in2}, packets[1].Inputs)
+	require.Len(t, packets[1].Outputs, 1)
+	require.False(t, packets[1].HasSplitRootOutput())
+
+	// A non-interactive full value send needs a zero value change output
+	// to carry the tombstone.
+	packets, err = SplitByAssetID(inputs, map[asset.ID][]*VOutput{
+		id1: {recipient(50, false)},
+		id2: {recipient(20, true), recipient(30, true)},
+	}, 0, testParams)
+	require.NoError(t, err)
+	changeOut, err = packets[0].SplitRootOutput()
+	require.NoError(t, err)
+	require.EqualValues(t, 0, changeOut.Amount)
+	require.False(t, packets[1].HasSplitRootOutput())
+
+	// Spending more than the inputs of an asset ID is not allowed.
+	_, err = SplitByAssetID(inputs, map[asset.ID][]*VOutput{
+		id1: {recipient(51, false)},
+		id2: {recipient(50, false)},
+	}, 0, testParams)
+	require.ErrorContains(t, err, "exceed inputs")
+
+	// Every asset ID that is spent needs a recipient.
+	_, err = SplitByAssetID(inputs, map[asset.ID][]*VOutput{
+		id1: {recipient(50, false)},
+	}, 0, testParams)
+	require.ErrorContains(t, err, "no outputs for asset ID")
+
+	// And every recipient needs inputs.
+	_, err = SplitByAssetID([]*VInput{in2}, map[asset.ID][]*VOutput{
+		id1: {recipient(50, false)},
+		id2: {recipient(50, false)},
+	}, 0, testParams)
+	require.ErrorContains(t, err, "no inputs selected")
+
+	// Change outputs can't be specified by the caller.
+	splitRoot := recipient(10, false)
+	splitRoot.Type = TypeSplitRoot
+	_, err = SplitByAssetID([]*VInput{in2}, map[asset.ID][]*VOutput{
+		id2: {splitRoot},
+	}, 0, testParams)
+	require.ErrorContains(t, err, "change outputs are added")
+
+	// The same input can't be spent twice.
+	_, err = SplitByAssetID([]*VInput{in2, in2}, map[asset.ID][]*VOutput{
+		id2: {recipient(50, false)},
+	}, 0, testParams)
+	require.ErrorContains(t, err, "duplicate")
+}
+
+// TestFromAddressesAmountless tests that an address without an amount can only
+// be sent to once the sender chose the amount.
+func TestFromAddressesAmountless(t *testing.T) {
+	t.Parallel()
+
+	addr, _, _ := address.RandAddr(t, testParams)
+	for addr.AssetType() != asset.Normal {
+		addr, _, _ = address.RandAddr(t, testParams)
+	}
+	addr.Amount = 0
+
+	_, err := FromAddresses([]*address.Tap{addr.Tap}, 1)
+	require.Error(t, err)
+
+	sendAddr, err := addr.Tap.ForAmount(42)
+	require.NoError(t, err)
+
+	pkt, err := FromAddresses([]*address.Tap{sendAddr}, 1)
+	require.NoError(t, err)
+	require.Len(t, pkt.Outputs, 2)
+	require.EqualValues(t, 42, pkt.Outputs[1].Amount)
+}
@@ -2,9 +2,11 @@ package tappsbt
 
 import (
 	"bytes"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcec/v2/schnorr"
@@ -14,6 +16,7 @@ import (
 	"github.com/btcsuite/btcd/wire"
 	"github.com/lightninglabs/taproot-assets/address"
 	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightninglabs/taproot-assets/chanutils"
 	"github.com/lightninglabs/taproot-assets/commitment"
 	"github.com/lightningnetwork/lnd/tlv"
 )
@@ -22,6 +25,14 @@ var (
 	// ErrKeyNotFound is returned when a key is not found among the unknown
 	// fields of a packet.
 	ErrKeyNotFound = errors.New("tappsbt: key not found")
+
+	// ErrMalformedPacket is returned when a packet can't be parsed because
+	// it is malformed.
+	ErrMalformedPacket = errors.New("tappsbt: malformed packet")
+
+	// ErrUnknownVersion is returned when a packet has a version that is
+	// not known to this package.
+	ErrUnknownVersion = errors.New("tappsbt: unknown packet version")
 )
 
 // decoderFunc is a function type for decoding a virtual PSBT item from a byte
@@ -39,7 +50,7 @@ type decoderMapping struct {
 // argument b64 is true, the passed byte slice is decoded from base64 encoding
 // before processing.
 func NewFromRawBytes(r io.Reader, b64 bool) (*VPacket, error) {
-	packet, err := psbt.NewFromRawBytes(r, b64)
+	packet, err := parsePsbt(r, b64)
 	if err != nil {
 		return nil, fmt.Errorf("error decoding PSBT: %w", err)
 	}
@@ -47,13 +58,47 @@ func NewFromRawBytes(r io.Reader, b64 bool) (*VPacket, error) {
 	return NewFromPsbt(packet)
 }
 
+// NewFromBase64 returns a new instance of a VPacket struct decoded from the
+// given base64 encoded string.
+func NewFromBase64(b64 string) (*VPacket, error) {
+	return NewFromRawBytes(strings.NewReader(b64), true)
+}
+
+// NewFromHex returns a new instance of a VPacket struct decoded from the given
+// hex encoded string.
+func NewFromHex(hexStr string) (*VPacket, error) {
+	rawBytes, err := hex.DecodeString(hexStr)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding hex: %w", err)
+	}
+
+	return NewFromRawBytes(bytes.NewReader(rawBytes), false)
+}
+
+// parsePsbt parses a raw PSBT packet. The btcd PSBT parser panics on some
+// malformed inputs (for example a BIP-0032 derivation that is too short), so
+// any such panic is turned into an error to make sure a malformed packet can't
+// crash the daemon.
+func parsePsbt(r io.Reader, b64 bool) (packet *psbt.Packet, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			packet = nil
+			err = fmt.Errorf("%w: %v", ErrMalformedPacket, p)
+		}
+	}()
+
+	return psbt.NewFromRawBytes(r, b64)
+}
+
 // NewFromPsbt returns a new instance of a VPacket struct created by reading the
 // custom fields on the given PSBT packet.
 func NewFromPsbt(packet *psbt.Packet) (*VPacket, error) {
 	// Make sure we have the correct markers for a virtual transaction.
-	if len(packet.Unknowns) != 3 {
-		return nil, fmt.Errorf("expected 3 global unknown fields, "+
-			"got %d", len(packet.Unknowns))
+	// Packets encoded before the version field was added only carry two
+	// global fields, any fields not known to us are passed through.
+	if len(packet.Unknowns) < 2 {
+		return nil, fmt.Errorf("expected at least 2 global unknown "+
+			"fields, got %d", len(packet.Unknowns))
 	}
 
 	// We want an explicit "isVirtual" boolean marker.
@@ -81,13 +126,27 @@ func NewFromPsbt(packet *psbt.Packet) (*VPacket, error) {
 			"params HRP: %w", err)
 	}
 
-	// The version is currently optional, as it's not used anywhere.
-	var version uint8
+	// The version is optional, packets without it are of the initial
+	// version.
+	version := V0
 	versionField, err := findCustomFieldsByKeyPrefix(
 		packet.Unknowns, PsbtKeyTypeGlobalTapPsbtVersion,
 	)
-	if err == nil {
-		version = versionField.Value[0]
+	switch {
+	case err == nil:
+		if len(versionField.Value) != 1 {
+			return nil, fmt.Errorf("%w: invalid version length %d",
+				ErrMalformedPacket, len(versionField.Value))
+		}
+		version = VPacketVersion(versionField.Value[0])
+
+	case errors.Is(err, ErrKeyNotFound):
+
+	default:
+		return nil, fmt.Errorf("error reading version: %w", err)
+	}
+	if version > LatestVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownVersion, version)
 	}
 
 	vPkt := &VPacket{
@@ -95,6 +154,11 @@ func NewFromPsbt(packet *psbt.Packet) (*VPacket, error) {
 		ChainParams: chainParams,
 		Inputs:      make([]*VInput, len(packet.Inputs)),
 		Outputs:     make([]*VOutput, len(packet.Outputs)),
+		Unknowns: unknownFields(
+			packet.Unknowns, PsbtKeyTypeGlobalTapIsVirtualTx,
+			PsbtKeyTypeGlobalTapChainParamsHRP,
+			PsbtKeyTypeGlobalTapPsbtVersion,
+		),
 	}
 
 	for idx := range packet.Inputs {
@@ -171,7 +235,10 @@ func (i *VInput) decode(pIn psbt.PInput) error {
 		decoder: tlvDecoder(&i.proof, tlv.DVarBytes),
 	}}
 
+	knownKeys := make([][]byte, len(mapping))
 	for idx := range mapping {
+		knownKeys[idx] = mapping[idx].key
+
 		unknown, err := findCustomFieldsByKeyPrefix(
 			i.Unknowns, mapping[idx].key,
 		)
@@ -202,7 +269,10 @@ func (i *VInput) decode(pIn psbt.PInput) error {
 	if err := i.deserializeScriptKey(); err != nil {
 		return err
 	}
-	i.Unknowns = nil
+
+	// We only keep the fields we don't know about, so they can be passed
+	// through when re-encoding the input.
+	i.Unknowns = unknownFields(i.Unknowns, knownKeys...)
 
 	return nil
 }
@@ -262,9 +332,17 @@ func (o *VOutput) decode(pOut psbt.POutput, txOut *wire.TxOut) error {
 			&o.AnchorOutputTapscriptSibling,
 			commitment.TapscriptPreimageDecoder,
 		),
+	}, {
+		key: PsbtKeyTypeOutputTapProofDeliveryAddress,
+		decoder: tlvDecoder(
+			&o.ProofDeliveryAddress, address.UrlDecoder,
+		),
 	}}
 
+	knownKeys := make([][]byte, len(mapping))
 	for idx := range mapping {
+		knownKeys[idx] = mapping[idx].key
+
 		unknown, err := findCustomFieldsByKeyPrefix(
 			pOut.Unknowns, mapping[idx].key,
 		)
@@ -284,6 +362,7 @@ func (o *VOutput) decode(pOut psbt.POutput, txOut *wire.TxOut) error {
 	// For some fields an intermediate step was required, copy them over
 	// into their target type now.
 	o.AnchorOutputIndex = uint32(anchorOutputIndex)
+	o.Unknowns = unknownFields(pOut.Unknowns, knownKeys...)
 
 	return nil
 }
@@ -411,6 +490,24 @@ func findCustomFieldsByKeyPrefix(customFields []*customPsbtField,
 		ErrKeyNotFound, keyPrefix)
 }
 
+// unknownFields returns all custom fields that don't match any of the given
+// known key prefixes, or nil if there are none.
+func unknownFields(customFields []*customPsbtField,
+	knownKeys ...[]byte) []*customPsbtField {
+
+	var unknowns []*customPsbtField
+	for _, customField := range customFields {
+		isKnown := chanutils.Any(knownKeys, func(key []byte) bool {
+			return bytes.HasPrefix(customField.Key, key)
+		})
+		if !isKnown {
+			unknowns = append(unknowns, customField)
+		}
+	}
+
+	return unknowns
+}
+
 // vOutputTypeDecoder is a TLV decoder function that decodes from the given
 // reader into a VOutputType.
 func vOutputTypeDecoder(r io.Reader, val any, buf *[8]byte, l uint64) error {
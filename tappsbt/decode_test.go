@@ -3,13 +3,19 @@ package tappsbt
 import (
 	"bytes"
 	"encoding/hex"
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil/psbt"
 	"github.com/lightninglabs/taproot-assets/address"
 	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightninglabs/taproot-assets/internal/test"
+	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/stretchr/testify/require"
 )
 
@@ -21,6 +27,8 @@ func assertEqualPackets(t *testing.T, expected, actual *VPacket) {
 		require.Fail(t, "ChainParams not equal")
 	}
 
+	require.Equal(t, expected.Unknowns, actual.Unknowns)
+
 	require.Len(t, expected.Inputs, len(actual.Inputs))
 	for idx := range expected.Inputs {
 		e := expected.Inputs[idx]
@@ -118,6 +126,11 @@ func TestDecodeBase64(t *testing.T) {
 	require.NoError(t, err)
 
 	require.Equal(t, string(fileContent), reEncoded)
+
+	// The string based helper must yield the same packet.
+	fromString, err := NewFromBase64(string(fileContent))
+	require.NoError(t, err)
+	assertEqualPackets(t, packet, fromString)
 }
 
 // TestDecodeHex tests the decoding of a virtual packet from a hex string.
@@ -135,4 +148,278 @@ func TestDecodeHex(t *testing.T) {
 	require.NoError(t, err)
 
 	require.Len(t, packet.Outputs, 2)
+
+	// The string based helper must yield the same packet, which must
+	// re-encode to the exact same hex string.
+	fromString, err := NewFromHex(string(fileContent))
+	require.NoError(t, err)
+	assertEqualPackets(t, packet, fromString)
+
+	reEncoded, err := fromString.HexEncode()
+	require.NoError(t, err)
+	require.Equal(t, string(fileContent), reEncoded)
+
+	_, err = NewFromHex("not hex")
+	require.ErrorContains(t, err, "error decoding hex")
+}
+
+// withGlobalVersion encodes the given packet and either removes the global
+// version field or replaces its value.
+func withGlobalVersion(t *testing.T, vPkt *VPacket,
+	version []byte) *psbt.Packet {
+
+	packet, err := vPkt.EncodeAsPsbt()
+	require.NoError(t, err)
+
+	unknowns := make([]*customPsbtField, 0, len(packet.Unknowns))
+	for _, field := range packet.Unknowns {
+		if bytes.Equal(field.Key, PsbtKeyTypeGlobalTapPsbtVersion) {
+			if version == nil {
+				continue
+			}
+			field.Value = version
+		}
+
+		unknowns = append(unknowns, field)
+	}
+	packet.Unknowns = unknowns
+
+	return packet
+}
+
+// TestPacketVersion tests that packets without a version field are decoded as
+// the initial version and that unknown versions are rejected.
+func TestPacketVersion(t *testing.T) {
+	t.Parallel()
+
+	vPkt := RandPacket(t)
+	require.Equal(t, V0, vPkt.Version)
+
+	// A packet encoded before the version field was introduced is decoded
+	// as the initial version.
+	decoded, err := NewFromPsbt(withGlobalVersion(t, vPkt, nil))
+	require.NoError(t, err)
+	require.Equal(t, V0, decoded.Version)
+	assertEqualPackets(t, vPkt, decoded)
+
+	// A packet with an explicit version is decoded the same way.
+	decoded, err = NewFromPsbt(withGlobalVersion(t, vPkt, []byte{0}))
+	require.NoError(t, err)
+	require.Equal(t, V0, decoded.Version)
+
+	// A version we don't know about must be rejected.
+	unknownVersion := []byte{byte(LatestVersion) + 1}
+	_, err = NewFromPsbt(withGlobalVersion(t, vPkt, unknownVersion))
+	require.ErrorIs(t, err, ErrUnknownVersion)
+
+	_, err = NewFromPsbt(withGlobalVersion(t, vPkt, []byte{0, 0}))
+	require.ErrorIs(t, err, ErrMalformedPacket)
+
+	// We also refuse to encode a packet with an unknown version.
+	vPkt.Version = LatestVersion + 1
+	_, err = vPkt.EncodeAsPsbt()
+	require.ErrorIs(t, err, ErrUnknownVersion)
+}
+
+// FuzzPacketDecode tests that decoding arbitrary virtual packets doesn't
+// panic.
+func FuzzPacketDecode(f *testing.F) {
+	fileContent, err := os.ReadFile(filepath.Join("testdata", "psbt.hex"))
+	require.NoError(f, err)
+	rawBytes, err := hex.DecodeString(string(fileContent))
+	require.NoError(f, err)
+
+	f.Add(rawBytes)
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		_, _ = NewFromRawBytes(bytes.NewReader(data), false)
+	})
+}
+
+// generatedTestVectorName is the name of the test vector file that is
+// generated by TestPacketTestVectors.
+const generatedTestVectorName = "psbt_encoding_generated.json"
+
+// vectorInput is the expected content of a virtual input in a test vector.
+type vectorInput struct {
+	PrevOutPoint  string       `json:"prev_out_point"`
+	PrevAssetID   string       `json:"prev_asset_id"`
+	PrevScriptKey string       `json:"prev_script_key"`
+	Asset         *asset.Asset `json:"asset"`
+}
+
+// vectorOutput is the expected content of a virtual output in a test vector.
+type vectorOutput struct {
+	Amount                  uint64       `json:"amount,string"`
+	Type                    VOutputType  `json:"type"`
+	Interactive             bool         `json:"interactive"`
+	AnchorOutputIndex       uint32       `json:"anchor_output_index"`
+	AnchorOutputInternalKey string       `json:"anchor_output_internal_key"`
+	ScriptKey               string       `json:"script_key"`
+	Asset                   *asset.Asset `json:"asset"`
+}
+
+// validPacketTestCase is a test vector of a base64 encoded virtual packet and
+// its expected content.
+type validPacketTestCase struct {
+	Packet         string          `json:"packet"`
+	ChainParamsHRP string          `json:"chain_params_hrp"`
+	Inputs         []*vectorInput  `json:"inputs"`
+	Outputs        []*vectorOutput `json:"outputs"`
+	Comment        string          `json:"comment"`
+}
+
+// errorPacketTestCase is a test vector of a base64 encoded virtual packet that
+// must be rejected when decoding.
+type errorPacketTestCase struct {
+	Packet  string `json:"packet"`
+	Error   string `json:"error"`
+	Comment string `json:"comment"`
+}
+
+// packetTestVectors are the test vectors for the encoding of virtual packets.
+type packetTestVectors = test.TestVectors[
+	validPacketTestCase, errorPacketTestCase,
+]
+
+// serializedKey returns the hex encoded, compressed public key or an empty
+// string if the key is nil.
+func serializedKey(key *btcec.PublicKey) string {
+	if key == nil {
+		return ""
+	}
+
+	return hex.EncodeToString(key.SerializeCompressed())
+}
+
+// newValidPacketTestCase creates a test vector from the given packet.
+func newValidPacketTestCase(t *testing.T, vPkt *VPacket,
+	comment string) *validPacketTestCase {
+
+	b64, err := vPkt.B64Encode()
+	require.NoError(t, err)
+
+	tc := &validPacketTestCase{
+		Packet:         b64,
+		ChainParamsHRP: vPkt.ChainParams.TapHRP,
+		Comment:        comment,
+	}
+	for _, vIn := range vPkt.Inputs {
+		tc.Inputs = append(tc.Inputs, &vectorInput{
+			PrevOutPoint: vIn.PrevID.OutPoint.String(),
+			PrevAssetID:  vIn.PrevID.ID.String(),
+			PrevScriptKey: hex.EncodeToString(
+				vIn.PrevID.ScriptKey[:],
+			),
+			Asset: vIn.Asset(),
+		})
+	}
+	for _, vOut := range vPkt.Outputs {
+		tc.Outputs = append(tc.Outputs, &vectorOutput{
+			Amount:            vOut.Amount,
+			Type:              vOut.Type,
+			Interactive:       vOut.Interactive,
+			AnchorOutputIndex: vOut.AnchorOutputIndex,
+			AnchorOutputInternalKey: serializedKey(
+				vOut.AnchorOutputInternalKey,
+			),
+			ScriptKey: serializedKey(vOut.ScriptKey.PubKey),
+			Asset:     vOut.Asset,
+		})
+	}
+
+	return tc
+}
+
+// TestPacketTestVectors generates the virtual packet encoding test vectors and
+// then asserts that all test vector files in the testdata directory are valid.
+func TestPacketTestVectors(t *testing.T) {
+	t.Parallel()
+
+	// The test data file just contains a random packet from a previous
+	// integration test run.
+	fileContent, err := os.ReadFile(filepath.Join("testdata", "psbt.b64"))
+	require.NoError(t, err)
+	testPacket, err := NewFromRawBytes(bytes.NewBuffer(fileContent), true)
+	require.NoError(t, err)
+
+	var keyBytes [32]byte
+	keyBytes[31] = 1
+	_, pubKey := btcec.PrivKeyFromBytes(keyBytes[:])
+	interactivePacket := ForInteractiveSend(
+		asset.ID{1, 2, 3}, 1000, asset.NewScriptKey(pubKey), 1,
+		keychain.KeyDescriptor{PubKey: pubKey},
+		&address.RegressionNetTap,
+	)
+
+	plainPacket, err := psbt.New(nil, nil, 2, 0, nil)
+	require.NoError(t, err)
+	plainB64, err := plainPacket.B64Encode()
+	require.NoError(t, err)
+
+	futurePacket := withGlobalVersion(
+		t, interactivePacket, []byte{byte(LatestVersion) + 1},
+	)
+	futureB64, err := futurePacket.B64Encode()
+	require.NoError(t, err)
+
+	vectors := &packetTestVectors{
+		ValidTestCases: []*validPacketTestCase{
+			newValidPacketTestCase(
+				t, testPacket, "packet from integration test",
+			),
+			newValidPacketTestCase(
+				t, interactivePacket,
+				"unfunded interactive send",
+			),
+		},
+		ErrorTestCases: []*errorPacketTestCase{{
+			Packet:  plainB64,
+			Error:   "expected at least 2 global unknown fields",
+			Comment: "regular PSBT",
+		}, {
+			Packet:  futureB64,
+			Error:   "unknown packet version",
+			Comment: "unknown packet version",
+		}},
+	}
+
+	test.WriteTestVectors(
+		t, filepath.Join("testdata", generatedTestVectorName), vectors,
+	)
+
+	vectorFiles := test.TestVectorFiles(t, "testdata", "psbt_encoding_")
+	for _, fileName := range vectorFiles {
+		var fileVectors packetTestVectors
+		test.ParseTestVectors(t, fileName, &fileVectors)
+
+		t.Run(fileName, func(t *testing.T) {
+			runPacketTestVectors(t, &fileVectors)
+		})
+	}
+}
+
+// runPacketTestVectors asserts that the given packet test vectors are valid.
+func runPacketTestVectors(t *testing.T, vectors *packetTestVectors) {
+	for _, tc := range vectors.ValidTestCases {
+		vPkt, err := NewFromRawBytes(strings.NewReader(tc.Packet), true)
+		require.NoError(t, err, tc.Comment)
+
+		// Decoding and re-encoding the packet must result in the same
+		// packet with the expected content.
+		decoded := newValidPacketTestCase(t, vPkt, tc.Comment)
+		expectedJSON, err := json.Marshal(tc)
+		require.NoError(t, err, tc.Comment)
+		decodedJSON, err := json.Marshal(decoded)
+		require.NoError(t, err, tc.Comment)
+		require.JSONEq(
+			t, string(expectedJSON), string(decodedJSON),
+			tc.Comment,
+		)
+	}
+
+	for _, tc := range vectors.ErrorTestCases {
+		_, err := NewFromRawBytes(strings.NewReader(tc.Packet), true)
+		require.ErrorContains(t, err, tc.Error, tc.Comment)
+	}
 }
@@ -3,15 +3,18 @@ package tappsbt
 import (
 	"bytes"
 	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"math"
+	"net/url"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcec/v2/schnorr"
 	"github.com/btcsuite/btcd/btcutil/psbt"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
+	"github.com/lightninglabs/taproot-assets/address"
 	"github.com/lightninglabs/taproot-assets/asset"
 	"github.com/lightninglabs/taproot-assets/chanutils"
 	"github.com/lightninglabs/taproot-assets/commitment"
@@ -45,6 +48,10 @@ type encoderMapping struct {
 // EncodeAsPsbt returns the PSBT encoding of the current virtual packet, or an
 // error if the encoding fails.
 func (p *VPacket) EncodeAsPsbt() (*psbt.Packet, error) {
+	if p.Version > LatestVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownVersion, p.Version)
+	}
+
 	unsignedTx := &wire.MsgTx{
 		Version: 2,
 		TxIn:    make([]*wire.TxIn, len(p.Inputs)),
@@ -65,11 +72,23 @@ func (p *VPacket) EncodeAsPsbt() (*psbt.Packet, error) {
 			},
 			{
 				Key:   PsbtKeyTypeGlobalTapPsbtVersion,
-				Value: []byte{p.Version},
+				Value: []byte{byte(p.Version)},
 			},
 		},
 	}
 
+	// Any global fields we don't know about are passed through as they
+	// are, after our own fields.
+	err := checkUnknownFields(
+		p.Unknowns, PsbtKeyTypeGlobalTapIsVirtualTx,
+		PsbtKeyTypeGlobalTapChainParamsHRP,
+		PsbtKeyTypeGlobalTapPsbtVersion,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid global unknowns: %w", err)
+	}
+	packet.Unknowns = append(packet.Unknowns, p.Unknowns...)
+
 	for idx := range p.Inputs {
 		pIn, err := p.Inputs[idx].encode()
 		if err != nil {
@@ -119,9 +138,21 @@ func (p *VPacket) B64Encode() (string, error) {
 	return base64.StdEncoding.EncodeToString(b.Bytes()), nil
 }
 
+// HexEncode returns the hex encoding of the serialization of the current
+// virtual packet, or an error if the encoding fails.
+func (p *VPacket) HexEncode() (string, error) {
+	var b bytes.Buffer
+	if err := p.Serialize(&b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b.Bytes()), nil
+}
+
 // encode encodes the current VInput struct into a PInput and a wire.TxIn.
 func (i *VInput) encode() (psbt.PInput, error) {
 	pIn := i.PInput
+	pIn.Unknowns = nil
 
 	var (
 		prevID      = &i.PrevID
@@ -166,7 +197,10 @@ func (i *VInput) encode() (psbt.PInput, error) {
 		encoder: tlvEncoder(&i.proof, tlv.EVarBytes),
 	}}
 
+	knownKeys := make([][]byte, len(mapping))
 	for idx := range mapping {
+		knownKeys[idx] = mapping[idx].key
+
 		customFields, err := mapping[idx].encoder(mapping[idx].key)
 		if err != nil {
 			return pIn, fmt.Errorf("error encoding input key %x: "+
@@ -178,6 +212,13 @@ func (i *VInput) encode() (psbt.PInput, error) {
 		}
 	}
 
+	// Any fields we don't know about are passed through as they are, after
+	// our own fields.
+	if err := checkUnknownFields(i.Unknowns, knownKeys...); err != nil {
+		return pIn, fmt.Errorf("invalid input unknowns: %w", err)
+	}
+	pIn.Unknowns = append(pIn.Unknowns, i.Unknowns...)
+
 	return pIn, nil
 }
 
@@ -244,9 +285,15 @@ func (o *VOutput) encode(coinType uint32) (psbt.POutput, *wire.TxOut, error) {
 		encoder: tapscriptPreimageEncoder(
 			o.AnchorOutputTapscriptSibling,
 		),
+	}, {
+		key:     PsbtKeyTypeOutputTapProofDeliveryAddress,
+		encoder: urlEncoder(o.ProofDeliveryAddress),
 	}}
 
+	knownKeys := make([][]byte, len(mapping))
 	for idx := range mapping {
+		knownKeys[idx] = mapping[idx].key
+
 		customFields, err := mapping[idx].encoder(mapping[idx].key)
 		if err != nil {
 			return pOut, nil, fmt.Errorf("error encoding input "+
@@ -258,9 +305,31 @@ func (o *VOutput) encode(coinType uint32) (psbt.POutput, *wire.TxOut, error) {
 		}
 	}
 
+	// Any fields we don't know about are passed through as they are, after
+	// our own fields.
+	if err := checkUnknownFields(o.Unknowns, knownKeys...); err != nil {
+		return pOut, nil, fmt.Errorf("invalid output unknowns: %w",
+			err)
+	}
+	pOut.Unknowns = append(pOut.Unknowns, o.Unknowns...)
+
 	return pOut, txOut, nil
 }
 
+// checkUnknownFields makes sure none of the given custom fields that are meant
+// to be passed through clash with any of the given known keys, as that would
+// result in duplicate keys that can't be decoded anymore.
+func checkUnknownFields(customFields []*customPsbtField,
+	knownKeys ...[]byte) error {
+
+	unknowns := unknownFields(customFields, knownKeys...)
+	if len(unknowns) != len(customFields) {
+		return fmt.Errorf("custom field conflicts with known key")
+	}
+
+	return nil
+}
+
 // tlvEncoder returns a function that encodes the given value using the given TLV
 // tlvEncoder.
 func tlvEncoder(val any, enc tlv.Encoder) encoderFunc {
@@ -391,6 +460,18 @@ func tapscriptPreimageEncoder(t *commitment.TapscriptPreimage) encoderFunc {
 	return tlvEncoder(&t, commitment.TapscriptPreimageEncoder)
 }
 
+// urlEncoder returns a function that encodes the given URL as a custom PSBT
+// field.
+func urlEncoder(val *url.URL) encoderFunc {
+	if val == nil {
+		return func(key []byte) ([]*customPsbtField, error) {
+			return nil, nil
+		}
+	}
+
+	return tlvEncoder(&val, address.UrlEncoder)
+}
+
 // payToTaprootScript creates a pk script for a pay-to-taproot output key. We
 // create a copy of the tapscript.PayToTaprootScript function here to avoid a
 // circular dependency.
@@ -19,3 +19,29 @@ func TestEncodeAsPsbt(t *testing.T) {
 
 	require.NotEmpty(t, b64)
 }
+
+// TestEncodeConflictingUnknowns tests that custom fields that would clash with
+// the fields of this package are rejected when encoding a packet.
+func TestEncodeConflictingUnknowns(t *testing.T) {
+	t.Parallel()
+
+	conflicting := []*customPsbtField{{
+		Key:   PsbtKeyTypeGlobalTapChainParamsHRP,
+		Value: []byte("tapbc"),
+	}}
+
+	pkg := RandPacket(t)
+	pkg.Unknowns = conflicting
+	_, err := pkg.EncodeAsPsbt()
+	require.ErrorContains(t, err, "invalid global unknowns")
+
+	pkg = RandPacket(t)
+	pkg.Inputs[0].Unknowns = conflicting
+	_, err = pkg.EncodeAsPsbt()
+	require.ErrorContains(t, err, "invalid input unknowns")
+
+	pkg = RandPacket(t)
+	pkg.Outputs[0].Unknowns = conflicting
+	_, err = pkg.EncodeAsPsbt()
+	require.ErrorContains(t, err, "invalid output unknowns")
+}
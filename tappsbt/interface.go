@@ -3,6 +3,7 @@ package tappsbt
 import (
 	"bytes"
 	"fmt"
+	"net/url"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcutil"
@@ -50,6 +51,7 @@ var (
 	PsbtKeyTypeOutputTapAsset                              = []byte{0x76}
 	PsbtKeyTypeOutputTapSplitAsset                         = []byte{0x77}
 	PsbtKeyTypeOutputTapAnchorTapscriptSibling             = []byte{0x78}
+	PsbtKeyTypeOutputTapProofDeliveryAddress               = []byte{0x79}
 )
 
 // The following keys are used as custom fields on the BTC level anchor
@@ -111,6 +113,23 @@ var (
 	}
 )
 
+// VPacketVersion is the version of the virtual transaction packet format.
+type VPacketVersion uint8
+
+const (
+	// V0 is the initial version of the virtual packet format. Packets that
+	// were encoded before the version field was added to the global
+	// section are decoded as V0.
+	V0 VPacketVersion = 0
+
+	// LatestVersion is the latest version of the virtual packet format
+	// that is known to this package. Packets with a higher version are
+	// rejected, both when encoding and decoding. A new version must be
+	// added above and this constant bumped whenever the encoding of a
+	// packet changes in a way that older decoders can't handle.
+	LatestVersion = V0
+)
+
 // VPacket is a PSBT extension packet for a virtual transaction. It represents
 // the virtual asset state transition as it will be validated by the Taproot
 // Asset VM. Some elements within the virtual packet may refer to on-chain
@@ -142,10 +161,16 @@ type VPacket struct {
 	// encode and decode certain contents of the virtual packet.
 	ChainParams *address.ChainParams
 
-	// Version is the version of the virtual transaction. This is currently
-	// unused but can be used to signal a new version of the virtual PSBT
-	// format in the future.
-	Version uint8
+	// Version is the version of the virtual packet format. It is encoded
+	// in the global section of the PSBT so that decoders can reject
+	// packets with a layout they don't know about.
+	Version VPacketVersion
+
+	// Unknowns are the global key-value pairs of the PSBT that are not
+	// known to this package. They are not interpreted in any way but are
+	// preserved when decoding and re-encoding a packet, which allows other
+	// applications to attach their own data to a virtual packet.
+	Unknowns []*psbt.Unknown
 }
 
 // SetInputAsset sets the input asset that is being spent.
@@ -275,7 +300,9 @@ type Anchor struct {
 // VInput represents an input to a virtual asset state transition transaction.
 type VInput struct {
 	// PInput is the embedded default PSBT input struct that is used for
-	// asset related input data.
+	// asset related input data. After decoding, its Unknowns field only
+	// contains the key-value pairs that are not known to this package.
+	// Those are preserved when re-encoding the input.
 	psbt.PInput
 
 	// PrevID is the asset previous ID of the asset being spent.
@@ -337,7 +364,20 @@ func (i *VInput) deserializeScriptKey() error {
 		return nil
 	}
 
+	// The inputs can carry derivation information for more than one key,
+	// for example if the asset is spent through a script path. The PSBT
+	// encoding sorts the derivations by key, so we need to look up the one
+	// of the internal key instead of relying on the order.
 	bip32Derivation := i.Bip32Derivation[0]
+	for _, derivation := range i.Bip32Derivation {
+		if len(derivation.PubKey) == btcec.PubKeyBytesLenCompressed &&
+			bytes.Equal(derivation.PubKey[1:], i.TaprootInternalKey) {
+
+			bip32Derivation = derivation
+			break
+		}
+	}
+
 	rawKeyDesc, err := KeyDescFromBip32Derivation(bip32Derivation)
 	if err != nil {
 		return fmt.Errorf("error decoding script key derivation info: "+
@@ -376,6 +416,13 @@ const (
 	// from a split or a tombstone from a non-interactive full value send
 	// output, as well as passive assets.
 	TypePassiveSplitRoot VOutputType = 3
+
+	// TypeBurn is an output that burns the asset it carries. The script
+	// key of a burn output must be the provably un-spendable burn key that
+	// is derived from the NUMS key and the first input of the virtual
+	// transaction. A burn output can't be interactive, as there is no
+	// receiver that could be aware of the transfer.
+	TypeBurn VOutputType = 4
 )
 
 // IsSplitRoot returns true if the output type is a split root, indicating that
@@ -425,6 +472,9 @@ func (t VOutputType) String() string {
 	case TypePassiveSplitRoot:
 		return "passive_split_root"
 
+	case TypeBurn:
+		return "burn"
+
 	default:
 		return fmt.Sprintf("unknown <%d>", t)
 	}
@@ -493,6 +543,16 @@ type VOutput struct {
 	// serialized, this will be stored in the TaprootInternalKey and
 	// TaprootDerivationPath fields of the PSBT output.
 	ScriptKey asset.ScriptKey
+
+	// ProofDeliveryAddress is the address of the proof courier that should
+	// be used to deliver the proof of this output to the receiver. If this
+	// is nil, the sender's default proof courier is used.
+	ProofDeliveryAddress *url.URL
+
+	// Unknowns are the key-value pairs of the PSBT output that are not
+	// known to this package. They are not interpreted in any way but are
+	// preserved when decoding and re-encoding the output.
+	Unknowns []*psbt.Unknown
 }
 
 // SplitLocator creates a split locator from the output. The asset ID is passed
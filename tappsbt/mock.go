@@ -1,6 +1,7 @@
 package tappsbt
 
 import (
+	"net/url"
 	"testing"
 
 	"github.com/btcsuite/btcd/btcutil/psbt"
@@ -10,6 +11,7 @@ import (
 	"github.com/lightninglabs/taproot-assets/commitment"
 	"github.com/lightninglabs/taproot-assets/internal/test"
 	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/stretchr/testify/require"
 )
 
 var (
@@ -54,6 +56,17 @@ func RandPacket(t testing.TB) *VPacket {
 	testPreimage2 := commitment.NewPreimageFromBranch(
 		txscript.NewTapBranch(leaf1, leaf1),
 	)
+	courierAddress, err := url.ParseRequestURI("hashmail://localhost:10009")
+	require.NoError(t, err)
+
+	// Unknown key-value pairs, for example proprietary fields added by
+	// another application, must be passed through.
+	proprietary := func(subType byte) []*psbt.Unknown {
+		return []*psbt.Unknown{{
+			Key:   []byte{0xfc, 0x03, 'a', 'p', 'p', subType},
+			Value: []byte("custom data"),
+		}}
+	}
 
 	vPacket := &VPacket{
 		Inputs: []*VInput{{
@@ -72,6 +85,9 @@ func RandPacket(t testing.TB) *VPacket {
 				Bip32Derivation:   bip32Derivations,
 				TrBip32Derivation: trBip32Derivations,
 			},
+			PInput: psbt.PInput{
+				Unknowns: proprietary(0x01),
+			},
 		}, {
 			// Empty input.
 		}},
@@ -87,6 +103,8 @@ func RandPacket(t testing.TB) *VPacket {
 			ScriptKey:                          testOutputAsset.ScriptKey,
 			SplitAsset:                         testOutputAsset,
 			AnchorOutputTapscriptSibling:       testPreimage1,
+			ProofDeliveryAddress:               courierAddress,
+			Unknowns:                           proprietary(0x02),
 		}, {
 			Amount: 345,
 			Type:   TypeSplitRoot,
@@ -101,6 +119,7 @@ func RandPacket(t testing.TB) *VPacket {
 			AnchorOutputTapscriptSibling:       testPreimage2,
 		}},
 		ChainParams: testParams,
+		Unknowns:    proprietary(0x03),
 	}
 	vPacket.SetInputAsset(0, testAsset, []byte("this is a proof"))
 
@@ -0,0 +1,191 @@
+package tappsbt
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"sort"
+
+	"github.com/lightninglabs/taproot-assets/asset"
+)
+
+var (
+	// ErrNoInputs is returned when a packet doesn't have any inputs.
+	ErrNoInputs = errors.New("tappsbt: packet has no inputs")
+
+	// ErrNoOutputs is returned when a packet doesn't have any outputs.
+	ErrNoOutputs = errors.New("tappsbt: packet has no outputs")
+
+	// ErrMissingInputAsset is returned when the asset of an input is not
+	// set, which means the packet can't be validated or signed.
+	ErrMissingInputAsset = errors.New("tappsbt: input asset not set")
+
+	// ErrMixedAssetIDs is returned when the inputs of a packet spend more
+	// than one asset ID or an input's asset doesn't match its previous ID.
+	ErrMixedAssetIDs = errors.New("tappsbt: inputs must spend a single " +
+		"asset ID")
+
+	// ErrUnsupportedSigHashType is returned when an input specifies a
+	// sighash type that can't be used to sign a virtual transaction.
+	ErrUnsupportedSigHashType = errors.New("tappsbt: unsupported input " +
+		"sighash type")
+
+	// ErrInvalidInteractiveFlag is returned when the interactive flag of an
+	// output is not consistent with its type or the other outputs.
+	ErrInvalidInteractiveFlag = errors.New("tappsbt: invalid interactive " +
+		"flag")
+
+	// ErrMultipleSplitRoots is returned when a packet has more than one
+	// split root output.
+	ErrMultipleSplitRoots = errors.New("tappsbt: multiple split root " +
+		"outputs")
+
+	// ErrMissingSplitRoot is returned when a packet needs a split root
+	// output but doesn't have one.
+	ErrMissingSplitRoot = errors.New("tappsbt: missing split root output")
+
+	// ErrNonContiguousAnchorOutputs is returned when the anchor output
+	// indexes used by the outputs of a packet have gaps.
+	ErrNonContiguousAnchorOutputs = errors.New("tappsbt: anchor output " +
+		"indexes not contiguous")
+
+	// ErrInvalidBurnKey is returned when a burn output doesn't use the burn
+	// key that is derived from the first input of the packet.
+	ErrInvalidBurnKey = errors.New("tappsbt: burn output must use burn " +
+		"script key")
+
+	// ErrAmountMismatch is returned when the output amounts of a packet
+	// don't add up to the input amounts.
+	ErrAmountMismatch = errors.New("tappsbt: output amounts don't match " +
+		"input amounts")
+)
+
+// Validate checks the structural invariants of the virtual packet. The inputs
+// must all spend the same asset ID with a supported sighash type and the output
+// amounts must add up to the total input amount. Burn outputs must use the burn
+// key of the first input. A packet needs exactly one split root output, unless
+// it is a full value send to a single interactive recipient. The anchor output
+// indexes used by the outputs must form a contiguous range, multiple outputs
+// can share the same anchor output though.
+func (p *VPacket) Validate() error {
+	if len(p.Inputs) == 0 {
+		return ErrNoInputs
+	}
+	if len(p.Outputs) == 0 {
+		return ErrNoOutputs
+	}
+
+	var (
+		assetID  = p.Inputs[0].PrevID.ID
+		inputSum uint64
+		carry    uint64
+	)
+	for idx := range p.Inputs {
+		vIn := p.Inputs[idx]
+		if vIn.Asset() == nil {
+			return fmt.Errorf("%w: input %d", ErrMissingInputAsset,
+				idx)
+		}
+
+		if vIn.PrevID.ID != assetID || vIn.Asset().ID() != assetID {
+			return fmt.Errorf("%w: input %d has asset ID %v, "+
+				"expected %v", ErrMixedAssetIDs, idx,
+				vIn.Asset().ID(), assetID)
+		}
+
+		if !asset.IsSupportedSigHashType(vIn.SighashType) {
+			return fmt.Errorf("%w: input %d has sighash type %v",
+				ErrUnsupportedSigHashType, idx, vIn.SighashType)
+		}
+
+		inputSum, carry = bits.Add64(inputSum, vIn.Asset().Amount, 0)
+		if carry != 0 {
+			return fmt.Errorf("total input amount overflow")
+		}
+	}
+
+	var (
+		outputSum       uint64
+		splitRoots      int
+		recipients      []*VOutput
+		anchorIndexes   = make(map[uint32]struct{})
+		sortedAnchorIdx []uint32
+	)
+	for idx := range p.Outputs {
+		vOut := p.Outputs[idx]
+
+		if vOut.Interactive && !vOut.Type.CanBeInteractive() {
+			return fmt.Errorf("%w: output %d of type %v cannot be "+
+				"interactive", ErrInvalidInteractiveFlag, idx,
+				vOut.Type)
+		}
+
+		// A burn output must commit to the inputs being burned.
+		if vOut.Type == TypeBurn {
+			burnKey := asset.DeriveBurnKey(p.Inputs[0].PrevID)
+			if vOut.ScriptKey.PubKey == nil ||
+				!burnKey.IsEqual(vOut.ScriptKey.PubKey) {
+
+				return fmt.Errorf("%w: output %d",
+					ErrInvalidBurnKey, idx)
+			}
+		}
+
+		switch {
+		case vOut.Type.IsSplitRoot():
+			splitRoots++
+
+		case vOut.Type != TypePassiveAssetsOnly:
+			recipients = append(recipients, vOut)
+		}
+
+		outputSum, carry = bits.Add64(outputSum, vOut.Amount, 0)
+		if carry != 0 {
+			return fmt.Errorf("total output amount overflow")
+		}
+
+		if _, ok := anchorIndexes[vOut.AnchorOutputIndex]; !ok {
+			anchorIndexes[vOut.AnchorOutputIndex] = struct{}{}
+			sortedAnchorIdx = append(
+				sortedAnchorIdx, vOut.AnchorOutputIndex,
+			)
+		}
+	}
+
+	if outputSum != inputSum {
+		return fmt.Errorf("%w: inputs %d, outputs %d",
+			ErrAmountMismatch, inputSum, outputSum)
+	}
+
+	// Only a full value send to a single interactive recipient can be done
+	// without a split. Every other transfer needs exactly one split root
+	// that carries the change or the tombstone of the split.
+	if splitRoots > 1 {
+		return fmt.Errorf("%w: got %d", ErrMultipleSplitRoots,
+			splitRoots)
+	}
+	if splitRoots == 0 {
+		switch {
+		case len(recipients) == 1 && !recipients[0].Interactive:
+			return fmt.Errorf("%w: single output must be "+
+				"interactive", ErrInvalidInteractiveFlag)
+
+		case len(recipients) != 1:
+			return fmt.Errorf("%w: %d recipient outputs",
+				ErrMissingSplitRoot, len(recipients))
+		}
+	}
+
+	sort.Slice(sortedAnchorIdx, func(i, j int) bool {
+		return sortedAnchorIdx[i] < sortedAnchorIdx[j]
+	})
+	for i := 1; i < len(sortedAnchorIdx); i++ {
+		if sortedAnchorIdx[i] != sortedAnchorIdx[i-1]+1 {
+			return fmt.Errorf("%w: gap between index %d and %d",
+				ErrNonContiguousAnchorOutputs,
+				sortedAnchorIdx[i-1], sortedAnchorIdx[i])
+		}
+	}
+
+	return nil
+}
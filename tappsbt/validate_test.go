@@ -0,0 +1,198 @@
+package tappsbt
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/stretchr/testify/require"
+)
+
+// TestValidate tests that the structural invariants of a virtual packet are
+// enforced.
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	genesis := asset.RandGenesis(t, asset.Normal)
+	otherGenesis := asset.RandGenesis(t, asset.Normal)
+
+	output := func(amount uint64, typ VOutputType, interactive bool,
+		anchorIdx uint32) *VOutput {
+
+		return &VOutput{
+			Amount:            amount,
+			Type:              typ,
+			Interactive:       interactive,
+			AnchorOutputIndex: anchorIdx,
+			ScriptKey:         asset.RandScriptKey(t),
+		}
+	}
+	packet := func(inputs []*VInput, outputs ...*VOutput) *VPacket {
+		return &VPacket{
+			Inputs:      inputs,
+			Outputs:     outputs,
+			ChainParams: testParams,
+		}
+	}
+	inputs := func() []*VInput {
+		return []*VInput{
+			randInput(t, genesis, 30), randInput(t, genesis, 20),
+		}
+	}
+
+	sigHashInputs := func(sigHashType txscript.SigHashType) []*VInput {
+		vInputs := inputs()
+		for _, vIn := range vInputs {
+			vIn.SighashType = sigHashType
+		}
+
+		return vInputs
+	}
+
+	burnInputs := inputs()
+	burnOutput := func(interactive bool) *VOutput {
+		vOut := output(40, TypeBurn, interactive, 0)
+		vOut.ScriptKey = asset.NewBurnScriptKey(burnInputs[0].PrevID)
+
+		return vOut
+	}
+
+	testCases := []struct {
+		name string
+		pkt  *VPacket
+		err  error
+	}{{
+		name: "split with change",
+		pkt: packet(
+			inputs(), output(10, TypeSplitRoot, false, 0),
+			output(40, TypeSimple, false, 1),
+		),
+	}, {
+		name: "interactive full value send",
+		pkt: packet(
+			inputs()[:1], output(30, TypeSimple, true, 3),
+		),
+	}, {
+		name: "shared anchor output",
+		pkt: packet(
+			inputs(), output(0, TypeSplitRoot, false, 0),
+			output(25, TypeSimple, false, 1),
+			output(25, TypeSimple, true, 1),
+		),
+	}, {
+		name: "anyone can pay inputs",
+		pkt: packet(
+			sigHashInputs(
+				txscript.SigHashSingle|
+					txscript.SigHashAnyOneCanPay,
+			),
+			output(10, TypeSplitRoot, false, 0),
+			output(40, TypeSimple, false, 1),
+		),
+	}, {
+		name: "burn with change",
+		pkt: packet(
+			burnInputs, burnOutput(false),
+			output(10, TypeSplitRoot, false, 1),
+		),
+	}, {
+		name: "burn with wrong key",
+		pkt: packet(
+			burnInputs, output(40, TypeBurn, false, 0),
+			output(10, TypeSplitRoot, false, 1),
+		),
+		err: ErrInvalidBurnKey,
+	}, {
+		name: "interactive burn",
+		pkt: packet(
+			burnInputs, burnOutput(true),
+			output(10, TypeSplitRoot, false, 1),
+		),
+		err: ErrInvalidInteractiveFlag,
+	}, {
+		name: "no inputs",
+		pkt:  packet(nil, output(30, TypeSimple, true, 0)),
+		err:  ErrNoInputs,
+	}, {
+		name: "no outputs",
+		pkt:  packet(inputs()),
+		err:  ErrNoOutputs,
+	}, {
+		name: "missing input asset",
+		pkt: packet(
+			[]*VInput{{}}, output(30, TypeSimple, true, 0),
+		),
+		err: ErrMissingInputAsset,
+	}, {
+		name: "unsupported sighash type",
+		pkt: packet(
+			sigHashInputs(txscript.SigHashNone),
+			output(10, TypeSplitRoot, false, 0),
+			output(40, TypeSimple, false, 1),
+		),
+		err: ErrUnsupportedSigHashType,
+	}, {
+		name: "mixed asset IDs",
+		pkt: packet(
+			append(inputs(), randInput(t, otherGenesis, 5)),
+			output(55, TypeSimple, true, 0),
+		),
+		err: ErrMixedAssetIDs,
+	}, {
+		name: "amount mismatch",
+		pkt: packet(
+			inputs(), output(10, TypeSplitRoot, false, 0),
+			output(41, TypeSimple, false, 1),
+		),
+		err: ErrAmountMismatch,
+	}, {
+		name: "multiple split roots",
+		pkt: packet(
+			inputs(), output(10, TypeSplitRoot, false, 0),
+			output(10, TypePassiveSplitRoot, false, 0),
+			output(30, TypeSimple, false, 1),
+		),
+		err: ErrMultipleSplitRoots,
+	}, {
+		name: "missing split root",
+		pkt: packet(
+			inputs(), output(10, TypeSimple, true, 0),
+			output(40, TypeSimple, true, 1),
+		),
+		err: ErrMissingSplitRoot,
+	}, {
+		name: "non-interactive single output",
+		pkt: packet(
+			inputs(), output(50, TypeSimple, false, 0),
+		),
+		err: ErrInvalidInteractiveFlag,
+	}, {
+		name: "unknown interactive output type",
+		pkt: packet(
+			inputs(), output(10, TypeSplitRoot, false, 0),
+			output(40, VOutputType(99), true, 1),
+		),
+		err: ErrInvalidInteractiveFlag,
+	}, {
+		name: "anchor output gap",
+		pkt: packet(
+			inputs(), output(10, TypeSplitRoot, false, 0),
+			output(40, TypeSimple, false, 2),
+		),
+		err: ErrNonContiguousAnchorOutputs,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.pkt.Validate()
+			if tc.err == nil {
+				require.NoError(t, err)
+				return
+			}
+
+			require.ErrorIs(t, err, tc.err)
+		})
+	}
+}
@@ -50,6 +50,49 @@ type AssetWalletClient interface {
 	// VerifyAssetOwnership verifies the asset ownership proof embedded in the
 	// given transition proof of an asset and returns true if the proof is valid.
 	VerifyAssetOwnership(ctx context.Context, in *VerifyAssetOwnershipRequest, opts ...grpc.CallOption) (*VerifyAssetOwnershipResponse, error)
+	// PrepareInteractiveReceive is called on the receiving node of an interactive
+	// transfer. It validates the requested transfer and derives a new script key
+	// and anchor internal key the sender should send the asset to. Both keys are
+	// stored in the database to make sure they are identified as local keys later
+	// on when importing the proof of the transfer.
+	PrepareInteractiveReceive(ctx context.Context, in *PrepareInteractiveReceiveRequest, opts ...grpc.CallOption) (*PrepareInteractiveReceiveResponse, error)
+	// VerifyInteractiveReceive is called on the receiving node of an interactive
+	// transfer to countersign the virtual PSBT signed by the sender, before the
+	// sender anchors it on chain. It makes sure the packet pays the keys derived
+	// by PrepareInteractiveReceive, is signed correctly and doesn't create a
+	// tombstone output for a full-value transfer.
+	VerifyInteractiveReceive(ctx context.Context, in *VerifyInteractiveReceiveRequest, opts ...grpc.CallOption) (*VerifyInteractiveReceiveResponse, error)
+	// ComputeVirtualPsbtSigHashes returns the signature hash of a funded virtual
+	// transaction for each of its inputs. This allows the inputs to be signed by
+	// an external signer that holds the script keys, instead of the connected lnd
+	// node. The witnesses can then be added with AddVirtualPsbtWitnesses.
+	ComputeVirtualPsbtSigHashes(ctx context.Context, in *ComputeVirtualPsbtSigHashesRequest, opts ...grpc.CallOption) (*ComputeVirtualPsbtSigHashesResponse, error)
+	// AddVirtualPsbtWitnesses adds the witnesses created by an external signer to
+	// the inputs of a funded virtual transaction. The resulting transfer is
+	// validated with the Taproot Asset VM before the signed packet is returned,
+	// so it can be anchored with AnchorVirtualPsbts.
+	AddVirtualPsbtWitnesses(ctx context.Context, in *AddVirtualPsbtWitnessesRequest, opts ...grpc.CallOption) (*AddVirtualPsbtWitnessesResponse, error)
+	// PrepareAnchorPsbt merges multiple signed virtual transactions into a single
+	// BTC level anchor transaction, just like AnchorVirtualPsbts. But instead of
+	// signing and broadcasting the anchor transaction, the funded anchor PSBT is
+	// returned, so it can be signed by an external wallet, for example a hardware
+	// wallet. The transfer is completed by handing the signed PSBT to
+	// PublishAnchorPsbt. Prepared transfers are only kept in memory and need to
+	// be prepared again after a restart.
+	PrepareAnchorPsbt(ctx context.Context, in *PrepareAnchorPsbtRequest, opts ...grpc.CallOption) (*PrepareAnchorPsbtResponse, error)
+	// PublishAnchorPsbt resumes a transfer prepared with PrepareAnchorPsbt using
+	// the anchor PSBT that was signed by an external wallet. The signed
+	// transaction is verified before the transfer is logged and broadcast.
+	PublishAnchorPsbt(ctx context.Context, in *PublishAnchorPsbtRequest, opts ...grpc.CallOption) (*taprpc.SendAssetResponse, error)
+	// ProveReserves creates a reserves report over all unspent assets owned by
+	// the wallet. For each asset, the report contains its proof file, with the
+	// last proof carrying an ownership proof that is bound to the challenge of
+	// the verifier.
+	ProveReserves(ctx context.Context, in *ProveReservesRequest, opts ...grpc.CallOption) (*ProveReservesResponse, error)
+	// VerifyReserves verifies a reserves report created by ProveReserves against
+	// the given challenge and returns the total reserves it proves. The verifier
+	// still needs to check that the returned anchor outputs are unspent.
+	VerifyReserves(ctx context.Context, in *VerifyReservesRequest, opts ...grpc.CallOption) (*VerifyReservesResponse, error)
 }
 
 type assetWalletClient struct {
@@ -123,6 +166,78 @@ func (c *assetWalletClient) VerifyAssetOwnership(ctx context.Context, in *Verify
 	return out, nil
 }
 
+func (c *assetWalletClient) PrepareInteractiveReceive(ctx context.Context, in *PrepareInteractiveReceiveRequest, opts ...grpc.CallOption) (*PrepareInteractiveReceiveResponse, error) {
+	out := new(PrepareInteractiveReceiveResponse)
+	err := c.cc.Invoke(ctx, "/assetwalletrpc.AssetWallet/PrepareInteractiveReceive", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *assetWalletClient) VerifyInteractiveReceive(ctx context.Context, in *VerifyInteractiveReceiveRequest, opts ...grpc.CallOption) (*VerifyInteractiveReceiveResponse, error) {
+	out := new(VerifyInteractiveReceiveResponse)
+	err := c.cc.Invoke(ctx, "/assetwalletrpc.AssetWallet/VerifyInteractiveReceive", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *assetWalletClient) ComputeVirtualPsbtSigHashes(ctx context.Context, in *ComputeVirtualPsbtSigHashesRequest, opts ...grpc.CallOption) (*ComputeVirtualPsbtSigHashesResponse, error) {
+	out := new(ComputeVirtualPsbtSigHashesResponse)
+	err := c.cc.Invoke(ctx, "/assetwalletrpc.AssetWallet/ComputeVirtualPsbtSigHashes", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *assetWalletClient) AddVirtualPsbtWitnesses(ctx context.Context, in *AddVirtualPsbtWitnessesRequest, opts ...grpc.CallOption) (*AddVirtualPsbtWitnessesResponse, error) {
+	out := new(AddVirtualPsbtWitnessesResponse)
+	err := c.cc.Invoke(ctx, "/assetwalletrpc.AssetWallet/AddVirtualPsbtWitnesses", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *assetWalletClient) PrepareAnchorPsbt(ctx context.Context, in *PrepareAnchorPsbtRequest, opts ...grpc.CallOption) (*PrepareAnchorPsbtResponse, error) {
+	out := new(PrepareAnchorPsbtResponse)
+	err := c.cc.Invoke(ctx, "/assetwalletrpc.AssetWallet/PrepareAnchorPsbt", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *assetWalletClient) PublishAnchorPsbt(ctx context.Context, in *PublishAnchorPsbtRequest, opts ...grpc.CallOption) (*taprpc.SendAssetResponse, error) {
+	out := new(taprpc.SendAssetResponse)
+	err := c.cc.Invoke(ctx, "/assetwalletrpc.AssetWallet/PublishAnchorPsbt", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *assetWalletClient) ProveReserves(ctx context.Context, in *ProveReservesRequest, opts ...grpc.CallOption) (*ProveReservesResponse, error) {
+	out := new(ProveReservesResponse)
+	err := c.cc.Invoke(ctx, "/assetwalletrpc.AssetWallet/ProveReserves", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *assetWalletClient) VerifyReserves(ctx context.Context, in *VerifyReservesRequest, opts ...grpc.CallOption) (*VerifyReservesResponse, error) {
+	out := new(VerifyReservesResponse)
+	err := c.cc.Invoke(ctx, "/assetwalletrpc.AssetWallet/VerifyReserves", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AssetWalletServer is the server API for AssetWallet service.
 // All implementations must embed UnimplementedAssetWalletServer
 // for forward compatibility
@@ -158,6 +273,49 @@ type AssetWalletServer interface {
 	// VerifyAssetOwnership verifies the asset ownership proof embedded in the
 	// given transition proof of an asset and returns true if the proof is valid.
 	VerifyAssetOwnership(context.Context, *VerifyAssetOwnershipRequest) (*VerifyAssetOwnershipResponse, error)
+	// PrepareInteractiveReceive is called on the receiving node of an interactive
+	// transfer. It validates the requested transfer and derives a new script key
+	// and anchor internal key the sender should send the asset to. Both keys are
+	// stored in the database to make sure they are identified as local keys later
+	// on when importing the proof of the transfer.
+	PrepareInteractiveReceive(context.Context, *PrepareInteractiveReceiveRequest) (*PrepareInteractiveReceiveResponse, error)
+	// VerifyInteractiveReceive is called on the receiving node of an interactive
+	// transfer to countersign the virtual PSBT signed by the sender, before the
+	// sender anchors it on chain. It makes sure the packet pays the keys derived
+	// by PrepareInteractiveReceive, is signed correctly and doesn't create a
+	// tombstone output for a full-value transfer.
+	VerifyInteractiveReceive(context.Context, *VerifyInteractiveReceiveRequest) (*VerifyInteractiveReceiveResponse, error)
+	// ComputeVirtualPsbtSigHashes returns the signature hash of a funded virtual
+	// transaction for each of its inputs. This allows the inputs to be signed by
+	// an external signer that holds the script keys, instead of the connected lnd
+	// node. The witnesses can then be added with AddVirtualPsbtWitnesses.
+	ComputeVirtualPsbtSigHashes(context.Context, *ComputeVirtualPsbtSigHashesRequest) (*ComputeVirtualPsbtSigHashesResponse, error)
+	// AddVirtualPsbtWitnesses adds the witnesses created by an external signer to
+	// the inputs of a funded virtual transaction. The resulting transfer is
+	// validated with the Taproot Asset VM before the signed packet is returned,
+	// so it can be anchored with AnchorVirtualPsbts.
+	AddVirtualPsbtWitnesses(context.Context, *AddVirtualPsbtWitnessesRequest) (*AddVirtualPsbtWitnessesResponse, error)
+	// PrepareAnchorPsbt merges multiple signed virtual transactions into a single
+	// BTC level anchor transaction, just like AnchorVirtualPsbts. But instead of
+	// signing and broadcasting the anchor transaction, the funded anchor PSBT is
+	// returned, so it can be signed by an external wallet, for example a hardware
+	// wallet. The transfer is completed by handing the signed PSBT to
+	// PublishAnchorPsbt. Prepared transfers are only kept in memory and need to
+	// be prepared again after a restart.
+	PrepareAnchorPsbt(context.Context, *PrepareAnchorPsbtRequest) (*PrepareAnchorPsbtResponse, error)
+	// PublishAnchorPsbt resumes a transfer prepared with PrepareAnchorPsbt using
+	// the anchor PSBT that was signed by an external wallet. The signed
+	// transaction is verified before the transfer is logged and broadcast.
+	PublishAnchorPsbt(context.Context, *PublishAnchorPsbtRequest) (*taprpc.SendAssetResponse, error)
+	// ProveReserves creates a reserves report over all unspent assets owned by
+	// the wallet. For each asset, the report contains its proof file, with the
+	// last proof carrying an ownership proof that is bound to the challenge of
+	// the verifier.
+	ProveReserves(context.Context, *ProveReservesRequest) (*ProveReservesResponse, error)
+	// VerifyReserves verifies a reserves report created by ProveReserves against
+	// the given challenge and returns the total reserves it proves. The verifier
+	// still needs to check that the returned anchor outputs are unspent.
+	VerifyReserves(context.Context, *VerifyReservesRequest) (*VerifyReservesResponse, error)
 	mustEmbedUnimplementedAssetWalletServer()
 }
 
@@ -186,6 +344,30 @@ func (UnimplementedAssetWalletServer) ProveAssetOwnership(context.Context, *Prov
 func (UnimplementedAssetWalletServer) VerifyAssetOwnership(context.Context, *VerifyAssetOwnershipRequest) (*VerifyAssetOwnershipResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method VerifyAssetOwnership not implemented")
 }
+func (UnimplementedAssetWalletServer) PrepareInteractiveReceive(context.Context, *PrepareInteractiveReceiveRequest) (*PrepareInteractiveReceiveResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PrepareInteractiveReceive not implemented")
+}
+func (UnimplementedAssetWalletServer) VerifyInteractiveReceive(context.Context, *VerifyInteractiveReceiveRequest) (*VerifyInteractiveReceiveResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyInteractiveReceive not implemented")
+}
+func (UnimplementedAssetWalletServer) ComputeVirtualPsbtSigHashes(context.Context, *ComputeVirtualPsbtSigHashesRequest) (*ComputeVirtualPsbtSigHashesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ComputeVirtualPsbtSigHashes not implemented")
+}
+func (UnimplementedAssetWalletServer) AddVirtualPsbtWitnesses(context.Context, *AddVirtualPsbtWitnessesRequest) (*AddVirtualPsbtWitnessesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddVirtualPsbtWitnesses not implemented")
+}
+func (UnimplementedAssetWalletServer) PrepareAnchorPsbt(context.Context, *PrepareAnchorPsbtRequest) (*PrepareAnchorPsbtResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PrepareAnchorPsbt not implemented")
+}
+func (UnimplementedAssetWalletServer) PublishAnchorPsbt(context.Context, *PublishAnchorPsbtRequest) (*taprpc.SendAssetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PublishAnchorPsbt not implemented")
+}
+func (UnimplementedAssetWalletServer) ProveReserves(context.Context, *ProveReservesRequest) (*ProveReservesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ProveReserves not implemented")
+}
+func (UnimplementedAssetWalletServer) VerifyReserves(context.Context, *VerifyReservesRequest) (*VerifyReservesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyReserves not implemented")
+}
 func (UnimplementedAssetWalletServer) mustEmbedUnimplementedAssetWalletServer() {}
 
 // UnsafeAssetWalletServer may be embedded to opt out of forward compatibility for this service.
@@ -325,6 +507,150 @@ func _AssetWallet_VerifyAssetOwnership_Handler(srv interface{}, ctx context.Cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AssetWallet_PrepareInteractiveReceive_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PrepareInteractiveReceiveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AssetWalletServer).PrepareInteractiveReceive(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/assetwalletrpc.AssetWallet/PrepareInteractiveReceive",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AssetWalletServer).PrepareInteractiveReceive(ctx, req.(*PrepareInteractiveReceiveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AssetWallet_VerifyInteractiveReceive_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyInteractiveReceiveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AssetWalletServer).VerifyInteractiveReceive(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/assetwalletrpc.AssetWallet/VerifyInteractiveReceive",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AssetWalletServer).VerifyInteractiveReceive(ctx, req.(*VerifyInteractiveReceiveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AssetWallet_ComputeVirtualPsbtSigHashes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ComputeVirtualPsbtSigHashesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AssetWalletServer).ComputeVirtualPsbtSigHashes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/assetwalletrpc.AssetWallet/ComputeVirtualPsbtSigHashes",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AssetWalletServer).ComputeVirtualPsbtSigHashes(ctx, req.(*ComputeVirtualPsbtSigHashesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AssetWallet_AddVirtualPsbtWitnesses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddVirtualPsbtWitnessesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AssetWalletServer).AddVirtualPsbtWitnesses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/assetwalletrpc.AssetWallet/AddVirtualPsbtWitnesses",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AssetWalletServer).AddVirtualPsbtWitnesses(ctx, req.(*AddVirtualPsbtWitnessesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AssetWallet_PrepareAnchorPsbt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PrepareAnchorPsbtRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AssetWalletServer).PrepareAnchorPsbt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/assetwalletrpc.AssetWallet/PrepareAnchorPsbt",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AssetWalletServer).PrepareAnchorPsbt(ctx, req.(*PrepareAnchorPsbtRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AssetWallet_PublishAnchorPsbt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PublishAnchorPsbtRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AssetWalletServer).PublishAnchorPsbt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/assetwalletrpc.AssetWallet/PublishAnchorPsbt",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AssetWalletServer).PublishAnchorPsbt(ctx, req.(*PublishAnchorPsbtRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AssetWallet_ProveReserves_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProveReservesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AssetWalletServer).ProveReserves(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/assetwalletrpc.AssetWallet/ProveReserves",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AssetWalletServer).ProveReserves(ctx, req.(*ProveReservesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AssetWallet_VerifyReserves_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyReservesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AssetWalletServer).VerifyReserves(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/assetwalletrpc.AssetWallet/VerifyReserves",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AssetWalletServer).VerifyReserves(ctx, req.(*VerifyReservesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // AssetWallet_ServiceDesc is the grpc.ServiceDesc for AssetWallet service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -360,6 +686,38 @@ var AssetWallet_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "VerifyAssetOwnership",
 			Handler:    _AssetWallet_VerifyAssetOwnership_Handler,
 		},
+		{
+			MethodName: "PrepareInteractiveReceive",
+			Handler:    _AssetWallet_PrepareInteractiveReceive_Handler,
+		},
+		{
+			MethodName: "VerifyInteractiveReceive",
+			Handler:    _AssetWallet_VerifyInteractiveReceive_Handler,
+		},
+		{
+			MethodName: "ComputeVirtualPsbtSigHashes",
+			Handler:    _AssetWallet_ComputeVirtualPsbtSigHashes_Handler,
+		},
+		{
+			MethodName: "AddVirtualPsbtWitnesses",
+			Handler:    _AssetWallet_AddVirtualPsbtWitnesses_Handler,
+		},
+		{
+			MethodName: "PrepareAnchorPsbt",
+			Handler:    _AssetWallet_PrepareAnchorPsbt_Handler,
+		},
+		{
+			MethodName: "PublishAnchorPsbt",
+			Handler:    _AssetWallet_PublishAnchorPsbt_Handler,
+		},
+		{
+			MethodName: "ProveReserves",
+			Handler:    _AssetWallet_ProveReserves_Handler,
+		},
+		{
+			MethodName: "VerifyReserves",
+			Handler:    _AssetWallet_VerifyReserves_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "assetwalletrpc/assetwallet.proto",
@@ -88,6 +88,51 @@ type TaprootAssetsClient interface {
 	// FetchAssetMeta allows a caller to fetch the reveal meta data for an asset
 	// either by the asset ID for that asset, or a meta hash.
 	FetchAssetMeta(ctx context.Context, in *FetchAssetMetaRequest, opts ...grpc.CallOption) (*AssetMeta, error)
+	// tapcli: `assets burn`
+	// BurnAsset burns the given number of units of a given asset by sending them
+	// to a provably un-spendable script key. Burning means irrevocably destroying
+	// a certain number of assets, reducing the total supply of the asset. Because
+	// burning is such a destructive and non-reversible operation, some specific
+	// values need to be set in the request to avoid accidental burns.
+	BurnAsset(ctx context.Context, in *BurnAssetRequest, opts ...grpc.CallOption) (*BurnAssetResponse, error)
+	// tapcli: `backup`
+	// BackupDatabase writes a consistent snapshot of the daemon's database to a
+	// file, without interrupting any minting or transfers that are in progress.
+	// The backup can be restored on startup with the databaserestorefile option.
+	BackupDatabase(ctx context.Context, in *BackupDatabaseRequest, opts ...grpc.CallOption) (*BackupDatabaseResponse, error)
+	// tapcli: `dbstats`
+	// DatabaseStats returns statistics about the size of the daemon's database,
+	// which can be used to monitor its growth.
+	DatabaseStats(ctx context.Context, in *DatabaseStatsRequest, opts ...grpc.CallOption) (*DatabaseStatsResponse, error)
+	// tapcli: `addrs prune`
+	// PruneAddrs removes all addresses from the address book that can no longer
+	// receive any transfers, because they either expired or are single-use and
+	// already received their transfer. The events of the pruned addresses are
+	// removed as well.
+	PruneAddrs(ctx context.Context, in *PruneAddrsRequest, opts ...grpc.CallOption) (*PruneAddrsResponse, error)
+	// tapcli: `assets metahistory`
+	// FetchGroupMetaHistory returns the verified history of the metadata updates
+	// carried by the tranches of an asset group, along with the metadata wallets
+	// should display for the group.
+	FetchGroupMetaHistory(ctx context.Context, in *FetchGroupMetaHistoryRequest, opts ...grpc.CallOption) (*FetchGroupMetaHistoryResponse, error)
+	// SubscribeReceiveAssetEventNtfns registers a subscription to the event
+	// notification stream which relates to the asset receiving process. An event
+	// is sent as soon as an inbound transfer to one of our addresses is detected
+	// in the mempool, and on every subsequent status change of that transfer.
+	SubscribeReceiveAssetEventNtfns(ctx context.Context, in *SubscribeReceiveAssetEventNtfnsRequest, opts ...grpc.CallOption) (TaprootAssets_SubscribeReceiveAssetEventNtfnsClient, error)
+	// tapcli: `proofs exportbundle`
+	// ExportProofBundle exports the proof files of all assets owned by the
+	// daemon, or only of those with the given asset IDs, as a single compressed
+	// proof bundle. The bundle contains a manifest that describes each of its
+	// proof files and can be imported into another daemon with
+	// ImportProofBundle, for example to restore a backup or to migrate a wallet.
+	ExportProofBundle(ctx context.Context, in *ExportProofBundleRequest, opts ...grpc.CallOption) (*ProofBundle, error)
+	// tapcli: `proofs importbundle`
+	// ImportProofBundle fully verifies all proof files of the given proof bundle
+	// and then imports them into the daemon, resulting in a spendable asset for
+	// each of them. If any of the proof files is invalid or doesn't match the
+	// manifest of the bundle, none of them are imported.
+	ImportProofBundle(ctx context.Context, in *ImportProofBundleRequest, opts ...grpc.CallOption) (*ImportProofBundleResponse, error)
 }
 
 type taprootAssetsClient struct {
@@ -283,6 +328,101 @@ func (c *taprootAssetsClient) FetchAssetMeta(ctx context.Context, in *FetchAsset
 	return out, nil
 }
 
+func (c *taprootAssetsClient) BurnAsset(ctx context.Context, in *BurnAssetRequest, opts ...grpc.CallOption) (*BurnAssetResponse, error) {
+	out := new(BurnAssetResponse)
+	err := c.cc.Invoke(ctx, "/taprpc.TaprootAssets/BurnAsset", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taprootAssetsClient) BackupDatabase(ctx context.Context, in *BackupDatabaseRequest, opts ...grpc.CallOption) (*BackupDatabaseResponse, error) {
+	out := new(BackupDatabaseResponse)
+	err := c.cc.Invoke(ctx, "/taprpc.TaprootAssets/BackupDatabase", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taprootAssetsClient) DatabaseStats(ctx context.Context, in *DatabaseStatsRequest, opts ...grpc.CallOption) (*DatabaseStatsResponse, error) {
+	out := new(DatabaseStatsResponse)
+	err := c.cc.Invoke(ctx, "/taprpc.TaprootAssets/DatabaseStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taprootAssetsClient) PruneAddrs(ctx context.Context, in *PruneAddrsRequest, opts ...grpc.CallOption) (*PruneAddrsResponse, error) {
+	out := new(PruneAddrsResponse)
+	err := c.cc.Invoke(ctx, "/taprpc.TaprootAssets/PruneAddrs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taprootAssetsClient) FetchGroupMetaHistory(ctx context.Context, in *FetchGroupMetaHistoryRequest, opts ...grpc.CallOption) (*FetchGroupMetaHistoryResponse, error) {
+	out := new(FetchGroupMetaHistoryResponse)
+	err := c.cc.Invoke(ctx, "/taprpc.TaprootAssets/FetchGroupMetaHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taprootAssetsClient) SubscribeReceiveAssetEventNtfns(ctx context.Context, in *SubscribeReceiveAssetEventNtfnsRequest, opts ...grpc.CallOption) (TaprootAssets_SubscribeReceiveAssetEventNtfnsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TaprootAssets_ServiceDesc.Streams[1], "/taprpc.TaprootAssets/SubscribeReceiveAssetEventNtfns", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &taprootAssetsSubscribeReceiveAssetEventNtfnsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TaprootAssets_SubscribeReceiveAssetEventNtfnsClient interface {
+	Recv() (*AddrEvent, error)
+	grpc.ClientStream
+}
+
+type taprootAssetsSubscribeReceiveAssetEventNtfnsClient struct {
+	grpc.ClientStream
+}
+
+func (x *taprootAssetsSubscribeReceiveAssetEventNtfnsClient) Recv() (*AddrEvent, error) {
+	m := new(AddrEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *taprootAssetsClient) ExportProofBundle(ctx context.Context, in *ExportProofBundleRequest, opts ...grpc.CallOption) (*ProofBundle, error) {
+	out := new(ProofBundle)
+	err := c.cc.Invoke(ctx, "/taprpc.TaprootAssets/ExportProofBundle", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taprootAssetsClient) ImportProofBundle(ctx context.Context, in *ImportProofBundleRequest, opts ...grpc.CallOption) (*ImportProofBundleResponse, error) {
+	out := new(ImportProofBundleResponse)
+	err := c.cc.Invoke(ctx, "/taprpc.TaprootAssets/ImportProofBundle", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TaprootAssetsServer is the server API for TaprootAssets service.
 // All implementations must embed UnimplementedTaprootAssetsServer
 // for forward compatibility
@@ -357,6 +497,51 @@ type TaprootAssetsServer interface {
 	// FetchAssetMeta allows a caller to fetch the reveal meta data for an asset
 	// either by the asset ID for that asset, or a meta hash.
 	FetchAssetMeta(context.Context, *FetchAssetMetaRequest) (*AssetMeta, error)
+	// tapcli: `assets burn`
+	// BurnAsset burns the given number of units of a given asset by sending them
+	// to a provably un-spendable script key. Burning means irrevocably destroying
+	// a certain number of assets, reducing the total supply of the asset. Because
+	// burning is such a destructive and non-reversible operation, some specific
+	// values need to be set in the request to avoid accidental burns.
+	BurnAsset(context.Context, *BurnAssetRequest) (*BurnAssetResponse, error)
+	// tapcli: `backup`
+	// BackupDatabase writes a consistent snapshot of the daemon's database to a
+	// file, without interrupting any minting or transfers that are in progress.
+	// The backup can be restored on startup with the databaserestorefile option.
+	BackupDatabase(context.Context, *BackupDatabaseRequest) (*BackupDatabaseResponse, error)
+	// tapcli: `dbstats`
+	// DatabaseStats returns statistics about the size of the daemon's database,
+	// which can be used to monitor its growth.
+	DatabaseStats(context.Context, *DatabaseStatsRequest) (*DatabaseStatsResponse, error)
+	// tapcli: `addrs prune`
+	// PruneAddrs removes all addresses from the address book that can no longer
+	// receive any transfers, because they either expired or are single-use and
+	// already received their transfer. The events of the pruned addresses are
+	// removed as well.
+	PruneAddrs(context.Context, *PruneAddrsRequest) (*PruneAddrsResponse, error)
+	// tapcli: `assets metahistory`
+	// FetchGroupMetaHistory returns the verified history of the metadata updates
+	// carried by the tranches of an asset group, along with the metadata wallets
+	// should display for the group.
+	FetchGroupMetaHistory(context.Context, *FetchGroupMetaHistoryRequest) (*FetchGroupMetaHistoryResponse, error)
+	// SubscribeReceiveAssetEventNtfns registers a subscription to the event
+	// notification stream which relates to the asset receiving process. An event
+	// is sent as soon as an inbound transfer to one of our addresses is detected
+	// in the mempool, and on every subsequent status change of that transfer.
+	SubscribeReceiveAssetEventNtfns(*SubscribeReceiveAssetEventNtfnsRequest, TaprootAssets_SubscribeReceiveAssetEventNtfnsServer) error
+	// tapcli: `proofs exportbundle`
+	// ExportProofBundle exports the proof files of all assets owned by the
+	// daemon, or only of those with the given asset IDs, as a single compressed
+	// proof bundle. The bundle contains a manifest that describes each of its
+	// proof files and can be imported into another daemon with
+	// ImportProofBundle, for example to restore a backup or to migrate a wallet.
+	ExportProofBundle(context.Context, *ExportProofBundleRequest) (*ProofBundle, error)
+	// tapcli: `proofs importbundle`
+	// ImportProofBundle fully verifies all proof files of the given proof bundle
+	// and then imports them into the daemon, resulting in a spendable asset for
+	// each of them. If any of the proof files is invalid or doesn't match the
+	// manifest of the bundle, none of them are imported.
+	ImportProofBundle(context.Context, *ImportProofBundleRequest) (*ImportProofBundleResponse, error)
 	mustEmbedUnimplementedTaprootAssetsServer()
 }
 
@@ -418,6 +603,30 @@ func (UnimplementedTaprootAssetsServer) SubscribeSendAssetEventNtfns(*SubscribeS
 func (UnimplementedTaprootAssetsServer) FetchAssetMeta(context.Context, *FetchAssetMetaRequest) (*AssetMeta, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method FetchAssetMeta not implemented")
 }
+func (UnimplementedTaprootAssetsServer) BurnAsset(context.Context, *BurnAssetRequest) (*BurnAssetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BurnAsset not implemented")
+}
+func (UnimplementedTaprootAssetsServer) BackupDatabase(context.Context, *BackupDatabaseRequest) (*BackupDatabaseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BackupDatabase not implemented")
+}
+func (UnimplementedTaprootAssetsServer) DatabaseStats(context.Context, *DatabaseStatsRequest) (*DatabaseStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DatabaseStats not implemented")
+}
+func (UnimplementedTaprootAssetsServer) PruneAddrs(context.Context, *PruneAddrsRequest) (*PruneAddrsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PruneAddrs not implemented")
+}
+func (UnimplementedTaprootAssetsServer) FetchGroupMetaHistory(context.Context, *FetchGroupMetaHistoryRequest) (*FetchGroupMetaHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FetchGroupMetaHistory not implemented")
+}
+func (UnimplementedTaprootAssetsServer) SubscribeReceiveAssetEventNtfns(*SubscribeReceiveAssetEventNtfnsRequest, TaprootAssets_SubscribeReceiveAssetEventNtfnsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeReceiveAssetEventNtfns not implemented")
+}
+func (UnimplementedTaprootAssetsServer) ExportProofBundle(context.Context, *ExportProofBundleRequest) (*ProofBundle, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportProofBundle not implemented")
+}
+func (UnimplementedTaprootAssetsServer) ImportProofBundle(context.Context, *ImportProofBundleRequest) (*ImportProofBundleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportProofBundle not implemented")
+}
 func (UnimplementedTaprootAssetsServer) mustEmbedUnimplementedTaprootAssetsServer() {}
 
 // UnsafeTaprootAssetsServer may be embedded to opt out of forward compatibility for this service.
@@ -758,6 +967,153 @@ func _TaprootAssets_FetchAssetMeta_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TaprootAssets_BurnAsset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BurnAssetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaprootAssetsServer).BurnAsset(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/taprpc.TaprootAssets/BurnAsset",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaprootAssetsServer).BurnAsset(ctx, req.(*BurnAssetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaprootAssets_BackupDatabase_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BackupDatabaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaprootAssetsServer).BackupDatabase(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/taprpc.TaprootAssets/BackupDatabase",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaprootAssetsServer).BackupDatabase(ctx, req.(*BackupDatabaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaprootAssets_DatabaseStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DatabaseStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaprootAssetsServer).DatabaseStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/taprpc.TaprootAssets/DatabaseStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaprootAssetsServer).DatabaseStats(ctx, req.(*DatabaseStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaprootAssets_PruneAddrs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PruneAddrsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaprootAssetsServer).PruneAddrs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/taprpc.TaprootAssets/PruneAddrs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaprootAssetsServer).PruneAddrs(ctx, req.(*PruneAddrsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaprootAssets_FetchGroupMetaHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FetchGroupMetaHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaprootAssetsServer).FetchGroupMetaHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/taprpc.TaprootAssets/FetchGroupMetaHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaprootAssetsServer).FetchGroupMetaHistory(ctx, req.(*FetchGroupMetaHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaprootAssets_SubscribeReceiveAssetEventNtfns_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeReceiveAssetEventNtfnsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TaprootAssetsServer).SubscribeReceiveAssetEventNtfns(m, &taprootAssetsSubscribeReceiveAssetEventNtfnsServer{stream})
+}
+
+type TaprootAssets_SubscribeReceiveAssetEventNtfnsServer interface {
+	Send(*AddrEvent) error
+	grpc.ServerStream
+}
+
+type taprootAssetsSubscribeReceiveAssetEventNtfnsServer struct {
+	grpc.ServerStream
+}
+
+func (x *taprootAssetsSubscribeReceiveAssetEventNtfnsServer) Send(m *AddrEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TaprootAssets_ExportProofBundle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportProofBundleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaprootAssetsServer).ExportProofBundle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/taprpc.TaprootAssets/ExportProofBundle",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaprootAssetsServer).ExportProofBundle(ctx, req.(*ExportProofBundleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaprootAssets_ImportProofBundle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportProofBundleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaprootAssetsServer).ImportProofBundle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/taprpc.TaprootAssets/ImportProofBundle",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaprootAssetsServer).ImportProofBundle(ctx, req.(*ImportProofBundleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // TaprootAssets_ServiceDesc is the grpc.ServiceDesc for TaprootAssets service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -833,6 +1189,34 @@ var TaprootAssets_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "FetchAssetMeta",
 			Handler:    _TaprootAssets_FetchAssetMeta_Handler,
 		},
+		{
+			MethodName: "BurnAsset",
+			Handler:    _TaprootAssets_BurnAsset_Handler,
+		},
+		{
+			MethodName: "BackupDatabase",
+			Handler:    _TaprootAssets_BackupDatabase_Handler,
+		},
+		{
+			MethodName: "DatabaseStats",
+			Handler:    _TaprootAssets_DatabaseStats_Handler,
+		},
+		{
+			MethodName: "PruneAddrs",
+			Handler:    _TaprootAssets_PruneAddrs_Handler,
+		},
+		{
+			MethodName: "FetchGroupMetaHistory",
+			Handler:    _TaprootAssets_FetchGroupMetaHistory_Handler,
+		},
+		{
+			MethodName: "ExportProofBundle",
+			Handler:    _TaprootAssets_ExportProofBundle_Handler,
+		},
+		{
+			MethodName: "ImportProofBundle",
+			Handler:    _TaprootAssets_ImportProofBundle_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -840,6 +1224,11 @@ var TaprootAssets_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _TaprootAssets_SubscribeSendAssetEventNtfns_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "SubscribeReceiveAssetEventNtfns",
+			Handler:       _TaprootAssets_SubscribeReceiveAssetEventNtfns_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "taprootassets.proto",
 }
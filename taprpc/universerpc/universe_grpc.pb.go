@@ -79,7 +79,8 @@ type UniverseClient interface {
 	// tapcli: `universe stats`
 	// UniverseStats returns a set of aggregrate statistics for the current state
 	// of the Universe. Stats returned include: total number of syncs, total
-	// number of proofs, and total number of known assets.
+	// number of proofs, total number of proof queries, and total number of
+	// known assets, groups and leaves.
 	UniverseStats(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*StatsResponse, error)
 	// tapcli `universe stats assets`
 	// QueryAssetStats returns a set of statistics for a given set of assets.
@@ -270,7 +271,8 @@ type UniverseServer interface {
 	// tapcli: `universe stats`
 	// UniverseStats returns a set of aggregrate statistics for the current state
 	// of the Universe. Stats returned include: total number of syncs, total
-	// number of proofs, and total number of known assets.
+	// number of proofs, total number of proof queries, and total number of
+	// known assets, groups and leaves.
 	UniverseStats(context.Context, *StatsRequest) (*StatsResponse, error)
 	// tapcli `universe stats assets`
 	// QueryAssetStats returns a set of statistics for a given set of assets.
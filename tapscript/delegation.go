@@ -0,0 +1,375 @@
+package tapscript
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightninglabs/taproot-assets/tappsbt"
+	"github.com/lightningnetwork/lnd/keychain"
+)
+
+var (
+	// ErrDelegationLimitsNotMet is returned if an operator attempts to
+	// spend a delegated asset whose lock times don't satisfy the limits
+	// of the operator leaf.
+	ErrDelegationLimitsNotMet = errors.New("delegation limits not met")
+)
+
+// DelegationLimits are the limits the operator of a delegated script key is
+// bound to. The limits are enforced by the operator leaf script and are
+// checked against the lock time fields of the asset being spent, since those
+// are the values the virtual transaction is created with.
+type DelegationLimits struct {
+	// LockTime is the absolute lock time (block height or timestamp) the
+	// operator must wait for before spending, enforced with
+	// OP_CHECKLOCKTIMEVERIFY. A value of zero means no limit.
+	LockTime uint64
+
+	// RelativeLockTime is the relative lock time (in BIP-0068 sequence
+	// encoding) the operator must wait for before spending, enforced with
+	// OP_CHECKSEQUENCEVERIFY. A value of zero means no limit.
+	RelativeLockTime uint64
+}
+
+// Validate makes sure the limits can be expressed in a tapscript leaf.
+func (l DelegationLimits) Validate() error {
+	if l.LockTime > math.MaxUint32 {
+		return fmt.Errorf("lock time %d exceeds maximum", l.LockTime)
+	}
+
+	if l.RelativeLockTime > math.MaxUint32 ||
+		l.RelativeLockTime&wire.SequenceLockTimeDisabled != 0 {
+
+		return fmt.Errorf("invalid relative lock time %d",
+			l.RelativeLockTime)
+	}
+
+	return nil
+}
+
+// SatisfiedBy returns an error if the lock times of the given asset don't
+// satisfy the limits, which means the operator leaf can't be used to spend it.
+func (l DelegationLimits) SatisfiedBy(a *asset.Asset) error {
+	if a.LockTime < l.LockTime {
+		return fmt.Errorf("%w: asset lock time %d below %d",
+			ErrDelegationLimitsNotMet, a.LockTime, l.LockTime)
+	}
+
+	if a.RelativeLockTime < l.RelativeLockTime {
+		return fmt.Errorf("%w: asset relative lock time %d below %d",
+			ErrDelegationLimitsNotMet, a.RelativeLockTime,
+			l.RelativeLockTime)
+	}
+
+	return nil
+}
+
+// DelegationPath is the spend path used to spend an asset locked to a
+// delegated script key.
+type DelegationPath uint8
+
+const (
+	// DelegationPathOwnerKey is the key spend path of the owner, which is
+	// the internal key of the delegated script key.
+	DelegationPathOwnerKey DelegationPath = iota
+
+	// DelegationPathOwnerScript is the script spend path of the owner
+	// leaf, which can be used at any time.
+	DelegationPathOwnerScript
+
+	// DelegationPathOperatorScript is the script spend path of the
+	// operator leaf, which can only be used within the delegation limits.
+	DelegationPathOperatorScript
+)
+
+// String returns a human-readable description of the spend path.
+func (p DelegationPath) String() string {
+	switch p {
+	case DelegationPathOwnerKey:
+		return "owner_key"
+	case DelegationPathOwnerScript:
+		return "owner_script"
+	case DelegationPathOperatorScript:
+		return "operator_script"
+	default:
+		return fmt.Sprintf("<unknown delegation path %d>", p)
+	}
+}
+
+// DelegatedScriptKey is a script key that lets an owner delegate spending of
+// an asset to an operator. The owner key is used as the internal key, so the
+// owner can always spend through the key spend path or the owner leaf. The
+// operator can only spend through the operator leaf, which enforces the
+// delegation limits.
+type DelegatedScriptKey struct {
+	// Owner is the key of the owner of the asset.
+	Owner keychain.KeyDescriptor
+
+	// Operator is the key of the operator the spending is delegated to.
+	Operator keychain.KeyDescriptor
+
+	// Limits are the limits the operator is bound to.
+	Limits DelegationLimits
+
+	// OwnerLeaf is the leaf that can be spent by the owner at any time.
+	OwnerLeaf txscript.TapLeaf
+
+	// OperatorLeaf is the leaf that can be spent by the operator within
+	// the delegation limits.
+	OperatorLeaf txscript.TapLeaf
+
+	// Tree is the tapscript tree that commits to both leaves.
+	Tree *txscript.IndexedTapScriptTree
+
+	// ScriptKey is the resulting script key that assets can be sent to.
+	ScriptKey asset.ScriptKey
+}
+
+// OwnerLeafScript returns the leaf that lets the owner spend with a single
+// signature.
+func OwnerLeafScript(owner *btcec.PublicKey) (txscript.TapLeaf, error) {
+	script, err := txscript.NewScriptBuilder().
+		AddData(schnorr.SerializePubKey(owner)).
+		AddOp(txscript.OP_CHECKSIG).
+		Script()
+	if err != nil {
+		return txscript.TapLeaf{}, err
+	}
+
+	return txscript.NewBaseTapLeaf(script), nil
+}
+
+// OperatorLeafScript returns the leaf that lets the operator spend with a
+// single signature, once all the lock times of the given limits are reached.
+func OperatorLeafScript(operator *btcec.PublicKey,
+	limits DelegationLimits) (txscript.TapLeaf, error) {
+
+	if err := limits.Validate(); err != nil {
+		return txscript.TapLeaf{}, err
+	}
+
+	builder := txscript.NewScriptBuilder()
+	if limits.LockTime > 0 {
+		builder.AddInt64(int64(limits.LockTime))
+		builder.AddOp(txscript.OP_CHECKLOCKTIMEVERIFY)
+		builder.AddOp(txscript.OP_DROP)
+	}
+	if limits.RelativeLockTime > 0 {
+		builder.AddInt64(int64(limits.RelativeLockTime))
+		builder.AddOp(txscript.OP_CHECKSEQUENCEVERIFY)
+		builder.AddOp(txscript.OP_DROP)
+	}
+	builder.AddData(schnorr.SerializePubKey(operator))
+	builder.AddOp(txscript.OP_CHECKSIG)
+
+	script, err := builder.Script()
+	if err != nil {
+		return txscript.TapLeaf{}, err
+	}
+
+	return txscript.NewBaseTapLeaf(script), nil
+}
+
+// NewDelegatedScriptKey creates a new script key that can be spent by the
+// owner at any time and by the operator within the given limits.
+func NewDelegatedScriptKey(owner, operator keychain.KeyDescriptor,
+	limits DelegationLimits) (*DelegatedScriptKey, error) {
+
+	if owner.PubKey == nil || operator.PubKey == nil {
+		return nil, fmt.Errorf("owner and operator keys must be set")
+	}
+
+	ownerXOnly := schnorr.SerializePubKey(owner.PubKey)
+	operatorXOnly := schnorr.SerializePubKey(operator.PubKey)
+	if bytes.Equal(ownerXOnly, operatorXOnly) {
+		return nil, fmt.Errorf("owner and operator keys must differ")
+	}
+
+	ownerLeaf, err := OwnerLeafScript(owner.PubKey)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create owner leaf: %w", err)
+	}
+	operatorLeaf, err := OperatorLeafScript(operator.PubKey, limits)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create operator leaf: %w",
+			err)
+	}
+
+	tree := txscript.AssembleTaprootScriptTree(ownerLeaf, operatorLeaf)
+	rootHash := tree.RootNode.TapHash()
+	outputKey := txscript.ComputeTaprootOutputKey(
+		owner.PubKey, rootHash[:],
+	)
+
+	return &DelegatedScriptKey{
+		Owner:        owner,
+		Operator:     operator,
+		Limits:       limits,
+		OwnerLeaf:    ownerLeaf,
+		OperatorLeaf: operatorLeaf,
+		Tree:         tree,
+		ScriptKey: asset.ScriptKey{
+			PubKey: outputKey,
+			TweakedScriptKey: &asset.TweakedScriptKey{
+				RawKey: owner,
+				Tweak:  rootHash[:],
+				Leaves: []txscript.TapLeaf{
+					ownerLeaf, operatorLeaf,
+				},
+			},
+		},
+	}, nil
+}
+
+// ControlBlock returns the serialized control block for spending the given
+// leaf of the delegated script key.
+func (d *DelegatedScriptKey) ControlBlock(
+	leaf txscript.TapLeaf) ([]byte, error) {
+
+	proofIdx, ok := d.Tree.LeafProofIndex[leaf.TapHash()]
+	if !ok {
+		return nil, fmt.Errorf("leaf not part of delegation tree")
+	}
+
+	controlBlock := d.Tree.LeafMerkleProofs[proofIdx].ToControlBlock(
+		d.Owner.PubKey,
+	)
+
+	return controlBlock.ToBytes()
+}
+
+// PrepareDelegatedSpend prepares the input with the given index of the virtual
+// packet to be signed through the given spend path of the delegated script key.
+// The signer picks the signing key and sign method from the derivation and
+// leaf information set here.
+func PrepareDelegatedSpend(vPkt *tappsbt.VPacket, idx int,
+	key *DelegatedScriptKey, path DelegationPath) error {
+
+	if idx < 0 || idx >= len(vPkt.Inputs) {
+		return fmt.Errorf("invalid input index %d", idx)
+	}
+
+	vIn := vPkt.Inputs[idx]
+	inputAsset := vIn.Asset()
+	if inputAsset == nil {
+		return fmt.Errorf("input %d has no asset", idx)
+	}
+
+	if !inputAsset.ScriptKey.PubKey.IsEqual(key.ScriptKey.PubKey) {
+		return fmt.Errorf("input %d is not locked to delegated script "+
+			"key", idx)
+	}
+
+	var (
+		coinType   = vPkt.ChainParams.HDCoinType
+		signingKey keychain.KeyDescriptor
+		leaf       *txscript.TapLeaf
+	)
+	switch path {
+	case DelegationPathOwnerKey:
+		signingKey = key.Owner
+
+	case DelegationPathOwnerScript:
+		signingKey = key.Owner
+		leaf = &key.OwnerLeaf
+
+	case DelegationPathOperatorScript:
+		if err := key.Limits.SatisfiedBy(inputAsset); err != nil {
+			return err
+		}
+
+		signingKey = key.Operator
+		leaf = &key.OperatorLeaf
+
+	default:
+		return fmt.Errorf("unknown delegation path: %v", path)
+	}
+
+	// The owner is always the raw key of the script key and the internal
+	// key of the input. The derivations are sorted by key when the packet
+	// is encoded, so the decoder restores the script key from the
+	// derivation that matches the internal key.
+	inputAsset.ScriptKey.TweakedScriptKey = &asset.TweakedScriptKey{
+		RawKey: key.Owner,
+		Tweak:  key.ScriptKey.Tweak,
+		Leaves: key.ScriptKey.Leaves,
+	}
+	ownerDerivation, ownerTrDerivation :=
+		tappsbt.Bip32DerivationFromKeyDesc(key.Owner, coinType)
+	signerDerivation, signerTrDerivation :=
+		tappsbt.Bip32DerivationFromKeyDesc(signingKey, coinType)
+
+	vIn.Bip32Derivation = tappsbt.AddBip32Derivation(
+		[]*psbt.Bip32Derivation{ownerDerivation}, signerDerivation,
+	)
+	vIn.TaprootBip32Derivation = []*psbt.TaprootBip32Derivation{
+		signerTrDerivation,
+	}
+	vIn.TaprootInternalKey = ownerTrDerivation.XOnlyPubKey
+	vIn.TaprootMerkleRoot = key.ScriptKey.Tweak
+	vIn.TaprootLeafScript = nil
+
+	// For the key spend path, we're done. For the script spend paths, we
+	// need to reveal the leaf and its control block.
+	if leaf == nil {
+		return nil
+	}
+
+	controlBlock, err := key.ControlBlock(*leaf)
+	if err != nil {
+		return err
+	}
+
+	leafHash := leaf.TapHash()
+	signerTrDerivation.LeafHashes = [][]byte{leafHash[:]}
+	vIn.TaprootLeafScript = []*psbt.TaprootTapLeafScript{{
+		ControlBlock: controlBlock,
+		Script:       leaf.Script,
+		LeafVersion:  leaf.LeafVersion,
+	}}
+
+	return nil
+}
+
+// scriptSpendKeyDesc returns the key descriptor of the key that should sign
+// for the leaf of a script spend. This is the raw script key by default, but
+// can be any other key with a BIP-0032 derivation on the input, for example
+// the key of an operator of a delegated script key.
+func scriptSpendKeyDesc(vIn *tappsbt.VInput,
+	derivation *psbt.TaprootBip32Derivation) (keychain.KeyDescriptor,
+	error) {
+
+	rawKey := vIn.Asset().ScriptKey.RawKey
+	if rawKey.PubKey != nil && bytes.Equal(
+		schnorr.SerializePubKey(rawKey.PubKey), derivation.XOnlyPubKey,
+	) {
+
+		return rawKey, nil
+	}
+
+	for _, bip32Derivation := range vIn.Bip32Derivation {
+		if len(bip32Derivation.PubKey) != btcec.PubKeyBytesLenCompressed {
+			continue
+		}
+
+		if !bytes.Equal(
+			bip32Derivation.PubKey[1:], derivation.XOnlyPubKey,
+		) {
+
+			continue
+		}
+
+		return tappsbt.KeyDescFromBip32Derivation(bip32Derivation)
+	}
+
+	return keychain.KeyDescriptor{}, fmt.Errorf("missing BIP-0032 " +
+		"derivation for script spend signing key")
+}
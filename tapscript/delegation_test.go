@@ -0,0 +1,307 @@
+package tapscript_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightninglabs/lndclient"
+	tap "github.com/lightninglabs/taproot-assets"
+	"github.com/lightninglabs/taproot-assets/address"
+	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightninglabs/taproot-assets/internal/test"
+	"github.com/lightninglabs/taproot-assets/tappsbt"
+	"github.com/lightninglabs/taproot-assets/tapscript"
+	"github.com/lightninglabs/taproot-assets/vm"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/stretchr/testify/require"
+)
+
+// multiKeySigner is a signer that signs with the private key matching the key
+// descriptor of the sign request.
+type multiKeySigner struct {
+	keys map[asset.SerializedKey]*btcec.PrivateKey
+}
+
+func (m *multiKeySigner) SignVirtualTx(signDesc *lndclient.SignDescriptor,
+	tx *wire.MsgTx, prevOut *wire.TxOut) (*schnorr.Signature, error) {
+
+	privKey, ok := m.keys[asset.ToSerialized(signDesc.KeyDesc.PubKey)]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key")
+	}
+
+	return tapscript.NewMockSigner(privKey).SignVirtualTx(
+		signDesc, tx, prevOut,
+	)
+}
+
+// delegatedPacket creates a packet that spends an asset locked to the given
+// delegated script key with the given lock times.
+func delegatedPacket(t *testing.T, key *tapscript.DelegatedScriptKey,
+	lockTime, relLockTime uint64) *tappsbt.VPacket {
+
+	inputAsset, err := asset.New(
+		asset.RandGenesis(t, asset.Normal), 10, lockTime, relLockTime,
+		key.ScriptKey, nil,
+	)
+	require.NoError(t, err)
+
+	prevID := asset.PrevID{
+		OutPoint:  test.RandOp(t),
+		ID:        inputAsset.ID(),
+		ScriptKey: asset.ToSerialized(key.ScriptKey.PubKey),
+	}
+	vPkt := &tappsbt.VPacket{
+		Inputs: []*tappsbt.VInput{{
+			PrevID: prevID,
+		}},
+		Outputs: []*tappsbt.VOutput{{
+			Interactive: true,
+			Amount:      inputAsset.Amount,
+			ScriptKey: asset.NewScriptKey(
+				test.RandPubKey(t),
+			),
+			AnchorOutputInternalKey: test.RandPubKey(t),
+		}},
+		ChainParams: &address.MainNetTap,
+	}
+	vPkt.SetInputAsset(0, inputAsset, nil)
+
+	err = tapscript.PrepareOutputAssets(context.Background(), vPkt)
+	require.NoError(t, err)
+
+	return vPkt
+}
+
+// TestDelegatedScriptKey tests that assets locked to a delegated script key
+// can be spent by the owner at any time and by the operator only within the
+// delegation limits.
+func TestDelegatedScriptKey(t *testing.T) {
+	t.Parallel()
+
+	ownerPrivKey := test.RandPrivKey(t)
+	operatorPrivKey := test.RandPrivKey(t)
+	owner := keychain.KeyDescriptor{
+		PubKey: ownerPrivKey.PubKey(),
+		KeyLocator: keychain.KeyLocator{
+			Family: 212,
+			Index:  1,
+		},
+	}
+	operator := keychain.KeyDescriptor{
+		PubKey: operatorPrivKey.PubKey(),
+		KeyLocator: keychain.KeyLocator{
+			Family: 212,
+			Index:  2,
+		},
+	}
+	limits := tapscript.DelegationLimits{
+		LockTime:         800_000,
+		RelativeLockTime: 144,
+	}
+
+	_, err := tapscript.NewDelegatedScriptKey(owner, owner, limits)
+	require.ErrorContains(t, err, "must differ")
+
+	key, err := tapscript.NewDelegatedScriptKey(owner, operator, limits)
+	require.NoError(t, err)
+
+	signer := &multiKeySigner{
+		keys: map[asset.SerializedKey]*btcec.PrivateKey{
+			asset.ToSerialized(owner.PubKey):    ownerPrivKey,
+			asset.ToSerialized(operator.PubKey): operatorPrivKey,
+		},
+	}
+	validator := &tap.ValidatorV0{}
+
+	testCases := []struct {
+		name        string
+		path        tapscript.DelegationPath
+		lockTime    uint64
+		relLockTime uint64
+		prepareErr  error
+	}{{
+		name: "owner key spend without lock times",
+		path: tapscript.DelegationPathOwnerKey,
+	}, {
+		name: "owner script spend without lock times",
+		path: tapscript.DelegationPathOwnerScript,
+	}, {
+		name:        "operator within limits",
+		path:        tapscript.DelegationPathOperatorScript,
+		lockTime:    limits.LockTime,
+		relLockTime: limits.RelativeLockTime,
+	}, {
+		name:        "operator before lock time",
+		path:        tapscript.DelegationPathOperatorScript,
+		lockTime:    limits.LockTime - 1,
+		relLockTime: limits.RelativeLockTime,
+		prepareErr:  tapscript.ErrDelegationLimitsNotMet,
+	}, {
+		name:        "operator before relative lock time",
+		path:        tapscript.DelegationPathOperatorScript,
+		lockTime:    limits.LockTime,
+		relLockTime: limits.RelativeLockTime - 1,
+		prepareErr:  tapscript.ErrDelegationLimitsNotMet,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			vPkt := delegatedPacket(
+				t, key, tc.lockTime, tc.relLockTime,
+			)
+
+			err := tapscript.PrepareDelegatedSpend(
+				vPkt, 0, key, tc.path,
+			)
+			if tc.prepareErr != nil {
+				require.ErrorIs(t, err, tc.prepareErr)
+				return
+			}
+			require.NoError(t, err)
+
+			err = tapscript.SignVirtualTransaction(
+				vPkt, signer, validator,
+			)
+			require.NoError(t, err)
+
+			// Script spends reveal the leaf and control block.
+			newAsset := vPkt.Outputs[0].Asset
+			witness := newAsset.PrevWitnesses[0].TxWitness
+			if tc.path == tapscript.DelegationPathOwnerKey {
+				require.Len(t, witness, 1)
+			} else {
+				require.Len(t, witness, 3)
+			}
+		})
+	}
+
+	// The limits are enforced by the script itself, so an operator can't
+	// get around them by skipping the checks when preparing the spend.
+	vPkt := delegatedPacket(
+		t, key, limits.LockTime, limits.RelativeLockTime,
+	)
+	err = tapscript.PrepareDelegatedSpend(
+		vPkt, 0, key, tapscript.DelegationPathOperatorScript,
+	)
+	require.NoError(t, err)
+
+	vPkt.Inputs[0].Asset().LockTime = limits.LockTime - 1
+	err = tapscript.SignVirtualTransaction(vPkt, signer, validator)
+	var vmErr vm.Error
+	require.ErrorAs(t, err, &vmErr)
+	require.Equal(t, vm.ErrInvalidTransferWitness, vmErr.Kind)
+
+	// The packet survives an encoding round trip with the operator
+	// derivation in place.
+	vPkt = delegatedPacket(
+		t, key, limits.LockTime, limits.RelativeLockTime,
+	)
+	err = tapscript.PrepareDelegatedSpend(
+		vPkt, 0, key, tapscript.DelegationPathOperatorScript,
+	)
+	require.NoError(t, err)
+
+	b64, err := vPkt.B64Encode()
+	require.NoError(t, err)
+	decoded, err := tappsbt.NewFromRawBytes(strings.NewReader(b64), true)
+	require.NoError(t, err)
+	decodedKey := decoded.Inputs[0].Asset().ScriptKey
+	require.True(t, owner.PubKey.IsEqual(decodedKey.RawKey.PubKey))
+
+	err = tapscript.SignVirtualTransaction(decoded, signer, validator)
+	require.NoError(t, err)
+}
+
+// TestDelegatedSpendEncoding tests that an input prepared for the operator
+// script path survives an encoding round trip, even if the derivation of the
+// operator key is sorted before the one of the owner key.
+func TestDelegatedSpendEncoding(t *testing.T) {
+	t.Parallel()
+
+	ownerPrivKey := test.RandPrivKey(t)
+	operatorPrivKey := test.RandPrivKey(t)
+
+	// The derivations are sorted by their serialized key, so we make sure
+	// the operator key comes first.
+	if bytes.Compare(
+		ownerPrivKey.PubKey().SerializeCompressed(),
+		operatorPrivKey.PubKey().SerializeCompressed(),
+	) < 0 {
+
+		ownerPrivKey, operatorPrivKey = operatorPrivKey, ownerPrivKey
+	}
+
+	owner := keychain.KeyDescriptor{
+		PubKey: ownerPrivKey.PubKey(),
+		KeyLocator: keychain.KeyLocator{
+			Family: 212,
+			Index:  1,
+		},
+	}
+	operator := keychain.KeyDescriptor{
+		PubKey: operatorPrivKey.PubKey(),
+		KeyLocator: keychain.KeyLocator{
+			Family: 212,
+			Index:  2,
+		},
+	}
+	limits := tapscript.DelegationLimits{
+		LockTime:         800_000,
+		RelativeLockTime: 144,
+	}
+
+	key, err := tapscript.NewDelegatedScriptKey(owner, operator, limits)
+	require.NoError(t, err)
+
+	vPkt := delegatedPacket(
+		t, key, limits.LockTime, limits.RelativeLockTime,
+	)
+	err = tapscript.PrepareDelegatedSpend(
+		vPkt, 0, key, tapscript.DelegationPathOperatorScript,
+	)
+	require.NoError(t, err)
+
+	b64, err := vPkt.B64Encode()
+	require.NoError(t, err)
+	decoded, err := tappsbt.NewFromRawBytes(strings.NewReader(b64), true)
+	require.NoError(t, err)
+
+	decodedIn := decoded.Inputs[0]
+	require.Len(t, decodedIn.Bip32Derivation, 2)
+	require.Equal(
+		t, operator.PubKey.SerializeCompressed(),
+		decodedIn.Bip32Derivation[0].PubKey,
+	)
+
+	// The script key must still be restored with the owner as its raw
+	// key, while the operator remains the signing key.
+	decodedKey := decodedIn.Asset().ScriptKey
+	require.True(t, owner.PubKey.IsEqual(decodedKey.RawKey.PubKey))
+	require.Equal(t, owner.KeyLocator, decodedKey.RawKey.KeyLocator)
+	require.Equal(t, key.ScriptKey.Tweak, decodedKey.Tweak)
+	require.Len(t, decodedIn.TaprootBip32Derivation, 1)
+	require.Equal(
+		t, schnorr.SerializePubKey(operator.PubKey),
+		decodedIn.TaprootBip32Derivation[0].XOnlyPubKey,
+	)
+
+	signer := &multiKeySigner{
+		keys: map[asset.SerializedKey]*btcec.PrivateKey{
+			asset.ToSerialized(owner.PubKey):    ownerPrivKey,
+			asset.ToSerialized(operator.PubKey): operatorPrivKey,
+		},
+	}
+	err = tapscript.SignVirtualTransaction(
+		decoded, signer, &tap.ValidatorV0{},
+	)
+	require.NoError(t, err)
+}
@@ -0,0 +1,239 @@
+package tapscript
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightningnetwork/lnd/keychain"
+)
+
+var (
+	// ErrHtlcNotExpired is returned if the sender of an HTLC attempts to
+	// reclaim an asset before the timeout of the HTLC is reached.
+	ErrHtlcNotExpired = errors.New("htlc not expired")
+
+	// ErrInvalidPreimage is returned if a preimage doesn't match the
+	// payment hash of an HTLC.
+	ErrInvalidPreimage = errors.New("invalid preimage")
+)
+
+// HtlcPath is the spend path used to spend an asset locked to an HTLC script
+// key.
+type HtlcPath uint8
+
+const (
+	// HtlcPathSuccess is the script spend path of the receiver, which
+	// requires the preimage of the payment hash.
+	HtlcPathSuccess HtlcPath = iota
+
+	// HtlcPathTimeout is the script spend path of the sender, which can
+	// only be used once the CLTV expiry is reached.
+	HtlcPathTimeout
+)
+
+// String returns a human-readable description of the spend path.
+func (p HtlcPath) String() string {
+	switch p {
+	case HtlcPathSuccess:
+		return "success"
+	case HtlcPathTimeout:
+		return "timeout"
+	default:
+		return fmt.Sprintf("<unknown htlc path %d>", p)
+	}
+}
+
+// RefundLeafScript returns a leaf that lets the given key spend with a single
+// signature, once the given absolute lock time (block height or timestamp) is
+// reached.
+func RefundLeafScript(key *btcec.PublicKey,
+	cltvExpiry uint32) (txscript.TapLeaf, error) {
+
+	if key == nil {
+		return txscript.TapLeaf{}, fmt.Errorf("refund key not set")
+	}
+	if cltvExpiry == 0 {
+		return txscript.TapLeaf{}, fmt.Errorf("cltv expiry must be set")
+	}
+
+	return OperatorLeafScript(key, DelegationLimits{
+		LockTime: uint64(cltvExpiry),
+	})
+}
+
+// HtlcScriptKey is a script key for an HTLC-style asset output. The receiver
+// can spend the asset by revealing the preimage of the payment hash, the sender
+// can reclaim the asset once the CLTV expiry is reached.
+type HtlcScriptKey struct {
+	// PaymentHash is the SHA256 hash of the preimage the receiver needs to
+	// reveal to spend the asset.
+	PaymentHash [sha256.Size]byte
+
+	// Receiver is the key of the receiver of the HTLC.
+	Receiver *btcec.PublicKey
+
+	// Sender is the key of the sender of the HTLC, which is used to
+	// reclaim the asset after the timeout.
+	Sender *btcec.PublicKey
+
+	// CltvExpiry is the absolute lock time (block height or timestamp)
+	// after which the sender can reclaim the asset.
+	CltvExpiry uint32
+
+	// SuccessLeaf is the leaf that can be spent by the receiver with the
+	// preimage.
+	SuccessLeaf txscript.TapLeaf
+
+	// TimeoutLeaf is the leaf that can be spent by the sender after the
+	// CLTV expiry.
+	TimeoutLeaf txscript.TapLeaf
+
+	// TapscriptScriptKey is the script key that commits to both leaves.
+	*TapscriptScriptKey
+}
+
+// NewHtlcScriptKey creates a new HTLC script key with the given internal key.
+// To make sure the asset can only be spent through one of the HTLC paths, an
+// un-spendable internal key such as asset.NUMSPubKey should be used. Otherwise
+// the internal key usually is a MuSig2 key of the receiver and the sender that
+// allows them to cooperatively spend the asset.
+func NewHtlcScriptKey(internalKey keychain.KeyDescriptor,
+	paymentHash [sha256.Size]byte, receiver, sender *btcec.PublicKey,
+	cltvExpiry uint32) (*HtlcScriptKey, error) {
+
+	if receiver == nil || sender == nil {
+		return nil, fmt.Errorf("receiver and sender keys must be set")
+	}
+	if receiver.IsEqual(sender) {
+		return nil, fmt.Errorf("receiver and sender keys must differ")
+	}
+
+	successLeaf, err := HashLockLeafScript(paymentHash, receiver)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create success leaf: %w",
+			err)
+	}
+	timeoutLeaf, err := RefundLeafScript(sender, cltvExpiry)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create timeout leaf: %w",
+			err)
+	}
+
+	key, err := NewTapscriptScriptKey(
+		internalKey, successLeaf, timeoutLeaf,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &HtlcScriptKey{
+		PaymentHash:        paymentHash,
+		Receiver:           receiver,
+		Sender:             sender,
+		CltvExpiry:         cltvExpiry,
+		SuccessLeaf:        successLeaf,
+		TimeoutLeaf:        timeoutLeaf,
+		TapscriptScriptKey: key,
+	}, nil
+}
+
+// leaf returns the leaf of the given spend path.
+func (h *HtlcScriptKey) leaf(path HtlcPath) (txscript.TapLeaf, error) {
+	switch path {
+	case HtlcPathSuccess:
+		return h.SuccessLeaf, nil
+
+	case HtlcPathTimeout:
+		return h.TimeoutLeaf, nil
+
+	default:
+		return txscript.TapLeaf{}, fmt.Errorf("unknown htlc path: %v",
+			path)
+	}
+}
+
+// TapLeafScript returns the leaf script of the given spend path, including its
+// control block. This should be set as the Taproot leaf script of a virtual
+// input to obtain the signature hash of the spend path, for example with
+// VirtualTxSigHashes.
+func (h *HtlcScriptKey) TapLeafScript(
+	path HtlcPath) (*psbt.TaprootTapLeafScript, error) {
+
+	leaf, err := h.leaf(path)
+	if err != nil {
+		return nil, err
+	}
+
+	controlBlock, err := h.ControlBlock(leaf)
+	if err != nil {
+		return nil, err
+	}
+
+	return &psbt.TaprootTapLeafScript{
+		ControlBlock: controlBlock,
+		Script:       leaf.Script,
+		LeafVersion:  leaf.LeafVersion,
+	}, nil
+}
+
+// CheckTimeout returns an error if the lock time of the given asset doesn't
+// satisfy the CLTV expiry of the HTLC, which means the sender can't reclaim it
+// yet. The lock time of the asset is what the virtual transaction is created
+// with.
+func (h *HtlcScriptKey) CheckTimeout(a *asset.Asset) error {
+	if a.LockTime < uint64(h.CltvExpiry) {
+		return fmt.Errorf("%w: asset lock time %d below expiry %d",
+			ErrHtlcNotExpired, a.LockTime, h.CltvExpiry)
+	}
+
+	return nil
+}
+
+// SuccessWitness returns the witness that spends the asset through the success
+// path with the given signature of the receiver and the preimage of the payment
+// hash.
+func (h *HtlcScriptKey) SuccessWitness(sig []byte,
+	preimage []byte) (wire.TxWitness, error) {
+
+	if sha256.Sum256(preimage) != h.PaymentHash {
+		return nil, ErrInvalidPreimage
+	}
+
+	return h.witness(HtlcPathSuccess, sig, preimage)
+}
+
+// TimeoutWitness returns the witness that spends the asset through the timeout
+// path with the given signature of the sender.
+func (h *HtlcScriptKey) TimeoutWitness(sig []byte) (wire.TxWitness, error) {
+	return h.witness(HtlcPathTimeout, sig)
+}
+
+// witness assembles the witness for the given spend path from the signature,
+// the additional stack elements, the leaf script and its control block.
+func (h *HtlcScriptKey) witness(path HtlcPath, sig []byte,
+	stack ...[]byte) (wire.TxWitness, error) {
+
+	if len(sig) != schnorr.SignatureSize &&
+		len(sig) != schnorr.SignatureSize+1 {
+
+		return nil, fmt.Errorf("invalid signature length %d", len(sig))
+	}
+
+	leafScript, err := h.TapLeafScript(path)
+	if err != nil {
+		return nil, err
+	}
+
+	witness := wire.TxWitness{sig}
+	witness = append(witness, stack...)
+	witness = append(witness, leafScript.Script, leafScript.ControlBlock)
+
+	return witness, nil
+}
@@ -0,0 +1,135 @@
+package tapscript_test
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/wire"
+	tap "github.com/lightninglabs/taproot-assets"
+	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightninglabs/taproot-assets/internal/test"
+	"github.com/lightninglabs/taproot-assets/tapscript"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/stretchr/testify/require"
+)
+
+// TestHtlcScriptKey tests that an asset locked to an HTLC script key can be
+// spent by the receiver with the preimage and by the sender after the timeout.
+func TestHtlcScriptKey(t *testing.T) {
+	t.Parallel()
+
+	var (
+		receiverPrivKey = test.RandPrivKey(t)
+		senderPrivKey   = test.RandPrivKey(t)
+		preimage        = test.RandBytes(32)
+		paymentHash     = sha256.Sum256(preimage)
+		expiry          = uint32(800_000)
+		validator       = &tap.ValidatorV0{}
+		internalKey     = keychain.KeyDescriptor{
+			PubKey: asset.NUMSPubKey,
+		}
+	)
+
+	_, err := tapscript.NewHtlcScriptKey(
+		internalKey, paymentHash, receiverPrivKey.PubKey(),
+		receiverPrivKey.PubKey(), expiry,
+	)
+	require.ErrorContains(t, err, "must differ")
+
+	_, err = tapscript.NewHtlcScriptKey(
+		internalKey, paymentHash, receiverPrivKey.PubKey(),
+		senderPrivKey.PubKey(), 0,
+	)
+	require.ErrorContains(t, err, "cltv expiry")
+
+	htlc, err := tapscript.NewHtlcScriptKey(
+		internalKey, paymentHash, receiverPrivKey.PubKey(),
+		senderPrivKey.PubKey(), expiry,
+	)
+	require.NoError(t, err)
+
+	// spend attempts to spend an asset with the given lock time through
+	// the given path. The witness is created from a signature over the
+	// sighash of the path.
+	spend := func(path tapscript.HtlcPath, lockTime uint64,
+		createWitness func(sig []byte) (wire.TxWitness, error)) error {
+
+		leaf := htlc.SuccessLeaf
+		privKey := receiverPrivKey
+		if path == tapscript.HtlcPathTimeout {
+			leaf = htlc.TimeoutLeaf
+			privKey = senderPrivKey
+		}
+
+		vPkt := scriptKeyPacket(
+			t, htlc.TapscriptScriptKey, leaf, lockTime, 0,
+		)
+		leafScript, err := htlc.TapLeafScript(path)
+		require.NoError(t, err)
+		vPkt.Inputs[0].TaprootLeafScript = []*psbt.TaprootTapLeafScript{
+			leafScript,
+		}
+
+		sigHashes, err := tapscript.VirtualTxSigHashes(vPkt)
+		require.NoError(t, err)
+		sig, err := schnorr.Sign(privKey, sigHashes[0])
+		require.NoError(t, err)
+
+		witness, err := createWitness(sig.Serialize())
+		if err != nil {
+			return err
+		}
+
+		return tapscript.AddVirtualTxWitnesses(
+			vPkt, []wire.TxWitness{witness}, validator,
+		)
+	}
+
+	t.Run("success with preimage", func(t *testing.T) {
+		err := spend(
+			tapscript.HtlcPathSuccess, 0,
+			func(sig []byte) (wire.TxWitness, error) {
+				return htlc.SuccessWitness(sig, preimage)
+			},
+		)
+		require.NoError(t, err)
+	})
+
+	t.Run("success with wrong preimage", func(t *testing.T) {
+		err := spend(
+			tapscript.HtlcPathSuccess, 0,
+			func(sig []byte) (wire.TxWitness, error) {
+				return htlc.SuccessWitness(
+					sig, test.RandBytes(32),
+				)
+			},
+		)
+		require.ErrorIs(t, err, tapscript.ErrInvalidPreimage)
+	})
+
+	t.Run("timeout after expiry", func(t *testing.T) {
+		err := spend(
+			tapscript.HtlcPathTimeout, uint64(expiry),
+			htlc.TimeoutWitness,
+		)
+		require.NoError(t, err)
+	})
+
+	t.Run("timeout before expiry", func(t *testing.T) {
+		inputAsset := &asset.Asset{LockTime: uint64(expiry - 1)}
+		require.ErrorIs(
+			t, htlc.CheckTimeout(inputAsset),
+			tapscript.ErrHtlcNotExpired,
+		)
+
+		// The expiry is enforced by the script itself, so skipping the
+		// check doesn't help the sender.
+		err := spend(
+			tapscript.HtlcPathTimeout, uint64(expiry-1),
+			htlc.TimeoutWitness,
+		)
+		require.Error(t, err)
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/lightninglabs/lndclient"
 	"github.com/lightninglabs/taproot-assets/asset"
 	"github.com/lightninglabs/taproot-assets/commitment"
+	"github.com/lightninglabs/taproot-assets/tappsbt"
 )
 
 // TxValidator is the interface used to validate an asset transfer
@@ -25,3 +26,18 @@ type Signer interface {
 	SignVirtualTx(signDesc *lndclient.SignDescriptor, tx *wire.MsgTx,
 		prevOut *wire.TxOut) (*schnorr.Signature, error)
 }
+
+// PacketSigner is a Signer that can additionally be given the virtual packet
+// a virtual TX was created from. This allows a signer that doesn't trust its
+// caller, such as a remote signer, to inspect which assets are spent and
+// created by the transfer before signing it.
+type PacketSigner interface {
+	Signer
+
+	// SignVirtualPacketInput generates a signature for the input with the
+	// given index of the virtual packet, according to the passed signing
+	// descriptor and the virtual TX of the input.
+	SignVirtualPacketInput(vPkt *tappsbt.VPacket, inputIdx int,
+		signDesc *lndclient.SignDescriptor, tx *wire.MsgTx,
+		prevOut *wire.TxOut) (*schnorr.Signature, error)
+}
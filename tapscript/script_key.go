@@ -0,0 +1,278 @@
+package tapscript
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightningnetwork/lnd/keychain"
+)
+
+// MultiSigLeafScript returns a leaf that requires valid signatures from at
+// least `threshold` of the given keys. The signatures must be provided in the
+// reverse order of the keys, with an empty element for each key that doesn't
+// sign.
+func MultiSigLeafScript(threshold int,
+	keys ...*btcec.PublicKey) (txscript.TapLeaf, error) {
+
+	if len(keys) == 0 {
+		return txscript.TapLeaf{}, fmt.Errorf("no multisig keys")
+	}
+	if threshold < 1 || threshold > len(keys) {
+		return txscript.TapLeaf{}, fmt.Errorf("invalid multisig "+
+			"threshold %d for %d keys", threshold, len(keys))
+	}
+
+	builder := txscript.NewScriptBuilder()
+	seen := make(map[[32]byte]struct{}, len(keys))
+	for idx, key := range keys {
+		if key == nil {
+			return txscript.TapLeaf{}, fmt.Errorf("multisig key "+
+				"%d not set", idx)
+		}
+
+		var xOnly [32]byte
+		copy(xOnly[:], schnorr.SerializePubKey(key))
+		if _, ok := seen[xOnly]; ok {
+			return txscript.TapLeaf{}, fmt.Errorf("duplicate "+
+				"multisig key %x", xOnly[:])
+		}
+		seen[xOnly] = struct{}{}
+
+		builder.AddData(xOnly[:])
+		if idx == 0 {
+			builder.AddOp(txscript.OP_CHECKSIG)
+		} else {
+			builder.AddOp(txscript.OP_CHECKSIGADD)
+		}
+	}
+	builder.AddInt64(int64(threshold))
+	builder.AddOp(txscript.OP_NUMEQUAL)
+
+	script, err := builder.Script()
+	if err != nil {
+		return txscript.TapLeaf{}, err
+	}
+
+	return txscript.NewBaseTapLeaf(script), nil
+}
+
+// HashLockLeafScript returns a leaf that lets the given key spend with a single
+// signature, once the 32-byte preimage of the given SHA256 hash is revealed.
+func HashLockLeafScript(hash [sha256.Size]byte,
+	key *btcec.PublicKey) (txscript.TapLeaf, error) {
+
+	if key == nil {
+		return txscript.TapLeaf{}, fmt.Errorf("hash lock key not set")
+	}
+
+	script, err := txscript.NewScriptBuilder().
+		AddOp(txscript.OP_SIZE).
+		AddInt64(sha256.Size).
+		AddOp(txscript.OP_EQUALVERIFY).
+		AddOp(txscript.OP_SHA256).
+		AddData(hash[:]).
+		AddOp(txscript.OP_EQUALVERIFY).
+		AddData(schnorr.SerializePubKey(key)).
+		AddOp(txscript.OP_CHECKSIG).
+		Script()
+	if err != nil {
+		return txscript.TapLeaf{}, err
+	}
+
+	return txscript.NewBaseTapLeaf(script), nil
+}
+
+// TimeoutLeafScript returns a leaf that lets the given key spend with a single
+// signature, once the given relative lock time (in BIP-0068 sequence encoding)
+// is reached.
+func TimeoutLeafScript(key *btcec.PublicKey,
+	relativeLockTime uint32) (txscript.TapLeaf, error) {
+
+	if key == nil {
+		return txscript.TapLeaf{}, fmt.Errorf("timeout key not set")
+	}
+	if relativeLockTime == 0 {
+		return txscript.TapLeaf{}, fmt.Errorf("timeout must be set")
+	}
+
+	return OperatorLeafScript(key, DelegationLimits{
+		RelativeLockTime: uint64(relativeLockTime),
+	})
+}
+
+// TapscriptScriptKey is a script key that commits to a tapscript tree of
+// spending conditions, for example a multisig or a hash lock with a timeout.
+type TapscriptScriptKey struct {
+	// InternalKey is the internal key of the script key, which can always
+	// be used to spend through the key spend path.
+	InternalKey keychain.KeyDescriptor
+
+	// Leaves are the leaves of the tapscript tree, in the order they were
+	// added to the builder.
+	Leaves []txscript.TapLeaf
+
+	// Tree is the tapscript tree that commits to all leaves.
+	Tree *txscript.IndexedTapScriptTree
+
+	// ScriptKey is the resulting script key that assets can be sent to.
+	// The leaves are stored along with the tweak, so the control block of
+	// each leaf can be re-created after the key is persisted.
+	ScriptKey asset.ScriptKey
+}
+
+// NewTapscriptScriptKey creates a new script key from the given internal key
+// and tapscript leaves.
+func NewTapscriptScriptKey(internalKey keychain.KeyDescriptor,
+	leaves ...txscript.TapLeaf) (*TapscriptScriptKey, error) {
+
+	if internalKey.PubKey == nil {
+		return nil, fmt.Errorf("internal key must be set")
+	}
+	if len(leaves) == 0 {
+		return nil, fmt.Errorf("at least one leaf is required")
+	}
+
+	seen := make(map[[32]byte]struct{}, len(leaves))
+	for _, leaf := range leaves {
+		leafHash := leaf.TapHash()
+		if _, ok := seen[leafHash]; ok {
+			return nil, fmt.Errorf("duplicate leaf %x", leafHash[:])
+		}
+		seen[leafHash] = struct{}{}
+	}
+
+	tree := txscript.AssembleTaprootScriptTree(leaves...)
+	rootHash := tree.RootNode.TapHash()
+	outputKey := txscript.ComputeTaprootOutputKey(
+		internalKey.PubKey, rootHash[:],
+	)
+
+	leavesCopy := make([]txscript.TapLeaf, len(leaves))
+	copy(leavesCopy, leaves)
+
+	return &TapscriptScriptKey{
+		InternalKey: internalKey,
+		Leaves:      leavesCopy,
+		Tree:        tree,
+		ScriptKey: asset.ScriptKey{
+			PubKey: outputKey,
+			TweakedScriptKey: &asset.TweakedScriptKey{
+				RawKey: internalKey,
+				Tweak:  rootHash[:],
+				Leaves: leavesCopy,
+			},
+		},
+	}, nil
+}
+
+// TapscriptScriptKeyFromTweaked re-creates a script key from a tweaked script
+// key with persisted leaves, for example to spend an asset through the script
+// path after the key was loaded from the database.
+func TapscriptScriptKeyFromTweaked(
+	tweaked *asset.TweakedScriptKey) (*TapscriptScriptKey, error) {
+
+	if tweaked == nil || len(tweaked.Leaves) == 0 {
+		return nil, fmt.Errorf("script key has no tapscript leaves")
+	}
+
+	key, err := NewTapscriptScriptKey(tweaked.RawKey, tweaked.Leaves...)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(key.ScriptKey.Tweak, tweaked.Tweak) {
+		return nil, fmt.Errorf("tapscript leaves don't match script " +
+			"key tweak")
+	}
+
+	return key, nil
+}
+
+// ControlBlock returns the serialized control block for spending the given
+// leaf of the script key.
+func (k *TapscriptScriptKey) ControlBlock(
+	leaf txscript.TapLeaf) ([]byte, error) {
+
+	proofIdx, ok := k.Tree.LeafProofIndex[leaf.TapHash()]
+	if !ok {
+		return nil, fmt.Errorf("leaf not part of tapscript tree")
+	}
+
+	controlBlock := k.Tree.LeafMerkleProofs[proofIdx].ToControlBlock(
+		k.InternalKey.PubKey,
+	)
+
+	return controlBlock.ToBytes()
+}
+
+// ScriptKeyBuilder assembles a script key from common spending conditions. The
+// leaves are added to the tapscript tree in the order the builder methods are
+// called.
+type ScriptKeyBuilder struct {
+	internalKey keychain.KeyDescriptor
+	leaves      []txscript.TapLeaf
+}
+
+// NewScriptKeyBuilder creates a new builder for a script key with the given
+// internal key. To create a script key that can only be spent through the
+// script path, an un-spendable internal key such as asset.NUMSPubKey should be
+// used.
+func NewScriptKeyBuilder(
+	internalKey keychain.KeyDescriptor) *ScriptKeyBuilder {
+
+	return &ScriptKeyBuilder{
+		internalKey: internalKey,
+	}
+}
+
+// AddLeaf adds a custom leaf to the tapscript tree of the script key.
+func (b *ScriptKeyBuilder) AddLeaf(leaf txscript.TapLeaf) {
+	b.leaves = append(b.leaves, leaf)
+}
+
+// AddMultiSig adds a k-of-n multisig leaf to the tapscript tree of the script
+// key.
+func (b *ScriptKeyBuilder) AddMultiSig(threshold int,
+	keys ...*btcec.PublicKey) error {
+
+	leaf, err := MultiSigLeafScript(threshold, keys...)
+	if err != nil {
+		return fmt.Errorf("unable to create multisig leaf: %w", err)
+	}
+
+	b.AddLeaf(leaf)
+
+	return nil
+}
+
+// AddHashLockWithTimeout adds a hash lock leaf for the receiver and a timeout
+// leaf for the sender to the tapscript tree of the script key. The receiver can
+// spend by revealing the preimage of the given hash, the sender can reclaim the
+// asset once the relative lock time is reached.
+func (b *ScriptKeyBuilder) AddHashLockWithTimeout(hash [sha256.Size]byte,
+	receiver, sender *btcec.PublicKey, relativeLockTime uint32) error {
+
+	hashLockLeaf, err := HashLockLeafScript(hash, receiver)
+	if err != nil {
+		return fmt.Errorf("unable to create hash lock leaf: %w", err)
+	}
+	timeoutLeaf, err := TimeoutLeafScript(sender, relativeLockTime)
+	if err != nil {
+		return fmt.Errorf("unable to create timeout leaf: %w", err)
+	}
+
+	b.AddLeaf(hashLockLeaf)
+	b.AddLeaf(timeoutLeaf)
+
+	return nil
+}
+
+// Build creates the script key that commits to all leaves added so far.
+func (b *ScriptKeyBuilder) Build() (*TapscriptScriptKey, error) {
+	return NewTapscriptScriptKey(b.internalKey, b.leaves...)
+}
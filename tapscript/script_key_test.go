@@ -0,0 +1,185 @@
+package tapscript_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	tap "github.com/lightninglabs/taproot-assets"
+	"github.com/lightninglabs/taproot-assets/address"
+	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightninglabs/taproot-assets/internal/test"
+	"github.com/lightninglabs/taproot-assets/tappsbt"
+	"github.com/lightninglabs/taproot-assets/tapscript"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptKeyPacket creates a packet that spends an asset locked to the given
+// script key with the given lock times through the given leaf.
+func scriptKeyPacket(t *testing.T, key *tapscript.TapscriptScriptKey,
+	leaf txscript.TapLeaf, lockTime, relLockTime uint64) *tappsbt.VPacket {
+
+	inputAsset, err := asset.New(
+		asset.RandGenesis(t, asset.Normal), 10, lockTime, relLockTime,
+		key.ScriptKey, nil,
+	)
+	require.NoError(t, err)
+
+	vPkt := &tappsbt.VPacket{
+		Inputs: []*tappsbt.VInput{{
+			PrevID: asset.PrevID{
+				OutPoint: test.RandOp(t),
+				ID:       inputAsset.ID(),
+				ScriptKey: asset.ToSerialized(
+					key.ScriptKey.PubKey,
+				),
+			},
+		}},
+		Outputs: []*tappsbt.VOutput{{
+			Interactive: true,
+			Amount:      inputAsset.Amount,
+			ScriptKey: asset.NewScriptKey(
+				test.RandPubKey(t),
+			),
+			AnchorOutputInternalKey: test.RandPubKey(t),
+		}},
+		ChainParams: &address.MainNetTap,
+	}
+	vPkt.SetInputAsset(0, inputAsset, nil)
+
+	controlBlock, err := key.ControlBlock(leaf)
+	require.NoError(t, err)
+	vPkt.Inputs[0].TaprootLeafScript = []*psbt.TaprootTapLeafScript{{
+		ControlBlock: controlBlock,
+		Script:       leaf.Script,
+		LeafVersion:  leaf.LeafVersion,
+	}}
+
+	err = tapscript.PrepareOutputAssets(context.Background(), vPkt)
+	require.NoError(t, err)
+
+	return vPkt
+}
+
+// TestScriptKeyBuilder tests that assets locked to script keys created by the
+// script key builder can be spent through each of the leaves.
+func TestScriptKeyBuilder(t *testing.T) {
+	t.Parallel()
+
+	var (
+		privKeys  = make([]*btcec.PrivateKey, 3)
+		pubKeys   = make([]*btcec.PublicKey, 3)
+		preimage  = test.RandBytes(32)
+		hash      = sha256.Sum256(preimage)
+		timeout   = uint32(144)
+		validator = &tap.ValidatorV0{}
+	)
+	for idx := range privKeys {
+		privKeys[idx] = test.RandPrivKey(t)
+		pubKeys[idx] = privKeys[idx].PubKey()
+	}
+	internalKey := keychain.KeyDescriptor{
+		PubKey: asset.NUMSPubKey,
+	}
+
+	builder := tapscript.NewScriptKeyBuilder(internalKey)
+	require.ErrorContains(
+		t, builder.AddMultiSig(4, pubKeys...), "invalid multisig",
+	)
+	require.ErrorContains(
+		t, builder.AddMultiSig(1, pubKeys[0], pubKeys[0]), "duplicate",
+	)
+	require.NoError(t, builder.AddMultiSig(2, pubKeys...))
+	require.NoError(t, builder.AddHashLockWithTimeout(
+		hash, pubKeys[0], pubKeys[1], timeout,
+	))
+
+	key, err := builder.Build()
+	require.NoError(t, err)
+	require.Len(t, key.Leaves, 3)
+	require.Equal(t, key.Leaves, key.ScriptKey.Leaves)
+
+	// The key can be re-created from the persisted tweaked script key.
+	restored, err := tapscript.TapscriptScriptKeyFromTweaked(
+		key.ScriptKey.TweakedScriptKey,
+	)
+	require.NoError(t, err)
+	require.True(t, restored.ScriptKey.PubKey.IsEqual(key.ScriptKey.PubKey))
+
+	_, err = tapscript.TapscriptScriptKeyFromTweaked(
+		&asset.TweakedScriptKey{
+			RawKey: internalKey,
+			Tweak:  key.ScriptKey.Tweak,
+			Leaves: key.Leaves[:2],
+		},
+	)
+	require.ErrorContains(t, err, "don't match")
+
+	sign := func(vPkt *tappsbt.VPacket, privKey *btcec.PrivateKey) []byte {
+		sigHashes, err := tapscript.VirtualTxSigHashes(vPkt)
+		require.NoError(t, err)
+
+		sig, err := schnorr.Sign(privKey, sigHashes[0])
+		require.NoError(t, err)
+
+		return sig.Serialize()
+	}
+	spend := func(vPkt *tappsbt.VPacket, leaf txscript.TapLeaf,
+		stack ...[]byte) error {
+
+		controlBlock, err := key.ControlBlock(leaf)
+		require.NoError(t, err)
+
+		witness := wire.TxWitness(stack)
+		witness = append(witness, leaf.Script, controlBlock)
+
+		return tapscript.AddVirtualTxWitnesses(
+			vPkt, []wire.TxWitness{witness}, validator,
+		)
+	}
+
+	multiSigLeaf, hashLockLeaf, timeoutLeaf := key.Leaves[0], key.Leaves[1],
+		key.Leaves[2]
+
+	t.Run("multisig", func(t *testing.T) {
+		vPkt := scriptKeyPacket(t, key, multiSigLeaf, 0, 0)
+
+		// The signatures are provided in the reverse order of the keys.
+		sig0, sig2 := sign(vPkt, privKeys[0]), sign(vPkt, privKeys[2])
+		require.NoError(t, spend(vPkt, multiSigLeaf, sig2, nil, sig0))
+
+		vPkt = scriptKeyPacket(t, key, multiSigLeaf, 0, 0)
+		sig0 = sign(vPkt, privKeys[0])
+		require.Error(t, spend(vPkt, multiSigLeaf, nil, nil, sig0))
+	})
+
+	t.Run("hash lock", func(t *testing.T) {
+		vPkt := scriptKeyPacket(t, key, hashLockLeaf, 0, 0)
+		sig := sign(vPkt, privKeys[0])
+		require.NoError(t, spend(vPkt, hashLockLeaf, sig, preimage))
+
+		vPkt = scriptKeyPacket(t, key, hashLockLeaf, 0, 0)
+		sig = sign(vPkt, privKeys[0])
+		require.Error(
+			t, spend(vPkt, hashLockLeaf, sig, test.RandBytes(32)),
+		)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		vPkt := scriptKeyPacket(t, key, timeoutLeaf, 0, uint64(timeout))
+		sig := sign(vPkt, privKeys[1])
+		require.NoError(t, spend(vPkt, timeoutLeaf, sig))
+
+		vPkt = scriptKeyPacket(
+			t, key, timeoutLeaf, 0, uint64(timeout-1),
+		)
+		sig = sign(vPkt, privKeys[1])
+		require.Error(t, spend(vPkt, timeoutLeaf, sig))
+	})
+}
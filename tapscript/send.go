@@ -14,11 +14,14 @@ import (
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/btcutil/psbt"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
+	"github.com/lightninglabs/lndclient"
 	"github.com/lightninglabs/taproot-assets/address"
 	"github.com/lightninglabs/taproot-assets/asset"
 	"github.com/lightninglabs/taproot-assets/commitment"
 	"github.com/lightninglabs/taproot-assets/tappsbt"
+	"github.com/lightningnetwork/lnd/keychain"
 	"golang.org/x/exp/slices"
 )
 
@@ -133,6 +136,14 @@ type FundingDescriptor struct {
 
 	// Amount is the amount of the asset to transfer.
 	Amount uint64
+
+	// Edition is the optional edition number of the collectible to
+	// transfer. If zero, any edition may be selected.
+	Edition uint64
+
+	// PrevIDs is an optional list of asset inputs that must be spent to
+	// fund the transfer. If empty, coin selection picks the inputs.
+	PrevIDs []asset.PrevID
 }
 
 // TapCommitmentKey is the key that maps to the root commitment for the asset
@@ -502,13 +513,7 @@ func PrepareOutputAssets(ctx context.Context, vPkt *tappsbt.VPacket) error {
 		// Record the PrevID of the input asset in a Witness for the new
 		// asset. This Witness still needs a valid signature for the new
 		// asset to be valid.
-		outputs[recipientIndex].Asset.PrevWitnesses = []asset.Witness{
-			{
-				PrevID:          &input.PrevID,
-				TxWitness:       nil,
-				SplitCommitment: nil,
-			},
-		}
+		outputs[recipientIndex].Asset.ReplaceWitnesses(input.PrevID)
 
 		// We are done, since we don't need to create a split
 		// commitment.
@@ -576,66 +581,298 @@ func PrepareOutputAssets(ctx context.Context, vPkt *tappsbt.VPacket) error {
 func SignVirtualTransaction(vPkt *tappsbt.VPacket, signer Signer,
 	validator TxValidator) error {
 
-	inputs := vPkt.Inputs
-	outputs := vPkt.Outputs
+	newAsset, prevAssets, isSplit, err := spendAssets(vPkt)
+	if err != nil {
+		return err
+	}
+
+	// Create a Taproot Asset virtual transaction representing the asset
+	// transfer.
+	virtualTx, _, err := VirtualTx(newAsset, prevAssets)
+	if err != nil {
+		return err
+	}
+
+	packetSigner, withPacket := signer.(PacketSigner)
+
+	for idx := range vPkt.Inputs {
+		input := vPkt.Inputs[idx]
+
+		// A signer that wants to inspect the transfer is given the
+		// packet along with the virtual TX of each input.
+		inputSigner := signer
+		if withPacket {
+			inputSigner = &packetInputSigner{
+				PacketSigner: packetSigner,
+				vPkt:         vPkt,
+				inputIdx:     idx,
+			}
+		}
+
+		// For each input asset leaf, we need to produce a witness.
+		// Update the input of the virtual TX, generate a witness, and
+		// attach it to the copy of the new Asset.
+		virtualTxCopy := virtualTx.Copy()
+		inputSpecificVirtualTx, err := VirtualTxWithInputSigHash(
+			virtualTxCopy, input.Asset(), input.PrevID, uint32(idx),
+			input.SighashType, nil,
+		)
+		if err != nil {
+			return fmt.Errorf("error preparing virtual tx for "+
+				"input %d: %w", idx, err)
+		}
+
+		// Sign the virtual transaction based on the input script
+		// information (key spend or script spend).
+		newWitness, err := CreateTaprootSignature(
+			input, inputSpecificVirtualTx, 0, inputSigner,
+		)
+		if err != nil {
+			return fmt.Errorf("error creating taproot "+
+				"signature: %w", err)
+		}
+
+		newAsset.PrevWitnesses[idx].TxWitness = newWitness
+	}
+
+	return finalizeSpend(vPkt, newAsset, prevAssets, isSplit, validator)
+}
+
+// packetInputSigner is a Signer that passes the virtual packet and the index
+// of the input being signed on to a PacketSigner.
+type packetInputSigner struct {
+	PacketSigner
+
+	vPkt     *tappsbt.VPacket
+	inputIdx int
+}
+
+// SignVirtualTx generates a signature according to the passed signing
+// descriptor and virtual TX of the input.
+//
+// NOTE: This is part of the Signer interface.
+func (p *packetInputSigner) SignVirtualTx(signDesc *lndclient.SignDescriptor,
+	tx *wire.MsgTx, prevOut *wire.TxOut) (*schnorr.Signature, error) {
+
+	return p.SignVirtualPacketInput(
+		p.vPkt, p.inputIdx, signDesc, tx, prevOut,
+	)
+}
+
+// InputVirtualTx returns the virtual TX of the given packet that is signed for
+// the input with the given index. A signer that is given the packet can use
+// it to make sure the virtual TX it is asked to sign spends and creates
+// exactly the assets of the packet.
+func InputVirtualTx(vPkt *tappsbt.VPacket, idx int) (*wire.MsgTx, error) {
+	if idx < 0 || idx >= len(vPkt.Inputs) {
+		return nil, fmt.Errorf("invalid input index %d", idx)
+	}
+
+	newAsset, prevAssets, _, err := spendAssets(vPkt)
+	if err != nil {
+		return nil, err
+	}
+
+	virtualTx, _, err := VirtualTx(newAsset, prevAssets)
+	if err != nil {
+		return nil, err
+	}
+
+	vIn := vPkt.Inputs[idx]
+	return VirtualTxWithInputSigHash(
+		virtualTx, vIn.Asset(), vIn.PrevID, uint32(idx),
+		vIn.SighashType, nil,
+	)
+}
+
+// VirtualTxSigHashes returns the signature hash of the virtual transaction of
+// the given packet for each of its inputs, in the order of the inputs. Inputs
+// that specify exactly one Taproot leaf script are spent through the script
+// path, all other inputs through the key path. This allows the script keys of
+// the inputs to be held by an external signer.
+func VirtualTxSigHashes(vPkt *tappsbt.VPacket) ([][]byte, error) {
+	newAsset, prevAssets, _, err := spendAssets(vPkt)
+	if err != nil {
+		return nil, err
+	}
+
+	virtualTx, _, err := VirtualTx(newAsset, prevAssets)
+	if err != nil {
+		return nil, err
+	}
+
+	sigHashes := make([][]byte, len(vPkt.Inputs))
+	for idx := range vPkt.Inputs {
+		vIn := vPkt.Inputs[idx]
+
+		if len(vIn.TaprootLeafScript) == 1 {
+			leafScript := vIn.TaprootLeafScript[0]
+			leaf := txscript.TapLeaf{
+				LeafVersion: leafScript.LeafVersion,
+				Script:      leafScript.Script,
+			}
+			sigHashes[idx], err = InputScriptSpendSigHash(
+				virtualTx, vIn.Asset(), vIn.PrevID,
+				uint32(idx), vIn.SighashType, &leaf,
+			)
+		} else {
+			sigHashes[idx], err = InputKeySpendSigHash(
+				virtualTx, vIn.Asset(), vIn.PrevID,
+				uint32(idx), vIn.SighashType,
+			)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to compute sighash "+
+				"for input %d: %w", idx, err)
+		}
+	}
+
+	return sigHashes, nil
+}
+
+// AddVirtualTxWitnesses attaches the given witnesses, one for each input and
+// in the order of the inputs, to the virtual transaction of the given packet.
+// The witnesses are usually created by an external signer over the signature
+// hashes returned by VirtualTxSigHashes. The resulting transfer is validated
+// with the Taproot Asset VM, so the packet is ready to be anchored on success.
+func AddVirtualTxWitnesses(vPkt *tappsbt.VPacket, witnesses []wire.TxWitness,
+	validator TxValidator) error {
+
+	if len(witnesses) != len(vPkt.Inputs) {
+		return fmt.Errorf("expected %d witnesses, got %d",
+			len(vPkt.Inputs), len(witnesses))
+	}
+
+	newAsset, prevAssets, isSplit, err := spendAssets(vPkt)
+	if err != nil {
+		return err
+	}
+
+	for idx := range witnesses {
+		if len(witnesses[idx]) == 0 {
+			return fmt.Errorf("witness for input %d is empty", idx)
+		}
+
+		newAsset.PrevWitnesses[idx].TxWitness = witnesses[idx]
+	}
+
+	return finalizeSpend(vPkt, newAsset, prevAssets, isSplit, validator)
+}
+
+// VerifyVirtualTransaction verifies the witnesses of an already signed virtual
+// transaction with the Taproot Asset VM. This can be used by the receiver of an
+// interactive transfer to make sure the packet of the sender is valid before
+// it is anchored on chain.
+func VerifyVirtualTransaction(vPkt *tappsbt.VPacket,
+	validator TxValidator) error {
+
+	newAsset, prevAssets, isSplit, err := spendAssets(vPkt)
+	if err != nil {
+		return err
+	}
+
+	return validateSpend(vPkt, newAsset, prevAssets, isSplit, validator)
+}
+
+// spendAssets returns the new asset of the given packet that carries the
+// witnesses of the transfer and the set of assets it spends. For splits, the
+// new asset is the split root asset.
+func spendAssets(vPkt *tappsbt.VPacket) (*asset.Asset, commitment.InputSet,
+	bool, error) {
+
+	if len(vPkt.Inputs) == 0 || len(vPkt.Outputs) == 0 {
+		return nil, nil, false, fmt.Errorf("packet must have at " +
+			"least one input and one output")
+	}
 
 	// If this is a split transfer, it means that the asset to be signed is
 	// the root asset, which is located at the change output.
 	isSplit, err := vPkt.HasSplitCommitment()
 	if err != nil {
-		return err
+		return nil, nil, false, err
 	}
 
 	// Identify new output asset. For splits, the new asset that receives
 	// the signature is the one with the split root set to true.
-	newAsset := outputs[0].Asset
+	newAsset := vPkt.Outputs[0].Asset
 	if isSplit {
 		splitOut, err := vPkt.SplitRootOutput()
 		if err != nil {
-			return fmt.Errorf("no split root output found for "+
-				"split transaction: %w", err)
+			return nil, nil, false, fmt.Errorf("no split root "+
+				"output found for split transaction: %w", err)
 		}
 		newAsset = splitOut.Asset
 	}
+	if newAsset == nil {
+		return nil, nil, false, fmt.Errorf("packet output asset " +
+			"missing")
+	}
+	if len(newAsset.PrevWitnesses) != len(vPkt.Inputs) {
+		return nil, nil, false, fmt.Errorf("packet output asset has "+
+			"%d witnesses, expected %d",
+			len(newAsset.PrevWitnesses), len(vPkt.Inputs))
+	}
 
 	// Construct input set from all input assets.
-	prevAssets := make(commitment.InputSet, len(inputs))
+	prevAssets := make(commitment.InputSet, len(vPkt.Inputs))
 	for idx := range vPkt.Inputs {
 		input := vPkt.Inputs[idx]
+		if input.Asset() == nil {
+			return nil, nil, false, fmt.Errorf("input %d asset "+
+				"missing", idx)
+		}
+
 		prevAssets[input.PrevID] = input.Asset()
 	}
 
-	// Create a Taproot Asset virtual transaction representing the asset
-	// transfer.
-	virtualTx, _, err := VirtualTx(newAsset, prevAssets)
+	return newAsset, prevAssets, isSplit, nil
+}
+
+// finalizeSpend validates the new asset with its witnesses attached and, for
+// splits, updates each split asset to store the signed root asset.
+func finalizeSpend(vPkt *tappsbt.VPacket, newAsset *asset.Asset,
+	prevAssets commitment.InputSet, isSplit bool,
+	validator TxValidator) error {
+
+	// Validate the transfer with the witnesses we just attached.
+	err := validateSpend(vPkt, newAsset, prevAssets, isSplit, validator)
 	if err != nil {
 		return err
 	}
 
-	for idx := range inputs {
-		input := inputs[idx]
+	// If the transfer contains no asset splits, we're done.
+	if !isSplit {
+		return nil
+	}
 
-		// For each input asset leaf, we need to produce a witness.
-		// Update the input of the virtual TX, generate a witness, and
-		// attach it to the copy of the new Asset.
-		virtualTxCopy := virtualTx.Copy()
-		inputSpecificVirtualTx := VirtualTxWithInput(
-			virtualTxCopy, input.Asset(), uint32(idx), nil,
-		)
+	// Update each split asset to store the root asset with the witness
+	// attached, so the receiver can verify inclusion of the root asset.
+	for idx := range vPkt.Outputs {
+		splitAsset := vPkt.Outputs[idx].Asset
 
-		// Sign the virtual transaction based on the input script
-		// information (key spend or script spend).
-		newWitness, err := CreateTaprootSignature(
-			input, inputSpecificVirtualTx, 0, signer,
-		)
-		if err != nil {
-			return fmt.Errorf("error creating taproot "+
-				"signature: %w", err)
+		// The output that houses the root asset in case of a split has
+		// a special field for the split asset. That asset is no longer
+		// needed (and isn't committed to anywhere), but in order for it
+		// to be validated externally, we still want to include it and
+		// therefore also want to update it with the signed root asset.
+		if vPkt.Outputs[idx].Type.IsSplitRoot() {
+			splitAsset = vPkt.Outputs[idx].SplitAsset
 		}
 
-		newAsset.PrevWitnesses[idx].TxWitness = newWitness
+		splitCommitment := splitAsset.PrevWitnesses[0].SplitCommitment
+		splitCommitment.RootAsset = *newAsset.Copy()
 	}
 
+	return nil
+}
+
+// validateSpend validates the given new asset with its witnesses attached with
+// the Taproot Asset VM. If the transfer includes an asset split, each split
+// asset is validated against the split commitment of the new asset.
+func validateSpend(vPkt *tappsbt.VPacket, newAsset *asset.Asset,
+	prevAssets commitment.InputSet, isSplit bool,
+	validator TxValidator) error {
+
 	// Create an instance of the Taproot Asset VM and validate the transfer.
 	verifySpend := func(splitAssets []*commitment.SplitAsset) error {
 		newAssetCopy := newAsset.Copy()
@@ -643,7 +880,7 @@ func SignVirtualTransaction(vPkt *tappsbt.VPacket, signer Signer,
 	}
 
 	// If the transfer contains no asset splits, we only need to validate
-	// the new asset with its witness attached, then we can exit early.
+	// the new asset with its witness attached.
 	if !isSplit {
 		return verifySpend(nil)
 	}
@@ -651,8 +888,13 @@ func SignVirtualTransaction(vPkt *tappsbt.VPacket, signer Signer,
 	// If the transfer includes an asset split, we have to validate each
 	// split asset to ensure that our new Asset is committing to a valid
 	// SplitCommitment.
+	outputs := vPkt.Outputs
 	splitAssets := make([]*commitment.SplitAsset, len(outputs))
 	for idx := range outputs {
+		if outputs[idx].Asset == nil {
+			return fmt.Errorf("output %d asset missing", idx)
+		}
+
 		splitAssets[idx] = &commitment.SplitAsset{
 			Asset:       *outputs[idx].Asset,
 			OutputIndex: outputs[idx].AnchorOutputIndex,
@@ -664,32 +906,16 @@ func SignVirtualTransaction(vPkt *tappsbt.VPacket, signer Signer,
 		// validation, as the root asset is already validated as the
 		// newAsset.
 		if outputs[idx].Type.IsSplitRoot() {
-			splitAssets[idx].Asset = *outputs[idx].SplitAsset
-		}
-	}
-	if err := verifySpend(splitAssets); err != nil {
-		return err
-	}
-
-	// Update each split asset to store the root asset with the witness
-	// attached, so the receiver can verify inclusion of the root asset.
-	for idx := range outputs {
-		splitAsset := outputs[idx].Asset
+			if outputs[idx].SplitAsset == nil {
+				return fmt.Errorf("split root output %d is "+
+					"missing split asset", idx)
+			}
 
-		// The output that houses the root asset in case of a split has
-		// a special field for the split asset. That asset is no longer
-		// needed (and isn't committed to anywhere), but in order for it
-		// to be validated externally, we still want to include it and
-		// therefore also want to update it with the signed root asset.
-		if outputs[idx].Type.IsSplitRoot() {
-			splitAsset = outputs[idx].SplitAsset
+			splitAssets[idx].Asset = *outputs[idx].SplitAsset
 		}
-
-		splitCommitment := splitAsset.PrevWitnesses[0].SplitCommitment
-		splitCommitment.RootAsset = *newAsset.Copy()
 	}
 
-	return nil
+	return verifySpend(splitAssets)
 }
 
 // CreateOutputCommitments creates the final set of Taproot asset commitments
@@ -739,7 +965,7 @@ func CreateOutputCommitments(inputTapCommitments tappsbt.InputCommitments,
 
 	// We require all outputs that reference the same anchor output to be
 	// identical, otherwise some assumptions in the code below don't hold.
-	if err := assertAnchorsEqual(vPkt); err != nil {
+	if err := assertAnchorsEqual(vPkt.Outputs); err != nil {
 		return nil, err
 	}
 
@@ -842,8 +1068,7 @@ func CreateOutputCommitments(inputTapCommitments tappsbt.InputCommitments,
 		// send. We do the same even for interactive sends to not need
 		// to distinguish between the two cases in the proof file
 		// itself.
-		committedAsset := vOut.Asset.Copy()
-		committedAsset.PrevWitnesses[0].SplitCommitment = nil
+		committedAsset := vOut.Asset.CopyWithoutSplitCommitment()
 
 		// This is a new output which only commits to a single asset
 		// leaf.
@@ -865,45 +1090,80 @@ func CreateOutputCommitments(inputTapCommitments tappsbt.InputCommitments,
 }
 
 // AnchorPassiveAssets anchors the passive assets within the given Taproot Asset
-// commitment.
+// commitment. All passive assets are committed to in a single batch, so the
+// commitment is only recomputed once, no matter how many passive assets there
+// are.
 func AnchorPassiveAssets(passiveAssets []*tappsbt.VPacket,
 	tapCommitment *commitment.TapCommitment) error {
 
+	delta := commitment.TapCommitmentDelta{
+		Upserts: make([]*asset.Asset, 0, len(passiveAssets)),
+	}
 	for idx := range passiveAssets {
-		passiveAsset := passiveAssets[idx].Outputs[0].Asset
-		var err error
-
-		// Ensure that a commitment for this asset exists.
-		assetCommitment, ok := tapCommitment.Commitment(passiveAsset)
-		if ok {
-			err = assetCommitment.Upsert(passiveAsset)
-			if err != nil {
-				return fmt.Errorf("unable to upsert passive "+
-					"asset into asset commitment: %w", err)
-			}
-		} else {
-			// If no commitment exists yet, create one and insert
-			// the passive asset into it.
-			assetCommitment, err = commitment.NewAssetCommitment(
-				passiveAsset,
-			)
-			if err != nil {
-				return fmt.Errorf("unable to create "+
-					"commitment for passive asset: %w", err)
-			}
-		}
+		delta.Upserts = append(
+			delta.Upserts, passiveAssets[idx].Outputs[0].Asset,
+		)
+	}
 
-		err = tapCommitment.Upsert(assetCommitment)
-		if err != nil {
-			return fmt.Errorf("unable to upsert passive "+
-				"asset commitment into Taproot Asset "+
-				"commitment: %w", err)
-		}
+	if err := tapCommitment.ApplyDelta(delta); err != nil {
+		return fmt.Errorf("unable to upsert passive assets into "+
+			"Taproot Asset commitment: %w", err)
 	}
 
 	return nil
 }
 
+// NewReAnchorPacket creates a virtual packet that moves the given asset in full
+// from its current anchor outpoint to the anchor output with the given index
+// and internal key, without changing its script key. The witnesses of the
+// output asset are replaced with a single unsigned witness spending the
+// previous anchor, so the packet only needs to be signed to re-anchor the
+// asset.
+func NewReAnchorPacket(prevAsset *asset.Asset, anchorPoint wire.OutPoint,
+	anchorOutputIndex uint32, internalKey keychain.KeyDescriptor,
+	chainParams *address.ChainParams) *tappsbt.VPacket {
+
+	inputAsset := prevAsset.Copy()
+	prevID := asset.PrevID{
+		OutPoint: anchorPoint,
+		ID:       inputAsset.ID(),
+		ScriptKey: asset.ToSerialized(
+			inputAsset.ScriptKey.PubKey,
+		),
+	}
+
+	outputAsset := prevAsset.Copy()
+	outputAsset.ReplaceWitnesses(prevID)
+
+	vOutput := &tappsbt.VOutput{
+		Amount: outputAsset.Amount,
+
+		// In this case, the receiver of the output is also the sender.
+		// We therefore set interactive to true to indicate that the
+		// receiver is aware of the transfer.
+		Interactive: true,
+
+		AnchorOutputIndex: anchorOutputIndex,
+		ScriptKey:         outputAsset.ScriptKey,
+		Asset:             outputAsset,
+	}
+	vOutput.SetAnchorInternalKey(internalKey, chainParams.HDCoinType)
+
+	vPkt := &tappsbt.VPacket{
+		Inputs: []*tappsbt.VInput{{
+			PrevID: prevID,
+		}},
+		Outputs:     []*tappsbt.VOutput{vOutput},
+		ChainParams: chainParams,
+	}
+
+	// The input asset proof is not needed for re-anchoring, as the asset
+	// itself doesn't change.
+	vPkt.SetInputAsset(0, inputAsset, nil)
+
+	return vPkt
+}
+
 // AreValidAnchorOutputIndexes checks a set of virtual outputs for the minimum
 // number of outputs, and tests if the external indexes could be used for a
 // Taproot Asset only spend, i.e. a TX that does not need other outputs added to
@@ -1004,15 +1264,72 @@ func UpdateTaprootOutputKeys(btcPacket *psbt.Packet, vPkt *tappsbt.VPacket,
 	outputCommitments []*commitment.TapCommitment) (
 	map[uint32]*commitment.TapCommitment, error) {
 
+	return UpdateTaprootOutputKeysForPackets(
+		btcPacket, []*tappsbt.VPacket{vPkt},
+		[][]*commitment.TapCommitment{outputCommitments},
+	)
+}
+
+// UpdateTaprootOutputKeysForPackets updates a PSBT with outputs embedding the
+// TapCommitments of multiple virtual packets (one for each asset ID) that are
+// anchored in the same BTC level transaction. The output commitments are
+// expected in the same order as the virtual packets, with one commitment for
+// each virtual output. Virtual outputs of different packets that reference the
+// same anchor output index are merged into a single commitment.
+func UpdateTaprootOutputKeysForPackets(btcPacket *psbt.Packet,
+	vPkts []*tappsbt.VPacket,
+	outputCommitments [][]*commitment.TapCommitment) (
+	map[uint32]*commitment.TapCommitment, error) {
+
+	if len(vPkts) != len(outputCommitments) {
+		return nil, fmt.Errorf("expected output commitments for %d "+
+			"virtual packets, got %d", len(vPkts),
+			len(outputCommitments))
+	}
+
+	// Outputs of different packets that share an anchor output index also
+	// need to agree on the anchor output information.
+	var allOutputs []*tappsbt.VOutput
+	for idx := range vPkts {
+		allOutputs = append(allOutputs, vPkts[idx].Outputs...)
+	}
+	if err := assertAnchorsEqual(allOutputs); err != nil {
+		return nil, err
+	}
+
 	// Add the commitment outputs to the BTC level PSBT now.
 	anchorCommitments := make(map[uint32]*commitment.TapCommitment)
+	for pktIdx := range vPkts {
+		err := updateTaprootOutputKeys(
+			btcPacket, vPkts[pktIdx], outputCommitments[pktIdx],
+			anchorCommitments,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return anchorCommitments, nil
+}
+
+// updateTaprootOutputKeys embeds the output commitments of a single virtual
+// packet into the PSBT, merging them with the commitments of any previous
+// packets that were committed to the same anchor output.
+func updateTaprootOutputKeys(btcPacket *psbt.Packet, vPkt *tappsbt.VPacket,
+	outputCommitments []*commitment.TapCommitment,
+	anchorCommitments map[uint32]*commitment.TapCommitment) error {
+
+	if len(vPkt.Outputs) != len(outputCommitments) {
+		return ErrMissingTapCommitment
+	}
+
 	for idx := range vPkt.Outputs {
 		vOut := vPkt.Outputs[idx]
 		vOutCommitment := outputCommitments[idx]
 
 		// The commitment must be defined at this point.
 		if vOutCommitment == nil {
-			return nil, ErrMissingTapCommitment
+			return ErrMissingTapCommitment
 		}
 
 		// It could be that we have multiple outputs that are being
@@ -1023,7 +1340,7 @@ func UpdateTaprootOutputKeys(btcPacket *psbt.Packet, vPkt *tappsbt.VPacket,
 		if ok {
 			err := anchorCommitment.Merge(vOutCommitment)
 			if err != nil {
-				return nil, fmt.Errorf("cannot merge output "+
+				return fmt.Errorf("cannot merge output "+
 					"commitments: %w", err)
 			}
 		} else {
@@ -1035,7 +1352,7 @@ func UpdateTaprootOutputKeys(btcPacket *psbt.Packet, vPkt *tappsbt.VPacket,
 		// actual TX outputs. This should be checked earlier and is just
 		// a final safeguard here.
 		if vOut.AnchorOutputIndex >= uint32(len(btcPacket.Outputs)) {
-			return nil, ErrInvalidOutputIndexes
+			return ErrInvalidOutputIndexes
 		}
 
 		btcOut := btcPacket.Outputs[vOut.AnchorOutputIndex]
@@ -1043,7 +1360,7 @@ func UpdateTaprootOutputKeys(btcPacket *psbt.Packet, vPkt *tappsbt.VPacket,
 			btcOut.TaprootInternalKey,
 		)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
 		// Prepare the anchor output's tapscript sibling, if there is
@@ -1058,7 +1375,7 @@ func UpdateTaprootOutputKeys(btcPacket *psbt.Packet, vPkt *tappsbt.VPacket,
 		if siblingPreimage != nil {
 			siblingHash, err = siblingPreimage.TapHash()
 			if err != nil {
-				return nil, fmt.Errorf("unable to get "+
+				return fmt.Errorf("unable to get "+
 					"sibling hash: %w", err)
 			}
 		}
@@ -1069,14 +1386,14 @@ func UpdateTaprootOutputKeys(btcPacket *psbt.Packet, vPkt *tappsbt.VPacket,
 			*internalKey, siblingHash, *anchorCommitment,
 		)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
 		btcTxOut := btcPacket.UnsignedTx.TxOut[vOut.AnchorOutputIndex]
 		btcTxOut.PkScript = script
 	}
 
-	return anchorCommitments, nil
+	return nil
 }
 
 // interactiveFullValueSend returns true (and the index of the recipient output)
@@ -1108,13 +1425,13 @@ func interactiveFullValueSend(totalInputAmount uint64,
 	return recipientIndex, fullValueInteractiveSend
 }
 
-// assertAnchorsEqual makes sure that the anchor output information for each
-// output of the virtual packet that anchors to the same BTC level output is
+// assertAnchorsEqual makes sure that the anchor output information for each of
+// the given virtual outputs that anchors to the same BTC level output is
 // identical.
-func assertAnchorsEqual(vPkt *tappsbt.VPacket) error {
+func assertAnchorsEqual(outputs []*tappsbt.VOutput) error {
 	deDupMap := make(map[uint32]*tappsbt.Anchor)
-	for idx := range vPkt.Outputs {
-		vOut := vPkt.Outputs[idx]
+	for idx := range outputs {
+		vOut := outputs[idx]
 
 		siblingBytes, _, err := commitment.MaybeEncodeTapscriptPreimage(
 			vOut.AnchorOutputTapscriptSibling,
@@ -989,6 +989,148 @@ var signVirtualTransactionTestCases = []testCase{{
 		return nil
 	},
 	err: nil,
+}, {
+	name: "verify signed interactive full value send",
+	f: func(t *testing.T) error {
+		state := initSpendScenario(t)
+
+		pkt := createPacket(
+			state.address1, state.asset1PrevID,
+			state, state.asset1InputAssets, true,
+		)
+		err := tapscript.PrepareOutputAssets(context.Background(), pkt)
+		require.NoError(t, err)
+
+		err = tapscript.SignVirtualTransaction(
+			pkt, state.signer, state.validator,
+		)
+		require.NoError(t, err)
+
+		return tapscript.VerifyVirtualTransaction(pkt, state.validator)
+	},
+	err: nil,
+}, {
+	name: "verify unsigned interactive full value send",
+	f: func(t *testing.T) error {
+		state := initSpendScenario(t)
+
+		pkt := createPacket(
+			state.address1, state.asset1PrevID,
+			state, state.asset1InputAssets, true,
+		)
+		err := tapscript.PrepareOutputAssets(context.Background(), pkt)
+		require.NoError(t, err)
+
+		err = tapscript.VerifyVirtualTransaction(pkt, state.validator)
+		require.Error(t, err)
+
+		return nil
+	},
+	err: nil,
+}, {
+	name: "sign asset split with external signer",
+	f: func(t *testing.T) error {
+		state := initSpendScenario(t)
+
+		pkt := createPacket(
+			state.address1, state.asset2PrevID,
+			state, state.asset2InputAssets, false,
+		)
+		err := tapscript.PrepareOutputAssets(context.Background(), pkt)
+		require.NoError(t, err)
+
+		sigHashes, err := tapscript.VirtualTxSigHashes(pkt)
+		require.NoError(t, err)
+		require.Len(t, sigHashes, 1)
+
+		// The external signer signs the sighash with the BIP-0086
+		// tweaked script key.
+		tweakedKey := txscript.TweakTaprootPrivKey(
+			state.spenderPrivKey, nil,
+		)
+		sig, err := schnorr.Sign(tweakedKey, sigHashes[0])
+		require.NoError(t, err)
+
+		unvalidatedAsset := pkt.Outputs[0].Asset.Copy()
+		err = tapscript.AddVirtualTxWitnesses(
+			pkt, []wire.TxWitness{{sig.Serialize()}},
+			state.validator,
+		)
+		require.NoError(t, err)
+
+		checkSignedAsset(
+			t, unvalidatedAsset, pkt.Outputs[0].Asset, true, false,
+		)
+		return nil
+	},
+	err: nil,
+}, {
+	name: "add invalid witness from external signer",
+	f: func(t *testing.T) error {
+		state := initSpendScenario(t)
+
+		pkt := createPacket(
+			state.address1, state.asset2PrevID,
+			state, state.asset2InputAssets, false,
+		)
+		err := tapscript.PrepareOutputAssets(context.Background(), pkt)
+		require.NoError(t, err)
+
+		// A signature by the untweaked key doesn't satisfy the script
+		// key of the input.
+		sigHashes, err := tapscript.VirtualTxSigHashes(pkt)
+		require.NoError(t, err)
+		sig, err := schnorr.Sign(&state.spenderPrivKey, sigHashes[0])
+		require.NoError(t, err)
+
+		err = tapscript.AddVirtualTxWitnesses(
+			pkt, []wire.TxWitness{{sig.Serialize()}},
+			state.validator,
+		)
+		require.Error(t, err)
+
+		return nil
+	},
+	err: nil,
+}, {
+	name: "reject witness over standard limits from external signer",
+	f: func(t *testing.T) error {
+		state := initSpendScenario(t)
+
+		pkt := createPacket(
+			state.address1, state.asset2PrevID,
+			state, state.asset2InputAssets, false,
+		)
+		err := tapscript.PrepareOutputAssets(context.Background(), pkt)
+		require.NoError(t, err)
+
+		sigHashes, err := tapscript.VirtualTxSigHashes(pkt)
+		require.NoError(t, err)
+		tweakedKey := txscript.TweakTaprootPrivKey(
+			state.spenderPrivKey, nil,
+		)
+		sig, err := schnorr.Sign(tweakedKey, sigHashes[0])
+		require.NoError(t, err)
+
+		// An annex makes the witness larger than the standard limits
+		// allow, which must be caught before the transfer is anchored
+		// and broadcast.
+		annex := make([]byte, vm.StandardMaxWitnessSize)
+		annex[0] = txscript.TaprootAnnexTag
+
+		standardLimits := vm.StandardLimits()
+		err = tapscript.AddVirtualTxWitnesses(
+			pkt, []wire.TxWitness{{sig.Serialize(), annex}},
+			&tap.ValidatorV0{Limits: &standardLimits},
+		)
+
+		var vmErr vm.Error
+		require.ErrorAs(t, err, &vmErr)
+		require.Equal(t, vm.ErrWitnessTooLarge, vmErr.Kind)
+
+		return nil
+	},
+	err: nil,
 }}
 
 // TestCreateOutputCommitments tests edge cases around creating TapCommitments
@@ -1541,6 +1683,104 @@ var updateTaprootOutputKeysTestCases = []testCase{{
 	err: nil,
 }}
 
+// TestUpdateTaprootOutputKeysForPackets tests that the outputs of multiple
+// virtual packets with different asset IDs are merged into the same anchor
+// outputs.
+func TestUpdateTaprootOutputKeysForPackets(t *testing.T) {
+	t.Parallel()
+
+	// signedPacket creates, signs and commits to a virtual packet that
+	// sends the given input to the given address.
+	signedPacket := func(state spendData, addr address.Tap,
+		prevID asset.PrevID, inputSet commitment.InputSet,
+		inputCommitment *commitment.TapCommitment) (*tappsbt.VPacket,
+		[]*commitment.TapCommitment) {
+
+		pkt := createPacket(addr, prevID, state, inputSet, false)
+		err := tapscript.PrepareOutputAssets(context.Background(), pkt)
+		require.NoError(t, err)
+		err = tapscript.SignVirtualTransaction(
+			pkt, state.signer, state.validator,
+		)
+		require.NoError(t, err)
+
+		outputCommitments, err := tapscript.CreateOutputCommitments(
+			tappsbt.InputCommitments{
+				0: inputCommitment,
+			}, pkt, nil,
+		)
+		require.NoError(t, err)
+
+		return pkt, outputCommitments
+	}
+
+	// The first packet sends part of a normal asset, the second one sends
+	// a grouped collectible in full. Both packets use the same anchor
+	// output indexes and keys for their change and receiver outputs.
+	state := initSpendScenario(t)
+	pkt1, commitments1 := signedPacket(
+		state, state.address1, state.asset2PrevID,
+		state.asset2InputAssets, &state.asset2TapTree,
+	)
+
+	state.spenderScriptKey = *asset.NUMSPubKey
+	pkt2, commitments2 := signedPacket(
+		state, state.address1CollectGroup,
+		state.asset1CollectGroupPrevID,
+		state.asset1CollectGroupInputAssets,
+		&state.asset1CollectGroupTapTree,
+	)
+
+	var allOutputs []*tappsbt.VOutput
+	allOutputs = append(allOutputs, pkt1.Outputs...)
+	allOutputs = append(allOutputs, pkt2.Outputs...)
+	btcPkt, err := tapscript.CreateAnchorTx(allOutputs)
+	require.NoError(t, err)
+
+	anchorCommitments, err := tapscript.UpdateTaprootOutputKeysForPackets(
+		btcPkt, []*tappsbt.VPacket{pkt1, pkt2},
+		[][]*commitment.TapCommitment{commitments1, commitments2},
+	)
+	require.NoError(t, err)
+	require.Len(t, anchorCommitments, 2)
+
+	// Each anchor output must commit to the assets of both packets, and
+	// the BTC level output must commit to the merged tree.
+	for _, outIdx := range []int{0, 1} {
+		out1, out2 := pkt1.Outputs[outIdx], pkt2.Outputs[outIdx]
+		require.Equal(t, out1.AnchorOutputIndex, out2.AnchorOutputIndex)
+
+		anchorCommitment := anchorCommitments[out1.AnchorOutputIndex]
+		outAssets := []*asset.Asset{out1.Asset, out2.Asset}
+		for _, outAsset := range outAssets {
+			provenAsset, _, err := anchorCommitment.Proof(
+				outAsset.TapCommitmentKey(),
+				outAsset.AssetCommitmentKey(),
+			)
+			require.NoError(t, err)
+			require.NotNil(t, provenAsset)
+		}
+
+		internalKey := out1.AnchorOutputInternalKey
+		expectedScript, err := tapscript.PayToAddrScript(
+			*internalKey, nil, *anchorCommitment,
+		)
+		require.NoError(t, err)
+
+		txOut := btcPkt.UnsignedTx.TxOut[out1.AnchorOutputIndex]
+		require.Equal(t, expectedScript, txOut.PkScript)
+	}
+
+	// Outputs of different packets that share an anchor output must agree
+	// on the anchor output information.
+	pkt2.Outputs[1].AnchorOutputInternalKey = test.RandPubKey(t)
+	_, err = tapscript.UpdateTaprootOutputKeysForPackets(
+		btcPkt, []*tappsbt.VPacket{pkt1, pkt2},
+		[][]*commitment.TapCommitment{commitments1, commitments2},
+	)
+	require.ErrorIs(t, err, tapscript.ErrInvalidAnchorInfo)
+}
+
 func createSpend(t *testing.T, state *spendData, inputSet commitment.InputSet,
 	full bool) (*psbt.Packet, *tappsbt.VPacket,
 	[]*commitment.TapCommitment) {
@@ -1840,6 +2080,52 @@ func TestAreValidAnchorOutputIndexes(t *testing.T) {
 	require.NoError(t, err)
 }
 
+// TestNewReAnchorPacket tests that an asset with existing witnesses and a split
+// commitment root can be re-signed for a new anchor output.
+func TestNewReAnchorPacket(t *testing.T) {
+	t.Parallel()
+
+	state := initSpendScenario(t)
+
+	prevAsset := state.asset1.Copy()
+	prevAsset.PrevWitnesses = []asset.Witness{{
+		PrevID:    &state.asset1PrevID,
+		TxWitness: wire.TxWitness{test.RandBytes(64)},
+	}}
+	prevAsset.SplitCommitmentRoot = mssmt.NewComputedNode(
+		sha256.Sum256(test.RandBytes(32)), prevAsset.Amount,
+	)
+
+	anchorPoint := test.RandOp(t)
+	internalKey := keychain.KeyDescriptor{
+		PubKey: test.RandPubKey(t),
+	}
+	vPkt := tapscript.NewReAnchorPacket(
+		prevAsset, anchorPoint, 1, internalKey, &address.MainNetTap,
+	)
+
+	// The input asset is left untouched, while the output asset only has
+	// a single unsigned witness that spends the previous anchor.
+	require.True(t, prevAsset.DeepEqual(vPkt.Inputs[0].Asset()))
+	require.Equal(t, anchorPoint, vPkt.Inputs[0].PrevID.OutPoint)
+
+	outputAsset := vPkt.Outputs[0].Asset
+	require.Nil(t, outputAsset.SplitCommitmentRoot)
+	require.Equal(t, []asset.Witness{{
+		PrevID: &vPkt.Inputs[0].PrevID,
+	}}, outputAsset.PrevWitnesses)
+	require.Equal(t, uint32(1), vPkt.Outputs[0].AnchorOutputIndex)
+	require.True(t, internalKey.PubKey.IsEqual(
+		vPkt.Outputs[0].AnchorOutputInternalKey,
+	))
+
+	err := tapscript.SignVirtualTransaction(
+		vPkt, state.signer, state.validator,
+	)
+	require.NoError(t, err)
+	require.Len(t, outputAsset.PrevWitnesses[0].TxWitness, 1)
+}
+
 // TestAddressValidInput tests edge cases around validating inputs for asset
 // transfers with isValidInput.
 func TestAddressValidInput(t *testing.T) {
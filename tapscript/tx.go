@@ -18,7 +18,6 @@ import (
 	"github.com/lightninglabs/taproot-assets/mssmt"
 	"github.com/lightninglabs/taproot-assets/tappsbt"
 	"github.com/lightningnetwork/lnd/input"
-	"github.com/lightningnetwork/lnd/keychain"
 )
 
 var (
@@ -33,6 +32,11 @@ var (
 	// ErrInvalidScriptVersion represents an error case where an asset input
 	// commits to an invalid script version.
 	ErrInvalidScriptVersion = errors.New("invalid script version")
+
+	// ErrUnsupportedSigHashType represents an error case where an asset
+	// input is signed with a sighash type that isn't supported for virtual
+	// transactions.
+	ErrUnsupportedSigHashType = errors.New("unsupported sighash type")
 )
 
 const (
@@ -88,10 +92,9 @@ func virtualTxIn(newAsset *asset.Asset, prevAssets commitment.InputSet) (
 		inputsConsumed := make(
 			map[asset.PrevID]struct{}, len(prevAssets),
 		)
-
-		// TODO(bhandras): thread the context through.
-		ctx := context.TODO()
-
+		inputLeaves := make(
+			map[[32]byte]*mssmt.LeafNode, len(prevAssets),
+		)
 		for _, input := range newAsset.PrevWitnesses {
 			// At this point, each input MUST have a prev ID.
 			if input.PrevID == nil {
@@ -105,19 +108,15 @@ func virtualTxIn(newAsset *asset.Asset, prevAssets commitment.InputSet) (
 				return nil, nil, ErrNoInputs
 			}
 
-			// Now we'll insert this prev asset leaf into the tree.
-			// The generated leaf includes the amount of the asset,
-			// so the sum of this tree will be the total amount
-			// being spent.
-			key := input.PrevID.Hash()
+			// Now we'll add this prev asset leaf to the leaves of
+			// the tree. The generated leaf includes the amount of
+			// the asset, so the sum of this tree will be the total
+			// amount being spent.
 			leaf, err := prevAsset.Leaf()
 			if err != nil {
 				return nil, nil, err
 			}
-			_, err = inputTree.Insert(ctx, key, leaf)
-			if err != nil {
-				return nil, nil, err
-			}
+			inputLeaves[input.PrevID.Hash()] = leaf
 
 			inputsConsumed[*input.PrevID] = struct{}{}
 		}
@@ -130,6 +129,15 @@ func virtualTxIn(newAsset *asset.Asset, prevAssets commitment.InputSet) (
 		if len(inputsConsumed) != len(prevAssets) {
 			return nil, nil, ErrInputMismatch
 		}
+
+		// All inputs are inserted at once, so the branches of the tree
+		// are only computed once.
+		//
+		// TODO(bhandras): thread the context through.
+		_, err := inputTree.InsertMany(context.TODO(), inputLeaves)
+		if err != nil {
+			return nil, nil, err
+		}
 	}
 
 	treeRoot, err := inputTree.Root(context.Background())
@@ -257,6 +265,53 @@ func VirtualTxWithInput(virtualTx *wire.MsgTx, input *asset.Asset,
 	return txCopy
 }
 
+// VirtualTxWithInputSigHash returns a copy of the `virtualTx` amended to
+// include all input-specific details for a signature with the given sighash
+// type.
+//
+// For SIGHASH_ANYONECANPAY, the prevout of the virtual input commits to only
+// the given input instead of all inputs of the state transition. This allows
+// multiple parties to each sign their own inputs of a collaborative transfer
+// without knowing the inputs of the other parties, as long as they agree on
+// the outputs.
+func VirtualTxWithInputSigHash(virtualTx *wire.MsgTx, input *asset.Asset,
+	prevID asset.PrevID, idx uint32, sigHashType txscript.SigHashType,
+	witness wire.TxWitness) (*wire.MsgTx, error) {
+
+	if !asset.IsSupportedSigHashType(sigHashType) {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedSigHashType,
+			sigHashType)
+	}
+
+	txCopy := VirtualTxWithInput(virtualTx, input, idx, witness)
+	if sigHashType&txscript.SigHashAnyOneCanPay == 0 {
+		return txCopy, nil
+	}
+
+	// The input is the only leaf of its own input tree, so the prev index
+	// always points to the first leaf.
+	inputTree := mssmt.NewCompactedTree(mssmt.NewDefaultStore())
+	leaf, err := input.Leaf()
+	if err != nil {
+		return nil, err
+	}
+
+	// TODO(bhandras): thread the context through.
+	ctx := context.TODO()
+	_, err = inputTree.Insert(ctx, prevID.Hash(), leaf)
+	if err != nil {
+		return nil, err
+	}
+	treeRoot, err := inputTree.Root(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	txCopy.TxIn[zeroIndex].PreviousOutPoint = *virtualTxInPrevOut(treeRoot)
+
+	return txCopy, nil
+}
+
 // InputAssetPrevOut returns a TxOut that represents the input asset in a
 // Taproot Asset virtual TX.
 func InputAssetPrevOut(prevAsset asset.Asset) (*wire.TxOut, error) {
@@ -297,9 +352,15 @@ func InputPrevOutFetcher(prevAsset asset.Asset) (*txscript.CannedPrevOutputFetch
 // a specific Taproot Asset input that can be spent through the key path. This
 // is the message over which signatures are generated over.
 func InputKeySpendSigHash(virtualTx *wire.MsgTx, input *asset.Asset,
-	idx uint32, sigHashType txscript.SigHashType) ([]byte, error) {
+	prevID asset.PrevID, idx uint32,
+	sigHashType txscript.SigHashType) ([]byte, error) {
 
-	virtualTxCopy := VirtualTxWithInput(virtualTx, input, idx, nil)
+	virtualTxCopy, err := VirtualTxWithInputSigHash(
+		virtualTx, input, prevID, idx, sigHashType, nil,
+	)
+	if err != nil {
+		return nil, err
+	}
 	prevOutFetcher, err := InputPrevOutFetcher(*input)
 	if err != nil {
 		return nil, err
@@ -315,10 +376,15 @@ func InputKeySpendSigHash(virtualTx *wire.MsgTx, input *asset.Asset,
 // for a specific Taproot Asset input that can be spent through the script path.
 // This is the message over which signatures are generated over.
 func InputScriptSpendSigHash(virtualTx *wire.MsgTx, input *asset.Asset,
-	idx uint32, sigHashType txscript.SigHashType,
+	prevID asset.PrevID, idx uint32, sigHashType txscript.SigHashType,
 	tapLeaf *txscript.TapLeaf) ([]byte, error) {
 
-	virtualTxCopy := VirtualTxWithInput(virtualTx, input, idx, nil)
+	virtualTxCopy, err := VirtualTxWithInputSigHash(
+		virtualTx, input, prevID, idx, sigHashType, nil,
+	)
+	if err != nil {
+		return nil, err
+	}
 	prevOutFetcher, err := InputPrevOutFetcher(*input)
 	if err != nil {
 		return nil, err
@@ -369,9 +435,7 @@ func CreateTaprootSignature(vIn *tappsbt.VInput, virtualTx *wire.MsgTx,
 	// Start with a default sign descriptor and the BIP-0086 sign method
 	// then adjust depending on the input parameters.
 	spendDesc := lndclient.SignDescriptor{
-		KeyDesc: keychain.KeyDescriptor{
-			PubKey: vIn.Asset().ScriptKey.RawKey.PubKey,
-		},
+		KeyDesc:    vIn.Asset().ScriptKey.RawKey,
 		SignMethod: input.TaprootKeySpendBIP0086SignMethod,
 		Output:     prevOut,
 		HashType:   vIn.SighashType,
@@ -421,6 +485,15 @@ func CreateTaprootSignature(vIn *tappsbt.VInput, virtualTx *wire.MsgTx,
 				"found")
 		}
 
+		// The leaf might require a signature from a key other than the
+		// raw script key, for example the operator of a delegated script
+		// key.
+		keyDesc, err := scriptSpendKeyDesc(vIn, derivation)
+		if err != nil {
+			return nil, err
+		}
+
+		spendDesc.KeyDesc = keyDesc
 		spendDesc.SignMethod = input.TaprootScriptSpendSignMethod
 		spendDesc.WitnessScript = leafScript.Script
 
@@ -9,14 +9,25 @@ import (
 
 // ValidatorV0 is an implementation of the tapscript.TxValidator interface
 // that supports Taproot Asset script version 0.
-type ValidatorV0 struct{}
+type ValidatorV0 struct {
+	// Limits optionally overrides the default witness size and stack
+	// depth limits enforced by the VM, which are equivalent to consensus.
+	// Setting stricter limits, such as vm.StandardLimits, allows transfers
+	// to be rejected before they're broadcast.
+	Limits *vm.Limits
+}
 
 // Execute creates and runs an instance of the Taproot Asset script V0 VM.
 func (v *ValidatorV0) Execute(newAsset *asset.Asset,
 	splitAssets []*commitment.SplitAsset,
 	prevAssets commitment.InputSet) error {
 
-	engine, err := vm.New(newAsset, splitAssets, prevAssets)
+	var opts []vm.EngineOption
+	if v.Limits != nil {
+		opts = append(opts, vm.WithLimits(*v.Limits))
+	}
+
+	engine, err := vm.New(newAsset, splitAssets, prevAssets, opts...)
 	if err != nil {
 		return err
 	}
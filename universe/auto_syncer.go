@@ -41,6 +41,11 @@ type FederationConfig struct {
 	// set of Universe servers.
 	SyncInterval time.Duration
 
+	// SyncIDs is the set of universes that are synced with the
+	// federation. If empty, all the universes known to a server are
+	// synced.
+	SyncIDs []Identifier
+
 	// ErrChan is the main error channel the custodian will report back
 	// critical errors to the main server.
 	ErrChan chan<- error
@@ -156,7 +161,7 @@ func (f *FederationEnvoy) syncUniverseState(ctx context.Context,
 	// Attempt to sync with the remote Universe server, if this errors then
 	// we'll bail out early as something wrong happened.
 	diff, err := f.cfg.UniverseSyncer.SyncUniverse(
-		ctx, addr, SyncIssuance,
+		ctx, addr, SyncIssuance, f.cfg.SyncIDs...,
 	)
 	if err != nil {
 		return err
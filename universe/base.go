@@ -8,6 +8,7 @@ import (
 
 	"github.com/btcsuite/btcd/btcec/v2/schnorr"
 	"github.com/davecgh/go-spew/spew"
+	"github.com/lightninglabs/taproot-assets/mssmt"
 	"github.com/lightninglabs/taproot-assets/proof"
 )
 
@@ -32,6 +33,11 @@ type MintingArchiveConfig struct {
 	// external/internal queries to the base universe instance.
 	UniverseStats Telemetry
 
+	// TransferPolicy governs which universes index transfer proofs in
+	// addition to issuance proofs. If nil, only issuance proofs are
+	// accepted.
+	TransferPolicy *TransferPolicy
+
 	// TODO(roasbeef): query re genesis asset known?
 
 	// TODO(roasbeef): load all at once, or lazy load dynamic?
@@ -131,6 +137,8 @@ func (a *MintingArchive) RootNodes(ctx context.Context) ([]BaseRoot, error) {
 // event for the specified base universe identifier. This method will return an
 // error if the passed minting proof is invalid. If the leaf is already known,
 // then no action is taken and the existing issuance commitment proof returned.
+// Transfer proofs are only accepted if the transfer policy permits them for
+// the universe, and the proof of the spent asset is already part of it.
 func (a *MintingArchive) RegisterIssuance(ctx context.Context, id Identifier,
 	key BaseKey, leaf *MintingLeaf) (*IssuanceProof, error) {
 
@@ -160,7 +168,25 @@ func (a *MintingArchive) RegisterIssuance(ctx context.Context, id Identifier,
 	if err != nil {
 		return nil, fmt.Errorf("unable to decode proof: %v", err)
 	}
-	assetSnapshot, err := newProof.Verify(ctx, nil, a.cfg.HeaderVerifier)
+
+	// A transfer proof can only be verified against the asset it spends,
+	// so the proof of that asset must already be part of this universe.
+	var prevAsset *proof.AssetSnapshot
+	if isTransferProof(&newProof) {
+		if !a.cfg.TransferPolicy.IndexesTransfers(id) {
+			return nil, fmt.Errorf("%w: id=%v",
+				ErrTransferProofsDisabled, id.String())
+		}
+
+		prevAsset, err = prevSnapshot(ctx, baseUni, &newProof)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	assetSnapshot, err := newProof.Verify(
+		ctx, prevAsset, a.cfg.HeaderVerifier,
+	)
 	if err != nil {
 		return nil, fmt.Errorf("unable to verify proof: %v", err)
 	}
@@ -232,15 +258,16 @@ func (a *MintingArchive) FetchIssuanceProof(ctx context.Context, id Identifier,
 	log.Debugf("Retrieving Universe proof for: id=%v, base_key=%v",
 		id.String(), spew.Sdump(key))
 
-	// Log a sync event for the leaf query leaf in the background as an
-	// async goroutine.
+	// Log a proof query event for the queried leaf in the background as
+	// an async goroutine.
 	defer func() {
 		go func() {
-			err := a.cfg.UniverseStats.LogSyncEvent(
+			err := a.cfg.UniverseStats.LogProofQueryEvent(
 				context.Background(), id, key,
 			)
 			if err != nil {
-				log.Warnf("unable to log sync event: %v", err)
+				log.Warnf("unable to log proof query event: %v",
+					err)
 			}
 		}()
 	}()
@@ -273,3 +300,17 @@ func (a *MintingArchive) MintingLeaves(ctx context.Context,
 		return baseUni.MintingLeaves(ctx)
 	})
 }
+
+// TreeStats returns statistics about the nodes of the MS-SMT backing the
+// specified base universe.
+func (a *MintingArchive) TreeStats(ctx context.Context,
+	id Identifier) (*mssmt.TreeStats, error) {
+
+	log.Debugf("Retrieving tree stats for Universe: id=%v", id.String())
+
+	return withBaseUni(
+		a, id, func(baseUni BaseBackend) (*mssmt.TreeStats, error) {
+			return baseUni.TreeStats(ctx)
+		},
+	)
+}
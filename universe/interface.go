@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net"
 	"strconv"
+	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcec/v2/schnorr"
@@ -170,6 +171,10 @@ type BaseBackend interface {
 	// MintingLeaves returns all the minting leaves inserted into the
 	// universe.
 	MintingLeaves(ctx context.Context) ([]MintingLeaf, error)
+
+	// TreeStats returns statistics about the nodes of the MS-SMT backing
+	// the universe.
+	TreeStats(ctx context.Context) (*mssmt.TreeStats, error)
 }
 
 // BaseRoot is the ms-smt root for a base universe. This root can be used to
@@ -451,6 +456,34 @@ type FederationLog interface {
 	LogNewSyncs(ctx context.Context, addrs ...ServerAddr) error
 }
 
+// LeafProvenance describes the origin of a leaf that was synced from a remote
+// Universe server.
+type LeafProvenance struct {
+	// Key is the key of the leaf within its universe.
+	Key BaseKey
+
+	// Server is the Universe server the leaf was fetched from.
+	Server ServerAddr
+
+	// SyncTime is the time the leaf was synced.
+	SyncTime time.Time
+}
+
+// ProvenanceLog is used to keep track of the Universe server each leaf that
+// was synced from the federation was fetched from.
+type ProvenanceLog interface {
+	// LogLeafProvenance records that the leaf stored at the given key of
+	// the target universe was fetched from the given server. If the origin
+	// of the leaf is already known, then it's left untouched.
+	LogLeafProvenance(ctx context.Context, addr ServerAddr, id Identifier,
+		key BaseKey) error
+
+	// LeafProvenance returns the origin of each leaf of the target
+	// universe that was synced from a remote Universe server.
+	LeafProvenance(ctx context.Context,
+		id Identifier) ([]LeafProvenance, error)
+}
+
 // SyncStatsSort is an enum used to specify the sort order of the returned sync
 // stats.
 type SyncStatsSort uint8
@@ -468,6 +501,10 @@ const (
 
 	// SortByAssetID sorts the returned stats by the asset ID.
 	SortByAssetID
+
+	// SortByTotalQueries sorts the returned stats by the total number of
+	// proof queries, starting with the most queried asset.
+	SortByTotalQueries
 )
 
 // SyncStatsQuery packages a set of query parameters to retrieve stats related
@@ -523,6 +560,10 @@ type AssetSyncSnapshot struct {
 	// for the asset.
 	TotalProofs uint64
 
+	// TotalQueries is the total number of times a proof of the asset was
+	// queried.
+	TotalQueries uint64
+
 	// TODO(roasbeef): add last sync?
 }
 
@@ -548,6 +589,17 @@ type AggregateStats struct {
 	// NumTotalProofs is the total number of proofs that have been inserted
 	// into the Universe.
 	NumTotalProofs uint64
+
+	// NumTotalQueries is the total number of proof queries that have been
+	// served by the Universe.
+	NumTotalQueries uint64
+
+	// NumTotalGroups is the total number of asset groups in the Universe.
+	NumTotalGroups uint64
+
+	// NumTotalLeaves is the total number of leaves across all the trees of
+	// the Universe.
+	NumTotalLeaves uint64
 }
 
 // Telemetry it a type used by the Universe syncer and base universe to export
@@ -561,15 +613,17 @@ type Telemetry interface {
 	// the Universe.
 	AggregateSyncStats(ctx context.Context) (AggregateStats, error)
 
-	// LogSyncEvent logs a sync event for the target universe.
-	//
-	// TODO(roasbeef): log based on a given leaf, or entire tree?
-	//  * rn main entrypoint is in RPC server, which is leaf based
-	//  * alternatively, can log when a set of leaves are queried, as
-	//    that's still a sync event, but can be a noop
+	// LogSyncEvent logs a sync event for the target universe. A sync
+	// event is logged for each leaf that was synced from a federation
+	// server.
 	LogSyncEvent(ctx context.Context, uniID Identifier,
 		key BaseKey) error
 
+	// LogProofQueryEvent logs that the proof of the given leaf of the
+	// target universe was queried.
+	LogProofQueryEvent(ctx context.Context, uniID Identifier,
+		key BaseKey) error
+
 	// LogNewProofEvent logs a new proof insertion event for the target
 	// universe.
 	LogNewProofEvent(ctx context.Context, uniID Identifier,
@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/lightninglabs/taproot-assets/chanutils"
+	"github.com/lightninglabs/taproot-assets/monitoring"
 	"github.com/lightninglabs/taproot-assets/mssmt"
 )
 
@@ -16,6 +18,12 @@ var (
 	ErrUnsupportedSync = fmt.Errorf("unsupported sync type")
 )
 
+const (
+	// syncDeadline is the time after which the watchdog reports a sync
+	// with a remote universe that is still running as stalled.
+	syncDeadline = time.Hour
+)
+
 // SimpleSyncCfg contains all the configuration needed to create a new
 // SimpleSyncer.
 type SimpleSyncCfg struct {
@@ -31,6 +39,14 @@ type SimpleSyncCfg struct {
 	// This is used to insert new proof into the local DB as a result of
 	// the diff operation.
 	LocalRegistrar Registrar
+
+	// ProvenanceLog is used to record the remote Universe server each new
+	// leaf was fetched from.
+	ProvenanceLog ProvenanceLog
+
+	// UniverseStats is used to log a sync event for each leaf that was
+	// synced from a remote Universe.
+	UniverseStats Telemetry
 }
 
 // SimpleSyncer is a simple implementation of the Syncer interface. It's based
@@ -49,11 +65,12 @@ func NewSimpleSyncer(cfg SimpleSyncCfg) *SimpleSyncer {
 // executeSync attempts to sync the local Universe with the remote diff engine.
 // A simple approach where a set difference is used to find the set of assets
 // that need to be synced is used.
-func (s *SimpleSyncer) executeSync(ctx context.Context, diffEngine DiffEngine,
-	syncType SyncType, idsToSync []Identifier) ([]AssetSyncDiff, error) {
+func (s *SimpleSyncer) executeSync(ctx context.Context, host ServerAddr,
+	diffEngine DiffEngine, syncType SyncType,
+	idsToSync []Identifier) ([]AssetSyncDiff, error) {
 
 	var (
-		rootsToSync = make(chan BaseRoot, len(idsToSync))
+		targetRoots []BaseRoot
 		err         error
 	)
 	switch {
@@ -64,43 +81,27 @@ func (s *SimpleSyncer) executeSync(ctx context.Context, diffEngine DiffEngine,
 		log.Tracef("Fetching %v roots for IDs: %v", len(idsToSync),
 			spew.Sdump(idsToSync))
 
-		// We'll use an error group to fetch each Universe root we need
-		// as a series of parallel requests backed by a worker pool.
-		//
-		// TODO(roasbeef): can actually make non-blocking..
-		err = chanutils.ParSlice(
+		// We'll use a worker pool to fetch each Universe root we need
+		// as a series of parallel requests.
+		targetRoots, err = chanutils.ParSliceResults(
 			ctx, idsToSync,
-			func(ctx context.Context, id Identifier) error {
-				root, err := diffEngine.RootNode(ctx, id)
-				if err != nil {
-					return err
-				}
-
-				rootsToSync <- root
-				return nil
+			func(ctx context.Context, id Identifier) (BaseRoot,
+				error) {
+
+				return diffEngine.RootNode(ctx, id)
 			},
 		)
 
 	// Otherwise, we'll just fetch all the roots from the remote universe.
 	default:
 		log.Infof("Fetching all roots for remote Universe server...")
-		roots, err := diffEngine.RootNodes(ctx)
-		if err != nil {
-			return nil, err
-		}
-
-		rootsToSync = make(chan BaseRoot, len(roots))
-
-		// TODO(roasbeef): can make non-blocking w/ goroutine
-		chanutils.SendAll(rootsToSync, roots...)
+		targetRoots, err = diffEngine.RootNodes(ctx)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("unable to fetch roots for "+
 			"universe sync: %w", err)
 	}
 
-	targetRoots := chanutils.Collect(rootsToSync)
-
 	log.Infof("Obtained %v roots from remote Universe server",
 		len(targetRoots))
 	log.Tracef("Obtained %v roots from remote Universe server: %v",
@@ -160,7 +161,7 @@ func (s *SimpleSyncer) executeSync(ctx context.Context, diffEngine DiffEngine,
 
 		// Now that we know where the divergence is, we can fetch the
 		// issuance proofs from the remote party.
-		newLeaves := make(chan *MintingLeaf, len(keysToFetch))
+		fetchedProofs := make(chan *IssuanceProof, len(keysToFetch))
 		err = chanutils.ParSlice(ctx, keysToFetch, func(ctx context.Context, key BaseKey) error {
 			newProof, err := diffEngine.FetchIssuanceProof(ctx, uniID, key)
 			if err != nil {
@@ -180,30 +181,22 @@ func (s *SimpleSyncer) executeSync(ctx context.Context, diffEngine DiffEngine,
 			// TODO(roasbeef): inclusion w/ root here, also that
 			// it's the expected asset ID
 
-			log.Infof("UniverseRoot(%v): inserting new leaf",
-				uniID.String())
-			log.Tracef("UniverseRoot(%v): inserting new leaf for "+
-				"key=%v", uniID.String(), spew.Sdump(key))
-
-			// TODO(roasbeef): this is actually giving a lagging
-			// proof for each of them
-			_, err = s.cfg.LocalRegistrar.RegisterIssuance(
-				ctx, uniID, key, leafProof.Leaf,
-			)
-			if err != nil {
-				return fmt.Errorf("unable to register "+
-					"issuance proof: %w", err)
-			}
-
-			newLeaves <- leafProof.Leaf
+			fetchedProofs <- leafProof
 			return nil
 		})
 		if err != nil {
 			return err
 		}
 
+		newLeaves, err := s.registerLeaves(
+			ctx, host, uniID, chanutils.Collect(fetchedProofs),
+		)
+		if err != nil {
+			return err
+		}
+
 		log.Infof("Universe sync for UniverseRoot(%v) complete, %d "+
-			"new leaves inserted", uniID.String(), len(keysToFetch))
+			"new leaves inserted", uniID.String(), len(newLeaves))
 
 		// TODO(roabseef): sanity check local and remote roots match
 		// now?
@@ -213,7 +206,7 @@ func (s *SimpleSyncer) executeSync(ctx context.Context, diffEngine DiffEngine,
 		syncDiffs <- AssetSyncDiff{
 			OldUniverseRoot: localRoot,
 			NewUniverseRoot: remoteRoot,
-			NewLeafProofs:   chanutils.Collect(newLeaves),
+			NewLeafProofs:   newLeaves,
 		}
 
 		log.Infof("Sync for UniverseRoot(%v) complete!", uniID.String())
@@ -231,6 +224,82 @@ func (s *SimpleSyncer) executeSync(ctx context.Context, diffEngine DiffEngine,
 	return chanutils.Collect(syncDiffs), nil
 }
 
+// registerLeaves inserts the given leaves fetched from the remote universe into
+// the local universe. A transfer leaf can only be inserted once the leaf of
+// the asset it spends is known, so leaves that are missing their previous leaf
+// are retried after all others, for as long as that makes progress.
+func (s *SimpleSyncer) registerLeaves(ctx context.Context, host ServerAddr,
+	uniID Identifier, leafProofs []*IssuanceProof) ([]*MintingLeaf, error) {
+
+	var newLeaves []*MintingLeaf
+	for len(leafProofs) > 0 {
+		var pendingProofs []*IssuanceProof
+		for _, leafProof := range leafProofs {
+			key := leafProof.MintingKey
+
+			log.Infof("UniverseRoot(%v): inserting new leaf",
+				uniID.String())
+			log.Tracef("UniverseRoot(%v): inserting new leaf for "+
+				"key=%v", uniID.String(), spew.Sdump(key))
+
+			// TODO(roasbeef): this is actually giving a lagging
+			// proof for each of them
+			_, err := s.cfg.LocalRegistrar.RegisterIssuance(
+				ctx, uniID, key, leafProof.Leaf,
+			)
+			switch {
+			case errors.Is(err, ErrMissingPrevLeaf):
+				pendingProofs = append(pendingProofs, leafProof)
+				continue
+
+			// If we don't index the transfers of this universe,
+			// then we only sync its issuance leaves.
+			case errors.Is(err, ErrTransferProofsDisabled):
+				log.Debugf("UniverseRoot(%v): skipping "+
+					"transfer leaf", uniID.String())
+				continue
+
+			case err != nil:
+				return nil, fmt.Errorf("unable to register "+
+					"issuance proof: %w", err)
+			}
+
+			// The leaf is already part of our universe at this
+			// point, so failing to record where it came from or
+			// the sync event shouldn't fail the sync.
+			err = s.cfg.ProvenanceLog.LogLeafProvenance(
+				ctx, host, uniID, key,
+			)
+			if err != nil {
+				log.Warnf("UniverseRoot(%v): unable to log "+
+					"leaf provenance: %v", uniID.String(),
+					err)
+			}
+
+			err = s.cfg.UniverseStats.LogSyncEvent(ctx, uniID, key)
+			if err != nil {
+				log.Warnf("UniverseRoot(%v): unable to log "+
+					"sync event: %v", uniID.String(), err)
+			}
+
+			newLeaves = append(newLeaves, leafProof.Leaf)
+		}
+
+		// If none of the remaining leaves could be inserted, then the
+		// leaves they depend on are neither part of our universe nor
+		// of the remote one.
+		if len(pendingProofs) == len(leafProofs) {
+			return nil, fmt.Errorf("%w: unable to insert %d "+
+				"transfer leaves", ErrMissingPrevLeaf,
+				len(pendingProofs))
+		}
+
+		leafProofs = pendingProofs
+	}
+
+	return newLeaves, nil
+}
+
 // SyncUniverse attempts to synchronize the local universe with the remote
 // universe, governed by the sync type and the set of universe IDs to sync.
 func (s *SimpleSyncer) SyncUniverse(ctx context.Context, host ServerAddr,
@@ -263,5 +332,22 @@ func (s *SimpleSyncer) SyncUniverse(ctx context.Context, host ServerAddr,
 
 	// With the engine created, we can now sync the local Universe with the
 	// remote instance.
-	return s.executeSync(ctx, diffEngine, syncType, idsToSync)
+	start := time.Now()
+	doneSyncing := monitoring.TrackActivity(
+		monitoring.SubsystemUniverseSync,
+		fmt.Sprintf("sync with host=%v", host.HostStr()),
+		syncDeadline,
+	)
+	syncDiffs, err := s.executeSync(
+		ctx, host, diffEngine, syncType, idsToSync,
+	)
+	doneSyncing()
+
+	var numNewLeaves int
+	for _, syncDiff := range syncDiffs {
+		numNewLeaves += len(syncDiff.NewLeafProofs)
+	}
+	monitoring.ObserveUniverseSync(start, numNewLeaves, err)
+
+	return syncDiffs, err
 }
@@ -0,0 +1,135 @@
+package universe
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightninglabs/taproot-assets/proof"
+)
+
+var (
+	// ErrTransferProofsDisabled is returned when a transfer proof is
+	// inserted into a universe that only indexes issuance proofs.
+	ErrTransferProofsDisabled = fmt.Errorf("universe doesn't index " +
+		"transfer proofs")
+
+	// ErrMissingPrevLeaf is returned when a transfer proof is inserted
+	// into a universe that doesn't contain the proof of the asset the
+	// transfer spends.
+	ErrMissingPrevLeaf = fmt.Errorf("proof of spent asset not found in " +
+		"universe")
+)
+
+// TransferPolicy governs which universes index the proofs of asset transfers
+// in addition to the issuance proofs. Indexing transfer proofs allows
+// receivers to fetch the full provenance of an asset from a universe server.
+type TransferPolicy struct {
+	// indexAll is true if all universes index transfer proofs.
+	indexAll bool
+
+	// ids is the set of universes that index transfer proofs, keyed by
+	// their string representation.
+	ids map[string]struct{}
+}
+
+// NewTransferPolicy creates a new transfer policy. If indexAll is true, then
+// all universes index transfer proofs. Otherwise, only the universes of the
+// given IDs do.
+func NewTransferPolicy(indexAll bool, ids ...Identifier) *TransferPolicy {
+	idSet := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		idSet[id.String()] = struct{}{}
+	}
+
+	return &TransferPolicy{
+		indexAll: indexAll,
+		ids:      idSet,
+	}
+}
+
+// IndexesTransfers returns true if the universe with the given ID indexes
+// transfer proofs. A nil policy only permits issuance proofs.
+func (t *TransferPolicy) IndexesTransfers(id Identifier) bool {
+	if t == nil {
+		return false
+	}
+
+	_, ok := t.ids[id.String()]
+	return t.indexAll || ok
+}
+
+// isTransferProof returns true if the given proof proves the transfer of an
+// asset rather than its issuance.
+func isTransferProof(p *proof.Proof) bool {
+	// Assets created by a split commit to a zero previous ID as well, so
+	// we also need to check for the split commitment.
+	prevWitnesses := p.Asset.PrevWitnesses
+	if len(prevWitnesses) != 1 || prevWitnesses[0].PrevID == nil ||
+		prevWitnesses[0].SplitCommitment != nil {
+
+		return true
+	}
+
+	return *prevWitnesses[0].PrevID != asset.ZeroPrevID
+}
+
+// prevLeafKey returns the universe key of the leaf that holds the proof of the
+// asset spent by the transfer the given proof proves. For split assets, this
+// is the first input of the split root asset.
+func prevLeafKey(p *proof.Proof) (BaseKey, error) {
+	a := &p.Asset
+	if a.HasSplitCommitmentWitness() {
+		a = &a.PrevWitnesses[0].SplitCommitment.RootAsset
+	}
+
+	if len(a.PrevWitnesses) == 0 || a.PrevWitnesses[0].PrevID == nil {
+		return BaseKey{}, fmt.Errorf("asset has no previous input")
+	}
+	prevID := a.PrevWitnesses[0].PrevID
+
+	scriptKey, err := btcec.ParsePubKey(prevID.ScriptKey[:])
+	if err != nil {
+		return BaseKey{}, fmt.Errorf("invalid previous script key: %w",
+			err)
+	}
+
+	return BaseKey{
+		MintingOutpoint: prevID.OutPoint,
+		ScriptKey: &asset.ScriptKey{
+			PubKey: scriptKey,
+		},
+	}, nil
+}
+
+// prevSnapshot returns the snapshot of the asset spent by the transfer the
+// given proof proves. The proof of the spent asset must already be part of the
+// given universe. As every leaf is verified before it's inserted, the snapshot
+// is derived from the stored proof without verifying it again.
+func prevSnapshot(ctx context.Context, baseUni BaseBackend,
+	p *proof.Proof) (*proof.AssetSnapshot, error) {
+
+	prevKey, err := prevLeafKey(p)
+	if err != nil {
+		return nil, err
+	}
+
+	prevProofs, err := baseUni.FetchIssuanceProof(ctx, prevKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: outpoint=%v: %v",
+			ErrMissingPrevLeaf, prevKey.MintingOutpoint, err)
+	}
+
+	var prevProof proof.Proof
+	err = prevProof.Decode(
+		bytes.NewReader(prevProofs[0].Leaf.GenesisProof),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode previous proof: %w",
+			err)
+	}
+
+	return prevProof.Snapshot()
+}
@@ -1,7 +1,6 @@
 package taprootassets
 
 import (
-	"bytes"
 	"context"
 	"crypto/tls"
 	"fmt"
@@ -174,9 +173,7 @@ func (r *RpcUniverseDiff) FetchIssuanceProof(ctx context.Context,
 	}
 
 	var compressedProof mssmt.CompressedProof
-	err = compressedProof.Decode(
-		bytes.NewReader(uProofs.UniverseInclusionProof),
-	)
+	err = compressedProof.DecodeBytes(uProofs.UniverseInclusionProof)
 	if err != nil {
 		return nil, err
 	}
@@ -1,7 +1,6 @@
 package taprootassets
 
 import (
-	"bytes"
 	"context"
 	"crypto/tls"
 	"fmt"
@@ -69,9 +68,7 @@ func unmarshalIssuanceProof(ctx context.Context,
 	}
 
 	var compressedProof mssmt.CompressedProof
-	err = compressedProof.Decode(
-		bytes.NewReader(proofResp.UniverseInclusionProof),
-	)
+	err = compressedProof.DecodeBytes(proofResp.UniverseInclusionProof)
 	if err != nil {
 		return nil, err
 	}
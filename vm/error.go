@@ -33,8 +33,8 @@ const (
 	ErrAmountMismatch
 
 	// ErrInvalidSigHashFlag represents an error case where an asset witness
-	// contains signatures created with any sighash flag other than
-	// SIGHASH_DEFAULT.
+	// contains a signature created with a sighash flag that isn't supported
+	// for virtual transactions.
 	ErrInvalidSigHashFlag
 
 	// ErrInvalidGenesisStateTransition represents an error case where an
@@ -62,6 +62,39 @@ const (
 	// ErrInvalidRootAsset represents an error case where the root asset
 	// of an asset split has zero value but a spendable script key.
 	ErrInvalidRootAsset
+
+	// ErrBurnedInput represents an error case where an asset input is a
+	// burned asset, which is provably un-spendable.
+	ErrBurnedInput
+
+	// ErrDivisibilityMismatch represents an error case where an asset, or
+	// asset split, does not match the non-divisible flag of its inputs.
+	ErrDivisibilityMismatch
+
+	// ErrNonDivisibleSplit represents an error case where a non-divisible
+	// asset is split into multiple non-zero outputs.
+	ErrNonDivisibleSplit
+
+	// ErrWitnessTooLarge represents an error case where the serialized
+	// witness of an asset input exceeds the size limit of the VM.
+	ErrWitnessTooLarge
+
+	// ErrScriptTooLarge represents an error case where the tapscript leaf
+	// revealed by an asset input exceeds the size limit of the VM.
+	ErrScriptTooLarge
+
+	// ErrStackTooDeep represents an error case where the combined stack
+	// depth of an asset input's script execution exceeds the limit of the
+	// VM.
+	ErrStackTooDeep
+
+	// ErrEditionMismatch represents an error case where an asset, or asset
+	// split, does not match the edition serial of its inputs.
+	ErrEditionMismatch
+
+	// ErrInvalidEdition represents an error case where an asset other
+	// than a collectible carries an edition serial.
+	ErrInvalidEdition
 )
 
 // Wrap select errors related to virtual TX handling to provide more
@@ -106,6 +139,22 @@ func (k ErrorKind) String() string {
 		return "invalid split commitment proof"
 	case ErrInvalidRootAsset:
 		return "invalid zero-value root asset"
+	case ErrBurnedInput:
+		return "burned asset cannot be spent"
+	case ErrDivisibilityMismatch:
+		return "asset non-divisible flag mismatch"
+	case ErrNonDivisibleSplit:
+		return "non-divisible asset split into multiple outputs"
+	case ErrWitnessTooLarge:
+		return "asset witness exceeds size limit"
+	case ErrScriptTooLarge:
+		return "asset script exceeds size limit"
+	case ErrStackTooDeep:
+		return "asset script stack exceeds depth limit"
+	case ErrEditionMismatch:
+		return "asset edition mismatch"
+	case ErrInvalidEdition:
+		return "edition serial on non-collectible asset"
 	default:
 		return "unknown"
 	}
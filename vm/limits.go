@@ -0,0 +1,182 @@
+package vm
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+const (
+	// DefaultMaxWitnessSize is the default maximum serialized size of a
+	// single asset input witness in bytes. Tapscript witnesses are only
+	// bounded by the block weight in Bitcoin, so this mirrors the largest
+	// witness that could be part of a valid block.
+	DefaultMaxWitnessSize = blockchain.MaxBlockWeight
+
+	// DefaultMaxScriptSize is the default maximum size of a tapscript leaf
+	// revealed in a script path spend. The script size limit of
+	// pre-taproot scripts doesn't apply to tapscript, so this is only
+	// bounded by the block weight as well.
+	DefaultMaxScriptSize = blockchain.MaxBlockWeight
+
+	// DefaultMaxStackDepth is the default maximum combined depth of the
+	// stack and alt stack at any point during script execution. This
+	// mirrors the consensus limit of Bitcoin scripts, which also applies
+	// to tapscript.
+	DefaultMaxStackDepth = txscript.MaxStackSize
+
+	// StandardMaxWitnessSize is the maximum serialized size of a single
+	// asset input witness in bytes of the standard limits. This mirrors
+	// the largest standard Bitcoin transaction, measured in virtual bytes.
+	StandardMaxWitnessSize = 100_000
+
+	// StandardMaxScriptSize is the maximum size of a tapscript leaf
+	// revealed in a script path spend of the standard limits. This
+	// mirrors the script size limit of pre-taproot Bitcoin scripts.
+	StandardMaxScriptSize = txscript.MaxScriptSize
+)
+
+// Limits is the set of standardness and size limits the VM enforces on the
+// witness of each asset input. Violating any of the limits results in a
+// typed Error, so callers can tell them apart from invalid witnesses.
+type Limits struct {
+	// MaxWitnessSize is the maximum serialized size of an input witness
+	// in bytes.
+	MaxWitnessSize int
+
+	// MaxScriptSize is the maximum size of the tapscript leaf revealed
+	// in a script path spend in bytes.
+	MaxScriptSize int
+
+	// MaxStackDepth is the maximum combined depth of the stack and alt
+	// stack, both of the initial witness stack and during execution.
+	MaxStackDepth int
+}
+
+// DefaultLimits returns the limits enforced by the VM unless specified
+// otherwise. These are equivalent to the limits Bitcoin consensus places on
+// tapscript witnesses, so any witness that is valid on chain is also accepted
+// by the VM.
+func DefaultLimits() Limits {
+	return Limits{
+		MaxWitnessSize: DefaultMaxWitnessSize,
+		MaxScriptSize:  DefaultMaxScriptSize,
+		MaxStackDepth:  DefaultMaxStackDepth,
+	}
+}
+
+// StandardLimits returns limits that are stricter than consensus. They can be
+// used to reject a transfer with an unusually large witness before it is
+// broadcast, but must not be used to validate transfers that are already
+// confirmed.
+func StandardLimits() Limits {
+	return Limits{
+		MaxWitnessSize: StandardMaxWitnessSize,
+		MaxScriptSize:  StandardMaxScriptSize,
+		MaxStackDepth:  DefaultMaxStackDepth,
+	}
+}
+
+// Validate makes sure the limits are sane.
+func (l Limits) Validate() error {
+	switch {
+	case l.MaxWitnessSize <= 0:
+		return fmt.Errorf("max witness size must be positive")
+
+	case l.MaxScriptSize <= 0:
+		return fmt.Errorf("max script size must be positive")
+
+	case l.MaxStackDepth <= 0:
+		return fmt.Errorf("max stack depth must be positive")
+	}
+
+	return nil
+}
+
+// checkWitness makes sure the given input witness doesn't exceed the static
+// limits, which can be checked without executing any scripts.
+func (l Limits) checkWitness(witness wire.TxWitness) error {
+	if size := witness.SerializeSize(); size > l.MaxWitnessSize {
+		return newErrInner(ErrWitnessTooLarge, fmt.Errorf("witness "+
+			"size %d exceeds limit %d", size, l.MaxWitnessSize))
+	}
+
+	// An optional annex is never part of the stack or script.
+	items := witness
+	if len(items) >= 2 {
+		lastItem := items[len(items)-1]
+		if len(lastItem) > 0 && lastItem[0] == txscript.TaprootAnnexTag {
+			items = items[:len(items)-1]
+		}
+	}
+
+	// A key path spend only contains a signature, so only script path
+	// spends, which end with a valid control block, reveal a script.
+	stackSize := len(items)
+	if len(items) >= 2 {
+		_, err := txscript.ParseControlBlock(items[len(items)-1])
+		if err == nil {
+			script := items[len(items)-2]
+			if len(script) > l.MaxScriptSize {
+				return newErrInner(
+					ErrScriptTooLarge, fmt.Errorf("script "+
+						"size %d exceeds limit %d",
+						len(script), l.MaxScriptSize),
+				)
+			}
+
+			stackSize -= 2
+		}
+	}
+
+	return l.checkStackDepth(stackSize)
+}
+
+// checkStackDepth makes sure the given combined stack depth doesn't exceed
+// the limit.
+func (l Limits) checkStackDepth(depth int) error {
+	if depth > l.MaxStackDepth {
+		return newErrInner(ErrStackTooDeep, fmt.Errorf("stack depth "+
+			"%d exceeds limit %d", depth, l.MaxStackDepth))
+	}
+
+	return nil
+}
+
+// execute runs the given script engine to completion, enforcing the stack
+// depth limit after each step. Apart from that, this is equivalent to
+// txscript.Engine.Execute.
+func (l Limits) execute(engine *txscript.Engine) error {
+	for done := false; !done; {
+		var err error
+		done, err = engine.Step()
+		if err != nil {
+			return newErrInner(ErrInvalidTransferWitness, err)
+		}
+
+		depth := len(engine.GetStack()) + len(engine.GetAltStack())
+		if err := l.checkStackDepth(depth); err != nil {
+			return err
+		}
+	}
+
+	err := engine.CheckErrorCondition(true)
+	if err != nil {
+		return newErrInner(ErrInvalidTransferWitness, err)
+	}
+
+	return nil
+}
+
+// EngineOption is a functional option that modifies the VM.
+type EngineOption func(*Engine)
+
+// WithLimits is an EngineOption that replaces the default limits enforced
+// by the VM.
+func WithLimits(limits Limits) EngineOption {
+	return func(vm *Engine) {
+		vm.limits = limits
+	}
+}
@@ -3,6 +3,7 @@ package vm
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
@@ -26,18 +27,32 @@ type Engine struct {
 	// prevAssets maps newAsset's inputs by the hash of their PrevID to
 	// their asset.
 	prevAssets commitment.InputSet
+
+	// limits is the set of size and standardness limits enforced on the
+	// witness of each input.
+	limits Limits
 }
 
 // New returns a new virtual machine capable of executing and verifying Taproot
 // Asset state transitions.
 func New(newAsset *asset.Asset, splitAssets []*commitment.SplitAsset,
-	prevAssets commitment.InputSet) (*Engine, error) {
+	prevAssets commitment.InputSet, opts ...EngineOption) (*Engine, error) {
 
-	return &Engine{
+	vm := &Engine{
 		newAsset:    newAsset,
 		splitAssets: splitAssets,
 		prevAssets:  prevAssets,
-	}, nil
+		limits:      DefaultLimits(),
+	}
+	for _, opt := range opts {
+		opt(vm)
+	}
+
+	if err := vm.limits.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid VM limits: %w", err)
+	}
+
+	return vm, nil
 }
 
 // matchesPrevGenesis determines whether certain key parameters of the new
@@ -97,6 +112,38 @@ func matchesAssetParams(newAsset, prevAsset *asset.Asset,
 		return newErrKind(ErrTypeMismatch)
 	}
 
+	if newAsset.NonDivisible != prevAsset.NonDivisible {
+		return newErrKind(ErrDivisibilityMismatch)
+	}
+
+	if newAsset.Edition != prevAsset.Edition {
+		return newErrKind(ErrEditionMismatch)
+	}
+
+	return nil
+}
+
+// validateNonDivisibleSplit makes sure a non-divisible asset isn't split into
+// multiple non-zero outputs. The split commitment root commits to the sum of
+// all outputs of the split, so any non-zero output must carry that full sum.
+// This allows us to enforce the rule even if we only know about a single split
+// asset, as is the case when verifying a proof.
+func (vm *Engine) validateNonDivisibleSplit() error {
+	if !vm.newAsset.NonDivisible || vm.newAsset.SplitCommitmentRoot == nil {
+		return nil
+	}
+
+	totalAmount := vm.newAsset.SplitCommitmentRoot.NodeSum()
+	if vm.newAsset.Amount != 0 && vm.newAsset.Amount != totalAmount {
+		return newErrKind(ErrNonDivisibleSplit)
+	}
+
+	for _, splitAsset := range vm.splitAssets {
+		if splitAsset.Amount != 0 && splitAsset.Amount != totalAmount {
+			return newErrKind(ErrNonDivisibleSplit)
+		}
+	}
+
 	return nil
 }
 
@@ -163,8 +210,7 @@ func (vm *Engine) validateSplit(splitAsset *commitment.SplitAsset) error {
 		ScriptKey:   asset.ToSerialized(splitAsset.ScriptKey.PubKey),
 		Amount:      splitAsset.Amount,
 	}
-	splitNoWitness := splitAsset.Copy()
-	splitNoWitness.PrevWitnesses[0].SplitCommitment = nil
+	splitNoWitness := splitAsset.CopyWithoutSplitCommitment()
 	splitLeaf, err := splitNoWitness.Leaf()
 	if err != nil {
 		return err
@@ -196,17 +242,37 @@ func (vm *Engine) validateWitnessV0(virtualTx *wire.MsgTx, inputIdx uint32,
 		return newErrKind(ErrInvalidTransferWitness)
 	}
 
+	// Before doing any expensive work, make sure the witness doesn't
+	// exceed any of the static size limits.
+	if err := vm.limits.checkWitness(witness.TxWitness); err != nil {
+		return err
+	}
+
 	// The parameters of the new and old asset much match exactly.
 	err := matchesAssetParams(vm.newAsset, prevAsset, witness)
 	if err != nil {
 		return err
 	}
 
+	// Only a subset of the sighash types can be used to sign an asset
+	// input, as the virtual transaction only has a single input and
+	// output.
+	sigHashType := witness.SigHashType()
+	if !asset.IsSupportedSigHashType(sigHashType) {
+		return newErrKind(ErrInvalidSigHashFlag)
+	}
+
 	// Update the virtual transaction input with details for the specific
-	// Taproot Asset input and proceed to validate its witness.
-	virtualTxCopy := tapscript.VirtualTxWithInput(
-		virtualTx, prevAsset, inputIdx, witness.TxWitness,
+	// Taproot Asset input and proceed to validate its witness. A signature
+	// with SIGHASH_ANYONECANPAY only commits to this input, so the prevout
+	// of the virtual input is derived from it alone.
+	virtualTxCopy, err := tapscript.VirtualTxWithInputSigHash(
+		virtualTx, prevAsset, *witness.PrevID, inputIdx, sigHashType,
+		witness.TxWitness,
 	)
+	if err != nil {
+		return err
+	}
 
 	prevOutFetcher, err := tapscript.InputPrevOutFetcher(*prevAsset)
 	if err != nil {
@@ -233,11 +299,8 @@ func (vm *Engine) validateWitnessV0(virtualTx *wire.MsgTx, inputIdx uint32,
 	if err != nil {
 		return newErrInner(ErrInvalidTransferWitness, err)
 	}
-	if err := engine.Execute(); err != nil {
-		return newErrInner(ErrInvalidTransferWitness, err)
-	}
 
-	return nil
+	return vm.limits.execute(engine)
 }
 
 // validateStateTransition attempts to validate a normal state transition where
@@ -262,6 +325,12 @@ func (vm *Engine) validateStateTransition(virtualTx *wire.MsgTx) error {
 			return ErrNoInputs
 		}
 
+		// A burned asset is provably un-spendable, so we can reject
+		// the transition early without executing any scripts.
+		if prevAsset.IsBurn() {
+			return newErrKind(ErrBurnedInput)
+		}
+
 		switch prevAsset.ScriptVersion {
 		case asset.ScriptV0:
 			err := vm.validateWitnessV0(
@@ -281,6 +350,11 @@ func (vm *Engine) validateStateTransition(virtualTx *wire.MsgTx) error {
 // Execute attempts to execute an asset's state transition to determine whether
 // it was valid or not represented by the error returned.
 func (vm *Engine) Execute() error {
+	// Only collectibles can be minted as individually numbered editions.
+	if vm.newAsset.Edition > 0 && vm.newAsset.Type != asset.Collectible {
+		return newErrKind(ErrInvalidEdition)
+	}
+
 	// A genesis asset should have a single witness and a PrevID of all
 	// zeros and empty witness and split commitment proof.
 	if vm.newAsset.HasGenesisWitness() {
@@ -299,6 +373,12 @@ func (vm *Engine) Execute() error {
 		}
 	}
 
+	// A non-divisible asset can only be moved as a whole, even if a split
+	// commitment is used to do so.
+	if err := vm.validateNonDivisibleSplit(); err != nil {
+		return err
+	}
+
 	// Now that we know we're not dealing with a genesis state transition,
 	// we'll map our set of asset inputs and outputs to the 1-input 1-output
 	// virtual transaction.
@@ -1,6 +1,7 @@
 package vm
 
 import (
+	"bytes"
 	"context"
 	"sort"
 	"testing"
@@ -13,6 +14,7 @@ import (
 	"github.com/lightninglabs/taproot-assets/asset"
 	"github.com/lightninglabs/taproot-assets/commitment"
 	"github.com/lightninglabs/taproot-assets/internal/test"
+	"github.com/lightninglabs/taproot-assets/mssmt"
 	"github.com/lightninglabs/taproot-assets/tapscript"
 	"github.com/lightningnetwork/lnd/input"
 	"github.com/stretchr/testify/require"
@@ -32,15 +34,13 @@ func randAsset(t *testing.T, assetType asset.Type,
 }
 
 func genTaprootKeySpend(t *testing.T, privKey btcec.PrivateKey,
-	virtualTx *wire.MsgTx, input *asset.Asset, idx uint32) wire.TxWitness {
+	virtualTx *wire.MsgTx, input *asset.Asset, prevID asset.PrevID,
+	idx uint32, sigHashType txscript.SigHashType) wire.TxWitness {
 
 	t.Helper()
 
-	virtualTxCopy := tapscript.VirtualTxWithInput(
-		virtualTx, input, idx, nil,
-	)
 	sigHash, err := tapscript.InputKeySpendSigHash(
-		virtualTxCopy, input, idx, txscript.SigHashDefault,
+		virtualTx, input, prevID, idx, sigHashType,
 	)
 	require.NoError(t, err)
 
@@ -48,12 +48,18 @@ func genTaprootKeySpend(t *testing.T, privKey btcec.PrivateKey,
 	sig, err := schnorr.Sign(taprootPrivKey, sigHash)
 	require.NoError(t, err)
 
-	return wire.TxWitness{sig.Serialize()}
+	witness := sig.Serialize()
+	if sigHashType != txscript.SigHashDefault {
+		witness = append(witness, byte(sigHashType))
+	}
+
+	return wire.TxWitness{witness}
 }
 
 func genTaprootScriptSpend(t *testing.T, privKey btcec.PrivateKey,
-	virtualTx *wire.MsgTx, input *asset.Asset, idx uint32,
-	sigHashType txscript.SigHashType, controlBlock *txscript.ControlBlock,
+	virtualTx *wire.MsgTx, input *asset.Asset, prevID asset.PrevID,
+	idx uint32, sigHashType txscript.SigHashType,
+	controlBlock *txscript.ControlBlock,
 	tapLeaf *txscript.TapLeaf, scriptWitness []byte) wire.TxWitness {
 
 	t.Helper()
@@ -62,11 +68,8 @@ func genTaprootScriptSpend(t *testing.T, privKey btcec.PrivateKey,
 	require.NoError(t, err)
 
 	if scriptWitness == nil {
-		virtualTxCopy := tapscript.VirtualTxWithInput(
-			virtualTx, input, idx, nil,
-		)
 		sigHash, err := tapscript.InputScriptSpendSigHash(
-			virtualTxCopy, input, idx, sigHashType, tapLeaf,
+			virtualTx, input, prevID, idx, sigHashType, tapLeaf,
 		)
 		require.NoError(t, err)
 
@@ -138,7 +141,8 @@ func collectibleStateTransition(t *testing.T) (*asset.Asset,
 	virtualTx, _, err := tapscript.VirtualTx(newAsset, inputs)
 	require.NoError(t, err)
 	newWitness := genTaprootKeySpend(
-		t, *privKey, virtualTx, genesisAsset, 0,
+		t, *privKey, virtualTx, genesisAsset, *prevID, 0,
+		txscript.SigHashDefault,
 	)
 	require.NoError(t, err)
 	newAsset.PrevWitnesses[0].TxWitness = newWitness
@@ -204,7 +208,8 @@ func normalStateTransition(t *testing.T) (*asset.Asset, commitment.SplitSet,
 	virtualTx, _, err := tapscript.VirtualTx(newAsset, inputs)
 	require.NoError(t, err)
 	newWitness := genTaprootKeySpend(
-		t, *privKey1, virtualTx, genesisAsset1, 0,
+		t, *privKey1, virtualTx, genesisAsset1, *prevID1, 0,
+		txscript.SigHashDefault,
 	)
 	require.NoError(t, err)
 	newAsset.PrevWitnesses[0].TxWitness = newWitness
@@ -214,7 +219,7 @@ func normalStateTransition(t *testing.T) (*asset.Asset, commitment.SplitSet,
 	controlBlock := leafProof.ToControlBlock(privKey2.PubKey())
 
 	newAsset.PrevWitnesses[1].TxWitness = genTaprootScriptSpend(
-		t, *privKey2, virtualTx, genesisAsset2, 1,
+		t, *privKey2, virtualTx, genesisAsset2, *prevID2, 1,
 		txscript.SigHashDefault, &controlBlock, &tapLeaf, nil,
 	)
 
@@ -264,7 +269,9 @@ func splitStateTransition(t *testing.T) (*asset.Asset, commitment.SplitSet,
 	)
 	require.NoError(t, err)
 	newWitness := genTaprootKeySpend(
-		t, *privKey, virtualTx, genesisAsset, 0,
+		t, *privKey, virtualTx, genesisAsset,
+		*splitCommitment.RootAsset.PrevWitnesses[0].PrevID, 0,
+		txscript.SigHashDefault,
 	)
 	require.NoError(t, err)
 	splitCommitment.RootAsset.PrevWitnesses[0].TxWitness = newWitness
@@ -324,7 +331,9 @@ func splitFullValueStateTransition(validRootLocator,
 		)
 		require.NoError(t, err)
 		newWitness := genTaprootKeySpend(
-			t, *privKey, virtualTx, genesisAsset, 0,
+			t, *privKey, virtualTx, genesisAsset,
+			*splitCommitment.RootAsset.PrevWitnesses[0].PrevID, 0,
+			txscript.SigHashDefault,
 		)
 		require.NoError(t, err)
 		splitCommitment.RootAsset.PrevWitnesses[0].TxWitness = newWitness
@@ -372,7 +381,9 @@ func splitCollectibleStateTransition(validRoot bool) stateTransitionFunc {
 		)
 		require.NoError(t, err)
 		newWitness := genTaprootKeySpend(
-			t, *privKey, virtualTx, genesisAsset, 0,
+			t, *privKey, virtualTx, genesisAsset,
+			*splitCommitment.RootAsset.PrevWitnesses[0].PrevID, 0,
+			txscript.SigHashDefault,
 		)
 		require.NoError(t, err)
 		splitCommitment.RootAsset.PrevWitnesses[0].TxWitness = newWitness
@@ -475,7 +486,8 @@ func scriptTreeSpendStateTransition(t *testing.T, useHashLock,
 		)
 		require.NoError(t, err)
 		newWitness := genTaprootScriptSpend(
-			t, *scriptPrivKey, virtualTx, genesisAsset, 0,
+			t, *scriptPrivKey, virtualTx, genesisAsset,
+			*splitCommitment.RootAsset.PrevWitnesses[0].PrevID, 0,
 			sigHashType, testTapScript.ControlBlock, usedLeaf,
 			scriptWitness,
 		)
@@ -487,6 +499,107 @@ func scriptTreeSpendStateTransition(t *testing.T, useHashLock,
 	}
 }
 
+func burnedInputStateTransition(t *testing.T) (*asset.Asset,
+	commitment.SplitSet, commitment.InputSet) {
+
+	// We create an input asset that was itself the result of a burn, which
+	// means it uses the burn key derived from its own first input.
+	burnPrevID := asset.PrevID{
+		OutPoint:  test.RandOp(t),
+		ID:        asset.RandID(t),
+		ScriptKey: asset.RandSerializedKey(t),
+	}
+	burnedAsset := randAsset(
+		t, asset.Normal, asset.DeriveBurnKey(burnPrevID),
+	)
+	burnedAsset.PrevWitnesses = []asset.Witness{{
+		PrevID:    &burnPrevID,
+		TxWitness: wire.TxWitness{{1}},
+	}}
+	require.True(t, burnedAsset.IsBurn())
+
+	prevID := &asset.PrevID{
+		OutPoint:  wire.OutPoint{},
+		ID:        burnedAsset.Genesis.ID(),
+		ScriptKey: asset.ToSerialized(burnedAsset.ScriptKey.PubKey),
+	}
+	newAsset := burnedAsset.Copy()
+	newAsset.ScriptKey = asset.NewScriptKey(test.RandPubKey(t))
+	newAsset.PrevWitnesses = []asset.Witness{{
+		PrevID:    prevID,
+		TxWitness: wire.TxWitness{{1}},
+	}}
+
+	inputs := commitment.InputSet{*prevID: burnedAsset}
+	return newAsset, nil, inputs
+}
+
+func divisibilityMismatchStateTransition(t *testing.T) (*asset.Asset,
+	commitment.SplitSet, commitment.InputSet) {
+
+	// We create a valid split of a divisible asset and then mark the input
+	// as non-divisible after the fact, which means the flag of the input
+	// no longer matches the flag of the assets created by the split.
+	newAsset, splitSet, inputs := splitStateTransition(t)
+	for _, prevAsset := range inputs {
+		prevAsset.NonDivisible = true
+	}
+
+	return newAsset, splitSet, inputs
+}
+
+func editionStateTransition(mismatch bool) stateTransitionFunc {
+	return func(t *testing.T) (*asset.Asset, commitment.SplitSet,
+		commitment.InputSet) {
+
+		privKey := test.RandPrivKey(t)
+		scriptKey := txscript.ComputeTaprootKeyNoScript(
+			privKey.PubKey(),
+		)
+
+		genesisAsset := randAsset(t, asset.Collectible, scriptKey)
+		genesisAsset.Edition = 3
+
+		prevID := &asset.PrevID{
+			ID: genesisAsset.Genesis.ID(),
+			ScriptKey: asset.ToSerialized(
+				genesisAsset.ScriptKey.PubKey,
+			),
+		}
+		newAsset := genesisAsset.Copy()
+		newAsset.ScriptKey = asset.NewScriptKey(test.RandPubKey(t))
+		newAsset.PrevWitnesses = []asset.Witness{{
+			PrevID: prevID,
+		}}
+
+		// The edition is committed to in the asset leaf, so a new
+		// asset claiming a different edition than its input is
+		// rejected even if it's properly signed.
+		if mismatch {
+			newAsset.Edition = 4
+		}
+
+		inputs := commitment.InputSet{*prevID: genesisAsset}
+		virtualTx, _, err := tapscript.VirtualTx(newAsset, inputs)
+		require.NoError(t, err)
+		newAsset.PrevWitnesses[0].TxWitness = genTaprootKeySpend(
+			t, *privKey, virtualTx, genesisAsset, *prevID, 0,
+			txscript.SigHashDefault,
+		)
+
+		return newAsset, nil, inputs
+	}
+}
+
+func normalEditionStateTransition(t *testing.T) (*asset.Asset,
+	commitment.SplitSet, commitment.InputSet) {
+
+	newAsset, splitSet, inputs := normalStateTransition(t)
+	newAsset.Edition = 1
+
+	return newAsset, splitSet, inputs
+}
+
 func TestVM(t *testing.T) {
 	t.Parallel()
 
@@ -550,6 +663,31 @@ func TestVM(t *testing.T) {
 			f:    splitFullValueStateTransition(false, true),
 			err:  newErrKind(ErrInvalidRootAsset),
 		},
+		{
+			name: "invalid burned input",
+			f:    burnedInputStateTransition,
+			err:  newErrKind(ErrBurnedInput),
+		},
+		{
+			name: "invalid divisibility mismatch",
+			f:    divisibilityMismatchStateTransition,
+			err:  newErrKind(ErrDivisibilityMismatch),
+		},
+		{
+			name: "collectible edition state transition",
+			f:    editionStateTransition(false),
+			err:  nil,
+		},
+		{
+			name: "invalid edition mismatch",
+			f:    editionStateTransition(true),
+			err:  newErrKind(ErrEditionMismatch),
+		},
+		{
+			name: "invalid normal asset edition",
+			f:    normalEditionStateTransition,
+			err:  newErrKind(ErrInvalidEdition),
+		},
 		{
 			name: "split collectible state transition",
 			f:    splitCollectibleStateTransition(true),
@@ -642,3 +780,307 @@ func TestVM(t *testing.T) {
 		}
 	}
 }
+
+// TestNonDivisibleSplit tests that a non-divisible asset can only be moved as a
+// whole when a split commitment is used.
+func TestNonDivisibleSplit(t *testing.T) {
+	t.Parallel()
+
+	const totalAmount = 3
+
+	newSplit := func(amount uint64) *commitment.SplitAsset {
+		splitAsset := randAsset(t, asset.Normal, test.RandPubKey(t))
+		splitAsset.Amount = amount
+		splitAsset.NonDivisible = true
+
+		return &commitment.SplitAsset{
+			Asset:       *splitAsset,
+			OutputIndex: 1,
+		}
+	}
+
+	testCases := []struct {
+		name       string
+		rootAmount uint64
+		splits     []uint64
+		err        error
+	}{{
+		name:       "full value split",
+		rootAmount: 0,
+		splits:     []uint64{totalAmount},
+		err:        nil,
+	}, {
+		name:       "partial root amount",
+		rootAmount: 1,
+		splits:     []uint64{totalAmount - 1},
+		err:        newErrKind(ErrNonDivisibleSplit),
+	}, {
+		name:       "multiple non-zero splits",
+		rootAmount: 0,
+		splits:     []uint64{1, totalAmount - 1},
+		err:        newErrKind(ErrNonDivisibleSplit),
+	}}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			rootAsset := randAsset(
+				t, asset.Normal, test.RandPubKey(t),
+			)
+			rootAsset.Amount = testCase.rootAmount
+			rootAsset.NonDivisible = true
+			rootAsset.SplitCommitmentRoot = mssmt.NewComputedNode(
+				[32]byte{1}, totalAmount,
+			)
+
+			var splitAssets []*commitment.SplitAsset
+			for _, amount := range testCase.splits {
+				splitAssets = append(
+					splitAssets, newSplit(amount),
+				)
+			}
+
+			vm, err := New(rootAsset, splitAssets, nil)
+			require.NoError(t, err)
+
+			err = vm.validateNonDivisibleSplit()
+			require.Equal(t, testCase.err, err)
+		})
+	}
+}
+
+// TestAnyoneCanPaySigHash tests that a signature with SIGHASH_ANYONECANPAY
+// only commits to its own input, so it stays valid if the other inputs of the
+// state transition change, while any other signature doesn't.
+func TestAnyoneCanPaySigHash(t *testing.T) {
+	t.Parallel()
+
+	genesis := asset.RandGenesis(t, asset.Normal)
+	newInput := func(amount uint64) (*btcec.PrivateKey, *asset.Asset,
+		wire.OutPoint) {
+
+		privKey := test.RandPrivKey(t)
+		scriptKey := asset.NewScriptKey(
+			txscript.ComputeTaprootKeyNoScript(privKey.PubKey()),
+		)
+		input := asset.RandAssetWithValues(t, genesis, nil, scriptKey)
+		input.Amount = amount
+
+		return privKey, input, test.RandOp(t)
+	}
+
+	rootLocator := &commitment.SplitLocator{
+		OutputIndex: 0,
+		AssetID:     genesis.ID(),
+		ScriptKey:   asset.RandSerializedKey(t),
+		Amount:      2,
+	}
+	externalLocator := &commitment.SplitLocator{
+		OutputIndex: 1,
+		AssetID:     genesis.ID(),
+		ScriptKey:   asset.RandSerializedKey(t),
+		Amount:      6,
+	}
+
+	// Our own input is always the first input of the transition, the input
+	// of the other party can be swapped out for one with the same amount.
+	ownKey, ownAsset, ownOutPoint := newInput(5)
+	transition := func(otherAsset *asset.Asset,
+		otherOutPoint wire.OutPoint) (*asset.Asset, commitment.InputSet,
+		*wire.MsgTx) {
+
+		inputs := []commitment.SplitCommitmentInput{{
+			Asset:    ownAsset,
+			OutPoint: ownOutPoint,
+		}, {
+			Asset:    otherAsset,
+			OutPoint: otherOutPoint,
+		}}
+		splitCommitment, err := commitment.NewSplitCommitment(
+			context.Background(), inputs, rootLocator,
+			externalLocator,
+		)
+		require.NoError(t, err)
+
+		virtualTx, _, err := tapscript.VirtualTx(
+			splitCommitment.RootAsset, splitCommitment.PrevAssets,
+		)
+		require.NoError(t, err)
+
+		return splitCommitment.RootAsset, splitCommitment.PrevAssets,
+			virtualTx
+	}
+
+	testCases := []struct {
+		name        string
+		sigHashType txscript.SigHashType
+		valid       bool
+		errKind     ErrorKind
+	}{{
+		name:        "sighash all anyone can pay",
+		sigHashType: txscript.SigHashAll | txscript.SigHashAnyOneCanPay,
+		valid:       true,
+	}, {
+		name: "sighash single anyone can pay",
+		sigHashType: txscript.SigHashSingle |
+			txscript.SigHashAnyOneCanPay,
+		valid: true,
+	}, {
+		name:        "sighash all",
+		sigHashType: txscript.SigHashAll,
+		errKind:     ErrInvalidTransferWitness,
+	}, {
+		name:        "sighash none",
+		sigHashType: txscript.SigHashNone,
+		errKind:     ErrInvalidSigHashFlag,
+	}}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			// We sign our own input for a transition with one
+			// input of the other party.
+			_, otherAsset, otherOutPoint := newInput(3)
+			rootAsset, _, virtualTx := transition(
+				otherAsset, otherOutPoint,
+			)
+			signSigHashType := testCase.sigHashType
+			if !asset.IsSupportedSigHashType(signSigHashType) {
+				signSigHashType = txscript.SigHashAll
+			}
+			ownWitness := genTaprootKeySpend(
+				t, *ownKey, virtualTx, ownAsset,
+				*rootAsset.PrevWitnesses[0].PrevID, 0,
+				signSigHashType,
+			)
+
+			// We can't sign with an unsupported sighash type, so we
+			// replace the flag of the signature instead.
+			ownWitness[0][schnorr.SignatureSize] = byte(
+				testCase.sigHashType,
+			)
+
+			// The other party then decides to spend a different
+			// input with the same amount, which doesn't change the
+			// outputs of the transition.
+			newKey, newAsset, newOutPoint := newInput(3)
+			rootAsset, inputs, newVirtualTx := transition(
+				newAsset, newOutPoint,
+			)
+			require.Equal(t, virtualTx.TxOut, newVirtualTx.TxOut)
+
+			rootAsset.PrevWitnesses[0].TxWitness = ownWitness
+			newWitness := genTaprootKeySpend(
+				t, *newKey, newVirtualTx, newAsset,
+				*rootAsset.PrevWitnesses[1].PrevID, 1,
+				txscript.SigHashDefault,
+			)
+			rootAsset.PrevWitnesses[1].TxWitness = newWitness
+
+			vm, err := New(rootAsset, nil, inputs)
+			require.NoError(t, err)
+
+			err = vm.Execute()
+			if testCase.valid {
+				require.NoError(t, err)
+				return
+			}
+
+			var vmErr Error
+			require.ErrorAs(t, err, &vmErr)
+			require.Equal(t, testCase.errKind, vmErr.Kind)
+		})
+	}
+}
+
+// TestVMLimits tests that the VM enforces the configured witness size, script
+// size and stack depth limits with typed errors.
+func TestVMLimits(t *testing.T) {
+	t.Parallel()
+
+	// The normal state transition spends one input through the key path
+	// and one through a script path.
+	newAsset, _, inputSet := normalStateTransition(t)
+
+	limits := func(mod func(*Limits)) Limits {
+		l := DefaultLimits()
+		mod(&l)
+		return l
+	}
+
+	testCases := []struct {
+		name   string
+		limits Limits
+		err    ErrorKind
+	}{{
+		name:   "witness too large",
+		limits: limits(func(l *Limits) { l.MaxWitnessSize = 60 }),
+		err:    ErrWitnessTooLarge,
+	}, {
+		name:   "script too large",
+		limits: limits(func(l *Limits) { l.MaxScriptSize = 10 }),
+		err:    ErrScriptTooLarge,
+	}, {
+		name:   "stack too deep during execution",
+		limits: limits(func(l *Limits) { l.MaxStackDepth = 1 }),
+		err:    ErrStackTooDeep,
+	}}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			vm, err := New(
+				newAsset, nil, inputSet,
+				WithLimits(testCase.limits),
+			)
+			require.NoError(t, err)
+
+			var vmErr Error
+			require.ErrorAs(t, vm.Execute(), &vmErr)
+			require.Equal(t, testCase.err, vmErr.Kind)
+		})
+	}
+
+	// The default limits don't affect a normal transfer.
+	vm, err := New(newAsset, nil, inputSet, WithLimits(DefaultLimits()))
+	require.NoError(t, err)
+	require.NoError(t, vm.Execute())
+
+	// Limits that would reject everything are refused.
+	_, err = New(newAsset, nil, inputSet, WithLimits(Limits{}))
+	require.Error(t, err)
+
+	// An annex is neither part of the script nor the stack.
+	witness := newAsset.PrevWitnesses[1].TxWitness
+	annexWitness := append(
+		append(wire.TxWitness{}, witness...),
+		[]byte{txscript.TaprootAnnexTag},
+	)
+	tight := DefaultLimits()
+	tight.MaxStackDepth = 1
+	require.NoError(t, tight.checkWitness(annexWitness))
+
+	tight.MaxScriptSize = len(witness[len(witness)-2]) - 1
+	var vmErr Error
+	require.ErrorAs(t, tight.checkWitness(annexWitness), &vmErr)
+	require.Equal(t, ErrScriptTooLarge, vmErr.Kind)
+
+	// A tapscript leaf larger than the pre-taproot script size limit is
+	// valid by consensus, so it's only rejected by the standard limits.
+	largeScript := bytes.Repeat(
+		[]byte{txscript.OP_TRUE}, txscript.MaxScriptSize+1,
+	)
+	largeWitness := wire.TxWitness{largeScript, witness[len(witness)-1]}
+	require.NoError(t, DefaultLimits().checkWitness(largeWitness))
+	require.ErrorAs(
+		t, StandardLimits().checkWitness(largeWitness), &vmErr,
+	)
+	require.Equal(t, ErrScriptTooLarge, vmErr.Kind)
+
+	// The standard limits don't affect a normal transfer either.
+	vm, err = New(newAsset, nil, inputSet, WithLimits(StandardLimits()))
+	require.NoError(t, err)
+	require.NoError(t, vm.Execute())
+}
@@ -3,6 +3,7 @@ package taprootassets
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"fmt"
 	"math"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/wtxmgr"
 	"github.com/lightninglabs/lndclient"
 	"github.com/lightninglabs/taproot-assets/tapfreighter"
 	"github.com/lightninglabs/taproot-assets/tapgarden"
@@ -40,11 +42,20 @@ const (
 	defaultChangeType = walletrpc.ChangeAddressType_CHANGE_ADDRESS_TYPE_P2TR
 )
 
+var (
+	// lndInternalLockID is the ID lnd uses to lease the inputs of a PSBT
+	// it funded. It's the SHA256 hash of the string "lnd-internal-lock-id".
+	lndInternalLockID = wtxmgr.LockID(
+		sha256.Sum256([]byte("lnd-internal-lock-id")),
+	)
+)
+
 // FundPsbt attaches enough inputs to the target PSBT packet for it to be
-// valid.
+// valid. If coin control is given, only the UTXOs it allows are used as
+// inputs.
 func (l *LndRpcWalletAnchor) FundPsbt(ctx context.Context, packet *psbt.Packet,
-	minConfs uint32, feeRate chainfee.SatPerKWeight) (tapgarden.FundedPsbt,
-	error) {
+	minConfs uint32, feeRate chainfee.SatPerKWeight,
+	coinControl *tapgarden.CoinControl) (tapgarden.FundedPsbt, error) {
 
 	var psbtBuf bytes.Buffer
 	if err := packet.Serialize(&psbtBuf); err != nil {
@@ -52,6 +63,39 @@ func (l *LndRpcWalletAnchor) FundPsbt(ctx context.Context, packet *psbt.Packet,
 			"psbt: %w", err)
 	}
 
+	var account string
+	if coinControl != nil {
+		account = coinControl.Account
+
+		// If the UTXOs to spend are given, we'll add them to a copy of
+		// the template. The wallet then only adds a change output,
+		// without selecting any other coins.
+		if len(coinControl.Outpoints) > 0 {
+			template, err := psbt.NewFromRawBytes(&psbtBuf, false)
+			if err != nil {
+				return tapgarden.FundedPsbt{}, fmt.Errorf(
+					"unable to decode psbt: %w", err,
+				)
+			}
+
+			for _, outpoint := range coinControl.Outpoints {
+				template.UnsignedTx.AddTxIn(&wire.TxIn{
+					PreviousOutPoint: outpoint,
+				})
+				template.Inputs = append(
+					template.Inputs, psbt.PInput{},
+				)
+			}
+
+			psbtBuf.Reset()
+			if err := template.Serialize(&psbtBuf); err != nil {
+				return tapgarden.FundedPsbt{}, fmt.Errorf(
+					"unable to encode psbt: %w", err,
+				)
+			}
+		}
+	}
+
 	pkt, changeIndex, leasedUtxos, err := l.lnd.WalletKit.FundPsbt(
 		ctx, &walletrpc.FundPsbtRequest{
 			Template: &walletrpc.FundPsbtRequest_Psbt{
@@ -60,6 +104,7 @@ func (l *LndRpcWalletAnchor) FundPsbt(ctx context.Context, packet *psbt.Packet,
 			Fees: &walletrpc.FundPsbtRequest_SatPerVbyte{
 				SatPerVbyte: uint64(feeRate.FeePerKVByte()) / 1000,
 			},
+			Account:    account,
 			MinConfs:   int32(minConfs),
 			ChangeType: defaultChangeType,
 		},
@@ -131,7 +176,21 @@ func (l *LndRpcWalletAnchor) ImportTaprootOutput(ctx context.Context,
 }
 
 // UnlockInput unlocks the set of target inputs after a batch is abandoned.
-func (l *LndRpcWalletAnchor) UnlockInput(ctx context.Context) error {
+func (l *LndRpcWalletAnchor) UnlockInput(ctx context.Context,
+	inputs []wire.OutPoint) error {
+
+	// The inputs of a funded PSBT are always leased with lnd's internal
+	// lock ID.
+	for _, input := range inputs {
+		err := l.lnd.WalletKit.ReleaseOutput(
+			ctx, lndInternalLockID, input,
+		)
+		if err != nil {
+			return fmt.Errorf("unable to release input %v: %w",
+				input, err)
+		}
+	}
+
 	return nil
 }
 